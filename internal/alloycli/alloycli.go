@@ -24,10 +24,15 @@ func Run() {
 	cmd.SetVersionTemplate("{{ .Version }}\n")
 
 	cmd.AddCommand(
+		completionCommand(),
 		convertCommand(),
+		doctorCommand(),
 		fmtCommand(),
 		runCommand(),
+		replCommand(),
+		testCommand(),
 		toolsCommand(),
+		validateCommand(),
 	)
 
 	if err := cmd.Execute(); err != nil {
@@ -13,16 +13,22 @@ import (
 	"github.com/grafana/alloy/internal/runtime/logging"
 )
 
-func applyAutoMemLimit(l *logging.Logger) error {
+// applyAutoMemLimit sets GOMEMLIMIT to ratio of the system memory when the
+// system experiment is requested, unless ratio is 0, and returns the limit
+// which was set.
+func applyAutoMemLimit(l *logging.Logger, ratio float64) (int64, error) {
+	if ratio == 0 {
+		return 0, nil
+	}
+
 	// For non-linux builds without cgroups, memlimit will always report an error.
 	// However, if the system experiment is requested, we can use the system memory limit provider.
 	// This logic is similar to https://github.com/KimMachineGun/automemlimit/blob/main/memlimit/experiment.go
 	if v, ok := os.LookupEnv("AUTOMEMLIMIT_EXPERIMENT"); ok {
 		if slices.Contains(strings.Split(v, ","), "system") {
-			_, err := memlimit.SetGoMemLimitWithOpts(memlimit.WithProvider(memlimit.FromSystem), memlimit.WithLogger(slog.New(l.Handler())))
-			return err
+			return memlimit.SetGoMemLimitWithOpts(memlimit.WithProvider(memlimit.FromSystem), memlimit.WithRatio(ratio), memlimit.WithLogger(slog.New(l.Handler())))
 		}
 	}
 
-	return nil
+	return 0, nil
 }
@@ -10,7 +10,11 @@ import (
 	"github.com/grafana/alloy/internal/runtime/logging"
 )
 
-func applyAutoMemLimit(l *logging.Logger) error {
-	_, err := memlimit.SetGoMemLimitWithOpts(memlimit.WithLogger(slog.New(l.Handler())))
-	return err
+// applyAutoMemLimit sets GOMEMLIMIT to ratio of the memory limit of the
+// cgroup, unless ratio is 0, and returns the limit which was set.
+func applyAutoMemLimit(l *logging.Logger, ratio float64) (int64, error) {
+	if ratio == 0 {
+		return 0, nil
+	}
+	return memlimit.SetGoMemLimitWithOpts(memlimit.WithRatio(ratio), memlimit.WithLogger(slog.New(l.Handler())))
 }
@@ -19,7 +19,7 @@ func TestNoMemlimitErrorLogs(t *testing.T) {
 	l, err := logging.New(buffer, logging.DefaultOptions)
 	require.NoError(t, err)
 
-	applyAutoMemLimit(l)
+	applyAutoMemLimit(l, 0.9)
 
 	require.Equal(t, "", buffer.String())
 
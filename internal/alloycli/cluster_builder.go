@@ -25,6 +25,12 @@ type ClusterOptions struct {
 	EnableClustering       bool
 	MinimumClusterSize     int
 	MinimumSizeWaitTimeout time.Duration
+	DrainTimeout           time.Duration
+	HashingAlgorithm       string
+	RingTokens             int
+	ReplicationFactor      int
+	PartitionProtection    bool
+	Zone                   string
 	NodeName               string
 	AdvertiseAddress       string
 	ListenAddress          string
@@ -61,6 +67,12 @@ func NewClusterService(
 		EnableClustering:       opts.EnableClustering,
 		MinimumClusterSize:     opts.MinimumClusterSize,
 		MinimumSizeWaitTimeout: opts.MinimumSizeWaitTimeout,
+		DrainTimeout:           opts.DrainTimeout,
+		HashingAlgorithm:       opts.HashingAlgorithm,
+		RingTokens:             opts.RingTokens,
+		ReplicationFactor:      opts.ReplicationFactor,
+		PartitionProtection:    opts.PartitionProtection,
+		Zone:                   opts.Zone,
 		NodeName:               opts.NodeName,
 		RejoinInterval:         opts.RejoinInterval,
 		ClusterMaxJoinPeers:    opts.ClusterMaxJoinPeers,
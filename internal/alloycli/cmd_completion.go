@@ -0,0 +1,108 @@
+package alloycli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/grafana/alloy/internal/converter"
+	"github.com/grafana/alloy/internal/featuregate"
+)
+
+const (
+	completionBash       = "bash"
+	completionZsh        = "zsh"
+	completionFish       = "fish"
+	completionPowerShell = "powershell"
+)
+
+func completionCommand() *cobra.Command {
+	var noDescriptions bool
+
+	cmd := &cobra.Command{
+		Use:   "completion [flags] bash|zsh|fish|powershell",
+		Short: "Generate shell completion scripts",
+		Long: `The completion subcommand writes a completion script for the given shell
+to stdout.
+
+The completions include the subcommands and flags of Alloy, and the values of
+flags which accept a fixed set of values, like --stability.level, or the
+--source-format of convert. Flags which accept a component ID, like --id of
+prometheus.remote_write wal-dump, complete the IDs of components with data in
+the storage path.
+
+To load the completions in the current bash session, run:
+
+	source <(alloy completion bash)
+
+To load the completions in every new zsh session, run once:
+
+	alloy completion zsh > "${fpath[1]}/_alloy"
+
+To load the completions in every new fish session, run once:
+
+	alloy completion fish > ~/.config/fish/completions/alloy.fish`,
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		ValidArgs:             []string{completionBash, completionZsh, completionFish, completionPowerShell},
+		DisableFlagsInUseLine: true,
+		SilenceUsage:          true,
+
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return writeCompletion(cmd.Root(), os.Stdout, args[0], !noDescriptions)
+		},
+	}
+
+	cmd.Flags().BoolVar(&noDescriptions, "no-descriptions", noDescriptions, "Don't include descriptions of the completions")
+	return cmd
+}
+
+// writeCompletion writes the completion script of root for shell to w.
+func writeCompletion(root *cobra.Command, w io.Writer, shell string, descriptions bool) error {
+	switch shell {
+	case completionBash:
+		return root.GenBashCompletionV2(w, descriptions)
+	case completionZsh:
+		if descriptions {
+			return root.GenZshCompletion(w)
+		}
+		return root.GenZshCompletionNoDesc(w)
+	case completionFish:
+		return root.GenFishCompletion(w, descriptions)
+	case completionPowerShell:
+		if descriptions {
+			return root.GenPowerShellCompletionWithDesc(w)
+		}
+		return root.GenPowerShellCompletion(w)
+	default:
+		return fmt.Errorf("unsupported shell %q", shell)
+	}
+}
+
+// registerFlagValues registers the completions of the flag called name of
+// cmd, which are the given values. The flag must exist.
+func registerFlagValues(cmd *cobra.Command, name string, values ...string) {
+	err := cmd.RegisterFlagCompletionFunc(name, cobra.FixedCompletions(values, cobra.ShellCompDirectiveNoFileComp))
+	if err != nil {
+		panic(err)
+	}
+}
+
+// stabilityLevels returns the values accepted by --stability.level.
+func stabilityLevels() []string {
+	var levels []string
+	for _, v := range featuregate.AllowedValues() {
+		// AllowedValues are quoted to be used in help messages.
+		if level, err := strconv.Unquote(v); err == nil {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
+
+// configFormats returns the values accepted by --config.format.
+func configFormats() []string {
+	return append([]string{"alloy"}, converter.SupportedFormats...)
+}
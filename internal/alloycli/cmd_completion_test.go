@@ -0,0 +1,55 @@
+package alloycli
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompletion(t *testing.T) {
+	complete := func(t *testing.T, args ...string) []string {
+		root := &cobra.Command{Use: "alloy"}
+		root.AddCommand(completionCommand(), convertCommand(), runCommand(), validateCommand())
+
+		var out bytes.Buffer
+		root.SetOut(&out)
+		root.SetArgs(append([]string{cobra.ShellCompNoDescRequestCmd}, args...))
+		require.NoError(t, root.Execute())
+
+		// The last line holds the directive.
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		return lines[:len(lines)-1]
+	}
+
+	tests := []struct {
+		args   []string
+		expect []string
+	}{
+		{[]string{"completion", ""}, []string{"bash", "zsh", "fish", "powershell"}},
+		{[]string{"convert", "--source-format", ""}, []string{"otelcol", "prometheus", "promtail", "static"}},
+		{[]string{"run", "--config.format", ""}, []string{"alloy", "otelcol", "prometheus", "promtail", "static"}},
+		{[]string{"validate", "--stability.level", ""}, []string{"generally-available", "public-preview", "experimental"}},
+		{[]string{"run", "--cluster.hashing-algorithm", ""}, []string{"ring", "rendezvous", "maglev"}},
+		{[]string{"validate", "--report.format", ""}, []string{"text", "json"}},
+	}
+	for _, tc := range tests {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			require.Equal(t, tc.expect, complete(t, tc.args...))
+		})
+	}
+}
+
+func TestWriteCompletion(t *testing.T) {
+	root := &cobra.Command{Use: "alloy"}
+	root.AddCommand(completionCommand(), runCommand())
+
+	for _, shell := range []string{completionBash, completionZsh, completionFish, completionPowerShell} {
+		var out bytes.Buffer
+		require.NoError(t, writeCompletion(root, &out, shell, true), shell)
+		require.Contains(t, out.String(), "alloy", shell)
+	}
+	require.EqualError(t, writeCompletion(root, &bytes.Buffer{}, "tcsh", true), `unsupported shell "tcsh"`)
+}
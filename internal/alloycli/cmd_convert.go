@@ -2,11 +2,14 @@ package alloycli
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
@@ -22,6 +25,11 @@ func convertCommand() *cobra.Command {
 		sourceFormat: "",
 		bypassErrors: false,
 		extraArgs:    "",
+
+		sourceURLTimeout: 30 * time.Second,
+
+		stdout: os.Stdout,
+		stderr: os.Stderr,
 	}
 
 	cmd := &cobra.Command{
@@ -33,11 +41,17 @@ an Alloy configuration file.
 If the file argument is not supplied or if the file argument is "-", then
 convert will read from stdin.
 
+The --source-url flag can be used to download the config file with an HTTP
+GET request instead. The --source-url.header flag adds a header to the
+request, like "Authorization: Bearer ${TOKEN}". It can be repeated, and
+environment variables referenced in header values are expanded.
+
 The -o flag can be used to write the formatted file back to disk. When -o
-is not provided, convert will write the result to stdout.
+is not provided or is "-", convert will write the result to stdout.
 
 The -r flag can be used to generate a diagnostic report. When -r is not
-provided, no report is generated.
+provided, no report is generated. When -r is "-", the report is written
+to stderr.
 
 The -f flag can be used to specify the format we are converting from.
 
@@ -51,13 +65,18 @@ by separating them with a space.`,
 		Args:         cobra.RangeArgs(0, 1),
 		SilenceUsage: true,
 
-		RunE: func(_ *cobra.Command, args []string) error {
+		RunE: func(cmd *cobra.Command, args []string) error {
 			var err error
 
-			if len(args) == 0 {
+			switch {
+			case f.sourceURL != "" && len(args) > 0:
+				err = fmt.Errorf("a file can't be provided with --source-url")
+			case f.sourceURL != "":
+				err = f.RunURL(cmd.Context(), f.sourceURL)
+			case len(args) == 0:
 				// Read from stdin when there are no args provided.
 				err = f.Run("-")
-			} else {
+			default:
 				err = f.Run(args[0])
 			}
 
@@ -73,11 +92,15 @@ by separating them with a space.`,
 		},
 	}
 
-	cmd.Flags().StringVarP(&f.output, "output", "o", f.output, "The filepath and filename where the output is written.")
-	cmd.Flags().StringVarP(&f.report, "report", "r", f.report, "The filepath and filename where the report is written.")
+	cmd.Flags().StringVarP(&f.output, "output", "o", f.output, "The filepath and filename where the output is written. Use - to write to stdout.")
+	cmd.Flags().StringVarP(&f.report, "report", "r", f.report, "The filepath and filename where the report is written. Use - to write to stderr.")
 	cmd.Flags().StringVarP(&f.sourceFormat, "source-format", "f", f.sourceFormat, fmt.Sprintf("The format of the source file. Supported formats: %s.", supportedFormatsList()))
 	cmd.Flags().BoolVarP(&f.bypassErrors, "bypass-errors", "b", f.bypassErrors, "Enable bypassing errors when converting")
 	cmd.Flags().StringVarP(&f.extraArgs, "extra-args", "e", f.extraArgs, "Extra arguments from the original format used by the converter. Multiple arguments can be passed by separating them with a space.")
+	cmd.Flags().StringVar(&f.sourceURL, "source-url", f.sourceURL, "The URL to download the source file from, instead of reading a file.")
+	cmd.Flags().StringArrayVar(&f.sourceURLHeaders, "source-url.header", f.sourceURLHeaders, "A header to send when downloading the source file, like \"Authorization: Bearer ${TOKEN}\". Environment variables are expanded. Can be repeated.")
+	cmd.Flags().DurationVar(&f.sourceURLTimeout, "source-url.timeout", f.sourceURLTimeout, "The timeout of the download of the source file.")
+	registerFlagValues(cmd, "source-format", converter.SupportedFormats...)
 	return cmd
 }
 
@@ -87,6 +110,12 @@ type alloyConvert struct {
 	sourceFormat string
 	bypassErrors bool
 	extraArgs    string
+
+	sourceURL        string
+	sourceURLHeaders []string
+	sourceURLTimeout time.Duration
+
+	stdout, stderr io.Writer
 }
 
 func (fc *alloyConvert) Run(configFile string) error {
@@ -114,6 +143,37 @@ func (fc *alloyConvert) Run(configFile string) error {
 	return convert(f, fc)
 }
 
+// RunURL converts the config file downloaded from url.
+func (fc *alloyConvert) RunURL(ctx context.Context, url string) error {
+	if fc.sourceFormat == "" {
+		return fmt.Errorf("source-format is a required flag")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("invalid source URL: %w", err)
+	}
+	for _, h := range fc.sourceURLHeaders {
+		name, value, ok := strings.Cut(h, ":")
+		if !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf("invalid header %q, must be formatted as \"Name: value\"", h)
+		}
+		req.Header.Add(strings.TrimSpace(name), strings.TrimSpace(os.ExpandEnv(value)))
+	}
+
+	client := &http.Client{Timeout: fc.sourceURLTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("downloading the source file: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("downloading the source file: unexpected status %s", resp.Status)
+	}
+	return convert(resp.Body, fc)
+}
+
 func convert(r io.Reader, fc *alloyConvert) error {
 	inputBytes, err := io.ReadAll(r)
 	if err != nil {
@@ -140,8 +200,8 @@ func convert(r io.Reader, fc *alloyConvert) error {
 	var buf bytes.Buffer
 	buf.WriteString(string(alloyBytes))
 
-	if fc.output == "" {
-		_, err := io.Copy(os.Stdout, &buf)
+	if fc.output == "" || fc.output == "-" {
+		_, err := io.Copy(fc.stdout, &buf)
 		return err
 	}
 
@@ -156,6 +216,9 @@ func convert(r io.Reader, fc *alloyConvert) error {
 }
 
 func generateConvertReport(diags convert_diag.Diagnostics, fc *alloyConvert) error {
+	if fc.report == "-" {
+		return diags.GenerateReport(fc.stderr, convert_diag.Text, fc.bypassErrors)
+	}
 	if fc.report != "" {
 		file, err := os.Create(fc.report)
 		if err != nil {
@@ -1,6 +1,10 @@
 package alloycli
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -84,3 +88,43 @@ func TestParseExtraArgs(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertRunURL(t *testing.T) {
+	t.Setenv("CONVERT_TEST_TOKEN", "s3cr3t")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer s3cr3t" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`
+scrape_configs:
+  - job_name: example
+    static_configs:
+      - targets: ["localhost:9090"]
+`))
+	}))
+	defer srv.Close()
+
+	newConvert := func(headers ...string) (*alloyConvert, *strings.Builder) {
+		var stdout strings.Builder
+		return &alloyConvert{
+			output:           "-",
+			sourceFormat:     "prometheus",
+			sourceURLHeaders: headers,
+			stdout:           &stdout,
+			stderr:           &strings.Builder{},
+		}, &stdout
+	}
+
+	fc, stdout := newConvert("Authorization: Bearer ${CONVERT_TEST_TOKEN}")
+	require.NoError(t, fc.RunURL(context.Background(), srv.URL))
+	require.Contains(t, stdout.String(), `prometheus.scrape "example" {`)
+	require.Contains(t, stdout.String(), `__address__ = "localhost:9090"`)
+
+	fc, _ = newConvert()
+	require.EqualError(t, fc.RunURL(context.Background(), srv.URL), "downloading the source file: unexpected status 401 Unauthorized")
+
+	fc, _ = newConvert("Authorization")
+	require.EqualError(t, fc.RunURL(context.Background(), srv.URL), `invalid header "Authorization", must be formatted as "Name: value"`)
+}
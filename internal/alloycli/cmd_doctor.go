@@ -0,0 +1,106 @@
+package alloycli
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/grafana/alloy/internal/doctor"
+	alloy_runtime "github.com/grafana/alloy/internal/runtime"
+	"github.com/grafana/alloy/syntax/ast"
+)
+
+func doctorCommand() *cobra.Command {
+	defaults := newAlloyRun()
+	d := &alloyDoctor{
+		httpListenAddr: defaults.httpListenAddr,
+		storagePath:    defaults.storagePath,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "doctor [flags] [path]",
+		Short: "Check the environment for common issues",
+		Long: `The doctor subcommand checks the environment for issues which prevent
+Alloy or some of its components from working, and reports how to fix them.
+
+doctor checks that the HTTP listen address is available, that the storage
+path is writable, the limit of open files, and the synchronization of the
+system clock. Run it with the same user, flags, and environment as Alloy,
+before starting Alloy.
+
+If a configuration path is provided, doctor also checks the kernel version,
+kernel parameters, and capabilities needed by the eBPF components of the
+configuration. Without a configuration path, these checks always run, and
+report warnings instead of errors.
+
+doctor exits with a non-zero status code if any check reports an error.`,
+		Args:         cobra.MaximumNArgs(1),
+		SilenceUsage: true,
+
+		RunE: func(_ *cobra.Command, args []string) error {
+			return d.Run(args)
+		},
+	}
+
+	cmd.Flags().StringVar(&d.httpListenAddr, "server.http.listen-addr", d.httpListenAddr, "Address to listen for HTTP traffic on")
+	cmd.Flags().StringVar(&d.storagePath, "storage.path", d.storagePath, "Base directory where components can store data")
+	cmd.Flags().BoolVar(&d.configExpandEnv, "config.expand-env", d.configExpandEnv, "Expand references to environment variables in config files before parsing them")
+	return cmd
+}
+
+type alloyDoctor struct {
+	httpListenAddr  string
+	storagePath     string
+	configExpandEnv bool
+}
+
+func (d *alloyDoctor) Run(configPaths []string) error {
+	opts := doctor.Options{
+		HTTPListenAddr: d.httpListenAddr,
+		StoragePath:    d.storagePath,
+	}
+	if len(configPaths) > 0 {
+		sources, err := loadConfigSources(configPaths, "alloy", false, "", d.configExpandEnv)
+		if err != nil {
+			return err
+		}
+		source, err := alloy_runtime.ParseSources(sources)
+		if err != nil {
+			return fmt.Errorf("parsing config: %w", err)
+		}
+		opts.EBPFComponents = ebpfComponents(source)
+		opts.SkipEBPF = len(opts.EBPFComponents) == 0
+	}
+
+	worst, err := doctor.Write(os.Stdout, doctor.Run(doctor.Checks(opts)))
+	if err != nil {
+		return err
+	}
+	if worst == doctor.StatusError {
+		return fmt.Errorf("found issues which prevent Alloy from working")
+	}
+	return nil
+}
+
+// ebpfComponentNames lists the components relying on eBPF.
+var ebpfComponentNames = []string{"beyla.ebpf", "pyroscope.ebpf"}
+
+// ebpfComponents returns the sorted IDs of the top-level components of
+// source relying on eBPF.
+func ebpfComponents(source *alloy_runtime.Source) []string {
+	var ids []string
+	for _, f := range source.SourceFiles() {
+		for _, stmt := range f.Body {
+			b, ok := stmt.(*ast.BlockStmt)
+			if !ok || !slices.Contains(ebpfComponentNames, strings.Join(b.Name, ".")) {
+				continue
+			}
+			ids = append(ids, strings.Join(b.Name, ".")+"."+b.Label)
+		}
+	}
+	slices.Sort(ids)
+	return ids
+}
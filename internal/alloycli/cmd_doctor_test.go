@@ -0,0 +1,31 @@
+package alloycli
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	alloy_runtime "github.com/grafana/alloy/internal/runtime"
+)
+
+func TestEBPFComponents(t *testing.T) {
+	source, err := alloy_runtime.ParseSources(map[string][]byte{
+		"a.alloy": []byte(`
+			pyroscope.ebpf "default" {
+				forward_to = []
+				targets    = []
+			}
+			logging {}
+		`),
+		"b.alloy": []byte(`
+			beyla.ebpf "default" { }
+			prometheus.exporter.self "default" { }
+		`),
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"beyla.ebpf.default", "pyroscope.ebpf.default"}, ebpfComponents(source))
+
+	source, err = alloy_runtime.ParseSource("c.alloy", []byte(`logging {}`))
+	require.NoError(t, err)
+	require.Empty(t, ebpfComponents(source))
+}
@@ -10,6 +10,10 @@ import (
 
 	"github.com/spf13/cobra"
 
+	"github.com/grafana/alloy/internal/canonical"
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/rewrite"
+	"github.com/grafana/alloy/syntax/ast"
 	"github.com/grafana/alloy/syntax/diag"
 	"github.com/grafana/alloy/syntax/parser"
 	"github.com/grafana/alloy/syntax/printer"
@@ -29,7 +33,11 @@ specified configuration file.
 
 If the file argument is not supplied or if the file argument is "-", then fmt will read from stdin.
 
-The -w flag can be used to write the formatted file back to disk. -w can not be provided when fmt is reading from stdin. When -w is not provided, fmt will write the result to stdout.`,
+The -w flag can be used to write the formatted file back to disk. -w can not be provided when fmt is reading from stdin. When -w is not provided, fmt will write the result to stdout.
+
+The --fix flag rewrites deprecated syntax, like renamed arguments, before formatting. A summary of the rewrites is written to stderr, including the ones which must be done manually.
+
+The --canonical flag also orders attributes and blocks consistently: required arguments of components first, then optional arguments, in alphabetical order, then blocks, and groups top-level blocks by kind, sorted by name and label. Comments are moved with the statements they describe.`,
 		Args:         cobra.RangeArgs(0, 1),
 		SilenceUsage: true,
 		Aliases:      []string{"format"},
@@ -58,12 +66,16 @@ The -w flag can be used to write the formatted file back to disk. -w can not be
 
 	cmd.Flags().BoolVarP(&f.write, "write", "w", f.write, "write result to (source) file instead of stdout")
 	cmd.Flags().BoolVarP(&f.test, "test", "t", f.test, "exit with non-zero when changes would be made. Cannot be used with -w/--write")
+	cmd.Flags().BoolVar(&f.fix, "fix", f.fix, "rewrite deprecated syntax before formatting")
+	cmd.Flags().BoolVar(&f.canonical, "canonical", f.canonical, "order attributes and blocks canonically")
 	return cmd
 }
 
 type alloyFmt struct {
-	write bool
-	test  bool
+	write     bool
+	test      bool
+	fix       bool
+	canonical bool
 }
 
 func (ff *alloyFmt) Run(configFile string) error {
@@ -76,7 +88,7 @@ func (ff *alloyFmt) Run(configFile string) error {
 		if ff.write {
 			return fmt.Errorf("cannot use -w with standard input")
 		}
-		return format("<stdin>", nil, os.Stdin, false, ff.test)
+		return ff.format("<stdin>", nil, os.Stdin)
 
 	default:
 		fi, err := os.Stat(configFile)
@@ -92,11 +104,11 @@ func (ff *alloyFmt) Run(configFile string) error {
 			return err
 		}
 		defer f.Close()
-		return format(configFile, fi, f, ff.write, ff.test)
+		return ff.format(configFile, fi, f)
 	}
 }
 
-func format(filename string, fi os.FileInfo, r io.Reader, write bool, test bool) error {
+func (ff *alloyFmt) format(filename string, fi os.FileInfo, r io.Reader) error {
 	bb, err := io.ReadAll(r)
 	if err != nil {
 		return err
@@ -107,6 +119,18 @@ func format(filename string, fi os.FileInfo, r io.Reader, write bool, test bool)
 		return err
 	}
 
+	if ff.fix {
+		changes := rewrite.Apply(f, rewrite.Rules())
+		printRewriteSummary(os.Stderr, changes)
+	}
+
+	if ff.canonical {
+		f, err = orderCanonically(f)
+		if err != nil {
+			return err
+		}
+	}
+
 	var buf bytes.Buffer
 	if err := printer.Fprint(&buf, f); err != nil {
 		return err
@@ -116,14 +140,14 @@ func format(filename string, fi os.FileInfo, r io.Reader, write bool, test bool)
 	_, _ = buf.Write([]byte{'\n'})
 
 	// If -t/--test flag is check, only check if file is formatted correctly
-	if test {
+	if ff.test {
 		if !reflect.DeepEqual(bb, buf.Bytes()) {
 			return fmt.Errorf("file %s is not formatted correctly", filename)
 		}
 		return nil
 	}
 
-	if !write {
+	if !ff.write {
 		_, err := io.Copy(os.Stdout, &buf)
 		return err
 	}
@@ -137,3 +161,39 @@ func format(filename string, fi os.FileInfo, r io.Reader, write bool, test bool)
 	_, err = io.Copy(wf, &buf)
 	return err
 }
+
+// orderCanonically returns f with its statements in canonical order.
+func orderCanonically(f *ast.File) (*ast.File, error) {
+	// f is printed first, so that the source matches the rewrites of --fix.
+	var src bytes.Buffer
+	if err := printer.Fprint(&src, f); err != nil {
+		return nil, err
+	}
+	ordered, err := canonical.Order(f.Name, src.Bytes(), componentArgs)
+	if err != nil {
+		return nil, err
+	}
+	return parser.ParseFile(f.Name, ordered)
+}
+
+// componentArgs returns the arguments of the registered component called
+// name.
+func componentArgs(name string) (any, bool) {
+	reg, ok := component.Get(name)
+	if !ok {
+		return nil, false
+	}
+	return reg.Args, true
+}
+
+// printRewriteSummary writes the changes made by rewrite rules to w.
+func printRewriteSummary(w io.Writer, changes []rewrite.Change) {
+	var manual int
+	for _, c := range changes {
+		if c.Manual {
+			manual++
+		}
+		fmt.Fprintln(w, c)
+	}
+	fmt.Fprintf(w, "%d deprecated syntax rewritten, %d requiring a manual fix\n", len(changes)-manual, manual)
+}
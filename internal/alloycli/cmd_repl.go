@@ -0,0 +1,428 @@
+package alloycli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/grafana/alloy/syntax/ast"
+	"github.com/grafana/alloy/syntax/parser"
+	"github.com/grafana/alloy/syntax/scanner"
+	"github.com/grafana/alloy/syntax/token"
+	"github.com/grafana/alloy/syntax/token/builder"
+	"github.com/grafana/alloy/syntax/vm"
+)
+
+func replCommand() *cobra.Command {
+	r := &alloyRepl{
+		uiPrefix: "/",
+		timeout:  10 * time.Second,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "repl [flags]",
+		Short: "Evaluate expressions interactively",
+		Long: `The repl subcommand starts an interactive session which evaluates Alloy
+expressions, like the ones used as component arguments.
+
+Expressions can call the functions of the standard library, like sys.env or
+string.format. Assign the result of an expression to a name with "name = expr"
+to reuse it in later expressions. Expressions spanning several lines are
+evaluated once all their brackets are closed.
+
+The --instance flag sets the URL of a running instance, like
+http://127.0.0.1:12345. The exports of the components of its root module can
+then be referenced by ID, like local.file.example.content.
+
+Type :help in the session to list the available commands.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return r.Run(cmd.Context(), os.Stdin, os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVar(&r.instance, "instance", r.instance, "URL of a running instance to load component exports from")
+	cmd.Flags().StringVar(&r.uiPrefix, "server.http.ui-path-prefix", r.uiPrefix, "Prefix the UI of the instance is served at")
+	cmd.Flags().DurationVar(&r.timeout, "instance.timeout", r.timeout, "Timeout of the requests to the instance")
+	return cmd
+}
+
+type alloyRepl struct {
+	instance string
+	uiPrefix string
+	timeout  time.Duration
+}
+
+// Run runs a session reading input from in and writing results to out until
+// in is exhausted or the session is quit.
+func (r *alloyRepl) Run(ctx context.Context, in io.Reader, out io.Writer) error {
+	s := &replSession{
+		variables: map[string]any{},
+		exports:   map[string]any{},
+	}
+	if r.instance != "" {
+		apiURL, err := url.JoinPath(r.instance, r.uiPrefix, "api/v0/web")
+		if err != nil {
+			return fmt.Errorf("invalid instance URL: %w", err)
+		}
+		s.loadExports = func() (map[string]any, []string, error) {
+			ctx, cancel := context.WithTimeout(ctx, r.timeout)
+			defer cancel()
+			return fetchExports(ctx, http.DefaultClient, apiURL)
+		}
+		if err := s.reload(); err != nil {
+			return fmt.Errorf("loading exports from %s: %w", r.instance, err)
+		}
+		fmt.Fprintf(out, "Loaded the exports of %d components from %s.\n", len(s.components), r.instance)
+	}
+	return s.run(in, out)
+}
+
+const replHelp = `Enter an expression to evaluate it, or "name = expr" to assign its result.
+
+Commands:
+  :help    Print this help.
+  :vars    List the assigned names and the loaded components.
+  :reload  Reload the exports of the components of the instance.
+  :quit    Quit the session.
+`
+
+// replSession evaluates the input of a REPL session.
+type replSession struct {
+	// variables holds the values assigned during the session.
+	variables map[string]any
+	// exports holds the exports of the components of the instance, nested by
+	// the parts of their IDs.
+	exports    map[string]any
+	components []string
+	// loadExports loads the exports of the components of the instance. It is
+	// nil when the session isn't connected to an instance.
+	loadExports func() (map[string]any, []string, error)
+}
+
+func (s *replSession) run(in io.Reader, out io.Writer) error {
+	var (
+		lines   = bufio.NewScanner(in)
+		pending strings.Builder
+	)
+	fmt.Fprint(out, "> ")
+	for lines.Scan() {
+		if pending.Len() > 0 {
+			pending.WriteByte('\n')
+		}
+		pending.WriteString(lines.Text())
+
+		input := pending.String()
+		if openBrackets(input) > 0 {
+			fmt.Fprint(out, "... ")
+			continue
+		}
+		pending.Reset()
+
+		quit, err := s.handle(strings.TrimSpace(input), out)
+		if err != nil {
+			fmt.Fprintf(out, "Error: %s\n", err)
+		}
+		if quit {
+			return nil
+		}
+		fmt.Fprint(out, "> ")
+	}
+	fmt.Fprintln(out)
+	return lines.Err()
+}
+
+// handle handles a single command or expression. handle returns true when the
+// session must be quit.
+func (s *replSession) handle(input string, out io.Writer) (bool, error) {
+	switch input {
+	case "":
+		return false, nil
+	case ":quit", ":q":
+		return true, nil
+	case ":help":
+		fmt.Fprint(out, replHelp)
+		return false, nil
+	case ":vars":
+		s.printVars(out)
+		return false, nil
+	case ":reload":
+		if s.loadExports == nil {
+			return false, fmt.Errorf("not connected to an instance, use the --instance flag")
+		}
+		if err := s.reload(); err != nil {
+			return false, err
+		}
+		fmt.Fprintf(out, "Loaded the exports of %d components.\n", len(s.components))
+		return false, nil
+	}
+	if strings.HasPrefix(input, ":") {
+		return false, fmt.Errorf("unknown command %s, type :help to list the commands", input)
+	}
+
+	name, expr, err := parseReplInput(input)
+	if err != nil {
+		return false, err
+	}
+	var val any
+	if err := vm.New(expr).Evaluate(vm.NewScope(s.scope()), &val); err != nil {
+		return false, err
+	}
+	if name != "" {
+		s.variables[name] = val
+		return false, nil
+	}
+	fmt.Fprintln(out, formatReplValue(val))
+	return false, nil
+}
+
+func (s *replSession) reload() error {
+	exports, components, err := s.loadExports()
+	if err != nil {
+		return err
+	}
+	s.exports, s.components = exports, components
+	return nil
+}
+
+// scope returns the variables expressions are evaluated with. Assigned names
+// shadow the components of the instance.
+func (s *replSession) scope() map[string]any {
+	scope := make(map[string]any, len(s.exports)+len(s.variables))
+	for k, v := range s.exports {
+		scope[k] = v
+	}
+	for k, v := range s.variables {
+		scope[k] = v
+	}
+	return scope
+}
+
+func (s *replSession) printVars(out io.Writer) {
+	names := make([]string, 0, len(s.variables))
+	for name := range s.variables {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		fmt.Fprintf(out, "%s = %s\n", name, formatReplValue(s.variables[name]))
+	}
+	for _, id := range s.components {
+		fmt.Fprintf(out, "%s (component)\n", id)
+	}
+}
+
+// parseReplInput parses an expression, or an assignment of an expression to
+// a name. name is empty for expressions.
+func parseReplInput(input string) (name string, expr ast.Expr, err error) {
+	expr, exprErr := parser.ParseExpression(input)
+	if exprErr == nil {
+		return "", expr, nil
+	}
+
+	f, err := parser.ParseFile("repl", []byte(input))
+	if err != nil || len(f.Body) != 1 {
+		return "", nil, exprErr
+	}
+	attr, ok := f.Body[0].(*ast.AttributeStmt)
+	if !ok {
+		return "", nil, exprErr
+	}
+	return attr.Name.Name, attr.Value, nil
+}
+
+// openBrackets returns the number of brackets opened and not closed yet in
+// input.
+func openBrackets(input string) int {
+	var (
+		depth int
+		s     = scanner.New(token.NewFile("repl"), []byte(input), nil, 0)
+	)
+	for {
+		_, tok, _ := s.Scan()
+		switch tok {
+		case token.EOF:
+			return depth
+		case token.LPAREN, token.LBRACK, token.LCURLY:
+			depth++
+		case token.RPAREN, token.RBRACK, token.RCURLY:
+			depth--
+		}
+	}
+}
+
+func formatReplValue(val any) string {
+	if val != nil && reflect.TypeOf(val).Kind() == reflect.Func {
+		return "function"
+	}
+	expr := builder.NewExpr()
+	expr.SetValue(val)
+	return string(expr.Bytes())
+}
+
+// fetchExports returns the exports of the components of the root module of
+// the instance serving its API at apiURL, nested by the parts of the
+// component IDs so that expressions can reference them like in a
+// configuration file. fetchExports also returns the sorted IDs of the
+// components.
+func fetchExports(ctx context.Context, client *http.Client, apiURL string) (map[string]any, []string, error) {
+	var components []struct {
+		LocalID string `json:"localID"`
+	}
+	if err := getJSON(ctx, client, apiURL+"/components", &components); err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		exports = map[string]any{}
+		ids     = make([]string, 0, len(components))
+	)
+	for _, c := range components {
+		var detail struct {
+			Exports []alloyJSONStatement `json:"exports"`
+		}
+		if err := getJSON(ctx, client, apiURL+"/components/"+c.LocalID, &detail); err != nil {
+			return nil, nil, err
+		}
+		ids = append(ids, c.LocalID)
+
+		parts := strings.Split(c.LocalID, ".")
+		parent := exports
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := parent[part].(map[string]any)
+			if !ok {
+				child = map[string]any{}
+				parent[part] = child
+			}
+			parent = child
+		}
+		parent[parts[len(parts)-1]] = decodeAlloyJSONBody(detail.Exports)
+	}
+	slices.Sort(ids)
+	return exports, ids, nil
+}
+
+func getJSON(ctx context.Context, client *http.Client, url string, v any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s: unexpected status %s", url, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		return fmt.Errorf("GET %s: decoding response: %w", url, err)
+	}
+	return nil
+}
+
+// alloyJSONStatement is an attribute or a block of a body encoded by
+// alloyjson.
+type alloyJSONStatement struct {
+	Name  string               `json:"name"`
+	Type  string               `json:"type"`
+	Label string               `json:"label"`
+	Value alloyJSONValue       `json:"value"`
+	Body  []alloyJSONStatement `json:"body"`
+}
+
+// alloyJSONValue is a value encoded by alloyjson.
+type alloyJSONValue struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// decodeAlloyJSONBody decodes a body as an object. Blocks are decoded as
+// objects keyed by their name and label, and repeated blocks as arrays of
+// objects.
+func decodeAlloyJSONBody(stmts []alloyJSONStatement) map[string]any {
+	obj := make(map[string]any, len(stmts))
+	for _, stmt := range stmts {
+		if stmt.Type != "block" {
+			obj[stmt.Name] = decodeAlloyJSONValue(stmt.Value)
+			continue
+		}
+
+		var (
+			body   = decodeAlloyJSONBody(stmt.Body)
+			parent = obj
+			key    = stmt.Name
+		)
+		if stmt.Label != "" {
+			labels, ok := obj[stmt.Name].(map[string]any)
+			if !ok {
+				labels = map[string]any{}
+				obj[stmt.Name] = labels
+			}
+			parent, key = labels, stmt.Label
+		}
+		switch existing := parent[key].(type) {
+		case nil:
+			parent[key] = body
+		case []any:
+			parent[key] = append(existing, body)
+		default:
+			parent[key] = []any{existing, body}
+		}
+	}
+	return obj
+}
+
+// decodeAlloyJSONValue decodes a value. Capsules and functions can't be
+// decoded and are replaced by their description.
+func decodeAlloyJSONValue(v alloyJSONValue) any {
+	switch v.Type {
+	case "number":
+		var f float64
+		_ = json.Unmarshal(v.Value, &f)
+		return f
+	case "string", "capsule", "function":
+		var s string
+		_ = json.Unmarshal(v.Value, &s)
+		return s
+	case "bool":
+		var b bool
+		_ = json.Unmarshal(v.Value, &b)
+		return b
+	case "array":
+		var elems []alloyJSONValue
+		_ = json.Unmarshal(v.Value, &elems)
+		arr := make([]any, 0, len(elems))
+		for _, elem := range elems {
+			arr = append(arr, decodeAlloyJSONValue(elem))
+		}
+		return arr
+	case "object":
+		var fields []struct {
+			Key   string         `json:"key"`
+			Value alloyJSONValue `json:"value"`
+		}
+		_ = json.Unmarshal(v.Value, &fields)
+		obj := make(map[string]any, len(fields))
+		for _, field := range fields {
+			obj[field.Key] = decodeAlloyJSONValue(field.Value)
+		}
+		return obj
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,131 @@
+package alloycli
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/syntax/encoding/alloyjson"
+)
+
+func TestReplSession(t *testing.T) {
+	t.Setenv("REPL_TEST_HOST", "example.com")
+
+	s := &replSession{
+		variables: map[string]any{},
+		exports: map[string]any{
+			"local": map[string]any{"file": map[string]any{"example": map[string]any{"content": "hello"}}},
+		},
+		components: []string{"local.file.example"},
+	}
+
+	input := strings.Join([]string{
+		`string.format("%s:%d", sys.env("REPL_TEST_HOST"), 443)`,
+		`local.file.example.content`,
+		`targets = [`,
+		`  {__address__ = "a:80"},`,
+		`  {__address__ = "b:80"},`,
+		`]`,
+		`targets[1].__address__`,
+		`local = "shadowed"`,
+		`local`,
+		`:vars`,
+		`1 +`,
+		`:reload`,
+		`:unknown`,
+		`:quit`,
+		`"never evaluated"`,
+	}, "\n")
+
+	var out strings.Builder
+	require.NoError(t, s.run(strings.NewReader(input), &out))
+	require.Equal(t, `> "example.com:443"
+> "hello"
+> ... ... ... > "b:80"
+> > "shadowed"
+> local = "shadowed"
+targets = [{
+	__address__ = "a:80",
+}, {
+	__address__ = "b:80",
+}]
+local.file.example (component)
+> Error: 1:4: expected expression, got EOF
+> Error: not connected to an instance, use the --instance flag
+> Error: unknown command :unknown, type :help to list the commands
+> `, out.String())
+}
+
+func TestReplSession_Function(t *testing.T) {
+	s := &replSession{variables: map[string]any{}}
+
+	var out strings.Builder
+	require.NoError(t, s.run(strings.NewReader("encoding.from_json\n"), &out))
+	require.Equal(t, "> function\n> \n", out.String())
+}
+
+func TestFetchExports(t *testing.T) {
+	type target struct {
+		Name string `alloy:",label"`
+		URL  string `alloy:"url,attr"`
+	}
+	type exports struct {
+		Content string            `alloy:"content,attr"`
+		Labels  map[string]string `alloy:"labels,attr"`
+		Ports   []int             `alloy:"ports,attr"`
+		Enabled bool              `alloy:"enabled,attr"`
+		Targets []target          `alloy:"target,block"`
+	}
+	body, err := alloyjson.MarshalBody(exports{
+		Content: "hello",
+		Labels:  map[string]string{"env": "prod"},
+		Ports:   []int{80, 443},
+		Enabled: true,
+		Targets: []target{{Name: "a", URL: "http://a"}},
+	})
+	require.NoError(t, err)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ui/api/v0/web/components", func(w http.ResponseWriter, _ *http.Request) {
+		_ = json.NewEncoder(w).Encode([]map[string]string{
+			{"localID": "local.file.b"},
+			{"localID": "local.file.a"},
+		})
+	})
+	mux.HandleFunc("/ui/api/v0/web/components/local.file.a", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"localID": "local.file.a", "exports": ` + string(body) + `}`))
+	})
+	mux.HandleFunc("/ui/api/v0/web/components/local.file.b", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte(`{"localID": "local.file.b"}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	actual, ids, err := fetchExports(context.Background(), srv.Client(), srv.URL+"/ui/api/v0/web")
+	require.NoError(t, err)
+	require.Equal(t, []string{"local.file.a", "local.file.b"}, ids)
+	require.Equal(t, map[string]any{
+		"local": map[string]any{
+			"file": map[string]any{
+				"a": map[string]any{
+					"content": "hello",
+					"labels":  map[string]any{"env": "prod"},
+					"ports":   []any{float64(80), float64(443)},
+					"enabled": true,
+					"target": map[string]any{
+						"a": map[string]any{"url": "http://a"},
+					},
+				},
+				"b": map[string]any{},
+			},
+		},
+	}, actual)
+
+	_, _, err = fetchExports(context.Background(), srv.Client(), srv.URL+"/missing")
+	require.ErrorContains(t, err, "unexpected status 404 Not Found")
+}
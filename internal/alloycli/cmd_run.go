@@ -1,6 +1,7 @@
 package alloycli
 
 import (
+	"cmp"
 	"context"
 	"crypto/sha256"
 	"errors"
@@ -14,6 +15,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -26,6 +28,7 @@ import (
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/exp/maps"
 
 	"github.com/grafana/alloy/internal/alloyseed"
@@ -39,14 +42,18 @@ import (
 	"github.com/grafana/alloy/internal/runtime/logging/level"
 	"github.com/grafana/alloy/internal/runtime/tracing"
 	"github.com/grafana/alloy/internal/service"
+	"github.com/grafana/alloy/internal/service/cluster"
 	httpservice "github.com/grafana/alloy/internal/service/http"
 	"github.com/grafana/alloy/internal/service/labelstore"
 	"github.com/grafana/alloy/internal/service/livedebugging"
+	opampservice "github.com/grafana/alloy/internal/service/opamp"
 	otel_service "github.com/grafana/alloy/internal/service/otel"
 	remotecfgservice "github.com/grafana/alloy/internal/service/remotecfg"
+	"github.com/grafana/alloy/internal/service/secrets"
 	uiservice "github.com/grafana/alloy/internal/service/ui"
 	"github.com/grafana/alloy/internal/static/config/instrumentation"
 	"github.com/grafana/alloy/internal/usagestats"
+	"github.com/grafana/alloy/internal/util/envsubst"
 	"github.com/grafana/alloy/internal/util/windowspriority"
 	"github.com/grafana/alloy/syntax/diag"
 
@@ -60,27 +67,14 @@ var (
 )
 
 func runCommand() *cobra.Command {
-	r := &alloyRun{
-		inMemoryAddr:          "alloy.internal:12345",
-		httpListenAddr:        "127.0.0.1:12345",
-		storagePath:           "data-alloy/",
-		minStability:          featuregate.StabilityGenerallyAvailable,
-		uiPrefix:              "/",
-		disableReporting:      false,
-		enablePprof:           true,
-		configFormat:          "alloy",
-		clusterAdvInterfaces:  advertise.DefaultInterfaces,
-		clusterMaxJoinPeers:   5,
-		clusterRejoinInterval: 60 * time.Second,
-		disableSupportBundle:  false,
-		// For backwards compatibility - use the LegacyValidation of Prometheus metrics name. This is a global variable
-		// setting that has changed upstream. See https://github.com/prometheus/common/pull/724.
-		prometheusMetricNameValidationScheme: prometheusLegacyMetricValidationScheme,
-		windowsPriority:                      windowspriority.PriorityNormal,
-	}
+	var (
+		r           = newAlloyRun()
+		configFiles []string
+		dryRun      bool
+	)
 
 	cmd := &cobra.Command{
-		Use:   "run [flags] path",
+		Use:   "run [flags] [path]",
 		Short: "Run Grafana Alloy",
 		Long: `The run subcommand runs Grafana Alloy in the foreground until an interrupt
 is received.
@@ -93,6 +87,11 @@ immediately.
 If path is a directory, all *.alloy files in that directory will be combined
 into a single unit. Subdirectories are not recursively searched for further merging.
 
+Additional configuration directories or file paths can be provided with the
+--config.file flag, which can be repeated. Their blocks are combined with the
+blocks of path into a single unit, in the order of the file names. The path
+argument can be omitted when --config.file is provided.
+
 run starts an HTTP server which can be used to debug Grafana Alloy or
 force it to reload (by sending a GET or POST request to /-/reload). The listen
 address can be changed through the --server.http.listen-addr flag.
@@ -107,78 +106,156 @@ Additionally, the HTTP server exposes the following debug endpoints:
 
 If reloading the config dir/file-path fails, Grafana Alloy will continue running in
 its last valid state. Components which failed may be be listed as unhealthy,
-depending on the nature of the reload error.
+depending on the nature of the reload error. With --reload.rollback-on-error,
+Grafana Alloy instead reapplies the last config which was applied successfully.
+--reload.timeout limits how long a reload can take before it's reported as
+failed.
+
+With --dry-run, run loads the configuration like validate, without starting
+the HTTP server or any component, writes the components to stdout in the
+order they're evaluated, each followed by the components it references, and
+exits. run exits with a non-zero status code if the configuration contains
+errors.
 `,
-		Args:         cobra.ExactArgs(1),
+		Args:         cobra.MaximumNArgs(1),
 		SilenceUsage: true,
 
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return r.Run(cmd, args[0])
+			if dryRun {
+				return r.DryRun(append(args, configFiles...), os.Stdout, os.Stderr)
+			}
+			return r.Run(cmd, append(args, configFiles...))
 		},
 	}
 
+	r.addFlags(cmd)
+	addDeprecatedFlags(cmd)
+	cmd.Flags().StringArrayVar(&configFiles, "config.file", nil, "Additional configuration directory or file path to combine with path. Can be repeated")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", dryRun, "Validate the configuration and print its components without starting them, then exit")
+	return cmd
+}
+
+// newAlloyRun returns an alloyRun with the default values of its flags.
+func newAlloyRun() *alloyRun {
+	return &alloyRun{
+		inMemoryAddr:             "alloy.internal:12345",
+		httpListenAddr:           "127.0.0.1:12345",
+		storagePath:              "data-alloy/",
+		minStability:             featuregate.StabilityGenerallyAvailable,
+		uiPrefix:                 "/",
+		disableReporting:         false,
+		enablePprof:              true,
+		configFormat:             "alloy",
+		clusterAdvInterfaces:     advertise.DefaultInterfaces,
+		clusterMaxJoinPeers:      5,
+		clusterRejoinInterval:    60 * time.Second,
+		clusterHashingAlgorithm:  cluster.HashingAlgorithmRing,
+		clusterRingTokens:        512,
+		clusterReplicationFactor: 1,
+		disableSupportBundle:     false,
+		// For backwards compatibility - use the LegacyValidation of Prometheus metrics name. This is a global variable
+		// setting that has changed upstream. See https://github.com/prometheus/common/pull/724.
+		prometheusMetricNameValidationScheme: prometheusLegacyMetricValidationScheme,
+		windowsPriority:                      windowspriority.PriorityNormal,
+		runtimeMemoryLimitRatio:              0.9,
+		runtimeAutoMaxProcs:                  true,
+		fipsEnforce:                          boringcrypto.Enabled,
+	}
+}
+
+// addFlags adds the flags which configure fr to cmd.
+func (fr *alloyRun) addFlags(cmd *cobra.Command) {
 	// Server flags
 	cmd.Flags().
-		StringVar(&r.httpListenAddr, "server.http.listen-addr", r.httpListenAddr, "Address to listen for HTTP traffic on")
-	cmd.Flags().StringVar(&r.inMemoryAddr, "server.http.memory-addr", r.inMemoryAddr, "Address to listen for in-memory HTTP traffic on. Change if it collides with a real address")
-	cmd.Flags().StringVar(&r.uiPrefix, "server.http.ui-path-prefix", r.uiPrefix, "Prefix to serve the HTTP UI at")
+		StringVar(&fr.httpListenAddr, "server.http.listen-addr", fr.httpListenAddr, "Address to listen for HTTP traffic on")
+	cmd.Flags().StringVar(&fr.inMemoryAddr, "server.http.memory-addr", fr.inMemoryAddr, "Address to listen for in-memory HTTP traffic on. Change if it collides with a real address")
+	cmd.Flags().StringVar(&fr.uiPrefix, "server.http.ui-path-prefix", fr.uiPrefix, "Prefix to serve the HTTP UI at")
 	cmd.Flags().
-		BoolVar(&r.enablePprof, "server.http.enable-pprof", r.enablePprof, "Enable /debug/pprof profiling endpoints.")
+		BoolVar(&fr.enablePprof, "server.http.enable-pprof", fr.enablePprof, "Enable /debug/pprof profiling endpoints.")
 	cmd.Flags().
-		BoolVar(&r.disableSupportBundle, "server.http.disable-support-bundle", r.disableSupportBundle, "Disable /-/support support bundle retrieval.")
+		BoolVar(&fr.disableSupportBundle, "server.http.disable-support-bundle", fr.disableSupportBundle, "Disable /-/support support bundle retrieval.")
 
 	// Cluster flags
 	cmd.Flags().
-		BoolVar(&r.clusterEnabled, "cluster.enabled", r.clusterEnabled, "Start in clustered mode")
+		BoolVar(&fr.clusterEnabled, "cluster.enabled", fr.clusterEnabled, "Start in clustered mode")
+	cmd.Flags().
+		StringVar(&fr.clusterNodeName, "cluster.node-name", fr.clusterNodeName, "The name to use for this node")
+	cmd.Flags().
+		StringVar(&fr.clusterAdvAddr, "cluster.advertise-address", fr.clusterAdvAddr, "Address to advertise to the cluster")
+	cmd.Flags().
+		StringVar(&fr.clusterJoinAddr, "cluster.join-addresses", fr.clusterJoinAddr, "Comma-separated list of addresses to join the cluster at")
+	cmd.Flags().
+		StringVar(&fr.clusterDiscoverPeers, "cluster.discover-peers", fr.clusterDiscoverPeers, "List of key-value tuples for discovering peers")
 	cmd.Flags().
-		StringVar(&r.clusterNodeName, "cluster.node-name", r.clusterNodeName, "The name to use for this node")
+		StringSliceVar(&fr.clusterAdvInterfaces, "cluster.advertise-interfaces", fr.clusterAdvInterfaces, "List of interfaces used to infer an address to advertise")
 	cmd.Flags().
-		StringVar(&r.clusterAdvAddr, "cluster.advertise-address", r.clusterAdvAddr, "Address to advertise to the cluster")
+		DurationVar(&fr.clusterRejoinInterval, "cluster.rejoin-interval", fr.clusterRejoinInterval, "How often to rejoin the list of peers")
 	cmd.Flags().
-		StringVar(&r.clusterJoinAddr, "cluster.join-addresses", r.clusterJoinAddr, "Comma-separated list of addresses to join the cluster at")
+		IntVar(&fr.clusterMaxJoinPeers, "cluster.max-join-peers", fr.clusterMaxJoinPeers, "Number of peers to join from the discovered set")
 	cmd.Flags().
-		StringVar(&r.clusterDiscoverPeers, "cluster.discover-peers", r.clusterDiscoverPeers, "List of key-value tuples for discovering peers")
+		StringVar(&fr.clusterName, "cluster.name", fr.clusterName, "The name of the cluster to join")
 	cmd.Flags().
-		StringSliceVar(&r.clusterAdvInterfaces, "cluster.advertise-interfaces", r.clusterAdvInterfaces, "List of interfaces used to infer an address to advertise")
+		BoolVar(&fr.clusterEnableTLS, "cluster.enable-tls", fr.clusterEnableTLS, "Specifies whether TLS should be used for communication between peers")
 	cmd.Flags().
-		DurationVar(&r.clusterRejoinInterval, "cluster.rejoin-interval", r.clusterRejoinInterval, "How often to rejoin the list of peers")
+		StringVar(&fr.clusterTLSCAPath, "cluster.tls-ca-path", fr.clusterTLSCAPath, "Path to the CA certificate file")
 	cmd.Flags().
-		IntVar(&r.clusterMaxJoinPeers, "cluster.max-join-peers", r.clusterMaxJoinPeers, "Number of peers to join from the discovered set")
+		StringVar(&fr.clusterTLSCertPath, "cluster.tls-cert-path", fr.clusterTLSCertPath, "Path to the certificate file")
 	cmd.Flags().
-		StringVar(&r.clusterName, "cluster.name", r.clusterName, "The name of the cluster to join")
+		StringVar(&fr.clusterTLSKeyPath, "cluster.tls-key-path", fr.clusterTLSKeyPath, "Path to the key file")
 	cmd.Flags().
-		BoolVar(&r.clusterEnableTLS, "cluster.enable-tls", r.clusterEnableTLS, "Specifies whether TLS should be used for communication between peers")
+		StringVar(&fr.clusterTLSServerName, "cluster.tls-server-name", fr.clusterTLSServerName, "Server name to use for TLS communication")
 	cmd.Flags().
-		StringVar(&r.clusterTLSCAPath, "cluster.tls-ca-path", r.clusterTLSCAPath, "Path to the CA certificate file")
+		IntVar(&fr.clusterWaitForSize, "cluster.wait-for-size", fr.clusterWaitForSize, "Wait for the cluster to reach the specified number of instances before allowing components that use clustering to begin processing. Zero means disabled")
 	cmd.Flags().
-		StringVar(&r.clusterTLSCertPath, "cluster.tls-cert-path", r.clusterTLSCertPath, "Path to the certificate file")
+		DurationVar(&fr.clusterWaitTimeout, "cluster.wait-timeout", 0, "Maximum duration to wait for minimum cluster size before proceeding with available nodes. Zero means wait forever, no timeout")
 	cmd.Flags().
-		StringVar(&r.clusterTLSKeyPath, "cluster.tls-key-path", r.clusterTLSKeyPath, "Path to the key file")
+		DurationVar(&fr.clusterDrainTimeout, "cluster.drain-timeout", 0, "Maximum duration to hand off work to other peers before leaving the cluster on shutdown. Zero means disabled")
 	cmd.Flags().
-		StringVar(&r.clusterTLSServerName, "cluster.tls-server-name", r.clusterTLSServerName, "Server name to use for TLS communication")
+		StringVar(&fr.clusterHashingAlgorithm, "cluster.hashing-algorithm", fr.clusterHashingAlgorithm, fmt.Sprintf("Algorithm used to distribute work across the cluster (%s, %s, %s)", cluster.HashingAlgorithmRing, cluster.HashingAlgorithmRendezvous, cluster.HashingAlgorithmMaglev))
 	cmd.Flags().
-		IntVar(&r.clusterWaitForSize, "cluster.wait-for-size", r.clusterWaitForSize, "Wait for the cluster to reach the specified number of instances before allowing components that use clustering to begin processing. Zero means disabled")
+		IntVar(&fr.clusterRingTokens, "cluster.ring-tokens", fr.clusterRingTokens, "Number of tokens per node when using the ring hashing algorithm")
 	cmd.Flags().
-		DurationVar(&r.clusterWaitTimeout, "cluster.wait-timeout", 0, "Maximum duration to wait for minimum cluster size before proceeding with available nodes. Zero means wait forever, no timeout")
+		IntVar(&fr.clusterReplicationFactor, "cluster.replication-factor", fr.clusterReplicationFactor, "Number of nodes which are assigned the same work by components that use clustering")
+	cmd.Flags().
+		BoolVar(&fr.clusterPartitionProtection, "cluster.partition-protection", fr.clusterPartitionProtection, "Stop components that use clustering from processing while the node is in a minority partition of the cluster")
+	cmd.Flags().
+		StringVar(&fr.clusterZone, "cluster.zone", fr.clusterZone, "Availability zone of the node; work in a zone is preferably assigned to the nodes in that zone")
 
 	// Config flags
-	cmd.Flags().StringVar(&r.configFormat, "config.format", r.configFormat, fmt.Sprintf("The format of the source file. Supported formats: %s.", supportedFormatsList()))
-	cmd.Flags().BoolVar(&r.configBypassConversionErrors, "config.bypass-conversion-errors", r.configBypassConversionErrors, "Enable bypassing errors when converting")
-	cmd.Flags().StringVar(&r.configExtraArgs, "config.extra-args", r.configExtraArgs, "Extra arguments from the original format used by the converter. Multiple arguments can be passed by separating them with a space.")
+	cmd.Flags().StringVar(&fr.configFormat, "config.format", fr.configFormat, fmt.Sprintf("The format of the source file. Supported formats: %s.", supportedFormatsList()))
+	cmd.Flags().BoolVar(&fr.configBypassConversionErrors, "config.bypass-conversion-errors", fr.configBypassConversionErrors, "Enable bypassing errors when converting")
+	cmd.Flags().StringVar(&fr.configExtraArgs, "config.extra-args", fr.configExtraArgs, "Extra arguments from the original format used by the converter. Multiple arguments can be passed by separating them with a space.")
+	cmd.Flags().BoolVar(&fr.configExpandEnv, "config.expand-env", fr.configExpandEnv, "Expand references to environment variables like ${VAR}, ${VAR:-default}, and ${VAR:?error} in config files before parsing them")
+	cmd.Flags().DurationVar(&fr.configEvaluationTimeout, "config.evaluation-timeout", fr.configEvaluationTimeout, "Maximum duration of the evaluation of a single component before it's reported as unhealthy. Zero means no timeout")
+
+	// Reload flags
+	cmd.Flags().DurationVar(&fr.reloadTimeout, "reload.timeout", fr.reloadTimeout, "Maximum duration of a config reload before it's reported as failed. Zero means no timeout")
+	cmd.Flags().BoolVar(&fr.reloadRollbackOnError, "reload.rollback-on-error", fr.reloadRollbackOnError, "Reapply the previous config when a config reload fails")
+
+	// Runtime flags
+	cmd.Flags().Float64Var(&fr.runtimeMemoryLimitRatio, "runtime.memory-limit-ratio", fr.runtimeMemoryLimitRatio, "Ratio of the cgroup memory limit to set as GOMEMLIMIT, between 0 and 1. Zero disables setting GOMEMLIMIT automatically. The GOMEMLIMIT and AUTOMEMLIMIT environment variables take precedence")
+	cmd.Flags().BoolVar(&fr.runtimeAutoMaxProcs, "runtime.auto-gomaxprocs", fr.runtimeAutoMaxProcs, "Set GOMAXPROCS to the cgroup CPU limit. The GOMAXPROCS environment variable takes precedence")
+
+	// FIPS flags
+	cmd.Flags().BoolVar(&fr.fipsEnforce, "fips.enforce", fr.fipsEnforce, "Refuse to start components configured with TLS or authentication settings which aren't FIPS approved. Enabled by default in FIPS builds")
 
 	// Misc flags
 	cmd.Flags().
-		BoolVar(&r.disableReporting, "disable-reporting", r.disableReporting, "Disable reporting of enabled components to Grafana.")
-	cmd.Flags().StringVar(&r.storagePath, "storage.path", r.storagePath, "Base directory where components can store data")
-	cmd.Flags().Var(&r.minStability, "stability.level", fmt.Sprintf("Minimum stability level of features to enable. Supported values: %s", strings.Join(featuregate.AllowedValues(), ", ")))
-	cmd.Flags().BoolVar(&r.enableCommunityComps, "feature.community-components.enabled", r.enableCommunityComps, "Enable community components.")
-	cmd.Flags().StringVar(&r.prometheusMetricNameValidationScheme, "feature.prometheus.metric-validation-scheme", prometheusLegacyMetricValidationScheme, fmt.Sprintf("Prometheus metric validation scheme to use. Supported values: %q, %q. NOTE: this is an experimental flag and may be removed in future releases.", prometheusLegacyMetricValidationScheme, prometheusUTF8MetricValidationScheme))
+		BoolVar(&fr.disableReporting, "disable-reporting", fr.disableReporting, "Disable reporting of enabled components to Grafana.")
+	cmd.Flags().StringVar(&fr.storagePath, "storage.path", fr.storagePath, "Base directory where components can store data")
+	cmd.Flags().Var(&fr.minStability, "stability.level", fmt.Sprintf("Minimum stability level of features to enable. Supported values: %s", strings.Join(featuregate.AllowedValues(), ", ")))
+	cmd.Flags().BoolVar(&fr.enableCommunityComps, "feature.community-components.enabled", fr.enableCommunityComps, "Enable community components.")
+	cmd.Flags().StringVar(&fr.prometheusMetricNameValidationScheme, "feature.prometheus.metric-validation-scheme", prometheusLegacyMetricValidationScheme, fmt.Sprintf("Prometheus metric validation scheme to use. Supported values: %q, %q. NOTE: this is an experimental flag and may be removed in future releases.", prometheusLegacyMetricValidationScheme, prometheusUTF8MetricValidationScheme))
 	if runtime.GOOS == "windows" {
-		cmd.Flags().StringVar(&r.windowsPriority, "windows.priority", r.windowsPriority, fmt.Sprintf("Process priority to use when running on windows. This flag is currently in public preview. Supported values: %s", strings.Join(slices.Collect(windowspriority.PriorityValues()), ", ")))
+		cmd.Flags().StringVar(&fr.windowsPriority, "windows.priority", fr.windowsPriority, fmt.Sprintf("Process priority to use when running on windows. This flag is currently in public preview. Supported values: %s", strings.Join(slices.Collect(windowspriority.PriorityValues()), ", ")))
+		registerFlagValues(cmd, "windows.priority", slices.Collect(windowspriority.PriorityValues())...)
 	}
 
-	addDeprecatedFlags(cmd)
-	return cmd
+	registerFlagValues(cmd, "cluster.hashing-algorithm", cluster.HashingAlgorithmRing, cluster.HashingAlgorithmRendezvous, cluster.HashingAlgorithmMaglev)
+	registerFlagValues(cmd, "config.format", configFormats()...)
+	registerFlagValues(cmd, "stability.level", stabilityLevels()...)
+	registerFlagValues(cmd, "feature.prometheus.metric-validation-scheme", prometheusLegacyMetricValidationScheme, prometheusUTF8MetricValidationScheme)
+	_ = cmd.MarkFlagDirname("storage.path")
 }
 
 type alloyRun struct {
@@ -205,25 +282,50 @@ type alloyRun struct {
 	clusterTLSServerName                 string
 	clusterWaitForSize                   int
 	clusterWaitTimeout                   time.Duration
+	clusterDrainTimeout                  time.Duration
+	clusterHashingAlgorithm              string
+	clusterRingTokens                    int
+	clusterReplicationFactor             int
+	clusterPartitionProtection           bool
+	clusterZone                          string
 	configFormat                         string
 	configBypassConversionErrors         bool
 	configExtraArgs                      string
+	configExpandEnv                      bool
+	configEvaluationTimeout              time.Duration
+	reloadTimeout                        time.Duration
+	reloadRollbackOnError                bool
 	enableCommunityComps                 bool
 	disableSupportBundle                 bool
 	prometheusMetricNameValidationScheme string
 	windowsPriority                      string
+	runtimeMemoryLimitRatio              float64
+	runtimeAutoMaxProcs                  bool
+	fipsEnforce                          bool
 }
 
-func (fr *alloyRun) Run(cmd *cobra.Command, configPath string) error {
+// Run runs Alloy with the configuration combined from configPaths, which can
+// point at files or directories.
+func (fr *alloyRun) Run(cmd *cobra.Command, configPaths []string) error {
 	var wg sync.WaitGroup
 	defer wg.Wait()
 
-	ctx, cancel := interruptContext()
+	// drain is set once the node participates in the cluster, so that its work
+	// is handed off to peers before the components are stopped.
+	var drain atomic.Pointer[func()]
+	ctx, cancel := interruptContext(func() {
+		if fn := drain.Load(); fn != nil {
+			(*fn)()
+		}
+	})
 	defer cancel()
 
-	if configPath == "" {
+	if len(configPaths) == 0 || slices.Contains(configPaths, "") {
 		return fmt.Errorf("path argument not provided")
 	}
+	// The first path is the root of the configuration, which relative paths,
+	// like the paths of imported modules, are resolved against.
+	configPath := configPaths[0]
 
 	// Buffer logs until log format has been determined
 	l, err := logging.NewDeferred(os.Stderr)
@@ -262,15 +364,26 @@ func (fr *alloyRun) Run(cmd *cobra.Command, configPath string) error {
 	// injected.
 	otel.SetTracerProvider(t)
 
-	level.Info(l).Log("boringcrypto enabled", boringcrypto.Enabled)
+	level.Info(l).Log("boringcrypto enabled", boringcrypto.Enabled, "fips enforced", fr.fipsEnforce)
 
 	// Set the memory limit, this will honor GOMEMLIMIT if set
 	// If there is a cgroup on linux it will use that
-	err = applyAutoMemLimit(l)
+	if fr.runtimeMemoryLimitRatio < 0 || fr.runtimeMemoryLimitRatio > 1 {
+		return fmt.Errorf("--runtime.memory-limit-ratio must be between 0 and 1")
+	}
+	memLimit, err := applyAutoMemLimit(l, fr.runtimeMemoryLimitRatio)
 	if err != nil {
 		level.Error(l).Log("msg", "failed to apply memory limit", "err", err)
 	}
 
+	// Set GOMAXPROCS, this will honor GOMAXPROCS if set.
+	maxProcsSource := limitSourceDefault
+	if fr.runtimeAutoMaxProcs {
+		maxProcsSource = applyAutoMaxProcs(l, "/")
+	} else if _, ok := os.LookupEnv("GOMAXPROCS"); ok {
+		maxProcsSource = limitSourceEnv
+	}
+
 	// Enable the profiling.
 	setMutexBlockProfiling(l)
 
@@ -291,6 +404,7 @@ func (fr *alloyRun) Run(cmd *cobra.Command, configPath string) error {
 	// metrics are still exposed.
 	reg := prometheus.DefaultRegisterer
 	reg.MustRegister(newResourcesCollector(l))
+	reg.MustRegister(newRuntimeLimitsCollectors(memLimitSource(memLimit), maxProcsSource)...)
 
 	// There's a cyclic dependency between the definition of the Alloy controller,
 	// the reload/ready functions, and the HTTP service.
@@ -302,29 +416,7 @@ func (fr *alloyRun) Run(cmd *cobra.Command, configPath string) error {
 		ready  func() bool
 	)
 
-	clusterService, err := buildClusterService(ClusterOptions{
-		Log:     log.With(l, "service", "cluster"),
-		Tracer:  t,
-		Metrics: reg,
-
-		EnableClustering:       fr.clusterEnabled,
-		NodeName:               fr.clusterNodeName,
-		AdvertiseAddress:       fr.clusterAdvAddr,
-		ListenAddress:          fr.httpListenAddr,
-		JoinPeers:              splitPeers(fr.clusterJoinAddr, ","),
-		DiscoverPeers:          fr.clusterDiscoverPeers,
-		RejoinInterval:         fr.clusterRejoinInterval,
-		AdvertiseInterfaces:    fr.clusterAdvInterfaces,
-		ClusterMaxJoinPeers:    fr.clusterMaxJoinPeers,
-		ClusterName:            fr.clusterName,
-		EnableTLS:              fr.clusterEnableTLS,
-		TLSCertPath:            fr.clusterTLSCertPath,
-		TLSCAPath:              fr.clusterTLSCAPath,
-		TLSKeyPath:             fr.clusterTLSKeyPath,
-		TLSServerName:          fr.clusterTLSServerName,
-		MinimumClusterSize:     fr.clusterWaitForSize,
-		MinimumSizeWaitTimeout: fr.clusterWaitTimeout,
-	})
+	clusterService, err := buildClusterService(fr.clusterOptions(log.With(l, "service", "cluster"), t, reg))
 	if err != nil {
 		return err
 	}
@@ -367,12 +459,23 @@ func (fr *alloyRun) Run(cmd *cobra.Command, configPath string) error {
 		return fmt.Errorf("failed to create the remotecfg service: %w", err)
 	}
 
+	opampService, err := opampservice.New(opampservice.Options{
+		Logger:      log.With(l, "service", "opamp"),
+		ConfigPath:  configPath,
+		StoragePath: fr.storagePath,
+		Metrics:     reg,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create the opamp service: %w", err)
+	}
+
 	liveDebuggingService := livedebugging.New()
 
 	uiService := uiservice.New(uiservice.Options{
 		UIPrefix:        fr.uiPrefix,
 		CallbackManager: liveDebuggingService.Data().(livedebugging.CallbackManager),
 		Logger:          log.With(l, "service", "ui"),
+		EnforceFIPS:     fr.fipsEnforce,
 	})
 
 	otelService := otel_service.New(l)
@@ -381,6 +484,11 @@ func (fr *alloyRun) Run(cmd *cobra.Command, configPath string) error {
 	}
 
 	labelService := labelstore.New(l, reg)
+
+	secretsService := secrets.New(secrets.Options{
+		Logger: log.With(l, "service", "secrets"),
+	})
+
 	alloyseed.Init(fr.storagePath, l)
 
 	f := alloy_runtime.New(alloy_runtime.Options{
@@ -390,33 +498,48 @@ func (fr *alloyRun) Run(cmd *cobra.Command, configPath string) error {
 		Reg:                  reg,
 		MinStability:         fr.minStability,
 		EnableCommunityComps: fr.enableCommunityComps,
+		EnforceFIPS:          fr.fipsEnforce,
+		EvaluationTimeout:    fr.configEvaluationTimeout,
 		Services: []service.Service{
 			clusterService,
 			httpService,
 			labelService,
 			liveDebuggingService,
+			opampService,
 			otelService,
 			remoteCfgService,
+			secretsService,
 			uiService,
 		},
 	})
 
+	reloader := &reloader{
+		log: l,
+		load: func(source *alloy_runtime.Source) error {
+			httpService.SetSources(source.SourceFiles())
+			return f.LoadSource(source, nil, configPath)
+		},
+		timeout:  fr.reloadTimeout,
+		rollback: fr.reloadRollbackOnError,
+	}
+
 	ready = f.Ready
 	reload = func() (map[string][]byte, error) {
-		sources, err := loadSourceFiles(configPath, fr.configFormat, fr.configBypassConversionErrors, fr.configExtraArgs)
+		sources, err := loadConfigSources(configPaths, fr.configFormat, fr.configBypassConversionErrors, fr.configExtraArgs, fr.configExpandEnv)
 		if err != nil {
 			instrumentation.InstrumentConfig(false, [32]byte{}, fr.clusterName)
-			return nil, fmt.Errorf("reading config path %q: %w", configPath, err)
+			// sources holds the files referenced by the diagnostics of
+			// env expansion errors, if any.
+			return sources, err
 		}
 
 		alloySource, err := alloy_runtime.ParseSources(sources)
 		defer instrumentation.InstrumentConfig(err == nil, hashSourceFiles(sources), fr.clusterName)
 		if err != nil {
-			return sources, fmt.Errorf("reading config path %q: %w", configPath, err)
+			return sources, fmt.Errorf("reading config path %q: %w", strings.Join(configPaths, ", "), err)
 		}
 
-		httpService.SetSources(alloySource.SourceFiles())
-		if err := f.LoadSource(alloySource, nil, configPath); err != nil {
+		if err := reloader.apply(alloySource); err != nil {
 			return sources, fmt.Errorf("error during the initial load: %w", err)
 		}
 
@@ -477,6 +600,12 @@ func (fr *alloyRun) Run(cmd *cobra.Command, configPath string) error {
 	if err != nil {
 		return fmt.Errorf("failed to set clusterer state to Participant after initial load")
 	}
+	drainFn := func() {
+		if err := clusterService.Drain(context.Background(), f); err != nil {
+			level.Error(l).Log("msg", "failed to drain cluster node", "err", err)
+		}
+	}
+	drain.Store(&drainFn)
 
 	reloadSignal := make(chan os.Signal, 1)
 	signal.Notify(reloadSignal, syscall.SIGHUP)
@@ -496,6 +625,109 @@ func (fr *alloyRun) Run(cmd *cobra.Command, configPath string) error {
 	}
 }
 
+// clusterOptions returns the options of the cluster service configured by the
+// flags of fr.
+func (fr *alloyRun) clusterOptions(l log.Logger, t trace.TracerProvider, reg prometheus.Registerer) ClusterOptions {
+	return ClusterOptions{
+		Log:     l,
+		Tracer:  t,
+		Metrics: reg,
+
+		EnableClustering:       fr.clusterEnabled,
+		NodeName:               fr.clusterNodeName,
+		AdvertiseAddress:       fr.clusterAdvAddr,
+		ListenAddress:          fr.httpListenAddr,
+		JoinPeers:              splitPeers(fr.clusterJoinAddr, ","),
+		DiscoverPeers:          fr.clusterDiscoverPeers,
+		RejoinInterval:         fr.clusterRejoinInterval,
+		AdvertiseInterfaces:    fr.clusterAdvInterfaces,
+		ClusterMaxJoinPeers:    fr.clusterMaxJoinPeers,
+		ClusterName:            fr.clusterName,
+		EnableTLS:              fr.clusterEnableTLS,
+		TLSCertPath:            fr.clusterTLSCertPath,
+		TLSCAPath:              fr.clusterTLSCAPath,
+		TLSKeyPath:             fr.clusterTLSKeyPath,
+		TLSServerName:          fr.clusterTLSServerName,
+		MinimumClusterSize:     fr.clusterWaitForSize,
+		MinimumSizeWaitTimeout: fr.clusterWaitTimeout,
+		DrainTimeout:           fr.clusterDrainTimeout,
+		HashingAlgorithm:       fr.clusterHashingAlgorithm,
+		RingTokens:             fr.clusterRingTokens,
+		ReplicationFactor:      fr.clusterReplicationFactor,
+		PartitionProtection:    fr.clusterPartitionProtection,
+		Zone:                   fr.clusterZone,
+	}
+}
+
+// standaloneServices returns the services of the run command for subcommands
+// which load configuration files outside of a running Alloy instance, like
+// validate and test.
+func (fr *alloyRun) standaloneServices(l *logging.Logger, t *tracing.Tracer, reg prometheus.Registerer, configPath, storagePath string) ([]service.Service, error) {
+	// Clustering is disabled, as the network interfaces and peers of the
+	// host are irrelevant.
+	clusterOpts := fr.clusterOptions(log.With(l, "service", "cluster"), t, reg)
+	clusterOpts.EnableClustering = false
+	if findPort(clusterOpts.ListenAddress, 0) == 0 {
+		// The node address is never used, but it must have a port, which isn't
+		// known yet when the HTTP server listens on a random one.
+		clusterOpts.ListenAddress = "127.0.0.1:80"
+	}
+	clusterService, err := buildClusterService(clusterOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	remoteCfgService, err := remotecfgservice.New(remotecfgservice.Options{
+		Logger:      log.With(l, "service", "remotecfg"),
+		ConfigPath:  configPath,
+		StoragePath: storagePath,
+		Metrics:     reg,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the remotecfg service: %w", err)
+	}
+
+	opampService, err := opampservice.New(opampservice.Options{
+		Logger:      log.With(l, "service", "opamp"),
+		ConfigPath:  configPath,
+		StoragePath: storagePath,
+		Metrics:     reg,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create the opamp service: %w", err)
+	}
+
+	liveDebuggingService := livedebugging.New()
+
+	otelService := otel_service.New(l)
+	if otelService == nil {
+		return nil, fmt.Errorf("failed to create otel service")
+	}
+
+	return []service.Service{
+		clusterService,
+		httpservice.New(httpservice.Options{
+			Logger:           l,
+			Tracer:           t,
+			HTTPListenAddr:   fr.httpListenAddr,
+			MemoryListenAddr: fr.inMemoryAddr,
+			MinStability:     fr.minStability,
+		}),
+		labelstore.New(l, reg),
+		liveDebuggingService,
+		opampService,
+		otelService,
+		remoteCfgService,
+		secrets.New(secrets.Options{Logger: log.With(l, "service", "secrets")}),
+		uiservice.New(uiservice.Options{
+			UIPrefix:        fr.uiPrefix,
+			CallbackManager: liveDebuggingService.Data().(livedebugging.CallbackManager),
+			Logger:          log.With(l, "service", "ui"),
+			EnforceFIPS:     fr.fipsEnforce,
+		}),
+	}, nil
+}
+
 func (fr *alloyRun) configurePrometheusMetricNameValidationScheme(l log.Logger) error {
 	switch fr.prometheusMetricNameValidationScheme {
 	case prometheusLegacyMetricValidationScheme:
@@ -531,6 +763,83 @@ func getEnabledComponentsFunc(f *alloy_runtime.Runtime) func() map[string]interf
 	}
 }
 
+// loadConfigSources loads the sources found at each of paths, which can point
+// at files or directories, into a single map. Only a single path can be
+// converted from another format.
+//
+// If expandEnv is true, references to environment variables are expanded. If
+// required variables aren't set, loadConfigSources returns the unexpanded
+// sources along with the diagnostics pointing at them.
+func loadConfigSources(paths []string, converterSourceFormat string, converterBypassErrors bool, configExtraArgs string, expandEnv bool) (map[string][]byte, error) {
+	if len(paths) > 1 && converterSourceFormat != "alloy" {
+		return nil, fmt.Errorf("only one config path can be provided with the %q config format", converterSourceFormat)
+	}
+	if err := checkExpandEnv(converterSourceFormat, expandEnv); err != nil {
+		return nil, err
+	}
+
+	var (
+		merged = map[string][]byte{}
+		// seen holds the absolute names of the loaded files, which may be
+		// found through different paths.
+		seen = map[string]string{}
+	)
+	for _, path := range paths {
+		sources, err := loadSourceFiles(path, converterSourceFormat, converterBypassErrors, configExtraArgs)
+		if err != nil {
+			return nil, fmt.Errorf("reading config path %q: %w", path, err)
+		}
+
+		for name, bb := range sources {
+			abs, err := filepath.Abs(name)
+			if err != nil {
+				return nil, fmt.Errorf("reading config path %q: %w", path, err)
+			}
+			if prev, ok := seen[abs]; ok {
+				return nil, fmt.Errorf("config file %q is provided more than once, also as %q", name, prev)
+			}
+			seen[abs] = name
+			merged[name] = bb
+		}
+	}
+
+	if expandEnv {
+		return expandSourcesEnv(merged)
+	}
+	return merged, nil
+}
+
+// checkExpandEnv returns an error if env expansion is enabled for a config
+// format which doesn't support it.
+func checkExpandEnv(converterSourceFormat string, expandEnv bool) error {
+	if expandEnv && converterSourceFormat != "alloy" {
+		return fmt.Errorf("--config.expand-env only applies to the alloy config format, use the env expansion of the %q format instead, if any", converterSourceFormat)
+	}
+	return nil
+}
+
+// expandSourcesEnv expands the references to environment variables in
+// sources. If required variables aren't set, expandSourcesEnv returns sources
+// unchanged along with the diagnostics pointing at them.
+func expandSourcesEnv(sources map[string][]byte) (map[string][]byte, error) {
+	var (
+		expanded = make(map[string][]byte, len(sources))
+		diags    diag.Diagnostics
+	)
+	for name, bb := range sources {
+		out, fileDiags := envsubst.Expand(name, bb, os.LookupEnv)
+		diags = append(diags, fileDiags...)
+		expanded[name] = out
+	}
+	if diags.HasErrors() {
+		slices.SortFunc(diags, func(a, b diag.Diagnostic) int {
+			return cmp.Or(cmp.Compare(a.StartPos.Filename, b.StartPos.Filename), cmp.Compare(a.StartPos.Offset, b.StartPos.Offset))
+		})
+		return sources, diags
+	}
+	return expanded, nil
+}
+
 func loadSourceFiles(path string, converterSourceFormat string, converterBypassErrors bool, configExtraArgs string) (map[string][]byte, error) {
 	fi, err := os.Stat(path)
 	if err != nil {
@@ -612,20 +921,27 @@ func addDeprecatedFlags(cmd *cobra.Command) {
 	}
 }
 
-func interruptContext() (context.Context, context.CancelFunc) {
+// interruptContext returns a context which is canceled when an interrupt
+// signal is received. onInterrupt is called before the context is canceled.
+func interruptContext(onInterrupt func()) (context.Context, context.CancelFunc) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	go func() {
 		defer cancel()
 		sig := make(chan os.Signal, 1)
 		signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+		interrupted := false
 		select {
 		case <-sig:
+			interrupted = true
 		case <-ctx.Done():
 		}
 		signal.Stop(sig)
 
 		fmt.Fprintln(os.Stderr, "interrupt received")
+		if interrupted {
+			onInterrupt()
+		}
 	}()
 
 	return ctx, cancel
@@ -0,0 +1,151 @@
+package alloycli
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+
+	"github.com/fatih/color"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/alloy/internal/component"
+	alloy_runtime "github.com/grafana/alloy/internal/runtime"
+	"github.com/grafana/alloy/internal/runtime/logging"
+	"github.com/grafana/alloy/internal/runtime/tracing"
+	"github.com/grafana/alloy/syntax/diag"
+)
+
+// DryRun loads the configuration at configPaths like Run, without starting
+// services or components, and writes the components and their dependencies
+// to stdout.
+func (fr *alloyRun) DryRun(configPaths []string, stdout, stderr io.Writer) error {
+	if len(configPaths) == 0 || slices.Contains(configPaths, "") {
+		return fmt.Errorf("path argument not provided")
+	}
+
+	// Only warnings and errors which aren't reported as diagnostics are logged.
+	l, err := logging.New(stderr, logging.Options{Level: logging.LevelWarn, Format: logging.FormatLogfmt})
+	if err != nil {
+		return fmt.Errorf("building logger: %w", err)
+	}
+	if err := fr.configurePrometheusMetricNameValidationScheme(l); err != nil {
+		return err
+	}
+
+	sources, err := loadConfigSources(configPaths, fr.configFormat, fr.configBypassConversionErrors, fr.configExtraArgs, fr.configExpandEnv)
+	var f *alloy_runtime.Runtime
+	if err == nil {
+		f, err = fr.dryRunLoad(l, configPaths[0], sources)
+	}
+	if err != nil {
+		var diags diag.Diagnostics
+		if errors.As(err, &diags) {
+			p := diag.NewPrinter(diag.PrinterConfig{
+				Color:              !color.NoColor,
+				ContextLinesBefore: 1,
+				ContextLinesAfter:  1,
+			})
+			_ = p.Fprint(stderr, sources, diags)
+			if !diags.HasErrors() {
+				err = nil
+			}
+		}
+		if err != nil {
+			return fmt.Errorf("invalid configuration: %w", err)
+		}
+	}
+
+	components, err := f.ListComponents("", component.InfoOptions{})
+	if err != nil {
+		return err
+	}
+	writeComponentGraph(stdout, components)
+	return nil
+}
+
+// dryRunLoad loads sources, read from path, in a runtime which decodes the
+// arguments of components without building them. The returned runtime must
+// not be run.
+func (fr *alloyRun) dryRunLoad(l *logging.Logger, path string, sources map[string][]byte) (*alloy_runtime.Runtime, error) {
+	alloySource, err := alloy_runtime.ParseSources(sources)
+	if err != nil {
+		return nil, err
+	}
+
+	// Services may write to their storage directory when they're created,
+	// which a dry run must not do.
+	storagePath, err := os.MkdirTemp("", "alloy-dry-run")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(storagePath)
+
+	t, err := tracing.New(tracing.DefaultOptions)
+	if err != nil {
+		return nil, fmt.Errorf("building tracer: %w", err)
+	}
+	reg := prometheus.NewRegistry()
+
+	services, err := fr.standaloneServices(l, t, reg, path, storagePath)
+	if err != nil {
+		return nil, err
+	}
+
+	f := alloy_runtime.New(alloy_runtime.Options{
+		Logger:               l,
+		Tracer:               t,
+		DataPath:             storagePath,
+		Reg:                  reg,
+		MinStability:         fr.minStability,
+		EnableCommunityComps: fr.enableCommunityComps,
+		Services:             services,
+		DryRun:               true,
+		EnforceFIPS:          fr.fipsEnforce,
+	})
+	return f, f.LoadSource(alloySource, nil, path)
+}
+
+// writeComponentGraph writes components to w in the order they're evaluated,
+// where components are evaluated after the components they reference. Each
+// component is followed by the components it references.
+func writeComponentGraph(w io.Writer, components []*component.Info) {
+	byID := make(map[string]*component.Info, len(components))
+	for _, c := range components {
+		byID[c.ID.LocalID] = c
+	}
+
+	// Components whose references are all written are written next, in
+	// alphabetical order.
+	var (
+		written = make(map[string]bool, len(components))
+		order   = make([]*component.Info, 0, len(components))
+	)
+	remaining := slices.Clone(components)
+	slices.SortFunc(remaining, func(a, b *component.Info) int { return cmp.Compare(a.ID.LocalID, b.ID.LocalID) })
+	for len(remaining) > 0 {
+		i := slices.IndexFunc(remaining, func(c *component.Info) bool {
+			return !slices.ContainsFunc(c.References, func(ref string) bool {
+				return byID[ref] != nil && !written[ref]
+			})
+		})
+		if i < 0 {
+			// The graph has a cycle, which is reported when loading it.
+			i = 0
+		}
+		written[remaining[i].ID.LocalID] = true
+		order = append(order, remaining[i])
+		remaining = slices.Delete(remaining, i, i+1)
+	}
+
+	fmt.Fprintf(w, "%d components:\n", len(order))
+	for _, c := range order {
+		fmt.Fprintf(w, "  %s\n", c.ID.LocalID)
+		refs := slices.Sorted(slices.Values(c.References))
+		for _, ref := range refs {
+			fmt.Fprintf(w, "    <- %s\n", ref)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+package alloycli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun(t *testing.T) {
+	dir := t.TempDir()
+	storagePath := filepath.Join(dir, "data")
+
+	validPath := filepath.Join(dir, "valid.alloy")
+	require.NoError(t, os.WriteFile(validPath, []byte(`
+		prometheus.remote_write "default" {
+			endpoint {
+				url = "http://localhost:9090/api/v1/write"
+			}
+		}
+
+		prometheus.scrape "default" {
+			targets    = prometheus.exporter.self.default.targets
+			forward_to = [prometheus.remote_write.default.receiver]
+		}
+
+		prometheus.exporter.self "default" {}
+	`), 0644))
+
+	invalidPath := filepath.Join(dir, "invalid.alloy")
+	require.NoError(t, os.WriteFile(invalidPath, []byte(`
+		prometheus.scrape "default" {
+			targets    = []
+			forward_to = [prometheus.remote_write.missing.receiver]
+		}
+	`), 0644))
+
+	t.Run("valid", func(t *testing.T) {
+		r := newAlloyRun()
+		r.storagePath = storagePath
+		r.httpListenAddr = "127.0.0.1:0"
+
+		var stdout, stderr bytes.Buffer
+		require.NoError(t, r.DryRun([]string{validPath}, &stdout, &stderr))
+		require.Equal(t, `3 components:
+  prometheus.exporter.self.default
+  prometheus.remote_write.default
+  prometheus.scrape.default
+    <- prometheus.exporter.self.default
+    <- prometheus.remote_write.default
+`, stdout.String())
+		require.NoDirExists(t, storagePath, "a dry run doesn't write to the storage path")
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		err := newAlloyRun().DryRun([]string{invalidPath}, &stdout, &stderr)
+		require.ErrorContains(t, err, "invalid configuration")
+		require.Contains(t, stderr.String(), `component "prometheus.remote_write.missing.receiver" does not exist`)
+		require.Empty(t, stdout.String())
+	})
+	t.Run("builtin modules", func(t *testing.T) {
+		builtinPath := filepath.Join(dir, "builtin.alloy")
+		require.NoError(t, os.WriteFile(builtinPath, []byte(`
+			import.builtin "k8s" {
+				module = "kubernetes/logs"
+			}
+
+			import.builtin "node" {
+				module = "node/metrics"
+			}
+
+			import.builtin "otlp" {
+				module = "otlp/gateway"
+			}
+
+			k8s.pods "default" {
+				forward_to = [loki.write.default.receiver]
+			}
+
+			node.exporter "default" {
+				forward_to = [prometheus.remote_write.default.receiver]
+			}
+
+			otlp.receiver "default" {
+				traces_output = [otelcol.exporter.otlp.default.input]
+			}
+
+			loki.write "default" {
+				endpoint {
+					url = "http://localhost:3100/loki/api/v1/push"
+				}
+			}
+
+			prometheus.remote_write "default" {
+				endpoint {
+					url = "http://localhost:9090/api/v1/write"
+				}
+			}
+
+			otelcol.exporter.otlp "default" {
+				client {
+					endpoint = "localhost:4317"
+				}
+			}
+		`), 0644))
+
+		var stdout, stderr bytes.Buffer
+		require.NoError(t, newAlloyRun().DryRun([]string{builtinPath}, &stdout, &stderr), stderr.String())
+		require.Contains(t, stdout.String(), "k8s.pods.default")
+		require.Contains(t, stdout.String(), "node.exporter.default")
+		require.Contains(t, stdout.String(), "otlp.receiver.default")
+	})
+}
@@ -1,13 +1,24 @@
 package alloycli
 
 import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/alloy/internal/featuregate"
+	alloy_runtime "github.com/grafana/alloy/internal/runtime"
+	"github.com/grafana/alloy/internal/runtime/logging"
+	"github.com/grafana/alloy/internal/runtime/tracing"
+	opampservice "github.com/grafana/alloy/internal/service/opamp"
+	"github.com/grafana/alloy/syntax/diag"
 )
 
 func TestConfigurePrometheusMetricNameValidationScheme(t *testing.T) {
@@ -70,3 +81,132 @@ func TestConfigurePrometheusMetricNameValidationScheme(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadConfigSources(t *testing.T) {
+	var (
+		dir      = t.TempDir()
+		teamA    = filepath.Join(dir, "team-a")
+		teamB    = filepath.Join(dir, "team-b.alloy")
+		teamAOne = filepath.Join(teamA, "one.alloy")
+		teamATwo = filepath.Join(teamA, "two.alloy")
+	)
+	require.NoError(t, os.Mkdir(teamA, 0o755))
+	require.NoError(t, os.WriteFile(teamAOne, []byte(`logging {}`), 0o644))
+	require.NoError(t, os.WriteFile(teamATwo, []byte(`tracing {}`), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(teamA, "notes.txt"), []byte(`ignored`), 0o644))
+	require.NoError(t, os.WriteFile(teamB, []byte(`http {}`), 0o644))
+
+	t.Run("merges files and directories", func(t *testing.T) {
+		sources, err := loadConfigSources([]string{teamA, teamB}, "alloy", false, "", false)
+		require.NoError(t, err)
+		require.Equal(t, map[string][]byte{
+			teamAOne: []byte(`logging {}`),
+			teamATwo: []byte(`tracing {}`),
+			teamB:    []byte(`http {}`),
+		}, sources)
+	})
+
+	t.Run("rejects files provided more than once", func(t *testing.T) {
+		_, err := loadConfigSources([]string{teamA, teamAOne}, "alloy", false, "", false)
+		require.EqualError(t, err, `config file "`+teamAOne+`" is provided more than once, also as "`+teamAOne+`"`)
+	})
+
+	t.Run("rejects missing paths", func(t *testing.T) {
+		_, err := loadConfigSources([]string{teamB, filepath.Join(dir, "missing.alloy")}, "alloy", false, "", false)
+		require.ErrorContains(t, err, `reading config path "`+filepath.Join(dir, "missing.alloy")+`"`)
+	})
+
+	t.Run("converts a single path only", func(t *testing.T) {
+		_, err := loadConfigSources([]string{teamB, teamAOne}, "prometheus", false, "", false)
+		require.EqualError(t, err, `only one config path can be provided with the "prometheus" config format`)
+	})
+}
+
+func TestLoadConfigSources_ExpandEnv(t *testing.T) {
+	var (
+		dir  = t.TempDir()
+		path = filepath.Join(dir, "config.alloy")
+		src  = []byte(`logging {
+	level = "${ALLOY_TEST_LEVEL:-info}"
+}
+
+prometheus.remote_write "default" {
+	endpoint {
+		url = "${ALLOY_TEST_URL:?must be set}"
+	}
+}
+`)
+	)
+	require.NoError(t, os.WriteFile(path, src, 0o644))
+
+	sources, err := loadConfigSources([]string{path}, "alloy", false, "", true)
+	var diags diag.Diagnostics
+	require.ErrorAs(t, err, &diags)
+	require.Equal(t, path+":7:10: required environment variable ALLOY_TEST_URL isn't set or is empty: must be set", diags.Error())
+	require.Equal(t, map[string][]byte{path: src}, sources, "the unexpanded sources are returned to print the diagnostics")
+
+	t.Setenv("ALLOY_TEST_URL", "http://localhost:9009/api/v1/push")
+	sources, err = loadConfigSources([]string{path}, "alloy", false, "", true)
+	require.NoError(t, err)
+	require.Contains(t, string(sources[path]), `level = "info"`)
+	require.Contains(t, string(sources[path]), `url = "http://localhost:9009/api/v1/push"`)
+
+	_, err = loadConfigSources([]string{path}, "prometheus", false, "", true)
+	require.EqualError(t, err, `--config.expand-env only applies to the alloy config format, use the env expansion of the "prometheus" format instead, if any`)
+}
+
+func TestStandaloneServices_Run(t *testing.T) {
+	fr := newAlloyRun()
+	fr.httpListenAddr = "127.0.0.1:0"
+	storagePath := t.TempDir()
+
+	l, err := logging.New(os.Stderr, logging.Options{Level: logging.LevelWarn, Format: logging.FormatLogfmt})
+	require.NoError(t, err)
+	tr, err := tracing.New(tracing.DefaultOptions)
+	require.NoError(t, err)
+	reg := prometheus.NewRegistry()
+
+	services, err := fr.standaloneServices(l, tr, reg, "config.alloy", storagePath)
+	require.NoError(t, err)
+
+	f := alloy_runtime.New(alloy_runtime.Options{
+		Logger:       l,
+		Tracer:       tr,
+		DataPath:     storagePath,
+		Reg:          reg,
+		MinStability: featuregate.StabilityExperimental,
+		Services:     services,
+	})
+
+	ctx, cancel := context.WithCancel(t.Context())
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		f.Run(ctx)
+	}()
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	source, err := alloy_runtime.ParseSource("config.alloy", []byte(`
+		logging {
+			level = "warn"
+		}
+	`))
+	require.NoError(t, err)
+	require.NoError(t, f.LoadSource(source, nil, "config.alloy"))
+	require.True(t, f.Ready())
+
+	// The services which create their own controller are running.
+	for _, svc := range services {
+		opamp, ok := svc.(*opampservice.Service)
+		if !ok {
+			continue
+		}
+		require.Eventually(t, func() bool {
+			return opamp.Data().(opampservice.Data).Host != nil
+		}, 10*time.Second, 10*time.Millisecond)
+	}
+}
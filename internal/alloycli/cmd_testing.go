@@ -0,0 +1,260 @@
+package alloycli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/spf13/cobra"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/pipelinetest"
+	alloy_runtime "github.com/grafana/alloy/internal/runtime"
+	"github.com/grafana/alloy/internal/runtime/logging"
+	"github.com/grafana/alloy/internal/runtime/tracing"
+	"github.com/grafana/alloy/syntax/diag"
+)
+
+// testFileSuffix is the suffix of the test files found in directories.
+const testFileSuffix = "_test.alloy"
+
+func testCommand() *cobra.Command {
+	ft := &alloyTest{
+		run:     newAlloyRun(),
+		timeout: 10 * time.Second,
+	}
+	// Tests must not conflict with an Alloy instance running on the same host.
+	ft.run.httpListenAddr = "127.0.0.1:0"
+
+	cmd := &cobra.Command{
+		Use:   "test [flags] path...",
+		Short: "Run pipeline tests",
+		Long: `The test subcommand runs test files, which feed synthetic telemetry into
+pipelines and assert on what the pipelines output.
+
+A test file is a configuration file which imports the pipelines under test and
+connects them to testing components: testing.logs.input, testing.metrics.input
+and testing.traces.input send data to the pipelines, while
+testing.logs.output, testing.metrics.output and testing.traces.output record
+what the pipelines output and declare the expected data in expect blocks. The
+testing components are only available to the test subcommand.
+
+Each path can point at a test file or a directory. Directories are searched
+recursively for files ending in _test.alloy.
+
+Each test file runs in its own isolated instance until the expectations of all
+its output components are met, or until --timeout elapses. test exits with a
+non-zero status code if any test file fails.
+`,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+
+		RunE: func(_ *cobra.Command, args []string) error {
+			return ft.Run(args, os.Stdout, os.Stderr)
+		},
+	}
+
+	ft.run.addFlags(cmd)
+	addDeprecatedFlags(cmd)
+	cmd.Flags().DurationVar(&ft.timeout, "timeout", ft.timeout, "Maximum time to wait for the expectations of a test file to be met")
+	return cmd
+}
+
+type alloyTest struct {
+	run     *alloyRun
+	timeout time.Duration
+}
+
+func (ft *alloyTest) Run(paths []string, stdout, stderr io.Writer) error {
+	if ft.timeout <= 0 {
+		return fmt.Errorf("timeout must be positive")
+	}
+
+	l, err := logging.New(stderr, logging.Options{Level: logging.LevelWarn, Format: logging.FormatLogfmt})
+	if err != nil {
+		return fmt.Errorf("building logger: %w", err)
+	}
+	if err := ft.run.configurePrometheusMetricNameValidationScheme(l); err != nil {
+		return err
+	}
+
+	files, err := findTestFiles(paths)
+	if err != nil {
+		return err
+	}
+	if len(files) == 0 {
+		return fmt.Errorf("no test files found")
+	}
+
+	var failed int
+	for _, file := range files {
+		start := time.Now()
+		err := ft.runFile(l, file, stderr)
+		elapsed := time.Since(start).Seconds()
+
+		if err == nil {
+			fmt.Fprintf(stdout, "PASS: %s (%.2fs)\n", file, elapsed)
+			continue
+		}
+		failed++
+		fmt.Fprintf(stdout, "FAIL: %s (%.2fs)\n", file, elapsed)
+		for _, line := range strings.Split(err.Error(), "\n") {
+			fmt.Fprintf(stdout, "    %s\n", line)
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d test files failed", failed, len(files))
+	}
+	return nil
+}
+
+// findTestFiles returns the files at paths, replacing directories with the
+// test files they contain.
+func findTestFiles(paths []string) ([]string, error) {
+	var files []string
+	for _, path := range paths {
+		fi, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !fi.IsDir() {
+			files = append(files, path)
+			continue
+		}
+
+		err = filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && strings.HasSuffix(d.Name(), testFileSuffix) {
+				files = append(files, p)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// runFile runs the test file at path until the expectations of its output
+// components are met or the timeout elapses. Load errors are printed to
+// stderr.
+func (ft *alloyTest) runFile(l *logging.Logger, path string, stderr io.Writer) error {
+	bb, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	sources := map[string][]byte{path: bb}
+	if ft.run.configExpandEnv {
+		// On errors, sources holds the files referenced by the diagnostics,
+		// which are printed like load errors below.
+		sources, err = expandSourcesEnv(sources)
+	}
+	loadErr := err
+
+	storagePath, err := os.MkdirTemp("", "alloy-test")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(storagePath)
+
+	t, err := tracing.New(tracing.DefaultOptions)
+	if err != nil {
+		return fmt.Errorf("building tracer: %w", err)
+	}
+	reg := prometheus.NewRegistry()
+
+	services, err := ft.run.standaloneServices(l, t, reg, path, storagePath)
+	if err != nil {
+		return err
+	}
+
+	registry := pipelinetest.NewRegistry(component.NewDefaultRegistry(ft.run.minStability, ft.run.enableCommunityComps))
+	f := alloy_runtime.New(alloy_runtime.Options{
+		Logger:               l,
+		Tracer:               t,
+		DataPath:             storagePath,
+		Reg:                  reg,
+		MinStability:         ft.run.minStability,
+		EnableCommunityComps: ft.run.enableCommunityComps,
+		Services:             services,
+		ComponentRegistry:    registry,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), ft.timeout)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		f.Run(ctx)
+	}()
+
+	if loadErr == nil {
+		loadErr = ft.load(f, path, sources)
+	}
+	if err := loadErr; err != nil {
+		var diags diag.Diagnostics
+		if errors.As(err, &diags) {
+			p := diag.NewPrinter(diag.PrinterConfig{
+				Color:              !color.NoColor,
+				ContextLinesBefore: 1,
+				ContextLinesAfter:  1,
+			})
+			_ = p.Fprint(stderr, sources, diags)
+			fmt.Fprintln(stderr)
+			return fmt.Errorf("could not load the test file")
+		}
+		return err
+	}
+
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		results := registry.Results()
+		if len(results) == 0 {
+			return fmt.Errorf("the test file doesn't declare any testing output component")
+		}
+
+		var failures []string
+		for _, res := range results {
+			if res.Err == nil {
+				continue
+			}
+			for _, line := range strings.Split(res.Err.Error(), "\n") {
+				failures = append(failures, res.ID+": "+line)
+			}
+		}
+		if len(failures) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("expectations not met after %s:\n%s", ft.timeout, strings.Join(failures, "\n"))
+		case <-ticker.C:
+		}
+	}
+}
+
+func (ft *alloyTest) load(f *alloy_runtime.Runtime, path string, sources map[string][]byte) error {
+	alloySource, err := alloy_runtime.ParseSources(sources)
+	if err != nil {
+		return err
+	}
+	return f.LoadSource(alloySource, nil, path)
+}
@@ -0,0 +1,192 @@
+package alloycli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/featuregate"
+)
+
+func TestPipelineTest(t *testing.T) {
+	dir := t.TempDir()
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pipeline.alloy"), []byte(`
+		declare "logs" {
+			argument "forward_to" {}
+
+			loki.relabel "env" {
+				forward_to = argument.forward_to.value
+
+				rule {
+					target_label = "env"
+					replacement  = "prod"
+				}
+			}
+
+			export "receiver" {
+				value = loki.relabel.env.receiver
+			}
+		}
+
+		declare "metrics" {
+			argument "forward_to" {}
+
+			prometheus.relabel "drop_debug" {
+				forward_to = argument.forward_to.value
+
+				rule {
+					source_labels = ["level"]
+					regex         = "debug"
+					action        = "drop"
+				}
+			}
+
+			export "receiver" {
+				value = prometheus.relabel.drop_debug.receiver
+			}
+		}
+	`), 0644))
+
+	passPath := filepath.Join(dir, "pass_test.alloy")
+	require.NoError(t, os.WriteFile(passPath, []byte(`
+		import.file "pipeline" {
+			filename = file.path_join(module_path, "pipeline.alloy")
+		}
+
+		pipeline.logs "default" {
+			forward_to = [testing.logs.output.default.receiver]
+		}
+
+		testing.logs.input "default" {
+			forward_to = [pipeline.logs.default.receiver]
+
+			entry {
+				line   = "hello"
+				labels = {"app" = "api"}
+			}
+		}
+
+		testing.logs.output "default" {
+			expect {
+				line   = "hello"
+				labels = {"app" = "api", "env" = "prod"}
+				count  = 1
+			}
+		}
+
+		pipeline.metrics "default" {
+			forward_to = [testing.metrics.output.default.receiver]
+		}
+
+		testing.metrics.input "default" {
+			forward_to = [pipeline.metrics.default.receiver]
+
+			sample {
+				name   = "requests_total"
+				labels = {"level" = "info"}
+				value  = 3
+			}
+
+			sample {
+				name   = "requests_total"
+				labels = {"level" = "debug"}
+				value  = 5
+			}
+		}
+
+		testing.metrics.output "default" {
+			expect {
+				name  = "requests_total"
+				value = 3
+				count = 1
+			}
+		}
+
+		testing.traces.input "default" {
+			span {
+				name       = "GET /"
+				attributes = {"http.method" = "GET"}
+			}
+
+			output {
+				traces = [testing.traces.output.default.input]
+			}
+		}
+
+		testing.traces.output "default" {
+			expect {
+				name       = "GET /"
+				attributes = {"http.method" = "GET"}
+			}
+		}
+	`), 0644))
+
+	failPath := filepath.Join(dir, "fail_test.alloy")
+	require.NoError(t, os.WriteFile(failPath, []byte(`
+		import.file "pipeline" {
+			filename = file.path_join(module_path, "pipeline.alloy")
+		}
+
+		pipeline.metrics "default" {
+			forward_to = [testing.metrics.output.default.receiver]
+		}
+
+		testing.metrics.input "default" {
+			forward_to = [pipeline.metrics.default.receiver]
+
+			sample {
+				name   = "requests_total"
+				labels = {"level" = "debug"}
+				value  = 5
+			}
+		}
+
+		testing.metrics.output "default" {
+			expect {
+				name   = "requests_total"
+				labels = {"level" = "debug"}
+			}
+		}
+	`), 0644))
+
+	newTest := func() *alloyTest {
+		run := newAlloyRun()
+		run.minStability = featuregate.StabilityGenerallyAvailable
+		run.httpListenAddr = "127.0.0.1:0"
+		return &alloyTest{run: run, timeout: 2 * time.Second}
+	}
+
+	t.Run("pass", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		require.NoError(t, newTest().Run([]string{passPath}, &stdout, &stderr), stdout.String()+stderr.String())
+		require.Contains(t, stdout.String(), "PASS: "+passPath)
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		err := newTest().Run([]string{dir}, &stdout, &stderr)
+		require.EqualError(t, err, "1 of 2 test files failed")
+		require.Contains(t, stdout.String(), "PASS: "+passPath)
+		require.Contains(t, stdout.String(), "FAIL: "+failPath)
+		require.Contains(t, stdout.String(), `testing.metrics.output.default: samples of "requests_total" with labels {level="debug"}: expected at least one match, got none`)
+	})
+
+	t.Run("load error", func(t *testing.T) {
+		path := filepath.Join(dir, "invalid.alloy")
+		require.NoError(t, os.WriteFile(path, []byte(`
+			testing.logs.input "default" {
+				forward_to = []
+			}
+		`), 0644))
+
+		var stdout, stderr bytes.Buffer
+		err := newTest().Run([]string{path}, &stdout, &stderr)
+		require.Error(t, err)
+		require.Contains(t, stderr.String(), "at least one entry block must be set")
+	})
+}
@@ -16,6 +16,9 @@ func toolsCommand() *cobra.Command {
 
 	cmd.AddCommand(
 		getTools("prometheus.remote_write", remotewrite.InstallTools),
+		exportsCommand(),
+		scaffoldCommand(),
+		selfMonitoringCommand(),
 	)
 
 	return cmd
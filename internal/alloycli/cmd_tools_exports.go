@@ -0,0 +1,93 @@
+package alloycli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+func exportsCommand() *cobra.Command {
+	e := &alloyExports{
+		instance: "http://127.0.0.1:12345",
+		uiPrefix: "/",
+		timeout:  10 * time.Second,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "exports [flags]",
+		Short: "Print the exports of the components of a running instance",
+		Long: `The exports subcommand prints the current exports of the components of a
+running instance as JSON, like the targets exported by a discovery component.
+
+Components are sorted by module and ID, and their exports are encoded in the
+same format as in the API of the UI. Secrets are redacted.
+
+The --type flag keeps the components with the provided names, or name prefixes
+ending with a dot, like "discovery.". The --module flag keeps the components of
+a module and of the modules it contains.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return e.Run(cmd.Context(), os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVar(&e.instance, "instance", e.instance, "URL of the running instance")
+	cmd.Flags().StringVar(&e.uiPrefix, "server.http.ui-path-prefix", e.uiPrefix, "Prefix the UI of the instance is served at")
+	cmd.Flags().DurationVar(&e.timeout, "instance.timeout", e.timeout, "Timeout of the request to the instance")
+	cmd.Flags().StringSliceVar(&e.types, "type", e.types, "Component names or name prefixes ending with a dot to keep. Can be repeated")
+	cmd.Flags().StringVar(&e.module, "module", e.module, "ID of the module to keep the components of")
+	return cmd
+}
+
+type alloyExports struct {
+	instance string
+	uiPrefix string
+	timeout  time.Duration
+	types    []string
+	module   string
+}
+
+// Run writes the exports of the components of the instance to out.
+func (e *alloyExports) Run(ctx context.Context, out io.Writer) error {
+	exportsURL, err := url.JoinPath(e.instance, e.uiPrefix, "api/v0/web/exports")
+	if err != nil {
+		return fmt.Errorf("invalid instance URL: %w", err)
+	}
+	query := url.Values{}
+	if len(e.types) > 0 {
+		query.Set("type", strings.Join(e.types, ","))
+	}
+	if e.module != "" {
+		query.Set("module", e.module)
+	}
+	if len(query) > 0 {
+		exportsURL += "?" + query.Encode()
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, e.timeout)
+	defer cancel()
+
+	var exports json.RawMessage
+	if err := getJSON(ctx, http.DefaultClient, exportsURL, &exports); err != nil {
+		return fmt.Errorf("loading exports from %s: %w", e.instance, err)
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, exports, "", "  "); err != nil {
+		return err
+	}
+	buf.WriteByte('\n')
+	_, err = buf.WriteTo(out)
+	return err
+}
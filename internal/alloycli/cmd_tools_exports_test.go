@@ -0,0 +1,47 @@
+package alloycli
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExports(t *testing.T) {
+	var query string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ui/api/v0/web/exports", func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.RawQuery
+		_, _ = w.Write([]byte(`[{"moduleID":"","localID":"local.file.a","name":"local.file","exports":[]}]`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	e := &alloyExports{
+		instance: srv.URL,
+		uiPrefix: "/ui",
+		timeout:  time.Second,
+		types:    []string{"local.file", "discovery."},
+		module:   "module.file.a",
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, e.Run(context.Background(), &out))
+	require.Equal(t, "module=module.file.a&type=local.file%2Cdiscovery.", query)
+	require.Equal(t, `[
+  {
+    "moduleID": "",
+    "localID": "local.file.a",
+    "name": "local.file",
+    "exports": []
+  }
+]
+`, out.String())
+
+	e.uiPrefix = "/missing"
+	require.ErrorContains(t, e.Run(context.Background(), &out), "unexpected status 404 Not Found")
+}
@@ -0,0 +1,96 @@
+package alloycli
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/grafana/alloy/internal/scaffold"
+)
+
+func scaffoldCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scaffold",
+		Short: "Generate the boilerplate of new code",
+	}
+	cmd.AddCommand(scaffoldComponentCommand())
+	return cmd
+}
+
+func scaffoldComponentCommand() *cobra.Command {
+	root := "."
+
+	cmd := &cobra.Command{
+		Use:   "component [flags] spec",
+		Short: "Generate the boilerplate of a new component",
+		Long: fmt.Sprintf(`The component subcommand generates the package of a new component from a
+specification file, which is written in the Alloy syntax:
+
+  name      = "example.widget"
+  stability = "experimental"
+
+  argument "url" {
+    type        = "string"
+    description = "URL of the widget to poll."
+  }
+
+  argument "poll_frequency" {
+    type     = "duration"
+    optional = true
+    default  = "1m"
+  }
+
+  export "content" {
+    type = "string"
+  }
+
+  metric "polls_total" {
+    type = "counter"
+    help = "Total number of polls."
+  }
+
+The generated package holds the Arguments and Exports of the component, its
+registration, its metrics, and tests. The package is imported by
+internal/component/all so that the component is available to run. Existing
+files are never overwritten.
+
+Arguments and exports support the following types: %s.
+Metrics can be counters, gauges, or histograms. Community components set
+community = true instead of a stability level.
+`, strings.Join(scaffold.FieldTypes(), ", ")),
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+
+		RunE: func(_ *cobra.Command, args []string) error {
+			return runScaffoldComponent(args[0], root, os.Stdout)
+		},
+	}
+
+	cmd.Flags().StringVar(&root, "repository.path", root, "Path to the root of the Alloy repository to generate the component into")
+	return cmd
+}
+
+func runScaffoldComponent(specPath, root string, w io.Writer) error {
+	bb, err := os.ReadFile(specPath)
+	if err != nil {
+		return err
+	}
+	spec, err := scaffold.ParseSpec(bb)
+	if err != nil {
+		return fmt.Errorf("invalid spec %s: %w", specPath, err)
+	}
+
+	files, err := scaffold.Generate(spec, root)
+	for _, f := range files {
+		fmt.Fprintf(w, "wrote %s\n", f)
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\nImplement the TODOs of the generated files, then document %s in docs/sources/reference/components.\n", spec.Name)
+	return nil
+}
@@ -0,0 +1,251 @@
+package alloycli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"text/template"
+
+	"github.com/spf13/cobra"
+
+	"github.com/grafana/alloy/syntax/parser"
+	"github.com/grafana/alloy/syntax/printer"
+)
+
+func selfMonitoringCommand() *cobra.Command {
+	s := &alloySelfMonitoring{
+		job:            "integrations/alloy",
+		scrapeInterval: "60s",
+		logLevel:       "info",
+		logFormat:      "logfmt",
+	}
+
+	cmd := &cobra.Command{
+		Use:   "self-monitoring [flags]",
+		Short: "Generate a configuration which monitors Alloy itself",
+		Long: `The self-monitoring subcommand generates a configuration snippet which
+scrapes the metrics of Alloy, collects its logs, and sends them to the
+provided endpoints.
+
+The metrics and logs have the job and instance labels, and the cluster and
+namespace labels when --cluster and --namespace are set, which are used by the
+dashboards of the Alloy mixin. Logs also have a level label.
+
+Set --metrics.url to send metrics with Prometheus remote write, and --logs.url
+to send logs to Loki. At least one of them is required. Passwords are never
+written to the snippet: the snippet reads them from the environment variables
+named by --metrics.password-env and --logs.password-env.
+
+The snippet holds a logging block. If your configuration already has one, add
+the receiver of the snippet to its write_to argument instead.`,
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+
+		RunE: func(_ *cobra.Command, _ []string) error {
+			out := io.Writer(os.Stdout)
+			if s.output != "" {
+				f, err := os.Create(s.output)
+				if err != nil {
+					return err
+				}
+				defer f.Close()
+				out = f
+			}
+			return s.Run(out)
+		},
+	}
+
+	cmd.Flags().StringVar(&s.metricsURL, "metrics.url", s.metricsURL, "Prometheus remote write URL to send metrics to")
+	cmd.Flags().StringVar(&s.metricsUsername, "metrics.username", s.metricsUsername, "Basic authentication username for --metrics.url")
+	cmd.Flags().StringVar(&s.metricsPasswordEnv, "metrics.password-env", s.metricsPasswordEnv, "Environment variable holding the basic authentication password for --metrics.url")
+	cmd.Flags().StringVar(&s.logsURL, "logs.url", s.logsURL, "Loki push URL to send logs to")
+	cmd.Flags().StringVar(&s.logsUsername, "logs.username", s.logsUsername, "Basic authentication username for --logs.url")
+	cmd.Flags().StringVar(&s.logsPasswordEnv, "logs.password-env", s.logsPasswordEnv, "Environment variable holding the basic authentication password for --logs.url")
+	cmd.Flags().StringVar(&s.job, "job", s.job, "Value of the job label")
+	cmd.Flags().StringVar(&s.cluster, "cluster", s.cluster, "Value of the cluster label")
+	cmd.Flags().StringVar(&s.namespace, "namespace", s.namespace, "Value of the namespace label")
+	cmd.Flags().StringVar(&s.scrapeInterval, "scrape-interval", s.scrapeInterval, "How often to scrape the metrics")
+	cmd.Flags().StringVar(&s.logLevel, "log.level", s.logLevel, "Level of the logs to send. Supported values: error, warn, info, debug")
+	cmd.Flags().StringVar(&s.logFormat, "log.format", s.logFormat, "Format of the logs. Supported values: logfmt, json")
+	cmd.Flags().StringVarP(&s.output, "output", "o", s.output, "File to write the snippet to. Defaults to stdout")
+	registerFlagValues(cmd, "log.level", "error", "warn", "info", "debug")
+	registerFlagValues(cmd, "log.format", "logfmt", "json")
+	return cmd
+}
+
+type alloySelfMonitoring struct {
+	metricsURL         string
+	metricsUsername    string
+	metricsPasswordEnv string
+	logsURL            string
+	logsUsername       string
+	logsPasswordEnv    string
+	job                string
+	cluster            string
+	namespace          string
+	scrapeInterval     string
+	logLevel           string
+	logFormat          string
+	output             string
+}
+
+// Run writes the self-monitoring snippet to out.
+func (s *alloySelfMonitoring) Run(out io.Writer) error {
+	bb, err := s.generate()
+	if err != nil {
+		return err
+	}
+	_, err = out.Write(bb)
+	return err
+}
+
+func (s *alloySelfMonitoring) generate() ([]byte, error) {
+	if s.metricsURL == "" && s.logsURL == "" {
+		return nil, fmt.Errorf("at least one of --metrics.url and --logs.url must be set")
+	}
+	if s.job == "" {
+		return nil, fmt.Errorf("--job must not be empty")
+	}
+	if s.logFormat != "logfmt" && s.logFormat != "json" {
+		return nil, fmt.Errorf("invalid log format %q", s.logFormat)
+	}
+
+	var buf bytes.Buffer
+	data := map[string]any{
+		"MetricsURL":         s.metricsURL,
+		"MetricsUsername":    s.metricsUsername,
+		"MetricsPasswordEnv": s.metricsPasswordEnv,
+		"LogsURL":            s.logsURL,
+		"LogsUsername":       s.logsUsername,
+		"LogsPasswordEnv":    s.logsPasswordEnv,
+		"ScrapeInterval":     s.scrapeInterval,
+		"LogLevel":           s.logLevel,
+		"LogFormat":          s.logFormat,
+		"Labels":             s.labels(),
+	}
+	if err := selfMonitoringTemplate.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+
+	// Format the snippet like alloy fmt, which also checks that it's valid.
+	f, err := parser.ParseFile("self-monitoring.alloy", buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("generated an invalid snippet: %w", err)
+	}
+	var formatted bytes.Buffer
+	if err := printer.Fprint(&formatted, f); err != nil {
+		return nil, err
+	}
+	formatted.WriteByte('\n')
+	return formatted.Bytes(), nil
+}
+
+// labels returns the labels set on the metrics and logs, except instance,
+// in the order they're written to the snippet.
+func (s *alloySelfMonitoring) labels() [][2]string {
+	labels := [][2]string{{"job", s.job}}
+	if s.cluster != "" {
+		labels = append(labels, [2]string{"cluster", s.cluster})
+	}
+	if s.namespace != "" {
+		labels = append(labels, [2]string{"namespace", s.namespace})
+	}
+	return labels
+}
+
+var selfMonitoringTemplate = template.Must(template.New("self-monitoring").Funcs(template.FuncMap{
+	"quote": strconv.Quote,
+}).Parse(`// Monitoring of Alloy itself, generated with "alloy tools self-monitoring".
+// The labels match the ones used by the dashboards of the Alloy mixin.
+{{- if .MetricsURL }}
+
+prometheus.exporter.self "self_monitoring" {}
+
+discovery.relabel "self_monitoring" {
+	targets = prometheus.exporter.self.self_monitoring.targets
+{{- range .Labels }}
+
+	rule {
+		target_label = {{ quote (index . 0) }}
+		replacement  = {{ quote (index . 1) }}
+	}
+{{- end }}
+
+	rule {
+		target_label = "instance"
+		replacement  = constants.hostname
+	}
+}
+
+prometheus.scrape "self_monitoring" {
+	targets         = discovery.relabel.self_monitoring.output
+	forward_to      = [prometheus.remote_write.self_monitoring.receiver]
+	scrape_interval = {{ quote .ScrapeInterval }}
+}
+
+prometheus.remote_write "self_monitoring" {
+	endpoint {
+		url = {{ quote .MetricsURL }}
+{{- if or .MetricsUsername .MetricsPasswordEnv }}
+
+		basic_auth {
+			username = {{ quote .MetricsUsername }}
+{{- if .MetricsPasswordEnv }}
+			password = sys.env({{ quote .MetricsPasswordEnv }})
+{{- end }}
+		}
+{{- end }}
+	}
+}
+{{- end }}
+{{- if .LogsURL }}
+
+logging {
+	level    = {{ quote .LogLevel }}
+	format   = {{ quote .LogFormat }}
+	write_to = [loki.process.self_monitoring.receiver]
+}
+
+loki.process "self_monitoring" {
+	forward_to = [loki.write.self_monitoring.receiver]
+{{ if eq .LogFormat "json" }}
+	stage.json {
+		expressions = { level = "" }
+	}
+{{ else }}
+	stage.logfmt {
+		mapping = { level = "" }
+	}
+{{ end }}
+	stage.labels {
+		values = { level = "" }
+	}
+
+	stage.static_labels {
+		values = {
+{{- range .Labels }}
+			{{ index . 0 }} = {{ quote (index . 1) }},
+{{- end }}
+			instance = constants.hostname,
+		}
+	}
+}
+
+loki.write "self_monitoring" {
+	endpoint {
+		url = {{ quote .LogsURL }}
+{{- if or .LogsUsername .LogsPasswordEnv }}
+
+		basic_auth {
+			username = {{ quote .LogsUsername }}
+{{- if .LogsPasswordEnv }}
+			password = sys.env({{ quote .LogsPasswordEnv }})
+{{- end }}
+		}
+{{- end }}
+	}
+}
+{{- end }}
+`))
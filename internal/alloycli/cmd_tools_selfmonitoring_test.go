@@ -0,0 +1,98 @@
+package alloycli
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/featuregate"
+)
+
+func TestSelfMonitoring(t *testing.T) {
+	tests := []struct {
+		name     string
+		s        alloySelfMonitoring
+		contains []string
+		excludes []string
+	}{
+		{
+			name: "metrics and logs",
+			s: alloySelfMonitoring{
+				metricsURL:         "http://mimir:9009/api/v1/push",
+				metricsUsername:    "123",
+				metricsPasswordEnv: "MIMIR_PASSWORD",
+				logsURL:            "http://loki:3100/loki/api/v1/push",
+				job:                "integrations/alloy",
+				cluster:            "prod",
+				namespace:          "monitoring",
+				scrapeInterval:     "60s",
+				logLevel:           "info",
+				logFormat:          "logfmt",
+			},
+			contains: []string{
+				`target_label = "cluster"`,
+				`replacement  = "prod"`,
+				`password = sys.env("MIMIR_PASSWORD")`,
+				"stage.logfmt {",
+				`namespace = "monitoring",`,
+				`url = "http://loki:3100/loki/api/v1/push"`,
+			},
+			excludes: []string{"stage.json", "password = sys.env(\"\")"},
+		},
+		{
+			name: "metrics only",
+			s: alloySelfMonitoring{
+				metricsURL:     "http://mimir:9009/api/v1/push",
+				job:            "alloy",
+				scrapeInterval: "15s",
+				logLevel:       "info",
+				logFormat:      "logfmt",
+			},
+			contains: []string{`replacement  = "alloy"`, `scrape_interval = "15s"`},
+			excludes: []string{"logging", "loki.", "basic_auth", `"cluster"`},
+		},
+		{
+			name: "json logs only",
+			s: alloySelfMonitoring{
+				logsURL:         "http://loki:3100/loki/api/v1/push",
+				logsUsername:    "456",
+				logsPasswordEnv: "LOKI_PASSWORD",
+				job:             "integrations/alloy",
+				logLevel:        "warn",
+				logFormat:       "json",
+			},
+			contains: []string{`level    = "warn"`, `format   = "json"`, "stage.json {", `password = sys.env("LOKI_PASSWORD")`},
+			excludes: []string{"prometheus.", "stage.logfmt"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			require.NoError(t, tt.s.Run(&out))
+			for _, s := range tt.contains {
+				require.Contains(t, out.String(), s)
+			}
+			for _, s := range tt.excludes {
+				require.NotContains(t, out.String(), s)
+			}
+
+			// The snippet must be a valid configuration on its own.
+			path := filepath.Join(t.TempDir(), "self-monitoring.alloy")
+			require.NoError(t, os.WriteFile(path, out.Bytes(), 0644))
+
+			run := newAlloyRun()
+			run.minStability = featuregate.StabilityGenerallyAvailable
+			v := &alloyValidate{run: run, reportFormat: validateReportText}
+			var stdout, stderr bytes.Buffer
+			require.NoError(t, v.Run([]string{path}, &stdout, &stderr), stderr.String())
+		})
+	}
+
+	t.Run("no endpoint", func(t *testing.T) {
+		s := alloySelfMonitoring{job: "integrations/alloy", logFormat: "logfmt"}
+		require.EqualError(t, s.Run(&bytes.Buffer{}), "at least one of --metrics.url and --logs.url must be set")
+	})
+}
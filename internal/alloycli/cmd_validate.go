@@ -0,0 +1,187 @@
+package alloycli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/grafana/alloy/internal/runtime/logging"
+	"github.com/grafana/alloy/syntax/diag"
+)
+
+const (
+	validateReportText = "text"
+	validateReportJSON = "json"
+)
+
+func validateCommand() *cobra.Command {
+	v := &alloyValidate{
+		run:          newAlloyRun(),
+		reportFormat: validateReportText,
+	}
+
+	cmd := &cobra.Command{
+		Use:   "validate [flags] path...",
+		Short: "Validate configuration files",
+		Long: `The validate subcommand checks that configuration files would be loaded
+successfully by the run subcommand, without running them.
+
+Each path is validated independently and can point at a configuration file or
+directory, like the path given to run. validate accepts the same flags as run,
+which change how the configuration is loaded, for example --stability.level.
+
+validate parses the configuration, resolves references between components,
+imports modules, and decodes and validates the arguments of every component
+and configuration block. Components are never started.
+
+validate exits with a non-zero status code if any path contains errors. Use
+--report.format=json to write the diagnostics of every path to stdout as JSON.
+`,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+
+		RunE: func(_ *cobra.Command, args []string) error {
+			return v.Run(args, os.Stdout, os.Stderr)
+		},
+	}
+
+	v.run.addFlags(cmd)
+	addDeprecatedFlags(cmd)
+	cmd.Flags().StringVar(&v.reportFormat, "report.format", v.reportFormat, fmt.Sprintf("Format of the validation report. Supported values: %s, %s", validateReportText, validateReportJSON))
+	registerFlagValues(cmd, "report.format", validateReportText, validateReportJSON)
+	return cmd
+}
+
+type alloyValidate struct {
+	run          *alloyRun
+	reportFormat string
+}
+
+// validateResult holds the result of validating a single path.
+type validateResult struct {
+	Path        string               `json:"path"`
+	Valid       bool                 `json:"valid"`
+	Diagnostics []validateDiagnostic `json:"diagnostics"`
+
+	sources map[string][]byte // Used to print diagnostics.
+	diags   diag.Diagnostics
+}
+
+// validateDiagnostic is the JSON representation of a diag.Diagnostic.
+type validateDiagnostic struct {
+	Severity    string `json:"severity"`
+	Message     string `json:"message"`
+	Value       string `json:"value,omitempty"`
+	File        string `json:"file,omitempty"`
+	StartLine   int    `json:"start_line,omitempty"`
+	StartColumn int    `json:"start_column,omitempty"`
+	EndLine     int    `json:"end_line,omitempty"`
+	EndColumn   int    `json:"end_column,omitempty"`
+}
+
+func (fv *alloyValidate) Run(paths []string, stdout, stderr io.Writer) error {
+	if fv.reportFormat != validateReportText && fv.reportFormat != validateReportJSON {
+		return fmt.Errorf("invalid report format %q", fv.reportFormat)
+	}
+	if err := checkExpandEnv(fv.run.configFormat, fv.run.configExpandEnv); err != nil {
+		return err
+	}
+
+	// Only warnings and errors which aren't reported as diagnostics are logged.
+	l, err := logging.New(stderr, logging.Options{Level: logging.LevelWarn, Format: logging.FormatLogfmt})
+	if err != nil {
+		return fmt.Errorf("building logger: %w", err)
+	}
+	if err := fv.run.configurePrometheusMetricNameValidationScheme(l); err != nil {
+		return err
+	}
+
+	results := make([]validateResult, 0, len(paths))
+	var invalid []string
+	for _, path := range paths {
+		res := fv.validate(l, path)
+		if !res.Valid {
+			invalid = append(invalid, path)
+		}
+		results = append(results, res)
+	}
+
+	switch fv.reportFormat {
+	case validateReportJSON:
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			return fmt.Errorf("writing report: %w", err)
+		}
+	default:
+		p := diag.NewPrinter(diag.PrinterConfig{
+			Color:              !color.NoColor,
+			ContextLinesBefore: 1,
+			ContextLinesAfter:  1,
+		})
+		for _, res := range results {
+			if len(res.diags) > 0 {
+				_ = p.Fprint(stderr, res.sources, res.diags)
+				fmt.Fprintln(stderr)
+			}
+		}
+	}
+
+	if len(invalid) > 0 {
+		return fmt.Errorf("invalid configuration: %s", strings.Join(invalid, ", "))
+	}
+	return nil
+}
+
+// validate loads the configuration at path in a runtime which decodes the
+// arguments of components without building them.
+func (fv *alloyValidate) validate(l *logging.Logger, path string) validateResult {
+	res := validateResult{Path: path}
+
+	sources, err := loadSourceFiles(path, fv.run.configFormat, fv.run.configBypassConversionErrors, fv.run.configExtraArgs)
+	if err == nil && fv.run.configExpandEnv {
+		// On errors, sources holds the files referenced by the diagnostics.
+		sources, err = expandSourcesEnv(sources)
+	}
+	res.sources = sources
+	if err == nil {
+		_, err = fv.run.dryRunLoad(l, path, sources)
+	}
+
+	if err != nil && !errors.As(err, &res.diags) {
+		res.diags = diag.Diagnostics{{Severity: diag.SeverityLevelError, Message: err.Error()}}
+	}
+	res.Valid = !res.diags.HasErrors()
+
+	res.Diagnostics = make([]validateDiagnostic, 0, len(res.diags))
+	for _, d := range res.diags {
+		res.Diagnostics = append(res.Diagnostics, newValidateDiagnostic(d))
+	}
+	return res
+}
+
+func newValidateDiagnostic(d diag.Diagnostic) validateDiagnostic {
+	res := validateDiagnostic{
+		Severity: "error",
+		Message:  d.Message,
+		Value:    d.Value,
+		File:     d.StartPos.Filename,
+	}
+	if d.Severity == diag.SeverityLevelWarn {
+		res.Severity = "warn"
+	}
+	if d.StartPos.Valid() {
+		res.StartLine, res.StartColumn = d.StartPos.Line, d.StartPos.Column
+		res.EndLine, res.EndColumn = d.StartPos.Line, d.StartPos.Column
+	}
+	if d.EndPos.Valid() {
+		res.EndLine, res.EndColumn = d.EndPos.Line, d.EndPos.Column
+	}
+	return res
+}
@@ -0,0 +1,138 @@
+package alloycli
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/featuregate"
+)
+
+func TestValidate(t *testing.T) {
+	dir := t.TempDir()
+	logPath := filepath.Join(dir, "logs", "alloy.log")
+
+	validPath := filepath.Join(dir, "valid.alloy")
+	require.NoError(t, os.WriteFile(validPath, []byte(`
+		logging {
+			file {
+				path = `+quote(logPath)+`
+			}
+		}
+
+		declare "pipeline" {
+			argument "url" {}
+
+			prometheus.remote_write "default" {
+				endpoint {
+					url = argument.url.value
+				}
+			}
+		}
+
+		pipeline "default" {
+			url = "http://localhost:9090/api/v1/write"
+		}
+
+		prometheus.exporter.self "default" {}
+
+		prometheus.scrape "default" {
+			targets    = prometheus.exporter.self.default.targets
+			forward_to = [prometheus.remote_write.default.receiver]
+		}
+
+		prometheus.remote_write "default" {
+			endpoint {
+				url = "http://localhost:9090/api/v1/write"
+			}
+		}
+	`), 0644))
+
+	invalidPath := filepath.Join(dir, "invalid.alloy")
+	require.NoError(t, os.WriteFile(invalidPath, []byte(`
+		prometheus.scrape "default" {
+			targets         = []
+			forward_to      = [prometheus.remote_write.missing.receiver]
+			scrape_interval = "not a duration"
+		}
+	`), 0644))
+
+	newValidate := func(reportFormat string) *alloyValidate {
+		run := newAlloyRun()
+		run.minStability = featuregate.StabilityGenerallyAvailable
+		return &alloyValidate{run: run, reportFormat: reportFormat}
+	}
+
+	t.Run("valid", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		require.NoError(t, newValidate(validateReportText).Run([]string{validPath}, &stdout, &stderr))
+		require.Empty(t, stdout.String())
+
+		// Validation must not have side effects, like creating log files.
+		require.NoFileExists(t, logPath)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		err := newValidate(validateReportJSON).Run([]string{validPath, invalidPath}, &stdout, &stderr)
+		require.EqualError(t, err, "invalid configuration: "+invalidPath)
+
+		var results []validateResult
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &results))
+		require.Len(t, results, 2)
+
+		require.Equal(t, validPath, results[0].Path)
+		require.True(t, results[0].Valid)
+		require.Empty(t, results[0].Diagnostics)
+
+		require.Equal(t, invalidPath, results[1].Path)
+		require.False(t, results[1].Valid)
+		require.NotEmpty(t, results[1].Diagnostics)
+		d := results[1].Diagnostics[0]
+		require.Equal(t, "error", d.Severity)
+		require.Equal(t, invalidPath, d.File)
+		require.Equal(t, 4, d.StartLine)
+		require.Contains(t, d.Message, "prometheus.remote_write.missing")
+	})
+
+	t.Run("stability level", func(t *testing.T) {
+		experimentalPath := filepath.Join(dir, "experimental.alloy")
+		require.NoError(t, os.WriteFile(experimentalPath, []byte(`
+			prometheus.aggregate "default" {
+				forward_to = []
+			}
+		`), 0644))
+
+		var stdout, stderr bytes.Buffer
+		err := newValidate(validateReportJSON).Run([]string{experimentalPath}, &stdout, &stderr)
+		require.EqualError(t, err, "invalid configuration: "+experimentalPath)
+
+		var results []validateResult
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &results))
+		require.Len(t, results, 1)
+		require.False(t, results[0].Valid)
+		require.Contains(t, results[0].Diagnostics[0].Message, "experimental")
+		require.Equal(t, 2, results[0].Diagnostics[0].StartLine)
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		err := newValidate(validateReportJSON).Run([]string{filepath.Join(dir, "missing.alloy")}, &stdout, &stderr)
+		require.Error(t, err)
+
+		var results []validateResult
+		require.NoError(t, json.Unmarshal(stdout.Bytes(), &results))
+		require.Len(t, results, 1)
+		require.False(t, results[0].Valid)
+		require.Contains(t, results[0].Diagnostics[0].Message, "missing.alloy")
+	})
+}
+
+func quote(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
@@ -0,0 +1,170 @@
+package alloycli
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+
+	alloy_runtime "github.com/grafana/alloy/internal/runtime"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/syntax/ast"
+	"github.com/grafana/alloy/syntax/printer"
+)
+
+// reloader applies configurations to a runtime. Applies are serialized, can
+// time out, and can be rolled back to the last configuration applied
+// successfully when they fail.
+type reloader struct {
+	log log.Logger
+	// load applies a configuration to the runtime.
+	load func(*alloy_runtime.Source) error
+	// timeout is the maximum duration of an apply. Zero means no timeout.
+	timeout time.Duration
+	// rollback enables reapplying the previous configuration when an apply
+	// fails.
+	rollback bool
+
+	// mut is held for the whole duration of an apply, including the ones which
+	// timed out.
+	mut     sync.Mutex
+	current *alloy_runtime.Source
+}
+
+// apply applies source, logging the blocks it adds, removes, and updates.
+//
+// The runtime can't abort an apply, so an apply which times out keeps running
+// in the background and apply returns an error immediately. Later applies wait
+// for it to complete.
+func (r *reloader) apply(source *alloy_runtime.Source) error {
+	r.mut.Lock()
+
+	diff := diffSources(r.current, source)
+	level.Info(r.log).Log(append([]any{"msg", "applying config"}, diff.keyvals()...)...)
+
+	done := make(chan error, 1)
+	go func() { done <- r.load(source) }()
+
+	var timeout <-chan time.Time
+	if r.timeout > 0 {
+		timer := time.NewTimer(r.timeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case err := <-done:
+		defer r.mut.Unlock()
+		return r.finish(source, err)
+
+	case <-timeout:
+		err := fmt.Errorf("applying the config didn't complete within %s", r.timeout)
+		go func() {
+			defer r.mut.Unlock()
+
+			loadErr := <-done
+			level.Warn(r.log).Log("msg", "config apply which timed out completed", "timeout", r.timeout, "err", loadErr)
+			if r.rollback {
+				// The caller was told that the apply failed, so don't keep it
+				// even if it eventually succeeded.
+				loadErr = err
+			}
+			if err := r.finish(source, loadErr); err != nil {
+				level.Error(r.log).Log("msg", "failed to apply config", "err", err)
+			}
+		}()
+		return err
+	}
+}
+
+// finish records the result of applying source, rolling back to the previous
+// configuration if err isn't nil and rollbacks are enabled. r.mut must be
+// held.
+func (r *reloader) finish(source *alloy_runtime.Source, err error) error {
+	if err == nil {
+		r.current = source
+		return nil
+	}
+	if !r.rollback || r.current == nil {
+		return err
+	}
+
+	level.Warn(r.log).Log("msg", "rolling back to the previous config", "err", err)
+	if rollbackErr := r.load(r.current); rollbackErr != nil {
+		return fmt.Errorf("%w; rolling back to the previous config also failed: %s", err, rollbackErr)
+	}
+	level.Info(r.log).Log("msg", "rolled back to the previous config")
+	return fmt.Errorf("%w; rolled back to the previous config", err)
+}
+
+// sourceDiff holds the IDs of the top-level blocks which differ between two
+// configurations.
+type sourceDiff struct {
+	added, removed, updated []string
+	unchanged               int
+}
+
+// diffSources compares the top-level blocks of prev and next. prev may be nil.
+func diffSources(prev, next *alloy_runtime.Source) sourceDiff {
+	var (
+		diff       sourceDiff
+		prevBlocks = sourceBlocks(prev)
+		nextBlocks = sourceBlocks(next)
+	)
+	for id, text := range nextBlocks {
+		prevText, ok := prevBlocks[id]
+		switch {
+		case !ok:
+			diff.added = append(diff.added, id)
+		case prevText != text:
+			diff.updated = append(diff.updated, id)
+		default:
+			diff.unchanged++
+		}
+	}
+	for id := range prevBlocks {
+		if _, ok := nextBlocks[id]; !ok {
+			diff.removed = append(diff.removed, id)
+		}
+	}
+
+	slices.Sort(diff.added)
+	slices.Sort(diff.removed)
+	slices.Sort(diff.updated)
+	return diff
+}
+
+func (d sourceDiff) keyvals() []any {
+	return []any{
+		"added", strings.Join(d.added, ","),
+		"removed", strings.Join(d.removed, ","),
+		"updated", strings.Join(d.updated, ","),
+		"unchanged", d.unchanged,
+	}
+}
+
+// sourceBlocks returns the formatted top-level blocks of source by ID.
+func sourceBlocks(source *alloy_runtime.Source) map[string]string {
+	blocks := map[string]string{}
+	for _, f := range source.SourceFiles() {
+		for _, stmt := range f.Body {
+			b, ok := stmt.(*ast.BlockStmt)
+			if !ok {
+				continue
+			}
+			id := strings.Join(b.Name, ".")
+			if b.Label != "" {
+				id += "." + b.Label
+			}
+
+			var buf bytes.Buffer
+			_ = printer.Fprint(&buf, b)
+			blocks[id] = buf.String()
+		}
+	}
+	return blocks
+}
@@ -0,0 +1,147 @@
+package alloycli
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	alloy_runtime "github.com/grafana/alloy/internal/runtime"
+)
+
+// fakeRuntime records the sources applied by a reloader.
+type fakeRuntime struct {
+	mut     sync.Mutex
+	applied []*alloy_runtime.Source
+	fail    map[*alloy_runtime.Source]bool
+	block   chan struct{}
+}
+
+func (rt *fakeRuntime) load(source *alloy_runtime.Source) error {
+	if rt.block != nil {
+		<-rt.block
+	}
+	rt.mut.Lock()
+	defer rt.mut.Unlock()
+	rt.applied = append(rt.applied, source)
+	if rt.fail[source] {
+		return errors.New("load failed")
+	}
+	return nil
+}
+
+func (rt *fakeRuntime) appliedSources() []*alloy_runtime.Source {
+	rt.mut.Lock()
+	defer rt.mut.Unlock()
+	return append([]*alloy_runtime.Source{}, rt.applied...)
+}
+
+func parseTestSource(t *testing.T, text string) *alloy_runtime.Source {
+	t.Helper()
+	source, err := alloy_runtime.ParseSource(t.Name(), []byte(text))
+	require.NoError(t, err)
+	return source
+}
+
+func TestReloader(t *testing.T) {
+	t.Run("keeps failed config without rollback", func(t *testing.T) {
+		var (
+			good = parseTestSource(t, `logging {}`)
+			bad  = parseTestSource(t, `logging {}`)
+			rt   = &fakeRuntime{fail: map[*alloy_runtime.Source]bool{bad: true}}
+			r    = &reloader{log: log.NewNopLogger(), load: rt.load}
+		)
+		require.NoError(t, r.apply(good))
+		require.EqualError(t, r.apply(bad), "load failed")
+		require.Equal(t, []*alloy_runtime.Source{good, bad}, rt.appliedSources())
+		require.Same(t, good, r.current)
+	})
+
+	t.Run("rolls back failed config", func(t *testing.T) {
+		var (
+			good = parseTestSource(t, `logging {}`)
+			bad  = parseTestSource(t, `logging {}`)
+			rt   = &fakeRuntime{fail: map[*alloy_runtime.Source]bool{bad: true}}
+			r    = &reloader{log: log.NewNopLogger(), load: rt.load, rollback: true}
+		)
+		require.NoError(t, r.apply(good))
+		require.EqualError(t, r.apply(bad), "load failed; rolled back to the previous config")
+		require.Equal(t, []*alloy_runtime.Source{good, bad, good}, rt.appliedSources())
+		require.Same(t, good, r.current)
+	})
+
+	t.Run("doesn't roll back the initial config", func(t *testing.T) {
+		var (
+			bad = parseTestSource(t, `logging {}`)
+			rt  = &fakeRuntime{fail: map[*alloy_runtime.Source]bool{bad: true}}
+			r   = &reloader{log: log.NewNopLogger(), load: rt.load, rollback: true}
+		)
+		require.EqualError(t, r.apply(bad), "load failed")
+		require.Equal(t, []*alloy_runtime.Source{bad}, rt.appliedSources())
+		require.Nil(t, r.current)
+	})
+
+	t.Run("rolls back config which timed out", func(t *testing.T) {
+		var (
+			good = parseTestSource(t, `logging {}`)
+			slow = parseTestSource(t, `logging {}`)
+			rt   = &fakeRuntime{}
+			r    = &reloader{log: log.NewNopLogger(), load: rt.load, rollback: true, timeout: 10 * time.Millisecond}
+		)
+		require.NoError(t, r.apply(good))
+
+		rt.block = make(chan struct{})
+		require.EqualError(t, r.apply(slow), "applying the config didn't complete within 10ms")
+		close(rt.block)
+
+		// Wait for the rollback to complete.
+		r.mut.Lock()
+		defer r.mut.Unlock()
+		require.Equal(t, []*alloy_runtime.Source{good, slow, good}, rt.appliedSources())
+		require.Same(t, good, r.current)
+	})
+}
+
+func TestDiffSources(t *testing.T) {
+	prev := parseTestSource(t, `
+logging {
+	level = "info"
+}
+
+prometheus.scrape "default" {
+	targets    = []
+	forward_to = []
+}
+
+discovery.kubernetes "pods" {
+	role = "pod"
+}`)
+	next := parseTestSource(t, `
+logging {
+	level = "debug"
+}
+
+prometheus.scrape "default" {
+	targets    = []
+	forward_to = []
+}
+
+discovery.kubernetes "nodes" {
+	role = "node"
+}`)
+
+	require.Equal(t, sourceDiff{
+		added:   []string{"discovery.kubernetes.nodes"},
+		removed: []string{"discovery.kubernetes.pods"},
+		updated: []string{"logging"},
+
+		unchanged: 1,
+	}, diffSources(prev, next))
+
+	require.Equal(t, sourceDiff{
+		added: []string{"discovery.kubernetes.nodes", "logging", "prometheus.scrape.default"},
+	}, diffSources(nil, next))
+}
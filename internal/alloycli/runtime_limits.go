@@ -0,0 +1,228 @@
+package alloycli
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+// Sources of the runtime limits, exposed as the source label of the runtime
+// limit metrics.
+const (
+	// limitSourceEnv is used when the limit is set with an environment
+	// variable.
+	limitSourceEnv = "env"
+	// limitSourceAuto is used when the limit is derived from the limits of the
+	// cgroup or the system.
+	limitSourceAuto = "auto"
+	// limitSourceDefault is used when the limit is the default of the Go
+	// runtime.
+	limitSourceDefault = "default"
+)
+
+// memLimitSource returns the source of the memory limit after
+// applyAutoMemLimit set it to limit.
+func memLimitSource(limit int64) string {
+	switch {
+	case os.Getenv("GOMEMLIMIT") != "":
+		return limitSourceEnv
+	case limit > 0:
+		return limitSourceAuto
+	default:
+		return limitSourceDefault
+	}
+}
+
+// applyAutoMaxProcs sets GOMAXPROCS to the CPU quota of the cgroup of the
+// process, rounded down, unless the GOMAXPROCS environment variable is set.
+// It returns the source of GOMAXPROCS.
+//
+// The cgroup files are read from root, which is "/" outside of tests.
+func applyAutoMaxProcs(l log.Logger, root string) string {
+	if v, ok := os.LookupEnv("GOMAXPROCS"); ok {
+		level.Info(l).Log("msg", "GOMAXPROCS is already set, skipping", "GOMAXPROCS", v)
+		return limitSourceEnv
+	}
+
+	cpus, err := cgroupCPULimit(root)
+	if err != nil {
+		level.Error(l).Log("msg", "failed to read cgroup CPU limit", "err", err)
+		return limitSourceDefault
+	}
+	if cpus == 0 {
+		return limitSourceDefault
+	}
+
+	procs := max(1, int(math.Floor(cpus)))
+	if procs >= runtime.GOMAXPROCS(0) {
+		return limitSourceDefault
+	}
+	runtime.GOMAXPROCS(procs)
+	level.Info(l).Log("msg", "set GOMAXPROCS from cgroup CPU limit", "GOMAXPROCS", procs, "cpu_limit", cpus)
+	return limitSourceAuto
+}
+
+// cgroupCPULimit returns the number of CPUs that the cgroup of the process
+// is limited to, or 0 if it isn't limited. Both cgroup v1 and v2 are
+// supported.
+//
+// The limits of the parent cgroups apply too, so the lowest limit of the
+// cgroup hierarchy is returned.
+func cgroupCPULimit(root string) (float64, error) {
+	bb, err := os.ReadFile(filepath.Join(root, "proc", "self", "cgroup"))
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	var limit float64
+	for _, line := range strings.Split(strings.TrimSpace(string(bb)), "\n") {
+		// Each line has the format hierarchy-ID:controller-list:cgroup-path.
+		parts := strings.SplitN(line, ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+
+		var (
+			l   float64
+			err error
+		)
+		switch {
+		case parts[0] == "0" && parts[1] == "":
+			l, err = walkCgroup(filepath.Join(root, "sys", "fs", "cgroup"), parts[2], readCPUMax)
+		case slices.Contains(strings.Split(parts[1], ","), "cpu"):
+			l, err = walkCgroup(filepath.Join(root, "sys", "fs", "cgroup", "cpu"), parts[2], readCFSQuota)
+		default:
+			continue
+		}
+		if err != nil {
+			return 0, err
+		}
+		limit = minLimit(limit, l)
+	}
+	return limit, nil
+}
+
+// walkCgroup returns the lowest limit read by read from the directory of the
+// cgroup and of its parents, mounted at mount. Directories that don't exist,
+// like the ones of the parents of the cgroup of a container, are skipped.
+func walkCgroup(mount string, cgroup string, read func(dir string) (float64, error)) (float64, error) {
+	var limit float64
+	for p := path.Clean("/" + cgroup); ; p = path.Dir(p) {
+		l, err := read(filepath.Join(mount, filepath.FromSlash(p)))
+		if err != nil {
+			return 0, err
+		}
+		limit = minLimit(limit, l)
+		if p == "/" {
+			return limit, nil
+		}
+	}
+}
+
+// readCPUMax reads the CPU limit from the cpu.max file of a cgroup v2
+// directory, which holds the quota and the period, or max for no limit.
+func readCPUMax(dir string) (float64, error) {
+	bb, err := os.ReadFile(filepath.Join(dir, "cpu.max"))
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	fields := strings.Fields(string(bb))
+	if len(fields) == 0 || len(fields) > 2 {
+		return 0, fmt.Errorf("invalid cpu.max %q", string(bb))
+	}
+	if fields[0] == "max" {
+		return 0, nil
+	}
+	period := "100000"
+	if len(fields) == 2 {
+		period = fields[1]
+	}
+	return cpuQuota(fields[0], period)
+}
+
+// readCFSQuota reads the CPU limit from the cpu.cfs_quota_us and
+// cpu.cfs_period_us files of a cgroup v1 directory. A quota of -1 means no
+// limit.
+func readCFSQuota(dir string) (float64, error) {
+	quota, err := os.ReadFile(filepath.Join(dir, "cpu.cfs_quota_us"))
+	if os.IsNotExist(err) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+	if strings.TrimSpace(string(quota)) == "-1" {
+		return 0, nil
+	}
+
+	period, err := os.ReadFile(filepath.Join(dir, "cpu.cfs_period_us"))
+	if err != nil {
+		return 0, err
+	}
+	return cpuQuota(strings.TrimSpace(string(quota)), strings.TrimSpace(string(period)))
+}
+
+func cpuQuota(quota, period string) (float64, error) {
+	q, err := strconv.ParseFloat(quota, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid CPU quota %q: %w", quota, err)
+	}
+	p, err := strconv.ParseFloat(period, 64)
+	if err != nil || p <= 0 {
+		return 0, fmt.Errorf("invalid CPU period %q", period)
+	}
+	if q <= 0 {
+		return 0, nil
+	}
+	return q / p, nil
+}
+
+// minLimit returns the lowest of two limits, where 0 means no limit.
+func minLimit(a, b float64) float64 {
+	switch {
+	case a == 0:
+		return b
+	case b == 0:
+		return a
+	default:
+		return min(a, b)
+	}
+}
+
+// newRuntimeLimitsCollectors returns the metrics which expose the
+// GOMEMLIMIT and GOMAXPROCS values used by the Go runtime, and where they
+// come from.
+func newRuntimeLimitsCollectors(memLimitSource, maxProcsSource string) []prometheus.Collector {
+	return []prometheus.Collector{
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "alloy_runtime_gomemlimit_bytes",
+			Help:        "Soft memory limit of the Go runtime. The source label is env when set with GOMEMLIMIT, auto when derived from the cgroup or system memory, and default otherwise.",
+			ConstLabels: prometheus.Labels{"source": memLimitSource},
+		}, func() float64 {
+			return float64(debug.SetMemoryLimit(-1))
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Name:        "alloy_runtime_gomaxprocs",
+			Help:        "Number of CPUs that can execute Go code simultaneously. The source label is env when set with GOMAXPROCS, auto when derived from the cgroup CPU limit, and default otherwise.",
+			ConstLabels: prometheus.Labels{"source": maxProcsSource},
+		}, func() float64 {
+			return float64(runtime.GOMAXPROCS(0))
+		}),
+	}
+}
@@ -0,0 +1,135 @@
+package alloycli
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCgroupCPULimit(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  float64
+	}{
+		{
+			name:  "no cgroup",
+			files: map[string]string{},
+			want:  0,
+		},
+		{
+			name: "cgroup v2 without limit",
+			files: map[string]string{
+				"proc/self/cgroup":      "0::/\n",
+				"sys/fs/cgroup/cpu.max": "max 100000\n",
+			},
+			want: 0,
+		},
+		{
+			name: "cgroup v2 in a container",
+			files: map[string]string{
+				"proc/self/cgroup":      "0::/\n",
+				"sys/fs/cgroup/cpu.max": "250000 100000\n",
+			},
+			want: 2.5,
+		},
+		{
+			name: "cgroup v2 with a lower limit on a parent",
+			files: map[string]string{
+				"proc/self/cgroup": "0::/kubepods.slice/pod1/container1\n",
+				"sys/fs/cgroup/kubepods.slice/pod1/container1/cpu.max": "400000 100000\n",
+				"sys/fs/cgroup/kubepods.slice/pod1/cpu.max":            "150000 100000\n",
+				"sys/fs/cgroup/kubepods.slice/cpu.max":                 "max 100000\n",
+			},
+			want: 1.5,
+		},
+		{
+			name: "cgroup v1 in a container",
+			files: map[string]string{
+				"proc/self/cgroup":                    "12:memory:/docker/abc\n4:cpu,cpuacct:/docker/abc\n",
+				"sys/fs/cgroup/cpu/cpu.cfs_quota_us":  "50000\n",
+				"sys/fs/cgroup/cpu/cpu.cfs_period_us": "100000\n",
+			},
+			want: 0.5,
+		},
+		{
+			name: "cgroup v1 without limit",
+			files: map[string]string{
+				"proc/self/cgroup":                    "4:cpu,cpuacct:/\n",
+				"sys/fs/cgroup/cpu/cpu.cfs_quota_us":  "-1\n",
+				"sys/fs/cgroup/cpu/cpu.cfs_period_us": "100000\n",
+			},
+			want: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := writeCgroupFiles(t, tt.files)
+
+			limit, err := cgroupCPULimit(root)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, limit)
+		})
+	}
+
+	t.Run("invalid cpu.max", func(t *testing.T) {
+		root := writeCgroupFiles(t, map[string]string{
+			"proc/self/cgroup":      "0::/\n",
+			"sys/fs/cgroup/cpu.max": "unlimited 100000\n",
+		})
+
+		_, err := cgroupCPULimit(root)
+		require.ErrorContains(t, err, "invalid CPU quota")
+	})
+}
+
+func TestApplyAutoMaxProcs(t *testing.T) {
+	previous := runtime.GOMAXPROCS(0)
+	t.Cleanup(func() { runtime.GOMAXPROCS(previous) })
+
+	limited := writeCgroupFiles(t, map[string]string{
+		"proc/self/cgroup":      "0::/\n",
+		"sys/fs/cgroup/cpu.max": "150000 100000\n",
+	})
+
+	t.Run("cgroup limit", func(t *testing.T) {
+		if previous < 2 {
+			t.Skip("requires at least 2 CPUs")
+		}
+		runtime.GOMAXPROCS(previous)
+
+		require.Equal(t, limitSourceAuto, applyAutoMaxProcs(log.NewNopLogger(), limited))
+		require.Equal(t, 1, runtime.GOMAXPROCS(0))
+	})
+
+	t.Run("no cgroup limit", func(t *testing.T) {
+		runtime.GOMAXPROCS(previous)
+
+		require.Equal(t, limitSourceDefault, applyAutoMaxProcs(log.NewNopLogger(), t.TempDir()))
+		require.Equal(t, previous, runtime.GOMAXPROCS(0))
+	})
+
+	t.Run("environment variable", func(t *testing.T) {
+		runtime.GOMAXPROCS(previous)
+		t.Setenv("GOMAXPROCS", "3")
+
+		require.Equal(t, limitSourceEnv, applyAutoMaxProcs(log.NewNopLogger(), limited))
+		require.Equal(t, previous, runtime.GOMAXPROCS(0))
+	})
+}
+
+func writeCgroupFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	root := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	}
+	return root
+}
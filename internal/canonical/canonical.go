@@ -0,0 +1,234 @@
+// Package canonical reorders the statements of configuration files so that
+// equivalent configurations are written the same way, which makes diffs of
+// configurations across a fleet reviewable.
+package canonical
+
+import (
+	"bytes"
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/grafana/alloy/syntax/ast"
+	"github.com/grafana/alloy/syntax/parser"
+)
+
+// ArgsLookup returns the arguments of the component called name, a struct
+// or a pointer to a struct with alloy tags, or false if name isn't a
+// component.
+type ArgsLookup func(name string) (any, bool)
+
+// Order returns src with the statements of every body reordered:
+//
+//   - In the bodies of components and of their blocks, required attributes
+//     are written first, followed by optional attributes, both in alphabetical
+//     order, followed by blocks in the order of the fields of the arguments.
+//     Blocks of the same name and blocks of the same enum, like the stages of
+//     loki.process, keep their relative order.
+//   - At the top level of the file and in declare and template blocks,
+//     configuration blocks like logging are written first, followed by
+//     argument, import, and declare blocks, components, and export blocks.
+//     Blocks of each group are sorted by name, then by label.
+//   - In other bodies, attributes are sorted alphabetically and blocks keep
+//     their order.
+//
+// Comments are moved with the statement following them, or with the
+// statement they follow on the same line. The result must be formatted with
+// the printer package.
+func Order(filename string, src []byte, lookup ArgsLookup) ([]byte, error) {
+	f, err := parser.ParseFile(filename, src)
+	if err != nil {
+		return nil, err
+	}
+
+	o := orderer{src: src, lookup: lookup}
+	for _, g := range f.Comments {
+		o.comments = append(o.comments, g...)
+	}
+
+	var buf bytes.Buffer
+	o.writeBody(&buf, f.Body, 0, len(src), topLevel, nil)
+	return buf.Bytes(), nil
+}
+
+type bodyKind int
+
+const (
+	topLevel bodyKind = iota // Top level of a file, declare or template block.
+	unknown                  // Body whose schema is unknown.
+	known                    // Body of a component or of one of its blocks.
+)
+
+type orderer struct {
+	src      []byte
+	comments []*ast.Comment
+	lookup   ArgsLookup
+}
+
+// stmt is a statement to reorder with the source around it.
+type stmt struct {
+	node    ast.Stmt
+	end     int    // Offset after the statement and its comments.
+	lead    []byte // Comments before the statement.
+	sortKey sortKey
+	kind    bodyKind // Kind of the body of a block.
+	schema  schema   // Schema of the body of a block.
+}
+
+type sortKey struct {
+	group int
+	index int
+	name  string
+	label string
+}
+
+// writeBody writes the statements of body, found between the offsets start
+// and end of the source, to buf in canonical order. s is the schema of body
+// if kind is known.
+func (o *orderer) writeBody(buf *bytes.Buffer, body ast.Body, start, end int, kind bodyKind, s schema) {
+	if len(body) == 0 {
+		buf.Write(o.src[start:end])
+		return
+	}
+
+	stmts := make([]stmt, 0, len(body))
+	prev := start
+	for _, n := range body {
+		st := stmt{node: n, end: o.lineEnd(n)}
+		st.lead = bytes.TrimLeft(o.src[prev:ast.StartPos(n).Offset()], " \t\r\n")
+		st.sortKey, st.kind, st.schema = o.classify(n, kind, s)
+		stmts = append(stmts, st)
+		prev = st.end
+	}
+	slices.SortStableFunc(stmts, func(a, b stmt) int {
+		return cmp.Or(
+			cmp.Compare(a.sortKey.group, b.sortKey.group),
+			cmp.Compare(a.sortKey.index, b.sortKey.index),
+			strings.Compare(a.sortKey.name, b.sortKey.name),
+			strings.Compare(a.sortKey.label, b.sortKey.label),
+		)
+	})
+
+	if start > 0 {
+		// Start the statements of a block on a new line.
+		buf.WriteString("\n")
+	}
+	for i, st := range stmts {
+		if i > 0 {
+			_, prevAttr := stmts[i-1].node.(*ast.AttributeStmt)
+			_, attr := st.node.(*ast.AttributeStmt)
+			if prevAttr && attr && kind != topLevel {
+				buf.WriteString("\n")
+			} else {
+				buf.WriteString("\n\n")
+			}
+		}
+		buf.Write(st.lead)
+		o.writeStmt(buf, st)
+	}
+	buf.Write(o.src[prev:end])
+}
+
+func (o *orderer) writeStmt(buf *bytes.Buffer, st stmt) {
+	b, ok := st.node.(*ast.BlockStmt)
+	if !ok {
+		buf.Write(o.src[ast.StartPos(st.node).Offset():st.end])
+		return
+	}
+	lcurly, rcurly := b.LCurlyPos.Offset(), b.RCurlyPos.Offset()
+	buf.Write(o.src[b.NamePos.Offset() : lcurly+1])
+	o.writeBody(buf, b.Body, lcurly+1, rcurly, st.kind, st.schema)
+	buf.Write(o.src[rcurly:st.end])
+}
+
+// lineEnd returns the offset after n, including the comments following n on
+// the same line.
+func (o *orderer) lineEnd(n ast.Stmt) int {
+	endPos := ast.EndPos(n)
+	end := endPos.Offset() + 1
+	line := endPos.Position().Line
+	for _, c := range o.comments {
+		if c.StartPos.Offset() >= end && c.StartPos.Position().Line == line {
+			end = c.StartPos.Offset() + len(c.Text)
+		}
+	}
+	return end
+}
+
+// Groups of statements at the top level.
+const (
+	groupAttr   = iota
+	groupConfig // Blocks without labels, like logging.
+	groupArgument
+	groupImport
+	groupDeclare
+	groupComponent
+	groupExport
+)
+
+// Groups of statements in other bodies. Attributes of unknown bodies are
+// in groupAttr.
+const (
+	groupRequired = iota
+	groupOptional
+	groupBlock
+)
+
+// classify returns the sort key of n in a body of the given kind and schema,
+// and the kind and schema of the body of n if n is a block.
+func (o *orderer) classify(n ast.Stmt, kind bodyKind, s schema) (sortKey, bodyKind, schema) {
+	switch n := n.(type) {
+	case *ast.AttributeStmt:
+		key := sortKey{group: groupAttr, name: n.Name.Name}
+		if kind == known {
+			key.group = groupOptional
+			if f, ok := s[n.Name.Name]; ok && !f.block && !f.optional {
+				key.group = groupRequired
+			}
+		}
+		return key, unknown, nil
+
+	case *ast.BlockStmt:
+		name := strings.Join(n.Name, ".")
+		switch kind {
+		case topLevel:
+			key := sortKey{name: name, label: n.Label}
+			switch {
+			case name == "argument":
+				key.group = groupArgument
+			case name == "export":
+				key.group = groupExport
+			case name == "declare":
+				key.group = groupDeclare
+				return key, topLevel, nil
+			case strings.HasPrefix(name, "import."):
+				key.group = groupImport
+			case n.Label == "":
+				key.group = groupConfig
+			default:
+				// Components, including custom components and foreach blocks.
+				key.group = groupComponent
+				if args, ok := o.lookup(name); ok {
+					return key, known, schemaOf(args)
+				}
+			}
+			return key, unknown, nil
+
+		case known:
+			// Blocks keep the order of the arguments, and the relative order
+			// of the blocks of the same field.
+			if f, ok := s[name]; ok && f.block {
+				return sortKey{group: groupBlock, index: f.index}, known, schemaOfType(f.typ)
+			}
+			return sortKey{group: groupBlock, index: math.MaxInt}, unknown, nil
+
+		default:
+			if name == "template" {
+				return sortKey{group: groupBlock}, topLevel, nil
+			}
+			return sortKey{group: groupBlock}, unknown, nil
+		}
+	}
+	return sortKey{}, unknown, nil
+}
@@ -0,0 +1,205 @@
+package canonical
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/syntax/parser"
+	"github.com/grafana/alloy/syntax/printer"
+)
+
+type testArgs struct {
+	Optional  string          `alloy:"optional,attr,optional"`
+	Required  string          `alloy:"required,attr"`
+	Another   int             `alloy:"another,attr"`
+	Endpoints []testEndpoint  `alloy:"endpoint,block"`
+	Common    testCommon      `alloy:",squash"`
+	Stages    []testStageEnum `alloy:",enum"`
+	Settings  *testSettings   `alloy:"settings,block,optional"`
+}
+
+type testEndpoint struct {
+	URL     string `alloy:"url,attr"`
+	Name    string `alloy:"name,attr,optional"`
+	Timeout string `alloy:"timeout,attr,optional"`
+}
+
+type testCommon struct {
+	Debug bool `alloy:"debug,attr,optional"`
+}
+
+type testStageEnum struct {
+	JSON  *struct{} `alloy:"stage.json,block,optional"`
+	Regex *struct{} `alloy:"stage.regex,block,optional"`
+}
+
+type testSettings struct {
+	Enabled bool `alloy:"enabled,attr,optional"`
+}
+
+func testLookup(name string) (any, bool) {
+	if name == "test.component" {
+		return testArgs{}, true
+	}
+	return nil, false
+}
+
+func TestOrder(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		expect string
+	}{
+		{
+			name: "component",
+			input: `
+test.component "default" {
+	settings {
+		enabled = true
+	}
+	stage.regex {}
+	optional = "a" // Trailing comment.
+	endpoint {
+		timeout = "1s"
+		url     = "http://b"
+	}
+	// Comment about debug.
+	debug = true
+	stage.json {}
+	endpoint {
+		url = "http://a"
+	}
+	required = "b"
+	another  = 1
+}
+`,
+			expect: `test.component "default" {
+	another  = 1
+	required = "b"
+	// Comment about debug.
+	debug    = true
+	optional = "a" // Trailing comment.
+
+	endpoint {
+		url     = "http://b"
+		timeout = "1s"
+	}
+
+	endpoint {
+		url = "http://a"
+	}
+
+	stage.regex { }
+
+	stage.json { }
+
+	settings {
+		enabled = true
+	}
+}
+`,
+		},
+		{
+			name: "top level",
+			input: `
+// The exported value.
+export "out" {
+	value = test.component.b.output
+}
+
+test.component "b" {
+	required = "b"
+	another  = 2
+}
+
+logging {
+	level  = "debug"
+	format = "json"
+}
+custom "a" {
+	z = 1
+	a = 2
+}
+
+test.component "a" {
+	required = "a"
+	another  = 1
+}
+
+argument "in" {
+	optional = true
+}
+`,
+			expect: `logging {
+	format = "json"
+	level  = "debug"
+}
+
+argument "in" {
+	optional = true
+}
+
+custom "a" {
+	a = 2
+	z = 1
+}
+
+test.component "a" {
+	another  = 1
+	required = "a"
+}
+
+test.component "b" {
+	another  = 2
+	required = "b"
+}
+
+// The exported value.
+export "out" {
+	value = test.component.b.output
+}
+`,
+		},
+		{
+			name: "declare",
+			input: `declare "example" {
+	export "out" {
+		value = 1
+	}
+
+	argument "in" { }
+}
+`,
+			expect: `declare "example" {
+	argument "in" { }
+
+	export "out" {
+		value = 1
+	}
+}
+`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expect, order(t, tc.input))
+			require.Equal(t, tc.expect, order(t, tc.expect), "ordering is idempotent")
+		})
+	}
+}
+
+// order orders and formats src.
+func order(t *testing.T, src string) string {
+	out, err := Order("test.alloy", []byte(src), testLookup)
+	require.NoError(t, err)
+
+	f, err := parser.ParseFile("test.alloy", out)
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, printer.Fprint(&buf, f))
+	buf.WriteString("\n")
+	return buf.String()
+}
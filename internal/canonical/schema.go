@@ -0,0 +1,98 @@
+package canonical
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// schema describes the attributes and blocks of a body by name.
+type schema map[string]field
+
+type field struct {
+	index    int          // Order of the field in the arguments.
+	block    bool         // Whether the field is a block.
+	optional bool         // Whether the field is optional.
+	typ      reflect.Type // Type of the body of a block.
+}
+
+// schemaOf returns the schema of the arguments args.
+func schemaOf(args any) schema {
+	return schemaOfType(reflect.TypeOf(args))
+}
+
+// schemaOfType returns the schema of the struct type t, or of the type t
+// points to or holds elements of. It returns nil if t isn't a struct.
+func schemaOfType(t reflect.Type) schema {
+	t = structType(t)
+	if t == nil {
+		return nil
+	}
+	s := schema{}
+	index := 0
+	s.add(t, &index)
+	return s
+}
+
+// add adds the fields of the struct type t to s. index is the order of the
+// next field.
+func (s schema) add(t reflect.Type, index *int) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("alloy")
+		if !ok || tag == "-" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		name, flags := parts[0], parts[1:]
+
+		switch {
+		case slices.Contains(flags, "squash"):
+			if ft := structType(sf.Type); ft != nil {
+				s.add(ft, index)
+			}
+			continue
+
+		case slices.Contains(flags, "enum"):
+			// The blocks of an enum share the same order, so that they keep
+			// their relative order.
+			if ft := structType(sf.Type); ft != nil {
+				for j := 0; j < ft.NumField(); j++ {
+					bf := ft.Field(j)
+					bname, _, _ := strings.Cut(bf.Tag.Get("alloy"), ",")
+					if bname != "" {
+						s[bname] = field{index: *index, block: true, optional: true, typ: bf.Type}
+					}
+				}
+			}
+
+		case slices.Contains(flags, "attr"), slices.Contains(flags, "block"):
+			s[name] = field{
+				index:    *index,
+				block:    slices.Contains(flags, "block"),
+				optional: slices.Contains(flags, "optional"),
+				typ:      sf.Type,
+			}
+
+		default:
+			continue
+		}
+		*index++
+	}
+}
+
+// structType returns t if it's a struct type, or the struct type t points to
+// or holds elements of. It returns nil otherwise.
+func structType(t reflect.Type) reflect.Type {
+	for t != nil {
+		switch t.Kind() {
+		case reflect.Pointer, reflect.Slice, reflect.Array:
+			t = t.Elem()
+		case reflect.Struct:
+			return t
+		default:
+			return nil
+		}
+	}
+	return nil
+}
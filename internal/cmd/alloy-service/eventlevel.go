@@ -0,0 +1,48 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+)
+
+// eventLevel is the level of an event in the Windows Event Log.
+type eventLevel int
+
+const (
+	eventLevelInfo eventLevel = iota
+	eventLevelWarning
+	eventLevelError
+)
+
+// levelPattern matches the level of logfmt and JSON log lines.
+var levelPattern = regexp.MustCompile(`\blevel"?\s*[=:]\s*"?(\w+)`)
+
+// levelOf returns the level to log msg with in the Windows Event Log.
+//
+// The level is taken from the level key of logfmt and JSON log lines. The
+// level of other lines, like the error printed when Alloy fails to start, is
+// guessed from their text.
+func levelOf(msg string) eventLevel {
+	if m := levelPattern.FindStringSubmatch(msg); m != nil {
+		switch strings.ToLower(m[1]) {
+		case "warn", "warning":
+			return eventLevelWarning
+		case "error", "fatal", "panic", "crit", "critical":
+			return eventLevelError
+		default:
+			return eventLevelInfo
+		}
+	}
+
+	lower := strings.ToLower(msg)
+	switch {
+	case strings.HasPrefix(strings.TrimSpace(lower), "error"), strings.HasPrefix(lower, "panic:"):
+		return eventLevelError
+	case strings.Contains(lower, "warn"):
+		return eventLevelWarning
+	case strings.Contains(lower, "error"):
+		return eventLevelError
+	default:
+		return eventLevelInfo
+	}
+}
@@ -0,0 +1,30 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_levelOf(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want eventLevel
+	}{
+		{`ts=2025-01-01T00:00:00Z level=info msg="starting complete graph evaluation"`, eventLevelInfo},
+		{`ts=2025-01-01T00:00:00Z level=info msg="finished node evaluation" node_id=loki.source.file.error_logs`, eventLevelInfo},
+		{`ts=2025-01-01T00:00:00Z level=warn msg="failed to watch file" err="no such file"`, eventLevelWarning},
+		{`ts=2025-01-01T00:00:00Z level=error msg="failed to evaluate config"`, eventLevelError},
+		{`{"ts":"2025-01-01T00:00:00Z","level":"warn","msg":"tls config is deprecated"}`, eventLevelWarning},
+		{`{"ts":"2025-01-01T00:00:00Z","level":"error","msg":"failed to evaluate config"}`, eventLevelError},
+		{"Error: could not perform the initial load successfully", eventLevelError},
+		{"panic: runtime error: invalid memory address or nil pointer dereference", eventLevelError},
+		{"warning: deprecated flag", eventLevelWarning},
+		{"Alloy is ready.", eventLevelInfo},
+	}
+	for _, tt := range tests {
+		t.Run(tt.msg, func(t *testing.T) {
+			require.Equal(t, tt.want, levelOf(tt.msg))
+		})
+	}
+}
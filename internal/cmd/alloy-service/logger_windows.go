@@ -54,30 +54,18 @@ func (l *logger) Log(kvps ...interface{}) error {
 	return log.NewLogfmtLogger(l).Log(kvps...)
 }
 
-var (
-	warnText  = "warn"
-	errorText = "error"
-)
-
 // Write implements [io.Writer], writing the provided data to the event logger.
-// If the data contains the phrase "warn," then the text is logged as a
-// warn-level event. If the data contains the phrase "error," then the text is
-// logged as an error-level event.
+// The level of the event is determined by [levelOf].
 func (l *logger) Write(data []byte) (n int, err error) {
 	var (
 		leveledLogger = l.el.Info
 		msg           = string(data)
 	)
 
-	// TODO(rfratto): Find a way to reduce the amount of false positives where
-	// log lines get incorrectly flagged as warning/error log lines.
-	//
-	// A longer-term solution would need to consider that logs may be emitted as
-	// either logfmt or JSON.
-	switch {
-	case strings.Contains(msg, warnText):
+	switch levelOf(msg) {
+	case eventLevelWarning:
 		leveledLogger = l.el.Warning
-	case strings.Contains(msg, errorText):
+	case eventLevelError:
 		leveledLogger = l.el.Error
 	}
 
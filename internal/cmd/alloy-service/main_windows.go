@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
@@ -36,6 +37,7 @@ func main() {
 		Args:        managerConfig.Args,
 		Environment: managerConfig.Environment,
 		Dir:         managerConfig.WorkingDirectory,
+		ReloadURL:   reloadURL(managerConfig.Args),
 
 		// Send logs directly to the event logger.
 		Stdout: logger,
@@ -54,7 +56,11 @@ type alloyService struct {
 	cfg    serviceManagerConfig
 }
 
-const cmdsAccepted = svc.AcceptStop | svc.AcceptShutdown
+const cmdsAccepted = svc.AcceptStop | svc.AcceptShutdown | svc.AcceptParamChange
+
+// reloadTimeout is the maximum time to wait for Alloy to reload its
+// configuration.
+const reloadTimeout = time.Minute
 
 func (as *alloyService) Execute(args []string, r <-chan svc.ChangeRequest, s chan<- svc.Status) (svcSpecificEC bool, exitCode uint32) {
 	defer func() {
@@ -70,18 +76,16 @@ func (as *alloyService) Execute(args []string, r <-chan svc.ChangeRequest, s cha
 	s <- svc.Status{State: svc.StartPending}
 
 	// Run the serviceManager.
-	{
-		sm := newServiceManager(as.logger, as.cfg)
-
-		workers.Add(1)
-		go func() {
-			// In case the service manager exits on its own, we cancel our context to
-			// signal to the parent goroutine to exit.
-			defer cancel()
-			defer workers.Done()
-			sm.Run(ctx)
-		}()
-	}
+	sm := newServiceManager(as.logger, as.cfg)
+
+	workers.Add(1)
+	go func() {
+		// In case the service manager exits on its own, we cancel our context to
+		// signal to the parent goroutine to exit.
+		defer cancel()
+		defer workers.Done()
+		sm.Run(ctx)
+	}()
 
 	s <- svc.Status{State: svc.Running, Accepts: cmdsAccepted}
 	defer func() {
@@ -99,6 +103,14 @@ func (as *alloyService) Execute(args []string, r <-chan svc.ChangeRequest, s cha
 				s <- req.CurrentStatus
 			case svc.Pause, svc.Continue:
 				// no-op
+			case svc.ParamChange:
+				// Reload in the background so that the service keeps answering
+				// control requests while Alloy reloads.
+				workers.Add(1)
+				go func() {
+					defer workers.Done()
+					as.reload(ctx, sm)
+				}()
 			default:
 				// Every other command should terminate the service.
 				return false, 0
@@ -106,3 +118,17 @@ func (as *alloyService) Execute(args []string, r <-chan svc.ChangeRequest, s cha
 		}
 	}
 }
+
+// reload requests Alloy to reload its configuration, which is triggered by
+// sending the paramchange control code to the service.
+func (as *alloyService) reload(ctx context.Context, sm *serviceManager) {
+	ctx, cancel := context.WithTimeout(ctx, reloadTimeout)
+	defer cancel()
+
+	level.Info(as.logger).Log("msg", "reloading configuration")
+	if err := sm.Reload(ctx); err != nil {
+		level.Error(as.logger).Log("msg", "failed to reload configuration", "err", err)
+		return
+	}
+	level.Info(as.logger).Log("msg", "reloaded configuration")
+}
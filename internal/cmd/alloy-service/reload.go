@@ -0,0 +1,44 @@
+package main
+
+import (
+	"net"
+	"strings"
+)
+
+// defaultListenAddr is the default address of the Alloy HTTP server.
+const defaultListenAddr = "127.0.0.1:12345"
+
+// reloadURL returns the URL of the reload endpoint of Alloy when it runs with
+// the provided arguments.
+//
+// Alloy doesn't support reloading through signals on Windows, so the
+// configuration is reloaded through its HTTP server instead.
+func reloadURL(args []string) string {
+	listenAddr := defaultListenAddr
+	for i, arg := range args {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if !strings.HasPrefix(arg, "-") || name != "server.http.listen-addr" {
+			continue
+		}
+		switch {
+		case hasValue:
+			listenAddr = value
+		case i+1 < len(args):
+			listenAddr = args[i+1]
+		}
+	}
+
+	host, port, err := net.SplitHostPort(strings.Trim(listenAddr, `"`))
+	if err != nil {
+		return ""
+	}
+	// The server listens on all interfaces; send the request over the loopback
+	// interface.
+	switch host {
+	case "", "0.0.0.0":
+		host = "127.0.0.1"
+	case "::":
+		host = "::1"
+	}
+	return "http://" + net.JoinHostPort(host, port) + "/-/reload"
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_reloadURL(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{
+			name: "default address",
+			args: []string{"run", `C:\Program Files\GrafanaLabs\Alloy\config.alloy`},
+			want: "http://127.0.0.1:12345/-/reload",
+		},
+		{
+			name: "flag with value",
+			args: []string{"run", "config.alloy", "--server.http.listen-addr=127.0.0.1:8080"},
+			want: "http://127.0.0.1:8080/-/reload",
+		},
+		{
+			name: "flag followed by value",
+			args: []string{"run", "--server.http.listen-addr", "localhost:8080", "config.alloy"},
+			want: "http://localhost:8080/-/reload",
+		},
+		{
+			name: "quoted value",
+			args: []string{"run", `--server.http.listen-addr="10.0.0.1:8080"`},
+			want: "http://10.0.0.1:8080/-/reload",
+		},
+		{
+			name: "all IPv4 interfaces",
+			args: []string{"run", "--server.http.listen-addr=0.0.0.0:12345"},
+			want: "http://127.0.0.1:12345/-/reload",
+		},
+		{
+			name: "all interfaces",
+			args: []string{"run", "--server.http.listen-addr=:12345"},
+			want: "http://127.0.0.1:12345/-/reload",
+		},
+		{
+			name: "all IPv6 interfaces",
+			args: []string{"run", "--server.http.listen-addr=[::]:12345"},
+			want: "http://[::1]:12345/-/reload",
+		},
+		{
+			name: "invalid address",
+			args: []string{"run", "--server.http.listen-addr=localhost"},
+			want: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, reloadURL(tt.args))
+		})
+	}
+}
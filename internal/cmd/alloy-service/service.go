@@ -2,9 +2,12 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"io"
+	"net/http"
 	"os"
 	"os/exec"
+	"strings"
 
 	"github.com/go-kit/log"
 	"github.com/go-kit/log/level"
@@ -36,6 +39,10 @@ type serviceManagerConfig struct {
 	//
 	// If Stdout or Stderr are nil, they will default to os.DevNull.
 	Stdout, Stderr io.Writer
+
+	// ReloadURL is the URL to request to reload the configuration of the
+	// binary. Reloading isn't supported if ReloadURL is empty.
+	ReloadURL string
 }
 
 // newServiceManager creates a new, unstarted serviceManager. Call
@@ -91,3 +98,26 @@ func (svc *serviceManager) buildCommand(ctx context.Context) *exec.Cmd {
 	cmd.Env = append(cmd.Env, svc.cfg.Environment...)
 	return cmd
 }
+
+// Reload requests the binary to reload its configuration.
+func (svc *serviceManager) Reload(ctx context.Context) error {
+	if svc.cfg.ReloadURL == "" {
+		return fmt.Errorf("reloading isn't supported")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, svc.cfg.ReloadURL, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("unexpected status code %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
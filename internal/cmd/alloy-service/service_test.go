@@ -136,6 +136,49 @@ func Test_serviceManager(t *testing.T) {
 			require.Equal(t, []byte("Hello, world!"), buf.Bytes())
 		})
 	})
+
+	t.Run("can reload service binary", func(t *testing.T) {
+		listenHost := getListenHost(t)
+
+		var buf syncBuffer
+
+		mgr := newServiceManager(l, serviceManagerConfig{
+			Path:      serviceBinary,
+			Args:      []string{"-listen-addr", listenHost},
+			Stdout:    &buf,
+			ReloadURL: fmt.Sprintf("http://%s/-/reload", listenHost),
+		})
+
+		ctx, cancel := context.WithCancel(componenttest.TestContext(t))
+		defer cancel()
+		go mgr.Run(ctx)
+
+		util.Eventually(t, func(t require.TestingT) {
+			require.NoError(t, mgr.Reload(ctx))
+		})
+
+		util.Eventually(t, func(t require.TestingT) {
+			require.Equal(t, []byte("config reloaded"), buf.Bytes())
+		})
+	})
+
+	t.Run("reports failed reloads", func(t *testing.T) {
+		listenHost := getListenHost(t)
+
+		mgr := newServiceManager(l, serviceManagerConfig{
+			Path:      serviceBinary,
+			Args:      []string{"-listen-addr", listenHost},
+			ReloadURL: fmt.Sprintf("http://%s/-/missing", listenHost),
+		})
+
+		ctx, cancel := context.WithCancel(componenttest.TestContext(t))
+		defer cancel()
+		go mgr.Run(ctx)
+
+		util.Eventually(t, func(t require.TestingT) {
+			require.ErrorContains(t, mgr.Reload(ctx), "unexpected status code 404")
+		})
+	})
 }
 
 func buildExampleService(t *testing.T, l log.Logger) string {
@@ -50,6 +50,13 @@ func run() error {
 	mux.HandleFunc("/echo/env", func(w http.ResponseWriter, r *http.Request) {
 		_, _ = w.Write([]byte(strings.Join(os.Environ(), "\n")))
 	})
+	mux.HandleFunc("/-/reload", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "unexpected method "+r.Method, http.StatusMethodNotAllowed)
+			return
+		}
+		_, _ = os.Stdout.Write([]byte("config reloaded"))
+	})
 
 	srv := &http.Server{Handler: mux}
 	_ = srv.Serve(lis)
@@ -40,16 +40,26 @@ type Routes struct {
 type Attributes struct {
 	Kubernetes KubernetesDecorator `alloy:"kubernetes,block"`
 	InstanceID InstanceIDConfig    `alloy:"instance_id,block,optional"`
+	HostID     HostIDConfig        `alloy:"host_id,block,optional"`
 	Select     Selections          `alloy:"select,block,optional"`
 }
 
 type KubernetesDecorator struct {
-	Enable                string        `alloy:"enable,attr"`
-	ClusterName           string        `alloy:"cluster_name,attr,optional"`
-	InformersSyncTimeout  time.Duration `alloy:"informers_sync_timeout,attr,optional"`
-	InformersResyncPeriod time.Duration `alloy:"informers_resync_period,attr,optional"`
-	DisableInformers      []string      `alloy:"disable_informers,attr,optional"`
-	MetaRestrictLocalNode bool          `alloy:"meta_restrict_local_node,attr,optional"`
+	Enable                string              `alloy:"enable,attr"`
+	ClusterName           string              `alloy:"cluster_name,attr,optional"`
+	InformersSyncTimeout  time.Duration       `alloy:"informers_sync_timeout,attr,optional"`
+	InformersResyncPeriod time.Duration       `alloy:"informers_resync_period,attr,optional"`
+	DisableInformers      []string            `alloy:"disable_informers,attr,optional"`
+	MetaRestrictLocalNode bool                `alloy:"meta_restrict_local_node,attr,optional"`
+	MetaCacheAddress      string              `alloy:"meta_cache_address,attr,optional"`
+	DropExternal          bool                `alloy:"drop_external,attr,optional"`
+	ResourceLabels        map[string][]string `alloy:"resource_labels,attr,optional"`
+	MetaSourceLabels      MetaSourceLabels    `alloy:"meta_source_labels,block,optional"`
+}
+
+type MetaSourceLabels struct {
+	ServiceName      string `alloy:"service_name,attr,optional"`
+	ServiceNamespace string `alloy:"service_namespace,attr,optional"`
 }
 
 type InstanceIDConfig struct {
@@ -57,6 +67,11 @@ type InstanceIDConfig struct {
 	OverrideHostname      string `alloy:"override_hostname,attr,optional"`
 }
 
+type HostIDConfig struct {
+	Override     string        `alloy:"override,attr,optional"`
+	FetchTimeout time.Duration `alloy:"fetch_timeout,attr,optional"`
+}
+
 type Selections []Selection
 
 type Selection struct {
@@ -126,6 +141,9 @@ type EBPF struct {
 	ContextPropagationEnabled bool          `alloy:"enable_context_propagation,attr,optional"`
 	HighRequestVolume         bool          `alloy:"high_request_volume,attr,optional"`
 	HeuristicSQLDetect        bool          `alloy:"heuristic_sql_detect,attr,optional"`
+	BatchLength               int           `alloy:"batch_length,attr,optional"`
+	BatchTimeout              time.Duration `alloy:"batch_timeout,attr,optional"`
+	TrafficControlBackend     string        `alloy:"traffic_control_backend,attr,optional"`
 }
 
 type Filters struct {
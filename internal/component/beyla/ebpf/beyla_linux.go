@@ -61,7 +61,9 @@ type Component struct {
 var _ component.HealthComponent = (*Component)(nil)
 
 func (args Routes) Convert() *transform.RoutesConfig {
-	routes := beyla.DefaultConfig.Routes
+	// Copy the default routes so that they aren't modified.
+	defaultRoutes := *beyla.DefaultConfig.Routes
+	routes := &defaultRoutes
 	if args.Unmatch != "" {
 		routes.Unmatch = transform.UnmatchType(args.Unmatch)
 	}
@@ -74,6 +76,25 @@ func (args Routes) Convert() *transform.RoutesConfig {
 	return routes
 }
 
+func (args Routes) Validate() error {
+	switch transform.UnmatchType(args.Unmatch) {
+	case "", transform.UnmatchUnset, transform.UnmatchPath, transform.UnmatchWildcard, transform.UnmatchHeuristic:
+	default:
+		return fmt.Errorf("routes.unmatched: invalid value %q. Valid values are: unset, path, wildcard, heuristic", args.Unmatch)
+	}
+
+	switch transform.IgnoreMode(args.IgnoredEvents) {
+	case "", transform.IgnoreMetrics, transform.IgnoreTraces, transform.IgnoreAll:
+	default:
+		return fmt.Errorf("routes.ignore_mode: invalid value %q. Valid values are: metrics, traces, all", args.IgnoredEvents)
+	}
+
+	if len(args.WildcardChar) > 1 {
+		return fmt.Errorf("routes.wildcard_char: must be a single character, got %q", args.WildcardChar)
+	}
+	return nil
+}
+
 func (args Attributes) Convert() beyla.Attributes {
 	attrs := beyla.DefaultConfig.Attributes
 	// Kubernetes
@@ -89,11 +110,23 @@ func (args Attributes) Convert() beyla.Attributes {
 	attrs.Kubernetes.DisableInformers = args.Kubernetes.DisableInformers
 	attrs.Kubernetes.MetaRestrictLocalNode = args.Kubernetes.MetaRestrictLocalNode
 	attrs.Kubernetes.ClusterName = args.Kubernetes.ClusterName
+	attrs.Kubernetes.MetaCacheAddress = args.Kubernetes.MetaCacheAddress
+	attrs.Kubernetes.DropExternal = args.Kubernetes.DropExternal
+	if args.Kubernetes.ResourceLabels != nil {
+		attrs.Kubernetes.ResourceLabels = args.Kubernetes.ResourceLabels
+	}
+	attrs.Kubernetes.MetaSourceLabels.ServiceName = args.Kubernetes.MetaSourceLabels.ServiceName
+	attrs.Kubernetes.MetaSourceLabels.ServiceNamespace = args.Kubernetes.MetaSourceLabels.ServiceNamespace
 	// InstanceID
 	if args.InstanceID.HostnameDNSResolution {
 		attrs.InstanceID.HostnameDNSResolution = args.InstanceID.HostnameDNSResolution
 	}
 	attrs.InstanceID.OverrideHostname = args.InstanceID.OverrideHostname
+	// HostID
+	attrs.HostID.Override = args.HostID.Override
+	if args.HostID.FetchTimeout != 0 {
+		attrs.HostID.FetchTimeout = args.HostID.FetchTimeout
+	}
 	// Selection
 	if args.Select != nil {
 		attrs.Select = args.Select.Convert()
@@ -346,9 +379,34 @@ func (args EBPF) Convert() beylaCfg.EBPFTracer {
 	ebpf.TrackRequestHeaders = args.TrackRequestHeaders
 	ebpf.HighRequestVolume = args.HighRequestVolume
 	ebpf.HeuristicSQLDetect = args.HeuristicSQLDetect
+	if args.BatchLength != 0 {
+		ebpf.BatchLength = args.BatchLength
+	}
+	if args.BatchTimeout != 0 {
+		ebpf.BatchTimeout = args.BatchTimeout
+	}
+	if args.TrafficControlBackend != "" {
+		// The backend has already been validated.
+		_ = ebpf.TCBackend.UnmarshalText([]byte(args.TrafficControlBackend))
+	}
 	return ebpf
 }
 
+func (args EBPF) Validate() error {
+	if args.BatchLength < 0 {
+		return fmt.Errorf("ebpf.batch_length: must be greater than or equal to 0")
+	}
+	if args.BatchTimeout < 0 {
+		return fmt.Errorf("ebpf.batch_timeout: must be greater than or equal to 0")
+	}
+	switch args.TrafficControlBackend {
+	case "", "tc", "tcx", "auto":
+	default:
+		return fmt.Errorf("ebpf.traffic_control_backend: invalid value %q. Valid values are: tc, tcx, auto", args.TrafficControlBackend)
+	}
+	return nil
+}
+
 func (args Filters) Convert() filter.AttributesConfig {
 	filters := filter.AttributesConfig{
 		Application: map[string]filter.MatchDefinition{},
@@ -369,6 +427,22 @@ func (args Filters) Convert() filter.AttributesConfig {
 	return filters
 }
 
+func (args Filters) Validate() error {
+	if err := args.Application.Validate("application"); err != nil {
+		return err
+	}
+	return args.Network.Validate("network")
+}
+
+func (args AttributeFamilies) Validate(family string) error {
+	for i, attr := range args {
+		if attr.Match == "" && attr.NotMatch == "" {
+			return fmt.Errorf("filters.%s[%d] must define at least one of: match or not_match", family, i)
+		}
+	}
+	return nil
+}
+
 func New(opts component.Options, args Arguments) (*Component, error) {
 	reg := prometheus.NewRegistry()
 	c := &Component{
@@ -559,6 +633,15 @@ func (args *Arguments) Validate() error {
 	if err := args.Metrics.Validate(); err != nil {
 		return err
 	}
+	if err := args.Routes.Validate(); err != nil {
+		return err
+	}
+	if err := args.EBPF.Validate(); err != nil {
+		return err
+	}
+	if err := args.Filters.Validate(); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -44,6 +44,19 @@ func TestArguments_UnmarshalSyntax(t *testing.T) {
 				cluster_name = "test"
 				disable_informers = ["node"]
 				meta_restrict_local_node = true
+				meta_cache_address = "beyla-k8s-cache:50055"
+				drop_external = true
+				resource_labels = {
+					"service.name" = ["app.kubernetes.io/name", "app"],
+				}
+				meta_source_labels {
+					service_name = "app.kubernetes.io/name"
+					service_namespace = "app.kubernetes.io/part-of"
+				}
+			}
+			host_id {
+				override = "test-host"
+				fetch_timeout = "2s"
 			}
 			select {
 				attr = "sql_client_duration"
@@ -101,6 +114,9 @@ func TestArguments_UnmarshalSyntax(t *testing.T) {
 			http_request_timeout = "10s"
 			high_request_volume = true
 			heuristic_sql_detect = true
+			batch_length = 50
+			batch_timeout = "2s"
+			traffic_control_backend = "tcx"
 		}
 		filters {
 			application {
@@ -131,6 +147,14 @@ func TestArguments_UnmarshalSyntax(t *testing.T) {
 	require.Equal(t, "test", cfg.Attributes.Kubernetes.ClusterName)
 	require.Equal(t, []string{"node"}, cfg.Attributes.Kubernetes.DisableInformers)
 	require.True(t, cfg.Attributes.Kubernetes.MetaRestrictLocalNode)
+	require.Equal(t, "beyla-k8s-cache:50055", cfg.Attributes.Kubernetes.MetaCacheAddress)
+	require.True(t, cfg.Attributes.Kubernetes.DropExternal)
+	require.Len(t, cfg.Attributes.Kubernetes.ResourceLabels, 1)
+	require.Equal(t, []string{"app.kubernetes.io/name", "app"}, cfg.Attributes.Kubernetes.ResourceLabels["service.name"])
+	require.Equal(t, "app.kubernetes.io/name", cfg.Attributes.Kubernetes.MetaSourceLabels.ServiceName)
+	require.Equal(t, "app.kubernetes.io/part-of", cfg.Attributes.Kubernetes.MetaSourceLabels.ServiceNamespace)
+	require.Equal(t, "test-host", cfg.Attributes.HostID.Override)
+	require.Equal(t, 2*time.Second, cfg.Attributes.HostID.FetchTimeout)
 	require.Len(t, cfg.Attributes.Select, 1)
 	sel, ok := cfg.Attributes.Select["sql_client_duration"]
 	require.True(t, ok)
@@ -166,6 +190,11 @@ func TestArguments_UnmarshalSyntax(t *testing.T) {
 	require.Equal(t, 10*time.Second, cfg.EBPF.HTTPRequestTimeout)
 	require.True(t, cfg.EBPF.HighRequestVolume)
 	require.True(t, cfg.EBPF.HeuristicSQLDetect)
+	require.Equal(t, 50, cfg.EBPF.BatchLength)
+	require.Equal(t, 2*time.Second, cfg.EBPF.BatchTimeout)
+	tcBackend := beyla.DefaultConfig.EBPF.TCBackend
+	require.NoError(t, tcBackend.UnmarshalText([]byte("tcx")))
+	require.Equal(t, tcBackend, cfg.EBPF.TCBackend)
 	require.Len(t, cfg.Filters.Application, 1)
 	require.Len(t, cfg.Filters.Network, 1)
 	require.Equal(t, filter.MatchDefinition{NotMatch: "UDP"}, cfg.Filters.Application["transport"])
@@ -295,6 +324,56 @@ func TestConvert_Routes(t *testing.T) {
 	config := args.Convert()
 
 	require.Equal(t, expectedConfig, config)
+	// The default routes must not be modified.
+	require.Empty(t, beyla.DefaultConfig.Routes.Patterns)
+	require.Equal(t, transform.UnmatchDefault, beyla.DefaultConfig.Routes.Unmatch)
+}
+
+func TestRoutes_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    Routes
+		wantErr string
+	}{
+		{
+			name: "empty routes",
+			args: Routes{},
+		},
+		{
+			name: "valid routes",
+			args: Routes{
+				Unmatch:       "path",
+				IgnoredEvents: "traces",
+				WildcardChar:  "#",
+			},
+		},
+		{
+			name:    "invalid unmatched",
+			args:    Routes{Unmatch: "invalid"},
+			wantErr: `routes.unmatched: invalid value "invalid". Valid values are: unset, path, wildcard, heuristic`,
+		},
+		{
+			name:    "invalid ignore mode",
+			args:    Routes{IgnoredEvents: "logs"},
+			wantErr: `routes.ignore_mode: invalid value "logs". Valid values are: metrics, traces, all`,
+		},
+		{
+			name:    "multi-character wildcard",
+			args:    Routes{WildcardChar: "**"},
+			wantErr: `routes.wildcard_char: must be a single character, got "**"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.args.Validate()
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
 }
 
 func TestConvert_Attributes(t *testing.T) {
@@ -496,6 +575,22 @@ func TestConvert_Filters(t *testing.T) {
 	require.Equal(t, expectedConfig, config)
 }
 
+func TestEBPF_Validate(t *testing.T) {
+	require.NoError(t, EBPF{BatchLength: 10, BatchTimeout: time.Second, TrafficControlBackend: "tc"}.Validate())
+	require.EqualError(t, EBPF{BatchLength: -1}.Validate(), "ebpf.batch_length: must be greater than or equal to 0")
+	require.EqualError(t, EBPF{TrafficControlBackend: "netlink"}.Validate(), `ebpf.traffic_control_backend: invalid value "netlink". Valid values are: tc, tcx, auto`)
+}
+
+func TestFilters_Validate(t *testing.T) {
+	require.NoError(t, Filters{
+		Application: AttributeFamilies{{Attr: "transport", NotMatch: "UDP"}},
+		Network:     AttributeFamilies{{Attr: "dst_port", Match: "53"}},
+	}.Validate())
+	require.EqualError(t, Filters{
+		Network: AttributeFamilies{{Attr: "dst_port", Match: "53"}, {Attr: "src_port"}},
+	}.Validate(), "filters.network[1] must define at least one of: match or not_match")
+}
+
 func TestServices_Validate(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"strings"
 
+	"github.com/grafana/alloy/internal/fips"
 	"github.com/grafana/alloy/syntax/alloytypes"
 	"github.com/prometheus/common/config"
 )
@@ -397,6 +398,11 @@ func (t *TLSConfig) Validate() error {
 	return nil
 }
 
+// CheckFIPS implements fips.Checker.
+func (t *TLSConfig) CheckFIPS() error {
+	return fips.CheckTLSVersion(uint16(t.MinVersion))
+}
+
 // OAuth2Config sets up the OAuth2 client.
 type OAuth2Config struct {
 	ClientID         string            `alloy:"client_id,attr,optional"`
@@ -230,3 +230,16 @@ func TestHTTPClientBadConfig(t *testing.T) {
 	err := syntax.Unmarshal([]byte(exampleAlloyConfig), &httpClientConfig)
 	require.ErrorContains(t, err, "at most one of basic_auth password & password_file must be configured")
 }
+
+func TestTLSConfigCheckFIPS(t *testing.T) {
+	var cfg TLSConfig
+	require.NoError(t, cfg.CheckFIPS())
+
+	err := syntax.Unmarshal([]byte(`min_version = "TLS13"`), &cfg)
+	require.NoError(t, err)
+	require.NoError(t, cfg.CheckFIPS())
+
+	err = syntax.Unmarshal([]byte(`min_version = "TLS10"`), &cfg)
+	require.NoError(t, err)
+	require.EqualError(t, cfg.CheckFIPS(), "TLS version TLS 1.0 is not FIPS approved, the minimum version is TLS 1.2")
+}
@@ -0,0 +1,96 @@
+package limit
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/time/rate"
+
+	"github.com/grafana/alloy/internal/util"
+)
+
+// Arguments configures the `limit` block of the loki.source components, which
+// limits the rate of the log entries they forward.
+type Arguments struct {
+	Rate  float64 `alloy:"rate,attr"`
+	Burst int     `alloy:"burst,attr"`
+	Drop  bool    `alloy:"drop,attr,optional"`
+}
+
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if a.Rate <= 0 {
+		return fmt.Errorf("rate must be greater than 0")
+	}
+	if a.Burst <= 0 {
+		return fmt.Errorf("burst must be greater than 0")
+	}
+	return nil
+}
+
+// Limiter limits the rate of the log entries forwarded by a component. Entries
+// which exceed the limit are either dropped, or held back until the limit
+// allows them, which applies backpressure to the component reading them.
+//
+// A Limiter without arguments lets all entries through.
+type Limiter struct {
+	dropped prometheus.Counter
+
+	mut     sync.RWMutex
+	drop    bool
+	limiter *rate.Limiter // nil when no limit is configured.
+}
+
+// NewLimiter creates a new Limiter and registers its metrics to reg.
+func NewLimiter(reg prometheus.Registerer) *Limiter {
+	dropped := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "loki_source_limit_dropped_entries_total",
+		Help: "Total number of log entries dropped because they exceeded the rate limit of the component.",
+	})
+	return &Limiter{
+		dropped: util.MustRegisterOrGet(reg, dropped).(prometheus.Counter),
+	}
+}
+
+// Update applies new arguments to the Limiter. A nil args removes the limit.
+// The tokens of the current limit are kept when the limit is changed.
+func (l *Limiter) Update(args *Arguments) {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	switch {
+	case args == nil:
+		l.limiter = nil
+	case l.limiter == nil:
+		l.limiter = rate.NewLimiter(rate.Limit(args.Rate), args.Burst)
+	default:
+		l.limiter.SetLimit(rate.Limit(args.Rate))
+		l.limiter.SetBurst(args.Burst)
+	}
+	if args != nil {
+		l.drop = args.Drop
+	}
+}
+
+// Allow reports whether the next entry should be forwarded. When the limit is
+// exceeded, Allow either drops the entry and returns false, or waits until
+// the entry is allowed. Allow returns false if ctx is canceled while waiting.
+func (l *Limiter) Allow(ctx context.Context) bool {
+	l.mut.RLock()
+	limiter, drop := l.limiter, l.drop
+	l.mut.RUnlock()
+
+	if limiter == nil {
+		return true
+	}
+	if drop {
+		if limiter.Allow() {
+			return true
+		}
+		l.dropped.Inc()
+		return false
+	}
+	return limiter.Wait(ctx) == nil
+}
@@ -0,0 +1,60 @@
+package limit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiter_NoLimit(t *testing.T) {
+	l := NewLimiter(prometheus.NewRegistry())
+	for range 100 {
+		require.True(t, l.Allow(context.Background()))
+	}
+}
+
+func TestLimiter_Drop(t *testing.T) {
+	l := NewLimiter(prometheus.NewRegistry())
+	l.Update(&Arguments{Rate: 0.001, Burst: 3, Drop: true})
+
+	var allowed int
+	for range 10 {
+		if l.Allow(context.Background()) {
+			allowed++
+		}
+	}
+	require.Equal(t, 3, allowed)
+	require.Equal(t, 7.0, testutil.ToFloat64(l.dropped))
+
+	// Removing the limit lets all entries through.
+	l.Update(nil)
+	require.True(t, l.Allow(context.Background()))
+}
+
+func TestLimiter_Block(t *testing.T) {
+	l := NewLimiter(prometheus.NewRegistry())
+	l.Update(&Arguments{Rate: 0.001, Burst: 1})
+	require.True(t, l.Allow(context.Background()))
+
+	// The next entry waits for the limit, until ctx is canceled.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.False(t, l.Allow(ctx))
+	require.Equal(t, 0.0, testutil.ToFloat64(l.dropped))
+
+	// Raising the limit unblocks the entries.
+	l.Update(&Arguments{Rate: 1000, Burst: 1})
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	require.True(t, l.Allow(ctx))
+}
+
+func TestArguments_Validate(t *testing.T) {
+	require.ErrorContains(t, (&Arguments{Rate: 0, Burst: 1}).Validate(), "rate must be greater than 0")
+	require.ErrorContains(t, (&Arguments{Rate: 1, Burst: 0}).Validate(), "burst must be greater than 0")
+	require.NoError(t, (&Arguments{Rate: 1, Burst: 1}).Validate())
+}
@@ -60,10 +60,12 @@ func ParseID(input string) ID {
 // InfoOptions is used by to determine how much information to return with
 // [Info].
 type InfoOptions struct {
-	GetHealth    bool // When true, sets the Health field of returned components.
-	GetArguments bool // When true, sets the Arguments field of returned components.
-	GetExports   bool // When true, sets the Exports field of returned components.
-	GetDebugInfo bool // When true, sets the DebugInfo field of returned components.
+	GetHealth        bool // When true, sets the Health field of returned components.
+	GetHealthHistory bool // When true, sets the HealthHistory field of returned components.
+	GetArguments     bool // When true, sets the Arguments field of returned components.
+	GetExports       bool // When true, sets the Exports field of returned components.
+	GetDebugInfo     bool // When true, sets the DebugInfo field of returned components.
+	GetEvaluation    bool // When true, sets the Evaluation field of returned components.
 }
 
 // String returns the "<ModuleID>/<LocalID>" string representation of the id.
@@ -126,10 +128,37 @@ type Info struct {
 	ComponentName string // Name of the component.
 	Health        Health // Current component health.
 
+	// HealthHistory holds recent health transitions of the component, ordered
+	// from newest to oldest.
+	HealthHistory []Health
+
 	Arguments            Arguments   // Current arguments value of the component.
 	Exports              Exports     // Current exports value of the component.
 	DebugInfo            interface{} // Current debug info of the component.
 	LiveDebuggingEnabled bool
+
+	// FIPSViolations holds the settings of the current arguments of the
+	// component which aren't approved by FIPS 140-3, if any.
+	FIPSViolations error
+
+	// Evaluation describes the last evaluation of the component. It's nil if
+	// it wasn't requested.
+	Evaluation *EvaluationInfo
+}
+
+// EvaluationInfo describes the evaluation of a component by its controller.
+type EvaluationInfo struct {
+	// Order is the position of the component in the evaluation order of the
+	// blocks of its module, where a block comes after all the blocks it
+	// depends on.
+	Order int
+
+	LastEvaluated time.Time     // Time the last evaluation started. Zero if never evaluated.
+	LastDuration  time.Duration // Duration of the last evaluation.
+
+	// LastQueueWait is the time the last evaluation waited in the evaluation
+	// queue after a dependency of the component updated its exports.
+	LastQueueWait time.Duration
 }
 
 // MarshalJSON returns a JSON representation of cd. The format of the
@@ -142,22 +171,31 @@ func (info *Info) MarshalJSON() ([]byte, error) {
 			UpdatedTime time.Time `json:"updatedTime"`
 		}
 
+		componentEvaluationJSON struct {
+			Order                int       `json:"order"`
+			LastEvaluatedTime    time.Time `json:"lastEvaluatedTime"`
+			LastDurationSeconds  float64   `json:"lastDurationSeconds"`
+			LastQueueWaitSeconds float64   `json:"lastQueueWaitSeconds"`
+		}
+
 		componentDetailJSON struct {
-			Name                 string               `json:"name"`
-			Type                 string               `json:"type,omitempty"`
-			LocalID              string               `json:"localID"`
-			ModuleID             string               `json:"moduleID"`
-			Label                string               `json:"label,omitempty"`
-			References           []string             `json:"referencesTo"`
-			ReferencedBy         []string             `json:"referencedBy"`
-			DataFlowEdgesTo      []string             `json:"dataFlowEdgesTo"`
-			Health               *componentHealthJSON `json:"health"`
-			Original             string               `json:"original"`
-			Arguments            json.RawMessage      `json:"arguments,omitempty"`
-			Exports              json.RawMessage      `json:"exports,omitempty"`
-			DebugInfo            json.RawMessage      `json:"debugInfo,omitempty"`
-			CreatedModuleIDs     []string             `json:"createdModuleIDs,omitempty"`
-			LiveDebuggingEnabled bool                 `json:"liveDebuggingEnabled"`
+			Name                 string                   `json:"name"`
+			Type                 string                   `json:"type,omitempty"`
+			LocalID              string                   `json:"localID"`
+			ModuleID             string                   `json:"moduleID"`
+			Label                string                   `json:"label,omitempty"`
+			References           []string                 `json:"referencesTo"`
+			ReferencedBy         []string                 `json:"referencedBy"`
+			DataFlowEdgesTo      []string                 `json:"dataFlowEdgesTo"`
+			Health               *componentHealthJSON     `json:"health"`
+			HealthHistory        []componentHealthJSON    `json:"healthHistory,omitempty"`
+			Original             string                   `json:"original"`
+			Arguments            json.RawMessage          `json:"arguments,omitempty"`
+			Exports              json.RawMessage          `json:"exports,omitempty"`
+			DebugInfo            json.RawMessage          `json:"debugInfo,omitempty"`
+			CreatedModuleIDs     []string                 `json:"createdModuleIDs,omitempty"`
+			LiveDebuggingEnabled bool                     `json:"liveDebuggingEnabled"`
+			Evaluation           *componentEvaluationJSON `json:"evaluation,omitempty"`
 		}
 	)
 
@@ -193,6 +231,25 @@ func (info *Info) MarshalJSON() ([]byte, error) {
 		return nil, err
 	}
 
+	var healthHistory []componentHealthJSON
+	for _, h := range info.HealthHistory {
+		healthHistory = append(healthHistory, componentHealthJSON{
+			State:       h.Health.String(),
+			Message:     h.Message,
+			UpdatedTime: h.UpdateTime,
+		})
+	}
+
+	var evaluation *componentEvaluationJSON
+	if info.Evaluation != nil {
+		evaluation = &componentEvaluationJSON{
+			Order:                info.Evaluation.Order,
+			LastEvaluatedTime:    info.Evaluation.LastEvaluated,
+			LastDurationSeconds:  info.Evaluation.LastDuration.Seconds(),
+			LastQueueWaitSeconds: info.Evaluation.LastQueueWait.Seconds(),
+		}
+	}
+
 	return json.Marshal(&componentDetailJSON{
 		Name:            info.ComponentName,
 		Type:            "block",
@@ -207,11 +264,13 @@ func (info *Info) MarshalJSON() ([]byte, error) {
 			Message:     info.Health.Message,
 			UpdatedTime: info.Health.UpdateTime,
 		},
+		HealthHistory:        healthHistory,
 		Arguments:            arguments,
 		Exports:              exports,
 		DebugInfo:            debugInfo,
 		CreatedModuleIDs:     info.ModuleIDs,
 		LiveDebuggingEnabled: info.LiveDebuggingEnabled,
+		Evaluation:           evaluation,
 	})
 }
 
@@ -1,4 +1,4 @@
-package collector
+package database_observability
 
 import (
 	"time"
@@ -7,13 +7,14 @@ import (
 	"github.com/prometheus/common/model"
 
 	"github.com/grafana/alloy/internal/component/common/loki"
-	"github.com/grafana/alloy/internal/component/database_observability"
 )
 
-func buildLokiEntry(op string, instanceKey string, line string) loki.Entry {
+// BuildLokiEntry builds a log entry for an operation of a collector, labeled
+// with the instance of the monitored database.
+func BuildLokiEntry(op string, instanceKey string, line string) loki.Entry {
 	return loki.Entry{
 		Labels: model.LabelSet{
-			"job":      database_observability.JobName,
+			"job":      JobName,
 			"op":       model.LabelValue(op),
 			"instance": model.LabelValue(instanceKey),
 		},
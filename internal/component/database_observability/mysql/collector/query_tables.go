@@ -12,6 +12,7 @@ import (
 	"go.uber.org/atomic"
 
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/database_observability"
 	"github.com/grafana/alloy/internal/component/database_observability/mysql/collector/parser"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
 )
@@ -154,7 +155,7 @@ func (c *QueryTables) tablesFromEventsStatements(ctx context.Context) error {
 
 		tables := c.sqlParser.ExtractTableNames(c.logger, digest, stmt)
 		for _, table := range tables {
-			c.entryHandler.Chan() <- buildLokiEntry(
+			c.entryHandler.Chan() <- database_observability.BuildLokiEntry(
 				OP_QUERY_PARSED_TABLE_NAME,
 				c.instanceKey,
 				fmt.Sprintf(`schema="%s" digest="%s" table="%s"`, schema, digest, table),
@@ -215,7 +216,7 @@ func (c *QueryTables) tablesFromPreparedStatements(ctx context.Context) error {
 
 		tables := c.sqlParser.ExtractTableNames(c.logger, schema, stmt)
 		for _, table := range tables {
-			c.entryHandler.Chan() <- buildLokiEntry(
+			c.entryHandler.Chan() <- database_observability.BuildLokiEntry(
 				OP_QUERY_PARSED_TABLE_NAME,
 				c.instanceKey,
 				fmt.Sprintf(`schema="%s" digest="%s" table="%s"`, schema, digest, table),
@@ -14,6 +14,7 @@ import (
 	"go.uber.org/atomic"
 
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/database_observability"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
 )
 
@@ -244,7 +245,7 @@ func (c *SchemaTable) extractSchema(ctx context.Context) error {
 		}
 		schemas = append(schemas, schema)
 
-		c.entryHandler.Chan() <- buildLokiEntry(
+		c.entryHandler.Chan() <- database_observability.BuildLokiEntry(
 			OP_SCHEMA_DETECTION,
 			c.instanceKey,
 			fmt.Sprintf(`schema="%s"`, schema),
@@ -288,7 +289,7 @@ func (c *SchemaTable) extractSchema(ctx context.Context) error {
 				b64TableSpec:  "",
 			})
 
-			c.entryHandler.Chan() <- buildLokiEntry(
+			c.entryHandler.Chan() <- database_observability.BuildLokiEntry(
 				OP_TABLE_DETECTION,
 				c.instanceKey,
 				fmt.Sprintf(`schema="%s" table="%s"`, schema, tableName),
@@ -330,7 +331,7 @@ func (c *SchemaTable) extractSchema(ctx context.Context) error {
 			}
 		}
 
-		c.entryHandler.Chan() <- buildLokiEntry(
+		c.entryHandler.Chan() <- database_observability.BuildLokiEntry(
 			OP_CREATE_STATEMENT,
 			c.instanceKey,
 			fmt.Sprintf(
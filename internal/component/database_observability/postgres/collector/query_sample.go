@@ -0,0 +1,226 @@
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/database_observability"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+const (
+	OP_QUERY_SAMPLE = "query_sample"
+	QuerySampleName = "query_sample"
+)
+
+// The normalized text of the queries is read from pg_stat_statements, which
+// replaces their constants with placeholders.
+const selectQuerySamples = `
+SELECT
+	activity.datname,
+	activity.pid,
+	activity.query_start,
+	EXTRACT(EPOCH FROM (now() - activity.query_start)) * 1000 AS elapsed_time_ms,
+	activity.query_id,
+	statements.query
+	%s
+FROM pg_stat_activity AS activity
+LEFT JOIN LATERAL (
+	SELECT query
+	FROM pg_stat_statements
+	WHERE queryid = activity.query_id AND dbid = activity.datid
+	LIMIT 1
+) AS statements ON true
+WHERE activity.state = 'active'
+	AND activity.backend_type = 'client backend'
+	AND activity.pid != pg_backend_pid()
+	AND activity.query_start IS NOT NULL
+	AND activity.datname IS NOT NULL`
+
+type QuerySampleArguments struct {
+	DB                    *sql.DB
+	InstanceKey           string
+	CollectInterval       time.Duration
+	EntryHandler          loki.EntryHandler
+	DisableQueryRedaction bool
+
+	Logger log.Logger
+}
+
+type QuerySample struct {
+	dbConnection          *sql.DB
+	instanceKey           string
+	collectInterval       time.Duration
+	entryHandler          loki.EntryHandler
+	disableQueryRedaction bool
+
+	logger  log.Logger
+	running *atomic.Bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+
+	// lastSamples are the queries seen at the previous collection, so that
+	// queries running across several collections are only sampled once.
+	lastSamples map[sampleKey]struct{}
+}
+
+// sampleKey identifies the execution of a query.
+type sampleKey struct {
+	pid        int64
+	queryStart time.Time
+}
+
+func NewQuerySample(args QuerySampleArguments) (*QuerySample, error) {
+	return &QuerySample{
+		dbConnection:          args.DB,
+		instanceKey:           args.InstanceKey,
+		collectInterval:       args.CollectInterval,
+		entryHandler:          args.EntryHandler,
+		disableQueryRedaction: args.DisableQueryRedaction,
+		logger:                log.With(args.Logger, "collector", QuerySampleName),
+		running:               &atomic.Bool{},
+		lastSamples:           map[sampleKey]struct{}{},
+	}, nil
+}
+
+func (c *QuerySample) Name() string {
+	return QuerySampleName
+}
+
+func (c *QuerySample) Start(ctx context.Context) error {
+	if c.disableQueryRedaction {
+		level.Warn(c.logger).Log("msg", "collector started with query redaction disabled. Query samples will include complete SQL text including query parameters.")
+	} else {
+		level.Debug(c.logger).Log("msg", "collector started")
+	}
+
+	c.running.Store(true)
+	ctx, cancel := context.WithCancel(ctx)
+	c.ctx = ctx
+	c.cancel = cancel
+
+	go func() {
+		defer func() {
+			c.Stop()
+			c.running.Store(false)
+		}()
+
+		ticker := time.NewTicker(c.collectInterval)
+
+		for {
+			if err := c.fetchQuerySamples(c.ctx); err != nil {
+				level.Error(c.logger).Log("msg", "collector error", "err", err)
+			}
+
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+				// continue loop
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (c *QuerySample) Stopped() bool {
+	return !c.running.Load()
+}
+
+// Stop should be kept idempotent
+func (c *QuerySample) Stop() {
+	c.cancel()
+}
+
+func (c *QuerySample) fetchQuerySamples(ctx context.Context) error {
+	var sqlTextField string
+	if c.disableQueryRedaction {
+		sqlTextField = ",activity.query"
+	}
+	query := fmt.Sprintf(selectQuerySamples, sqlTextField)
+
+	rs, err := c.dbConnection.QueryContext(ctx, query)
+	if err != nil {
+		level.Error(c.logger).Log("msg", "failed to fetch pg_stat_activity samples", "err", err)
+		return err
+	}
+	defer rs.Close()
+
+	samples := make(map[sampleKey]struct{})
+	for rs.Next() {
+		row := struct {
+			Database      string
+			PID           int64
+			QueryStart    time.Time
+			ElapsedTimeMs float64
+			QueryID       sql.NullInt64
+			DigestText    sql.NullString
+			SQLText       sql.NullString
+		}{}
+
+		scanArgs := []interface{}{
+			&row.Database,
+			&row.PID,
+			&row.QueryStart,
+			&row.ElapsedTimeMs,
+			&row.QueryID,
+			&row.DigestText,
+		}
+		if c.disableQueryRedaction {
+			scanArgs = append(scanArgs, &row.SQLText)
+		}
+
+		err := rs.Scan(scanArgs...)
+		if err != nil {
+			level.Error(c.logger).Log("msg", "failed to scan pg_stat_activity samples", "err", err)
+			continue
+		}
+
+		key := sampleKey{pid: row.PID, queryStart: row.QueryStart}
+		samples[key] = struct{}{}
+		if _, ok := c.lastSamples[key]; ok {
+			continue
+		}
+
+		// Queries are only sampled once pg_stat_statements has normalized
+		// them, so that their constants are never sent.
+		if !row.QueryID.Valid || !row.DigestText.Valid {
+			level.Debug(c.logger).Log("msg", "skipping query without normalized text", "schema", row.Database, "pid", row.PID)
+			continue
+		}
+
+		logMessage := fmt.Sprintf(
+			`schema="%s" pid="%d" digest="%d" digest_text="%s" elapsed_time="%fms" elapsed_time_ms="%fms"`,
+			row.Database,
+			row.PID,
+			row.QueryID.Int64,
+			row.DigestText.String,
+			row.ElapsedTimeMs,
+			row.ElapsedTimeMs,
+		)
+		if c.disableQueryRedaction && row.SQLText.Valid {
+			logMessage += fmt.Sprintf(` sql_text="%s"`, row.SQLText.String)
+		}
+
+		c.entryHandler.Chan() <- database_observability.BuildLokiEntry(
+			OP_QUERY_SAMPLE,
+			c.instanceKey,
+			logMessage,
+		)
+	}
+
+	if err := rs.Err(); err != nil {
+		level.Error(c.logger).Log("msg", "error during iterating over samples result set", "err", err)
+		return err
+	}
+
+	c.lastSamples = samples
+	return nil
+}
@@ -0,0 +1,173 @@
+package collector
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	loki_fake "github.com/grafana/alloy/internal/component/common/loki/client/fake"
+	"github.com/grafana/alloy/internal/component/database_observability"
+)
+
+var querySampleColumns = []string{
+	"datname",
+	"pid",
+	"query_start",
+	"elapsed_time_ms",
+	"query_id",
+	"query",
+}
+
+func TestQuerySample(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	queryStart := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testcases := []struct {
+		name        string
+		collections [][][]driver.Value
+		logsLines   []string
+	}{
+		{
+			name: "select query",
+			collections: [][][]driver.Value{{
+				{"some_db", int64(101), queryStart, float64(12.5), int64(-4857), "select * from some_table where id = $1"},
+			}},
+			logsLines: []string{
+				`schema="some_db" pid="101" digest="-4857" digest_text="select * from some_table where id = $1" elapsed_time="12.500000ms" elapsed_time_ms="12.500000ms"`,
+			},
+		},
+		{
+			name: "query without normalized text",
+			collections: [][][]driver.Value{{
+				{"some_db", int64(101), queryStart, float64(12.5), nil, nil},
+				{"some_db", int64(102), queryStart, float64(12.5), int64(42), nil},
+				{"some_db", int64(103), queryStart, float64(12.5), int64(43), "select 1"},
+			}},
+			logsLines: []string{
+				`schema="some_db" pid="103" digest="43" digest_text="select 1" elapsed_time="12.500000ms" elapsed_time_ms="12.500000ms"`,
+			},
+		},
+		{
+			name: "query running across collections",
+			collections: [][][]driver.Value{
+				{
+					{"some_db", int64(101), queryStart, float64(12.5), int64(42), "select pg_sleep($1)"},
+				},
+				{
+					{"some_db", int64(101), queryStart, float64(1012.5), int64(42), "select pg_sleep($1)"},
+					{"some_db", int64(102), queryStart.Add(time.Second), float64(1), int64(43), "select 1"},
+				},
+			},
+			logsLines: []string{
+				`schema="some_db" pid="101" digest="42" digest_text="select pg_sleep($1)" elapsed_time="12.500000ms" elapsed_time_ms="12.500000ms"`,
+				`schema="some_db" pid="102" digest="43" digest_text="select 1" elapsed_time="1.000000ms" elapsed_time_ms="1.000000ms"`,
+			},
+		},
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+			require.NoError(t, err)
+			defer db.Close()
+
+			lokiClient := loki_fake.NewClient(func() {})
+
+			collector, err := NewQuerySample(QuerySampleArguments{
+				DB:              db,
+				InstanceKey:     "postgres-db",
+				CollectInterval: time.Millisecond,
+				EntryHandler:    lokiClient,
+				Logger:          log.NewLogfmtLogger(os.Stderr),
+			})
+			require.NoError(t, err)
+			require.NotNil(t, collector)
+
+			for _, rows := range tc.collections {
+				mock.ExpectQuery(fmt.Sprintf(selectQuerySamples, "")).WithoutArgs().RowsWillBeClosed().
+					WillReturnRows(
+						sqlmock.NewRows(querySampleColumns).AddRows(rows...),
+					)
+			}
+
+			err = collector.Start(t.Context())
+			require.NoError(t, err)
+
+			require.Eventually(t, func() bool {
+				return mock.ExpectationsWereMet() == nil
+			}, 5*time.Second, 10*time.Millisecond)
+
+			collector.Stop()
+			lokiClient.Stop()
+
+			require.Eventually(t, func() bool {
+				return collector.Stopped()
+			}, 5*time.Second, 100*time.Millisecond)
+
+			lokiEntries := lokiClient.Received()
+			require.Equal(t, len(tc.logsLines), len(lokiEntries))
+			for i, entry := range lokiEntries {
+				require.Equal(t, model.LabelSet{"job": database_observability.JobName, "op": OP_QUERY_SAMPLE, "instance": "postgres-db"}, entry.Labels)
+				require.Equal(t, tc.logsLines[i], entry.Line)
+			}
+		})
+	}
+}
+
+func TestQuerySampleDisableQueryRedaction(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	lokiClient := loki_fake.NewClient(func() {})
+
+	collector, err := NewQuerySample(QuerySampleArguments{
+		DB:                    db,
+		InstanceKey:           "postgres-db",
+		CollectInterval:       time.Minute,
+		EntryHandler:          lokiClient,
+		Logger:                log.NewLogfmtLogger(os.Stderr),
+		DisableQueryRedaction: true,
+	})
+	require.NoError(t, err)
+
+	mock.ExpectQuery(fmt.Sprintf(selectQuerySamples, ",activity.query")).WithoutArgs().RowsWillBeClosed().
+		WillReturnRows(
+			sqlmock.NewRows(append(querySampleColumns, "sql_text")).AddRow(
+				"some_db", int64(101), time.Now(), float64(12.5), int64(42), "select * from some_table where id = $1", "select * from some_table where id = 1",
+			),
+		)
+
+	err = collector.Start(t.Context())
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return len(lokiClient.Received()) == 1
+	}, 5*time.Second, 100*time.Millisecond)
+
+	collector.Stop()
+	lokiClient.Stop()
+
+	require.Eventually(t, func() bool {
+		return collector.Stopped()
+	}, 5*time.Second, 100*time.Millisecond)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+	require.Equal(t,
+		`schema="some_db" pid="101" digest="42" digest_text="select * from some_table where id = $1" elapsed_time="12.500000ms" elapsed_time_ms="12.500000ms" sql_text="select * from some_table where id = 1"`,
+		lokiClient.Received()[0].Line,
+	)
+}
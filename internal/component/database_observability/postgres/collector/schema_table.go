@@ -0,0 +1,562 @@
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/hashicorp/golang-lru/v2/expirable"
+	"github.com/lib/pq"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/database_observability"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+const (
+	OP_SCHEMA_DETECTION = "schema_detection"
+	OP_TABLE_DETECTION  = "table_detection"
+	OP_CREATE_STATEMENT = "create_statement"
+	SchemaTableName     = "schema_table"
+)
+
+const (
+	selectSchemaName = `
+	SELECT
+		nspname
+	FROM
+		pg_catalog.pg_namespace
+	WHERE
+		nspname NOT IN ('pg_catalog', 'information_schema')
+		AND nspname NOT LIKE 'pg_toast%'
+		AND nspname NOT LIKE 'pg_temp_%'
+	ORDER BY nspname`
+
+	// The xmin of a table changes whenever its definition is altered, which
+	// PostgreSQL doesn't otherwise track.
+	selectTableName = `
+	SELECT
+		c.relname,
+		c.relkind,
+		c.xmin::text
+	FROM
+		pg_catalog.pg_class c
+		JOIN pg_catalog.pg_namespace n ON n.oid = c.relnamespace
+	WHERE
+		n.nspname = $1 AND c.relkind IN ('r', 'p', 'v', 'm', 'f')
+	ORDER BY c.relname`
+
+	selectViewDefinition = `SELECT pg_catalog.pg_get_viewdef(to_regclass($1), true)`
+
+	selectColumnNames = `
+	SELECT
+		a.attname,
+		pg_catalog.format_type(a.atttypid, a.atttypmod),
+		a.attnotnull,
+		pg_catalog.pg_get_expr(d.adbin, d.adrelid),
+		a.attidentity <> '',
+		i.indisprimary IS NOT NULL
+	FROM
+		pg_catalog.pg_attribute a
+		LEFT JOIN pg_catalog.pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		LEFT JOIN pg_catalog.pg_index i ON i.indrelid = a.attrelid AND i.indisprimary AND a.attnum = ANY(i.indkey)
+	WHERE
+		a.attrelid = to_regclass($1) AND a.attnum > 0 AND NOT a.attisdropped
+	ORDER BY a.attnum ASC`
+
+	// Expressions are returned for the key columns of an index which aren't
+	// table columns.
+	selectIndexNames = `
+	SELECT
+		ic.relname,
+		k.ord,
+		a.attname,
+		CASE WHEN k.attnum = 0 THEN pg_catalog.pg_get_indexdef(ix.indexrelid, k.ord::int, true) END,
+		NOT a.attnotnull,
+		ix.indisunique,
+		am.amname
+	FROM
+		pg_catalog.pg_index ix
+		JOIN pg_catalog.pg_class ic ON ic.oid = ix.indexrelid
+		JOIN pg_catalog.pg_am am ON am.oid = ic.relam
+		CROSS JOIN LATERAL unnest(ix.indkey::int2[]) WITH ORDINALITY AS k(attnum, ord)
+		LEFT JOIN pg_catalog.pg_attribute a ON a.attrelid = ix.indrelid AND a.attnum = k.attnum
+	WHERE
+		ix.indrelid = to_regclass($1)
+	ORDER BY ic.relname, k.ord`
+
+	selectForeignKeys = `
+	SELECT
+		con.conname,
+		a.attname,
+		ref.relname,
+		refa.attname
+	FROM
+		pg_catalog.pg_constraint con
+		CROSS JOIN LATERAL unnest(con.conkey, con.confkey) WITH ORDINALITY AS k(attnum, refattnum, ord)
+		JOIN pg_catalog.pg_attribute a ON a.attrelid = con.conrelid AND a.attnum = k.attnum
+		JOIN pg_catalog.pg_class ref ON ref.oid = con.confrelid
+		JOIN pg_catalog.pg_attribute refa ON refa.attrelid = con.confrelid AND refa.attnum = k.refattnum
+	WHERE
+		con.contype = 'f' AND con.conrelid = to_regclass($1)
+	ORDER BY con.conname, k.ord`
+)
+
+// tableTypes maps the kinds of relations of pg_class to the table types of
+// information_schema.tables.
+var tableTypes = map[string]string{
+	"r": "BASE TABLE",
+	"p": "BASE TABLE",
+	"v": "VIEW",
+	"m": "MATERIALIZED VIEW",
+	"f": "FOREIGN TABLE",
+}
+
+type SchemaTableArguments struct {
+	DB              *sql.DB
+	InstanceKey     string
+	CollectInterval time.Duration
+	EntryHandler    loki.EntryHandler
+
+	CacheEnabled bool
+	CacheSize    int
+	CacheTTL     time.Duration
+
+	Logger log.Logger
+}
+
+type SchemaTable struct {
+	dbConnection    *sql.DB
+	instanceKey     string
+	collectInterval time.Duration
+	entryHandler    loki.EntryHandler
+
+	// Cache of table definitions. Entries are removed after a configurable TTL.
+	// Key is a string of the form "schema.table@xmin", where xmin is the
+	// transaction which last altered the table (this allows capturing schema
+	// changes at each scan, regardless of caching).
+	cache *expirable.LRU[string, *tableInfo]
+
+	logger  log.Logger
+	running *atomic.Bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+type tableInfo struct {
+	schema        string
+	tableName     string
+	tableType     string
+	xmin          string
+	b64CreateStmt string
+	b64TableSpec  string
+}
+
+type tableSpec struct {
+	Columns     []columnSpec `json:"columns"`
+	Indexes     []indexSpec  `json:"indexes,omitempty"`
+	ForeignKeys []foreignKey `json:"foreign_keys,omitempty"`
+}
+type columnSpec struct {
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	NotNull       bool   `json:"not_null,omitempty"`
+	AutoIncrement bool   `json:"auto_increment,omitempty"`
+	PrimaryKey    bool   `json:"primary_key,omitempty"`
+	DefaultValue  string `json:"default_value,omitempty"`
+}
+
+type indexSpec struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Columns     []string `json:"columns"`
+	Expressions []string `json:"expressions,omitempty"`
+	Unique      bool     `json:"unique"`
+	Nullable    bool     `json:"nullable"`
+}
+
+type foreignKey struct {
+	Name                 string `json:"name"`
+	ColumnName           string `json:"column_name"`
+	ReferencedTableName  string `json:"referenced_table_name"`
+	ReferencedColumnName string `json:"referenced_column_name"`
+}
+
+func NewSchemaTable(args SchemaTableArguments) (*SchemaTable, error) {
+	c := &SchemaTable{
+		dbConnection:    args.DB,
+		instanceKey:     args.InstanceKey,
+		collectInterval: args.CollectInterval,
+		entryHandler:    args.EntryHandler,
+		logger:          log.With(args.Logger, "collector", SchemaTableName),
+		running:         &atomic.Bool{},
+	}
+
+	if args.CacheEnabled {
+		c.cache = expirable.NewLRU[string, *tableInfo](args.CacheSize, nil, args.CacheTTL)
+	}
+
+	return c, nil
+}
+
+func (c *SchemaTable) Name() string {
+	return SchemaTableName
+}
+
+func (c *SchemaTable) Start(ctx context.Context) error {
+	level.Debug(c.logger).Log("msg", SchemaTableName+" collector started")
+
+	c.running.Store(true)
+	ctx, cancel := context.WithCancel(ctx)
+	c.ctx = ctx
+	c.cancel = cancel
+
+	go func() {
+		defer func() {
+			c.Stop()
+			c.running.Store(false)
+		}()
+
+		ticker := time.NewTicker(c.collectInterval)
+
+		for {
+			if err := c.extractSchema(c.ctx); err != nil {
+				level.Error(c.logger).Log("msg", "collector error", "err", err)
+			}
+
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+				// continue loop
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (c *SchemaTable) Stopped() bool {
+	return !c.running.Load()
+}
+
+// Stop should be kept idempotent
+func (c *SchemaTable) Stop() {
+	c.cancel()
+}
+
+func (c *SchemaTable) extractSchema(ctx context.Context) error {
+	rs, err := c.dbConnection.QueryContext(ctx, selectSchemaName)
+	if err != nil {
+		level.Error(c.logger).Log("msg", "failed to query schemata", "err", err)
+		return err
+	}
+	defer rs.Close()
+
+	var schemas []string
+	for rs.Next() {
+		var schema string
+		if err := rs.Scan(&schema); err != nil {
+			level.Error(c.logger).Log("msg", "failed to scan schemata", "err", err)
+			break
+		}
+		schemas = append(schemas, schema)
+
+		c.entryHandler.Chan() <- database_observability.BuildLokiEntry(
+			OP_SCHEMA_DETECTION,
+			c.instanceKey,
+			fmt.Sprintf(`schema="%s"`, schema),
+		)
+	}
+
+	if err := rs.Err(); err != nil {
+		level.Error(c.logger).Log("msg", "error during iterating over schemas result set", "err", err)
+		return err
+	}
+
+	if len(schemas) == 0 {
+		level.Info(c.logger).Log("msg", "no schema detected from pg_namespace")
+		return nil
+	}
+
+	tables := []*tableInfo{}
+
+	for _, schema := range schemas {
+		schemaTables, err := c.fetchTables(ctx, schema)
+		if err != nil {
+			return err
+		}
+		tables = append(tables, schemaTables...)
+	}
+
+	if len(tables) == 0 {
+		level.Info(c.logger).Log("msg", "no tables detected from pg_class")
+		return nil
+	}
+
+	for _, table := range tables {
+		fullyQualifiedTable := pq.QuoteIdentifier(table.schema) + "." + pq.QuoteIdentifier(table.tableName)
+		cacheKey := fmt.Sprintf("%s@%s", fullyQualifiedTable, table.xmin)
+
+		cacheHit := false
+		if c.cache != nil {
+			if cached, ok := c.cache.Get(cacheKey); ok {
+				table = cached
+				cacheHit = true
+			}
+		}
+
+		if !cacheHit {
+			table, err = c.fetchTableDefinitions(ctx, fullyQualifiedTable, table)
+			if err != nil {
+				level.Error(c.logger).Log("msg", "failed to get table definitions", "schema", table.schema, "table", table.tableName, "err", err)
+				continue
+			}
+			if c.cache != nil {
+				c.cache.Add(cacheKey, table)
+			}
+		}
+
+		c.entryHandler.Chan() <- database_observability.BuildLokiEntry(
+			OP_CREATE_STATEMENT,
+			c.instanceKey,
+			fmt.Sprintf(
+				`schema="%s" table="%s" create_statement="%s" table_spec="%s"`,
+				table.schema, table.tableName, table.b64CreateStmt, table.b64TableSpec,
+			),
+		)
+	}
+
+	return nil
+}
+
+func (c *SchemaTable) fetchTables(ctx context.Context, schema string) ([]*tableInfo, error) {
+	rs, err := c.dbConnection.QueryContext(ctx, selectTableName, schema)
+	if err != nil {
+		level.Error(c.logger).Log("msg", "failed to query tables", "err", err)
+		return nil, err
+	}
+	defer rs.Close()
+
+	var tables []*tableInfo
+	for rs.Next() {
+		var tableName, relKind, xmin string
+		if err := rs.Scan(&tableName, &relKind, &xmin); err != nil {
+			level.Error(c.logger).Log("msg", "failed to scan tables", "err", err)
+			break
+		}
+		tables = append(tables, &tableInfo{
+			schema:    schema,
+			tableName: tableName,
+			tableType: tableTypes[relKind],
+			xmin:      xmin,
+		})
+
+		c.entryHandler.Chan() <- database_observability.BuildLokiEntry(
+			OP_TABLE_DETECTION,
+			c.instanceKey,
+			fmt.Sprintf(`schema="%s" table="%s"`, schema, tableName),
+		)
+	}
+
+	if err := rs.Err(); err != nil {
+		level.Error(c.logger).Log("msg", "error during iterating over tables result set", "err", err)
+		return nil, err
+	}
+	return tables, nil
+}
+
+// fetchTableDefinitions builds the create statement of a table, as
+// PostgreSQL doesn't store it. The create statements of tables only declare
+// their columns and primary key.
+func (c *SchemaTable) fetchTableDefinitions(ctx context.Context, fullyQualifiedTable string, table *tableInfo) (*tableInfo, error) {
+	spec, err := c.fetchColumnsDefinitions(ctx, fullyQualifiedTable, table.schema, table.tableName)
+	if err != nil {
+		level.Error(c.logger).Log("msg", "failed to analyze table spec", "schema", table.schema, "table", table.tableName, "err", err)
+		return table, err
+	}
+
+	var createStmt string
+	switch table.tableType {
+	case "BASE TABLE", "FOREIGN TABLE":
+		createStmt = createTableStatement(fullyQualifiedTable, table.tableType, spec)
+	case "VIEW", "MATERIALIZED VIEW":
+		var definition string
+		if err := c.dbConnection.QueryRowContext(ctx, selectViewDefinition, fullyQualifiedTable).Scan(&definition); err != nil {
+			level.Error(c.logger).Log("msg", "failed to scan view definition", "schema", table.schema, "table", table.tableName, "err", err)
+			return table, err
+		}
+		createStmt = fmt.Sprintf("CREATE %s %s AS\n%s", table.tableType, fullyQualifiedTable, definition)
+	default:
+		level.Error(c.logger).Log("msg", "unknown table type", "schema", table.schema, "table", table.tableName, "table_type", table.tableType)
+		return nil, fmt.Errorf("unknown table type: %s", table.tableType)
+	}
+	table.b64CreateStmt = base64.StdEncoding.EncodeToString([]byte(createStmt))
+
+	jsonSpec, err := json.Marshal(spec)
+	if err != nil {
+		level.Error(c.logger).Log("msg", "failed to marshal table spec", "schema", table.schema, "table", table.tableName, "err", err)
+		return table, err
+	}
+	table.b64TableSpec = base64.StdEncoding.EncodeToString(jsonSpec)
+
+	return table, nil
+}
+
+// createTableStatement builds the create statement of a table from its spec.
+func createTableStatement(fullyQualifiedTable string, tableType string, spec *tableSpec) string {
+	var definitions, primaryKey []string
+	for _, col := range spec.Columns {
+		def := pq.QuoteIdentifier(col.Name) + " " + col.Type
+		if col.DefaultValue != "" {
+			def += " DEFAULT " + col.DefaultValue
+		}
+		if col.NotNull {
+			def += " NOT NULL"
+		}
+		definitions = append(definitions, def)
+		if col.PrimaryKey {
+			primaryKey = append(primaryKey, pq.QuoteIdentifier(col.Name))
+		}
+	}
+	if len(primaryKey) > 0 {
+		definitions = append(definitions, "PRIMARY KEY ("+strings.Join(primaryKey, ", ")+")")
+	}
+
+	keyword := "TABLE"
+	if tableType == "FOREIGN TABLE" {
+		keyword = "FOREIGN TABLE"
+	}
+	return fmt.Sprintf("CREATE %s %s (\n  %s\n)", keyword, fullyQualifiedTable, strings.Join(definitions, ",\n  "))
+}
+
+func (c *SchemaTable) fetchColumnsDefinitions(ctx context.Context, fullyQualifiedTable string, schemaName string, tableName string) (*tableSpec, error) {
+	colRS, err := c.dbConnection.QueryContext(ctx, selectColumnNames, fullyQualifiedTable)
+	if err != nil {
+		level.Error(c.logger).Log("msg", "failed to query table columns", "schema", schemaName, "table", tableName, "err", err)
+		return nil, err
+	}
+	defer colRS.Close()
+
+	tblSpec := &tableSpec{Columns: []columnSpec{}}
+
+	for colRS.Next() {
+		var columnName, columnType string
+		var notNull, identity, primaryKey bool
+		var columnDefault sql.NullString
+		if err := colRS.Scan(&columnName, &columnType, &notNull, &columnDefault, &identity, &primaryKey); err != nil {
+			level.Error(c.logger).Log("msg", "failed to scan table columns", "schema", schemaName, "table", tableName, "err", err)
+			return nil, err
+		}
+
+		colSpec := columnSpec{
+			Name:    columnName,
+			Type:    columnType,
+			NotNull: notNull,
+			// Serial columns are backed by a sequence, identity columns aren't.
+			AutoIncrement: identity || strings.HasPrefix(columnDefault.String, "nextval("),
+			PrimaryKey:    primaryKey,
+			DefaultValue:  columnDefault.String,
+		}
+		tblSpec.Columns = append(tblSpec.Columns, colSpec)
+	}
+
+	if err := colRS.Err(); err != nil {
+		level.Error(c.logger).Log("msg", "error during iterating over table columns result set", "schema", schemaName, "table", tableName, "err", err)
+		return nil, err
+	}
+
+	idxRS, err := c.dbConnection.QueryContext(ctx, selectIndexNames, fullyQualifiedTable)
+	if err != nil {
+		level.Error(c.logger).Log("msg", "failed to query table indexes", "schema", schemaName, "table", tableName, "err", err)
+		return nil, err
+	}
+	defer idxRS.Close()
+
+	for idxRS.Next() {
+		var indexName, indexType string
+		var seqInIndex int
+		var unique bool
+		var columnName, expression sql.NullString
+		var nullable sql.NullBool
+		if err := idxRS.Scan(&indexName, &seqInIndex, &columnName, &expression, &nullable, &unique, &indexType); err != nil {
+			level.Error(c.logger).Log("msg", "failed to scan table indexes", "schema", schemaName, "table", tableName, "err", err)
+			return nil, err
+		}
+
+		if !columnName.Valid && !expression.Valid {
+			level.Error(c.logger).Log("msg", "index without a column or expression", "schema", schemaName, "table", tableName, "index", indexName)
+			continue
+		}
+
+		// Append column to the last index if it's the same as the previous one (i.e. multi-column index)
+		if nIndexes := len(tblSpec.Indexes); nIndexes > 0 && tblSpec.Indexes[nIndexes-1].Name == indexName {
+			lastIndex := &tblSpec.Indexes[nIndexes-1]
+			if len(lastIndex.Columns)+len(lastIndex.Expressions) != seqInIndex-1 {
+				level.Error(c.logger).Log("msg", "unexpected index ordinal position", "schema", schemaName, "table", tableName, "index", indexName, "seq", seqInIndex, "len_columns", len(lastIndex.Columns), "len_expressions", len(lastIndex.Expressions))
+				continue
+			}
+
+			if columnName.Valid {
+				lastIndex.Columns = append(lastIndex.Columns, columnName.String)
+			} else if expression.Valid {
+				lastIndex.Expressions = append(lastIndex.Expressions, expression.String)
+			}
+			lastIndex.Nullable = lastIndex.Nullable || nullable.Bool
+		} else {
+			idx := indexSpec{
+				Name:     indexName,
+				Type:     indexType,
+				Unique:   unique,
+				Nullable: nullable.Bool,
+			}
+
+			if columnName.Valid {
+				idx.Columns = append(idx.Columns, columnName.String)
+			} else if expression.Valid {
+				idx.Expressions = append(idx.Expressions, expression.String)
+			}
+			tblSpec.Indexes = append(tblSpec.Indexes, idx)
+		}
+	}
+
+	if err := idxRS.Err(); err != nil {
+		level.Error(c.logger).Log("msg", "error during iterating over table indexes result set", "schema", schemaName, "table", tableName, "err", err)
+		return nil, err
+	}
+
+	fkRS, err := c.dbConnection.QueryContext(ctx, selectForeignKeys, fullyQualifiedTable)
+	if err != nil {
+		level.Error(c.logger).Log("msg", "failed to query table foreign keys", "schema", schemaName, "table", tableName, "err", err)
+		return nil, err
+	}
+	defer fkRS.Close()
+
+	for fkRS.Next() {
+		var constraintName, columnName, referencedTableName, referencedColumnName string
+		if err := fkRS.Scan(&constraintName, &columnName, &referencedTableName, &referencedColumnName); err != nil {
+			level.Error(c.logger).Log("msg", "failed to scan foreign keys", "schema", schemaName, "table", tableName, "err", err)
+			return nil, err
+		}
+
+		tblSpec.ForeignKeys = append(tblSpec.ForeignKeys, foreignKey{
+			Name:                 constraintName,
+			ColumnName:           columnName,
+			ReferencedTableName:  referencedTableName,
+			ReferencedColumnName: referencedColumnName,
+		})
+	}
+
+	if err := fkRS.Err(); err != nil {
+		level.Error(c.logger).Log("msg", "error during iterating over foreign keys result set", "schema", schemaName, "table", tableName, "err", err)
+		return nil, err
+	}
+
+	return tblSpec, nil
+}
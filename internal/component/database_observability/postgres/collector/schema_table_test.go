@@ -0,0 +1,293 @@
+package collector
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	loki_fake "github.com/grafana/alloy/internal/component/common/loki/client/fake"
+	"github.com/grafana/alloy/internal/component/database_observability"
+)
+
+func TestSchemaTable(t *testing.T) {
+	// The goroutine which deletes expired entries runs indefinitely,
+	// see https://github.com/hashicorp/golang-lru/blob/v2.0.7/expirable/expirable_lru.go#L79-L80
+	defer goleak.VerifyNone(t, goleak.IgnoreTopFunction("github.com/hashicorp/golang-lru/v2/expirable.NewLRU[...].func1"))
+
+	t.Run("detect table and view schema", func(t *testing.T) {
+		t.Parallel()
+
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.NoError(t, err)
+		defer db.Close()
+
+		lokiClient := loki_fake.NewClient(func() {})
+
+		collector, err := NewSchemaTable(SchemaTableArguments{
+			DB:              db,
+			InstanceKey:     "postgres-db",
+			CollectInterval: time.Minute,
+			EntryHandler:    lokiClient,
+			CacheEnabled:    false,
+			Logger:          log.NewLogfmtLogger(os.Stderr),
+		})
+		require.NoError(t, err)
+		require.NotNil(t, collector)
+
+		mock.ExpectQuery(selectSchemaName).WithoutArgs().RowsWillBeClosed().
+			WillReturnRows(
+				sqlmock.NewRows([]string{
+					"nspname",
+				}).AddRow(
+					"public",
+				),
+			)
+
+		mock.ExpectQuery(selectTableName).WithArgs("public").RowsWillBeClosed().
+			WillReturnRows(
+				sqlmock.NewRows([]string{
+					"relname",
+					"relkind",
+					"xmin",
+				}).AddRow(
+					"users",
+					"r",
+					"731",
+				).AddRow(
+					"active_users",
+					"v",
+					"732",
+				),
+			)
+
+		mock.ExpectQuery(selectColumnNames).WithArgs(`"public"."users"`).RowsWillBeClosed().
+			WillReturnRows(
+				sqlmock.NewRows([]string{
+					"attname",
+					"format_type",
+					"attnotnull",
+					"pg_get_expr",
+					"identity",
+					"primary_key",
+				}).AddRow(
+					"id",
+					"integer",
+					true,
+					"nextval('users_id_seq'::regclass)",
+					false,
+					true,
+				).AddRow(
+					"team_id",
+					"integer",
+					false,
+					nil,
+					false,
+					false,
+				).AddRow(
+					"email",
+					"character varying(255)",
+					true,
+					nil,
+					false,
+					false,
+				),
+			)
+
+		mock.ExpectQuery(selectIndexNames).WithArgs(`"public"."users"`).RowsWillBeClosed().
+			WillReturnRows(
+				sqlmock.NewRows([]string{
+					"relname",
+					"ord",
+					"attname",
+					"expression",
+					"nullable",
+					"indisunique",
+					"amname",
+				}).AddRow(
+					"users_pkey",
+					1,
+					"id",
+					nil,
+					false,
+					true,
+					"btree",
+				).AddRow(
+					"users_team_email_idx",
+					1,
+					"team_id",
+					nil,
+					true,
+					false,
+					"btree",
+				).AddRow(
+					"users_team_email_idx",
+					2,
+					nil,
+					"lower(email::text)",
+					nil,
+					false,
+					"btree",
+				),
+			)
+
+		mock.ExpectQuery(selectForeignKeys).WithArgs(`"public"."users"`).RowsWillBeClosed().
+			WillReturnRows(
+				sqlmock.NewRows([]string{
+					"conname",
+					"attname",
+					"relname",
+					"attname",
+				}).AddRow(
+					"users_team_id_fkey",
+					"team_id",
+					"teams",
+					"id",
+				),
+			)
+
+		mock.ExpectQuery(selectColumnNames).WithArgs(`"public"."active_users"`).RowsWillBeClosed().
+			WillReturnRows(
+				sqlmock.NewRows([]string{
+					"attname",
+					"format_type",
+					"attnotnull",
+					"pg_get_expr",
+					"identity",
+					"primary_key",
+				}).AddRow(
+					"id",
+					"integer",
+					false,
+					nil,
+					false,
+					false,
+				),
+			)
+
+		mock.ExpectQuery(selectIndexNames).WithArgs(`"public"."active_users"`).RowsWillBeClosed().
+			WillReturnRows(
+				sqlmock.NewRows([]string{
+					"relname",
+					"ord",
+					"attname",
+					"expression",
+					"nullable",
+					"indisunique",
+					"amname",
+				}),
+			)
+
+		mock.ExpectQuery(selectForeignKeys).WithArgs(`"public"."active_users"`).RowsWillBeClosed().
+			WillReturnRows(
+				sqlmock.NewRows([]string{
+					"conname",
+					"attname",
+					"relname",
+					"attname",
+				}),
+			)
+
+		mock.ExpectQuery(selectViewDefinition).WithArgs(`"public"."active_users"`).RowsWillBeClosed().
+			WillReturnRows(
+				sqlmock.NewRows([]string{
+					"pg_get_viewdef",
+				}).AddRow(
+					" SELECT id\n   FROM users;",
+				),
+			)
+
+		err = collector.Start(t.Context())
+		require.NoError(t, err)
+
+		require.Eventually(t, func() bool {
+			return len(lokiClient.Received()) == 5
+		}, 5*time.Second, 100*time.Millisecond)
+
+		collector.Stop()
+		lokiClient.Stop()
+
+		require.Eventually(t, func() bool {
+			return collector.Stopped()
+		}, 5*time.Second, 100*time.Millisecond)
+
+		err = mock.ExpectationsWereMet()
+		require.NoError(t, err)
+
+		expectedTableCreateStmt := base64.StdEncoding.EncodeToString([]byte("CREATE TABLE \"public\".\"users\" (\n  \"id\" integer DEFAULT nextval('users_id_seq'::regclass) NOT NULL,\n  \"team_id\" integer,\n  \"email\" character varying(255) NOT NULL,\n  PRIMARY KEY (\"id\")\n)"))
+		expectedTableSpec := base64.StdEncoding.EncodeToString([]byte(`{"columns":[{"name":"id","type":"integer","not_null":true,"auto_increment":true,"primary_key":true,"default_value":"nextval('users_id_seq'::regclass)"},{"name":"team_id","type":"integer"},{"name":"email","type":"character varying(255)","not_null":true}],"indexes":[{"name":"users_pkey","type":"btree","columns":["id"],"unique":true,"nullable":false},{"name":"users_team_email_idx","type":"btree","columns":["team_id"],"expressions":["lower(email::text)"],"unique":false,"nullable":true}],"foreign_keys":[{"name":"users_team_id_fkey","column_name":"team_id","referenced_table_name":"teams","referenced_column_name":"id"}]}`))
+		expectedViewCreateStmt := base64.StdEncoding.EncodeToString([]byte("CREATE VIEW \"public\".\"active_users\" AS\n SELECT id\n   FROM users;"))
+		expectedViewSpec := base64.StdEncoding.EncodeToString([]byte(`{"columns":[{"name":"id","type":"integer"}]}`))
+
+		lokiEntries := lokiClient.Received()
+		require.Equal(t, model.LabelSet{"job": database_observability.JobName, "op": OP_SCHEMA_DETECTION, "instance": "postgres-db"}, lokiEntries[0].Labels)
+		require.Equal(t, `schema="public"`, lokiEntries[0].Line)
+		require.Equal(t, model.LabelSet{"job": database_observability.JobName, "op": OP_TABLE_DETECTION, "instance": "postgres-db"}, lokiEntries[1].Labels)
+		require.Equal(t, `schema="public" table="users"`, lokiEntries[1].Line)
+		require.Equal(t, model.LabelSet{"job": database_observability.JobName, "op": OP_TABLE_DETECTION, "instance": "postgres-db"}, lokiEntries[2].Labels)
+		require.Equal(t, `schema="public" table="active_users"`, lokiEntries[2].Line)
+		require.Equal(t, model.LabelSet{"job": database_observability.JobName, "op": OP_CREATE_STATEMENT, "instance": "postgres-db"}, lokiEntries[3].Labels)
+		require.Equal(t, fmt.Sprintf(`schema="public" table="users" create_statement="%s" table_spec="%s"`, expectedTableCreateStmt, expectedTableSpec), lokiEntries[3].Line)
+		require.Equal(t, model.LabelSet{"job": database_observability.JobName, "op": OP_CREATE_STATEMENT, "instance": "postgres-db"}, lokiEntries[4].Labels)
+		require.Equal(t, fmt.Sprintf(`schema="public" table="active_users" create_statement="%s" table_spec="%s"`, expectedViewCreateStmt, expectedViewSpec), lokiEntries[4].Line)
+	})
+
+	t.Run("cached table definitions are reused until the table is altered", func(t *testing.T) {
+		t.Parallel()
+
+		db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+		require.NoError(t, err)
+		defer db.Close()
+
+		lokiClient := loki_fake.NewClient(func() {})
+
+		collector, err := NewSchemaTable(SchemaTableArguments{
+			DB:              db,
+			InstanceKey:     "postgres-db",
+			CollectInterval: time.Minute,
+			EntryHandler:    lokiClient,
+			CacheEnabled:    true,
+			CacheSize:       256,
+			CacheTTL:        10 * time.Minute,
+			Logger:          log.NewLogfmtLogger(os.Stderr),
+		})
+		require.NoError(t, err)
+
+		expectTable := func(xmin string, withDefinitions bool) {
+			mock.ExpectQuery(selectSchemaName).WithoutArgs().RowsWillBeClosed().
+				WillReturnRows(sqlmock.NewRows([]string{"nspname"}).AddRow("public"))
+			mock.ExpectQuery(selectTableName).WithArgs("public").RowsWillBeClosed().
+				WillReturnRows(sqlmock.NewRows([]string{"relname", "relkind", "xmin"}).AddRow("users", "r", xmin))
+			if !withDefinitions {
+				return
+			}
+			mock.ExpectQuery(selectColumnNames).WithArgs(`"public"."users"`).RowsWillBeClosed().
+				WillReturnRows(sqlmock.NewRows([]string{"attname", "format_type", "attnotnull", "pg_get_expr", "identity", "primary_key"}).
+					AddRow("id", "integer", true, nil, true, true))
+			mock.ExpectQuery(selectIndexNames).WithArgs(`"public"."users"`).RowsWillBeClosed().
+				WillReturnRows(sqlmock.NewRows([]string{"relname", "ord", "attname", "expression", "nullable", "indisunique", "amname"}))
+			mock.ExpectQuery(selectForeignKeys).WithArgs(`"public"."users"`).RowsWillBeClosed().
+				WillReturnRows(sqlmock.NewRows([]string{"conname", "attname", "relname", "attname"}))
+		}
+
+		expectTable("731", true)
+		require.NoError(t, collector.extractSchema(t.Context()))
+		expectTable("731", false)
+		require.NoError(t, collector.extractSchema(t.Context()))
+		expectTable("745", true)
+		require.NoError(t, collector.extractSchema(t.Context()))
+
+		require.NoError(t, mock.ExpectationsWereMet())
+		require.Eventually(t, func() bool {
+			return len(lokiClient.Received()) == 9
+		}, 5*time.Second, 100*time.Millisecond)
+		lokiClient.Stop()
+	})
+}
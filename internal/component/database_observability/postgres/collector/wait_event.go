@@ -0,0 +1,156 @@
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/go-kit/log"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/database_observability"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+const (
+	OP_WAIT_EVENT = "wait_event"
+	WaitEventName = "wait_event"
+)
+
+const selectWaitEvents = `
+SELECT
+	datname,
+	pid,
+	backend_type,
+	state,
+	wait_event_type,
+	wait_event,
+	query_id
+FROM pg_stat_activity
+WHERE wait_event IS NOT NULL
+	AND state = 'active'
+	AND pid != pg_backend_pid()
+	AND datname IS NOT NULL`
+
+type WaitEventArguments struct {
+	DB              *sql.DB
+	InstanceKey     string
+	CollectInterval time.Duration
+	EntryHandler    loki.EntryHandler
+
+	Logger log.Logger
+}
+
+// WaitEvent samples the events which the active sessions are waiting on.
+type WaitEvent struct {
+	dbConnection    *sql.DB
+	instanceKey     string
+	collectInterval time.Duration
+	entryHandler    loki.EntryHandler
+
+	logger  log.Logger
+	running *atomic.Bool
+	ctx     context.Context
+	cancel  context.CancelFunc
+}
+
+func NewWaitEvent(args WaitEventArguments) (*WaitEvent, error) {
+	return &WaitEvent{
+		dbConnection:    args.DB,
+		instanceKey:     args.InstanceKey,
+		collectInterval: args.CollectInterval,
+		entryHandler:    args.EntryHandler,
+		logger:          log.With(args.Logger, "collector", WaitEventName),
+		running:         &atomic.Bool{},
+	}, nil
+}
+
+func (c *WaitEvent) Name() string {
+	return WaitEventName
+}
+
+func (c *WaitEvent) Start(ctx context.Context) error {
+	level.Debug(c.logger).Log("msg", WaitEventName+" collector started")
+
+	c.running.Store(true)
+	ctx, cancel := context.WithCancel(ctx)
+	c.ctx = ctx
+	c.cancel = cancel
+
+	go func() {
+		defer func() {
+			c.Stop()
+			c.running.Store(false)
+		}()
+
+		ticker := time.NewTicker(c.collectInterval)
+
+		for {
+			if err := c.fetchWaitEvents(c.ctx); err != nil {
+				level.Error(c.logger).Log("msg", "collector error", "err", err)
+			}
+
+			select {
+			case <-c.ctx.Done():
+				return
+			case <-ticker.C:
+				// continue loop
+			}
+		}
+	}()
+
+	return nil
+}
+
+func (c *WaitEvent) Stopped() bool {
+	return !c.running.Load()
+}
+
+// Stop should be kept idempotent
+func (c *WaitEvent) Stop() {
+	c.cancel()
+}
+
+func (c *WaitEvent) fetchWaitEvents(ctx context.Context) error {
+	rs, err := c.dbConnection.QueryContext(ctx, selectWaitEvents)
+	if err != nil {
+		level.Error(c.logger).Log("msg", "failed to fetch wait events", "err", err)
+		return err
+	}
+	defer rs.Close()
+
+	for rs.Next() {
+		var (
+			database, backendType, state, waitEventType, waitEvent string
+			pid                                                    int64
+			queryID                                                sql.NullInt64
+		)
+		if err := rs.Scan(&database, &pid, &backendType, &state, &waitEventType, &waitEvent, &queryID); err != nil {
+			level.Error(c.logger).Log("msg", "failed to scan wait events", "err", err)
+			continue
+		}
+
+		var digest string
+		if queryID.Valid {
+			digest = fmt.Sprintf("%d", queryID.Int64)
+		}
+
+		c.entryHandler.Chan() <- database_observability.BuildLokiEntry(
+			OP_WAIT_EVENT,
+			c.instanceKey,
+			fmt.Sprintf(
+				`schema="%s" pid="%d" digest="%s" backend_type="%s" state="%s" wait_event_type="%s" wait_event="%s" wait_event_name="%s:%s"`,
+				database, pid, digest, backendType, state, waitEventType, waitEvent, waitEventType, waitEvent,
+			),
+		)
+	}
+
+	if err := rs.Err(); err != nil {
+		level.Error(c.logger).Log("msg", "error during iterating over wait events result set", "err", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package collector
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+
+	loki_fake "github.com/grafana/alloy/internal/component/common/loki/client/fake"
+	"github.com/grafana/alloy/internal/component/database_observability"
+)
+
+func TestWaitEvent(t *testing.T) {
+	// The expirable LRU of TestSchemaTable, which runs beforehand, leaves its
+	// goroutine running.
+	defer goleak.VerifyNone(t, goleak.IgnoreTopFunction("github.com/hashicorp/golang-lru/v2/expirable.NewLRU[...].func1"))
+
+	db, mock, err := sqlmock.New(sqlmock.QueryMatcherOption(sqlmock.QueryMatcherEqual))
+	require.NoError(t, err)
+	defer db.Close()
+
+	lokiClient := loki_fake.NewClient(func() {})
+
+	collector, err := NewWaitEvent(WaitEventArguments{
+		DB:              db,
+		InstanceKey:     "postgres-db",
+		CollectInterval: time.Minute,
+		EntryHandler:    lokiClient,
+		Logger:          log.NewLogfmtLogger(os.Stderr),
+	})
+	require.NoError(t, err)
+	require.NotNil(t, collector)
+
+	mock.ExpectQuery(selectWaitEvents).WithoutArgs().RowsWillBeClosed().
+		WillReturnRows(
+			sqlmock.NewRows([]string{
+				"datname",
+				"pid",
+				"backend_type",
+				"state",
+				"wait_event_type",
+				"wait_event",
+				"query_id",
+			}).AddRow(
+				"some_db", int64(101), "client backend", "active", "Lock", "relation", int64(42),
+			).AddRow(
+				"some_db", int64(102), "client backend", "active", "IO", "DataFileRead", nil,
+			),
+		)
+
+	err = collector.Start(t.Context())
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool {
+		return len(lokiClient.Received()) == 2
+	}, 5*time.Second, 100*time.Millisecond)
+
+	collector.Stop()
+	lokiClient.Stop()
+
+	require.Eventually(t, func() bool {
+		return collector.Stopped()
+	}, 5*time.Second, 100*time.Millisecond)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+
+	lokiEntries := lokiClient.Received()
+	for _, entry := range lokiEntries {
+		require.Equal(t, model.LabelSet{"job": database_observability.JobName, "op": OP_WAIT_EVENT, "instance": "postgres-db"}, entry.Labels)
+	}
+	require.Equal(t, `schema="some_db" pid="101" digest="42" backend_type="client backend" state="active" wait_event_type="Lock" wait_event="relation" wait_event_name="Lock:relation"`, lokiEntries[0].Line)
+	require.Equal(t, `schema="some_db" pid="102" digest="" backend_type="client backend" state="active" wait_event_type="IO" wait_event="DataFileRead" wait_event_name="IO:DataFileRead"`, lokiEntries[1].Line)
+}
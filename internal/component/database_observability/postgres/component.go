@@ -0,0 +1,383 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/prometheus/common/model"
+	"go.uber.org/atomic"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/database_observability"
+	"github.com/grafana/alloy/internal/component/database_observability/postgres/collector"
+	"github.com/grafana/alloy/internal/component/discovery"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	http_service "github.com/grafana/alloy/internal/service/http"
+	"github.com/grafana/alloy/syntax"
+	"github.com/grafana/alloy/syntax/alloytypes"
+)
+
+const name = "database_observability.postgres"
+
+func init() {
+	component.Register(component.Registration{
+		Name:      name,
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   Exports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+var (
+	_ syntax.Defaulter = (*Arguments)(nil)
+	_ syntax.Validator = (*Arguments)(nil)
+)
+
+type Arguments struct {
+	DataSourceName    alloytypes.Secret   `alloy:"data_source_name,attr"`
+	CollectInterval   time.Duration       `alloy:"collect_interval,attr,optional"`
+	ForwardTo         []loki.LogsReceiver `alloy:"forward_to,attr"`
+	EnableCollectors  []string            `alloy:"enable_collectors,attr,optional"`
+	DisableCollectors []string            `alloy:"disable_collectors,attr,optional"`
+
+	DisableQueryRedaction bool `alloy:"disable_query_redaction,attr,optional"`
+}
+
+var DefaultArguments = Arguments{
+	CollectInterval: 1 * time.Minute,
+}
+
+func (a *Arguments) SetToDefault() {
+	*a = DefaultArguments
+}
+
+func (a *Arguments) Validate() error {
+	_, err := pq.ParseURL(string(a.DataSourceName))
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+type Exports struct {
+	Targets []discovery.Target `alloy:"targets,attr"`
+}
+
+var (
+	_ component.Component       = (*Component)(nil)
+	_ http_service.Component    = (*Component)(nil)
+	_ component.HealthComponent = (*Component)(nil)
+)
+
+type Collector interface {
+	Name() string
+	Start(context.Context) error
+	Stopped() bool
+	Stop()
+}
+
+type Component struct {
+	opts         component.Options
+	args         Arguments
+	mut          sync.RWMutex
+	receivers    []loki.LogsReceiver
+	handler      loki.LogsReceiver
+	registry     *prometheus.Registry
+	baseTarget   discovery.Target
+	collectors   []Collector
+	instanceKey  string
+	dbConnection *sql.DB
+	healthErr    *atomic.String
+}
+
+func New(opts component.Options, args Arguments) (*Component, error) {
+	c := &Component{
+		opts:      opts,
+		args:      args,
+		receivers: args.ForwardTo,
+		handler:   loki.NewLogsReceiver(),
+		registry:  prometheus.NewRegistry(),
+		healthErr: atomic.NewString(""),
+	}
+
+	instance, err := instanceKey(string(args.DataSourceName))
+	if err != nil {
+		return nil, err
+	}
+	c.instanceKey = instance
+
+	baseTarget, err := c.getBaseTarget()
+	if err != nil {
+		return nil, err
+	}
+	c.baseTarget = baseTarget
+
+	if err := c.Update(args); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+func (c *Component) Run(ctx context.Context) error {
+	defer func() {
+		level.Info(c.opts.Logger).Log("msg", name+" component shutting down, stopping collectors")
+		c.mut.RLock()
+		for _, collector := range c.collectors {
+			collector.Stop()
+		}
+		if c.dbConnection != nil {
+			c.dbConnection.Close()
+		}
+		c.mut.RUnlock()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case entry := <-c.handler.Chan():
+			c.mut.RLock()
+			for _, receiver := range c.receivers {
+				receiver.Chan() <- entry
+			}
+			c.mut.RUnlock()
+		}
+	}
+}
+
+func (c *Component) getBaseTarget() (discovery.Target, error) {
+	data, err := c.opts.GetServiceData(http_service.ServiceName)
+	if err != nil {
+		return discovery.EmptyTarget, fmt.Errorf("failed to get HTTP information: %w", err)
+	}
+	httpData := data.(http_service.Data)
+
+	return discovery.NewTargetFromMap(map[string]string{
+		model.AddressLabel:     httpData.MemoryListenAddr,
+		model.SchemeLabel:      "http",
+		model.MetricsPathLabel: path.Join(httpData.HTTPPathForComponent(c.opts.ID), "metrics"),
+		"instance":             c.instanceKey,
+		"job":                  database_observability.JobName,
+	}), nil
+}
+
+func (c *Component) Update(args component.Arguments) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	c.opts.OnStateChange(Exports{
+		Targets: []discovery.Target{c.baseTarget},
+	})
+
+	for _, collector := range c.collectors {
+		collector.Stop()
+	}
+	c.collectors = nil
+
+	if c.dbConnection != nil {
+		c.dbConnection.Close()
+	}
+
+	c.args = args.(Arguments)
+	c.receivers = c.args.ForwardTo
+
+	if err := c.startCollectors(); err != nil {
+		c.healthErr.Store(err.Error())
+		return err
+	}
+
+	c.healthErr.Store("")
+	return nil
+}
+
+func enableOrDisableCollectors(a Arguments) map[string]bool {
+	// configurable collectors and their default enabled/disabled value
+	collectors := map[string]bool{
+		collector.SchemaTableName: true,
+		collector.QuerySampleName: false,
+		collector.WaitEventName:   false,
+	}
+
+	for _, disabled := range a.DisableCollectors {
+		if _, ok := collectors[disabled]; ok {
+			collectors[disabled] = false
+		}
+	}
+	for _, enabled := range a.EnableCollectors {
+		if _, ok := collectors[enabled]; ok {
+			collectors[enabled] = true
+		}
+	}
+
+	return collectors
+}
+
+func (c *Component) startCollectors() error {
+	dbConnection, err := sql.Open("postgres", string(c.args.DataSourceName))
+	if err != nil {
+		return err
+	}
+
+	if dbConnection == nil {
+		return errors.New("nil DB connection")
+	}
+	if err = dbConnection.Ping(); err != nil {
+		return err
+	}
+	c.dbConnection = dbConnection
+
+	entryHandler := loki.NewEntryHandler(c.handler.Chan(), func() {})
+
+	collectors := enableOrDisableCollectors(c.args)
+
+	if collectors[collector.SchemaTableName] {
+		stCollector, err := collector.NewSchemaTable(collector.SchemaTableArguments{
+			DB:              dbConnection,
+			InstanceKey:     c.instanceKey,
+			CollectInterval: c.args.CollectInterval,
+			EntryHandler:    entryHandler,
+			Logger:          c.opts.Logger,
+
+			CacheEnabled: true,
+			CacheSize:    256,
+			CacheTTL:     10 * time.Minute,
+		})
+		if err != nil {
+			level.Error(c.opts.Logger).Log("msg", "failed to create SchemaTable collector", "err", err)
+			return err
+		}
+		if err := stCollector.Start(context.Background()); err != nil {
+			level.Error(c.opts.Logger).Log("msg", "failed to start SchemaTable collector", "err", err)
+			return err
+		}
+		c.collectors = append(c.collectors, stCollector)
+	}
+
+	if collectors[collector.QuerySampleName] {
+		qsCollector, err := collector.NewQuerySample(collector.QuerySampleArguments{
+			DB:                    dbConnection,
+			InstanceKey:           c.instanceKey,
+			CollectInterval:       c.args.CollectInterval,
+			EntryHandler:          entryHandler,
+			Logger:                c.opts.Logger,
+			DisableQueryRedaction: c.args.DisableQueryRedaction,
+		})
+		if err != nil {
+			level.Error(c.opts.Logger).Log("msg", "failed to create QuerySample collector", "err", err)
+			return err
+		}
+		if err := qsCollector.Start(context.Background()); err != nil {
+			level.Error(c.opts.Logger).Log("msg", "failed to start QuerySample collector", "err", err)
+			return err
+		}
+		c.collectors = append(c.collectors, qsCollector)
+	}
+
+	if collectors[collector.WaitEventName] {
+		weCollector, err := collector.NewWaitEvent(collector.WaitEventArguments{
+			DB:              dbConnection,
+			InstanceKey:     c.instanceKey,
+			CollectInterval: c.args.CollectInterval,
+			EntryHandler:    entryHandler,
+			Logger:          c.opts.Logger,
+		})
+		if err != nil {
+			level.Error(c.opts.Logger).Log("msg", "failed to create WaitEvent collector", "err", err)
+			return err
+		}
+		if err := weCollector.Start(context.Background()); err != nil {
+			level.Error(c.opts.Logger).Log("msg", "failed to start WaitEvent collector", "err", err)
+			return err
+		}
+		c.collectors = append(c.collectors, weCollector)
+	}
+
+	// Connection Info collector is always enabled
+	ciCollector, err := collector.NewConnectionInfo(collector.ConnectionInfoArguments{
+		DSN:      string(c.args.DataSourceName),
+		Registry: c.registry,
+	})
+	if err != nil {
+		level.Error(c.opts.Logger).Log("msg", "failed to create ConnectionInfo collector", "err", err)
+		return err
+	}
+	if err := ciCollector.Start(context.Background()); err != nil {
+		level.Error(c.opts.Logger).Log("msg", "failed to start ConnectionInfo collector", "err", err)
+		return err
+	}
+	c.collectors = append(c.collectors, ciCollector)
+
+	return nil
+}
+
+func (c *Component) Handler() http.Handler {
+	return promhttp.HandlerFor(c.registry, promhttp.HandlerOpts{})
+}
+
+func (c *Component) CurrentHealth() component.Health {
+	if err := c.healthErr.Load(); err != "" {
+		return component.Health{
+			Health:     component.HealthTypeUnhealthy,
+			Message:    err,
+			UpdateTime: time.Now(),
+		}
+	}
+
+	var unhealthyCollectors []string
+
+	c.mut.RLock()
+	for _, collector := range c.collectors {
+		if collector.Stopped() {
+			unhealthyCollectors = append(unhealthyCollectors, collector.Name())
+		}
+	}
+	c.mut.RUnlock()
+
+	if len(unhealthyCollectors) > 0 {
+		return component.Health{
+			Health:     component.HealthTypeUnhealthy,
+			Message:    "One or more collectors are unhealthy: [" + strings.Join(unhealthyCollectors, ", ") + "]",
+			UpdateTime: time.Now(),
+		}
+	}
+
+	return component.Health{
+		Health:     component.HealthTypeHealthy,
+		Message:    "All collectors are healthy",
+		UpdateTime: time.Now(),
+	}
+}
+
+// instanceKey returns postgresql://host:port/dbname of the PostgreSQL server.
+// This is the same key as used by the postgres_exporter integration.
+func instanceKey(dsn string) (string, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "", err
+	}
+
+	hostport := u.Host
+	if hostport == "" {
+		hostport = "localhost:5432"
+	}
+
+	return fmt.Sprintf("postgresql://%s/%s", hostport, strings.TrimPrefix(u.Path, "/")), nil
+}
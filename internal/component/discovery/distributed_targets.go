@@ -1,6 +1,8 @@
 package discovery
 
 import (
+	"slices"
+
 	"github.com/grafana/ckit/peer"
 	"github.com/grafana/ckit/shard"
 
@@ -61,10 +63,13 @@ func NewDistributedTargetsWithCustomLabels(clusteringEnabled bool, cluster clust
 		unique[targetKey] = struct{}{}
 
 		// Determine if target belongs locally. Make sure it doesn't if cluster not ready.
+		// The cluster may return more than one owner when it's configured with a
+		// replication factor; the target belongs locally if any of them is the
+		// local node.
 		belongsToLocal := false
 		if cluster.Ready() {
-			peers, err := cluster.Lookup(targetKey, 1, shard.OpReadWrite)
-			belongsToLocal = err != nil || len(peers) == 0 || peers[0].Self
+			peers, err := lookupOwners(cluster, tgt, targetKey)
+			belongsToLocal = err != nil || len(peers) == 0 || slices.ContainsFunc(peers, func(p peer.Peer) bool { return p.Self })
 		}
 
 		if belongsToLocal {
@@ -109,6 +114,23 @@ func (dt *DistributedTargets) MovedToRemoteInstance(prev *DistributedTargets) []
 	return movedAwayTargets
 }
 
+// lookupOwners returns the owners of a target. Targets are assigned to as
+// many peers as the replication factor of the cluster, and targets with a
+// zone are preferably assigned to the peers in the same zone, if the cluster
+// supports them.
+func lookupOwners(c cluster.Cluster, tgt Target, key shard.Key) ([]peer.Peer, error) {
+	numOwners := 1
+	if rc, ok := c.(cluster.ReplicatedCluster); ok {
+		numOwners = rc.ReplicationFactor(shard.OpReadWrite)
+	}
+	if zc, ok := c.(cluster.ZoneCluster); ok {
+		if zone, ok := tgt.Get(cluster.ZoneLabel); ok && zone != "" {
+			return zc.LookupZone(key, zone, numOwners, shard.OpReadWrite)
+		}
+	}
+	return c.Lookup(key, numOwners, shard.OpReadWrite)
+}
+
 func keyFor(tgt Target) shard.Key {
 	return shard.Key(tgt.NonMetaLabelsHash())
 }
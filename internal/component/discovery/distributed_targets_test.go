@@ -95,6 +95,24 @@ var localTargetsTestCases = []struct {
 			target1, target2,
 		},
 	},
+	{
+		name: "targets are assigned to as many peers as the replication factor",
+		cluster: &fakeReplicatedCluster{
+			fakeCluster: fakeCluster{
+				peers: allTestPeers,
+				lookupMap: map[shard.Key][]peer.Peer{
+					keyFor(target1): {peer2, peer1Self, peer3},
+					keyFor(target2): {peer2, peer3, peer1Self},
+					keyFor(target3): {peer1Self, peer3, peer2},
+				},
+			},
+			replicationFactor: 2,
+		},
+		allTargets: allTestTargets,
+		expectedLocalTargets: []Target{
+			target1, target3,
+		},
+	},
 	{
 		name: "lookup errors fall back to local target assignment",
 		cluster: &fakeCluster{
@@ -318,3 +336,18 @@ func (f *fakeCluster) Peers() []peer.Peer {
 func (f *fakeCluster) Ready() bool {
 	return true
 }
+
+// fakeReplicatedCluster returns the first numOwners owners of each key.
+type fakeReplicatedCluster struct {
+	fakeCluster
+	replicationFactor int
+}
+
+func (f *fakeReplicatedCluster) Lookup(key shard.Key, numOwners int, op shard.Op) ([]peer.Peer, error) {
+	owners, err := f.fakeCluster.Lookup(key, numOwners, op)
+	return owners[:min(numOwners, len(owners))], err
+}
+
+func (f *fakeReplicatedCluster) ReplicationFactor(_ shard.Op) int {
+	return f.replicationFactor
+}
@@ -42,6 +42,7 @@ type ServerArguments struct {
 
 	RateLimiting    RateLimitingArguments `alloy:"rate_limiting,block,optional"`
 	IncludeMetadata bool                  `alloy:"include_metadata,attr,optional"`
+	AppKeys         []AppKeyArguments     `alloy:"app_key,block,optional"`
 }
 
 func (s *ServerArguments) SetToDefault() {
@@ -53,6 +54,50 @@ func (s *ServerArguments) SetToDefault() {
 	s.RateLimiting.SetToDefault()
 }
 
+// Validate ensures that the API keys of the applications are unique.
+func (s *ServerArguments) Validate() error {
+	var (
+		apps = make(map[string]struct{}, len(s.AppKeys))
+		keys = make(map[alloytypes.Secret]struct{}, len(s.AppKeys))
+	)
+	for _, appKey := range s.AppKeys {
+		if _, ok := apps[appKey.App]; ok {
+			return fmt.Errorf("app_key for app %q is defined more than once", appKey.App)
+		}
+		apps[appKey.App] = struct{}{}
+
+		if _, ok := keys[appKey.APIKey]; ok || appKey.APIKey == s.APIKey {
+			return fmt.Errorf("api_key of app %q is already in use", appKey.App)
+		}
+		keys[appKey.APIKey] = struct{}{}
+	}
+	return nil
+}
+
+// AppKeyArguments configures an API key which is only valid for the telemetry
+// of a single application, with its own rate limits.
+type AppKeyArguments struct {
+	App    string            `alloy:"app,attr"`
+	APIKey alloytypes.Secret `alloy:"api_key,attr"`
+
+	RateLimiting RateLimitingArguments `alloy:"rate_limiting,block,optional"`
+}
+
+func (a *AppKeyArguments) SetToDefault() {
+	*a = AppKeyArguments{}
+	a.RateLimiting.SetToDefault()
+}
+
+func (a *AppKeyArguments) Validate() error {
+	if a.App == "" {
+		return fmt.Errorf("app must not be empty")
+	}
+	if a.APIKey == "" {
+		return fmt.Errorf("api_key of app %q must not be empty", a.App)
+	}
+	return nil
+}
+
 // RateLimitingArguments configures rate limiting for the HTTP server.
 type RateLimitingArguments struct {
 	Enabled   bool    `alloy:"enabled,attr,optional"`
@@ -75,6 +120,7 @@ type SourceMapsArguments struct {
 	DownloadFromOrigins []string            `alloy:"download_from_origins,attr,optional"`
 	DownloadTimeout     time.Duration       `alloy:"download_timeout,attr,optional"`
 	Locations           []LocationArguments `alloy:"location,block,optional"`
+	Upload              *UploadArguments    `alloy:"upload,block,optional"`
 }
 
 func (s *SourceMapsArguments) SetToDefault() {
@@ -91,6 +137,34 @@ type LocationArguments struct {
 	MinifiedPathPrefix string `alloy:"minified_path_prefix,attr"`
 }
 
+// UploadArguments configures the endpoint where source maps can be uploaded
+// to be stored on the filesystem.
+type UploadArguments struct {
+	Path               string            `alloy:"path,attr"`
+	MinifiedPathPrefix string            `alloy:"minified_path_prefix,attr"`
+	APIKey             alloytypes.Secret `alloy:"api_key,attr"`
+	MaxFileSize        units.Base2Bytes  `alloy:"max_file_size,attr,optional"`
+}
+
+func (u *UploadArguments) SetToDefault() {
+	*u = UploadArguments{
+		MaxFileSize: 50 * units.MiB,
+	}
+}
+
+func (u *UploadArguments) Validate() error {
+	if u.Path == "" {
+		return fmt.Errorf("path must not be empty")
+	}
+	if u.APIKey == "" {
+		return fmt.Errorf("api_key must not be empty")
+	}
+	if u.MaxFileSize <= 0 {
+		return fmt.Errorf("max_file_size must be greater than 0")
+	}
+	return nil
+}
+
 // OutputArguments configures where to send emitted logs and traces. Metrics
 // emitted by app_agent_receiver are exported as targets to be scraped.
 type OutputArguments struct {
@@ -0,0 +1,133 @@
+package receiver
+
+import (
+	"testing"
+
+	"github.com/alecthomas/units"
+	"github.com/grafana/alloy/syntax"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArguments_AppKeysAndUpload(t *testing.T) {
+	cfg := `
+		server {
+			api_key = "globalkey"
+
+			app_key {
+				app     = "frontend"
+				api_key = "frontendkey"
+
+				rate_limiting {
+					rate = 10
+				}
+			}
+		}
+
+		sourcemaps {
+			upload {
+				path                 = "/var/sourcemaps"
+				minified_path_prefix = "http://example.com/"
+				api_key              = "uploadkey"
+			}
+		}
+
+		output {}
+	`
+
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(cfg), &args))
+
+	require.Equal(t, []AppKeyArguments{{
+		App:    "frontend",
+		APIKey: "frontendkey",
+		RateLimiting: RateLimitingArguments{
+			Enabled:   true,
+			Rate:      10,
+			BurstSize: 100,
+		},
+	}}, args.Server.AppKeys)
+	require.Equal(t, &UploadArguments{
+		Path:               "/var/sourcemaps",
+		MinifiedPathPrefix: "http://example.com/",
+		APIKey:             "uploadkey",
+		MaxFileSize:        50 * units.MiB,
+	}, args.SourceMaps.Upload)
+}
+
+func TestArguments_InvalidAppKeys(t *testing.T) {
+	tt := []struct {
+		name      string
+		cfg       string
+		expectErr string
+	}{
+		{
+			name: "duplicate app",
+			cfg: `
+				server {
+					app_key {
+						app     = "frontend"
+						api_key = "key1"
+					}
+					app_key {
+						app     = "frontend"
+						api_key = "key2"
+					}
+				}
+				output {}
+			`,
+			expectErr: `app_key for app "frontend" is defined more than once`,
+		},
+		{
+			name: "duplicate key",
+			cfg: `
+				server {
+					app_key {
+						app     = "frontend"
+						api_key = "key1"
+					}
+					app_key {
+						app     = "backoffice"
+						api_key = "key1"
+					}
+				}
+				output {}
+			`,
+			expectErr: `api_key of app "backoffice" is already in use`,
+		},
+		{
+			name: "same key as the server",
+			cfg: `
+				server {
+					api_key = "key1"
+
+					app_key {
+						app     = "frontend"
+						api_key = "key1"
+					}
+				}
+				output {}
+			`,
+			expectErr: `api_key of app "frontend" is already in use`,
+		},
+		{
+			name: "empty app",
+			cfg: `
+				server {
+					app_key {
+						app     = ""
+						api_key = "key1"
+					}
+				}
+				output {}
+			`,
+			expectErr: `app must not be empty`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			require.ErrorContains(t, syntax.Unmarshal([]byte(tc.cfg), &args), tc.expectErr)
+		})
+	}
+}
@@ -3,6 +3,7 @@ package receiver
 import (
 	"crypto/subtle"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"sync"
 	"time"
@@ -28,6 +29,10 @@ type handler struct {
 	argsMut sync.RWMutex
 	args    ServerArguments
 	cors    *cors.Cors
+
+	// appLimiters holds the rate limiter of each app with an API key, by app
+	// name.
+	appLimiters map[string]*rate.Limiter
 }
 
 var _ http.Handler = (*handler)(nil)
@@ -44,6 +49,7 @@ func newHandler(l log.Logger, reg prometheus.Registerer, exporters []exporter) *
 		rateLimiter: rate.NewLimiter(rate.Inf, 0),
 		exporters:   exporters,
 		errorsTotal: errorsTotal,
+		appLimiters: make(map[string]*rate.Limiter),
 	}
 }
 
@@ -53,20 +59,20 @@ func (h *handler) Update(args ServerArguments) {
 
 	h.args = args
 
-	if args.RateLimiting.Enabled {
-		// Updating the rate limit to time.Now() would immediately fill the
-		// buckets. To allow requsts to immediately pass through, we adjust the
-		// time to set the limit/burst to to allow for both the normal rate and
-		// burst to be filled.
-		t := time.Now().Add(-time.Duration(float64(time.Second) * args.RateLimiting.Rate * args.RateLimiting.BurstSize))
+	updateRateLimiter(h.rateLimiter, args.RateLimiting)
 
-		h.rateLimiter.SetLimitAt(t, rate.Limit(args.RateLimiting.Rate))
-		h.rateLimiter.SetBurstAt(t, int(args.RateLimiting.BurstSize))
-	} else {
-		// Set to infinite rate limit.
-		h.rateLimiter.SetLimit(rate.Inf)
-		h.rateLimiter.SetBurst(0) // 0 burst is ignored when using rate.Inf.
+	// Keep the limiters of the existing apps, so that updating the arguments
+	// doesn't discard their state.
+	appLimiters := make(map[string]*rate.Limiter, len(args.AppKeys))
+	for _, appKey := range args.AppKeys {
+		limiter, ok := h.appLimiters[appKey.App]
+		if !ok {
+			limiter = rate.NewLimiter(rate.Inf, 0)
+		}
+		updateRateLimiter(limiter, appKey.RateLimiting)
+		appLimiters[appKey.App] = limiter
 	}
+	h.appLimiters = appLimiters
 
 	if len(args.CORSAllowedOrigins) > 0 {
 		h.cors = cors.New(cors.Options{
@@ -78,6 +84,23 @@ func (h *handler) Update(args ServerArguments) {
 	}
 }
 
+func updateRateLimiter(limiter *rate.Limiter, args RateLimitingArguments) {
+	if args.Enabled {
+		// Updating the rate limit to time.Now() would immediately fill the
+		// buckets. To allow requsts to immediately pass through, we adjust the
+		// time to set the limit/burst to to allow for both the normal rate and
+		// burst to be filled.
+		t := time.Now().Add(-time.Duration(float64(time.Second) * args.Rate * args.BurstSize))
+
+		limiter.SetLimitAt(t, rate.Limit(args.Rate))
+		limiter.SetBurstAt(t, int(args.BurstSize))
+	} else {
+		// Set to infinite rate limit.
+		limiter.SetLimit(rate.Inf)
+		limiter.SetBurst(0) // 0 burst is ignored when using rate.Inf.
+	}
+}
+
 func (h *handler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
 	h.argsMut.RLock()
 	defer h.argsMut.RUnlock()
@@ -102,14 +125,14 @@ func (h *handler) handleRequest(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
-	// If an API key is configured, ensure the request has a matching key.
-	if len(h.args.APIKey) > 0 {
-		apiHeader := req.Header.Get(apiKeyHeader)
-
-		if subtle.ConstantTimeCompare([]byte(apiHeader), []byte(h.args.APIKey)) != 1 {
-			http.Error(rw, "API key not provided or incorrect", http.StatusUnauthorized)
-			return
-		}
+	app, ok := h.authenticate(req)
+	if !ok {
+		http.Error(rw, "API key not provided or incorrect", http.StatusUnauthorized)
+		return
+	}
+	if app != "" && !h.appLimiters[app].Allow() {
+		http.Error(rw, http.StatusText(http.StatusTooManyRequests), http.StatusTooManyRequests)
+		return
 	}
 
 	// Validate content length.
@@ -124,6 +147,12 @@ func (h *handler) handleRequest(rw http.ResponseWriter, req *http.Request) {
 		return
 	}
 
+	// An app key only grants access to the telemetry of its own app.
+	if app != "" && p.Meta.App.Name != app {
+		http.Error(rw, fmt.Sprintf("API key is not valid for app %q", p.Meta.App.Name), http.StatusForbidden)
+		return
+	}
+
 	var wg sync.WaitGroup
 	for _, exp := range h.exporters {
 		wg.Add(1)
@@ -141,3 +170,23 @@ func (h *handler) handleRequest(rw http.ResponseWriter, req *http.Request) {
 	rw.WriteHeader(http.StatusAccepted)
 	_, _ = rw.Write([]byte("ok"))
 }
+
+// authenticate checks the API key of the request. If an API key or app keys
+// are configured, the request must have a matching key. When the key of an
+// app matches, the name of the app is returned.
+func (h *handler) authenticate(req *http.Request) (app string, ok bool) {
+	if len(h.args.APIKey) == 0 && len(h.args.AppKeys) == 0 {
+		return "", true
+	}
+
+	apiHeader := []byte(req.Header.Get(apiKeyHeader))
+	if len(h.args.APIKey) > 0 && subtle.ConstantTimeCompare(apiHeader, []byte(h.args.APIKey)) == 1 {
+		return "", true
+	}
+	for _, appKey := range h.args.AppKeys {
+		if subtle.ConstantTimeCompare(apiHeader, []byte(appKey.APIKey)) == 1 {
+			return appKey.App, true
+		}
+	}
+	return "", false
+}
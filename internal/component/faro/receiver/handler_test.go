@@ -270,6 +270,113 @@ func TestRateLimiter(t *testing.T) {
 	assert.Equal(t, http.StatusTooManyRequests, reqs[4].Result().StatusCode)
 }
 
+const appPayload = `{
+	"traces": {
+		"resourceSpans": []
+	},
+	"logs": [],
+	"exceptions": [],
+	"measurements": [],
+	"meta": {
+		"app": {
+			"name": "frontend"
+		}
+	}
+}`
+
+func TestAppKeys(t *testing.T) {
+	var (
+		exporter1 = &testExporter{"exporter1", false, nil}
+
+		h = newHandler(
+			util.TestLogger(t),
+			prometheus.NewRegistry(),
+			[]exporter{exporter1},
+		)
+	)
+
+	h.Update(ServerArguments{
+		APIKey: "globalkey",
+		AppKeys: []AppKeyArguments{
+			{App: "frontend", APIKey: "frontendkey"},
+			{App: "backoffice", APIKey: "backofficekey"},
+		},
+	})
+
+	tt := []struct {
+		name   string
+		apiKey string
+		expect int
+	}{
+		{name: "global key", apiKey: "globalkey", expect: http.StatusAccepted},
+		{name: "key of the app", apiKey: "frontendkey", expect: http.StatusAccepted},
+		{name: "key of another app", apiKey: "backofficekey", expect: http.StatusForbidden},
+		{name: "unknown key", apiKey: "badkey", expect: http.StatusUnauthorized},
+		{name: "missing key", apiKey: "", expect: http.StatusUnauthorized},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "/collect", strings.NewReader(appPayload))
+			require.NoError(t, err)
+			req.Header.Set("x-api-key", tc.apiKey)
+
+			rr := httptest.NewRecorder()
+			h.ServeHTTP(rr, req)
+			require.Equal(t, tc.expect, rr.Result().StatusCode)
+		})
+	}
+
+	// Only the requests with the global key and the key of the app are exported.
+	require.Len(t, exporter1.payloads, 2)
+}
+
+func TestAppKeyRateLimiter(t *testing.T) {
+	var (
+		exporter1 = &testExporter{"exporter1", false, nil}
+
+		h = newHandler(
+			util.TestLogger(t),
+			prometheus.NewRegistry(),
+			[]exporter{exporter1},
+		)
+	)
+
+	h.Update(ServerArguments{
+		RateLimiting: RateLimitingArguments{
+			Enabled: false,
+		},
+		AppKeys: []AppKeyArguments{
+			{
+				App:    "frontend",
+				APIKey: "frontendkey",
+				RateLimiting: RateLimitingArguments{
+					Enabled:   true,
+					Rate:      1,
+					BurstSize: 1,
+				},
+			},
+			{App: "backoffice", APIKey: "backofficekey"},
+		},
+	})
+
+	doRequest := func(apiKey string) int {
+		req, err := http.NewRequest(http.MethodPost, "/collect", strings.NewReader(appPayload))
+		require.NoError(t, err)
+		req.Header.Set("x-api-key", apiKey)
+
+		rr := httptest.NewRecorder()
+		h.ServeHTTP(rr, req)
+		return rr.Result().StatusCode
+	}
+
+	assert.Equal(t, http.StatusAccepted, doRequest("frontendkey"))
+	assert.Equal(t, http.StatusTooManyRequests, doRequest("frontendkey"))
+
+	// The limits of the other apps aren't affected.
+	assert.Equal(t, http.StatusForbidden, doRequest("backofficekey"))
+}
+
 type testExporter struct {
 	name     string
 	broken   bool
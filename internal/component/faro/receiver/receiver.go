@@ -28,6 +28,7 @@ func init() {
 type Component struct {
 	log               log.Logger
 	handler           *handler
+	uploadHandler     *uploadHandler
 	lazySourceMaps    *varSourceMapsStore
 	sourceMapsMetrics *sourceMapMetrics
 	serverMetrics     *serverMetrics
@@ -58,6 +59,8 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		traces  = newTracesExporter(log.With(o.Logger, "exporter", "traces"))
 	)
 
+	sourceMapsMetrics := newSourceMapMetrics(o.Registerer)
+
 	c := &Component{
 		log: o.Logger,
 		handler: newHandler(
@@ -65,8 +68,13 @@ func New(o component.Options, args Arguments) (*Component, error) {
 			o.Registerer,
 			[]exporter{metrics, logs, traces},
 		),
+		uploadHandler: newUploadHandler(
+			log.With(o.Logger, "subcomponent", "upload_handler"),
+			varStore,
+			sourceMapsMetrics,
+		),
 		lazySourceMaps:    varStore,
-		sourceMapsMetrics: newSourceMapMetrics(o.Registerer),
+		sourceMapsMetrics: sourceMapsMetrics,
 		serverMetrics:     newServerMetrics(o.Registerer),
 
 		metrics: metrics,
@@ -130,6 +138,7 @@ func (c *Component) Update(args component.Arguments) error {
 	c.logs.SetLabels(newArgs.LogLabels)
 
 	c.handler.Update(newArgs.Server)
+	c.uploadHandler.Update(newArgs.SourceMaps.Upload)
 
 	c.lazySourceMaps.SetInner(newSourceMapsStore(
 		log.With(c.log, "subcomponent", "handler"),
@@ -159,6 +168,7 @@ func (c *Component) Update(args component.Arguments) error {
 			args.Server,
 			c.serverMetrics,
 			c.handler,
+			c.uploadHandler,
 		)
 
 		// Reset health status.
@@ -226,6 +236,15 @@ func (vs *varSourceMapsStore) GetSourceMap(sourceURL string, release string) (*s
 	return nil, fmt.Errorf("no sourcemap available")
 }
 
+func (vs *varSourceMapsStore) Invalidate(release string) {
+	vs.mut.RLock()
+	defer vs.mut.RUnlock()
+
+	if vs.inner != nil {
+		vs.inner.Invalidate(release)
+	}
+}
+
 func (vs *varSourceMapsStore) SetInner(inner sourceMapsStore) {
 	vs.mut.Lock()
 	defer vs.mut.Unlock()
@@ -60,24 +60,27 @@ func newServerMetrics(reg prometheus.Registerer) *serverMetrics {
 // server is not dynamically updatable. To update server, shut down the old
 // server and start a new one.
 type server struct {
-	log     log.Logger
-	args    ServerArguments
-	handler http.Handler
-	metrics *serverMetrics
+	log           log.Logger
+	args          ServerArguments
+	handler       http.Handler
+	uploadHandler http.Handler
+	metrics       *serverMetrics
 }
 
-func newServer(l log.Logger, args ServerArguments, metrics *serverMetrics, h http.Handler) *server {
+func newServer(l log.Logger, args ServerArguments, metrics *serverMetrics, h http.Handler, uploadHandler http.Handler) *server {
 	return &server{
-		log:     l,
-		args:    args,
-		handler: h,
-		metrics: metrics,
+		log:           l,
+		args:          args,
+		handler:       h,
+		uploadHandler: uploadHandler,
+		metrics:       metrics,
 	}
 }
 
 func (s *server) Run(ctx context.Context) error {
 	r := mux.NewRouter()
 	r.Handle("/collect", s.handler).Methods(http.MethodPost, http.MethodOptions)
+	r.PathPrefix(uploadPathPrefix).Handler(s.uploadHandler).Methods(http.MethodPost, http.MethodPut)
 
 	r.HandleFunc("/-/ready", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
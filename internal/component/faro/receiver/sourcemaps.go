@@ -2,6 +2,7 @@ package receiver
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -28,6 +29,8 @@ import (
 // transforming minified source locations to the original source location.
 type sourceMapsStore interface {
 	GetSourceMap(sourceURL string, release string) (*sourcemap.Consumer, error)
+	// Invalidate removes the cached source maps of release.
+	Invalidate(release string)
 }
 
 // Stub interfaces for easier mocking.
@@ -70,6 +73,7 @@ type sourceMapMetrics struct {
 	cacheSize *prometheus.CounterVec
 	downloads *prometheus.CounterVec
 	fileReads *prometheus.CounterVec
+	uploads   *prometheus.CounterVec
 }
 
 func newSourceMapMetrics(reg prometheus.Registerer) *sourceMapMetrics {
@@ -86,11 +90,16 @@ func newSourceMapMetrics(reg prometheus.Registerer) *sourceMapMetrics {
 			Name: "faro_receiver_sourcemap_file_reads_total",
 			Help: "source map file reads from file system, by origin and status",
 		}, []string{"origin", "status"}),
+		uploads: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "faro_receiver_sourcemap_uploads_total",
+			Help: "source map uploads, by status",
+		}, []string{"status"}),
 	}
 
 	m.cacheSize = util.MustRegisterOrGet(reg, m.cacheSize).(*prometheus.CounterVec)
 	m.downloads = util.MustRegisterOrGet(reg, m.downloads).(*prometheus.CounterVec)
 	m.fileReads = util.MustRegisterOrGet(reg, m.fileReads).(*prometheus.CounterVec)
+	m.uploads = util.MustRegisterOrGet(reg, m.uploads).(*prometheus.CounterVec)
 	return m
 }
 
@@ -182,7 +191,27 @@ func (store *sourceMapsStoreImpl) GetSourceMap(sourceURL string, release string)
 	return consumer, nil
 }
 
+func (store *sourceMapsStoreImpl) Invalidate(release string) {
+	store.cacheMut.Lock()
+	defer store.cacheMut.Unlock()
+
+	suffix := "__" + release
+	for cacheKey := range store.cache {
+		if strings.HasSuffix(cacheKey, suffix) {
+			delete(store.cache, cacheKey)
+		}
+	}
+}
+
 func (store *sourceMapsStoreImpl) getSourceMapContent(sourceURL string, release string) (content []byte, sourceMapURL string, err error) {
+	// Uploaded source maps take precedence over the other locations.
+	if store.args.Upload != nil {
+		content, sourceMapURL, err = store.getUploadedSourceMap(sourceURL, release)
+		if content != nil || err != nil {
+			return content, sourceMapURL, err
+		}
+	}
+
 	// Attempt to find the source map in the filesystem first.
 	for _, loc := range store.locs {
 		content, sourceMapURL, err = store.getSourceMapFromFileSystem(sourceURL, release, loc)
@@ -242,6 +271,33 @@ func (store *sourceMapsStoreImpl) getSourceMapFromFileSystem(sourceURL string, r
 	return content, sourceURL, err
 }
 
+func (store *sourceMapsStoreImpl) getUploadedSourceMap(sourceURL string, release string) (content []byte, sourceMapURL string, err error) {
+	upload := store.args.Upload
+	if len(sourceURL) == 0 || !strings.HasPrefix(sourceURL, upload.MinifiedPathPrefix) {
+		return nil, "", nil
+	}
+
+	filePath := strings.TrimPrefix(strings.Split(sourceURL, "?")[0], upload.MinifiedPathPrefix)
+	mapFilePath, ok := uploadedSourceMapPath(upload.Path, release, filePath+".map")
+	if !ok {
+		return nil, "", nil
+	}
+
+	content, err = os.ReadFile(mapFilePath)
+	if errors.Is(err, fs.ErrNotExist) {
+		store.metrics.fileReads.WithLabelValues(getOrigin(sourceURL), "not_found").Inc()
+		level.Debug(store.log).Log("msg", "uploaded source map not found", "url", sourceURL, "file_path", mapFilePath)
+		return nil, "", nil
+	} else if err != nil {
+		store.metrics.fileReads.WithLabelValues(getOrigin(sourceURL), "error").Inc()
+		return nil, "", err
+	}
+	store.metrics.fileReads.WithLabelValues(getOrigin(sourceURL), "ok").Inc()
+	level.Debug(store.log).Log("msg", "uploaded source map found", "url", sourceURL, "file_path", mapFilePath)
+
+	return content, sourceURL, nil
+}
+
 func (store *sourceMapsStoreImpl) downloadSourceMapContent(sourceURL string) (content []byte, resolvedSourceMapURL string, err error) {
 	level.Debug(store.log).Log("msg", "attempting to download source file", "url", sourceURL)
 
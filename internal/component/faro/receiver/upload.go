@@ -0,0 +1,167 @@
+package receiver
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/log"
+	"github.com/go-sourcemap/sourcemap"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+const uploadPathPrefix = "/sourcemaps/"
+
+// uploadHandler receives the source maps uploaded for a release and stores
+// them on the filesystem, where the source maps store looks them up.
+type uploadHandler struct {
+	log     log.Logger
+	store   sourceMapsStore
+	metrics *sourceMapMetrics
+
+	argsMut sync.RWMutex
+	args    *UploadArguments
+}
+
+var _ http.Handler = (*uploadHandler)(nil)
+
+func newUploadHandler(l log.Logger, store sourceMapsStore, metrics *sourceMapMetrics) *uploadHandler {
+	return &uploadHandler{
+		log:     l,
+		store:   store,
+		metrics: metrics,
+	}
+}
+
+// Update sets the upload arguments. Uploads are rejected when args is nil.
+func (h *uploadHandler) Update(args *UploadArguments) {
+	h.argsMut.Lock()
+	defer h.argsMut.Unlock()
+
+	h.args = args
+}
+
+func (h *uploadHandler) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	h.argsMut.RLock()
+	defer h.argsMut.RUnlock()
+
+	if h.args == nil {
+		http.Error(rw, "source map uploads are not enabled", http.StatusNotFound)
+		return
+	}
+
+	apiHeader := req.Header.Get(apiKeyHeader)
+	if subtle.ConstantTimeCompare([]byte(apiHeader), []byte(h.args.APIKey)) != 1 {
+		h.metrics.uploads.WithLabelValues("unauthorized").Inc()
+		http.Error(rw, "API key not provided or incorrect", http.StatusUnauthorized)
+		return
+	}
+
+	// The path of the request is the release followed by the path of the source
+	// map, relative to the minified path prefix.
+	release, filePath, _ := strings.Cut(strings.TrimPrefix(req.URL.Path, uploadPathPrefix), "/")
+	if !strings.HasSuffix(filePath, ".map") {
+		h.metrics.uploads.WithLabelValues("invalid_path").Inc()
+		http.Error(rw, "the path of a source map must end with .map", http.StatusBadRequest)
+		return
+	}
+	mapFilePath, ok := uploadedSourceMapPath(h.args.Path, release, filePath)
+	if !ok {
+		h.metrics.uploads.WithLabelValues("invalid_path").Inc()
+		http.Error(rw, "the release and path of the source map must not be empty", http.StatusBadRequest)
+		return
+	}
+
+	maxSize := int64(h.args.MaxFileSize)
+	if req.ContentLength > maxSize {
+		h.metrics.uploads.WithLabelValues("too_large").Inc()
+		http.Error(rw, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+		return
+	}
+	content, err := io.ReadAll(http.MaxBytesReader(rw, req.Body, maxSize))
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			h.metrics.uploads.WithLabelValues("too_large").Inc()
+			http.Error(rw, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return
+		}
+		h.metrics.uploads.WithLabelValues("error").Inc()
+		http.Error(rw, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Reject invalid source maps now rather than when they're used.
+	if _, err := sourcemap.Parse(filePath, content); err != nil {
+		h.metrics.uploads.WithLabelValues("invalid_source_map").Inc()
+		http.Error(rw, fmt.Sprintf("invalid source map: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	if err := writeFileAtomic(mapFilePath, content); err != nil {
+		level.Error(h.log).Log("msg", "failed to store uploaded source map", "file_path", mapFilePath, "err", err)
+		h.metrics.uploads.WithLabelValues("error").Inc()
+		http.Error(rw, "failed to store source map", http.StatusInternalServerError)
+		return
+	}
+	level.Info(h.log).Log("msg", "stored uploaded source map", "release", release, "file_path", mapFilePath)
+	h.metrics.uploads.WithLabelValues("ok").Inc()
+
+	// Source maps which weren't found for the release may be cached.
+	h.store.Invalidate(release)
+
+	rw.WriteHeader(http.StatusCreated)
+	_, _ = rw.Write([]byte("ok"))
+}
+
+// uploadedSourceMapPath returns the path on the filesystem of the source map
+// uploaded for release, where filePath is relative to the minified path
+// prefix. The release and parts of filePath are cleaned so that the returned
+// path is always within root.
+func uploadedSourceMapPath(root, release, filePath string) (string, bool) {
+	release = cleanFilePathPart(release)
+	if release == "" {
+		return "", false
+	}
+
+	pathParts := []string{root, release}
+	for _, part := range strings.Split(filePath, "/") {
+		if part = cleanFilePathPart(part); part != "" {
+			pathParts = append(pathParts, part)
+		}
+	}
+	if len(pathParts) == 2 {
+		return "", false
+	}
+	return filepath.Join(pathParts...), true
+}
+
+// writeFileAtomic writes content to a temporary file which is then renamed to
+// name, so that readers never see a partially written file.
+func writeFileAtomic(name string, content []byte) error {
+	dir := filepath.Dir(name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(dir, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(content); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
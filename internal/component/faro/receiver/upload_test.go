@@ -0,0 +1,188 @@
+package receiver
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/grafana/alloy/internal/component/faro/receiver/internal/payload"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestUploadHandler(t *testing.T, args *UploadArguments) (*uploadHandler, *sourceMapsStoreImpl) {
+	t.Helper()
+
+	var (
+		logger  = util.TestLogger(t)
+		metrics = newSourceMapMetrics(prometheus.NewRegistry())
+
+		store = newSourceMapsStore(
+			logger,
+			SourceMapsArguments{Upload: args},
+			metrics,
+			&mockHTTPClient{},
+			&mockFileService{},
+		)
+
+		h = newUploadHandler(logger, store, metrics)
+	)
+	h.Update(args)
+	return h, store
+}
+
+func doUpload(h http.Handler, path, apiKey string, body []byte) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPut, path, bytes.NewReader(body))
+	req.Header.Set("x-api-key", apiKey)
+
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+	return rr
+}
+
+func TestUpload(t *testing.T) {
+	dir := t.TempDir()
+	h, store := newTestUploadHandler(t, &UploadArguments{
+		Path:               dir,
+		MinifiedPathPrefix: "http://localhost:1234/",
+		APIKey:             "uploadkey",
+		MaxFileSize:        1 << 20,
+	})
+
+	// The source map isn't available before it's uploaded.
+	require.Equal(t, mockException(), transformException(util.TestLogger(t), store, mockException(), "123"))
+
+	rr := doUpload(h, "/sourcemaps/123/foo.js.map", "uploadkey", loadTestData(t, "foo.js.map"))
+	require.Equal(t, http.StatusCreated, rr.Result().StatusCode)
+	require.FileExists(t, filepath.Join(dir, "123", "foo.js.map"))
+
+	expect := &payload.Exception{
+		Stacktrace: &payload.Stacktrace{
+			Frames: []payload.Frame{
+				{
+					Colno:    37,
+					Filename: "/__parcel_source_root/demo/src/actions.ts",
+					Function: "?",
+					Lineno:   6,
+				},
+				{
+					Colno:    2,
+					Filename: "/__parcel_source_root/demo/src/actions.ts",
+					Function: "?",
+					Lineno:   7,
+				},
+			},
+		},
+	}
+	require.Equal(t, expect, transformException(util.TestLogger(t), store, mockException(), "123"))
+
+	// Source maps are stored per release.
+	require.Equal(t, mockException(), transformException(util.TestLogger(t), store, mockException(), "456"))
+}
+
+func TestUploadRejected(t *testing.T) {
+	dir := t.TempDir()
+	h, _ := newTestUploadHandler(t, &UploadArguments{
+		Path:               dir,
+		MinifiedPathPrefix: "http://localhost:1234/",
+		APIKey:             "uploadkey",
+		MaxFileSize:        1 << 10,
+	})
+
+	sourceMap := loadTestData(t, "foo.js.map")
+
+	tt := []struct {
+		name   string
+		path   string
+		apiKey string
+		body   []byte
+		expect int
+	}{
+		{
+			name:   "missing key",
+			path:   "/sourcemaps/123/foo.js.map",
+			body:   sourceMap,
+			expect: http.StatusUnauthorized,
+		},
+		{
+			name:   "invalid key",
+			path:   "/sourcemaps/123/foo.js.map",
+			apiKey: "badkey",
+			body:   sourceMap,
+			expect: http.StatusUnauthorized,
+		},
+		{
+			name:   "not a source map path",
+			path:   "/sourcemaps/123/foo.js",
+			apiKey: "uploadkey",
+			body:   sourceMap,
+			expect: http.StatusBadRequest,
+		},
+		{
+			name:   "missing release",
+			path:   "/sourcemaps/foo.js.map",
+			apiKey: "uploadkey",
+			body:   sourceMap,
+			expect: http.StatusBadRequest,
+		},
+		{
+			name:   "invalid source map",
+			path:   "/sourcemaps/123/foo.js.map",
+			apiKey: "uploadkey",
+			body:   []byte("not a source map"),
+			expect: http.StatusBadRequest,
+		},
+		{
+			name:   "too large",
+			path:   "/sourcemaps/123/foo.js.map",
+			apiKey: "uploadkey",
+			body:   []byte(strings.Repeat("a", 1<<11)),
+			expect: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			rr := doUpload(h, tc.path, tc.apiKey, tc.body)
+			require.Equal(t, tc.expect, rr.Result().StatusCode)
+		})
+	}
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestUploadDisabled(t *testing.T) {
+	h, _ := newTestUploadHandler(t, nil)
+
+	rr := doUpload(h, "/sourcemaps/123/foo.js.map", "", loadTestData(t, "foo.js.map"))
+	require.Equal(t, http.StatusNotFound, rr.Result().StatusCode)
+}
+
+func Test_uploadedSourceMapPath(t *testing.T) {
+	tt := []struct {
+		release  string
+		filePath string
+		expect   string
+		ok       bool
+	}{
+		{"123", "foo.js.map", filepath.FromSlash("/uploads/123/foo.js.map"), true},
+		{"123", "static/js/foo.js.map", filepath.FromSlash("/uploads/123/static/js/foo.js.map"), true},
+		{"../123", "../../foo.js.map", filepath.FromSlash("/uploads/123/foo.js.map"), true},
+		{"123", "static/..\\..\\foo.js.map", filepath.FromSlash("/uploads/123/static/foo.js.map"), true},
+		{"", "foo.js.map", "", false},
+		{"123", "../", "", false},
+	}
+
+	for _, tc := range tt {
+		actual, ok := uploadedSourceMapPath(filepath.FromSlash("/uploads"), tc.release, tc.filePath)
+		require.Equal(t, tc.ok, ok, "release %q, file path %q", tc.release, tc.filePath)
+		require.Equal(t, tc.expect, actual, "release %q, file path %q", tc.release, tc.filePath)
+	}
+}
@@ -0,0 +1,153 @@
+package credentials
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/syntax/alloytypes"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "local.credentials",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   Exports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments holds values which are used to configure the local.credentials
+// component. Exactly one of the blocks must be set.
+type Arguments struct {
+	BasicAuth *BasicAuth `alloy:"basic_auth,block,optional"`
+	Bearer    *Bearer    `alloy:"bearer,block,optional"`
+	OAuth2    *OAuth2    `alloy:"oauth2,block,optional"`
+	SigV4     *SigV4     `alloy:"sigv4,block,optional"`
+}
+
+// BasicAuth configures basic authentication credentials.
+type BasicAuth struct {
+	Username string            `alloy:"username,attr"`
+	Password alloytypes.Secret `alloy:"password,attr"`
+}
+
+// Bearer configures a bearer token.
+type Bearer struct {
+	Token alloytypes.Secret `alloy:"token,attr"`
+}
+
+// OAuth2 configures OAuth2 client credentials.
+type OAuth2 struct {
+	ClientID     string            `alloy:"client_id,attr"`
+	ClientSecret alloytypes.Secret `alloy:"client_secret,attr"`
+	TokenURL     string            `alloy:"token_url,attr"`
+	Scopes       []string          `alloy:"scopes,attr,optional"`
+}
+
+// SigV4 configures AWS Signature Version 4 credentials.
+type SigV4 struct {
+	Region    string            `alloy:"region,attr,optional"`
+	AccessKey string            `alloy:"access_key,attr,optional"`
+	SecretKey alloytypes.Secret `alloy:"secret_key,attr,optional"`
+	Profile   string            `alloy:"profile,attr,optional"`
+	RoleARN   string            `alloy:"role_arn,attr,optional"`
+}
+
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	var set int
+	for _, b := range []bool{a.BasicAuth != nil, a.Bearer != nil, a.OAuth2 != nil, a.SigV4 != nil} {
+		if b {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("exactly one of basic_auth, bearer, oauth2, or sigv4 must be configured")
+	}
+	return nil
+}
+
+// Validate implements syntax.Validator.
+func (s *SigV4) Validate() error {
+	if (s.AccessKey == "") != (s.SecretKey == "") {
+		return fmt.Errorf("access_key and secret_key must be configured together")
+	}
+	return nil
+}
+
+// Exports holds values which are exported by the local.credentials
+// component. Fields of the blocks which aren't configured are exported as
+// their zero value.
+type Exports struct {
+	Username     string            `alloy:"username,attr"`
+	Password     alloytypes.Secret `alloy:"password,attr"`
+	BearerToken  alloytypes.Secret `alloy:"bearer_token,attr"`
+	ClientID     string            `alloy:"client_id,attr"`
+	ClientSecret alloytypes.Secret `alloy:"client_secret,attr"`
+	TokenURL     string            `alloy:"token_url,attr"`
+	Scopes       []string          `alloy:"scopes,attr"`
+	Region       string            `alloy:"region,attr"`
+	AccessKey    string            `alloy:"access_key,attr"`
+	SecretKey    alloytypes.Secret `alloy:"secret_key,attr"`
+	Profile      string            `alloy:"profile,attr"`
+	RoleARN      string            `alloy:"role_arn,attr"`
+}
+
+func (a Arguments) exports() Exports {
+	var e Exports
+	switch {
+	case a.BasicAuth != nil:
+		e.Username = a.BasicAuth.Username
+		e.Password = a.BasicAuth.Password
+	case a.Bearer != nil:
+		e.BearerToken = a.Bearer.Token
+	case a.OAuth2 != nil:
+		e.ClientID = a.OAuth2.ClientID
+		e.ClientSecret = a.OAuth2.ClientSecret
+		e.TokenURL = a.OAuth2.TokenURL
+		e.Scopes = a.OAuth2.Scopes
+	case a.SigV4 != nil:
+		e.Region = a.SigV4.Region
+		e.AccessKey = a.SigV4.AccessKey
+		e.SecretKey = a.SigV4.SecretKey
+		e.Profile = a.SigV4.Profile
+		e.RoleARN = a.SigV4.RoleARN
+	}
+	return e
+}
+
+// Component implements the local.credentials component.
+type Component struct {
+	opts component.Options
+}
+
+var _ component.Component = (*Component)(nil)
+
+// New creates a new local.credentials component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	c := &Component{opts: o}
+
+	// Call to Update() to set the exports once at the start.
+	if err := c.Update(args); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Run implements component.Component.
+func (c *Component) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+// Update implements component.Component.
+func (c *Component) Update(args component.Arguments) error {
+	c.opts.OnStateChange(args.(Arguments).exports())
+	return nil
+}
@@ -0,0 +1,102 @@
+package credentials_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component/local/credentials"
+	"github.com/grafana/alloy/internal/runtime/componenttest"
+	"github.com/grafana/alloy/syntax"
+	"github.com/grafana/alloy/syntax/alloytypes"
+)
+
+func TestCredentials(t *testing.T) {
+	tc, err := componenttest.NewControllerFromID(nil, "local.credentials")
+	require.NoError(t, err)
+
+	var args credentials.Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(`
+		basic_auth {
+			username = "alloy"
+			password = "secret"
+		}
+	`), &args))
+	go func() {
+		require.NoError(t, tc.Run(componenttest.TestContext(t), args))
+	}()
+
+	require.NoError(t, tc.WaitExports(time.Second))
+	require.Equal(t, credentials.Exports{
+		Username: "alloy",
+		Password: alloytypes.Secret("secret"),
+	}, tc.Exports())
+
+	// Rotated credentials are exported on update.
+	args = credentials.Arguments{}
+	require.NoError(t, syntax.Unmarshal([]byte(`
+		oauth2 {
+			client_id     = "alloy"
+			client_secret = "rotated"
+			token_url     = "https://example.com/token"
+			scopes        = ["metrics"]
+		}
+	`), &args))
+	require.NoError(t, tc.Update(args))
+	require.Eventually(t, func() bool {
+		return tc.Exports().(credentials.Exports).ClientSecret == "rotated"
+	}, time.Second, 10*time.Millisecond)
+	require.Equal(t, credentials.Exports{
+		ClientID:     "alloy",
+		ClientSecret: alloytypes.Secret("rotated"),
+		TokenURL:     "https://example.com/token",
+		Scopes:       []string{"metrics"},
+	}, tc.Exports())
+}
+
+func TestArgumentsValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name:   "bearer",
+			config: `bearer { token = "token" }`,
+		},
+		{
+			name:   "sigv4 without keys",
+			config: `sigv4 { region = "us-east-1" }`,
+		},
+		{
+			name:   "no blocks",
+			config: ``,
+			err:    "exactly one of basic_auth, bearer, oauth2, or sigv4 must be configured",
+		},
+		{
+			name: "multiple blocks",
+			config: `
+				bearer { token = "token" }
+				sigv4 { region = "us-east-1" }
+			`,
+			err: "exactly one of basic_auth, bearer, oauth2, or sigv4 must be configured",
+		},
+		{
+			name:   "sigv4 access key without secret key",
+			config: `sigv4 { access_key = "key" }`,
+			err:    "access_key and secret_key must be configured together",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args credentials.Arguments
+			err := syntax.Unmarshal([]byte(tt.config), &args)
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
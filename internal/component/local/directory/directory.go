@@ -0,0 +1,381 @@
+package directory
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/bmatcuk/doublestar"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/featuregate"
+	filedetector "github.com/grafana/alloy/internal/filedetector"
+	"github.com/grafana/alloy/internal/runtime/equality"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/syntax/alloytypes"
+)
+
+// waitReadPeriod holds the time to wait before reading the directory while
+// the local.directory component is running.
+//
+// This prevents local.directory from updating too frequently and exporting
+// partial writes.
+const waitReadPeriod time.Duration = 30 * time.Millisecond
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "local.directory",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   Exports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments holds values which are used to configure the local.directory
+// component.
+type Arguments struct {
+	// Path indicates the directory to watch.
+	Path string `alloy:"path,attr"`
+	// Include holds the glob patterns of the files to list. All files are
+	// listed when empty.
+	Include []string `alloy:"include,attr,optional"`
+	// Exclude holds the glob patterns of the files to leave out.
+	Exclude []string `alloy:"exclude,attr,optional"`
+	// Recursive lists the files of the subdirectories too.
+	Recursive bool `alloy:"recursive,attr,optional"`
+	// MaxContentSize is the size in bytes up to which the content of the files
+	// is exported. The content isn't exported when 0.
+	MaxContentSize int64 `alloy:"max_content_size,attr,optional"`
+	// IsSecret marks the content of the files as secret values which should
+	// not be displayed to the user.
+	IsSecret bool `alloy:"is_secret,attr,optional"`
+	// Type indicates how to detect changes to the directory.
+	Type filedetector.Detector `alloy:"detector,attr,optional"`
+	// PollFrequency determines the frequency to check for changes when Type is
+	// Poll.
+	PollFrequency time.Duration `alloy:"poll_frequency,attr,optional"`
+}
+
+// DefaultArguments provides the default arguments for the local.directory
+// component.
+var DefaultArguments = Arguments{
+	Type:          filedetector.DetectorFSNotify,
+	PollFrequency: time.Minute,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *Arguments) SetToDefault() {
+	*a = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if a.Path == "" {
+		return fmt.Errorf("path must not be empty")
+	}
+	if a.PollFrequency <= 0 {
+		return fmt.Errorf("poll_frequency must be greater than 0")
+	}
+	if a.MaxContentSize < 0 {
+		return fmt.Errorf("max_content_size must not be negative")
+	}
+	// Match only reports the syntax errors of the parts of the pattern that it
+	// reaches, so the patterns are matched against themselves.
+	for _, pattern := range append(append([]string{}, a.Include...), a.Exclude...) {
+		if _, err := doublestar.Match(pattern, pattern); err != nil {
+			return fmt.Errorf("invalid glob pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// Exports holds values which are exported by the local.directory component.
+type Exports struct {
+	// Files in the directory, ordered by name.
+	Files []File `alloy:"files,attr"`
+}
+
+// File describes a file in the directory.
+type File struct {
+	// Name of the file, relative to the directory.
+	Name string `alloy:"name,attr"`
+	// Path of the file.
+	Path string `alloy:"path,attr"`
+	// Size of the file in bytes.
+	Size int64 `alloy:"size,attr"`
+	// ModTime is the modification time of the file, formatted as RFC3339.
+	ModTime string `alloy:"mod_time,attr"`
+	// Content of the file, only set for files up to max_content_size bytes.
+	Content alloytypes.OptionalSecret `alloy:"content,attr"`
+}
+
+// Component implements the local.directory component.
+type Component struct {
+	opts component.Options
+
+	mut       sync.Mutex
+	args      Arguments
+	lastFiles []File
+	detector  io.Closer
+
+	healthMut sync.RWMutex
+	health    component.Health
+
+	// reloadCh is a buffered channel which is written to when the watched
+	// directory should be listed again by the component.
+	reloadCh chan struct{}
+}
+
+var (
+	_ component.Component       = (*Component)(nil)
+	_ component.HealthComponent = (*Component)(nil)
+)
+
+// New creates a new local.directory component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	c := &Component{
+		opts:     o,
+		reloadCh: make(chan struct{}, 1),
+	}
+
+	// Perform an update which will immediately set our exports to the initial
+	// files of the directory.
+	if err := c.Update(args); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Run implements component.Component.
+func (c *Component) Run(ctx context.Context) error {
+	defer func() {
+		c.mut.Lock()
+		defer c.mut.Unlock()
+
+		if c.detector != nil {
+			if err := c.detector.Close(); err != nil {
+				level.Error(c.opts.Logger).Log("msg", "failed to shut down detector", "err", err)
+			}
+		}
+		c.detector = nil
+	}()
+
+	// The detector may have been closed by a previous call to Run.
+	c.mut.Lock()
+	_ = c.configureDetector()
+	c.mut.Unlock()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.reloadCh:
+			time.Sleep(waitReadPeriod)
+
+			// We ignore the error here from readDirectory since readDirectory will
+			// log errors and also report the error as the health of the component.
+			c.mut.Lock()
+			_ = c.readDirectory()
+			c.mut.Unlock()
+		}
+	}
+}
+
+// readDirectory lists the files of the directory and exports them if they
+// changed. mut must be held when called.
+func (c *Component) readDirectory() error {
+	files, err := listFiles(c.args)
+	if err != nil {
+		c.setHealth(component.Health{
+			Health:     component.HealthTypeUnhealthy,
+			Message:    fmt.Sprintf("failed to read directory: %s", err),
+			UpdateTime: time.Now(),
+		})
+		level.Error(c.opts.Logger).Log("msg", "failed to read directory", "path", c.args.Path, "err", err)
+		return err
+	}
+
+	// Only export the files when they changed, to avoid re-evaluating the
+	// components that depend on them on every event.
+	if c.lastFiles == nil || !equality.DeepEqual(c.lastFiles, files) {
+		c.lastFiles = files
+		c.opts.OnStateChange(Exports{Files: files})
+	}
+
+	c.setHealth(component.Health{
+		Health:     component.HealthTypeHealthy,
+		Message:    "read directory",
+		UpdateTime: time.Now(),
+	})
+	return nil
+}
+
+// listFiles returns the files of the directory matching the arguments, in
+// lexical order.
+func listFiles(args Arguments) ([]File, error) {
+	files := make([]File, 0)
+	err := filepath.WalkDir(args.Path, func(curPath string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if curPath != args.Path && !args.Recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(args.Path, curPath)
+		if err != nil {
+			return err
+		}
+		name := filepath.ToSlash(rel)
+		if !matches(name, args) {
+			return nil
+		}
+
+		// Symbolic links are followed, and skipped when they point to a
+		// directory or don't resolve.
+		fi, err := os.Stat(curPath)
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if !fi.Mode().IsRegular() {
+			return nil
+		}
+
+		f := File{
+			Name:    name,
+			Path:    curPath,
+			Size:    fi.Size(),
+			ModTime: fi.ModTime().UTC().Format(time.RFC3339),
+			Content: alloytypes.OptionalSecret{IsSecret: args.IsSecret},
+		}
+		if args.MaxContentSize > 0 && fi.Size() <= args.MaxContentSize {
+			bb, err := os.ReadFile(curPath)
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			f.Content.Value = string(bb)
+		}
+		files = append(files, f)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// matches returns whether the file with the given name, relative to the
+// directory, matches the include and exclude patterns.
+func matches(name string, args Arguments) bool {
+	for _, pattern := range args.Exclude {
+		if ok, _ := doublestar.Match(pattern, name); ok {
+			return false
+		}
+	}
+	if len(args.Include) == 0 {
+		return true
+	}
+	for _, pattern := range args.Include {
+		if ok, _ := doublestar.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Update implements component.Component.
+func (c *Component) Update(args component.Arguments) error {
+	newArgs := args.(Arguments)
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.args = newArgs
+
+	// Force an immediate read of the directory to report any potential errors
+	// early. The files are exported again since the arguments may have changed.
+	c.lastFiles = nil
+	if err := c.readDirectory(); err != nil {
+		return fmt.Errorf("failed to read directory: %w", err)
+	}
+
+	// Shut down the existing detector (if any) in case the path or the way to
+	// watch it changed.
+	if c.detector != nil {
+		if err := c.detector.Close(); err != nil {
+			level.Error(c.opts.Logger).Log("msg", "failed to shut down old detector", "err", err)
+		}
+		c.detector = nil
+	}
+
+	return c.configureDetector()
+}
+
+// configureDetector configures the detector if one isn't set. mut must be held
+// when called.
+func (c *Component) configureDetector() error {
+	if c.detector != nil {
+		// Already have a detector; don't do anything.
+		return nil
+	}
+
+	var err error
+
+	reload := func() {
+		select {
+		case c.reloadCh <- struct{}{}:
+		default:
+			// no-op: a reload is already queued so we don't need to queue a second
+			// one.
+		}
+	}
+
+	switch c.args.Type {
+	case filedetector.DetectorPoll:
+		c.detector = filedetector.NewPoller(filedetector.PollerOptions{
+			Filename:      c.args.Path,
+			ReloadFile:    reload,
+			PollFrequency: c.args.PollFrequency,
+		})
+	case filedetector.DetectorFSNotify:
+		c.detector, err = filedetector.NewFSNotify(filedetector.FSNotifyOptions{
+			Logger:        c.opts.Logger,
+			Filename:      c.args.Path,
+			ReloadFile:    reload,
+			PollFrequency: c.args.PollFrequency,
+			Recursive:     c.args.Recursive,
+		})
+	}
+
+	return err
+}
+
+// CurrentHealth implements component.HealthComponent.
+func (c *Component) CurrentHealth() component.Health {
+	c.healthMut.RLock()
+	defer c.healthMut.RUnlock()
+	return c.health
+}
+
+func (c *Component) setHealth(h component.Health) {
+	c.healthMut.Lock()
+	defer c.healthMut.Unlock()
+	c.health = h
+}
@@ -0,0 +1,188 @@
+package directory_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component/local/directory"
+	filedetector "github.com/grafana/alloy/internal/filedetector"
+	"github.com/grafana/alloy/internal/runtime/componenttest"
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestDirectory(t *testing.T) {
+	t.Run("Polling change detector", func(t *testing.T) {
+		runDirectoryTests(t, filedetector.DetectorPoll)
+	})
+
+	t.Run("Event change detector", func(t *testing.T) {
+		runDirectoryTests(t, filedetector.DetectorFSNotify)
+	})
+}
+
+// runDirectoryTests will run a suite of tests with the configured update type.
+func runDirectoryTests(t *testing.T, ut filedetector.Detector) {
+	newSuiteController := func(t *testing.T, args directory.Arguments) *componenttest.Controller {
+		tc, err := componenttest.NewControllerFromID(nil, "local.directory")
+		require.NoError(t, err)
+
+		args.Type = ut
+		// Pick a polling frequency which is fast enough so that tests finish
+		// quickly but not so frequent such that Go struggles to schedule the
+		// goroutines of the tests on slower machines.
+		args.PollFrequency = 50 * time.Millisecond
+		go func() {
+			err := tc.Run(componenttest.TestContext(t), args)
+			require.NoError(t, err)
+		}()
+
+		// Swallow the initial exports notification.
+		require.NoError(t, tc.WaitExports(time.Second))
+		return tc
+	}
+
+	t.Run("New files are detected", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "a.txt"), "a")
+
+		tc := newSuiteController(t, directory.Arguments{Path: dir})
+		require.Equal(t, []string{"a.txt"}, fileNames(tc))
+
+		writeFile(t, filepath.Join(dir, "b.txt"), "b")
+		require.Eventually(t, func() bool {
+			return len(fileNames(tc)) == 2
+		}, 5*time.Second, 10*time.Millisecond)
+		require.Equal(t, []string{"a.txt", "b.txt"}, fileNames(tc))
+	})
+
+	t.Run("Removed files are detected", func(t *testing.T) {
+		dir := t.TempDir()
+		writeFile(t, filepath.Join(dir, "a.txt"), "a")
+		writeFile(t, filepath.Join(dir, "b.txt"), "b")
+
+		tc := newSuiteController(t, directory.Arguments{Path: dir})
+		require.Equal(t, []string{"a.txt", "b.txt"}, fileNames(tc))
+
+		require.NoError(t, os.Remove(filepath.Join(dir, "a.txt")))
+		require.Eventually(t, func() bool {
+			return len(fileNames(tc)) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+		require.Equal(t, []string{"b.txt"}, fileNames(tc))
+	})
+
+	t.Run("Files in new subdirectories are detected", func(t *testing.T) {
+		dir := t.TempDir()
+
+		tc := newSuiteController(t, directory.Arguments{Path: dir, Recursive: true})
+		require.Empty(t, fileNames(tc))
+
+		require.NoError(t, os.Mkdir(filepath.Join(dir, "sub"), 0755))
+		// Give the detector a chance to watch the new directory.
+		time.Sleep(100 * time.Millisecond)
+		writeFile(t, filepath.Join(dir, "sub", "a.txt"), "a")
+		require.Eventually(t, func() bool {
+			return len(fileNames(tc)) == 1
+		}, 5*time.Second, 10*time.Millisecond)
+		require.Equal(t, []string{"sub/a.txt"}, fileNames(tc))
+	})
+}
+
+func TestDirectoryFiles(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "small.json"), "{}")
+	writeFile(t, filepath.Join(dir, "large.json"), `{"key": "value"}`)
+	writeFile(t, filepath.Join(dir, "ignored.json"), "{}")
+	writeFile(t, filepath.Join(dir, "notes.txt"), "notes")
+	writeFile(t, filepath.Join(dir, "sub", "nested.json"), "{}")
+
+	tc, err := componenttest.NewControllerFromID(nil, "local.directory")
+	require.NoError(t, err)
+	go func() {
+		err := tc.Run(componenttest.TestContext(t), directory.Arguments{
+			Path:           dir,
+			Include:        []string{"**/*.json"},
+			Exclude:        []string{"ignored.*"},
+			Recursive:      true,
+			MaxContentSize: 8,
+			Type:           filedetector.DetectorFSNotify,
+			PollFrequency:  time.Minute,
+		})
+		require.NoError(t, err)
+	}()
+	require.NoError(t, tc.WaitExports(time.Second))
+
+	files := tc.Exports().(directory.Exports).Files
+	require.Len(t, files, 3)
+
+	require.Equal(t, "large.json", files[0].Name)
+	require.Equal(t, filepath.Join(dir, "large.json"), files[0].Path)
+	require.Equal(t, int64(16), files[0].Size)
+	require.Empty(t, files[0].Content.Value)
+
+	require.Equal(t, "small.json", files[1].Name)
+	require.Equal(t, int64(2), files[1].Size)
+	require.Equal(t, "{}", files[1].Content.Value)
+	require.False(t, files[1].Content.IsSecret)
+	_, err = time.Parse(time.RFC3339, files[1].ModTime)
+	require.NoError(t, err)
+
+	require.Equal(t, "sub/nested.json", files[2].Name)
+}
+
+func TestArguments(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name:   "valid",
+			config: "path = \"/var/lib/alloy\"\n" + `include = ["*.json"]`,
+		},
+		{
+			name:   "invalid glob",
+			config: "path = \"/var/lib/alloy\"\n" + `exclude = ["[a"]`,
+			err:    `invalid glob pattern "[a"`,
+		},
+		{
+			name:   "negative max content size",
+			config: "path = \"/var/lib/alloy\"\n" + `max_content_size = -1`,
+			err:    "max_content_size must not be negative",
+		},
+		{
+			name:   "invalid poll frequency",
+			config: "path = \"/var/lib/alloy\"\n" + `poll_frequency = "0s"`,
+			err:    "poll_frequency must be greater than 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args directory.Arguments
+			err := syntax.Unmarshal([]byte(tt.config), &args)
+			if tt.err == "" {
+				require.NoError(t, err)
+				require.Equal(t, filedetector.DetectorFSNotify, args.Type)
+				return
+			}
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func fileNames(tc *componenttest.Controller) []string {
+	names := []string{}
+	for _, f := range tc.Exports().(directory.Exports).Files {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0664))
+}
@@ -0,0 +1,339 @@
+package tls
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/config"
+	"github.com/grafana/alloy/internal/featuregate"
+	filedetector "github.com/grafana/alloy/internal/filedetector"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/syntax/alloytypes"
+)
+
+// waitReadPeriod holds the time to wait before reading the files after a
+// change is detected.
+//
+// A rotation usually writes several files, so this prevents local.tls from
+// exporting a certificate before its key is written.
+const waitReadPeriod time.Duration = 100 * time.Millisecond
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "local.tls",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   Exports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments holds values which are used to configure the local.tls component.
+type Arguments struct {
+	// CAFile is the path to the CA certificates.
+	CAFile string `alloy:"ca_file,attr,optional"`
+	// CertFile and KeyFile are the paths to the certificate and its key.
+	CertFile string `alloy:"cert_file,attr,optional"`
+	KeyFile  string `alloy:"key_file,attr,optional"`
+	// MinVersion is the minimum TLS version exported with the certificates.
+	MinVersion config.TLSVersion `alloy:"min_version,attr,optional"`
+
+	// Type indicates how to detect changes to the files.
+	Type filedetector.Detector `alloy:"detector,attr,optional"`
+	// PollFrequency determines the frequency to check for changes when Type is
+	// Poll.
+	PollFrequency time.Duration `alloy:"poll_frequency,attr,optional"`
+}
+
+// DefaultArguments provides the default arguments for the local.tls
+// component.
+var DefaultArguments = Arguments{
+	MinVersion:    config.TLSVersion(tls.VersionTLS12),
+	Type:          filedetector.DetectorFSNotify,
+	PollFrequency: time.Minute,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *Arguments) SetToDefault() {
+	*a = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if a.CAFile == "" && a.CertFile == "" && a.KeyFile == "" {
+		return fmt.Errorf("at least one of ca_file or cert_file and key_file must be configured")
+	}
+	if (a.CertFile == "") != (a.KeyFile == "") {
+		return fmt.Errorf("cert_file and key_file must be configured together")
+	}
+	if a.PollFrequency <= 0 {
+		return fmt.Errorf("poll_frequency must be greater than 0")
+	}
+	if _, err := a.MinVersion.MarshalText(); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (a *Arguments) filenames() []string {
+	var res []string
+	for _, f := range []string{a.CAFile, a.CertFile, a.KeyFile} {
+		if f != "" {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// Exports holds values which are exported by the local.tls component.
+type Exports struct {
+	CA         string            `alloy:"ca_pem,attr"`
+	Cert       string            `alloy:"cert_pem,attr"`
+	Key        alloytypes.Secret `alloy:"key_pem,attr"`
+	MinVersion string            `alloy:"min_version,attr"`
+}
+
+// Component implements the local.tls component.
+type Component struct {
+	opts component.Options
+
+	mut       sync.Mutex
+	args      Arguments
+	detectors []io.Closer
+
+	healthMut sync.RWMutex
+	health    component.Health
+
+	// reloadCh is a buffered channel which is written to when the watched files
+	// should be reloaded by the component.
+	reloadCh   chan struct{}
+	certExpiry prometheus.Gauge
+}
+
+var (
+	_ component.Component       = (*Component)(nil)
+	_ component.HealthComponent = (*Component)(nil)
+)
+
+// New creates a new local.tls component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	c := &Component{
+		opts: o,
+
+		reloadCh: make(chan struct{}, 1),
+		certExpiry: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "local_tls_certificate_expiry_timestamp_seconds",
+			Help: "The expiry time of the exported certificate in unix seconds",
+		}),
+	}
+
+	if err := o.Registerer.Register(c.certExpiry); err != nil {
+		return nil, err
+	}
+	// Perform an update which will immediately set our exports to the initial
+	// contents of the files.
+	if err := c.Update(args); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Run implements component.Component.
+func (c *Component) Run(ctx context.Context) error {
+	defer func() {
+		c.mut.Lock()
+		defer c.mut.Unlock()
+		c.closeDetectors()
+	}()
+
+	// Run may be called again after the detectors were closed, so they're
+	// recreated if needed.
+	c.mut.Lock()
+	_ = c.configureDetectors()
+	c.mut.Unlock()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-c.reloadCh:
+			time.Sleep(waitReadPeriod)
+
+			// The previous exports are kept if the files are invalid, for example
+			// while they're being rotated. readFiles reports the error as the
+			// health of the component.
+			c.mut.Lock()
+			_ = c.readFiles()
+			c.mut.Unlock()
+		}
+	}
+}
+
+// readFiles reads and validates the files, and exports their contents. mut
+// must be held when called.
+func (c *Component) readFiles() error {
+	exports, expiry, err := load(c.args)
+	if err != nil {
+		c.setHealth(component.Health{
+			Health:     component.HealthTypeUnhealthy,
+			Message:    err.Error(),
+			UpdateTime: time.Now(),
+		})
+		level.Error(c.opts.Logger).Log("msg", "failed to load TLS files", "err", err)
+		return err
+	}
+
+	if !expiry.IsZero() {
+		c.certExpiry.Set(float64(expiry.Unix()))
+	}
+	c.opts.OnStateChange(exports)
+	c.setHealth(component.Health{
+		Health:     component.HealthTypeHealthy,
+		Message:    "loaded TLS files",
+		UpdateTime: time.Now(),
+	})
+	return nil
+}
+
+// load reads the files of args and checks that they hold valid certificates,
+// and that the key matches the certificate. It returns the expiry time of the
+// certificate, if any.
+func load(args Arguments) (Exports, time.Time, error) {
+	minVersion, err := args.MinVersion.MarshalText()
+	if err != nil {
+		return Exports{}, time.Time{}, err
+	}
+	res := Exports{MinVersion: string(minVersion)}
+
+	if args.CAFile != "" {
+		bb, err := os.ReadFile(args.CAFile)
+		if err != nil {
+			return Exports{}, time.Time{}, fmt.Errorf("failed to read ca_file: %w", err)
+		}
+		if !x509.NewCertPool().AppendCertsFromPEM(bb) {
+			return Exports{}, time.Time{}, fmt.Errorf("no certificates found in ca_file %s", args.CAFile)
+		}
+		res.CA = string(bb)
+	}
+
+	var expiry time.Time
+	if args.CertFile != "" {
+		cert, err := os.ReadFile(args.CertFile)
+		if err != nil {
+			return Exports{}, time.Time{}, fmt.Errorf("failed to read cert_file: %w", err)
+		}
+		key, err := os.ReadFile(args.KeyFile)
+		if err != nil {
+			return Exports{}, time.Time{}, fmt.Errorf("failed to read key_file: %w", err)
+		}
+		pair, err := tls.X509KeyPair(cert, key)
+		if err != nil {
+			return Exports{}, time.Time{}, fmt.Errorf("invalid certificate and key: %w", err)
+		}
+		if pair.Leaf != nil {
+			expiry = pair.Leaf.NotAfter
+		}
+		res.Cert = string(cert)
+		res.Key = alloytypes.Secret(key)
+	}
+	return res, expiry, nil
+}
+
+// Update implements component.Component.
+func (c *Component) Update(args component.Arguments) error {
+	newArgs := args.(Arguments)
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.args = newArgs
+
+	// Force an immediate read of the files to report any potential errors
+	// early.
+	if err := c.readFiles(); err != nil {
+		return err
+	}
+
+	// The paths may have changed, so the existing detectors are replaced.
+	c.closeDetectors()
+	return c.configureDetectors()
+}
+
+// configureDetectors configures a detector for each file if none are set. mut
+// must be held when called.
+func (c *Component) configureDetectors() error {
+	if len(c.detectors) > 0 {
+		return nil
+	}
+
+	reloadFiles := func() {
+		select {
+		case c.reloadCh <- struct{}{}:
+		default:
+			// no-op: a reload is already queued so we don't need to queue a second
+			// one.
+		}
+	}
+
+	for _, filename := range c.args.filenames() {
+		var (
+			detector io.Closer
+			err      error
+		)
+		switch c.args.Type {
+		case filedetector.DetectorPoll:
+			detector = filedetector.NewPoller(filedetector.PollerOptions{
+				Filename:      filename,
+				ReloadFile:    reloadFiles,
+				PollFrequency: c.args.PollFrequency,
+			})
+		case filedetector.DetectorFSNotify:
+			detector, err = filedetector.NewFSNotify(filedetector.FSNotifyOptions{
+				Logger:        c.opts.Logger,
+				Filename:      filename,
+				ReloadFile:    reloadFiles,
+				PollFrequency: c.args.PollFrequency,
+			})
+		}
+		if err != nil {
+			c.closeDetectors()
+			return err
+		}
+		c.detectors = append(c.detectors, detector)
+	}
+	return nil
+}
+
+// closeDetectors closes the detectors. mut must be held when called.
+func (c *Component) closeDetectors() {
+	for _, d := range c.detectors {
+		if err := d.Close(); err != nil {
+			level.Error(c.opts.Logger).Log("msg", "failed to shut down detector", "err", err)
+		}
+	}
+	c.detectors = nil
+}
+
+// CurrentHealth implements component.HealthComponent.
+func (c *Component) CurrentHealth() component.Health {
+	c.healthMut.RLock()
+	defer c.healthMut.RUnlock()
+	return c.health
+}
+
+func (c *Component) setHealth(h component.Health) {
+	c.healthMut.Lock()
+	defer c.healthMut.Unlock()
+	c.health = h
+}
@@ -0,0 +1,140 @@
+package tls_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/local/tls"
+	filedetector "github.com/grafana/alloy/internal/filedetector"
+	"github.com/grafana/alloy/internal/runtime/componenttest"
+	"github.com/grafana/alloy/syntax"
+	"github.com/grafana/alloy/syntax/alloytypes"
+)
+
+func TestTLS(t *testing.T) {
+	t.Run("Polling change detector", func(t *testing.T) {
+		runTLSTests(t, filedetector.DetectorPoll)
+	})
+
+	t.Run("Event change detector", func(t *testing.T) {
+		runTLSTests(t, filedetector.DetectorFSNotify)
+	})
+}
+
+// runTLSTests will run a suite of tests with the configured update type.
+func runTLSTests(t *testing.T, ut filedetector.Detector) {
+	dir := t.TempDir()
+	caFile, certFile, keyFile := filepath.Join(dir, "ca.pem"), filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+
+	ca, _ := generateCert(t)
+	cert, key := generateCert(t)
+	require.NoError(t, os.WriteFile(caFile, ca, 0664))
+	require.NoError(t, os.WriteFile(certFile, cert, 0664))
+	require.NoError(t, os.WriteFile(keyFile, key, 0600))
+
+	tc, err := componenttest.NewControllerFromID(nil, "local.tls")
+	require.NoError(t, err)
+	go func() {
+		args := tls.DefaultArguments
+		args.CAFile = caFile
+		args.CertFile = certFile
+		args.KeyFile = keyFile
+		args.Type = ut
+		args.PollFrequency = 50 * time.Millisecond
+		require.NoError(t, tc.Run(componenttest.TestContext(t), args))
+	}()
+
+	require.NoError(t, tc.WaitRunning(time.Second))
+	require.NoError(t, tc.WaitExports(time.Second))
+	require.Equal(t, tls.Exports{
+		CA:         string(ca),
+		Cert:       string(cert),
+		Key:        alloytypes.Secret(key),
+		MinVersion: "TLS12",
+	}, tc.Exports())
+
+	// A certificate which doesn't match the key isn't exported.
+	newCert, newKey := generateCert(t)
+	c, err := tc.GetComponent()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(certFile, newCert, 0664))
+	require.Eventually(t, func() bool {
+		return c.(component.HealthComponent).CurrentHealth().Health == component.HealthTypeUnhealthy
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal(t, string(cert), tc.Exports().(tls.Exports).Cert)
+
+	// The rotated certificate is exported once its key is written.
+	require.NoError(t, os.WriteFile(keyFile, newKey, 0600))
+	require.Eventually(t, func() bool {
+		e := tc.Exports().(tls.Exports)
+		return e.Cert == string(newCert) && e.Key == alloytypes.Secret(newKey)
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestArgumentsValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name:   "no files",
+			config: `min_version = "TLS13"`,
+			err:    "at least one of ca_file or cert_file and key_file must be configured",
+		},
+		{
+			name:   "cert without key",
+			config: `cert_file = "cert.pem"`,
+			err:    "cert_file and key_file must be configured together",
+		},
+		{
+			name: "invalid poll frequency",
+			config: `
+				ca_file        = "ca.pem"
+				poll_frequency = "0s"
+			`,
+			err: "poll_frequency must be greater than 0",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args tls.Arguments
+			require.ErrorContains(t, syntax.Unmarshal([]byte(tt.config), &args), tt.err)
+		})
+	}
+}
+
+// generateCert returns a self-signed certificate and its key.
+func generateCert(t *testing.T) ([]byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "alloy"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
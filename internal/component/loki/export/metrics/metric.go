@@ -0,0 +1,261 @@
+package metrics
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grafana/loki/v3/clients/pkg/logentry/logql"
+	prometheus_client "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+
+	"github.com/grafana/alloy/internal/component/common/loki"
+)
+
+// Counter counts the log entries matching a selector, or sums a value
+// extracted from them.
+type Counter struct {
+	Name       string   `alloy:"name,attr"`
+	Selector   string   `alloy:"selector,attr"`
+	Labels     []string `alloy:"labels,attr,optional"`
+	ValueRegex string   `alloy:"value_regex,attr,optional"`
+}
+
+// Validate implements syntax.Validator.
+func (c *Counter) Validate() error {
+	return validateMetric(c.Name, c.Selector, c.Labels, c.ValueRegex)
+}
+
+// Histogram observes a value extracted from the log entries matching a
+// selector.
+type Histogram struct {
+	Name       string    `alloy:"name,attr"`
+	Selector   string    `alloy:"selector,attr"`
+	Labels     []string  `alloy:"labels,attr,optional"`
+	ValueRegex string    `alloy:"value_regex,attr"`
+	Buckets    []float64 `alloy:"buckets,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (h *Histogram) SetToDefault() {
+	*h = Histogram{
+		Buckets: prometheus_client.DefBuckets,
+	}
+}
+
+// Validate implements syntax.Validator.
+func (h *Histogram) Validate() error {
+	if err := validateMetric(h.Name, h.Selector, h.Labels, h.ValueRegex); err != nil {
+		return err
+	}
+	if len(h.Buckets) == 0 {
+		return fmt.Errorf("histogram %q must have at least one bucket", h.Name)
+	}
+	for i := 1; i < len(h.Buckets); i++ {
+		if h.Buckets[i] <= h.Buckets[i-1] {
+			return fmt.Errorf("buckets of histogram %q must be in increasing order", h.Name)
+		}
+	}
+	return nil
+}
+
+func validateMetric(name, selector string, lbls []string, valueRegex string) error {
+	if !model.IsValidLegacyMetricName(name) {
+		return fmt.Errorf("invalid metric name %q", name)
+	}
+	if _, err := logql.ParseExpr(selector); err != nil {
+		return fmt.Errorf("invalid selector of metric %q: %w", name, err)
+	}
+	for _, l := range lbls {
+		if !model.LabelName(l).IsValid() || l == model.MetricNameLabel {
+			return fmt.Errorf("invalid label name %q in metric %q", l, name)
+		}
+	}
+	if valueRegex != "" {
+		re, err := regexp.Compile(valueRegex)
+		if err != nil {
+			return fmt.Errorf("invalid value_regex of metric %q: %w", name, err)
+		}
+		if re.NumSubexp() == 0 {
+			return fmt.Errorf("value_regex of metric %q must have a capture group", name)
+		}
+	}
+	return nil
+}
+
+// metric holds the series of a Counter or a Histogram.
+type metric struct {
+	name     string
+	typ      model.MetricType
+	matchers []*labels.Matcher
+	filter   logql.Filter
+	labels   []string
+	valueRe  *regexp.Regexp
+	buckets  []float64 // Only set for histograms.
+
+	series map[uint64]*series
+}
+
+// series is a set of log entries with the same metric labels.
+type series struct {
+	labels  labels.Labels // Without the metric name.
+	updated time.Time
+
+	// Total of a counter, or sum of the values observed by a histogram.
+	sum float64
+	// Number of values observed in each bucket of a histogram, and in total.
+	buckets []uint64
+	count   uint64
+}
+
+func newMetric(name string, typ model.MetricType, selector string, lbls []string, valueRegex string, buckets []float64) (*metric, error) {
+	expr, err := logql.ParseExpr(selector)
+	if err != nil {
+		return nil, err
+	}
+	filter, err := expr.Filter()
+	if err != nil {
+		return nil, err
+	}
+
+	m := &metric{
+		name:     name,
+		typ:      typ,
+		matchers: expr.Matchers(),
+		filter:   filter,
+		labels:   lbls,
+		buckets:  buckets,
+		series:   make(map[uint64]*series),
+	}
+	if valueRegex != "" {
+		if m.valueRe, err = regexp.Compile(valueRegex); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+func newCounter(c Counter) (*metric, error) {
+	return newMetric(c.Name, model.MetricTypeCounter, c.Selector, c.Labels, c.ValueRegex, nil)
+}
+
+func newHistogram(h Histogram) (*metric, error) {
+	return newMetric(h.Name, model.MetricTypeHistogram, h.Selector, h.Labels, h.ValueRegex, h.Buckets)
+}
+
+// matches returns whether the entry matches the selector of the metric.
+func (m *metric) matches(e loki.Entry) bool {
+	for _, matcher := range m.matchers {
+		if !matcher.Matches(string(e.Labels[model.LabelName(matcher.Name)])) {
+			return false
+		}
+	}
+	return m.filter == nil || m.filter([]byte(e.Line))
+}
+
+// observe records an entry which matches the metric. It returns false if
+// the value of the entry can't be extracted.
+func (m *metric) observe(e loki.Entry, now time.Time) bool {
+	v := 1.0
+	if m.valueRe != nil {
+		match := m.valueRe.FindStringSubmatch(e.Line)
+		if len(match) < 2 {
+			return false
+		}
+		var err error
+		if v, err = strconv.ParseFloat(match[1], 64); err != nil || math.IsNaN(v) {
+			return false
+		}
+		// Counters can't decrease.
+		if m.typ == model.MetricTypeCounter && v < 0 {
+			return false
+		}
+	}
+
+	lbls := m.seriesLabels(e.Labels)
+	hash := lbls.Hash()
+	s, ok := m.series[hash]
+	if !ok {
+		s = &series{labels: lbls}
+		if m.typ == model.MetricTypeHistogram {
+			s.buckets = make([]uint64, len(m.buckets))
+		}
+		m.series[hash] = s
+	}
+	s.updated = now
+	s.sum += v
+	if m.typ == model.MetricTypeHistogram {
+		s.count++
+		if i := sort.SearchFloat64s(m.buckets, v); i < len(m.buckets) {
+			s.buckets[i]++
+		}
+	}
+	return true
+}
+
+// seriesLabels returns the labels of the series of an entry: the labels of
+// the metric, or every label of the entry if the metric has none.
+func (m *metric) seriesLabels(entryLabels model.LabelSet) labels.Labels {
+	b := labels.NewScratchBuilder(len(entryLabels))
+	if len(m.labels) == 0 {
+		for name, value := range entryLabels {
+			b.Add(string(name), string(value))
+		}
+	} else {
+		for _, name := range m.labels {
+			if value, ok := entryLabels[model.LabelName(name)]; ok {
+				b.Add(name, string(value))
+			}
+		}
+	}
+	b.Sort()
+	return b.Labels()
+}
+
+// sample is a sample of a series to write.
+type sample struct {
+	labels labels.Labels
+	value  float64
+}
+
+// samples returns the samples of a series, with extraLabels added. If stale
+// is true, the samples are staleness markers.
+func (m *metric) samples(s *series, extraLabels labels.Labels, stale bool) []sample {
+	build := func(name string, extra ...string) labels.Labels {
+		b := labels.NewBuilder(s.labels)
+		extraLabels.Range(func(l labels.Label) { b.Set(l.Name, l.Value) })
+		for i := 0; i+1 < len(extra); i += 2 {
+			b.Set(extra[i], extra[i+1])
+		}
+		b.Set(model.MetricNameLabel, name)
+		return b.Labels()
+	}
+
+	var samples []sample
+	if m.typ == model.MetricTypeCounter {
+		samples = []sample{{labels: build(m.name), value: s.sum}}
+	} else {
+		var cumulative uint64
+		for i, upper := range m.buckets {
+			cumulative += s.buckets[i]
+			samples = append(samples, sample{labels: build(m.name+"_bucket", model.BucketLabel, strconv.FormatFloat(upper, 'f', -1, 64)), value: float64(cumulative)})
+		}
+		samples = append(samples,
+			sample{labels: build(m.name+"_bucket", model.BucketLabel, "+Inf"), value: float64(s.count)},
+			sample{labels: build(m.name + "_sum"), value: s.sum},
+			sample{labels: build(m.name + "_count"), value: float64(s.count)},
+		)
+	}
+
+	if stale {
+		for i := range samples {
+			samples[i].value = math.Float64frombits(value.StaleNaN)
+		}
+	}
+	return samples
+}
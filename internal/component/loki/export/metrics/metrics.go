@@ -0,0 +1,333 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+
+	prometheus_client "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/storage"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/prometheus"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/service/cluster"
+	"github.com/grafana/alloy/internal/service/labelstore"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "loki.export.metrics",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   Exports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments holds values which are used to configure the loki.export.metrics
+// component.
+type Arguments struct {
+	// Where the metrics should be forwarded to.
+	ForwardTo []storage.Appendable `alloy:"forward_to,attr"`
+
+	// How often the samples of the metrics are written.
+	Interval time.Duration `alloy:"interval,attr,optional"`
+	// How long a series can go without matching entries before it's marked
+	// stale and removed.
+	MaxIdleDuration time.Duration `alloy:"max_idle_duration,attr,optional"`
+	// Label set to the name of the cluster node on every series.
+	InstanceLabel string `alloy:"instance_label,attr,optional"`
+
+	Counters   []Counter   `alloy:"counter,block,optional"`
+	Histograms []Histogram `alloy:"histogram,block,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{
+		Interval:        time.Minute,
+		MaxIdleDuration: 5 * time.Minute,
+	}
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if args.Interval <= 0 {
+		return fmt.Errorf("interval must be greater than 0")
+	}
+	if args.MaxIdleDuration < args.Interval {
+		return fmt.Errorf("max_idle_duration must be greater than or equal to interval")
+	}
+	if args.InstanceLabel != "" && (!model.LabelName(args.InstanceLabel).IsValid() || args.InstanceLabel == model.MetricNameLabel) {
+		return fmt.Errorf("invalid instance_label %q", args.InstanceLabel)
+	}
+
+	names := make(map[string]struct{})
+	for _, name := range args.metricNames() {
+		if _, ok := names[name]; ok {
+			return fmt.Errorf("metric %q is defined more than once", name)
+		}
+		names[name] = struct{}{}
+	}
+	return nil
+}
+
+func (args *Arguments) metricNames() []string {
+	var names []string
+	for _, c := range args.Counters {
+		names = append(names, c.Name)
+	}
+	for _, h := range args.Histograms {
+		names = append(names, h.Name)
+	}
+	return names
+}
+
+// Exports holds values which are exported by the loki.export.metrics
+// component.
+type Exports struct {
+	Receiver loki.LogsReceiver `alloy:"receiver,attr"`
+}
+
+// Component implements the loki.export.metrics component.
+type Component struct {
+	opts     component.Options
+	cluster  cluster.Cluster
+	receiver loki.LogsReceiver
+	fanout   *prometheus.Fanout
+	updated  chan struct{}
+
+	entriesMatched prometheus_client.Counter
+	entriesSkipped prometheus_client.Counter
+	samplesWritten prometheus_client.Counter
+
+	mut     sync.Mutex
+	args    Arguments
+	metrics map[string]*metric
+	configs map[string]any // Counter or Histogram of each metric.
+}
+
+var _ component.Component = (*Component)(nil)
+
+// New creates a new loki.export.metrics component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	data, err := o.GetServiceData(labelstore.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+	ls := data.(labelstore.LabelStore)
+
+	data, err = o.GetServiceData(cluster.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Component{
+		opts:     o,
+		cluster:  data.(cluster.Cluster),
+		receiver: loki.NewLogsReceiver(),
+		fanout:   prometheus.NewFanout(args.ForwardTo, o.ID, o.Registerer, ls),
+		updated:  make(chan struct{}, 1),
+		metrics:  make(map[string]*metric),
+		configs:  make(map[string]any),
+	}
+	c.entriesMatched = prometheus_client.NewCounter(prometheus_client.CounterOpts{
+		Name: "loki_export_metrics_entries_matched_total",
+		Help: "Total number of log entries matching the selector of a metric.",
+	})
+	c.entriesSkipped = prometheus_client.NewCounter(prometheus_client.CounterOpts{
+		Name: "loki_export_metrics_entries_skipped_total",
+		Help: "Total number of log entries matching the selector of a metric whose value couldn't be extracted.",
+	})
+	c.samplesWritten = prometheus_client.NewCounter(prometheus_client.CounterOpts{
+		Name: "loki_export_metrics_samples_written_total",
+		Help: "Total number of samples written.",
+	})
+	for _, metric := range []prometheus_client.Collector{c.entriesMatched, c.entriesSkipped, c.samplesWritten} {
+		if err := o.Registerer.Register(metric); err != nil {
+			return nil, err
+		}
+	}
+
+	// Immediately export the receiver which remains the same for the component
+	// lifetime.
+	o.OnStateChange(Exports{Receiver: c.receiver})
+
+	if err := c.Update(args); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Run implements component.Component.
+func (c *Component) Run(ctx context.Context) error {
+	ticker := time.NewTicker(c.getInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Mark every series stale, since they won't be written anymore.
+			c.flush(context.Background(), true)
+			return nil
+		case <-c.updated:
+			ticker.Reset(c.getInterval())
+		case entry := <-c.receiver.Chan():
+			c.observe(entry)
+		case <-ticker.C:
+			c.flush(ctx, false)
+		}
+	}
+}
+
+// Update implements component.Component.
+func (c *Component) Update(args component.Arguments) error {
+	newArgs := args.(Arguments)
+
+	newMetrics := make(map[string]*metric)
+	newConfigs := make(map[string]any)
+	add := func(name string, cfg any, build func() (*metric, error)) error {
+		newConfigs[name] = cfg
+		// Keep the series of the metrics which didn't change.
+		if m, ok := c.metrics[name]; ok && reflect.DeepEqual(c.configs[name], cfg) && c.args.InstanceLabel == newArgs.InstanceLabel {
+			newMetrics[name] = m
+			return nil
+		}
+		m, err := build()
+		if err != nil {
+			return fmt.Errorf("invalid metric %q: %w", name, err)
+		}
+		newMetrics[name] = m
+		return nil
+	}
+
+	c.mut.Lock()
+	for _, cfg := range newArgs.Counters {
+		if err := add(cfg.Name, cfg, func() (*metric, error) { return newCounter(cfg) }); err != nil {
+			c.mut.Unlock()
+			return err
+		}
+	}
+	for _, cfg := range newArgs.Histograms {
+		if err := add(cfg.Name, cfg, func() (*metric, error) { return newHistogram(cfg) }); err != nil {
+			c.mut.Unlock()
+			return err
+		}
+	}
+
+	// The series of the metrics which changed or were removed won't be
+	// written anymore, so they're marked stale.
+	var stale []sample
+	extraLabels := c.extraLabels()
+	for name, m := range c.metrics {
+		if newMetrics[name] == m {
+			continue
+		}
+		for _, s := range m.series {
+			stale = append(stale, m.samples(s, extraLabels, true)...)
+		}
+	}
+
+	c.args = newArgs
+	c.metrics = newMetrics
+	c.configs = newConfigs
+	c.mut.Unlock()
+
+	c.fanout.UpdateChildren(newArgs.ForwardTo)
+	c.write(context.Background(), stale)
+
+	select {
+	case c.updated <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (c *Component) getInterval() time.Duration {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return c.args.Interval
+}
+
+// observe records an entry in the metrics whose selector it matches.
+func (c *Component) observe(e loki.Entry) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	now := time.Now()
+	for _, m := range c.metrics {
+		if !m.matches(e) {
+			continue
+		}
+		c.entriesMatched.Inc()
+		if !m.observe(e, now) {
+			c.entriesSkipped.Inc()
+		}
+	}
+}
+
+// extraLabels returns the labels added to every series. The mutex must be
+// held.
+func (c *Component) extraLabels() labels.Labels {
+	if c.args.InstanceLabel == "" {
+		return labels.EmptyLabels()
+	}
+	for _, p := range c.cluster.Peers() {
+		if p.Self {
+			return labels.FromStrings(c.args.InstanceLabel, p.Name)
+		}
+	}
+	return labels.EmptyLabels()
+}
+
+// flush writes the samples of every series. The series which didn't match
+// any entry for max_idle_duration, or every series if final is true, are
+// marked stale and removed.
+func (c *Component) flush(ctx context.Context, final bool) {
+	c.mut.Lock()
+	now := time.Now()
+	extraLabels := c.extraLabels()
+	var samples []sample
+	for _, m := range c.metrics {
+		for hash, s := range m.series {
+			stale := final || now.Sub(s.updated) >= c.args.MaxIdleDuration
+			samples = append(samples, m.samples(s, extraLabels, stale)...)
+			if stale {
+				delete(m.series, hash)
+			}
+		}
+	}
+	c.mut.Unlock()
+
+	c.write(ctx, samples)
+}
+
+func (c *Component) write(ctx context.Context, samples []sample) {
+	if len(samples) == 0 {
+		return
+	}
+
+	ts := timestamp.FromTime(time.Now())
+	app := c.fanout.Appender(ctx)
+	for _, s := range samples {
+		if _, err := app.Append(0, s.labels, ts, s.value); err != nil {
+			level.Warn(c.opts.Logger).Log("msg", "failed to write sample", "labels", s.labels, "err", err)
+			continue
+		}
+		c.samplesWritten.Inc()
+	}
+	if err := app.Commit(); err != nil {
+		level.Warn(c.opts.Logger).Log("msg", "failed to commit samples", "err", err)
+	}
+}
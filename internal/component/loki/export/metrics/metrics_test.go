@@ -0,0 +1,313 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"testing"
+	"time"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/service/cluster"
+	"github.com/grafana/alloy/internal/service/labelstore"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/internal/util/testappender"
+	"github.com/grafana/alloy/syntax"
+	"github.com/grafana/loki/pkg/push"
+)
+
+func TestMetrics(t *testing.T) {
+	collector := testappender.NewCollectingAppender()
+	c := newTestComponent(t, collector, `
+		forward_to = []
+		counter {
+			name     = "http_requests_total"
+			selector = "{job=\"nginx\"} |= \"GET\""
+			labels   = ["job", "status"]
+		}
+		counter {
+			name        = "http_response_bytes_total"
+			selector    = "{job=\"nginx\"}"
+			labels      = ["job"]
+			value_regex = "bytes=(\\d+)"
+		}
+		histogram {
+			name        = "http_request_duration_seconds"
+			selector    = "{job=\"nginx\"}"
+			labels      = ["job"]
+			value_regex = "duration=([0-9.]+)"
+			buckets     = [0.1, 1]
+		}
+	`)
+
+	for _, e := range []loki.Entry{
+		newEntry(`GET /a bytes=100 duration=0.05`, "job", "nginx", "status", "200"),
+		newEntry(`GET /b bytes=200 duration=0.5`, "job", "nginx", "status", "200"),
+		newEntry(`POST /c bytes=50 duration=2`, "job", "nginx", "status", "500"),
+		newEntry(`GET /d`, "job", "nginx", "status", "404"),
+		newEntry(`GET /e bytes=1 duration=0.1`, "job", "mysql"),
+	} {
+		c.observe(e)
+	}
+
+	c.flush(t.Context(), false)
+	expect := map[string]float64{
+		`{__name__="http_requests_total", job="nginx", status="200"}`:               2,
+		`{__name__="http_requests_total", job="nginx", status="404"}`:               1,
+		`{__name__="http_response_bytes_total", job="nginx"}`:                       350,
+		`{__name__="http_request_duration_seconds_bucket", job="nginx", le="0.1"}`:  1,
+		`{__name__="http_request_duration_seconds_bucket", job="nginx", le="1"}`:    2,
+		`{__name__="http_request_duration_seconds_bucket", job="nginx", le="+Inf"}`: 3,
+		`{__name__="http_request_duration_seconds_sum", job="nginx"}`:               2.55,
+		`{__name__="http_request_duration_seconds_count", job="nginx"}`:             3,
+	}
+	require.ElementsMatch(t, slices.Collect(maps.Keys(expect)), sampleLabels(collector))
+	for series, v := range expect {
+		require.InDelta(t, v, collector.LatestSampleFor(series).Value, 1e-9, series)
+	}
+}
+
+func TestMetrics_AllLabels(t *testing.T) {
+	collector := testappender.NewCollectingAppender()
+	c := newTestComponent(t, collector, `
+		forward_to     = []
+		instance_label = "instance"
+		counter {
+			name     = "errors_total"
+			selector = "{level=\"error\"}"
+		}
+	`)
+
+	c.observe(newEntry("failed", "level", "error", "app", "api"))
+	c.observe(newEntry("ok", "level", "info", "app", "api"))
+	c.flush(t.Context(), false)
+
+	require.Equal(t, []string{
+		`{__name__="errors_total", app="api", instance="self", level="error"}`,
+	}, sampleLabels(collector))
+}
+
+func TestMetrics_Staleness(t *testing.T) {
+	collector := testappender.NewCollectingAppender()
+	c := newTestComponent(t, collector, `
+		forward_to = []
+		counter {
+			name     = "requests_total"
+			selector = "{job=\"api\"}"
+			labels   = ["path"]
+		}
+		counter {
+			name     = "errors_total"
+			selector = "{job=\"worker\"} |= \"error\""
+		}
+	`)
+
+	c.observe(newEntry("GET", "job", "api", "path", "/a"))
+	c.observe(newEntry("GET", "job", "api", "path", "/b"))
+	c.observe(newEntry("error", "job", "worker"))
+
+	// Series which didn't match an entry for max_idle_duration are marked
+	// stale and removed.
+	c.mut.Lock()
+	for _, s := range c.metrics["requests_total"].series {
+		if s.labels.Get("path") == "/b" {
+			s.updated = time.Now().Add(-time.Hour)
+		}
+	}
+	c.mut.Unlock()
+
+	c.flush(t.Context(), false)
+	require.Equal(t, 1.0, collector.LatestSampleFor(`{__name__="requests_total", path="/a"}`).Value)
+	requireStale(t, collector, `{__name__="requests_total", path="/b"}`)
+	require.Len(t, c.metrics["requests_total"].series, 1)
+
+	// Updating the arguments keeps the series of unchanged metrics, and marks
+	// the series of the removed metrics stale.
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(`
+		forward_to = []
+		counter {
+			name     = "requests_total"
+			selector = "{job=\"api\"}"
+			labels   = ["path"]
+		}
+	`), &args))
+	args.ForwardTo = []storage.Appendable{testappender.ConstantAppendable{Inner: collector}}
+	require.NoError(t, c.Update(args))
+	requireStale(t, collector, `{__name__="errors_total", job="worker"}`)
+
+	c.observe(newEntry("GET", "job", "api", "path", "/a"))
+	c.flush(t.Context(), false)
+	require.Equal(t, 2.0, collector.LatestSampleFor(`{__name__="requests_total", path="/a"}`).Value)
+
+	// Every series is marked stale when the component stops.
+	c.flush(context.Background(), true)
+	requireStale(t, collector, `{__name__="requests_total", path="/a"}`)
+}
+
+func TestMetrics_Receiver(t *testing.T) {
+	collector := testappender.NewCollectingAppender()
+	c := newTestComponent(t, collector, `
+		forward_to = []
+		interval   = "10ms"
+		counter {
+			name     = "lines_total"
+			selector = "{job=\"api\"}"
+		}
+	`)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		require.NoError(t, c.Run(ctx))
+	}()
+
+	c.receiver.Chan() <- newEntry("hello", "job", "api")
+	require.Eventually(t, func() bool {
+		s := collector.LatestSampleFor(`{__name__="lines_total", job="api"}`)
+		return s != nil && s.Value == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	cancel()
+	<-done
+	requireStale(t, collector, `{__name__="lines_total", job="api"}`)
+}
+
+func TestArgumentsValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name: "duplicate metric",
+			config: `
+				forward_to = []
+				counter {
+					name     = "a"
+					selector = "{job=\"a\"}"
+				}
+				histogram {
+					name        = "a"
+					selector    = "{job=\"a\"}"
+					value_regex = "(.*)"
+				}
+			`,
+			err: `metric "a" is defined more than once`,
+		},
+		{
+			name: "invalid selector",
+			config: `
+				forward_to = []
+				counter {
+					name     = "a"
+					selector = "job=a"
+				}
+			`,
+			err: `invalid selector of metric "a"`,
+		},
+		{
+			name: "regex without capture group",
+			config: `
+				forward_to = []
+				counter {
+					name        = "a"
+					selector    = "{job=\"a\"}"
+					value_regex = "bytes=\\d+"
+				}
+			`,
+			err: `value_regex of metric "a" must have a capture group`,
+		},
+		{
+			name: "unsorted buckets",
+			config: `
+				forward_to = []
+				histogram {
+					name        = "a"
+					selector    = "{job=\"a\"}"
+					value_regex = "(.*)"
+					buckets     = [1, 0.5]
+				}
+			`,
+			err: `buckets of histogram "a" must be in increasing order`,
+		},
+		{
+			name: "idle duration shorter than interval",
+			config: `
+				forward_to        = []
+				interval          = "1m"
+				max_idle_duration = "30s"
+			`,
+			err: "max_idle_duration must be greater than or equal to interval",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tt.config), &args)
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func newTestComponent(t *testing.T, app testappender.CollectingAppender, config string) *Component {
+	t.Helper()
+
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(config), &args))
+	args.ForwardTo = []storage.Appendable{testappender.ConstantAppendable{Inner: app}}
+
+	c, err := New(component.Options{
+		ID:             "loki.export.metrics.test",
+		Logger:         util.TestAlloyLogger(t),
+		OnStateChange:  func(e component.Exports) {},
+		Registerer:     prom.NewRegistry(),
+		GetServiceData: getServiceData,
+	}, args)
+	require.NoError(t, err)
+	return c
+}
+
+func getServiceData(name string) (interface{}, error) {
+	switch name {
+	case labelstore.ServiceName:
+		return labelstore.New(nil, prom.DefaultRegisterer), nil
+	case cluster.ServiceName:
+		return cluster.Mock(), nil
+	default:
+		return nil, fmt.Errorf("service not found %s", name)
+	}
+}
+
+func newEntry(line string, lbls ...string) loki.Entry {
+	ls := model.LabelSet{}
+	for i := 0; i+1 < len(lbls); i += 2 {
+		ls[model.LabelName(lbls[i])] = model.LabelValue(lbls[i+1])
+	}
+	return loki.Entry{Labels: ls, Entry: push.Entry{Timestamp: time.Now(), Line: line}}
+}
+
+func requireStale(t *testing.T, app testappender.CollectingAppender, series string) {
+	t.Helper()
+	s := app.LatestSampleFor(series)
+	require.NotNil(t, s, series)
+	require.True(t, value.IsStaleNaN(s.Value), series)
+}
+
+func sampleLabels(app testappender.CollectingAppender) []string {
+	var res []string
+	for series := range app.CollectedSamples() {
+		res = append(res, series)
+	}
+	slices.Sort(res)
+	return res
+}
@@ -138,14 +138,16 @@ func (c *Component) Update(args component.Arguments) error {
 	// first load. This will allow a component with no stages to function
 	// properly.
 	if stagesChanged(c.stages, newArgs.Stages) || c.stages == nil {
-		if c.entryHandler != nil {
-			c.entryHandler.Stop()
-		}
-
+		// The new pipeline is created before the previous one is stopped, so
+		// that the resources shared by their stages, like the pod cache of
+		// stage.enrich_kubernetes, are kept.
 		pipeline, err := stages.NewPipeline(c.opts.Logger, newArgs.Stages, &c.opts.ID, c.opts.Registerer, c.opts.MinStability)
 		if err != nil {
 			return err
 		}
+		if c.entryHandler != nil {
+			c.entryHandler.Stop()
+		}
 		entryHandler := loki.NewEntryHandler(c.processOut, func() { pipeline.Cleanup() })
 		c.entryHandler = pipeline.Wrap(entryHandler)
 		c.processIn = c.entryHandler.Chan()
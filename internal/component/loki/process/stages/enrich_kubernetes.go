@@ -0,0 +1,327 @@
+package stages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/prometheus/util/strutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/grafana/alloy/internal/component/common/kubernetes"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+// Fields of the pod to look up an entry's pod by.
+const (
+	EnrichKubernetesLookupByIP  = "ip"
+	EnrichKubernetesLookupByUID = "uid"
+)
+
+// Names of the fields written to the extracted data by the enrich_kubernetes
+// stage.
+const (
+	enrichKubernetesNamespace        = "kubernetes_namespace"
+	enrichKubernetesPodName          = "kubernetes_pod_name"
+	enrichKubernetesPodUID           = "kubernetes_pod_uid"
+	enrichKubernetesNodeName         = "kubernetes_node_name"
+	enrichKubernetesWorkloadKind     = "kubernetes_workload_kind"
+	enrichKubernetesWorkloadName     = "kubernetes_workload_name"
+	enrichKubernetesPodLabelPrefix   = "kubernetes_pod_label_"
+	enrichKubernetesAnnotationPrefix = "kubernetes_pod_annotation_"
+)
+
+var (
+	ErrEmptyEnrichKubernetesSource   = errors.New("source cannot be empty")
+	ErrInvalidEnrichKubernetesLookup = fmt.Errorf("lookup_by must be either %q or %q", EnrichKubernetesLookupByIP, EnrichKubernetesLookupByUID)
+)
+
+// EnrichKubernetesConfig represents the enrich_kubernetes stage config.
+type EnrichKubernetesConfig struct {
+	Source      string                     `alloy:"source,attr"`
+	LookupBy    string                     `alloy:"lookup_by,attr,optional"`
+	NodeName    string                     `alloy:"node_name,attr,optional"`
+	Labels      []string                   `alloy:"labels,attr,optional"`
+	Annotations []string                   `alloy:"annotations,attr,optional"`
+	Client      kubernetes.ClientArguments `alloy:"client,block,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (c *EnrichKubernetesConfig) SetToDefault() {
+	*c = EnrichKubernetesConfig{
+		LookupBy: EnrichKubernetesLookupByIP,
+		Client:   kubernetes.DefaultClientArguments,
+	}
+}
+
+// Validate implements syntax.Validator.
+func (c *EnrichKubernetesConfig) Validate() error {
+	if c.Source == "" {
+		return ErrEmptyEnrichKubernetesSource
+	}
+	if c.LookupBy != EnrichKubernetesLookupByIP && c.LookupBy != EnrichKubernetesLookupByUID {
+		return ErrInvalidEnrichKubernetesLookup
+	}
+	if slices.Contains(c.Labels, "") || slices.Contains(c.Annotations, "") {
+		return errors.New("label and annotation names cannot be empty")
+	}
+	return nil
+}
+
+func newEnrichKubernetesStage(logger log.Logger, config EnrichKubernetesConfig) (Stage, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+
+	pods, err := acquirePodCache(logger, podCacheKey{client: config.Client, nodeName: config.NodeName})
+	if err != nil {
+		return nil, fmt.Errorf("creating the pod cache: %w", err)
+	}
+
+	return &enrichKubernetesStage{
+		logger: log.With(logger, "component", "stage", "type", "enrich_kubernetes"),
+		cfg:    config,
+		pods:   pods,
+	}, nil
+}
+
+type enrichKubernetesStage struct {
+	logger log.Logger
+	cfg    EnrichKubernetesConfig
+	pods   *podCache
+
+	cleanupOnce sync.Once
+}
+
+// Run implements Stage.
+func (s *enrichKubernetesStage) Run(in chan Entry) chan Entry {
+	return RunWith(in, func(e Entry) Entry {
+		s.process(e.Extracted)
+		return e
+	})
+}
+
+// Name implements Stage.
+func (s *enrichKubernetesStage) Name() string {
+	return StageTypeEnrichKubernetes
+}
+
+// Cleanup implements Stage.
+func (s *enrichKubernetesStage) Cleanup() {
+	s.cleanupOnce.Do(s.pods.release)
+}
+
+func (s *enrichKubernetesStage) process(extracted map[string]interface{}) {
+	value, ok := extracted[s.cfg.Source]
+	if !ok {
+		return
+	}
+	key, err := getString(value)
+	if err != nil {
+		level.Debug(s.logger).Log("msg", "failed to convert source value to string", "source", s.cfg.Source, "err", err, "type", reflect.TypeOf(value))
+		return
+	}
+	if s.cfg.LookupBy == EnrichKubernetesLookupByIP {
+		// Addresses of connections, like the ones of syslog messages, may
+		// include the port.
+		if host, _, err := net.SplitHostPort(key); err == nil {
+			key = host
+		}
+	}
+
+	pod := s.pods.lookup(s.cfg.LookupBy, key)
+	if pod == nil {
+		level.Debug(s.logger).Log("msg", "no pod found", "lookup_by", s.cfg.LookupBy, "value", key)
+		return
+	}
+
+	extracted[enrichKubernetesNamespace] = pod.Namespace
+	extracted[enrichKubernetesPodName] = pod.Name
+	extracted[enrichKubernetesPodUID] = string(pod.UID)
+	if pod.Spec.NodeName != "" {
+		extracted[enrichKubernetesNodeName] = pod.Spec.NodeName
+	}
+	if kind, name := podWorkload(pod); kind != "" {
+		extracted[enrichKubernetesWorkloadKind] = kind
+		extracted[enrichKubernetesWorkloadName] = name
+	}
+	for _, name := range s.cfg.Labels {
+		if v, ok := pod.Labels[name]; ok {
+			extracted[enrichKubernetesPodLabelPrefix+strutil.SanitizeLabelName(name)] = v
+		}
+	}
+	for _, name := range s.cfg.Annotations {
+		if v, ok := pod.Annotations[name]; ok {
+			extracted[enrichKubernetesAnnotationPrefix+strutil.SanitizeLabelName(name)] = v
+		}
+	}
+}
+
+// podWorkload returns the kind and name of the workload managing pod. Pods
+// owned by a ReplicaSet created by a Deployment are reported as owned by the
+// Deployment.
+func podWorkload(pod *corev1.Pod) (kind, name string) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return "", ""
+	}
+	if owner.Kind == "ReplicaSet" {
+		if hash := pod.Labels["pod-template-hash"]; hash != "" {
+			if deployment, ok := strings.CutSuffix(owner.Name, "-"+hash); ok && deployment != "" {
+				return "Deployment", deployment
+			}
+		}
+	}
+	return owner.Kind, owner.Name
+}
+
+// podCacheKey identifies the pods watched by a podCache.
+type podCacheKey struct {
+	client   kubernetes.ClientArguments
+	nodeName string
+}
+
+// podCache is an informer cache of pods, indexed by IP and UID. A podCache is
+// shared by the enrich_kubernetes stages with the same client settings and
+// node, so that recreating the stages of a pipeline doesn't list all the pods
+// again.
+type podCache struct {
+	key      podCacheKey
+	refs     int
+	informer cache.SharedIndexInformer
+	cancel   context.CancelFunc
+}
+
+var (
+	podCachesMut sync.Mutex
+	podCaches    []*podCache
+
+	// newKubernetesClient builds the client used by a pod cache. It's
+	// replaced in tests.
+	newKubernetesClient = func(logger log.Logger, args kubernetes.ClientArguments) (k8s.Interface, error) {
+		restConfig, err := args.BuildRESTConfig(logger)
+		if err != nil {
+			return nil, err
+		}
+		return k8s.NewForConfig(restConfig)
+	}
+)
+
+// acquirePodCache returns the pod cache for key, starting a new one if none
+// is running. The pod cache must be released once it's no longer used.
+func acquirePodCache(logger log.Logger, key podCacheKey) (*podCache, error) {
+	podCachesMut.Lock()
+	defer podCachesMut.Unlock()
+
+	for _, c := range podCaches {
+		if reflect.DeepEqual(c.key, key) {
+			c.refs++
+			return c, nil
+		}
+	}
+
+	client, err := newKubernetesClient(logger, key.client)
+	if err != nil {
+		return nil, err
+	}
+	factory := informers.NewSharedInformerFactoryWithOptions(client, 0, informers.WithTweakListOptions(func(lo *metav1.ListOptions) {
+		if key.nodeName != "" {
+			lo.FieldSelector = "spec.nodeName=" + key.nodeName
+		}
+	}))
+	informer := factory.Core().V1().Pods().Informer()
+	err = informer.AddIndexers(cache.Indexers{
+		EnrichKubernetesLookupByIP:  indexPodIPs,
+		EnrichKubernetesLookupByUID: indexPodUID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go informer.Run(ctx.Done())
+
+	c := &podCache{key: key, refs: 1, informer: informer, cancel: cancel}
+	podCaches = append(podCaches, c)
+	return c, nil
+}
+
+// release stops the pod cache once it's released by all its users.
+func (c *podCache) release() {
+	podCachesMut.Lock()
+	defer podCachesMut.Unlock()
+
+	c.refs--
+	if c.refs > 0 {
+		return
+	}
+	c.cancel()
+	podCaches = slices.DeleteFunc(podCaches, func(other *podCache) bool { return other == c })
+}
+
+// lookup returns the pod whose field index, ip or uid, has the given value.
+// Running pods are preferred over the pods which completed and kept their
+// IP.
+func (c *podCache) lookup(index, value string) *corev1.Pod {
+	objs, err := c.informer.GetIndexer().ByIndex(index, value)
+	if err != nil {
+		return nil
+	}
+
+	var res *corev1.Pod
+	for _, obj := range objs {
+		pod, ok := obj.(*corev1.Pod)
+		if ok && (res == nil || preferPod(pod, res)) {
+			res = pod
+		}
+	}
+	return res
+}
+
+// preferPod returns whether a is a better match than b: running pods are
+// preferred, then the most recent pods.
+func preferPod(a, b *corev1.Pod) bool {
+	aRunning, bRunning := a.Status.Phase == corev1.PodRunning, b.Status.Phase == corev1.PodRunning
+	if aRunning != bRunning {
+		return aRunning
+	}
+	return b.CreationTimestamp.Before(&a.CreationTimestamp)
+}
+
+// indexPodIPs indexes pods by IP. Pods using the host network share the IP
+// of their node, so they aren't indexed.
+func indexPodIPs(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok || pod.Spec.HostNetwork {
+		return nil, nil
+	}
+
+	var ips []string
+	if pod.Status.PodIP != "" {
+		ips = append(ips, pod.Status.PodIP)
+	}
+	for _, ip := range pod.Status.PodIPs {
+		if ip.IP != "" && !slices.Contains(ips, ip.IP) {
+			ips = append(ips, ip.IP)
+		}
+	}
+	return ips, nil
+}
+
+func indexPodUID(obj interface{}) ([]string, error) {
+	pod, ok := obj.(*corev1.Pod)
+	if !ok {
+		return nil, nil
+	}
+	return []string{string(pod.UID)}, nil
+}
@@ -0,0 +1,203 @@
+package stages
+
+import (
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+
+	"github.com/grafana/alloy/internal/component/common/kubernetes"
+	"github.com/grafana/alloy/internal/featuregate"
+	util_log "github.com/grafana/loki/v3/pkg/util/log"
+)
+
+var testEnrichKubernetesAlloy = `
+stage.regex {
+	expression = "^(?P<client>\\S+) "
+}
+
+stage.enrich_kubernetes {
+	source      = "client"
+	labels      = ["app.kubernetes.io/name"]
+	annotations = ["team"]
+}
+`
+
+func fakePods(t *testing.T, pods ...*corev1.Pod) {
+	t.Helper()
+
+	objs := make([]*corev1.Pod, len(pods))
+	copy(objs, pods)
+	prev := newKubernetesClient
+	newKubernetesClient = func(log.Logger, kubernetes.ClientArguments) (k8s.Interface, error) {
+		client := fake.NewClientset()
+		for _, pod := range objs {
+			_, err := client.CoreV1().Pods(pod.Namespace).Create(t.Context(), pod, metav1.CreateOptions{})
+			require.NoError(t, err)
+		}
+		return client, nil
+	}
+	t.Cleanup(func() { newKubernetesClient = prev })
+}
+
+func TestEnrichKubernetesPipeline(t *testing.T) {
+	fakePods(t,
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "api-7d4b9c-x2x8z",
+				Namespace:   "shop",
+				UID:         "8e5b7c4a",
+				Labels:      map[string]string{"app.kubernetes.io/name": "api", "pod-template-hash": "7d4b9c"},
+				Annotations: map[string]string{"team": "checkout"},
+				OwnerReferences: []metav1.OwnerReference{
+					{Kind: "ReplicaSet", Name: "api-7d4b9c", Controller: ptr.To(true)},
+				},
+				CreationTimestamp: metav1.NewTime(time.Unix(2000, 0)),
+			},
+			Spec:   corev1.PodSpec{NodeName: "node-1"},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"},
+		},
+		// A completed pod which had the same IP.
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              "migrate-abcde",
+				Namespace:         "shop",
+				UID:               "1f2e3d4c",
+				CreationTimestamp: metav1.NewTime(time.Unix(3000, 0)),
+			},
+			Status: corev1.PodStatus{Phase: corev1.PodSucceeded, PodIP: "10.0.0.1"},
+		},
+		// Pods using the host network aren't indexed by IP.
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "node-exporter", Namespace: "monitoring", UID: "5a6b7c8d"},
+			Spec:       corev1.PodSpec{HostNetwork: true},
+			Status:     corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.1.0.1"},
+		},
+	)
+
+	pl, err := NewPipeline(util_log.Logger, loadConfig(testEnrichKubernetesAlloy), &plName, prometheus.NewRegistry(), featuregate.StabilityExperimental)
+	require.NoError(t, err)
+	defer pl.Cleanup()
+
+	stage := pl.stages[1].(*enrichKubernetesStage)
+	require.Eventually(t, stage.pods.informer.HasSynced, 5*time.Second, 10*time.Millisecond)
+
+	out := processEntries(pl,
+		newEntry(nil, model.LabelSet{}, "10.0.0.1:51234 GET /cart", time.Now()),
+		newEntry(nil, model.LabelSet{}, "10.1.0.1 GET /metrics", time.Now()),
+	)
+	require.Len(t, out, 2)
+
+	require.Equal(t, map[string]interface{}{
+		"client":                                      "10.0.0.1:51234",
+		"kubernetes_namespace":                        "shop",
+		"kubernetes_pod_name":                         "api-7d4b9c-x2x8z",
+		"kubernetes_pod_uid":                          "8e5b7c4a",
+		"kubernetes_node_name":                        "node-1",
+		"kubernetes_workload_kind":                    "Deployment",
+		"kubernetes_workload_name":                    "api",
+		"kubernetes_pod_label_app_kubernetes_io_name": "api",
+		"kubernetes_pod_annotation_team":              "checkout",
+	}, out[0].Extracted)
+	require.Equal(t, map[string]interface{}{"client": "10.1.0.1"}, out[1].Extracted)
+}
+
+func TestEnrichKubernetes_LookupByUID(t *testing.T) {
+	fakePods(t, &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "batch-1-xyz",
+			Namespace: "jobs",
+			UID:       "0a1b2c3d",
+			OwnerReferences: []metav1.OwnerReference{
+				{Kind: "Job", Name: "batch-1", Controller: ptr.To(true)},
+			},
+		},
+	})
+
+	s, err := newEnrichKubernetesStage(util_log.Logger, EnrichKubernetesConfig{Source: "uid", LookupBy: EnrichKubernetesLookupByUID})
+	require.NoError(t, err)
+	defer s.Cleanup()
+	require.Eventually(t, s.(*enrichKubernetesStage).pods.informer.HasSynced, 5*time.Second, 10*time.Millisecond)
+
+	out := processEntries(s, Entry{Extracted: map[string]interface{}{"uid": "0a1b2c3d"}})
+	require.Len(t, out, 1)
+	require.Equal(t, "batch-1-xyz", out[0].Extracted["kubernetes_pod_name"])
+	require.Equal(t, "Job", out[0].Extracted["kubernetes_workload_kind"])
+	require.Equal(t, "batch-1", out[0].Extracted["kubernetes_workload_name"])
+}
+
+func TestEnrichKubernetes_SharedPodCache(t *testing.T) {
+	fakePods(t)
+
+	cfg := EnrichKubernetesConfig{Source: "ip", LookupBy: EnrichKubernetesLookupByIP}
+	a, err := newEnrichKubernetesStage(util_log.Logger, cfg)
+	require.NoError(t, err)
+	b, err := newEnrichKubernetesStage(util_log.Logger, cfg)
+	require.NoError(t, err)
+
+	cfg.NodeName = "node-1"
+	c, err := newEnrichKubernetesStage(util_log.Logger, cfg)
+	require.NoError(t, err)
+
+	require.Same(t, a.(*enrichKubernetesStage).pods, b.(*enrichKubernetesStage).pods)
+	require.NotSame(t, a.(*enrichKubernetesStage).pods, c.(*enrichKubernetesStage).pods)
+	require.Len(t, podCaches, 2)
+
+	a.Cleanup()
+	a.Cleanup() // Cleaning up twice must release the cache once.
+	require.Len(t, podCaches, 2)
+	b.Cleanup()
+	c.Cleanup()
+	require.Empty(t, podCaches)
+}
+
+func TestEnrichKubernetesConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  EnrichKubernetesConfig
+		wantErr string
+	}{
+		{
+			name:    "empty source",
+			config:  EnrichKubernetesConfig{LookupBy: EnrichKubernetesLookupByIP},
+			wantErr: ErrEmptyEnrichKubernetesSource.Error(),
+		},
+		{
+			name:    "invalid lookup",
+			config:  EnrichKubernetesConfig{Source: "ip", LookupBy: "name"},
+			wantErr: ErrInvalidEnrichKubernetesLookup.Error(),
+		},
+		{
+			name:    "empty label",
+			config:  EnrichKubernetesConfig{Source: "ip", LookupBy: EnrichKubernetesLookupByIP, Labels: []string{""}},
+			wantErr: "label and annotation names cannot be empty",
+		},
+		{
+			name:   "valid",
+			config: EnrichKubernetesConfig{Source: "ip", LookupBy: EnrichKubernetesLookupByUID},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.EqualError(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestEnrichKubernetes_Stability(t *testing.T) {
+	_, err := NewPipeline(util_log.Logger, loadConfig(testEnrichKubernetesAlloy), &plName, prometheus.NewRegistry(), featuregate.StabilityGenerallyAvailable)
+	require.ErrorContains(t, err, `stage "enrich_kubernetes" is at stability level "experimental"`)
+}
@@ -16,10 +16,11 @@ import (
 
 // TODO(@tpaschalis) Let's use this as the list of stages we need to port over.
 const (
-	StageTypeCRI        = "cri"
-	StageTypeDecolorize = "decolorize"
-	StageTypeDocker     = "docker"
-	StageTypeDrop       = "drop"
+	StageTypeCRI              = "cri"
+	StageTypeDecolorize       = "decolorize"
+	StageTypeDocker           = "docker"
+	StageTypeDrop             = "drop"
+	StageTypeEnrichKubernetes = "enrich_kubernetes"
 	//TODO(thampiotr): Add support for eventlogmessage stage
 	StageTypeEventLogMessage    = "eventlogmessage"
 	StageTypeGeoIP              = "geoip"
@@ -49,7 +50,8 @@ const (
 
 // Add stages that are not GA. Stages that are not specified here are considered GA.
 var stagesUnstable = map[string]featuregate.Stability{
-	StageTypeWindowsEvent: featuregate.StabilityExperimental,
+	StageTypeEnrichKubernetes: featuregate.StabilityExperimental,
+	StageTypeWindowsEvent:     featuregate.StabilityExperimental,
 }
 
 // Processor takes an existing set of labels, timestamp and log entry and returns either a possibly mutated
@@ -252,6 +254,15 @@ func New(logger log.Logger, jobName *string, cfg StageConfig, registerer prometh
 		}
 	case cfg.SamplingConfig != nil:
 		s = newSamplingStage(logger, *cfg.SamplingConfig, registerer)
+	case cfg.EnrichKubernetesConfig != nil:
+		// Check the stability level before watching the pods of the cluster.
+		if err := checkFeatureStability(StageTypeEnrichKubernetes, minStability); err != nil {
+			return nil, err
+		}
+		s, err = newEnrichKubernetesStage(logger, *cfg.EnrichKubernetesConfig)
+		if err != nil {
+			return nil, err
+		}
 	case cfg.EventLogMessageConfig != nil:
 		s = newEventLogMessageStage(logger, cfg.EventLogMessageConfig)
 	case cfg.WindowsEventConfig != nil:
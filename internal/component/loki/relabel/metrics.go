@@ -11,6 +11,7 @@ type metrics struct {
 	cacheHits        prometheus_client.Counter
 	cacheMisses      prometheus_client.Counter
 	cacheSize        prometheus_client.Gauge
+	cacheEvictions   prometheus_client.Counter
 }
 
 // newMetrics creates a new set of metrics. If reg is non-nil, the metrics
@@ -38,6 +39,10 @@ func newMetrics(reg prometheus_client.Registerer) *metrics {
 		Name: "loki_relabel_cache_size",
 		Help: "Total size of relabel cache",
 	})
+	m.cacheEvictions = prometheus_client.NewCounter(prometheus_client.CounterOpts{
+		Name: "loki_relabel_cache_evictions",
+		Help: "Total number of items evicted from the relabel cache",
+	})
 
 	if reg != nil {
 		m.entriesProcessed = util.MustRegisterOrGet(reg, m.entriesProcessed).(prometheus_client.Counter)
@@ -45,6 +50,7 @@ func newMetrics(reg prometheus_client.Registerer) *metrics {
 		m.cacheMisses = util.MustRegisterOrGet(reg, m.cacheMisses).(prometheus_client.Counter)
 		m.cacheHits = util.MustRegisterOrGet(reg, m.cacheHits).(prometheus_client.Counter)
 		m.cacheSize = util.MustRegisterOrGet(reg, m.cacheSize).(prometheus_client.Gauge)
+		m.cacheEvictions = util.MustRegisterOrGet(reg, m.cacheEvictions).(prometheus_client.Counter)
 	}
 
 	return &m
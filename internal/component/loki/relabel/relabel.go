@@ -2,6 +2,7 @@ package relabel
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 	"sync"
@@ -39,7 +40,8 @@ type Arguments struct {
 	// The relabelling rules to apply to each log entry before it's forwarded.
 	RelabelConfigs []*alloy_relabel.Config `alloy:"rule,block,optional"`
 
-	// The maximum number of items to hold in the component's LRU cache.
+	// The maximum number of items to hold in the component's LRU cache. A
+	// value of 0 disables the cache.
 	MaxCacheSize int `alloy:"max_cache_size,attr,optional"`
 }
 
@@ -54,6 +56,14 @@ func (a *Arguments) SetToDefault() {
 	*a = DefaultArguments
 }
 
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if a.MaxCacheSize < 0 {
+		return errors.New("max_cache_size must not be negative")
+	}
+	return nil
+}
+
 // Exports holds values which are exported by the loki.relabel component.
 type Exports struct {
 	Receiver loki.LogsReceiver   `alloy:"receiver,attr"`
@@ -70,7 +80,7 @@ type Component struct {
 	receiver loki.LogsReceiver
 	fanout   []loki.LogsReceiver
 
-	cache        *lru.Cache
+	cache        *lru.Cache // nil if caching is disabled.
 	maxCacheSize int
 
 	debugDataPublisher livedebugging.DebugDataPublisher
@@ -83,11 +93,6 @@ var (
 
 // New creates a new loki.relabel component.
 func New(o component.Options, args Arguments) (*Component, error) {
-	cache, err := lru.New(args.MaxCacheSize)
-	if err != nil {
-		return nil, err
-	}
-
 	debugDataPublisher, err := o.GetServiceData(livedebugging.ServiceName)
 	if err != nil {
 		return nil, err
@@ -96,8 +101,6 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	c := &Component{
 		opts:               o,
 		metrics:            newMetrics(o.Registerer),
-		cache:              cache,
-		maxCacheSize:       args.MaxCacheSize,
 		debugDataPublisher: debugDataPublisher.(livedebugging.DebugDataPublisher),
 	}
 
@@ -163,16 +166,13 @@ func (c *Component) Update(args component.Arguments) error {
 
 	newArgs := args.(Arguments)
 	newRCS := alloy_relabel.ComponentToPromRelabelConfigs(newArgs.RelabelConfigs)
-	if relabelingChanged(c.rcs, newRCS) {
+	if c.cache != nil && relabelingChanged(c.rcs, newRCS) {
 		level.Debug(c.opts.Logger).Log("msg", "received new relabel configs, purging cache")
 		c.cache.Purge()
 		c.metrics.cacheSize.Set(0)
 	}
-	if newArgs.MaxCacheSize != c.maxCacheSize {
-		evicted := c.cache.Resize(newArgs.MaxCacheSize)
-		if evicted > 0 {
-			level.Debug(c.opts.Logger).Log("msg", "resizing the cache lead to evicting of items", "len_items_evicted", evicted)
-		}
+	if err := c.resizeCache(newArgs.MaxCacheSize); err != nil {
+		return err
 	}
 	c.rcs = newRCS
 	c.fanout = newArgs.ForwardTo
@@ -182,6 +182,39 @@ func (c *Component) Update(args component.Arguments) error {
 	return nil
 }
 
+// resizeCache changes the size of the cache, creating it if caching was
+// disabled and dropping it if size is 0.
+func (c *Component) resizeCache(size int) error {
+	switch {
+	case c.cache != nil && size == c.maxCacheSize:
+		return nil
+	case size == 0:
+		if c.cache != nil {
+			level.Debug(c.opts.Logger).Log("msg", "disabling the cache")
+		}
+		c.cache = nil
+	case c.cache == nil:
+		cache, err := lru.New(size)
+		if err != nil {
+			return err
+		}
+		c.cache = cache
+	default:
+		evicted := c.cache.Resize(size)
+		if evicted > 0 {
+			level.Debug(c.opts.Logger).Log("msg", "resizing the cache lead to evicting of items", "len_items_evicted", evicted)
+			c.metrics.cacheEvictions.Add(float64(evicted))
+		}
+	}
+	c.maxCacheSize = size
+	if c.cache == nil {
+		c.metrics.cacheSize.Set(0)
+	} else {
+		c.metrics.cacheSize.Set(float64(c.cache.Len()))
+	}
+	return nil
+}
+
 func relabelingChanged(prev, next []*relabel.Config) bool {
 	if len(prev) != len(next) {
 		return true
@@ -204,6 +237,14 @@ type cacheItem struct {
 // not have this issue as relabel config rules are only applied to targets.
 // Do we want to use labels.Labels in loki.Entry instead?
 func (c *Component) relabel(e loki.Entry) model.LabelSet {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	// Without a cache, every entry is relabeled as it comes in.
+	if c.cache == nil {
+		return c.process(e)
+	}
+
 	hash := e.Labels.Fingerprint()
 
 	// Let's look in the cache for the hash of the entry's labels.
@@ -232,7 +273,9 @@ func (c *Component) relabel(e loki.Entry) model.LabelSet {
 		val = append(val.([]cacheItem), cacheItem{e.Labels, relabeled})
 	}
 
-	c.cache.Add(hash, val)
+	if evicted := c.cache.Add(hash, val); evicted {
+		c.metrics.cacheEvictions.Inc()
+	}
 	c.metrics.cacheSize.Set(float64(c.cache.Len()))
 
 	return relabeled
@@ -9,6 +9,7 @@ import (
 
 	"github.com/grafana/loki/v3/pkg/logproto"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/relabel"
 	"github.com/stretchr/testify/assert"
@@ -311,6 +312,73 @@ func TestCache(t *testing.T) {
 		actualKeys = append(actualKeys, f)
 	}
 	require.Equal(t, wantKeys, actualKeys)
+	require.Equal(t, 2.0, testutil.ToFloat64(c.metrics.cacheEvictions))
+}
+
+func TestCacheDisabled(t *testing.T) {
+	opts := component.Options{
+		Logger:         util.TestAlloyLogger(t),
+		Registerer:     prometheus.NewRegistry(),
+		OnStateChange:  func(e component.Exports) {},
+		GetServiceData: getServiceData,
+	}
+	args := Arguments{
+		RelabelConfigs: []*alloy_relabel.Config{
+			{
+				SourceLabels: []string{"name"},
+				Regex:        alloy_relabel.Regexp(relabel.MustNewRegexp("(.+)")),
+
+				Action:      "replace",
+				TargetLabel: "env",
+				Replacement: "staging",
+			}},
+		MaxCacheSize: 0,
+	}
+
+	c, err := New(opts, args)
+	require.NoError(t, err)
+	require.Nil(t, c.cache)
+
+	e := getEntry()
+	e.Labels = model.LabelSet{"name": "foo"}
+	want := model.LabelSet{"env": "staging", "name": "foo"}
+	require.Equal(t, want, c.relabel(e))
+	require.Equal(t, want, c.relabel(e))
+	require.Equal(t, 0.0, testutil.ToFloat64(c.metrics.cacheHits))
+	require.Equal(t, 0.0, testutil.ToFloat64(c.metrics.cacheMisses))
+
+	// Enabling the cache creates it.
+	args.MaxCacheSize = 1
+	require.NoError(t, c.Update(args))
+	require.NotNil(t, c.cache)
+	require.Equal(t, want, c.relabel(e))
+	require.Equal(t, want, c.relabel(e))
+	require.Equal(t, 1.0, testutil.ToFloat64(c.metrics.cacheHits))
+
+	e.Labels = model.LabelSet{"name": "bar"}
+	c.relabel(e)
+	require.Equal(t, 1.0, testutil.ToFloat64(c.metrics.cacheEvictions))
+
+	// Disabling the cache drops it.
+	args.MaxCacheSize = 0
+	require.NoError(t, c.Update(args))
+	require.Nil(t, c.cache)
+	require.Equal(t, 0.0, testutil.ToFloat64(c.metrics.cacheSize))
+}
+
+func TestArgumentsValidate(t *testing.T) {
+	var args Arguments
+	err := syntax.Unmarshal([]byte(`
+forward_to     = []
+max_cache_size = -1
+`), &args)
+	require.EqualError(t, err, "max_cache_size must not be negative")
+
+	err = syntax.Unmarshal([]byte(`
+forward_to     = []
+max_cache_size = 0
+`), &args)
+	require.NoError(t, err)
 }
 
 func TestEntrySentToTwoRelabelComponents(t *testing.T) {
@@ -6,6 +6,8 @@ type DebugInfo struct {
 	Error              string                   `alloy:"error,attr,optional"`
 	PrometheusRules    []DebugK8sPrometheusRule `alloy:"prometheus_rule,block,optional"`
 	LokiRuleNamespaces []DebugLokiNamespace     `alloy:"loki_rule_namespace,block,optional"`
+	PlannedChanges     []DebugPlannedChange     `alloy:"planned_change,block,optional"`
+	Conflicts          []DebugConflict          `alloy:"conflict,block,optional"`
 }
 
 type DebugK8sPrometheusRule struct {
@@ -20,8 +22,30 @@ type DebugLokiNamespace struct {
 	NumRuleGroups int    `alloy:"num_rule_groups,attr"`
 }
 
+// DebugPlannedChange is a change to a rule group which isn't applied because
+// dry run mode is enabled.
+type DebugPlannedChange struct {
+	Namespace string `alloy:"namespace,attr"`
+	Group     string `alloy:"group,attr"`
+	Action    string `alloy:"action,attr"`
+}
+
+// DebugConflict is a rule group which has the same name as a rule group in a
+// Loki namespace not managed by the component.
+type DebugConflict struct {
+	Namespace            string `alloy:"namespace,attr"`
+	Group                string `alloy:"group,attr"`
+	ConflictingNamespace string `alloy:"conflicting_namespace,attr"`
+}
+
 func (c *Component) DebugInfo() interface{} {
 	var output DebugInfo
+
+	c.debugMut.RLock()
+	output.PlannedChanges = c.plannedChanges
+	output.Conflicts = c.conflicts
+	c.debugMut.RUnlock()
+
 	for ns := range c.currentState {
 		if !isManagedLokiNamespace(c.args.LokiNameSpacePrefix, ns) {
 			continue
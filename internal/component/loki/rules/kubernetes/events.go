@@ -1,9 +1,12 @@
 package rules
 
 import (
+	"cmp"
 	"context"
 	"fmt"
 	"regexp"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/grafana/alloy/internal/component/common/kubernetes"
@@ -81,13 +84,16 @@ func (c *Component) syncLoki(ctx context.Context) error {
 		return err
 	}
 
-	for ns := range rulesByNamespace {
+	unmanagedState := make(kubernetes.RuleGroupsByNamespace)
+	for ns, groups := range rulesByNamespace {
 		if !isManagedLokiNamespace(c.args.LokiNameSpacePrefix, ns) {
+			unmanagedState[ns] = groups
 			delete(rulesByNamespace, ns)
 		}
 	}
 
 	c.currentState = rulesByNamespace
+	c.unmanagedState = unmanagedState
 
 	return nil
 }
@@ -101,7 +107,15 @@ func (c *Component) reconcileState(ctx context.Context) error {
 		return err
 	}
 
+	c.reportConflicts(findConflicts(desiredState, c.unmanagedState))
+
 	diffs := kubernetes.DiffRuleState(desiredState, c.currentState)
+	if c.args.DryRun {
+		c.reportPlannedChanges(diffs)
+		return nil
+	}
+	c.reportPlannedChanges(nil)
+
 	var result error
 	for ns, diff := range diffs {
 		err = c.applyChanges(ctx, ns, diff)
@@ -114,6 +128,94 @@ func (c *Component) reconcileState(ctx context.Context) error {
 	return result
 }
 
+// findConflicts returns the rule groups which would be synced to Loki while a
+// rule group with the same name exists in a namespace not managed by Alloy.
+func findConflicts(desired, unmanaged kubernetes.RuleGroupsByNamespace) []DebugConflict {
+	unmanagedNamespaces := make(map[string][]string)
+	for ns, groups := range unmanaged {
+		for _, group := range groups {
+			unmanagedNamespaces[group.Name] = append(unmanagedNamespaces[group.Name], ns)
+		}
+	}
+
+	var conflicts []DebugConflict
+	for ns, groups := range desired {
+		for _, group := range groups {
+			for _, unmanagedNs := range unmanagedNamespaces[group.Name] {
+				conflicts = append(conflicts, DebugConflict{
+					Namespace:            ns,
+					Group:                group.Name,
+					ConflictingNamespace: unmanagedNs,
+				})
+			}
+		}
+	}
+
+	slices.SortFunc(conflicts, func(a, b DebugConflict) int {
+		return cmp.Or(
+			strings.Compare(a.Namespace, b.Namespace),
+			strings.Compare(a.Group, b.Group),
+			strings.Compare(a.ConflictingNamespace, b.ConflictingNamespace),
+		)
+	})
+	return conflicts
+}
+
+// reportConflicts logs the conflicts when they change and stores them for the
+// debug information. Conflicting rule groups are still synced.
+func (c *Component) reportConflicts(conflicts []DebugConflict) {
+	c.debugMut.Lock()
+	defer c.debugMut.Unlock()
+
+	if slices.Equal(c.conflicts, conflicts) {
+		return
+	}
+	for _, conflict := range conflicts {
+		level.Warn(c.log).Log(
+			"msg", "rule group conflicts with a rule group not managed by this component",
+			"namespace", conflict.Namespace,
+			"group", conflict.Group,
+			"conflicting_namespace", conflict.ConflictingNamespace,
+		)
+	}
+	c.conflicts = conflicts
+	c.metrics.conflicts.Set(float64(len(conflicts)))
+}
+
+// reportPlannedChanges logs the changes which would be applied to Loki if dry
+// run mode was disabled when they change, and stores them for the debug
+// information. It's called with no changes when dry run mode is disabled.
+func (c *Component) reportPlannedChanges(diffs kubernetes.RuleGroupDiffsByNamespace) {
+	var changes []DebugPlannedChange
+	for ns, diff := range diffs {
+		for _, d := range diff {
+			group := d.Desired.Name
+			if d.Kind == kubernetes.RuleGroupDiffKindRemove {
+				group = d.Actual.Name
+			}
+			changes = append(changes, DebugPlannedChange{
+				Namespace: ns,
+				Group:     group,
+				Action:    string(d.Kind),
+			})
+		}
+	}
+	slices.SortFunc(changes, func(a, b DebugPlannedChange) int {
+		return cmp.Or(strings.Compare(a.Namespace, b.Namespace), strings.Compare(a.Group, b.Group))
+	})
+
+	c.debugMut.Lock()
+	defer c.debugMut.Unlock()
+
+	if slices.Equal(c.plannedChanges, changes) {
+		return
+	}
+	for _, change := range changes {
+		level.Info(c.log).Log("msg", "dry run: rule group not synced", "action", change.Action, "namespace", change.Namespace, "group", change.Group)
+	}
+	c.plannedChanges = changes
+}
+
 func (c *Component) loadStateFromK8s() (kubernetes.RuleGroupsByNamespace, error) {
 	matchedNamespaces, err := c.namespaceLister.List(c.namespaceSelector)
 	if err != nil {
@@ -12,6 +12,7 @@ import (
 	lokiClient "github.com/grafana/alloy/internal/loki/client"
 	v1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
 	promListers "github.com/prometheus-operator/prometheus-operator/pkg/client/listers/monitoring/v1"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/prometheus/model/rulefmt"
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
@@ -184,3 +185,115 @@ func TestEventLoop(t *testing.T) {
 		return len(rules) == 0
 	}, time.Second, 10*time.Millisecond)
 }
+
+func newTestComponent(t *testing.T, args Arguments, rules ...*v1.PrometheusRule) *Component {
+	nsIndexer := cache.NewIndexer(
+		cache.DeletionHandlingMetaNamespaceKeyFunc,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+	ruleIndexer := cache.NewIndexer(
+		cache.DeletionHandlingMetaNamespaceKeyFunc,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+	for _, rule := range rules {
+		require.NoError(t, nsIndexer.Add(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: rule.Namespace}}))
+		require.NoError(t, ruleIndexer.Add(rule))
+	}
+
+	return &Component{
+		log:               log.NewNopLogger(),
+		namespaceLister:   coreListers.NewNamespaceLister(nsIndexer),
+		namespaceSelector: labels.Everything(),
+		ruleLister:        promListers.NewPrometheusRuleLister(ruleIndexer),
+		ruleSelector:      labels.Everything(),
+		lokiClient:        newFakeLokiClient(),
+		args:              args,
+		metrics:           newMetrics(),
+	}
+}
+
+func newTestRule(name string, groups ...string) *v1.PrometheusRule {
+	rule := &v1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "namespace",
+			UID:       types.UID("64aab764-c95e-4ee9-a932-cd63ba57e6cf"),
+		},
+	}
+	for _, group := range groups {
+		rule.Spec.Groups = append(rule.Spec.Groups, v1.RuleGroup{
+			Name: group,
+			Rules: []v1.Rule{
+				{
+					Alert: "alert",
+					Expr:  intstr.FromString("expr"),
+				},
+			},
+		})
+	}
+	return rule
+}
+
+func TestDryRun(t *testing.T) {
+	rule := newTestRule("name", "group1", "group2")
+	component := newTestComponent(t, Arguments{LokiNameSpacePrefix: "alloy", DryRun: true}, rule)
+	ctx := t.Context()
+
+	// A rule group which was previously synced and whose PrometheusRule doesn't exist anymore.
+	removedNs := "alloy-namespace-removed-a9e3bd1c-8b31-4c2e-9a3b-76b0a1d6e2f4"
+	require.NoError(t, component.lokiClient.CreateRuleGroup(ctx, removedNs, rulefmt.RuleGroup{Name: "group3"}))
+
+	require.NoError(t, component.syncLoki(ctx))
+	require.NoError(t, component.reconcileState(ctx))
+
+	ns := lokiNamespaceForRuleCRD("alloy", rule)
+	require.Equal(t, []DebugPlannedChange{
+		{Namespace: ns, Group: "group1", Action: "add"},
+		{Namespace: ns, Group: "group2", Action: "add"},
+		{Namespace: removedNs, Group: "group3", Action: "remove"},
+	}, component.DebugInfo().(DebugInfo).PlannedChanges)
+
+	// Nothing is applied to Loki.
+	rules, err := component.lokiClient.ListRules(ctx, "")
+	require.NoError(t, err)
+	require.Equal(t, map[string][]rulefmt.RuleGroup{
+		removedNs: {{Name: "group3"}},
+	}, rules)
+
+	// The changes are applied once dry run mode is disabled.
+	component.args.DryRun = false
+	require.NoError(t, component.reconcileState(ctx))
+	require.Empty(t, component.DebugInfo().(DebugInfo).PlannedChanges)
+
+	rules, err = component.lokiClient.ListRules(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	require.Len(t, rules[ns], 2)
+}
+
+func TestConflicts(t *testing.T) {
+	rule := newTestRule("name", "group1", "group2")
+	component := newTestComponent(t, Arguments{LokiNameSpacePrefix: "alloy"}, rule)
+	ctx := t.Context()
+
+	// A rule group with the same name, managed outside of Alloy.
+	require.NoError(t, component.lokiClient.CreateRuleGroup(ctx, "team-a", rulefmt.RuleGroup{Name: "group2"}))
+
+	require.NoError(t, component.syncLoki(ctx))
+	require.NoError(t, component.reconcileState(ctx))
+
+	require.Equal(t, []DebugConflict{
+		{
+			Namespace:            lokiNamespaceForRuleCRD("alloy", rule),
+			Group:                "group2",
+			ConflictingNamespace: "team-a",
+		},
+	}, component.DebugInfo().(DebugInfo).Conflicts)
+	require.Equal(t, 1.0, testutil.ToFloat64(component.metrics.conflicts))
+
+	// Conflicting rule groups are still synced, and the unmanaged one is left as is.
+	rules, err := component.lokiClient.ListRules(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, rules, 2)
+	require.Len(t, rules[lokiNamespaceForRuleCRD("alloy", rule)], 2)
+}
@@ -65,7 +65,12 @@ type Component struct {
 	namespaceSelector labels.Selector
 	ruleSelector      labels.Selector
 
-	currentState commonK8s.RuleGroupsByNamespace
+	currentState   commonK8s.RuleGroupsByNamespace
+	unmanagedState commonK8s.RuleGroupsByNamespace
+
+	debugMut       sync.RWMutex
+	plannedChanges []DebugPlannedChange
+	conflicts      []DebugConflict
 
 	metrics   *metrics
 	healthMut sync.RWMutex
@@ -80,6 +85,8 @@ type metrics struct {
 	eventsRetried *prometheus.CounterVec
 
 	lokiClientTiming *prometheus.HistogramVec
+
+	conflicts prometheus.Gauge
 }
 
 func (m *metrics) Register(r prometheus.Registerer) error {
@@ -88,6 +95,7 @@ func (m *metrics) Register(r prometheus.Registerer) error {
 	m.eventsFailed = util.MustRegisterOrGet(r, m.eventsFailed).(*prometheus.CounterVec)
 	m.eventsRetried = util.MustRegisterOrGet(r, m.eventsRetried).(*prometheus.CounterVec)
 	m.lokiClientTiming = util.MustRegisterOrGet(r, m.lokiClientTiming).(*prometheus.HistogramVec)
+	m.conflicts = util.MustRegisterOrGet(r, m.conflicts).(prometheus.Gauge)
 	return nil
 }
 
@@ -119,6 +127,11 @@ func newMetrics() *metrics {
 			Help:      "Duration of requests to the Loki API.",
 			Buckets:   instrument.DefBuckets,
 		}, instrument.HistogramCollectorBuckets),
+		conflicts: prometheus.NewGauge(prometheus.GaugeOpts{
+			Subsystem: "loki_rules",
+			Name:      "conflicts",
+			Help:      "Number of rule groups with the same name as a rule group in a Loki namespace not managed by the component.",
+		}),
 	}
 }
 
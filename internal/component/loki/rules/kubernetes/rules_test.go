@@ -14,6 +14,7 @@ func TestAlloyConfig(t *testing.T) {
 		username = "GRAFANA_CLOUD_USER"
 		password = "GRAFANA_CLOUD_API_KEY"
 	}
+	dry_run = true
 `
 
 	var args Arguments
@@ -15,6 +15,7 @@ type Arguments struct {
 	HTTPClientConfig    config.HTTPClientConfig `alloy:",squash"`
 	SyncInterval        time.Duration           `alloy:"sync_interval,attr,optional"`
 	LokiNameSpacePrefix string                  `alloy:"loki_namespace_prefix,attr,optional"`
+	DryRun              bool                    `alloy:"dry_run,attr,optional"`
 
 	RuleSelector          kubernetes.LabelSelector `alloy:"rule_selector,block,optional"`
 	RuleNamespaceSelector kubernetes.LabelSelector `alloy:"rule_namespace_selector,block,optional"`
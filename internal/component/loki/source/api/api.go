@@ -8,6 +8,7 @@ import (
 
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/limit"
 	fnet "github.com/grafana/alloy/internal/component/common/net"
 	"github.com/grafana/alloy/internal/component/common/relabel"
 	"github.com/grafana/alloy/internal/component/loki/source/api/internal/lokipush"
@@ -34,6 +35,7 @@ type Arguments struct {
 	Labels               map[string]string   `alloy:"labels,attr,optional"`
 	RelabelRules         relabel.Rules       `alloy:"relabel_rules,attr,optional"`
 	UseIncomingTimestamp bool                `alloy:"use_incoming_timestamp,attr,optional"`
+	Limit                *limit.Arguments    `alloy:"limit,block,optional"`
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -55,6 +57,7 @@ type Component struct {
 	opts               component.Options
 	entriesChan        chan loki.Entry
 	uncheckedCollector *util.UncheckedCollector
+	limiter            *limit.Limiter
 
 	serverMut sync.Mutex
 	server    *lokipush.PushAPIServer
@@ -71,6 +74,7 @@ func New(opts component.Options, args Arguments) (*Component, error) {
 		entriesChan:        make(chan loki.Entry),
 		receivers:          args.ForwardTo,
 		uncheckedCollector: util.NewUncheckedCollector(nil),
+		limiter:            limit.NewLimiter(opts.Registerer),
 	}
 	opts.Registerer.MustRegister(c.uncheckedCollector)
 	err := c.Update(args)
@@ -86,6 +90,9 @@ func (c *Component) Run(ctx context.Context) (err error) {
 	for {
 		select {
 		case entry := <-c.entriesChan:
+			if !c.limiter.Allow(ctx) {
+				continue
+			}
 			c.receiversMut.RLock()
 			receivers := c.receivers
 			c.receiversMut.RUnlock()
@@ -125,6 +132,7 @@ func (c *Component) Update(args component.Arguments) error {
 	c.receiversMut.Lock()
 	c.receivers = newArgs.ForwardTo
 	c.receiversMut.Unlock()
+	c.limiter.Update(newArgs.Limit)
 
 	c.serverMut.Lock()
 	defer c.serverMut.Unlock()
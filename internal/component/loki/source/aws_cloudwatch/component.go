@@ -0,0 +1,297 @@
+package aws_cloudwatch
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	aws_config "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/go-kit/log"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/limit"
+	"github.com/grafana/alloy/internal/component/common/loki/positions"
+	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
+	"github.com/grafana/alloy/internal/component/loki/source/aws_cloudwatch/internal"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/syntax/alloytypes"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "loki.source.awscloudwatch",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments holds values which are used to configure the
+// loki.source.awscloudwatch component.
+type Arguments struct {
+	Region    string            `alloy:"region,attr,optional"`
+	Endpoint  string            `alloy:"endpoint,attr,optional"`
+	AccessKey string            `alloy:"access_key,attr,optional"`
+	SecretKey alloytypes.Secret `alloy:"secret_key,attr,optional"`
+	RoleARN   string            `alloy:"role_arn,attr,optional"`
+
+	LogGroupNames       []string          `alloy:"log_group_names,attr,optional"`
+	LogGroupNamePrefix  string            `alloy:"log_group_name_prefix,attr,optional"`
+	LogGroupTags        map[string]string `alloy:"log_group_tags,attr,optional"`
+	LogStreamNamePrefix string            `alloy:"log_stream_name_prefix,attr,optional"`
+	FilterPattern       string            `alloy:"filter_pattern,attr,optional"`
+
+	PollInterval      time.Duration `alloy:"poll_interval,attr,optional"`
+	DiscoveryInterval time.Duration `alloy:"discovery_interval,attr,optional"`
+	InitialLookback   time.Duration `alloy:"initial_lookback,attr,optional"`
+
+	Labels               map[string]string   `alloy:"labels,attr,optional"`
+	UseIncomingTimestamp bool                `alloy:"use_incoming_timestamp,attr,optional"`
+	ForwardTo            []loki.LogsReceiver `alloy:"forward_to,attr"`
+	RelabelRules         alloy_relabel.Rules `alloy:"relabel_rules,attr,optional"`
+	Limit                *limit.Arguments    `alloy:"limit,block,optional"`
+}
+
+// DefaultArguments sets the configuration defaults.
+var DefaultArguments = Arguments{
+	PollInterval:      30 * time.Second,
+	DiscoveryInterval: 5 * time.Minute,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *Arguments) SetToDefault() {
+	*a = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if len(a.LogGroupNames) == 0 && a.LogGroupNamePrefix == "" && len(a.LogGroupTags) == 0 {
+		return errors.New("at least one of log_group_names, log_group_name_prefix or log_group_tags must be set")
+	}
+	if (a.AccessKey == "") != (a.SecretKey == "") {
+		return errors.New("access_key and secret_key must be set together")
+	}
+	if a.PollInterval <= 0 {
+		return fmt.Errorf("poll_interval must be greater than 0")
+	}
+	if a.DiscoveryInterval <= 0 {
+		return fmt.Errorf("discovery_interval must be greater than 0")
+	}
+	if a.InitialLookback < 0 {
+		return fmt.Errorf("initial_lookback must not be negative")
+	}
+	return nil
+}
+
+// Convert returns the configuration of the tailer.
+func (a *Arguments) Convert() internal.Config {
+	lbls := make(model.LabelSet, len(a.Labels))
+	for k, v := range a.Labels {
+		lbls[model.LabelName(k)] = model.LabelValue(v)
+	}
+	return internal.Config{
+		LogGroupNames:        a.LogGroupNames,
+		LogGroupNamePrefix:   a.LogGroupNamePrefix,
+		LogGroupTags:         a.LogGroupTags,
+		LogStreamNamePrefix:  a.LogStreamNamePrefix,
+		FilterPattern:        a.FilterPattern,
+		PollInterval:         a.PollInterval,
+		DiscoveryInterval:    a.DiscoveryInterval,
+		InitialLookback:      a.InitialLookback,
+		Labels:               lbls,
+		RelabelConfigs:       alloy_relabel.ComponentToPromRelabelConfigs(a.RelabelRules),
+		UseIncomingTimestamp: a.UseIncomingTimestamp,
+	}
+}
+
+// tailerArguments returns the arguments which the tailer depends on.
+func (a Arguments) tailerArguments() Arguments {
+	a.ForwardTo = nil
+	a.Limit = nil
+	return a
+}
+
+// Component implements the loki.source.awscloudwatch component.
+type Component struct {
+	opts    component.Options
+	logger  log.Logger
+	metrics *internal.Metrics
+	limiter *limit.Limiter
+
+	mut        sync.RWMutex
+	fanout     []loki.LogsReceiver
+	tailer     *internal.Tailer
+	tailerArgs Arguments
+
+	posFile positions.Positions
+	handler loki.LogsReceiver
+}
+
+// New creates a new loki.source.awscloudwatch component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	err := os.MkdirAll(o.DataPath, 0750)
+	if err != nil && !os.IsExist(err) {
+		return nil, err
+	}
+	positionsFile, err := positions.New(o.Logger, positions.Config{
+		SyncPeriod:        10 * time.Second,
+		PositionsFile:     filepath.Join(o.DataPath, "positions.yml"),
+		IgnoreInvalidYaml: false,
+		ReadOnly:          false,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Component{
+		opts:    o,
+		logger:  log.With(o.Logger, "component", "aws_cloudwatch_logs"),
+		metrics: internal.NewMetrics(o.Registerer),
+		limiter: limit.NewLimiter(o.Registerer),
+		handler: loki.NewLogsReceiver(),
+		fanout:  args.ForwardTo,
+		posFile: positionsFile,
+	}
+
+	// Call to Update() to start the tailer and set receivers once at the start.
+	if err := c.Update(args); err != nil {
+		positionsFile.Stop()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Run implements component.Component.
+func (c *Component) Run(ctx context.Context) error {
+	defer func() {
+		c.mut.Lock()
+		level.Info(c.opts.Logger).Log("msg", "loki.source.awscloudwatch component shutting down, stopping the tailer")
+		if c.tailer != nil {
+			c.tailer.Stop()
+		}
+		c.posFile.Stop()
+		c.mut.Unlock()
+	}()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case entry := <-c.handler.Chan():
+			if !c.limiter.Allow(ctx) {
+				continue
+			}
+			c.mut.RLock()
+			for _, receiver := range c.fanout {
+				select {
+				case <-ctx.Done():
+					c.mut.RUnlock()
+					return nil
+				case receiver.Chan() <- entry:
+				}
+			}
+			c.mut.RUnlock()
+		}
+	}
+}
+
+// Update implements component.Component.
+func (c *Component) Update(args component.Arguments) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	newArgs := args.(Arguments)
+	c.fanout = newArgs.ForwardTo
+	c.limiter.Update(newArgs.Limit)
+
+	// Restarting the tailer loses the IDs of the events read at the
+	// checkpoints, so it's only restarted if its arguments changed.
+	tailerArgs := newArgs.tailerArguments()
+	if c.tailer != nil && reflect.DeepEqual(c.tailerArgs, tailerArgs) {
+		return nil
+	}
+
+	client, err := newClient(newArgs)
+	if err != nil {
+		return fmt.Errorf("failed to create CloudWatch Logs client: %w", err)
+	}
+
+	if c.tailer != nil {
+		c.tailer.Stop()
+	}
+	entryHandler := loki.NewEntryHandler(c.handler.Chan(), func() {})
+	c.tailer = internal.NewTailer(c.logger, client, c.metrics, c.posFile, entryHandler, newArgs.Convert())
+	c.tailerArgs = tailerArgs
+
+	return nil
+}
+
+// newClient creates a CloudWatch Logs client with the default AWS
+// credentials, unless static credentials are set, assuming role_arn if set.
+func newClient(args Arguments) (*cloudwatchlogs.Client, error) {
+	var opts []func(*aws_config.LoadOptions) error
+	if args.Region != "" {
+		opts = append(opts, aws_config.WithRegion(args.Region))
+	}
+	if args.AccessKey != "" {
+		opts = append(opts, aws_config.WithCredentialsProvider(
+			credentials.NewStaticCredentialsProvider(args.AccessKey, string(args.SecretKey), ""),
+		))
+	}
+
+	cfg, err := aws_config.LoadDefaultConfig(context.TODO(), opts...)
+	if err != nil {
+		return nil, err
+	}
+	if args.RoleARN != "" {
+		cfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), args.RoleARN))
+	}
+
+	return cloudwatchlogs.NewFromConfig(cfg, func(o *cloudwatchlogs.Options) {
+		if args.Endpoint != "" {
+			o.BaseEndpoint = aws.String(args.Endpoint)
+		}
+	}), nil
+}
+
+// DebugInfo returns information about the log groups being tailed.
+func (c *Component) DebugInfo() interface{} {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	var info debugInfo
+	for _, group := range c.tailer.LogGroups() {
+		groupInfo := logGroupDebugInfo{Name: group}
+		if checkpoint := c.tailer.Checkpoint(group); !checkpoint.IsZero() {
+			groupInfo.Checkpoint = checkpoint.UTC().Format(time.RFC3339Nano)
+		}
+		info.LogGroups = append(info.LogGroups, groupInfo)
+	}
+	return info
+}
+
+type debugInfo struct {
+	LogGroups []logGroupDebugInfo `alloy:"log_group,block,optional"`
+}
+
+type logGroupDebugInfo struct {
+	Name       string `alloy:"name,attr"`
+	Checkpoint string `alloy:"checkpoint,attr,optional"`
+}
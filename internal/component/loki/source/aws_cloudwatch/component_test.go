@@ -0,0 +1,85 @@
+package aws_cloudwatch
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestArguments(t *testing.T) {
+	cfg := `
+		region                 = "eu-west-1"
+		log_group_names        = ["/ecs/frontend"]
+		log_group_name_prefix  = "/aws/lambda/"
+		log_group_tags         = {team = "shop"}
+		log_stream_name_prefix = "prod-"
+		filter_pattern         = "ERROR"
+		initial_lookback       = "1h"
+		labels                 = {job = "cloudwatch"}
+		forward_to             = []
+	`
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(cfg), &args))
+	require.Equal(t, 30*time.Second, args.PollInterval)
+	require.Equal(t, 5*time.Minute, args.DiscoveryInterval)
+
+	tailerCfg := args.Convert()
+	require.Equal(t, []string{"/ecs/frontend"}, tailerCfg.LogGroupNames)
+	require.Equal(t, "/aws/lambda/", tailerCfg.LogGroupNamePrefix)
+	require.Equal(t, map[string]string{"team": "shop"}, tailerCfg.LogGroupTags)
+	require.Equal(t, "prod-", tailerCfg.LogStreamNamePrefix)
+	require.Equal(t, "ERROR", tailerCfg.FilterPattern)
+	require.Equal(t, time.Hour, tailerCfg.InitialLookback)
+	require.Equal(t, model.LabelSet{"job": "cloudwatch"}, tailerCfg.Labels)
+}
+
+func TestArguments_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		cfg    string
+		errMsg string
+	}{
+		{
+			name:   "no log groups",
+			cfg:    `forward_to = []`,
+			errMsg: "at least one of log_group_names, log_group_name_prefix or log_group_tags must be set",
+		},
+		{
+			name: "access key without secret key",
+			cfg: `
+				log_group_names = ["app"]
+				access_key      = "AKIA"
+				forward_to      = []
+			`,
+			errMsg: "access_key and secret_key must be set together",
+		},
+		{
+			name: "zero poll interval",
+			cfg: `
+				log_group_names = ["app"]
+				poll_interval   = "0s"
+				forward_to      = []
+			`,
+			errMsg: "poll_interval must be greater than 0",
+		},
+		{
+			name: "negative initial lookback",
+			cfg: `
+				log_group_names  = ["app"]
+				initial_lookback = "-1m"
+				forward_to       = []
+			`,
+			errMsg: "initial_lookback must not be negative",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args Arguments
+			require.EqualError(t, syntax.Unmarshal([]byte(tt.cfg), &args), tt.errMsg)
+		})
+	}
+}
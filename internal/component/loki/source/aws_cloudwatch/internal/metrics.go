@@ -0,0 +1,46 @@
+package internal
+
+import (
+	"github.com/grafana/alloy/internal/util"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics holds the metrics of the CloudWatch Logs tailer.
+type Metrics struct {
+	Entries            *prometheus.CounterVec
+	Errors             *prometheus.CounterVec
+	LogGroups          prometheus.Gauge
+	LastEventTimestamp *prometheus.GaugeVec
+}
+
+// NewMetrics creates a new set of metrics. If reg is non-nil, the metrics
+// will be registered.
+func NewMetrics(reg prometheus.Registerer) *Metrics {
+	var m Metrics
+
+	m.Entries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "loki_source_awscloudwatch_entries_total",
+		Help: "Total number of log entries read from CloudWatch Logs.",
+	}, []string{"log_group"})
+	m.Errors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "loki_source_awscloudwatch_request_errors_total",
+		Help: "Total number of failed requests to the CloudWatch Logs API.",
+	}, []string{"operation"})
+	m.LogGroups = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "loki_source_awscloudwatch_log_groups",
+		Help: "Number of log groups being tailed.",
+	})
+	m.LastEventTimestamp = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "loki_source_awscloudwatch_last_event_timestamp_seconds",
+		Help: "Timestamp of the newest log event read from each log group. This allows to calculate how far the component is behind.",
+	}, []string{"log_group"})
+
+	if reg != nil {
+		m.Entries = util.MustRegisterOrGet(reg, m.Entries).(*prometheus.CounterVec)
+		m.Errors = util.MustRegisterOrGet(reg, m.Errors).(*prometheus.CounterVec)
+		m.LogGroups = util.MustRegisterOrGet(reg, m.LogGroups).(prometheus.Gauge)
+		m.LastEventTimestamp = util.MustRegisterOrGet(reg, m.LastEventTimestamp).(*prometheus.GaugeVec)
+	}
+
+	return &m
+}
@@ -0,0 +1,365 @@
+package internal
+
+// The internal package reads log events from CloudWatch Logs and forwards
+// them to loki components.
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/go-kit/log"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/relabel"
+
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/positions"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+const (
+	labelLogGroup  = "__aws_cw_log_group"
+	labelLogStream = "__aws_cw_log_stream"
+)
+
+// Client is the part of the CloudWatch Logs API used by the Tailer.
+type Client interface {
+	cloudwatchlogs.DescribeLogGroupsAPIClient
+	cloudwatchlogs.FilterLogEventsAPIClient
+	ListTagsForResource(ctx context.Context, params *cloudwatchlogs.ListTagsForResourceInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.ListTagsForResourceOutput, error)
+}
+
+// Config configures a Tailer.
+type Config struct {
+	// LogGroupNames are always tailed.
+	LogGroupNames []string
+	// Log groups whose name starts with LogGroupNamePrefix and which have all
+	// of the LogGroupTags are discovered, if either is set.
+	LogGroupNamePrefix string
+	LogGroupTags       map[string]string
+
+	LogStreamNamePrefix string
+	FilterPattern       string
+
+	PollInterval      time.Duration
+	DiscoveryInterval time.Duration
+	// InitialLookback is how far back to read a log group without checkpoint.
+	InitialLookback time.Duration
+
+	Labels               model.LabelSet
+	RelabelConfigs       []*relabel.Config
+	UseIncomingTimestamp bool
+}
+
+// discover reports whether log groups must be discovered with the API.
+func (c *Config) discover() bool {
+	return c.LogGroupNamePrefix != "" || len(c.LogGroupTags) > 0
+}
+
+// Tailer polls the log events of CloudWatch log groups, and saves the
+// timestamp of the newest event of each log group as its checkpoint.
+type Tailer struct {
+	logger    log.Logger
+	client    Client
+	metrics   *Metrics
+	positions positions.Positions
+	handler   loki.EntryHandler
+	cfg       Config
+	now       func() time.Time
+
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mut    sync.RWMutex
+	groups []string
+	// seen holds the IDs of the events at the checkpoint of each log group,
+	// which are read again by the next poll.
+	seen map[string]map[string]struct{}
+}
+
+// NewTailer creates a Tailer and starts tailing the log groups.
+func NewTailer(logger log.Logger, client Client, metrics *Metrics, positions positions.Positions, handler loki.EntryHandler, cfg Config) *Tailer {
+	ctx, cancel := context.WithCancel(context.Background())
+	t := newTailer(logger, client, metrics, positions, handler, cfg)
+	t.cancel = cancel
+	go t.run(ctx)
+	return t
+}
+
+func newTailer(logger log.Logger, client Client, metrics *Metrics, positions positions.Positions, handler loki.EntryHandler, cfg Config) *Tailer {
+	return &Tailer{
+		logger:    logger,
+		client:    client,
+		metrics:   metrics,
+		positions: positions,
+		handler:   handler,
+		cfg:       cfg,
+		now:       time.Now,
+		done:      make(chan struct{}),
+		seen:      make(map[string]map[string]struct{}),
+	}
+}
+
+func (t *Tailer) run(ctx context.Context) {
+	defer close(t.done)
+
+	discoverTicker := time.NewTicker(t.cfg.DiscoveryInterval)
+	defer discoverTicker.Stop()
+	pollTicker := time.NewTicker(t.cfg.PollInterval)
+	defer pollTicker.Stop()
+
+	t.discover(ctx)
+	t.poll(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-discoverTicker.C:
+			t.discover(ctx)
+		case <-pollTicker.C:
+			t.poll(ctx)
+		}
+	}
+}
+
+// discover updates the list of log groups to tail. The previous list is kept
+// if the log groups can't be listed.
+func (t *Tailer) discover(ctx context.Context) {
+	groups := append([]string{}, t.cfg.LogGroupNames...)
+	if t.cfg.discover() {
+		discovered, err := t.discoverLogGroups(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				level.Error(t.logger).Log("msg", "failed to discover log groups", "err", err)
+			}
+			return
+		}
+		for _, group := range discovered {
+			if !slices.Contains(groups, group) {
+				groups = append(groups, group)
+			}
+		}
+	}
+
+	t.mut.Lock()
+	defer t.mut.Unlock()
+	for _, group := range t.groups {
+		if !slices.Contains(groups, group) {
+			level.Info(t.logger).Log("msg", "stopped tailing log group", "log_group", group)
+			delete(t.seen, group)
+			t.metrics.LastEventTimestamp.DeleteLabelValues(group)
+		}
+	}
+	for _, group := range groups {
+		if !slices.Contains(t.groups, group) {
+			level.Info(t.logger).Log("msg", "started tailing log group", "log_group", group)
+		}
+	}
+	t.groups = groups
+	t.metrics.LogGroups.Set(float64(len(groups)))
+}
+
+func (t *Tailer) discoverLogGroups(ctx context.Context) ([]string, error) {
+	input := &cloudwatchlogs.DescribeLogGroupsInput{}
+	if t.cfg.LogGroupNamePrefix != "" {
+		input.LogGroupNamePrefix = aws.String(t.cfg.LogGroupNamePrefix)
+	}
+
+	var groups []string
+	paginator := cloudwatchlogs.NewDescribeLogGroupsPaginator(t.client, input)
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			t.metrics.Errors.WithLabelValues("DescribeLogGroups").Inc()
+			return nil, err
+		}
+		for _, group := range out.LogGroups {
+			if len(t.cfg.LogGroupTags) > 0 {
+				matches, err := t.hasTags(ctx, aws.ToString(group.LogGroupArn))
+				if err != nil {
+					return nil, err
+				}
+				if !matches {
+					continue
+				}
+			}
+			groups = append(groups, aws.ToString(group.LogGroupName))
+		}
+	}
+	return groups, nil
+}
+
+// hasTags reports whether a log group has all of the tags of the Config.
+func (t *Tailer) hasTags(ctx context.Context, arn string) (bool, error) {
+	out, err := t.client.ListTagsForResource(ctx, &cloudwatchlogs.ListTagsForResourceInput{
+		ResourceArn: aws.String(arn),
+	})
+	if err != nil {
+		t.metrics.Errors.WithLabelValues("ListTagsForResource").Inc()
+		return false, err
+	}
+	for k, v := range t.cfg.LogGroupTags {
+		if tag, ok := out.Tags[k]; !ok || tag != v {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (t *Tailer) poll(ctx context.Context) {
+	for _, group := range t.LogGroups() {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := t.pollLogGroup(ctx, group); err != nil && ctx.Err() == nil {
+			level.Error(t.logger).Log("msg", "failed to read log events", "log_group", group, "err", err)
+		}
+	}
+}
+
+// pollLogGroup reads the log events of a log group since its checkpoint.
+// FilterLogEvents includes the events at the start time, so the events
+// already read at the checkpoint are skipped.
+func (t *Tailer) pollLogGroup(ctx context.Context, group string) error {
+	start := t.checkpoint(group)
+	input := &cloudwatchlogs.FilterLogEventsInput{
+		LogGroupName: aws.String(group),
+		StartTime:    aws.Int64(start),
+	}
+	if t.cfg.LogStreamNamePrefix != "" {
+		input.LogStreamNamePrefix = aws.String(t.cfg.LogStreamNamePrefix)
+	}
+	if t.cfg.FilterPattern != "" {
+		input.FilterPattern = aws.String(t.cfg.FilterPattern)
+	}
+
+	t.mut.RLock()
+	seen := t.seen[group]
+	t.mut.RUnlock()
+
+	newest, newestIDs := start, seen
+	paginator := cloudwatchlogs.NewFilterLogEventsPaginator(t.client, input)
+	for paginator.HasMorePages() {
+		out, err := paginator.NextPage(ctx)
+		if err != nil {
+			t.metrics.Errors.WithLabelValues("FilterLogEvents").Inc()
+			return err
+		}
+		for _, event := range out.Events {
+			ts, id := aws.ToInt64(event.Timestamp), aws.ToString(event.EventId)
+			if _, ok := seen[id]; ok && ts == start {
+				continue
+			}
+
+			if entry, ok := t.entry(group, event); ok {
+				select {
+				case <-ctx.Done():
+					return ctx.Err()
+				case t.handler.Chan() <- entry:
+				}
+				t.metrics.Entries.WithLabelValues(group).Inc()
+			}
+
+			switch {
+			case ts > newest:
+				newest, newestIDs = ts, map[string]struct{}{id: {}}
+			case ts == newest:
+				if newestIDs == nil {
+					newestIDs = make(map[string]struct{})
+				}
+				newestIDs[id] = struct{}{}
+			}
+		}
+
+		// Save the progress after each page, so a failed request doesn't
+		// read the events of the previous pages again.
+		t.positions.Put(positions.CursorKey(group), "", newest)
+		t.metrics.LastEventTimestamp.WithLabelValues(group).Set(float64(newest) / 1000)
+		t.mut.Lock()
+		t.seen[group] = newestIDs
+		t.mut.Unlock()
+	}
+	return nil
+}
+
+// checkpoint returns the timestamp in milliseconds to read a log group from.
+func (t *Tailer) checkpoint(group string) int64 {
+	pos, err := t.positions.Get(positions.CursorKey(group), "")
+	if err != nil {
+		level.Warn(t.logger).Log("msg", "failed to read checkpoint of log group, reading from the initial lookback", "log_group", group, "err", err)
+	}
+	if err != nil || pos == 0 {
+		return t.now().Add(-t.cfg.InitialLookback).UnixMilli()
+	}
+	return pos
+}
+
+// entry converts a log event to a loki.Entry. It returns false if the
+// relabeling rules drop the event.
+func (t *Tailer) entry(group string, event types.FilteredLogEvent) (loki.Entry, bool) {
+	lb := labels.NewBuilder(labels.EmptyLabels())
+	lb.Set(labelLogGroup, group)
+	lb.Set(labelLogStream, aws.ToString(event.LogStreamName))
+
+	processed := lb.Labels()
+	if len(t.cfg.RelabelConfigs) > 0 {
+		var keep bool
+		processed, keep = relabel.Process(processed, t.cfg.RelabelConfigs...)
+		if !keep {
+			return loki.Entry{}, false
+		}
+	}
+
+	lbls := make(model.LabelSet)
+	processed.Range(func(l labels.Label) {
+		// Drop the internal labels.
+		if strings.HasPrefix(l.Name, "__") {
+			return
+		}
+		lbls[model.LabelName(l.Name)] = model.LabelValue(l.Value)
+	})
+	lbls = lbls.Merge(t.cfg.Labels)
+
+	ts := t.now()
+	if t.cfg.UseIncomingTimestamp {
+		ts = time.UnixMilli(aws.ToInt64(event.Timestamp))
+	}
+	return loki.Entry{
+		Labels: lbls,
+		Entry: logproto.Entry{
+			Timestamp: ts,
+			Line:      aws.ToString(event.Message),
+		},
+	}, true
+}
+
+// LogGroups returns the log groups being tailed.
+func (t *Tailer) LogGroups() []string {
+	t.mut.RLock()
+	defer t.mut.RUnlock()
+	return append([]string{}, t.groups...)
+}
+
+// Checkpoint returns the timestamp of the newest log event read from a log
+// group, or the zero time if no event was read.
+func (t *Tailer) Checkpoint(group string) time.Time {
+	pos, err := t.positions.Get(positions.CursorKey(group), "")
+	if err != nil || pos == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(pos)
+}
+
+// Stop stops tailing the log groups.
+func (t *Tailer) Stop() {
+	t.cancel()
+	<-t.done
+	t.handler.Stop()
+}
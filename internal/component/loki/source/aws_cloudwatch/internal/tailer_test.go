@@ -0,0 +1,268 @@
+package internal
+
+import (
+	"context"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/relabel"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/positions"
+	"github.com/grafana/alloy/internal/util"
+)
+
+// fakeClient serves log groups and log events from memory. It returns the
+// log events two by two to exercise the pagination.
+type fakeClient struct {
+	mut    sync.Mutex
+	groups []types.LogGroup
+	tags   map[string]map[string]string
+	events map[string][]types.FilteredLogEvent
+}
+
+func (c *fakeClient) DescribeLogGroups(_ context.Context, in *cloudwatchlogs.DescribeLogGroupsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.DescribeLogGroupsOutput, error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	var out cloudwatchlogs.DescribeLogGroupsOutput
+	for _, g := range c.groups {
+		if strings.HasPrefix(aws.ToString(g.LogGroupName), aws.ToString(in.LogGroupNamePrefix)) {
+			out.LogGroups = append(out.LogGroups, g)
+		}
+	}
+	return &out, nil
+}
+
+func (c *fakeClient) ListTagsForResource(_ context.Context, in *cloudwatchlogs.ListTagsForResourceInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.ListTagsForResourceOutput, error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return &cloudwatchlogs.ListTagsForResourceOutput{Tags: c.tags[aws.ToString(in.ResourceArn)]}, nil
+}
+
+func (c *fakeClient) FilterLogEvents(_ context.Context, in *cloudwatchlogs.FilterLogEventsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.FilterLogEventsOutput, error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	var events []types.FilteredLogEvent
+	for _, e := range c.events[aws.ToString(in.LogGroupName)] {
+		if aws.ToInt64(e.Timestamp) >= aws.ToInt64(in.StartTime) &&
+			strings.HasPrefix(aws.ToString(e.LogStreamName), aws.ToString(in.LogStreamNamePrefix)) {
+			events = append(events, e)
+		}
+	}
+
+	offset := 0
+	if in.NextToken != nil {
+		offset, _ = strconv.Atoi(*in.NextToken)
+	}
+	end := min(offset+2, len(events))
+	out := &cloudwatchlogs.FilterLogEventsOutput{Events: events[offset:end]}
+	if end < len(events) {
+		out.NextToken = aws.String(strconv.Itoa(end))
+	}
+	return out, nil
+}
+
+func (c *fakeClient) addEvent(group, stream, id string, ts time.Time, msg string) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.events[group] = append(c.events[group], types.FilteredLogEvent{
+		EventId:       aws.String(id),
+		LogStreamName: aws.String(stream),
+		Message:       aws.String(msg),
+		Timestamp:     aws.Int64(ts.UnixMilli()),
+	})
+}
+
+func logGroup(name string) types.LogGroup {
+	return types.LogGroup{
+		LogGroupName: aws.String(name),
+		LogGroupArn:  aws.String("arn:aws:logs:us-east-1:123456789012:log-group:" + name),
+	}
+}
+
+func newTestPositions(t *testing.T) positions.Positions {
+	pos, err := positions.New(util.TestLogger(t), positions.Config{
+		SyncPeriod:    10 * time.Second,
+		PositionsFile: filepath.Join(t.TempDir(), "positions.yml"),
+	})
+	require.NoError(t, err)
+	t.Cleanup(pos.Stop)
+	return pos
+}
+
+// drain returns the entries sent to the handler.
+func drain(ch chan loki.Entry) []loki.Entry {
+	var entries []loki.Entry
+	for {
+		select {
+		case e := <-ch:
+			entries = append(entries, e)
+		default:
+			return entries
+		}
+	}
+}
+
+func TestTailer_Discover(t *testing.T) {
+	client := &fakeClient{
+		groups: []types.LogGroup{
+			logGroup("/aws/lambda/checkout"),
+			logGroup("/aws/lambda/payments"),
+			logGroup("/aws/lambda/search"),
+			logGroup("/ecs/frontend"),
+		},
+		tags: map[string]map[string]string{
+			"arn:aws:logs:us-east-1:123456789012:log-group:/aws/lambda/checkout": {"team": "shop"},
+			"arn:aws:logs:us-east-1:123456789012:log-group:/aws/lambda/payments": {"team": "shop", "env": "prod"},
+			"arn:aws:logs:us-east-1:123456789012:log-group:/ecs/frontend":        {"team": "shop"},
+		},
+	}
+
+	tests := []struct {
+		name string
+		cfg  Config
+		want []string
+	}{
+		{
+			name: "names",
+			cfg:  Config{LogGroupNames: []string{"/ecs/frontend"}},
+			want: []string{"/ecs/frontend"},
+		},
+		{
+			name: "prefix",
+			cfg:  Config{LogGroupNamePrefix: "/aws/lambda/"},
+			want: []string{"/aws/lambda/checkout", "/aws/lambda/payments", "/aws/lambda/search"},
+		},
+		{
+			name: "tags",
+			cfg:  Config{LogGroupTags: map[string]string{"team": "shop"}},
+			want: []string{"/aws/lambda/checkout", "/aws/lambda/payments", "/ecs/frontend"},
+		},
+		{
+			name: "prefix and tags",
+			cfg:  Config{LogGroupNamePrefix: "/aws/lambda/", LogGroupTags: map[string]string{"team": "shop", "env": "prod"}},
+			want: []string{"/aws/lambda/payments"},
+		},
+		{
+			name: "names and prefix",
+			cfg:  Config{LogGroupNames: []string{"/ecs/frontend", "/aws/lambda/search"}, LogGroupNamePrefix: "/aws/lambda/s"},
+			want: []string{"/ecs/frontend", "/aws/lambda/search"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			metrics := NewMetrics(prometheus.NewRegistry())
+			handler := loki.NewEntryHandler(make(chan loki.Entry), func() {})
+			tailer := newTailer(util.TestLogger(t), client, metrics, newTestPositions(t), handler, tt.cfg)
+
+			tailer.discover(t.Context())
+			require.Equal(t, tt.want, tailer.LogGroups())
+			require.Equal(t, float64(len(tt.want)), testutil.ToFloat64(metrics.LogGroups))
+		})
+	}
+}
+
+func TestTailer_Poll(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	client := &fakeClient{events: map[string][]types.FilteredLogEvent{}}
+	client.addEvent("app", "web-1", "1", now.Add(-2*time.Hour), "too old")
+	client.addEvent("app", "web-1", "2", now.Add(-30*time.Minute), "first")
+	client.addEvent("app", "worker-1", "3", now.Add(-20*time.Minute), "second")
+	client.addEvent("app", "web-2", "4", now.Add(-10*time.Minute), "third")
+
+	ch := make(chan loki.Entry, 10)
+	metrics := NewMetrics(prometheus.NewRegistry())
+	pos := newTestPositions(t)
+	tailer := newTailer(util.TestLogger(t), client, metrics, pos, loki.NewEntryHandler(ch, func() {}), Config{
+		LogGroupNames:       []string{"app"},
+		LogStreamNamePrefix: "web-",
+		InitialLookback:     time.Hour,
+		Labels:              model.LabelSet{"job": "cloudwatch"},
+		RelabelConfigs: []*relabel.Config{{
+			SourceLabels: model.LabelNames{labelLogStream},
+			Regex:        relabel.MustNewRegexp("(.*)"),
+			TargetLabel:  "stream",
+			Replacement:  "$1",
+			Action:       relabel.Replace,
+		}},
+		UseIncomingTimestamp: true,
+	})
+	tailer.now = func() time.Time { return now }
+
+	tailer.discover(t.Context())
+	tailer.poll(t.Context())
+
+	entries := drain(ch)
+	require.Len(t, entries, 2)
+	require.Equal(t, "first", entries[0].Line)
+	require.Equal(t, now.Add(-30*time.Minute), entries[0].Timestamp.UTC())
+	require.Equal(t, model.LabelSet{"job": "cloudwatch", "stream": "web-1"}, entries[0].Labels)
+	require.Equal(t, "third", entries[1].Line)
+	require.Equal(t, model.LabelSet{"job": "cloudwatch", "stream": "web-2"}, entries[1].Labels)
+
+	require.Equal(t, now.Add(-10*time.Minute), tailer.Checkpoint("app").UTC())
+	require.Equal(t, 2.0, testutil.ToFloat64(metrics.Entries.WithLabelValues("app")))
+
+	// The next poll reads the new events, including the ones at the same
+	// millisecond as the checkpoint, without reading the last event again.
+	client.addEvent("app", "web-1", "5", now.Add(-10*time.Minute), "fourth")
+	client.addEvent("app", "web-1", "6", now.Add(-5*time.Minute), "fifth")
+	tailer.poll(t.Context())
+
+	entries = drain(ch)
+	require.Len(t, entries, 2)
+	require.Equal(t, "fourth", entries[0].Line)
+	require.Equal(t, "fifth", entries[1].Line)
+	require.Equal(t, now.Add(-5*time.Minute), tailer.Checkpoint("app").UTC())
+
+	// A new tailer starts from the checkpoint. It doesn't know which events
+	// were read at the checkpoint, so it reads them again.
+	tailer = newTailer(util.TestLogger(t), client, metrics, pos, loki.NewEntryHandler(ch, func() {}), Config{
+		LogGroupNames: []string{"app"},
+	})
+	client.addEvent("app", "web-1", "7", now.Add(-time.Minute), "sixth")
+	tailer.discover(t.Context())
+	tailer.poll(t.Context())
+
+	entries = drain(ch)
+	require.Len(t, entries, 2)
+	require.Equal(t, "fifth", entries[0].Line)
+	require.Equal(t, "sixth", entries[1].Line)
+}
+
+func TestTailer_DropEntries(t *testing.T) {
+	client := &fakeClient{events: map[string][]types.FilteredLogEvent{}}
+	client.addEvent("app", "debug", "1", time.Now(), "dropped")
+	client.addEvent("app", "main", "2", time.Now(), "kept")
+
+	ch := make(chan loki.Entry, 10)
+	tailer := newTailer(util.TestLogger(t), client, NewMetrics(nil), newTestPositions(t), loki.NewEntryHandler(ch, func() {}), Config{
+		LogGroupNames:   []string{"app"},
+		InitialLookback: time.Minute,
+		RelabelConfigs: []*relabel.Config{{
+			SourceLabels: model.LabelNames{labelLogStream},
+			Regex:        relabel.MustNewRegexp("debug"),
+			Action:       relabel.Drop,
+		}},
+	})
+	tailer.discover(t.Context())
+	tailer.poll(t.Context())
+
+	entries := drain(ch)
+	require.Len(t, entries, 1)
+	require.Equal(t, "kept", entries[0].Line)
+	require.Empty(t, entries[0].Labels)
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/limit"
 	fnet "github.com/grafana/alloy/internal/component/common/net"
 	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
 	"github.com/grafana/alloy/internal/component/loki/source/aws_firehose/internal"
@@ -39,6 +40,7 @@ type Arguments struct {
 	UseIncomingTimestamp bool                `alloy:"use_incoming_timestamp,attr,optional"`
 	ForwardTo            []loki.LogsReceiver `alloy:"forward_to,attr"`
 	RelabelRules         alloy_relabel.Rules `alloy:"relabel_rules,attr,optional"`
+	Limit                *limit.Arguments    `alloy:"limit,block,optional"`
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -66,6 +68,7 @@ type Component struct {
 	// utils
 	serverMetrics  *util.UncheckedCollector
 	handlerMetrics *internal.Metrics
+	limiter        *limit.Limiter
 	logger         log.Logger
 }
 
@@ -77,6 +80,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		fanout:         args.ForwardTo,
 		serverMetrics:  util.NewUncheckedCollector(nil),
 		handlerMetrics: internal.NewMetrics(o.Registerer),
+		limiter:        limit.NewLimiter(o.Registerer),
 
 		logger: log.With(o.Logger, "component", "aws_firehose_logs"),
 	}
@@ -103,6 +107,9 @@ func (c *Component) Run(ctx context.Context) error {
 		case <-ctx.Done():
 			return nil
 		case entry := <-c.destination.Chan():
+			if !c.limiter.Allow(ctx) {
+				continue
+			}
 			c.mut.RLock()
 			for _, receiver := range c.fanout {
 				receiver.Chan() <- entry
@@ -120,6 +127,7 @@ func (c *Component) Update(args component.Arguments) error {
 
 	newArgs := args.(Arguments)
 	c.fanout = newArgs.ForwardTo
+	c.limiter.Update(newArgs.Limit)
 
 	var newRelabels []*relabel.Config = nil
 	// first condition to consider if the handler needs to be updated is if the UseIncomingTimestamp field
@@ -9,6 +9,7 @@ import (
 	"github.com/IBM/sarama"
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/limit"
 	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
 	"github.com/grafana/alloy/internal/component/loki/source/azure_event_hubs/internal/parser"
 	kt "github.com/grafana/alloy/internal/component/loki/source/internal/kafkatarget"
@@ -47,6 +48,7 @@ type Arguments struct {
 	Assignor               string              `alloy:"assignor,attr,optional"`
 
 	ForwardTo []loki.LogsReceiver `alloy:"forward_to,attr"`
+	Limit     *limit.Arguments    `alloy:"limit,block,optional"`
 }
 
 // AzureEventHubsAuthentication describe the configuration for authentication with Azure Event Hub
@@ -79,6 +81,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	c := &Component{
 		mut:     sync.RWMutex{},
 		opts:    o,
+		limiter: limit.NewLimiter(o.Registerer),
 		handler: loki.NewLogsReceiver(),
 		fanout:  args.ForwardTo,
 	}
@@ -94,6 +97,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 // Component implements the loki.source.azure_event_hubs component.
 type Component struct {
 	opts    component.Options
+	limiter *limit.Limiter
 	mut     sync.RWMutex
 	fanout  []loki.LogsReceiver
 	handler loki.LogsReceiver
@@ -117,6 +121,9 @@ func (c *Component) Run(ctx context.Context) error {
 		case <-ctx.Done():
 			return nil
 		case entry := <-c.handler.Chan():
+			if !c.limiter.Allow(ctx) {
+				continue
+			}
 			c.mut.RLock()
 			for _, receiver := range c.fanout {
 				receiver.Chan() <- entry
@@ -138,6 +145,7 @@ func (c *Component) Update(args component.Arguments) error {
 
 	newArgs := args.(Arguments)
 	c.fanout = newArgs.ForwardTo
+	c.limiter.Update(newArgs.Limit)
 
 	cfg, err := newArgs.Convert()
 	if err != nil {
@@ -15,6 +15,7 @@ import (
 
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/limit"
 	"github.com/grafana/alloy/internal/component/common/loki/positions"
 	cft "github.com/grafana/alloy/internal/component/loki/source/cloudflare/internal/cloudflaretarget"
 	"github.com/grafana/alloy/internal/featuregate"
@@ -46,6 +47,7 @@ type Arguments struct {
 	FieldsType       string              `alloy:"fields_type,attr,optional"`
 	AdditionalFields []string            `alloy:"additional_fields,attr,optional"`
 	ForwardTo        []loki.LogsReceiver `alloy:"forward_to,attr"`
+	Limit            *limit.Arguments    `alloy:"limit,block,optional"`
 }
 
 // Convert returns a cloudflaretarget Config struct from the Arguments.
@@ -93,6 +95,7 @@ func (c *Arguments) Validate() error {
 type Component struct {
 	opts    component.Options
 	metrics *cft.Metrics
+	limiter *limit.Limiter
 
 	mut    sync.RWMutex
 	fanout []loki.LogsReceiver
@@ -121,6 +124,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	c := &Component{
 		opts:    o,
 		metrics: cft.NewMetrics(o.Registerer),
+		limiter: limit.NewLimiter(o.Registerer),
 		handler: loki.NewLogsReceiver(),
 		fanout:  args.ForwardTo,
 		posFile: positionsFile,
@@ -148,6 +152,9 @@ func (c *Component) Run(ctx context.Context) error {
 		case <-ctx.Done():
 			return nil
 		case entry := <-c.handler.Chan():
+			if !c.limiter.Allow(ctx) {
+				continue
+			}
 			c.mut.RLock()
 			for _, receiver := range c.fanout {
 				receiver.Chan() <- entry
@@ -164,6 +171,7 @@ func (c *Component) Update(args component.Arguments) error {
 
 	newArgs := args.(Arguments)
 	c.fanout = newArgs.ForwardTo
+	c.limiter.Update(newArgs.Limit)
 
 	if c.target != nil {
 		c.target.Stop()
@@ -23,6 +23,7 @@ import (
 	"github.com/grafana/alloy/internal/component"
 	types "github.com/grafana/alloy/internal/component/common/config"
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/limit"
 	"github.com/grafana/alloy/internal/component/common/loki/positions"
 	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
 	"github.com/grafana/alloy/internal/component/discovery"
@@ -62,6 +63,7 @@ type Arguments struct {
 	RelabelRules     alloy_relabel.Rules     `alloy:"relabel_rules,attr,optional"`
 	HTTPClientConfig *types.HTTPClientConfig `alloy:"http_client_config,block,optional"`
 	RefreshInterval  time.Duration           `alloy:"refresh_interval,attr,optional"`
+	Limit            *limit.Arguments        `alloy:"limit,block,optional"`
 }
 
 // GetDefaultArguments return an instance of Arguments with the optional fields
@@ -103,6 +105,7 @@ var (
 type Component struct {
 	opts    component.Options
 	metrics *dt.Metrics
+	limiter *limit.Limiter
 
 	mut           sync.RWMutex
 	args          Arguments
@@ -136,6 +139,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	c := &Component{
 		opts:    o,
 		metrics: dt.NewMetrics(o.Registerer),
+		limiter: limit.NewLimiter(o.Registerer),
 
 		handler:   loki.NewLogsReceiver(),
 		manager:   newManager(o.Logger, nil),
@@ -171,6 +175,9 @@ func (c *Component) Run(ctx context.Context) error {
 		case <-ctx.Done():
 			return nil
 		case entry := <-c.handler.Chan():
+			if !c.limiter.Allow(ctx) {
+				continue
+			}
 			c.receiversMut.RLock()
 			receivers := c.receivers
 			c.receiversMut.RUnlock()
@@ -194,6 +201,7 @@ func (c *Component) Update(args component.Arguments) error {
 	c.receiversMut.Lock()
 	c.receivers = newArgs.ForwardTo
 	c.receiversMut.Unlock()
+	c.limiter.Update(newArgs.Limit)
 
 	c.mut.Lock()
 	defer c.mut.Unlock()
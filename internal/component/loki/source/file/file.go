@@ -15,6 +15,7 @@ import (
 
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/limit"
 	"github.com/grafana/alloy/internal/component/common/loki/positions"
 	"github.com/grafana/alloy/internal/component/discovery"
 	"github.com/grafana/alloy/internal/featuregate"
@@ -49,6 +50,7 @@ type Arguments struct {
 	FileWatch           FileWatch           `alloy:"file_watch,block,optional"`
 	TailFromEnd         bool                `alloy:"tail_from_end,attr,optional"`
 	LegacyPositionsFile string              `alloy:"legacy_positions_file,attr,optional"`
+	Limit               *limit.Arguments    `alloy:"limit,block,optional"`
 }
 
 type FileWatch struct {
@@ -80,6 +82,7 @@ var _ component.Component = (*Component)(nil)
 type Component struct {
 	opts    component.Options
 	metrics *metrics
+	limiter *limit.Limiter
 
 	updateMut sync.Mutex
 
@@ -119,6 +122,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	c := &Component{
 		opts:    o,
 		metrics: newMetrics(o.Registerer),
+		limiter: limit.NewLimiter(o.Registerer),
 
 		handler:       loki.NewLogsReceiver(),
 		receivers:     args.ForwardTo,
@@ -157,6 +161,9 @@ func (c *Component) Run(ctx context.Context) error {
 		case <-ctx.Done():
 			return nil
 		case entry := <-c.handler.Chan():
+			if !c.limiter.Allow(ctx) {
+				continue
+			}
 			c.mut.RLock()
 			for _, receiver := range c.receivers {
 				receiver.Chan() <- entry
@@ -191,6 +198,7 @@ func (c *Component) Update(args component.Arguments) error {
 	defer c.mut.Unlock()
 	c.args = newArgs
 	c.receivers = newArgs.ForwardTo
+	c.limiter.Update(newArgs.Limit)
 
 	c.tasks = make(map[positions.Entry]runnerTask)
 
@@ -13,6 +13,7 @@ import (
 
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/limit"
 	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
 	"github.com/grafana/alloy/internal/component/loki/source/gcplog/gcptypes"
 	gt "github.com/grafana/alloy/internal/component/loki/source/gcplog/internal/gcplogtarget"
@@ -38,6 +39,7 @@ type Arguments struct {
 	PushTarget   *gcptypes.PushConfig `alloy:"push,block,optional"`
 	ForwardTo    []loki.LogsReceiver  `alloy:"forward_to,attr"`
 	RelabelRules alloy_relabel.Rules  `alloy:"relabel_rules,attr,optional"`
+	Limit        *limit.Arguments     `alloy:"limit,block,optional"`
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -58,6 +60,7 @@ type Component struct {
 	opts          component.Options
 	metrics       *gt.Metrics
 	serverMetrics *util.UncheckedCollector
+	limiter       *limit.Limiter
 
 	mut    sync.RWMutex
 	fanout []loki.LogsReceiver
@@ -71,6 +74,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	c := &Component{
 		opts:          o,
 		metrics:       gt.NewMetrics(o.Registerer),
+		limiter:       limit.NewLimiter(o.Registerer),
 		handler:       loki.NewLogsReceiver(),
 		fanout:        args.ForwardTo,
 		serverMetrics: util.NewUncheckedCollector(nil),
@@ -103,6 +107,9 @@ func (c *Component) Run(ctx context.Context) error {
 		case <-ctx.Done():
 			return nil
 		case entry := <-c.handler.Chan():
+			if !c.limiter.Allow(ctx) {
+				continue
+			}
 			c.mut.RLock()
 			for _, receiver := range c.fanout {
 				receiver.Chan() <- entry
@@ -119,6 +126,7 @@ func (c *Component) Update(args component.Arguments) error {
 
 	newArgs := args.(Arguments)
 	c.fanout = newArgs.ForwardTo
+	c.limiter.Update(newArgs.Limit)
 
 	var rcs []*relabel.Config
 	if len(newArgs.RelabelRules) > 0 {
@@ -20,6 +20,7 @@ import (
 	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
 	"github.com/grafana/alloy/internal/component/loki/source/gcplog/gcptypes"
 	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax"
 )
 
 // TODO (@tpaschalis) We can't test this easily as there's no way to inject
@@ -141,6 +142,50 @@ const testPushPayload = `
 	"subscription": "projects/test-project/subscriptions/test"
 }`
 
+func TestPullArguments(t *testing.T) {
+	for _, tc := range []struct {
+		name        string
+		settings    string
+		expectedErr string
+	}{
+		{name: "defaults"},
+		{
+			name: "ack deadline",
+			settings: `
+				max_extension            = "30m"
+				max_extension_period     = "2m"
+				max_outstanding_messages = 100`,
+		},
+		{
+			name:        "extension period too short",
+			settings:    `max_extension_period = "1s"`,
+			expectedErr: "max_extension_period must be between 10s and 600s",
+		},
+		{
+			name:        "negative outstanding messages",
+			settings:    `max_outstanding_messages = -1`,
+			expectedErr: "max_outstanding_messages must not be negative",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(`
+				pull {
+					project_id   = "test-project"
+					subscription = "test-subscription"
+					`+tc.settings+`
+				}
+				forward_to = []
+			`), &args)
+			if tc.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectedErr)
+			}
+		})
+	}
+}
+
 var exportedRules = alloy_relabel.Rules{
 	{
 		SourceLabels: []string{"__gcp_message_id"},
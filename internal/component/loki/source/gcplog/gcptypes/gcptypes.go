@@ -14,15 +14,43 @@ type PullConfig struct {
 	Labels               map[string]string `alloy:"labels,attr,optional"`
 	UseIncomingTimestamp bool              `alloy:"use_incoming_timestamp,attr,optional"`
 	UseFullLine          bool              `alloy:"use_full_line,attr,optional"`
+
+	// Ack deadline management and flow control of the subscriber. Zero values
+	// use the defaults of the Pub/Sub client.
+	MaxExtension           time.Duration `alloy:"max_extension,attr,optional"`
+	MaxExtensionPeriod     time.Duration `alloy:"max_extension_period,attr,optional"`
+	MaxOutstandingMessages int           `alloy:"max_outstanding_messages,attr,optional"`
+}
+
+// Validate implements syntax.Validator.
+func (p *PullConfig) Validate() error {
+	if p.MaxExtension < 0 {
+		return fmt.Errorf("max_extension must not be negative")
+	}
+	if p.MaxExtensionPeriod != 0 && (p.MaxExtensionPeriod < 10*time.Second || p.MaxExtensionPeriod > 600*time.Second) {
+		return fmt.Errorf("max_extension_period must be between 10s and 600s")
+	}
+	if p.MaxOutstandingMessages < 0 {
+		return fmt.Errorf("max_outstanding_messages must not be negative")
+	}
+	return nil
 }
 
 // PushConfig configures a GCPLog target with the 'push' strategy.
 type PushConfig struct {
-	Server               *fnet.ServerConfig `alloy:",squash"`
-	PushTimeout          time.Duration      `alloy:"push_timeout,attr,optional"`
-	Labels               map[string]string  `alloy:"labels,attr,optional"`
-	UseIncomingTimestamp bool               `alloy:"use_incoming_timestamp,attr,optional"`
-	UseFullLine          bool               `alloy:"use_full_line,attr,optional"`
+	Server               *fnet.ServerConfig  `alloy:",squash"`
+	PushTimeout          time.Duration       `alloy:"push_timeout,attr,optional"`
+	Labels               map[string]string   `alloy:"labels,attr,optional"`
+	UseIncomingTimestamp bool                `alloy:"use_incoming_timestamp,attr,optional"`
+	UseFullLine          bool                `alloy:"use_full_line,attr,optional"`
+	Authentication       *PushAuthentication `alloy:"authentication,block,optional"`
+}
+
+// PushAuthentication configures the verification of the OIDC tokens sent by
+// authenticated push subscriptions.
+type PushAuthentication struct {
+	Audience            string `alloy:"audience,attr"`
+	ServiceAccountEmail string `alloy:"service_account_email,attr,optional"`
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -18,11 +18,17 @@ type Metrics struct {
 	gcplogEntries                 *prometheus.CounterVec
 	gcplogErrors                  *prometheus.CounterVec
 	gcplogTargetLastSuccessScrape *prometheus.GaugeVec
+	gcplogLag                     *prometheus.HistogramVec
 
 	gcpPushEntries *prometheus.CounterVec
 	gcpPushErrors  *prometheus.CounterVec
+	gcpPushLag     *prometheus.HistogramVec
 }
 
+// lagBuckets are the buckets of the time between the publication of a message
+// and its reception, from 100ms to about 2h.
+var lagBuckets = prometheus.ExponentialBuckets(0.1, 2, 17)
+
 // NewMetrics creates a new set of metrics. Metrics will be registered to reg.
 func NewMetrics(reg prometheus.Registerer) *Metrics {
 	var m Metrics
@@ -44,6 +50,12 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 		Help: "Timestamp of target's last successful poll",
 	}, []string{"project", "target"})
 
+	m.gcplogLag = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "loki_source_gcplog_pull_message_lag_seconds",
+		Help:    "Time between the publication of a message and its reception by the gcplog target",
+		Buckets: lagBuckets,
+	}, []string{"project", "subscription"})
+
 	// Push subscription metrics
 	m.gcpPushEntries = prometheus.NewCounterVec(prometheus.CounterOpts{
 		Name: "loki_source_gcplog_push_entries_total",
@@ -55,10 +67,18 @@ func NewMetrics(reg prometheus.Registerer) *Metrics {
 		Help: "Number of parsing errors while receiving gcplog messages",
 	}, []string{"reason"})
 
+	m.gcpPushLag = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "loki_source_gcplog_push_message_lag_seconds",
+		Help:    "Time between the publication of a message and its reception by the gcplog target",
+		Buckets: lagBuckets,
+	}, []string{"subscription"})
+
 	m.gcplogEntries = util.MustRegisterOrGet(reg, m.gcplogEntries).(*prometheus.CounterVec)
 	m.gcplogErrors = util.MustRegisterOrGet(reg, m.gcplogErrors).(*prometheus.CounterVec)
 	m.gcplogTargetLastSuccessScrape = util.MustRegisterOrGet(reg, m.gcplogTargetLastSuccessScrape).(*prometheus.GaugeVec)
+	m.gcplogLag = util.MustRegisterOrGet(reg, m.gcplogLag).(*prometheus.HistogramVec)
 	m.gcpPushEntries = util.MustRegisterOrGet(reg, m.gcpPushEntries).(*prometheus.CounterVec)
 	m.gcpPushErrors = util.MustRegisterOrGet(reg, m.gcpPushErrors).(*prometheus.CounterVec)
+	m.gcpPushLag = util.MustRegisterOrGet(reg, m.gcpPushLag).(*prometheus.HistogramVec)
 	return &m
 }
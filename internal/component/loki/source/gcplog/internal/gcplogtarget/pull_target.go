@@ -66,6 +66,17 @@ func NewPullTarget(metrics *Metrics, logger log.Logger, handler loki.EntryHandle
 		return nil, err
 	}
 
+	sub := ps.SubscriptionInProject(config.Subscription, config.ProjectID)
+	if config.MaxExtension > 0 {
+		sub.ReceiveSettings.MaxExtension = config.MaxExtension
+	}
+	if config.MaxExtensionPeriod > 0 {
+		sub.ReceiveSettings.MaxExtensionPeriod = config.MaxExtensionPeriod
+	}
+	if config.MaxOutstandingMessages > 0 {
+		sub.ReceiveSettings.MaxOutstandingMessages = config.MaxOutstandingMessages
+	}
+
 	target := &PullTarget{
 		metrics:       metrics,
 		logger:        logger,
@@ -76,7 +87,7 @@ func NewPullTarget(metrics *Metrics, logger log.Logger, handler loki.EntryHandle
 		ctx:           ctx,
 		cancel:        cancel,
 		ps:            ps,
-		sub:           ps.SubscriptionInProject(config.Subscription, config.ProjectID),
+		sub:           sub,
 		backoff:       backoff.New(ctx, defaultBackoff),
 		msgs:          make(chan *pubsub.Message),
 	}
@@ -107,6 +118,9 @@ func (t *PullTarget) run() error {
 		case <-t.ctx.Done():
 			return t.ctx.Err()
 		case m := <-t.msgs:
+			if !m.PublishTime.IsZero() {
+				t.metrics.gcplogLag.WithLabelValues(t.config.ProjectID, t.config.Subscription).Observe(time.Since(m.PublishTime).Seconds())
+			}
 			entry, err := parseGCPLogsEntry(m.Data, lbls, nil, t.config.UseIncomingTimestamp, t.config.UseFullLine, t.relabelConfig)
 			if err != nil {
 				level.Error(t.logger).Log("event", "error formating log entry", "cause", err)
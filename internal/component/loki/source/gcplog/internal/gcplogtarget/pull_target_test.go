@@ -11,6 +11,7 @@ import (
 	"github.com/go-kit/log"
 	"github.com/grafana/dskit/backoff"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"gotest.tools/assert"
 
@@ -239,3 +240,19 @@ var testBackoff = backoff.Config{
 	MinBackoff: 1 * time.Millisecond,
 	MaxBackoff: 10 * time.Millisecond,
 }
+
+func TestPullTarget_Lag(t *testing.T) {
+	tc := testPullTarget(t)
+
+	go func() {
+		_ = tc.target.run()
+	}()
+
+	tc.sub.messages <- &pubsub.Message{Data: []byte(gcpLogEntry), PublishTime: time.Now().Add(-time.Minute)}
+	require.Eventually(t, func() bool {
+		return len(tc.promClient.Received()) > 0
+	}, time.Second, 50*time.Millisecond)
+	require.NoError(t, tc.target.Stop())
+
+	require.Equal(t, 1, testutil.CollectAndCount(tc.target.metrics.gcplogLag))
+}
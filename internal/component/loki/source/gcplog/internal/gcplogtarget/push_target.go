@@ -8,9 +8,12 @@ package gcplogtarget
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/gorilla/mux"
@@ -18,6 +21,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/relabel"
+	"google.golang.org/api/idtoken"
 
 	"github.com/grafana/alloy/internal/component/common/loki"
 	fnet "github.com/grafana/alloy/internal/component/common/net"
@@ -37,6 +41,10 @@ type PushTarget struct {
 	server         *fnet.TargetServer
 }
 
+// validateIDToken validates a Google-signed OIDC token, and is replaced in
+// tests.
+var validateIDToken = idtoken.Validate
+
 // NewPushTarget constructs a PushTarget.
 func NewPushTarget(metrics *Metrics, logger log.Logger, handler loki.EntryHandler, jobName string, config *gcptypes.PushConfig, relabel []*relabel.Config, reg prometheus.Registerer) (*PushTarget, error) {
 	wrappedLogger := log.With(logger, "component", "gcp_push")
@@ -68,6 +76,15 @@ func NewPushTarget(metrics *Metrics, logger log.Logger, handler loki.EntryHandle
 func (p *PushTarget) push(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
+	if p.config.Authentication != nil {
+		if err := p.authenticate(r); err != nil {
+			p.metrics.gcpPushErrors.WithLabelValues("unauthorized").Inc()
+			level.Warn(p.logger).Log("msg", "failed to authenticate gcp push request", "err", err.Error())
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+	}
+
 	// Create no-op context.WithTimeout returns to simplify logic
 	ctx := r.Context()
 	cancel := context.CancelFunc(func() {})
@@ -98,6 +115,10 @@ func (p *PushTarget) push(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if publishTime, err := time.Parse(time.RFC3339Nano, pushMessage.Message.PublishTimestamp); err == nil {
+		p.metrics.gcpPushLag.WithLabelValues(pushMessage.Subscription).Observe(time.Since(publishTime).Seconds())
+	}
+
 	entry, err := translate(pushMessage, p.Labels(), p.config.UseIncomingTimestamp, p.config.UseFullLine, p.relabelConfigs, r.Header.Get("X-Scope-OrgID"))
 	if err != nil {
 		p.metrics.gcpPushErrors.WithLabelValues("translation").Inc()
@@ -121,6 +142,25 @@ func (p *PushTarget) push(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusNoContent)
 }
 
+// authenticate verifies the OIDC token that authenticated push subscriptions
+// send in the Authorization header.
+func (p *PushTarget) authenticate(r *http.Request) error {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || token == "" {
+		return errors.New("missing bearer token")
+	}
+	payload, err := validateIDToken(r.Context(), token, p.config.Authentication.Audience)
+	if err != nil {
+		return err
+	}
+	if email := p.config.Authentication.ServiceAccountEmail; email != "" {
+		if payload.Claims["email"] != email || payload.Claims["email_verified"] != true {
+			return fmt.Errorf("token wasn't issued for service account %s", email)
+		}
+	}
+	return nil
+}
+
 func (p *PushTarget) doSendEntry(ctx context.Context, entry loki.Entry) error {
 	select {
 	// Timeout the loki.Entry channel send operation, which is the only blocking operation in the handler
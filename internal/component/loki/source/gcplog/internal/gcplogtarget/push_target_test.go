@@ -1,6 +1,7 @@
 package gcplogtarget
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
@@ -12,9 +13,11 @@ import (
 	"github.com/go-kit/log"
 	"github.com/phayes/freeport"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/model/relabel"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/api/idtoken"
 
 	"github.com/grafana/alloy/internal/component/common/loki"
 	"github.com/grafana/alloy/internal/component/common/loki/client/fake"
@@ -473,3 +476,68 @@ func waitForMessages(eh *fake.Client) {
 		countdown--
 	}
 }
+
+func TestPushTarget_Authentication(t *testing.T) {
+	validateIDToken = func(_ context.Context, token string, audience string) (*idtoken.Payload, error) {
+		if audience != "https://alloy.example.com/gcp/api/v1/push" {
+			return nil, fmt.Errorf("unexpected audience %s", audience)
+		}
+		switch token {
+		case "valid":
+			return &idtoken.Payload{Claims: map[string]any{"email": "pubsub@test-project.iam.gserviceaccount.com", "email_verified": true}}, nil
+		case "other-account":
+			return &idtoken.Payload{Claims: map[string]any{"email": "other@test-project.iam.gserviceaccount.com", "email_verified": true}}, nil
+		default:
+			return nil, fmt.Errorf("invalid token")
+		}
+	}
+	defer func() { validateIDToken = idtoken.Validate }()
+
+	eh := fake.NewClient(func() {})
+	defer eh.Stop()
+
+	port, err := freeport.GetFreePort()
+	require.NoError(t, err)
+	config := &gcptypes.PushConfig{
+		Server: &fnet.ServerConfig{
+			HTTP: &fnet.HTTPConfig{
+				ListenAddress: "localhost",
+				ListenPort:    port,
+			},
+			// assign random grpc port
+			GRPC: &fnet.GRPCConfig{ListenPort: 0},
+		},
+		Authentication: &gcptypes.PushAuthentication{
+			Audience:            "https://alloy.example.com/gcp/api/v1/push",
+			ServiceAccountEmail: "pubsub@test-project.iam.gserviceaccount.com",
+		},
+	}
+
+	reg := prometheus.NewRegistry()
+	pt, err := NewPushTarget(NewMetrics(reg), log.NewNopLogger(), eh, t.Name()+"_test_job", config, nil, nil)
+	require.NoError(t, err)
+	defer func() {
+		_ = pt.Stop()
+	}()
+
+	for header, expectedStatus := range map[string]int{
+		"":                     http.StatusUnauthorized,
+		"Bearer invalid":       http.StatusUnauthorized,
+		"Bearer other-account": http.StatusUnauthorized,
+		"Bearer valid":         http.StatusNoContent,
+	} {
+		req, err := makeGCPPushRequest(fmt.Sprintf("http://%s:%d", localhost, port), testPayload)
+		require.NoError(t, err)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		require.Equal(t, expectedStatus, res.StatusCode, "Authorization: %q", header)
+	}
+
+	waitForMessages(eh)
+	require.Len(t, eh.Received(), 1)
+	require.Equal(t, 3.0, testutil.ToFloat64(pt.metrics.gcpPushErrors.WithLabelValues("unauthorized")))
+	require.Equal(t, 1, testutil.CollectAndCount(pt.metrics.gcpPushLag))
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/limit"
 	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
 	"github.com/grafana/alloy/internal/component/loki/source/gelf/internal/target"
 	"github.com/grafana/alloy/internal/featuregate"
@@ -35,6 +36,7 @@ type Component struct {
 	target    *target.Target
 	o         component.Options
 	metrics   *target.Metrics
+	limiter   *limit.Limiter
 	handler   *handler
 	receivers []loki.LogsReceiver
 }
@@ -49,6 +51,9 @@ func (c *Component) Run(ctx context.Context) error {
 		case <-ctx.Done():
 			return nil
 		case entry := <-c.handler.c:
+			if !c.limiter.Allow(ctx) {
+				continue
+			}
 			c.mut.RLock()
 			lokiEntry := loki.Entry{
 				Labels: entry.Labels,
@@ -76,6 +81,7 @@ func (c *Component) Update(args component.Arguments) error {
 		c.target.Stop()
 	}
 	c.receivers = newArgs.Receivers
+	c.limiter.Update(newArgs.Limit)
 
 	var rcs []*relabel.Config
 	if len(newArgs.RelabelRules) > 0 {
@@ -97,6 +103,7 @@ type Arguments struct {
 	UseIncomingTimestamp bool                `alloy:"use_incoming_timestamp,attr,optional"`
 	RelabelRules         alloy_relabel.Rules `alloy:"relabel_rules,attr,optional"`
 	Receivers            []loki.LogsReceiver `alloy:"forward_to,attr"`
+	Limit                *limit.Arguments    `alloy:"limit,block,optional"`
 }
 
 func defaultArgs() Arguments {
@@ -125,6 +132,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	c := &Component{
 		o:       o,
 		metrics: metrics,
+		limiter: limit.NewLimiter(o.Registerer),
 		handler: &handler{c: make(chan loki.Entry)},
 	}
 	// Call to Update() to start readers and set receivers once at the start.
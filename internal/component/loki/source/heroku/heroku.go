@@ -7,6 +7,7 @@ import (
 
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/limit"
 	fnet "github.com/grafana/alloy/internal/component/common/net"
 	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
 	ht "github.com/grafana/alloy/internal/component/loki/source/heroku/internal/herokutarget"
@@ -38,6 +39,7 @@ type Arguments struct {
 	UseIncomingTimestamp bool                `alloy:"use_incoming_timestamp,attr,optional"`
 	ForwardTo            []loki.LogsReceiver `alloy:"forward_to,attr"`
 	RelabelRules         alloy_relabel.Rules `alloy:"relabel_rules,attr,optional"`
+	Limit                *limit.Arguments    `alloy:"limit,block,optional"`
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -52,6 +54,7 @@ type Component struct {
 	opts          component.Options
 	metrics       *ht.Metrics              // Metrics about Heroku entries.
 	serverMetrics *util.UncheckedCollector // Metircs about the HTTP server managed by the component.
+	limiter       *limit.Limiter
 
 	mut    sync.RWMutex
 	args   Arguments
@@ -66,6 +69,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	c := &Component{
 		opts:          o,
 		metrics:       ht.NewMetrics(o.Registerer),
+		limiter:       limit.NewLimiter(o.Registerer),
 		mut:           sync.RWMutex{},
 		args:          Arguments{},
 		fanout:        args.ForwardTo,
@@ -104,6 +108,9 @@ func (c *Component) Run(ctx context.Context) error {
 		case <-ctx.Done():
 			return nil
 		case entry := <-c.handler.Chan():
+			if !c.limiter.Allow(ctx) {
+				continue
+			}
 			c.mut.RLock()
 			for _, receiver := range c.fanout {
 				receiver.Chan() <- entry
@@ -120,6 +127,7 @@ func (c *Component) Update(args component.Arguments) error {
 
 	newArgs := args.(Arguments)
 	c.fanout = newArgs.ForwardTo
+	c.limiter.Update(newArgs.Limit)
 
 	var rcs []*relabel.Config
 	if len(newArgs.RelabelRules) > 0 {
@@ -76,6 +76,9 @@ type TokenProviderType string
 const (
 	// TokenProviderTypeAzure represents using the Azure as the token provider
 	TokenProviderTypeAzure TokenProviderType = "azure"
+	// TokenProviderTypeClientCredentials represents using the OAuth 2.0 client
+	// credentials flow as the token provider
+	TokenProviderTypeClientCredentials TokenProviderType = "client_credentials"
 )
 
 // KafkaSASLConfig describe the SASL configuration for authentication with Kafka brokers
@@ -104,6 +107,12 @@ type OAuthConfig struct {
 	TokenProvider TokenProviderType `yaml:"token_provider,omitempty"`
 
 	Scopes []string
+
+	// Client credentials used by the client_credentials token provider
+	TokenURL       string
+	ClientID       string
+	ClientSecret   flagext.Secret
+	EndpointParams map[string]string
 }
 
 // MessageParser defines parsing for each incoming message
@@ -3,14 +3,21 @@ package kafkatarget
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"net/url"
 	"time"
 
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
 	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
 	"github.com/IBM/sarama"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
 )
 
+// tokenRequestTimeout is how long to wait for an access token.
+const tokenRequestTimeout = 5 * time.Second
+
 func NewOAuthProvider(opts OAuthConfig) (sarama.AccessTokenProvider, error) {
 	switch opts.TokenProvider {
 	case TokenProviderTypeAzure:
@@ -19,6 +26,21 @@ func NewOAuthProvider(opts OAuthConfig) (sarama.AccessTokenProvider, error) {
 			return nil, err
 		}
 		return &TokenProviderAzure{tokenProvider: cred, scopes: opts.Scopes}, nil
+	case TokenProviderTypeClientCredentials:
+		cfg := clientcredentials.Config{
+			ClientID:     opts.ClientID,
+			ClientSecret: opts.ClientSecret.String(),
+			TokenURL:     opts.TokenURL,
+			Scopes:       opts.Scopes,
+		}
+		if len(opts.EndpointParams) > 0 {
+			cfg.EndpointParams = url.Values{}
+			for k, v := range opts.EndpointParams {
+				cfg.EndpointParams.Set(k, v)
+			}
+		}
+		ctx := context.WithValue(context.Background(), oauth2.HTTPClient, &http.Client{Timeout: tokenRequestTimeout})
+		return &TokenProviderClientCredentials{tokenSource: cfg.TokenSource(ctx)}, nil
 	default:
 		return nil, fmt.Errorf("token provider '%s' is not supported", opts.TokenProvider)
 	}
@@ -36,7 +58,7 @@ type TokenProviderAzure struct {
 
 // Token returns a new *sarama.AccessToken or an error
 func (t *TokenProviderAzure) Token() (*sarama.AccessToken, error) {
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), tokenRequestTimeout)
 	defer cancel()
 	token, err := t.tokenProvider.GetToken(ctx, policy.TokenRequestOptions{Scopes: t.scopes})
 	if err != nil {
@@ -44,3 +66,19 @@ func (t *TokenProviderAzure) Token() (*sarama.AccessToken, error) {
 	}
 	return &sarama.AccessToken{Token: token.Token}, nil
 }
+
+// TokenProviderClientCredentials implements sarama.AccessTokenProvider with
+// the OAuth 2.0 client credentials flow. The token is cached until it
+// expires, and sarama asks for a token each time it authenticates.
+type TokenProviderClientCredentials struct {
+	tokenSource oauth2.TokenSource
+}
+
+// Token returns a new *sarama.AccessToken or an error
+func (t *TokenProviderClientCredentials) Token() (*sarama.AccessToken, error) {
+	token, err := t.tokenSource.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire token: %w", err)
+	}
+	return &sarama.AccessToken{Token: token.AccessToken}, nil
+}
@@ -0,0 +1,72 @@
+package kafkatarget
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/dskit/flagext"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenProviderClientCredentials(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		require.NoError(t, r.ParseForm())
+		require.Equal(t, "client_credentials", r.PostForm.Get("grant_type"))
+		require.Equal(t, "kafka", r.PostForm.Get("scope"))
+		require.Equal(t, "kafka-cluster", r.PostForm.Get("audience"))
+		clientID, clientSecret, ok := r.BasicAuth()
+		require.True(t, ok)
+		require.Equal(t, "alloy", clientID)
+		require.Equal(t, "secret", clientSecret)
+
+		w.Header().Set("Content-Type", "application/json")
+		require.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "my-token",
+			"token_type":   "Bearer",
+			"expires_in":   3600,
+		}))
+	}))
+	defer srv.Close()
+
+	provider, err := NewOAuthProvider(OAuthConfig{
+		TokenProvider:  TokenProviderTypeClientCredentials,
+		Scopes:         []string{"kafka"},
+		TokenURL:       srv.URL,
+		ClientID:       "alloy",
+		ClientSecret:   flagext.SecretWithValue("secret"),
+		EndpointParams: map[string]string{"audience": "kafka-cluster"},
+	})
+	require.NoError(t, err)
+
+	token, err := provider.Token()
+	require.NoError(t, err)
+	require.Equal(t, "my-token", token.Token)
+
+	// The token is reused until it expires.
+	token, err = provider.Token()
+	require.NoError(t, err)
+	require.Equal(t, "my-token", token.Token)
+	require.Equal(t, 1, requests)
+}
+
+func TestTokenProviderClientCredentials_Error(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"error":"invalid_client"}`, http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	provider, err := NewOAuthProvider(OAuthConfig{
+		TokenProvider: TokenProviderTypeClientCredentials,
+		TokenURL:      srv.URL,
+		ClientID:      "alloy",
+		ClientSecret:  flagext.SecretWithValue("wrong"),
+	})
+	require.NoError(t, err)
+
+	_, err = provider.Token()
+	require.ErrorContains(t, err, "failed to acquire token")
+}
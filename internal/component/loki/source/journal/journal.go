@@ -13,6 +13,7 @@ import (
 	"github.com/prometheus/common/model"
 
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/limit"
 	"github.com/grafana/alloy/internal/component/common/loki/positions"
 	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
 	"github.com/grafana/alloy/internal/component/loki/source/journal/internal/target"
@@ -40,6 +41,7 @@ type Component struct {
 	mut       sync.RWMutex
 	t         *target.JournalTarget
 	metrics   *target.Metrics
+	limiter   *limit.Limiter
 	o         component.Options
 	handler   chan loki.Entry
 	positions positions.Positions
@@ -65,6 +67,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 
 	c := &Component{
 		metrics:   target.NewMetrics(o.Registerer),
+		limiter:   limit.NewLimiter(o.Registerer),
 		o:         o,
 		handler:   make(chan loki.Entry),
 		positions: positionsFile,
@@ -89,6 +92,9 @@ func (c *Component) Run(ctx context.Context) error {
 		case <-ctx.Done():
 			return nil
 		case entry := <-c.handler:
+			if !c.limiter.Allow(ctx) {
+				continue
+			}
 			c.mut.RLock()
 			lokiEntry := loki.Entry{
 				Labels: entry.Labels,
@@ -107,6 +113,7 @@ func (c *Component) Update(args component.Arguments) error {
 	newArgs := args.(Arguments)
 	c.mut.Lock()
 	defer c.mut.Unlock()
+	c.limiter.Update(newArgs.Limit)
 	if c.t != nil {
 		err := c.t.Stop()
 		if err != nil {
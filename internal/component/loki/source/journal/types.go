@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/limit"
 	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
 )
 
@@ -16,6 +17,7 @@ type Arguments struct {
 	Matches      string              `alloy:"matches,attr,optional"`
 	Receivers    []loki.LogsReceiver `alloy:"forward_to,attr"`
 	Labels       map[string]string   `alloy:"labels,attr,optional"`
+	Limit        *limit.Arguments    `alloy:"limit,block,optional"`
 }
 
 func defaultArgs() Arguments {
@@ -2,12 +2,15 @@ package kafka
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"sync"
 
 	"github.com/IBM/sarama"
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/component/common/config"
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/limit"
 	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
 	kt "github.com/grafana/alloy/internal/component/loki/source/internal/kafkatarget"
 	"github.com/grafana/alloy/internal/featuregate"
@@ -43,6 +46,7 @@ type Arguments struct {
 
 	ForwardTo    []loki.LogsReceiver `alloy:"forward_to,attr"`
 	RelabelRules alloy_relabel.Rules `alloy:"relabel_rules,attr,optional"`
+	Limit        *limit.Arguments    `alloy:"limit,block,optional"`
 }
 
 // KafkaAuthentication describe the configuration for authentication with Kafka brokers
@@ -63,8 +67,32 @@ type KafkaSASLConfig struct {
 }
 
 type OAuthConfigConfig struct {
-	TokenProvider string   `alloy:"token_provider,attr"`
-	Scopes        []string `alloy:"scopes,attr"`
+	TokenProvider  string            `alloy:"token_provider,attr"`
+	Scopes         []string          `alloy:"scopes,attr,optional"`
+	TokenURL       string            `alloy:"token_url,attr,optional"`
+	ClientID       string            `alloy:"client_id,attr,optional"`
+	ClientSecret   alloytypes.Secret `alloy:"client_secret,attr,optional"`
+	EndpointParams map[string]string `alloy:"endpoint_params,attr,optional"`
+}
+
+// Validate implements syntax.Validator.
+func (c *OAuthConfigConfig) Validate() error {
+	switch kt.TokenProviderType(c.TokenProvider) {
+	case kt.TokenProviderTypeAzure:
+		if c.TokenURL != "" || c.ClientID != "" || c.ClientSecret != "" || len(c.EndpointParams) > 0 {
+			return errors.New("token_url, client_id, client_secret and endpoint_params can only be set with the client_credentials token provider")
+		}
+	case kt.TokenProviderTypeClientCredentials:
+		if c.TokenURL == "" {
+			return errors.New("token_url must be set with the client_credentials token provider")
+		}
+		if c.ClientID == "" || c.ClientSecret == "" {
+			return errors.New("client_id and client_secret must be set with the client_credentials token provider")
+		}
+	default:
+		return fmt.Errorf("unsupported token provider %q", c.TokenProvider)
+	}
+	return nil
 }
 
 // DefaultArguments provides the default arguments for a kafka component.
@@ -89,7 +117,8 @@ func (a *Arguments) SetToDefault() {
 
 // Component implements the loki.source.kafka component.
 type Component struct {
-	opts component.Options
+	opts    component.Options
+	limiter *limit.Limiter
 
 	mut    sync.RWMutex
 	fanout []loki.LogsReceiver
@@ -102,6 +131,7 @@ type Component struct {
 func New(o component.Options, args Arguments) (*Component, error) {
 	c := &Component{
 		opts:    o,
+		limiter: limit.NewLimiter(o.Registerer),
 		mut:     sync.RWMutex{},
 		fanout:  args.ForwardTo,
 		target:  nil,
@@ -136,6 +166,9 @@ func (c *Component) Run(ctx context.Context) error {
 		case <-ctx.Done():
 			return nil
 		case entry := <-c.handler.Chan():
+			if !c.limiter.Allow(ctx) {
+				continue
+			}
 			c.mut.RLock()
 			for _, receiver := range c.fanout {
 				receiver.Chan() <- entry
@@ -152,6 +185,7 @@ func (c *Component) Update(args component.Arguments) error {
 
 	newArgs := args.(Arguments)
 	c.fanout = newArgs.ForwardTo
+	c.limiter.Update(newArgs.Limit)
 
 	if c.target != nil {
 		err := c.target.Stop()
@@ -205,8 +239,12 @@ func (auth KafkaAuthentication) Convert() kt.Authentication {
 			UseTLS:    auth.SASLConfig.UseTLS,
 			TLSConfig: *auth.SASLConfig.TLSConfig.Convert(),
 			OAuthConfig: kt.OAuthConfig{
-				TokenProvider: kt.TokenProviderType(auth.SASLConfig.OAuthConfig.TokenProvider),
-				Scopes:        auth.SASLConfig.OAuthConfig.Scopes,
+				TokenProvider:  kt.TokenProviderType(auth.SASLConfig.OAuthConfig.TokenProvider),
+				Scopes:         auth.SASLConfig.OAuthConfig.Scopes,
+				TokenURL:       auth.SASLConfig.OAuthConfig.TokenURL,
+				ClientID:       auth.SASLConfig.OAuthConfig.ClientID,
+				ClientSecret:   flagext.SecretWithValue(string(auth.SASLConfig.OAuthConfig.ClientSecret)),
+				EndpointParams: auth.SASLConfig.OAuthConfig.EndpointParams,
 			},
 		},
 	}
@@ -3,6 +3,7 @@ package kafka
 import (
 	"testing"
 
+	kt "github.com/grafana/alloy/internal/component/loki/source/internal/kafkatarget"
 	"github.com/grafana/alloy/syntax"
 	"github.com/stretchr/testify/require"
 )
@@ -84,3 +85,87 @@ func TestSASLOAuthAlloyConfig(t *testing.T) {
 	err := syntax.Unmarshal([]byte(exampleAlloyConfig), &args)
 	require.NoError(t, err)
 }
+
+func TestSASLOAuthClientCredentialsAlloyConfig(t *testing.T) {
+	var exampleAlloyConfig = `
+	brokers = ["localhost:9092", "localhost:23456"]
+	topics  = ["quickstart-events"]
+
+	authentication {
+		type = "sasl"
+		sasl_config {
+			mechanism = "OAUTHBEARER"
+			use_tls   = true
+			oauth_config {
+				token_provider  = "client_credentials"
+				token_url       = "https://auth.example.com/oauth2/token"
+				client_id       = "alloy"
+				client_secret   = "secret"
+				scopes          = ["kafka"]
+				endpoint_params = {audience = "kafka-cluster"}
+			}
+		}
+	}
+	labels     = {component = "loki.source.kafka"}
+	forward_to = []
+`
+
+	var args Arguments
+	err := syntax.Unmarshal([]byte(exampleAlloyConfig), &args)
+	require.NoError(t, err)
+
+	oauthCfg := args.Convert().KafkaConfig.Authentication.SASLConfig.OAuthConfig
+	require.Equal(t, kt.TokenProviderTypeClientCredentials, oauthCfg.TokenProvider)
+	require.Equal(t, "https://auth.example.com/oauth2/token", oauthCfg.TokenURL)
+	require.Equal(t, "alloy", oauthCfg.ClientID)
+	require.Equal(t, "secret", oauthCfg.ClientSecret.String())
+	require.Equal(t, []string{"kafka"}, oauthCfg.Scopes)
+	require.Equal(t, map[string]string{"audience": "kafka-cluster"}, oauthCfg.EndpointParams)
+}
+
+func TestOAuthConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config OAuthConfigConfig
+		errMsg string
+	}{
+		{
+			name:   "azure",
+			config: OAuthConfigConfig{TokenProvider: "azure", Scopes: []string{"my-scope"}},
+		},
+		{
+			name:   "azure with client credentials",
+			config: OAuthConfigConfig{TokenProvider: "azure", ClientID: "alloy"},
+			errMsg: "token_url, client_id, client_secret and endpoint_params can only be set with the client_credentials token provider",
+		},
+		{
+			name:   "client credentials",
+			config: OAuthConfigConfig{TokenProvider: "client_credentials", TokenURL: "https://auth.example.com", ClientID: "alloy", ClientSecret: "secret"},
+		},
+		{
+			name:   "client credentials without token url",
+			config: OAuthConfigConfig{TokenProvider: "client_credentials", ClientID: "alloy", ClientSecret: "secret"},
+			errMsg: "token_url must be set with the client_credentials token provider",
+		},
+		{
+			name:   "client credentials without secret",
+			config: OAuthConfigConfig{TokenProvider: "client_credentials", TokenURL: "https://auth.example.com", ClientID: "alloy"},
+			errMsg: "client_id and client_secret must be set with the client_credentials token provider",
+		},
+		{
+			name:   "unknown token provider",
+			config: OAuthConfigConfig{TokenProvider: "aws"},
+			errMsg: `unsupported token provider "aws"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.errMsg == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.errMsg)
+			}
+		})
+	}
+}
@@ -16,6 +16,7 @@ import (
 	"github.com/grafana/alloy/internal/component"
 	commonk8s "github.com/grafana/alloy/internal/component/common/kubernetes"
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/limit"
 	"github.com/grafana/alloy/internal/component/common/loki/positions"
 	"github.com/grafana/alloy/internal/component/discovery"
 	"github.com/grafana/alloy/internal/component/loki/source/kubernetes/kubetail"
@@ -46,6 +47,7 @@ type Arguments struct {
 	Client commonk8s.ClientArguments `alloy:"client,block,optional"`
 
 	Clustering cluster.ComponentBlock `alloy:"clustering,block,optional"`
+	Limit      *limit.Arguments       `alloy:"limit,block,optional"`
 }
 
 // DefaultArguments holds default settings for loki.source.kubernetes.
@@ -62,6 +64,7 @@ func (args *Arguments) SetToDefault() {
 type Component struct {
 	log       log.Logger
 	opts      component.Options
+	limiter   *limit.Limiter
 	positions positions.Positions
 	cluster   cluster.Cluster
 
@@ -80,8 +83,15 @@ var (
 	_ component.Component      = (*Component)(nil)
 	_ component.DebugComponent = (*Component)(nil)
 	_ cluster.Component        = (*Component)(nil)
+	_ cluster.DrainComponent   = (*Component)(nil)
 )
 
+// drainHandoffPeriod is how long the component keeps tailing logs once the
+// local node announced it's leaving the cluster. Peers are notified of the
+// departure at most once per second and need a few seconds to start tailing
+// the targets they take over.
+const drainHandoffPeriod = 10 * time.Second
+
 // New creates a new loki.source.kubernetes component.
 func New(o component.Options, args Arguments) (*Component, error) {
 	err := os.MkdirAll(o.DataPath, 0750)
@@ -106,6 +116,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		cluster:   data.(cluster.Cluster),
 		log:       o.Logger,
 		opts:      o,
+		limiter:   limit.NewLimiter(o.Registerer),
 		handler:   loki.NewLogsReceiver(),
 		positions: positionsFile,
 	}
@@ -135,6 +146,9 @@ func (c *Component) Run(ctx context.Context) error {
 		case <-ctx.Done():
 			return nil
 		case entry := <-c.handler.Chan():
+			if !c.limiter.Allow(ctx) {
+				continue
+			}
 			c.receiversMut.RLock()
 			receivers := c.receivers
 			c.receiversMut.RUnlock()
@@ -154,6 +168,7 @@ func (c *Component) Update(args component.Arguments) error {
 	c.receiversMut.Lock()
 	c.receivers = newArgs.ForwardTo
 	c.receiversMut.Unlock()
+	c.limiter.Update(newArgs.Limit)
 
 	c.mut.Lock()
 	defer c.mut.Unlock()
@@ -221,6 +236,26 @@ func (c *Component) NotifyClusterChange() {
 	c.resyncTargets(c.args.Targets)
 }
 
+// Drain implements cluster.DrainComponent. It keeps tailing the local targets
+// for a short period so that peers can start tailing them before this
+// instance stops.
+func (c *Component) Drain(ctx context.Context) {
+	c.mut.Lock()
+	enabled := c.args.Clustering.Enabled
+	c.mut.Unlock()
+
+	if !enabled {
+		return
+	}
+
+	t := time.NewTimer(drainHandoffPeriod)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+}
+
 // getTailerOptions gets tailer options from arguments. If args hasn't changed
 // from the last call to getTailerOptions, c.lastOptions is returned.
 // c.lastOptions must be updated by the caller.
@@ -15,6 +15,7 @@ import (
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/component/common/kubernetes"
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/limit"
 	"github.com/grafana/alloy/internal/component/common/loki/positions"
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/runner"
@@ -49,6 +50,7 @@ type Arguments struct {
 
 	// Client settings to connect to Kubernetes.
 	Client kubernetes.ClientArguments `alloy:"client,block,optional"`
+	Limit  *limit.Arguments           `alloy:"limit,block,optional"`
 }
 
 // DefaultArguments holds default settings for loki.source.kubernetes_events.
@@ -81,6 +83,7 @@ func (args *Arguments) Validate() error {
 type Component struct {
 	log        log.Logger
 	opts       component.Options
+	limiter    *limit.Limiter
 	positions  positions.Positions
 	handler    loki.LogsReceiver
 	runner     *runner.Runner[eventControllerTask]
@@ -119,6 +122,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	c := &Component{
 		log:       o.Logger,
 		opts:      o,
+		limiter:   limit.NewLimiter(o.Registerer),
 		positions: positionsFile,
 		handler:   loki.NewLogsReceiver(),
 		runner: runner.New(func(t eventControllerTask) runner.Worker {
@@ -169,6 +173,9 @@ func (c *Component) Run(ctx context.Context) error {
 			case <-ctx.Done():
 				return nil
 			case entry := <-c.handler.Chan():
+				if !c.limiter.Allow(ctx) {
+					continue
+				}
 				c.receiversMut.RLock()
 				receivers := c.receivers
 				c.receiversMut.RUnlock()
@@ -195,6 +202,7 @@ func (c *Component) Update(args component.Arguments) error {
 	c.receiversMut.Lock()
 	c.receivers = newArgs.ForwardTo
 	c.receiversMut.Unlock()
+	c.limiter.Update(newArgs.Limit)
 
 	restConfig := c.restConfig
 
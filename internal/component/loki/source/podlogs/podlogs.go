@@ -14,6 +14,7 @@ import (
 	"github.com/grafana/alloy/internal/component/common/config"
 	commonk8s "github.com/grafana/alloy/internal/component/common/kubernetes"
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/limit"
 	"github.com/grafana/alloy/internal/component/common/loki/positions"
 	"github.com/grafana/alloy/internal/component/loki/source/kubernetes"
 	"github.com/grafana/alloy/internal/component/loki/source/kubernetes/kubetail"
@@ -49,6 +50,7 @@ type Arguments struct {
 	NamespaceSelector config.LabelSelector `alloy:"namespace_selector,block,optional"`
 
 	Clustering cluster.ComponentBlock `alloy:"clustering,block,optional"`
+	Limit      *limit.Arguments       `alloy:"limit,block,optional"`
 }
 
 // DefaultArguments holds default settings for loki.source.kubernetes.
@@ -63,8 +65,9 @@ func (args *Arguments) SetToDefault() {
 
 // Component implements the loki.source.podlogs component.
 type Component struct {
-	log  log.Logger
-	opts component.Options
+	log     log.Logger
+	opts    component.Options
+	limiter *limit.Limiter
 
 	tailer     *kubetail.Manager
 	reconciler *reconciler
@@ -114,8 +117,9 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	)
 
 	c := &Component{
-		log:  o.Logger,
-		opts: o,
+		log:     o.Logger,
+		opts:    o,
+		limiter: limit.NewLimiter(o.Registerer),
 
 		tailer:     tailer,
 		reconciler: reconciler,
@@ -176,6 +180,9 @@ func (c *Component) runHandler(ctx context.Context) {
 		case <-ctx.Done():
 			return
 		case entry := <-c.handler.Chan():
+			if !c.limiter.Allow(ctx) {
+				continue
+			}
 			c.receiversMut.RLock()
 			receivers := c.receivers
 			c.receiversMut.RUnlock()
@@ -195,6 +202,7 @@ func (c *Component) Update(args component.Arguments) error {
 	c.receiversMut.Lock()
 	c.receivers = newArgs.ForwardTo
 	c.receiversMut.Unlock()
+	c.limiter.Update(newArgs.Limit)
 
 	c.mut.Lock()
 	defer c.mut.Unlock()
@@ -7,6 +7,7 @@ import (
 
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/limit"
 	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
 	st "github.com/grafana/alloy/internal/component/loki/source/syslog/internal/syslogtarget"
 	"github.com/grafana/alloy/internal/featuregate"
@@ -32,12 +33,14 @@ type Arguments struct {
 	SyslogListeners []ListenerConfig    `alloy:"listener,block"`
 	ForwardTo       []loki.LogsReceiver `alloy:"forward_to,attr"`
 	RelabelRules    alloy_relabel.Rules `alloy:"relabel_rules,attr,optional"`
+	Limit           *limit.Arguments    `alloy:"limit,block,optional"`
 }
 
 // Component implements the loki.source.syslog component.
 type Component struct {
 	opts    component.Options
 	metrics *st.Metrics
+	limiter *limit.Limiter
 
 	mut     sync.RWMutex
 	args    Arguments
@@ -52,6 +55,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 	c := &Component{
 		opts:    o,
 		metrics: st.NewMetrics(o.Registerer),
+		limiter: limit.NewLimiter(o.Registerer),
 		handler: loki.NewLogsReceiver(),
 		fanout:  args.ForwardTo,
 
@@ -83,6 +87,9 @@ func (c *Component) Run(ctx context.Context) error {
 		case <-ctx.Done():
 			return nil
 		case entry := <-c.handler.Chan():
+			if !c.limiter.Allow(ctx) {
+				continue
+			}
 			c.mut.RLock()
 			for _, receiver := range c.fanout {
 				receiver.Chan() <- entry
@@ -99,6 +106,7 @@ func (c *Component) Update(args component.Arguments) error {
 
 	newArgs := args.(Arguments)
 	c.fanout = newArgs.ForwardTo
+	c.limiter.Update(newArgs.Limit)
 
 	var rcs []*relabel.Config
 	if len(newArgs.RelabelRules) > 0 {
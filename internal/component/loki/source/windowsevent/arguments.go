@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/limit"
 )
 
 // Arguments holds values which are used to configure the loki.source.windowsevent
@@ -25,6 +26,7 @@ type Arguments struct {
 	ForwardTo            []loki.LogsReceiver `alloy:"forward_to,attr"`
 	Labels               map[string]string   `alloy:"labels,attr,optional"`
 	LegacyBookmarkPath   string              `alloy:"legacy_bookmark_path,attr,optional"`
+	Limit                *limit.Arguments    `alloy:"limit,block,optional"`
 }
 
 func defaultArgs() Arguments {
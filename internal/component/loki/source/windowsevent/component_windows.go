@@ -12,6 +12,7 @@ import (
 
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/component/common/loki/limit"
 	"github.com/grafana/alloy/internal/component/common/loki/utils"
 	"github.com/grafana/alloy/internal/featuregate"
 )
@@ -34,7 +35,8 @@ var (
 
 // Component implements the loki.source.windowsevent component.
 type Component struct {
-	opts component.Options
+	opts    component.Options
+	limiter *limit.Limiter
 
 	mut       sync.RWMutex
 	args      Arguments
@@ -60,6 +62,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 
 	c := &Component{
 		opts:      o,
+		limiter:   limit.NewLimiter(o.Registerer),
 		receivers: args.ForwardTo,
 		handle:    &handler{handler: make(chan api.Entry)},
 		args:      args,
@@ -86,6 +89,9 @@ func (c *Component) Run(ctx context.Context) error {
 		case <-ctx.Done():
 			return nil
 		case entry := <-c.handle.handler:
+			if !c.limiter.Allow(ctx) {
+				continue
+			}
 			c.mut.RLock()
 			lokiEntry := loki.Entry{
 				Labels: entry.Labels,
@@ -135,6 +141,7 @@ func (c *Component) Update(args component.Arguments) error {
 
 	c.args = newArgs
 	c.receivers = newArgs.ForwardTo
+	c.limiter.Update(newArgs.Limit)
 	return nil
 }
 
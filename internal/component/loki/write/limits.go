@@ -0,0 +1,182 @@
+package write
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/alecthomas/units"
+	"github.com/grafana/loki/pkg/push"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/internal/util"
+)
+
+// Policies applied to the entries which exceed the entry limits.
+const (
+	LimitsPolicyDropEntry    = "drop_entry"
+	LimitsPolicyDropMetadata = "drop_metadata"
+	LimitsPolicyTruncate     = "truncate"
+)
+
+// Reasons reported in the metrics of the entry limits.
+const (
+	reasonLabelValueLength          = "label_value_length"
+	reasonStructuredMetadataSize    = "structured_metadata_size"
+	reasonStructuredMetadataEntries = "structured_metadata_entries"
+)
+
+// EntryLimits holds the size limits of the labels and structured metadata of
+// each log entry, and the policy applied to the entries which exceed them.
+type EntryLimits struct {
+	MaxLabelValueLength          int              `alloy:"max_label_value_length,attr,optional"`
+	MaxStructuredMetadataSize    units.Base2Bytes `alloy:"max_structured_metadata_size,attr,optional"`
+	MaxStructuredMetadataEntries int              `alloy:"max_structured_metadata_entries,attr,optional"`
+	Policy                       string           `alloy:"policy,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (l *EntryLimits) SetToDefault() {
+	*l = EntryLimits{Policy: LimitsPolicyDropEntry}
+}
+
+// Validate implements syntax.Validator.
+func (l *EntryLimits) Validate() error {
+	switch l.Policy {
+	case LimitsPolicyDropEntry, LimitsPolicyDropMetadata, LimitsPolicyTruncate:
+	default:
+		return fmt.Errorf("unknown policy %q, must be one of %q, %q or %q", l.Policy, LimitsPolicyDropEntry, LimitsPolicyDropMetadata, LimitsPolicyTruncate)
+	}
+	if l.MaxLabelValueLength < 0 || l.MaxStructuredMetadataSize < 0 || l.MaxStructuredMetadataEntries < 0 {
+		return fmt.Errorf("the entry limits must not be negative")
+	}
+	return nil
+}
+
+type limitsMetrics struct {
+	droppedEntries *prometheus.CounterVec
+	trimmedEntries *prometheus.CounterVec
+}
+
+func newLimitsMetrics(reg prometheus.Registerer) *limitsMetrics {
+	m := &limitsMetrics{
+		droppedEntries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "loki_write_oversized_entries_dropped_total",
+			Help: "Number of log entries dropped because they exceeded the entry limits.",
+		}, []string{"reason"}),
+		trimmedEntries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "loki_write_oversized_entries_trimmed_total",
+			Help: "Number of log entries whose labels or structured metadata were trimmed because they exceeded the entry limits.",
+		}, []string{"reason"}),
+	}
+
+	if reg != nil {
+		m.droppedEntries = util.MustRegisterOrGet(reg, m.droppedEntries).(*prometheus.CounterVec)
+		m.trimmedEntries = util.MustRegisterOrGet(reg, m.trimmedEntries).(*prometheus.CounterVec)
+	}
+	return m
+}
+
+// apply enforces the limits on the entry. It returns false if the entry must
+// be dropped. The labels and structured metadata of the entry are copied
+// before being trimmed, since they can be shared with other components.
+func (l EntryLimits) apply(entry *loki.Entry, m *limitsMetrics) bool {
+	if reason := l.exceeded(*entry); reason != "" && l.Policy == LimitsPolicyDropEntry {
+		m.droppedEntries.WithLabelValues(reason).Inc()
+		return false
+	}
+
+	if l.MaxLabelValueLength > 0 {
+		var labels model.LabelSet
+		for name, value := range entry.Labels {
+			if len(value) <= l.MaxLabelValueLength {
+				continue
+			}
+			if labels == nil {
+				labels = entry.Labels.Clone()
+			}
+			if l.Policy == LimitsPolicyTruncate {
+				labels[name] = model.LabelValue(truncate(string(value), l.MaxLabelValueLength))
+			} else {
+				delete(labels, name)
+			}
+		}
+		if labels != nil {
+			entry.Labels = labels
+			m.trimmedEntries.WithLabelValues(reasonLabelValueLength).Inc()
+		}
+	}
+
+	metadata := entry.StructuredMetadata
+	reason := ""
+	if l.MaxStructuredMetadataEntries > 0 && len(metadata) > l.MaxStructuredMetadataEntries {
+		reason = reasonStructuredMetadataEntries
+		metadata = metadata[:l.MaxStructuredMetadataEntries]
+	}
+	if l.MaxStructuredMetadataSize > 0 && structuredMetadataSize(metadata) > int(l.MaxStructuredMetadataSize) {
+		if reason == "" {
+			reason = reasonStructuredMetadataSize
+		}
+		size, n := 0, 0
+		for ; n < len(metadata); n++ {
+			size += len(metadata[n].Name) + len(metadata[n].Value)
+			if size > int(l.MaxStructuredMetadataSize) {
+				break
+			}
+		}
+		metadata = metadata[:n]
+	}
+	if reason != "" {
+		if l.Policy == LimitsPolicyDropMetadata {
+			metadata = nil
+		}
+		// Copy the metadata so that the entries of other components aren't
+		// modified.
+		entry.StructuredMetadata = append(push.LabelsAdapter(nil), metadata...)
+		m.trimmedEntries.WithLabelValues(reason).Inc()
+	}
+
+	return true
+}
+
+// exceeded returns the first limit exceeded by the entry, or an empty string
+// if the entry is within its limits.
+func (l EntryLimits) exceeded(entry loki.Entry) string {
+	if l.MaxLabelValueLength > 0 {
+		for _, value := range entry.Labels {
+			if len(value) > l.MaxLabelValueLength {
+				return reasonLabelValueLength
+			}
+		}
+	}
+	if l.MaxStructuredMetadataEntries > 0 && len(entry.StructuredMetadata) > l.MaxStructuredMetadataEntries {
+		return reasonStructuredMetadataEntries
+	}
+	if l.MaxStructuredMetadataSize > 0 && structuredMetadataSize(entry.StructuredMetadata) > int(l.MaxStructuredMetadataSize) {
+		return reasonStructuredMetadataSize
+	}
+	return ""
+}
+
+// structuredMetadataSize returns the size of the structured metadata, computed
+// the same way as Loki does.
+func structuredMetadataSize(metadata push.LabelsAdapter) int {
+	size := 0
+	for _, l := range metadata {
+		size += len(l.Name) + len(l.Value)
+	}
+	return size
+}
+
+// truncate truncates s to at most n bytes, without splitting UTF-8
+// characters.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
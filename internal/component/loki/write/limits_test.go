@@ -0,0 +1,166 @@
+package write
+
+import (
+	"testing"
+
+	"github.com/grafana/loki/pkg/push"
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component/common/loki"
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestEntryLimits(t *testing.T) {
+	newEntry := func() loki.Entry {
+		return loki.Entry{
+			Labels: model.LabelSet{"job": "test", "path": "/var/log/pods/été.log"},
+			Entry: logproto.Entry{
+				Line: "log line",
+				StructuredMetadata: push.LabelsAdapter{
+					{Name: "trace_id", Value: "0123456789"},
+					{Name: "span_id", Value: "01234"},
+					{Name: "user", Value: "alice"},
+				},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		name    string
+		config  string
+		dropped bool
+		reason  string
+		labels  model.LabelSet
+		meta    push.LabelsAdapter
+	}{
+		{
+			name:   "no limits",
+			config: ``,
+			labels: newEntry().Labels,
+			meta:   newEntry().StructuredMetadata,
+		},
+		{
+			name:    "drop entry on label value length",
+			config:  `max_label_value_length = 16`,
+			dropped: true,
+			reason:  reasonLabelValueLength,
+		},
+		{
+			name: "drop metadata on label value length",
+			config: `
+				max_label_value_length = 16
+				policy                 = "drop_metadata"
+			`,
+			reason: reasonLabelValueLength,
+			labels: model.LabelSet{"job": "test"},
+			meta:   newEntry().StructuredMetadata,
+		},
+		{
+			name: "truncate label value without splitting characters",
+			config: `
+				max_label_value_length = 18
+				policy                 = "truncate"
+			`,
+			reason: reasonLabelValueLength,
+			labels: model.LabelSet{"job": "test", "path": "/var/log/pods/ét"},
+			meta:   newEntry().StructuredMetadata,
+		},
+		{
+			name:    "drop entry on structured metadata size",
+			config:  `max_structured_metadata_size = "32B"`,
+			dropped: true,
+			reason:  reasonStructuredMetadataSize,
+		},
+		{
+			name: "drop metadata on structured metadata size",
+			config: `
+				max_structured_metadata_size = "32B"
+				policy                       = "drop_metadata"
+			`,
+			reason: reasonStructuredMetadataSize,
+			labels: newEntry().Labels,
+		},
+		{
+			name: "truncate structured metadata size",
+			config: `
+				max_structured_metadata_size = "32B"
+				policy                       = "truncate"
+			`,
+			reason: reasonStructuredMetadataSize,
+			labels: newEntry().Labels,
+			meta:   push.LabelsAdapter{{Name: "trace_id", Value: "0123456789"}, {Name: "span_id", Value: "01234"}},
+		},
+		{
+			name: "truncate structured metadata entries",
+			config: `
+				max_structured_metadata_entries = 1
+				policy                          = "truncate"
+			`,
+			reason: reasonStructuredMetadataEntries,
+			labels: newEntry().Labels,
+			meta:   push.LabelsAdapter{{Name: "trace_id", Value: "0123456789"}},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var limits EntryLimits
+			require.NoError(t, syntax.Unmarshal([]byte(tc.config), &limits))
+
+			m := newLimitsMetrics(prometheus.NewRegistry())
+			entry := newEntry()
+			original := newEntry()
+
+			kept := limits.apply(&entry, m)
+			require.Equal(t, !tc.dropped, kept)
+			if tc.reason != "" {
+				counter := m.trimmedEntries
+				if tc.dropped {
+					counter = m.droppedEntries
+				}
+				require.Equal(t, 1.0, testutil.ToFloat64(counter.WithLabelValues(tc.reason)))
+			}
+			if tc.dropped {
+				return
+			}
+			require.Equal(t, tc.labels, entry.Labels)
+			require.Equal(t, tc.meta, entry.StructuredMetadata)
+			require.Equal(t, original.Line, entry.Line)
+		})
+	}
+}
+
+func TestEntryLimitsSharedEntry(t *testing.T) {
+	limits := EntryLimits{
+		MaxLabelValueLength:          3,
+		MaxStructuredMetadataEntries: 1,
+		Policy:                       LimitsPolicyTruncate,
+	}
+	entry := loki.Entry{
+		Labels: model.LabelSet{"job": "test"},
+		Entry: logproto.Entry{
+			StructuredMetadata: push.LabelsAdapter{{Name: "a", Value: "1"}, {Name: "b", Value: "2"}},
+		},
+	}
+	shared := entry
+
+	require.True(t, limits.apply(&entry, newLimitsMetrics(nil)))
+	require.Equal(t, model.LabelSet{"job": "tes"}, entry.Labels)
+	require.Len(t, entry.StructuredMetadata, 1)
+
+	// The labels and structured metadata of the entries sent to other
+	// components must not be modified.
+	require.Equal(t, model.LabelSet{"job": "test"}, shared.Labels)
+	require.Len(t, shared.StructuredMetadata, 2)
+}
+
+func TestEntryLimitsValidate(t *testing.T) {
+	var limits EntryLimits
+	err := syntax.Unmarshal([]byte(`policy = "compress"`), &limits)
+	require.ErrorContains(t, err, `unknown policy "compress"`)
+
+	err = syntax.Unmarshal([]byte(`max_label_value_length = -1`), &limits)
+	require.ErrorContains(t, err, "the entry limits must not be negative")
+}
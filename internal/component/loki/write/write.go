@@ -35,6 +35,7 @@ type Arguments struct {
 	ExternalLabels map[string]string `alloy:"external_labels,attr,optional"`
 	MaxStreams     int               `alloy:"max_streams,attr,optional"`
 	WAL            WalArguments      `alloy:"wal,block,optional"`
+	EntryLimits    EntryLimits       `alloy:"entry_limits,block,optional"`
 }
 
 // WalArguments holds the settings for configuring the Write-Ahead Log (WAL) used
@@ -78,8 +79,9 @@ var (
 
 // Component implements the loki.write component.
 type Component struct {
-	opts    component.Options
-	metrics *client.Metrics
+	opts          component.Options
+	metrics       *client.Metrics
+	limitsMetrics *limitsMetrics
 
 	mut      sync.RWMutex
 	args     Arguments
@@ -97,8 +99,9 @@ type Component struct {
 // New creates a new loki.write component.
 func New(o component.Options, args Arguments) (*Component, error) {
 	c := &Component{
-		opts:    o,
-		metrics: client.NewMetrics(o.Registerer),
+		opts:          o,
+		metrics:       client.NewMetrics(o.Registerer),
+		limitsMetrics: newLimitsMetrics(o.Registerer),
 	}
 
 	// Create and immediately export the receiver which remains the same for
@@ -134,6 +137,10 @@ func (c *Component) Run(ctx context.Context) error {
 			return nil
 		case entry := <-c.receiver.Chan():
 			c.mut.RLock()
+			if !c.args.EntryLimits.apply(&entry, c.limitsMetrics) {
+				c.mut.RUnlock()
+				continue
+			}
 			select {
 			case <-ctx.Done():
 				c.mut.RUnlock()
@@ -171,7 +171,9 @@ func hasFieldOfType(obj interface{}, fieldType reflect.Type) bool {
 			return true
 		}
 
-		if fv.Kind() == reflect.Interface && fieldType.AssignableTo(ft) {
+		// Fields of an empty interface type, like decoded documents, can hold
+		// any value and don't indicate that the type is exported.
+		if fv.Kind() == reflect.Interface && ft.NumMethod() > 0 && fieldType.AssignableTo(ft) {
 			return true
 		}
 
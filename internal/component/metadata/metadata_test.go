@@ -74,6 +74,13 @@ func Test_inferMetadata(t *testing.T) {
 				exports: []Type{},
 			},
 		},
+		{
+			name: "remote.http",
+			expected: Metadata{
+				accepts: []Type{},
+				exports: []Type{},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
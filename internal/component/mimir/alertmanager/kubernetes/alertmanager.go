@@ -0,0 +1,292 @@
+package alertmanager
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/instrument"
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	controller "sigs.k8s.io/controller-runtime"
+
+	"github.com/grafana/alloy/internal/component"
+	commonK8s "github.com/grafana/alloy/internal/component/common/kubernetes"
+	"github.com/grafana/alloy/internal/featuregate"
+	mimirClient "github.com/grafana/alloy/internal/mimir/client"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "mimir.alertmanager.kubernetes",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   nil,
+		Build: func(o component.Options, c component.Arguments) (component.Component, error) {
+			return New(o, c.(Arguments))
+		},
+	})
+}
+
+type Component struct {
+	log     log.Logger
+	opts    component.Options
+	metrics *metrics
+
+	mut       sync.Mutex
+	args      Arguments
+	k8sClient kubernetes.Interface
+	syncer    *syncer
+	updated   chan struct{}
+
+	healthMut sync.RWMutex
+	health    component.Health
+}
+
+type metrics struct {
+	configUpdatesTotal prometheus.Counter
+	syncsFailed        prometheus.Counter
+
+	mimirClientTiming *prometheus.HistogramVec
+}
+
+func newMetrics() *metrics {
+	return &metrics{
+		configUpdatesTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Subsystem: "mimir_alertmanager",
+			Name:      "config_updates_total",
+			Help:      "Total number of times the configuration has been updated.",
+		}),
+		syncsFailed: prometheus.NewCounter(prometheus.CounterOpts{
+			Subsystem: "mimir_alertmanager",
+			Name:      "syncs_failed_total",
+			Help:      "Total number of times the Alertmanager configurations failed to be synced to Mimir.",
+		}),
+		mimirClientTiming: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Subsystem: "mimir_alertmanager",
+			Name:      "mimir_client_request_duration_seconds",
+			Help:      "Duration of requests to the Mimir API.",
+			Buckets:   instrument.DefBuckets,
+		}, instrument.HistogramCollectorBuckets),
+	}
+}
+
+func (m *metrics) register(r prometheus.Registerer) error {
+	for _, c := range []prometheus.Collector{
+		m.configUpdatesTotal,
+		m.syncsFailed,
+		m.mimirClientTiming,
+	} {
+		if err := r.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+var _ component.Component = (*Component)(nil)
+var _ component.HealthComponent = (*Component)(nil)
+
+// New creates a new Component and initializes required clients based on the provided configuration.
+func New(o component.Options, args Arguments) (*Component, error) {
+	m := newMetrics()
+	if err := m.register(o.Registerer); err != nil {
+		return nil, fmt.Errorf("registering metrics failed: %w", err)
+	}
+
+	// TODO: allow overriding some stuff in RestConfig and k8s client options?
+	restConfig, err := controller.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get k8s config: %w", err)
+	}
+	k8sClient, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create k8s client: %w", err)
+	}
+
+	c := &Component{
+		log:       o.Logger,
+		opts:      o,
+		metrics:   m,
+		args:      args,
+		k8sClient: k8sClient,
+		updated:   make(chan struct{}, 1),
+	}
+	c.syncer, err = c.newSyncer(args)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Component) Run(ctx context.Context) error {
+	var managed map[string]struct{}
+	for {
+		c.mut.Lock()
+		args := c.args
+		s := c.syncer
+		c.mut.Unlock()
+
+		// Hand over the tenants synced by the previous syncer, so that their
+		// configuration is deleted if they aren't defined anymore.
+		if managed != nil {
+			s.managed = managed
+		}
+
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			c.runSyncer(runCtx, s, args)
+		}()
+
+		select {
+		case <-ctx.Done():
+			cancel()
+			<-done
+			return nil
+		case <-c.updated:
+			c.metrics.configUpdatesTotal.Inc()
+			cancel()
+			<-done
+			managed = s.managed
+		}
+	}
+}
+
+func (c *Component) Update(newConfig component.Arguments) error {
+	args := newConfig.(Arguments)
+	s, err := c.newSyncer(args)
+	if err != nil {
+		return err
+	}
+
+	c.mut.Lock()
+	c.args = args
+	c.syncer = s
+	c.mut.Unlock()
+
+	select {
+	case c.updated <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (c *Component) newSyncer(args Arguments) (*syncer, error) {
+	httpClient := args.HTTPClientConfig.Convert()
+	client, err := mimirClient.New(c.log, mimirClient.Config{
+		ID:               args.TenantID,
+		Address:          args.Address,
+		HTTPClientConfig: *httpClient,
+	}, c.metrics.mimirClientTiming)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &syncer{
+		client:           client,
+		logger:           c.log,
+		defaultTenant:    args.TenantID,
+		configKey:        args.ConfigKey,
+		tenantAnnotation: args.TenantAnnotation,
+		namespaces:       args.Namespaces,
+		managed:          make(map[string]struct{}),
+	}
+	if args.ConfigMapSelector != nil {
+		if s.configMapSelector, err = commonK8s.ConvertSelectorToListOptions(*args.ConfigMapSelector); err != nil {
+			return nil, err
+		}
+	}
+	if args.SecretSelector != nil {
+		if s.secretSelector, err = commonK8s.ConvertSelectorToListOptions(*args.SecretSelector); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// runSyncer starts the informers of the watched resources, and syncs the
+// Alertmanager configurations whenever they change and every sync interval,
+// until the context is canceled.
+func (c *Component) runSyncer(ctx context.Context, s *syncer, args Arguments) {
+	changes := make(chan struct{}, 1)
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { notify(changes) },
+		UpdateFunc: func(interface{}, interface{}) { notify(changes) },
+		DeleteFunc: func(interface{}) { notify(changes) },
+	}
+
+	if s.configMapSelector != nil {
+		factory := c.newInformerFactory(s.configMapSelector)
+		configMaps := factory.Core().V1().ConfigMaps()
+		s.configMapLister = configMaps.Lister()
+		if _, err := configMaps.Informer().AddEventHandler(handler); err != nil {
+			c.reportUnhealthy(err)
+			return
+		}
+		factory.Start(ctx.Done())
+		factory.WaitForCacheSync(ctx.Done())
+	}
+	if s.secretSelector != nil {
+		factory := c.newInformerFactory(s.secretSelector)
+		secrets := factory.Core().V1().Secrets()
+		s.secretLister = secrets.Lister()
+		if _, err := secrets.Informer().AddEventHandler(handler); err != nil {
+			c.reportUnhealthy(err)
+			return
+		}
+		factory.Start(ctx.Done())
+		factory.WaitForCacheSync(ctx.Done())
+	}
+
+	// Reconciling with caches that aren't synced would delete the
+	// configuration of the tenants which weren't listed yet.
+	if ctx.Err() != nil {
+		return
+	}
+
+	ticker := time.NewTicker(args.SyncInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.reconcile(ctx); err != nil {
+			level.Error(c.log).Log("msg", "failed to sync Alertmanager configurations", "err", err)
+			c.metrics.syncsFailed.Inc()
+			c.reportUnhealthy(err)
+		} else {
+			c.reportHealthy()
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-changes:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Component) newInformerFactory(selector labels.Selector) informers.SharedInformerFactory {
+	return informers.NewSharedInformerFactoryWithOptions(
+		c.k8sClient,
+		24*time.Hour,
+		informers.WithTweakListOptions(func(lo *metav1.ListOptions) {
+			lo.LabelSelector = selector.String()
+		}),
+	)
+}
+
+func notify(ch chan struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
+	}
+}
@@ -0,0 +1,198 @@
+package alertmanager
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/go-kit/log"
+	"github.com/hashicorp/go-multierror"
+	"k8s.io/apimachinery/pkg/labels"
+	coreListers "k8s.io/client-go/listers/core/v1"
+
+	"github.com/grafana/alloy/internal/mimir/client"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+const (
+	kindConfigMap = "ConfigMap"
+	kindSecret    = "Secret"
+
+	templateSuffix = ".tmpl"
+)
+
+// source is a ConfigMap or Secret which holds the Alertmanager configuration
+// of a tenant.
+type source struct {
+	kind      string
+	namespace string
+	name      string
+	tenant    string
+	data      map[string]string
+}
+
+func (s source) String() string {
+	return fmt.Sprintf("%s %s/%s", s.kind, s.namespace, s.name)
+}
+
+// syncer syncs the Alertmanager configurations found in Kubernetes to Mimir.
+type syncer struct {
+	client client.AlertmanagerInterface
+	logger log.Logger
+
+	defaultTenant    string
+	configKey        string
+	tenantAnnotation string
+	namespaces       []string
+
+	// The listers are nil if the corresponding resources aren't watched.
+	configMapLister   coreListers.ConfigMapLister
+	configMapSelector labels.Selector
+	secretLister      coreListers.SecretLister
+	secretSelector    labels.Selector
+
+	// managed holds the tenants whose configuration was synced by the
+	// component. It's only accessed by the goroutine running the syncer, and
+	// handed over to the next syncer when the arguments change.
+	managed map[string]struct{}
+}
+
+// sources returns the ConfigMaps and Secrets which hold an Alertmanager
+// configuration, sorted by kind, namespace and name.
+func (s *syncer) sources() ([]source, error) {
+	var sources []source
+
+	if s.configMapLister != nil {
+		configMaps, err := s.configMapLister.List(s.configMapSelector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list config maps: %w", err)
+		}
+		for _, cm := range configMaps {
+			sources = s.appendSource(sources, kindConfigMap, cm.Namespace, cm.Name, cm.Annotations, cm.Data)
+		}
+	}
+
+	if s.secretLister != nil {
+		secrets, err := s.secretLister.List(s.secretSelector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list secrets: %w", err)
+		}
+		for _, secret := range secrets {
+			data := make(map[string]string, len(secret.Data))
+			for k, v := range secret.Data {
+				data[k] = string(v)
+			}
+			sources = s.appendSource(sources, kindSecret, secret.Namespace, secret.Name, secret.Annotations, data)
+		}
+	}
+
+	slices.SortFunc(sources, func(a, b source) int {
+		return cmp.Or(
+			strings.Compare(a.kind, b.kind),
+			strings.Compare(a.namespace, b.namespace),
+			strings.Compare(a.name, b.name),
+		)
+	})
+	return sources, nil
+}
+
+func (s *syncer) appendSource(sources []source, kind, namespace, name string, annotations, data map[string]string) []source {
+	if len(s.namespaces) > 0 && !slices.Contains(s.namespaces, namespace) {
+		return sources
+	}
+	if _, ok := data[s.configKey]; !ok {
+		return sources
+	}
+
+	tenant, ok := annotations[s.tenantAnnotation]
+	if !ok {
+		tenant = s.defaultTenant
+	}
+	return append(sources, source{
+		kind:      kind,
+		namespace: namespace,
+		name:      name,
+		tenant:    tenant,
+		data:      data,
+	})
+}
+
+// desiredState returns the Alertmanager configuration of each tenant. If
+// several sources hold the configuration of the same tenant, the first one is
+// used and an error is returned for the others.
+func (s *syncer) desiredState(sources []source) (map[string]client.AlertmanagerConfig, error) {
+	var errs error
+	desired := make(map[string]client.AlertmanagerConfig)
+	owners := make(map[string]source)
+	for _, src := range sources {
+		if owner, ok := owners[src.tenant]; ok {
+			errs = multierror.Append(errs, fmt.Errorf("%s: the Alertmanager configuration of tenant %q is already defined by %s", src, src.tenant, owner))
+			continue
+		}
+		owners[src.tenant] = src
+
+		cfg := client.AlertmanagerConfig{
+			AlertmanagerConfig: src.data[s.configKey],
+			TemplateFiles:      make(map[string]string),
+		}
+		for k, v := range src.data {
+			if strings.HasSuffix(k, templateSuffix) {
+				cfg.TemplateFiles[k] = v
+			}
+		}
+		desired[src.tenant] = cfg
+	}
+	return desired, errs
+}
+
+// reconcile updates the Alertmanager configuration of the tenants which
+// changed in Kubernetes, and deletes the configuration of the tenants which
+// aren't defined anymore.
+func (s *syncer) reconcile(ctx context.Context) error {
+	sources, err := s.sources()
+	if err != nil {
+		return err
+	}
+
+	desired, result := s.desiredState(sources)
+	for _, tenant := range slices.Sorted(maps.Keys(desired)) {
+		cfg := desired[tenant]
+		current, err := s.client.GetAlertmanagerConfig(ctx, tenant)
+		if err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to get the Alertmanager configuration of tenant %q: %w", tenant, err))
+			continue
+		}
+		s.managed[tenant] = struct{}{}
+		if current != nil && equalConfigs(*current, cfg) {
+			continue
+		}
+
+		if err := s.client.CreateAlertmanagerConfig(ctx, tenant, cfg); err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to update the Alertmanager configuration of tenant %q: %w", tenant, err))
+			continue
+		}
+		level.Info(s.logger).Log("msg", "updated Alertmanager configuration", "tenant", tenant)
+	}
+
+	for _, tenant := range slices.Sorted(maps.Keys(s.managed)) {
+		if _, ok := desired[tenant]; ok {
+			continue
+		}
+		if err := s.client.DeleteAlertmanagerConfig(ctx, tenant); err != nil {
+			result = multierror.Append(result, fmt.Errorf("failed to delete the Alertmanager configuration of tenant %q: %w", tenant, err))
+			continue
+		}
+		delete(s.managed, tenant)
+		level.Info(s.logger).Log("msg", "deleted Alertmanager configuration", "tenant", tenant)
+	}
+
+	return result
+}
+
+func equalConfigs(a, b client.AlertmanagerConfig) bool {
+	// A missing map and an empty one are equivalent.
+	return a.AlertmanagerConfig == b.AlertmanagerConfig && maps.Equal(a.TemplateFiles, b.TemplateFiles)
+}
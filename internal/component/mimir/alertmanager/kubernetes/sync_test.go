@@ -0,0 +1,189 @@
+package alertmanager
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	coreListers "k8s.io/client-go/listers/core/v1"
+	"k8s.io/client-go/tools/cache"
+
+	"github.com/grafana/alloy/internal/mimir/client"
+)
+
+type fakeAlertmanagerClient struct {
+	configs map[string]client.AlertmanagerConfig
+	creates int
+	getErr  error
+}
+
+var _ client.AlertmanagerInterface = &fakeAlertmanagerClient{}
+
+func newFakeAlertmanagerClient() *fakeAlertmanagerClient {
+	return &fakeAlertmanagerClient{
+		configs: make(map[string]client.AlertmanagerConfig),
+	}
+}
+
+func (f *fakeAlertmanagerClient) GetAlertmanagerConfig(_ context.Context, tenant string) (*client.AlertmanagerConfig, error) {
+	if f.getErr != nil {
+		return nil, f.getErr
+	}
+	cfg, ok := f.configs[tenant]
+	if !ok {
+		return nil, nil
+	}
+	return &cfg, nil
+}
+
+func (f *fakeAlertmanagerClient) CreateAlertmanagerConfig(_ context.Context, tenant string, cfg client.AlertmanagerConfig) error {
+	f.creates++
+	f.configs[tenant] = cfg
+	return nil
+}
+
+func (f *fakeAlertmanagerClient) DeleteAlertmanagerConfig(_ context.Context, tenant string) error {
+	delete(f.configs, tenant)
+	return nil
+}
+
+func newIndexer() cache.Indexer {
+	return cache.NewIndexer(
+		cache.DeletionHandlingMetaNamespaceKeyFunc,
+		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
+	)
+}
+
+func newTestSyncer(configMaps, secrets cache.Indexer, c client.AlertmanagerInterface) *syncer {
+	return &syncer{
+		client:            c,
+		logger:            log.NewNopLogger(),
+		defaultTenant:     "default",
+		configKey:         "alertmanager.yaml",
+		tenantAnnotation:  "alloy.grafana.com/mimir-tenant",
+		configMapLister:   coreListers.NewConfigMapLister(configMaps),
+		configMapSelector: labels.Everything(),
+		secretLister:      coreListers.NewSecretLister(secrets),
+		secretSelector:    labels.Everything(),
+		managed:           make(map[string]struct{}),
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	configMaps, secrets := newIndexer(), newIndexer()
+	mimir := newFakeAlertmanagerClient()
+	s := newTestSyncer(configMaps, secrets, mimir)
+	ctx := t.Context()
+
+	// A configuration which isn't managed by the component.
+	mimir.configs["unmanaged"] = client.AlertmanagerConfig{AlertmanagerConfig: "unmanaged"}
+
+	defaultConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "alertmanager"},
+		Data: map[string]string{
+			"alertmanager.yaml": "default",
+			"title.tmpl":        "title",
+			"README.md":         "ignored",
+		},
+	}
+	tenantConfig := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "team-a",
+			Name:        "alertmanager",
+			Annotations: map[string]string{"alloy.grafana.com/mimir-tenant": "team-a"},
+		},
+		Data: map[string][]byte{"alertmanager.yaml": []byte("team-a")},
+	}
+	unrelated := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "unrelated"},
+		Data:       map[string]string{"config.yaml": "ignored"},
+	}
+	require.NoError(t, configMaps.Add(defaultConfig))
+	require.NoError(t, configMaps.Add(unrelated))
+	require.NoError(t, secrets.Add(tenantConfig))
+
+	require.NoError(t, s.reconcile(ctx))
+	require.Equal(t, map[string]client.AlertmanagerConfig{
+		"default": {
+			AlertmanagerConfig: "default",
+			TemplateFiles:      map[string]string{"title.tmpl": "title"},
+		},
+		"team-a": {
+			AlertmanagerConfig: "team-a",
+			TemplateFiles:      map[string]string{},
+		},
+		"unmanaged": {AlertmanagerConfig: "unmanaged"},
+	}, mimir.configs)
+	require.Equal(t, 2, mimir.creates)
+
+	// Configurations which didn't change aren't updated.
+	require.NoError(t, s.reconcile(ctx))
+	require.Equal(t, 2, mimir.creates)
+
+	// The configuration of a tenant which isn't defined anymore is deleted.
+	require.NoError(t, secrets.Delete(tenantConfig))
+	require.NoError(t, s.reconcile(ctx))
+	require.Contains(t, mimir.configs, "default")
+	require.NotContains(t, mimir.configs, "team-a")
+	require.Contains(t, mimir.configs, "unmanaged")
+}
+
+func TestReconcileConflict(t *testing.T) {
+	configMaps, secrets := newIndexer(), newIndexer()
+	mimir := newFakeAlertmanagerClient()
+	s := newTestSyncer(configMaps, secrets, mimir)
+
+	for _, ns := range []string{"b", "a"} {
+		require.NoError(t, configMaps.Add(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Namespace: ns, Name: "alertmanager"},
+			Data:       map[string]string{"alertmanager.yaml": ns},
+		}))
+	}
+
+	err := s.reconcile(t.Context())
+	require.ErrorContains(t, err, `ConfigMap b/alertmanager: the Alertmanager configuration of tenant "default" is already defined by ConfigMap a/alertmanager`)
+	require.Equal(t, "a", mimir.configs["default"].AlertmanagerConfig)
+}
+
+func TestReconcileNamespaces(t *testing.T) {
+	configMaps, secrets := newIndexer(), newIndexer()
+	mimir := newFakeAlertmanagerClient()
+	s := newTestSyncer(configMaps, secrets, mimir)
+	s.namespaces = []string{"monitoring"}
+
+	require.NoError(t, configMaps.Add(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "team-a",
+			Name:        "alertmanager",
+			Annotations: map[string]string{"alloy.grafana.com/mimir-tenant": "team-a"},
+		},
+		Data: map[string]string{"alertmanager.yaml": "team-a"},
+	}))
+
+	require.NoError(t, s.reconcile(t.Context()))
+	require.Empty(t, mimir.configs)
+}
+
+func TestReconcileKeepsTenantsOnError(t *testing.T) {
+	configMaps, secrets := newIndexer(), newIndexer()
+	mimir := newFakeAlertmanagerClient()
+	s := newTestSyncer(configMaps, secrets, mimir)
+	ctx := t.Context()
+
+	require.NoError(t, configMaps.Add(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "monitoring", Name: "alertmanager"},
+		Data:       map[string]string{"alertmanager.yaml": "default"},
+	}))
+	require.NoError(t, s.reconcile(ctx))
+
+	// A tenant which failed to be synced is still managed.
+	mimir.getErr = errors.New("expected test error")
+	require.ErrorContains(t, s.reconcile(ctx), "expected test error")
+	require.Contains(t, mimir.configs, "default")
+	require.Contains(t, s.managed, "default")
+}
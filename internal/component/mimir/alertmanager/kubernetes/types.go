@@ -0,0 +1,50 @@
+package alertmanager
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/common/config"
+	"github.com/grafana/alloy/internal/component/common/kubernetes"
+)
+
+type Arguments struct {
+	Address          string                  `alloy:"address,attr"`
+	TenantID         string                  `alloy:"tenant_id,attr,optional"`
+	HTTPClientConfig config.HTTPClientConfig `alloy:",squash"`
+	SyncInterval     time.Duration           `alloy:"sync_interval,attr,optional"`
+	ConfigKey        string                  `alloy:"config_key,attr,optional"`
+	TenantAnnotation string                  `alloy:"tenant_annotation,attr,optional"`
+	Namespaces       []string                `alloy:"namespaces,attr,optional"`
+
+	ConfigMapSelector *kubernetes.LabelSelector `alloy:"config_map_selector,block,optional"`
+	SecretSelector    *kubernetes.LabelSelector `alloy:"secret_selector,block,optional"`
+}
+
+var DefaultArguments = Arguments{
+	SyncInterval:     5 * time.Minute,
+	ConfigKey:        "alertmanager.yaml",
+	TenantAnnotation: "alloy.grafana.com/mimir-tenant",
+	HTTPClientConfig: config.DefaultHTTPClientConfig,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if args.SyncInterval <= 0 {
+		return fmt.Errorf("sync_interval must be greater than 0")
+	}
+	if args.ConfigKey == "" {
+		return fmt.Errorf("config_key must not be empty")
+	}
+	if args.ConfigMapSelector == nil && args.SecretSelector == nil {
+		return fmt.Errorf("at least one of config_map_selector and secret_selector must be configured")
+	}
+
+	// We must explicitly Validate because HTTPClientConfig is squashed and it won't run otherwise
+	return args.HTTPClientConfig.Validate()
+}
@@ -0,0 +1,65 @@
+package alertmanager
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestAlloyConfig(t *testing.T) {
+	var testCases = []struct {
+		name                  string
+		config                string
+		expectedErrorContains string
+	}{
+		{
+			name: "config map selector",
+			config: `
+	address = "GRAFANA_CLOUD_METRICS_URL"
+	config_map_selector {
+		match_labels = {"alertmanager" = "mimir"}
+	}`,
+		},
+		{
+			name: "secret selector",
+			config: `
+	address = "GRAFANA_CLOUD_METRICS_URL"
+	tenant_id = "default"
+	secret_selector {
+		match_expression {
+			key = "alertmanager"
+			operator = "Exists"
+		}
+	}`,
+		},
+		{
+			name: "no selector",
+			config: `
+	address = "GRAFANA_CLOUD_METRICS_URL"`,
+			expectedErrorContains: "at least one of config_map_selector and secret_selector must be configured",
+		},
+		{
+			name: "invalid http config",
+			config: `
+	address = "GRAFANA_CLOUD_METRICS_URL"
+	bearer_token = "token"
+	bearer_token_file = "/path/to/file.token"
+	config_map_selector {}`,
+			expectedErrorContains: "at most one of basic_auth, authorization, oauth2, bearer_token & bearer_token_file must be configured",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.config), &args)
+			if tc.expectedErrorContains == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectedErrorContains)
+			}
+		})
+	}
+}
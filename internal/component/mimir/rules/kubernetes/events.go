@@ -33,6 +33,7 @@ type eventProcessor struct {
 	queue    workqueue.TypedRateLimitingInterface[kubernetes.Event]
 	stopChan chan struct{}
 	health   healthReporter
+	status   statusReporter
 
 	mimirClient        client.Interface
 	namespaceLister    coreListers.NamespaceLister
@@ -41,6 +42,7 @@ type eventProcessor struct {
 	ruleSelector       labels.Selector
 	namespacePrefix    string
 	externalLabels     map[string]string
+	sourceTenantLabel  string
 	extraQueryMatchers *ExtraQueryMatchers
 
 	metrics *metrics
@@ -156,15 +158,29 @@ func (e *eventProcessor) reconcileState(ctx context.Context) error {
 	currentState := e.getMimirState()
 	diffs := kubernetes.DiffRuleState(desiredState, currentState)
 
+	statuses := make(map[string]NamespaceSyncStatus, len(desiredState))
+	for ns, groups := range desiredState {
+		statuses[ns] = newNamespaceSyncStatus(ns, len(groups))
+	}
+
 	var result error
 	for ns, diff := range diffs {
 		err = e.applyChanges(ctx, ns, diff)
 		if err != nil {
 			result = multierror.Append(result, err)
+			// Namespaces which are being removed don't have a status.
+			if status, ok := statuses[ns]; ok {
+				status.Synced = false
+				status.LastError = err.Error()
+				statuses[ns] = status
+			}
 			continue
 		}
 	}
 
+	if e.status != nil {
+		e.status.reportSyncStatus(statuses)
+	}
 	return result
 }
 
@@ -198,6 +214,17 @@ func (e *eventProcessor) desiredStateFromKubernetes() (kubernetes.RuleGroupsByNa
 				}
 			}
 
+			if e.sourceTenantLabel != "" {
+				for _, ruleGroup := range groups {
+					for i := range ruleGroup.Rules {
+						if ruleGroup.Rules[i].Labels == nil {
+							ruleGroup.Rules[i].Labels = make(map[string]string, 1)
+						}
+						ruleGroup.Rules[i].Labels[e.sourceTenantLabel] = rule.Namespace
+					}
+				}
+			}
+
 			if e.extraQueryMatchers != nil {
 				for _, ruleGroup := range groups {
 					for i := range ruleGroup.Rules {
@@ -2,6 +2,7 @@ package rules
 
 import (
 	"context"
+	"errors"
 	"os"
 	"sync"
 	"testing"
@@ -385,6 +386,198 @@ func TestExtraQueryMatchers(t *testing.T) {
 	}
 }
 
+func TestSourceTenantLabel(t *testing.T) {
+	nsIndexer := testNamespaceIndexer()
+	ruleIndexer := testRuleIndexer()
+
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "team-a",
+			UID:  types.UID("33f8860c-bd06-4c0d-a0b1-a114d6b9937b"),
+		},
+	}
+
+	rule := &v1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "name",
+			Namespace: "team-a",
+			UID:       types.UID("64aab764-c95e-4ee9-a932-cd63ba57e6cf"),
+		},
+		Spec: v1.PrometheusRuleSpec{
+			Groups: []v1.RuleGroup{
+				{
+					Name: "group1",
+					Rules: []v1.Rule{
+						{
+							Alert: "alert1",
+							Expr:  intstr.FromString("expr1"),
+						},
+					},
+				},
+			},
+		},
+	}
+	require.NoError(t, nsIndexer.Add(ns))
+	require.NoError(t, ruleIndexer.Add(rule))
+
+	processor := &eventProcessor{
+		mimirClient:       newFakeMimirClient(),
+		namespaceLister:   coreListers.NewNamespaceLister(nsIndexer),
+		ruleLister:        promListers.NewPrometheusRuleLister(ruleIndexer),
+		namespaceSelector: labels.Everything(),
+		ruleSelector:      labels.Everything(),
+		namespacePrefix:   "alloy",
+		metrics:           newMetrics(),
+		logger:            log.NewNopLogger(),
+		externalLabels:    map[string]string{"cluster": "prod"},
+		sourceTenantLabel: "source_tenant",
+	}
+
+	ctx := t.Context()
+	require.NoError(t, processor.reconcileState(ctx))
+
+	rules, err := processor.mimirClient.ListRules(ctx, "")
+	require.NoError(t, err)
+	ruleBuf, err := yaml.Marshal(rules[mimirNamespaceForRuleCRD("alloy", rule)])
+	require.NoError(t, err)
+
+	expectedRule := `- name: group1
+  rules:
+  - alert: alert1
+    expr: expr1
+    labels:
+      cluster: prod
+      source_tenant: team-a
+`
+	require.YAMLEq(t, expectedRule, string(ruleBuf))
+}
+
+type fakeStatusReporter struct {
+	mtx      sync.Mutex
+	statuses map[string]NamespaceSyncStatus
+}
+
+func (f *fakeStatusReporter) reportSyncStatus(statuses map[string]NamespaceSyncStatus) {
+	f.mtx.Lock()
+	f.statuses = statuses
+	f.mtx.Unlock()
+}
+
+func (f *fakeStatusReporter) getExports() Exports {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return newExports(f.statuses)
+}
+
+// failingMimirClient fails to create the rule groups of a single Mimir namespace.
+type failingMimirClient struct {
+	*fakeMimirClient
+	namespace string
+}
+
+func (m *failingMimirClient) CreateRuleGroup(ctx context.Context, namespace string, rule rulefmt.RuleGroup) error {
+	if namespace == m.namespace {
+		return errors.New("expected test error")
+	}
+	return m.fakeMimirClient.CreateRuleGroup(ctx, namespace, rule)
+}
+
+func TestSyncStatus(t *testing.T) {
+	nsIndexer := testNamespaceIndexer()
+	ruleIndexer := testRuleIndexer()
+
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "namespace",
+			UID:  types.UID("33f8860c-bd06-4c0d-a0b1-a114d6b9937b"),
+		},
+	}
+
+	newRule := func(name, uid string, groups ...string) *v1.PrometheusRule {
+		rule := &v1.PrometheusRule{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "namespace",
+				UID:       types.UID(uid),
+			},
+		}
+		for _, group := range groups {
+			rule.Spec.Groups = append(rule.Spec.Groups, v1.RuleGroup{
+				Name: group,
+				Rules: []v1.Rule{
+					{
+						Alert: "alert",
+						Expr:  intstr.FromString("expr"),
+					},
+				},
+			})
+		}
+		return rule
+	}
+	good := newRule("good", "64aab764-c95e-4ee9-a932-cd63ba57e6cf", "group1", "group2")
+	bad := newRule("bad", "a9e3bd1c-8b31-4c2e-9a3b-76b0a1d6e2f4", "group1")
+
+	require.NoError(t, nsIndexer.Add(ns))
+	require.NoError(t, ruleIndexer.Add(good))
+	require.NoError(t, ruleIndexer.Add(bad))
+
+	mimirClient := &failingMimirClient{
+		fakeMimirClient: newFakeMimirClient(),
+		namespace:       mimirNamespaceForRuleCRD("alloy", bad),
+	}
+	status := &fakeStatusReporter{}
+	processor := &eventProcessor{
+		status:            status,
+		mimirClient:       mimirClient,
+		namespaceLister:   coreListers.NewNamespaceLister(nsIndexer),
+		ruleLister:        promListers.NewPrometheusRuleLister(ruleIndexer),
+		namespaceSelector: labels.Everything(),
+		ruleSelector:      labels.Everything(),
+		namespacePrefix:   "alloy",
+		metrics:           newMetrics(),
+		logger:            log.NewNopLogger(),
+	}
+
+	ctx := t.Context()
+	require.ErrorContains(t, processor.reconcileState(ctx), "expected test error")
+	require.Equal(t, Exports{
+		Synced: false,
+		Namespaces: []NamespaceSyncStatus{
+			{
+				MimirNamespace: "alloy/namespace/bad/a9e3bd1c-8b31-4c2e-9a3b-76b0a1d6e2f4",
+				Namespace:      "namespace",
+				Name:           "bad",
+				Synced:         false,
+				RuleGroups:     1,
+				LastError:      "expected test error",
+			},
+			{
+				MimirNamespace: "alloy/namespace/good/64aab764-c95e-4ee9-a932-cd63ba57e6cf",
+				Namespace:      "namespace",
+				Name:           "good",
+				Synced:         true,
+				RuleGroups:     2,
+			},
+		},
+	}, status.getExports())
+
+	// Once the failing rule is removed, every remaining namespace is synced.
+	require.NoError(t, ruleIndexer.Delete(bad))
+	require.NoError(t, processor.reconcileState(ctx))
+	require.Equal(t, Exports{
+		Synced: true,
+		Namespaces: []NamespaceSyncStatus{
+			{
+				MimirNamespace: "alloy/namespace/good/64aab764-c95e-4ee9-a932-cd63ba57e6cf",
+				Namespace:      "namespace",
+				Name:           "good",
+				Synced:         true,
+				RuleGroups:     2,
+			},
+		},
+	}, status.getExports())
+}
+
 func testRuleIndexer() cache.Indexer {
 	ruleIndexer := cache.NewIndexer(
 		cache.DeletionHandlingMetaNamespaceKeyFunc,
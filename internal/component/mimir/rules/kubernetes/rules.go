@@ -48,7 +48,7 @@ func init() {
 		Name:      "mimir.rules.kubernetes",
 		Stability: featuregate.StabilityGenerallyAvailable,
 		Args:      Arguments{},
-		Exports:   nil,
+		Exports:   Exports{},
 		Build: func(o component.Options, c component.Arguments) (component.Component, error) {
 			return New(o, c.(Arguments))
 		},
@@ -75,6 +75,9 @@ type Component struct {
 	metrics   *metrics
 	healthMut sync.RWMutex
 	health    component.Health
+
+	exportsMut sync.Mutex
+	exports    Exports
 }
 
 type metrics struct {
@@ -162,6 +165,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		return nil, fmt.Errorf("initializing component failed: %w", err)
 	}
 
+	o.OnStateChange(c.exports)
 	return c, nil
 }
 
@@ -449,6 +453,7 @@ func (c *Component) newEventProcessor(queue workqueue.TypedRateLimitingInterface
 		queue:              queue,
 		stopChan:           stopChan,
 		health:             c,
+		status:             c,
 		mimirClient:        c.mimirClient,
 		namespaceLister:    namespaceLister,
 		ruleLister:         ruleLister,
@@ -458,6 +463,7 @@ func (c *Component) newEventProcessor(queue workqueue.TypedRateLimitingInterface
 		metrics:            c.metrics,
 		logger:             c.log,
 		externalLabels:     externalLabels,
+		sourceTenantLabel:  c.args.SourceTenantLabel,
 		extraQueryMatchers: c.args.ExtraQueryMatchers,
 	}
 }
@@ -471,6 +477,13 @@ type healthReporter interface {
 	reportHealthy()
 }
 
+// statusReporter encapsulates the logic for exporting the sync status of the
+// managed Mimir namespaces to make testing portions of the Component easier.
+type statusReporter interface {
+	// reportSyncStatus exports the sync status, indexed by Mimir namespace.
+	reportSyncStatus(statuses map[string]NamespaceSyncStatus)
+}
+
 // lifecycle encapsulates state transitions and mutable state to make testing
 // portions of the Component easier.
 type lifecycle interface {
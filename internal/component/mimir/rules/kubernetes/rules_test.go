@@ -91,6 +91,19 @@ func TestAlloyConfigs(t *testing.T) {
 	}`,
 			expectedErrorContains: `invalid match type`,
 		},
+		{
+			name: "source tenant label",
+			config: `
+	address = "GRAFANA_CLOUD_METRICS_URL"
+	source_tenant_label = "source_tenant"`,
+		},
+		{
+			name: "invalid source tenant label",
+			config: `
+	address = "GRAFANA_CLOUD_METRICS_URL"
+	source_tenant_label = "\xff"`,
+			expectedErrorContains: `source_tenant_label "\xff" is not a valid label name`,
+		},
 	}
 
 	for _, tc := range testCases {
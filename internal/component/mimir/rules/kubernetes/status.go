@@ -0,0 +1,72 @@
+package rules
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Exports holds the values exported by the mimir.rules.kubernetes component.
+type Exports struct {
+	// Synced is true if the rule groups of every managed Mimir namespace match
+	// the PrometheusRule resources they were loaded from.
+	Synced bool `alloy:"synced,attr"`
+	// Namespaces holds the sync status of every managed Mimir namespace.
+	Namespaces []NamespaceSyncStatus `alloy:"namespaces,attr"`
+}
+
+// NamespaceSyncStatus is the sync status of the rule groups loaded from a
+// single PrometheusRule resource into a Mimir namespace.
+type NamespaceSyncStatus struct {
+	MimirNamespace string `alloy:"mimir_namespace,attr"`
+	Namespace      string `alloy:"namespace,attr"`
+	Name           string `alloy:"name,attr"`
+	Synced         bool   `alloy:"synced,attr"`
+	RuleGroups     int    `alloy:"rule_groups,attr"`
+	LastError      string `alloy:"last_error,attr"`
+}
+
+// newNamespaceSyncStatus returns the status of a Mimir namespace before its
+// changes are applied. The Kubernetes namespace and name are extracted from
+// the Mimir namespace, see mimirNamespaceForRuleCRD.
+func newNamespaceSyncStatus(mimirNamespace string, ruleGroups int) NamespaceSyncStatus {
+	status := NamespaceSyncStatus{
+		MimirNamespace: mimirNamespace,
+		Synced:         true,
+		RuleGroups:     ruleGroups,
+	}
+	// Kubernetes namespaces and names can't contain a slash, but the prefix can.
+	parts := strings.Split(mimirNamespace, "/")
+	if len(parts) >= 4 {
+		status.Namespace = parts[len(parts)-3]
+		status.Name = parts[len(parts)-2]
+	}
+	return status
+}
+
+func newExports(statuses map[string]NamespaceSyncStatus) Exports {
+	exports := Exports{
+		Synced:     true,
+		Namespaces: make([]NamespaceSyncStatus, 0, len(statuses)),
+	}
+	for _, status := range statuses {
+		exports.Synced = exports.Synced && status.Synced
+		exports.Namespaces = append(exports.Namespaces, status)
+	}
+	slices.SortFunc(exports.Namespaces, func(a, b NamespaceSyncStatus) int {
+		return strings.Compare(a.MimirNamespace, b.MimirNamespace)
+	})
+	return exports
+}
+
+func (c *Component) reportSyncStatus(statuses map[string]NamespaceSyncStatus) {
+	exports := newExports(statuses)
+
+	c.exportsMut.Lock()
+	defer c.exportsMut.Unlock()
+	if reflect.DeepEqual(c.exports, exports) {
+		return
+	}
+	c.exports = exports
+	c.opts.OnStateChange(exports)
+}
@@ -6,6 +6,7 @@ import (
 	"slices"
 	"time"
 
+	"github.com/prometheus/common/model"
 	promlabels "github.com/prometheus/prometheus/model/labels"
 
 	"github.com/grafana/alloy/internal/component/common/config"
@@ -31,6 +32,7 @@ type Arguments struct {
 	SyncInterval         time.Duration           `alloy:"sync_interval,attr,optional"`
 	MimirNameSpacePrefix string                  `alloy:"mimir_namespace_prefix,attr,optional"`
 	ExternalLabels       map[string]string       `alloy:"external_labels,attr,optional"`
+	SourceTenantLabel    string                  `alloy:"source_tenant_label,attr,optional"`
 	ExtraQueryMatchers   *ExtraQueryMatchers     `alloy:"extra_query_matchers,block,optional"`
 
 	RuleSelector          kubernetes.LabelSelector `alloy:"rule_selector,block,optional"`
@@ -57,6 +59,9 @@ func (args *Arguments) Validate() error {
 	if args.MimirNameSpacePrefix == "" {
 		return fmt.Errorf("mimir_namespace_prefix must not be empty")
 	}
+	if args.SourceTenantLabel != "" && !model.LabelName(args.SourceTenantLabel).IsValid() {
+		return fmt.Errorf("source_tenant_label %q is not a valid label name", args.SourceTenantLabel)
+	}
 	if err := args.ExtraQueryMatchers.Validate(); err != nil {
 		return err
 	}
@@ -0,0 +1,56 @@
+package otelcol
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/otelcol/internal/awsutil"
+	"github.com/grafana/alloy/syntax"
+)
+
+// AWSSessionArguments holds shared settings for components which send
+// requests to AWS.
+type AWSSessionArguments struct {
+	Region         string        `alloy:"region,attr,optional"`
+	Endpoint       string        `alloy:"endpoint,attr,optional"`
+	RoleARN        string        `alloy:"role_arn,attr,optional"`
+	MaxRetries     int           `alloy:"max_retries,attr,optional"`
+	RequestTimeout time.Duration `alloy:"request_timeout,attr,optional"`
+	NoVerifySSL    bool          `alloy:"no_verify_ssl,attr,optional"`
+}
+
+var (
+	_ syntax.Defaulter = (*AWSSessionArguments)(nil)
+	_ syntax.Validator = (*AWSSessionArguments)(nil)
+)
+
+// SetToDefault implements syntax.Defaulter.
+func (args *AWSSessionArguments) SetToDefault() {
+	*args = AWSSessionArguments{
+		MaxRetries:     2,
+		RequestTimeout: 30 * time.Second,
+	}
+}
+
+// Validate returns an error if args is invalid.
+func (args *AWSSessionArguments) Validate() error {
+	if args.MaxRetries < 0 {
+		return fmt.Errorf("max_retries must not be negative")
+	}
+	if args.RequestTimeout <= 0 {
+		return fmt.Errorf("request_timeout must be greater than 0")
+	}
+	return nil
+}
+
+// Convert converts args into the settings of the AWS clients.
+func (args *AWSSessionArguments) Convert() awsutil.SessionSettings {
+	return awsutil.SessionSettings{
+		Region:         args.Region,
+		Endpoint:       args.Endpoint,
+		RoleARN:        args.RoleARN,
+		MaxRetries:     args.MaxRetries,
+		RequestTimeout: args.RequestTimeout,
+		NoVerifySSL:    args.NoVerifySSL,
+	}
+}
@@ -1,6 +1,7 @@
 package otelcol
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/grafana/alloy/syntax/alloytypes"
@@ -55,13 +56,34 @@ func (args KafkaPlaintextArguments) Convert() map[string]interface{} {
 
 // KafkaSASLArguments configures SASL authentication against the Kafka broker.
 type KafkaSASLArguments struct {
-	Username  string               `alloy:"username,attr"`
-	Password  alloytypes.Secret    `alloy:"password,attr"`
+	Username  string               `alloy:"username,attr,optional"`
+	Password  alloytypes.Secret    `alloy:"password,attr,optional"`
 	Mechanism string               `alloy:"mechanism,attr"`
 	Version   int                  `alloy:"version,attr,optional"`
 	AWSMSK    KafkaAWSMSKArguments `alloy:"aws_msk,block,optional"`
 }
 
+// Validate returns an error if args is invalid.
+func (args *KafkaSASLArguments) Validate() error {
+	switch args.Mechanism {
+	case "PLAIN", "SCRAM-SHA-256", "SCRAM-SHA-512":
+		if args.Username == "" || args.Password == "" {
+			return fmt.Errorf("username and password are required when mechanism is %q", args.Mechanism)
+		}
+	case "AWS_MSK_IAM":
+		// The credentials are retrieved from the AWS default credential chain,
+		// for example from the IAM role of the instance.
+		if args.AWSMSK.Region == "" || args.AWSMSK.BrokerAddr == "" {
+			return fmt.Errorf("the aws_msk block with region and broker_addr is required when mechanism is %q", args.Mechanism)
+		}
+	case "AWS_MSK_IAM_OAUTHBEARER":
+		if args.AWSMSK.Region == "" {
+			return fmt.Errorf("the aws_msk block with region is required when mechanism is %q", args.Mechanism)
+		}
+	}
+	return nil
+}
+
 // Convert converts args into the upstream type.
 func (args KafkaSASLArguments) Convert() map[string]interface{} {
 	return map[string]interface{}{
@@ -74,10 +96,10 @@ func (args KafkaSASLArguments) Convert() map[string]interface{} {
 }
 
 // KafkaAWSMSKArguments exposes additional SASL authentication measures required to
-// use the AWS_MSK_IAM mechanism.
+// use the AWS_MSK_IAM and AWS_MSK_IAM_OAUTHBEARER mechanisms.
 type KafkaAWSMSKArguments struct {
 	Region     string `alloy:"region,attr"`
-	BrokerAddr string `alloy:"broker_addr,attr"`
+	BrokerAddr string `alloy:"broker_addr,attr,optional"`
 }
 
 // Convert converts args into the upstream type.
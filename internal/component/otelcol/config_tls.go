@@ -1,9 +1,13 @@
 package otelcol
 
 import (
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
+	"github.com/grafana/alloy/internal/fips"
 	"github.com/grafana/alloy/syntax/alloytypes"
 	"go.opentelemetry.io/collector/config/configopaque"
 	otelconfigtls "go.opentelemetry.io/collector/config/configtls"
@@ -114,3 +118,29 @@ func (t *TLSSetting) Validate() error {
 
 	return nil
 }
+
+// tlsVersions maps the TLS versions accepted by min_version to their
+// tls.VersionTLS constants.
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// fipsCurves are the curve_preferences approved by FIPS 140-3.
+var fipsCurves = []string{"P256", "P384", "P521"}
+
+// CheckFIPS implements fips.Checker.
+func (t *TLSSetting) CheckFIPS() error {
+	errs := []error{
+		fips.CheckTLSVersion(tlsVersions[t.MinVersion]),
+		fips.CheckCipherSuites(t.CipherSuites),
+	}
+	for _, curve := range t.CurvePreferences {
+		if !slices.Contains(fipsCurves, curve) {
+			errs = append(errs, fmt.Errorf("curve %s is not FIPS approved", curve))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,54 @@
+package otelcol_test
+
+import (
+	"testing"
+
+	"github.com/grafana/alloy/internal/component/otelcol"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTLSSettingCheckFIPS(t *testing.T) {
+	tests := []struct {
+		name      string
+		setting   otelcol.TLSSetting
+		expectErr string
+	}{
+		{
+			name:    "defaults",
+			setting: otelcol.TLSSetting{},
+		},
+		{
+			name: "approved settings",
+			setting: otelcol.TLSSetting{
+				MinVersion:       "1.2",
+				CipherSuites:     []string{"TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256"},
+				CurvePreferences: []string{"P256", "P384"},
+			},
+		},
+		{
+			name:      "old TLS version",
+			setting:   otelcol.TLSSetting{MinVersion: "1.1"},
+			expectErr: "TLS version TLS 1.1 is not FIPS approved, the minimum version is TLS 1.2",
+		},
+		{
+			name:      "weak cipher suite",
+			setting:   otelcol.TLSSetting{CipherSuites: []string{"TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256"}},
+			expectErr: "cipher suite TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256 is not FIPS approved",
+		},
+		{
+			name:      "unapproved curve",
+			setting:   otelcol.TLSSetting{CurvePreferences: []string{"X25519"}},
+			expectErr: "curve X25519 is not FIPS approved",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.setting.CheckFIPS()
+			if tt.expectErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.expectErr)
+			}
+		})
+	}
+}
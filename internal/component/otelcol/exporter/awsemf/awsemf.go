@@ -0,0 +1,108 @@
+// Package awsemf provides an otelcol.exporter.awsemf component
+package awsemf
+
+import (
+	otelcomponent "go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pipeline"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
+	"github.com/grafana/alloy/internal/component/otelcol/exporter"
+	"github.com/grafana/alloy/internal/component/otelcol/exporter/awsemf/internal/emf"
+	"github.com/grafana/alloy/internal/featuregate"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.exporter.awsemf",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   otelcol.ConsumerExports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			fact := emf.NewFactory()
+			return exporter.New(opts, fact, args.(Arguments), exporter.TypeSignalConstFunc(exporter.TypeMetrics))
+		},
+	})
+}
+
+// Arguments configures the otelcol.exporter.awsemf component.
+type Arguments struct {
+	AWS otelcol.AWSSessionArguments `alloy:",squash"`
+
+	LogGroupName                  string `alloy:"log_group_name,attr,optional"`
+	LogStreamName                 string `alloy:"log_stream_name,attr,optional"`
+	Namespace                     string `alloy:"namespace,attr,optional"`
+	DimensionRollupOption         string `alloy:"dimension_rollup_option,attr,optional"`
+	OutputDestination             string `alloy:"output_destination,attr,optional"`
+	ResourceToTelemetryConversion bool   `alloy:"resource_to_telemetry_conversion,attr,optional"`
+
+	Queue otelcol.QueueArguments `alloy:"sending_queue,block,optional"`
+	Retry otelcol.RetryArguments `alloy:"retry_on_failure,block,optional"`
+
+	// DebugMetrics configures component internal metrics. Optional
+	DebugMetrics otelcolCfg.DebugMetricsArguments `alloy:"debug_metrics,block,optional"`
+}
+
+var _ exporter.Arguments = Arguments{}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{
+		LogGroupName:          "/metrics/default",
+		LogStreamName:         "otel-stream",
+		DimensionRollupOption: emf.ZeroAndSingleDimensionRollup,
+		OutputDestination:     emf.OutputCloudWatch,
+	}
+	args.AWS.SetToDefault()
+	args.Queue.SetToDefault()
+	args.Retry.SetToDefault()
+	args.DebugMetrics.SetToDefault()
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if err := args.AWS.Validate(); err != nil {
+		return err
+	}
+	cfg, err := args.Convert()
+	if err != nil {
+		return err
+	}
+	return cfg.(*emf.Config).Validate()
+}
+
+// Convert implements exporter.Arguments.
+func (args Arguments) Convert() (otelcomponent.Config, error) {
+	q, err := args.Queue.Convert()
+	if err != nil {
+		return nil, err
+	}
+	return &emf.Config{
+		SessionSettings:               args.AWS.Convert(),
+		LogGroupName:                  args.LogGroupName,
+		LogStreamName:                 args.LogStreamName,
+		Namespace:                     args.Namespace,
+		DimensionRollupOption:         args.DimensionRollupOption,
+		OutputDestination:             args.OutputDestination,
+		ResourceToTelemetryConversion: args.ResourceToTelemetryConversion,
+		QueueSettings:                 *q,
+		BackOffConfig:                 *args.Retry.Convert(),
+	}, nil
+}
+
+// Extensions implements exporter.Arguments.
+func (args Arguments) Extensions() map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// Exporters implements exporter.Arguments.
+func (args Arguments) Exporters() map[pipeline.Signal]map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// DebugMetricsConfig implements exporter.Arguments.
+func (args Arguments) DebugMetricsConfig() otelcolCfg.DebugMetricsArguments {
+	return args.DebugMetrics
+}
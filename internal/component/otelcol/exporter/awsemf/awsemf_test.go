@@ -0,0 +1,115 @@
+package awsemf_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/grafana/alloy/internal/component/otelcol/exporter/awsemf"
+	"github.com/grafana/alloy/internal/component/otelcol/exporter/awsemf/internal/emf"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/awsutil"
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestConfigConversion(t *testing.T) {
+	defaultQueue := exporterhelper.QueueConfig{
+		Enabled:      true,
+		NumConsumers: 10,
+		QueueSize:    1000,
+	}
+	defaultRetry := configretry.BackOffConfig{
+		Enabled:             true,
+		InitialInterval:     5 * time.Second,
+		RandomizationFactor: 0.5,
+		Multiplier:          1.5,
+		MaxInterval:         30 * time.Second,
+		MaxElapsedTime:      5 * time.Minute,
+	}
+
+	tests := []struct {
+		testName string
+		alloyCfg string
+		expected emf.Config
+	}{
+		{
+			testName: "default",
+			alloyCfg: ``,
+			expected: emf.Config{
+				SessionSettings: awsutil.SessionSettings{
+					MaxRetries:     2,
+					RequestTimeout: 30 * time.Second,
+				},
+				LogGroupName:          "/metrics/default",
+				LogStreamName:         "otel-stream",
+				DimensionRollupOption: emf.ZeroAndSingleDimensionRollup,
+				OutputDestination:     emf.OutputCloudWatch,
+				QueueSettings:         defaultQueue,
+				BackOffConfig:         defaultRetry,
+			},
+		},
+		{
+			testName: "customized",
+			alloyCfg: `
+				region = "eu-west-1"
+				role_arn = "arn:aws:iam::123456789012:role/emf"
+				log_group_name = "/alloy/metrics"
+				log_stream_name = "alloy"
+				namespace = "Shop"
+				dimension_rollup_option = "NoDimensionRollup"
+				resource_to_telemetry_conversion = true
+			`,
+			expected: emf.Config{
+				SessionSettings: awsutil.SessionSettings{
+					Region:         "eu-west-1",
+					RoleARN:        "arn:aws:iam::123456789012:role/emf",
+					MaxRetries:     2,
+					RequestTimeout: 30 * time.Second,
+				},
+				LogGroupName:                  "/alloy/metrics",
+				LogStreamName:                 "alloy",
+				Namespace:                     "Shop",
+				DimensionRollupOption:         emf.NoDimensionRollup,
+				OutputDestination:             emf.OutputCloudWatch,
+				ResourceToTelemetryConversion: true,
+				QueueSettings:                 defaultQueue,
+				BackOffConfig:                 defaultRetry,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var args awsemf.Arguments
+			require.NoError(t, syntax.Unmarshal([]byte(tc.alloyCfg), &args))
+			actual, err := args.Convert()
+			require.NoError(t, err)
+			require.Equal(t, &tc.expected, actual.(*emf.Config))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		alloyCfg string
+		err      string
+	}{
+		{`max_retries = -1`, "max_retries must not be negative"},
+		{`dimension_rollup_option = "All"`, `unknown dimension rollup option "All"`},
+		{`output_destination = "file"`, `unknown output destination "file"`},
+		{`log_group_name = ""`, "log_group_name and log_stream_name must be set"},
+		{"log_group_name = \"\"\noutput_destination = \"stdout\"", ""},
+	}
+
+	for _, tc := range tests {
+		var args awsemf.Arguments
+		err := syntax.Unmarshal([]byte(tc.alloyCfg), &args)
+		if tc.err == "" {
+			require.NoError(t, err)
+		} else {
+			require.ErrorContains(t, err, tc.err)
+		}
+	}
+}
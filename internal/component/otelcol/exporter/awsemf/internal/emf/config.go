@@ -0,0 +1,68 @@
+// Package emf provides an OpenTelemetry Collector exporter which converts
+// metrics into CloudWatch embedded metric format (EMF) log events and writes
+// them to CloudWatch Logs, where CloudWatch extracts them as metrics.
+package emf
+
+import (
+	"fmt"
+
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/grafana/alloy/internal/component/otelcol/internal/awsutil"
+)
+
+// The dimension rollup options.
+const (
+	ZeroAndSingleDimensionRollup = "ZeroAndSingleDimensionRollup"
+	SingleDimensionRollupOnly    = "SingleDimensionRollupOnly"
+	NoDimensionRollup            = "NoDimensionRollup"
+)
+
+// The output destinations.
+const (
+	OutputCloudWatch = "cloudwatch"
+	OutputStdout     = "stdout"
+)
+
+// Config defines the configuration of the exporter.
+type Config struct {
+	awsutil.SessionSettings `mapstructure:",squash"`
+
+	LogGroupName  string `mapstructure:"log_group_name"`
+	LogStreamName string `mapstructure:"log_stream_name"`
+	// Namespace is the CloudWatch namespace of the metrics. When it's empty,
+	// the namespace is built from the service.namespace and service.name
+	// resource attributes.
+	Namespace string `mapstructure:"namespace"`
+	// DimensionRollupOption selects the dimension sets added next to the set
+	// of all the labels of a metric.
+	DimensionRollupOption string `mapstructure:"dimension_rollup_option"`
+	// OutputDestination is where the EMF log events are written.
+	OutputDestination string `mapstructure:"output_destination"`
+	// ResourceToTelemetryConversion adds the resource attributes to the
+	// labels of the metrics.
+	ResourceToTelemetryConversion bool `mapstructure:"resource_to_telemetry_conversion"`
+
+	QueueSettings exporterhelper.QueueConfig `mapstructure:"sending_queue"`
+	BackOffConfig configretry.BackOffConfig  `mapstructure:"retry_on_failure"`
+}
+
+// Validate returns an error if the configuration is invalid.
+func (cfg *Config) Validate() error {
+	switch cfg.DimensionRollupOption {
+	case ZeroAndSingleDimensionRollup, SingleDimensionRollupOnly, NoDimensionRollup:
+	default:
+		return fmt.Errorf("unknown dimension rollup option %q", cfg.DimensionRollupOption)
+	}
+	switch cfg.OutputDestination {
+	case OutputCloudWatch:
+		if cfg.LogGroupName == "" || cfg.LogStreamName == "" {
+			return fmt.Errorf("log_group_name and log_stream_name must be set")
+		}
+	case OutputStdout:
+	default:
+		return fmt.Errorf("unknown output destination %q", cfg.OutputDestination)
+	}
+	return nil
+}
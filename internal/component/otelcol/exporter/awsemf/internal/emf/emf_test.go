@@ -0,0 +1,270 @@
+package emf
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+var testStart = time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func testConfig() *Config {
+	return createDefaultConfig().(*Config)
+}
+
+// testMetrics returns a gauge, a cumulative sum and a cumulative histogram
+// with a point at ts and values scaled by n.
+func testMetrics(ts time.Time, n float64) pmetric.Metrics {
+	md := pmetric.NewMetrics()
+	rm := md.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("service.namespace", "shop")
+	rm.Resource().Attributes().PutStr("service.name", "checkout")
+	metrics := rm.ScopeMetrics().AppendEmpty().Metrics()
+
+	gauge := metrics.AppendEmpty()
+	gauge.SetName("queue_size")
+	gauge.SetUnit("By")
+	gdp := gauge.SetEmptyGauge().DataPoints().AppendEmpty()
+	gdp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	gdp.SetDoubleValue(n)
+	gdp.Attributes().PutStr("queue", "orders")
+
+	sum := metrics.AppendEmpty()
+	sum.SetName("requests")
+	s := sum.SetEmptySum()
+	s.SetIsMonotonic(true)
+	s.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	sdp := s.DataPoints().AppendEmpty()
+	sdp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	sdp.SetIntValue(int64(10 * n))
+	sdp.Attributes().PutStr("queue", "orders")
+
+	hist := metrics.AppendEmpty()
+	hist.SetName("latency")
+	hist.SetUnit("ms")
+	h := hist.SetEmptyHistogram()
+	h.SetAggregationTemporality(pmetric.AggregationTemporalityCumulative)
+	hdp := h.DataPoints().AppendEmpty()
+	hdp.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+	hdp.SetCount(uint64(4 * n))
+	hdp.SetSum(100 * n)
+	hdp.SetMin(1)
+	hdp.SetMax(50)
+	hdp.Attributes().PutStr("route", "/cart")
+	hdp.Attributes().PutStr("method", "GET")
+
+	return md
+}
+
+func TestEvents(t *testing.T) {
+	tr := newTranslator(testConfig())
+
+	// The first points of the cumulative series only set the baseline.
+	events := tr.events(testMetrics(testStart, 1), testStart)
+	require.Equal(t, []*event{{
+		namespace: "shop/checkout",
+		timestamp: testStart.UnixMilli(),
+		labels:    map[string]string{"queue": "orders"},
+		metrics:   []metricValue{{name: "queue_size", unit: "Bytes", value: 1.0}},
+	}}, events)
+
+	next := testStart.Add(time.Minute)
+	events = tr.events(testMetrics(next, 3), next)
+	require.Equal(t, []*event{
+		{
+			namespace: "shop/checkout",
+			timestamp: next.UnixMilli(),
+			labels:    map[string]string{"queue": "orders"},
+			metrics: []metricValue{
+				{name: "queue_size", unit: "Bytes", value: 3.0},
+				{name: "requests", value: 20.0},
+			},
+		},
+		{
+			namespace: "shop/checkout",
+			timestamp: next.UnixMilli(),
+			labels:    map[string]string{"route": "/cart", "method": "GET"},
+			metrics: []metricValue{{
+				name:  "latency",
+				unit:  "Milliseconds",
+				value: statisticSet{Max: 50, Min: 1, Count: 8, Sum: 200},
+			}},
+		},
+	}, events)
+}
+
+func TestEvents_Reset(t *testing.T) {
+	tr := newTranslator(testConfig())
+	tr.events(testMetrics(testStart, 3), testStart)
+
+	// After a reset, the new cumulative values are the deltas.
+	next := testStart.Add(time.Minute)
+	events := tr.events(testMetrics(next, 1), next)
+	require.Len(t, events, 2)
+	require.Equal(t, metricValue{name: "requests", value: 10.0}, events[0].metrics[1])
+	require.Equal(t, statisticSet{Max: 50, Min: 1, Count: 4, Sum: 100}, events[1].metrics[0].value)
+}
+
+func TestEvents_Namespace(t *testing.T) {
+	cfg := testConfig()
+	cfg.Namespace = "Custom"
+	cfg.ResourceToTelemetryConversion = true
+
+	events := newTranslator(cfg).events(testMetrics(testStart, 1), testStart)
+	require.Len(t, events, 1)
+	require.Equal(t, "Custom", events[0].namespace)
+	require.Equal(t, map[string]string{
+		"queue":             "orders",
+		"service.namespace": "shop",
+		"service.name":      "checkout",
+	}, events[0].labels)
+}
+
+func TestDimensions(t *testing.T) {
+	labels := map[string]string{"b": "1", "a": "2"}
+
+	require.Equal(t, [][]string{{"a", "b"}, {}, {"a"}, {"b"}}, dimensions(labels, ZeroAndSingleDimensionRollup))
+	require.Equal(t, [][]string{{"a", "b"}, {"a"}, {"b"}}, dimensions(labels, SingleDimensionRollupOnly))
+	require.Equal(t, [][]string{{"a", "b"}}, dimensions(labels, NoDimensionRollup))
+	require.Equal(t, [][]string{{"a"}, {}}, dimensions(map[string]string{"a": "1"}, ZeroAndSingleDimensionRollup))
+	require.Equal(t, [][]string{{}}, dimensions(nil, ZeroAndSingleDimensionRollup))
+}
+
+func TestMarshal(t *testing.T) {
+	e := &event{
+		namespace: "shop/checkout",
+		timestamp: testStart.UnixMilli(),
+		labels:    map[string]string{"queue": "orders"},
+		metrics: []metricValue{
+			{name: "queue_size", unit: "Bytes", value: 3.0},
+			{name: "latency", unit: "Milliseconds", value: statisticSet{Max: 50, Min: 1, Count: 8, Sum: 200}},
+		},
+	}
+	b, err := e.marshal(NoDimensionRollup)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"queue": "orders",
+		"queue_size": 3,
+		"latency": {"Max": 50, "Min": 1, "Count": 8, "Sum": 200},
+		"_aws": {
+			"Timestamp": 1714564800000,
+			"CloudWatchMetrics": [{
+				"Namespace": "shop/checkout",
+				"Dimensions": [["queue"]],
+				"Metrics": [
+					{"Name": "queue_size", "Unit": "Bytes"},
+					{"Name": "latency", "Unit": "Milliseconds"}
+				]
+			}]
+		}
+	}`, string(b))
+}
+
+func TestBatches(t *testing.T) {
+	newEvents := func(n int, size int, step time.Duration) []types.InputLogEvent {
+		res := make([]types.InputLogEvent, n)
+		for i := range res {
+			res[i] = types.InputLogEvent{
+				Message:   aws.String(strings.Repeat("x", size)),
+				Timestamp: aws.Int64(testStart.Add(time.Duration(i) * step).UnixMilli()),
+			}
+		}
+		return res
+	}
+
+	sizes := func(batches [][]types.InputLogEvent) []int {
+		var res []int
+		for _, b := range batches {
+			res = append(res, len(b))
+		}
+		return res
+	}
+
+	require.Equal(t, []int{10000, 500}, sizes(batches(newEvents(10500, 10, 0))))
+	require.Equal(t, []int{10, 10, 5}, sizes(batches(newEvents(25, 100*1024-eventOverhead, 0))))
+	require.Equal(t, []int{3, 2}, sizes(batches(newEvents(5, 10, 12*time.Hour))))
+}
+
+type fakeLogs struct {
+	streams  map[string][]string
+	requests int
+	err      error
+}
+
+func (f *fakeLogs) PutLogEvents(_ context.Context, params *cloudwatchlogs.PutLogEventsInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutLogEventsOutput, error) {
+	f.requests++
+	if f.err != nil {
+		return nil, f.err
+	}
+	key := *params.LogGroupName + ":" + *params.LogStreamName
+	if _, ok := f.streams[key]; !ok {
+		return nil, &types.ResourceNotFoundException{Message: aws.String("The specified log group does not exist.")}
+	}
+	for _, ev := range params.LogEvents {
+		f.streams[key] = append(f.streams[key], *ev.Message)
+	}
+	return &cloudwatchlogs.PutLogEventsOutput{}, nil
+}
+
+func (f *fakeLogs) CreateLogGroup(context.Context, *cloudwatchlogs.CreateLogGroupInput, ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CreateLogGroupOutput, error) {
+	return nil, &types.ResourceAlreadyExistsException{Message: aws.String("The specified log group already exists")}
+}
+
+func (f *fakeLogs) CreateLogStream(_ context.Context, params *cloudwatchlogs.CreateLogStreamInput, _ ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CreateLogStreamOutput, error) {
+	f.streams[*params.LogGroupName+":"+*params.LogStreamName] = nil
+	return &cloudwatchlogs.CreateLogStreamOutput{}, nil
+}
+
+func TestExporter(t *testing.T) {
+	logs := &fakeLogs{streams: make(map[string][]string)}
+	exp := newExporter(testConfig(), zap.NewNop())
+	exp.client = logs
+
+	require.NoError(t, exp.pushMetrics(context.Background(), testMetrics(testStart, 1)))
+	require.NoError(t, exp.pushMetrics(context.Background(), testMetrics(testStart.Add(time.Minute), 2)))
+
+	// The log stream is created by the first request.
+	require.Equal(t, 3, logs.requests)
+	messages := logs.streams["/metrics/default:otel-stream"]
+	require.Len(t, messages, 3)
+	for _, msg := range messages {
+		var doc map[string]any
+		require.NoError(t, json.Unmarshal([]byte(msg), &doc))
+		require.Contains(t, doc, "_aws")
+	}
+}
+
+func TestExporter_PermanentError(t *testing.T) {
+	exp := newExporter(testConfig(), zap.NewNop())
+	exp.client = &fakeLogs{err: &types.InvalidParameterException{Message: aws.String("invalid")}}
+
+	err := exp.pushMetrics(context.Background(), testMetrics(testStart, 1))
+	require.True(t, consumererror.IsPermanent(err))
+}
+
+func TestExporter_Stdout(t *testing.T) {
+	cfg := testConfig()
+	cfg.OutputDestination = OutputStdout
+
+	var buf bytes.Buffer
+	exp := newExporter(cfg, zap.NewNop())
+	exp.stdout = &buf
+	require.NoError(t, exp.start(context.Background(), nil))
+
+	require.NoError(t, exp.pushMetrics(context.Background(), testMetrics(testStart, 1)))
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 1)
+	require.Contains(t, lines[0], `"queue_size":1`)
+}
@@ -0,0 +1,193 @@
+package emf
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go-v2/service/cloudwatchlogs/types"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.uber.org/zap"
+)
+
+// The limits of the PutLogEvents API.
+//
+// See https://docs.aws.amazon.com/AmazonCloudWatchLogs/latest/APIReference/API_PutLogEvents.html.
+const (
+	maxEventsPerRequest = 10000
+	maxRequestSize      = 1048576
+	maxEventSize        = 256*1024 - eventOverhead
+	maxRequestTimeSpan  = 24 * time.Hour
+	// eventOverhead is the size added to the size of each event message.
+	eventOverhead = 26
+)
+
+// logsClient is the part of the CloudWatch Logs client used by the exporter.
+type logsClient interface {
+	PutLogEvents(ctx context.Context, params *cloudwatchlogs.PutLogEventsInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.PutLogEventsOutput, error)
+	CreateLogGroup(ctx context.Context, params *cloudwatchlogs.CreateLogGroupInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CreateLogGroupOutput, error)
+	CreateLogStream(ctx context.Context, params *cloudwatchlogs.CreateLogStreamInput, optFns ...func(*cloudwatchlogs.Options)) (*cloudwatchlogs.CreateLogStreamOutput, error)
+}
+
+type emfExporter struct {
+	cfg        *Config
+	logger     *zap.Logger
+	translator *translator
+
+	client logsClient
+	stdout io.Writer
+}
+
+func newExporter(cfg *Config, logger *zap.Logger) *emfExporter {
+	return &emfExporter{
+		cfg:        cfg,
+		logger:     logger,
+		translator: newTranslator(cfg),
+		stdout:     os.Stdout,
+	}
+}
+
+func (e *emfExporter) start(ctx context.Context, _ component.Host) error {
+	if e.cfg.OutputDestination != OutputCloudWatch {
+		return nil
+	}
+	awsCfg, err := e.cfg.SessionSettings.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	e.client = cloudwatchlogs.NewFromConfig(awsCfg, func(o *cloudwatchlogs.Options) {
+		if e.cfg.Endpoint != "" {
+			o.BaseEndpoint = aws.String(e.cfg.Endpoint)
+		}
+	})
+	return nil
+}
+
+func (e *emfExporter) pushMetrics(ctx context.Context, md pmetric.Metrics) error {
+	var logEvents []types.InputLogEvent
+	for _, ev := range e.translator.events(md, time.Now()) {
+		msg, err := ev.marshal(e.cfg.DimensionRollupOption)
+		if err != nil {
+			e.logger.Warn("failed to encode an EMF log event, dropping it", zap.Error(err))
+			continue
+		}
+		if len(msg) > maxEventSize {
+			e.logger.Warn("EMF log event is too large, dropping it", zap.Int("size", len(msg)), zap.String("namespace", ev.namespace))
+			continue
+		}
+		logEvents = append(logEvents, types.InputLogEvent{
+			Message:   aws.String(string(msg)),
+			Timestamp: aws.Int64(ev.timestamp),
+		})
+	}
+	if len(logEvents) == 0 {
+		return nil
+	}
+
+	if e.cfg.OutputDestination == OutputStdout {
+		for _, ev := range logEvents {
+			if _, err := fmt.Fprintln(e.stdout, *ev.Message); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// The events of a request must be in chronological order.
+	slices.SortStableFunc(logEvents, func(a, b types.InputLogEvent) int {
+		return int(*a.Timestamp - *b.Timestamp)
+	})
+
+	var errs error
+	for _, batch := range batches(logEvents) {
+		errs = errors.Join(errs, e.putLogEvents(ctx, batch))
+	}
+	return errs
+}
+
+// batches splits log events sorted by timestamp into batches within the
+// limits of a PutLogEvents request.
+func batches(logEvents []types.InputLogEvent) [][]types.InputLogEvent {
+	var (
+		res   [][]types.InputLogEvent
+		start int
+		size  int
+	)
+	for i, ev := range logEvents {
+		evSize := len(*ev.Message) + eventOverhead
+		if i > start && (i-start >= maxEventsPerRequest ||
+			size+evSize > maxRequestSize ||
+			time.Duration(*ev.Timestamp-*logEvents[start].Timestamp)*time.Millisecond > maxRequestTimeSpan) {
+
+			res = append(res, logEvents[start:i])
+			start, size = i, 0
+		}
+		size += evSize
+	}
+	return append(res, logEvents[start:])
+}
+
+// putLogEvents writes log events, creating the log group and stream when they
+// don't exist.
+func (e *emfExporter) putLogEvents(ctx context.Context, logEvents []types.InputLogEvent) error {
+	input := &cloudwatchlogs.PutLogEventsInput{
+		LogGroupName:  aws.String(e.cfg.LogGroupName),
+		LogStreamName: aws.String(e.cfg.LogStreamName),
+		LogEvents:     logEvents,
+	}
+
+	out, err := e.client.PutLogEvents(ctx, input)
+	var notFound *types.ResourceNotFoundException
+	if errors.As(err, &notFound) {
+		if err := e.createLogStream(ctx); err != nil {
+			return fmt.Errorf("failed to create log stream %s in log group %s: %w", e.cfg.LogStreamName, e.cfg.LogGroupName, err)
+		}
+		out, err = e.client.PutLogEvents(ctx, input)
+	}
+	if err != nil {
+		var (
+			invalidParameter    *types.InvalidParameterException
+			dataAlreadyAccepted *types.DataAlreadyAcceptedException
+		)
+		if errors.As(err, &invalidParameter) || errors.As(err, &dataAlreadyAccepted) {
+			return consumererror.NewPermanent(err)
+		}
+		return err
+	}
+
+	if info := out.RejectedLogEventsInfo; info != nil {
+		e.logger.Warn("CloudWatch Logs rejected EMF log events",
+			zap.Int32p("too_new_start_index", info.TooNewLogEventStartIndex),
+			zap.Int32p("too_old_end_index", info.TooOldLogEventEndIndex),
+			zap.Int32p("expired_end_index", info.ExpiredLogEventEndIndex))
+	}
+	return nil
+}
+
+func (e *emfExporter) createLogStream(ctx context.Context) error {
+	var exists *types.ResourceAlreadyExistsException
+
+	_, err := e.client.CreateLogGroup(ctx, &cloudwatchlogs.CreateLogGroupInput{
+		LogGroupName: aws.String(e.cfg.LogGroupName),
+	})
+	if err != nil && !errors.As(err, &exists) {
+		return err
+	}
+
+	_, err = e.client.CreateLogStream(ctx, &cloudwatchlogs.CreateLogStreamInput{
+		LogGroupName:  aws.String(e.cfg.LogGroupName),
+		LogStreamName: aws.String(e.cfg.LogStreamName),
+	})
+	if err != nil && !errors.As(err, &exists) {
+		return err
+	}
+	return nil
+}
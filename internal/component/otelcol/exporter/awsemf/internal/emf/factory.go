@@ -0,0 +1,61 @@
+package emf
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/grafana/alloy/internal/component/otelcol/internal/awsutil"
+)
+
+// TypeStr is the unique identifier for the exporter.
+const TypeStr = "awsemf"
+
+// NewFactory returns a new factory for the exporter.
+func NewFactory() exporter.Factory {
+	return exporter.NewFactory(
+		component.MustNewType(TypeStr),
+		createDefaultConfig,
+		exporter.WithMetrics(createMetricsExporter, component.StabilityLevelBeta),
+	)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		SessionSettings: awsutil.SessionSettings{
+			MaxRetries:     2,
+			RequestTimeout: 30 * time.Second,
+		},
+		LogGroupName:          "/metrics/default",
+		LogStreamName:         "otel-stream",
+		DimensionRollupOption: ZeroAndSingleDimensionRollup,
+		OutputDestination:     OutputCloudWatch,
+		QueueSettings:         exporterhelper.NewDefaultQueueConfig(),
+		BackOffConfig:         configretry.NewDefaultBackOffConfig(),
+	}
+}
+
+func createMetricsExporter(
+	ctx context.Context,
+	params exporter.Settings,
+	cfg component.Config,
+) (exporter.Metrics, error) {
+
+	eCfg := cfg.(*Config)
+	exp := newExporter(eCfg, params.Logger)
+	return exporterhelper.NewMetrics(
+		ctx,
+		params,
+		cfg,
+		exp.pushMetrics,
+		exporterhelper.WithStart(exp.start),
+		// The AWS clients apply the request timeout.
+		exporterhelper.WithTimeout(exporterhelper.TimeoutConfig{Timeout: 0}),
+		exporterhelper.WithQueue(eCfg.QueueSettings),
+		exporterhelper.WithRetry(eCfg.BackOffConfig),
+	)
+}
@@ -0,0 +1,436 @@
+package emf
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	semconv "go.opentelemetry.io/collector/semconv/v1.27.0"
+)
+
+const (
+	// defaultNamespace is the namespace of the metrics without a namespace
+	// or a service name.
+	defaultNamespace = "default"
+	// maxDimensions is the maximum number of dimensions of a metric.
+	maxDimensions = 30
+	// deltaStaleness is the time after which the last value of a cumulative
+	// series which isn't written anymore is forgotten.
+	deltaStaleness = 5 * time.Minute
+)
+
+// event is an EMF log event, with the metrics sharing the same namespace,
+// timestamp and labels.
+//
+// See https://docs.aws.amazon.com/AmazonCloudWatch/latest/monitoring/CloudWatch_Embedded_Metric_Format_Specification.html.
+type event struct {
+	namespace string
+	timestamp int64
+	labels    map[string]string
+	metrics   []metricValue
+}
+
+type metricValue struct {
+	name  string
+	unit  string
+	value any
+}
+
+// statisticSet is the value of the histograms and summaries.
+type statisticSet struct {
+	Max   float64
+	Min   float64
+	Count uint64
+	Sum   float64
+}
+
+type metadata struct {
+	Timestamp         int64             `json:"Timestamp"`
+	CloudWatchMetrics []metricDirective `json:"CloudWatchMetrics"`
+}
+
+type metricDirective struct {
+	Namespace  string             `json:"Namespace"`
+	Dimensions [][]string         `json:"Dimensions"`
+	Metrics    []metricDefinition `json:"Metrics"`
+}
+
+type metricDefinition struct {
+	Name string `json:"Name"`
+	Unit string `json:"Unit,omitempty"`
+}
+
+// translator converts metrics into EMF log events.
+type translator struct {
+	cfg    *Config
+	deltas *deltaCalculator
+}
+
+func newTranslator(cfg *Config) *translator {
+	return &translator{
+		cfg:    cfg,
+		deltas: newDeltaCalculator(),
+	}
+}
+
+// events converts metrics into EMF log events. The first point of each
+// cumulative series is only used to compute the delta of the next one.
+func (t *translator) events(md pmetric.Metrics, now time.Time) []*event {
+	var (
+		res    []*event
+		groups = make(map[string]*event)
+	)
+	add := func(namespace string, labels map[string]string, ts pcommon.Timestamp, m metricValue) {
+		key := groupKey(namespace, ts, labels)
+		e, ok := groups[key]
+		if !ok {
+			e = &event{namespace: namespace, timestamp: ts.AsTime().UnixMilli(), labels: labels}
+			if ts == 0 {
+				e.timestamp = now.UnixMilli()
+			}
+			groups[key] = e
+			res = append(res, e)
+		}
+		e.metrics = append(e.metrics, m)
+	}
+
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		namespace := t.namespace(rm.Resource())
+
+		var resLabels map[string]string
+		if t.cfg.ResourceToTelemetryConversion {
+			resLabels = labelsOf(nil, rm.Resource().Attributes())
+		}
+
+		sms := rm.ScopeMetrics()
+		for j := 0; j < sms.Len(); j++ {
+			metrics := sms.At(j).Metrics()
+			for k := 0; k < metrics.Len(); k++ {
+				t.addMetric(namespace, resLabels, metrics.At(k), now, add)
+			}
+		}
+	}
+
+	t.deltas.prune(now)
+	return res
+}
+
+func (t *translator) addMetric(
+	namespace string,
+	resLabels map[string]string,
+	m pmetric.Metric,
+	now time.Time,
+	add func(namespace string, labels map[string]string, ts pcommon.Timestamp, m metricValue),
+) {
+
+	unit := unitOf(m.Unit())
+	point := func(attrs pcommon.Map, ts pcommon.Timestamp, value any) {
+		add(namespace, labelsOf(resLabels, attrs), ts, metricValue{name: m.Name(), unit: unit, value: value})
+	}
+	// delta returns the difference between values and the previous values
+	// of a cumulative series.
+	delta := func(attrs pcommon.Map, values ...float64) ([]float64, bool) {
+		return t.deltas.delta(seriesKey(namespace, m.Name(), labelsOf(resLabels, attrs)), values, now)
+	}
+
+	switch m.Type() {
+	case pmetric.MetricTypeGauge:
+		dps := m.Gauge().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			point(dp.Attributes(), dp.Timestamp(), numberValue(dp))
+		}
+
+	case pmetric.MetricTypeSum:
+		cumulative := m.Sum().AggregationTemporality() == pmetric.AggregationTemporalityCumulative && m.Sum().IsMonotonic()
+		dps := m.Sum().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			v := numberValue(dp)
+			if cumulative {
+				d, ok := delta(dp.Attributes(), v)
+				if !ok {
+					continue
+				}
+				v = d[0]
+			}
+			point(dp.Attributes(), dp.Timestamp(), v)
+		}
+
+	case pmetric.MetricTypeHistogram:
+		cumulative := m.Histogram().AggregationTemporality() == pmetric.AggregationTemporalityCumulative
+		dps := m.Histogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			stats := statisticSet{Count: dp.Count(), Sum: dp.Sum(), Min: dp.Min(), Max: dp.Max()}
+			if !dp.HasMin() || !dp.HasMax() {
+				stats.Min, stats.Max = mean(dp.Sum(), dp.Count()), mean(dp.Sum(), dp.Count())
+			}
+			if cumulative && !deltaStats(&stats, func(v ...float64) ([]float64, bool) { return delta(dp.Attributes(), v...) }) {
+				continue
+			}
+			point(dp.Attributes(), dp.Timestamp(), stats)
+		}
+
+	case pmetric.MetricTypeExponentialHistogram:
+		cumulative := m.ExponentialHistogram().AggregationTemporality() == pmetric.AggregationTemporalityCumulative
+		dps := m.ExponentialHistogram().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			stats := statisticSet{Count: dp.Count(), Sum: dp.Sum(), Min: dp.Min(), Max: dp.Max()}
+			if !dp.HasMin() || !dp.HasMax() {
+				stats.Min, stats.Max = mean(dp.Sum(), dp.Count()), mean(dp.Sum(), dp.Count())
+			}
+			if cumulative && !deltaStats(&stats, func(v ...float64) ([]float64, bool) { return delta(dp.Attributes(), v...) }) {
+				continue
+			}
+			point(dp.Attributes(), dp.Timestamp(), stats)
+		}
+
+	case pmetric.MetricTypeSummary:
+		dps := m.Summary().DataPoints()
+		for i := 0; i < dps.Len(); i++ {
+			dp := dps.At(i)
+			stats := statisticSet{Count: dp.Count(), Sum: dp.Sum(), Min: mean(dp.Sum(), dp.Count()), Max: mean(dp.Sum(), dp.Count())}
+			qs := dp.QuantileValues()
+			for j := 0; j < qs.Len(); j++ {
+				switch qs.At(j).Quantile() {
+				case 0:
+					stats.Min = qs.At(j).Value()
+				case 1:
+					stats.Max = qs.At(j).Value()
+				}
+			}
+			// Summaries are always cumulative.
+			if !deltaStats(&stats, func(v ...float64) ([]float64, bool) { return delta(dp.Attributes(), v...) }) {
+				continue
+			}
+			point(dp.Attributes(), dp.Timestamp(), stats)
+		}
+	}
+}
+
+// deltaStats replaces the count and sum of a cumulative statistic set with
+// their delta, and returns false if it's the first point of the series or
+// nothing was observed since the previous one.
+func deltaStats(stats *statisticSet, delta func(values ...float64) ([]float64, bool)) bool {
+	d, ok := delta(float64(stats.Count), stats.Sum)
+	if !ok || d[0] == 0 {
+		return false
+	}
+	stats.Count, stats.Sum = uint64(d[0]), d[1]
+	return true
+}
+
+// namespace returns the CloudWatch namespace of the metrics of a resource.
+func (t *translator) namespace(res pcommon.Resource) string {
+	if t.cfg.Namespace != "" {
+		return t.cfg.Namespace
+	}
+	attrs := res.Attributes()
+	name, ok := attrs.Get(semconv.AttributeServiceName)
+	if !ok || name.AsString() == "" {
+		return defaultNamespace
+	}
+	if ns, ok := attrs.Get(semconv.AttributeServiceNamespace); ok && ns.AsString() != "" {
+		return ns.AsString() + "/" + name.AsString()
+	}
+	return name.AsString()
+}
+
+// marshal encodes the event as an EMF log event.
+func (e *event) marshal(rollup string) ([]byte, error) {
+	doc := make(map[string]any, len(e.labels)+len(e.metrics)+1)
+	for k, v := range e.labels {
+		doc[k] = v
+	}
+
+	directive := metricDirective{
+		Namespace:  e.namespace,
+		Dimensions: dimensions(e.labels, rollup),
+	}
+	for _, m := range e.metrics {
+		if _, ok := doc[m.name]; ok {
+			continue
+		}
+		doc[m.name] = m.value
+		directive.Metrics = append(directive.Metrics, metricDefinition{Name: m.name, Unit: m.unit})
+	}
+	doc["_aws"] = metadata{
+		Timestamp:         e.timestamp,
+		CloudWatchMetrics: []metricDirective{directive},
+	}
+	return json.Marshal(doc)
+}
+
+// dimensions returns the dimension sets of metrics with the given labels:
+// the set of all the labels and the sets of the rollup option.
+func dimensions(labels map[string]string, rollup string) [][]string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	var res [][]string
+	if len(keys) <= maxDimensions {
+		res = append(res, keys)
+	}
+	if len(keys) == 0 {
+		return res
+	}
+	if rollup == ZeroAndSingleDimensionRollup {
+		res = append(res, []string{})
+	}
+	// With a single label, the single dimension rollup is the set of all the
+	// labels.
+	if (rollup == ZeroAndSingleDimensionRollup || rollup == SingleDimensionRollupOnly) && len(keys) > 1 {
+		for _, k := range keys {
+			res = append(res, []string{k})
+		}
+	}
+	return res
+}
+
+// labelsOf returns the labels of a point with the given attributes.
+func labelsOf(base map[string]string, attrs pcommon.Map) map[string]string {
+	res := make(map[string]string, len(base)+attrs.Len())
+	for k, v := range base {
+		res[k] = v
+	}
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		res[k] = v.AsString()
+		return true
+	})
+	return res
+}
+
+func numberValue(dp pmetric.NumberDataPoint) float64 {
+	if dp.ValueType() == pmetric.NumberDataPointValueTypeInt {
+		return float64(dp.IntValue())
+	}
+	return dp.DoubleValue()
+}
+
+func mean(sum float64, count uint64) float64 {
+	if count == 0 {
+		return 0
+	}
+	return sum / float64(count)
+}
+
+// unitOf converts a UCUM unit into a CloudWatch unit. Units without an
+// equivalent are left out.
+func unitOf(unit string) string {
+	switch unit {
+	case "s":
+		return "Seconds"
+	case "ms":
+		return "Milliseconds"
+	case "us":
+		return "Microseconds"
+	case "By":
+		return "Bytes"
+	case "bit":
+		return "Bits"
+	case "By/s":
+		return "Bytes/Second"
+	case "bit/s":
+		return "Bits/Second"
+	case "%":
+		return "Percent"
+	case "1", "{count}":
+		return "Count"
+	default:
+		return ""
+	}
+}
+
+func groupKey(namespace string, ts pcommon.Timestamp, labels map[string]string) string {
+	return namespace + "\xff" + ts.String() + "\xff" + labelsKey(labels)
+}
+
+func seriesKey(namespace, name string, labels map[string]string) string {
+	return namespace + "\xff" + name + "\xff" + labelsKey(labels)
+}
+
+func labelsKey(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	var sb strings.Builder
+	for _, k := range keys {
+		sb.WriteString(k)
+		sb.WriteByte('\xfe')
+		sb.WriteString(labels[k])
+		sb.WriteByte('\xfe')
+	}
+	return sb.String()
+}
+
+// deltaCalculator computes the deltas of cumulative series.
+type deltaCalculator struct {
+	mut       sync.Mutex
+	entries   map[string]*deltaEntry
+	lastPrune time.Time
+}
+
+type deltaEntry struct {
+	values   []float64
+	lastSeen time.Time
+}
+
+func newDeltaCalculator() *deltaCalculator {
+	return &deltaCalculator{entries: make(map[string]*deltaEntry)}
+}
+
+// delta returns the difference between values and the previous values of the
+// series, and false if it's the first point of the series. The first value
+// is a counter: when it's lower than the previous one, the series was reset
+// and values are returned.
+func (d *deltaCalculator) delta(key string, values []float64, now time.Time) ([]float64, bool) {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+
+	e, ok := d.entries[key]
+	if !ok {
+		d.entries[key] = &deltaEntry{values: values, lastSeen: now}
+		return nil, false
+	}
+	prev := e.values
+	e.values, e.lastSeen = values, now
+
+	if len(prev) != len(values) || values[0] < prev[0] {
+		return values, true
+	}
+	res := make([]float64, len(values))
+	for i, v := range values {
+		res[i] = v - prev[i]
+	}
+	return res, true
+}
+
+// prune forgets the series which weren't written for a while.
+func (d *deltaCalculator) prune(now time.Time) {
+	d.mut.Lock()
+	defer d.mut.Unlock()
+
+	if now.Sub(d.lastPrune) < deltaStaleness {
+		return
+	}
+	d.lastPrune = now
+	for k, e := range d.entries {
+		if now.Sub(e.lastSeen) > deltaStaleness {
+			delete(d.entries, k)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+// Package awsxray provides an otelcol.exporter.awsxray component
+package awsxray
+
+import (
+	otelcomponent "go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pipeline"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
+	"github.com/grafana/alloy/internal/component/otelcol/exporter"
+	"github.com/grafana/alloy/internal/component/otelcol/exporter/awsxray/internal/xray"
+	"github.com/grafana/alloy/internal/featuregate"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.exporter.awsxray",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   otelcol.ConsumerExports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			fact := xray.NewFactory()
+			return exporter.New(opts, fact, args.(Arguments), exporter.TypeSignalConstFunc(exporter.TypeTraces))
+		},
+	})
+}
+
+// Arguments configures the otelcol.exporter.awsxray component.
+type Arguments struct {
+	AWS otelcol.AWSSessionArguments `alloy:",squash"`
+
+	IndexedAttributes  []string `alloy:"indexed_attributes,attr,optional"`
+	IndexAllAttributes bool     `alloy:"index_all_attributes,attr,optional"`
+
+	Queue otelcol.QueueArguments `alloy:"sending_queue,block,optional"`
+	Retry otelcol.RetryArguments `alloy:"retry_on_failure,block,optional"`
+
+	// DebugMetrics configures component internal metrics. Optional
+	DebugMetrics otelcolCfg.DebugMetricsArguments `alloy:"debug_metrics,block,optional"`
+}
+
+var _ exporter.Arguments = Arguments{}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{}
+	args.AWS.SetToDefault()
+	args.Queue.SetToDefault()
+	args.Retry.SetToDefault()
+	args.DebugMetrics.SetToDefault()
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	return args.AWS.Validate()
+}
+
+// Convert implements exporter.Arguments.
+func (args Arguments) Convert() (otelcomponent.Config, error) {
+	q, err := args.Queue.Convert()
+	if err != nil {
+		return nil, err
+	}
+	return &xray.Config{
+		SessionSettings:    args.AWS.Convert(),
+		IndexedAttributes:  args.IndexedAttributes,
+		IndexAllAttributes: args.IndexAllAttributes,
+		QueueSettings:      *q,
+		BackOffConfig:      *args.Retry.Convert(),
+	}, nil
+}
+
+// Extensions implements exporter.Arguments.
+func (args Arguments) Extensions() map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// Exporters implements exporter.Arguments.
+func (args Arguments) Exporters() map[pipeline.Signal]map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// DebugMetricsConfig implements exporter.Arguments.
+func (args Arguments) DebugMetricsConfig() otelcolCfg.DebugMetricsArguments {
+	return args.DebugMetrics
+}
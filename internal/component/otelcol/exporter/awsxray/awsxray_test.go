@@ -0,0 +1,107 @@
+package awsxray_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/grafana/alloy/internal/component/otelcol/exporter/awsxray"
+	"github.com/grafana/alloy/internal/component/otelcol/exporter/awsxray/internal/xray"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/awsutil"
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestConfigConversion(t *testing.T) {
+	defaultRetry := configretry.BackOffConfig{
+		Enabled:             true,
+		InitialInterval:     5 * time.Second,
+		RandomizationFactor: 0.5,
+		Multiplier:          1.5,
+		MaxInterval:         30 * time.Second,
+		MaxElapsedTime:      5 * time.Minute,
+	}
+
+	tests := []struct {
+		testName string
+		alloyCfg string
+		expected xray.Config
+	}{
+		{
+			testName: "default",
+			alloyCfg: ``,
+			expected: xray.Config{
+				SessionSettings: awsutil.SessionSettings{
+					MaxRetries:     2,
+					RequestTimeout: 30 * time.Second,
+				},
+				QueueSettings: exporterhelper.QueueConfig{
+					Enabled:      true,
+					NumConsumers: 10,
+					QueueSize:    1000,
+				},
+				BackOffConfig: defaultRetry,
+			},
+		},
+		{
+			testName: "customized",
+			alloyCfg: `
+				region = "eu-west-1"
+				endpoint = "https://xray.example.com"
+				role_arn = "arn:aws:iam::123456789012:role/xray"
+				max_retries = 5
+				request_timeout = "10s"
+				no_verify_ssl = true
+				indexed_attributes = ["user.id", "tenant"]
+				index_all_attributes = true
+
+				sending_queue {
+					num_consumers = 2
+				}
+				retry_on_failure {
+					enabled = false
+				}
+			`,
+			expected: xray.Config{
+				SessionSettings: awsutil.SessionSettings{
+					Region:         "eu-west-1",
+					Endpoint:       "https://xray.example.com",
+					RoleARN:        "arn:aws:iam::123456789012:role/xray",
+					MaxRetries:     5,
+					RequestTimeout: 10 * time.Second,
+					NoVerifySSL:    true,
+				},
+				IndexedAttributes:  []string{"user.id", "tenant"},
+				IndexAllAttributes: true,
+				QueueSettings: exporterhelper.QueueConfig{
+					Enabled:      true,
+					NumConsumers: 2,
+					QueueSize:    1000,
+				},
+				BackOffConfig: func() configretry.BackOffConfig {
+					r := defaultRetry
+					r.Enabled = false
+					return r
+				}(),
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var args awsxray.Arguments
+			require.NoError(t, syntax.Unmarshal([]byte(tc.alloyCfg), &args))
+			actual, err := args.Convert()
+			require.NoError(t, err)
+			require.Equal(t, &tc.expected, actual.(*xray.Config))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	var args awsxray.Arguments
+	require.ErrorContains(t, syntax.Unmarshal([]byte(`max_retries = -1`), &args), "max_retries must not be negative")
+	require.ErrorContains(t, syntax.Unmarshal([]byte(`request_timeout = "0s"`), &args), "request_timeout must be greater than 0")
+}
@@ -0,0 +1,157 @@
+package xray
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+)
+
+// signingName is the name of the X-Ray service used to sign requests.
+const signingName = "xray"
+
+// client calls the X-Ray PutTraceSegments API.
+//
+// See https://docs.aws.amazon.com/xray/latest/api/API_PutTraceSegments.html.
+type client struct {
+	cfg      aws.Config
+	endpoint string
+	signer   *v4.Signer
+	retryer  aws.Retryer
+}
+
+func newClient(cfg aws.Config, endpoint string) *client {
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://xray.%s.amazonaws.com", cfg.Region)
+	}
+	var retryer aws.Retryer = retry.NewStandard()
+	if cfg.Retryer != nil {
+		retryer = cfg.Retryer()
+	}
+	return &client{
+		cfg:      cfg,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		signer:   v4.NewSigner(),
+		retryer:  retryer,
+	}
+}
+
+type putTraceSegmentsInput struct {
+	TraceSegmentDocuments []string `json:"TraceSegmentDocuments"`
+}
+
+type putTraceSegmentsOutput struct {
+	UnprocessedTraceSegments []unprocessedTraceSegment `json:"UnprocessedTraceSegments"`
+}
+
+type unprocessedTraceSegment struct {
+	ID        string `json:"Id"`
+	ErrorCode string `json:"ErrorCode"`
+	Message   string `json:"Message"`
+}
+
+// apiError is returned when the X-Ray API responds with an error. It
+// implements the interfaces the retryer of the AWS SDK uses to find out
+// whether a request should be retried.
+type apiError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("PutTraceSegments failed with status %d: %s: %s", e.status, e.code, e.message)
+}
+
+func (e *apiError) HTTPStatusCode() int { return e.status }
+func (e *apiError) ErrorCode() string   { return e.code }
+
+// PutTraceSegments sends segment documents, retrying with the retryer of the
+// AWS configuration, and returns the segments which weren't processed.
+func (c *client) PutTraceSegments(ctx context.Context, documents []string) ([]unprocessedTraceSegment, error) {
+	body, err := json.Marshal(putTraceSegmentsInput{TraceSegmentDocuments: documents})
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		out, err := c.send(ctx, body)
+		if err == nil {
+			return out.UnprocessedTraceSegments, nil
+		}
+		if attempt >= c.retryer.MaxAttempts() || !c.retryer.IsErrorRetryable(err) {
+			return nil, err
+		}
+
+		delay, delayErr := c.retryer.RetryDelay(attempt, err)
+		if delayErr != nil {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+func (c *client) send(ctx context.Context, body []byte) (*putTraceSegmentsOutput, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+"/TraceSegments", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	creds, err := c.cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	hash := sha256.Sum256(body)
+	if err := c.signer.SignHTTP(ctx, creds, req, hex.EncodeToString(hash[:]), signingName, c.cfg.Region, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
+
+	resp, err := c.cfg.HTTPClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode/100 != 2 {
+		apiErr := &apiError{status: resp.StatusCode, code: resp.Header.Get("X-Amzn-ErrorType")}
+		var errBody struct {
+			Type    string `json:"__type"`
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(respBody, &errBody) == nil {
+			if apiErr.code == "" {
+				apiErr.code = errBody.Type
+			}
+			apiErr.message = errBody.Message
+		}
+		// The error type may be followed by details, like
+		// ThrottledException:http://internal.amazon.com/coral/...
+		apiErr.code, _, _ = strings.Cut(apiErr.code, ":")
+		return nil, apiErr
+	}
+
+	var out putTraceSegmentsOutput
+	if err := json.Unmarshal(respBody, &out); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	return &out, nil
+}
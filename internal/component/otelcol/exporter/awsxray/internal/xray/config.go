@@ -0,0 +1,26 @@
+// Package xray provides an OpenTelemetry Collector exporter which converts
+// spans into AWS X-Ray segment documents and sends them with the X-Ray
+// PutTraceSegments API.
+package xray
+
+import (
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/grafana/alloy/internal/component/otelcol/internal/awsutil"
+)
+
+// Config defines the configuration of the exporter.
+type Config struct {
+	awsutil.SessionSettings `mapstructure:",squash"`
+
+	// IndexedAttributes are the span attributes which are converted into
+	// X-Ray annotations, which can be searched. The other attributes are
+	// converted into metadata.
+	IndexedAttributes []string `mapstructure:"indexed_attributes"`
+	// IndexAllAttributes converts all the span attributes into annotations.
+	IndexAllAttributes bool `mapstructure:"index_all_attributes"`
+
+	QueueSettings exporterhelper.QueueConfig `mapstructure:"sending_queue"`
+	BackOffConfig configretry.BackOffConfig  `mapstructure:"retry_on_failure"`
+}
@@ -0,0 +1,95 @@
+package xray
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+// maxSegmentsPerRequest is the maximum number of segment documents sent in a
+// single PutTraceSegments request.
+const maxSegmentsPerRequest = 50
+
+type xrayExporter struct {
+	cfg        *Config
+	logger     *zap.Logger
+	translator *translator
+	client     *client
+}
+
+func newExporter(cfg *Config, logger *zap.Logger) *xrayExporter {
+	return &xrayExporter{
+		cfg:        cfg,
+		logger:     logger,
+		translator: newTranslator(cfg),
+	}
+}
+
+func (e *xrayExporter) start(ctx context.Context, _ component.Host) error {
+	awsCfg, err := e.cfg.SessionSettings.LoadConfig(ctx)
+	if err != nil {
+		return err
+	}
+	e.client = newClient(awsCfg, e.cfg.Endpoint)
+	return nil
+}
+
+func (e *xrayExporter) pushTraces(ctx context.Context, td ptrace.Traces) error {
+	documents := e.documents(td)
+
+	var errs error
+	for len(documents) > 0 {
+		n := min(len(documents), maxSegmentsPerRequest)
+		batch := documents[:n]
+		documents = documents[n:]
+
+		unprocessed, err := e.client.PutTraceSegments(ctx, batch)
+		if err != nil {
+			errs = errors.Join(errs, wrapError(err))
+			continue
+		}
+		for _, s := range unprocessed {
+			e.logger.Warn("X-Ray didn't process a segment",
+				zap.String("id", s.ID), zap.String("error_code", s.ErrorCode), zap.String("message", s.Message))
+		}
+	}
+	return errs
+}
+
+// documents converts the spans into segment documents. Spans which can't be
+// converted are dropped.
+func (e *xrayExporter) documents(td ptrace.Traces) []string {
+	var res []string
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		sss := rs.ScopeSpans()
+		for j := 0; j < sss.Len(); j++ {
+			spans := sss.At(j).Spans()
+			for k := 0; k < spans.Len(); k++ {
+				doc, err := json.Marshal(e.translator.segment(spans.At(k), rs.Resource()))
+				if err != nil {
+					e.logger.Warn("failed to convert a span into an X-Ray segment, dropping it", zap.Error(err))
+					continue
+				}
+				res = append(res, string(doc))
+			}
+		}
+	}
+	return res
+}
+
+// wrapError marks the errors for which retrying the request wouldn't help as
+// permanent.
+func wrapError(err error) error {
+	var apiErr *apiError
+	if errors.As(err, &apiErr) && apiErr.status >= 400 && apiErr.status < 500 && apiErr.status != 429 {
+		return consumererror.NewPermanent(err)
+	}
+	return err
+}
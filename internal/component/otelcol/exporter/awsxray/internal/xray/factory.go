@@ -0,0 +1,57 @@
+package xray
+
+import (
+	"context"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/configretry"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	"github.com/grafana/alloy/internal/component/otelcol/internal/awsutil"
+)
+
+// TypeStr is the unique identifier for the exporter.
+const TypeStr = "awsxray"
+
+// NewFactory returns a new factory for the exporter.
+func NewFactory() exporter.Factory {
+	return exporter.NewFactory(
+		component.MustNewType(TypeStr),
+		createDefaultConfig,
+		exporter.WithTraces(createTracesExporter, component.StabilityLevelBeta),
+	)
+}
+
+func createDefaultConfig() component.Config {
+	return &Config{
+		SessionSettings: awsutil.SessionSettings{
+			MaxRetries:     2,
+			RequestTimeout: 30 * time.Second,
+		},
+		QueueSettings: exporterhelper.NewDefaultQueueConfig(),
+		BackOffConfig: configretry.NewDefaultBackOffConfig(),
+	}
+}
+
+func createTracesExporter(
+	ctx context.Context,
+	params exporter.Settings,
+	cfg component.Config,
+) (exporter.Traces, error) {
+
+	eCfg := cfg.(*Config)
+	exp := newExporter(eCfg, params.Logger)
+	return exporterhelper.NewTraces(
+		ctx,
+		params,
+		cfg,
+		exp.pushTraces,
+		exporterhelper.WithStart(exp.start),
+		// The AWS clients apply the request timeout.
+		exporterhelper.WithTimeout(exporterhelper.TimeoutConfig{Timeout: 0}),
+		exporterhelper.WithQueue(eCfg.QueueSettings),
+		exporterhelper.WithRetry(eCfg.BackOffConfig),
+	)
+}
@@ -0,0 +1,288 @@
+package xray
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	semconv "go.opentelemetry.io/collector/semconv/v1.27.0"
+)
+
+// maxSegmentNameLength is the maximum length of the name of a segment.
+const maxSegmentNameLength = 200
+
+// segment is an X-Ray segment document. Spans which aren't the entry point of
+// a service are written as independent subsegments, which have the same
+// fields as segments.
+//
+// See https://docs.aws.amazon.com/xray/latest/devguide/xray-api-segmentdocuments.html.
+type segment struct {
+	Name        string                    `json:"name"`
+	ID          string                    `json:"id"`
+	TraceID     string                    `json:"trace_id"`
+	ParentID    string                    `json:"parent_id,omitempty"`
+	StartTime   float64                   `json:"start_time"`
+	EndTime     float64                   `json:"end_time"`
+	Type        string                    `json:"type,omitempty"`
+	Namespace   string                    `json:"namespace,omitempty"`
+	Error       bool                      `json:"error,omitempty"`
+	Throttle    bool                      `json:"throttle,omitempty"`
+	Fault       bool                      `json:"fault,omitempty"`
+	Cause       *cause                    `json:"cause,omitempty"`
+	HTTP        *httpData                 `json:"http,omitempty"`
+	Service     *serviceData              `json:"service,omitempty"`
+	Annotations map[string]any            `json:"annotations,omitempty"`
+	Metadata    map[string]map[string]any `json:"metadata,omitempty"`
+}
+
+type cause struct {
+	Exceptions []exception `json:"exceptions"`
+}
+
+type exception struct {
+	ID      string `json:"id"`
+	Type    string `json:"type,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type httpData struct {
+	Request  *requestData  `json:"request,omitempty"`
+	Response *responseData `json:"response,omitempty"`
+}
+
+type requestData struct {
+	Method    string `json:"method,omitempty"`
+	URL       string `json:"url,omitempty"`
+	UserAgent string `json:"user_agent,omitempty"`
+	ClientIP  string `json:"client_ip,omitempty"`
+}
+
+type responseData struct {
+	Status int64 `json:"status,omitempty"`
+}
+
+type serviceData struct {
+	Version string `json:"version"`
+}
+
+// translator converts spans into segments.
+type translator struct {
+	indexedAttributes  map[string]struct{}
+	indexAllAttributes bool
+}
+
+func newTranslator(cfg *Config) *translator {
+	t := &translator{
+		indexedAttributes:  make(map[string]struct{}, len(cfg.IndexedAttributes)),
+		indexAllAttributes: cfg.IndexAllAttributes,
+	}
+	for _, k := range cfg.IndexedAttributes {
+		t.indexedAttributes[k] = struct{}{}
+	}
+	return t
+}
+
+// segment converts a span of a resource into a segment.
+func (t *translator) segment(span ptrace.Span, res pcommon.Resource) *segment {
+	attrs := span.Attributes()
+	resAttrs := res.Attributes()
+
+	seg := &segment{
+		ID:        hexID(span.SpanID()),
+		TraceID:   traceID(span.TraceID()),
+		StartTime: timestamp(span.StartTimestamp()),
+		EndTime:   timestamp(span.EndTimestamp()),
+	}
+	if !span.ParentSpanID().IsEmpty() {
+		seg.ParentID = hexID(span.ParentSpanID())
+	}
+
+	// Server spans and root spans are the entry point of a service and are
+	// named after it. The other spans are subsegments, named after the
+	// service they call when it's known.
+	name := span.Name()
+	switch {
+	case span.Kind() == ptrace.SpanKindServer || span.ParentSpanID().IsEmpty():
+		if v := stringAttr(resAttrs, semconv.AttributeServiceName); v != "" {
+			name = v
+		}
+	default:
+		seg.Type = "subsegment"
+		if span.Kind() == ptrace.SpanKindClient || span.Kind() == ptrace.SpanKindProducer {
+			seg.Namespace = "remote"
+			if v := stringAttr(attrs, semconv.AttributePeerService, semconv.AttributeServerAddress); v != "" {
+				name = v
+			}
+		}
+	}
+	seg.Name = segmentName(name)
+
+	if v := stringAttr(resAttrs, semconv.AttributeServiceVersion); v != "" {
+		seg.Service = &serviceData{Version: v}
+	}
+
+	seg.HTTP = httpFor(attrs)
+	t.setStatus(seg, span)
+	t.setAttributes(seg, attrs)
+	return seg
+}
+
+// setStatus sets the error, throttle and fault flags of a segment from the
+// status of the span and its HTTP status code, and the exceptions recorded
+// as events.
+func (t *translator) setStatus(seg *segment, span ptrace.Span) {
+	var status int64
+	if seg.HTTP != nil && seg.HTTP.Response != nil {
+		status = seg.HTTP.Response.Status
+	}
+
+	switch {
+	case status == 429:
+		seg.Error, seg.Throttle = true, true
+	case status >= 400 && status < 500:
+		seg.Error = true
+	case status >= 500:
+		seg.Fault = true
+	case span.Status().Code() == ptrace.StatusCodeError:
+		seg.Fault = true
+	}
+
+	events := span.Events()
+	for i := 0; i < events.Len(); i++ {
+		e := events.At(i)
+		if e.Name() != "exception" {
+			continue
+		}
+		if seg.Cause == nil {
+			seg.Cause = &cause{}
+		}
+		seg.Cause.Exceptions = append(seg.Cause.Exceptions, exception{
+			ID:      fmt.Sprintf("%016x", len(seg.Cause.Exceptions)+1),
+			Type:    stringAttr(e.Attributes(), semconv.AttributeExceptionType),
+			Message: stringAttr(e.Attributes(), semconv.AttributeExceptionMessage),
+		})
+	}
+	if seg.Cause == nil && span.Status().Code() == ptrace.StatusCodeError && span.Status().Message() != "" {
+		seg.Cause = &cause{Exceptions: []exception{{
+			ID:      fmt.Sprintf("%016x", 1),
+			Message: span.Status().Message(),
+		}}}
+	}
+}
+
+// setAttributes converts the indexed span attributes into annotations and the
+// other ones into metadata.
+func (t *translator) setAttributes(seg *segment, attrs pcommon.Map) {
+	attrs.Range(func(k string, v pcommon.Value) bool {
+		_, indexed := t.indexedAttributes[k]
+		if (indexed || t.indexAllAttributes) && isAnnotationValue(v) {
+			if seg.Annotations == nil {
+				seg.Annotations = make(map[string]any)
+			}
+			seg.Annotations[annotationKey(k)] = v.AsRaw()
+			return true
+		}
+
+		if seg.Metadata == nil {
+			seg.Metadata = map[string]map[string]any{"default": {}}
+		}
+		seg.Metadata["default"][k] = v.AsRaw()
+		return true
+	})
+}
+
+// httpFor returns the HTTP data of a span, or nil if it isn't an HTTP span.
+func httpFor(attrs pcommon.Map) *httpData {
+	req := requestData{
+		Method:    stringAttr(attrs, semconv.AttributeHTTPRequestMethod, "http.method"),
+		URL:       stringAttr(attrs, semconv.AttributeURLFull, "http.url"),
+		UserAgent: stringAttr(attrs, semconv.AttributeUserAgentOriginal, "http.user_agent"),
+		ClientIP:  stringAttr(attrs, semconv.AttributeClientAddress, "http.client_ip"),
+	}
+	var status int64
+	for _, k := range []string{semconv.AttributeHTTPResponseStatusCode, "http.status_code"} {
+		if v, ok := attrs.Get(k); ok {
+			status = v.Int()
+			break
+		}
+	}
+
+	if req == (requestData{}) && status == 0 {
+		return nil
+	}
+	res := &httpData{}
+	if req != (requestData{}) {
+		res.Request = &req
+	}
+	if status != 0 {
+		res.Response = &responseData{Status: status}
+	}
+	return res
+}
+
+// traceID converts a trace ID into the X-Ray format, where the first 4 bytes
+// are the epoch part of the ID.
+func traceID(id pcommon.TraceID) string {
+	return "1-" + hex.EncodeToString(id[:4]) + "-" + hex.EncodeToString(id[4:])
+}
+
+func hexID(id pcommon.SpanID) string {
+	return hex.EncodeToString(id[:])
+}
+
+// timestamp converts a timestamp into seconds since the epoch.
+func timestamp(ts pcommon.Timestamp) float64 {
+	return float64(ts) / 1e9
+}
+
+// stringAttr returns the value of the first of keys which is set in attrs.
+func stringAttr(attrs pcommon.Map, keys ...string) string {
+	for _, k := range keys {
+		if v, ok := attrs.Get(k); ok && v.AsString() != "" {
+			return v.AsString()
+		}
+	}
+	return ""
+}
+
+// segmentName removes the characters which aren't allowed in the name of a
+// segment and truncates it.
+func segmentName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) || unicode.IsSpace(r) || strings.ContainsRune("_.:/%&#=+\\-@", r) {
+			return r
+		}
+		return -1
+	}, name)
+	if runes := []rune(name); len(runes) > maxSegmentNameLength {
+		name = string(runes[:maxSegmentNameLength])
+	}
+	if name == "" {
+		return "span"
+	}
+	return name
+}
+
+// annotationKey replaces the characters which aren't allowed in the key of
+// an annotation with underscores.
+func annotationKey(k string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, k)
+}
+
+// isAnnotationValue returns whether v can be the value of an annotation.
+func isAnnotationValue(v pcommon.Value) bool {
+	switch v.Type() {
+	case pcommon.ValueTypeStr, pcommon.ValueTypeBool, pcommon.ValueTypeInt, pcommon.ValueTypeDouble:
+		return true
+	default:
+		return false
+	}
+}
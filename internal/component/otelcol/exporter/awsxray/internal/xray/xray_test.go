@@ -0,0 +1,223 @@
+package xray
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumererror"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.uber.org/zap"
+)
+
+var (
+	testTraceID = pcommon.TraceID{0x5f, 0x84, 0xc7, 0xa1, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+	testSpanID  = pcommon.SpanID{1, 2, 3, 4, 5, 6, 7, 8}
+	testParent  = pcommon.SpanID{8, 7, 6, 5, 4, 3, 2, 1}
+)
+
+func TestSegment(t *testing.T) {
+	res := pcommon.NewResource()
+	res.Attributes().PutStr("service.name", "checkout")
+	res.Attributes().PutStr("service.version", "1.2.3")
+
+	t.Run("server span", func(t *testing.T) {
+		span := ptrace.NewSpan()
+		span.SetTraceID(testTraceID)
+		span.SetSpanID(testSpanID)
+		span.SetParentSpanID(testParent)
+		span.SetKind(ptrace.SpanKindServer)
+		span.SetName("GET /cart")
+		span.SetStartTimestamp(pcommon.Timestamp(1_500_000_000))
+		span.SetEndTimestamp(pcommon.Timestamp(2_750_000_000))
+		span.Attributes().PutStr("http.request.method", "GET")
+		span.Attributes().PutStr("url.full", "https://shop.example.com/cart")
+		span.Attributes().PutInt("http.response.status_code", 429)
+		span.Attributes().PutStr("user.id", "42")
+		span.Attributes().PutStr("cart.id", "abc")
+
+		cfg := &Config{IndexedAttributes: []string{"user.id"}}
+		seg := newTranslator(cfg).segment(span, res)
+		require.Equal(t, &segment{
+			Name:      "checkout",
+			ID:        "0102030405060708",
+			TraceID:   "1-5f84c7a1-0102030405060708090a0b0c",
+			ParentID:  "0807060504030201",
+			StartTime: 1.5,
+			EndTime:   2.75,
+			Error:     true,
+			Throttle:  true,
+			HTTP: &httpData{
+				Request:  &requestData{Method: "GET", URL: "https://shop.example.com/cart"},
+				Response: &responseData{Status: 429},
+			},
+			Service:     &serviceData{Version: "1.2.3"},
+			Annotations: map[string]any{"user_id": "42"},
+			Metadata: map[string]map[string]any{"default": {
+				"http.request.method":       "GET",
+				"url.full":                  "https://shop.example.com/cart",
+				"http.response.status_code": int64(429),
+				"cart.id":                   "abc",
+			}},
+		}, seg)
+	})
+
+	t.Run("client span", func(t *testing.T) {
+		span := ptrace.NewSpan()
+		span.SetTraceID(testTraceID)
+		span.SetSpanID(testSpanID)
+		span.SetParentSpanID(testParent)
+		span.SetKind(ptrace.SpanKindClient)
+		span.SetName("SELECT")
+		span.Attributes().PutStr("peer.service", "payments (db)")
+		span.Status().SetCode(ptrace.StatusCodeError)
+		e := span.Events().AppendEmpty()
+		e.SetName("exception")
+		e.Attributes().PutStr("exception.type", "TimeoutError")
+		e.Attributes().PutStr("exception.message", "timed out")
+
+		seg := newTranslator(&Config{IndexAllAttributes: true}).segment(span, res)
+		require.Equal(t, "payments db", seg.Name)
+		require.Equal(t, "subsegment", seg.Type)
+		require.Equal(t, "remote", seg.Namespace)
+		require.True(t, seg.Fault)
+		require.False(t, seg.Error)
+		require.Nil(t, seg.HTTP)
+		require.Equal(t, &cause{Exceptions: []exception{{ID: "0000000000000001", Type: "TimeoutError", Message: "timed out"}}}, seg.Cause)
+		require.Equal(t, map[string]any{"peer_service": "payments (db)"}, seg.Annotations)
+		require.Nil(t, seg.Metadata)
+	})
+
+	t.Run("root span", func(t *testing.T) {
+		span := ptrace.NewSpan()
+		span.SetKind(ptrace.SpanKindInternal)
+		span.SetName("job")
+
+		seg := newTranslator(&Config{}).segment(span, pcommon.NewResource())
+		require.Equal(t, "job", seg.Name)
+		require.Empty(t, seg.Type)
+		require.Empty(t, seg.ParentID)
+	})
+}
+
+func TestSegmentName(t *testing.T) {
+	require.Equal(t, "span", segmentName("()"))
+	require.Equal(t, "api.example.com:443/v1", segmentName("api.example.com:443/v1"))
+	require.Len(t, segmentName(strings.Repeat("a", 300)), maxSegmentNameLength)
+}
+
+// fakeXRay records the segment documents sent to it, and fails the first
+// requests with the given status codes.
+type fakeXRay struct {
+	mut       sync.Mutex
+	failures  []int
+	requests  int
+	documents []string
+}
+
+func (f *fakeXRay) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+	f.requests++
+
+	if r.URL.Path != "/TraceSegments" || !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256") ||
+		!strings.Contains(r.Header.Get("Authorization"), "/us-east-1/xray/aws4_request") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	if len(f.failures) > 0 {
+		status := f.failures[0]
+		f.failures = f.failures[1:]
+		w.Header().Set("X-Amzn-ErrorType", "SomeException:http://internal.amazon.com/")
+		w.WriteHeader(status)
+		_, _ = io.WriteString(w, `{"message": "failed"}`)
+		return
+	}
+
+	var in putTraceSegmentsInput
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	f.documents = append(f.documents, in.TraceSegmentDocuments...)
+	_, _ = io.WriteString(w, `{"UnprocessedTraceSegments": []}`)
+}
+
+func startExporter(t *testing.T, f *fakeXRay) *xrayExporter {
+	t.Helper()
+
+	dir := t.TempDir()
+	t.Setenv("AWS_CONFIG_FILE", filepath.Join(dir, "config"))
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+
+	cfg := createDefaultConfig().(*Config)
+	cfg.Region = "us-east-1"
+	cfg.Endpoint = srv.URL
+	cfg.MaxRetries = 1
+	cfg.RequestTimeout = 5 * time.Second
+
+	exp := newExporter(cfg, zap.NewNop())
+	require.NoError(t, exp.start(context.Background(), nil))
+	return exp
+}
+
+func newTraces(spans int) ptrace.Traces {
+	td := ptrace.NewTraces()
+	rs := td.ResourceSpans().AppendEmpty()
+	rs.Resource().Attributes().PutStr("service.name", "checkout")
+	ss := rs.ScopeSpans().AppendEmpty()
+	for i := 0; i < spans; i++ {
+		span := ss.Spans().AppendEmpty()
+		span.SetTraceID(testTraceID)
+		span.SetSpanID(pcommon.SpanID{0, 0, 0, 0, 0, 0, 0, byte(i + 1)})
+		span.SetName("span")
+	}
+	return td
+}
+
+func TestExporter(t *testing.T) {
+	f := &fakeXRay{}
+	exp := startExporter(t, f)
+
+	require.NoError(t, exp.pushTraces(context.Background(), newTraces(120)))
+	require.Equal(t, 3, f.requests)
+	require.Len(t, f.documents, 120)
+
+	var seg segment
+	require.NoError(t, json.Unmarshal([]byte(f.documents[0]), &seg))
+	require.Equal(t, "checkout", seg.Name)
+	require.Equal(t, "1-5f84c7a1-0102030405060708090a0b0c", seg.TraceID)
+}
+
+func TestExporter_Retry(t *testing.T) {
+	f := &fakeXRay{failures: []int{http.StatusServiceUnavailable}}
+	exp := startExporter(t, f)
+
+	require.NoError(t, exp.pushTraces(context.Background(), newTraces(1)))
+	require.Equal(t, 2, f.requests)
+	require.Len(t, f.documents, 1)
+}
+
+func TestExporter_PermanentError(t *testing.T) {
+	f := &fakeXRay{failures: []int{http.StatusBadRequest}}
+	exp := startExporter(t, f)
+
+	err := exp.pushTraces(context.Background(), newTraces(1))
+	require.ErrorContains(t, err, "status 400: SomeException: failed")
+	require.True(t, consumererror.IsPermanent(err))
+	require.Equal(t, 1, f.requests)
+}
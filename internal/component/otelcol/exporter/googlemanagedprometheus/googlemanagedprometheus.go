@@ -0,0 +1,141 @@
+// Package googlemanagedprometheus provides an otelcol.exporter.googlemanagedprometheus component
+package googlemanagedprometheus
+
+import (
+	"github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/collector"
+	otelcomponent "go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/pipeline"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
+	"github.com/grafana/alloy/internal/component/otelcol/exporter"
+	googlecloudconfig "github.com/grafana/alloy/internal/component/otelcol/exporter/googlecloud/config"
+	"github.com/grafana/alloy/internal/component/otelcol/exporter/googlemanagedprometheus/internal/gmp"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.exporter.googlemanagedprometheus",
+		Community: true,
+		Args:      Arguments{},
+		Exports:   otelcol.ConsumerExports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			fact := gmp.NewFactory()
+			return exporter.New(opts, fact, args.(Arguments), exporter.TypeSignalConstFunc(exporter.TypeMetrics))
+		},
+	})
+}
+
+// Arguments configures the otelcol.exporter.googlemanagedprometheus component.
+type Arguments struct {
+	Queue otelcol.QueueArguments `alloy:"sending_queue,block,optional"`
+
+	Project   string          `alloy:"project,attr,optional"`
+	UserAgent string          `alloy:"user_agent,attr,optional"`
+	Metric    MetricArguments `alloy:"metric,block,optional"`
+
+	// DebugMetrics configures component internal metrics. Optional
+	DebugMetrics otelcolCfg.DebugMetricsArguments `alloy:"debug_metrics,block,optional"`
+}
+
+var _ exporter.Arguments = Arguments{}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	args.Queue.SetToDefault()
+
+	args.UserAgent = "opentelemetry-collector-contrib {{version}}"
+	args.Metric.SetToDefault()
+
+	args.DebugMetrics.SetToDefault()
+}
+
+// Convert implements exporter.Arguments.
+func (args Arguments) Convert() (otelcomponent.Config, error) {
+	result := gmp.NewFactory().CreateDefaultConfig().(*gmp.Config)
+
+	q, err := args.Queue.Convert()
+	if err != nil {
+		return nil, err
+	}
+	result.QueueSettings = *q
+
+	result.ProjectID = args.Project
+	result.UserAgent = args.UserAgent
+	result.MetricConfig = args.Metric.Convert()
+
+	return result, nil
+}
+
+// Extensions implements exporter.Arguments.
+func (args Arguments) Extensions() map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// Exporters implements exporter.Arguments.
+func (args Arguments) Exporters() map[pipeline.Signal]map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// DebugMetricsConfig implements exporter.Arguments.
+func (args Arguments) DebugMetricsConfig() otelcolCfg.DebugMetricsArguments {
+	return args.DebugMetrics
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	otelCfg, err := args.Convert()
+	if err != nil {
+		return err
+	}
+	return otelCfg.(*gmp.Config).Validate()
+}
+
+// MetricArguments configures how metrics are written to Google Cloud Managed
+// Service for Prometheus.
+type MetricArguments struct {
+	Prefix                  string                             `alloy:"prefix,attr,optional"`
+	Endpoint                string                             `alloy:"endpoint,attr,optional"`
+	Compression             string                             `alloy:"compression,attr,optional"`
+	GRPCPoolSize            int                                `alloy:"grpc_pool_size,attr,optional"`
+	UseInsecure             bool                               `alloy:"use_insecure,attr,optional"`
+	ResourceFilters         []googlecloudconfig.ResourceFilter `alloy:"resource_filters,attr,optional"`
+	CumulativeNormalization bool                               `alloy:"cumulative_normalization,attr,optional"`
+	AddMetricSuffixes       bool                               `alloy:"add_metric_suffixes,attr,optional"`
+	EnableTargetInfo        bool                               `alloy:"enable_target_info,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *MetricArguments) SetToDefault() {
+	*args = MetricArguments{
+		Prefix:                  "prometheus.googleapis.com",
+		Endpoint:                "monitoring.googleapis.com:443",
+		CumulativeNormalization: true,
+		AddMetricSuffixes:       true,
+		EnableTargetInfo:        true,
+	}
+}
+
+// Convert converts args into the metric configuration of the exporter.
+func (args *MetricArguments) Convert() gmp.MetricConfig {
+	var filters []collector.ResourceFilter
+	for _, f := range args.ResourceFilters {
+		filters = append(filters, collector.ResourceFilter{Prefix: f.Prefix, Regex: f.Regex})
+	}
+
+	return gmp.MetricConfig{
+		Prefix: args.Prefix,
+		ClientConfig: collector.ClientConfig{
+			Endpoint:     args.Endpoint,
+			Compression:  args.Compression,
+			UseInsecure:  args.UseInsecure,
+			GRPCPoolSize: args.GRPCPoolSize,
+		},
+		ResourceFilters:         filters,
+		CumulativeNormalization: args.CumulativeNormalization,
+		AddMetricSuffixes:       args.AddMetricSuffixes,
+		EnableTargetInfo:        args.EnableTargetInfo,
+	}
+}
@@ -0,0 +1,153 @@
+package googlemanagedprometheus_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/collector"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+
+	googlecloudconfig "github.com/grafana/alloy/internal/component/otelcol/exporter/googlecloud/config"
+	"github.com/grafana/alloy/internal/component/otelcol/exporter/googlemanagedprometheus"
+	"github.com/grafana/alloy/internal/component/otelcol/exporter/googlemanagedprometheus/internal/gmp"
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestConfigConversion(t *testing.T) {
+	tests := []struct {
+		testName string
+		agentCfg string
+		expected gmp.Config
+	}{
+		{
+			testName: "default",
+			agentCfg: `
+			`,
+			expected: gmp.Config{
+				UserAgent: "opentelemetry-collector-contrib {{version}}",
+				MetricConfig: gmp.MetricConfig{
+					Prefix: "prometheus.googleapis.com",
+					ClientConfig: collector.ClientConfig{
+						Endpoint: "monitoring.googleapis.com:443",
+					},
+					CumulativeNormalization: true,
+					AddMetricSuffixes:       true,
+					EnableTargetInfo:        true,
+				},
+				TimeoutSettings: exporterhelper.TimeoutConfig{
+					Timeout: 12 * time.Second,
+				},
+				QueueSettings: exporterhelper.QueueConfig{
+					Enabled:      true,
+					NumConsumers: 10,
+					QueueSize:    1000,
+				},
+			},
+		},
+		{
+			testName: "customized",
+			agentCfg: `
+				project = "foo-bar"
+				user_agent = "custom-user-agent"
+
+				metric {
+					prefix = "custom.googleapis.com"
+					endpoint = "localhost:8080"
+					compression = "gzip"
+					grpc_pool_size = 2
+					use_insecure = true
+					resource_filters = [
+						{
+							prefix = "cloud.",
+						},
+						{
+							regex = "k8s\\..*",
+						},
+					]
+					cumulative_normalization = false
+					add_metric_suffixes = false
+					enable_target_info = false
+				}
+
+				sending_queue {
+					enabled = false
+					num_consumers = 5
+					queue_size = 2000
+				}
+			`,
+			expected: gmp.Config{
+				ProjectID: "foo-bar",
+				UserAgent: "custom-user-agent",
+				MetricConfig: gmp.MetricConfig{
+					Prefix: "custom.googleapis.com",
+					ClientConfig: collector.ClientConfig{
+						Endpoint:     "localhost:8080",
+						Compression:  "gzip",
+						UseInsecure:  true,
+						GRPCPoolSize: 2,
+					},
+					ResourceFilters: []collector.ResourceFilter{
+						{Prefix: "cloud."},
+						{Regex: "k8s\\..*"},
+					},
+				},
+				TimeoutSettings: exporterhelper.TimeoutConfig{
+					Timeout: 12 * time.Second,
+				},
+				QueueSettings: exporterhelper.QueueConfig{
+					Enabled:      false,
+					NumConsumers: 5,
+					QueueSize:    2000,
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var args googlemanagedprometheus.Arguments
+			require.NoError(t, syntax.Unmarshal([]byte(tc.agentCfg), &args))
+			actual, err := args.Convert()
+			require.NoError(t, err)
+			require.Equal(t, &tc.expected, actual.(*gmp.Config))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, tt := range []struct {
+		name      string
+		cfg       *googlemanagedprometheus.Arguments
+		expectErr bool
+	}{
+		{
+			name: "invalid config",
+			cfg: &googlemanagedprometheus.Arguments{
+				Metric: googlemanagedprometheus.MetricArguments{
+					ResourceFilters: []googlecloudconfig.ResourceFilter{
+						{
+							Regex: "invalid regex(",
+						},
+					},
+				},
+			},
+			expectErr: true,
+		},
+		{
+			name:      "valid config",
+			cfg:       &googlemanagedprometheus.Arguments{},
+			expectErr: false,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.cfg.Validate()
+			if tt.expectErr {
+				require.Error(t, err)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+// Package gmp provides an OpenTelemetry Collector exporter which writes
+// metrics to Google Cloud Managed Service for Prometheus. It's built on the
+// Google Cloud metrics exporter, with the metric names and monitored resources
+// used by Managed Service for Prometheus.
+package gmp
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/collector"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// Config defines the configuration of the exporter.
+type Config struct {
+	ProjectID string `mapstructure:"project"`
+	UserAgent string `mapstructure:"user_agent"`
+
+	MetricConfig MetricConfig `mapstructure:"metric"`
+
+	TimeoutSettings exporterhelper.TimeoutConfig `mapstructure:",squash"`
+	QueueSettings   exporterhelper.QueueConfig   `mapstructure:"sending_queue"`
+}
+
+// MetricConfig defines how metrics are written.
+type MetricConfig struct {
+	// Prefix is prepended to the name of every metric.
+	Prefix       string                 `mapstructure:"prefix"`
+	ClientConfig collector.ClientConfig `mapstructure:",squash"`
+	// ResourceFilters selects the resource attributes added as metric labels.
+	ResourceFilters []collector.ResourceFilter `mapstructure:"resource_filters"`
+	// CumulativeNormalization normalizes cumulative metrics without start
+	// times or with explicit reset points.
+	CumulativeNormalization bool `mapstructure:"cumulative_normalization"`
+	// AddMetricSuffixes adds the unit and type suffixes to the metric names,
+	// like the Prometheus exporters do.
+	AddMetricSuffixes bool `mapstructure:"add_metric_suffixes"`
+	// EnableTargetInfo adds a target_info metric for each resource.
+	EnableTargetInfo bool `mapstructure:"enable_target_info"`
+}
+
+// createDefaultConfig returns the default configuration of the exporter.
+func createDefaultConfig() *Config {
+	return &Config{
+		UserAgent: "opentelemetry-collector-contrib {{version}}",
+		MetricConfig: MetricConfig{
+			Prefix: "prometheus.googleapis.com",
+			ClientConfig: collector.ClientConfig{
+				Endpoint: "monitoring.googleapis.com:443",
+			},
+			CumulativeNormalization: true,
+			AddMetricSuffixes:       true,
+			EnableTargetInfo:        true,
+		},
+		TimeoutSettings: exporterhelper.TimeoutConfig{Timeout: 12 * time.Second},
+		QueueSettings:   exporterhelper.NewDefaultQueueConfig(),
+	}
+}
+
+// Validate returns an error if the configuration is invalid.
+func (cfg *Config) Validate() error {
+	for _, f := range cfg.MetricConfig.ResourceFilters {
+		if f.Regex == "" {
+			continue
+		}
+		if _, err := regexp.Compile(f.Regex); err != nil {
+			return fmt.Errorf("unable to parse resource filter regex: %w", err)
+		}
+	}
+	return nil
+}
+
+// toCollectorConfig returns the configuration of the Google Cloud metrics
+// exporter which writes the metrics.
+func (cfg *Config) toCollectorConfig() collector.Config {
+	res := collector.DefaultConfig()
+	res.ProjectID = cfg.ProjectID
+	res.UserAgent = cfg.UserAgent
+
+	mc := &res.MetricConfig
+	mc.Prefix = cfg.MetricConfig.Prefix
+	mc.KnownDomains = []string{cfg.MetricConfig.Prefix}
+	mc.ClientConfig = cfg.MetricConfig.ClientConfig
+	mc.ResourceFilters = cfg.MetricConfig.ResourceFilters
+	mc.CumulativeNormalization = cfg.MetricConfig.CumulativeNormalization
+
+	// Managed Service for Prometheus creates the metric descriptors itself and
+	// identifies series by the prometheus_target resource and the scope
+	// labels instead of the labels added by the Google Cloud exporter.
+	mc.SkipCreateMetricDescriptor = true
+	mc.InstrumentationLibraryLabels = false
+	mc.ServiceResourceLabels = false
+	mc.EnableSumOfSquaredDeviation = true
+
+	mc.GetMetricName = metricNamer{addSuffixes: cfg.MetricConfig.AddMetricSuffixes}.GetMetricName
+	mc.MapMonitoredResource = MapToPrometheusTarget
+	mc.ExtraMetrics = func(m pmetric.Metrics) {
+		if cfg.MetricConfig.EnableTargetInfo {
+			addTargetInfo(m)
+		}
+		addScopeLabels(m)
+	}
+	return res
+}
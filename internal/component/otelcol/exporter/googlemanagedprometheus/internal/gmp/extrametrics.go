@@ -0,0 +1,123 @@
+package gmp
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+const (
+	targetInfoName    = "target_info"
+	scopeNameLabel    = "otel_scope_name"
+	scopeVersionLabel = "otel_scope_version"
+)
+
+// addTargetInfo adds a target_info gauge to each resource with metrics, which
+// has the resource attributes which aren't part of the prometheus_target
+// monitored resource as labels.
+func addTargetInfo(m pmetric.Metrics) {
+	rms := m.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+
+		ts, ok := latestTimestamp(rm)
+		if !ok {
+			continue
+		}
+
+		attrs := pcommon.NewMap()
+		rm.Resource().Attributes().Range(func(k string, v pcommon.Value) bool {
+			if !isTargetAttribute(k) {
+				v.CopyTo(attrs.PutEmpty(k))
+			}
+			return true
+		})
+		if attrs.Len() == 0 {
+			continue
+		}
+
+		metric := rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty()
+		metric.SetName(targetInfoName)
+		dp := metric.SetEmptyGauge().DataPoints().AppendEmpty()
+		dp.SetIntValue(1)
+		dp.SetTimestamp(ts)
+		attrs.MoveTo(dp.Attributes())
+	}
+}
+
+// latestTimestamp returns the timestamp of the latest data point of a
+// resource, and whether it has any data point.
+func latestTimestamp(rm pmetric.ResourceMetrics) (pcommon.Timestamp, bool) {
+	var (
+		latest pcommon.Timestamp
+		found  bool
+	)
+	forEachPoint(rm, func(_ pcommon.InstrumentationScope, _ pcommon.Map, ts pcommon.Timestamp) {
+		if !found || ts > latest {
+			latest = ts
+		}
+		found = true
+	})
+	if found && latest == 0 {
+		latest = pcommon.NewTimestampFromTime(time.Now())
+	}
+	return latest, found
+}
+
+// addScopeLabels adds the name and version of the instrumentation scope to
+// each data point, as described by the OpenTelemetry specification for
+// Prometheus compatibility.
+func addScopeLabels(m pmetric.Metrics) {
+	rms := m.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		forEachPoint(rms.At(i), func(scope pcommon.InstrumentationScope, attrs pcommon.Map, _ pcommon.Timestamp) {
+			if scope.Name() != "" {
+				attrs.PutStr(scopeNameLabel, scope.Name())
+			}
+			if scope.Version() != "" {
+				attrs.PutStr(scopeVersionLabel, scope.Version())
+			}
+		})
+	}
+}
+
+// forEachPoint calls fn with the scope, attributes and timestamp of each data
+// point of a resource.
+func forEachPoint(rm pmetric.ResourceMetrics, fn func(scope pcommon.InstrumentationScope, attrs pcommon.Map, ts pcommon.Timestamp)) {
+	sms := rm.ScopeMetrics()
+	for i := 0; i < sms.Len(); i++ {
+		scope := sms.At(i).Scope()
+		metrics := sms.At(i).Metrics()
+		for j := 0; j < metrics.Len(); j++ {
+			metric := metrics.At(j)
+			switch metric.Type() {
+			case pmetric.MetricTypeGauge:
+				dps := metric.Gauge().DataPoints()
+				for k := 0; k < dps.Len(); k++ {
+					fn(scope, dps.At(k).Attributes(), dps.At(k).Timestamp())
+				}
+			case pmetric.MetricTypeSum:
+				dps := metric.Sum().DataPoints()
+				for k := 0; k < dps.Len(); k++ {
+					fn(scope, dps.At(k).Attributes(), dps.At(k).Timestamp())
+				}
+			case pmetric.MetricTypeHistogram:
+				dps := metric.Histogram().DataPoints()
+				for k := 0; k < dps.Len(); k++ {
+					fn(scope, dps.At(k).Attributes(), dps.At(k).Timestamp())
+				}
+			case pmetric.MetricTypeExponentialHistogram:
+				dps := metric.ExponentialHistogram().DataPoints()
+				for k := 0; k < dps.Len(); k++ {
+					fn(scope, dps.At(k).Attributes(), dps.At(k).Timestamp())
+				}
+			case pmetric.MetricTypeSummary:
+				dps := metric.Summary().DataPoints()
+				for k := 0; k < dps.Len(); k++ {
+					fn(scope, dps.At(k).Attributes(), dps.At(k).Timestamp())
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,48 @@
+package gmp
+
+import (
+	"context"
+
+	"github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/collector"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/exporter"
+	"go.opentelemetry.io/collector/exporter/exporterhelper"
+)
+
+// TypeStr is the unique identifier for the exporter.
+const TypeStr = "googlemanagedprometheus"
+
+// NewFactory returns a new factory for the exporter.
+func NewFactory() exporter.Factory {
+	return exporter.NewFactory(
+		component.MustNewType(TypeStr),
+		func() component.Config { return createDefaultConfig() },
+		exporter.WithMetrics(createMetricsExporter, component.StabilityLevelBeta),
+	)
+}
+
+func createMetricsExporter(
+	ctx context.Context,
+	params exporter.Settings,
+	cfg component.Config,
+) (exporter.Metrics, error) {
+
+	eCfg := cfg.(*Config)
+	mExp, err := collector.NewGoogleCloudMetricsExporter(ctx, eCfg.toCollectorConfig(), params, eCfg.TimeoutSettings.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	return exporterhelper.NewMetrics(
+		ctx,
+		params,
+		cfg,
+		mExp.PushMetrics,
+		exporterhelper.WithStart(mExp.Start),
+		exporterhelper.WithShutdown(mExp.Shutdown),
+		// The Google Cloud exporter applies the timeout to its own requests.
+		exporterhelper.WithTimeout(exporterhelper.TimeoutConfig{Timeout: 0}),
+		exporterhelper.WithQueue(eCfg.QueueSettings),
+		exporterhelper.WithCapabilities(consumer.Capabilities{MutatesData: true}),
+	)
+}
@@ -0,0 +1,183 @@
+package gmp
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestGetMetricName(t *testing.T) {
+	newMetric := func(name, unit string, setType func(pmetric.Metric)) pmetric.Metric {
+		m := pmetric.NewMetric()
+		m.SetName(name)
+		m.SetUnit(unit)
+		setType(m)
+		return m
+	}
+	gauge := func(m pmetric.Metric) { m.SetEmptyGauge() }
+	counter := func(m pmetric.Metric) { m.SetEmptySum().SetIsMonotonic(true) }
+	upDownCounter := func(m pmetric.Metric) { m.SetEmptySum() }
+	histogram := func(m pmetric.Metric) { m.SetEmptyHistogram() }
+	expHistogram := func(m pmetric.Metric) { m.SetEmptyExponentialHistogram() }
+	summary := func(m pmetric.Metric) { m.SetEmptySummary() }
+
+	tests := []struct {
+		name     string
+		baseName string
+		metric   pmetric.Metric
+		suffixes bool
+		expected string
+	}{
+		{"gauge", "mem.usage", newMetric("mem.usage", "By", gauge), true, "mem_usage_bytes/gauge"},
+		{"gauge without suffixes", "mem.usage", newMetric("mem.usage", "By", gauge), false, "mem_usage/gauge"},
+		{"counter", "http.requests", newMetric("http.requests", "", counter), true, "http_requests_total/counter"},
+		{"up-down counter", "queue.size", newMetric("queue.size", "", upDownCounter), true, "queue_size/gauge"},
+		{"histogram", "latency", newMetric("latency", "s", histogram), true, "latency_seconds/histogram"},
+		{"exponential histogram", "latency", newMetric("latency", "s", expHistogram), true, "latency_seconds/histogram"},
+		{"summary quantiles", "rpc", newMetric("rpc", "", summary), true, "rpc/summary"},
+		{"summary sum", "rpc_sum", newMetric("rpc", "", summary), true, "rpc_sum/summary:counter"},
+		{"summary count", "rpc_count", newMetric("rpc", "", summary), true, "rpc_count/summary"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			name, err := metricNamer{addSuffixes: tc.suffixes}.GetMetricName(tc.baseName, tc.metric)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, name)
+		})
+	}
+
+	_, err := metricNamer{}.GetMetricName("empty", pmetric.NewMetric())
+	require.Error(t, err)
+}
+
+func TestMapToPrometheusTarget(t *testing.T) {
+	tests := []struct {
+		name     string
+		attrs    map[string]any
+		expected map[string]string
+	}{
+		{
+			name: "semantic conventions",
+			attrs: map[string]any{
+				"cloud.region":            "us-central1",
+				"cloud.availability_zone": "us-central1-c",
+				"k8s.cluster.name":        "prod",
+				"k8s.namespace.name":      "default",
+				"service.namespace":       "shop",
+				"service.name":            "checkout",
+				"service.instance.id":     "checkout-0",
+			},
+			expected: map[string]string{
+				"location":  "us-central1-c",
+				"cluster":   "prod",
+				"namespace": "default",
+				"job":       "shop/checkout",
+				"instance":  "checkout-0",
+			},
+		},
+		{
+			name: "prometheus target labels",
+			attrs: map[string]any{
+				"location":         "europe-west1",
+				"cluster":          "dev",
+				"namespace":        "monitoring",
+				"job":              "alloy",
+				"instance":         "alloy-0:12345",
+				"k8s.cluster.name": "ignored",
+				"service.name":     "ignored",
+			},
+			expected: map[string]string{
+				"location":  "europe-west1",
+				"cluster":   "dev",
+				"namespace": "monitoring",
+				"job":       "alloy",
+				"instance":  "alloy-0:12345",
+			},
+		},
+		{
+			name:  "empty",
+			attrs: map[string]any{},
+			expected: map[string]string{
+				"location":  "",
+				"cluster":   "",
+				"namespace": "",
+				"job":       "",
+				"instance":  "",
+			},
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			res := pcommon.NewResource()
+			require.NoError(t, res.Attributes().FromRaw(tc.attrs))
+
+			mr := MapToPrometheusTarget(res)
+			require.Equal(t, "prometheus_target", mr.Type)
+			require.Equal(t, tc.expected, mr.Labels)
+		})
+	}
+}
+
+func TestExtraMetrics(t *testing.T) {
+	m := pmetric.NewMetrics()
+	rm := m.ResourceMetrics().AppendEmpty()
+	require.NoError(t, rm.Resource().Attributes().FromRaw(map[string]any{
+		"service.name":        "checkout",
+		"service.instance.id": "checkout-0",
+		"cloud.region":        "us-central1",
+		"host.name":           "node-1",
+	}))
+	sm := rm.ScopeMetrics().AppendEmpty()
+	sm.Scope().SetName("io.opentelemetry.http")
+	sm.Scope().SetVersion("1.2.3")
+	gauge := sm.Metrics().AppendEmpty()
+	gauge.SetName("up")
+	gauge.SetEmptyGauge().DataPoints().AppendEmpty().SetTimestamp(10)
+	counter := sm.Metrics().AppendEmpty()
+	counter.SetName("requests")
+	counter.SetEmptySum().DataPoints().AppendEmpty().SetTimestamp(20)
+
+	// A resource without data points doesn't get a target_info metric.
+	m.ResourceMetrics().AppendEmpty().Resource().Attributes().PutStr("host.name", "node-2")
+
+	cfg := createDefaultConfig()
+	cfg.toCollectorConfig().MetricConfig.ExtraMetrics(m)
+
+	require.Equal(t, 2, rm.ScopeMetrics().Len())
+	for _, dp := range []pmetric.NumberDataPoint{
+		gauge.Gauge().DataPoints().At(0),
+		counter.Sum().DataPoints().At(0),
+	} {
+		require.Equal(t, map[string]any{
+			"otel_scope_name":    "io.opentelemetry.http",
+			"otel_scope_version": "1.2.3",
+		}, dp.Attributes().AsRaw())
+	}
+
+	targetInfo := rm.ScopeMetrics().At(1).Metrics().At(0)
+	require.Equal(t, "target_info", targetInfo.Name())
+	dp := targetInfo.Gauge().DataPoints().At(0)
+	require.Equal(t, int64(1), dp.IntValue())
+	require.Equal(t, pcommon.Timestamp(20), dp.Timestamp())
+	require.Equal(t, map[string]any{
+		"cloud.region": "us-central1",
+		"host.name":    "node-1",
+	}, dp.Attributes().AsRaw())
+
+	require.Equal(t, 0, m.ResourceMetrics().At(1).ScopeMetrics().Len())
+}
+
+func TestExtraMetrics_TargetInfoDisabled(t *testing.T) {
+	m := pmetric.NewMetrics()
+	rm := m.ResourceMetrics().AppendEmpty()
+	rm.Resource().Attributes().PutStr("host.name", "node-1")
+	rm.ScopeMetrics().AppendEmpty().Metrics().AppendEmpty().SetEmptyGauge().DataPoints().AppendEmpty()
+
+	cfg := createDefaultConfig()
+	cfg.MetricConfig.EnableTargetInfo = false
+	cfg.toCollectorConfig().MetricConfig.ExtraMetrics(m)
+
+	require.Equal(t, 1, rm.ScopeMetrics().Len())
+}
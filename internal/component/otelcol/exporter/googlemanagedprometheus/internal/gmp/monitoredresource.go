@@ -0,0 +1,66 @@
+package gmp
+
+import (
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	semconv "go.opentelemetry.io/collector/semconv/v1.27.0"
+	monitoredrespb "google.golang.org/genproto/googleapis/api/monitoredres"
+)
+
+// The labels of the prometheus_target monitored resource.
+const (
+	locationLabel  = "location"
+	clusterLabel   = "cluster"
+	namespaceLabel = "namespace"
+	jobLabel       = "job"
+	instanceLabel  = "instance"
+)
+
+// MapToPrometheusTarget maps a resource to the prometheus_target monitored
+// resource. Each label is taken from the resource attribute of the same name
+// when it's set, and from the matching semantic convention attributes
+// otherwise. The job is built from service.namespace and service.name, as
+// described by the OpenTelemetry specification for Prometheus compatibility.
+func MapToPrometheusTarget(res pcommon.Resource) *monitoredrespb.MonitoredResource {
+	attrs := res.Attributes()
+
+	job := firstAttribute(attrs, jobLabel)
+	if job == "" {
+		job = firstAttribute(attrs, semconv.AttributeServiceName)
+		if ns := firstAttribute(attrs, semconv.AttributeServiceNamespace); ns != "" {
+			job = ns + "/" + job
+		}
+	}
+
+	return &monitoredrespb.MonitoredResource{
+		Type: "prometheus_target",
+		Labels: map[string]string{
+			locationLabel:  firstAttribute(attrs, locationLabel, semconv.AttributeCloudAvailabilityZone, semconv.AttributeCloudRegion),
+			clusterLabel:   firstAttribute(attrs, clusterLabel, semconv.AttributeK8SClusterName),
+			namespaceLabel: firstAttribute(attrs, namespaceLabel, semconv.AttributeK8SNamespaceName),
+			jobLabel:       job,
+			instanceLabel:  firstAttribute(attrs, instanceLabel, semconv.AttributeServiceInstanceID),
+		},
+	}
+}
+
+// firstAttribute returns the value of the first of keys which is set in
+// attrs, or an empty string if none are.
+func firstAttribute(attrs pcommon.Map, keys ...string) string {
+	for _, k := range keys {
+		if v, ok := attrs.Get(k); ok && v.AsString() != "" {
+			return v.AsString()
+		}
+	}
+	return ""
+}
+
+// isTargetAttribute returns whether the resource attribute key is used to
+// build the prometheus_target monitored resource.
+func isTargetAttribute(key string) bool {
+	switch key {
+	case locationLabel, clusterLabel, namespaceLabel, jobLabel, instanceLabel,
+		semconv.AttributeServiceName, semconv.AttributeServiceNamespace, semconv.AttributeServiceInstanceID:
+		return true
+	}
+	return false
+}
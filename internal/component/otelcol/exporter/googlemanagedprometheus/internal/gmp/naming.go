@@ -0,0 +1,47 @@
+package gmp
+
+import (
+	"fmt"
+
+	"github.com/GoogleCloudPlatform/opentelemetry-operations-go/exporter/collector"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/pkg/translator/prometheus"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// metricNamer names metrics the way Managed Service for Prometheus expects:
+// the Prometheus name of the metric followed by the kind of the metric, like
+// prometheus.googleapis.com/http_requests_total/counter.
+type metricNamer struct {
+	addSuffixes bool
+}
+
+// GetMetricName implements the GetMetricName extension point of the Google
+// Cloud metrics exporter. For summaries, baseName is the name of the metric
+// with the _sum or _count suffix of the series being written.
+func (n metricNamer) GetMetricName(baseName string, metric pmetric.Metric) (string, error) {
+	name := prometheus.BuildCompliantName(metric, "", n.addSuffixes)
+
+	switch metric.Type() {
+	case pmetric.MetricTypeGauge:
+		return name + "/gauge", nil
+	case pmetric.MetricTypeSum:
+		// Non-monotonic sums can go down, so they are written as gauges.
+		if !metric.Sum().IsMonotonic() {
+			return name + "/gauge", nil
+		}
+		return name + "/counter", nil
+	case pmetric.MetricTypeSummary:
+		switch {
+		case baseName == metric.Name()+collector.SummarySumSuffix:
+			return name + collector.SummarySumSuffix + "/summary:counter", nil
+		case baseName == metric.Name()+collector.SummaryCountPrefix:
+			return name + collector.SummaryCountPrefix + "/summary", nil
+		default:
+			return name + "/summary", nil
+		}
+	case pmetric.MetricTypeHistogram, pmetric.MetricTypeExponentialHistogram:
+		return name + "/histogram", nil
+	default:
+		return "", fmt.Errorf("unsupported metric type %s for metric %q", metric.Type(), metric.Name())
+	}
+}
@@ -270,3 +270,84 @@ func TestEncoding(t *testing.T) {
 		})
 	}
 }
+
+func TestSASLAuthentication(t *testing.T) {
+	tests := []struct {
+		testName    string
+		sasl        string
+		expectedErr string
+	}{
+		{
+			testName: "aws_msk_iam",
+			sasl: `
+				mechanism = "AWS_MSK_IAM"
+				aws_msk {
+					region      = "us-east-1"
+					broker_addr = "b-1.msk.us-east-1.amazonaws.com:9098"
+				}
+			`,
+		},
+		{
+			testName: "aws_msk_iam_oauthbearer",
+			sasl: `
+				mechanism = "AWS_MSK_IAM_OAUTHBEARER"
+				aws_msk {
+					region = "us-east-1"
+				}
+			`,
+		},
+		{
+			testName: "aws_msk_iam_missing_broker_addr",
+			sasl: `
+				mechanism = "AWS_MSK_IAM"
+				aws_msk {
+					region = "us-east-1"
+				}
+			`,
+			expectedErr: `the aws_msk block with region and broker_addr is required when mechanism is "AWS_MSK_IAM"`,
+		},
+		{
+			testName:    "aws_msk_iam_oauthbearer_missing_aws_msk",
+			sasl:        `mechanism = "AWS_MSK_IAM_OAUTHBEARER"`,
+			expectedErr: `the aws_msk block with region is required when mechanism is "AWS_MSK_IAM_OAUTHBEARER"`,
+		},
+		{
+			testName: "plain_missing_password",
+			sasl: `
+				mechanism = "PLAIN"
+				username  = "user"
+			`,
+			expectedErr: `username and password are required when mechanism is "PLAIN"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			cfg := `
+				protocol_version = "2.0.0"
+				authentication {
+					sasl {` + tc.sasl + `}
+				}
+			`
+			var args kafka.Arguments
+			err := syntax.Unmarshal([]byte(cfg), &args)
+			if tc.expectedErr != "" {
+				require.ErrorContains(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+
+			actualPtr, err := args.Convert()
+			require.NoError(t, err)
+			actual := actualPtr.(*kafkaexporter.Config)
+
+			require.NotNil(t, actual.Authentication.SASL)
+			require.Empty(t, actual.Authentication.SASL.Username)
+			require.Empty(t, actual.Authentication.SASL.Password)
+			require.Equal(t, args.Authentication.SASL.Mechanism, actual.Authentication.SASL.Mechanism)
+			require.Equal(t, "us-east-1", actual.Authentication.SASL.AWSMSK.Region)
+			require.Equal(t, args.Authentication.SASL.AWSMSK.BrokerAddr, actual.Authentication.SASL.AWSMSK.BrokerAddr)
+			require.NoError(t, actual.Validate())
+		})
+	}
+}
@@ -45,6 +45,8 @@ func init() {
 				switch myArgs.RoutingKey {
 				case "traceID":
 					typeSignal = exporter.TypeLogs | exporter.TypeTraces
+				case "attributes":
+					typeSignal = exporter.TypeTraces
 				case "service":
 					if opts.MinStability.Permits(featuregate.StabilityExperimental) {
 						typeSignal = exporter.TypeLogs | exporter.TypeTraces | exporter.TypeMetrics
@@ -68,9 +70,10 @@ func init() {
 
 // Arguments configures the otelcol.exporter.loadbalancing component.
 type Arguments struct {
-	Protocol   Protocol         `alloy:"protocol,block"`
-	Resolver   ResolverSettings `alloy:"resolver,block"`
-	RoutingKey string           `alloy:"routing_key,attr,optional"`
+	Protocol          Protocol         `alloy:"protocol,block"`
+	Resolver          ResolverSettings `alloy:"resolver,block"`
+	RoutingKey        string           `alloy:"routing_key,attr,optional"`
+	RoutingAttributes []string         `alloy:"routing_attributes,attr,optional"`
 
 	Timeout time.Duration          `alloy:"timeout,attr,optional"`
 	Retry   otelcol.RetryArguments `alloy:"retry_on_failure,block,optional"`
@@ -102,6 +105,13 @@ func (args *Arguments) Validate() error {
 	switch args.RoutingKey {
 	case "service", "traceID", "resource", "metric", "streamID":
 		// The routing key is valid.
+		if len(args.RoutingAttributes) > 0 {
+			return fmt.Errorf("routing_attributes can only be set when routing_key is \"attributes\"")
+		}
+	case "attributes":
+		if len(args.RoutingAttributes) == 0 {
+			return fmt.Errorf("routing_attributes must be set when routing_key is \"attributes\"")
+		}
 	default:
 		return fmt.Errorf("invalid routing key %q", args.RoutingKey)
 	}
@@ -126,9 +136,10 @@ func (args Arguments) Convert() (otelcomponent.Config, error) {
 	}
 
 	return &loadbalancingexporter.Config{
-		Protocol:   *protocol,
-		Resolver:   args.Resolver.Convert(),
-		RoutingKey: args.RoutingKey,
+		Protocol:          *protocol,
+		Resolver:          args.Resolver.Convert(),
+		RoutingKey:        args.RoutingKey,
+		RoutingAttributes: args.RoutingAttributes,
 		TimeoutSettings: exporterhelper.TimeoutConfig{
 			Timeout: args.Timeout,
 		},
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/component/otelcol"
 	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
 	"github.com/grafana/alloy/internal/component/otelcol/exporter/loadbalancing"
@@ -16,6 +17,9 @@ import (
 	"github.com/grafana/alloy/syntax"
 	"github.com/grafana/dskit/backoff"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/exporter/loadbalancingexporter"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/config/configgrpc"
 	"go.opentelemetry.io/collector/config/configopaque"
@@ -24,6 +28,7 @@ import (
 	"go.opentelemetry.io/collector/exporter/otlpexporter"
 	"go.opentelemetry.io/collector/pdata/ptrace"
 	"go.opentelemetry.io/collector/pdata/ptrace/ptraceotlp"
+	"go.opentelemetry.io/otel/trace/noop"
 	"google.golang.org/grpc"
 )
 
@@ -179,6 +184,62 @@ func Test(t *testing.T) {
 	}
 }
 
+// TestResolverMetrics ensures that the metrics of the resolver are exposed
+// through the registerer of the component.
+func TestResolverMetrics(t *testing.T) {
+	var args loadbalancing.Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(`
+		resolver {
+			static {
+				hostnames = ["127.0.0.1:4317", "127.0.0.1:4318"]
+			}
+		}
+		protocol {
+			otlp {
+				client {}
+			}
+		}
+	`), &args))
+
+	reg, ok := component.Get("otelcol.exporter.loadbalancing")
+	require.True(t, ok)
+
+	registry := prometheus.NewRegistry()
+	c, err := reg.Build(component.Options{
+		ID:            "otelcol.exporter.loadbalancing.test",
+		Logger:        util.TestLogger(t),
+		Registerer:    registry,
+		Tracer:        noop.NewTracerProvider(),
+		OnStateChange: func(component.Exports) {},
+	}, args)
+	require.NoError(t, err)
+
+	ctx := componenttest.TestContext(t)
+	go func() {
+		require.NoError(t, c.Run(ctx))
+	}()
+
+	require.EventuallyWithT(t, func(t *assert.CollectT) {
+		mfs, err := registry.Gather()
+		require.NoError(t, err)
+
+		metrics := make(map[string]*dto.MetricFamily)
+		for _, mf := range mfs {
+			metrics[mf.GetName()] = mf
+		}
+		require.Contains(t, metrics, "otelcol_loadbalancer_num_resolutions_total")
+		require.Contains(t, metrics, "otelcol_loadbalancer_num_backend_updates_total")
+		require.Contains(t, metrics, "otelcol_loadbalancer_num_backends")
+
+		backends := metrics["otelcol_loadbalancer_num_backends"].GetMetric()
+		require.Len(t, backends, 1)
+		require.Equal(t, 2.0, backends[0].GetGauge().GetValue())
+		require.Contains(t, backends[0].GetLabel(), &dto.LabelPair{Name: ptr("resolver"), Value: ptr("static")})
+	}, 5*time.Second, 50*time.Millisecond)
+}
+
+func ptr(s string) *string { return &s }
+
 // makeTracesServer returns a host:port which will accept traces over insecure
 // gRPC.
 func makeTracesServer(t *testing.T, ch chan ptrace.Traces) string {
@@ -315,6 +376,33 @@ func TestConfigConversion(t *testing.T) {
 				Protocol:   defaultProtocol,
 			},
 		},
+		{
+			testName: "static with attributes routing",
+			alloyCfg: `
+			routing_key = "attributes"
+			routing_attributes = ["service.name", "span.kind"]
+			resolver {
+				static {
+					hostnames = ["endpoint-1"]
+				}
+			}
+			protocol {
+				otlp {
+					client {}
+				}
+			}
+			`,
+			expected: loadbalancingexporter.Config{
+				Resolver: loadbalancingexporter.ResolverSettings{
+					Static: &loadbalancingexporter.StaticResolver{
+						Hostnames: []string{"endpoint-1"},
+					},
+				},
+				RoutingKey:        "attributes",
+				RoutingAttributes: []string{"service.name", "span.kind"},
+				Protocol:          defaultProtocol,
+			},
+		},
 		{
 			testName: "static with timeout",
 			alloyCfg: `
@@ -656,6 +744,47 @@ func TestConfigConversion(t *testing.T) {
 	}
 }
 
+func TestRoutingAttributesValidation(t *testing.T) {
+	tests := []struct {
+		testName    string
+		alloyCfg    string
+		expectedErr string
+	}{
+		{
+			testName:    "attributes routing without routing attributes",
+			alloyCfg:    `routing_key = "attributes"`,
+			expectedErr: `routing_attributes must be set when routing_key is "attributes"`,
+		},
+		{
+			testName: "routing attributes with another routing key",
+			alloyCfg: `
+			routing_key = "service"
+			routing_attributes = ["service.name"]
+			`,
+			expectedErr: `routing_attributes can only be set when routing_key is "attributes"`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			alloyCfg := tc.alloyCfg + `
+			resolver {
+				static {
+					hostnames = ["endpoint-1"]
+				}
+			}
+			protocol {
+				otlp {
+					client {}
+				}
+			}
+			`
+			var args loadbalancing.Arguments
+			require.ErrorContains(t, syntax.Unmarshal([]byte(alloyCfg), &args), tc.expectedErr)
+		})
+	}
+}
+
 func TestDebugMetricsConfig(t *testing.T) {
 	tests := []struct {
 		testName string
@@ -0,0 +1,61 @@
+// Package awsutil provides the configuration shared by the exporters which
+// send data to AWS.
+package awsutil
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	aws_config "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// SessionSettings configures the AWS clients of an exporter.
+type SessionSettings struct {
+	Region         string        `mapstructure:"region"`
+	Endpoint       string        `mapstructure:"endpoint"`
+	RoleARN        string        `mapstructure:"role_arn"`
+	MaxRetries     int           `mapstructure:"max_retries"`
+	RequestTimeout time.Duration `mapstructure:"request_timeout"`
+	NoVerifySSL    bool          `mapstructure:"no_verify_ssl"`
+}
+
+// LoadConfig loads the AWS configuration with the default credentials,
+// assuming RoleARN if set.
+func (s SessionSettings) LoadConfig(ctx context.Context) (aws.Config, error) {
+	httpClient := awshttp.NewBuildableClient().WithTimeout(s.RequestTimeout)
+	if s.NoVerifySSL {
+		httpClient = httpClient.WithTransportOptions(func(tr *http.Transport) {
+			if tr.TLSClientConfig == nil {
+				tr.TLSClientConfig = &tls.Config{}
+			}
+			tr.TLSClientConfig.InsecureSkipVerify = true
+		})
+	}
+
+	opts := []func(*aws_config.LoadOptions) error{
+		aws_config.WithHTTPClient(httpClient),
+		aws_config.WithRetryMaxAttempts(s.MaxRetries + 1),
+	}
+	if s.Region != "" {
+		opts = append(opts, aws_config.WithRegion(s.Region))
+	}
+
+	cfg, err := aws_config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return aws.Config{}, err
+	}
+	if cfg.Region == "" {
+		return aws.Config{}, errors.New("no AWS region is configured, set region or the AWS_REGION environment variable")
+	}
+	if s.RoleARN != "" {
+		cfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), s.RoleARN))
+	}
+	return cfg, nil
+}
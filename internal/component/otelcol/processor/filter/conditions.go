@@ -0,0 +1,42 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/processor/filterprocessor"
+)
+
+// validateConditions validates each condition of the config separately, with
+// the same functions as the filter processor, so that the errors point to the
+// block and the index of the invalid conditions.
+func validateConditions(cfg *filterprocessor.Config) error {
+	var errs []error
+	validate := func(block, context string, conds []string, set func(cfg *filterprocessor.Config, conds []string)) {
+		for i, cond := range conds {
+			var single filterprocessor.Config
+			set(&single, []string{cond})
+			if err := single.Validate(); err != nil {
+				errs = append(errs, fmt.Errorf("%s.%s[%d]: %w", block, context, i, err))
+			}
+		}
+	}
+
+	validate("traces", "span", cfg.Traces.SpanConditions, func(cfg *filterprocessor.Config, conds []string) {
+		cfg.Traces.SpanConditions = conds
+	})
+	validate("traces", "spanevent", cfg.Traces.SpanEventConditions, func(cfg *filterprocessor.Config, conds []string) {
+		cfg.Traces.SpanEventConditions = conds
+	})
+	validate("metrics", "metric", cfg.Metrics.MetricConditions, func(cfg *filterprocessor.Config, conds []string) {
+		cfg.Metrics.MetricConditions = conds
+	})
+	validate("metrics", "datapoint", cfg.Metrics.DataPointConditions, func(cfg *filterprocessor.Config, conds []string) {
+		cfg.Metrics.DataPointConditions = conds
+	})
+	validate("logs", "log_record", cfg.Logs.LogConditions, func(cfg *filterprocessor.Config, conds []string) {
+		cfg.Logs.LogConditions = conds
+	})
+
+	return errors.Join(errs...)
+}
@@ -62,6 +62,11 @@ func (args *Arguments) Validate() error {
 	if err != nil {
 		return err
 	}
+	// Parse the conditions one by one first, so that the error points to the
+	// invalid condition.
+	if err := validateConditions(otelArgs); err != nil {
+		return err
+	}
 	return otelArgs.Validate()
 }
 
@@ -156,6 +156,22 @@ func TestArguments_UnmarshalAlloy(t *testing.T) {
 			`,
 			errMsg: `unable to parse OTTL condition "UnknowFunction(\"http.method\")": undefined function "UnknowFunction"`,
 		},
+		{
+			testName: "invalidConditionIndex",
+			cfg: `
+			traces {
+				span = [
+					"name == \"a\"",
+				]
+				spanevent = [
+					"name == \"a\"",
+					"name ==",
+				]
+			}
+			output {}
+			`,
+			errMsg: `traces.spanevent[1]: unable to parse OTTL condition "name =="`,
+		},
 	}
 
 	for _, tc := range tests {
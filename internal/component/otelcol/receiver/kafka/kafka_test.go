@@ -301,6 +301,69 @@ func TestArguments_Auth(t *testing.T) {
 				},
 			},
 		},
+		{
+			testName: "sasl_aws_msk_iam",
+			cfg: `
+				brokers = ["10.10.10.10:9092"]
+				protocol_version = "2.0.0"
+
+				authentication {
+					sasl {
+						mechanism = "AWS_MSK_IAM"
+						aws_msk {
+							region = "us-east-1"
+							broker_addr = "b-1.msk.us-east-1.amazonaws.com:9098"
+						}
+					}
+				}
+
+				output {}
+			`,
+			expected: map[string]interface{}{
+				"brokers":            []string{"10.10.10.10:9092"},
+				"protocol_version":   "2.0.0",
+				"session_timeout":    10 * time.Second,
+				"heartbeat_interval": 3 * time.Second,
+				"encoding":           "otlp_proto",
+				"group_id":           "otel-collector",
+				"client_id":          "otel-collector",
+				"initial_offset":     "latest",
+				"min_fetch_size":     1,
+				"default_fetch_size": 1048576,
+				"metadata": kafkaexporter.Metadata{
+					Full: true,
+					Retry: kafkaexporter.MetadataRetry{
+						Max:     3,
+						Backoff: 250 * time.Millisecond,
+					},
+				},
+				"autocommit": kafkareceiver.AutoCommit{
+					Enable:   true,
+					Interval: 1 * time.Second,
+				},
+				"header_extraction": kafkareceiver.HeaderExtraction{
+					ExtractHeaders: false,
+					Headers:        []string{},
+				},
+				"error_backoff": configretry.BackOffConfig{
+					Enabled:             false,
+					InitialInterval:     0,
+					RandomizationFactor: 0,
+					Multiplier:          0,
+					MaxInterval:         0,
+					MaxElapsedTime:      0,
+				},
+				"auth": map[string]interface{}{
+					"sasl": map[string]interface{}{
+						"mechanism": "AWS_MSK_IAM",
+						"aws_msk": map[string]interface{}{
+							"region":      "us-east-1",
+							"broker_addr": "b-1.msk.us-east-1.amazonaws.com:9098",
+						},
+					},
+				},
+			},
+		},
 		{
 			testName: "tls",
 			cfg: `
@@ -0,0 +1,227 @@
+package netflow
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// Fields of the flows. The type and the exporter of the flows are set as
+// attributes of the log records, and the other fields in their body.
+const (
+	fieldType         = "flow.type"
+	fieldExporter     = "flow.exporter.address"
+	fieldBytes        = "flow.bytes"
+	fieldPackets      = "flow.packets"
+	fieldSamplingRate = "flow.sampling_rate"
+	fieldStart        = "flow.start"
+	fieldTCPFlags     = "flow.tcp_flags"
+	fieldInIf         = "flow.interface.in"
+	fieldOutIf        = "flow.interface.out"
+	fieldNextHop      = "flow.next_hop.address"
+	fieldSrcAS        = "flow.as.source"
+	fieldDstAS        = "flow.as.destination"
+	fieldSrcAddr      = "source.address"
+	fieldSrcPort      = "source.port"
+	fieldDstAddr      = "destination.address"
+	fieldDstPort      = "destination.port"
+	fieldNetworkType  = "network.type"
+	fieldTransport    = "network.transport"
+)
+
+// metricFields are the fields which the metrics can be aggregated by.
+var metricFields = map[string]struct{}{
+	fieldType:         {},
+	fieldExporter:     {},
+	fieldSamplingRate: {},
+	fieldInIf:         {},
+	fieldOutIf:        {},
+	fieldNextHop:      {},
+	fieldSrcAS:        {},
+	fieldDstAS:        {},
+	fieldSrcAddr:      {},
+	fieldSrcPort:      {},
+	fieldDstAddr:      {},
+	fieldDstPort:      {},
+	fieldNetworkType:  {},
+	fieldTransport:    {},
+}
+
+// Names of the metrics.
+const (
+	metricBytes   = "flow.bytes"
+	metricPackets = "flow.packets"
+)
+
+// scopeName is the name of the instrumentation scope of the logs and
+// metrics.
+const scopeName = "github.com/grafana/alloy/otelcol/receiver/netflow"
+
+// protocolNames are the names of the common IP protocols.
+var protocolNames = map[uint8]string{
+	1:   "icmp",
+	6:   "tcp",
+	17:  "udp",
+	47:  "gre",
+	50:  "esp",
+	58:  "ipv6-icmp",
+	132: "sctp",
+}
+
+// convertLogs converts flows to log records. The records are timestamped with
+// the end of their flow when it's known.
+func convertLogs(flows []flow, now time.Time) plog.Logs {
+	logs := plog.NewLogs()
+	scopeLogs := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+	scopeLogs.Scope().SetName(scopeName)
+
+	for _, f := range flows {
+		lr := scopeLogs.LogRecords().AppendEmpty()
+		ts := now
+		if !f.End.IsZero() {
+			ts = f.End
+		}
+		lr.SetTimestamp(pcommon.NewTimestampFromTime(ts))
+		lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(now))
+
+		lr.Attributes().PutStr(fieldType, f.Type)
+		lr.Attributes().PutStr(fieldExporter, f.Exporter.String())
+
+		body := lr.Body().SetEmptyMap()
+		putFields(body, f)
+		if !f.Start.IsZero() {
+			body.PutStr(fieldStart, f.Start.UTC().Format(time.RFC3339Nano))
+		}
+	}
+	return logs
+}
+
+// putFields sets the fields of a flow which describe its traffic. Its bytes
+// and packets are scaled by its sampling rate.
+func putFields(m pcommon.Map, f flow) {
+	m.PutInt(fieldBytes, int64(f.Bytes*f.SamplingRate))
+	m.PutInt(fieldPackets, int64(f.Packets*f.SamplingRate))
+	m.PutInt(fieldSamplingRate, int64(f.SamplingRate))
+
+	switch f.EtherType {
+	case etherTypeIPv4:
+		m.PutStr(fieldNetworkType, "ipv4")
+	case etherTypeIPv6:
+		m.PutStr(fieldNetworkType, "ipv6")
+	}
+	if f.SrcAddr != nil {
+		m.PutStr(fieldSrcAddr, f.SrcAddr.String())
+	}
+	if f.DstAddr != nil {
+		m.PutStr(fieldDstAddr, f.DstAddr.String())
+	}
+	if f.Protocol != 0 {
+		if name, ok := protocolNames[f.Protocol]; ok {
+			m.PutStr(fieldTransport, name)
+		} else {
+			m.PutStr(fieldTransport, strconv.Itoa(int(f.Protocol)))
+		}
+	}
+	if f.SrcPort != 0 || f.DstPort != 0 {
+		m.PutInt(fieldSrcPort, int64(f.SrcPort))
+		m.PutInt(fieldDstPort, int64(f.DstPort))
+	}
+	if f.Protocol == ipProtocolTCP {
+		m.PutInt(fieldTCPFlags, int64(f.TCPFlags))
+	}
+	if f.InIf != 0 {
+		m.PutInt(fieldInIf, int64(f.InIf))
+	}
+	if f.OutIf != 0 {
+		m.PutInt(fieldOutIf, int64(f.OutIf))
+	}
+	if f.NextHop != nil && !f.NextHop.IsUnspecified() {
+		m.PutStr(fieldNextHop, f.NextHop.String())
+	}
+	if f.SrcAS != 0 {
+		m.PutInt(fieldSrcAS, int64(f.SrcAS))
+	}
+	if f.DstAS != 0 {
+		m.PutInt(fieldDstAS, int64(f.DstAS))
+	}
+}
+
+// convertMetrics converts flows to delta sums of their bytes and packets,
+// aggregated by the given fields. The sums start with the earliest flow.
+func convertMetrics(flows []flow, attributes []string, now time.Time) pmetric.Metrics {
+	type series struct {
+		attrs          pcommon.Map
+		bytes, packets int64
+	}
+	var (
+		order []string
+		sums  = make(map[string]*series)
+		start = now
+	)
+	for _, f := range flows {
+		fields := pcommon.NewMap()
+		fields.PutStr(fieldType, f.Type)
+		fields.PutStr(fieldExporter, f.Exporter.String())
+		putFields(fields, f)
+		if !f.Start.IsZero() && f.Start.Before(start) {
+			start = f.Start
+		}
+
+		attrs := pcommon.NewMap()
+		var key strings.Builder
+		for _, name := range attributes {
+			if v, ok := fields.Get(name); ok {
+				v.CopyTo(attrs.PutEmpty(name))
+				key.WriteString(v.AsString())
+			}
+			key.WriteByte(0)
+		}
+
+		s, ok := sums[key.String()]
+		if !ok {
+			s = &series{attrs: attrs}
+			sums[key.String()] = s
+			order = append(order, key.String())
+		}
+		bytes, _ := fields.Get(fieldBytes)
+		packets, _ := fields.Get(fieldPackets)
+		s.bytes += bytes.Int()
+		s.packets += packets.Int()
+	}
+
+	metrics := pmetric.NewMetrics()
+	scopeMetrics := metrics.ResourceMetrics().AppendEmpty().ScopeMetrics().AppendEmpty()
+	scopeMetrics.Scope().SetName(scopeName)
+
+	bytesSum := newDeltaSum(scopeMetrics, metricBytes, "By", "Bytes of the flows, scaled by their sampling rate.")
+	packetsSum := newDeltaSum(scopeMetrics, metricPackets, "{packet}", "Packets of the flows, scaled by their sampling rate.")
+	for _, key := range order {
+		s := sums[key]
+		appendPoint(bytesSum, s.attrs, s.bytes, start, now)
+		appendPoint(packetsSum, s.attrs, s.packets, start, now)
+	}
+	return metrics
+}
+
+func appendPoint(sum pmetric.Sum, attrs pcommon.Map, value int64, start, now time.Time) {
+	point := sum.DataPoints().AppendEmpty()
+	point.SetStartTimestamp(pcommon.NewTimestampFromTime(start))
+	point.SetTimestamp(pcommon.NewTimestampFromTime(now))
+	point.SetIntValue(value)
+	attrs.CopyTo(point.Attributes())
+}
+
+func newDeltaSum(sm pmetric.ScopeMetrics, name, unit, description string) pmetric.Sum {
+	m := sm.Metrics().AppendEmpty()
+	m.SetName(name)
+	m.SetUnit(unit)
+	m.SetDescription(description)
+	sum := m.SetEmptySum()
+	sum.SetAggregationTemporality(pmetric.AggregationTemporalityDelta)
+	sum.SetIsMonotonic(true)
+	return sum
+}
@@ -0,0 +1,114 @@
+package netflow
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+func TestConvertLogs(t *testing.T) {
+	now := time.Unix(1_700_000_100, 0)
+	flows := []flow{
+		{
+			Type:         typeNetFlowV5,
+			Exporter:     testExporter,
+			SamplingRate: 10,
+			Bytes:        1500,
+			Packets:      3,
+			Start:        time.Unix(1_700_000_000, 0),
+			End:          time.Unix(1_700_000_050, 0),
+			EtherType:    etherTypeIPv4,
+			Protocol:     6,
+			SrcAddr:      testSrc,
+			DstAddr:      testDst,
+			SrcPort:      1234,
+			DstPort:      443,
+			TCPFlags:     0x18,
+			NextHop:      net.IPv4zero.To4(),
+			InIf:         1,
+			SrcAS:        64512,
+		},
+		{
+			Type:         typeSFlowV5,
+			Exporter:     testExporter,
+			SamplingRate: 1,
+			Bytes:        60,
+			Packets:      1,
+			Protocol:     89,
+		},
+	}
+
+	logs := convertLogs(flows, now)
+	require.Equal(t, 2, logs.LogRecordCount())
+	records := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords()
+
+	lr := records.At(0)
+	require.Equal(t, pcommon.NewTimestampFromTime(flows[0].End), lr.Timestamp())
+	require.Equal(t, pcommon.NewTimestampFromTime(now), lr.ObservedTimestamp())
+	require.Equal(t, map[string]any{
+		fieldType:     typeNetFlowV5,
+		fieldExporter: "192.0.2.1",
+	}, lr.Attributes().AsRaw())
+	require.Equal(t, map[string]any{
+		fieldBytes:        int64(15000),
+		fieldPackets:      int64(30),
+		fieldSamplingRate: int64(10),
+		fieldStart:        "2023-11-14T22:13:20Z",
+		fieldNetworkType:  "ipv4",
+		fieldTransport:    "tcp",
+		fieldSrcAddr:      "10.0.0.1",
+		fieldSrcPort:      int64(1234),
+		fieldDstAddr:      "10.0.0.2",
+		fieldDstPort:      int64(443),
+		fieldTCPFlags:     int64(0x18),
+		fieldInIf:         int64(1),
+		fieldSrcAS:        int64(64512),
+	}, lr.Body().Map().AsRaw())
+
+	lr = records.At(1)
+	require.Equal(t, pcommon.NewTimestampFromTime(now), lr.Timestamp())
+	require.Equal(t, map[string]any{
+		fieldBytes:        int64(60),
+		fieldPackets:      int64(1),
+		fieldSamplingRate: int64(1),
+		fieldTransport:    "89",
+	}, lr.Body().Map().AsRaw())
+}
+
+func TestConvertMetrics(t *testing.T) {
+	now := time.Unix(1_700_000_100, 0)
+	start := time.Unix(1_700_000_000, 0)
+	flows := []flow{
+		{Type: typeIPFIX, Exporter: testExporter, SamplingRate: 1, Bytes: 100, Packets: 1, Protocol: 6, Start: start},
+		{Type: typeIPFIX, Exporter: testExporter, SamplingRate: 2, Bytes: 200, Packets: 2, Protocol: 6},
+		{Type: typeIPFIX, Exporter: testExporter, SamplingRate: 1, Bytes: 50, Packets: 1},
+	}
+
+	metrics := convertMetrics(flows, []string{fieldExporter, fieldTransport}, now)
+	ms := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics()
+	require.Equal(t, 2, ms.Len())
+
+	expect := map[string][]int64{
+		metricBytes:   {500, 50},
+		metricPackets: {5, 1},
+	}
+	for i := range ms.Len() {
+		m := ms.At(i)
+		require.Equal(t, pmetric.AggregationTemporalityDelta, m.Sum().AggregationTemporality())
+		require.True(t, m.Sum().IsMonotonic())
+
+		points := m.Sum().DataPoints()
+		require.Equal(t, 2, points.Len(), m.Name())
+		for j := range points.Len() {
+			require.Equal(t, expect[m.Name()][j], points.At(j).IntValue(), m.Name())
+			require.Equal(t, pcommon.NewTimestampFromTime(start), points.At(j).StartTimestamp())
+			require.Equal(t, pcommon.NewTimestampFromTime(now), points.At(j).Timestamp())
+		}
+		require.Equal(t, map[string]any{fieldExporter: "192.0.2.1", fieldTransport: "tcp"}, points.At(0).Attributes().AsRaw())
+		require.Equal(t, map[string]any{fieldExporter: "192.0.2.1"}, points.At(1).Attributes().AsRaw())
+	}
+}
@@ -0,0 +1,393 @@
+package netflow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/netip"
+	"sync"
+	"time"
+
+	"github.com/netsampler/goflow2/v2/decoders/netflow"
+	"github.com/netsampler/goflow2/v2/decoders/netflowlegacy"
+	"github.com/netsampler/goflow2/v2/decoders/sflow"
+	flowpb "github.com/netsampler/goflow2/v2/pb"
+	"github.com/netsampler/goflow2/v2/producer"
+	protoproducer "github.com/netsampler/goflow2/v2/producer/proto"
+)
+
+// Types of the flows, named after the protocol of the datagrams they're
+// received in.
+const (
+	typeNetFlowV5 = "netflow_v5"
+	typeNetFlowV9 = "netflow_v9"
+	typeIPFIX     = "ipfix"
+	typeSFlowV5   = "sflow_v5"
+)
+
+const (
+	etherTypeIPv4 = 0x0800
+	etherTypeIPv6 = 0x86dd
+
+	ipProtocolTCP = 6
+)
+
+const (
+	// netFlowV5MaxRecords is the maximum number of records of a NetFlow v5
+	// datagram, as defined by Cisco.
+	netFlowV5MaxRecords = 30
+	netFlowV5HeaderLen  = 24
+	netFlowV5RecordLen  = 48
+)
+
+const (
+	// maxTemplates bounds the number of templates cached for all exporters,
+	// so that datagrams from many spoofed exporters can't exhaust the memory.
+	maxTemplates = 65536
+
+	// maxExporters bounds the number of exporters whose NetFlow v9 and IPFIX
+	// sampling rates are tracked. The sampling rates of all exporters are
+	// forgotten once it's reached.
+	maxExporters = 4096
+)
+
+var (
+	errTruncated       = errors.New("datagram is truncated")
+	errMissingTemplate = errors.New("template of the data records is unknown")
+)
+
+// flow is a flow record decoded from a datagram, regardless of its protocol.
+// The fields which aren't provided by the exporter are left empty.
+type flow struct {
+	Type     string
+	Exporter net.IP
+
+	// SamplingRate is the number of packets observed by the exporter for
+	// each packet accounted in the flow. It's 1 when the packets aren't
+	// sampled, or when the sampling rate is unknown.
+	SamplingRate uint64
+	Bytes        uint64
+	Packets      uint64
+
+	// Start and End are the times of the first and last packets of the flow.
+	Start, End time.Time
+
+	EtherType uint16
+	Protocol  uint8
+	SrcAddr   net.IP
+	DstAddr   net.IP
+	SrcPort   uint16
+	DstPort   uint16
+	TCPFlags  uint8
+	NextHop   net.IP
+	InIf      uint32
+	OutIf     uint32
+	SrcAS     uint32
+	DstAS     uint32
+}
+
+// decoder decodes the NetFlow, IPFIX, and sFlow datagrams with goflow2. It
+// caches the NetFlow v9 and IPFIX templates of every exporter.
+type decoder struct {
+	templates *templateCache
+
+	mut       sync.Mutex
+	producer  producer.ProducerInterface
+	exporters map[netip.Addr]struct{}
+}
+
+func newDecoder() *decoder {
+	return &decoder{
+		templates: newTemplateCache(),
+		producer:  newProducer(),
+		exporters: make(map[netip.Addr]struct{}),
+	}
+}
+
+func newProducer() producer.ProducerInterface {
+	cfg, err := (&protoproducer.ProducerConfig{}).Compile()
+	if err != nil {
+		panic(fmt.Sprintf("compiling the default flow producer configuration: %s", err))
+	}
+	p, err := protoproducer.CreateProtoProducer(cfg, protoproducer.CreateSamplingSystem)
+	if err != nil {
+		panic(fmt.Sprintf("creating the flow producer: %s", err))
+	}
+	return p
+}
+
+// Decode decodes the flows of a datagram received from addr. The protocol
+// of the datagram is detected from its version number. When some of the data
+// records can't be decoded because their template is unknown,
+// errMissingTemplate is returned along with the other flows.
+func (d *decoder) Decode(b []byte, addr net.IP) ([]flow, error) {
+	if len(b) < 4 {
+		return nil, errTruncated
+	}
+
+	var (
+		msg any
+		err error
+		buf = bytes.NewBuffer(b)
+	)
+
+	// sFlow datagrams start with a 32-bit version, and the NetFlow and IPFIX
+	// ones with a 16-bit version.
+	switch version := binary.BigEndian.Uint16(b); version {
+	case 0:
+		if v := binary.BigEndian.Uint32(b); v != 5 {
+			return nil, fmt.Errorf("unsupported sFlow version %d", v)
+		}
+		var p sflow.Packet
+		err, msg = sflow.DecodeMessageVersion(buf, &p), &p
+	case 5:
+		if err := checkNetFlowV5(b); err != nil {
+			return nil, err
+		}
+		var p netflowlegacy.PacketNetFlowV5
+		err, msg = netflowlegacy.DecodeMessageVersion(buf, &p), &p
+	case 9, 10:
+		var (
+			v9    netflow.NFv9Packet
+			ipfix netflow.IPFIXPacket
+		)
+		ts := exporterTemplates{cache: d.templates, exporter: addr.String()}
+		err = netflow.DecodeMessageVersion(buf, ts, &v9, &ipfix)
+		if version == 9 {
+			msg = &v9
+		} else {
+			msg = &ipfix
+		}
+	default:
+		return nil, fmt.Errorf("unsupported datagram version %d", version)
+	}
+
+	var missingTemplate bool
+	switch {
+	case errors.Is(err, netflow.ErrorTemplateNotFound):
+		// The other flowsets are decoded regardless.
+		missingTemplate = true
+	case errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, io.EOF):
+		return nil, fmt.Errorf("%w: %w", errTruncated, err)
+	case err != nil:
+		return nil, err
+	}
+
+	flows, err := d.produce(msg, addr)
+	if err != nil {
+		return nil, err
+	}
+	if missingTemplate {
+		return flows, errMissingTemplate
+	}
+	return flows, nil
+}
+
+// checkNetFlowV5 checks the record count of a NetFlow v5 datagram before it's
+// decoded, as goflow2 allocates the records from the count of the header.
+func checkNetFlowV5(b []byte) error {
+	if len(b) < netFlowV5HeaderLen {
+		return errTruncated
+	}
+	count := int(binary.BigEndian.Uint16(b[2:]))
+	if count > netFlowV5MaxRecords {
+		return fmt.Errorf("NetFlow v5 datagram has %d records, more than the maximum of %d", count, netFlowV5MaxRecords)
+	}
+	if len(b) < netFlowV5HeaderLen+count*netFlowV5RecordLen {
+		return errTruncated
+	}
+	return nil
+}
+
+// produce converts a decoded datagram to flows.
+func (d *decoder) produce(msg any, addr net.IP) ([]flow, error) {
+	exporter, _ := netip.AddrFromSlice(addr)
+	exporter = exporter.Unmap()
+
+	d.mut.Lock()
+	defer d.mut.Unlock()
+
+	if _, ok := d.exporters[exporter]; !ok {
+		if len(d.exporters) >= maxExporters {
+			d.producer = newProducer()
+			clear(d.exporters)
+		}
+		d.exporters[exporter] = struct{}{}
+	}
+
+	msgs, err := d.producer.Produce(msg, &producer.ProduceArgs{
+		Src:            netip.AddrPortFrom(exporter, 0),
+		SamplerAddress: exporter,
+		TimeReceived:   time.Now(),
+	})
+	// The messages are pooled, so they're copied before being committed.
+	defer d.producer.Commit(msgs)
+	if err != nil {
+		return nil, err
+	}
+
+	typ := flowType(msg)
+	flows := make([]flow, 0, len(msgs))
+	for _, m := range msgs {
+		pm, ok := m.(*protoproducer.ProtoProducerMessage)
+		if !ok {
+			continue
+		}
+		flows = append(flows, toFlow(typ, &pm.FlowMessage))
+	}
+	return flows, nil
+}
+
+func flowType(msg any) string {
+	switch msg.(type) {
+	case *netflowlegacy.PacketNetFlowV5:
+		return typeNetFlowV5
+	case *netflow.NFv9Packet:
+		return typeNetFlowV9
+	case *netflow.IPFIXPacket:
+		return typeIPFIX
+	default:
+		return typeSFlowV5
+	}
+}
+
+func toFlow(typ string, m *flowpb.FlowMessage) flow {
+	f := flow{
+		Type:         typ,
+		Exporter:     ipField(m.SamplerAddress),
+		SamplingRate: m.SamplingRate,
+		Bytes:        m.Bytes,
+		Packets:      m.Packets,
+		Start:        timeField(m.TimeFlowStartNs),
+		End:          timeField(m.TimeFlowEndNs),
+		EtherType:    uint16(m.Etype),
+		Protocol:     uint8(m.Proto),
+		SrcAddr:      ipField(m.SrcAddr),
+		DstAddr:      ipField(m.DstAddr),
+		SrcPort:      uint16(m.SrcPort),
+		DstPort:      uint16(m.DstPort),
+		TCPFlags:     uint8(m.TcpFlags),
+		NextHop:      ipField(m.NextHop),
+		InIf:         m.InIf,
+		OutIf:        m.OutIf,
+		SrcAS:        m.SrcAs,
+		DstAS:        m.DstAs,
+	}
+	if f.NextHop == nil {
+		// sFlow exporters send the next hop of the flows they route with BGP
+		// in their gateway records.
+		f.NextHop = ipField(m.BgpNextHop)
+	}
+	if f.SamplingRate == 0 {
+		f.SamplingRate = 1
+	}
+	return f
+}
+
+// ipField copies an address of a flow message, as the messages are reused.
+func ipField(b []byte) net.IP {
+	if len(b) != net.IPv4len && len(b) != net.IPv6len {
+		return nil
+	}
+	return net.IP(append([]byte(nil), b...))
+}
+
+func timeField(ns uint64) time.Time {
+	if ns == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(ns))
+}
+
+type templateKey struct {
+	Version  uint16
+	Exporter string
+	Domain   uint32
+	ID       uint16
+}
+
+// templateCache caches the templates of the exporters. Templates are scoped
+// to the exporter, its observation domain or source ID, and the protocol
+// version.
+type templateCache struct {
+	mut       sync.RWMutex
+	templates map[templateKey]any
+}
+
+func newTemplateCache() *templateCache {
+	return &templateCache{templates: make(map[templateKey]any)}
+}
+
+func (c *templateCache) Get(key templateKey) (any, bool) {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+	t, ok := c.templates[key]
+	return t, ok
+}
+
+func (c *templateCache) Put(key templateKey, t any) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	if _, ok := c.templates[key]; !ok && len(c.templates) >= maxTemplates {
+		return
+	}
+	c.templates[key] = t
+}
+
+func (c *templateCache) Delete(key templateKey) (any, bool) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	t, ok := c.templates[key]
+	delete(c.templates, key)
+	return t, ok
+}
+
+// exporterTemplates exposes the templates of an exporter to goflow2.
+type exporterTemplates struct {
+	cache    *templateCache
+	exporter string
+}
+
+var _ netflow.NetFlowTemplateSystem = exporterTemplates{}
+
+func (t exporterTemplates) key(version uint16, domain uint32, id uint16) templateKey {
+	return templateKey{Version: version, Exporter: t.exporter, Domain: domain, ID: id}
+}
+
+func (t exporterTemplates) GetTemplate(version uint16, domain uint32, id uint16) (any, error) {
+	if tmpl, ok := t.cache.Get(t.key(version, domain, id)); ok {
+		return tmpl, nil
+	}
+	return nil, netflow.ErrorTemplateNotFound
+}
+
+// AddTemplate adds a template. The templates which don't fit in the cache are
+// dropped without an error, so that the rest of the datagram is decoded.
+// IPFIX templates without fields withdraw the previous template with their ID.
+func (t exporterTemplates) AddTemplate(version uint16, domain uint32, id uint16, tmpl any) error {
+	key := t.key(version, domain, id)
+	switch tmpl := tmpl.(type) {
+	case netflow.TemplateRecord:
+		if version == 10 && tmpl.FieldCount == 0 {
+			t.cache.Delete(key)
+			return nil
+		}
+	case netflow.IPFIXOptionsTemplateRecord:
+		if tmpl.FieldCount == 0 {
+			t.cache.Delete(key)
+			return nil
+		}
+	}
+	t.cache.Put(key, tmpl)
+	return nil
+}
+
+func (t exporterTemplates) RemoveTemplate(version uint16, domain uint32, id uint16) (any, error) {
+	if tmpl, ok := t.cache.Delete(t.key(version, domain, id)); ok {
+		return tmpl, nil
+	}
+	return nil, netflow.ErrorTemplateNotFound
+}
@@ -0,0 +1,341 @@
+package netflow
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Fields of the test datagrams, see RFC 3954, the IANA IPFIX registry, and
+// https://sflow.org/sflow_version_5.txt.
+const (
+	ieOctetDeltaCount          = 1
+	iePacketDeltaCount         = 2
+	ieProtocolIdentifier       = 4
+	ieSourceIPv4Address        = 8
+	ieIngressInterface         = 10
+	ieDestinationTransportPort = 11
+	ieDestinationIPv4Address   = 12
+	ieFlowEndSysUpTime         = 21
+	ieSourceIPv6Address        = 27
+	ieSamplingInterval         = 34
+	ieFlowStartMilliseconds    = 152
+	ieSamplingPacketInterval   = 305
+
+	// variableLength is the length of the IPFIX fields whose length is sent
+	// with every data record.
+	variableLength = 65535
+
+	sFlowFlowSample         = 1
+	sFlowExpandedFlowSample = 3
+	sFlowRawPacketHeader    = 1
+	sFlowSampledIPv4        = 3
+	sFlowSampledIPv6        = 4
+	sFlowExtendedGateway    = 1003
+	sFlowHeaderEthernet     = 1
+
+	etherTypeVLAN = 0x8100
+)
+
+var (
+	testExporter = net.ParseIP("192.0.2.1").To4()
+	testSrc      = net.ParseIP("10.0.0.1").To4()
+	testDst      = net.ParseIP("10.0.0.2").To4()
+)
+
+func TestDecode_NetFlowV5(t *testing.T) {
+	var p packet
+	p.u16(5).u16(1)
+	p.u32(60_000)                // uptime
+	p.u32(1_700_000_000).u32(0)  // unix time
+	p.u32(1).u8(0).u8(0).u16(10) // sequence, engine, and sampling
+	p.ip(testSrc).ip(testDst).ip(net.IPv4zero.To4())
+	p.u16(1).u16(2)              // interfaces
+	p.u32(3).u32(1500)           // packets and bytes
+	p.u32(50_000).u32(59_000)    // first and last
+	p.u16(1234).u16(443)         // ports
+	p.u8(0).u8(0x18).u8(6).u8(0) // padding, flags, protocol, and tos
+	p.u16(64512).u16(64513)      // AS
+	p.u8(24).u8(24).u16(0)
+
+	flows, err := newDecoder().Decode(p.b, testExporter)
+	require.NoError(t, err)
+	require.Equal(t, []flow{{
+		Type:         typeNetFlowV5,
+		Exporter:     testExporter,
+		SamplingRate: 10,
+		Bytes:        1500,
+		Packets:      3,
+		Start:        time.Unix(1_700_000_000-10, 0),
+		End:          time.Unix(1_700_000_000-1, 0),
+		EtherType:    etherTypeIPv4,
+		Protocol:     6,
+		SrcAddr:      testSrc,
+		DstAddr:      testDst,
+		SrcPort:      1234,
+		DstPort:      443,
+		TCPFlags:     0x18,
+		NextHop:      net.IPv4zero.To4(),
+		InIf:         1,
+		OutIf:        2,
+		SrcAS:        64512,
+		DstAS:        64513,
+	}}, flows)
+
+	_, err = newDecoder().Decode(p.b[:len(p.b)-1], testExporter)
+	require.ErrorIs(t, err, errTruncated)
+
+	// The record count of the header is checked against the datagram.
+	binary.BigEndian.PutUint16(p.b[2:], 2)
+	_, err = newDecoder().Decode(p.b, testExporter)
+	require.ErrorIs(t, err, errTruncated)
+	binary.BigEndian.PutUint16(p.b[2:], 65535)
+	_, err = newDecoder().Decode(p.b, testExporter)
+	require.EqualError(t, err, "NetFlow v5 datagram has 65535 records, more than the maximum of 30")
+}
+
+func TestDecode_NetFlowV9(t *testing.T) {
+	d := newDecoder()
+
+	var data packet
+	data.u16(256).u16(4 + 2*21 + 2) // data flowset, padded
+	for _, port := range []uint16{53, 123} {
+		data.ip(testSrc).ip(testDst).u16(port).u8(17).u32(100).u16(2).u32(50_000)
+	}
+	data.u16(0)
+
+	// Data records are skipped until their template is received.
+	flows, err := d.Decode(netFlowV9(1, data.b), testExporter)
+	require.ErrorIs(t, err, errMissingTemplate)
+	require.Empty(t, flows)
+
+	var tmpl packet
+	tmpl.u16(0).u16(4 + 4 + 7*4) // template flowset
+	tmpl.u16(256).u16(7)
+	tmpl.u16(ieSourceIPv4Address).u16(4)
+	tmpl.u16(ieDestinationIPv4Address).u16(4)
+	tmpl.u16(ieDestinationTransportPort).u16(2)
+	tmpl.u16(ieProtocolIdentifier).u16(1)
+	tmpl.u16(ieOctetDeltaCount).u16(4)
+	tmpl.u16(iePacketDeltaCount).u16(2) // reduced-size encoding
+	tmpl.u16(ieFlowEndSysUpTime).u16(4)
+
+	flows, err = d.Decode(netFlowV9(2, append(tmpl.b, data.b...)), testExporter)
+	require.NoError(t, err)
+	require.Len(t, flows, 2)
+	require.Equal(t, flow{
+		Type:         typeNetFlowV9,
+		Exporter:     testExporter,
+		SamplingRate: 1,
+		Bytes:        100,
+		Packets:      2,
+		Start:        time.Unix(1_700_000_000, 0), // defaults to the export time
+		End:          time.Unix(1_700_000_000-10, 0),
+		EtherType:    etherTypeIPv4,
+		Protocol:     17,
+		SrcAddr:      testSrc,
+		DstAddr:      testDst,
+		DstPort:      123,
+	}, flows[1])
+
+	// Templates are scoped to their exporter.
+	_, err = d.Decode(netFlowV9(1, data.b), net.ParseIP("192.0.2.2"))
+	require.ErrorIs(t, err, errMissingTemplate)
+}
+
+func TestDecode_IPFIX(t *testing.T) {
+	d := newDecoder()
+
+	var sets packet
+	sets.u16(2).u16(4 + 4 + 4*4 + 4) // template set
+	sets.u16(300).u16(4)
+	sets.u16(ieSourceIPv6Address).u16(16)
+	sets.u16(ieOctetDeltaCount).u16(8)
+	sets.u16(ieFlowStartMilliseconds).u16(8)
+	sets.u16(0x8000 | 1).u16(variableLength).u32(29305) // enterprise field
+	sets.u16(3).u16(4 + 6 + 2*4 + 2)                    // options template set, padded
+	sets.u16(301).u16(2).u16(1)
+	sets.u16(ieIngressInterface).u16(4) // scope
+	sets.u16(ieSamplingPacketInterval).u16(4)
+	sets.u16(0)
+
+	src := net.ParseIP("2001:db8::1")
+	sets.u16(300).u16(4 + 16 + 8 + 8 + 1 + 3) // data set
+	sets.ip(src).u64(4000).u64(1_699_999_999_000).u8(3).bytes([]byte("abc"))
+	sets.u16(301).u16(4 + 2*4) // options data set
+	sets.u32(1).u32(100)
+	sets.u16(302).u16(4) // unknown template
+
+	flows, err := d.Decode(ipfix(sets.b), testExporter)
+	require.ErrorIs(t, err, errMissingTemplate)
+	require.Equal(t, []flow{{
+		Type:         typeIPFIX,
+		Exporter:     testExporter,
+		SamplingRate: 100,
+		Bytes:        4000,
+		Start:        time.UnixMilli(1_699_999_999_000),
+		End:          time.Unix(1_700_000_000, 0), // defaults to the export time
+		EtherType:    etherTypeIPv6,
+		SrcAddr:      src,
+	}}, flows)
+
+	// Templates are withdrawn with an empty template record.
+	var withdraw packet
+	withdraw.u16(2).u16(8).u16(300).u16(0)
+	_, err = d.Decode(ipfix(withdraw.b), testExporter)
+	require.NoError(t, err)
+	_, ok := d.templates.Get(templateKey{Version: 10, Exporter: testExporter.String(), ID: 300})
+	require.False(t, ok)
+}
+
+func TestDecode_SFlow(t *testing.T) {
+	agent := net.ParseIP("192.0.2.10").To4()
+
+	var frame packet
+	frame.bytes(make([]byte, 12)).u16(etherTypeVLAN).u16(100).u16(etherTypeIPv4)
+	frame.u8(0x45).bytes(make([]byte, 8)).u8(6).u16(0).ip(testSrc).ip(testDst)
+	frame.u16(40000).u16(80).u32(0).u32(0).u8(0x50).u8(0x02).u16(0).u32(0)
+
+	var p packet
+	p.u32(5).u32(1).ip(agent).u32(0).u32(1).u32(1000).u32(3)
+
+	// Flow sample with a raw packet header and an extended gateway.
+	var sample packet
+	sample.u32(1).u32(7).u32(512).u32(0).u32(0).u32(3).u32(4).u32(2)
+	sample.u32(sFlowRawPacketHeader).u32(16 + uint32(len(frame.b)))
+	sample.u32(sFlowHeaderEthernet).u32(1514).u32(4).u32(uint32(len(frame.b))).bytes(frame.b)
+	sample.u32(sFlowExtendedGateway).u32(8 + 12 + 4 + 16 + 8)
+	sample.u32(1).ip(net.ParseIP("10.0.0.254").To4())
+	sample.u32(64500).u32(64501).u32(64502)
+	sample.u32(1).u32(2).u32(2).u32(64503).u32(64504)
+	sample.u32(0).u32(0) // communities and local preference
+	p.u32(sFlowFlowSample).u32(uint32(len(sample.b))).bytes(sample.b)
+
+	// Counter samples are skipped.
+	p.u32(2).u32(12).u32(0).u32(0).u32(0)
+
+	// Expanded flow sample with a sampled IPv4 record.
+	sample = packet{}
+	sample.u32(8).u32(0).u32(3).u32(0).u32(0).u32(0).u32(0).u32(5).u32(0).u32(6).u32(1)
+	sample.u32(sFlowSampledIPv4).u32(32)
+	sample.u32(60).u32(17).ip(testSrc).ip(testDst).u32(53).u32(5353).u32(0).u32(0)
+	p.u32(sFlowExpandedFlowSample).u32(uint32(len(sample.b))).bytes(sample.b)
+
+	flows, err := newDecoder().Decode(p.b, testExporter)
+	require.NoError(t, err)
+
+	// Flow samples don't have timestamps, so they're timed at reception.
+	for i := range flows {
+		require.False(t, flows[i].Start.IsZero())
+		require.Equal(t, flows[i].Start, flows[i].End)
+		flows[i].Start, flows[i].End = time.Time{}, time.Time{}
+	}
+	require.Equal(t, []flow{
+		{
+			Type:         typeSFlowV5,
+			Exporter:     agent,
+			SamplingRate: 512,
+			Bytes:        1514,
+			Packets:      1,
+			EtherType:    etherTypeIPv4,
+			Protocol:     6,
+			SrcAddr:      testSrc,
+			DstAddr:      testDst,
+			SrcPort:      40000,
+			DstPort:      80,
+			TCPFlags:     0x02,
+			NextHop:      net.ParseIP("10.0.0.254").To4(),
+			InIf:         3,
+			OutIf:        4,
+			SrcAS:        64501,
+			DstAS:        64504,
+		},
+		{
+			Type:         typeSFlowV5,
+			Exporter:     agent,
+			SamplingRate: 1,
+			Bytes:        60,
+			Packets:      1,
+			EtherType:    etherTypeIPv4,
+			Protocol:     17,
+			SrcAddr:      testSrc,
+			DstAddr:      testDst,
+			SrcPort:      53,
+			DstPort:      5353,
+			InIf:         5,
+			OutIf:        6,
+		},
+	}, flows)
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	tt := []struct {
+		name      string
+		datagram  []byte
+		expectErr string
+	}{
+		{"empty", nil, "datagram is truncated"},
+		{"unsupported version", []byte{0, 7, 0, 0}, "unsupported datagram version 7"},
+		{"unsupported sFlow version", []byte{0, 0, 0, 4}, "unsupported sFlow version 4"},
+		{"truncated IPFIX header", ipfix(nil)[:12], "datagram is truncated"},
+		{"invalid set length", netFlowV9(1, []byte{1, 0, 0, 2}), "negative length"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := newDecoder().Decode(tc.datagram, testExporter)
+			require.ErrorContains(t, err, tc.expectErr)
+		})
+	}
+}
+
+// netFlowV9 returns a NetFlow v9 datagram with the given flowsets, whose
+// number is count. The exporter has been up for a minute.
+func netFlowV9(count uint16, flowsets []byte) []byte {
+	var p packet
+	p.u16(9).u16(count).u32(60_000).u32(1_700_000_000).u32(1).u32(0)
+	return append(p.b, flowsets...)
+}
+
+// ipfix returns an IPFIX message with the given sets.
+func ipfix(sets []byte) []byte {
+	var p packet
+	p.u16(10).u16(uint16(16 + len(sets))).u32(1_700_000_000).u32(1).u32(0)
+	return append(p.b, sets...)
+}
+
+// packet builds datagrams in network byte order.
+type packet struct {
+	b []byte
+}
+
+func (p *packet) u8(v uint8) *packet {
+	p.b = append(p.b, v)
+	return p
+}
+
+func (p *packet) u16(v uint16) *packet {
+	p.b = binary.BigEndian.AppendUint16(p.b, v)
+	return p
+}
+
+func (p *packet) u32(v uint32) *packet {
+	p.b = binary.BigEndian.AppendUint32(p.b, v)
+	return p
+}
+
+func (p *packet) u64(v uint64) *packet {
+	p.b = binary.BigEndian.AppendUint64(p.b, v)
+	return p
+}
+
+func (p *packet) ip(ip net.IP) *packet {
+	return p.bytes(ip)
+}
+
+func (p *packet) bytes(b []byte) *packet {
+	p.b = append(p.b, b...)
+	return p
+}
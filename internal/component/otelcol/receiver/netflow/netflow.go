@@ -0,0 +1,344 @@
+// Package netflow provides an otelcol.receiver.netflow component.
+package netflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/backoff"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/fanoutconsumer"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/interceptconsumer"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/livedebuggingpublisher"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/service/livedebugging"
+	"github.com/grafana/alloy/internal/util"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.receiver.netflow",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+
+		Build: func(o component.Options, a component.Arguments) (component.Component, error) {
+			return New(o, a.(Arguments))
+		},
+	})
+}
+
+// maxDatagramSize is the maximum size of a UDP datagram.
+const maxDatagramSize = 65535
+
+// Arguments configures the otelcol.receiver.netflow component.
+type Arguments struct {
+	Endpoint         string   `alloy:"endpoint,attr,optional"`
+	MetricAttributes []string `alloy:"metric_attributes,attr,optional"`
+
+	// Output configures where to send received data. Required.
+	Output *otelcol.ConsumerArguments `alloy:"output,block"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{
+		Endpoint:         "localhost:2055",
+		MetricAttributes: []string{fieldType, fieldExporter, fieldTransport},
+	}
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	seen := make(map[string]struct{}, len(args.MetricAttributes))
+	for _, name := range args.MetricAttributes {
+		if _, ok := metricFields[name]; !ok {
+			return fmt.Errorf("unsupported metric attribute %q", name)
+		}
+		if _, ok := seen[name]; ok {
+			return fmt.Errorf("duplicate metric attribute %q", name)
+		}
+		seen[name] = struct{}{}
+	}
+	return nil
+}
+
+// Component is the otelcol.receiver.netflow component.
+type Component struct {
+	log     log.Logger
+	opts    component.Options
+	metrics *metrics
+	decoder *decoder
+
+	mut         sync.RWMutex
+	args        Arguments
+	logsSink    consumer.Logs
+	metricsSink consumer.Metrics
+	updated     chan struct{}
+
+	healthMut sync.RWMutex
+	health    component.Health
+
+	debugDataPublisher livedebugging.DebugDataPublisher
+}
+
+var (
+	_ component.Component       = (*Component)(nil)
+	_ component.HealthComponent = (*Component)(nil)
+	_ component.LiveDebugging   = (*Component)(nil)
+)
+
+type metrics struct {
+	datagramsReceived prometheus.Counter
+	datagramsDropped  *prometheus.CounterVec
+	missingTemplates  prometheus.Counter
+	flowsReceived     *prometheus.CounterVec
+}
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	m := &metrics{
+		datagramsReceived: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "otelcol_receiver_netflow_datagrams_received_total",
+			Help: "Total number of flow datagrams received.",
+		}),
+		datagramsDropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "otelcol_receiver_netflow_datagrams_dropped_total",
+			Help: "Total number of flow datagrams dropped, by reason.",
+		}, []string{"reason"}),
+		missingTemplates: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "otelcol_receiver_netflow_missing_templates_total",
+			Help: "Total number of NetFlow v9 and IPFIX data sets skipped because their template is unknown.",
+		}),
+		flowsReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "otelcol_receiver_netflow_flows_received_total",
+			Help: "Total number of flows received, by type.",
+		}, []string{"type"}),
+	}
+	m.datagramsReceived = util.MustRegisterOrGet(reg, m.datagramsReceived).(prometheus.Counter)
+	m.datagramsDropped = util.MustRegisterOrGet(reg, m.datagramsDropped).(*prometheus.CounterVec)
+	m.missingTemplates = util.MustRegisterOrGet(reg, m.missingTemplates).(prometheus.Counter)
+	m.flowsReceived = util.MustRegisterOrGet(reg, m.flowsReceived).(*prometheus.CounterVec)
+	return m
+}
+
+// New creates a new otelcol.receiver.netflow component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	debugDataPublisher, err := o.GetServiceData(livedebugging.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Component{
+		log:                o.Logger,
+		opts:               o,
+		metrics:            newMetrics(o.Registerer),
+		decoder:            newDecoder(),
+		updated:            make(chan struct{}, 1),
+		debugDataPublisher: debugDataPublisher.(livedebugging.DebugDataPublisher),
+	}
+	if err := c.Update(args); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Run implements component.Component. The listener is restarted whenever the
+// arguments change. The templates of the exporters are kept across restarts.
+func (c *Component) Run(ctx context.Context) error {
+	for {
+		c.mut.RLock()
+		args := c.args
+		c.mut.RUnlock()
+
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			c.runListener(runCtx, args)
+		}()
+
+		select {
+		case <-ctx.Done():
+			cancel()
+			<-done
+			return nil
+		case <-c.updated:
+			cancel()
+			<-done
+		}
+	}
+}
+
+// Update implements component.Component.
+func (c *Component) Update(newConfig component.Arguments) error {
+	args := newConfig.(Arguments)
+
+	var logsSink consumer.Logs
+	if nextLogs := args.Output.Logs; len(nextLogs) > 0 {
+		fanout := fanoutconsumer.Logs(nextLogs)
+		logsSink = interceptconsumer.Logs(fanout,
+			func(ctx context.Context, ld plog.Logs) error {
+				livedebuggingpublisher.PublishLogsIfActive(c.debugDataPublisher, c.opts.ID, ld, otelcol.GetComponentMetadata(nextLogs))
+				return fanout.ConsumeLogs(ctx, ld)
+			},
+		)
+	}
+
+	var metricsSink consumer.Metrics
+	if nextMetrics := args.Output.Metrics; len(nextMetrics) > 0 {
+		fanout := fanoutconsumer.Metrics(nextMetrics)
+		metricsSink = interceptconsumer.Metrics(fanout,
+			func(ctx context.Context, md pmetric.Metrics) error {
+				livedebuggingpublisher.PublishMetricsIfActive(c.debugDataPublisher, c.opts.ID, md, otelcol.GetComponentMetadata(nextMetrics))
+				return fanout.ConsumeMetrics(ctx, md)
+			},
+		)
+	}
+
+	c.mut.Lock()
+	c.args = args
+	c.logsSink = logsSink
+	c.metricsSink = metricsSink
+	c.mut.Unlock()
+
+	select {
+	case c.updated <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// listenBackoff is the backoff between the attempts to listen for flows.
+var listenBackoff = backoff.Config{
+	MinBackoff: 100 * time.Millisecond,
+	MaxBackoff: 10 * time.Second,
+}
+
+// runListener receives datagrams until the context is canceled. The listener
+// is restarted with a backoff when binding the endpoint or reading from it
+// fails.
+func (c *Component) runListener(ctx context.Context, args Arguments) {
+	bo := backoff.New(ctx, listenBackoff)
+	for {
+		err := c.listen(ctx, args, bo.Reset)
+		if ctx.Err() != nil {
+			return
+		}
+		level.Error(c.log).Log("msg", "flow listener failed, retrying", "endpoint", args.Endpoint, "err", err, "num_retries", bo.NumRetries())
+		c.setHealth(err)
+
+		bo.Wait()
+		if !bo.Ongoing() {
+			return
+		}
+	}
+}
+
+// listen receives datagrams until the context is canceled or reading fails.
+// ready is called once the endpoint is bound.
+func (c *Component) listen(ctx context.Context, args Arguments, ready func()) error {
+	conn, err := net.ListenPacket("udp", args.Endpoint)
+	if err != nil {
+		return fmt.Errorf("failed to listen for flows: %w", err)
+	}
+	level.Info(c.log).Log("msg", "listening for flows", "endpoint", args.Endpoint)
+	c.setHealth(nil)
+	ready()
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		conn.Close()
+	}()
+
+	buf := make([]byte, maxDatagramSize)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return fmt.Errorf("flow listener stopped: %w", err)
+		}
+		c.handleDatagram(ctx, buf[:n], addr.(*net.UDPAddr))
+	}
+}
+
+func (c *Component) handleDatagram(ctx context.Context, b []byte, addr *net.UDPAddr) {
+	c.metrics.datagramsReceived.Inc()
+
+	flows, err := c.decoder.Decode(b, addr.IP)
+	switch {
+	case errors.Is(err, errMissingTemplate):
+		level.Debug(c.log).Log("msg", "skipping data records with unknown template", "addr", addr)
+		c.metrics.missingTemplates.Inc()
+	case err != nil:
+		level.Debug(c.log).Log("msg", "dropping invalid flow datagram", "addr", addr, "err", err)
+		c.metrics.datagramsDropped.WithLabelValues("invalid").Inc()
+		return
+	}
+	if len(flows) == 0 {
+		return
+	}
+	for _, f := range flows {
+		c.metrics.flowsReceived.WithLabelValues(f.Type).Inc()
+	}
+
+	c.mut.RLock()
+	args, logsSink, metricsSink := c.args, c.logsSink, c.metricsSink
+	c.mut.RUnlock()
+
+	now := time.Now()
+	var consumeErr error
+	if logsSink != nil {
+		consumeErr = errors.Join(consumeErr, logsSink.ConsumeLogs(ctx, convertLogs(flows, now)))
+	}
+	if metricsSink != nil {
+		consumeErr = errors.Join(consumeErr, metricsSink.ConsumeMetrics(ctx, convertMetrics(flows, args.MetricAttributes, now)))
+	}
+	if consumeErr != nil {
+		level.Error(c.log).Log("msg", "failed to consume flows", "err", consumeErr)
+		c.metrics.datagramsDropped.WithLabelValues("consumer_error").Inc()
+	}
+}
+
+func (c *Component) setHealth(err error) {
+	c.healthMut.Lock()
+	defer c.healthMut.Unlock()
+
+	if err == nil {
+		c.health = component.Health{
+			Health:     component.HealthTypeHealthy,
+			Message:    "listening for flows",
+			UpdateTime: time.Now(),
+		}
+	} else {
+		c.health = component.Health{
+			Health:     component.HealthTypeUnhealthy,
+			Message:    err.Error(),
+			UpdateTime: time.Now(),
+		}
+	}
+}
+
+// CurrentHealth implements component.HealthComponent.
+func (c *Component) CurrentHealth() component.Health {
+	c.healthMut.RLock()
+	defer c.healthMut.RUnlock()
+	return c.health
+}
+
+// LiveDebugging implements component.LiveDebugging.
+func (c *Component) LiveDebugging() {}
@@ -0,0 +1,209 @@
+package netflow
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/fakeconsumer"
+	"github.com/grafana/alloy/internal/runtime/componenttest"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax"
+)
+
+func Test(t *testing.T) {
+	port := freePort(t)
+
+	cfg := fmt.Sprintf(`
+		endpoint          = "127.0.0.1:%d"
+		metric_attributes = ["flow.type", "source.address"]
+
+		output {
+			// no-op: will be overridden by test code.
+		}
+	`, port)
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(cfg), &args))
+
+	logCh := make(chan plog.Logs, 10)
+	metricCh := make(chan pmetric.Metrics, 10)
+	args.Output = makeOutput(logCh, metricCh)
+
+	ctx := componenttest.TestContext(t)
+	ctrl, err := componenttest.NewControllerFromID(util.TestLogger(t), "otelcol.receiver.netflow")
+	require.NoError(t, err)
+	go func() {
+		require.NoError(t, ctrl.Run(ctx, args))
+	}()
+	require.NoError(t, ctrl.WaitRunning(time.Second))
+
+	conn, err := net.Dial("udp", fmt.Sprintf("127.0.0.1:%d", port))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	var p packet
+	p.u16(5).u16(2).u32(60_000).u32(1_700_000_000).u32(0).u32(1).u32(0)
+	for range 2 {
+		p.ip(testSrc).ip(testDst).u32(0).u32(0)
+		p.u32(2).u32(120).u32(50_000).u32(59_000)
+		p.u16(1234).u16(53).u8(0).u8(0).u8(17).u8(0)
+		p.u32(0).u32(0)
+	}
+
+	// The listener may not be ready yet, so the datagram is sent until the
+	// flows are received.
+	var logs plog.Logs
+	require.Eventually(t, func() bool {
+		_, err := conn.Write(p.b)
+		require.NoError(t, err)
+		select {
+		case logs = <-logCh:
+			return true
+		case <-time.After(100 * time.Millisecond):
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.Equal(t, 2, logs.LogRecordCount())
+	lr := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	require.Equal(t, typeNetFlowV5, lr.Attributes().AsRaw()[fieldType])
+	require.Equal(t, "127.0.0.1", lr.Attributes().AsRaw()[fieldExporter])
+	require.Equal(t, "udp", lr.Body().Map().AsRaw()[fieldTransport])
+
+	select {
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "failed waiting for metrics")
+	case metrics := <-metricCh:
+		m := metrics.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+		require.Equal(t, metricBytes, m.Name())
+		require.Equal(t, 1, m.Sum().DataPoints().Len())
+		point := m.Sum().DataPoints().At(0)
+		require.Equal(t, int64(240), point.IntValue())
+		require.Equal(t, map[string]any{
+			fieldType:    typeNetFlowV5,
+			fieldSrcAddr: "10.0.0.1",
+		}, point.Attributes().AsRaw())
+	}
+}
+
+func TestListenerRetry(t *testing.T) {
+	// The endpoint is taken when the component starts.
+	taken, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	endpoint := taken.LocalAddr().String()
+
+	var args Arguments
+	args.SetToDefault()
+	args.Endpoint = endpoint
+	args.Output = makeOutput(make(chan plog.Logs, 10), make(chan pmetric.Metrics, 10))
+
+	ctx := componenttest.TestContext(t)
+	ctrl, err := componenttest.NewControllerFromID(util.TestLogger(t), "otelcol.receiver.netflow")
+	require.NoError(t, err)
+	go func() {
+		require.NoError(t, ctrl.Run(ctx, args))
+	}()
+	require.NoError(t, ctrl.WaitRunning(time.Second))
+
+	comp, err := ctrl.GetComponent()
+	require.NoError(t, err)
+	health := func() component.HealthType {
+		return comp.(component.HealthComponent).CurrentHealth().Health
+	}
+	require.Eventually(t, func() bool {
+		return health() == component.HealthTypeUnhealthy
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The listener binds the endpoint once it's released.
+	require.NoError(t, taken.Close())
+	require.Eventually(t, func() bool {
+		return health() == component.HealthTypeHealthy
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestArguments(t *testing.T) {
+	tt := []struct {
+		name      string
+		cfg       string
+		expectErr string
+	}{
+		{
+			name: "defaults",
+			cfg:  `output {}`,
+		},
+		{
+			name: "unsupported metric attribute",
+			cfg: `
+				metric_attributes = ["flow.bytes"]
+				output {}
+			`,
+			expectErr: `unsupported metric attribute "flow.bytes"`,
+		},
+		{
+			name: "duplicate metric attribute",
+			cfg: `
+				metric_attributes = ["source.address", "source.address"]
+				output {}
+			`,
+			expectErr: `duplicate metric attribute "source.address"`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			if tc.expectErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectErr)
+			}
+		})
+	}
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+// makeOutput returns a ConsumerArguments which will forward logs and metrics
+// to the provided channels.
+func makeOutput(logCh chan plog.Logs, metricCh chan pmetric.Metrics) *otelcol.ConsumerArguments {
+	logsConsumer := fakeconsumer.Consumer{
+		ConsumeLogsFunc: func(ctx context.Context, l plog.Logs) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case logCh <- l:
+				return nil
+			}
+		},
+	}
+	metricsConsumer := fakeconsumer.Consumer{
+		ConsumeMetricsFunc: func(ctx context.Context, m pmetric.Metrics) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case metricCh <- m:
+				return nil
+			}
+		},
+	}
+
+	return &otelcol.ConsumerArguments{
+		Logs:    []otelcol.Consumer{&logsConsumer},
+		Metrics: []otelcol.Consumer{&metricsConsumer},
+	}
+}
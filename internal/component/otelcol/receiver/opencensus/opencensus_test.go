@@ -1,19 +1,36 @@
 package opencensus_test
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
+	commonpb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/common/v1"
+	agenttracepb "github.com/census-instrumentation/opencensus-proto/gen-go/agent/trace/v1"
+	tracepb "github.com/census-instrumentation/opencensus-proto/gen-go/trace/v1"
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
 	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/fakeconsumer"
+	"github.com/grafana/alloy/internal/component/otelcol/receiver"
 	"github.com/grafana/alloy/internal/component/otelcol/receiver/opencensus"
 	"github.com/grafana/alloy/internal/runtime/componenttest"
+	"github.com/grafana/alloy/internal/service/livedebugging"
 	"github.com/grafana/alloy/internal/util"
 	"github.com/grafana/alloy/syntax"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/opencensusreceiver"
 	"github.com/phayes/freeport"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
 )
 
 // Test ensures that otelcol.receiver.opencensus can start successfully.
@@ -78,6 +95,18 @@ func TestArguments_UnmarshalAlloy(t *testing.T) {
 
 		endpoint = "%s"
 		transport = "tcp"
+		max_recv_msg_size = "1MiB"
+
+		keepalive {
+			server_parameters {
+				max_connection_idle = "1m"
+				time                = "30s"
+			}
+			enforcement_policy {
+				min_time              = "10s"
+				permit_without_stream = true
+			}
+		}
 
 		output { /* no-op */ }
 	`, httpAddr)
@@ -99,6 +128,88 @@ func TestArguments_UnmarshalAlloy(t *testing.T) {
 	require.Equal(t, len(otelArgs.CorsOrigins), 2)
 	require.Equal(t, otelArgs.CorsOrigins[0], "https://*.test.com")
 	require.Equal(t, otelArgs.CorsOrigins[1], "https://test.com")
+
+	// Check the message size and keepalive arguments
+	require.Equal(t, otelArgs.MaxRecvMsgSizeMiB, 1)
+	require.Equal(t, otelArgs.Keepalive.ServerParameters.MaxConnectionIdle, time.Minute)
+	require.Equal(t, otelArgs.Keepalive.ServerParameters.Time, 30*time.Second)
+	require.Equal(t, otelArgs.Keepalive.EnforcementPolicy.MinTime, 10*time.Second)
+	require.Equal(t, otelArgs.Keepalive.EnforcementPolicy.PermitWithoutStream, true)
+}
+
+// TestDebugMetrics ensures that otelcol.receiver.opencensus exposes metrics
+// about the requests it receives.
+func TestDebugMetrics(t *testing.T) {
+	httpAddr := getFreeAddr(t)
+
+	var args opencensus.Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(fmt.Sprintf(`
+		endpoint = "%s"
+
+		output { /* no-op */ }
+	`, httpAddr)), &args))
+	args.Output = &otelcol.ConsumerArguments{
+		Traces: []otelcol.Consumer{&fakeconsumer.Consumer{
+			ConsumeTracesFunc: func(context.Context, ptrace.Traces) error { return nil },
+		}},
+	}
+
+	reg := prometheus.NewRegistry()
+	c, err := receiver.New(component.Options{
+		ID:         "otelcol.receiver.opencensus.test",
+		Logger:     util.TestAlloyLogger(t),
+		Tracer:     noop.NewTracerProvider(),
+		Registerer: reg,
+		GetServiceData: func(name string) (interface{}, error) {
+			return livedebugging.NewLiveDebugging(), nil
+		},
+	}, opencensusreceiver.NewFactory(), args)
+	require.NoError(t, err)
+
+	// Wait for the receiver to shut down so that its server is stopped before
+	// other tests run.
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		require.NoError(t, c.Run(ctx))
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	conn, err := grpc.NewClient(httpAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	var stream agenttracepb.TraceService_ExportClient
+	require.Eventually(t, func() bool {
+		stream, err = agenttracepb.NewTraceServiceClient(conn).Export(t.Context())
+		return err == nil
+	}, 5*time.Second, 50*time.Millisecond)
+	require.NoError(t, stream.Send(&agenttracepb.ExportTraceServiceRequest{
+		Node: &commonpb.Node{ServiceInfo: &commonpb.ServiceInfo{Name: "test"}},
+		Spans: []*tracepb.Span{{
+			TraceId: []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+			SpanId:  []byte{1, 2, 3, 4, 5, 6, 7, 8},
+			Name:    &tracepb.TruncatableString{Value: "get"},
+		}},
+	}))
+	require.NoError(t, stream.CloseSend())
+	// Wait for the stream to end so that the RPC is recorded.
+	_, err = stream.Recv()
+	require.ErrorIs(t, err, io.EOF)
+
+	expected := `
+		# HELP otelcol_receiver_accepted_spans_total Number of spans successfully pushed into the pipeline. [alpha]
+		# TYPE otelcol_receiver_accepted_spans_total counter
+		otelcol_receiver_accepted_spans_total{otel_scope_name="go.opentelemetry.io/collector/receiver/receiverhelper",otel_scope_version="",receiver="opencensus/otelcol.receiver.opencensus.test",transport="grpc"} 1
+	`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "otelcol_receiver_accepted_spans_total"))
+	count, err := testutil.GatherAndCount(reg, "rpc.server.duration_milliseconds")
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
 }
 
 func getFreeAddr(t *testing.T) string {
@@ -0,0 +1,159 @@
+package snmptrap
+
+import (
+	"encoding/hex"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/gosnmp/gosnmp"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+)
+
+const (
+	// snmpTrapOID is the OID of the varbind holding the OID of the
+	// notification in SNMPv2c and SNMPv3 traps.
+	snmpTrapOID = "1.3.6.1.6.3.1.1.4.1.0"
+	// genericTrapPrefix prefixes the OIDs of the generic SNMPv1 traps, see
+	// RFC 3584.
+	genericTrapPrefix = "1.3.6.1.6.3.1.1.5."
+	// enterpriseSpecificTrap is the generic trap number of the SNMPv1 traps
+	// defined by an enterprise.
+	enterpriseSpecificTrap = 6
+)
+
+// Attributes of the log records.
+const (
+	attrVersion      = "snmp.version"
+	attrTrapOID      = "snmp.trap.oid"
+	attrTrapName     = "snmp.trap.name"
+	attrEnterprise   = "snmp.trap.enterprise"
+	attrAgentAddress = "snmp.agent.address"
+	attrUser         = "snmp.user"
+	attrPeerAddress  = "network.peer.address"
+	attrPeerPort     = "network.peer.port"
+)
+
+// scopeName is the name of the instrumentation scope of the log records.
+const scopeName = "github.com/grafana/alloy/otelcol/receiver/snmptrap"
+
+// convertTrap converts a trap received from addr to a log record. The body of
+// the log record maps the names of the varbinds to their values.
+func convertTrap(trap *gosnmp.SnmpPacket, addr *net.UDPAddr, m *mibs, now time.Time) plog.Logs {
+	logs := plog.NewLogs()
+	scopeLogs := logs.ResourceLogs().AppendEmpty().ScopeLogs().AppendEmpty()
+	scopeLogs.Scope().SetName(scopeName)
+
+	lr := scopeLogs.LogRecords().AppendEmpty()
+	lr.SetTimestamp(pcommon.NewTimestampFromTime(now))
+	lr.SetObservedTimestamp(pcommon.NewTimestampFromTime(now))
+
+	attrs := lr.Attributes()
+	attrs.PutStr(attrVersion, trap.Version.String())
+	if addr != nil {
+		attrs.PutStr(attrPeerAddress, addr.IP.String())
+		attrs.PutInt(attrPeerPort, int64(addr.Port))
+	}
+	if usm, ok := trap.SecurityParameters.(*gosnmp.UsmSecurityParameters); ok && trap.Version == gosnmp.Version3 {
+		attrs.PutStr(attrUser, usm.UserName)
+	}
+
+	var trapOID string
+	if trap.Version == gosnmp.Version1 {
+		trapOID = v1TrapOID(trap)
+		attrs.PutStr(attrEnterprise, m.Name(trap.Enterprise))
+		attrs.PutStr(attrAgentAddress, trap.AgentAddress)
+	}
+
+	body := lr.Body().SetEmptyMap()
+	for _, pdu := range trap.Variables {
+		if strings.TrimPrefix(pdu.Name, ".") == snmpTrapOID {
+			if oid, ok := pdu.Value.(string); ok {
+				trapOID = strings.TrimPrefix(oid, ".")
+			}
+			continue
+		}
+		varbindValue(pdu, m, body.PutEmpty(m.Name(pdu.Name)))
+	}
+
+	if trapOID != "" {
+		attrs.PutStr(attrTrapOID, trapOID)
+		attrs.PutStr(attrTrapName, m.Name(trapOID))
+	}
+	return logs
+}
+
+// v1TrapOID returns the OID of the notification of an SNMPv1 trap, as defined
+// in RFC 3584.
+func v1TrapOID(trap *gosnmp.SnmpPacket) string {
+	if trap.GenericTrap == enterpriseSpecificTrap {
+		return strings.TrimPrefix(trap.Enterprise, ".") + ".0." + strconv.Itoa(trap.SpecificTrap)
+	}
+	return genericTrapPrefix + strconv.Itoa(trap.GenericTrap+1)
+}
+
+// varbindValue sets v to the value of a varbind. Integers are replaced by the
+// name of their value when their object defines an enumeration.
+func varbindValue(pdu gosnmp.SnmpPDU, m *mibs, v pcommon.Value) {
+	switch pdu.Type {
+	case gosnmp.Integer:
+		n := gosnmp.ToBigInt(pdu.Value).Int64()
+		if name, ok := m.Enum(pdu.Name, n); ok {
+			v.SetStr(name)
+		} else {
+			v.SetInt(n)
+		}
+
+	case gosnmp.Counter32, gosnmp.Counter64, gosnmp.Gauge32, gosnmp.TimeTicks, gosnmp.Uinteger32:
+		n := gosnmp.ToBigInt(pdu.Value)
+		if n.IsInt64() {
+			v.SetInt(n.Int64())
+		} else {
+			v.SetStr(n.String())
+		}
+
+	case gosnmp.OpaqueFloat:
+		if f, ok := pdu.Value.(float32); ok {
+			v.SetDouble(float64(f))
+		}
+
+	case gosnmp.OpaqueDouble:
+		if f, ok := pdu.Value.(float64); ok {
+			v.SetDouble(f)
+		}
+
+	case gosnmp.OctetString:
+		if b, ok := pdu.Value.([]byte); ok {
+			v.SetStr(octetString(b))
+		}
+
+	case gosnmp.ObjectIdentifier:
+		if oid, ok := pdu.Value.(string); ok {
+			v.SetStr(m.Name(oid))
+		}
+
+	case gosnmp.IPAddress:
+		if ip, ok := pdu.Value.(string); ok {
+			v.SetStr(ip)
+		}
+
+	default:
+		// Null values and exceptions such as noSuchObject are left empty.
+	}
+}
+
+// octetString returns the printable octet strings as is, and the other ones
+// as hexadecimal.
+func octetString(b []byte) string {
+	s := string(b)
+	if utf8.ValidString(s) && strings.IndexFunc(s, func(r rune) bool {
+		return !unicode.IsPrint(r) && !unicode.IsSpace(r)
+	}) < 0 {
+		return s
+	}
+	return "0x" + hex.EncodeToString(b)
+}
@@ -0,0 +1,77 @@
+package snmptrap
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConvertTrap_V1(t *testing.T) {
+	m, err := loadMIBs([]string{"testdata"})
+	require.NoError(t, err)
+
+	tt := []struct {
+		name         string
+		genericTrap  int
+		specificTrap int
+		expectOID    string
+		expectName   string
+	}{
+		{
+			name:        "generic",
+			genericTrap: 2,
+			expectOID:   "1.3.6.1.6.3.1.1.5.3",
+			expectName:  "linkDown",
+		},
+		{
+			name:         "enterprise specific",
+			genericTrap:  6,
+			specificTrap: 7,
+			expectOID:    "1.3.6.1.4.1.99999.0.7",
+			expectName:   "exampleV1Trap",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			trap := &gosnmp.SnmpPacket{
+				Version:   gosnmp.Version1,
+				Community: "public",
+				Variables: []gosnmp.SnmpPDU{
+					{Name: ".1.3.6.1.2.1.2.2.1.1.3", Type: gosnmp.Integer, Value: 3},
+					{Name: ".1.3.6.1.4.1.99999.1.1.2.0", Type: gosnmp.OctetString, Value: []byte{0x00, 0xff}},
+					{Name: ".1.3.6.1.4.1.99999.1.1.3.0", Type: gosnmp.Null},
+				},
+				SnmpTrap: gosnmp.SnmpTrap{
+					Enterprise:   ".1.3.6.1.4.1.99999",
+					AgentAddress: "10.0.0.1",
+					GenericTrap:  tc.genericTrap,
+					SpecificTrap: tc.specificTrap,
+				},
+			}
+			addr := &net.UDPAddr{IP: net.ParseIP("10.0.0.2"), Port: 1162}
+
+			logs := convertTrap(trap, addr, m, time.Unix(0, 0))
+			require.Equal(t, 1, logs.LogRecordCount())
+			lr := logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+
+			require.Equal(t, map[string]any{
+				attrVersion:      "1",
+				attrEnterprise:   "example",
+				attrAgentAddress: "10.0.0.1",
+				attrPeerAddress:  "10.0.0.2",
+				attrPeerPort:     int64(1162),
+				attrTrapOID:      tc.expectOID,
+				attrTrapName:     tc.expectName,
+			}, lr.Attributes().AsRaw())
+			require.Equal(t, map[string]any{
+				"ifIndex.3":          int64(3),
+				"exampleMessage.0":   "0x00ff",
+				"exampleObjects.3.0": nil,
+			}, lr.Body().Map().AsRaw())
+		})
+	}
+}
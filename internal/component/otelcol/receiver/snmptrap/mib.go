@@ -0,0 +1,410 @@
+package snmptrap
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// builtinOIDs holds the objects of the SMI and of the MIB modules which are
+// used by most traps, so that they're resolved without loading any MIB file.
+var builtinOIDs = map[string]string{
+	"0":       "ccitt",
+	"1":       "iso",
+	"2":       "joint-iso-ccitt",
+	"1.3":     "org",
+	"1.3.6":   "dod",
+	"1.3.6.1": "internet",
+
+	"1.3.6.1.1":       "directory",
+	"1.3.6.1.2":       "mgmt",
+	"1.3.6.1.2.1":     "mib-2",
+	"1.3.6.1.2.1.10":  "transmission",
+	"1.3.6.1.3":       "experimental",
+	"1.3.6.1.4":       "private",
+	"1.3.6.1.4.1":     "enterprises",
+	"1.3.6.1.5":       "security",
+	"1.3.6.1.6":       "snmpV2",
+	"1.3.6.1.6.1":     "snmpDomains",
+	"1.3.6.1.6.2":     "snmpProxys",
+	"1.3.6.1.6.3":     "snmpModules",
+	"1.3.6.1.2.1.1":   "system",
+	"1.3.6.1.2.1.1.1": "sysDescr",
+	"1.3.6.1.2.1.1.2": "sysObjectID",
+	"1.3.6.1.2.1.1.3": "sysUpTime",
+	"1.3.6.1.2.1.1.4": "sysContact",
+	"1.3.6.1.2.1.1.5": "sysName",
+	"1.3.6.1.2.1.1.6": "sysLocation",
+
+	"1.3.6.1.2.1.2":       "interfaces",
+	"1.3.6.1.2.1.2.2":     "ifTable",
+	"1.3.6.1.2.1.2.2.1":   "ifEntry",
+	"1.3.6.1.2.1.2.2.1.1": "ifIndex",
+	"1.3.6.1.2.1.2.2.1.2": "ifDescr",
+	"1.3.6.1.2.1.2.2.1.7": "ifAdminStatus",
+	"1.3.6.1.2.1.2.2.1.8": "ifOperStatus",
+
+	"1.3.6.1.6.3.1.1.4.1":  "snmpTrapOID",
+	"1.3.6.1.6.3.1.1.4.3":  "snmpTrapEnterprise",
+	"1.3.6.1.6.3.1.1.5.1":  "coldStart",
+	"1.3.6.1.6.3.1.1.5.2":  "warmStart",
+	"1.3.6.1.6.3.1.1.5.3":  "linkDown",
+	"1.3.6.1.6.3.1.1.5.4":  "linkUp",
+	"1.3.6.1.6.3.1.1.5.5":  "authenticationFailure",
+	"1.3.6.1.6.3.1.1.5.6":  "egpNeighborLoss",
+	"1.3.6.1.6.3.18.1.3.0": "snmpTrapAddress",
+	"1.3.6.1.6.3.18.1.4.0": "snmpTrapCommunity",
+}
+
+// mibs resolves OIDs to the names of the objects defined in MIB modules, and
+// integer values to the names of their enumerations.
+type mibs struct {
+	// names holds the name of each known OID, without a leading dot.
+	names map[string]string
+	// enums holds the enumerations of the integer objects, by OID.
+	enums map[string]map[int64]string
+}
+
+// definition is an object definition parsed from a MIB module. Its OID is the
+// OID of parent followed by the sub-identifiers.
+type definition struct {
+	parent string
+	subIDs []string
+	enum   map[int64]string
+}
+
+// loadMIBs parses the MIB modules found in paths, which are either files or
+// directories of files. Only the object definitions are parsed; the imports
+// of the modules aren't checked, so the modules can be loaded in any order.
+func loadMIBs(paths []string) (*mibs, error) {
+	defs := make(map[string]definition)
+	for _, path := range paths {
+		files, err := mibFiles(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read MIB file: %w", err)
+			}
+			parseMIB(string(content), defs)
+		}
+	}
+
+	m := &mibs{
+		names: make(map[string]string, len(builtinOIDs)+len(defs)),
+		enums: make(map[string]map[int64]string),
+	}
+	oids := make(map[string]string, len(builtinOIDs)+len(defs))
+	for oid, name := range builtinOIDs {
+		m.names[oid] = name
+		oids[name] = oid
+	}
+
+	// Definitions can reference parents defined later or in other modules, so
+	// they're resolved once every module is parsed.
+	var resolve func(name string, depth int) (string, bool)
+	resolve = func(name string, depth int) (string, bool) {
+		if oid, ok := oids[name]; ok {
+			return oid, true
+		}
+		def, ok := defs[name]
+		// The depth guards against cycles in invalid modules.
+		if !ok || depth > 128 {
+			return "", false
+		}
+
+		parts := def.subIDs
+		if def.parent != "" {
+			parentOID, ok := resolve(def.parent, depth+1)
+			if !ok {
+				return "", false
+			}
+			parts = append([]string{parentOID}, parts...)
+		}
+		oid := strings.Join(parts, ".")
+		oids[name] = oid
+		return oid, true
+	}
+	for name, def := range defs {
+		oid, ok := resolve(name, 0)
+		if !ok {
+			continue
+		}
+		m.names[oid] = name
+		if len(def.enum) > 0 {
+			m.enums[oid] = def.enum
+		}
+	}
+	return m, nil
+}
+
+func mibFiles(path string) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read MIB path: %w", err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read MIB directory: %w", err)
+	}
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			files = append(files, filepath.Join(path, entry.Name()))
+		}
+	}
+	return files, nil
+}
+
+// macros are the SMI macros which assign an OID to the object they define.
+var macros = map[string]bool{
+	"AGENT-CAPABILITIES": true,
+	"MODULE-COMPLIANCE":  true,
+	"MODULE-IDENTITY":    true,
+	"NOTIFICATION-GROUP": true,
+	"NOTIFICATION-TYPE":  true,
+	"OBJECT-GROUP":       true,
+	"OBJECT-IDENTITY":    true,
+	"OBJECT-TYPE":        true,
+	"TRAP-TYPE":          true,
+}
+
+// parseMIB adds the object definitions of a MIB module to defs.
+func parseMIB(content string, defs map[string]definition) {
+	tokens := tokenizeMIB(content)
+	for i := 0; i < len(tokens)-1; i++ {
+		name := tokens[i]
+		if !isValueName(name) {
+			continue
+		}
+
+		switch {
+		case tokens[i+1] == "OBJECT" && i+4 < len(tokens) && tokens[i+2] == "IDENTIFIER" && tokens[i+3] == "::=":
+			if def, end, ok := parseOIDValue(tokens, i+4); ok {
+				defs[name] = def
+				i = end
+			}
+
+		case macros[tokens[i+1]]:
+			def, end, ok := parseMacro(tokens, i+1)
+			if ok {
+				defs[name] = def
+			}
+			i = end
+		}
+	}
+}
+
+// parseMacro parses the clauses of a macro starting at tokens[start] up to
+// its value. It returns the index of the last token of the macro.
+func parseMacro(tokens []string, start int) (definition, int, bool) {
+	var (
+		enum       map[int64]string
+		enterprise string
+	)
+	for i := start + 1; i < len(tokens); i++ {
+		switch tokens[i] {
+		case "SYNTAX":
+			// Only the enumerations of integers are kept, for example:
+			// SYNTAX INTEGER { up(1), down(2) }
+			if i+2 < len(tokens) && tokens[i+1] == "INTEGER" && tokens[i+2] == "{" {
+				enum, i = parseEnum(tokens, i+2)
+			}
+
+		case "ENTERPRISE":
+			if i+1 < len(tokens) {
+				enterprise = tokens[i+1]
+			}
+
+		case "::=":
+			if tokens[start] == "TRAP-TYPE" {
+				// The OID of the notification of an SMIv1 trap is the enterprise
+				// followed by 0 and the specific trap number, see RFC 3584.
+				if i+1 < len(tokens) && enterprise != "" && isNumber(tokens[i+1]) {
+					return definition{parent: enterprise, subIDs: []string{"0", tokens[i+1]}}, i + 1, true
+				}
+				return definition{}, i, false
+			}
+
+			def, end, ok := parseOIDValue(tokens, i+1)
+			def.enum = enum
+			return def, end, ok
+
+		default:
+			// A new definition starts without the previous one having a value.
+			if macros[tokens[i]] && i > start+1 {
+				return definition{}, i - 2, false
+			}
+		}
+	}
+	return definition{}, len(tokens), false
+}
+
+// parseEnum parses the named numbers of an enumeration, starting at the
+// opening brace in tokens[start]. It returns the index of the closing brace.
+func parseEnum(tokens []string, start int) (map[int64]string, int) {
+	enum := make(map[int64]string)
+	for i := start + 1; i < len(tokens); i++ {
+		if tokens[i] == "}" {
+			return enum, i
+		}
+		if i+3 < len(tokens) && tokens[i+1] == "(" && tokens[i+3] == ")" {
+			if n, err := strconv.ParseInt(tokens[i+2], 10, 64); err == nil {
+				enum[n] = tokens[i]
+			}
+			i += 3
+		}
+	}
+	return enum, len(tokens)
+}
+
+// parseOIDValue parses an OID value such as { ifEntry 8 } or
+// { iso org(3) dod(6) 1 }, starting at the opening brace in tokens[start].
+// It returns the index of the closing brace.
+func parseOIDValue(tokens []string, start int) (definition, int, bool) {
+	if start >= len(tokens) || tokens[start] != "{" {
+		return definition{}, start, false
+	}
+
+	var def definition
+	for i := start + 1; i < len(tokens); i++ {
+		switch token := tokens[i]; {
+		case token == "}":
+			return def, i, def.parent != "" || len(def.subIDs) > 0
+		case isNumber(token):
+			def.subIDs = append(def.subIDs, token)
+		case i+3 < len(tokens) && tokens[i+1] == "(" && isNumber(tokens[i+2]) && tokens[i+3] == ")":
+			// A named number is equivalent to its number.
+			def.subIDs = append(def.subIDs, tokens[i+2])
+			i += 3
+		case i == start+1:
+			def.parent = token
+		default:
+			return definition{}, i, false
+		}
+	}
+	return definition{}, len(tokens), false
+}
+
+// tokenizeMIB splits a MIB module into tokens, without comments and quoted
+// strings.
+func tokenizeMIB(content string) []string {
+	var (
+		tokens []string
+		runes  = []rune(content)
+	)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			// Comments end at the end of the line or at the next "--".
+			i += 2
+			for i < len(runes) && runes[i] != '\n' {
+				if runes[i] == '-' && i+1 < len(runes) && runes[i+1] == '-' {
+					i += 2
+					break
+				}
+				i++
+			}
+
+		case r == '"':
+			i++
+			for i < len(runes) && runes[i] != '"' {
+				i++
+			}
+			i++
+
+		case r == ':' && i+2 < len(runes) && runes[i+1] == ':' && runes[i+2] == '=':
+			tokens = append(tokens, "::=")
+			i += 3
+
+		case isIdentifierRune(r):
+			start := i
+			for i < len(runes) && isIdentifierRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, string(runes[start:i]))
+
+		default:
+			tokens = append(tokens, string(r))
+			i++
+		}
+	}
+	return tokens
+}
+
+func isIdentifierRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_'
+}
+
+// isValueName reports whether token can be the name of a value, which starts
+// with a lowercase letter.
+func isValueName(token string) bool {
+	return token != "" && unicode.IsLower(rune(token[0]))
+}
+
+func isNumber(token string) bool {
+	if token == "" {
+		return false
+	}
+	for _, r := range token {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Name returns the name of the object with the longest OID prefixing oid,
+// followed by the remaining sub-identifiers, for example ifOperStatus.3. The
+// numeric OID is returned if no prefix is known.
+func (m *mibs) Name(oid string) string {
+	oid = strings.TrimPrefix(oid, ".")
+	for prefix := oid; prefix != ""; {
+		if name, ok := m.names[prefix]; ok {
+			if prefix == oid {
+				return name
+			}
+			return name + oid[len(prefix):]
+		}
+
+		i := strings.LastIndexByte(prefix, '.')
+		if i < 0 {
+			break
+		}
+		prefix = prefix[:i]
+	}
+	return oid
+}
+
+// Enum returns the name of the value of an integer object, if the object
+// defines an enumeration. Since oid is the OID of an instance, the OIDs of its
+// prefixes are looked up.
+func (m *mibs) Enum(oid string, value int64) (string, bool) {
+	oid = strings.TrimPrefix(oid, ".")
+	for prefix := oid; prefix != ""; {
+		if enum, ok := m.enums[prefix]; ok {
+			name, ok := enum[value]
+			return name, ok
+		}
+
+		i := strings.LastIndexByte(prefix, '.')
+		if i < 0 {
+			break
+		}
+		prefix = prefix[:i]
+	}
+	return "", false
+}
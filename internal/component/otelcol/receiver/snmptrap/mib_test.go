@@ -0,0 +1,64 @@
+package snmptrap
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMIBs(t *testing.T) {
+	m, err := loadMIBs([]string{"testdata"})
+	require.NoError(t, err)
+
+	tt := []struct {
+		oid    string
+		expect string
+	}{
+		{"1.3.6.1.4.1.99999", "example"},
+		{".1.3.6.1.4.1.99999.1", "exampleMIB"},
+		{"1.3.6.1.4.1.99999.1.1.1", "exampleStatus"},
+		{"1.3.6.1.4.1.99999.1.1.1.0", "exampleStatus.0"},
+		{"1.3.6.1.4.1.99999.1.1.2.4.2", "exampleMessage.4.2"},
+		{"1.3.6.1.4.1.99999.1.2.1", "exampleStatusChanged"},
+		{"1.3.6.1.4.1.99999.0.7", "exampleV1Trap"},
+		{"1.3.6.1.4.1.99999.3", "example.3"},
+		{"1.3.6.1.2.1.2.2.1.8.3", "ifOperStatus.3"},
+		{"1.3.6.1.6.3.1.1.5.3", "linkDown"},
+		{"3.1", "3.1"},
+	}
+	for _, tc := range tt {
+		require.Equal(t, tc.expect, m.Name(tc.oid), "oid %s", tc.oid)
+	}
+
+	// The SEQUENCE fields aren't object definitions.
+	require.NotContains(t, m.names, "exampleOwner")
+
+	name, ok := m.Enum("1.3.6.1.4.1.99999.1.1.1.0", 2)
+	require.True(t, ok)
+	require.Equal(t, "degraded", name)
+
+	_, ok = m.Enum("1.3.6.1.4.1.99999.1.1.1.0", 4)
+	require.False(t, ok)
+	_, ok = m.Enum("1.3.6.1.4.1.99999.1.1.2.0", 1)
+	require.False(t, ok)
+}
+
+func TestLoadMIBs_MissingPath(t *testing.T) {
+	_, err := loadMIBs([]string{"testdata/missing"})
+	require.ErrorContains(t, err, "failed to read MIB path")
+}
+
+func TestParseMIB_OIDValues(t *testing.T) {
+	defs := make(map[string]definition)
+	parseMIB(`
+		org OBJECT IDENTIFIER ::= { iso 3 }
+		internet OBJECT IDENTIFIER ::= { iso(1) org(3) dod(6) 1 }
+		absolute OBJECT IDENTIFIER ::= { 1 3 6 1 4 1 1 }
+		invalid OBJECT IDENTIFIER ::= { iso org }
+	`, defs)
+
+	require.Equal(t, definition{parent: "iso", subIDs: []string{"3"}}, defs["org"])
+	require.Equal(t, definition{subIDs: []string{"1", "3", "6", "1"}}, defs["internet"])
+	require.Equal(t, definition{subIDs: []string{"1", "3", "6", "1", "4", "1", "1"}}, defs["absolute"])
+	require.NotContains(t, defs, "invalid")
+}
@@ -0,0 +1,436 @@
+// Package snmptrap provides an otelcol.receiver.snmptrap component.
+package snmptrap
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/gosnmp/gosnmp"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/fanoutconsumer"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/interceptconsumer"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/livedebuggingpublisher"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/service/livedebugging"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax/alloytypes"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.receiver.snmptrap",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+
+		Build: func(o component.Options, a component.Arguments) (component.Component, error) {
+			return New(o, a.(Arguments))
+		},
+	})
+}
+
+var (
+	authProtocols = map[string]gosnmp.SnmpV3AuthProtocol{
+		"":       gosnmp.NoAuth,
+		"MD5":    gosnmp.MD5,
+		"SHA":    gosnmp.SHA,
+		"SHA224": gosnmp.SHA224,
+		"SHA256": gosnmp.SHA256,
+		"SHA384": gosnmp.SHA384,
+		"SHA512": gosnmp.SHA512,
+	}
+	privacyProtocols = map[string]gosnmp.SnmpV3PrivProtocol{
+		"":        gosnmp.NoPriv,
+		"DES":     gosnmp.DES,
+		"AES":     gosnmp.AES,
+		"AES192":  gosnmp.AES192,
+		"AES256":  gosnmp.AES256,
+		"AES192C": gosnmp.AES192C,
+		"AES256C": gosnmp.AES256C,
+	}
+)
+
+// Arguments configures the otelcol.receiver.snmptrap component.
+type Arguments struct {
+	Endpoint    string              `alloy:"endpoint,attr,optional"`
+	Transport   string              `alloy:"transport,attr,optional"`
+	Communities []alloytypes.Secret `alloy:"communities,attr,optional"`
+	MIBPaths    []string            `alloy:"mib_paths,attr,optional"`
+	Users       []UserArguments     `alloy:"user,block,optional"`
+
+	// Output configures where to send received data. Required.
+	Output *otelcol.ConsumerArguments `alloy:"output,block"`
+}
+
+// UserArguments configures an SNMPv3 user which traps are accepted from.
+type UserArguments struct {
+	Name            string            `alloy:"name,attr"`
+	EngineID        string            `alloy:"engine_id,attr,optional"`
+	AuthProtocol    string            `alloy:"auth_protocol,attr,optional"`
+	AuthPassword    alloytypes.Secret `alloy:"auth_password,attr,optional"`
+	PrivacyProtocol string            `alloy:"privacy_protocol,attr,optional"`
+	PrivacyPassword alloytypes.Secret `alloy:"privacy_password,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{
+		Endpoint:  "localhost:162",
+		Transport: "udp",
+	}
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if args.Transport != "udp" && args.Transport != "tcp" {
+		return fmt.Errorf("transport must be udp or tcp, got %q", args.Transport)
+	}
+	for _, user := range args.Users {
+		if err := user.validate(); err != nil {
+			return fmt.Errorf("user %q: %w", user.Name, err)
+		}
+	}
+	return nil
+}
+
+func (u UserArguments) validate() error {
+	if _, ok := authProtocols[u.AuthProtocol]; !ok {
+		return fmt.Errorf("unsupported auth_protocol %q", u.AuthProtocol)
+	}
+	if _, ok := privacyProtocols[u.PrivacyProtocol]; !ok {
+		return fmt.Errorf("unsupported privacy_protocol %q", u.PrivacyProtocol)
+	}
+	if _, err := u.engineID(); err != nil {
+		return err
+	}
+
+	if u.AuthProtocol != "" && (u.AuthPassword == "" || u.EngineID == "") {
+		return fmt.Errorf("auth_password and engine_id are required when auth_protocol is set")
+	}
+	if u.PrivacyProtocol != "" {
+		if u.AuthProtocol == "" {
+			return fmt.Errorf("auth_protocol is required when privacy_protocol is set")
+		}
+		if u.PrivacyPassword == "" {
+			return fmt.Errorf("privacy_password is required when privacy_protocol is set")
+		}
+	}
+	return nil
+}
+
+// CheckFIPS implements fips.Checker. MD5 authentication and DES privacy
+// aren't approved by FIPS 140-3.
+func (u UserArguments) CheckFIPS() error {
+	var errs []error
+	if u.AuthProtocol == "MD5" {
+		errs = append(errs, fmt.Errorf("user %q: auth_protocol MD5 is not FIPS approved", u.Name))
+	}
+	if u.PrivacyProtocol == "DES" {
+		errs = append(errs, fmt.Errorf("user %q: privacy_protocol DES is not FIPS approved", u.Name))
+	}
+	return errors.Join(errs...)
+}
+
+// engineID decodes the hexadecimal engine ID of the user.
+func (u UserArguments) engineID() (string, error) {
+	id, err := hex.DecodeString(strings.TrimPrefix(strings.ToLower(u.EngineID), "0x"))
+	if err != nil {
+		return "", fmt.Errorf("engine_id must be hexadecimal: %w", err)
+	}
+	return string(id), nil
+}
+
+// securityLevel returns the security level of the traps sent by the user.
+func (u UserArguments) securityLevel() gosnmp.SnmpV3MsgFlags {
+	switch {
+	case u.PrivacyProtocol != "":
+		return gosnmp.AuthPriv
+	case u.AuthProtocol != "":
+		return gosnmp.AuthNoPriv
+	default:
+		return gosnmp.NoAuthNoPriv
+	}
+}
+
+// Component is the otelcol.receiver.snmptrap component.
+type Component struct {
+	log     log.Logger
+	opts    component.Options
+	metrics *metrics
+
+	mut      sync.RWMutex
+	args     Arguments
+	mibs     *mibs
+	logsSink consumer.Logs
+	updated  chan struct{}
+
+	healthMut sync.RWMutex
+	health    component.Health
+
+	debugDataPublisher livedebugging.DebugDataPublisher
+}
+
+var (
+	_ component.Component       = (*Component)(nil)
+	_ component.HealthComponent = (*Component)(nil)
+	_ component.LiveDebugging   = (*Component)(nil)
+)
+
+type metrics struct {
+	trapsReceived *prometheus.CounterVec
+	trapsDropped  *prometheus.CounterVec
+}
+
+func newMetrics(reg prometheus.Registerer) *metrics {
+	m := &metrics{
+		trapsReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "otelcol_receiver_snmptrap_traps_received_total",
+			Help: "Total number of SNMP traps received, by SNMP version.",
+		}, []string{"version"}),
+		trapsDropped: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "otelcol_receiver_snmptrap_traps_dropped_total",
+			Help: "Total number of SNMP traps dropped, by reason.",
+		}, []string{"reason"}),
+	}
+	m.trapsReceived = util.MustRegisterOrGet(reg, m.trapsReceived).(*prometheus.CounterVec)
+	m.trapsDropped = util.MustRegisterOrGet(reg, m.trapsDropped).(*prometheus.CounterVec)
+	return m
+}
+
+// New creates a new otelcol.receiver.snmptrap component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	debugDataPublisher, err := o.GetServiceData(livedebugging.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Component{
+		log:                o.Logger,
+		opts:               o,
+		metrics:            newMetrics(o.Registerer),
+		updated:            make(chan struct{}, 1),
+		debugDataPublisher: debugDataPublisher.(livedebugging.DebugDataPublisher),
+	}
+	if err := c.Update(args); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Run implements component.Component. The trap listener is restarted whenever
+// the arguments change.
+func (c *Component) Run(ctx context.Context) error {
+	for {
+		c.mut.RLock()
+		args := c.args
+		c.mut.RUnlock()
+
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			c.runListener(runCtx, args)
+		}()
+
+		select {
+		case <-ctx.Done():
+			cancel()
+			<-done
+			return nil
+		case <-c.updated:
+			cancel()
+			<-done
+		}
+	}
+}
+
+// Update implements component.Component.
+func (c *Component) Update(newConfig component.Arguments) error {
+	args := newConfig.(Arguments)
+
+	mibs, err := loadMIBs(args.MIBPaths)
+	if err != nil {
+		return err
+	}
+
+	nextLogs := args.Output.Logs
+	fanout := fanoutconsumer.Logs(nextLogs)
+	logsInterceptor := interceptconsumer.Logs(fanout,
+		func(ctx context.Context, ld plog.Logs) error {
+			livedebuggingpublisher.PublishLogsIfActive(c.debugDataPublisher, c.opts.ID, ld, otelcol.GetComponentMetadata(nextLogs))
+			return fanout.ConsumeLogs(ctx, ld)
+		},
+	)
+
+	c.mut.Lock()
+	c.args = args
+	c.mibs = mibs
+	c.logsSink = logsInterceptor
+	c.mut.Unlock()
+
+	select {
+	case c.updated <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// runListener listens for traps until the context is canceled.
+func (c *Component) runListener(ctx context.Context, args Arguments) {
+	tl := gosnmp.NewTrapListener()
+	params, err := c.listenerParams(args)
+	if err != nil {
+		c.setHealth(err)
+		return
+	}
+	tl.Params = params
+	tl.OnNewTrap = func(trap *gosnmp.SnmpPacket, addr *net.UDPAddr) {
+		c.handleTrap(ctx, args, trap, addr)
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- tl.Listen(args.Transport + "://" + args.Endpoint)
+	}()
+
+	// The listener can only be closed once it's listening.
+	select {
+	case <-tl.Listening():
+	case err := <-errCh:
+		level.Error(c.log).Log("msg", "failed to listen for SNMP traps", "endpoint", args.Endpoint, "err", err)
+		c.setHealth(err)
+		return
+	}
+	level.Info(c.log).Log("msg", "listening for SNMP traps", "endpoint", args.Endpoint, "transport", args.Transport)
+	c.setHealth(nil)
+
+	select {
+	case <-ctx.Done():
+		tl.Close()
+	case err := <-errCh:
+		level.Error(c.log).Log("msg", "SNMP trap listener stopped", "err", err)
+		c.setHealth(fmt.Errorf("SNMP trap listener stopped: %w", err))
+	}
+}
+
+func (c *Component) listenerParams(args Arguments) (*gosnmp.GoSNMP, error) {
+	users := gosnmp.NewSnmpV3SecurityParametersTable(gosnmp.Logger{})
+	for _, user := range args.Users {
+		engineID, err := user.engineID()
+		if err != nil {
+			return nil, err
+		}
+		err = users.Add(user.Name, &gosnmp.UsmSecurityParameters{
+			UserName:                 user.Name,
+			AuthoritativeEngineID:    engineID,
+			AuthenticationProtocol:   authProtocols[user.AuthProtocol],
+			AuthenticationPassphrase: string(user.AuthPassword),
+			PrivacyProtocol:          privacyProtocols[user.PrivacyProtocol],
+			PrivacyPassphrase:        string(user.PrivacyPassword),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure user %q: %w", user.Name, err)
+		}
+	}
+
+	// SNMPv1 and SNMPv2c traps are decoded regardless of the version, which
+	// must be SNMPv3 to authenticate the SNMPv3 traps with the users.
+	return &gosnmp.GoSNMP{
+		Version:                     gosnmp.Version3,
+		TrapSecurityParametersTable: users,
+	}, nil
+}
+
+func (c *Component) handleTrap(ctx context.Context, args Arguments, trap *gosnmp.SnmpPacket, addr *net.UDPAddr) {
+	c.metrics.trapsReceived.WithLabelValues(trap.Version.String()).Inc()
+
+	if reason, ok := authorizeTrap(args, trap); !ok {
+		level.Debug(c.log).Log("msg", "dropping unauthorized SNMP trap", "reason", reason, "addr", addr)
+		c.metrics.trapsDropped.WithLabelValues(reason).Inc()
+		return
+	}
+
+	c.mut.RLock()
+	logs := convertTrap(trap, addr, c.mibs, time.Now())
+	sink := c.logsSink
+	c.mut.RUnlock()
+
+	if err := sink.ConsumeLogs(ctx, logs); err != nil {
+		level.Error(c.log).Log("msg", "failed to consume SNMP trap", "err", err)
+		c.metrics.trapsDropped.WithLabelValues("consumer_error").Inc()
+	}
+}
+
+// authorizeTrap checks the community of SNMPv1 and SNMPv2c traps, and the
+// security level of SNMPv3 traps. The credentials of the SNMPv3 users are
+// already checked when the traps are decoded.
+func authorizeTrap(args Arguments, trap *gosnmp.SnmpPacket) (reason string, ok bool) {
+	if trap.Version != gosnmp.Version3 {
+		if len(args.Communities) == 0 {
+			return "", true
+		}
+		for _, community := range args.Communities {
+			if subtle.ConstantTimeCompare([]byte(trap.Community), []byte(community)) == 1 {
+				return "", true
+			}
+		}
+		return "community", false
+	}
+
+	usm, ok := trap.SecurityParameters.(*gosnmp.UsmSecurityParameters)
+	if !ok {
+		return "security_model", false
+	}
+
+	// Traps are accepted if they're at least as secure as the least secure
+	// configuration of their user.
+	level := trap.MsgFlags & gosnmp.AuthPriv
+	for _, user := range args.Users {
+		if user.Name == usm.UserName && level >= user.securityLevel() {
+			return "", true
+		}
+	}
+	return "security_level", false
+}
+
+func (c *Component) setHealth(err error) {
+	c.healthMut.Lock()
+	defer c.healthMut.Unlock()
+
+	if err == nil {
+		c.health = component.Health{
+			Health:     component.HealthTypeHealthy,
+			Message:    "listening for SNMP traps",
+			UpdateTime: time.Now(),
+		}
+	} else {
+		c.health = component.Health{
+			Health:     component.HealthTypeUnhealthy,
+			Message:    err.Error(),
+			UpdateTime: time.Now(),
+		}
+	}
+}
+
+// CurrentHealth implements component.HealthComponent.
+func (c *Component) CurrentHealth() component.Health {
+	c.healthMut.RLock()
+	defer c.healthMut.RUnlock()
+	return c.health
+}
+
+// LiveDebugging implements component.LiveDebugging.
+func (c *Component) LiveDebugging() {}
@@ -0,0 +1,299 @@
+package snmptrap
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/gosnmp/gosnmp"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/pdata/plog"
+
+	"github.com/grafana/alloy/internal/component/otelcol"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/fakeconsumer"
+	"github.com/grafana/alloy/internal/fips"
+	"github.com/grafana/alloy/internal/runtime/componenttest"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax"
+)
+
+const testEngineID = "8000000001020304"
+
+func Test(t *testing.T) {
+	port := freePort(t)
+
+	cfg := fmt.Sprintf(`
+		endpoint    = "127.0.0.1:%d"
+		communities = ["public"]
+		mib_paths   = ["testdata"]
+
+		user {
+			name             = "alloy"
+			engine_id        = "0x%s"
+			auth_protocol    = "SHA"
+			auth_password    = "authpassword"
+			privacy_protocol = "AES"
+			privacy_password = "privpassword"
+		}
+
+		output {
+			// no-op: will be overridden by test code.
+		}
+	`, port, testEngineID)
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(cfg), &args))
+
+	logCh := make(chan plog.Logs, 10)
+	args.Output = makeLogsOutput(logCh)
+
+	ctx := componenttest.TestContext(t)
+	ctrl, err := componenttest.NewControllerFromID(util.TestLogger(t), "otelcol.receiver.snmptrap")
+	require.NoError(t, err)
+	go func() {
+		require.NoError(t, ctrl.Run(ctx, args))
+	}()
+	require.NoError(t, ctrl.WaitRunning(time.Second))
+	waitListening(t, port)
+
+	variables := []gosnmp.SnmpPDU{
+		{Name: ".1.3.6.1.2.1.1.3.0", Type: gosnmp.TimeTicks, Value: uint32(1234)},
+		{Name: ".1.3.6.1.6.3.1.1.4.1.0", Type: gosnmp.ObjectIdentifier, Value: ".1.3.6.1.4.1.99999.1.2.1"},
+		{Name: ".1.3.6.1.4.1.99999.1.1.1.0", Type: gosnmp.Integer, Value: 3},
+		{Name: ".1.3.6.1.4.1.99999.1.1.2.0", Type: gosnmp.OctetString, Value: []byte("disk is full")},
+	}
+	// The traps which are expected to be dropped are sent first, with other
+	// variables.
+	droppedVariables := []gosnmp.SnmpPDU{
+		{Name: ".1.3.6.1.4.1.99999.1.1.2.0", Type: gosnmp.OctetString, Value: []byte("dropped")},
+	}
+	expectBody := map[string]any{
+		"sysUpTime.0":      int64(1234),
+		"exampleStatus.0":  "failed",
+		"exampleMessage.0": "disk is full",
+	}
+
+	t.Run("v2c", func(t *testing.T) {
+		// Traps with another community are dropped.
+		sendTrap(t, port, &gosnmp.GoSNMP{Version: gosnmp.Version2c, Community: "private"}, droppedVariables)
+		sendTrap(t, port, &gosnmp.GoSNMP{Version: gosnmp.Version2c, Community: "public"}, variables)
+
+		lr := receiveLogRecord(t, logCh)
+		require.Equal(t, "2c", lr.Attributes().AsRaw()[attrVersion])
+		require.Equal(t, "1.3.6.1.4.1.99999.1.2.1", lr.Attributes().AsRaw()[attrTrapOID])
+		require.Equal(t, "exampleStatusChanged", lr.Attributes().AsRaw()[attrTrapName])
+		require.Equal(t, "127.0.0.1", lr.Attributes().AsRaw()[attrPeerAddress])
+		require.Equal(t, expectBody, lr.Body().Map().AsRaw())
+	})
+
+	t.Run("v3", func(t *testing.T) {
+		// Traps less secure than the configuration of their user are dropped.
+		sendTrap(t, port, &gosnmp.GoSNMP{
+			Version:       gosnmp.Version3,
+			SecurityModel: gosnmp.UserSecurityModel,
+			MsgFlags:      gosnmp.NoAuthNoPriv,
+			SecurityParameters: &gosnmp.UsmSecurityParameters{
+				UserName:              "alloy",
+				AuthoritativeEngineID: engineID(t),
+			},
+		}, droppedVariables)
+		sendTrap(t, port, &gosnmp.GoSNMP{
+			Version:       gosnmp.Version3,
+			SecurityModel: gosnmp.UserSecurityModel,
+			MsgFlags:      gosnmp.AuthPriv,
+			SecurityParameters: &gosnmp.UsmSecurityParameters{
+				UserName:                 "alloy",
+				AuthoritativeEngineID:    engineID(t),
+				AuthenticationProtocol:   gosnmp.SHA,
+				AuthenticationPassphrase: "authpassword",
+				PrivacyProtocol:          gosnmp.AES,
+				PrivacyPassphrase:        "privpassword",
+			},
+		}, variables)
+
+		lr := receiveLogRecord(t, logCh)
+		require.Equal(t, "3", lr.Attributes().AsRaw()[attrVersion])
+		require.Equal(t, "alloy", lr.Attributes().AsRaw()[attrUser])
+		require.Equal(t, "exampleStatusChanged", lr.Attributes().AsRaw()[attrTrapName])
+		require.Equal(t, expectBody, lr.Body().Map().AsRaw())
+	})
+}
+
+func TestArguments(t *testing.T) {
+	tt := []struct {
+		name      string
+		cfg       string
+		expectErr string
+	}{
+		{
+			name: "defaults",
+			cfg:  `output {}`,
+		},
+		{
+			name: "invalid transport",
+			cfg: `
+				transport = "sctp"
+				output {}
+			`,
+			expectErr: `transport must be udp or tcp, got "sctp"`,
+		},
+		{
+			name: "unsupported auth protocol",
+			cfg: `
+				user {
+					name          = "alloy"
+					engine_id     = "8000000001020304"
+					auth_protocol = "SHA1"
+					auth_password = "authpassword"
+				}
+				output {}
+			`,
+			expectErr: `user "alloy": unsupported auth_protocol "SHA1"`,
+		},
+		{
+			name: "missing engine ID",
+			cfg: `
+				user {
+					name          = "alloy"
+					auth_protocol = "SHA"
+					auth_password = "authpassword"
+				}
+				output {}
+			`,
+			expectErr: `user "alloy": auth_password and engine_id are required when auth_protocol is set`,
+		},
+		{
+			name: "invalid engine ID",
+			cfg: `
+				user {
+					name      = "alloy"
+					engine_id = "engine"
+				}
+				output {}
+			`,
+			expectErr: `user "alloy": engine_id must be hexadecimal`,
+		},
+		{
+			name: "privacy without auth",
+			cfg: `
+				user {
+					name             = "alloy"
+					privacy_protocol = "AES"
+					privacy_password = "privpassword"
+				}
+				output {}
+			`,
+			expectErr: `user "alloy": auth_protocol is required when privacy_protocol is set`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.cfg), &args)
+			if tc.expectErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectErr)
+			}
+		})
+	}
+}
+
+func TestCheckFIPS(t *testing.T) {
+	var args Arguments
+	err := syntax.Unmarshal([]byte(`
+		user {
+			name          = "strong"
+			engine_id     = "8000000001020304"
+			auth_protocol = "SHA256"
+			auth_password = "authpassword"
+		}
+		user {
+			name             = "weak"
+			engine_id        = "8000000001020304"
+			auth_protocol    = "MD5"
+			auth_password    = "authpassword"
+			privacy_protocol = "DES"
+			privacy_password = "privpassword"
+		}
+		output {}
+	`), &args)
+	require.NoError(t, err)
+
+	require.NoError(t, args.Users[0].CheckFIPS())
+	require.EqualError(t, fips.Check(args), "user \"weak\": auth_protocol MD5 is not FIPS approved\nuser \"weak\": privacy_protocol DES is not FIPS approved")
+}
+
+func engineID(t *testing.T) string {
+	t.Helper()
+	id, err := UserArguments{EngineID: testEngineID}.engineID()
+	require.NoError(t, err)
+	return id
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer conn.Close()
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+// waitListening waits until the port is in use by the listener.
+func waitListening(t *testing.T, port int) {
+	t.Helper()
+	require.Eventually(t, func() bool {
+		conn, err := net.ListenPacket("udp", fmt.Sprintf("127.0.0.1:%d", port))
+		if err != nil {
+			return true
+		}
+		conn.Close()
+		return false
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func sendTrap(t *testing.T, port int, g *gosnmp.GoSNMP, variables []gosnmp.SnmpPDU) {
+	t.Helper()
+	g.Target = "127.0.0.1"
+	g.Port = uint16(port)
+	g.Transport = "udp"
+	g.Timeout = time.Second
+	require.NoError(t, g.Connect())
+	defer g.Conn.Close()
+
+	_, err := g.SendTrap(gosnmp.SnmpTrap{Variables: variables})
+	require.NoError(t, err)
+}
+
+func receiveLogRecord(t *testing.T, ch chan plog.Logs) plog.LogRecord {
+	t.Helper()
+	select {
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "failed waiting for log record")
+		return plog.LogRecord{}
+	case logs := <-ch:
+		require.Equal(t, 1, logs.LogRecordCount())
+		return logs.ResourceLogs().At(0).ScopeLogs().At(0).LogRecords().At(0)
+	}
+}
+
+// makeLogsOutput returns a ConsumerArguments which will forward logs to
+// the provided channel.
+func makeLogsOutput(ch chan plog.Logs) *otelcol.ConsumerArguments {
+	logsConsumer := fakeconsumer.Consumer{
+		ConsumeLogsFunc: func(ctx context.Context, l plog.Logs) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case ch <- l:
+				return nil
+			}
+		},
+	}
+
+	return &otelcol.ConsumerArguments{
+		Logs: []otelcol.Consumer{&logsConsumer},
+	}
+}
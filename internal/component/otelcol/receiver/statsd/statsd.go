@@ -0,0 +1,150 @@
+// Package statsd provides an otelcol.receiver.statsd component.
+package statsd
+
+import (
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
+	"github.com/grafana/alloy/internal/component/otelcol/receiver"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
+	otelcomponent "go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/pipeline"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "otelcol.receiver.statsd",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			fact := statsdreceiver.NewFactory()
+			return receiver.New(opts, fact, args.(Arguments))
+		},
+	})
+}
+
+// Arguments configures the otelcol.receiver.statsd component.
+type Arguments struct {
+	Endpoint                string        `alloy:"endpoint,attr,optional"`
+	Transport               string        `alloy:"transport,attr,optional"`
+	AggregationInterval     time.Duration `alloy:"aggregation_interval,attr,optional"`
+	EnableIPOnlyAggregation bool          `alloy:"enable_ip_only_aggregation,attr,optional"`
+	EnableMetricType        bool          `alloy:"enable_metric_type,attr,optional"`
+	EnableSimpleTags        bool          `alloy:"enable_simple_tags,attr,optional"`
+	IsMonotonicCounter      bool          `alloy:"is_monotonic_counter,attr,optional"`
+
+	TimerHistogramMapping []TimerHistogramMapping `alloy:"timer_histogram_mapping,block,optional"`
+
+	// DebugMetrics configures component internal metrics. Optional.
+	DebugMetrics otelcolCfg.DebugMetricsArguments `alloy:"debug_metrics,block,optional"`
+
+	// Output configures where to send received data. Required.
+	Output *otelcol.ConsumerArguments `alloy:"output,block"`
+}
+
+// TimerHistogramMapping configures how timer, histogram, and distribution
+// statsd metrics are aggregated.
+type TimerHistogramMapping struct {
+	StatsdType   string           `alloy:"statsd_type,attr"`
+	ObserverType string           `alloy:"observer_type,attr"`
+	Histogram    *HistogramConfig `alloy:"histogram,block,optional"`
+	Summary      *SummaryConfig   `alloy:"summary,block,optional"`
+}
+
+// HistogramConfig configures the exponential histograms of the histogram
+// observer.
+type HistogramConfig struct {
+	MaxSize int32 `alloy:"max_size,attr,optional"`
+}
+
+// SummaryConfig configures the percentiles of the summary observer.
+type SummaryConfig struct {
+	Percentiles []float64 `alloy:"percentiles,attr,optional"`
+}
+
+// Convert converts the mapping to the upstream type.
+func (m TimerHistogramMapping) Convert() protocol.TimerHistogramMapping {
+	res := protocol.TimerHistogramMapping{
+		StatsdType:   protocol.TypeName(m.StatsdType),
+		ObserverType: protocol.ObserverType(m.ObserverType),
+	}
+	if m.Histogram != nil {
+		res.Histogram.MaxSize = m.Histogram.MaxSize
+	}
+	if m.Summary != nil {
+		res.Summary.Percentiles = m.Summary.Percentiles
+	}
+	return res
+}
+
+var _ receiver.Arguments = Arguments{}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{
+		Endpoint:            "localhost:8125",
+		Transport:           string(confignet.TransportTypeUDP),
+		AggregationInterval: 60 * time.Second,
+	}
+	args.DebugMetrics.SetToDefault()
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	otelConfig, err := args.Convert()
+	if err != nil {
+		return err
+	}
+
+	return otelConfig.(*statsdreceiver.Config).Validate()
+}
+
+// Convert implements receiver.Arguments.
+func (args Arguments) Convert() (otelcomponent.Config, error) {
+	cfg := statsdreceiver.NewFactory().CreateDefaultConfig().(*statsdreceiver.Config)
+	cfg.NetAddr = confignet.AddrConfig{
+		Endpoint:  args.Endpoint,
+		Transport: confignet.TransportType(args.Transport),
+	}
+	cfg.AggregationInterval = args.AggregationInterval
+	cfg.EnableIPOnlyAggregation = args.EnableIPOnlyAggregation
+	cfg.EnableMetricType = args.EnableMetricType
+	cfg.EnableSimpleTags = args.EnableSimpleTags
+	cfg.IsMonotonicCounter = args.IsMonotonicCounter
+
+	// The upstream default mappings are kept unless mappings are configured.
+	if len(args.TimerHistogramMapping) > 0 {
+		cfg.TimerHistogramMapping = make([]protocol.TimerHistogramMapping, 0, len(args.TimerHistogramMapping))
+		for _, m := range args.TimerHistogramMapping {
+			cfg.TimerHistogramMapping = append(cfg.TimerHistogramMapping, m.Convert())
+		}
+	}
+
+	return cfg, nil
+}
+
+// Extensions implements receiver.Arguments.
+func (args Arguments) Extensions() map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// Exporters implements receiver.Arguments.
+func (args Arguments) Exporters() map[pipeline.Signal]map[otelcomponent.ID]otelcomponent.Component {
+	return nil
+}
+
+// NextConsumers implements receiver.Arguments.
+func (args Arguments) NextConsumers() *otelcol.ConsumerArguments {
+	return args.Output
+}
+
+// DebugMetricsConfig implements receiver.Arguments.
+func (args Arguments) DebugMetricsConfig() otelcolCfg.DebugMetricsArguments {
+	return args.DebugMetrics
+}
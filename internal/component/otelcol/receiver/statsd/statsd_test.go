@@ -0,0 +1,243 @@
+package statsd_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/component/otelcol"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/fakeconsumer"
+	"github.com/grafana/alloy/internal/component/otelcol/receiver/statsd"
+	"github.com/grafana/alloy/internal/runtime/componenttest"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/statsdreceiver/protocol"
+	"github.com/phayes/freeport"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/config/confignet"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+)
+
+// Test performs a basic integration test which runs the otelcol.receiver.statsd
+// component and ensures that it can receive and forward data.
+func Test(t *testing.T) {
+	addr := getFreeAddr(t)
+
+	ctx := componenttest.TestContext(t)
+	l := util.TestLogger(t)
+
+	ctrl, err := componenttest.NewControllerFromID(l, "otelcol.receiver.statsd")
+	require.NoError(t, err)
+
+	cfg := fmt.Sprintf(`
+		endpoint             = "%s"
+		aggregation_interval = "100ms"
+
+		output {
+			// no-op: will be overridden by test code.
+		}
+	`, addr)
+
+	var args statsd.Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(cfg), &args))
+
+	// Override our settings so metrics get forwarded to metricCh.
+	metricCh := make(chan pmetric.Metrics)
+	args.Output = makeMetricsOutput(metricCh)
+
+	go func() {
+		err := ctrl.Run(ctx, args)
+		require.NoError(t, err)
+	}()
+
+	require.NoError(t, ctrl.WaitRunning(3*time.Second))
+
+	// Send metrics in the background to our receiver until one is received.
+	go func() {
+		conn, err := net.Dial("udp", addr)
+		require.NoError(t, err)
+		defer conn.Close()
+
+		ticker := time.NewTicker(50 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				_, _ = fmt.Fprint(conn, "test.counter:42|c|#key:value\n")
+			}
+		}
+	}()
+
+	select {
+	case <-time.After(5 * time.Second):
+		require.FailNow(t, "failed waiting for metrics")
+	case m := <-metricCh:
+		metric := m.ResourceMetrics().At(0).ScopeMetrics().At(0).Metrics().At(0)
+		require.Equal(t, "test.counter", metric.Name())
+	}
+}
+
+// makeMetricsOutput returns ConsumerArguments which will forward metrics to
+// the provided channel.
+func makeMetricsOutput(ch chan pmetric.Metrics) *otelcol.ConsumerArguments {
+	metricsConsumer := fakeconsumer.Consumer{
+		ConsumeMetricsFunc: func(ctx context.Context, m pmetric.Metrics) error {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case ch <- m:
+				return nil
+			}
+		},
+	}
+
+	return &otelcol.ConsumerArguments{
+		Metrics: []otelcol.Consumer{&metricsConsumer},
+	}
+}
+
+func getFreeAddr(t *testing.T) string {
+	t.Helper()
+
+	portNumber, err := freeport.GetFreePort()
+	require.NoError(t, err)
+
+	return fmt.Sprintf("127.0.0.1:%d", portNumber)
+}
+
+func TestArguments(t *testing.T) {
+	tests := []struct {
+		testName string
+		cfg      string
+		expected statsdreceiver.Config
+	}{
+		{
+			testName: "defaults",
+			cfg: `
+				output {}
+			`,
+			expected: statsdreceiver.Config{
+				NetAddr: confignet.AddrConfig{
+					Endpoint:  "localhost:8125",
+					Transport: confignet.TransportTypeUDP,
+				},
+				AggregationInterval: 60 * time.Second,
+				TimerHistogramMapping: []protocol.TimerHistogramMapping{
+					{StatsdType: "timer", ObserverType: "gauge"},
+					{StatsdType: "histogram", ObserverType: "gauge"},
+					{StatsdType: "distribution", ObserverType: "gauge"},
+				},
+			},
+		},
+		{
+			testName: "explicit values",
+			cfg: `
+				endpoint                   = "0.0.0.0:9125"
+				transport                  = "tcp"
+				aggregation_interval       = "30s"
+				enable_ip_only_aggregation = true
+				enable_metric_type         = true
+				enable_simple_tags         = true
+				is_monotonic_counter       = true
+
+				timer_histogram_mapping {
+					statsd_type   = "timing"
+					observer_type = "histogram"
+					histogram {
+						max_size = 100
+					}
+				}
+
+				timer_histogram_mapping {
+					statsd_type   = "distribution"
+					observer_type = "summary"
+					summary {
+						percentiles = [50, 99]
+					}
+				}
+
+				output {}
+			`,
+			expected: statsdreceiver.Config{
+				NetAddr: confignet.AddrConfig{
+					Endpoint:  "0.0.0.0:9125",
+					Transport: confignet.TransportTypeTCP,
+				},
+				AggregationInterval:     30 * time.Second,
+				EnableIPOnlyAggregation: true,
+				EnableMetricType:        true,
+				EnableSimpleTags:        true,
+				IsMonotonicCounter:      true,
+				TimerHistogramMapping: []protocol.TimerHistogramMapping{
+					{StatsdType: "timing", ObserverType: "histogram", Histogram: protocol.HistogramConfig{MaxSize: 100}},
+					{StatsdType: "distribution", ObserverType: "summary", Summary: protocol.SummaryConfig{Percentiles: []float64{50, 99}}},
+				},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var args statsd.Arguments
+			require.NoError(t, syntax.Unmarshal([]byte(tc.cfg), &args))
+
+			actual, err := args.Convert()
+			require.NoError(t, err)
+			require.Equal(t, &tc.expected, actual.(*statsdreceiver.Config))
+		})
+	}
+}
+
+func TestArgumentsValidate(t *testing.T) {
+	tests := []struct {
+		testName string
+		cfg      string
+		err      string
+	}{
+		{
+			testName: "invalid aggregation interval",
+			cfg: `
+				aggregation_interval = "0s"
+				output {}
+			`,
+			err: "aggregation_interval must be a positive duration",
+		},
+		{
+			testName: "invalid statsd type",
+			cfg: `
+				timer_histogram_mapping {
+					statsd_type   = "counter"
+					observer_type = "gauge"
+				}
+				output {}
+			`,
+			err: "statsd_type is not a supported mapping for histogram and timing metrics: counter",
+		},
+		{
+			testName: "histogram config without histogram observer",
+			cfg: `
+				timer_histogram_mapping {
+					statsd_type   = "timer"
+					observer_type = "gauge"
+					histogram {
+						max_size = 100
+					}
+				}
+				output {}
+			`,
+			err: "histogram configuration requires observer_type: histogram",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.testName, func(t *testing.T) {
+			var args statsd.Arguments
+			require.ErrorContains(t, syntax.Unmarshal([]byte(tc.cfg), &args), tc.err)
+		})
+	}
+}
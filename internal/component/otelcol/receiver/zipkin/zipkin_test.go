@@ -1,19 +1,31 @@
 package zipkin_test
 
 import (
+	"context"
 	"fmt"
+	"net/http"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
 	otelcolCfg "github.com/grafana/alloy/internal/component/otelcol/config"
+	"github.com/grafana/alloy/internal/component/otelcol/internal/fakeconsumer"
+	"github.com/grafana/alloy/internal/component/otelcol/receiver"
 	"github.com/grafana/alloy/internal/component/otelcol/receiver/zipkin"
 	"github.com/grafana/alloy/internal/runtime/componenttest"
+	"github.com/grafana/alloy/internal/service/livedebugging"
 	"github.com/grafana/alloy/internal/util"
 	"github.com/grafana/alloy/syntax"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/zipkinreceiver"
 	"github.com/phayes/freeport"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/config/confighttp"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 func TestRun(t *testing.T) {
@@ -74,6 +86,7 @@ func TestArguments_UnmarshalAlloy(t *testing.T) {
 			allowed_origins = ["https://*.test.com", "https://test.com"]
 		}
 
+		max_request_body_size = "1MiB"
 		parse_string_tags = true
 
 		debug_metrics {
@@ -97,10 +110,74 @@ func TestArguments_UnmarshalAlloy(t *testing.T) {
 		require.Equal(t, len(otelArgs.ServerConfig.CORS.AllowedOrigins), 2)
 		require.Equal(t, otelArgs.ServerConfig.CORS.AllowedOrigins[0], "https://*.test.com")
 		require.Equal(t, otelArgs.ServerConfig.CORS.AllowedOrigins[1], "https://test.com")
+		require.Equal(t, otelArgs.ServerConfig.MaxRequestBodySize, int64(1024*1024))
 		require.Equal(t, otelArgs.ParseStringTags, true)
 	})
 }
 
+// TestDebugMetrics ensures that otelcol.receiver.zipkin exposes metrics about
+// the requests it receives.
+func TestDebugMetrics(t *testing.T) {
+	httpAddr := getFreeAddr(t)
+
+	var args zipkin.Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(fmt.Sprintf(`
+		endpoint = "%s"
+
+		output { /* no-op */ }
+	`, httpAddr)), &args))
+	args.Output = &otelcol.ConsumerArguments{
+		Traces: []otelcol.Consumer{&fakeconsumer.Consumer{
+			ConsumeTracesFunc: func(context.Context, ptrace.Traces) error { return nil },
+		}},
+	}
+
+	reg := prometheus.NewRegistry()
+	c, err := receiver.New(component.Options{
+		ID:         "otelcol.receiver.zipkin.test",
+		Logger:     util.TestAlloyLogger(t),
+		Tracer:     noop.NewTracerProvider(),
+		Registerer: reg,
+		GetServiceData: func(name string) (interface{}, error) {
+			return livedebugging.NewLiveDebugging(), nil
+		},
+	}, zipkinreceiver.NewFactory(), args)
+	require.NoError(t, err)
+
+	// Wait for the receiver to shut down so that its server is stopped before
+	// other tests run.
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		require.NoError(t, c.Run(ctx))
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	span := `[{"traceId":"5982fe77008310cc80f1da5e10147517","id":"bd7a977555f6b982","name":"get","timestamp":1472470996199000,"duration":207000}]`
+	require.Eventually(t, func() bool {
+		resp, err := http.Post("http://"+httpAddr+"/api/v2/spans", "application/json", strings.NewReader(span))
+		if err != nil {
+			return false
+		}
+		require.NoError(t, resp.Body.Close())
+		return resp.StatusCode == http.StatusAccepted
+	}, 5*time.Second, 50*time.Millisecond)
+
+	expected := `
+		# HELP otelcol_receiver_accepted_spans_total Number of spans successfully pushed into the pipeline. [alpha]
+		# TYPE otelcol_receiver_accepted_spans_total counter
+		otelcol_receiver_accepted_spans_total{otel_scope_name="go.opentelemetry.io/collector/receiver/receiverhelper",otel_scope_version="",receiver="zipkin/otelcol.receiver.zipkin.test",transport="http_v2_json"} 1
+	`
+	require.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "otelcol_receiver_accepted_spans_total"))
+	count, err := testutil.GatherAndCount(reg, "http.server.duration_milliseconds")
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
+
 func getFreeAddr(t *testing.T) string {
 	t.Helper()
 
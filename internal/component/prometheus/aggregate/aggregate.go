@@ -0,0 +1,309 @@
+package aggregate
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/prometheus"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/service/labelstore"
+	"github.com/grafana/alloy/internal/service/livedebugging"
+	prometheus_client "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/exemplar"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/metadata"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/storage"
+	"go.uber.org/atomic"
+)
+
+const name = "prometheus.aggregate"
+
+func init() {
+	component.Register(component.Registration{
+		Name:      name,
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+		Exports:   Exports{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments holds values which are used to configure the
+// prometheus.aggregate component.
+type Arguments struct {
+	// Where the aggregated metrics and the metrics which aren't aggregated
+	// should be forwarded to.
+	ForwardTo []storage.Appendable `alloy:"forward_to,attr"`
+
+	// How often the aggregated samples are written.
+	Interval time.Duration `alloy:"interval,attr,optional"`
+
+	Rules []Rule `alloy:"rule,block,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{
+		Interval: time.Minute,
+	}
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if args.Interval <= 0 {
+		return fmt.Errorf("interval must be greater than 0")
+	}
+	return nil
+}
+
+// Exports holds values which are exported by the prometheus.aggregate
+// component.
+type Exports struct {
+	Receiver storage.Appendable `alloy:"receiver,attr"`
+}
+
+// Component implements the prometheus.aggregate component.
+type Component struct {
+	opts     component.Options
+	fanout   *prometheus.Fanout
+	receiver *prometheus.Interceptor
+	exited   atomic.Bool
+	updated  chan struct{}
+
+	samplesAggregated prometheus_client.Counter
+	samplesWritten    prometheus_client.Counter
+
+	debugDataPublisher livedebugging.DebugDataPublisher
+
+	mut      sync.Mutex
+	interval time.Duration
+	rules    []*rule
+}
+
+var (
+	_ component.Component     = (*Component)(nil)
+	_ component.LiveDebugging = (*Component)(nil)
+)
+
+// New creates a new prometheus.aggregate component.
+func New(o component.Options, args Arguments) (*Component, error) {
+	debugDataPublisher, err := o.GetServiceData(livedebugging.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := o.GetServiceData(labelstore.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+	ls := data.(labelstore.LabelStore)
+
+	c := &Component{
+		opts:               o,
+		updated:            make(chan struct{}, 1),
+		debugDataPublisher: debugDataPublisher.(livedebugging.DebugDataPublisher),
+	}
+	c.samplesAggregated = prometheus_client.NewCounter(prometheus_client.CounterOpts{
+		Name: "alloy_prometheus_aggregate_samples_aggregated_total",
+		Help: "Total number of input samples aggregated by a rule.",
+	})
+	c.samplesWritten = prometheus_client.NewCounter(prometheus_client.CounterOpts{
+		Name: "alloy_prometheus_aggregate_samples_written_total",
+		Help: "Total number of aggregated samples written.",
+	})
+	for _, metric := range []prometheus_client.Collector{c.samplesAggregated, c.samplesWritten} {
+		if err := o.Registerer.Register(metric); err != nil {
+			return nil, err
+		}
+	}
+
+	c.fanout = prometheus.NewFanout(args.ForwardTo, o.ID, o.Registerer, ls)
+	c.receiver = prometheus.NewInterceptor(
+		c.fanout,
+		ls,
+		prometheus.WithAppendHook(func(ref storage.SeriesRef, l labels.Labels, t int64, v float64, next storage.Appender) (storage.SeriesRef, error) {
+			if c.exited.Load() {
+				return 0, fmt.Errorf("%s has exited", o.ID)
+			}
+
+			if !c.aggregate(l, v) {
+				return 0, nil
+			}
+			return next.Append(ref, l, t, v)
+		}),
+		prometheus.WithExemplarHook(func(ref storage.SeriesRef, l labels.Labels, e exemplar.Exemplar, next storage.Appender) (storage.SeriesRef, error) {
+			if c.exited.Load() {
+				return 0, fmt.Errorf("%s has exited", o.ID)
+			}
+
+			if !c.forwarded(l) {
+				return 0, nil
+			}
+			return next.AppendExemplar(ref, l, e)
+		}),
+		prometheus.WithMetadataHook(func(ref storage.SeriesRef, l labels.Labels, m metadata.Metadata, next storage.Appender) (storage.SeriesRef, error) {
+			if c.exited.Load() {
+				return 0, fmt.Errorf("%s has exited", o.ID)
+			}
+
+			if !c.forwarded(l) {
+				return 0, nil
+			}
+			return next.UpdateMetadata(ref, l, m)
+		}),
+	)
+
+	// Immediately export the receiver which remains the same for the component
+	// lifetime.
+	o.OnStateChange(Exports{Receiver: c.receiver})
+
+	if err := c.Update(args); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Run implements component.Component.
+func (c *Component) Run(ctx context.Context) error {
+	defer c.exited.Store(true)
+
+	ticker := time.NewTicker(c.getInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Write the samples aggregated since the last interval.
+			c.flush(context.Background())
+			return nil
+		case <-c.updated:
+			ticker.Reset(c.getInterval())
+		case <-ticker.C:
+			c.flush(ctx)
+		}
+	}
+}
+
+// Update implements component.Component.
+func (c *Component) Update(args component.Arguments) error {
+	newArgs := args.(Arguments)
+
+	rules := make([]*rule, 0, len(newArgs.Rules))
+	for _, r := range newArgs.Rules {
+		newRule, err := newRule(r)
+		if err != nil {
+			return err
+		}
+		rules = append(rules, newRule)
+	}
+
+	// Write the samples aggregated by the previous rules before replacing
+	// them.
+	c.flush(context.Background())
+
+	c.mut.Lock()
+	c.rules = rules
+	c.interval = newArgs.Interval
+	c.mut.Unlock()
+	c.fanout.UpdateChildren(newArgs.ForwardTo)
+
+	select {
+	case c.updated <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (c *Component) getInterval() time.Duration {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return c.interval
+}
+
+// aggregate records the sample in the rules matching the series l, and
+// returns whether the sample must also be forwarded.
+func (c *Component) aggregate(l labels.Labels, v float64) bool {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	forward := true
+	for _, r := range c.rules {
+		if !r.matches(l) {
+			continue
+		}
+		r.record(l, v)
+		c.samplesAggregated.Inc()
+		forward = forward && r.keepInput
+	}
+	return forward
+}
+
+// forwarded returns whether the samples of the series l are forwarded.
+func (c *Component) forwarded(l labels.Labels) bool {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	for _, r := range c.rules {
+		if r.matches(l) && !r.keepInput {
+			return false
+		}
+	}
+	return true
+}
+
+// flush writes the samples aggregated during the current interval.
+func (c *Component) flush(ctx context.Context) {
+	c.mut.Lock()
+	var samples []sample
+	for _, r := range c.rules {
+		samples = append(samples, r.flush()...)
+	}
+	c.mut.Unlock()
+
+	if len(samples) == 0 {
+		return
+	}
+
+	// Aggregated samples are written to the fanout directly, so that they're
+	// never aggregated again.
+	ts := timestamp.FromTime(time.Now())
+	app := c.fanout.Appender(ctx)
+	for _, s := range samples {
+		if _, err := app.Append(0, s.labels, ts, s.value); err != nil {
+			level.Warn(c.opts.Logger).Log("msg", "failed to write aggregated sample", "labels", s.labels, "err", err)
+			continue
+		}
+		c.samplesWritten.Inc()
+	}
+	if err := app.Commit(); err != nil {
+		level.Warn(c.opts.Logger).Log("msg", "failed to commit aggregated samples", "err", err)
+	}
+
+	componentID := livedebugging.ComponentID(c.opts.ID)
+	c.debugDataPublisher.PublishIfActive(livedebugging.NewData(
+		componentID,
+		livedebugging.PrometheusMetric,
+		uint64(len(samples)),
+		func() string {
+			var sb strings.Builder
+			for i, s := range samples {
+				if i > 0 {
+					sb.WriteString("\n")
+				}
+				fmt.Fprintf(&sb, "aggregated: ts=%d, labels=%s, value=%f", ts, s.labels, s.value)
+			}
+			return sb.String()
+		},
+	))
+}
+
+func (c *Component) LiveDebugging() {}
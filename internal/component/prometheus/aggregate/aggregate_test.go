@@ -0,0 +1,256 @@
+package aggregate
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"testing"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/service/labelstore"
+	"github.com/grafana/alloy/internal/service/livedebugging"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/internal/util/testappender"
+	"github.com/grafana/alloy/syntax"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregate(t *testing.T) {
+	collector := testappender.NewCollectingAppender()
+	c := newTestComponent(t, collector, `
+		forward_to = []
+		rule {
+			match   = "{__name__=\"http_requests_total\"}"
+			without = ["pod"]
+			outputs = ["sum", "count"]
+		}
+		rule {
+			match      = "{__name__=\"temperature\"}"
+			by         = ["room"]
+			outputs    = ["avg", "min", "max"]
+			keep_input = true
+		}
+	`)
+
+	app := c.receiver.Appender(t.Context())
+	for _, s := range []struct {
+		labels labels.Labels
+		value  float64
+	}{
+		{labels.FromStrings("__name__", "http_requests_total", "pod", "a", "code", "200"), 1},
+		// Only the last sample of each series in the interval is aggregated.
+		{labels.FromStrings("__name__", "http_requests_total", "pod", "a", "code", "200"), 10},
+		{labels.FromStrings("__name__", "http_requests_total", "pod", "b", "code", "200"), 20},
+		{labels.FromStrings("__name__", "http_requests_total", "pod", "b", "code", "500"), 5},
+		{labels.FromStrings("__name__", "temperature", "room", "kitchen", "sensor", "1"), 20},
+		{labels.FromStrings("__name__", "temperature", "room", "kitchen", "sensor", "2"), 24},
+		{labels.FromStrings("__name__", "up", "pod", "a"), 1},
+	} {
+		_, err := app.Append(0, s.labels, 1000, s.value)
+		require.NoError(t, err)
+	}
+	require.NoError(t, app.Commit())
+
+	// Samples which aren't aggregated, or whose rule keeps them, are forwarded
+	// immediately.
+	require.Equal(t, []string{
+		`{__name__="temperature", room="kitchen", sensor="1"}`,
+		`{__name__="temperature", room="kitchen", sensor="2"}`,
+		`{__name__="up", pod="a"}`,
+	}, sampleLabels(collector))
+
+	c.flush(t.Context())
+	expect := map[string]float64{
+		`{__name__="http_requests_total:sum", code="200"}`:   30,
+		`{__name__="http_requests_total:sum", code="500"}`:   5,
+		`{__name__="http_requests_total:count", code="200"}`: 2,
+		`{__name__="http_requests_total:count", code="500"}`: 1,
+		`{__name__="temperature:avg", room="kitchen"}`:       22,
+		`{__name__="temperature:min", room="kitchen"}`:       20,
+		`{__name__="temperature:max", room="kitchen"}`:       24,
+	}
+	for series, v := range expect {
+		s := collector.LatestSampleFor(series)
+		require.NotNil(t, s, series)
+		require.Equal(t, v, s.Value, series)
+	}
+
+	// Output series without samples in the next interval are marked as stale.
+	app = c.receiver.Appender(t.Context())
+	_, err := app.Append(0, labels.FromStrings("__name__", "http_requests_total", "pod", "a", "code", "200"), 2000, 11)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+	c.flush(t.Context())
+
+	require.Equal(t, 11.0, collector.LatestSampleFor(`{__name__="http_requests_total:sum", code="200"}`).Value)
+	require.True(t, value.IsStaleNaN(collector.LatestSampleFor(`{__name__="http_requests_total:sum", code="500"}`).Value))
+	require.True(t, value.IsStaleNaN(collector.LatestSampleFor(`{__name__="temperature:avg", room="kitchen"}`).Value))
+}
+
+func TestAggregate_StaleInput(t *testing.T) {
+	collector := testappender.NewCollectingAppender()
+	c := newTestComponent(t, collector, `
+		forward_to = []
+		rule {
+			match   = "{__name__=\"requests\"}"
+			outputs = ["sum"]
+		}
+	`)
+
+	app := c.receiver.Appender(t.Context())
+	for _, s := range []struct {
+		pod   string
+		value float64
+	}{{"a", 1}, {"b", 2}, {"b", math.Float64frombits(value.StaleNaN)}} {
+		_, err := app.Append(0, labels.FromStrings("__name__", "requests", "pod", s.pod), 1000, s.value)
+		require.NoError(t, err)
+	}
+	require.NoError(t, app.Commit())
+	c.flush(t.Context())
+
+	require.Equal(t, 1.0, collector.LatestSampleFor(`{__name__="requests:sum"}`).Value)
+}
+
+func TestArguments(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name: "valid",
+			config: `
+				forward_to = []
+				rule {
+					match = "{job=\"api\"}"
+					by = ["code"]
+					outputs = ["sum"]
+				}
+			`,
+		},
+		{
+			name: "invalid interval",
+			config: `
+				forward_to = []
+				interval = "0s"
+			`,
+			err: "interval must be greater than 0",
+		},
+		{
+			name: "invalid selector",
+			config: `
+				forward_to = []
+				rule {
+					match = "{job="
+					outputs = ["sum"]
+				}
+			`,
+			err: `invalid match selector "{job="`,
+		},
+		{
+			name: "by and without",
+			config: `
+				forward_to = []
+				rule {
+					match = "up"
+					by = ["a"]
+					without = ["b"]
+					outputs = ["sum"]
+				}
+			`,
+			err: "at most one of by and without can be set",
+		},
+		{
+			name: "metric name label",
+			config: `
+				forward_to = []
+				rule {
+					match = "up"
+					without = ["__name__"]
+					outputs = ["sum"]
+				}
+			`,
+			err: `invalid label name "__name__"`,
+		},
+		{
+			name: "unknown output",
+			config: `
+				forward_to = []
+				rule {
+					match = "up"
+					outputs = ["rate"]
+				}
+			`,
+			err: `unknown output "rate"`,
+		},
+		{
+			name: "duplicate output",
+			config: `
+				forward_to = []
+				rule {
+					match = "up"
+					outputs = ["sum", "sum"]
+				}
+			`,
+			err: `output "sum" is set more than once`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tt.config), &args)
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func newTestComponent(t *testing.T, app testappender.CollectingAppender, config string) *Component {
+	t.Helper()
+
+	var args Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(config), &args))
+	args.ForwardTo = []storage.Appendable{testappender.ConstantAppendable{Inner: app}}
+
+	c, err := New(component.Options{
+		ID:             "prometheus.aggregate.test",
+		Logger:         util.TestAlloyLogger(t),
+		OnStateChange:  func(e component.Exports) {},
+		Registerer:     prom.NewRegistry(),
+		GetServiceData: getServiceData,
+	}, args)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	t.Cleanup(cancel)
+	go func() { require.NoError(t, c.Run(ctx)) }()
+	return c
+}
+
+func sampleLabels(app testappender.CollectingAppender) []string {
+	var res []string
+	for series := range app.CollectedSamples() {
+		res = append(res, series)
+	}
+	slices.Sort(res)
+	return res
+}
+
+func getServiceData(name string) (interface{}, error) {
+	switch name {
+	case labelstore.ServiceName:
+		return labelstore.New(nil, prom.DefaultRegisterer), nil
+	case livedebugging.ServiceName:
+		return livedebugging.NewLiveDebugging(), nil
+	default:
+		return nil, fmt.Errorf("service not found %s", name)
+	}
+}
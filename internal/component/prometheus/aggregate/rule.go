@@ -0,0 +1,208 @@
+package aggregate
+
+import (
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// Outputs which can be computed by an aggregation rule.
+const (
+	OutputSum   = "sum"
+	OutputAvg   = "avg"
+	OutputMin   = "min"
+	OutputMax   = "max"
+	OutputCount = "count"
+)
+
+var validOutputs = []string{OutputSum, OutputAvg, OutputMin, OutputMax, OutputCount}
+
+// Rule describes how to aggregate the series matching a selector.
+type Rule struct {
+	Match     string   `alloy:"match,attr"`
+	By        []string `alloy:"by,attr,optional"`
+	Without   []string `alloy:"without,attr,optional"`
+	Outputs   []string `alloy:"outputs,attr"`
+	KeepInput bool     `alloy:"keep_input,attr,optional"`
+}
+
+// Validate implements syntax.Validator.
+func (r *Rule) Validate() error {
+	if _, err := parser.ParseMetricSelector(r.Match); err != nil {
+		return fmt.Errorf("invalid match selector %q: %w", r.Match, err)
+	}
+	if len(r.By) > 0 && len(r.Without) > 0 {
+		return fmt.Errorf("at most one of by and without can be set")
+	}
+	for _, name := range append(slices.Clone(r.By), r.Without...) {
+		if !model.LabelName(name).IsValid() || name == model.MetricNameLabel {
+			return fmt.Errorf("invalid label name %q", name)
+		}
+	}
+
+	if len(r.Outputs) == 0 {
+		return fmt.Errorf("at least one output must be set")
+	}
+	for i, output := range r.Outputs {
+		if !slices.Contains(validOutputs, output) {
+			return fmt.Errorf("unknown output %q, must be one of %v", output, validOutputs)
+		}
+		if slices.Contains(r.Outputs[:i], output) {
+			return fmt.Errorf("output %q is set more than once", output)
+		}
+	}
+	return nil
+}
+
+// rule holds the series aggregated by a Rule during the current interval.
+type rule struct {
+	matchers  []*labels.Matcher
+	by        []string
+	without   []string
+	outputs   []string
+	keepInput bool
+
+	groups map[uint64]*group
+	// Output series written by the last flush, to write staleness markers for
+	// the ones which disappear.
+	written map[uint64]labels.Labels
+}
+
+// group is a set of input series with the same labels once aggregated.
+type group struct {
+	labels labels.Labels
+	values map[uint64]float64 // Last value of each input series, by hash.
+}
+
+func newRule(r Rule) (*rule, error) {
+	matchers, err := parser.ParseMetricSelector(r.Match)
+	if err != nil {
+		return nil, err
+	}
+	by := slices.Clone(r.By)
+	if len(by) > 0 {
+		by = append(by, model.MetricNameLabel)
+	}
+	return &rule{
+		matchers:  matchers,
+		by:        by,
+		without:   r.Without,
+		outputs:   r.Outputs,
+		keepInput: r.KeepInput,
+		groups:    make(map[uint64]*group),
+		written:   make(map[uint64]labels.Labels),
+	}, nil
+}
+
+// matches returns whether the series l is aggregated by the rule. Series
+// without a metric name are never aggregated.
+func (r *rule) matches(l labels.Labels) bool {
+	if l.Get(model.MetricNameLabel) == "" {
+		return false
+	}
+	for _, m := range r.matchers {
+		if !m.Matches(l.Get(m.Name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// record stores the last value of the series l for the current interval.
+func (r *rule) record(l labels.Labels, v float64) {
+	if math.IsNaN(v) && !value.IsStaleNaN(v) {
+		return
+	}
+
+	b := labels.NewBuilder(l)
+	switch {
+	case len(r.by) > 0:
+		b.Keep(r.by...)
+	case len(r.without) > 0:
+		b.Del(r.without...)
+	default:
+		b.Keep(model.MetricNameLabel)
+	}
+	groupLabels := b.Labels()
+
+	key := groupLabels.Hash()
+	g, ok := r.groups[key]
+	if !ok {
+		if value.IsStaleNaN(v) {
+			return
+		}
+		g = &group{labels: groupLabels, values: make(map[uint64]float64)}
+		r.groups[key] = g
+	}
+	if value.IsStaleNaN(v) {
+		// The series is gone and is no longer part of the aggregation.
+		delete(g.values, l.Hash())
+		return
+	}
+	g.values[l.Hash()] = v
+}
+
+// sample is an output sample of an aggregation rule.
+type sample struct {
+	labels labels.Labels
+	value  float64
+}
+
+// flush returns the output samples of the current interval, and staleness
+// markers for the output series of the previous interval which have no
+// sample in this one. The groups are reset for the next interval.
+func (r *rule) flush() []sample {
+	var (
+		res     []sample
+		written = make(map[uint64]labels.Labels)
+	)
+	for _, g := range r.groups {
+		if len(g.values) == 0 {
+			continue
+		}
+		name := g.labels.Get(model.MetricNameLabel)
+		for _, output := range r.outputs {
+			l := labels.NewBuilder(g.labels).Set(model.MetricNameLabel, name+":"+output).Labels()
+			res = append(res, sample{labels: l, value: compute(output, g.values)})
+			written[l.Hash()] = l
+		}
+	}
+	for hash, l := range r.written {
+		if _, ok := written[hash]; !ok {
+			res = append(res, sample{labels: l, value: math.Float64frombits(value.StaleNaN)})
+		}
+	}
+
+	r.groups = make(map[uint64]*group)
+	r.written = written
+	return res
+}
+
+func compute(output string, values map[uint64]float64) float64 {
+	var sum float64
+	minValue, maxValue := math.Inf(1), math.Inf(-1)
+	for _, v := range values {
+		sum += v
+		minValue = min(minValue, v)
+		maxValue = max(maxValue, v)
+	}
+
+	switch output {
+	case OutputSum:
+		return sum
+	case OutputAvg:
+		return sum / float64(len(values))
+	case OutputMin:
+		return minValue
+	case OutputMax:
+		return maxValue
+	case OutputCount:
+		return float64(len(values))
+	}
+	panic(fmt.Sprintf("unknown output %q", output))
+}
@@ -30,12 +30,46 @@ type Arguments struct {
 	URI             alloytypes.Secret `alloy:"mongodb_uri,attr"`
 	DirectConnect   bool              `alloy:"direct_connect,attr,optional"`
 	DiscoveringMode bool              `alloy:"discovering_mode,attr,optional"`
+	CompatibleMode  bool              `alloy:"compatible_mode,attr,optional"`
+
+	CollectAll             bool `alloy:"collect_all,attr,optional"`
+	EnableDiagnosticData   bool `alloy:"enable_diagnostic_data,attr,optional"`
+	EnableReplicasetStatus bool `alloy:"enable_replicaset_status,attr,optional"`
+	EnableDBStats          bool `alloy:"enable_db_stats,attr,optional"`
+	EnableTopMetrics       bool `alloy:"enable_top_metrics,attr,optional"`
+	EnableIndexStats       bool `alloy:"enable_index_stats,attr,optional"`
+	EnableCollStats        bool `alloy:"enable_coll_stats,attr,optional"`
+
+	CollStatsNamespaces  []string `alloy:"collstats_namespaces,attr,optional"`
+	IndexStatsNamespaces []string `alloy:"indexstats_namespaces,attr,optional"`
+}
+
+// DefaultArguments holds the default arguments for the
+// prometheus.exporter.mongodb component.
+var DefaultArguments = Arguments{
+	CompatibleMode: true,
+	CollectAll:     true,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *Arguments) SetToDefault() {
+	*a = DefaultArguments
 }
 
 func (a *Arguments) Convert() *mongodb_exporter.Config {
 	return &mongodb_exporter.Config{
-		URI:             config_util.Secret(a.URI),
-		DirectConnect:   a.DirectConnect,
-		DiscoveringMode: a.DiscoveringMode,
+		URI:                    config_util.Secret(a.URI),
+		DirectConnect:          a.DirectConnect,
+		DiscoveringMode:        a.DiscoveringMode,
+		CompatibleMode:         a.CompatibleMode,
+		CollectAll:             a.CollectAll,
+		EnableDiagnosticData:   a.EnableDiagnosticData,
+		EnableReplicasetStatus: a.EnableReplicasetStatus,
+		EnableDBStats:          a.EnableDBStats,
+		EnableTopMetrics:       a.EnableTopMetrics,
+		EnableIndexStats:       a.EnableIndexStats,
+		EnableCollStats:        a.EnableCollStats,
+		CollStatsNamespaces:    a.CollStatsNamespaces,
+		IndexStatsNamespaces:   a.IndexStatsNamespaces,
 	}
 }
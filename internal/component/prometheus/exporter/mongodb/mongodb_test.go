@@ -23,11 +23,46 @@ func TestAlloyUnmarshal(t *testing.T) {
 		URI:             "mongodb://127.0.0.1:27017",
 		DirectConnect:   true,
 		DiscoveringMode: true,
+		CompatibleMode:  true,
+		CollectAll:      true,
 	}
 
 	require.Equal(t, expected, args)
 }
 
+func TestAlloyUnmarshal_Collectors(t *testing.T) {
+	alloyConfig := `
+	mongodb_uri              = "mongodb://127.0.0.1:27017"
+	compatible_mode          = false
+	collect_all              = false
+	enable_diagnostic_data   = true
+	enable_replicaset_status = true
+	enable_db_stats          = true
+	enable_top_metrics       = true
+	enable_index_stats       = true
+	enable_coll_stats        = true
+	collstats_namespaces     = ["shop.orders", "shop.users"]
+	indexstats_namespaces    = ["shop.orders"]
+	`
+
+	var args Arguments
+	err := syntax.Unmarshal([]byte(alloyConfig), &args)
+	require.NoError(t, err)
+
+	expected := mongodb_exporter.Config{
+		URI:                    "mongodb://127.0.0.1:27017",
+		EnableDiagnosticData:   true,
+		EnableReplicasetStatus: true,
+		EnableDBStats:          true,
+		EnableTopMetrics:       true,
+		EnableIndexStats:       true,
+		EnableCollStats:        true,
+		CollStatsNamespaces:    []string{"shop.orders", "shop.users"},
+		IndexStatsNamespaces:   []string{"shop.orders"},
+	}
+	require.Equal(t, expected, *args.Convert())
+}
+
 func TestConvert(t *testing.T) {
 	alloyConfig := `
 	mongodb_uri = "mongodb://127.0.0.1:27017"
@@ -44,6 +79,8 @@ func TestConvert(t *testing.T) {
 		URI:             "mongodb://127.0.0.1:27017",
 		DirectConnect:   true,
 		DiscoveringMode: true,
+		CompatibleMode:  true,
+		CollectAll:      true,
 	}
 	require.Equal(t, expected, *res)
 }
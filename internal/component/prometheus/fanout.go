@@ -26,10 +26,11 @@ type Fanout struct {
 	// children is where to fan out.
 	children []storage.Appendable
 	// ComponentID is what component this belongs to.
-	componentID    string
-	writeLatency   prometheus.Histogram
-	samplesCounter prometheus.Counter
-	ls             labelstore.LabelStore
+	componentID       string
+	writeLatency      prometheus.Histogram
+	samplesCounter    prometheus.Counter
+	histogramsCounter prometheus.Counter
+	ls                labelstore.LabelStore
 
 	// lastSeriesCount stores the number of series that were sent through the last appender. It helps to estimate how
 	// much memory to allocate for the staleness trackers.
@@ -51,12 +52,19 @@ func NewFanout(children []storage.Appendable, componentID string, register prome
 	})
 	_ = register.Register(s)
 
+	hs := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "prometheus_forwarded_histogram_samples_total",
+		Help: "Total number of native histogram samples sent to downstream components.",
+	})
+	_ = register.Register(hs)
+
 	return &Fanout{
-		children:       children,
-		componentID:    componentID,
-		writeLatency:   wl,
-		samplesCounter: s,
-		ls:             ls,
+		children:          children,
+		componentID:       componentID,
+		writeLatency:      wl,
+		samplesCounter:    s,
+		histogramsCounter: hs,
+		ls:                ls,
 	}
 }
 
@@ -207,6 +215,7 @@ func (a *appender) UpdateMetadata(ref storage.SeriesRef, l labels.Labels, m meta
 	return ref, multiErr
 }
 
+// AppendHistogram satisfies the Appender interface.
 func (a *appender) AppendHistogram(ref storage.SeriesRef, l labels.Labels, t int64, h *histogram.Histogram, fh *histogram.FloatHistogram) (storage.SeriesRef, error) {
 	if a.start.IsZero() {
 		a.start = time.Now()
@@ -214,16 +223,40 @@ func (a *appender) AppendHistogram(ref storage.SeriesRef, l labels.Labels, t int
 	if ref == 0 {
 		ref = storage.SeriesRef(a.fanout.ls.GetOrAddGlobalRefID(l))
 	}
+	a.stalenessTrackers = append(a.stalenessTrackers, labelstore.StalenessTracker{
+		GlobalRefID: uint64(ref),
+		Labels:      l,
+		Value:       HistogramValue(h, fh),
+	})
 	var multiErr error
+	updated := false
 	for _, x := range a.children {
 		_, err := x.AppendHistogram(ref, l, t, h, fh)
 		if err != nil {
 			multiErr = multierror.Append(multiErr, err)
+		} else {
+			updated = true
 		}
 	}
+	if updated {
+		a.fanout.histogramsCounter.Inc()
+	}
 	return ref, multiErr
 }
 
+// HistogramValue returns the value used to track the staleness of a native
+// histogram sample. Stale native histograms are marked with a StaleNaN sum.
+func HistogramValue(h *histogram.Histogram, fh *histogram.FloatHistogram) float64 {
+	switch {
+	case h != nil:
+		return h.Sum
+	case fh != nil:
+		return fh.Sum
+	default:
+		return 0
+	}
+}
+
 func (a *appender) AppendCTZeroSample(ref storage.SeriesRef, l labels.Labels, t, ct int64) (storage.SeriesRef, error) {
 	if a.start.IsZero() {
 		a.start = time.Now()
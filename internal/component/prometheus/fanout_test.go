@@ -4,9 +4,12 @@ import (
 	"testing"
 
 	"github.com/grafana/alloy/internal/service/labelstore"
+	"github.com/grafana/alloy/internal/util/testappender"
 	"github.com/prometheus/client_golang/prometheus"
-
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/tsdbutil"
 
 	"github.com/stretchr/testify/require"
 )
@@ -26,3 +29,45 @@ func TestCommit(t *testing.T) {
 	err := app.Commit()
 	require.NoError(t, err)
 }
+
+func TestAppendHistogram(t *testing.T) {
+	ls := labelstore.New(nil, prometheus.DefaultRegisterer)
+	collector := testappender.NewCollectingAppender()
+	fanout := NewFanout([]storage.Appendable{testappender.ConstantAppendable{Inner: collector}}, "", prometheus.NewRegistry(), ls)
+
+	lbls := labels.FromStrings("__name__", "request_duration_seconds")
+	h := tsdbutil.GenerateTestHistogram(1)
+	fh := tsdbutil.GenerateTestFloatHistogram(2)
+
+	app := fanout.Appender(t.Context())
+	_, err := app.AppendHistogram(0, lbls, 10, h, nil)
+	require.NoError(t, err)
+	require.Equal(t, h, collector.LatestHistogramFor(lbls.String()).Histogram)
+
+	_, err = app.AppendHistogram(0, lbls, 20, nil, fh)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+
+	got := collector.LatestHistogramFor(lbls.String())
+	require.Nil(t, got.Histogram)
+	require.Equal(t, fh, got.FloatHistogram)
+	require.Equal(t, int64(20), got.Timestamp)
+	require.Equal(t, 2.0, testutil.ToFloat64(fanout.histogramsCounter))
+	require.Equal(t, 0.0, testutil.ToFloat64(fanout.samplesCounter))
+}
+
+func TestInterceptorAppendHistogram(t *testing.T) {
+	ls := labelstore.New(nil, prometheus.DefaultRegisterer)
+	collector := testappender.NewCollectingAppender()
+	interceptor := NewInterceptor(testappender.ConstantAppendable{Inner: collector}, ls)
+
+	lbls := labels.FromStrings("__name__", "request_duration_seconds")
+	h := tsdbutil.GenerateTestHistogram(1)
+
+	app := interceptor.Appender(t.Context())
+	_, err := app.AppendHistogram(0, lbls, 10, h, nil)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+	require.Equal(t, h, collector.LatestHistogramFor(lbls.String()).Histogram)
+	require.Equal(t, int64(1), interceptor.lastSeriesCount.Load())
+}
@@ -193,6 +193,7 @@ func (a *interceptappender) UpdateMetadata(
 	return a.child.UpdateMetadata(ref, l, m)
 }
 
+// AppendHistogram satisfies the Appender interface.
 func (a *interceptappender) AppendHistogram(
 	ref storage.SeriesRef,
 	l labels.Labels,
@@ -204,6 +205,11 @@ func (a *interceptappender) AppendHistogram(
 	if ref == 0 {
 		ref = storage.SeriesRef(a.ls.GetOrAddGlobalRefID(l))
 	}
+	a.stalenessTrackers = append(a.stalenessTrackers, labelstore.StalenessTracker{
+		GlobalRefID: uint64(ref),
+		Labels:      l,
+		Value:       HistogramValue(h, fh),
+	})
 
 	if a.interceptor.onAppendHistogram != nil {
 		return a.interceptor.onAppendHistogram(ref, l, t, h, fh, a.child)
@@ -3,19 +3,25 @@ package configgen
 // SEE https://github.com/prometheus-operator/prometheus-operator/blob/aa8222d7e9b66e9293ed11c9291ea70173021029/pkg/prometheus/promcfg.go
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	promopv1alpha1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1alpha1"
 	"github.com/prometheus-operator/prometheus-operator/pkg/namespacelabeler"
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/discovery"
+	"github.com/prometheus/prometheus/discovery/dns"
+	promhttp "github.com/prometheus/prometheus/discovery/http"
 	"github.com/prometheus/prometheus/discovery/targetgroup"
 	"github.com/prometheus/prometheus/model/relabel"
 )
 
 func (cg *ConfigGenerator) GenerateScrapeConfigConfigs(m *promopv1alpha1.ScrapeConfig) (cfg []*config.ScrapeConfig, errors []error) {
 	cfg, errors = cg.generateStaticScrapeConfigConfigs(m, cfg, errors)
+	cfg, errors = cg.generateHTTPSDScrapeConfigConfigs(m, cfg, errors)
+	cfg, errors = cg.generateDNSSDScrapeConfigConfigs(m, cfg, errors)
 	return
 }
 
@@ -35,10 +41,10 @@ func (cg *ConfigGenerator) generateStaticScrapeConfigConfig(m *promopv1alpha1.Sc
 	relabels := cg.initRelabelings()
 	metricRelabels := relabeler{}
 	cfg, err = cg.commonScrapeConfigConfig(m, i, &relabels, &metricRelabels)
-	cfg.JobName = fmt.Sprintf("scrapeConfig/%s/%s/static/%d", m.Namespace, m.Name, i)
 	if err != nil {
 		return nil, err
 	}
+	cfg.JobName = fmt.Sprintf("scrapeConfig/%s/%s/static/%d", m.Namespace, m.Name, i)
 	targets := []model.LabelSet{}
 	for _, target := range sc.Targets {
 		targets = append(targets, model.LabelSet{
@@ -67,6 +73,113 @@ func (cg *ConfigGenerator) generateStaticScrapeConfigConfig(m *promopv1alpha1.Sc
 	return cfg, cfg.Validate(cg.ScrapeOptions.GlobalConfig())
 }
 
+func (cg *ConfigGenerator) generateHTTPSDScrapeConfigConfigs(m *promopv1alpha1.ScrapeConfig, cfg []*config.ScrapeConfig, errors []error) ([]*config.ScrapeConfig, []error) {
+	for i, ep := range m.Spec.HTTPSDConfigs {
+		scrapeConfig, err := cg.generateHTTPSDScrapeConfigConfig(m, ep, i)
+		if err != nil {
+			errors = append(errors, err)
+		} else {
+			cfg = append(cfg, scrapeConfig)
+		}
+	}
+	return cfg, errors
+}
+
+func (cg *ConfigGenerator) generateHTTPSDScrapeConfigConfig(m *promopv1alpha1.ScrapeConfig, sc promopv1alpha1.HTTPSDConfig, i int) (cfg *config.ScrapeConfig, err error) {
+	relabels := cg.initRelabelings()
+	metricRelabels := relabeler{}
+	cfg, err = cg.commonScrapeConfigConfig(m, i, &relabels, &metricRelabels)
+	if err != nil {
+		return nil, err
+	}
+	cfg.JobName = fmt.Sprintf("scrapeConfig/%s/%s/httpsd/%d", m.Namespace, m.Name, i)
+
+	sdConfig := promhttp.DefaultSDConfig
+	sdConfig.URL = sc.URL
+	if sc.RefreshInterval != nil {
+		if sdConfig.RefreshInterval, err = model.ParseDuration(string(*sc.RefreshInterval)); err != nil {
+			return nil, fmt.Errorf("parsing refresh interval from httpSDConfig: %w", err)
+		}
+	}
+	if sc.TLSConfig != nil {
+		if sdConfig.HTTPClientConfig.TLSConfig, err = cg.generateSafeTLS(*sc.TLSConfig, m.Namespace); err != nil {
+			return nil, err
+		}
+	}
+	if sc.BasicAuth != nil {
+		if sdConfig.HTTPClientConfig.BasicAuth, err = cg.generateBasicAuth(*sc.BasicAuth, m.Namespace); err != nil {
+			return nil, err
+		}
+	}
+	if sc.Authorization != nil {
+		if sdConfig.HTTPClientConfig.Authorization, err = cg.generateAuthorization(*sc.Authorization, m.Namespace); err != nil {
+			return nil, err
+		}
+	}
+	if err = sdConfig.HTTPClientConfig.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid httpSDConfig: %w", err)
+	}
+
+	cfg.ServiceDiscoveryConfigs = append(cfg.ServiceDiscoveryConfigs, &sdConfig)
+	cfg.RelabelConfigs = relabels.configs
+	cfg.MetricRelabelConfigs = metricRelabels.configs
+	return cfg, cfg.Validate(cg.ScrapeOptions.GlobalConfig())
+}
+
+func (cg *ConfigGenerator) generateDNSSDScrapeConfigConfigs(m *promopv1alpha1.ScrapeConfig, cfg []*config.ScrapeConfig, errors []error) ([]*config.ScrapeConfig, []error) {
+	for i, ep := range m.Spec.DNSSDConfigs {
+		scrapeConfig, err := cg.generateDNSSDScrapeConfigConfig(m, ep, i)
+		if err != nil {
+			errors = append(errors, err)
+		} else {
+			cfg = append(cfg, scrapeConfig)
+		}
+	}
+	return cfg, errors
+}
+
+func (cg *ConfigGenerator) generateDNSSDScrapeConfigConfig(m *promopv1alpha1.ScrapeConfig, sc promopv1alpha1.DNSSDConfig, i int) (cfg *config.ScrapeConfig, err error) {
+	relabels := cg.initRelabelings()
+	metricRelabels := relabeler{}
+	cfg, err = cg.commonScrapeConfigConfig(m, i, &relabels, &metricRelabels)
+	if err != nil {
+		return nil, err
+	}
+	cfg.JobName = fmt.Sprintf("scrapeConfig/%s/%s/dnssd/%d", m.Namespace, m.Name, i)
+
+	sdConfig := dns.DefaultSDConfig
+	sdConfig.Names = sc.Names
+	if sc.RefreshInterval != nil {
+		if sdConfig.RefreshInterval, err = model.ParseDuration(string(*sc.RefreshInterval)); err != nil {
+			return nil, fmt.Errorf("parsing refresh interval from dnsSDConfig: %w", err)
+		}
+	}
+	if sc.Type != nil {
+		sdConfig.Type = strings.ToUpper(*sc.Type)
+	}
+	if sc.Port != nil {
+		sdConfig.Port = *sc.Port
+	}
+	// Same checks as the YAML unmarshaling of the DNS-SD config of Prometheus.
+	if len(sdConfig.Names) == 0 {
+		return nil, errors.New("dnsSDConfig must contain at least one name")
+	}
+	switch sdConfig.Type {
+	case "SRV":
+	case "A", "AAAA", "MX":
+		if sdConfig.Port == 0 {
+			return nil, errors.New("a port is required in dnsSDConfig for all record types except SRV")
+		}
+	default:
+		return nil, fmt.Errorf("invalid dnsSDConfig record type %s", sdConfig.Type)
+	}
+
+	cfg.ServiceDiscoveryConfigs = append(cfg.ServiceDiscoveryConfigs, &sdConfig)
+	cfg.RelabelConfigs = relabels.configs
+	cfg.MetricRelabelConfigs = metricRelabels.configs
+	return cfg, cfg.Validate(cg.ScrapeOptions.GlobalConfig())
+}
+
 func (cg *ConfigGenerator) commonScrapeConfigConfig(m *promopv1alpha1.ScrapeConfig, _ int, relabels *relabeler, metricRelabels *relabeler) (cfg *config.ScrapeConfig, err error) {
 	cfg = cg.generateDefaultScrapeConfig()
 	if m.Spec.HonorLabels != nil {
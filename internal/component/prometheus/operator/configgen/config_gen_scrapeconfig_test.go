@@ -12,12 +12,15 @@ import (
 	"github.com/prometheus/common/model"
 	"github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/discovery"
+	"github.com/prometheus/prometheus/discovery/dns"
+	promhttp "github.com/prometheus/prometheus/discovery/http"
 	"github.com/prometheus/prometheus/discovery/targetgroup"
 	"github.com/prometheus/prometheus/model/relabel"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v3"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
 
 	"github.com/grafana/alloy/internal/component/common/kubernetes"
 	alloy_relabel "github.com/grafana/alloy/internal/component/common/relabel"
@@ -130,3 +133,52 @@ func TestGenerateStaticScrapeConfigConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestGenerateSDScrapeConfigConfigs(t *testing.T) {
+	m := &promopv1alpha1.ScrapeConfig{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "operator",
+			Name:      "scrapeconfig",
+		},
+		Spec: promopv1alpha1.ScrapeConfigSpec{
+			HTTPSDConfigs: []promopv1alpha1.HTTPSDConfig{{
+				URL:             "http://sd.example.com/targets",
+				RefreshInterval: ptr.To(promopv1.Duration("5m")),
+			}},
+			DNSSDConfigs: []promopv1alpha1.DNSSDConfig{
+				{Names: []string{"_metrics._tcp.example.com"}},
+				{Names: []string{"example.com"}, Type: ptr.To("a"), Port: ptr.To(9100)},
+				{Names: []string{"example.com"}, Type: ptr.To("A")},
+			},
+		},
+	}
+	cg := &ConfigGenerator{
+		Client: &kubernetes.ClientArguments{},
+		ScrapeOptions: operator.ScrapeOptions{
+			DefaultScrapeInterval: time.Hour,
+			DefaultScrapeTimeout:  42 * time.Second,
+		},
+	}
+	cfgs, errs := cg.GenerateScrapeConfigConfigs(m)
+	require.Len(t, errs, 1)
+	require.ErrorContains(t, errs[0], "a port is required")
+	require.Len(t, cfgs, 3)
+
+	httpSD := promhttp.DefaultSDConfig
+	httpSD.URL = "http://sd.example.com/targets"
+	httpSD.RefreshInterval = model.Duration(5 * time.Minute)
+	require.Equal(t, "scrapeConfig/operator/scrapeconfig/httpsd/0", cfgs[0].JobName)
+	require.Equal(t, discovery.Configs{&httpSD}, cfgs[0].ServiceDiscoveryConfigs)
+
+	srvSD := dns.DefaultSDConfig
+	srvSD.Names = []string{"_metrics._tcp.example.com"}
+	require.Equal(t, "scrapeConfig/operator/scrapeconfig/dnssd/0", cfgs[1].JobName)
+	require.Equal(t, discovery.Configs{&srvSD}, cfgs[1].ServiceDiscoveryConfigs)
+
+	aSD := dns.DefaultSDConfig
+	aSD.Names = []string{"example.com"}
+	aSD.Type = "A"
+	aSD.Port = 9100
+	require.Equal(t, "scrapeConfig/operator/scrapeconfig/dnssd/1", cfgs[2].JobName)
+	require.Equal(t, discovery.Configs{&aSD}, cfgs[2].ServiceDiscoveryConfigs)
+}
@@ -1,4 +1,4 @@
-package podmonitors
+package scrapeconfigs
 
 import (
 	"github.com/grafana/alloy/internal/component"
@@ -0,0 +1,148 @@
+package receive_push
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+)
+
+const (
+	// pushPathPrefix is the prefix of the path of the push API. The rest of
+	// the path holds the grouping labels.
+	pushPathPrefix = "/metrics/"
+	// base64Suffix marks label names whose value is base64 encoded in the
+	// path.
+	base64Suffix = "@base64"
+	// pushTimeMetric is written for every group with the time of the last
+	// push.
+	pushTimeMetric = "push_time_seconds"
+)
+
+// sample is a single sample of a pushed series.
+type sample struct {
+	labels labels.Labels
+	value  float64
+}
+
+// group holds the metrics pushed with the same grouping labels.
+type group struct {
+	labels labels.Labels
+	pushed time.Time
+	// Samples of each metric family, by family name.
+	families map[string][]sample
+}
+
+// samples returns the samples of every series of the group, including the
+// push time of the group. If stale is true, the samples are stale markers.
+func (g *group) samples(stale bool) []sample {
+	pushTime := labels.NewBuilder(g.labels).Set(model.MetricNameLabel, pushTimeMetric).Labels()
+	res := []sample{{labels: pushTime, value: float64(g.pushed.UnixNano()) / 1e9}}
+	for _, samples := range g.families {
+		res = append(res, samples...)
+	}
+	if stale {
+		for i := range res {
+			res[i].value = math.Float64frombits(value.StaleNaN)
+		}
+	}
+	return res
+}
+
+// parseGroupingLabels parses the grouping labels from the path of a push
+// request, which is of the form /metrics/job/<JOB>{/<LABEL_NAME>/<LABEL_VALUE>}.
+// Label names suffixed with @base64 have a base64 encoded value.
+func parseGroupingLabels(path string) (labels.Labels, error) {
+	parts := strings.Split(strings.TrimPrefix(path, pushPathPrefix), "/")
+	if len(parts)%2 != 0 {
+		return labels.EmptyLabels(), fmt.Errorf("grouping labels must be pairs of label names and values")
+	}
+
+	b := labels.NewScratchBuilder(len(parts) / 2)
+	seen := make(map[string]struct{}, len(parts)/2)
+	for i := 0; i < len(parts); i += 2 {
+		name, val := parts[i], parts[i+1]
+		if n, ok := strings.CutSuffix(name, base64Suffix); ok {
+			name = n
+			decoded, err := decodeBase64(val)
+			if err != nil {
+				return labels.EmptyLabels(), fmt.Errorf("invalid base64 value of label %q: %w", name, err)
+			}
+			val = decoded
+		}
+		if i == 0 && name != model.JobLabel {
+			return labels.EmptyLabels(), fmt.Errorf("the first grouping label must be %q", model.JobLabel)
+		}
+		if !model.LabelName(name).IsValid() || strings.HasPrefix(name, model.ReservedLabelPrefix) {
+			return labels.EmptyLabels(), fmt.Errorf("invalid grouping label name %q", name)
+		}
+		if _, ok := seen[name]; ok {
+			return labels.EmptyLabels(), fmt.Errorf("grouping label %q is defined more than once", name)
+		}
+		seen[name] = struct{}{}
+		if name == model.JobLabel && val == "" {
+			return labels.EmptyLabels(), fmt.Errorf("the %q grouping label must not be empty", model.JobLabel)
+		}
+		b.Add(name, val)
+	}
+	b.Sort()
+	return b.Labels(), nil
+}
+
+// decodeBase64 decodes a URL-safe base64 value, with or without padding. A
+// single "=" encodes the empty string.
+func decodeBase64(s string) (string, error) {
+	b, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// parseFamilies parses the metric families of the body of a push request,
+// in the text or the delimited protobuf format according to the Content-Type
+// header, and returns the samples of each family. The grouping labels are
+// set on every series, overriding labels of the same name.
+func parseFamilies(r *http.Request, groupingLabels labels.Labels) (map[string][]sample, error) {
+	dec := expfmt.NewDecoder(r.Body, expfmt.ResponseFormat(r.Header))
+	res := make(map[string][]sample)
+	for {
+		var mf dto.MetricFamily
+		err := dec.Decode(&mf)
+		if errors.Is(err, io.EOF) {
+			return res, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if mf.GetName() == pushTimeMetric {
+			return nil, fmt.Errorf("pushed metrics must not be named %q", pushTimeMetric)
+		}
+
+		vec, err := expfmt.ExtractSamples(&expfmt.DecodeOptions{Timestamp: model.Now()}, &mf)
+		if err != nil {
+			return nil, err
+		}
+		samples := make([]sample, 0, len(vec))
+		for _, s := range vec {
+			b := labels.NewBuilder(labels.EmptyLabels())
+			for name, val := range s.Metric {
+				b.Set(string(name), string(val))
+			}
+			groupingLabels.Range(func(l labels.Label) {
+				b.Set(l.Name, l.Value)
+			})
+			samples = append(samples, sample{labels: b.Labels(), value: float64(s.Value)})
+		}
+		res[mf.GetName()] = samples
+	}
+}
@@ -0,0 +1,356 @@
+package receive_push
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/storage"
+
+	"github.com/grafana/alloy/internal/component"
+	fnet "github.com/grafana/alloy/internal/component/common/net"
+	alloyprom "github.com/grafana/alloy/internal/component/prometheus"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/service/labelstore"
+	"github.com/grafana/alloy/internal/util"
+)
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "prometheus.receive_push",
+		Stability: featuregate.StabilityExperimental,
+		Args:      Arguments{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			return New(opts, args.(Arguments))
+		},
+	})
+}
+
+// Arguments holds values which are used to configure the
+// prometheus.receive_push component.
+type Arguments struct {
+	Server    *fnet.ServerConfig   `alloy:",squash"`
+	ForwardTo []storage.Appendable `alloy:"forward_to,attr"`
+
+	// How often the samples of the pushed metrics are written.
+	Interval time.Duration `alloy:"interval,attr,optional"`
+	// How long a group is kept after its last push. Groups never expire if
+	// TTL is 0.
+	TTL time.Duration `alloy:"ttl,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = Arguments{
+		Server:   fnet.DefaultServerConfig(),
+		Interval: time.Minute,
+	}
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if args.Interval <= 0 {
+		return fmt.Errorf("interval must be greater than 0")
+	}
+	if args.TTL < 0 {
+		return fmt.Errorf("ttl must not be negative")
+	}
+	if args.TTL > 0 && args.TTL < args.Interval {
+		return fmt.Errorf("ttl must be greater than or equal to interval")
+	}
+	return nil
+}
+
+// Component implements the prometheus.receive_push component.
+type Component struct {
+	opts               component.Options
+	fanout             *alloyprom.Fanout
+	uncheckedCollector *util.UncheckedCollector
+	updated            chan struct{}
+
+	groupsCount   prometheus.Gauge
+	groupsExpired prometheus.Counter
+
+	updateMut sync.RWMutex
+	args      Arguments
+	server    *fnet.TargetServer
+
+	mut    sync.Mutex
+	groups map[string]*group
+}
+
+var _ component.Component = (*Component)(nil)
+
+// New creates a new prometheus.receive_push component.
+func New(opts component.Options, args Arguments) (*Component, error) {
+	service, err := opts.GetServiceData(labelstore.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+	ls := service.(labelstore.LabelStore)
+
+	uncheckedCollector := util.NewUncheckedCollector(nil)
+	opts.Registerer.MustRegister(uncheckedCollector)
+
+	c := &Component{
+		opts:               opts,
+		fanout:             alloyprom.NewFanout(args.ForwardTo, opts.ID, opts.Registerer, ls),
+		uncheckedCollector: uncheckedCollector,
+		updated:            make(chan struct{}, 1),
+		groups:             make(map[string]*group),
+	}
+	c.groupsCount = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "prometheus_receive_push_groups",
+		Help: "Number of groups of pushed metrics.",
+	})
+	c.groupsExpired = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "prometheus_receive_push_groups_expired_total",
+		Help: "Total number of groups of pushed metrics removed after their TTL.",
+	})
+	for _, metric := range []prometheus.Collector{c.groupsCount, c.groupsExpired} {
+		if err := opts.Registerer.Register(metric); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.Update(args); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Run satisfies the Component interface.
+func (c *Component) Run(ctx context.Context) error {
+	defer func() {
+		c.updateMut.Lock()
+		defer c.updateMut.Unlock()
+		c.shutdownServer()
+	}()
+
+	ticker := time.NewTicker(c.getInterval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			level.Info(c.opts.Logger).Log("msg", "terminating due to context done")
+			// Mark every series stale, since the pushed metrics are only kept
+			// in memory.
+			c.flush(context.Background(), true)
+			return nil
+		case <-c.updated:
+			ticker.Reset(c.getInterval())
+		case <-ticker.C:
+			c.flush(ctx, false)
+		}
+	}
+}
+
+// Update satisfies the Component interface.
+func (c *Component) Update(args component.Arguments) error {
+	newArgs := args.(Arguments)
+	c.fanout.UpdateChildren(newArgs.ForwardTo)
+
+	c.updateMut.Lock()
+	defer c.updateMut.Unlock()
+
+	defer func() {
+		select {
+		case c.updated <- struct{}{}:
+		default:
+		}
+	}()
+
+	serverNeedsUpdate := !reflect.DeepEqual(c.args.Server, newArgs.Server)
+	if !serverNeedsUpdate {
+		c.args = newArgs
+		return nil
+	}
+	c.shutdownServer()
+
+	s, err := c.createNewServer(newArgs)
+	if err != nil {
+		return err
+	}
+	c.server = s
+
+	err = c.server.MountAndRun(func(router *mux.Router) {
+		router.PathPrefix(pushPathPrefix).Methods(http.MethodPut, http.MethodPost, http.MethodDelete).HandlerFunc(c.handlePush)
+	})
+	if err != nil {
+		return err
+	}
+
+	c.args = newArgs
+	return nil
+}
+
+func (c *Component) getInterval() time.Duration {
+	c.updateMut.RLock()
+	defer c.updateMut.RUnlock()
+	return c.args.Interval
+}
+
+func (c *Component) getTTL() time.Duration {
+	c.updateMut.RLock()
+	defer c.updateMut.RUnlock()
+	return c.args.TTL
+}
+
+// handlePush handles the requests of the push API. PUT replaces every metric
+// of a group, POST replaces the metrics of a group with the same names as the
+// pushed ones, and DELETE removes a group.
+func (c *Component) handlePush(w http.ResponseWriter, r *http.Request) {
+	groupingLabels, err := parseGroupingLabels(r.URL.Path)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if r.Method == http.MethodDelete {
+		c.write(r.Context(), c.deleteGroup(groupingLabels.String()))
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	families, err := parseFamilies(r, groupingLabels)
+	if err != nil {
+		level.Debug(c.opts.Logger).Log("msg", "failed to parse pushed metrics", "err", err)
+		http.Error(w, fmt.Sprintf("failed to parse pushed metrics: %s", err), http.StatusBadRequest)
+		return
+	}
+	c.write(r.Context(), c.push(groupingLabels, families, r.Method == http.MethodPut))
+	w.WriteHeader(http.StatusOK)
+}
+
+// push stores the pushed metric families of a group, and returns the samples
+// to write: the new samples of the group, and stale markers for the series
+// which aren't part of the group anymore.
+func (c *Component) push(groupingLabels labels.Labels, families map[string][]sample, replace bool) []sample {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	key := groupingLabels.String()
+	g, ok := c.groups[key]
+	if !ok {
+		g = &group{labels: groupingLabels, families: make(map[string][]sample)}
+		c.groups[key] = g
+		c.groupsCount.Set(float64(len(c.groups)))
+	}
+	old := g.samples(true)
+
+	if replace {
+		g.families = families
+	} else {
+		for name, samples := range families {
+			g.families[name] = samples
+		}
+	}
+	g.pushed = time.Now()
+
+	res := g.samples(false)
+	current := make(map[uint64]struct{}, len(res))
+	for _, s := range res {
+		current[s.labels.Hash()] = struct{}{}
+	}
+	for _, s := range old {
+		if _, ok := current[s.labels.Hash()]; !ok {
+			res = append(res, s)
+		}
+	}
+	return res
+}
+
+// deleteGroup removes a group and returns stale markers for its series.
+func (c *Component) deleteGroup(key string) []sample {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	g, ok := c.groups[key]
+	if !ok {
+		return nil
+	}
+	delete(c.groups, key)
+	c.groupsCount.Set(float64(len(c.groups)))
+	return g.samples(true)
+}
+
+// flush writes the samples of every group. The groups which weren't pushed
+// for the TTL, or every group if final is true, are marked stale and removed.
+func (c *Component) flush(ctx context.Context, final bool) {
+	ttl := c.getTTL()
+
+	c.mut.Lock()
+	now := time.Now()
+	var samples []sample
+	for key, g := range c.groups {
+		expired := ttl > 0 && now.Sub(g.pushed) >= ttl
+		samples = append(samples, g.samples(final || expired)...)
+		if final || expired {
+			delete(c.groups, key)
+		}
+		if expired {
+			c.groupsExpired.Inc()
+		}
+	}
+	c.groupsCount.Set(float64(len(c.groups)))
+	c.mut.Unlock()
+
+	c.write(ctx, samples)
+}
+
+func (c *Component) write(ctx context.Context, samples []sample) {
+	if len(samples) == 0 {
+		return
+	}
+
+	ts := timestamp.FromTime(time.Now())
+	app := c.fanout.Appender(ctx)
+	for _, s := range samples {
+		if _, err := app.Append(0, s.labels, ts, s.value); err != nil {
+			level.Warn(c.opts.Logger).Log("msg", "failed to write sample", "labels", s.labels, "err", err)
+		}
+	}
+	if err := app.Commit(); err != nil {
+		level.Warn(c.opts.Logger).Log("msg", "failed to commit samples", "err", err)
+	}
+}
+
+func (c *Component) createNewServer(args Arguments) (*fnet.TargetServer, error) {
+	// [server.Server] registers new metrics every time it is created. To
+	// avoid issues with re-registering metrics with the same name, we create a
+	// new registry for the server every time we create one, and pass it to an
+	// unchecked collector to bypass uniqueness checking.
+	serverRegistry := prometheus.NewRegistry()
+	c.uncheckedCollector.SetCollector(serverRegistry)
+
+	s, err := fnet.NewTargetServer(
+		c.opts.Logger,
+		"prometheus_receive_push",
+		serverRegistry,
+		args.Server,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create server: %v", err)
+	}
+
+	return s, nil
+}
+
+// shutdownServer will shut down the currently used server.
+// It is not goroutine-safe and an updateMut write lock must be held when it's called.
+func (c *Component) shutdownServer() {
+	if c.server != nil {
+		c.server.StopAndShutdown()
+		c.server = nil
+	}
+}
@@ -0,0 +1,227 @@
+package receive_push
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/phayes/freeport"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component"
+	fnet "github.com/grafana/alloy/internal/component/common/net"
+	"github.com/grafana/alloy/internal/service/labelstore"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/internal/util/testappender"
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestPush(t *testing.T) {
+	collector := testappender.NewCollectingAppender()
+	port := getFreePort(t)
+	c := newTestComponent(t, collector, port)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		require.NoError(t, c.Run(ctx))
+	}()
+
+	url := fmt.Sprintf("http://localhost:%d/metrics/job/backup/instance@base64/ZGI6NTQzMg", port)
+	require.Equal(t, http.StatusOK, request(t, http.MethodPut, url, `
+# TYPE backup_duration_seconds histogram
+backup_duration_seconds_bucket{le="10"} 1
+backup_duration_seconds_sum 4
+backup_duration_seconds_count 1
+# TYPE backup_bytes gauge
+backup_bytes{job="overridden",table="users"} 1024
+`))
+
+	require.Equal(t, 1024.0, collector.LatestSampleFor(`{__name__="backup_bytes", instance="db:5432", job="backup", table="users"}`).Value)
+	require.Equal(t, 1.0, collector.LatestSampleFor(`{__name__="backup_duration_seconds_bucket", instance="db:5432", job="backup", le="10"}`).Value)
+	require.Equal(t, 1.0, collector.LatestSampleFor(`{__name__="backup_duration_seconds_bucket", instance="db:5432", job="backup", le="+Inf"}`).Value)
+	require.Equal(t, 4.0, collector.LatestSampleFor(`{__name__="backup_duration_seconds_sum", instance="db:5432", job="backup"}`).Value)
+	require.NotNil(t, collector.LatestSampleFor(`{__name__="push_time_seconds", instance="db:5432", job="backup"}`))
+
+	// POST only replaces the metrics with the same names.
+	require.Equal(t, http.StatusOK, request(t, http.MethodPost, url, `backup_bytes{table="orders"} 2048`+"\n"))
+	requireStale(t, collector, `{__name__="backup_bytes", instance="db:5432", job="backup", table="users"}`)
+	require.Equal(t, 2048.0, collector.LatestSampleFor(`{__name__="backup_bytes", instance="db:5432", job="backup", table="orders"}`).Value)
+	require.Equal(t, 4.0, collector.LatestSampleFor(`{__name__="backup_duration_seconds_sum", instance="db:5432", job="backup"}`).Value)
+
+	// PUT replaces every metric of the group.
+	require.Equal(t, http.StatusOK, request(t, http.MethodPut, url, `backup_bytes{table="orders"} 4096`+"\n"))
+	require.Equal(t, 4096.0, collector.LatestSampleFor(`{__name__="backup_bytes", instance="db:5432", job="backup", table="orders"}`).Value)
+	requireStale(t, collector, `{__name__="backup_duration_seconds_sum", instance="db:5432", job="backup"}`)
+
+	// Other groups aren't affected.
+	require.Equal(t, http.StatusOK, request(t, http.MethodPut, fmt.Sprintf("http://localhost:%d/metrics/job/cleanup", port), "cleanup_files 3\n"))
+
+	// DELETE removes the group.
+	require.Equal(t, http.StatusAccepted, request(t, http.MethodDelete, url, ""))
+	requireStale(t, collector, `{__name__="backup_bytes", instance="db:5432", job="backup", table="orders"}`)
+	requireStale(t, collector, `{__name__="push_time_seconds", instance="db:5432", job="backup"}`)
+	require.Equal(t, 3.0, collector.LatestSampleFor(`{__name__="cleanup_files", job="cleanup"}`).Value)
+
+	// Invalid requests are rejected.
+	require.Equal(t, http.StatusBadRequest, request(t, http.MethodPut, fmt.Sprintf("http://localhost:%d/metrics/instance/a", port), "up 1\n"))
+	require.Equal(t, http.StatusBadRequest, request(t, http.MethodPut, fmt.Sprintf("http://localhost:%d/metrics/job/a", port), "not a metric\n"))
+
+	// Every series is marked stale when the component stops.
+	cancel()
+	<-done
+	requireStale(t, collector, `{__name__="cleanup_files", job="cleanup"}`)
+}
+
+func TestTTL(t *testing.T) {
+	collector := testappender.NewCollectingAppender()
+	c := newTestComponent(t, collector, getFreePort(t))
+	require.NoError(t, c.Update(Arguments{
+		Server:    c.args.Server,
+		ForwardTo: c.args.ForwardTo,
+		Interval:  time.Minute,
+		TTL:       time.Hour,
+	}))
+
+	for _, job := range []string{"old", "new"} {
+		lbls := labels.FromStrings("job", job)
+		c.push(lbls, map[string][]sample{
+			"up": {{labels: labels.FromStrings("__name__", "up", "job", job), value: 1}},
+		}, true)
+	}
+	c.mut.Lock()
+	c.groups[labels.FromStrings("job", "old").String()].pushed = time.Now().Add(-2 * time.Hour)
+	c.mut.Unlock()
+
+	c.flush(t.Context(), false)
+	requireStale(t, collector, `{__name__="up", job="old"}`)
+	require.Equal(t, 1.0, collector.LatestSampleFor(`{__name__="up", job="new"}`).Value)
+	require.Len(t, c.groups, 1)
+}
+
+func TestParseGroupingLabels(t *testing.T) {
+	tests := []struct {
+		path   string
+		expect labels.Labels
+		err    string
+	}{
+		{path: "/metrics/job/a", expect: labels.FromStrings("job", "a")},
+		{path: "/metrics/job/a/instance/b/zone/c", expect: labels.FromStrings("job", "a", "instance", "b", "zone", "c")},
+		{path: "/metrics/job@base64/L3Zhci90bXA", expect: labels.FromStrings("job", "/var/tmp")},
+		{path: "/metrics/job/a/path@base64/=", expect: labels.FromStrings("job", "a", "path", "")},
+		{path: "/metrics/job/a/instance", err: "grouping labels must be pairs"},
+		{path: "/metrics/instance/a", err: `the first grouping label must be "job"`},
+		{path: "/metrics/job/a/job/b", err: `grouping label "job" is defined more than once`},
+		{path: "/metrics/job/a/__name__/b", err: `invalid grouping label name "__name__"`},
+		{path: "/metrics/job@base64/=", err: `the "job" grouping label must not be empty`},
+		{path: "/metrics/job@base64/!", err: `invalid base64 value of label "job"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			lbls, err := parseGroupingLabels(tt.path)
+			if tt.err != "" {
+				require.ErrorContains(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expect, lbls)
+		})
+	}
+}
+
+func TestArgumentsValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name:   "default",
+			config: `forward_to = []`,
+		},
+		{
+			name: "invalid interval",
+			config: `
+				forward_to = []
+				interval   = "0s"
+			`,
+			err: "interval must be greater than 0",
+		},
+		{
+			name: "ttl shorter than interval",
+			config: `
+				forward_to = []
+				interval   = "1m"
+				ttl        = "30s"
+			`,
+			err: "ttl must be greater than or equal to interval",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tt.config), &args)
+			if tt.err == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func newTestComponent(t *testing.T, app testappender.CollectingAppender, port int) *Component {
+	t.Helper()
+
+	var args Arguments
+	args.SetToDefault()
+	args.Server = &fnet.ServerConfig{
+		HTTP: &fnet.HTTPConfig{ListenAddress: "localhost", ListenPort: port},
+		GRPC: &fnet.GRPCConfig{ListenAddress: "127.0.0.1", ListenPort: getFreePort(t)},
+	}
+	args.ForwardTo = []storage.Appendable{testappender.ConstantAppendable{Inner: app}}
+
+	c, err := New(component.Options{
+		ID:         "prometheus.receive_push.test",
+		Logger:     util.TestAlloyLogger(t),
+		Registerer: prometheus.NewRegistry(),
+		GetServiceData: func(name string) (interface{}, error) {
+			return labelstore.New(nil, prometheus.DefaultRegisterer), nil
+		},
+	}, args)
+	require.NoError(t, err)
+	return c
+}
+
+func request(t *testing.T, method, url, body string) int {
+	t.Helper()
+
+	req, err := http.NewRequestWithContext(t.Context(), method, url, strings.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	require.NoError(t, resp.Body.Close())
+	return resp.StatusCode
+}
+
+func requireStale(t *testing.T, app testappender.CollectingAppender, series string) {
+	t.Helper()
+	s := app.LatestSampleFor(series)
+	require.NotNil(t, s, series)
+	require.True(t, value.IsStaleNaN(s.Value), series)
+}
+
+func getFreePort(t *testing.T) int {
+	p, err := freeport.GetFreePort()
+	require.NoError(t, err)
+	return p
+}
@@ -74,19 +74,20 @@ type Exports struct {
 
 // Component implements the prometheus.relabel component.
 type Component struct {
-	mut              sync.RWMutex
-	opts             component.Options
-	mrc              []*relabel.Config
-	receiver         *prometheus.Interceptor
-	metricsProcessed prometheus_client.Counter
-	metricsOutgoing  prometheus_client.Counter
-	cacheHits        prometheus_client.Counter
-	cacheMisses      prometheus_client.Counter
-	cacheSize        prometheus_client.Gauge
-	cacheDeletes     prometheus_client.Counter
-	fanout           *prometheus.Fanout
-	exited           atomic.Bool
-	ls               labelstore.LabelStore
+	mut                 sync.RWMutex
+	opts                component.Options
+	mrc                 []*relabel.Config
+	receiver            *prometheus.Interceptor
+	metricsProcessed    prometheus_client.Counter
+	metricsOutgoing     prometheus_client.Counter
+	histogramsProcessed prometheus_client.Counter
+	cacheHits           prometheus_client.Counter
+	cacheMisses         prometheus_client.Counter
+	cacheSize           prometheus_client.Gauge
+	cacheDeletes        prometheus_client.Counter
+	fanout              *prometheus.Fanout
+	exited              atomic.Bool
+	ls                  labelstore.LabelStore
 
 	debugDataPublisher livedebugging.DebugDataPublisher
 
@@ -129,6 +130,10 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		Name: "alloy_prometheus_relabel_metrics_written",
 		Help: "Total number of metrics written",
 	})
+	c.histogramsProcessed = prometheus_client.NewCounter(prometheus_client.CounterOpts{
+		Name: "alloy_prometheus_relabel_histograms_processed",
+		Help: "Total number of native histogram samples processed",
+	})
 	c.cacheMisses = prometheus_client.NewCounter(prometheus_client.CounterOpts{
 		Name: "alloy_prometheus_relabel_cache_misses",
 		Help: "Total number of cache misses",
@@ -146,7 +151,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		Help: "Total number of cache deletes",
 	})
 
-	for _, metric := range []prometheus_client.Collector{c.metricsProcessed, c.metricsOutgoing, c.cacheMisses, c.cacheHits, c.cacheSize, c.cacheDeletes} {
+	for _, metric := range []prometheus_client.Collector{c.metricsProcessed, c.metricsOutgoing, c.histogramsProcessed, c.cacheMisses, c.cacheHits, c.cacheSize, c.cacheDeletes} {
 		err = o.Registerer.Register(metric)
 		if err != nil {
 			return nil, err
@@ -196,10 +201,12 @@ func New(o component.Options, args Arguments) (*Component, error) {
 				return 0, fmt.Errorf("%s has exited", o.ID)
 			}
 
-			newLbl := c.relabel(0, l)
+			c.histogramsProcessed.Inc()
+			newLbl := c.relabel(prometheus.HistogramValue(h, fh), l)
 			if newLbl.IsEmpty() {
 				return 0, nil
 			}
+			c.metricsOutgoing.Inc()
 			return next.AppendHistogram(0, newLbl, t, h, fh)
 		}),
 	)
@@ -16,12 +16,14 @@ import (
 	"github.com/grafana/alloy/internal/service/labelstore"
 	"github.com/grafana/alloy/internal/service/livedebugging"
 	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/internal/util/testappender"
 	"github.com/grafana/alloy/syntax"
 	prom "github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/model/relabel"
 	"github.com/prometheus/prometheus/model/value"
 	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/tsdb/tsdbutil"
 	"github.com/stretchr/testify/require"
 )
 
@@ -122,6 +124,71 @@ func TestMetrics(t *testing.T) {
 	require.True(t, *(m.Counter.Value) == 1)
 }
 
+func TestHistograms(t *testing.T) {
+	collector := testappender.NewCollectingAppender()
+	var entry storage.Appendable
+	relabeller, err := New(component.Options{
+		ID:     "1",
+		Logger: util.TestAlloyLogger(t),
+		OnStateChange: func(e component.Exports) {
+			entry = e.(Exports).Receiver
+		},
+		Registerer:     prom.NewRegistry(),
+		GetServiceData: getServiceData,
+	}, Arguments{
+		ForwardTo: []storage.Appendable{testappender.ConstantAppendable{Inner: collector}},
+		MetricRelabelConfigs: []*alloy_relabel.Config{
+			{
+				SourceLabels: []string{"__address__"},
+				Regex:        alloy_relabel.Regexp(relabel.MustNewRegexp("(.+)")),
+				TargetLabel:  "new_label",
+				Replacement:  "new_value",
+				Action:       "replace",
+			},
+			{
+				SourceLabels: []string{"__name__"},
+				Regex:        alloy_relabel.Regexp(relabel.MustNewRegexp("dropped")),
+				Action:       "drop",
+			},
+		},
+		CacheSize: 100_000,
+	})
+	require.NoError(t, err)
+
+	h := tsdbutil.GenerateTestHistogram(1)
+	fh := tsdbutil.GenerateTestFloatHistogram(1)
+
+	app := entry.Appender(t.Context())
+	_, err = app.AppendHistogram(0, labels.FromStrings("__name__", "kept", "__address__", "localhost"), 10, h, nil)
+	require.NoError(t, err)
+	_, err = app.AppendHistogram(0, labels.FromStrings("__name__", "kept_float", "__address__", "localhost"), 10, nil, fh)
+	require.NoError(t, err)
+	_, err = app.AppendHistogram(0, labels.FromStrings("__name__", "dropped", "__address__", "localhost"), 10, h, nil)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+
+	require.Equal(t, h, collector.LatestHistogramFor(`{__address__="localhost", __name__="kept", new_label="new_value"}`).Histogram)
+	require.Equal(t, fh, collector.LatestHistogramFor(`{__address__="localhost", __name__="kept_float", new_label="new_value"}`).FloatHistogram)
+	require.Nil(t, collector.LatestHistogramFor(`{__address__="localhost", __name__="dropped", new_label="new_value"}`))
+
+	m := &dto.Metric{}
+	require.NoError(t, relabeller.histogramsProcessed.Write(m))
+	require.Equal(t, 3.0, m.Counter.GetValue())
+	require.NoError(t, relabeller.metricsOutgoing.Write(m))
+	require.Equal(t, 2.0, m.Counter.GetValue())
+
+	// Stale native histograms remove the series from the cache.
+	require.Equal(t, 3, relabeller.cache.Len())
+	stale := h.Copy()
+	stale.Sum = math.Float64frombits(value.StaleNaN)
+	app = entry.Appender(t.Context())
+	_, err = app.AppendHistogram(0, labels.FromStrings("__name__", "kept", "__address__", "localhost"), 20, stale, nil)
+	require.NoError(t, err)
+	require.NoError(t, app.Commit())
+	require.Equal(t, 2, relabeller.cache.Len())
+	require.True(t, value.IsStaleNaN(collector.LatestHistogramFor(`{__address__="localhost", __name__="kept", new_label="new_value"}`).Histogram.Sum))
+}
+
 func BenchmarkCache(b *testing.B) {
 	ls := labelstore.New(nil, prom.DefaultRegisterer)
 	fanout := prometheus.NewInterceptor(nil, ls, prometheus.WithAppendHook(func(ref storage.SeriesRef, l labels.Labels, _ int64, _ float64, _ storage.Appender) (storage.SeriesRef, error) {
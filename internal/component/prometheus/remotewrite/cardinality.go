@@ -0,0 +1,476 @@
+package remotewrite
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"golang.org/x/time/rate"
+)
+
+// Actions taken on the series of a metric name over the series limit.
+const (
+	CardinalityActionDrop      = "drop"
+	CardinalityActionAggregate = "aggregate"
+	CardinalityActionAlert     = "alert"
+)
+
+// overflowLabel is set on the series which aggregate the series over the
+// limit.
+const overflowLabel = "cardinality_overflow"
+
+// guardPruneInterval is how often idle series are forgotten by the
+// cardinality guard.
+var guardPruneInterval = time.Minute
+
+// DefaultCardinalityGuardOptions holds the default settings of the
+// cardinality_guard block.
+var DefaultCardinalityGuardOptions = CardinalityGuardOptions{
+	Action:      CardinalityActionDrop,
+	IdleTimeout: 10 * time.Minute,
+	KeepLabels:  []string{"le", "quantile"},
+}
+
+// CardinalityGuardOptions limits the number of active series and the rate of
+// samples of each metric name.
+type CardinalityGuardOptions struct {
+	MaxSeriesPerMetric int           `alloy:"max_series_per_metric,attr,optional"`
+	Action             string        `alloy:"action,attr,optional"`
+	IdleTimeout        time.Duration `alloy:"idle_timeout,attr,optional"`
+	KeepLabels         []string      `alloy:"keep_labels,attr,optional"`
+	RateLimit          float64       `alloy:"rate_limit,attr,optional"`
+	RateLimitBurst     int           `alloy:"rate_limit_burst,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (o *CardinalityGuardOptions) SetToDefault() {
+	*o = DefaultCardinalityGuardOptions
+	o.KeepLabels = slices.Clone(DefaultCardinalityGuardOptions.KeepLabels)
+}
+
+// Validate implements syntax.Validator.
+func (o *CardinalityGuardOptions) Validate() error {
+	switch o.Action {
+	case CardinalityActionDrop, CardinalityActionAggregate, CardinalityActionAlert:
+	default:
+		return fmt.Errorf("unknown cardinality_guard action %q, must be one of %q, %q or %q",
+			o.Action, CardinalityActionDrop, CardinalityActionAggregate, CardinalityActionAlert)
+	}
+
+	switch {
+	case o.MaxSeriesPerMetric < 0:
+		return fmt.Errorf("max_series_per_metric must not be negative")
+	case o.RateLimit < 0:
+		return fmt.Errorf("rate_limit must not be negative")
+	case o.RateLimitBurst < 0:
+		return fmt.Errorf("rate_limit_burst must not be negative")
+	case o.MaxSeriesPerMetric == 0 && o.RateLimit == 0:
+		return fmt.Errorf("at least one of max_series_per_metric and rate_limit must be set")
+	case o.IdleTimeout <= 0:
+		return fmt.Errorf("idle_timeout must be greater than 0")
+	}
+
+	for _, name := range o.KeepLabels {
+		if !model.LabelName(name).IsValid() || name == model.MetricNameLabel {
+			return fmt.Errorf("invalid label name %q in keep_labels", name)
+		}
+	}
+	return nil
+}
+
+// burst returns the number of samples of a metric name which can be appended
+// at once.
+func (o *CardinalityGuardOptions) burst() int {
+	if o.RateLimitBurst > 0 {
+		return o.RateLimitBurst
+	}
+	return max(1, int(math.Ceil(o.RateLimit)))
+}
+
+// guardResult is what must be done with a sample checked by the cardinality
+// guard.
+type guardResult int
+
+const (
+	guardAppend    guardResult = iota // Append the sample.
+	guardDrop                         // Drop the sample.
+	guardAggregate                    // Append the overflow sample instead.
+)
+
+// cardinalityGuard tracks the active series of each metric name appended to
+// the component.
+type cardinalityGuard struct {
+	log log.Logger
+	now func() time.Time
+
+	limitedSamples *prometheus.CounterVec
+	limitedMetrics prometheus.Gauge
+	trackedSeries  prometheus.Gauge
+
+	mut     sync.Mutex
+	opts    *CardinalityGuardOptions // nil when the guard is disabled.
+	metrics map[string]*guardedMetric
+}
+
+// guardedMetric holds the series of a single metric name.
+type guardedMetric struct {
+	series   map[uint64]time.Time       // Series under the limit, by hash, with the last time they were seen.
+	overflow map[uint64]*overflowSeries // Series over the limit, by hash.
+	groups   map[uint64]*overflowGroup  // Aggregated series, by hash of their labels.
+	limiter  *rate.Limiter
+}
+
+type overflowSeries struct {
+	lastSeen time.Time
+	value    float64
+	group    uint64
+}
+
+// overflowGroup is a series which aggregates the series over the limit with
+// the same kept labels.
+type overflowGroup struct {
+	labels  labels.Labels
+	sum     float64
+	members int
+	lastTs  int64
+}
+
+func newCardinalityGuard(logger log.Logger, reg prometheus.Registerer) *cardinalityGuard {
+	g := &cardinalityGuard{
+		log:     logger,
+		now:     time.Now,
+		metrics: make(map[string]*guardedMetric),
+
+		limitedSamples: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "prometheus_remote_write_cardinality_limited_samples_total",
+			Help: "Total number of samples of series over the series limit or over the rate limit of their metric name.",
+		}, []string{"reason"}),
+		limitedMetrics: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "prometheus_remote_write_cardinality_limited_metrics",
+			Help: "Number of metric names with more active series than the series limit.",
+		}),
+		trackedSeries: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "prometheus_remote_write_cardinality_tracked_series",
+			Help: "Number of active series tracked by the cardinality guard.",
+		}),
+	}
+	g.limitedSamples = util.MustRegisterOrGet(reg, g.limitedSamples).(*prometheus.CounterVec)
+	g.limitedMetrics = util.MustRegisterOrGet(reg, g.limitedMetrics).(prometheus.Gauge)
+	g.trackedSeries = util.MustRegisterOrGet(reg, g.trackedSeries).(prometheus.Gauge)
+	return g
+}
+
+// SetOptions updates the limits of the guard. Tracked series are forgotten
+// when the guard is disabled. When the series limit is lowered, the least
+// recently seen series over the new limit are forgotten, so that they're
+// checked against the new limit when they're seen again.
+func (g *cardinalityGuard) SetOptions(opts *CardinalityGuardOptions) {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+
+	if opts == nil {
+		g.metrics = make(map[string]*guardedMetric)
+	}
+	if g.opts != nil && opts != nil && (g.opts.Action != opts.Action ||
+		g.opts.MaxSeriesPerMetric != opts.MaxSeriesPerMetric || !slices.Equal(g.opts.KeepLabels, opts.KeepLabels)) {
+		// Series over the limit are tracked again, with their new overflow
+		// series, and may be under a raised limit.
+		for _, m := range g.metrics {
+			m.overflow = make(map[uint64]*overflowSeries)
+			m.groups = make(map[uint64]*overflowGroup)
+			if opts.MaxSeriesPerMetric > 0 {
+				trimSeries(m, opts.MaxSeriesPerMetric)
+			}
+		}
+	}
+	g.opts = opts
+
+	for _, m := range g.metrics {
+		m.limiter = g.newLimiter()
+	}
+	g.updateGauges()
+}
+
+// trimSeries forgets the least recently seen series of m over the limit.
+func trimSeries(m *guardedMetric, limit int) {
+	if len(m.series) <= limit {
+		return
+	}
+	hashes := make([]uint64, 0, len(m.series))
+	for hash := range m.series {
+		hashes = append(hashes, hash)
+	}
+	sort.Slice(hashes, func(i, j int) bool { return m.series[hashes[i]].After(m.series[hashes[j]]) })
+	for _, hash := range hashes[limit:] {
+		delete(m.series, hash)
+	}
+}
+
+func (g *cardinalityGuard) newLimiter() *rate.Limiter {
+	if g.opts == nil || g.opts.RateLimit == 0 {
+		return nil
+	}
+	return rate.NewLimiter(rate.Limit(g.opts.RateLimit), g.opts.burst())
+}
+
+// CheckSample checks a float sample of the series l. When the result is
+// guardAggregate, the sample of the overflow series with the returned labels
+// and value must be appended instead.
+func (g *cardinalityGuard) CheckSample(l labels.Labels, t int64, v float64) (guardResult, labels.Labels, float64) {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+	if g.opts == nil {
+		return guardAppend, nil, 0
+	}
+
+	m, hash, limited := g.track(l)
+	if limited && g.opts.Action == CardinalityActionAggregate {
+		return g.aggregate(m, hash, t, v)
+	}
+	if value.IsStaleNaN(v) {
+		// The series is gone, so it no longer counts towards the limit.
+		delete(m.series, hash)
+		g.removeOverflow(m, hash)
+	}
+	return g.result(m, limited), nil, 0
+}
+
+// CheckHistogram checks a histogram sample of the series l. Histograms of
+// series over the limit are dropped unless the action is alert.
+func (g *cardinalityGuard) CheckHistogram(l labels.Labels) guardResult {
+	return g.checkSeries(l, true)
+}
+
+// CheckExemplar checks an exemplar of the series l. Exemplars of series over
+// the limit are dropped unless the action is alert, but they don't count
+// towards the rate limit.
+func (g *cardinalityGuard) CheckExemplar(l labels.Labels) guardResult {
+	return g.checkSeries(l, false)
+}
+
+func (g *cardinalityGuard) checkSeries(l labels.Labels, sample bool) guardResult {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+	if g.opts == nil {
+		return guardAppend
+	}
+
+	m, _, limited := g.track(l)
+	if !sample {
+		if limited && g.opts.Action != CardinalityActionAlert {
+			return guardDrop
+		}
+		return guardAppend
+	}
+	if limited && g.opts.Action == CardinalityActionAggregate {
+		g.limitedSamples.WithLabelValues("series_limit").Inc()
+		return guardDrop
+	}
+	return g.result(m, limited)
+}
+
+// result applies the action and the rate limit of the metric to a sample.
+func (g *cardinalityGuard) result(m *guardedMetric, limited bool) guardResult {
+	res := guardAppend
+	if limited {
+		g.limitedSamples.WithLabelValues("series_limit").Inc()
+		if g.opts.Action == CardinalityActionDrop {
+			res = guardDrop
+		}
+	}
+	if res == guardAppend && m.limiter != nil && !m.limiter.AllowN(g.now(), 1) {
+		g.limitedSamples.WithLabelValues("rate_limit").Inc()
+		if g.opts.Action != CardinalityActionAlert {
+			res = guardDrop
+		}
+	}
+	return res
+}
+
+// track marks the series l as seen and returns whether it's over the limit of
+// its metric name.
+func (g *cardinalityGuard) track(l labels.Labels) (*guardedMetric, uint64, bool) {
+	name := l.Get(model.MetricNameLabel)
+	m, ok := g.metrics[name]
+	if !ok {
+		m = &guardedMetric{
+			series:   make(map[uint64]time.Time),
+			overflow: make(map[uint64]*overflowSeries),
+			groups:   make(map[uint64]*overflowGroup),
+			limiter:  g.newLimiter(),
+		}
+		g.metrics[name] = m
+	}
+
+	now := g.now()
+	hash := l.Hash()
+	if _, ok := m.series[hash]; ok {
+		m.series[hash] = now
+		return m, hash, false
+	}
+	if s, ok := m.overflow[hash]; ok {
+		s.lastSeen = now
+		return m, hash, true
+	}
+
+	if g.opts.MaxSeriesPerMetric == 0 || len(m.series) < g.opts.MaxSeriesPerMetric {
+		m.series[hash] = now
+		g.updateGauges()
+		return m, hash, false
+	}
+
+	if len(m.overflow) == 0 {
+		level.Warn(g.log).Log("msg", "metric exceeded the series limit", "metric", name,
+			"limit", g.opts.MaxSeriesPerMetric, "action", g.opts.Action)
+	}
+	m.overflow[hash] = &overflowSeries{lastSeen: now, group: g.groupOf(m, l)}
+	g.updateGauges()
+	return m, hash, true
+}
+
+// groupOf returns the hash of the overflow series which aggregates the series
+// l, creating the group if needed.
+func (g *cardinalityGuard) groupOf(m *guardedMetric, l labels.Labels) uint64 {
+	b := labels.NewScratchBuilder(len(g.opts.KeepLabels) + 2)
+	b.Add(model.MetricNameLabel, l.Get(model.MetricNameLabel))
+	b.Add(overflowLabel, "true")
+	for _, name := range g.opts.KeepLabels {
+		if v := l.Get(name); v != "" {
+			b.Add(name, v)
+		}
+	}
+	b.Sort()
+	groupLabels := b.Labels()
+
+	hash := groupLabels.Hash()
+	if _, ok := m.groups[hash]; !ok {
+		m.groups[hash] = &overflowGroup{labels: groupLabels, lastTs: math.MinInt64}
+	}
+	m.groups[hash].members++
+	return hash
+}
+
+// aggregate adds the sample of a series over the limit to the sum of its
+// overflow series. The overflow series is appended at most once per
+// timestamp so that backends don't receive duplicate samples.
+func (g *cardinalityGuard) aggregate(m *guardedMetric, hash uint64, t int64, v float64) (guardResult, labels.Labels, float64) {
+	g.limitedSamples.WithLabelValues("series_limit").Inc()
+
+	s := m.overflow[hash]
+	group := m.groups[s.group]
+	if value.IsStaleNaN(v) {
+		g.removeOverflow(m, hash)
+		return guardDrop, nil, 0
+	}
+	if math.IsNaN(v) {
+		return guardDrop, nil, 0
+	}
+
+	group.sum += v - s.value
+	s.value = v
+	if t <= group.lastTs {
+		return guardDrop, nil, 0
+	}
+	if m.limiter != nil && !m.limiter.AllowN(g.now(), 1) {
+		g.limitedSamples.WithLabelValues("rate_limit").Inc()
+		return guardDrop, nil, 0
+	}
+	group.lastTs = t
+	return guardAggregate, group.labels, group.sum
+}
+
+func (g *cardinalityGuard) removeOverflow(m *guardedMetric, hash uint64) {
+	s, ok := m.overflow[hash]
+	if !ok {
+		return
+	}
+	delete(m.overflow, hash)
+
+	group := m.groups[s.group]
+	group.sum -= s.value
+	if group.members--; group.members == 0 {
+		delete(m.groups, s.group)
+	}
+}
+
+// Prune forgets the series which weren't seen for the idle timeout, and the
+// metric names without series.
+func (g *cardinalityGuard) Prune() {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+	if g.opts == nil {
+		return
+	}
+
+	cutoff := g.now().Add(-g.opts.IdleTimeout)
+	for name, m := range g.metrics {
+		for hash, lastSeen := range m.series {
+			if lastSeen.Before(cutoff) {
+				delete(m.series, hash)
+			}
+		}
+		for hash, s := range m.overflow {
+			if s.lastSeen.Before(cutoff) {
+				g.removeOverflow(m, hash)
+			}
+		}
+		if len(m.series) == 0 && len(m.overflow) == 0 {
+			delete(g.metrics, name)
+		}
+	}
+	g.updateGauges()
+}
+
+// updateGauges must be called with mut held.
+func (g *cardinalityGuard) updateGauges() {
+	var series, limited int
+	for _, m := range g.metrics {
+		series += len(m.series) + len(m.overflow)
+		if len(m.overflow) > 0 {
+			limited++
+		}
+	}
+	g.trackedSeries.Set(float64(series))
+	g.limitedMetrics.Set(float64(limited))
+}
+
+// LimitedMetrics returns the metric names over the series limit, sorted by
+// name.
+func (g *cardinalityGuard) LimitedMetrics() []LimitedMetricDebugInfo {
+	g.mut.Lock()
+	defer g.mut.Unlock()
+
+	var res []LimitedMetricDebugInfo
+	for name, m := range g.metrics {
+		if len(m.overflow) == 0 {
+			continue
+		}
+		res = append(res, LimitedMetricDebugInfo{
+			Name:          name,
+			ActiveSeries:  len(m.series) + len(m.overflow),
+			LimitedSeries: len(m.overflow),
+		})
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].Name < res[j].Name })
+	return res
+}
+
+// equalGuardOptions returns whether the two options hold the same limits.
+func equalGuardOptions(a, b *CardinalityGuardOptions) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.MaxSeriesPerMetric == b.MaxSeriesPerMetric && a.Action == b.Action &&
+		a.IdleTimeout == b.IdleTimeout && slices.Equal(a.KeepLabels, b.KeepLabels) &&
+		a.RateLimit == b.RateLimit && a.RateLimitBurst == b.RateLimitBurst
+}
@@ -0,0 +1,204 @@
+package remotewrite
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/util"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGuard(t *testing.T, opts CardinalityGuardOptions) (*cardinalityGuard, *time.Time) {
+	t.Helper()
+	require.NoError(t, opts.Validate())
+
+	now := time.Unix(1000, 0)
+	g := newCardinalityGuard(util.TestLogger(t), prometheus.NewRegistry())
+	g.now = func() time.Time { return now }
+	g.SetOptions(&opts)
+	return g, &now
+}
+
+func series(name, pod string) labels.Labels {
+	return labels.FromStrings("__name__", name, "pod", pod)
+}
+
+func TestCardinalityGuard_Drop(t *testing.T) {
+	opts := DefaultCardinalityGuardOptions
+	opts.MaxSeriesPerMetric = 2
+	g, _ := newTestGuard(t, opts)
+
+	for _, pod := range []string{"a", "b"} {
+		res, _, _ := g.CheckSample(series("requests", pod), 1, 1)
+		require.Equal(t, guardAppend, res)
+	}
+	res, _, _ := g.CheckSample(series("requests", "c"), 1, 1)
+	require.Equal(t, guardDrop, res)
+	require.Equal(t, guardDrop, g.CheckHistogram(series("requests", "c")))
+	require.Equal(t, guardDrop, g.CheckExemplar(series("requests", "c")))
+
+	// Known series and other metric names aren't limited.
+	res, _, _ = g.CheckSample(series("requests", "a"), 2, 1)
+	require.Equal(t, guardAppend, res)
+	res, _, _ = g.CheckSample(series("errors", "c"), 1, 1)
+	require.Equal(t, guardAppend, res)
+
+	require.Equal(t, []LimitedMetricDebugInfo{{Name: "requests", ActiveSeries: 3, LimitedSeries: 1}}, g.LimitedMetrics())
+	require.Equal(t, 2.0, testutil.ToFloat64(g.limitedSamples.WithLabelValues("series_limit")))
+	require.Equal(t, 1.0, testutil.ToFloat64(g.limitedMetrics))
+	require.Equal(t, 4.0, testutil.ToFloat64(g.trackedSeries))
+
+	// Stale markers free the slot of the series.
+	res, _, _ = g.CheckSample(series("requests", "b"), 3, math.Float64frombits(value.StaleNaN))
+	require.Equal(t, guardAppend, res)
+	res, _, _ = g.CheckSample(series("requests", "d"), 3, 1)
+	require.Equal(t, guardAppend, res)
+}
+
+func TestCardinalityGuard_Alert(t *testing.T) {
+	opts := DefaultCardinalityGuardOptions
+	opts.MaxSeriesPerMetric = 1
+	opts.Action = CardinalityActionAlert
+	g, _ := newTestGuard(t, opts)
+
+	for _, pod := range []string{"a", "b", "c"} {
+		res, _, _ := g.CheckSample(series("requests", pod), 1, 1)
+		require.Equal(t, guardAppend, res)
+	}
+	require.Equal(t, guardAppend, g.CheckExemplar(series("requests", "c")))
+	require.Equal(t, 2.0, testutil.ToFloat64(g.limitedSamples.WithLabelValues("series_limit")))
+	require.Equal(t, []LimitedMetricDebugInfo{{Name: "requests", ActiveSeries: 3, LimitedSeries: 2}}, g.LimitedMetrics())
+}
+
+func TestCardinalityGuard_Aggregate(t *testing.T) {
+	opts := DefaultCardinalityGuardOptions
+	opts.MaxSeriesPerMetric = 1
+	opts.Action = CardinalityActionAggregate
+	g, _ := newTestGuard(t, opts)
+
+	res, _, _ := g.CheckSample(series("requests", "a"), 1, 10)
+	require.Equal(t, guardAppend, res)
+
+	overflow := labels.FromStrings("__name__", "requests", "cardinality_overflow", "true")
+	res, l, v := g.CheckSample(series("requests", "b"), 1, 1)
+	require.Equal(t, guardAggregate, res)
+	require.Equal(t, overflow, l)
+	require.Equal(t, 1.0, v)
+
+	// The overflow series is only written once per timestamp.
+	res, _, _ = g.CheckSample(series("requests", "c"), 1, 2)
+	require.Equal(t, guardDrop, res)
+
+	res, l, v = g.CheckSample(series("requests", "b"), 2, 5)
+	require.Equal(t, guardAggregate, res)
+	require.Equal(t, overflow, l)
+	require.Equal(t, 7.0, v)
+
+	// Kept labels split the overflow series.
+	res, l, v = g.CheckSample(labels.FromStrings("__name__", "requests", "pod", "d", "le", "1"), 2, 3)
+	require.Equal(t, guardAggregate, res)
+	require.Equal(t, labels.FromStrings("__name__", "requests", "cardinality_overflow", "true", "le", "1"), l)
+	require.Equal(t, 3.0, v)
+
+	// Histograms can't be aggregated.
+	require.Equal(t, guardDrop, g.CheckHistogram(series("requests", "e")))
+}
+
+func TestCardinalityGuard_RateLimit(t *testing.T) {
+	opts := DefaultCardinalityGuardOptions
+	opts.RateLimit = 1
+	opts.RateLimitBurst = 2
+	g, now := newTestGuard(t, opts)
+
+	for i, expect := range []guardResult{guardAppend, guardAppend, guardDrop} {
+		res, _, _ := g.CheckSample(series("requests", "a"), int64(i), 1)
+		require.Equal(t, expect, res)
+	}
+	// The limit is per metric name.
+	res, _, _ := g.CheckSample(series("errors", "a"), 1, 1)
+	require.Equal(t, guardAppend, res)
+
+	*now = now.Add(time.Second)
+	res, _, _ = g.CheckSample(series("requests", "a"), 4, 1)
+	require.Equal(t, guardAppend, res)
+	require.Equal(t, 1.0, testutil.ToFloat64(g.limitedSamples.WithLabelValues("rate_limit")))
+}
+
+func TestCardinalityGuard_Prune(t *testing.T) {
+	opts := DefaultCardinalityGuardOptions
+	opts.MaxSeriesPerMetric = 1
+	g, now := newTestGuard(t, opts)
+
+	res, _, _ := g.CheckSample(series("requests", "a"), 1, 1)
+	require.Equal(t, guardAppend, res)
+	res, _, _ = g.CheckSample(series("requests", "b"), 1, 1)
+	require.Equal(t, guardDrop, res)
+
+	*now = now.Add(opts.IdleTimeout + time.Second)
+	g.Prune()
+	require.Empty(t, g.LimitedMetrics())
+	require.Zero(t, testutil.ToFloat64(g.trackedSeries))
+
+	res, _, _ = g.CheckSample(series("requests", "b"), 2, 1)
+	require.Equal(t, guardAppend, res)
+}
+
+func TestCardinalityGuard_SetOptions(t *testing.T) {
+	opts := DefaultCardinalityGuardOptions
+	opts.MaxSeriesPerMetric = 3
+	g, now := newTestGuard(t, opts)
+
+	for _, pod := range []string{"a", "b", "c"} {
+		*now = now.Add(time.Second)
+		res, _, _ := g.CheckSample(series("requests", pod), 1, 1)
+		require.Equal(t, guardAppend, res)
+	}
+
+	// Lowering the limit forgets the least recently seen series over it.
+	lowered := opts
+	lowered.MaxSeriesPerMetric = 1
+	g.SetOptions(&lowered)
+	require.Equal(t, 1.0, testutil.ToFloat64(g.trackedSeries))
+
+	res, _, _ := g.CheckSample(series("requests", "c"), 2, 1)
+	require.Equal(t, guardAppend, res)
+	for _, pod := range []string{"a", "b"} {
+		res, _, _ := g.CheckSample(series("requests", pod), 2, 1)
+		require.Equal(t, guardDrop, res)
+	}
+	require.Equal(t, []LimitedMetricDebugInfo{{Name: "requests", ActiveSeries: 3, LimitedSeries: 2}}, g.LimitedMetrics())
+
+	// Raising the limit lets the series over the old limit through.
+	g.SetOptions(&opts)
+	for _, pod := range []string{"a", "b", "c"} {
+		res, _, _ := g.CheckSample(series("requests", pod), 3, 1)
+		require.Equal(t, guardAppend, res)
+	}
+	require.Empty(t, g.LimitedMetrics())
+}
+
+func TestCardinalityGuardOptions_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(o *CardinalityGuardOptions)
+		err    string
+	}{
+		{"no limit", func(o *CardinalityGuardOptions) {}, "at least one of max_series_per_metric and rate_limit must be set"},
+		{"unknown action", func(o *CardinalityGuardOptions) { o.MaxSeriesPerMetric, o.Action = 1, "sample" }, `unknown cardinality_guard action "sample"`},
+		{"negative limit", func(o *CardinalityGuardOptions) { o.MaxSeriesPerMetric = -1 }, "max_series_per_metric must not be negative"},
+		{"no idle timeout", func(o *CardinalityGuardOptions) { o.RateLimit, o.IdleTimeout = 1, 0 }, "idle_timeout must be greater than 0"},
+		{"metric name label", func(o *CardinalityGuardOptions) { o.RateLimit, o.KeepLabels = 1, []string{"__name__"} }, `invalid label name "__name__" in keep_labels`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := DefaultCardinalityGuardOptions
+			tt.modify(&opts)
+			require.ErrorContains(t, opts.Validate(), tt.err)
+		})
+	}
+}
@@ -2,9 +2,13 @@ package remotewrite
 
 import (
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/grafana/alloy/internal/static/agentctl/waltools"
 	"github.com/olekukonko/tablewriter"
@@ -18,6 +22,7 @@ func InstallTools(cmd *cobra.Command) {
 		samplesCmd(),
 		targetStatsCmd(),
 		walStatsCmd(),
+		walDumpCmd(),
 	)
 }
 
@@ -203,6 +208,184 @@ deletion but then comes back at some point).`,
 	}
 }
 
+func walDumpCmd() *cobra.Command {
+	var (
+		storagePath string
+		componentID string
+		selector    string
+		series      bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "wal-dump [WAL directory]",
+		Short: "Dump the segments and series of the WAL",
+		Long: `wal-dump reads the WAL of a prometheus.remote_write component offline and
+prints the total number of series and samples, the oldest and newest sample
+timestamps, statistics for each segment, and the number of samples and the
+timestamp range of each series.
+
+The segments which aren't truncated yet hold the samples which may not have
+been sent to the remote endpoints, so comparing their timestamps with the
+current time shows how far behind remote_write is.
+
+Provide either the WAL directory or the ID of the component with --id. With
+--id, the WAL is looked up in the directory set with --storage.path, which must
+match the one of the run command.
+
+Examples:
+
+Dump the WAL of the prometheus.remote_write.default component:
+
+wal-dump --id prometheus.remote_write.default
+
+
+Dump the 'up' series of a WAL directory:
+
+wal-dump -s up /tmp/wal
+`,
+		Args: cobra.MaximumNArgs(1),
+
+		Run: func(_ *cobra.Command, args []string) {
+			var directory string
+			switch {
+			case len(args) == 1 && componentID != "":
+				fmt.Println("only one of the WAL directory and --id can be provided")
+				os.Exit(1)
+			case len(args) == 1:
+				directory = args[0]
+			case componentID != "":
+				directory = filepath.Join(storagePath, componentID)
+			default:
+				fmt.Println("either the WAL directory or --id must be provided")
+				os.Exit(1)
+			}
+
+			if _, err := os.Stat(directory); os.IsNotExist(err) {
+				fmt.Printf("%s does not exist\n", directory)
+				os.Exit(1)
+			} else if err != nil {
+				fmt.Printf("error getting wal: %v\n", err)
+				os.Exit(1)
+			}
+
+			// Check if ./wal is a subdirectory, use that instead.
+			if _, err := os.Stat(filepath.Join(directory, "wal")); err == nil {
+				directory = filepath.Join(directory, "wal")
+			}
+
+			if err := dumpWAL(os.Stdout, directory, selector, series); err != nil {
+				fmt.Printf("failed to dump WAL: %v\n", err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&storagePath, "storage.path", "data-alloy/", "base directory where components store data, used with --id")
+	cmd.Flags().StringVar(&componentID, "id", "", "ID of the prometheus.remote_write component whose WAL is dumped")
+	cmd.Flags().StringVarP(&selector, "selector", "s", "{}", "label selector of the series to print")
+	cmd.Flags().BoolVar(&series, "series", true, "print the statistics of each series")
+	_ = cmd.MarkFlagDirname("storage.path")
+	_ = cmd.RegisterFlagCompletionFunc("id", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+		return componentIDs(storagePath), cobra.ShellCompDirectiveNoFileComp
+	})
+	return cmd
+}
+
+// componentIDs returns the IDs of the prometheus.remote_write components with
+// a data directory in storagePath.
+func componentIDs(storagePath string) []string {
+	entries, err := os.ReadDir(storagePath)
+	if err != nil {
+		return nil
+	}
+	var ids []string
+	for _, e := range entries {
+		if e.IsDir() && strings.HasPrefix(e.Name(), "prometheus.remote_write.") {
+			ids = append(ids, e.Name())
+		}
+	}
+	return ids
+}
+
+// dumpWAL writes the statistics of the WAL at directory to w. The statistics
+// of the series matching selector are included if series is true.
+func dumpWAL(w io.Writer, directory, selector string, series bool) error {
+	segments, err := waltools.FindSegments(directory)
+	if err != nil {
+		return err
+	}
+
+	var total waltools.SegmentStats
+	for _, s := range segments {
+		total.Series += s.Series
+		total.Samples += s.Samples
+		if !s.From.IsZero() && (total.From.IsZero() || s.From.Before(total.From)) {
+			total.From = s.From
+		}
+		if s.To.After(total.To) {
+			total.To = s.To
+		}
+	}
+
+	fmt.Fprintf(w, "WAL Directory:      %s\n", directory)
+	fmt.Fprintf(w, "Oldest Sample:      %s\n", formatSampleTime(total.From))
+	fmt.Fprintf(w, "Newest Sample:      %s\n", formatSampleTime(total.To))
+	fmt.Fprintf(w, "Total Series:       %d\n", total.Series)
+	fmt.Fprintf(w, "Total Samples:      %d\n", total.Samples)
+
+	fmt.Fprintf(w, "\nSegments:\n")
+	table := tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Segment", "Size", "Series", "Samples", "Oldest Sample", "Newest Sample"})
+	for _, s := range segments {
+		table.Append([]string{
+			s.Name,
+			strconv.FormatInt(s.Size, 10),
+			strconv.Itoa(s.Series),
+			strconv.Itoa(s.Samples),
+			formatSampleTime(s.From),
+			formatSampleTime(s.To),
+		})
+	}
+	table.Render()
+
+	if !series {
+		return nil
+	}
+
+	stats, err := waltools.FindSamples(directory, selector)
+	if err != nil {
+		return err
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Labels.String() < stats[j].Labels.String()
+	})
+
+	fmt.Fprintf(w, "\nSeries matching %s:\n", selector)
+	table = tablewriter.NewWriter(w)
+	table.SetHeader([]string{"Series", "Samples", "Oldest Sample", "Newest Sample"})
+	for _, s := range stats {
+		from, to := s.From, s.To
+		if s.Samples == 0 {
+			from, to = time.Time{}, time.Time{}
+		}
+		table.Append([]string{
+			s.Labels.String(),
+			strconv.FormatInt(s.Samples, 10),
+			formatSampleTime(from),
+			formatSampleTime(to),
+		})
+	}
+	table.Render()
+	return nil
+}
+
+func formatSampleTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
 func must(err error) {
 	if err != nil {
 		panic(err)
@@ -0,0 +1,62 @@
+package remotewrite
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/tsdb/record"
+	"github.com/prometheus/prometheus/tsdb/wlog"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDumpWAL(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "wal")
+	w, err := wlog.NewSize(log.NewNopLogger(), prometheus.NewRegistry(), dir, wlog.DefaultSegmentSize, wlog.CompressionNone)
+	require.NoError(t, err)
+
+	var enc record.Encoder
+	require.NoError(t, w.Log(enc.Series([]record.RefSeries{
+		{Ref: 1, Labels: labels.FromStrings("__name__", "up", "job", "a")},
+		{Ref: 2, Labels: labels.FromStrings("__name__", "up", "job", "b")},
+		{Ref: 3, Labels: labels.FromStrings("__name__", "idle", "job", "a")},
+	}, nil)))
+	require.NoError(t, w.Log(enc.Samples([]record.RefSample{
+		{Ref: 1, T: 1_000, V: 1},
+		{Ref: 1, T: 2_000, V: 1},
+		{Ref: 2, T: 3_000, V: 0},
+	}, nil)))
+	require.NoError(t, w.Close())
+
+	var out strings.Builder
+	require.NoError(t, dumpWAL(&out, dir, `{__name__="up"}`, true))
+
+	actual := out.String()
+	for _, expect := range []string{
+		"Oldest Sample:      1970-01-01T00:00:01Z\n",
+		"Newest Sample:      1970-01-01T00:00:03Z\n",
+		"Total Series:       3\n",
+		"Total Samples:      3\n",
+		`| {__name__="up", job="a"} |       2 | 1970-01-01T00:00:01Z | 1970-01-01T00:00:02Z |`,
+		`| {__name__="up", job="b"} |       1 | 1970-01-01T00:00:03Z | 1970-01-01T00:00:03Z |`,
+	} {
+		require.Contains(t, actual, expect)
+	}
+	require.NotContains(t, actual, "idle")
+	require.Regexp(t, `\| 00000000 +\| +\d+ \| +3 \| +3 \| 1970-01-01T00:00:01Z \| 1970-01-01T00:00:03Z \|`, actual)
+}
+
+func TestComponentIDs(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"prometheus.remote_write.b", "prometheus.remote_write.a", "prometheus.scrape.default"} {
+		require.NoError(t, os.Mkdir(filepath.Join(dir, name), 0o755))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "prometheus.remote_write.file"), nil, 0o644))
+
+	require.Equal(t, []string{"prometheus.remote_write.a", "prometheus.remote_write.b"}, componentIDs(dir))
+	require.Empty(t, componentIDs(filepath.Join(dir, "missing")))
+}
@@ -0,0 +1,230 @@
+package remotewrite
+
+import (
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/grafana/alloy/internal/static/metrics/wal"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// DebugInfo is the debug information of a prometheus.remote_write component.
+// It's used by the UI to show whether data is flowing to each endpoint.
+type DebugInfo struct {
+	WAL       WALDebugInfo        `alloy:"wal,block" json:"wal"`
+	Endpoints []EndpointDebugInfo `alloy:"endpoint,block,optional" json:"endpoints"`
+
+	// Metric names over the series limit of the cardinality guard.
+	LimitedMetrics []LimitedMetricDebugInfo `alloy:"limited_metric,block,optional" json:"limitedMetrics,omitempty"`
+}
+
+// WALDebugInfo describes the state of the WAL of a prometheus.remote_write
+// component.
+type WALDebugInfo struct {
+	Segments int    `alloy:"segments,attr" json:"segments"`
+	Size     int64  `alloy:"size_bytes,attr" json:"sizeBytes"`
+	Error    string `alloy:"error,attr,optional" json:"error,omitempty"`
+
+	// Timestamp of the newest sample written to the WAL.
+	HighestTimestamp time.Time `alloy:"highest_timestamp,attr,optional" json:"highestTimestamp"`
+}
+
+// LimitedMetricDebugInfo describes a metric name with more active series than
+// the series limit of the cardinality guard.
+type LimitedMetricDebugInfo struct {
+	Name          string `alloy:"name,attr" json:"name"`
+	ActiveSeries  int    `alloy:"active_series,attr" json:"activeSeries"`
+	LimitedSeries int    `alloy:"limited_series,attr" json:"limitedSeries"`
+}
+
+// EndpointDebugInfo describes the state of the queue which sends data to a
+// single endpoint.
+type EndpointDebugInfo struct {
+	Name string `alloy:"name,attr" json:"name"`
+	URL  string `alloy:"url,attr" json:"url"`
+
+	// Number of samples, exemplars, and histograms read from the WAL which
+	// are waiting to be sent.
+	PendingSamples    int64 `alloy:"pending_samples,attr" json:"pendingSamples"`
+	PendingExemplars  int64 `alloy:"pending_exemplars,attr" json:"pendingExemplars"`
+	PendingHistograms int64 `alloy:"pending_histograms,attr" json:"pendingHistograms"`
+
+	Shards        int64 `alloy:"shards,attr" json:"shards"`
+	DesiredShards int64 `alloy:"desired_shards,attr" json:"desiredShards"`
+
+	// Timestamp of the newest sample sent successfully to the endpoint. Every
+	// sample in the WAL newer than this timestamp hasn't been sent yet. Lag is
+	// the difference between it and the newest sample in the WAL.
+	HighestSentTimestamp time.Time     `alloy:"highest_sent_timestamp,attr,optional" json:"highestSentTimestamp"`
+	Lag                  time.Duration `alloy:"lag,attr,optional" json:"lag"`
+
+	// Number of samples which were retried or permanently failed to be sent.
+	RetriedSamples int64 `alloy:"retried_samples,attr" json:"retriedSamples"`
+	FailedSamples  int64 `alloy:"failed_samples,attr" json:"failedSamples"`
+	// Number of times the queue was full and appending to it was retried.
+	EnqueueRetries int64 `alloy:"enqueue_retries,attr" json:"enqueueRetries"`
+}
+
+// DebugInfo implements component.DebugComponent.
+func (c *Component) DebugInfo() interface{} {
+	var info DebugInfo
+
+	segments, size, err := walSegments(wal.SubDirectory(c.opts.DataPath))
+	info.WAL.Segments, info.WAL.Size = segments, size
+	if err != nil {
+		info.WAL.Error = err.Error()
+	}
+
+	// Gather returns as many metrics as it could collect alongside any error,
+	// so partial debug info is still returned.
+	families, _ := c.debugRegistry.Gather()
+
+	endpoints := map[string]*EndpointDebugInfo{}
+	for _, mf := range families {
+		for _, m := range mf.GetMetric() {
+			value := metricValue(m)
+
+			if mf.GetName() == "prometheus_remote_storage_highest_timestamp_in_seconds" {
+				info.WAL.HighestTimestamp = secondsToTime(value)
+				continue
+			}
+
+			name, url := labelValue(m, "remote_name"), labelValue(m, "url")
+			if name == "" {
+				continue
+			}
+			ep, ok := endpoints[name]
+			if !ok {
+				ep = &EndpointDebugInfo{Name: name, URL: url}
+				endpoints[name] = ep
+			}
+
+			switch mf.GetName() {
+			case "prometheus_remote_storage_samples_pending":
+				ep.PendingSamples = int64(value)
+			case "prometheus_remote_storage_exemplars_pending":
+				ep.PendingExemplars = int64(value)
+			case "prometheus_remote_storage_histograms_pending":
+				ep.PendingHistograms = int64(value)
+			case "prometheus_remote_storage_shards":
+				ep.Shards = int64(value)
+			case "prometheus_remote_storage_shards_desired":
+				ep.DesiredShards = int64(math.Ceil(value))
+			case "prometheus_remote_storage_queue_highest_sent_timestamp_seconds":
+				ep.HighestSentTimestamp = secondsToTime(value)
+			case "prometheus_remote_storage_samples_retried_total":
+				ep.RetriedSamples = int64(value)
+			case "prometheus_remote_storage_samples_failed_total":
+				ep.FailedSamples = int64(value)
+			case "prometheus_remote_storage_enqueue_retries_total":
+				ep.EnqueueRetries = int64(value)
+			}
+		}
+	}
+
+	for _, ep := range endpoints {
+		if !ep.HighestSentTimestamp.IsZero() && ep.HighestSentTimestamp.Before(info.WAL.HighestTimestamp) {
+			ep.Lag = info.WAL.HighestTimestamp.Sub(ep.HighestSentTimestamp)
+		}
+		info.Endpoints = append(info.Endpoints, *ep)
+	}
+	sort.Slice(info.Endpoints, func(i, j int) bool {
+		return info.Endpoints[i].Name < info.Endpoints[j].Name
+	})
+
+	info.LimitedMetrics = c.guard.LimitedMetrics()
+	return info
+}
+
+// walSegments returns the number of segments in the WAL directory dir and
+// their total size in bytes.
+func walSegments(dir string) (count int, size int64, err error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, 0, err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if _, err := strconv.Atoi(e.Name()); err != nil {
+			continue
+		}
+		fi, err := e.Info()
+		if err != nil {
+			// The segment may have been removed by a truncation.
+			continue
+		}
+		count++
+		size += fi.Size()
+	}
+	return count, size, nil
+}
+
+func metricValue(m *dto.Metric) float64 {
+	switch {
+	case m.GetGauge() != nil:
+		return m.GetGauge().GetValue()
+	case m.GetCounter() != nil:
+		return m.GetCounter().GetValue()
+	default:
+		return 0
+	}
+}
+
+func labelValue(m *dto.Metric, name string) string {
+	for _, l := range m.GetLabel() {
+		if l.GetName() == name {
+			return l.GetValue()
+		}
+	}
+	return ""
+}
+
+func secondsToTime(s float64) time.Time {
+	if s <= 0 {
+		return time.Time{}
+	}
+	sec, frac := math.Modf(s)
+	return time.Unix(int64(sec), int64(frac*1e9)).UTC()
+}
+
+// debugRegisterer registers metrics to the component's registerer and to a
+// private registry, which is gathered to build the debug info of the
+// component.
+type debugRegisterer struct {
+	prometheus.Registerer
+	debug *prometheus.Registry
+}
+
+func newDebugRegisterer(reg prometheus.Registerer) debugRegisterer {
+	return debugRegisterer{Registerer: reg, debug: prometheus.NewRegistry()}
+}
+
+// Register implements prometheus.Registerer.
+func (r debugRegisterer) Register(c prometheus.Collector) error {
+	if err := r.Registerer.Register(c); err != nil {
+		return err
+	}
+	_ = r.debug.Register(c)
+	return nil
+}
+
+// MustRegister implements prometheus.Registerer.
+func (r debugRegisterer) MustRegister(cs ...prometheus.Collector) {
+	for _, c := range cs {
+		if err := r.Register(c); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// Unregister implements prometheus.Registerer.
+func (r debugRegisterer) Unregister(c prometheus.Collector) bool {
+	r.debug.Unregister(c)
+	return r.Registerer.Unregister(c)
+}
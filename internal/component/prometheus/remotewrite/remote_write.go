@@ -19,6 +19,7 @@ import (
 	"github.com/grafana/alloy/internal/service/livedebugging"
 	"github.com/grafana/alloy/internal/static/metrics/wal"
 	"github.com/grafana/alloy/internal/useragent"
+	promclient "github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/prometheus/model/exemplar"
 	"github.com/prometheus/prometheus/model/histogram"
 	"github.com/prometheus/prometheus/model/labels"
@@ -63,7 +64,9 @@ type Component struct {
 	cfg Arguments
 
 	receiver *prometheus.Interceptor
+	guard    *cardinalityGuard
 
+	debugRegistry      *promclient.Registry
 	debugDataPublisher livedebugging.DebugDataPublisher
 }
 
@@ -84,8 +87,11 @@ func New(o component.Options, c Arguments) (*Component, error) {
 		return nil, err
 	}
 
+	// Metrics of the remote storage are also registered to a private registry
+	// to build the debug info of the component.
+	remoteRegisterer := newDebugRegisterer(o.Registerer)
 	remoteLogger := log.With(o.Logger, "subcomponent", "rw")
-	remoteStore := remote.NewStorage(remoteLogger, o.Registerer, startTime, o.DataPath, remoteFlushDeadline, nil, false)
+	remoteStore := remote.NewStorage(remoteLogger, remoteRegisterer, startTime, o.DataPath, remoteFlushDeadline, nil, false)
 
 	walStorage.SetNotifier(remoteStore)
 
@@ -106,6 +112,8 @@ func New(o component.Options, c Arguments) (*Component, error) {
 		walStore:           walStorage,
 		remoteStore:        remoteStore,
 		storage:            storage.NewFanout(o.Logger, walStorage, remoteStore),
+		guard:              newCardinalityGuard(log.With(o.Logger, "subcomponent", "cardinality_guard"), o.Registerer),
+		debugRegistry:      remoteRegisterer.debug,
 		debugDataPublisher: debugDataPublisher.(livedebugging.DebugDataPublisher),
 	}
 	componentID := livedebugging.ComponentID(res.opts.ID)
@@ -124,6 +132,14 @@ func New(o component.Options, c Arguments) (*Component, error) {
 				return 0, fmt.Errorf("%s has exited", o.ID)
 			}
 
+			switch result, overflowLabels, overflowValue := res.guard.CheckSample(l, t, v); result {
+			case guardDrop:
+				return globalRef, nil
+			case guardAggregate:
+				_, err := next.Append(0, overflowLabels, t, overflowValue)
+				return globalRef, err
+			}
+
 			localID := ls.GetLocalRefID(res.opts.ID, uint64(globalRef))
 			newRef, nextErr := next.Append(storage.SeriesRef(localID), l, t, v)
 			if localID == 0 {
@@ -144,6 +160,10 @@ func New(o component.Options, c Arguments) (*Component, error) {
 				return 0, fmt.Errorf("%s has exited", o.ID)
 			}
 
+			if res.guard.CheckHistogram(l) == guardDrop {
+				return globalRef, nil
+			}
+
 			localID := ls.GetLocalRefID(res.opts.ID, uint64(globalRef))
 			newRef, nextErr := next.AppendHistogram(storage.SeriesRef(localID), l, t, h, fh)
 			if localID == 0 {
@@ -192,6 +212,10 @@ func New(o component.Options, c Arguments) (*Component, error) {
 				return 0, fmt.Errorf("%s has exited", o.ID)
 			}
 
+			if res.guard.CheckExemplar(l) == guardDrop {
+				return globalRef, nil
+			}
+
 			localID := ls.GetLocalRefID(res.opts.ID, uint64(globalRef))
 			newRef, nextErr := next.AppendExemplar(storage.SeriesRef(localID), l, e)
 			if localID == 0 {
@@ -223,6 +247,7 @@ func startTime() (int64, error) { return 0, nil }
 
 var _ component.Component = (*Component)(nil)
 var _ component.LiveDebugging = (*Component)(nil)
+var _ component.DebugComponent = (*Component)(nil)
 
 // Run implements Component.
 func (c *Component) Run(ctx context.Context) error {
@@ -241,10 +266,15 @@ func (c *Component) Run(ctx context.Context) error {
 	// deleted until at least some new data has been sent.
 	var lastTs = int64(math.MinInt64)
 
+	pruneTicker := time.NewTicker(guardPruneInterval)
+	defer pruneTicker.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
+		case <-pruneTicker.C:
+			c.guard.Prune()
 		case <-time.After(c.truncateFrequency()):
 			// We retrieve the current min/max keepalive time at once, since
 			// retrieving them separately could lead to issues where we have an older
@@ -323,6 +353,10 @@ func (c *Component) Update(newConfig component.Arguments) error {
 		return err
 	}
 
+	if !equalGuardOptions(c.cfg.CardinalityGuard, cfg.CardinalityGuard) {
+		c.guard.SetOptions(cfg.CardinalityGuard)
+	}
+
 	c.cfg = cfg
 	return nil
 }
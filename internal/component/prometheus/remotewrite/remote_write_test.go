@@ -14,6 +14,7 @@ import (
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/prompb"
 	"github.com/prometheus/prometheus/storage/remote"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -180,6 +181,110 @@ func TestUpdate(t *testing.T) {
 	}})
 }
 
+func TestDebugInfo(t *testing.T) {
+	writeResult := make(chan *prompb.WriteRequest)
+	srv := newTestServer(t, writeResult)
+	defer srv.Close()
+
+	args := testArgsForConfig(t, fmt.Sprintf(`
+		endpoint {
+			name           = "test-url"
+			url            = "%s/api/v1/write"
+			remote_timeout = "100ms"
+
+			queue_config {
+				batch_send_deadline = "100ms"
+			}
+		}
+	`, srv.URL))
+	tc, err := componenttest.NewControllerFromID(util.TestLogger(t), "prometheus.remote_write")
+	require.NoError(t, err)
+	go func() {
+		err = tc.Run(componenttest.TestContext(t), args)
+		require.NoError(t, err)
+	}()
+	require.NoError(t, tc.WaitRunning(5*time.Second))
+
+	sampleTime := time.Now().Add(time.Minute)
+	sendMetric(t, tc, labels.FromStrings("foo", "bar"), sampleTime.UnixMilli(), 12)
+	assertReceived(t, writeResult, []prompb.TimeSeries{{
+		Labels:  []prompb.Label{{Name: "foo", Value: "bar"}},
+		Samples: []prompb.Sample{{Timestamp: sampleTime.UnixMilli(), Value: 12}},
+	}})
+
+	c, err := tc.GetComponent()
+	require.NoError(t, err)
+
+	require.EventuallyWithT(t, func(t *assert.CollectT) {
+		info := c.(*remotewrite.Component).DebugInfo().(remotewrite.DebugInfo)
+		assert.Positive(t, info.WAL.Segments)
+		assert.Positive(t, info.WAL.Size)
+		assert.Equal(t, sampleTime.Unix(), info.WAL.HighestTimestamp.Unix())
+
+		if assert.Len(t, info.Endpoints, 1) {
+			ep := info.Endpoints[0]
+			assert.Equal(t, "test-url", ep.Name)
+			assert.Equal(t, srv.URL+"/api/v1/write", ep.URL)
+			assert.Zero(t, ep.PendingSamples)
+			assert.Equal(t, sampleTime.Unix(), ep.HighestSentTimestamp.Unix())
+			assert.Zero(t, ep.Lag)
+		}
+	}, 10*time.Second, 100*time.Millisecond)
+}
+
+func TestCardinalityGuard(t *testing.T) {
+	writeResult := make(chan *prompb.WriteRequest)
+	srv := newTestServer(t, writeResult)
+	defer srv.Close()
+
+	args := testArgsForConfig(t, fmt.Sprintf(`
+		endpoint {
+			name           = "test-url"
+			url            = "%s/api/v1/write"
+			remote_timeout = "100ms"
+
+			queue_config {
+				batch_send_deadline = "100ms"
+			}
+		}
+
+		cardinality_guard {
+			max_series_per_metric = 1
+			action                = "aggregate"
+		}
+	`, srv.URL))
+	tc, err := componenttest.NewControllerFromID(util.TestLogger(t), "prometheus.remote_write")
+	require.NoError(t, err)
+	go func() {
+		err = tc.Run(componenttest.TestContext(t), args)
+		require.NoError(t, err)
+	}()
+	require.NoError(t, tc.WaitRunning(5*time.Second))
+
+	// The second and third series are over the limit and are aggregated into
+	// a single series, which is written once for the timestamp.
+	sampleTime := time.Now().Add(time.Minute).UnixMilli()
+	appender := tc.Exports().(remotewrite.Exports).Receiver.Appender(t.Context())
+	for i, pod := range []string{"a", "b", "c"} {
+		_, err := appender.Append(0, labels.FromStrings("__name__", "requests", "pod", pod), sampleTime, float64(i+1))
+		require.NoError(t, err)
+	}
+	require.NoError(t, appender.Commit())
+
+	assertReceived(t, writeResult, []prompb.TimeSeries{{
+		Labels:  []prompb.Label{{Name: "__name__", Value: "requests"}, {Name: "pod", Value: "a"}},
+		Samples: []prompb.Sample{{Timestamp: sampleTime, Value: 1}},
+	}, {
+		Labels:  []prompb.Label{{Name: "__name__", Value: "requests"}, {Name: "cardinality_overflow", Value: "true"}},
+		Samples: []prompb.Sample{{Timestamp: sampleTime, Value: 2}},
+	}})
+
+	c, err := tc.GetComponent()
+	require.NoError(t, err)
+	info := c.(*remotewrite.Component).DebugInfo().(remotewrite.DebugInfo)
+	require.Equal(t, []remotewrite.LimitedMetricDebugInfo{{Name: "requests", ActiveSeries: 3, LimitedSeries: 2}}, info.LimitedMetrics)
+}
+
 func assertReceived(t *testing.T, writeResult chan *prompb.WriteRequest, expect []prompb.TimeSeries) {
 	select {
 	case <-time.After(time.Minute):
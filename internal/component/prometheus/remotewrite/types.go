@@ -61,6 +61,8 @@ type Arguments struct {
 	ExternalLabels map[string]string  `alloy:"external_labels,attr,optional"`
 	Endpoints      []*EndpointOptions `alloy:"endpoint,block,optional"`
 	WALOptions     WALOptions         `alloy:"wal,block,optional"`
+
+	CardinalityGuard *CardinalityGuardOptions `alloy:"cardinality_guard,block,optional"`
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -0,0 +1,232 @@
+package scrape
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/prometheus/model/histogram"
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/model/value"
+	"github.com/prometheus/prometheus/scrape"
+	"github.com/prometheus/prometheus/storage"
+)
+
+const (
+	// maxCachedScrapes is the maximum number of targets for which the last
+	// scrape is recorded. The targets requested the longest time ago are
+	// evicted first.
+	maxCachedScrapes = 100
+	// maxCachedBodySize is the maximum number of bytes of the samples of a
+	// scrape which are recorded. Further samples are dropped.
+	maxCachedBodySize = 1 << 20
+)
+
+// DebugScrape holds the last scrape of a target by the scrape loop.
+type DebugScrape struct {
+	URL       string        `json:"url"`
+	Time      time.Time     `json:"time"`
+	Duration  time.Duration `json:"duration"`
+	Samples   string        `json:"samples"`
+	Truncated bool          `json:"truncated,omitempty"`
+	Error     string        `json:"error,omitempty"`
+}
+
+// scrapeCache holds the last scrape of a bounded number of targets. Only the
+// scrapes of the targets which were requested from the debug endpoint are
+// recorded.
+type scrapeCache struct {
+	mut     sync.Mutex
+	scrapes map[string]DebugScrape
+	order   []string // URLs of the watched targets, least recently requested first.
+}
+
+func newScrapeCache() *scrapeCache {
+	return &scrapeCache{scrapes: make(map[string]DebugScrape)}
+}
+
+func (sc *scrapeCache) get(url string) (DebugScrape, bool) {
+	sc.mut.Lock()
+	defer sc.mut.Unlock()
+	s, ok := sc.scrapes[url]
+	return s, ok
+}
+
+// watched returns whether the scrapes of url are recorded.
+func (sc *scrapeCache) watched(url string) bool {
+	sc.mut.Lock()
+	defer sc.mut.Unlock()
+	_, ok := sc.scrapes[url]
+	return ok
+}
+
+// watch starts recording the scrapes of url, if it isn't recorded already,
+// and returns its last recorded scrape.
+func (sc *scrapeCache) watch(url string) DebugScrape {
+	sc.mut.Lock()
+	defer sc.mut.Unlock()
+
+	s, ok := sc.scrapes[url]
+	if ok {
+		for i, u := range sc.order {
+			if u == url {
+				sc.order = append(sc.order[:i], sc.order[i+1:]...)
+				break
+			}
+		}
+	} else {
+		s = DebugScrape{URL: url}
+		sc.scrapes[url] = s
+	}
+	sc.order = append(sc.order, url)
+
+	for len(sc.order) > maxCachedScrapes {
+		delete(sc.scrapes, sc.order[0])
+		sc.order = sc.order[1:]
+	}
+	return s
+}
+
+// record stores the last scrape of a watched target. Scrapes of targets
+// which aren't watched are dropped.
+func (sc *scrapeCache) record(s DebugScrape) {
+	sc.mut.Lock()
+	defer sc.mut.Unlock()
+	if _, ok := sc.scrapes[s.URL]; ok {
+		sc.scrapes[s.URL] = s
+	}
+}
+
+// Handler implements http.Component from the HTTP service. It serves the /scrape endpoint,
+// which returns the samples and the error of the last scrape of a target of
+// the component.
+//
+// The scrapes of a target are recorded from the first time it's requested,
+// so the first request returns 202 Accepted until the target is scraped.
+func (c *Component) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /scrape", func(w http.ResponseWriter, r *http.Request) {
+		url := r.URL.Query().Get("url")
+		if url == "" {
+			http.Error(w, "the url query parameter is required", http.StatusBadRequest)
+			return
+		}
+
+		// Only the active targets are recorded, so that the endpoint can't be
+		// used to fill the cache with arbitrary URLs.
+		if c.activeTarget(url) == nil {
+			http.Error(w, fmt.Sprintf("%s isn't an active target of the component", url), http.StatusNotFound)
+			return
+		}
+
+		s := c.scrapeCache.watch(url)
+		w.Header().Set("Content-Type", "application/json")
+		if s.Time.IsZero() {
+			w.WriteHeader(http.StatusAccepted)
+		}
+		if err := json.NewEncoder(w).Encode(s); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+	return mux
+}
+
+// activeTarget returns the active target with the given URL, or nil if there
+// isn't any.
+func (c *Component) activeTarget(url string) *scrape.Target {
+	for _, targets := range c.scraper.TargetsActive() {
+		for _, t := range targets {
+			if t != nil && t.URL().String() == url {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// debugAppendable records the samples appended by the scrape loops of the
+// watched targets in the scrape cache.
+type debugAppendable struct {
+	next  storage.Appendable
+	cache *scrapeCache
+}
+
+func (a *debugAppendable) Appender(ctx context.Context) storage.Appender {
+	app := a.next.Appender(ctx)
+
+	// The target is only in the context if the scrape manager passes the
+	// metadata in it.
+	target, ok := scrape.TargetFromContext(ctx)
+	if !ok {
+		return app
+	}
+	url := target.URL().String()
+	if !a.cache.watched(url) {
+		return app
+	}
+	return &debugAppender{Appender: app, cache: a.cache, target: target, url: url}
+}
+
+// debugAppender records the samples of a single scrape. The scrape loop
+// appends the report samples, such as up, before it commits, so the target
+// already holds the error and duration of the scrape on Commit.
+type debugAppender struct {
+	storage.Appender
+
+	cache     *scrapeCache
+	target    *scrape.Target
+	url       string
+	samples   strings.Builder
+	truncated bool
+}
+
+func (a *debugAppender) Append(ref storage.SeriesRef, l labels.Labels, t int64, v float64) (storage.SeriesRef, error) {
+	if !value.IsStaleNaN(v) {
+		a.record(l, strconv.FormatFloat(v, 'g', -1, 64))
+	}
+	return a.Appender.Append(ref, l, t, v)
+}
+
+func (a *debugAppender) AppendHistogram(ref storage.SeriesRef, l labels.Labels, t int64, h *histogram.Histogram, fh *histogram.FloatHistogram) (storage.SeriesRef, error) {
+	switch {
+	case h != nil && !value.IsStaleNaN(h.Sum):
+		a.record(l, h.String())
+	case fh != nil && !value.IsStaleNaN(fh.Sum):
+		a.record(l, fh.String())
+	}
+	return a.Appender.AppendHistogram(ref, l, t, h, fh)
+}
+
+func (a *debugAppender) record(l labels.Labels, v string) {
+	if a.truncated {
+		return
+	}
+	line := l.String() + " " + v + "\n"
+	if a.samples.Len()+len(line) > maxCachedBodySize {
+		a.truncated = true
+		return
+	}
+	a.samples.WriteString(line)
+}
+
+func (a *debugAppender) Commit() error {
+	err := a.Appender.Commit()
+
+	s := DebugScrape{
+		URL:       a.url,
+		Time:      a.target.LastScrape(),
+		Duration:  a.target.LastScrapeDuration(),
+		Samples:   a.samples.String(),
+		Truncated: a.truncated,
+	}
+	if scrapeErr := a.target.LastError(); scrapeErr != nil {
+		s.Error = scrapeErr.Error()
+	}
+	a.cache.record(s)
+	return err
+}
@@ -202,11 +202,15 @@ type Component struct {
 	distributedTargets *discovery.DistributedTargets
 
 	debugDataPublisher livedebugging.DebugDataPublisher
+
+	scrapeCache *scrapeCache
 }
 
 var (
 	_ component.Component     = (*Component)(nil)
 	_ component.LiveDebugging = (*Component)(nil)
+	_ cluster.DrainComponent  = (*Component)(nil)
+	_ http.Component          = (*Component)(nil)
 )
 
 // New creates a new prometheus.scrape component.
@@ -241,6 +245,9 @@ func New(o component.Options, args Arguments) (*Component, error) {
 			config_util.WithDialContextFunc(httpData.DialFunc),
 		},
 		EnableNativeHistogramsIngestion: args.ScrapeNativeHistograms,
+		// The debug endpoint uses the target in the appender context to
+		// record the last scrape of the target.
+		PassMetadataInContext: true,
 	}
 
 	unregisterer := util.WrapWithUnregisterer(o.Registerer)
@@ -270,6 +277,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		targetsGauge:        targetsGauge,
 		movedTargetsCounter: movedTargetsCounter,
 		unregisterer:        unregisterer,
+		scrapeCache:         newScrapeCache(),
 	}
 
 	interceptor := c.newInterceptor(ls)
@@ -278,7 +286,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		scrapeOptions,
 		o.Logger,
 		func(s string) (go_kit_log.Logger, error) { return logging.NewJSONFileLogger(s) },
-		interceptor,
+		&debugAppendable{next: interceptor, cache: c.scrapeCache},
 		unregisterer)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create scrape manager: %w", err)
@@ -418,6 +426,38 @@ func (c *Component) NotifyClusterChange() {
 	}
 }
 
+// Drain implements cluster.DrainComponent. It keeps scraping the local targets
+// for one scrape interval, so that peers have the opportunity to scrape the
+// targets they take over at least once before this instance stops.
+func (c *Component) Drain(ctx context.Context) {
+	c.mut.RLock()
+	var (
+		targets = c.args.Targets
+		jobName = c.opts.ID
+		args    = c.args
+	)
+	c.mut.RUnlock()
+
+	if !args.Clustering.Enabled {
+		return
+	}
+	if args.JobName != "" {
+		jobName = args.JobName
+	}
+
+	t := time.NewTimer(args.ScrapeInterval)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+	case <-t.C:
+	}
+
+	// The targets are now owned by peers, which are responsible for the
+	// staleness markers. We must not inject them when the scrape manager stops.
+	_, movedTargets := c.distributeTargets(targets, jobName, args)
+	c.scraper.DisableEndOfRunStalenessMarkers(jobName, movedTargets)
+}
+
 // Helper function to bridge the in-house configuration with the Prometheus
 // scrape_config.
 // As explained in the Config struct, the following fields are purposefully
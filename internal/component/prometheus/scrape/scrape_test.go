@@ -2,9 +2,14 @@ package scrape
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
 	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -13,6 +18,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/prometheus/prometheus/model/labels"
 	"github.com/prometheus/prometheus/storage"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/grafana/alloy/internal/component"
@@ -266,6 +272,107 @@ func TestCustomDialer(t *testing.T) {
 	require.NoError(t, err, "custom dialer was not used")
 }
 
+func TestDebugHandler(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var fail atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail.Load() {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprintln(w, "test_metric 42")
+	}))
+	defer srv.Close()
+	targetURL := srv.URL + "/metrics"
+
+	var config = fmt.Sprintf(`
+	targets         = [{ __address__ = %q }]
+	forward_to      = []
+	scrape_interval = "100ms"
+	scrape_timeout  = "85ms"
+	`, strings.TrimPrefix(srv.URL, "http://"))
+	var args Arguments
+	err := syntax.Unmarshal([]byte(config), &args)
+	require.NoError(t, err)
+
+	opts := component.Options{
+		Logger:     util.TestAlloyLogger(t),
+		Registerer: prometheus_client.NewRegistry(),
+		GetServiceData: func(name string) (interface{}, error) {
+			switch name {
+			case http_service.ServiceName:
+				return http_service.Data{
+					HTTPListenAddr:   "localhost:12345",
+					MemoryListenAddr: "alloy.internal:1245",
+					BaseHTTPPath:     "/",
+					DialFunc:         (&net.Dialer{}).DialContext,
+				}, nil
+
+			case cluster.ServiceName:
+				return cluster.Mock(), nil
+			case labelstore.ServiceName:
+				return labelstore.New(nil, prometheus_client.DefaultRegisterer), nil
+			case livedebugging.ServiceName:
+				return livedebugging.NewLiveDebugging(), nil
+
+			default:
+				return nil, fmt.Errorf("service %q does not exist", name)
+			}
+		},
+	}
+
+	s, err := New(opts, args)
+	require.NoError(t, err)
+	go s.Run(ctx)
+
+	handler := s.Handler()
+	get := func(rawURL string) (int, DebugScrape) {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/scrape?url="+url.QueryEscape(rawURL), nil))
+		var res DebugScrape
+		if rec.Code == http.StatusOK || rec.Code == http.StatusAccepted {
+			require.NoError(t, json.NewDecoder(rec.Body).Decode(&res))
+		}
+		return rec.Code, res
+	}
+
+	require.Eventually(t, func() bool {
+		return s.activeTarget(targetURL) != nil
+	}, 1*time.Minute, 100*time.Millisecond)
+
+	// The scrapes of the target are recorded from the first request.
+	code, res := get(targetURL)
+	require.Equal(t, http.StatusAccepted, code)
+	require.Equal(t, targetURL, res.URL)
+	require.Empty(t, res.Samples)
+
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		code, res := get(targetURL)
+		assert.Equal(c, http.StatusOK, code)
+		assert.Contains(c, res.Samples, `{__name__="test_metric", instance=`)
+		assert.Contains(c, res.Samples, `{__name__="up", instance=`)
+		assert.Empty(c, res.Error)
+	}, 1*time.Minute, 100*time.Millisecond)
+
+	// The error of the scrape loop is returned along with its report samples.
+	fail.Store(true)
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		_, res := get(targetURL)
+		assert.Equal(c, "server returned HTTP status 500 Internal Server Error", res.Error)
+		assert.NotContains(c, res.Samples, "test_metric")
+		assert.Contains(c, res.Samples, `{__name__="up", instance=`)
+	}, 1*time.Minute, 100*time.Millisecond)
+
+	// Only the active targets of the component can be requested.
+	code, _ = get("http://example.com/metrics")
+	require.Equal(t, http.StatusNotFound, code)
+
+	code, _ = get("")
+	require.Equal(t, http.StatusBadRequest, code)
+}
+
 func TestValidateScrapeConfig(t *testing.T) {
 	var exampleAlloyConfig = `
 	targets         = [{ "target1" = "target1" }]
@@ -278,3 +385,28 @@ func TestValidateScrapeConfig(t *testing.T) {
 	err := syntax.Unmarshal([]byte(exampleAlloyConfig), &args)
 	require.ErrorContains(t, err, "scrape_timeout (20s) greater than scrape_interval (10s) for scrape config with job name \"local\"")
 }
+
+func TestScrapeCacheEviction(t *testing.T) {
+	sc := newScrapeCache()
+	for i := range maxCachedScrapes + 1 {
+		sc.watch(fmt.Sprintf("http://target-%d/metrics", i))
+	}
+	// Requesting a watched target again moves it to the end of the queue.
+	sc.watch("http://target-1/metrics")
+	sc.watch("http://target-new/metrics")
+
+	require.False(t, sc.watched("http://target-0/metrics"))
+	require.False(t, sc.watched("http://target-2/metrics"))
+
+	// Only the scrapes of the watched targets are recorded.
+	sc.record(DebugScrape{URL: "http://target-1/metrics", Samples: "updated"})
+	sc.record(DebugScrape{URL: "http://target-2/metrics", Samples: "dropped"})
+
+	s, ok := sc.get("http://target-1/metrics")
+	require.True(t, ok)
+	require.Equal(t, "updated", s.Samples)
+	_, ok = sc.get("http://target-2/metrics")
+	require.False(t, ok)
+	require.Len(t, sc.scrapes, maxCachedScrapes)
+	require.Len(t, sc.order, maxCachedScrapes)
+}
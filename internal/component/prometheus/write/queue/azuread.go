@@ -0,0 +1,100 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/prometheus/prometheus/storage/remote/azuread"
+)
+
+// azureADTokenTimeout is how long to wait for Azure AD to return an access token.
+const azureADTokenTimeout = 30 * time.Second
+
+// azureADToken holds the Azure AD access token of an endpoint. The queue
+// can't authenticate each request itself, so the token is sent as the bearer
+// token of the endpoint, which is recreated when the token is refreshed.
+type azureADToken struct {
+	cred    azcore.TokenCredential
+	options policy.TokenRequestOptions
+
+	value     string
+	refreshAt time.Time
+}
+
+func newAzureADToken(cfg *AzureADConfig) (*azureADToken, error) {
+	cloudCfg, audience, err := azureCloud(cfg.Cloud)
+	if err != nil {
+		return nil, err
+	}
+	clientOpts := azcore.ClientOptions{Cloud: cloudCfg}
+
+	var cred azcore.TokenCredential
+	switch {
+	case cfg.ManagedIdentity != nil:
+		cred, err = azidentity.NewManagedIdentityCredential(&azidentity.ManagedIdentityCredentialOptions{
+			ClientOptions: clientOpts,
+			ID:            azidentity.ClientID(cfg.ManagedIdentity.ClientID),
+		})
+	case cfg.OAuth != nil:
+		cred, err = azidentity.NewClientSecretCredential(cfg.OAuth.TenantID, cfg.OAuth.ClientID, string(cfg.OAuth.ClientSecret), &azidentity.ClientSecretCredentialOptions{
+			ClientOptions: clientOpts,
+		})
+	case cfg.SDK != nil:
+		cred, err = azidentity.NewDefaultAzureCredential(&azidentity.DefaultAzureCredentialOptions{
+			ClientOptions: clientOpts,
+			TenantID:      cfg.SDK.TenantID,
+		})
+	default:
+		return nil, errors.New("must provide an Azure Managed Identity, Azure OAuth or Azure SDK in the Azure AD config")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &azureADToken{
+		cred:    cred,
+		options: policy.TokenRequestOptions{Scopes: []string{audience}},
+	}, nil
+}
+
+// refresh gets a new access token once the current one reached half of its
+// lifetime, like prometheus.remote_write does. It returns true if the token
+// changed.
+func (t *azureADToken) refresh(ctx context.Context, now time.Time) (bool, error) {
+	if t.value != "" && now.Before(t.refreshAt) {
+		return false, nil
+	}
+	ctx, cancel := context.WithTimeout(ctx, azureADTokenTimeout)
+	defer cancel()
+	accessToken, err := t.cred.GetToken(ctx, t.options)
+	if err != nil {
+		return false, fmt.Errorf("failed to get Azure AD access token: %w", err)
+	}
+	if accessToken.Token == "" {
+		return false, errors.New("Azure AD access token is empty")
+	}
+	t.value = accessToken.Token
+	t.refreshAt = now.Add(accessToken.ExpiresOn.Sub(now) / 2)
+	return true, nil
+}
+
+// azureCloud returns the configuration and the audience of the ingestion
+// endpoints of an Azure cloud.
+func azureCloud(name string) (cloud.Configuration, string, error) {
+	switch strings.ToLower(name) {
+	case strings.ToLower(azuread.AzureChina):
+		return cloud.AzureChina, azuread.IngestionChinaAudience, nil
+	case strings.ToLower(azuread.AzureGovernment):
+		return cloud.AzureGovernment, azuread.IngestionGovernmentAudience, nil
+	case strings.ToLower(azuread.AzurePublic):
+		return cloud.AzurePublic, azuread.IngestionPublicAudience, nil
+	default:
+		return cloud.Configuration{}, "", fmt.Errorf("unknown Azure cloud %q", name)
+	}
+}
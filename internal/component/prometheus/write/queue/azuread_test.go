@@ -0,0 +1,64 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeCredential struct {
+	calls  int
+	expiry time.Duration
+	now    func() time.Time
+}
+
+func (c *fakeCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	c.calls++
+	return azcore.AccessToken{
+		Token:     fmt.Sprintf("token-%d", c.calls),
+		ExpiresOn: c.now().Add(c.expiry),
+	}, nil
+}
+
+func TestAzureADToken_Refresh(t *testing.T) {
+	now := time.Now()
+	cred := &fakeCredential{expiry: time.Hour, now: func() time.Time { return now }}
+	token := &azureADToken{cred: cred}
+
+	changed, err := token.refresh(context.Background(), now)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, "token-1", token.value)
+
+	// The token is kept until half of its lifetime.
+	now = now.Add(29 * time.Minute)
+	changed, err = token.refresh(context.Background(), now)
+	require.NoError(t, err)
+	require.False(t, changed)
+	require.Equal(t, "token-1", token.value)
+
+	now = now.Add(time.Minute)
+	changed, err = token.refresh(context.Background(), now)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, "token-2", token.value)
+}
+
+func TestNewAzureADToken(t *testing.T) {
+	_, err := newAzureADToken(&AzureADConfig{
+		Cloud:           "AzureGovernment",
+		ManagedIdentity: &ManagedIdentityConfig{ClientID: "00000000-0000-0000-0000-000000000000"},
+	})
+	require.NoError(t, err)
+
+	_, err = newAzureADToken(&AzureADConfig{
+		Cloud:           "Mars",
+		ManagedIdentity: &ManagedIdentityConfig{ClientID: "00000000-0000-0000-0000-000000000000"},
+	})
+	require.ErrorContains(t, err, `unknown Azure cloud "Mars"`)
+}
@@ -5,10 +5,12 @@ import (
 	"path/filepath"
 	"reflect"
 	"sync"
+	"time"
 
 	"github.com/go-kit/log"
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
 	promqueue "github.com/grafana/walqueue/implementations/prometheus"
 	"github.com/prometheus/prometheus/storage"
 )
@@ -31,6 +33,7 @@ func NewComponent(opts component.Options, args Arguments) (*Queue, error) {
 		args:      args,
 		log:       opts.Logger,
 		endpoints: map[string]promqueue.Queue{},
+		tokens:    map[string]*azureADToken{},
 	}
 	s.opts.OnStateChange(Exports{Receiver: s})
 	err := s.createEndpoints()
@@ -48,9 +51,15 @@ type Queue struct {
 	opts      component.Options
 	log       log.Logger
 	endpoints map[string]promqueue.Queue
-	ctx       context.Context
+	// Azure AD access tokens of the endpoints, by endpoint name.
+	tokens map[string]*azureADToken
+	ctx    context.Context
 }
 
+// azureADRefreshInterval is how often to check whether the Azure AD access
+// tokens must be refreshed.
+var azureADRefreshInterval = time.Minute
+
 // Run starts the component, blocking until ctx is canceled or the component
 // suffers a fatal error. Run is guaranteed to be called exactly once per
 // Component.
@@ -71,8 +80,17 @@ func (s *Queue) Run(ctx context.Context) error {
 			return err
 		}
 	}
-	<-ctx.Done()
-	return nil
+
+	ticker := time.NewTicker(azureADRefreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.refreshTokens(ctx)
+		}
+	}
 }
 
 // Update provides a new Config to the component. The type of newConfig will
@@ -101,6 +119,9 @@ func (s *Queue) Update(args component.Arguments) error {
 
 	for _, epCfg := range s.args.Endpoints {
 		delete(deletableEndpoints, epCfg.Name)
+		if err := s.updateToken(epCfg); err != nil {
+			return err
+		}
 		ep, found := s.endpoints[epCfg.Name]
 		// If found stop and recreate.
 		if found {
@@ -108,9 +129,8 @@ func (s *Queue) Update(args component.Arguments) error {
 			// TODO drain the signals and re-add them
 			ep.Stop()
 		}
-		nativeCfg := epCfg.ToNativeType()
 		// Create
-		end, err := promqueue.NewQueue(epCfg.Name, nativeCfg, filepath.Join(s.opts.DataPath, epCfg.Name, "wal"), uint32(s.args.Persistence.MaxSignalsToBatch), s.args.Persistence.BatchInterval, s.args.TTL, s.opts.Registerer, "alloy", s.opts.Logger)
+		end, err := s.newQueue(epCfg)
 		if err != nil {
 			return err
 		}
@@ -124,14 +144,17 @@ func (s *Queue) Update(args component.Arguments) error {
 	for name := range deletableEndpoints {
 		s.endpoints[name].Stop()
 		delete(s.endpoints, name)
+		delete(s.tokens, name)
 	}
 	return nil
 }
 
 func (s *Queue) createEndpoints() error {
 	for _, ep := range s.args.Endpoints {
-		nativeCfg := ep.ToNativeType()
-		end, err := promqueue.NewQueue(ep.Name, nativeCfg, filepath.Join(s.opts.DataPath, ep.Name, "wal"), uint32(s.args.Persistence.MaxSignalsToBatch), s.args.Persistence.BatchInterval, s.args.TTL, s.opts.Registerer, "alloy", s.opts.Logger)
+		if err := s.updateToken(ep); err != nil {
+			return err
+		}
+		end, err := s.newQueue(ep)
 		if err != nil {
 			return err
 		}
@@ -140,6 +163,72 @@ func (s *Queue) createEndpoints() error {
 	return nil
 }
 
+// newQueue creates the queue of an endpoint, which is authenticated with the
+// Azure AD access token of the endpoint if it has one.
+func (s *Queue) newQueue(ep EndpointConfig) (promqueue.Queue, error) {
+	nativeCfg := ep.ToNativeType()
+	if token, ok := s.tokens[ep.Name]; ok {
+		nativeCfg.BearerToken = token.value
+	}
+	return promqueue.NewQueue(ep.Name, nativeCfg, filepath.Join(s.opts.DataPath, ep.Name, "wal"), uint32(s.args.Persistence.MaxSignalsToBatch), s.args.Persistence.BatchInterval, s.args.TTL, s.opts.Registerer, "alloy", s.opts.Logger)
+}
+
+// updateToken gets the Azure AD access token of an endpoint configured with
+// an azuread block.
+func (s *Queue) updateToken(ep EndpointConfig) error {
+	if ep.AzureAD == nil {
+		delete(s.tokens, ep.Name)
+		return nil
+	}
+	token, err := newAzureADToken(ep.AzureAD)
+	if err != nil {
+		return err
+	}
+	if _, err := token.refresh(context.Background(), time.Now()); err != nil {
+		return err
+	}
+	s.tokens[ep.Name] = token
+	return nil
+}
+
+// refreshTokens refreshes the Azure AD access tokens which reached half of
+// their lifetime, and recreates the queues of their endpoints to send the new
+// tokens. The WAL of an endpoint is kept when its queue is recreated.
+func (s *Queue) refreshTokens(ctx context.Context) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	for _, ep := range s.args.Endpoints {
+		token, ok := s.tokens[ep.Name]
+		if !ok {
+			continue
+		}
+		changed, err := token.refresh(ctx, time.Now())
+		if err != nil {
+			level.Error(s.log).Log("msg", "failed to refresh Azure AD access token", "endpoint", ep.Name, "err", err)
+			continue
+		}
+		if !changed {
+			continue
+		}
+		if old, found := s.endpoints[ep.Name]; found {
+			old.Stop()
+			delete(s.endpoints, ep.Name)
+		}
+		end, err := s.newQueue(ep)
+		if err == nil {
+			err = end.Start(ctx)
+		}
+		if err != nil {
+			// Forget the token so that the queue is created again on the next refresh.
+			token.value = ""
+			level.Error(s.log).Log("msg", "failed to recreate queue with refreshed Azure AD access token", "endpoint", ep.Name, "err", err)
+			continue
+		}
+		s.endpoints[ep.Name] = end
+	}
+}
+
 // Appender returns a new appender for the storage. The implementation
 // can choose whether or not to use the context, for deadlines or to check
 // for errors.
@@ -8,6 +8,7 @@ import (
 	"github.com/grafana/walqueue/types"
 	"github.com/prometheus/common/version"
 	"github.com/prometheus/prometheus/storage"
+	"github.com/prometheus/prometheus/storage/remote/azuread"
 )
 
 func defaultArgs() Arguments {
@@ -92,6 +93,14 @@ func (r *Arguments) Validate() error {
 		if conn.Parallelism.AllowedNetworkErrorFraction < 0 || conn.Parallelism.AllowedNetworkErrorFraction > 1 {
 			return fmt.Errorf("allowed_network_error_percent must be between 0.00 and 1.00")
 		}
+		if conn.AzureAD != nil {
+			if conn.BasicAuth != nil || conn.BearerToken != "" {
+				return fmt.Errorf("at most one of azuread, basic_auth & bearer_token must be configured")
+			}
+			if err := conn.AzureAD.toPrometheusType().Validate(); err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -103,6 +112,7 @@ type EndpointConfig struct {
 	URL         string            `alloy:"url,attr"`
 	BasicAuth   *BasicAuth        `alloy:"basic_auth,block,optional"`
 	BearerToken alloytypes.Secret `alloy:"bearer_token,attr,optional"`
+	AzureAD     *AzureADConfig    `alloy:"azuread,block,optional"`
 	Timeout     time.Duration     `alloy:"write_timeout,attr,optional"`
 	// How long to wait between retries.
 	RetryBackoff time.Duration `alloy:"retry_backoff,attr,optional"`
@@ -206,3 +216,53 @@ type BasicAuth struct {
 	Username string            `alloy:"username,attr,optional"`
 	Password alloytypes.Secret `alloy:"password,attr,optional"`
 }
+
+// AzureADConfig configures the Azure AD authentication of an endpoint. The
+// access tokens are sent as bearer tokens.
+type AzureADConfig struct {
+	ManagedIdentity *ManagedIdentityConfig `alloy:"managed_identity,block,optional"`
+	OAuth           *OAuthConfig           `alloy:"oauth,block,optional"`
+	SDK             *SDKConfig             `alloy:"sdk,block,optional"`
+
+	// Cloud is the Azure cloud in which the service is running. Example: AzurePublic/AzureGovernment/AzureChina.
+	Cloud string `alloy:"cloud,attr,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *AzureADConfig) SetToDefault() {
+	*a = AzureADConfig{
+		Cloud: azuread.AzurePublic,
+	}
+}
+
+func (a *AzureADConfig) toPrometheusType() *azuread.AzureADConfig {
+	cfg := &azuread.AzureADConfig{Cloud: a.Cloud}
+	if a.ManagedIdentity != nil {
+		cfg.ManagedIdentity = &azuread.ManagedIdentityConfig{ClientID: a.ManagedIdentity.ClientID}
+	}
+	if a.OAuth != nil {
+		cfg.OAuth = &azuread.OAuthConfig{
+			ClientID:     a.OAuth.ClientID,
+			ClientSecret: string(a.OAuth.ClientSecret),
+			TenantID:     a.OAuth.TenantID,
+		}
+	}
+	if a.SDK != nil {
+		cfg.SDK = &azuread.SDKConfig{TenantID: a.SDK.TenantID}
+	}
+	return cfg
+}
+
+type ManagedIdentityConfig struct {
+	ClientID string `alloy:"client_id,attr"`
+}
+
+type OAuthConfig struct {
+	ClientID     string            `alloy:"client_id,attr"`
+	ClientSecret alloytypes.Secret `alloy:"client_secret,attr"`
+	TenantID     string            `alloy:"tenant_id,attr"`
+}
+
+type SDKConfig struct {
+	TenantID string `alloy:"tenant_id,attr"`
+}
@@ -101,3 +101,77 @@ func TestParralelismConfig_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestAzureADConfig_Validate(t *testing.T) {
+	testCases := []struct {
+		name           string
+		cfg            string
+		expectedErrMsg string
+	}{
+		{
+			name: "managed identity",
+			cfg: `
+				azuread {
+					managed_identity {
+						client_id = "00000000-0000-0000-0000-000000000000"
+					}
+				}`,
+		},
+		{
+			name: "oauth in another cloud",
+			cfg: `
+				azuread {
+					cloud = "AzureChina"
+					oauth {
+						client_id     = "00000000-0000-0000-0000-000000000000"
+						client_secret = "secret"
+						tenant_id     = "tenant"
+					}
+				}`,
+		},
+		{
+			name: "invalid client id",
+			cfg: `
+				azuread {
+					managed_identity {
+						client_id = "client"
+					}
+				}`,
+			expectedErrMsg: "the provided Azure Managed Identity client_id is invalid",
+		},
+		{
+			name:           "no credentials",
+			cfg:            `azuread {}`,
+			expectedErrMsg: "must provide an Azure Managed Identity, Azure OAuth or Azure SDK in the Azure AD config",
+		},
+		{
+			name: "bearer token",
+			cfg: `
+				bearer_token = "token"
+				azuread {
+					sdk {
+						tenant_id = "tenant"
+					}
+				}`,
+			expectedErrMsg: "at most one of azuread, basic_auth & bearer_token must be configured",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(`
+				endpoint "azure" {
+					url = "http://example.com"
+					`+tc.cfg+`
+				}
+			`), &args)
+
+			if tc.expectedErrMsg == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.expectedErrMsg)
+			}
+		})
+	}
+}
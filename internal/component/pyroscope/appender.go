@@ -12,11 +12,13 @@ import (
 )
 
 const (
-	LabelNameDelta   = "__delta__"
-	LabelName        = "__name__"
-	LabelServiceName = "service_name"
+	LabelNameDelta    = "__delta__"
+	LabelName         = "__name__"
+	LabelServiceName  = "service_name"
+	LabelNameTenantID = "__tenant_id__"
 
 	HeaderContentType = "Content-Type"
+	HeaderTenantID    = "X-Scope-OrgID"
 )
 
 var NoopAppendable = AppendableFunc(func(_ context.Context, _ labels.Labels, _ []*RawSample) error { return nil })
@@ -2,6 +2,7 @@ package ebpf
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/grafana/alloy/internal/component/discovery"
@@ -23,8 +24,17 @@ type Arguments struct {
 	Demangle             string                 `alloy:"demangle,attr,optional"`
 	GoTableFallback      bool                   `alloy:"go_table_fallback,attr,optional"`
 	PythonEnabled        bool                   `alloy:"python_enabled,attr,optional"`
+	PythonFullFilePath   bool                   `alloy:"python_full_file_path,attr,optional"`
 	SymbolsMapSize       int                    `alloy:"symbols_map_size,attr,optional"`
 	PIDMapSize           int                    `alloy:"pid_map_size,attr,optional"`
+
+	UnknownSymbolModuleOffset bool `alloy:"unknown_symbol_module_offset,attr,optional"`
+	UnknownSymbolAddress      bool `alloy:"unknown_symbol_address,attr,optional"`
+
+	IncludeCgroups    []string `alloy:"include_cgroups,attr,optional"`
+	ExcludeCgroups    []string `alloy:"exclude_cgroups,attr,optional"`
+	IncludeNamespaces []string `alloy:"include_namespaces,attr,optional"`
+	ExcludeNamespaces []string `alloy:"exclude_namespaces,attr,optional"`
 }
 
 // Validate implements syntax.Validator.
@@ -36,5 +46,23 @@ func (arg *Arguments) Validate() error {
 	if arg.PIDMapSize <= 0 {
 		errs = append(errs, errors.New("pid_map_size must be greater than 0"))
 	}
+	if _, err := arg.cgroupFilter(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid cgroup filter: %w", err))
+	}
+	if _, err := arg.namespaceFilter(); err != nil {
+		errs = append(errs, fmt.Errorf("invalid namespace filter: %w", err))
+	}
 	return errors.Join(errs...)
 }
+
+// cgroupFilter returns the filter selecting the processes to profile by
+// their cgroup paths.
+func (arg *Arguments) cgroupFilter() (*patternFilter, error) {
+	return newPatternFilter(arg.IncludeCgroups, arg.ExcludeCgroups)
+}
+
+// namespaceFilter returns the filter selecting the targets to profile by
+// their Kubernetes namespace.
+func (arg *Arguments) namespaceFilter() (*patternFilter, error) {
+	return newPatternFilter(arg.IncludeNamespaces, arg.ExcludeNamespaces)
+}
@@ -37,10 +37,15 @@ func init() {
 }
 
 func New(opts component.Options, args Arguments) (component.Component, error) {
-	targetFinder, err := sd.NewTargetFinder(os.DirFS("/"), opts.Logger, targetsOptionFromArgs(args))
+	cgroupFilter, err := args.cgroupFilter()
+	if err != nil {
+		return nil, err
+	}
+	sdTargetFinder, err := sd.NewTargetFinder(os.DirFS("/"), opts.Logger, targetsOptionFromArgs(args))
 	if err != nil {
 		return nil, fmt.Errorf("ebpf target finder create: %w", err)
 	}
+	targetFinder := newCgroupTargetFinder(sdTargetFinder, os.DirFS("/"), cgroupFilter)
 	ms := newMetrics(opts.Registerer)
 
 	session, err := ebpfspy.NewSession(
@@ -124,6 +129,7 @@ func (c *Component) Run(ctx context.Context) error {
 				return nil
 			case newArgs := <-c.argsUpdate:
 				c.args = newArgs
+				c.updateCgroupFilter()
 				c.session.UpdateTargets(targetsOptionFromArgs(c.args))
 				c.metrics.targetsActive.Set(float64(len(c.targetFinder.DebugInfo())))
 				err := c.session.Update(convertSessionOptions(c.args, c.metrics))
@@ -148,6 +154,18 @@ func (c *Component) Run(ctx context.Context) error {
 	return g.Run()
 }
 
+// updateCgroupFilter applies the cgroup filter of the current arguments to
+// the target finder.
+func (c *Component) updateCgroupFilter() {
+	tf, ok := c.targetFinder.(*cgroupTargetFinder)
+	if !ok {
+		return
+	}
+	// The filter has been checked by Arguments.Validate.
+	filter, _ := c.args.cgroupFilter()
+	tf.UpdateFilter(filter)
+}
+
 func (c *Component) Update(args component.Arguments) error {
 	newArgs := args.(Arguments)
 	c.argsUpdate <- newArgs
@@ -222,8 +240,10 @@ func targetsOptionFromArgs(args Arguments) sd.TargetsOptions {
 	for _, t := range args.Targets {
 		targets = append(targets, t.AsMap())
 	}
+	// The filter has been checked by Arguments.Validate.
+	namespaceFilter, _ := args.namespaceFilter()
 	return sd.TargetsOptions{
-		Targets:            targets,
+		Targets:            filterTargetsByNamespace(targets, namespaceFilter),
 		TargetsOnly:        true,
 		ContainerCacheSize: args.ContainerIDCacheSize,
 	}
@@ -231,14 +251,17 @@ func targetsOptionFromArgs(args Arguments) sd.TargetsOptions {
 
 func convertSessionOptions(args Arguments, ms *metrics) ebpfspy.SessionOptions {
 	return ebpfspy.SessionOptions{
-		CollectUser:   args.CollectUserProfile,
-		CollectKernel: args.CollectKernelProfile,
-		SampleRate:    args.SampleRate,
-		PythonEnabled: args.PythonEnabled,
-		Metrics:       ms.ebpfMetrics,
+		CollectUser:               args.CollectUserProfile,
+		CollectKernel:             args.CollectKernelProfile,
+		UnknownSymbolModuleOffset: args.UnknownSymbolModuleOffset,
+		UnknownSymbolAddress:      args.UnknownSymbolAddress,
+		SampleRate:                args.SampleRate,
+		PythonEnabled:             args.PythonEnabled,
+		Metrics:                   ms.ebpfMetrics,
 		SymbolOptions: symtab.SymbolOptions{
-			GoTableFallback: args.GoTableFallback,
-			DemangleOptions: demangle2.ConvertDemangleOptions(args.Demangle),
+			GoTableFallback:    args.GoTableFallback,
+			PythonFullFilePath: args.PythonFullFilePath,
+			DemangleOptions:    demangle2.ConvertDemangleOptions(args.Demangle),
 		},
 		CacheOptions: symtab.CacheOptions{
 			PidCacheOptions: symtab.GCacheOptions{
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"os"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	ebpfspy "github.com/grafana/pyroscope/ebpf"
@@ -221,7 +222,14 @@ same_file_cache_size = 3000
 container_id_cache_size = 4000
 cache_rounds = 4
 collect_user_profile = true
-collect_kernel_profile = false`,
+collect_kernel_profile = false
+python_full_file_path = true
+unknown_symbol_module_offset = true
+unknown_symbol_address = true
+include_cgroups = ["/kubepods.slice/.*"]
+exclude_cgroups = [".*besteffort.*"]
+include_namespaces = ["prod-.*"]
+exclude_namespaces = ["prod-canary"]`,
 			expected: func() Arguments {
 				x := NewDefaultArguments()
 				x.Targets = []discovery.Target{
@@ -240,6 +248,13 @@ collect_kernel_profile = false`,
 				x.CacheRounds = 4
 				x.CollectUserProfile = true
 				x.CollectKernelProfile = false
+				x.PythonFullFilePath = true
+				x.UnknownSymbolModuleOffset = true
+				x.UnknownSymbolAddress = true
+				x.IncludeCgroups = []string{"/kubepods.slice/.*"}
+				x.ExcludeCgroups = []string{".*besteffort.*"}
+				x.IncludeNamespaces = []string{"prod-.*"}
+				x.ExcludeNamespaces = []string{"prod-canary"}
 				return x
 			},
 		},
@@ -262,6 +277,17 @@ pid_map_size = 0
 `,
 			expectedErr: "symbols_map_size must be greater than 0\npid_map_size must be greater than 0",
 		},
+		{
+			name: "invalid-filters",
+			in: `
+targets = [{"service_name" = "foo", "container_id"= "cid"}]
+forward_to = []
+include_cgroups = ["("]
+exclude_namespaces = ["[a-"]
+`,
+			expectedErr: "invalid cgroup filter: invalid pattern \"(\": error parsing regexp: missing closing ): `^(?:()$`\n" +
+				"invalid namespace filter: invalid pattern \"[a-\": error parsing regexp: invalid character class range: `a-)`",
+		},
 	} {
 		t.Run(tt.name, func(t *testing.T) {
 			arg := Arguments{}
@@ -349,6 +375,73 @@ session = {
 }`, string(v))
 }
 
+type pidTargetFinder struct {
+	sd.TargetFinder
+}
+
+func (m *pidTargetFinder) FindTarget(pid uint32) *sd.Target {
+	return sd.NewTargetForTesting("", pid, sd.DiscoveryTarget{"service_name": "foo"})
+}
+
+func (m *pidTargetFinder) RemoveDeadPID(uint32) {}
+
+func TestCgroupTargetFinder(t *testing.T) {
+	fsys := fstest.MapFS{
+		// cgroup v2
+		"proc/1/cgroup": {Data: []byte("0::/kubepods.slice/kubepods-burstable.slice/cri-containerd-1.scope\n")},
+		"proc/2/cgroup": {Data: []byte("0::/kubepods.slice/kubepods-besteffort.slice/cri-containerd-2.scope\n")},
+		"proc/3/cgroup": {Data: []byte("0::/system.slice/sshd.service\n")},
+		// cgroup v1
+		"proc/4/cgroup": {Data: []byte("12:cpu,cpuacct:/kubepods/burstable/pod1/4\n1:name=systemd:/kubepods.slice/4\n")},
+	}
+	filter, err := newPatternFilter([]string{"/kubepods.slice/.*"}, []string{".*besteffort.*"})
+	require.NoError(t, err)
+
+	tf := newCgroupTargetFinder(&pidTargetFinder{}, fsys, filter)
+	require.NotNil(t, tf.FindTarget(1))
+	require.Nil(t, tf.FindTarget(2))
+	require.Nil(t, tf.FindTarget(3))
+	require.NotNil(t, tf.FindTarget(4))
+	// The cgroup file of an exited process can't be read.
+	require.Nil(t, tf.FindTarget(5))
+
+	tf.RemoveDeadPID(1)
+	require.NotContains(t, tf.selected, uint32(1))
+
+	tf.UpdateFilter(nil)
+	for pid := uint32(1); pid <= 5; pid++ {
+		require.NotNil(t, tf.FindTarget(pid))
+	}
+}
+
+func TestFilterTargetsByNamespace(t *testing.T) {
+	targets := []sd.DiscoveryTarget{
+		{"__meta_kubernetes_namespace": "prod-eu"},
+		{"__meta_kubernetes_namespace": "prod-canary"},
+		{"__meta_kubernetes_namespace": "dev"},
+		{"namespace": "prod-us"},
+		{"service_name": "foo"},
+	}
+
+	filter, err := newPatternFilter([]string{"prod-.*"}, []string{"prod-canary"})
+	require.NoError(t, err)
+	require.Equal(t, []sd.DiscoveryTarget{
+		{"__meta_kubernetes_namespace": "prod-eu"},
+		{"namespace": "prod-us"},
+	}, filterTargetsByNamespace(targets, filter))
+
+	filter, err = newPatternFilter(nil, []string{"dev"})
+	require.NoError(t, err)
+	require.Equal(t, []sd.DiscoveryTarget{
+		{"__meta_kubernetes_namespace": "prod-eu"},
+		{"__meta_kubernetes_namespace": "prod-canary"},
+		{"namespace": "prod-us"},
+		{"service_name": "foo"},
+	}, filterTargetsByNamespace(targets, filter))
+
+	require.Equal(t, targets, filterTargetsByNamespace(targets, nil))
+}
+
 func newTestComponent(opts component.Options, args Arguments, session *mockSession, targetFinder sd.TargetFinder, ms *metrics) *Component {
 	alloyAppendable := pyroscope.NewFanout(args.ForwardTo, opts.ID, opts.Registerer)
 	res := &Component{
@@ -0,0 +1,70 @@
+package ebpf
+
+import (
+	"fmt"
+	"regexp"
+)
+
+// patternFilter selects values, such as cgroup paths or namespaces, with
+// include and exclude patterns. The patterns are fully anchored regular
+// expressions, like the ones of relabeling rules. A nil filter selects every
+// value.
+type patternFilter struct {
+	include []*regexp.Regexp
+	exclude []*regexp.Regexp
+}
+
+func newPatternFilter(include, exclude []string) (*patternFilter, error) {
+	var (
+		f   patternFilter
+		err error
+	)
+	if f.include, err = compilePatterns(include); err != nil {
+		return nil, err
+	}
+	if f.exclude, err = compilePatterns(exclude); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, p := range patterns {
+		re, err := regexp.Compile("^(?:" + p + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", p, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+// empty returns true if the filter selects every value.
+func (f *patternFilter) empty() bool {
+	return f == nil || len(f.include) == 0 && len(f.exclude) == 0
+}
+
+// matches returns true if any of the values matches an include pattern, or
+// there are no include patterns, and none of the values matches an exclude
+// pattern.
+func (f *patternFilter) matches(values ...string) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.include) > 0 && !matchAny(f.include, values) {
+		return false
+	}
+	return !matchAny(f.exclude, values)
+}
+
+func matchAny(patterns []*regexp.Regexp, values []string) bool {
+	for _, re := range patterns {
+		for _, v := range values {
+			if re.MatchString(v) {
+				return true
+			}
+		}
+	}
+	return false
+}
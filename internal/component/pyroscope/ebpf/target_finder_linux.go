@@ -0,0 +1,119 @@
+//go:build (linux && arm64) || (linux && amd64)
+
+package ebpf
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"strings"
+	"sync"
+
+	"github.com/grafana/pyroscope/ebpf/sd"
+)
+
+const labelKubernetesNamespace = "__meta_kubernetes_namespace"
+
+// cgroupTargetFinder wraps a sd.TargetFinder to only profile the processes
+// whose cgroups are selected by a filter. The session doesn't profile a
+// process without a target.
+type cgroupTargetFinder struct {
+	sd.TargetFinder
+	fs fs.FS
+
+	mut      sync.Mutex
+	filter   *patternFilter
+	selected map[uint32]bool
+}
+
+func newCgroupTargetFinder(tf sd.TargetFinder, fsys fs.FS, filter *patternFilter) *cgroupTargetFinder {
+	return &cgroupTargetFinder{
+		TargetFinder: tf,
+		fs:           fsys,
+		filter:       filter,
+		selected:     make(map[uint32]bool),
+	}
+}
+
+func (tf *cgroupTargetFinder) FindTarget(pid uint32) *sd.Target {
+	if !tf.isSelected(pid) {
+		return nil
+	}
+	return tf.TargetFinder.FindTarget(pid)
+}
+
+func (tf *cgroupTargetFinder) RemoveDeadPID(pid uint32) {
+	tf.mut.Lock()
+	delete(tf.selected, pid)
+	tf.mut.Unlock()
+	tf.TargetFinder.RemoveDeadPID(pid)
+}
+
+// UpdateFilter replaces the filter. It must be called before the targets of
+// the session are updated, so that the processes which weren't selected
+// before are looked up again.
+func (tf *cgroupTargetFinder) UpdateFilter(filter *patternFilter) {
+	tf.mut.Lock()
+	defer tf.mut.Unlock()
+	tf.filter = filter
+	tf.selected = make(map[uint32]bool)
+}
+
+func (tf *cgroupTargetFinder) isSelected(pid uint32) bool {
+	tf.mut.Lock()
+	defer tf.mut.Unlock()
+	if tf.filter.empty() {
+		return true
+	}
+	if selected, ok := tf.selected[pid]; ok {
+		return selected
+	}
+	// Processes whose cgroups can't be read are not selected, as they have
+	// most likely exited already.
+	paths, err := readCgroupPaths(tf.fs, pid)
+	selected := err == nil && tf.filter.matches(paths...)
+	tf.selected[pid] = selected
+	return selected
+}
+
+// readCgroupPaths returns the paths of the cgroups of a process from
+// /proc/<pid>/cgroup. There is a single path with cgroup v2, and one path
+// per hierarchy with cgroup v1.
+func readCgroupPaths(fsys fs.FS, pid uint32) ([]string, error) {
+	f, err := fsys.Open(fmt.Sprintf("proc/%d/cgroup", pid))
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var paths []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		// Each line has the format hierarchy-ID:controller-list:cgroup-path.
+		parts := strings.SplitN(scanner.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		paths = append(paths, parts[2])
+	}
+	return paths, scanner.Err()
+}
+
+// filterTargetsByNamespace returns the targets whose Kubernetes namespace is
+// selected by the filter.
+func filterTargetsByNamespace(targets []sd.DiscoveryTarget, filter *patternFilter) []sd.DiscoveryTarget {
+	if filter.empty() {
+		return targets
+	}
+	res := make([]sd.DiscoveryTarget, 0, len(targets))
+	for _, t := range targets {
+		ns, ok := t[labelKubernetesNamespace]
+		if !ok {
+			ns = t["namespace"]
+		}
+		if filter.matches(ns) {
+			res = append(res, t)
+		}
+	}
+	return res
+}
@@ -3,11 +3,14 @@ package receive_http
 import (
 	"bytes"
 	"context"
+	"crypto/subtle"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"reflect"
+	"slices"
+	"strings"
 	"sync"
 
 	"connectrpc.com/connect"
@@ -22,6 +25,7 @@ import (
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
 	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax/alloytypes"
 	pushv1 "github.com/grafana/pyroscope/api/gen/proto/go/push/v1"
 	"github.com/grafana/pyroscope/api/gen/proto/go/push/v1/pushv1connect"
 	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
@@ -47,6 +51,19 @@ func init() {
 type Arguments struct {
 	Server    *fnet.ServerConfig     `alloy:",squash"`
 	ForwardTo []pyroscope.Appendable `alloy:"forward_to,attr"`
+
+	BasicAuth   *BasicAuth        `alloy:"basic_auth,block,optional"`
+	BearerToken alloytypes.Secret `alloy:"bearer_token,attr,optional"`
+
+	TenantID       string   `alloy:"tenant_id,attr,optional"`
+	AllowedTenants []string `alloy:"allowed_tenants,attr,optional"`
+}
+
+// BasicAuth configures the credentials clients must send with basic
+// authentication.
+type BasicAuth struct {
+	Username string            `alloy:"username,attr"`
+	Password alloytypes.Secret `alloy:"password,attr"`
 }
 
 // SetToDefault implements syntax.Defaulter.
@@ -60,11 +77,23 @@ func (a *Arguments) SetToDefault() {
 	}
 }
 
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if a.BasicAuth != nil && a.BearerToken != "" {
+		return errors.New("at most one of basic_auth and bearer_token can be configured")
+	}
+	if a.TenantID != "" && len(a.AllowedTenants) > 0 && !slices.Contains(a.AllowedTenants, a.TenantID) {
+		return fmt.Errorf("tenant_id %q must be one of allowed_tenants", a.TenantID)
+	}
+	return nil
+}
+
 type Component struct {
 	opts               component.Options
 	server             *fnet.TargetServer
 	uncheckedCollector *util.UncheckedCollector
 	appendables        []pyroscope.Appendable
+	args               Arguments
 	mut                sync.Mutex
 }
 
@@ -104,6 +133,7 @@ func (c *Component) Update(args component.Arguments) error {
 	defer c.mut.Unlock()
 
 	c.appendables = newArgs.ForwardTo
+	c.args = newArgs
 
 	// if no server config provided, we'll use defaults
 	if newArgs.Server == nil {
@@ -143,6 +173,8 @@ func (c *Component) Update(args component.Arguments) error {
 	c.server = srv
 
 	return c.server.MountAndRun(func(router *mux.Router) {
+		router.Use(c.authenticate)
+
 		// this mounts the og pyroscope ingest API, mostly used by SDKs
 		router.HandleFunc("/ingest", c.handleIngest).Methods(http.MethodPost)
 
@@ -175,6 +207,11 @@ func (c *Component) Push(ctx context.Context, req *connect.Request[pushv1.PushRe
 
 	appendables := c.getAppendables()
 
+	tenantID, err := c.tenantID(req.Header())
+	if err != nil {
+		return nil, connect.NewError(connect.CodePermissionDenied, err)
+	}
+
 	var wg sync.WaitGroup
 	var errs error
 	var errorMut sync.Mutex
@@ -190,6 +227,7 @@ func (c *Component) Push(ctx context.Context, req *connect.Request[pushv1.PushRe
 			for idx := range req.Msg.Series {
 				lb.Reset(nil)
 				setLabelBuilderFromAPI(lb, req.Msg.Series[idx].Labels)
+				lb.Set(pyroscope.LabelNameTenantID, tenantID)
 				// Ensure service_name label is set
 				lbls := ensureServiceName(lb.Labels())
 				err := appendable.Append(ctx, lbls, apiToAlloySamples(req.Msg.Series[idx].Samples))
@@ -220,9 +258,66 @@ func (c *Component) getAppendables() []pyroscope.Appendable {
 	return appendables
 }
 
+// authenticate rejects the requests without the credentials configured by
+// the basic_auth block or the bearer_token argument.
+func (c *Component) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.mut.Lock()
+		basicAuth, bearerToken := c.args.BasicAuth, c.args.BearerToken
+		c.mut.Unlock()
+
+		switch {
+		case basicAuth != nil:
+			username, password, ok := r.BasicAuth()
+			if !ok ||
+				subtle.ConstantTimeCompare([]byte(username), []byte(basicAuth.Username)) != 1 ||
+				subtle.ConstantTimeCompare([]byte(password), []byte(basicAuth.Password)) != 1 {
+
+				w.Header().Set("WWW-Authenticate", `Basic realm="pyroscope.receive_http"`)
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+		case bearerToken != "":
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(bearerToken)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// tenantID returns the tenant of a request, from its X-Scope-OrgID header or
+// the tenant_id argument, and checks it against the allowed_tenants argument.
+func (c *Component) tenantID(h http.Header) (string, error) {
+	c.mut.Lock()
+	defaultTenantID, allowedTenants := c.args.TenantID, c.args.AllowedTenants
+	c.mut.Unlock()
+
+	tenantID := h.Get(pyroscope.HeaderTenantID)
+	if tenantID == "" {
+		tenantID = defaultTenantID
+	}
+	if len(allowedTenants) > 0 && !slices.Contains(allowedTenants, tenantID) {
+		if tenantID == "" {
+			return "", errors.New("the request has no tenant ID")
+		}
+		return "", fmt.Errorf("tenant %q is not allowed", tenantID)
+	}
+	return tenantID, nil
+}
+
 func (c *Component) handleIngest(w http.ResponseWriter, r *http.Request) {
 	appendables := c.getAppendables()
 
+	tenantID, err := c.tenantID(r.Header)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusForbidden)
+		return
+	}
+
 	// Parse labels early
 	var lbls labels.Labels
 	if nameParam := r.URL.Query().Get("name"); nameParam != "" {
@@ -243,6 +338,10 @@ func (c *Component) handleIngest(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// The tenant label is always set from the validated tenant, so that
+	// clients can't choose another tenant with the name parameter.
+	lbls = labels.NewBuilder(lbls).Set(pyroscope.LabelNameTenantID, tenantID).Labels()
+
 	// Ensure service_name label is set
 	lbls = ensureServiceName(lbls)
 
@@ -409,7 +409,7 @@ func startComponent(t *testing.T, appendables []pyroscope.Appendable) int {
 	port, err := freeport.GetFreePort()
 	require.NoError(t, err)
 
-	args := Arguments{
+	return startComponentWithArgs(t, Arguments{
 		Server: &fnet.ServerConfig{
 			HTTP: &fnet.HTTPConfig{
 				ListenAddress: "localhost",
@@ -417,8 +417,10 @@ func startComponent(t *testing.T, appendables []pyroscope.Appendable) int {
 			},
 		},
 		ForwardTo: appendables,
-	}
+	})
+}
 
+func startComponentWithArgs(t *testing.T, args Arguments) int {
 	comp, err := New(testOptions(t), args)
 	require.NoError(t, err)
 
@@ -429,6 +431,7 @@ func startComponent(t *testing.T, appendables []pyroscope.Appendable) int {
 		require.NoError(t, comp.Run(ctx))
 	}()
 
+	port := args.Server.HTTP.ListenPort
 	waitForServerReady(t, port)
 	return port
 }
@@ -578,3 +581,204 @@ func TestUpdateArgs(t *testing.T) {
 
 	waitForServerReady(t, ports[1])
 }
+
+func TestAuthentication(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           Arguments
+		setAuth        func(r *http.Request)
+		expectedStatus int
+	}{
+		{
+			name:           "no authentication",
+			setAuth:        func(r *http.Request) {},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "basic auth",
+			args:           Arguments{BasicAuth: &BasicAuth{Username: "user", Password: "pass"}},
+			setAuth:        func(r *http.Request) { r.SetBasicAuth("user", "pass") },
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "basic auth with wrong password",
+			args:           Arguments{BasicAuth: &BasicAuth{Username: "user", Password: "pass"}},
+			setAuth:        func(r *http.Request) { r.SetBasicAuth("user", "wrong") },
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "basic auth missing",
+			args:           Arguments{BasicAuth: &BasicAuth{Username: "user", Password: "pass"}},
+			setAuth:        func(r *http.Request) {},
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "bearer token",
+			args:           Arguments{BearerToken: "token"},
+			setAuth:        func(r *http.Request) { r.Header.Set("Authorization", "Bearer token") },
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "bearer token wrong",
+			args:           Arguments{BearerToken: "token"},
+			setAuth:        func(r *http.Request) { r.Header.Set("Authorization", "Bearer other") },
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port, err := freeport.GetFreePort()
+			require.NoError(t, err)
+
+			app := testAppendable(nil)
+			args := tt.args
+			args.Server = &fnet.ServerConfig{HTTP: &fnet.HTTPConfig{ListenAddress: "localhost", ListenPort: port}}
+			args.ForwardTo = []pyroscope.Appendable{app}
+			startComponentWithArgs(t, args)
+
+			// ingest API
+			req, err := http.NewRequest(http.MethodPost, fmt.Sprintf("http://localhost:%d/ingest?name=app", port), bytes.NewReader([]byte("profile")))
+			require.NoError(t, err)
+			tt.setAuth(req)
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			require.Equal(t, tt.expectedStatus, resp.StatusCode)
+
+			// push API
+			client := pushv1connect.NewPusherServiceClient(http.DefaultClient, fmt.Sprintf("http://localhost:%d", port))
+			pushReq := connect.NewRequest(&pushv1.PushRequest{Series: []*pushv1.RawProfileSeries{{
+				Labels:  []*typesv1.LabelPair{{Name: "__name__", Value: "cpu"}},
+				Samples: []*pushv1.RawSample{{RawProfile: []byte("profile")}},
+			}}})
+			tt.setAuth(&http.Request{Header: pushReq.Header()})
+			_, err = client.Push(t.Context(), pushReq)
+			if tt.expectedStatus == http.StatusOK {
+				require.NoError(t, err)
+			} else {
+				require.Equal(t, connect.CodeUnauthenticated, connect.CodeOf(err))
+			}
+		})
+	}
+}
+
+func TestTenants(t *testing.T) {
+	tests := []struct {
+		name           string
+		args           Arguments
+		header         string
+		nameParam      string
+		expectedStatus int
+		expectedTenant string
+	}{
+		{
+			name:           "no tenant",
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "tenant from header",
+			header:         "team-a",
+			expectedStatus: http.StatusOK,
+			expectedTenant: "team-a",
+		},
+		{
+			name:           "default tenant",
+			args:           Arguments{TenantID: "default"},
+			expectedStatus: http.StatusOK,
+			expectedTenant: "default",
+		},
+		{
+			name:           "header overrides default tenant",
+			args:           Arguments{TenantID: "default"},
+			header:         "team-a",
+			expectedStatus: http.StatusOK,
+			expectedTenant: "team-a",
+		},
+		{
+			name:           "allowed tenant",
+			args:           Arguments{AllowedTenants: []string{"team-a", "team-b"}},
+			header:         "team-b",
+			expectedStatus: http.StatusOK,
+			expectedTenant: "team-b",
+		},
+		{
+			name:           "tenant not allowed",
+			args:           Arguments{AllowedTenants: []string{"team-a"}},
+			header:         "team-b",
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "missing tenant not allowed",
+			args:           Arguments{AllowedTenants: []string{"team-a"}},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "tenant label from the client is overridden",
+			args:           Arguments{AllowedTenants: []string{"team-a"}},
+			header:         "team-a",
+			nameParam:      "{__tenant_id__=\"team-b\"}",
+			expectedStatus: http.StatusOK,
+			expectedTenant: "team-a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			port, err := freeport.GetFreePort()
+			require.NoError(t, err)
+
+			app := testAppendable(nil).(*testAppender)
+			args := tt.args
+			args.Server = &fnet.ServerConfig{HTTP: &fnet.HTTPConfig{ListenAddress: "localhost", ListenPort: port}}
+			args.ForwardTo = []pyroscope.Appendable{app}
+			startComponentWithArgs(t, args)
+
+			// ingest API
+			u := fmt.Sprintf("http://localhost:%d/ingest?name=%s", port, url.QueryEscape("app"+tt.nameParam))
+			req, err := http.NewRequest(http.MethodPost, u, bytes.NewReader([]byte("profile")))
+			require.NoError(t, err)
+			if tt.header != "" {
+				req.Header.Set("X-Scope-OrgID", tt.header)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+			require.Equal(t, tt.expectedStatus, resp.StatusCode)
+			if tt.expectedStatus == http.StatusOK {
+				require.Equal(t, tt.expectedTenant, app.lastProfile.Labels.Get(pyroscope.LabelNameTenantID))
+			}
+
+			// push API
+			client := pushv1connect.NewPusherServiceClient(http.DefaultClient, fmt.Sprintf("http://localhost:%d", port))
+			pushReq := connect.NewRequest(&pushv1.PushRequest{Series: []*pushv1.RawProfileSeries{{
+				Labels: []*typesv1.LabelPair{
+					{Name: "__name__", Value: "cpu"},
+					{Name: "__tenant_id__", Value: "team-b"},
+				},
+				Samples: []*pushv1.RawSample{{RawProfile: []byte("profile")}},
+			}}})
+			if tt.header != "" {
+				pushReq.Header().Set("X-Scope-OrgID", tt.header)
+			}
+			_, err = client.Push(t.Context(), pushReq)
+			if tt.expectedStatus != http.StatusOK {
+				require.Equal(t, connect.CodePermissionDenied, connect.CodeOf(err))
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.expectedTenant, app.pushedLabels[len(app.pushedLabels)-1].Get(pyroscope.LabelNameTenantID))
+		})
+	}
+}
+
+func TestValidateArguments(t *testing.T) {
+	args := Arguments{BasicAuth: &BasicAuth{Username: "user", Password: "pass"}, BearerToken: "token"}
+	require.EqualError(t, args.Validate(), "at most one of basic_auth and bearer_token can be configured")
+
+	args = Arguments{TenantID: "team-c", AllowedTenants: []string{"team-a"}}
+	require.EqualError(t, args.Validate(), `tenant_id "team-c" must be one of allowed_tenants`)
+
+	args = Arguments{TenantID: "team-a", AllowedTenants: []string{"team-a"}}
+	require.NoError(t, args.Validate())
+}
@@ -213,6 +213,9 @@ func (c *Component) Appender() pyroscope.Appender {
 type cacheItem struct {
 	original  model.LabelSet
 	relabeled model.LabelSet
+	// keep is stored separately, as rules can remove all labels of a
+	// profile without dropping it.
+	keep bool
 }
 
 // relabel applies the configured relabeling rules to the input labels
@@ -230,14 +233,14 @@ func (c *Component) relabel(lbls labels.Labels) (labels.Labels, bool) {
 	builder := labels.NewBuilder(lbls)
 	keep := relabel.ProcessBuilder(builder, c.rcs...)
 	if !keep {
-		c.addToCache(hash, labelSet, labels.EmptyLabels())
+		c.addToCache(hash, labelSet, labels.EmptyLabels(), false)
 		return labels.EmptyLabels(), false
 	}
 
 	newLabels := builder.Labels()
 
 	// Cache result
-	c.addToCache(hash, labelSet, newLabels)
+	c.addToCache(hash, labelSet, newLabels, true)
 
 	return newLabels, true
 }
@@ -247,10 +250,7 @@ func (c *Component) getCacheEntry(hash model.Fingerprint, labelSet model.LabelSe
 		for _, item := range val {
 			if labelSet.Equal(item.original) {
 				c.metrics.cacheHits.Inc()
-				if len(item.relabeled) == 0 {
-					return labels.Labels{}, false, true
-				}
-				return toLabelsLabels(item.relabeled), true, true
+				return toLabelsLabels(item.relabeled), item.keep, true
 			}
 		}
 	}
@@ -258,7 +258,7 @@ func (c *Component) getCacheEntry(hash model.Fingerprint, labelSet model.LabelSe
 	return labels.Labels{}, false, false
 }
 
-func (c *Component) addToCache(hash model.Fingerprint, original model.LabelSet, relabeled labels.Labels) {
+func (c *Component) addToCache(hash model.Fingerprint, original model.LabelSet, relabeled labels.Labels, keep bool) {
 	var cacheValue []cacheItem
 	if val, exists := c.cache.Get(hash); exists {
 		cacheValue = val
@@ -266,6 +266,7 @@ func (c *Component) addToCache(hash model.Fingerprint, original model.LabelSet,
 	cacheValue = append(cacheValue, cacheItem{
 		original:  original,
 		relabeled: toModelLabelSet(relabeled),
+		keep:      keep,
 	})
 	c.cache.Add(hash, cacheValue)
 	c.metrics.cacheSize.Set(float64(c.cache.Len()))
@@ -238,6 +238,34 @@ func TestCache(t *testing.T) {
 	require.Equal(t, 1, c.cache.Len(), "cache length should not change after hit")
 }
 
+func TestCacheAllLabelsRemoved(t *testing.T) {
+	app := NewTestAppender()
+	c, err := New(component.Options{
+		Logger:        util.TestLogger(t),
+		Registerer:    prometheus.NewRegistry(),
+		OnStateChange: func(e component.Exports) {},
+	}, Arguments{
+		ForwardTo: []pyroscope.Appendable{app},
+		RelabelConfigs: []*alloy_relabel.Config{{
+			Action: "labeldrop",
+			Regex:  alloy_relabel.Regexp{Regexp: regexp.MustCompile(".*")},
+		}},
+		MaxCacheSize: 4,
+	})
+	require.NoError(t, err)
+
+	// Removing all the labels doesn't drop the profile, with or without a
+	// cache hit.
+	lbls := labels.FromStrings("env", "prod")
+	require.NoError(t, c.AppendIngest(t.Context(), &pyroscope.IncomingProfile{Labels: lbls}))
+	require.NoError(t, c.AppendIngest(t.Context(), &pyroscope.IncomingProfile{Labels: lbls}))
+	require.Equal(t, 1.0, testutil.ToFloat64(c.metrics.cacheHits))
+	require.Len(t, app.Profiles(), 2)
+	for _, p := range app.Profiles() {
+		require.True(t, p.Labels.IsEmpty())
+	}
+}
+
 func TestCacheCollisions(t *testing.T) {
 	app := NewTestAppender()
 	c, err := New(component.Options{
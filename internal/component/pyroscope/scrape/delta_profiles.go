@@ -35,6 +35,12 @@ type DeltaProfiler interface {
 }
 
 func NewDeltaAppender(appender pyroscope.Appender, labels labels.Labels) pyroscope.Appender {
+	// Profiles which are already deltas, such as godeltaprof profiles, are
+	// labeled so the delta isn't computed again.
+	if labels.Get(pyroscope.LabelNameDelta) == "false" {
+		return appender
+	}
+
 	types, ok := deltaProfiles[labels.Get(model.MetricNameLabel)]
 	if !ok {
 		// for profiles that we don't need to produce delta, just return the appender
@@ -66,6 +66,27 @@ func TestDeltaProfilerAppenderNoop(t *testing.T) {
 	require.Equal(t, in, unmarshal(t, actual[0].RawProfile))
 }
 
+func TestDeltaProfilerAppenderGodeltaprof(t *testing.T) {
+	lbs := labels.Labels{
+		{Name: pyroscope.LabelNameDelta, Value: "false"},
+		{Name: model.MetricNameLabel, Value: pprofMutex},
+	}
+
+	actual := []*pyroscope.RawSample{}
+	appender := NewDeltaAppender(
+		pyroscope.AppendableFunc(func(ctx context.Context, lbs labels.Labels, samples []*pyroscope.RawSample) error {
+			actual = append(actual, samples...)
+			return nil
+		}), lbs)
+
+	// Profiles which are already deltas are forwarded as they are, including the first one.
+	in := newMemoryProfile(0, 0)
+	err := appender.Append(t.Context(), lbs, []*pyroscope.RawSample{{RawProfile: marshal(t, in)}})
+	require.NoError(t, err)
+	require.Len(t, actual, 1)
+	require.Equal(t, in, unmarshal(t, actual[0].RawProfile))
+}
+
 func marshal(t testing.TB, profile *googlev1.Profile) []byte {
 	t.Helper()
 	data, err := profile.MarshalVT()
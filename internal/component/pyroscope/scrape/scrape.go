@@ -29,6 +29,8 @@ const (
 	pprofMutex               string        = "mutex"
 	pprofProcessCPU          string        = "process_cpu"
 	pprofFgprof              string        = "fgprof"
+	pprofWall                string        = "wall"
+	pprofOffCPU              string        = "off_cpu"
 	pprofGoDeltaProfMemory   string        = "godeltaprof_memory"
 	pprofGoDeltaProfBlock    string        = "godeltaprof_block"
 	pprofGoDeltaProfMutex    string        = "godeltaprof_mutex"
@@ -98,6 +100,8 @@ type ProfilingConfig struct {
 	Mutex             ProfilingTarget         `alloy:"profile.mutex,block,optional"`
 	ProcessCPU        ProfilingTarget         `alloy:"profile.process_cpu,block,optional"`
 	FGProf            ProfilingTarget         `alloy:"profile.fgprof,block,optional"`
+	Wall              ProfilingTarget         `alloy:"profile.wall,block,optional"`
+	OffCPU            ProfilingTarget         `alloy:"profile.off_cpu,block,optional"`
 	GoDeltaProfMemory ProfilingTarget         `alloy:"profile.godeltaprof_memory,block,optional"`
 	GoDeltaProfMutex  ProfilingTarget         `alloy:"profile.godeltaprof_mutex,block,optional"`
 	GoDeltaProfBlock  ProfilingTarget         `alloy:"profile.godeltaprof_block,block,optional"`
@@ -117,6 +121,8 @@ func (cfg *ProfilingConfig) AllTargets() map[string]ProfilingTarget {
 		pprofMutex:             cfg.Mutex,
 		pprofProcessCPU:        cfg.ProcessCPU,
 		pprofFgprof:            cfg.FGProf,
+		pprofWall:              cfg.Wall,
+		pprofOffCPU:            cfg.OffCPU,
 		pprofGoDeltaProfMemory: cfg.GoDeltaProfMemory,
 		pprofGoDeltaProfMutex:  cfg.GoDeltaProfMutex,
 		pprofGoDeltaProfBlock:  cfg.GoDeltaProfBlock,
@@ -160,6 +166,16 @@ var DefaultProfilingConfig = ProfilingConfig{
 		Path:    "/debug/fgprof",
 		Delta:   true,
 	},
+	Wall: ProfilingTarget{
+		Enabled: false,
+		Path:    "/debug/pprof/wall",
+		Delta:   true,
+	},
+	OffCPU: ProfilingTarget{
+		Enabled: false,
+		Path:    "/debug/pprof/off_cpu",
+		Delta:   true,
+	},
 	// https://github.com/grafana/godeltaprof/blob/main/http/pprof/pprof.go#L21
 	GoDeltaProfMemory: ProfilingTarget{
 		Enabled: false,
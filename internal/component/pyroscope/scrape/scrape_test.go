@@ -152,6 +152,29 @@ func TestUnmarshalConfig(t *testing.T) {
 				return r
 			},
 		},
+		"wall and off-cpu": {
+			in: `
+			targets    = []
+			forward_to = null
+			profiling_config {
+				profile.wall {
+					enabled = true
+				}
+				profile.off_cpu {
+					enabled = true
+					path    = "/debug/offcpu"
+				}
+			}
+			`,
+			expected: func() Arguments {
+				r := NewDefaultArguments()
+				r.Targets = make([]discovery.Target, 0)
+				r.ProfilingConfig.Wall.Enabled = true
+				r.ProfilingConfig.OffCPU.Enabled = true
+				r.ProfilingConfig.OffCPU.Path = "/debug/offcpu"
+				return r
+			},
+		},
 		"invalid cpu scrape_interval": {
 			in: `
 			targets    = []
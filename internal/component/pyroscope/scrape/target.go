@@ -29,6 +29,8 @@ import (
 	"github.com/prometheus/prometheus/config"
 	"github.com/prometheus/prometheus/discovery/targetgroup"
 	"github.com/prometheus/prometheus/model/labels"
+
+	"github.com/grafana/alloy/internal/component/pyroscope"
 )
 
 // TargetHealth describes the health state of a target.
@@ -59,7 +61,10 @@ type Target struct {
 
 // NewTarget creates a reasonably configured target for querying.
 func NewTarget(lbls labels.Labels, params url.Values) *Target {
-	var publicLabels labels.Labels
+	var (
+		publicLabels labels.Labels
+		godeltaprof  bool
+	)
 	// lbls are sorted. Private labels goes before public labels.
 	// find pivot to calculate publicLabels as subslice, with no allocations
 	for i, l := range lbls {
@@ -69,10 +74,13 @@ func NewTarget(lbls labels.Labels, params url.Values) *Target {
 				switch l.Value {
 				case pprofGoDeltaProfMemory:
 					lbls[i].Value = pprofMemory
+					godeltaprof = true
 				case pprofGoDeltaProfBlock:
 					lbls[i].Value = pprofBlock
+					godeltaprof = true
 				case pprofGoDeltaProfMutex:
 					lbls[i].Value = pprofMutex
+					godeltaprof = true
 				}
 			}
 			continue
@@ -80,6 +88,11 @@ func NewTarget(lbls labels.Labels, params url.Values) *Target {
 		publicLabels = lbls[i:]
 		break
 	}
+	// godeltaprof computes the delta on the target, so neither the delta
+	// appender nor the server must compute it again.
+	if godeltaprof {
+		lbls = labels.NewBuilder(lbls).Set(pyroscope.LabelNameDelta, "false").Labels()
+	}
 	url := urlFromTarget(lbls, params)
 
 	h := fnv.New64a()
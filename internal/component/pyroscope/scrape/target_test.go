@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/alloy/internal/component/pyroscope"
 	"github.com/stretchr/testify/assert"
 
 	"github.com/prometheus/common/model"
@@ -174,6 +175,10 @@ func Test_NewTarget_godeltaprof(t *testing.T) {
 	assert.Equal(t, pprofMemory, withoutGodeltaprof.allLabels.Get(model.MetricNameLabel))
 	assert.Equal(t, "/debug/pprof/heap", withoutGodeltaprof.allLabels.Get(ProfilePath))
 	assert.Equal(t, "/debug/pprof/delta_heap", withGodeltaprof.allLabels.Get(ProfilePath))
+
+	// The delta is computed by godeltaprof, so it must not be computed again.
+	assert.Equal(t, "false", withGodeltaprof.allLabels.Get(pyroscope.LabelNameDelta))
+	assert.Empty(t, withoutGodeltaprof.allLabels.Get(pyroscope.LabelNameDelta))
 }
 
 func Test_targetsFromGroup_withSpecifiedDeltaProfilingDuration(t *testing.T) {
@@ -347,6 +352,8 @@ func TestProfileURL(t *testing.T) {
 				args.ProfilingConfig.GoDeltaProfMutex.Enabled = true
 				args.ProfilingConfig.GoDeltaProfMemory.Enabled = true
 				args.ProfilingConfig.FGProf.Enabled = true
+				args.ProfilingConfig.Wall.Enabled = true
+				args.ProfilingConfig.OffCPU.Enabled = true
 				args.ProfilingConfig.Custom = []CustomProfilingTarget{{
 					Enabled: true,
 					Path:    "/foo239",
@@ -367,7 +374,9 @@ func TestProfileURL(t *testing.T) {
 				"https://127.0.0.1:4100/debug/pprof/delta_mutex",
 				"https://127.0.0.1:4100/debug/pprof/goroutine",
 				"https://127.0.0.1:4100/debug/pprof/mutex",
+				"https://127.0.0.1:4100/debug/pprof/off_cpu?seconds=14",
 				"https://127.0.0.1:4100/debug/pprof/profile?seconds=14",
+				"https://127.0.0.1:4100/debug/pprof/wall?seconds=14",
 				"https://127.0.0.1:4100/foo239",
 			},
 		},
@@ -379,6 +388,8 @@ func TestProfileURL(t *testing.T) {
 				args.ProfilingConfig.GoDeltaProfMutex.Enabled = true
 				args.ProfilingConfig.GoDeltaProfMemory.Enabled = true
 				args.ProfilingConfig.FGProf.Enabled = true
+				args.ProfilingConfig.Wall.Enabled = true
+				args.ProfilingConfig.OffCPU.Enabled = true
 				args.ProfilingConfig.Custom = []CustomProfilingTarget{{
 					Enabled: true,
 					Path:    "/foo239",
@@ -400,7 +411,9 @@ func TestProfileURL(t *testing.T) {
 				"https://127.0.0.1:4100/mimir-prometheus/debug/pprof/delta_mutex",
 				"https://127.0.0.1:4100/mimir-prometheus/debug/pprof/goroutine",
 				"https://127.0.0.1:4100/mimir-prometheus/debug/pprof/mutex",
+				"https://127.0.0.1:4100/mimir-prometheus/debug/pprof/off_cpu?seconds=14",
 				"https://127.0.0.1:4100/mimir-prometheus/debug/pprof/profile?seconds=14",
+				"https://127.0.0.1:4100/mimir-prometheus/debug/pprof/wall?seconds=14",
 				"https://127.0.0.1:4100/mimir-prometheus/foo239",
 			},
 		},
@@ -11,6 +11,9 @@ type metrics struct {
 	sentProfiles    *prometheus.CounterVec
 	droppedProfiles *prometheus.CounterVec
 	retries         *prometheus.CounterVec
+
+	retryQueueLength *prometheus.GaugeVec
+	retryQueueFull   *prometheus.CounterVec
 }
 
 func newMetrics(reg prometheus.Registerer) *metrics {
@@ -35,6 +38,14 @@ func newMetrics(reg prometheus.Registerer) *metrics {
 			Name: "pyroscope_write_retries_total",
 			Help: "Total number of retries to Pyroscope.",
 		}, []string{"endpoint"}),
+		retryQueueLength: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "pyroscope_write_retry_queue_length",
+			Help: "Number of requests being retried to Pyroscope.",
+		}, []string{"endpoint"}),
+		retryQueueFull: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "pyroscope_write_retry_queue_full_total",
+			Help: "Total number of requests to Pyroscope dropped instead of retried because the retry queue was full.",
+		}, []string{"endpoint"}),
 	}
 
 	if reg != nil {
@@ -43,6 +54,8 @@ func newMetrics(reg prometheus.Registerer) *metrics {
 		m.sentProfiles = util.MustRegisterOrGet(reg, m.sentProfiles).(*prometheus.CounterVec)
 		m.droppedProfiles = util.MustRegisterOrGet(reg, m.droppedProfiles).(*prometheus.CounterVec)
 		m.retries = util.MustRegisterOrGet(reg, m.retries).(*prometheus.CounterVec)
+		m.retryQueueLength = util.MustRegisterOrGet(reg, m.retryQueueLength).(*prometheus.GaugeVec)
+		m.retryQueueFull = util.MustRegisterOrGet(reg, m.retryQueueFull).(*prometheus.CounterVec)
 	}
 
 	return m
@@ -32,6 +32,7 @@ import (
 	pushv1 "github.com/grafana/pyroscope/api/gen/proto/go/push/v1"
 	"github.com/grafana/pyroscope/api/gen/proto/go/push/v1/pushv1connect"
 	typesv1 "github.com/grafana/pyroscope/api/gen/proto/go/types/v1"
+	"golang.org/x/time/rate"
 )
 
 var (
@@ -77,6 +78,10 @@ type EndpointOptions struct {
 	MinBackoff        time.Duration            `alloy:"min_backoff_period,attr,optional"`  // start backoff at this level
 	MaxBackoff        time.Duration            `alloy:"max_backoff_period,attr,optional"`  // increase exponentially to this level
 	MaxBackoffRetries int                      `alloy:"max_backoff_retries,attr,optional"` // give up after this many; zero means infinite retries
+	RetryQueueSize    int                      `alloy:"retry_queue_size,attr,optional"`    // requests retried at the same time; zero means unbounded
+	TenantID          string                   `alloy:"tenant_id,attr,optional"`
+	RateLimit         float64                  `alloy:"rate_limit,attr,optional"` // requests per second; zero means no limit
+	RateLimitBurst    int                      `alloy:"rate_limit_burst,attr,optional"`
 }
 
 func GetDefaultEndpointOptions() EndpointOptions {
@@ -85,6 +90,7 @@ func GetDefaultEndpointOptions() EndpointOptions {
 		MinBackoff:        500 * time.Millisecond,
 		MaxBackoff:        5 * time.Minute,
 		MaxBackoffRetries: 10,
+		RateLimitBurst:    1,
 		HTTPClientConfig:  config.CloneDefaultHTTPClientConfig(),
 	}
 
@@ -98,6 +104,16 @@ func (r *EndpointOptions) SetToDefault() {
 
 // Validate implements syntax.Validator.
 func (r *EndpointOptions) Validate() error {
+	if r.RetryQueueSize < 0 {
+		return fmt.Errorf("retry_queue_size must not be negative, got %d", r.RetryQueueSize)
+	}
+	if r.RateLimit < 0 {
+		return fmt.Errorf("rate_limit must not be negative, got %v", r.RateLimit)
+	}
+	if r.RateLimit > 0 && r.RateLimitBurst < 1 {
+		return fmt.Errorf("rate_limit_burst must be at least 1 when rate_limit is set, got %d", r.RateLimitBurst)
+	}
+
 	// We must explicitly Validate because HTTPClientConfig is squashed and it won't run otherwise
 	if r.HTTPClientConfig != nil {
 		return r.HTTPClientConfig.Validate()
@@ -106,6 +122,26 @@ func (r *EndpointOptions) Validate() error {
 	return nil
 }
 
+// rateLimiter returns the limiter of the requests sent to the endpoint.
+func (r *EndpointOptions) rateLimiter() *rate.Limiter {
+	if r.RateLimit == 0 {
+		return rate.NewLimiter(rate.Inf, 0)
+	}
+	return rate.NewLimiter(rate.Limit(r.RateLimit), r.RateLimitBurst)
+}
+
+// setTenantID sets the tenant header of a request sent to the endpoint. The
+// tenant of the profile takes precedence over the tenant of the endpoint,
+// which takes precedence over the headers of the endpoint.
+func (r *EndpointOptions) setTenantID(h http.Header, profileTenantID string) {
+	switch {
+	case profileTenantID != "":
+		h.Set(pyroscope.HeaderTenantID, profileTenantID)
+	case r.TenantID != "":
+		h.Set(pyroscope.HeaderTenantID, r.TenantID)
+	}
+}
+
 // Component is the pyroscope.write component.
 type Component struct {
 	opts    component.Options
@@ -158,6 +194,8 @@ type fanOutClient struct {
 	// The list of push clients to fan out to.
 	pushClients   []pushv1connect.PusherServiceClient
 	ingestClients map[*EndpointOptions]*http.Client
+	limiters      []*rate.Limiter
+	retryQueues   []retryQueue
 	config        Arguments
 	opts          component.Options
 	metrics       *metrics
@@ -167,6 +205,8 @@ type fanOutClient struct {
 func NewFanOut(opts component.Options, config Arguments, metrics *metrics) (*fanOutClient, error) {
 	pushClients := make([]pushv1connect.PusherServiceClient, 0, len(config.Endpoints))
 	ingestClients := make(map[*EndpointOptions]*http.Client)
+	limiters := make([]*rate.Limiter, 0, len(config.Endpoints))
+	retryQueues := make([]retryQueue, 0, len(config.Endpoints))
 	uid := alloyseed.Get().UID
 
 	for _, endpoint := range config.Endpoints {
@@ -184,10 +224,14 @@ func NewFanOut(opts component.Options, config Arguments, metrics *metrics) (*fan
 			pushv1connect.NewPusherServiceClient(httpClient, endpoint.URL, WithUserAgent(userAgent)),
 		)
 		ingestClients[endpoint] = httpClient
+		limiters = append(limiters, endpoint.rateLimiter())
+		retryQueues = append(retryQueues, newRetryQueue(endpoint.RetryQueueSize))
 	}
 	return &fanOutClient{
 		pushClients:   pushClients,
 		ingestClients: ingestClients,
+		limiters:      limiters,
+		retryQueues:   retryQueues,
 		config:        config,
 		opts:          opts,
 		metrics:       metrics,
@@ -205,6 +249,7 @@ func (f *fanOutClient) Push(
 		errs                  error
 		errorMut              sync.Mutex
 		reqSize, profileCount = requestSize(req)
+		tenantID              = req.Header().Get(pyroscope.HeaderTenantID)
 	)
 
 	for i, client := range f.pushClients {
@@ -225,8 +270,13 @@ func (f *fanOutClient) Push(
 			for k, v := range f.config.Endpoints[i].Headers {
 				req.Header().Set(k, v)
 			}
+			f.config.Endpoints[i].setTenantID(req.Header(), tenantID)
+			queued := false
 			for {
 				err = func() error {
+					if err := f.limiters[i].Wait(ctx); err != nil {
+						return err
+					}
 					ctx, cancel := context.WithTimeout(ctx, f.config.Endpoints[i].RemoteTimeout)
 					defer cancel()
 
@@ -243,12 +293,25 @@ func (f *fanOutClient) Push(
 				if !shouldRetry(err) {
 					break
 				}
+				if !queued {
+					if !f.retryQueues[i].tryEnter() {
+						f.metrics.retryQueueFull.WithLabelValues(f.config.Endpoints[i].URL).Inc()
+						err = fmt.Errorf("retry queue is full: %w", err)
+						break
+					}
+					queued = true
+					f.metrics.retryQueueLength.WithLabelValues(f.config.Endpoints[i].URL).Inc()
+				}
 				backoff.Wait()
 				if !backoff.Ongoing() {
 					break
 				}
 				f.metrics.retries.WithLabelValues(f.config.Endpoints[i].URL).Inc()
 			}
+			if queued {
+				f.retryQueues[i].leave()
+				f.metrics.retryQueueLength.WithLabelValues(f.config.Endpoints[i].URL).Dec()
+			}
 			if err != nil {
 				f.metrics.droppedBytes.WithLabelValues(f.config.Endpoints[i].URL).Add(float64(reqSize))
 				f.metrics.droppedProfiles.WithLabelValues(f.config.Endpoints[i].URL).Add(float64(profileCount))
@@ -266,6 +329,39 @@ func (f *fanOutClient) Push(
 	return connect.NewResponse(&pushv1.PushResponse{}), nil
 }
 
+// retryQueue bounds the number of requests retried at the same time for an
+// endpoint. Requests which fail while the queue is full are dropped instead of
+// being retried. A nil retryQueue is unbounded.
+type retryQueue chan struct{}
+
+func newRetryQueue(size int) retryQueue {
+	if size == 0 {
+		return nil
+	}
+	return make(retryQueue, size)
+}
+
+// tryEnter adds a request to the queue, and returns false if the queue is
+// full.
+func (q retryQueue) tryEnter() bool {
+	if q == nil {
+		return true
+	}
+	select {
+	case q <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// leave removes a request added with tryEnter from the queue.
+func (q retryQueue) leave() {
+	if q != nil {
+		<-q
+	}
+}
+
 func shouldRetry(err error) bool {
 	if errors.Is(err, context.DeadlineExceeded) {
 		return true
@@ -333,12 +429,18 @@ func (f *fanOutClient) Append(ctx context.Context, lbs labels.Labels, samples []
 			RawProfile: sample.RawProfile,
 		})
 	}
-	// push to all clients
-	_, err := f.Push(ctx, connect.NewRequest(&pushv1.PushRequest{
+	req := connect.NewRequest(&pushv1.PushRequest{
 		Series: []*pushv1.RawProfileSeries{
 			{Labels: protoLabels, Samples: protoSamples},
 		},
-	}))
+	})
+	// The tenant label is reserved, so it has been filtered from the labels
+	// sent to the endpoints above.
+	if tenantID := lbs.Get(pyroscope.LabelNameTenantID); tenantID != "" {
+		req.Header().Set(pyroscope.HeaderTenantID, tenantID)
+	}
+	// push to all clients
+	_, err := f.Push(ctx, req)
 	return err
 }
 
@@ -372,7 +474,8 @@ func (f *fanOutClient) AppendIngest(ctx context.Context, profile *pyroscope.Inco
 	query := profile.URL.Query()
 	ls := labelset.New(make(map[string]string))
 
-	finalLabels := ensureNameMatchesService(profile.Labels)
+	tenantID := profile.Labels.Get(pyroscope.LabelNameTenantID)
+	finalLabels := ensureNameMatchesService(labels.NewBuilder(profile.Labels).Del(pyroscope.LabelNameTenantID).Labels())
 
 	if err := validateLabels(finalLabels); err != nil {
 		return fmt.Errorf("invalid labels in profile: %w", err)
@@ -414,6 +517,7 @@ func (f *fanOutClient) AppendIngest(ctx context.Context, profile *pyroscope.Inco
 			for k, v := range endpoint.Headers {
 				req.Header.Set(k, v)
 			}
+			endpoint.setTenantID(req.Header, tenantID)
 
 			// now set profile content type, overwrite what existed
 			for idx := range profile.ContentType {
@@ -424,6 +528,11 @@ func (f *fanOutClient) AppendIngest(ctx context.Context, profile *pyroscope.Inco
 				req.Header.Add(pyroscope.HeaderContentType, profile.ContentType[idx])
 			}
 
+			if err := f.limiters[endpointIdx].Wait(ctx); err != nil {
+				util.ErrorsJoinConcurrent(&errs, fmt.Errorf("rate limit for endpoint[%d]: %w", endpointIdx, err), &errorMut)
+				return
+			}
+
 			resp, err := f.ingestClients[endpoint].Do(req)
 			if err != nil {
 				util.ErrorsJoinConcurrent(&errs, fmt.Errorf("do request for endpoint[%d]: %w", endpointIdx, err), &errorMut)
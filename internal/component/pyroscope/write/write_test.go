@@ -14,6 +14,7 @@ import (
 
 	"connectrpc.com/connect"
 	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/config"
 	"github.com/grafana/alloy/internal/component/pyroscope"
 	"github.com/grafana/alloy/internal/util"
 	"github.com/grafana/alloy/syntax"
@@ -225,6 +226,10 @@ func Test_Unmarshal_Config(t *testing.T) {
 		min_backoff_period = "1s"
 		max_backoff_period = "10s"
 		max_backoff_retries = 10
+		retry_queue_size = 5
+		tenant_id = "tenant-a"
+		rate_limit = 2.5
+		rate_limit_burst = 3
 	}
 	external_labels = {
 		"foo" = "bar",
@@ -237,6 +242,40 @@ func Test_Unmarshal_Config(t *testing.T) {
 	require.Equal(t, time.Second, arg.Endpoints[1].MinBackoff)
 	require.Equal(t, time.Second*10, arg.Endpoints[1].MaxBackoff)
 	require.Equal(t, 10, arg.Endpoints[1].MaxBackoffRetries)
+	require.Equal(t, 5, arg.Endpoints[1].RetryQueueSize)
+	require.Equal(t, "tenant-a", arg.Endpoints[1].TenantID)
+	require.Equal(t, 2.5, arg.Endpoints[1].RateLimit)
+	require.Equal(t, 3, arg.Endpoints[1].RateLimitBurst)
+	require.Equal(t, 1, arg.Endpoints[0].RateLimitBurst)
+}
+
+func TestBadEndpointConfig(t *testing.T) {
+	for _, tc := range []struct {
+		config      string
+		expectedErr string
+	}{
+		{
+			config:      `retry_queue_size = -1`,
+			expectedErr: "retry_queue_size must not be negative, got -1",
+		},
+		{
+			config:      `rate_limit = -1`,
+			expectedErr: "rate_limit must not be negative, got -1",
+		},
+		{
+			config: `rate_limit = 1
+			rate_limit_burst = 0`,
+			expectedErr: "rate_limit_burst must be at least 1 when rate_limit is set, got 0",
+		},
+	} {
+		var args Arguments
+		err := syntax.Unmarshal([]byte(`
+		endpoint {
+			url = "http://localhost:4100"
+			`+tc.config+`
+		}`), &args)
+		require.ErrorContains(t, err, tc.expectedErr)
+	}
 }
 
 func TestBadAlloyConfig(t *testing.T) {
@@ -653,3 +692,123 @@ func Test_Write_FanOut_ValidateLabels(t *testing.T) {
 		})
 	}
 }
+
+func newTestFanOut(t *testing.T, endpoints ...*EndpointOptions) *fanOutClient {
+	t.Helper()
+	for _, e := range endpoints {
+		if e.HTTPClientConfig == nil {
+			e.HTTPClientConfig = config.CloneDefaultHTTPClientConfig()
+		}
+		if e.RemoteTimeout == 0 {
+			e.RemoteTimeout = GetDefaultEndpointOptions().RemoteTimeout
+		}
+	}
+	f, err := NewFanOut(component.Options{
+		Logger: util.TestAlloyLogger(t),
+	}, Arguments{Endpoints: endpoints}, newMetrics(prometheus.NewRegistry()))
+	require.NoError(t, err)
+	return f
+}
+
+func Test_Write_TenantID(t *testing.T) {
+	tenants := make(chan string, 10)
+	_, handler := pushv1connect.NewPusherServiceHandler(PushFunc(
+		func(_ context.Context, req *connect.Request[pushv1.PushRequest]) (*connect.Response[pushv1.PushResponse], error) {
+			for _, l := range req.Msg.Series[0].Labels {
+				require.NotEqual(t, pyroscope.LabelNameTenantID, l.Name)
+			}
+			tenants <- req.Header().Get("X-Scope-OrgID")
+			return &connect.Response[pushv1.PushResponse]{}, nil
+		},
+	))
+	mux := http.NewServeMux()
+	mux.Handle("/", handler)
+	mux.HandleFunc("/ingest", func(w http.ResponseWriter, r *http.Request) {
+		ls, err := labelset.Parse(r.URL.Query().Get("name"))
+		require.NoError(t, err)
+		require.NotContains(t, ls.Labels(), pyroscope.LabelNameTenantID)
+		tenants <- r.Header.Get("X-Scope-OrgID")
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	f := newTestFanOut(t,
+		&EndpointOptions{URL: server.URL, Headers: map[string]string{"X-Scope-OrgID": "header"}},
+		&EndpointOptions{URL: server.URL, Headers: map[string]string{"X-Scope-OrgID": "header"}, TenantID: "endpoint"},
+	)
+	samples := []*pyroscope.RawSample{{RawProfile: []byte("pprofraw")}}
+
+	// The tenant of the endpoint overrides the headers of the endpoint.
+	require.NoError(t, f.Append(t.Context(), labels.FromStrings("__name__", "test"), samples))
+	require.ElementsMatch(t, []string{"header", "endpoint"}, []string{<-tenants, <-tenants})
+
+	// The tenant of the profile overrides the tenant of the endpoint.
+	require.NoError(t, f.Append(t.Context(), labels.FromStrings("__name__", "test", "__tenant_id__", "profile"), samples))
+	require.Equal(t, []string{"profile", "profile"}, []string{<-tenants, <-tenants})
+
+	require.NoError(t, f.AppendIngest(t.Context(), &pyroscope.IncomingProfile{
+		RawBody: []byte("pprofraw"),
+		URL:     &url.URL{Path: "/ingest"},
+		Labels:  labels.FromStrings("__name__", "test", "__tenant_id__", "profile"),
+	}))
+	require.Equal(t, []string{"profile", "profile"}, []string{<-tenants, <-tenants})
+}
+
+func Test_Write_RetryQueueFull(t *testing.T) {
+	pushTotal := atomic.NewInt32(0)
+	_, handler := pushv1connect.NewPusherServiceHandler(PushFunc(
+		func(_ context.Context, req *connect.Request[pushv1.PushRequest]) (*connect.Response[pushv1.PushResponse], error) {
+			pushTotal.Inc()
+			return nil, connect.NewError(connect.CodeUnavailable, errors.New("unavailable"))
+		},
+	))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	f := newTestFanOut(t, &EndpointOptions{
+		URL:               server.URL,
+		MinBackoff:        time.Millisecond,
+		MaxBackoff:        time.Millisecond,
+		MaxBackoffRetries: 3,
+		RetryQueueSize:    1,
+	})
+	samples := []*pyroscope.RawSample{{RawProfile: []byte("pprofraw")}}
+
+	// While the queue is full, the request isn't retried.
+	require.True(t, f.retryQueues[0].tryEnter())
+	err := f.Append(t.Context(), labels.FromStrings("__name__", "test"), samples)
+	require.ErrorContains(t, err, "retry queue is full: unavailable: unavailable")
+	require.Equal(t, int32(1), pushTotal.Load())
+
+	f.retryQueues[0].leave()
+	pushTotal.Store(0)
+	err = f.Append(t.Context(), labels.FromStrings("__name__", "test"), samples)
+	require.ErrorContains(t, err, "unavailable: unavailable")
+	require.NotContains(t, err.Error(), "retry queue is full")
+	require.Equal(t, int32(3), pushTotal.Load())
+	require.True(t, f.retryQueues[0].tryEnter(), "the request must leave the queue")
+}
+
+func Test_Write_RateLimit(t *testing.T) {
+	_, handler := pushv1connect.NewPusherServiceHandler(PushFunc(
+		func(_ context.Context, req *connect.Request[pushv1.PushRequest]) (*connect.Response[pushv1.PushResponse], error) {
+			return &connect.Response[pushv1.PushResponse]{}, nil
+		},
+	))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	f := newTestFanOut(t, &EndpointOptions{
+		URL:            server.URL,
+		RateLimit:      10,
+		RateLimitBurst: 1,
+	})
+	samples := []*pyroscope.RawSample{{RawProfile: []byte("pprofraw")}}
+
+	start := time.Now()
+	for range 3 {
+		require.NoError(t, f.Append(t.Context(), labels.FromStrings("__name__", "test"), samples))
+	}
+	// The first request uses the burst, the next ones wait 100ms each.
+	require.GreaterOrEqual(t, time.Since(start), 150*time.Millisecond)
+}
@@ -0,0 +1,103 @@
+package http
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+const cacheFile = "cache.json"
+
+// CacheArguments configures the on-disk cache of the responses.
+type CacheArguments struct {
+	TTL time.Duration `alloy:"ttl,attr"`
+}
+
+// Validate implements syntax.Validator.
+func (args *CacheArguments) Validate() error {
+	if args.TTL <= 0 {
+		return fmt.Errorf("cache ttl must be greater than 0")
+	}
+	return nil
+}
+
+// cacheEntry is the content of the cache file.
+type cacheEntry struct {
+	Key       string    `json:"key"`
+	FetchedAt time.Time `json:"fetched_at"`
+	Pages     []string  `json:"pages"`
+}
+
+// cacheKey identifies the requests performed by the component, so that the
+// cached responses aren't used after they change.
+func (args *Arguments) cacheKey() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n%s\n", args.Method, args.URL, args.Body)
+	if args.Pagination != nil {
+		fmt.Fprintf(h, "%+v\n", *args.Pagination)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// readCache returns the pages of the cached responses, or nil if there aren't
+// any cached responses for key which are younger than the TTL. c.mut must be
+// held when calling.
+func (c *Component) readCache(key string) []string {
+	bb, err := os.ReadFile(filepath.Join(c.opts.DataPath, cacheFile))
+	if err != nil {
+		if !errors.Is(err, fs.ErrNotExist) {
+			level.Warn(c.log).Log("msg", "failed to read cached response", "err", err)
+		}
+		return nil
+	}
+
+	var entry cacheEntry
+	if err := json.Unmarshal(bb, &entry); err != nil {
+		level.Warn(c.log).Log("msg", "failed to decode cached response", "err", err)
+		return nil
+	}
+	if entry.Key != key || time.Since(entry.FetchedAt) >= c.args.Cache.TTL || len(entry.Pages) == 0 {
+		return nil
+	}
+
+	level.Debug(c.log).Log("msg", "using cached response", "fetched_at", entry.FetchedAt)
+	return entry.Pages
+}
+
+// writeCache writes the pages of the responses to the cache. Failing to
+// write the cache is logged but doesn't fail the poll. c.mut must be held
+// when calling.
+func (c *Component) writeCache(key string, pages []string) {
+	bb, err := json.Marshal(cacheEntry{
+		Key:       key,
+		FetchedAt: time.Now(),
+		Pages:     pages,
+	})
+	if err != nil {
+		level.Warn(c.log).Log("msg", "failed to encode response for the cache", "err", err)
+		return
+	}
+
+	if err := os.MkdirAll(c.opts.DataPath, 0o750); err != nil {
+		level.Warn(c.log).Log("msg", "failed to create cache directory", "err", err)
+		return
+	}
+	// Write to a temporary file first so that the cache is never partially
+	// written.
+	path := filepath.Join(c.opts.DataPath, cacheFile)
+	if err := os.WriteFile(path+".tmp", bb, 0o600); err != nil {
+		level.Warn(c.log).Log("msg", "failed to write cached response", "err", err)
+		return
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		level.Warn(c.log).Log("msg", "failed to write cached response", "err", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"reflect"
 	"strings"
 	"sync"
 	"time"
@@ -46,6 +47,10 @@ type Arguments struct {
 	Body    string            `alloy:"body,attr,optional"`
 
 	Client common_config.HTTPClientConfig `alloy:"client,block,optional"`
+
+	Format     string               `alloy:"format,attr,optional"`
+	Pagination *PaginationArguments `alloy:"pagination,block,optional"`
+	Cache      *CacheArguments      `alloy:"cache,block,optional"`
 }
 
 // DefaultArguments holds default settings for Arguments.
@@ -77,12 +82,28 @@ func (args *Arguments) Validate() error {
 		return err
 	}
 
+	switch args.Format {
+	case formatNone, formatJSON, formatYAML:
+	default:
+		return fmt.Errorf("format must be one of %q or %q", formatJSON, formatYAML)
+	}
+	if args.Format != formatNone && args.IsSecret {
+		return fmt.Errorf("format can't be set when is_secret is true, as the decoded content can't be marked as secret")
+	}
+
+	if args.Pagination != nil {
+		if err := args.Pagination.validate(args.Format); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // Exports holds settings exported by remote.http.
 type Exports struct {
 	Content alloytypes.OptionalSecret `alloy:"content,attr"`
+	Decoded any                       `alloy:"decoded,attr"`
 }
 
 // Component implements the remote.http component.
@@ -196,54 +217,157 @@ func (c *Component) pollError() error {
 	ctx, cancel := context.WithTimeout(context.Background(), c.args.PollTimeout)
 	defer cancel()
 
+	var (
+		pages []string
+		err   error
+	)
+	cacheKey := c.args.cacheKey()
+	if c.args.Cache != nil {
+		pages = c.readCache(cacheKey)
+	}
+	if pages == nil {
+		pages, err = c.fetchPages(ctx)
+		if err != nil {
+			return err
+		}
+		if c.args.Cache != nil {
+			c.writeCache(cacheKey, pages)
+		}
+	}
+
+	newExports, err := c.buildExports(pages)
+	if err != nil {
+		level.Error(c.log).Log("msg", "failed to decode response", "err", err)
+		return fmt.Errorf("decoding response: %w", err)
+	}
+
+	// Only send a state change event if the exports have changed from the
+	// previous poll.
+	if !reflect.DeepEqual(c.lastExports, newExports) {
+		c.opts.OnStateChange(newExports)
+	}
+	c.lastExports = newExports
+	return nil
+}
+
+// fetchPages performs the requests for the component's configured URL,
+// following the next pages of the response if pagination is configured. c.mut
+// must be held when calling.
+func (c *Component) fetchPages(ctx context.Context) ([]string, error) {
+	var (
+		pages   []string
+		pageURL = c.args.URL
+	)
+	for {
+		page, header, err := c.fetchPage(ctx, pageURL)
+		if err != nil {
+			return nil, err
+		}
+		pages = append(pages, page)
+
+		if c.args.Pagination == nil {
+			return pages, nil
+		}
+
+		next, err := c.args.Pagination.nextPage(c.args.URL, pageURL, page, header, c.args.Format)
+		if err != nil {
+			level.Error(c.log).Log("msg", "failed to find the next page", "err", err)
+			return nil, fmt.Errorf("finding next page: %w", err)
+		}
+		if next == "" {
+			return pages, nil
+		}
+		if len(pages) >= c.args.Pagination.MaxPages {
+			level.Warn(c.log).Log("msg", "stopped following pages after reaching max_pages", "max_pages", c.args.Pagination.MaxPages)
+			return pages, nil
+		}
+		pageURL = next
+	}
+}
+
+// fetchPage performs a single request for the given URL and returns the body
+// and the headers of the response.
+func (c *Component) fetchPage(ctx context.Context, pageURL string) (string, http.Header, error) {
 	var body io.Reader
 	if c.args.Body != "" {
 		body = strings.NewReader(c.args.Body)
 	}
 
-	req, err := http.NewRequest(c.args.Method, c.args.URL, body)
+	req, err := http.NewRequestWithContext(ctx, c.args.Method, pageURL, body)
 	if err != nil {
 		level.Error(c.log).Log("msg", "failed to build request", "err", err)
-		return fmt.Errorf("building request: %w", err)
+		return "", nil, fmt.Errorf("building request: %w", err)
 	}
 	for name, value := range c.args.Headers {
 		req.Header.Set(name, value)
 	}
-	req = req.WithContext(ctx)
 
 	resp, err := c.cli.Do(req)
 	if err != nil {
 		level.Error(c.log).Log("msg", "failed to perform request", "err", err)
-		return fmt.Errorf("performing request: %w", err)
+		return "", nil, fmt.Errorf("performing request: %w", err)
 	}
+	defer resp.Body.Close()
 
 	bb, err := io.ReadAll(resp.Body)
 	if err != nil {
 		level.Error(c.log).Log("msg", "failed to read response", "err", err)
-		return fmt.Errorf("reading response: %w", err)
+		return "", nil, fmt.Errorf("reading response: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		level.Error(c.log).Log("msg", "unexpected status code from response", "status", resp.Status)
-		return fmt.Errorf("unexpected status code %s", resp.Status)
+		return "", nil, fmt.Errorf("unexpected status code %s", resp.Status)
 	}
 
-	stringContent := strings.TrimSpace(string(bb))
+	return strings.TrimSpace(string(bb)), resp.Header, nil
+}
 
-	newExports := Exports{
+// buildExports builds the exports from the bodies of the fetched pages.
+func (c *Component) buildExports(pages []string) (Exports, error) {
+	exports := Exports{
 		Content: alloytypes.OptionalSecret{
 			IsSecret: c.args.IsSecret,
-			Value:    stringContent,
+			Value:    strings.Join(pages, "\n"),
 		},
 	}
+	if c.args.Format == formatNone {
+		return exports, nil
+	}
 
-	// Only send a state change event if the exports have changed from the
-	// previous poll.
-	if c.lastExports != newExports {
-		c.opts.OnStateChange(newExports)
+	decodedPages := make([]any, 0, len(pages))
+	for _, page := range pages {
+		decoded, err := decode(c.args.Format, page)
+		if err != nil {
+			return Exports{}, err
+		}
+		decodedPages = append(decodedPages, decoded)
 	}
-	c.lastExports = newExports
-	return nil
+
+	switch {
+	case c.args.Pagination == nil:
+		exports.Decoded = decodedPages[0]
+	case c.args.Pagination.ItemsField != "":
+		items := []any{}
+		for _, decoded := range decodedPages {
+			pageItems, err := lookupField(decoded, c.args.Pagination.ItemsField)
+			if err != nil {
+				return Exports{}, err
+			}
+			if pageItems == nil {
+				continue
+			}
+			list, ok := pageItems.([]any)
+			if !ok {
+				return Exports{}, fmt.Errorf("items_field %q isn't a list", c.args.Pagination.ItemsField)
+			}
+			items = append(items, list...)
+		}
+		exports.Decoded = items
+	default:
+		exports.Decoded = decodedPages
+	}
+	return exports, nil
 }
 
 // Update updates the remote.http component. After the update completes, a
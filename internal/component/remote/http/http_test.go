@@ -5,10 +5,13 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/grafana/alloy/internal/component"
 	http_component "github.com/grafana/alloy/internal/component/remote/http"
 	"github.com/grafana/alloy/internal/runtime/componenttest"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
@@ -121,6 +124,44 @@ func TestUnmarshalValidation(t *testing.T) {
 			`,
 			`poll_frequency must be greater than 0`,
 		},
+		{
+			"Invalid format",
+			`
+			url = "http://example.com"
+			format = "xml"
+			`,
+			`format must be one of "json" or "yaml"`,
+		},
+		{
+			"Format with is_secret",
+			`
+			url = "http://example.com"
+			format = "json"
+			is_secret = true
+			`,
+			`format can't be set when is_secret is true, as the decoded content can't be marked as secret`,
+		},
+		{
+			"Cursor pagination without format",
+			`
+			url = "http://example.com"
+			pagination {
+				mode = "cursor"
+				cursor_field = "next"
+			}
+			`,
+			`format must be set when pagination mode is "cursor"`,
+		},
+		{
+			"Invalid cache ttl",
+			`
+			url = "http://example.com"
+			cache {
+				ttl = "0s"
+			}
+			`,
+			`cache ttl must be greater than 0`,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.testname, func(t *testing.T) {
@@ -176,3 +217,161 @@ func (lh *lazyHandler) SetHandler(h http.HandlerFunc) {
 
 	lh.inner = h
 }
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		format   string
+		response string
+		expected any
+	}{
+		{
+			format:   "json",
+			response: `{"targets": [{"__address__": "localhost:9090"}], "enabled": true}`,
+			expected: map[string]any{
+				"targets": []any{map[string]any{"__address__": "localhost:9090"}},
+				"enabled": true,
+			},
+		},
+		{
+			format:   "yaml",
+			response: "targets:\n  - __address__: localhost:9090\nenabled: true\n",
+			expected: map[string]any{
+				"targets": []any{map[string]any{"__address__": "localhost:9090"}},
+				"enabled": true,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintln(w, tt.response)
+			}))
+			defer srv.Close()
+
+			exports := newComponent(t, t.TempDir(), fmt.Sprintf(`
+				url    = "%s"
+				format = "%s"
+			`, srv.URL, tt.format))
+			require.Equal(t, strings.TrimSpace(tt.response), exports().Content.Value)
+			require.Equal(t, tt.expected, exports().Decoded)
+		})
+	}
+}
+
+func TestPagination(t *testing.T) {
+	t.Run("link header", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Query().Get("page") {
+			case "":
+				w.Header().Set("Link", `</items?page=2>; rel="next", </items?page=3>; rel="last"`)
+				fmt.Fprint(w, `{"items": [1, 2]}`)
+			case "2":
+				w.Header().Set("Link", `</items?page=3>; rel="next"`)
+				fmt.Fprint(w, `{"items": [3]}`)
+			case "3":
+				fmt.Fprint(w, `{"items": []}`)
+			}
+		}))
+		defer srv.Close()
+
+		exports := newComponent(t, t.TempDir(), fmt.Sprintf(`
+			url    = "%s/items"
+			format = "json"
+			pagination {
+				items_field = "items"
+			}
+		`, srv.URL))
+		require.Equal(t, "{\"items\": [1, 2]}\n{\"items\": [3]}\n{\"items\": []}", exports().Content.Value)
+		require.Equal(t, []any{1.0, 2.0, 3.0}, exports().Decoded)
+	})
+
+	t.Run("cursor", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "1", r.URL.Query().Get("limit"))
+			switch r.URL.Query().Get("after") {
+			case "":
+				fmt.Fprint(w, `{"data": {"name": "a"}, "meta": {"next": "b"}}`)
+			case "b":
+				fmt.Fprint(w, `{"data": {"name": "b"}, "meta": {"next": "c"}}`)
+			default:
+				t.Errorf("unexpected cursor %q", r.URL.Query().Get("after"))
+			}
+		}))
+		defer srv.Close()
+
+		exports := newComponent(t, t.TempDir(), fmt.Sprintf(`
+			url    = "%s/items?limit=1"
+			format = "json"
+			pagination {
+				mode         = "cursor"
+				cursor_field = "meta.next"
+				cursor_param = "after"
+				max_pages    = 2
+			}
+		`, srv.URL))
+		require.Equal(t, []any{
+			map[string]any{"data": map[string]any{"name": "a"}, "meta": map[string]any{"next": "b"}},
+			map[string]any{"data": map[string]any{"name": "b"}, "meta": map[string]any{"next": "c"}},
+		}, exports().Decoded)
+	})
+}
+
+func TestCache(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "response %d", requests.Add(1))
+	}))
+	defer srv.Close()
+
+	dataPath := t.TempDir()
+	cfg := fmt.Sprintf(`
+		url = "%s"
+		cache {
+			ttl = "1h"
+		}
+	`, srv.URL)
+
+	exports := newComponent(t, dataPath, cfg)
+	require.Equal(t, "response 1", exports().Content.Value)
+
+	// A restarted component uses the cached response.
+	exports = newComponent(t, dataPath, cfg)
+	require.Equal(t, "response 1", exports().Content.Value)
+	require.Equal(t, int32(1), requests.Load())
+
+	// The cached response isn't used once the requests change.
+	exports = newComponent(t, dataPath, cfg+`method = "POST"`)
+	require.Equal(t, "response 2", exports().Content.Value)
+}
+
+// newComponent builds a remote.http component from cfg, which polls the
+// endpoint once, and returns a function to get its latest exports.
+func newComponent(t *testing.T, dataPath string, cfg string) func() http_component.Exports {
+	t.Helper()
+
+	var args http_component.Arguments
+	require.NoError(t, syntax.Unmarshal([]byte(cfg), &args))
+
+	var (
+		mut     sync.Mutex
+		exports http_component.Exports
+	)
+	_, err := http_component.New(component.Options{
+		ID:       "remote.http.test",
+		Logger:   util.TestAlloyLogger(t),
+		DataPath: dataPath,
+		OnStateChange: func(e component.Exports) {
+			mut.Lock()
+			defer mut.Unlock()
+			exports = e.(http_component.Exports)
+		},
+	}, args)
+	require.NoError(t, err)
+
+	return func() http_component.Exports {
+		mut.Lock()
+		defer mut.Unlock()
+		return exports
+	}
+}
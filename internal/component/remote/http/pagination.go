@@ -0,0 +1,195 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Formats which the content of the responses can be decoded from.
+const (
+	formatNone = ""
+	formatJSON = "json"
+	formatYAML = "yaml"
+)
+
+// Modes of following the next pages of a response.
+const (
+	paginationLinkHeader = "link_header"
+	paginationCursor     = "cursor"
+)
+
+// PaginationArguments configures how the next pages of a response are
+// followed.
+type PaginationArguments struct {
+	Mode        string `alloy:"mode,attr,optional"`
+	CursorField string `alloy:"cursor_field,attr,optional"`
+	CursorParam string `alloy:"cursor_param,attr,optional"`
+	ItemsField  string `alloy:"items_field,attr,optional"`
+	MaxPages    int    `alloy:"max_pages,attr,optional"`
+}
+
+// DefaultPaginationArguments holds default settings for PaginationArguments.
+var DefaultPaginationArguments = PaginationArguments{
+	Mode:        paginationLinkHeader,
+	CursorParam: "cursor",
+	MaxPages:    10,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *PaginationArguments) SetToDefault() {
+	*args = DefaultPaginationArguments
+}
+
+func (args *PaginationArguments) validate(format string) error {
+	switch args.Mode {
+	case paginationLinkHeader:
+	case paginationCursor:
+		if args.CursorField == "" {
+			return fmt.Errorf("pagination cursor_field must be set when mode is %q", paginationCursor)
+		}
+		if args.CursorParam == "" {
+			return fmt.Errorf("pagination cursor_param must be set when mode is %q", paginationCursor)
+		}
+		if format == formatNone {
+			return fmt.Errorf("format must be set when pagination mode is %q", paginationCursor)
+		}
+	default:
+		return fmt.Errorf("pagination mode must be one of %q or %q", paginationLinkHeader, paginationCursor)
+	}
+
+	if args.ItemsField != "" && format == formatNone {
+		return fmt.Errorf("format must be set when pagination items_field is set")
+	}
+	if args.MaxPages <= 0 {
+		return fmt.Errorf("pagination max_pages must be greater than 0")
+	}
+	return nil
+}
+
+// nextPage returns the URL of the page following the page fetched from
+// pageURL, or an empty string if it's the last page.
+func (args *PaginationArguments) nextPage(baseURL, pageURL string, page string, header http.Header, format string) (string, error) {
+	switch args.Mode {
+	case paginationLinkHeader:
+		next := nextLink(header)
+		if next == "" {
+			return "", nil
+		}
+		// The link may be relative to the page it was returned from.
+		base, err := url.Parse(pageURL)
+		if err != nil {
+			return "", err
+		}
+		ref, err := url.Parse(next)
+		if err != nil {
+			return "", fmt.Errorf("parsing link header: %w", err)
+		}
+		return base.ResolveReference(ref).String(), nil
+
+	case paginationCursor:
+		decoded, err := decode(format, page)
+		if err != nil {
+			return "", err
+		}
+		cursor, err := lookupField(decoded, args.CursorField)
+		if err != nil {
+			return "", err
+		}
+		cursorValue := formatCursor(cursor)
+		if cursorValue == "" {
+			return "", nil
+		}
+
+		u, err := url.Parse(baseURL)
+		if err != nil {
+			return "", err
+		}
+		query := u.Query()
+		query.Set(args.CursorParam, cursorValue)
+		u.RawQuery = query.Encode()
+		return u.String(), nil
+	}
+	return "", fmt.Errorf("unknown pagination mode %q", args.Mode)
+}
+
+// nextLink returns the target of the link with the "next" relation in the
+// Link headers, as defined in RFC 8288.
+func nextLink(header http.Header) string {
+	for _, value := range header.Values("Link") {
+		for _, link := range strings.Split(value, ",") {
+			parts := strings.Split(link, ";")
+			target := strings.TrimSpace(parts[0])
+			if !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+			for _, param := range parts[1:] {
+				name, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+				if !ok || !strings.EqualFold(strings.TrimSpace(name), "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(strings.TrimSpace(value), `"`)) {
+					if strings.EqualFold(rel, "next") {
+						return target[1 : len(target)-1]
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// formatCursor returns the cursor as a query parameter value. It returns an
+// empty string if there isn't any cursor.
+func formatCursor(cursor any) string {
+	switch cursor := cursor.(type) {
+	case nil:
+		return ""
+	case string:
+		return cursor
+	case float64:
+		return strconv.FormatFloat(cursor, 'f', -1, 64)
+	default:
+		return fmt.Sprint(cursor)
+	}
+}
+
+// decode decodes the content of a response in the given format.
+func decode(format string, content string) (any, error) {
+	var res any
+	switch format {
+	case formatJSON:
+		if err := json.Unmarshal([]byte(content), &res); err != nil {
+			return nil, err
+		}
+	case formatYAML:
+		if err := yaml.Unmarshal([]byte(content), &res); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown format %q", format)
+	}
+	return res, nil
+}
+
+// lookupField returns the value of the dot-separated field of a decoded
+// response. It returns nil if the field doesn't exist.
+func lookupField(decoded any, field string) (any, error) {
+	value := decoded
+	for _, key := range strings.Split(field, ".") {
+		if value == nil {
+			return nil, nil
+		}
+		object, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("field %q: %q isn't an object", field, key)
+		}
+		value = object[key]
+	}
+	return value, nil
+}
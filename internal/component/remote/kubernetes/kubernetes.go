@@ -14,8 +14,12 @@ import (
 	"github.com/grafana/alloy/internal/component/common/kubernetes"
 	"github.com/grafana/alloy/syntax/alloytypes"
 
+	corev1 "k8s.io/api/core/v1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/informers"
 	client_go "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
 )
 
 type ResourceType string
@@ -28,10 +32,13 @@ const (
 // Arguments control the component.
 type Arguments struct {
 	Namespace     string        `alloy:"namespace,attr"`
-	Name          string        `alloy:"name,attr"`
+	Name          string        `alloy:"name,attr,optional"`
 	PollFrequency time.Duration `alloy:"poll_frequency,attr,optional"`
 	PollTimeout   time.Duration `alloy:"poll_timeout,attr,optional"`
 
+	// LabelSelector selects the watched objects, instead of Name.
+	LabelSelector *kubernetes.LabelSelector `alloy:"label_selector,block,optional"`
+
 	// Client settings to connect to Kubernetes.
 	Client kubernetes.ClientArguments `alloy:"client,block,optional"`
 }
@@ -55,12 +62,27 @@ func (args *Arguments) Validate() error {
 	if args.PollTimeout <= 0 {
 		return fmt.Errorf("poll_timeout must not be greater than 0")
 	}
+	if args.Name == "" && args.LabelSelector == nil {
+		return fmt.Errorf("one of name or label_selector must be set")
+	}
+	if args.Name != "" && args.LabelSelector != nil {
+		return fmt.Errorf("name and label_selector can't both be set")
+	}
+	if args.LabelSelector != nil {
+		if _, err := kubernetes.ConvertSelectorToListOptions(*args.LabelSelector); err != nil {
+			return fmt.Errorf("invalid label_selector: %w", err)
+		}
+	}
 	return nil
 }
 
 // Exports holds settings exported by this component.
 type Exports struct {
 	Data map[string]alloytypes.OptionalSecret `alloy:"data,attr"`
+
+	// Objects holds the data of the objects selected by the label selector,
+	// keyed by object name.
+	Objects map[string]map[string]alloytypes.OptionalSecret `alloy:"objects,attr"`
 }
 
 // Component implements the remote.kubernetes.* component.
@@ -71,8 +93,10 @@ type Component struct {
 	mut  sync.Mutex
 	args Arguments
 
-	client *client_go.Clientset
-	kind   ResourceType
+	client   client_go.Interface
+	kind     ResourceType
+	selector labels.Selector // Set when watching the objects matching a label selector.
+	updated  chan struct{}
 
 	lastPoll    time.Time
 	lastExports Exports // Used for determining whether exports should be updated
@@ -92,7 +116,8 @@ func New(opts component.Options, args Arguments, rType ResourceType) (*Component
 		log:  opts.Logger,
 		opts: opts,
 
-		kind: rType,
+		kind:    rType,
+		updated: make(chan struct{}, 1),
 		health: component.Health{
 			Health:     component.HealthTypeUnknown,
 			Message:    "component started",
@@ -103,21 +128,149 @@ func New(opts component.Options, args Arguments, rType ResourceType) (*Component
 	if err := c.Update(args); err != nil {
 		return nil, err
 	}
+	// Run hasn't started yet, so there's nothing to restart.
+	select {
+	case <-c.updated:
+	default:
+	}
 	return c, nil
 }
 
 // Run starts the remote.kubernetes.* component.
 func (c *Component) Run(ctx context.Context) error {
 	for {
+		c.mut.Lock()
+		client, namespace, selector := c.client, c.args.Namespace, c.selector
+		c.mut.Unlock()
+
+		runCtx, cancel := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			if selector != nil {
+				c.watch(runCtx, client, namespace, selector)
+			} else {
+				c.pollLoop(runCtx)
+			}
+		}()
+
 		select {
 		case <-ctx.Done():
+			cancel()
+			<-done
 			return nil
+		case <-c.updated:
+			cancel()
+			<-done
+		}
+	}
+}
+
+// pollLoop polls the object at the poll frequency until ctx is canceled.
+func (c *Component) pollLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
 		case <-time.After(c.nextPoll()):
 			c.poll()
 		}
 	}
 }
 
+// watch starts an informer for the objects matching selector, and updates
+// the exports whenever they change, until ctx is canceled.
+func (c *Component) watch(ctx context.Context, client client_go.Interface, namespace string, selector labels.Selector) {
+	factory := informers.NewSharedInformerFactoryWithOptions(
+		client,
+		0,
+		informers.WithNamespace(namespace),
+		informers.WithTweakListOptions(func(lo *v1.ListOptions) {
+			lo.LabelSelector = selector.String()
+		}),
+	)
+
+	var (
+		informer cache.SharedIndexInformer
+		list     func() (map[string]map[string]alloytypes.OptionalSecret, error)
+	)
+	switch c.kind {
+	case TypeSecret:
+		secrets := factory.Core().V1().Secrets()
+		informer = secrets.Informer()
+		list = func() (map[string]map[string]alloytypes.OptionalSecret, error) {
+			items, err := secrets.Lister().Secrets(namespace).List(labels.Everything())
+			if err != nil {
+				return nil, err
+			}
+			objects := make(map[string]map[string]alloytypes.OptionalSecret, len(items))
+			for _, secret := range items {
+				objects[secret.Name] = secretData(secret)
+			}
+			return objects, nil
+		}
+	case TypeConfigMap:
+		configMaps := factory.Core().V1().ConfigMaps()
+		informer = configMaps.Informer()
+		list = func() (map[string]map[string]alloytypes.OptionalSecret, error) {
+			items, err := configMaps.Lister().ConfigMaps(namespace).List(labels.Everything())
+			if err != nil {
+				return nil, err
+			}
+			objects := make(map[string]map[string]alloytypes.OptionalSecret, len(items))
+			for _, cmap := range items {
+				objects[cmap.Name] = configMapData(cmap)
+			}
+			return objects, nil
+		}
+	}
+
+	changes := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case changes <- struct{}{}:
+		default:
+		}
+	}
+	_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc:    func(interface{}) { notify() },
+		UpdateFunc: func(interface{}, interface{}) { notify() },
+		DeleteFunc: func(interface{}) { notify() },
+	})
+	if err != nil {
+		c.updatePollHealth(err)
+		return
+	}
+
+	factory.Start(ctx.Done())
+	defer factory.Shutdown()
+
+	// Exporting from a cache that isn't synced would drop the objects which
+	// weren't listed yet.
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return
+	}
+
+	for {
+		objects, err := list()
+		if err == nil {
+			c.mut.Lock()
+			c.export(Exports{
+				Data:    map[string]alloytypes.OptionalSecret{},
+				Objects: objects,
+			})
+			c.mut.Unlock()
+		}
+		c.updatePollHealth(err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-changes:
+		}
+	}
+}
+
 // nextPoll returns how long to wait to poll given the last time a
 // poll occurred. nextPoll returns 0 if a poll should occur immediately.
 func (c *Component) nextPoll() time.Duration {
@@ -171,44 +324,98 @@ func (c *Component) pollError() error {
 	ctx, cancel := context.WithTimeout(context.Background(), c.args.PollTimeout)
 	defer cancel()
 
-	data := map[string]alloytypes.OptionalSecret{}
+	if c.selector != nil {
+		objects, err := c.listObjects(ctx)
+		if err != nil {
+			return err
+		}
+		c.export(Exports{
+			Data:    map[string]alloytypes.OptionalSecret{},
+			Objects: objects,
+		})
+		return nil
+	}
+
+	var data map[string]alloytypes.OptionalSecret
 	switch c.kind {
 	case TypeSecret:
 		secret, err := c.client.CoreV1().Secrets(c.args.Namespace).Get(ctx, c.args.Name, v1.GetOptions{})
 		if err != nil {
 			return err
 		}
-		for k, v := range secret.Data {
-			data[k] = alloytypes.OptionalSecret{
-				Value:    string(v),
-				IsSecret: true,
-			}
-		}
+		data = secretData(secret)
 	case TypeConfigMap:
 		cmap, err := c.client.CoreV1().ConfigMaps(c.args.Namespace).Get(ctx, c.args.Name, v1.GetOptions{})
 		if err != nil {
 			return err
 		}
-		for k, v := range cmap.Data {
-			data[k] = alloytypes.OptionalSecret{
-				Value:    v,
-				IsSecret: false,
-			}
-		}
+		data = configMapData(cmap)
 	}
 
-	newExports := Exports{
-		Data: data,
+	c.export(Exports{
+		Data:    data,
+		Objects: map[string]map[string]alloytypes.OptionalSecret{},
+	})
+	return nil
+}
+
+// listObjects returns the data of the objects matching the label selector,
+// keyed by object name. c.mut must be held when calling.
+func (c *Component) listObjects(ctx context.Context) (map[string]map[string]alloytypes.OptionalSecret, error) {
+	opts := v1.ListOptions{LabelSelector: c.selector.String()}
+	objects := map[string]map[string]alloytypes.OptionalSecret{}
+	switch c.kind {
+	case TypeSecret:
+		secrets, err := c.client.CoreV1().Secrets(c.args.Namespace).List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for i := range secrets.Items {
+			objects[secrets.Items[i].Name] = secretData(&secrets.Items[i])
+		}
+	case TypeConfigMap:
+		cmaps, err := c.client.CoreV1().ConfigMaps(c.args.Namespace).List(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for i := range cmaps.Items {
+			objects[cmaps.Items[i].Name] = configMapData(&cmaps.Items[i])
+		}
 	}
+	return objects, nil
+}
 
+// export sends newExports if they changed since the last exports. c.mut must
+// be held when calling.
+func (c *Component) export(newExports Exports) {
 	// Only send a state change event if the exports have changed from the
 	// previous poll.
-	if !reflect.DeepEqual(newExports.Data, c.lastExports.Data) {
+	if !reflect.DeepEqual(newExports, c.lastExports) {
 		c.opts.OnStateChange(newExports)
 	}
-
 	c.lastExports = newExports
-	return nil
+}
+
+func secretData(secret *corev1.Secret) map[string]alloytypes.OptionalSecret {
+	data := make(map[string]alloytypes.OptionalSecret, len(secret.Data))
+	for k, v := range secret.Data {
+		data[k] = alloytypes.OptionalSecret{
+			Value:    string(v),
+			IsSecret: true,
+		}
+	}
+	return data
+}
+
+func configMapData(cmap *corev1.ConfigMap) map[string]alloytypes.OptionalSecret {
+	data := make(map[string]alloytypes.OptionalSecret, len(cmap.Data))
+	for k, v := range cmap.Data {
+		data[k] = alloytypes.OptionalSecret{
+			Value:    v,
+			IsSecret: false,
+		}
+	}
+	return data
 }
 
 // Update updates the remote.kubernetes.* component. After the update completes, a
@@ -241,7 +448,20 @@ func (c *Component) Update(args component.Arguments) (err error) {
 		return fmt.Errorf("creating kubernetes client: %w", err)
 	}
 
-	return err
+	c.selector = nil
+	if c.args.LabelSelector != nil {
+		c.selector, err = kubernetes.ConvertSelectorToListOptions(*c.args.LabelSelector)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Restart the watch or the poll loop with the new arguments.
+	select {
+	case c.updated <- struct{}{}:
+	default:
+	}
+	return nil
 }
 
 // CurrentHealth returns the current health of the component.
@@ -1,12 +1,23 @@
 package kubernetes
 
 import (
+	"context"
+	"reflect"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/kubernetes"
 	"github.com/grafana/alloy/syntax"
+	"github.com/grafana/alloy/syntax/alloytypes"
 	"github.com/stretchr/testify/require"
 	"gotest.tools/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
 )
 
 func TestAlloyUnmarshal(t *testing.T) {
@@ -41,3 +52,138 @@ func TestValidate(t *testing.T) {
 		require.ErrorContains(t, err, "poll_timeout must not be greater than 0")
 	})
 }
+
+func TestValidateNameOrLabelSelector(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         string
+		expectedErr string
+	}{
+		{
+			name: "name",
+			cfg: `
+				namespace = "bar"
+				name = "foo"`,
+		},
+		{
+			name: "label selector",
+			cfg: `
+				namespace = "bar"
+				label_selector {
+					match_labels = {"app" = "foo"}
+				}`,
+		},
+		{
+			name:        "neither",
+			cfg:         `namespace = "bar"`,
+			expectedErr: "one of name or label_selector must be set",
+		},
+		{
+			name: "both",
+			cfg: `
+				namespace = "bar"
+				name = "foo"
+				label_selector {
+					match_labels = {"app" = "foo"}
+				}`,
+			expectedErr: "name and label_selector can't both be set",
+		},
+		{
+			name: "invalid label selector",
+			cfg: `
+				namespace = "bar"
+				label_selector {
+					match_expression {
+						key = "app"
+						operator = "Unknown"
+					}
+				}`,
+			expectedErr: "invalid label_selector",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tt.cfg), &args)
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
+
+func TestWatchLabelSelector(t *testing.T) {
+	newConfigMap := func(name, app, value string) *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "bar",
+				Labels:    map[string]string{"app": app},
+			},
+			Data: map[string]string{"key": value},
+		}
+	}
+	client := fake.NewClientset(
+		newConfigMap("first", "foo", "1"),
+		newConfigMap("other", "baz", "2"),
+	)
+
+	var (
+		mut     sync.Mutex
+		exports Exports
+	)
+	latestExports := func() Exports {
+		mut.Lock()
+		defer mut.Unlock()
+		return exports
+	}
+
+	args := DefaultArguments
+	args.Namespace = "bar"
+	args.LabelSelector = &kubernetes.LabelSelector{MatchLabels: map[string]string{"app": "foo"}}
+	c := &Component{
+		log: log.NewNopLogger(),
+		opts: component.Options{
+			OnStateChange: func(e component.Exports) {
+				mut.Lock()
+				defer mut.Unlock()
+				exports = e.(Exports)
+			},
+		},
+		args:     args,
+		client:   client,
+		kind:     TypeConfigMap,
+		selector: labels.SelectorFromSet(labels.Set{"app": "foo"}),
+		updated:  make(chan struct{}, 1),
+	}
+
+	// The initial poll lists the matching objects.
+	require.NoError(t, c.pollError())
+	require.Equal(t, map[string]map[string]alloytypes.OptionalSecret{
+		"first": {"key": {Value: "1"}},
+	}, latestExports().Objects)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	go func() { _ = c.Run(ctx) }()
+
+	// Changes of the matching objects are exported without polling.
+	_, err := client.CoreV1().ConfigMaps("bar").Create(ctx, newConfigMap("second", "foo", "3"), metav1.CreateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		return reflect.DeepEqual(map[string]map[string]alloytypes.OptionalSecret{
+			"first":  {"key": {Value: "1"}},
+			"second": {"key": {Value: "3"}},
+		}, latestExports().Objects)
+	}, 5*time.Second, 10*time.Millisecond)
+
+	require.NoError(t, client.CoreV1().ConfigMaps("bar").Delete(ctx, "first", metav1.DeleteOptions{}))
+	require.Eventually(t, func() bool {
+		return reflect.DeepEqual(map[string]map[string]alloytypes.OptionalSecret{
+			"second": {"key": {Value: "3"}},
+		}, latestExports().Objects)
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Empty(t, latestExports().Data)
+}
@@ -11,7 +11,9 @@ import (
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	aws_config "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/syntax/alloytypes"
@@ -76,7 +78,7 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		}),
 	}
 
-	w := newWatcher(bucket, file, s.updateChan, args.PollFrequency, s3Client)
+	w := newWatcher(bucket, file, s.updateChan, args.PollFrequency, s3Client, args.ServerSideEncryption)
 	s.watcher = w
 
 	err = o.Registerer.Register(s.s3Errors)
@@ -88,8 +90,8 @@ func New(o component.Options, args Arguments) (*Component, error) {
 		return nil, err
 	}
 
-	content, err := w.downloadSynchronously()
-	s.handleContentPolling(content, err)
+	content, object, err := w.downloadSynchronously()
+	s.handleContentPolling(result{result: []byte(content), object: object, err: err})
 	return s, nil
 }
 
@@ -119,7 +121,7 @@ func (s *Component) Update(args component.Arguments) error {
 	s.mut.Lock()
 	defer s.mut.Unlock()
 	s.args = newArgs
-	s.watcher.updateValues(bucket, file, newArgs.PollFrequency, s3Client)
+	s.watcher.updateValues(bucket, file, newArgs.PollFrequency, s3Client, newArgs.ServerSideEncryption)
 
 	return nil
 }
@@ -186,6 +188,19 @@ func generateS3Config(args Arguments) (*aws.Config, error) {
 		cfg.Region = args.Options.Region
 	}
 
+	// Assume the roles in order, each with the credentials of the previous
+	// one.
+	for _, role := range args.Options.AssumeRoles {
+		provider := stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), role.RoleARN, func(o *stscreds.AssumeRoleOptions) {
+			o.RoleSessionName = role.SessionName
+			o.Duration = role.Duration
+			if role.ExternalID != "" {
+				o.ExternalID = aws.String(role.ExternalID)
+			}
+		})
+		cfg.Credentials = aws.NewCredentialsCache(provider)
+	}
+
 	return &cfg, nil
 }
 
@@ -195,32 +210,44 @@ func (s *Component) handleContentUpdate(ctx context.Context) {
 		select {
 		case r := <-s.updateChan:
 			// r.result will never be nil,
-			s.handleContentPolling(string(r.result), r.err)
+			s.handleContentPolling(r)
 		case <-ctx.Done():
 			return
 		}
 	}
 }
 
-func (s *Component) handleContentPolling(newContent string, err error) {
+func (s *Component) handleContentPolling(r result) {
 	s.mut.Lock()
 	defer s.mut.Unlock()
 
-	if err == nil {
+	switch {
+	case r.err != nil:
+		s.s3Errors.Inc()
+		s.health.Health = component.HealthTypeUnhealthy
+		s.health.Message = r.err.Error()
+	case r.notModified:
+		// The exports are still up to date.
+		s.lastAccessed.SetToCurrentTime()
+		s.health.Health = component.HealthTypeHealthy
+		s.health.Message = "s3 file not modified"
+	default:
+		newContent := string(r.result)
 		s.opts.OnStateChange(Exports{
 			Content: alloytypes.OptionalSecret{
 				IsSecret: s.args.IsSecret,
 				Value:    newContent,
 			},
+			ETag:         r.object.etag,
+			VersionID:    r.object.versionID,
+			LastModified: r.object.lastModified,
+			ContentType:  r.object.contentType,
+			Metadata:     r.object.metadata,
 		})
 		s.lastAccessed.SetToCurrentTime()
 		s.content = newContent
 		s.health.Health = component.HealthTypeHealthy
 		s.health.Message = "s3 file updated"
-	} else {
-		s.s3Errors.Inc()
-		s.health.Health = component.HealthTypeUnhealthy
-		s.health.Message = err.Error()
 	}
 	s.health.UpdateTime = time.Now()
 }
@@ -3,12 +3,23 @@
 package s3
 
 import (
+	"crypto/md5"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/syntax"
+	"github.com/grafana/alloy/syntax/alloytypes"
 	"github.com/stretchr/testify/require"
 )
 
@@ -41,3 +52,298 @@ func TestPathBucketAndFileParsing(t *testing.T) {
 	require.Equal(t, "bucket", bucket)
 	require.Equal(t, "parent/file", file)
 }
+
+// fakeS3 serves a single object and the STS AssumeRole API, recording the
+// access keys which signed the requests.
+type fakeS3 struct {
+	mut          sync.Mutex
+	content      string
+	etag         string
+	headers      http.Header
+	accessKeys   []string
+	notModified  int
+	lastRequest  http.Header
+	assumedRoles []string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	// The Authorization header looks like "AWS4-HMAC-SHA256 Credential=<access key>/...".
+	auth := r.Header.Get("Authorization")
+	accessKey, _, _ := strings.Cut(strings.TrimPrefix(auth[strings.Index(auth, "Credential="):], "Credential="), "/")
+	f.accessKeys = append(f.accessKeys, accessKey)
+	f.lastRequest = r.Header.Clone()
+
+	if r.Method == http.MethodPost {
+		_ = r.ParseForm()
+		role := r.Form.Get("RoleArn")
+		f.assumedRoles = append(f.assumedRoles, role)
+		role = role[strings.LastIndex(role, "/")+1:]
+		fmt.Fprintf(w, `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>key-%[1]s</AccessKeyId>
+      <SecretAccessKey>secret</SecretAccessKey>
+      <SessionToken>token</SessionToken>
+      <Expiration>2100-01-01T00:00:00Z</Expiration>
+    </Credentials>
+    <AssumedRoleUser>
+      <Arn>arn:aws:sts::123456789012:assumed-role/%[1]s/alloy</Arn>
+      <AssumedRoleId>id</AssumedRoleId>
+    </AssumedRoleUser>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`, role)
+		return
+	}
+
+	if r.URL.Path != "/bucket/file" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if r.Header.Get("If-None-Match") == f.etag {
+		f.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	for k, v := range f.headers {
+		w.Header()[k] = v
+	}
+	w.Header().Set("ETag", f.etag)
+	w.Header().Set("Content-Type", "text/plain")
+	w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+	w.Header().Set("X-Amz-Meta-Owner", "team-a")
+	_, _ = io.WriteString(w, f.content)
+}
+
+func newTestWatcher(t *testing.T, f *fakeS3, args Arguments) *watcher {
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+
+	args.Path = "s3://bucket/file"
+	args.Options.Endpoint = srv.URL
+	args.Options.AccessKey = "key"
+	args.Options.Secret = "secret"
+	args.Options.Region = "us-east-1"
+	args.Options.UsePathStyle = true
+
+	cfg, err := generateS3Config(args)
+	require.NoError(t, err)
+	client := s3.NewFromConfig(*cfg, func(o *s3.Options) {
+		o.UsePathStyle = true
+		// The fake server doesn't return checksums.
+		o.DisableLogOutputChecksumValidationSkipped = true
+	})
+	bucket, file := getPathBucketAndFile(args.Path)
+	return newWatcher(bucket, file, make(chan result), time.Minute, client, args.ServerSideEncryption)
+}
+
+func TestConditionalGet(t *testing.T) {
+	f := &fakeS3{content: "first", etag: `"1"`}
+	w := newTestWatcher(t, f, DefaultArguments)
+
+	content, object, err := w.downloadSynchronously()
+	require.NoError(t, err)
+	require.Equal(t, "first", content)
+	require.Equal(t, objectInfo{
+		etag:         `"1"`,
+		lastModified: "2006-01-02T15:04:05Z",
+		contentType:  "text/plain",
+		metadata:     map[string]string{"owner": "team-a"},
+	}, object)
+
+	// The object isn't downloaded again until it changes.
+	_, _, err = w.downloadSynchronously()
+	require.ErrorIs(t, err, errNotModified)
+	require.Equal(t, 1, f.notModified)
+
+	f.mut.Lock()
+	f.content, f.etag = "second", `"2"`
+	f.mut.Unlock()
+	content, object, err = w.downloadSynchronously()
+	require.NoError(t, err)
+	require.Equal(t, "second", content)
+	require.Equal(t, `"2"`, object.etag)
+
+	// Updating the watcher downloads the object again.
+	w.updateValues(w.bucket, w.file, time.Minute, w.downloader, nil)
+	content, _, err = w.downloadSynchronously()
+	require.NoError(t, err)
+	require.Equal(t, "second", content)
+	require.Equal(t, 1, f.notModified)
+}
+
+func TestServerSideEncryptionKMS(t *testing.T) {
+	tests := []struct {
+		name        string
+		headers     map[string]string
+		keyID       string
+		expectedErr string
+	}{
+		{
+			name:        "not encrypted",
+			expectedErr: "object isn't encrypted with SSE-KMS",
+		},
+		{
+			name:    "any key",
+			headers: map[string]string{"X-Amz-Server-Side-Encryption": "aws:kms"},
+		},
+		{
+			name: "matching key ID",
+			headers: map[string]string{
+				"X-Amz-Server-Side-Encryption":                "aws:kms",
+				"X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id": "arn:aws:kms:us-east-1:123456789012:key/1234",
+			},
+			keyID: "1234",
+		},
+		{
+			name: "other key",
+			headers: map[string]string{
+				"X-Amz-Server-Side-Encryption":                "aws:kms",
+				"X-Amz-Server-Side-Encryption-Aws-Kms-Key-Id": "arn:aws:kms:us-east-1:123456789012:key/5678",
+			},
+			keyID:       "1234",
+			expectedErr: `object is encrypted with KMS key "arn:aws:kms:us-east-1:123456789012:key/5678" instead of "1234"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &fakeS3{content: "content", etag: `"1"`, headers: http.Header{}}
+			for k, v := range tt.headers {
+				f.headers.Set(k, v)
+			}
+			args := DefaultArguments
+			args.ServerSideEncryption = &ServerSideEncryption{Type: SSEKMS, KMSKeyID: tt.keyID}
+			w := newTestWatcher(t, f, args)
+
+			content, _, err := w.downloadSynchronously()
+			if tt.expectedErr != "" {
+				require.EqualError(t, err, tt.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, "content", content)
+		})
+	}
+}
+
+func TestServerSideEncryptionCustomerKey(t *testing.T) {
+	f := &fakeS3{content: "content", etag: `"1"`}
+	key := strings.Repeat("k", 32)
+	args := DefaultArguments
+	args.ServerSideEncryption = &ServerSideEncryption{Type: SSEC, CustomerKey: alloytypes.Secret(key)}
+	w := newTestWatcher(t, f, args)
+
+	_, _, err := w.downloadSynchronously()
+	require.NoError(t, err)
+
+	keyMD5 := md5.Sum([]byte(key))
+	require.Equal(t, "AES256", f.lastRequest.Get("X-Amz-Server-Side-Encryption-Customer-Algorithm"))
+	require.Equal(t, base64.StdEncoding.EncodeToString([]byte(key)), f.lastRequest.Get("X-Amz-Server-Side-Encryption-Customer-Key"))
+	require.Equal(t, base64.StdEncoding.EncodeToString(keyMD5[:]), f.lastRequest.Get("X-Amz-Server-Side-Encryption-Customer-Key-Md5"))
+}
+
+func TestAssumeRoleChain(t *testing.T) {
+	f := &fakeS3{content: "content", etag: `"1"`}
+	args := DefaultArguments
+	first, second := DefaultAssumeRole, DefaultAssumeRole
+	first.RoleARN = "arn:aws:iam::123456789012:role/first"
+	second.RoleARN = "arn:aws:iam::210987654321:role/second"
+	args.Options.AssumeRoles = []AssumeRole{first, second}
+	w := newTestWatcher(t, f, args)
+
+	_, _, err := w.downloadSynchronously()
+	require.NoError(t, err)
+
+	// Each role is assumed with the credentials of the previous one, and the
+	// object is downloaded with the credentials of the last one.
+	require.Equal(t, []string{first.RoleARN, second.RoleARN}, f.assumedRoles)
+	require.Equal(t, []string{"key", "key-first", "key-second"}, f.accessKeys)
+}
+
+func TestValidateArguments(t *testing.T) {
+	tests := []struct {
+		name        string
+		cfg         string
+		expectedErr string
+	}{
+		{
+			name: "assume roles",
+			cfg: `
+				path = "s3://bucket/file"
+				client {
+					assume_role {
+						role_arn = "arn:aws:iam::123456789012:role/first"
+					}
+					assume_role {
+						role_arn    = "arn:aws:iam::210987654321:role/second"
+						external_id = "id"
+						duration    = "1h"
+					}
+				}`,
+		},
+		{
+			name: "short assume role duration",
+			cfg: `
+				path = "s3://bucket/file"
+				client {
+					assume_role {
+						role_arn = "arn:aws:iam::123456789012:role/first"
+						duration = "1m"
+					}
+				}`,
+			expectedErr: "assume_role duration must be at least 15m",
+		},
+		{
+			name: "sse-kms",
+			cfg: `
+				path = "s3://bucket/file"
+				server_side_encryption {
+					type       = "sse-kms"
+					kms_key_id = "1234"
+				}`,
+		},
+		{
+			name: "sse-c with kms key",
+			cfg: `
+				path = "s3://bucket/file"
+				server_side_encryption {
+					type         = "sse-c"
+					kms_key_id   = "1234"
+					customer_key = "kkkkkkkkkkkkkkkkkkkkkkkkkkkkkkkk"
+				}`,
+			expectedErr: `server_side_encryption kms_key_id can only be set when type is "sse-kms"`,
+		},
+		{
+			name: "sse-c with short key",
+			cfg: `
+				path = "s3://bucket/file"
+				server_side_encryption {
+					type         = "sse-c"
+					customer_key = "short"
+				}`,
+			expectedErr: "server_side_encryption customer_key must be 32 bytes long",
+		},
+		{
+			name: "unknown sse type",
+			cfg: `
+				path = "s3://bucket/file"
+				server_side_encryption {
+					type = "sse-s3"
+				}`,
+			expectedErr: `server_side_encryption type must be one of "sse-kms" or "sse-c"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tt.cfg), &args)
+			if tt.expectedErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tt.expectedErr)
+			}
+		})
+	}
+}
@@ -17,6 +17,9 @@ type Arguments struct {
 	IsSecret bool `alloy:"is_secret,attr,optional"`
 	// Options allows the overriding of default settings.
 	Options Client `alloy:"client,block,optional"`
+
+	// ServerSideEncryption configures how the file is encrypted at rest.
+	ServerSideEncryption *ServerSideEncryption `alloy:"server_side_encryption,block,optional"`
 }
 
 // Client implements specific AWS configuration options
@@ -28,6 +31,79 @@ type Client struct {
 	UsePathStyle  bool              `alloy:"use_path_style,attr,optional"`
 	Region        string            `alloy:"region,attr,optional"`
 	SigningRegion string            `alloy:"signing_region,attr,optional"`
+
+	// AssumeRoles are assumed in order, each with the credentials of the
+	// previous one.
+	AssumeRoles []AssumeRole `alloy:"assume_role,block,optional"`
+}
+
+// AssumeRole configures a role to assume with STS.
+type AssumeRole struct {
+	RoleARN     string        `alloy:"role_arn,attr"`
+	SessionName string        `alloy:"session_name,attr,optional"`
+	ExternalID  string        `alloy:"external_id,attr,optional"`
+	Duration    time.Duration `alloy:"duration,attr,optional"`
+}
+
+// DefaultAssumeRole holds default settings for AssumeRole.
+var DefaultAssumeRole = AssumeRole{
+	SessionName: "alloy",
+	Duration:    15 * time.Minute,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (r *AssumeRole) SetToDefault() {
+	*r = DefaultAssumeRole
+}
+
+// Validate implements syntax.Validator.
+func (r *AssumeRole) Validate() error {
+	if r.RoleARN == "" {
+		return fmt.Errorf("assume_role role_arn must not be empty")
+	}
+	// STS rejects sessions shorter than 15 minutes.
+	if r.Duration < 15*time.Minute {
+		return fmt.Errorf("assume_role duration must be at least 15m")
+	}
+	return nil
+}
+
+// Types of server-side encryption.
+const (
+	SSEKMS = "sse-kms"
+	SSEC   = "sse-c"
+)
+
+// ServerSideEncryption configures how the file is encrypted at rest.
+type ServerSideEncryption struct {
+	// Type is either SSEKMS or SSEC.
+	Type string `alloy:"type,attr"`
+	// KMSKeyID is the key which the file must be encrypted with when using
+	// SSE-KMS.
+	KMSKeyID string `alloy:"kms_key_id,attr,optional"`
+	// CustomerKey is the key to decrypt the file with when using SSE-C.
+	CustomerKey alloytypes.Secret `alloy:"customer_key,attr,optional"`
+}
+
+// Validate implements syntax.Validator.
+func (sse *ServerSideEncryption) Validate() error {
+	switch sse.Type {
+	case SSEKMS:
+		if sse.CustomerKey != "" {
+			return fmt.Errorf("server_side_encryption customer_key can only be set when type is %q", SSEC)
+		}
+	case SSEC:
+		if sse.KMSKeyID != "" {
+			return fmt.Errorf("server_side_encryption kms_key_id can only be set when type is %q", SSEKMS)
+		}
+		// SSE-C keys are 256-bit AES keys.
+		if len(sse.CustomerKey) != 32 {
+			return fmt.Errorf("server_side_encryption customer_key must be 32 bytes long")
+		}
+	default:
+		return fmt.Errorf("server_side_encryption type must be one of %q or %q", SSEKMS, SSEC)
+	}
+	return nil
 }
 
 const minimumPollFrequency = 30 * time.Second
@@ -53,4 +129,11 @@ func (a *Arguments) Validate() error {
 // Exports implements the file content
 type Exports struct {
 	Content alloytypes.OptionalSecret `alloy:"content,attr"`
+
+	// Metadata of the file.
+	ETag         string            `alloy:"etag,attr"`
+	VersionID    string            `alloy:"version_id,attr"`
+	LastModified string            `alloy:"last_modified,attr"`
+	ContentType  string            `alloy:"content_type,attr"`
+	Metadata     map[string]string `alloy:"metadata,attr"`
 }
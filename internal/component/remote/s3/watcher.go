@@ -1,11 +1,19 @@
 package s3
 
 import (
+	"crypto/md5"
+	"encoding/base64"
+	"errors"
+	"fmt"
 	"io"
+	"net/http"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 
 	"context"
 
@@ -19,11 +27,29 @@ type watcher struct {
 	output     chan result
 	dlTicker   *time.Ticker
 	downloader *s3.Client
+	sse        *ServerSideEncryption
+
+	// etag is the ETag of the last downloaded object, used to only download
+	// it again when it changed.
+	etag string
 }
 
 type result struct {
 	result []byte
-	err    error
+	object objectInfo
+	// notModified is set when the object didn't change since the last
+	// download, in which case result and object are empty.
+	notModified bool
+	err         error
+}
+
+// objectInfo holds the metadata of a downloaded object.
+type objectInfo struct {
+	etag         string
+	versionID    string
+	lastModified string
+	contentType  string
+	metadata     map[string]string
 }
 
 func newWatcher(
@@ -31,6 +57,7 @@ func newWatcher(
 	out chan result,
 	frequency time.Duration,
 	downloader *s3.Client,
+	sse *ServerSideEncryption,
 ) *watcher {
 
 	return &watcher{
@@ -39,16 +66,20 @@ func newWatcher(
 		output:     out,
 		dlTicker:   time.NewTicker(frequency),
 		downloader: downloader,
+		sse:        sse,
 	}
 }
 
-func (w *watcher) updateValues(bucket, file string, frequency time.Duration, downloader *s3.Client) {
+func (w *watcher) updateValues(bucket, file string, frequency time.Duration, downloader *s3.Client, sse *ServerSideEncryption) {
 	w.mut.Lock()
 	defer w.mut.Unlock()
 	w.bucket = bucket
 	w.file = file
 	w.dlTicker.Reset(frequency)
 	w.downloader = downloader
+	w.sse = sse
+	// The object may be a different one, so it must be downloaded again.
+	w.etag = ""
 }
 
 func (w *watcher) run(ctx context.Context) {
@@ -68,11 +99,16 @@ func (w *watcher) run(ctx context.Context) {
 func (w *watcher) download(ctx context.Context) {
 	w.mut.Lock()
 	defer w.mut.Unlock()
-	buf, err := w.getObject(context.Background())
+	buf, object, err := w.getObject(context.Background())
 	r := result{
 		result: buf,
+		object: object,
 		err:    err,
 	}
+	if errors.Is(err, errNotModified) {
+		r.notModified = true
+		r.err = nil
+	}
 	select {
 	case <-ctx.Done():
 		return
@@ -80,34 +116,93 @@ func (w *watcher) download(ctx context.Context) {
 	}
 }
 
-func (w *watcher) downloadSynchronously() (string, error) {
+func (w *watcher) downloadSynchronously() (string, objectInfo, error) {
 	w.mut.Lock()
 	defer w.mut.Unlock()
-	buf, err := w.getObject(context.Background())
+	buf, object, err := w.getObject(context.Background())
 	if err != nil {
-		return "", err
+		return "", objectInfo{}, err
 	}
-	return string(buf), nil
+	return string(buf), object, nil
 }
 
+// errNotModified is returned by getObject when the object didn't change since
+// the last download.
+var errNotModified = errors.New("object not modified")
+
 // getObject ensure that the return []byte is never nil
-func (w *watcher) getObject(ctx context.Context) ([]byte, error) {
-	output, err := w.downloader.GetObject(ctx, &s3.GetObjectInput{
+func (w *watcher) getObject(ctx context.Context) ([]byte, objectInfo, error) {
+	input := &s3.GetObjectInput{
 		Bucket: aws.String(w.bucket),
 		Key:    aws.String(w.file),
-	})
+	}
+	if w.etag != "" {
+		input.IfNoneMatch = aws.String(w.etag)
+	}
+	if w.sse != nil && w.sse.Type == SSEC {
+		key := []byte(w.sse.CustomerKey)
+		keyMD5 := md5.Sum(key)
+		input.SSECustomerAlgorithm = aws.String(string(types.ServerSideEncryptionAes256))
+		input.SSECustomerKey = aws.String(base64.StdEncoding.EncodeToString(key))
+		input.SSECustomerKeyMD5 = aws.String(base64.StdEncoding.EncodeToString(keyMD5[:]))
+	}
+
+	output, err := w.downloader.GetObject(ctx, input)
 	if err != nil {
-		return []byte{}, err
+		var respErr *awshttp.ResponseError
+		if errors.As(err, &respErr) && respErr.HTTPStatusCode() == http.StatusNotModified {
+			return []byte{}, objectInfo{}, errNotModified
+		}
+		return []byte{}, objectInfo{}, err
 	}
 	defer output.Body.Close()
 
+	if err := w.checkEncryption(output); err != nil {
+		return []byte{}, objectInfo{}, err
+	}
+
 	buf := make([]byte, *output.ContentLength)
 
 	_, err = io.ReadFull(output.Body, buf)
 
 	if err != nil {
-		return []byte{}, err
+		return []byte{}, objectInfo{}, err
 	}
 
-	return buf, nil
+	object := objectInfo{
+		etag:        aws.ToString(output.ETag),
+		versionID:   aws.ToString(output.VersionId),
+		contentType: aws.ToString(output.ContentType),
+		metadata:    output.Metadata,
+	}
+	if output.LastModified != nil {
+		object.lastModified = output.LastModified.UTC().Format(time.RFC3339)
+	}
+	if object.metadata == nil {
+		object.metadata = map[string]string{}
+	}
+	w.etag = object.etag
+
+	return buf, object, nil
+}
+
+// checkEncryption returns an error when SSE-KMS is configured and the object
+// isn't encrypted with SSE-KMS, or with another key than the configured one.
+func (w *watcher) checkEncryption(output *s3.GetObjectOutput) error {
+	if w.sse == nil || w.sse.Type != SSEKMS {
+		return nil
+	}
+
+	switch output.ServerSideEncryption {
+	case types.ServerSideEncryptionAwsKms, types.ServerSideEncryptionAwsKmsDsse:
+	default:
+		return fmt.Errorf("object isn't encrypted with SSE-KMS")
+	}
+
+	// S3 returns the ARN of the key, which ends with the key ID.
+	keyID := aws.ToString(output.SSEKMSKeyId)
+	if w.sse.KMSKeyID != "" && keyID != w.sse.KMSKeyID && !strings.HasSuffix(keyID, ":key/"+w.sse.KMSKeyID) {
+		return fmt.Errorf("object is encrypted with KMS key %q instead of %q", keyID, w.sse.KMSKeyID)
+	}
+	return nil
 }
@@ -68,6 +68,13 @@ func (a *AuthArguments) authMethod() authMethod {
 	return invalidAuth{}
 }
 
+// Authenticate authenticates cli against Vault using the configured auth
+// method. The returned secret may be nil if the auth method doesn't generate
+// a secret.
+func (a *AuthArguments) Authenticate(ctx context.Context, cli *vault.Client) (*vault.Secret, error) {
+	return a.authMethod().vaultAuthenticate(ctx, cli)
+}
+
 // AuthToken authenticates against Vault with a token.
 type AuthToken struct {
 	Token alloytypes.Secret `alloy:"token,attr"`
@@ -3,6 +3,7 @@ package vault
 import (
 	"context"
 	"fmt"
+	"path"
 	"strings"
 
 	vault "github.com/hashicorp/vault/api"
@@ -14,8 +15,6 @@ type secretStore interface {
 	Read(ctx context.Context, args *Arguments) (*vault.Secret, error)
 }
 
-// TODO(rfratto): support logical stores.
-
 type kvStore struct{ c *vault.Client }
 
 func (ks *kvStore) Read(ctx context.Context, args *Arguments) (*vault.Secret, error) {
@@ -51,3 +50,24 @@ func (ks *kvStore) Read(ctx context.Context, args *Arguments) (*vault.Secret, er
 	kvSecret.Raw.Data = kvSecret.Data
 	return kvSecret.Raw, nil
 }
+
+// logicalStore reads secrets with the generic read API, which is used by
+// dynamic secrets engines like the database or AWS engines to issue new
+// credentials.
+type logicalStore struct{ c *vault.Client }
+
+func (ls *logicalStore) Read(ctx context.Context, args *Arguments) (*vault.Secret, error) {
+	secretPath := args.Path
+	if args.Key != "" {
+		secretPath = path.Join(args.Path, args.Key)
+	}
+
+	secret, err := ls.c.Logical().ReadWithContext(ctx, secretPath)
+	if err != nil {
+		return nil, err
+	}
+	if secret == nil {
+		return nil, fmt.Errorf("no secret found at %q", secretPath)
+	}
+	return secret, nil
+}
@@ -9,12 +9,20 @@ import (
 	"github.com/go-kit/log"
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/dskit/backoff"
 	vault "github.com/hashicorp/vault/api"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
 const tokenManagerInitializeTimeout = time.Minute
 
+// reissueBackoff configures the retries of retrieving a new token when the
+// current one is about to expire.
+var reissueBackoff = backoff.Config{
+	MinBackoff: time.Second,
+	MaxBackoff: time.Minute,
+}
+
 type getTokenFunc func(ctx context.Context, client *vault.Client) (*vault.Secret, error)
 
 // A tokenManager retrieves and manages the lifecycle of tokens. tokenManager,
@@ -195,8 +203,17 @@ func (tm *tokenManager) updateLifecycleWatcher(ctx context.Context) {
 				if ctx.Err() != nil {
 					return
 				}
-				// Error is logged as health and debug info.
-				_ = tm.updateToken(ctx)
+				// The lease can't be renewed anymore and is about to expire, so
+				// retrieve a new token until it succeeds. Errors are logged as
+				// health and debug info.
+				bo := backoff.New(ctx, reissueBackoff)
+				for bo.Ongoing() {
+					if err := tm.updateToken(ctx); err == nil {
+						return
+					}
+					bo.Wait()
+				}
+				return
 
 			case output := <-lw.RenewCh():
 				tm.refreshCounter.Inc()
@@ -209,7 +226,7 @@ func (tm *tokenManager) updateLifecycleWatcher(ctx context.Context) {
 
 // needsLifecycleWatcher determines if a secret needs a lifecycle watcher.
 // Secrets only need a lifecycle watcher if they are renewable or have a lease
-// duration.
+// duration. Secrets without a lease ID can't be watched.
 func needsLifecycleWatcher(secret *vault.Secret) bool {
 	if secret == nil {
 		return false
@@ -218,7 +235,7 @@ func needsLifecycleWatcher(secret *vault.Secret) bool {
 	if secret.Auth != nil {
 		return secret.Auth.Renewable || secret.Auth.LeaseDuration > 0
 	}
-	return secret.Renewable || secret.LeaseDuration > 0
+	return secret.LeaseID != "" && (secret.Renewable || secret.LeaseDuration > 0)
 }
 
 // SetClient updates the client associated with the tokenManager. This will
@@ -283,6 +300,11 @@ func getSecretInfo(secret *vault.Secret, updateTime time.Time) secretInfo {
 }
 
 func secretExpireTime(secret *vault.Secret) time.Time {
+	// Secrets issued by dynamic secrets engines have a lease instead of a TTL.
+	if secret.Auth == nil && secret.LeaseID != "" && secret.LeaseDuration > 0 {
+		return time.Now().UTC().Add(time.Duration(secret.LeaseDuration) * time.Second)
+	}
+
 	ttl, err := secret.TokenTTL()
 	if err != nil || ttl == 0 {
 		return time.Time{}
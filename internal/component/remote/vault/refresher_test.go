@@ -0,0 +1,104 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	vault "github.com/hashicorp/vault/api"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func Test_tokenManagerReissuesExpiringLease(t *testing.T) {
+	cli, err := vault.NewClient(vault.DefaultConfig())
+	require.NoError(t, err)
+
+	// The getter issues a new non-renewable lease of 2 seconds every time
+	// it's called, like dynamic secrets engines do.
+	var reads atomic.Int64
+	getter := func(context.Context, *vault.Client) (*vault.Secret, error) {
+		n := reads.Inc()
+		if n == 2 {
+			return nil, context.DeadlineExceeded
+		}
+		return &vault.Secret{
+			LeaseID:       "database/creds/readonly/lease",
+			LeaseDuration: 2,
+			Renewable:     false,
+			Data:          map[string]any{"read": n},
+		}, nil
+	}
+
+	prevBackoff := reissueBackoff
+	reissueBackoff.MinBackoff, reissueBackoff.MaxBackoff = 10*time.Millisecond, 10*time.Millisecond
+	defer func() { reissueBackoff = prevBackoff }()
+
+	tm, err := newTokenManager(tokenManagerOptions{
+		Log:            log.NewNopLogger(),
+		Getter:         getter,
+		ReadCounter:    prometheus.NewCounter(prometheus.CounterOpts{}),
+		RefreshCounter: prometheus.NewCounter(prometheus.CounterOpts{}),
+		Client:         cli,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), reads.Load())
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+	go tm.Run(ctx)
+
+	// A new lease is issued before the first one expires, retrying after the
+	// failed read.
+	start := time.Now()
+	require.Eventually(t, func() bool { return reads.Load() == 3 }, 2*time.Second, 10*time.Millisecond)
+	require.Less(t, time.Since(start), 2*time.Second)
+	require.Equal(t, "retrieved token", tm.CurrentHealth().Message)
+}
+
+func Test_logicalStore(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/database/creds/readonly" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"lease_id":       "database/creds/readonly/abcd",
+			"lease_duration": 3600,
+			"renewable":      true,
+			"data": map[string]any{
+				"username": "v-token-readonly",
+				"password": "password",
+			},
+		})
+	}))
+	defer srv.Close()
+
+	cfg := vault.DefaultConfig()
+	cfg.Address = srv.URL
+	cli, err := vault.NewClient(cfg)
+	require.NoError(t, err)
+
+	store := &logicalStore{c: cli}
+
+	for _, args := range []Arguments{
+		{Path: "database/creds/readonly"},
+		{Path: "database/creds", Key: "readonly"},
+	} {
+		secret, err := store.Read(t.Context(), &args)
+		require.NoError(t, err)
+		require.Equal(t, "database/creds/readonly/abcd", secret.LeaseID)
+		require.Equal(t, map[string]any{
+			"username": "v-token-readonly",
+			"password": "password",
+		}, secret.Data)
+	}
+
+	_, err = store.Read(t.Context(), &Arguments{Path: "database/creds/unknown"})
+	require.Error(t, err)
+}
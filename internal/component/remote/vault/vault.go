@@ -34,8 +34,9 @@ type Arguments struct {
 	Server    string `alloy:"server,attr"`
 	Namespace string `alloy:"namespace,attr,optional"`
 
-	Path string `alloy:"path,attr"`
-	Key  string `alloy:"key,attr,optional"`
+	Path   string `alloy:"path,attr"`
+	Key    string `alloy:"key,attr,optional"`
+	Engine string `alloy:"engine,attr,optional"`
 
 	RereadFrequency time.Duration `alloy:"reread_frequency,attr,optional"`
 
@@ -51,6 +52,7 @@ type Arguments struct {
 
 // DefaultArguments holds default settings for Arguments.
 var DefaultArguments = Arguments{
+	Engine: EngineKV,
 	ClientOptions: ClientOptions{
 		MinRetryWait: 1000 * time.Millisecond,
 		MaxRetryWait: 1500 * time.Millisecond,
@@ -59,6 +61,15 @@ var DefaultArguments = Arguments{
 	},
 }
 
+// Secrets engines which secrets can be read from.
+const (
+	// EngineKV reads secrets from a KV version 2 secrets engine.
+	EngineKV = "kv"
+	// EngineDynamic reads secrets with the generic read API, like the
+	// credentials issued by dynamic secrets engines.
+	EngineDynamic = "dynamic"
+)
+
 // client creates a Vault client from the arguments.
 func (a *Arguments) client() (*vault.Client, error) {
 	cfg := vault.DefaultConfig()
@@ -96,6 +107,10 @@ func (a *Arguments) Validate() error {
 		return fmt.Errorf("client_options.timeout must be greater than 0")
 	}
 
+	if a.Engine != EngineKV && a.Engine != EngineDynamic {
+		return fmt.Errorf("engine must be one of %q or %q", EngineKV, EngineDynamic)
+	}
+
 	return nil
 }
 
@@ -107,7 +122,9 @@ func (a *Arguments) authMethod() authMethod {
 }
 
 func (a *Arguments) secretStore(cli *vault.Client) secretStore {
-	// TODO(rfratto): support different stores (like a logical store).
+	if a.Engine == EngineDynamic {
+		return &logicalStore{c: cli}
+	}
 	return &kvStore{c: cli}
 }
 
@@ -14,8 +14,18 @@ func (b *ConfigBuilder) appendMongodbExporter(config *mongodb_exporter.Config, i
 
 func toMongodbExporter(config *mongodb_exporter.Config) *mongodb.Arguments {
 	return &mongodb.Arguments{
-		URI:             alloytypes.Secret(config.URI),
-		DirectConnect:   config.DirectConnect,
-		DiscoveringMode: config.DiscoveringMode,
+		URI:                    alloytypes.Secret(config.URI),
+		DirectConnect:          config.DirectConnect,
+		DiscoveringMode:        config.DiscoveringMode,
+		CompatibleMode:         config.CompatibleMode,
+		CollectAll:             config.CollectAll,
+		EnableDiagnosticData:   config.EnableDiagnosticData,
+		EnableReplicasetStatus: config.EnableReplicasetStatus,
+		EnableDBStats:          config.EnableDBStats,
+		EnableTopMetrics:       config.EnableTopMetrics,
+		EnableIndexStats:       config.EnableIndexStats,
+		EnableCollStats:        config.EnableCollStats,
+		CollStatsNamespaces:    config.CollStatsNamespaces,
+		IndexStatsNamespaces:   config.IndexStatsNamespaces,
 	}
 }
@@ -0,0 +1,137 @@
+package doctor
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+func checkListenAddr(addr string) Result {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		if errors.Is(err, syscall.EADDRINUSE) {
+			return Result{
+				Status:  StatusError,
+				Message: fmt.Sprintf("%s is already in use.", addr),
+				Fix:     "Stop the process listening on it, like another Alloy instance, or change --server.http.listen-addr.",
+			}
+		}
+		return Result{
+			Status:  StatusError,
+			Message: fmt.Sprintf("Can't listen on %s: %s.", addr, err),
+			Fix:     "Change --server.http.listen-addr to an address of this host.",
+		}
+	}
+	_ = lis.Close()
+	return Result{Status: StatusOK, Message: fmt.Sprintf("%s is available.", addr)}
+}
+
+func checkStoragePath(path string) Result {
+	fix := fmt.Sprintf("Grant the user running Alloy write access to %s, or change --storage.path.", path)
+
+	// A missing path isn't created, as Alloy creates it on start. The closest
+	// existing directory must be writable instead.
+	dir := filepath.Clean(path)
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return Result{Status: StatusError, Message: fmt.Sprintf("%s isn't a directory.", dir), Fix: fix}
+			}
+			break
+		}
+		parent := filepath.Dir(dir)
+		if !errors.Is(err, fs.ErrNotExist) || parent == dir {
+			return Result{Status: StatusError, Message: fmt.Sprintf("Can't access %s: %s.", dir, err), Fix: fix}
+		}
+		dir = parent
+	}
+
+	f, err := os.CreateTemp(dir, ".alloy-doctor-*")
+	if err != nil {
+		return Result{Status: StatusError, Message: fmt.Sprintf("Can't write to %s: %s.", dir, err), Fix: fix}
+	}
+	_ = f.Close()
+	_ = os.Remove(f.Name())
+
+	if dir != filepath.Clean(path) {
+		return Result{Status: StatusOK, Message: fmt.Sprintf("%s doesn't exist, and can be created in %s.", path, dir)}
+	}
+	return Result{Status: StatusOK, Message: fmt.Sprintf("%s is writable.", path)}
+}
+
+// minFileDescriptors is the lowest file descriptor limit which doesn't
+// trigger a warning. Every connection, open file, and tailed log counts
+// towards the limit.
+const minFileDescriptors = 65536
+
+func evaluateFileDescriptors(limit uint64) Result {
+	if limit < minFileDescriptors {
+		return Result{
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("The limit of open files is %d, which busy pipelines can exceed.", limit),
+			Fix:     fmt.Sprintf("Raise the hard limit to at least %d, like with LimitNOFILE in the systemd unit of Alloy or with ulimit -Hn.", minFileDescriptors),
+		}
+	}
+	return Result{Status: StatusOK, Message: fmt.Sprintf("The limit of open files is %d.", limit)}
+}
+
+// minKernelMajor and minKernelMinor form the oldest kernel version supported
+// by eBPF components.
+const (
+	minKernelMajor = 5
+	minKernelMinor = 8
+)
+
+// evaluateKernelVersion checks a kernel release, like 6.1.0-18-amd64.
+func evaluateKernelVersion(release string) Result {
+	var major, minor int
+	parts := strings.SplitN(release, ".", 3)
+	if len(parts) >= 2 {
+		major, _ = strconv.Atoi(parts[0])
+		minor, _ = strconv.Atoi(strings.TrimRightFunc(parts[1], func(r rune) bool { return r < '0' || r > '9' }))
+	}
+	switch {
+	case major == 0:
+		return Result{Status: StatusWarning, Message: fmt.Sprintf("Can't parse the kernel release %q.", release)}
+	case major < minKernelMajor || (major == minKernelMajor && minor < minKernelMinor):
+		return Result{
+			Status:  StatusWarning,
+			Message: fmt.Sprintf("Kernel %s is older than %d.%d.", release, minKernelMajor, minKernelMinor),
+			Fix:     fmt.Sprintf("Upgrade the kernel to %d.%d or later. Some distributions, like RHEL 8, backport the required features to older kernels.", minKernelMajor, minKernelMinor),
+		}
+	}
+	return Result{Status: StatusOK, Message: fmt.Sprintf("Kernel %s is supported.", release)}
+}
+
+// Capabilities used by eBPF components, as numbered by the kernel.
+const (
+	capSysAdmin = 21
+	capPerfmon  = 38
+	capBPF      = 39
+)
+
+// evaluateEBPFPrivileges checks the effective capabilities of the process, as
+// a bit set, and the value of the kernel.perf_event_paranoid parameter.
+func evaluateEBPFPrivileges(capEff uint64, perfEventParanoid int) Result {
+	has := func(c int) bool { return capEff&(1<<c) != 0 }
+	if has(capSysAdmin) || (has(capBPF) && has(capPerfmon)) {
+		return Result{Status: StatusOK, Message: "The process has the capabilities eBPF components need."}
+	}
+
+	msg := "The process lacks the CAP_BPF and CAP_PERFMON capabilities, or CAP_SYS_ADMIN."
+	if perfEventParanoid > 1 {
+		msg += fmt.Sprintf(" kernel.perf_event_paranoid is %d, which restricts performance monitoring to privileged processes.", perfEventParanoid)
+	}
+	return Result{
+		Status:  StatusError,
+		Message: msg,
+		Fix:     "Run Alloy as root, or grant it the capabilities, like with setcap or the security context of its container.",
+	}
+}
@@ -0,0 +1,89 @@
+//go:build linux
+
+package doctor
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+func checkClockSync() Result {
+	var tx unix.Timex
+	state, err := unix.Adjtimex(&tx)
+	if err != nil {
+		return Result{Status: StatusWarning, Message: fmt.Sprintf("Can't read the state of the system clock: %s.", err)}
+	}
+	if state == unix.TIME_ERROR || tx.Status&unix.STA_UNSYNC != 0 {
+		return Result{
+			Status:  StatusWarning,
+			Message: "The system clock isn't synchronized. Skewed timestamps can make backends reject samples, logs, and spans.",
+			Fix:     "Enable NTP synchronization, like with timedatectl set-ntp true, chrony, or ntpd.",
+		}
+	}
+	return Result{Status: StatusOK, Message: "The system clock is synchronized."}
+}
+
+func checkKernelVersion() Result {
+	var uts unix.Utsname
+	if err := unix.Uname(&uts); err != nil {
+		return Result{Status: StatusWarning, Message: fmt.Sprintf("Can't read the kernel release: %s.", err)}
+	}
+	return evaluateKernelVersion(unix.ByteSliceToString(uts.Release[:]))
+}
+
+func checkBTF() Result {
+	_, err := os.Stat("/sys/kernel/btf/vmlinux")
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return Result{
+			Status:  StatusWarning,
+			Message: "The kernel doesn't expose BTF type information at /sys/kernel/btf/vmlinux.",
+			Fix:     "Use a kernel built with CONFIG_DEBUG_INFO_BTF=y, which most distributions enable by default.",
+		}
+	case err != nil:
+		return Result{Status: StatusWarning, Message: fmt.Sprintf("Can't check BTF type information: %s.", err)}
+	}
+	return Result{Status: StatusOK, Message: "The kernel exposes BTF type information."}
+}
+
+func checkEBPFPrivileges() Result {
+	capEff, err := effectiveCapabilities()
+	if err != nil {
+		return Result{Status: StatusWarning, Message: fmt.Sprintf("Can't read the capabilities of the process: %s.", err)}
+	}
+
+	// The parameter only adds context to the result, so ignore errors.
+	paranoid := -1
+	if bb, err := os.ReadFile("/proc/sys/kernel/perf_event_paranoid"); err == nil {
+		paranoid, _ = strconv.Atoi(strings.TrimSpace(string(bb)))
+	}
+	return evaluateEBPFPrivileges(capEff, paranoid)
+}
+
+// effectiveCapabilities returns the effective capabilities of the process as
+// a bit set.
+func effectiveCapabilities() (uint64, error) {
+	f, err := os.Open("/proc/self/status")
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		if v, ok := strings.CutPrefix(s.Text(), "CapEff:"); ok {
+			return strconv.ParseUint(strings.TrimSpace(v), 16, 64)
+		}
+	}
+	if err := s.Err(); err != nil {
+		return 0, err
+	}
+	return 0, errors.New("CapEff not found in /proc/self/status")
+}
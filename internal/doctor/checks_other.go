@@ -0,0 +1,21 @@
+//go:build !linux
+
+package doctor
+
+func checkClockSync() Result {
+	return Result{Status: StatusSkipped, Message: "Only checked on Linux."}
+}
+
+func checkKernelVersion() Result { return ebpfUnsupported() }
+
+func checkBTF() Result { return ebpfUnsupported() }
+
+func checkEBPFPrivileges() Result { return ebpfUnsupported() }
+
+func ebpfUnsupported() Result {
+	return Result{
+		Status:  StatusError,
+		Message: "eBPF components only run on Linux.",
+		Fix:     "Run Alloy on Linux, or remove the eBPF components from the configuration.",
+	}
+}
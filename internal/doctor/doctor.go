@@ -0,0 +1,139 @@
+// Package doctor checks the environment Alloy runs in for common issues, like
+// ports already in use or low file descriptor limits, and reports how to fix
+// them.
+package doctor
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Status is the outcome of a check. Statuses are ordered by severity.
+type Status int
+
+const (
+	// StatusOK means that no issue was found.
+	StatusOK Status = iota
+	// StatusSkipped means that the check doesn't apply to the environment.
+	StatusSkipped
+	// StatusWarning means that Alloy can run, but may not work as expected.
+	StatusWarning
+	// StatusError means that Alloy or some of its components can't run.
+	StatusError
+)
+
+// String returns the label of s used in reports.
+func (s Status) String() string {
+	switch s {
+	case StatusOK:
+		return "ok"
+	case StatusSkipped:
+		return "skip"
+	case StatusWarning:
+		return "warn"
+	case StatusError:
+		return "error"
+	default:
+		return fmt.Sprintf("Status(%d)", int(s))
+	}
+}
+
+// Result is the result of a check.
+type Result struct {
+	Status  Status
+	Message string
+	// Fix describes how to fix the issue, if any.
+	Fix string
+}
+
+// Check checks a single aspect of the environment.
+type Check struct {
+	Name string
+	Run  func() Result
+}
+
+// Options configures the checks returned by Checks.
+type Options struct {
+	// HTTPListenAddr is the address the HTTP server listens on.
+	HTTPListenAddr string
+	// StoragePath is the directory components store data in.
+	StoragePath string
+	// EBPFComponents lists the IDs of the components of the configuration
+	// relying on eBPF.
+	EBPFComponents []string
+	// SkipEBPF skips the checks of eBPF components, like when the
+	// configuration doesn't use any.
+	SkipEBPF bool
+}
+
+// Checks returns the checks of the environment described by opts.
+func Checks(opts Options) []Check {
+	checks := []Check{
+		{Name: "HTTP listen address", Run: func() Result { return checkListenAddr(opts.HTTPListenAddr) }},
+		{Name: "File descriptors", Run: checkFileDescriptors},
+		{Name: "Storage path", Run: func() Result { return checkStoragePath(opts.StoragePath) }},
+		{Name: "Clock synchronization", Run: checkClockSync},
+	}
+
+	ebpfChecks := []Check{
+		{Name: "eBPF kernel version", Run: checkKernelVersion},
+		{Name: "eBPF type information", Run: checkBTF},
+		{Name: "eBPF privileges", Run: checkEBPFPrivileges},
+	}
+	for _, c := range ebpfChecks {
+		run := c.Run
+		c.Run = func() Result {
+			if opts.SkipEBPF {
+				return Result{Status: StatusSkipped, Message: "The configuration doesn't use eBPF components."}
+			}
+			res := run()
+			switch {
+			case len(opts.EBPFComponents) > 0 && res.Status > StatusSkipped:
+				res.Message += fmt.Sprintf(" Required by %s.", strings.Join(opts.EBPFComponents, ", "))
+			case len(opts.EBPFComponents) == 0 && res.Status == StatusError:
+				// Without a configuration, it's unknown whether eBPF
+				// components are used.
+				res.Status = StatusWarning
+			}
+			return res
+		}
+		checks = append(checks, c)
+	}
+	return checks
+}
+
+// Finding is the result of a check.
+type Finding struct {
+	Check string
+	Result
+}
+
+// Run runs checks in order.
+func Run(checks []Check) []Finding {
+	findings := make([]Finding, 0, len(checks))
+	for _, c := range checks {
+		findings = append(findings, Finding{Check: c.Name, Result: c.Run()})
+	}
+	return findings
+}
+
+// Write writes a report of findings to w and returns the most severe status
+// among them.
+func Write(w io.Writer, findings []Finding) (Status, error) {
+	worst := StatusOK
+	for _, f := range findings {
+		worst = max(worst, f.Status)
+
+		if _, err := fmt.Fprintf(w, "%-6s%s: %s\n", f.Status, f.Check, f.Message); err != nil {
+			return worst, err
+		}
+		if f.Fix == "" {
+			continue
+		}
+		if _, err := fmt.Fprintf(w, "%-6sFix: %s\n", "", f.Fix); err != nil {
+			return worst, err
+		}
+	}
+	return worst, nil
+}
@@ -0,0 +1,158 @@
+package doctor
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWrite(t *testing.T) {
+	findings := Run([]Check{
+		{Name: "First", Run: func() Result { return Result{Status: StatusOK, Message: "Fine."} }},
+		{Name: "Second", Run: func() Result {
+			return Result{Status: StatusWarning, Message: "Not great.", Fix: "Do something."}
+		}},
+		{Name: "Third", Run: func() Result { return Result{Status: StatusSkipped, Message: "Not applicable."} }},
+	})
+
+	var sb strings.Builder
+	worst, err := Write(&sb, findings)
+	require.NoError(t, err)
+	require.Equal(t, StatusWarning, worst)
+	require.Equal(t, `ok    First: Fine.
+warn  Second: Not great.
+      Fix: Do something.
+skip  Third: Not applicable.
+`, sb.String())
+}
+
+func TestChecks_SkipEBPF(t *testing.T) {
+	checks := Checks(Options{
+		HTTPListenAddr: "127.0.0.1:0",
+		StoragePath:    t.TempDir(),
+		SkipEBPF:       true,
+	})
+
+	for _, f := range Run(checks) {
+		if strings.HasPrefix(f.Check, "eBPF") {
+			require.Equal(t, StatusSkipped, f.Status, f.Check)
+			continue
+		}
+		require.NotEqual(t, StatusError, f.Status, "%s: %s", f.Check, f.Message)
+	}
+}
+
+func TestChecks_NoConfiguration(t *testing.T) {
+	checks := Checks(Options{
+		HTTPListenAddr: "127.0.0.1:0",
+		StoragePath:    t.TempDir(),
+	})
+
+	// The eBPF checks run, but don't report errors, as the configuration may
+	// not use eBPF components.
+	for _, f := range Run(checks) {
+		if strings.HasPrefix(f.Check, "eBPF") {
+			require.NotEqual(t, StatusSkipped, f.Status, f.Check)
+			require.NotEqual(t, StatusError, f.Status, "%s: %s", f.Check, f.Message)
+		}
+	}
+}
+
+func TestChecks_EBPFComponents(t *testing.T) {
+	if runtime.GOOS == "linux" {
+		t.Skip("the outcome of the eBPF checks depends on the host")
+	}
+
+	checks := Checks(Options{
+		HTTPListenAddr: "127.0.0.1:0",
+		StoragePath:    t.TempDir(),
+		EBPFComponents: []string{"beyla.ebpf.default"},
+	})
+	for _, f := range Run(checks) {
+		if strings.HasPrefix(f.Check, "eBPF") {
+			require.Equal(t, StatusError, f.Status)
+			require.Equal(t, "eBPF components only run on Linux. Required by beyla.ebpf.default.", f.Message)
+		}
+	}
+}
+
+func TestCheckListenAddr(t *testing.T) {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer lis.Close()
+
+	res := checkListenAddr(lis.Addr().String())
+	require.Equal(t, StatusError, res.Status)
+	require.Equal(t, lis.Addr().String()+" is already in use.", res.Message)
+
+	require.Equal(t, StatusOK, checkListenAddr("127.0.0.1:0").Status)
+}
+
+func TestCheckStoragePath(t *testing.T) {
+	dir := t.TempDir()
+	res := checkStoragePath(dir)
+	require.Equal(t, StatusOK, res.Status, res.Message)
+	require.Equal(t, dir+" is writable.", res.Message)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries, "the check must clean up after itself")
+
+	// Missing directories aren't created.
+	missing := filepath.Join(dir, "data-alloy", "nested")
+	res = checkStoragePath(missing)
+	require.Equal(t, StatusOK, res.Status, res.Message)
+	require.Equal(t, missing+" doesn't exist, and can be created in "+dir+".", res.Message)
+	require.NoDirExists(t, filepath.Join(dir, "data-alloy"))
+
+	file := filepath.Join(dir, "file")
+	require.NoError(t, os.WriteFile(file, nil, 0o644))
+	res = checkStoragePath(file)
+	require.Equal(t, StatusError, res.Status)
+	require.Equal(t, file+" isn't a directory.", res.Message)
+}
+
+func TestEvaluateFileDescriptors(t *testing.T) {
+	require.Equal(t, StatusWarning, evaluateFileDescriptors(1024).Status)
+	require.Equal(t, StatusOK, evaluateFileDescriptors(minFileDescriptors).Status)
+}
+
+func TestEvaluateKernelVersion(t *testing.T) {
+	tests := []struct {
+		release string
+		expect  Status
+	}{
+		{"6.1.0-18-amd64", StatusOK},
+		{"5.8.0", StatusOK},
+		{"5.15.0-1051-azure", StatusOK},
+		{"5.4.0-150-generic", StatusWarning},
+		{"4.18.0-513.el8.x86_64", StatusWarning},
+		{"6.6+", StatusOK},
+		{"unknown", StatusWarning},
+	}
+	for _, tc := range tests {
+		require.Equal(t, tc.expect, evaluateKernelVersion(tc.release).Status, tc.release)
+	}
+}
+
+func TestEvaluateEBPFPrivileges(t *testing.T) {
+	var (
+		sysAdmin       uint64 = 1 << capSysAdmin
+		bpfAndPerfmon  uint64 = 1<<capBPF | 1<<capPerfmon
+		bpfWithoutPerf uint64 = 1 << capBPF
+	)
+	require.Equal(t, StatusOK, evaluateEBPFPrivileges(sysAdmin, 2).Status)
+	require.Equal(t, StatusOK, evaluateEBPFPrivileges(bpfAndPerfmon, 2).Status)
+
+	res := evaluateEBPFPrivileges(bpfWithoutPerf, 2)
+	require.Equal(t, StatusError, res.Status)
+	require.Equal(t, "The process lacks the CAP_BPF and CAP_PERFMON capabilities, or CAP_SYS_ADMIN. kernel.perf_event_paranoid is 2, which restricts performance monitoring to privileged processes.", res.Message)
+
+	res = evaluateEBPFPrivileges(0, 1)
+	require.Equal(t, "The process lacks the CAP_BPF and CAP_PERFMON capabilities, or CAP_SYS_ADMIN.", res.Message)
+}
@@ -0,0 +1,19 @@
+//go:build !windows
+
+package doctor
+
+import (
+	"fmt"
+
+	"golang.org/x/sys/unix"
+)
+
+func checkFileDescriptors() Result {
+	var limit unix.Rlimit
+	if err := unix.Getrlimit(unix.RLIMIT_NOFILE, &limit); err != nil {
+		return Result{Status: StatusWarning, Message: fmt.Sprintf("Can't read the limit of open files: %s.", err)}
+	}
+	// The Go runtime raises the soft limit to the hard limit on startup, so
+	// the hard limit is the one Alloy gets.
+	return evaluateFileDescriptors(uint64(limit.Max))
+}
@@ -0,0 +1,7 @@
+//go:build windows
+
+package doctor
+
+func checkFileDescriptors() Result {
+	return Result{Status: StatusSkipped, Message: "Windows doesn't limit the number of open files."}
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding"
 	"fmt"
+	"io/fs"
+	"path/filepath"
 	"sync"
 	"time"
 
@@ -80,6 +82,9 @@ type FSNotifyOptions struct {
 	Filename      string
 	ReloadFile    func()        // Callback to request file reload.
 	PollFrequency time.Duration // How often to do fallback polling
+	// Recursive watches the subdirectories of Filename, including the ones
+	// created after the detector.
+	Recursive bool
 }
 
 // newFSNotify creates a new fsnotify detector which uses filesystem events to
@@ -89,13 +94,6 @@ func NewFSNotify(opts FSNotifyOptions) (*FSNotify, error) {
 	if err != nil {
 		return nil, err
 	}
-	if err := w.Add(opts.Filename); err != nil {
-		// It's possible that the file already got deleted by the time our fsnotify
-		// was created. We'll log the error and wait for our polling fallback for
-		// the file to be recreated.
-		level.Warn(opts.Logger).Log("msg", "failed to watch file", "err", err)
-	}
-
 	ctx, cancel := context.WithCancel(context.Background())
 
 	wd := &FSNotify{
@@ -104,6 +102,13 @@ func NewFSNotify(opts FSNotifyOptions) (*FSNotify, error) {
 		cancel:  cancel,
 	}
 
+	if err := wd.watch(); err != nil {
+		// It's possible that the file already got deleted by the time our fsnotify
+		// was created. We'll log the error and wait for our polling fallback for
+		// the file to be recreated.
+		level.Warn(opts.Logger).Log("msg", "failed to watch file", "err", err)
+	}
+
 	go wd.wait(ctx)
 	return wd, nil
 }
@@ -122,11 +127,7 @@ func (fsn *FSNotify) wait(ctx context.Context) {
 			//
 			// We'll use the poll period to re-establish the watch in case it was
 			// stopped. This is a no-op if the watch is already active.
-			fsn.watcherMut.Lock()
-			err := fsn.watcher.Add(fsn.opts.Filename)
-			fsn.watcherMut.Unlock()
-
-			if err != nil {
+			if err := fsn.watch(); err != nil {
 				level.Warn(fsn.opts.Logger).Log("msg", "failed re-watch file", "err", err)
 			}
 
@@ -145,11 +146,37 @@ func (fsn *FSNotify) wait(ctx context.Context) {
 			}
 		case ev := <-fsn.watcher.Events:
 			level.Debug(fsn.opts.Logger).Log("msg", "got fsnotify event", "op", ev.Op.String())
+			if fsn.opts.Recursive && ev.Has(fsnotify.Create) {
+				// Watch the new directories.
+				if err := fsn.watch(); err != nil {
+					level.Warn(fsn.opts.Logger).Log("msg", "failed to watch new directory", "err", err)
+				}
+			}
 			fsn.opts.ReloadFile()
 		}
 	}
 }
 
+// watch watches Filename, and its subdirectories if the detector is
+// recursive. Watching a path which is already watched is a no-op.
+func (fsn *FSNotify) watch() error {
+	fsn.watcherMut.Lock()
+	defer fsn.watcherMut.Unlock()
+
+	if err := fsn.watcher.Add(fsn.opts.Filename); err != nil || !fsn.opts.Recursive {
+		return err
+	}
+	return filepath.WalkDir(fsn.opts.Filename, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && path != fsn.opts.Filename {
+			return fsn.watcher.Add(path)
+		}
+		return nil
+	})
+}
+
 func (fsn *FSNotify) Close() error {
 	fsn.watcherMut.Lock()
 	defer fsn.watcherMut.Unlock()
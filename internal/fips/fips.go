@@ -0,0 +1,148 @@
+// Package fips checks component arguments against the TLS and authentication
+// settings approved by FIPS 140-3.
+//
+// When Alloy runs with FIPS enforcement, components whose arguments don't
+// pass Check aren't started.
+package fips
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+)
+
+// Checker is implemented by argument types which hold security settings
+// which may not be approved by FIPS 140-3.
+type Checker interface {
+	// CheckFIPS returns an error describing the settings which aren't
+	// approved.
+	CheckFIPS() error
+}
+
+var checkerType = reflect.TypeOf((*Checker)(nil)).Elem()
+
+// Check returns the errors of every Checker found in v, which is usually the
+// arguments of a component. Structs, pointers, slices, arrays, maps and
+// interfaces are walked recursively. Values which are reachable several times,
+// including through cycles, are only checked once.
+func Check(v any) error {
+	if v == nil {
+		return nil
+	}
+	c := checker{visited: make(map[visit]struct{})}
+	return c.check(reflect.ValueOf(v))
+}
+
+// visit identifies a pointer, map or slice which has been walked.
+type visit struct {
+	typ reflect.Type
+	ptr uintptr
+	len int
+}
+
+type checker struct {
+	visited map[visit]struct{}
+}
+
+// seen records v as visited, and returns true if it was already visited.
+func (c *checker) seen(v reflect.Value) bool {
+	var key visit
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Map:
+		key = visit{typ: v.Type(), ptr: v.Pointer()}
+	case reflect.Slice:
+		key = visit{typ: v.Type(), ptr: v.Pointer(), len: v.Len()}
+	default:
+		return false
+	}
+
+	if _, ok := c.visited[key]; ok {
+		return true
+	}
+	c.visited[key] = struct{}{}
+	return false
+}
+
+func (c *checker) check(v reflect.Value) error {
+	if !v.IsValid() {
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice:
+		if v.IsNil() {
+			return nil
+		}
+	}
+	if c.seen(v) {
+		return nil
+	}
+
+	if v.Type().Implements(checkerType) {
+		return v.Interface().(Checker).CheckFIPS()
+	}
+	// Checkers are usually implemented on pointer receivers, so try the
+	// address of v too.
+	if v.Kind() != reflect.Pointer && reflect.PointerTo(v.Type()).Implements(checkerType) {
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		return ptr.Interface().(Checker).CheckFIPS()
+	}
+
+	var errs []error
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		errs = append(errs, c.check(v.Elem()))
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if !v.Type().Field(i).IsExported() {
+				continue
+			}
+			errs = append(errs, c.check(v.Field(i)))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			errs = append(errs, c.check(v.Index(i)))
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			errs = append(errs, c.check(iter.Value()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// approvedCipherSuites are the TLS cipher suites approved by FIPS 140-3.
+var approvedCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_AES_128_GCM_SHA256,
+	tls.TLS_AES_256_GCM_SHA384,
+}
+
+// CheckCipherSuites returns an error if any of the named TLS cipher suites
+// isn't approved.
+func CheckCipherSuites(names []string) error {
+	var errs []error
+	for _, name := range names {
+		if !slices.ContainsFunc(approvedCipherSuites, func(id uint16) bool { return tls.CipherSuiteName(id) == name }) {
+			errs = append(errs, fmt.Errorf("cipher suite %s is not FIPS approved", name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// CheckTLSVersion returns an error if version, one of the tls.VersionTLS
+// constants, is lower than TLS 1.2. A version of 0 uses the default minimum
+// version of Go, TLS 1.2, and is accepted.
+func CheckTLSVersion(version uint16) error {
+	if version != 0 && version < tls.VersionTLS12 {
+		return fmt.Errorf("TLS version %s is not FIPS approved, the minimum version is TLS 1.2", tls.VersionName(version))
+	}
+	return nil
+}
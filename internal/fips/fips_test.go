@@ -0,0 +1,97 @@
+package fips
+
+import (
+	"crypto/tls"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type valueChecker struct{ err error }
+
+func (c valueChecker) CheckFIPS() error { return c.err }
+
+type pointerChecker struct{ err error }
+
+func (c *pointerChecker) CheckFIPS() error { return c.err }
+
+type arguments struct {
+	Value    valueChecker
+	Pointer  *pointerChecker
+	Embedded pointerChecker
+	List     []pointerChecker
+	Map      map[string]any
+	Nil      *pointerChecker
+	Any      any
+
+	unexported valueChecker
+}
+
+func TestCheck(t *testing.T) {
+	var (
+		errValue    = errors.New("value")
+		errPointer  = errors.New("pointer")
+		errEmbedded = errors.New("embedded")
+		errList     = errors.New("list")
+		errMap      = errors.New("map")
+		errAny      = errors.New("any")
+	)
+
+	args := arguments{
+		Value:    valueChecker{errValue},
+		Pointer:  &pointerChecker{errPointer},
+		Embedded: pointerChecker{errEmbedded},
+		List:     []pointerChecker{{nil}, {errList}},
+		Map:      map[string]any{"key": valueChecker{errMap}},
+		Any:      &valueChecker{errAny},
+
+		unexported: valueChecker{errors.New("unexported")},
+	}
+
+	err := Check(args)
+	for _, expect := range []error{errValue, errPointer, errEmbedded, errList, errMap, errAny} {
+		require.ErrorIs(t, err, expect)
+	}
+	require.NotContains(t, err.Error(), "unexported")
+
+	require.NoError(t, Check(nil))
+	require.NoError(t, Check(arguments{}))
+	require.NoError(t, Check(&arguments{}))
+}
+
+type node struct {
+	Checker *pointerChecker
+	Next    *node
+	Values  map[string]any
+}
+
+func TestCheck_Cycles(t *testing.T) {
+	errCycle := errors.New("cycle")
+
+	shared := &pointerChecker{errCycle}
+	a := &node{Checker: shared, Values: map[string]any{}}
+	b := &node{Checker: shared, Next: a}
+	a.Next = b
+	a.Values["self"] = a.Values
+
+	err := Check(a)
+	require.ErrorIs(t, err, errCycle)
+	// The shared checker is only checked once.
+	require.Equal(t, errCycle.Error(), err.Error())
+}
+
+func TestCheckCipherSuites(t *testing.T) {
+	require.NoError(t, CheckCipherSuites(nil))
+	require.NoError(t, CheckCipherSuites([]string{"TLS_AES_128_GCM_SHA256", "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384"}))
+
+	err := CheckCipherSuites([]string{"TLS_AES_128_GCM_SHA256", "TLS_CHACHA20_POLY1305_SHA256", "TLS_RSA_WITH_RC4_128_SHA"})
+	require.EqualError(t, err, "cipher suite TLS_CHACHA20_POLY1305_SHA256 is not FIPS approved\ncipher suite TLS_RSA_WITH_RC4_128_SHA is not FIPS approved")
+}
+
+func TestCheckTLSVersion(t *testing.T) {
+	require.NoError(t, CheckTLSVersion(0))
+	require.NoError(t, CheckTLSVersion(tls.VersionTLS12))
+	require.NoError(t, CheckTLSVersion(tls.VersionTLS13))
+	require.EqualError(t, CheckTLSVersion(tls.VersionTLS11), "TLS version TLS 1.1 is not FIPS approved, the minimum version is TLS 1.2")
+}
@@ -0,0 +1,86 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	"gopkg.in/yaml.v3"
+)
+
+const alertmanagerAPIPath = "/api/v1/alerts"
+
+// AlertmanagerConfig is the Alertmanager configuration of a tenant, along
+// with the templates it references.
+type AlertmanagerConfig struct {
+	TemplateFiles      map[string]string `yaml:"template_files"`
+	AlertmanagerConfig string            `yaml:"alertmanager_config"`
+}
+
+// AlertmanagerInterface manages the Alertmanager configuration of tenants.
+type AlertmanagerInterface interface {
+	GetAlertmanagerConfig(ctx context.Context, tenant string) (*AlertmanagerConfig, error)
+	CreateAlertmanagerConfig(ctx context.Context, tenant string, cfg AlertmanagerConfig) error
+	DeleteAlertmanagerConfig(ctx context.Context, tenant string) error
+}
+
+var _ AlertmanagerInterface = (*MimirClient)(nil)
+
+// GetAlertmanagerConfig retrieves the Alertmanager configuration of a
+// tenant. It returns nil if the tenant has no configuration.
+func (r *MimirClient) GetAlertmanagerConfig(ctx context.Context, tenant string) (*AlertmanagerConfig, error) {
+	res, err := r.sendTenantRequest(alertmanagerAPIPath, alertmanagerAPIPath, "GET", tenant, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err := checkResponse(res); err != nil {
+		return nil, fmt.Errorf("error GET %s: %w", alertmanagerAPIPath, err)
+	}
+
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg AlertmanagerConfig
+	if err := yaml.Unmarshal(body, &cfg); err != nil {
+		return nil, err
+	}
+	return &cfg, nil
+}
+
+// CreateAlertmanagerConfig creates or replaces the Alertmanager configuration
+// of a tenant.
+func (r *MimirClient) CreateAlertmanagerConfig(ctx context.Context, tenant string, cfg AlertmanagerConfig) error {
+	payload, err := yaml.Marshal(&cfg)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.doTenantRequest(alertmanagerAPIPath, alertmanagerAPIPath, "POST", tenant, payload)
+	if err != nil {
+		return err
+	}
+
+	res.Body.Close()
+
+	return nil
+}
+
+// DeleteAlertmanagerConfig deletes the Alertmanager configuration of a tenant.
+func (r *MimirClient) DeleteAlertmanagerConfig(ctx context.Context, tenant string) error {
+	res, err := r.doTenantRequest(alertmanagerAPIPath, alertmanagerAPIPath, "DELETE", tenant, nil)
+	if err != nil {
+		return err
+	}
+
+	res.Body.Close()
+
+	return nil
+}
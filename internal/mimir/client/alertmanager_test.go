@@ -0,0 +1,77 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/instrument"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMimirClient_AlertmanagerConfig(t *testing.T) {
+	var (
+		mut     sync.Mutex
+		configs = map[string]string{}
+	)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/api/v1/alerts", r.URL.Path)
+		tenant := r.Header.Get("X-Scope-OrgID")
+
+		mut.Lock()
+		defer mut.Unlock()
+		switch r.Method {
+		case http.MethodGet:
+			cfg, ok := configs[tenant]
+			if !ok {
+				http.Error(w, "alertmanager storage object not found", http.StatusNotFound)
+				return
+			}
+			_, _ = io.WriteString(w, cfg)
+		case http.MethodPost:
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			configs[tenant] = string(body)
+			w.WriteHeader(http.StatusCreated)
+		case http.MethodDelete:
+			delete(configs, tenant)
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer ts.Close()
+
+	client, err := New(log.NewNopLogger(), Config{
+		ID:      "default",
+		Address: ts.URL,
+	}, prometheus.NewHistogramVec(prometheus.HistogramOpts{}, instrument.HistogramCollectorBuckets))
+	require.NoError(t, err)
+
+	ctx := t.Context()
+	cfg, err := client.GetAlertmanagerConfig(ctx, "tenant-a")
+	require.NoError(t, err)
+	require.Nil(t, cfg)
+
+	expected := AlertmanagerConfig{
+		TemplateFiles:      map[string]string{"default.tmpl": `{{ define "title" }}alert{{ end }}`},
+		AlertmanagerConfig: "route:\n  receiver: default\nreceivers:\n  - name: default\n",
+	}
+	require.NoError(t, client.CreateAlertmanagerConfig(ctx, "tenant-a", expected))
+
+	cfg, err = client.GetAlertmanagerConfig(ctx, "tenant-a")
+	require.NoError(t, err)
+	require.Equal(t, &expected, cfg)
+
+	// The configuration of other tenants is left as is.
+	cfg, err = client.GetAlertmanagerConfig(ctx, "default")
+	require.NoError(t, err)
+	require.Nil(t, cfg)
+
+	require.NoError(t, client.DeleteAlertmanagerConfig(ctx, "tenant-a"))
+	cfg, err = client.GetAlertmanagerConfig(ctx, "tenant-a")
+	require.NoError(t, err)
+	require.Nil(t, cfg)
+}
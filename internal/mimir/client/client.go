@@ -87,26 +87,38 @@ func New(logger log.Logger, cfg Config, timingHistogram *prometheus.HistogramVec
 }
 
 func (r *MimirClient) doRequest(operation, path, method string, payload []byte) (*http.Response, error) {
-	req, err := buildRequest(operation, path, method, *r.endpoint, payload)
+	return r.doTenantRequest(operation, path, method, r.id, payload)
+}
+
+// doTenantRequest sends a request on behalf of a tenant, which can differ
+// from the tenant of the client.
+func (r *MimirClient) doTenantRequest(operation, path, method, tenant string, payload []byte) (*http.Response, error) {
+	resp, err := r.sendTenantRequest(operation, path, method, tenant, payload)
 	if err != nil {
 		return nil, err
 	}
 
-	if r.id != "" {
-		req.Header.Add(user.OrgIDHeaderName, r.id)
+	if err := checkResponse(resp); err != nil {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("error %s %s: %w", method, path, err)
 	}
 
-	resp, err := r.client.Do(req)
+	return resp, nil
+}
+
+// sendTenantRequest sends a request on behalf of a tenant without checking
+// the response for errors.
+func (r *MimirClient) sendTenantRequest(operation, path, method, tenant string, payload []byte) (*http.Response, error) {
+	req, err := buildRequest(operation, path, method, *r.endpoint, payload)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := checkResponse(resp); err != nil {
-		_ = resp.Body.Close()
-		return nil, fmt.Errorf("error %s %s: %w", method, path, err)
+	if tenant != "" {
+		req.Header.Add(user.OrgIDHeaderName, tenant)
 	}
 
-	return resp, nil
+	return r.client.Do(req)
 }
 
 // checkResponse checks an API response for errors.
@@ -0,0 +1,110 @@
+package moduleregistry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Cache stores the versions of a module fetched from a registry on disk.
+// Versions are immutable, so cached versions are never refetched.
+type Cache struct {
+	dir string
+}
+
+// CacheDir returns the directory in root where the versions of the module
+// with the given lock key are cached.
+func CacheDir(root, key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(root, hex.EncodeToString(sum[:8]))
+}
+
+// NewCache returns a cache storing versions in dir.
+func NewCache(dir string) *Cache {
+	return &Cache{dir: dir}
+}
+
+// Path returns the directory where version is stored.
+func (c *Cache) Path(version string) string {
+	return filepath.Join(c.dir, version)
+}
+
+// Get returns the files of version, or false if version isn't cached.
+func (c *Cache) Get(version string) (map[string]string, bool, error) {
+	if err := validateVersion(version); err != nil {
+		return nil, false, err
+	}
+	entries, err := os.ReadDir(c.Path(version))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, err
+	}
+
+	files := make(map[string]string, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || validateFileName(e.Name()) != nil {
+			continue
+		}
+		bb, err := os.ReadFile(filepath.Join(c.Path(version), e.Name()))
+		if err != nil {
+			return nil, false, err
+		}
+		files[e.Name()] = string(bb)
+	}
+	return files, true, nil
+}
+
+// Put stores the files of version. The files of a version are written
+// atomically, so a version is never partially cached.
+func (c *Cache) Put(version string, files map[string]string) error {
+	if err := validateVersion(version); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.MkdirTemp(c.dir, ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	for name, content := range files {
+		if err := validateFileName(name); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(tmp, name), []byte(content), 0o640); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(tmp, c.Path(version)); err != nil {
+		// The version may have been cached concurrently.
+		if _, statErr := os.Stat(c.Path(version)); statErr == nil {
+			return nil
+		}
+		return fmt.Errorf("caching version %s: %w", version, err)
+	}
+	return nil
+}
+
+// Versions returns the cached versions.
+func (c *Cache) Versions() ([]string, error) {
+	entries, err := os.ReadDir(c.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var versions []string
+	for _, e := range entries {
+		if e.IsDir() && validateVersion(e.Name()) == nil {
+			versions = append(versions, e.Name())
+		}
+	}
+	return versions, nil
+}
@@ -0,0 +1,84 @@
+package moduleregistry
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// httpIndex is a registry served by an HTTP server. The versions of a module
+// are listed in <url>/<module>/index.json, and the files of a version are
+// served at <url>/<module>/<version>/<file>.
+type httpIndex struct {
+	url *url.URL
+	cli *http.Client
+}
+
+// index is the index.json of a module.
+type index struct {
+	Versions []struct {
+		Version string   `json:"version"`
+		Files   []string `json:"files"`
+	} `json:"versions"`
+}
+
+func (r *httpIndex) index(ctx context.Context, module string) (*index, error) {
+	bb, err := get(ctx, r.cli, r.url.JoinPath(module, "index.json").String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("fetching the index of module %q: %w", module, err)
+	}
+	var idx index
+	if err := json.Unmarshal(bb, &idx); err != nil {
+		return nil, fmt.Errorf("parsing the index of module %q: %w", module, err)
+	}
+	return &idx, nil
+}
+
+// Versions implements Registry.
+func (r *httpIndex) Versions(ctx context.Context, module string) ([]string, error) {
+	idx, err := r.index(ctx, module)
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]string, 0, len(idx.Versions))
+	for _, v := range idx.Versions {
+		versions = append(versions, v.Version)
+	}
+	return versions, nil
+}
+
+// Fetch implements Registry.
+func (r *httpIndex) Fetch(ctx context.Context, module, version string) (map[string]string, error) {
+	if err := validateVersion(version); err != nil {
+		return nil, err
+	}
+	idx, err := r.index(ctx, module)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range idx.Versions {
+		if v.Version != version {
+			continue
+		}
+		if len(v.Files) == 0 {
+			return nil, fmt.Errorf("version %s of module %q has no files", version, module)
+		}
+
+		files := make(map[string]string, len(v.Files))
+		for _, name := range v.Files {
+			if err := validateFileName(name); err != nil {
+				return nil, err
+			}
+			bb, err := get(ctx, r.cli, r.url.JoinPath(module, version, name).String(), nil)
+			if err != nil {
+				return nil, fmt.Errorf("fetching file %q of version %s of module %q: %w", name, version, module, err)
+			}
+			files[name] = string(bb)
+		}
+		return files, nil
+	}
+	return nil, fmt.Errorf("module %q has no version %s", module, version)
+}
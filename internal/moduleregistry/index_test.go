@@ -0,0 +1,49 @@
+package moduleregistry
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPIndex(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/modules/grafana/k8s/index.json", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"versions": [
+			{"version": "2.0.0", "files": ["main.alloy", "extra.alloy"]},
+			{"version": "2.1.0", "files": ["../main.alloy"]}
+		]}`))
+	})
+	mux.HandleFunc("/modules/grafana/k8s/2.0.0/main.alloy", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`declare "main" {}`))
+	})
+	mux.HandleFunc("/modules/grafana/k8s/2.0.0/extra.alloy", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`declare "extra" {}`))
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	r, err := New(srv.URL+"/modules", srv.Client())
+	require.NoError(t, err)
+
+	versions, err := r.Versions(t.Context(), "grafana/k8s")
+	require.NoError(t, err)
+	require.Equal(t, []string{"2.0.0", "2.1.0"}, versions)
+
+	files, err := r.Fetch(t.Context(), "grafana/k8s", "2.0.0")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"main.alloy":  `declare "main" {}`,
+		"extra.alloy": `declare "extra" {}`,
+	}, files)
+
+	_, err = r.Fetch(t.Context(), "grafana/k8s", "2.1.0")
+	require.EqualError(t, err, `invalid module file name "../main.alloy"`)
+	_, err = r.Fetch(t.Context(), "grafana/k8s", "2.2.0")
+	require.EqualError(t, err, `module "grafana/k8s" has no version 2.2.0`)
+
+	_, err = r.Versions(t.Context(), "grafana/unknown")
+	require.ErrorContains(t, err, "unexpected status code 404 Not Found")
+}
@@ -0,0 +1,107 @@
+package moduleregistry
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Lockfile records the versions of the modules resolved from registries, so
+// that the same versions are imported until the lockfile is changed.
+type Lockfile struct {
+	Modules map[string]LockedModule `json:"modules"`
+}
+
+// LockedModule is the version of a module recorded in a lockfile.
+type LockedModule struct {
+	// Constraint is the version constraint the version was resolved from. The
+	// version is resolved again when the constraint changes.
+	Constraint string `json:"constraint"`
+	Version    string `json:"version"`
+	// Digest is the digest of the files of the version, which must not change.
+	Digest string `json:"digest"`
+}
+
+// LockKey returns the key of module of the registry at registryURL in a
+// lockfile.
+func LockKey(registryURL, module string) string {
+	return strings.TrimSuffix(registryURL, "/") + "/" + module
+}
+
+// lockfileMut serializes the updates of lockfiles, which are shared by the
+// imports of a configuration.
+var lockfileMut sync.Mutex
+
+// ReadLockfile reads the lockfile at path. An empty lockfile is returned if
+// the file doesn't exist.
+func ReadLockfile(path string) (*Lockfile, error) {
+	lockfileMut.Lock()
+	defer lockfileMut.Unlock()
+	return readLockfile(path)
+}
+
+func readLockfile(path string) (*Lockfile, error) {
+	lf := &Lockfile{Modules: make(map[string]LockedModule)}
+
+	bb, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return lf, nil
+	} else if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(bb, lf); err != nil {
+		return nil, fmt.Errorf("parsing lockfile %q: %w", path, err)
+	}
+	if lf.Modules == nil {
+		lf.Modules = make(map[string]LockedModule)
+	}
+	return lf, nil
+}
+
+// Lock records the version of the module with the given key in the lockfile
+// at path, which is created if it doesn't exist.
+func Lock(path, key string, m LockedModule) error {
+	lockfileMut.Lock()
+	defer lockfileMut.Unlock()
+
+	lf, err := readLockfile(path)
+	if err != nil {
+		return err
+	}
+	if lf.Modules[key] == m {
+		return nil
+	}
+	lf.Modules[key] = m
+
+	// Don't escape constraints like ~> 2.0, so the lockfile can be reviewed.
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(lf); err != nil {
+		return err
+	}
+
+	// Replace the lockfile atomically so it's never read partially written.
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
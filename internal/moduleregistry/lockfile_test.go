@@ -0,0 +1,73 @@
+package moduleregistry
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "alloy.lock")
+
+	lf, err := ReadLockfile(path)
+	require.NoError(t, err)
+	require.Empty(t, lf.Modules)
+
+	k8s := LockedModule{Constraint: "~> 2.0", Version: "2.0.3", Digest: "sha256:k8s"}
+	node := LockedModule{Constraint: "*", Version: "v1.0.0", Digest: "sha256:node"}
+	require.NoError(t, Lock(path, LockKey("oci://ghcr.io/grafana/", "k8s"), k8s))
+	require.NoError(t, Lock(path, LockKey("https://modules.example.com", "node"), node))
+
+	lf, err = ReadLockfile(path)
+	require.NoError(t, err)
+	require.Equal(t, map[string]LockedModule{
+		"oci://ghcr.io/grafana/k8s":        k8s,
+		"https://modules.example.com/node": node,
+	}, lf.Modules)
+
+	bb, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Equal(t, `{
+  "modules": {
+    "https://modules.example.com/node": {
+      "constraint": "*",
+      "version": "v1.0.0",
+      "digest": "sha256:node"
+    },
+    "oci://ghcr.io/grafana/k8s": {
+      "constraint": "~> 2.0",
+      "version": "2.0.3",
+      "digest": "sha256:k8s"
+    }
+  }
+}
+`, string(bb))
+}
+
+func TestCache(t *testing.T) {
+	c := NewCache(CacheDir(t.TempDir(), "oci://ghcr.io/grafana/k8s"))
+
+	versions, err := c.Versions()
+	require.NoError(t, err)
+	require.Empty(t, versions)
+
+	_, ok, err := c.Get("2.0.0")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	files := map[string]string{"main.alloy": `declare "main" {}`}
+	require.NoError(t, c.Put("2.0.0", files))
+	require.NoError(t, c.Put("2.0.0", files))
+	require.Error(t, c.Put("2.1.0", map[string]string{"../main.alloy": ""}))
+
+	cached, ok, err := c.Get("2.0.0")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.Equal(t, files, cached)
+
+	versions, err = c.Versions()
+	require.NoError(t, err)
+	require.Equal(t, []string{"2.0.0"}, versions)
+}
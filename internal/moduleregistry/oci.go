@@ -0,0 +1,246 @@
+package moduleregistry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+)
+
+// Media type and annotation of the manifests of modules in OCI registries.
+const (
+	ociManifestMediaType = "application/vnd.oci.image.manifest.v1+json"
+	ociTitleAnnotation   = "org.opencontainers.image.title"
+)
+
+// ociRegistry is an OCI registry. The versions of a module are the tags of
+// the repository of the module, and its files are the layers of the manifest
+// of each tag which are titled with the name of the file, like the files
+// pushed by oras.
+type ociRegistry struct {
+	base   *url.URL // Base URL of the API of the registry.
+	prefix string   // Prefix of the repositories of the modules.
+	cli    *http.Client
+
+	mut    sync.Mutex
+	tokens map[string]string // Bearer tokens by repository.
+}
+
+func newOCIRegistry(u *url.URL, cli *http.Client) *ociRegistry {
+	return &ociRegistry{
+		base:   &url.URL{Scheme: "https", Host: u.Host},
+		prefix: strings.Trim(u.Path, "/"),
+		cli:    cli,
+		tokens: make(map[string]string),
+	}
+}
+
+func (r *ociRegistry) repository(module string) string {
+	return path.Join(r.prefix, module)
+}
+
+// Versions implements Registry.
+func (r *ociRegistry) Versions(ctx context.Context, module string) ([]string, error) {
+	repo := r.repository(module)
+	bb, err := r.get(ctx, repo, "tags/list", "")
+	if err != nil {
+		return nil, fmt.Errorf("listing the tags of %q: %w", repo, err)
+	}
+	var tags struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.Unmarshal(bb, &tags); err != nil {
+		return nil, fmt.Errorf("parsing the tags of %q: %w", repo, err)
+	}
+	return tags.Tags, nil
+}
+
+// Fetch implements Registry.
+func (r *ociRegistry) Fetch(ctx context.Context, module, version string) (map[string]string, error) {
+	if err := validateVersion(version); err != nil {
+		return nil, err
+	}
+
+	repo := r.repository(module)
+	bb, err := r.get(ctx, repo, "manifests/"+version, ociManifestMediaType)
+	if err != nil {
+		return nil, fmt.Errorf("fetching the manifest of %s:%s: %w", repo, version, err)
+	}
+	var manifest struct {
+		Layers []struct {
+			Digest      string            `json:"digest"`
+			Annotations map[string]string `json:"annotations"`
+		} `json:"layers"`
+	}
+	if err := json.Unmarshal(bb, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing the manifest of %s:%s: %w", repo, version, err)
+	}
+
+	files := make(map[string]string)
+	for _, layer := range manifest.Layers {
+		name, ok := layer.Annotations[ociTitleAnnotation]
+		if !ok {
+			continue
+		}
+		if err := validateFileName(name); err != nil {
+			return nil, err
+		}
+		content, err := r.blob(ctx, repo, layer.Digest)
+		if err != nil {
+			return nil, fmt.Errorf("fetching file %q of %s:%s: %w", name, repo, version, err)
+		}
+		files[name] = string(content)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("%s:%s has no module files", repo, version)
+	}
+	return files, nil
+}
+
+// blob fetches the blob of repo with the given digest, and verifies its
+// digest.
+func (r *ociRegistry) blob(ctx context.Context, repo, digest string) ([]byte, error) {
+	expected, ok := strings.CutPrefix(digest, "sha256:")
+	if !ok {
+		return nil, fmt.Errorf("unsupported digest %q", digest)
+	}
+	bb, err := r.get(ctx, repo, "blobs/"+digest, "")
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(bb)
+	if hex.EncodeToString(sum[:]) != expected {
+		return nil, fmt.Errorf("blob doesn't match its digest %q", digest)
+	}
+	return bb, nil
+}
+
+// get performs a GET request to an endpoint of the API for repo. If the
+// registry requires a token, get requests one and retries.
+func (r *ociRegistry) get(ctx context.Context, repo, endpoint, accept string) ([]byte, error) {
+	u := r.base.JoinPath("v2", repo, endpoint).String()
+
+	for authenticated := false; ; authenticated = true {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+		r.mut.Lock()
+		token := r.tokens[repo]
+		r.mut.Unlock()
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
+		resp, err := r.cli.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		bb, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case resp.StatusCode == http.StatusOK:
+			return bb, nil
+		case resp.StatusCode == http.StatusUnauthorized && !authenticated:
+			if err := r.authenticate(ctx, repo, resp.Header.Get("WWW-Authenticate")); err != nil {
+				return nil, fmt.Errorf("authenticating to the registry: %w", err)
+			}
+		default:
+			return nil, StatusError{URL: u, Status: resp.Status, StatusCode: resp.StatusCode}
+		}
+	}
+}
+
+// authenticate requests a token to pull repo from the authorization server
+// of the bearer challenge of the registry.
+func (r *ociRegistry) authenticate(ctx context.Context, repo, challenge string) error {
+	scheme, params := parseChallenge(challenge)
+	if !strings.EqualFold(scheme, "Bearer") || params["realm"] == "" {
+		return errors.New("the registry requires credentials")
+	}
+
+	realm, err := url.Parse(params["realm"])
+	if err != nil {
+		return fmt.Errorf("invalid realm %q: %w", params["realm"], err)
+	}
+	query := realm.Query()
+	if service := params["service"]; service != "" {
+		query.Set("service", service)
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = "repository:" + repo + ":pull"
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	bb, err := get(ctx, r.cli, realm.String(), nil)
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.Unmarshal(bb, &resp); err != nil {
+		return fmt.Errorf("parsing token: %w", err)
+	}
+	token := resp.Token
+	if token == "" {
+		token = resp.AccessToken
+	}
+	if token == "" {
+		return errors.New("the authorization server returned no token")
+	}
+
+	r.mut.Lock()
+	r.tokens[repo] = token
+	r.mut.Unlock()
+	return nil
+}
+
+// parseChallenge parses the scheme and the parameters of a WWW-Authenticate
+// header like `Bearer realm="https://auth.example.com/token",service="example"`.
+func parseChallenge(challenge string) (string, map[string]string) {
+	scheme, rest, _ := strings.Cut(strings.TrimSpace(challenge), " ")
+	params := make(map[string]string)
+
+	for rest = strings.TrimSpace(rest); rest != ""; {
+		key, value, ok := strings.Cut(rest, "=")
+		if !ok {
+			break
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		if strings.HasPrefix(value, `"`) {
+			end := strings.Index(value[1:], `"`)
+			if end < 0 {
+				break
+			}
+			params[key] = value[1 : end+1]
+			rest = value[end+2:]
+		} else {
+			v, r, _ := strings.Cut(value, ",")
+			params[key] = strings.TrimSpace(v)
+			rest = "," + r
+		}
+		rest = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(rest), ","))
+	}
+	return scheme, params
+}
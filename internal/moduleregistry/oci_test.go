@@ -0,0 +1,95 @@
+package moduleregistry
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOCIRegistry(t *testing.T) {
+	const content = `declare "main" {}`
+	sum := sha256.Sum256([]byte(content))
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	var tokens atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "registry.test", r.URL.Query().Get("service"))
+		require.Equal(t, "repository:alloy/grafana/k8s:pull", r.URL.Query().Get("scope"))
+		tokens.Add(1)
+		_, _ = w.Write([]byte(`{"token": "secret"}`))
+	})
+	mux.HandleFunc("/v2/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="https://%s/token",service="registry.test"`, r.Host))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch strings.TrimPrefix(r.URL.Path, "/v2/alloy/grafana/k8s/") {
+		case "tags/list":
+			_, _ = w.Write([]byte(`{"name": "alloy/grafana/k8s", "tags": ["v2.0.0", "latest"]}`))
+		case "manifests/v2.0.0":
+			require.Equal(t, ociManifestMediaType, r.Header.Get("Accept"))
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"schemaVersion": 2,
+				"mediaType":     ociManifestMediaType,
+				"layers": []map[string]any{
+					{"digest": digest, "annotations": map[string]string{ociTitleAnnotation: "main.alloy"}},
+					{"digest": "sha256:0000", "mediaType": "application/octet-stream"},
+				},
+			})
+		case "manifests/v2.1.0":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"layers": []map[string]any{
+					{"digest": "sha256:0000", "annotations": map[string]string{ociTitleAnnotation: "main.alloy"}},
+				},
+			})
+		case "blobs/" + digest, "blobs/sha256:0000":
+			_, _ = w.Write([]byte(content))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	srv := httptest.NewTLSServer(mux)
+	defer srv.Close()
+
+	r, err := New("oci://"+srv.Listener.Addr().String()+"/alloy", srv.Client())
+	require.NoError(t, err)
+
+	versions, err := r.Versions(t.Context(), "grafana/k8s")
+	require.NoError(t, err)
+	require.Equal(t, []string{"v2.0.0", "latest"}, versions)
+
+	files, err := r.Fetch(t.Context(), "grafana/k8s", "v2.0.0")
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"main.alloy": content}, files)
+
+	_, err = r.Fetch(t.Context(), "grafana/k8s", "v2.1.0")
+	require.ErrorContains(t, err, `blob doesn't match its digest "sha256:0000"`)
+
+	// The token is reused across requests.
+	require.Equal(t, int32(1), tokens.Load())
+}
+
+func TestParseChallenge(t *testing.T) {
+	scheme, params := parseChallenge(`Bearer realm="https://auth.example.com/token",service="registry.example.com", scope="repository:a/b:pull,push"`)
+	require.Equal(t, "Bearer", scheme)
+	require.Equal(t, map[string]string{
+		"realm":   "https://auth.example.com/token",
+		"service": "registry.example.com",
+		"scope":   "repository:a/b:pull,push",
+	}, params)
+
+	scheme, params = parseChallenge(`Basic realm=registry`)
+	require.Equal(t, "Basic", scheme)
+	require.Equal(t, map[string]string{"realm": "registry"}, params)
+}
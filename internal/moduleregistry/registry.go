@@ -0,0 +1,153 @@
+// Package moduleregistry retrieves versioned modules from module registries,
+// which are either OCI registries or HTTP servers serving an index of the
+// versions of each module.
+package moduleregistry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+)
+
+// Registry lists and fetches the versions of modules.
+type Registry interface {
+	// Versions returns the versions of module, as named by the registry.
+	Versions(ctx context.Context, module string) ([]string, error)
+	// Fetch returns the files of a version of module, keyed by file name.
+	Fetch(ctx context.Context, module, version string) (map[string]string, error)
+}
+
+// OCIScheme is the scheme of the URLs of OCI registries.
+const OCIScheme = "oci"
+
+// New returns the registry at rawURL, which is an OCI registry if its scheme
+// is oci, and an HTTP index otherwise. Requests are made with cli.
+func New(rawURL string, cli *http.Client) (Registry, error) {
+	u, err := ParseURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme == OCIScheme {
+		return newOCIRegistry(u, cli), nil
+	}
+	return &httpIndex{url: u, cli: cli}, nil
+}
+
+// ParseURL parses the URL of a registry.
+func ParseURL(rawURL string) (*url.URL, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case OCIScheme, "http", "https":
+	default:
+		return nil, fmt.Errorf("unsupported registry URL scheme %q, must be one of oci, http, or https", u.Scheme)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("registry URL %q has no host", rawURL)
+	}
+	return u, nil
+}
+
+// Resolve returns the highest version of versions which satisfies constraint.
+// Versions which aren't semantic versions are ignored.
+func Resolve(versions []string, constraint *semver.Constraints) (string, error) {
+	var (
+		best     *semver.Version
+		resolved string
+	)
+	for _, v := range versions {
+		sv, err := semver.NewVersion(v)
+		if err != nil || !constraint.Check(sv) {
+			continue
+		}
+		if best == nil || sv.GreaterThan(best) {
+			best, resolved = sv, v
+		}
+	}
+	if best == nil {
+		return "", fmt.Errorf("no version satisfies %q", constraint)
+	}
+	return resolved, nil
+}
+
+// Digest returns the digest of the files of a module.
+func Digest(files map[string]string) string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		// Names can't contain NUL bytes, which separates them from contents.
+		fmt.Fprintf(h, "%s\x00%d\x00%s", name, len(files[name]), files[name])
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil))
+}
+
+// validateFileName checks that name is the name of a module file, which
+// can be stored in a directory.
+func validateFileName(name string) error {
+	if name == "" || name != path.Base(name) || strings.ContainsAny(name, "\\\x00") || name == "." || name == ".." {
+		return fmt.Errorf("invalid module file name %q", name)
+	}
+	if !strings.HasSuffix(name, ".alloy") {
+		return fmt.Errorf("module file %q doesn't have the .alloy extension", name)
+	}
+	return nil
+}
+
+// validateVersion checks that version can be used in URLs and file names.
+func validateVersion(version string) error {
+	if _, err := semver.NewVersion(version); err != nil {
+		return fmt.Errorf("invalid version %q: %w", version, err)
+	}
+	return nil
+}
+
+// get performs a GET request and returns the body of the response.
+func get(ctx context.Context, cli *http.Client, u string, header http.Header) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+
+	resp, err := cli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, StatusError{URL: u, Status: resp.Status, StatusCode: resp.StatusCode}
+	}
+	return io.ReadAll(resp.Body)
+}
+
+// StatusError is returned when a registry responds with an unexpected
+// status code.
+type StatusError struct {
+	URL        string
+	Status     string
+	StatusCode int
+}
+
+// Error returns the error string, denoting the URL and status.
+func (err StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %s from %s", err.Status, err.URL)
+}
@@ -0,0 +1,68 @@
+package moduleregistry
+
+import (
+	"testing"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolve(t *testing.T) {
+	versions := []string{"1.9.0", "v2.0.0", "2.0.3", "2.1.0", "3.0.0-rc.1", "latest"}
+
+	tt := []struct {
+		constraint string
+		expect     string
+		expectErr  string
+	}{
+		{constraint: "*", expect: "2.1.0"},
+		{constraint: "~> 2.0", expect: "2.0.3"},
+		{constraint: "^2.0", expect: "2.1.0"},
+		{constraint: "< 2", expect: "1.9.0"},
+		{constraint: "2.0.0", expect: "v2.0.0"},
+		{constraint: ">= 3.0.0-0", expect: "3.0.0-rc.1"},
+		{constraint: "~> 4.0", expectErr: `no version satisfies "~>4.0"`},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.constraint, func(t *testing.T) {
+			c, err := semver.NewConstraint(tc.constraint)
+			require.NoError(t, err)
+
+			actual, err := Resolve(versions, c)
+			if tc.expectErr != "" {
+				require.EqualError(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, actual)
+		})
+	}
+}
+
+func TestDigest(t *testing.T) {
+	files := map[string]string{"a.alloy": "a", "b.alloy": "b"}
+	require.Equal(t, Digest(files), Digest(map[string]string{"b.alloy": "b", "a.alloy": "a"}))
+	require.NotEqual(t, Digest(files), Digest(map[string]string{"a.alloy": "ab", "b.alloy": ""}))
+	require.NotEqual(t, Digest(files), Digest(map[string]string{"a.alloy": "a"}))
+}
+
+func TestParseURL(t *testing.T) {
+	for _, u := range []string{"oci://ghcr.io/grafana", "https://modules.example.com/index", "http://localhost:8080"} {
+		_, err := ParseURL(u)
+		require.NoError(t, err, u)
+	}
+
+	_, err := ParseURL("git://github.com/grafana/modules")
+	require.EqualError(t, err, `unsupported registry URL scheme "git", must be one of oci, http, or https`)
+	_, err = ParseURL("oci:///modules")
+	require.EqualError(t, err, `registry URL "oci:///modules" has no host`)
+}
+
+func TestValidateFileName(t *testing.T) {
+	require.NoError(t, validateFileName("module.alloy"))
+	require.Error(t, validateFileName("../module.alloy"))
+	require.Error(t, validateFileName("dir/module.alloy"))
+	require.Error(t, validateFileName(`dir\module.alloy`))
+	require.Error(t, validateFileName("module.txt"))
+}
@@ -0,0 +1,49 @@
+package pipelinetest
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// checkCount returns an error if matched doesn't satisfy the expected count.
+// If count is nil, at least one item must match.
+func checkCount(count *int, matched int) error {
+	switch {
+	case count == nil && matched == 0:
+		return errors.New("expected at least one match, got none")
+	case count != nil && *count != matched:
+		return fmt.Errorf("expected %d matches, got %d", *count, matched)
+	default:
+		return nil
+	}
+}
+
+func validateCount(count *int) error {
+	if count != nil && *count < 0 {
+		return errors.New("count must not be negative")
+	}
+	return nil
+}
+
+// matchLabels returns true if every label in expected has the same value as
+// returned by get.
+func matchLabels(expected map[string]string, get func(name string) (string, bool)) bool {
+	for name, value := range expected {
+		if v, ok := get(name); !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// formatLabels formats labels for error messages.
+func formatLabels(labels map[string]string) string {
+	pairs := make([]string, 0, len(labels))
+	for _, name := range slices.Sorted(maps.Keys(labels)) {
+		pairs = append(pairs, fmt.Sprintf("%s=%q", name, labels[name]))
+	}
+	return "{" + strings.Join(pairs, ", ") + "}"
+}
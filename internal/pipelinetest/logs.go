@@ -0,0 +1,181 @@
+package pipelinetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/grafana/loki/v3/pkg/logproto"
+	"github.com/prometheus/common/model"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/common/loki"
+)
+
+// LogsInputArguments holds the arguments of testing.logs.input.
+type LogsInputArguments struct {
+	ForwardTo []loki.LogsReceiver `alloy:"forward_to,attr"`
+	Entries   []LogEntry          `alloy:"entry,block,optional"`
+}
+
+// LogEntry is a log entry sent by testing.logs.input.
+type LogEntry struct {
+	Line   string            `alloy:"line,attr"`
+	Labels map[string]string `alloy:"labels,attr,optional"`
+}
+
+// Validate implements syntax.Validator.
+func (args *LogsInputArguments) Validate() error {
+	if len(args.Entries) == 0 {
+		return errors.New("at least one entry block must be set")
+	}
+	return nil
+}
+
+// logsInput sends its log entries to its receivers once, when it starts
+// running.
+type logsInput struct {
+	mut  sync.RWMutex
+	args LogsInputArguments
+}
+
+func newLogsInput(_ component.Options, args LogsInputArguments) (*logsInput, error) {
+	return &logsInput{args: args}, nil
+}
+
+// Run implements component.Component.
+func (c *logsInput) Run(ctx context.Context) error {
+	c.mut.RLock()
+	args := c.args
+	c.mut.RUnlock()
+
+	for _, e := range args.Entries {
+		entry := loki.Entry{
+			Labels: make(model.LabelSet, len(e.Labels)),
+			Entry:  logproto.Entry{Timestamp: time.Now(), Line: e.Line},
+		}
+		for name, value := range e.Labels {
+			entry.Labels[model.LabelName(name)] = model.LabelValue(value)
+		}
+
+		for _, r := range args.ForwardTo {
+			select {
+			case <-ctx.Done():
+				return nil
+			case r.Chan() <- entry.Clone():
+			}
+		}
+	}
+
+	<-ctx.Done()
+	return nil
+}
+
+// Update implements component.Component. Entries are only sent once, so
+// updated entries are ignored.
+func (c *logsInput) Update(args component.Arguments) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.args = args.(LogsInputArguments)
+	return nil
+}
+
+// LogsOutputArguments holds the arguments of testing.logs.output.
+type LogsOutputArguments struct {
+	Expectations []LogExpectation `alloy:"expect,block,optional"`
+}
+
+// LogExpectation describes log entries expected by testing.logs.output.
+type LogExpectation struct {
+	// Line is the expected log line. Any line matches if empty.
+	Line string `alloy:"line,attr,optional"`
+	// Labels must all be set on matching entries, which may have other labels.
+	Labels map[string]string `alloy:"labels,attr,optional"`
+	// Count is the expected number of matching entries. At least one entry
+	// must match if unset.
+	Count *int `alloy:"count,attr,optional"`
+}
+
+// Validate implements syntax.Validator.
+func (args *LogsOutputArguments) Validate() error {
+	for _, e := range args.Expectations {
+		if err := validateCount(e.Count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LogsOutputExports holds the exports of testing.logs.output.
+type LogsOutputExports struct {
+	Receiver loki.LogsReceiver `alloy:"receiver,attr"`
+}
+
+// logsOutput records the log entries it receives.
+type logsOutput struct {
+	receiver loki.LogsReceiver
+
+	mut     sync.RWMutex
+	args    LogsOutputArguments
+	entries []loki.Entry
+}
+
+func newLogsOutput(opts component.Options, args LogsOutputArguments) (*logsOutput, error) {
+	c := &logsOutput{
+		receiver: loki.NewLogsReceiver(),
+		args:     args,
+	}
+	opts.OnStateChange(LogsOutputExports{Receiver: c.receiver})
+	return c, nil
+}
+
+// Run implements component.Component.
+func (c *logsOutput) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case entry := <-c.receiver.Chan():
+			c.mut.Lock()
+			c.entries = append(c.entries, entry)
+			c.mut.Unlock()
+		}
+	}
+}
+
+// Update implements component.Component.
+func (c *logsOutput) Update(args component.Arguments) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.args = args.(LogsOutputArguments)
+	return nil
+}
+
+func (c *logsOutput) check() error {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	var errs []error
+	for _, e := range c.args.Expectations {
+		var matched int
+		for _, entry := range c.entries {
+			if e.Line != "" && e.Line != entry.Line {
+				continue
+			}
+			if !matchLabels(e.Labels, func(name string) (string, bool) {
+				v, ok := entry.Labels[model.LabelName(name)]
+				return string(v), ok
+			}) {
+				continue
+			}
+			matched++
+		}
+
+		if err := checkCount(e.Count, matched); err != nil {
+			errs = append(errs, fmt.Errorf("entries with labels %s and line %q: %w", formatLabels(e.Labels), e.Line, err))
+		}
+	}
+	return errors.Join(errs...)
+}
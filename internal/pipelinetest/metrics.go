@@ -0,0 +1,212 @@
+package pipelinetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"github.com/prometheus/prometheus/storage"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/prometheus"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/service/labelstore"
+)
+
+// MetricsInputArguments holds the arguments of testing.metrics.input.
+type MetricsInputArguments struct {
+	ForwardTo []storage.Appendable `alloy:"forward_to,attr"`
+	Samples   []Sample             `alloy:"sample,block,optional"`
+}
+
+// Sample is a sample sent by testing.metrics.input.
+type Sample struct {
+	Name   string            `alloy:"name,attr"`
+	Labels map[string]string `alloy:"labels,attr,optional"`
+	Value  float64           `alloy:"value,attr"`
+}
+
+// Validate implements syntax.Validator.
+func (args *MetricsInputArguments) Validate() error {
+	if len(args.Samples) == 0 {
+		return errors.New("at least one sample block must be set")
+	}
+	return nil
+}
+
+// metricsInput appends its samples to its receivers once, when it starts
+// running.
+type metricsInput struct {
+	opts   component.Options
+	fanout *prometheus.Fanout
+
+	mut  sync.RWMutex
+	args MetricsInputArguments
+}
+
+func newMetricsInput(opts component.Options, args MetricsInputArguments) (*metricsInput, error) {
+	data, err := opts.GetServiceData(labelstore.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &metricsInput{
+		opts:   opts,
+		fanout: prometheus.NewFanout(args.ForwardTo, opts.ID, opts.Registerer, data.(labelstore.LabelStore)),
+		args:   args,
+	}, nil
+}
+
+// Run implements component.Component.
+func (c *metricsInput) Run(ctx context.Context) error {
+	c.mut.RLock()
+	samples := c.args.Samples
+	c.mut.RUnlock()
+
+	ts := time.Now().UnixMilli()
+	app := c.fanout.Appender(ctx)
+	for _, s := range samples {
+		lb := labels.NewBuilder(labels.EmptyLabels())
+		for name, value := range s.Labels {
+			lb.Set(name, value)
+		}
+		lb.Set(labels.MetricName, s.Name)
+
+		if _, err := app.Append(0, lb.Labels(), ts, s.Value); err != nil {
+			level.Error(c.opts.Logger).Log("msg", "failed to append sample", "metric", s.Name, "err", err)
+		}
+	}
+	if err := app.Commit(); err != nil {
+		level.Error(c.opts.Logger).Log("msg", "failed to commit samples", "err", err)
+	}
+
+	<-ctx.Done()
+	return nil
+}
+
+// Update implements component.Component. Samples are only sent once, so
+// updated samples are ignored.
+func (c *metricsInput) Update(args component.Arguments) error {
+	newArgs := args.(MetricsInputArguments)
+
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.args = newArgs
+	c.fanout.UpdateChildren(newArgs.ForwardTo)
+	return nil
+}
+
+// MetricsOutputArguments holds the arguments of testing.metrics.output.
+type MetricsOutputArguments struct {
+	Expectations []SampleExpectation `alloy:"expect,block,optional"`
+}
+
+// SampleExpectation describes samples expected by testing.metrics.output.
+type SampleExpectation struct {
+	// Name is the expected metric name. Any metric matches if empty.
+	Name string `alloy:"name,attr,optional"`
+	// Labels must all be set on matching samples, which may have other labels.
+	Labels map[string]string `alloy:"labels,attr,optional"`
+	// Value is the expected value of matching samples. Any value matches if
+	// unset.
+	Value *float64 `alloy:"value,attr,optional"`
+	// Count is the expected number of matching samples. At least one sample
+	// must match if unset.
+	Count *int `alloy:"count,attr,optional"`
+}
+
+// Validate implements syntax.Validator.
+func (args *MetricsOutputArguments) Validate() error {
+	for _, e := range args.Expectations {
+		if err := validateCount(e.Count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MetricsOutputExports holds the exports of testing.metrics.output.
+type MetricsOutputExports struct {
+	Receiver storage.Appendable `alloy:"receiver,attr"`
+}
+
+type recordedSample struct {
+	labels labels.Labels
+	value  float64
+}
+
+// metricsOutput records the samples appended to it.
+type metricsOutput struct {
+	mut     sync.RWMutex
+	args    MetricsOutputArguments
+	samples []recordedSample
+}
+
+func newMetricsOutput(opts component.Options, args MetricsOutputArguments) (*metricsOutput, error) {
+	data, err := opts.GetServiceData(labelstore.ServiceName)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &metricsOutput{args: args}
+	receiver := prometheus.NewInterceptor(nil, data.(labelstore.LabelStore),
+		prometheus.WithAppendHook(func(ref storage.SeriesRef, l labels.Labels, _ int64, v float64, _ storage.Appender) (storage.SeriesRef, error) {
+			c.mut.Lock()
+			defer c.mut.Unlock()
+			c.samples = append(c.samples, recordedSample{labels: l, value: v})
+			return ref, nil
+		}),
+	)
+	opts.OnStateChange(MetricsOutputExports{Receiver: receiver})
+	return c, nil
+}
+
+// Run implements component.Component.
+func (c *metricsOutput) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+// Update implements component.Component.
+func (c *metricsOutput) Update(args component.Arguments) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.args = args.(MetricsOutputArguments)
+	return nil
+}
+
+func (c *metricsOutput) check() error {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	var errs []error
+	for _, e := range c.args.Expectations {
+		var matched int
+		for _, s := range c.samples {
+			if e.Name != "" && e.Name != s.labels.Get(labels.MetricName) {
+				continue
+			}
+			if e.Value != nil && *e.Value != s.value {
+				continue
+			}
+			if !matchLabels(e.Labels, func(name string) (string, bool) {
+				return s.labels.Get(name), s.labels.Has(name)
+			}) {
+				continue
+			}
+			matched++
+		}
+
+		if err := checkCount(e.Count, matched); err != nil {
+			desc := fmt.Sprintf("samples of %q with labels %s", e.Name, formatLabels(e.Labels))
+			if e.Value != nil {
+				desc += fmt.Sprintf(" and value %g", *e.Value)
+			}
+			errs = append(errs, fmt.Errorf("%s: %w", desc, err))
+		}
+	}
+	return errors.Join(errs...)
+}
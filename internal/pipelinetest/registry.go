@@ -0,0 +1,145 @@
+// Package pipelinetest implements the components used by the alloy test
+// command to feed synthetic telemetry into a pipeline and to assert on what
+// the pipeline outputs.
+//
+// The components aren't registered globally; they're only available to
+// configurations loaded with a [Registry].
+package pipelinetest
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/featuregate"
+)
+
+// Registry is a [component.Registry] which adds the testing components to the
+// components of another registry. It keeps track of the output components it
+// builds so that their expectations can be checked.
+type Registry struct {
+	base          component.Registry
+	registrations map[string]component.Registration
+
+	mut     sync.Mutex
+	outputs map[string]output
+}
+
+var _ component.Registry = (*Registry)(nil)
+
+// output is implemented by the output components.
+type output interface {
+	// check returns an error describing the expectations of the component
+	// which aren't met by the data it received so far.
+	check() error
+}
+
+// Result is the result of checking the expectations of an output component.
+type Result struct {
+	// ID is the globally unique ID of the output component.
+	ID string
+	// Err is nil if all the expectations of the component are met.
+	Err error
+}
+
+// NewRegistry returns a new Registry which looks up the components which
+// aren't testing components in base.
+func NewRegistry(base component.Registry) *Registry {
+	r := &Registry{
+		base:    base,
+		outputs: make(map[string]output),
+	}
+
+	r.registrations = make(map[string]component.Registration)
+	for _, reg := range []component.Registration{
+		{
+			Name:  "testing.logs.input",
+			Args:  LogsInputArguments{},
+			Build: buildInput(newLogsInput),
+		},
+		{
+			Name:    "testing.logs.output",
+			Args:    LogsOutputArguments{},
+			Exports: LogsOutputExports{},
+			Build:   buildOutput(r, newLogsOutput),
+		},
+		{
+			Name:  "testing.metrics.input",
+			Args:  MetricsInputArguments{},
+			Build: buildInput(newMetricsInput),
+		},
+		{
+			Name:    "testing.metrics.output",
+			Args:    MetricsOutputArguments{},
+			Exports: MetricsOutputExports{},
+			Build:   buildOutput(r, newMetricsOutput),
+		},
+		{
+			Name:  "testing.traces.input",
+			Args:  TracesInputArguments{},
+			Build: buildInput(newTracesInput),
+		},
+		{
+			Name:    "testing.traces.output",
+			Args:    TracesOutputArguments{},
+			Exports: TracesOutputExports{},
+			Build:   buildOutput(r, newTracesOutput),
+		},
+	} {
+		// The testing components are only available to alloy test, so their
+		// stability doesn't restrict their usage.
+		reg.Stability = featuregate.StabilityGenerallyAvailable
+		r.registrations[reg.Name] = reg
+	}
+	return r
+}
+
+func buildInput[Args any, C component.Component](build func(component.Options, Args) (C, error)) func(component.Options, component.Arguments) (component.Component, error) {
+	return func(opts component.Options, args component.Arguments) (component.Component, error) {
+		return build(opts, args.(Args))
+	}
+}
+
+func buildOutput[Args any, C interface {
+	component.Component
+	output
+}](r *Registry, build func(component.Options, Args) (C, error)) func(component.Options, component.Arguments) (component.Component, error) {
+	return func(opts component.Options, args component.Arguments) (component.Component, error) {
+		c, err := build(opts, args.(Args))
+		if err != nil {
+			return nil, err
+		}
+
+		r.mut.Lock()
+		defer r.mut.Unlock()
+		r.outputs[opts.ID] = c
+		return c, nil
+	}
+}
+
+// Get implements component.Registry.
+func (r *Registry) Get(name string) (component.Registration, error) {
+	if reg, ok := r.registrations[name]; ok {
+		return reg, nil
+	}
+	if strings.HasPrefix(name, "testing.") {
+		return component.Registration{}, fmt.Errorf("cannot find the definition of component name %q", name)
+	}
+	return r.base.Get(name)
+}
+
+// Results checks the expectations of the output components built so far. The
+// results are sorted by component ID.
+func (r *Registry) Results() []Result {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	res := make([]Result, 0, len(r.outputs))
+	for id, o := range r.outputs {
+		res = append(res, Result{ID: id, Err: o.check()})
+	}
+	slices.SortFunc(res, func(a, b Result) int { return strings.Compare(a.ID, b.ID) })
+	return res
+}
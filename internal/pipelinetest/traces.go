@@ -0,0 +1,247 @@
+package pipelinetest
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	otelconsumer "go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/pdata/pcommon"
+	"go.opentelemetry.io/collector/pdata/plog"
+	"go.opentelemetry.io/collector/pdata/pmetric"
+	"go.opentelemetry.io/collector/pdata/ptrace"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/component/otelcol"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+// TracesInputArguments holds the arguments of testing.traces.input.
+type TracesInputArguments struct {
+	Spans  []Span                     `alloy:"span,block,optional"`
+	Output *otelcol.ConsumerArguments `alloy:"output,block"`
+}
+
+// Span is a span sent by testing.traces.input. Every span is sent in its own
+// trace.
+type Span struct {
+	Name               string            `alloy:"name,attr"`
+	Attributes         map[string]string `alloy:"attributes,attr,optional"`
+	ResourceAttributes map[string]string `alloy:"resource_attributes,attr,optional"`
+}
+
+// Validate implements syntax.Validator.
+func (args *TracesInputArguments) Validate() error {
+	if len(args.Spans) == 0 {
+		return errors.New("at least one span block must be set")
+	}
+	return nil
+}
+
+// tracesInput sends its spans to its consumers once, when it starts running.
+type tracesInput struct {
+	opts component.Options
+
+	mut  sync.RWMutex
+	args TracesInputArguments
+}
+
+func newTracesInput(opts component.Options, args TracesInputArguments) (*tracesInput, error) {
+	return &tracesInput{opts: opts, args: args}, nil
+}
+
+// Run implements component.Component.
+func (c *tracesInput) Run(ctx context.Context) error {
+	c.mut.RLock()
+	args := c.args
+	c.mut.RUnlock()
+
+	td := ptrace.NewTraces()
+	now := time.Now()
+	for _, s := range args.Spans {
+		rs := td.ResourceSpans().AppendEmpty()
+		putAttributes(rs.Resource().Attributes(), s.ResourceAttributes)
+
+		span := rs.ScopeSpans().AppendEmpty().Spans().AppendEmpty()
+		span.SetName(s.Name)
+		var (
+			traceID pcommon.TraceID
+			spanID  pcommon.SpanID
+		)
+		_, _ = rand.Read(traceID[:])
+		_, _ = rand.Read(spanID[:])
+		span.SetTraceID(traceID)
+		span.SetSpanID(spanID)
+		span.SetStartTimestamp(pcommon.NewTimestampFromTime(now))
+		span.SetEndTimestamp(pcommon.NewTimestampFromTime(now))
+		putAttributes(span.Attributes(), s.Attributes)
+	}
+
+	for _, next := range args.Output.Traces {
+		// Consumers may modify the traces they receive.
+		cp := ptrace.NewTraces()
+		td.CopyTo(cp)
+		if err := next.ConsumeTraces(ctx, cp); err != nil {
+			level.Error(c.opts.Logger).Log("msg", "failed to send traces", "err", err)
+		}
+	}
+
+	<-ctx.Done()
+	return nil
+}
+
+func putAttributes(m pcommon.Map, attrs map[string]string) {
+	for k, v := range attrs {
+		m.PutStr(k, v)
+	}
+}
+
+// Update implements component.Component. Spans are only sent once, so
+// updated spans are ignored.
+func (c *tracesInput) Update(args component.Arguments) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.args = args.(TracesInputArguments)
+	return nil
+}
+
+// TracesOutputArguments holds the arguments of testing.traces.output.
+type TracesOutputArguments struct {
+	Expectations []SpanExpectation `alloy:"expect,block,optional"`
+}
+
+// SpanExpectation describes spans expected by testing.traces.output.
+type SpanExpectation struct {
+	// Name is the expected span name. Any span matches if empty.
+	Name string `alloy:"name,attr,optional"`
+	// Attributes must all be set on matching spans, which may have other
+	// attributes.
+	Attributes map[string]string `alloy:"attributes,attr,optional"`
+	// ResourceAttributes must all be set on the resource of matching spans.
+	ResourceAttributes map[string]string `alloy:"resource_attributes,attr,optional"`
+	// Count is the expected number of matching spans. At least one span must
+	// match if unset.
+	Count *int `alloy:"count,attr,optional"`
+}
+
+// Validate implements syntax.Validator.
+func (args *TracesOutputArguments) Validate() error {
+	for _, e := range args.Expectations {
+		if err := validateCount(e.Count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TracesOutputExports holds the exports of testing.traces.output.
+type TracesOutputExports struct {
+	Input otelcol.Consumer `alloy:"input,attr"`
+}
+
+// tracesOutput records the spans it consumes. Metrics and logs are dropped.
+type tracesOutput struct {
+	mut   sync.RWMutex
+	args  TracesOutputArguments
+	spans []recordedSpan
+}
+
+type recordedSpan struct {
+	name               string
+	attributes         pcommon.Map
+	resourceAttributes pcommon.Map
+}
+
+var _ otelcol.Consumer = (*tracesOutput)(nil)
+
+func newTracesOutput(opts component.Options, args TracesOutputArguments) (*tracesOutput, error) {
+	c := &tracesOutput{args: args}
+	opts.OnStateChange(TracesOutputExports{Input: c})
+	return c, nil
+}
+
+// Run implements component.Component.
+func (c *tracesOutput) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+// Update implements component.Component.
+func (c *tracesOutput) Update(args component.Arguments) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.args = args.(TracesOutputArguments)
+	return nil
+}
+
+// Capabilities implements otelconsumer.Traces.
+func (c *tracesOutput) Capabilities() otelconsumer.Capabilities {
+	return otelconsumer.Capabilities{MutatesData: false}
+}
+
+// ConsumeTraces implements otelconsumer.Traces.
+func (c *tracesOutput) ConsumeTraces(_ context.Context, td ptrace.Traces) error {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	for _, rs := range td.ResourceSpans().All() {
+		for _, ss := range rs.ScopeSpans().All() {
+			for _, span := range ss.Spans().All() {
+				rec := recordedSpan{
+					name:               span.Name(),
+					attributes:         pcommon.NewMap(),
+					resourceAttributes: pcommon.NewMap(),
+				}
+				span.Attributes().CopyTo(rec.attributes)
+				rs.Resource().Attributes().CopyTo(rec.resourceAttributes)
+				c.spans = append(c.spans, rec)
+			}
+		}
+	}
+	return nil
+}
+
+// ConsumeMetrics implements otelconsumer.Metrics.
+func (c *tracesOutput) ConsumeMetrics(context.Context, pmetric.Metrics) error { return nil }
+
+// ConsumeLogs implements otelconsumer.Logs.
+func (c *tracesOutput) ConsumeLogs(context.Context, plog.Logs) error { return nil }
+
+func (c *tracesOutput) check() error {
+	c.mut.RLock()
+	defer c.mut.RUnlock()
+
+	var errs []error
+	for _, e := range c.args.Expectations {
+		var matched int
+		for _, s := range c.spans {
+			if e.Name != "" && e.Name != s.name {
+				continue
+			}
+			if !matchLabels(e.Attributes, getAttribute(s.attributes)) ||
+				!matchLabels(e.ResourceAttributes, getAttribute(s.resourceAttributes)) {
+				continue
+			}
+			matched++
+		}
+
+		if err := checkCount(e.Count, matched); err != nil {
+			errs = append(errs, fmt.Errorf("spans %q with attributes %s and resource attributes %s: %w",
+				e.Name, formatLabels(e.Attributes), formatLabels(e.ResourceAttributes), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func getAttribute(m pcommon.Map) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := m.Get(name)
+		if !ok {
+			return "", false
+		}
+		return v.AsString(), true
+	}
+}
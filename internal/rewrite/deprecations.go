@@ -0,0 +1,87 @@
+package rewrite
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/grafana/alloy/syntax/ast"
+	"github.com/grafana/alloy/syntax/token"
+)
+
+func init() {
+	// The blacklist and whitelist arguments of prometheus.exporter.windows
+	// were replaced by exclude and include.
+	for _, block := range []string{"logical_disk", "network", "process", "smtp"} {
+		Register(RenameAttribute("prometheus.exporter.windows", []string{block}, "blacklist", "exclude"))
+		Register(RenameAttribute("prometheus.exporter.windows", []string{block}, "whitelist", "include"))
+	}
+	for _, prefix := range []string{"app", "site"} {
+		Register(RenameAttribute("prometheus.exporter.windows", []string{"iis"}, prefix+"_blacklist", prefix+"_exclude"))
+		Register(RenameAttribute("prometheus.exporter.windows", []string{"iis"}, prefix+"_whitelist", prefix+"_include"))
+	}
+	for _, block := range []string{"smb", "smb_client"} {
+		Register(RemoveAttribute("prometheus.exporter.windows", []string{block}, "enabled_list", "the argument has no effect"))
+	}
+
+	Register(RemoveAttribute("prometheus.exporter.kafka", nil, "prune_interval_seconds", "the argument has no effect, use metadata_refresh_interval instead"))
+
+	Register(scrapeProtobufNegotiation)
+}
+
+// nativeHistogramScrapeProtocols are the scrape protocols used by
+// prometheus.scrape when enable_protobuf_negotiation is true.
+var nativeHistogramScrapeProtocols = []string{"PrometheusProto", "OpenMetricsText1.0.0", "OpenMetricsText0.0.1", "PrometheusText0.0.4"}
+
+// scrapeProtobufNegotiation replaces the enable_protobuf_negotiation argument
+// of prometheus.scrape with the equivalent scrape_protocols.
+var scrapeProtobufNegotiation = Rule{
+	Name:        "prometheus.scrape.enable_protobuf_negotiation",
+	Description: "Replace prometheus.scrape.enable_protobuf_negotiation with scrape_protocols.",
+	Apply: func(body ast.Body) []Change {
+		const rule = "prometheus.scrape.enable_protobuf_negotiation"
+
+		var changes []Change
+		forEachBlock(body, "prometheus.scrape", nil, func(c, _ *ast.BlockStmt) {
+			i, attr := findAttribute(c.Body, "enable_protobuf_negotiation")
+			if attr == nil {
+				return
+			}
+			change := Change{Rule: rule, Pos: attr.Name.NamePos}
+
+			lit, ok := attr.Value.(*ast.LiteralExpr)
+			if !ok || lit.Kind != token.BOOL {
+				change.Manual = true
+				change.Message = fmt.Sprintf("%s: cannot replace enable_protobuf_negotiation, which isn't set to true or false", componentID(c))
+				changes = append(changes, change)
+				return
+			}
+
+			switch enabled, _ := strconv.ParseBool(lit.Value); {
+			case !enabled:
+				c.Body = append(c.Body[:i], c.Body[i+1:]...)
+				change.Message = fmt.Sprintf("%s: removed enable_protobuf_negotiation, which is disabled by default", componentID(c))
+			default:
+				if _, existing := findAttribute(c.Body, "scrape_protocols"); existing != nil {
+					change.Manual = true
+					change.Message = fmt.Sprintf("%s: cannot replace enable_protobuf_negotiation, scrape_protocols is already set", componentID(c))
+					break
+				}
+
+				// The new nodes share the position of the replaced attribute so
+				// that the printer keeps them on its line.
+				pos := attr.Name.NamePos
+				protocols := &ast.ArrayExpr{LBrackPos: pos, RBrackPos: pos}
+				for _, p := range nativeHistogramScrapeProtocols {
+					protocols.Elements = append(protocols.Elements, &ast.LiteralExpr{Kind: token.STRING, ValuePos: pos, Value: strconv.Quote(p)})
+				}
+				c.Body[i] = &ast.AttributeStmt{
+					Name:  &ast.Ident{Name: "scrape_protocols", NamePos: pos},
+					Value: protocols,
+				}
+				change.Message = fmt.Sprintf("%s: replaced enable_protobuf_negotiation with scrape_protocols", componentID(c))
+			}
+			changes = append(changes, change)
+		})
+		return changes
+	},
+}
@@ -0,0 +1,88 @@
+// Package rewrite implements rules which rewrite deprecated syntax in
+// configuration files, like renamed arguments or replaced components, so that
+// upgrades across breaking changes of components can be automated.
+package rewrite
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/grafana/alloy/syntax/ast"
+	"github.com/grafana/alloy/syntax/token"
+)
+
+// Rule rewrites a deprecated syntax.
+type Rule struct {
+	// Name uniquely identifies the rule.
+	Name string
+	// Description describes the rewrite for users.
+	Description string
+	// Apply rewrites body in place and returns the changes it made, including
+	// the rewrites it couldn't make automatically.
+	Apply func(body ast.Body) []Change
+}
+
+// Change describes a rewrite applied by a Rule.
+type Change struct {
+	// Rule is the name of the rule which made the change.
+	Rule string
+	// Pos is the position of the rewritten syntax in the original file.
+	Pos token.Pos
+	// Message describes the change.
+	Message string
+	// Manual is true if the rule couldn't rewrite the syntax automatically, in
+	// which case the syntax is left unchanged and Message describes why.
+	Manual bool
+}
+
+// String returns the position and message of c.
+func (c Change) String() string {
+	prefix := ""
+	if c.Manual {
+		prefix = "manual fix required: "
+	}
+	return fmt.Sprintf("%s: %s%s (%s)", c.Pos.Position(), prefix, c.Message, c.Rule)
+}
+
+var (
+	registeredMut sync.RWMutex
+	registered    = map[string]Rule{}
+)
+
+// Register registers a rule. Register panics if a rule with the same name is
+// already registered.
+func Register(r Rule) {
+	registeredMut.Lock()
+	defer registeredMut.Unlock()
+
+	if _, exists := registered[r.Name]; exists {
+		panic(fmt.Sprintf("rewrite: rule %q already registered", r.Name))
+	}
+	registered[r.Name] = r
+}
+
+// Rules returns the registered rules, sorted by name.
+func Rules() []Rule {
+	registeredMut.RLock()
+	defer registeredMut.RUnlock()
+
+	rules := make([]Rule, 0, len(registered))
+	for _, r := range registered {
+		rules = append(rules, r)
+	}
+	slices.SortFunc(rules, func(a, b Rule) int { return strings.Compare(a.Name, b.Name) })
+	return rules
+}
+
+// Apply applies rules to f in order, and returns the changes they made sorted
+// by position.
+func Apply(f *ast.File, rules []Rule) []Change {
+	var changes []Change
+	for _, r := range rules {
+		changes = append(changes, r.Apply(f.Body)...)
+	}
+	slices.SortStableFunc(changes, func(a, b Change) int { return a.Pos.Offset() - b.Pos.Offset() })
+	return changes
+}
@@ -0,0 +1,195 @@
+package rewrite
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/syntax/parser"
+	"github.com/grafana/alloy/syntax/printer"
+)
+
+func TestRules(t *testing.T) {
+	tests := []struct {
+		name    string
+		rules   []Rule
+		input   string
+		expect  string
+		changes []string
+	}{
+		{
+			name:  "rename attribute",
+			rules: []Rule{RenameAttribute("prometheus.exporter.windows", []string{"process"}, "blacklist", "exclude")},
+			input: `
+prometheus.exporter.windows "default" {
+	// Processes to skip.
+	process {
+		blacklist = "svchost"
+	}
+}
+
+prometheus.exporter.windows "other" {
+	process {
+		blacklist = "svchost"
+		exclude   = "lsass"
+	}
+}`,
+			expect: `
+prometheus.exporter.windows "default" {
+	// Processes to skip.
+	process {
+		exclude = "svchost"
+	}
+}
+
+prometheus.exporter.windows "other" {
+	process {
+		blacklist = "svchost"
+		exclude   = "lsass"
+	}
+}`,
+			changes: []string{
+				"test.alloy:5:3: prometheus.exporter.windows.default: renamed process.blacklist to exclude (prometheus.exporter.windows.process.blacklist)",
+				"test.alloy:11:3: manual fix required: prometheus.exporter.windows.other: cannot rename process.blacklist to exclude, which is already set (prometheus.exporter.windows.process.blacklist)",
+			},
+		},
+		{
+			name:  "remove attribute",
+			rules: []Rule{RemoveAttribute("prometheus.exporter.kafka", nil, "prune_interval_seconds", "the argument has no effect")},
+			input: `
+declare "kafka" {
+	prometheus.exporter.kafka "default" {
+		kafka_uris             = ["localhost:9092"]
+		prune_interval_seconds = 30
+	}
+}`,
+			expect: `
+declare "kafka" {
+	prometheus.exporter.kafka "default" {
+		kafka_uris = ["localhost:9092"]
+	}
+}`,
+			changes: []string{
+				"test.alloy:5:3: prometheus.exporter.kafka.default: removed prune_interval_seconds: the argument has no effect (prometheus.exporter.kafka.prune_interval_seconds)",
+			},
+		},
+		{
+			name:  "replace value",
+			rules: []Rule{ReplaceValue("otelcol.receiver.example", []string{"protocol"}, "modes", "legacy", "current")},
+			input: `
+otelcol.receiver.example "default" {
+	protocol {
+		modes = ["legacy", "other"]
+	}
+}`,
+			expect: `
+otelcol.receiver.example "default" {
+	protocol {
+		modes = ["current", "other"]
+	}
+}`,
+			changes: []string{
+				`test.alloy:4:12: otelcol.receiver.example.default: replaced the value "legacy" of protocol.modes with "current" (otelcol.receiver.example.protocol.modes=legacy)`,
+			},
+		},
+		{
+			name:  "rename component",
+			rules: []Rule{RenameComponent("otelcol.exporter.logging", "otelcol.exporter.debug")},
+			input: `
+otelcol.exporter.logging "default" {}
+
+otelcol.receiver.otlp "default" {
+	output {
+		traces = [otelcol.exporter.logging.default.input]
+	}
+}`,
+			expect: `
+otelcol.exporter.debug "default" { }
+
+otelcol.receiver.otlp "default" {
+	output {
+		traces = [otelcol.exporter.debug.default.input]
+	}
+}`,
+			changes: []string{
+				"test.alloy:2:1: replaced otelcol.exporter.logging.default with otelcol.exporter.debug.default (otelcol.exporter.logging)",
+				"test.alloy:6:13: updated reference otelcol.exporter.logging.default.input to otelcol.exporter.debug.default.input (otelcol.exporter.logging)",
+			},
+		},
+		{
+			name:  "scrape protobuf negotiation",
+			rules: []Rule{scrapeProtobufNegotiation},
+			input: `
+prometheus.scrape "enabled" {
+	targets                     = []
+	enable_protobuf_negotiation = true
+	forward_to                  = []
+}
+
+prometheus.scrape "disabled" {
+	targets                     = []
+	enable_protobuf_negotiation = false
+	forward_to                  = []
+}
+
+prometheus.scrape "dynamic" {
+	targets                     = []
+	enable_protobuf_negotiation = sys.env("PROTOBUF") == "1"
+	forward_to                  = []
+}`,
+			expect: `
+prometheus.scrape "enabled" {
+	targets          = []
+	scrape_protocols = ["PrometheusProto", "OpenMetricsText1.0.0", "OpenMetricsText0.0.1", "PrometheusText0.0.4"]
+	forward_to       = []
+}
+
+prometheus.scrape "disabled" {
+	targets = []
+
+	forward_to = []
+}
+
+prometheus.scrape "dynamic" {
+	targets                     = []
+	enable_protobuf_negotiation = sys.env("PROTOBUF") == "1"
+	forward_to                  = []
+}`,
+			changes: []string{
+				"test.alloy:4:2: prometheus.scrape.enabled: replaced enable_protobuf_negotiation with scrape_protocols (prometheus.scrape.enable_protobuf_negotiation)",
+				"test.alloy:10:2: prometheus.scrape.disabled: removed enable_protobuf_negotiation, which is disabled by default (prometheus.scrape.enable_protobuf_negotiation)",
+				"test.alloy:16:2: manual fix required: prometheus.scrape.dynamic: cannot replace enable_protobuf_negotiation, which isn't set to true or false (prometheus.scrape.enable_protobuf_negotiation)",
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := parser.ParseFile("test.alloy", []byte(tc.input))
+			require.NoError(t, err)
+
+			var changes []string
+			for _, c := range Apply(f, tc.rules) {
+				changes = append(changes, c.String())
+			}
+			require.Equal(t, tc.changes, changes)
+
+			var buf bytes.Buffer
+			require.NoError(t, printer.Fprint(&buf, f))
+			require.Equal(t, strings.TrimSpace(tc.expect), strings.TrimSpace(buf.String()))
+		})
+	}
+}
+
+func TestRegisteredRules(t *testing.T) {
+	names := map[string]bool{}
+	for _, r := range Rules() {
+		require.NotEmpty(t, r.Description)
+		require.False(t, names[r.Name], "duplicate rule %q", r.Name)
+		names[r.Name] = true
+	}
+	require.True(t, names["prometheus.exporter.windows.iis.app_blacklist"])
+	require.True(t, names["prometheus.scrape.enable_protobuf_negotiation"])
+}
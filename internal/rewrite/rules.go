@@ -0,0 +1,308 @@
+package rewrite
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/grafana/alloy/syntax/ast"
+	"github.com/grafana/alloy/syntax/token"
+)
+
+// RenameAttribute returns a rule which renames the attribute from to to in
+// the components named component. block is the path of the nested block
+// holding the attribute, and is empty for attributes of the component itself.
+//
+// Attributes aren't renamed if to is already set.
+func RenameAttribute(component string, block []string, from, to string) Rule {
+	name := qualifiedName(append([]string{component}, block...), from)
+	return Rule{
+		Name:        name,
+		Description: fmt.Sprintf("Rename %s to %s.", name, to),
+		Apply: func(body ast.Body) []Change {
+			var changes []Change
+			forEachBlock(body, component, block, func(c, b *ast.BlockStmt) {
+				_, attr := findAttribute(b.Body, from)
+				if attr == nil {
+					return
+				}
+
+				change := Change{Rule: name, Pos: attr.Name.NamePos}
+				if _, existing := findAttribute(b.Body, to); existing != nil {
+					change.Manual = true
+					change.Message = fmt.Sprintf("%s: cannot rename %s to %s, which is already set", componentID(c), qualifiedName(block, from), to)
+				} else {
+					attr.Name = &ast.Ident{Name: to, NamePos: attr.Name.NamePos}
+					change.Message = fmt.Sprintf("%s: renamed %s to %s", componentID(c), qualifiedName(block, from), to)
+				}
+				changes = append(changes, change)
+			})
+			return changes
+		},
+	}
+}
+
+// RemoveAttribute returns a rule which removes the attribute attr from the
+// components named component, because of reason. block is the path of the
+// nested block holding the attribute, and is empty for attributes of the
+// component itself.
+//
+// The printer keeps the line of a removed attribute blank when other
+// statements follow it.
+func RemoveAttribute(component string, block []string, attr, reason string) Rule {
+	name := qualifiedName(append([]string{component}, block...), attr)
+	return Rule{
+		Name:        name,
+		Description: fmt.Sprintf("Remove %s: %s.", name, reason),
+		Apply: func(body ast.Body) []Change {
+			var changes []Change
+			forEachBlock(body, component, block, func(c, b *ast.BlockStmt) {
+				i, stmt := findAttribute(b.Body, attr)
+				if stmt == nil {
+					return
+				}
+				b.Body = append(b.Body[:i], b.Body[i+1:]...)
+				changes = append(changes, Change{
+					Rule:    name,
+					Pos:     stmt.Name.NamePos,
+					Message: fmt.Sprintf("%s: removed %s: %s", componentID(c), qualifiedName(block, attr), reason),
+				})
+			})
+			return changes
+		},
+	}
+}
+
+// ReplaceValue returns a rule which replaces the string value from of the
+// attribute attr with to, in the components named component. block is the
+// path of the nested block holding the attribute, and is empty for attributes
+// of the component itself.
+//
+// Only literal values are replaced, including the elements of literal lists.
+func ReplaceValue(component string, block []string, attr, from, to string) Rule {
+	name := qualifiedName(append([]string{component}, block...), attr) + "=" + from
+	return Rule{
+		Name:        name,
+		Description: fmt.Sprintf("Replace the value %q of %s with %q.", from, qualifiedName(append([]string{component}, block...), attr), to),
+		Apply: func(body ast.Body) []Change {
+			var changes []Change
+			forEachBlock(body, component, block, func(c, b *ast.BlockStmt) {
+				_, stmt := findAttribute(b.Body, attr)
+				if stmt == nil {
+					return
+				}
+
+				values := []ast.Expr{stmt.Value}
+				if arr, ok := stmt.Value.(*ast.ArrayExpr); ok {
+					values = arr.Elements
+				}
+				for _, v := range values {
+					lit, ok := v.(*ast.LiteralExpr)
+					if !ok || lit.Kind != token.STRING {
+						continue
+					}
+					if s, err := strconv.Unquote(lit.Value); err != nil || s != from {
+						continue
+					}
+					lit.Value = strconv.Quote(to)
+					changes = append(changes, Change{
+						Rule:    name,
+						Pos:     lit.ValuePos,
+						Message: fmt.Sprintf("%s: replaced the value %q of %s with %q", componentID(c), from, qualifiedName(block, attr), to),
+					})
+				}
+			})
+			return changes
+		},
+	}
+}
+
+// RenameComponent returns a rule which renames the components named from to
+// to, and updates the references to their exports.
+func RenameComponent(from, to string) Rule {
+	var (
+		fromParts = strings.Split(from, ".")
+		toParts   = strings.Split(to, ".")
+	)
+	return Rule{
+		Name:        from,
+		Description: fmt.Sprintf("Replace %s with %s.", from, to),
+		Apply: func(body ast.Body) []Change {
+			var changes []Change
+			for _, b := range componentBlocks(body, from) {
+				id := componentID(b)
+				b.Name = toParts
+				changes = append(changes, Change{
+					Rule:    from,
+					Pos:     b.NamePos,
+					Message: fmt.Sprintf("replaced %s with %s", id, componentID(b)),
+				})
+			}
+
+			mapExprs(body, func(e ast.Expr) ast.Expr {
+				idents := traversal(e)
+				// References to components include at least their label.
+				if len(idents) <= len(fromParts) || !hasPrefix(idents, fromParts) {
+					return e
+				}
+
+				names := append(append([]string{}, toParts...), identNames(idents[len(fromParts):])...)
+				changes = append(changes, Change{
+					Rule:    from,
+					Pos:     idents[0].NamePos,
+					Message: fmt.Sprintf("updated reference %s to %s", strings.Join(identNames(idents), "."), strings.Join(names, ".")),
+				})
+				return newTraversal(idents[0].NamePos, names)
+			})
+			return changes
+		},
+	}
+}
+
+// forEachBlock calls fn for each block found at path in the components named
+// component. c is the component block and b is the nested block, which is c
+// if path is empty.
+func forEachBlock(body ast.Body, component string, path []string, fn func(c, b *ast.BlockStmt)) {
+	for _, c := range componentBlocks(body, component) {
+		blocks := []*ast.BlockStmt{c}
+		for _, name := range path {
+			var next []*ast.BlockStmt
+			for _, b := range blocks {
+				for _, stmt := range b.Body {
+					if nested, ok := stmt.(*ast.BlockStmt); ok && blockName(nested) == name {
+						next = append(next, nested)
+					}
+				}
+			}
+			blocks = next
+		}
+
+		for _, b := range blocks {
+			fn(c, b)
+		}
+	}
+}
+
+// componentBlocks returns the blocks of the components named name in body,
+// including the components nested in other blocks, like declare blocks.
+func componentBlocks(body ast.Body, name string) []*ast.BlockStmt {
+	var res []*ast.BlockStmt
+	for _, stmt := range body {
+		b, ok := stmt.(*ast.BlockStmt)
+		if !ok {
+			continue
+		}
+		if b.Label != "" && blockName(b) == name {
+			res = append(res, b)
+			continue
+		}
+		res = append(res, componentBlocks(b.Body, name)...)
+	}
+	return res
+}
+
+func findAttribute(body ast.Body, name string) (int, *ast.AttributeStmt) {
+	for i, stmt := range body {
+		if attr, ok := stmt.(*ast.AttributeStmt); ok && attr.Name.Name == name {
+			return i, attr
+		}
+	}
+	return -1, nil
+}
+
+func blockName(b *ast.BlockStmt) string { return strings.Join(b.Name, ".") }
+
+func componentID(b *ast.BlockStmt) string { return blockName(b) + "." + b.Label }
+
+func qualifiedName(path []string, name string) string {
+	return strings.Join(append(append([]string{}, path...), name), ".")
+}
+
+// mapExprs replaces the expressions in body with the result of fn. The
+// children of an expression are only visited if fn returns it unchanged.
+func mapExprs(body ast.Body, fn func(ast.Expr) ast.Expr) {
+	for _, stmt := range body {
+		switch stmt := stmt.(type) {
+		case *ast.AttributeStmt:
+			stmt.Value = mapExpr(stmt.Value, fn)
+		case *ast.BlockStmt:
+			mapExprs(stmt.Body, fn)
+		}
+	}
+}
+
+func mapExpr(e ast.Expr, fn func(ast.Expr) ast.Expr) ast.Expr {
+	if mapped := fn(e); mapped != e {
+		return mapped
+	}
+
+	switch e := e.(type) {
+	case *ast.ArrayExpr:
+		for i := range e.Elements {
+			e.Elements[i] = mapExpr(e.Elements[i], fn)
+		}
+	case *ast.ObjectExpr:
+		for _, f := range e.Fields {
+			f.Value = mapExpr(f.Value, fn)
+		}
+	case *ast.AccessExpr:
+		e.Value = mapExpr(e.Value, fn)
+	case *ast.IndexExpr:
+		e.Value = mapExpr(e.Value, fn)
+		e.Index = mapExpr(e.Index, fn)
+	case *ast.CallExpr:
+		e.Value = mapExpr(e.Value, fn)
+		for i := range e.Args {
+			e.Args[i] = mapExpr(e.Args[i], fn)
+		}
+	case *ast.UnaryExpr:
+		e.Value = mapExpr(e.Value, fn)
+	case *ast.BinaryExpr:
+		e.Left = mapExpr(e.Left, fn)
+		e.Right = mapExpr(e.Right, fn)
+	case *ast.ParenExpr:
+		e.Inner = mapExpr(e.Inner, fn)
+	}
+	return e
+}
+
+// traversal returns the identifiers of e if it's a chain of field accesses
+// like a.b.c, or nil otherwise.
+func traversal(e ast.Expr) []*ast.Ident {
+	switch e := e.(type) {
+	case *ast.IdentifierExpr:
+		return []*ast.Ident{e.Ident}
+	case *ast.AccessExpr:
+		if idents := traversal(e.Value); idents != nil {
+			return append(idents, e.Name)
+		}
+	}
+	return nil
+}
+
+// newTraversal builds a chain of field accesses of names at pos. All the
+// identifiers share pos so that the printer keeps the chain on its line.
+func newTraversal(pos token.Pos, names []string) ast.Expr {
+	var e ast.Expr = &ast.IdentifierExpr{Ident: &ast.Ident{Name: names[0], NamePos: pos}}
+	for _, name := range names[1:] {
+		e = &ast.AccessExpr{Value: e, Name: &ast.Ident{Name: name, NamePos: pos}}
+	}
+	return e
+}
+
+func hasPrefix(idents []*ast.Ident, prefix []string) bool {
+	for i, name := range prefix {
+		if idents[i].Name != name {
+			return false
+		}
+	}
+	return true
+}
+
+func identNames(idents []*ast.Ident) []string {
+	names := make([]string, 0, len(idents))
+	for _, ident := range idents {
+		names = append(names, ident.Name)
+	}
+	return names
+}
@@ -114,6 +114,25 @@ type Options struct {
 
 	// EnableCommunityComps enables the use of community components.
 	EnableCommunityComps bool
+
+	// ComponentRegistry is used to look up components. If nil, a registry of
+	// all the registered components which honors MinStability and
+	// EnableCommunityComps is used.
+	ComponentRegistry component.Registry
+
+	// DryRun only validates the loaded config source: arguments are decoded
+	// and validated, but components are never built and services and the
+	// logger are never updated. A controller with DryRun set must not be run.
+	DryRun bool
+
+	// EnforceFIPS refuses to build or update components whose arguments use
+	// TLS or authentication settings which aren't approved by FIPS 140-3.
+	EnforceFIPS bool
+
+	// EvaluationTimeout is the maximum duration of the evaluation of a single
+	// block. Blocks which take longer are reported as failed, so that they
+	// don't stall the evaluation of the other blocks. Zero means no timeout.
+	EvaluationTimeout time.Duration
 }
 
 // Runtime is the Alloy system.
@@ -148,9 +167,8 @@ func New(o Options) *Runtime {
 type controllerOptions struct {
 	Options
 
-	ComponentRegistry component.Registry // Custom component registry used in tests.
-	ModuleRegistry    *moduleRegistry    // Where to register created modules.
-	IsModule          bool               // Whether this controller is for a module.
+	ModuleRegistry *moduleRegistry // Where to register created modules.
+	IsModule       bool            // Whether this controller is for a module.
 	// A worker pool to evaluate components asynchronously. A default one will be created if this is nil.
 	WorkerPool worker.Pool
 }
@@ -201,6 +219,9 @@ func newController(o controllerOptions) *Runtime {
 			DataPath:             o.DataPath,
 			MinStability:         o.MinStability,
 			EnableCommunityComps: o.EnableCommunityComps,
+			DryRun:               o.DryRun,
+			EnforceFIPS:          o.EnforceFIPS,
+			EvaluationTimeout:    o.EvaluationTimeout,
 			OnBlockNodeUpdate: func(cn controller.BlockNode) {
 				// Changed node should be queued for reevaluation.
 				f.updateQueue.Enqueue(&controller.QueuedNode{Node: cn, LastUpdatedTime: time.Now()})
@@ -215,6 +236,10 @@ func newController(o controllerOptions) *Runtime {
 				if opts.RegOverride != nil {
 					reg = opts.RegOverride
 				}
+				minStability := o.MinStability
+				if opts.MinStability != featuregate.StabilityUndefined {
+					minStability = opts.MinStability
+				}
 
 				return newModuleController(&moduleControllerOptions{
 					ComponentRegistry:    o.ComponentRegistry,
@@ -223,8 +248,11 @@ func newController(o controllerOptions) *Runtime {
 					Tracer:               tracer,
 					Reg:                  reg,
 					DataPath:             o.DataPath,
-					MinStability:         o.MinStability,
+					MinStability:         minStability,
 					EnableCommunityComps: o.EnableCommunityComps,
+					DryRun:               o.DryRun,
+					EnforceFIPS:          o.EnforceFIPS,
+					EvaluationTimeout:    o.EvaluationTimeout,
 					ID:                   opts.Id,
 					ServiceMap:           serviceMap,
 					WorkerPool:           workerPool,
@@ -255,11 +283,26 @@ func (f *Runtime) Run(ctx context.Context) {
 	defer f.loader.Cleanup(!f.opts.IsModule)
 	defer level.Debug(f.log).Log("msg", "Alloy controller exiting")
 
+	// Blocks calling sys.secret are evaluated again when the secrets change.
+	secretsChanged := make(chan struct{}, 1)
+	if r := f.loader.SecretResolver(); r != nil {
+		cancel := r.OnSecretsChange(func() {
+			select {
+			case secretsChanged <- struct{}{}:
+			default:
+			}
+		})
+		defer cancel()
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 			return
 
+		case <-secretsChanged:
+			f.loader.EvaluateSecretUsers(ctx)
+
 		case <-f.updateQueue.Chan():
 			// Evaluate all nodes that have been updated. Sending the entire batch together will improve
 			// throughput - it prevents the situation where two nodes have the same dependency, and the first time
@@ -34,7 +34,7 @@ func (f *Runtime) GetComponent(id component.ID, opts component.InfoOptions) (*co
 		return nil, fmt.Errorf("%q is not a component", id)
 	}
 
-	return f.getComponentDetail(cn, graph, opts), nil
+	return f.getComponentDetail(cn, graph, f.nodeEvaluations(opts), opts), nil
 }
 
 // ListComponents implements [component.Provider].
@@ -52,18 +52,32 @@ func (f *Runtime) ListComponents(moduleID string, opts component.InfoOptions) ([
 	}
 
 	var (
-		components = f.loader.Components()
-		graph      = f.loader.Graph()
+		components  = f.loader.Components()
+		graph       = f.loader.Graph()
+		evaluations = f.nodeEvaluations(opts)
 	)
 
 	detail := make([]*component.Info, len(components))
 	for i, component := range components {
-		detail[i] = f.getComponentDetail(component, graph, opts)
+		detail[i] = f.getComponentDetail(component, graph, evaluations, opts)
 	}
 	return detail, nil
 }
 
-func (f *Runtime) getComponentDetail(cn controller.ComponentNode, graph *dag.Graph, opts component.InfoOptions) *component.Info {
+// nodeEvaluations returns the evaluations of the nodes of the graph keyed by
+// node ID, or nil if they weren't requested by opts.
+func (f *Runtime) nodeEvaluations(opts component.InfoOptions) map[string]controller.NodeEvaluation {
+	if !opts.GetEvaluation {
+		return nil
+	}
+	evaluations := make(map[string]controller.NodeEvaluation)
+	for _, e := range f.loader.EvaluationOrder() {
+		evaluations[e.NodeID] = e
+	}
+	return evaluations
+}
+
+func (f *Runtime) getComponentDetail(cn controller.ComponentNode, graph *dag.Graph, evaluations map[string]controller.NodeEvaluation, opts component.InfoOptions) *component.Info {
 	var references, referencedBy []string
 
 	// Skip over any edge which isn't between two component nodes. This is a
@@ -87,14 +101,18 @@ func (f *Runtime) getComponentDetail(cn controller.ComponentNode, graph *dag.Gra
 
 	// Fields which are optional to set.
 	var (
-		health    component.Health
-		arguments component.Arguments
-		exports   component.Exports
+		health        component.Health
+		healthHistory []component.Health
+		arguments     component.Arguments
+		exports       component.Exports
 	)
 
 	if opts.GetHealth {
 		health = cn.CurrentHealth()
 	}
+	if opts.GetHealthHistory {
+		healthHistory = cn.HealthHistory()
+	}
 	if opts.GetArguments {
 		arguments = cn.Arguments()
 	}
@@ -117,6 +135,7 @@ func (f *Runtime) getComponentDetail(cn controller.ComponentNode, graph *dag.Gra
 
 		ComponentName: cn.ComponentName(),
 		Health:        health,
+		HealthHistory: healthHistory,
 
 		Arguments: arguments,
 		Exports:   exports,
@@ -126,6 +145,7 @@ func (f *Runtime) getComponentDetail(cn controller.ComponentNode, graph *dag.Gra
 
 	if builtinComponent, ok := cn.(*controller.BuiltinComponentNode); ok {
 		componentInfo.Component = builtinComponent.Component()
+		componentInfo.FIPSViolations = builtinComponent.FIPSViolations()
 		if opts.GetDebugInfo {
 			componentInfo.DebugInfo = builtinComponent.DebugInfo()
 		}
@@ -134,6 +154,15 @@ func (f *Runtime) getComponentDetail(cn controller.ComponentNode, graph *dag.Gra
 	_, liveDebuggingEnabled := componentInfo.Component.(component.LiveDebugging)
 	componentInfo.LiveDebuggingEnabled = liveDebuggingEnabled
 
+	if e, ok := evaluations[cn.NodeID()]; ok {
+		componentInfo.Evaluation = &component.EvaluationInfo{
+			Order:         e.Order,
+			LastEvaluated: e.LastEvaluated,
+			LastDuration:  e.LastDuration,
+			LastQueueWait: e.LastQueueWait,
+		}
+	}
+
 	return componentInfo
 }
 
@@ -0,0 +1,131 @@
+package runtime
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/internal/testcomponents"
+	"github.com/stretchr/testify/require"
+)
+
+type fipsArguments struct {
+	Weak bool `alloy:"weak,attr,optional"`
+}
+
+// fipsChecks counts the calls to fipsArguments.CheckFIPS.
+var fipsChecks atomic.Int64
+
+func (args *fipsArguments) CheckFIPS() error {
+	fipsChecks.Add(1)
+	if args.Weak {
+		return errors.New("weak settings")
+	}
+	return nil
+}
+
+func fipsTestRegistry() component.Registry {
+	return component.NewRegistryMap(
+		featuregate.StabilityGenerallyAvailable,
+		true,
+		map[string]component.Registration{
+			"fips_test": {
+				Name:      "fips_test",
+				Stability: featuregate.StabilityGenerallyAvailable,
+				Args:      fipsArguments{},
+				Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+					return &testcomponents.Fake{}, nil
+				},
+			},
+		},
+	)
+}
+
+func TestEnforceFIPS(t *testing.T) {
+	registry := fipsTestRegistry()
+
+	cfg := `
+		fips_test "strong" {}
+		fips_test "weak" {
+			weak = true
+		}
+	`
+
+	tests := []struct {
+		name        string
+		enforceFIPS bool
+		expectErr   string
+	}{
+		{name: "enforced", enforceFIPS: true, expectErr: "arguments are not FIPS compliant: weak settings"},
+		{name: "not enforced", enforceFIPS: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer verifyNoGoroutineLeaks(t)
+
+			f, err := ParseSource(t.Name(), []byte(cfg))
+			require.NoError(t, err)
+
+			opts := testOptions(t)
+			opts.ComponentRegistry = registry
+			opts.EnforceFIPS = tt.enforceFIPS
+			ctrl := New(opts)
+			defer cleanUpController(t.Context(), ctrl)
+
+			err = ctrl.LoadSource(f, nil, "")
+			if tt.expectErr != "" {
+				require.ErrorContains(t, err, tt.expectErr)
+			} else {
+				require.NoError(t, err)
+			}
+
+			strong, err := ctrl.GetComponent(component.ID{LocalID: "fips_test.strong"}, component.InfoOptions{})
+			require.NoError(t, err)
+			require.NoError(t, strong.FIPSViolations)
+			require.NotNil(t, strong.Component)
+
+			weak, err := ctrl.GetComponent(component.ID{LocalID: "fips_test.weak"}, component.InfoOptions{})
+			require.NoError(t, err)
+			require.EqualError(t, weak.FIPSViolations, "weak settings")
+			require.Equal(t, tt.enforceFIPS, weak.Component == nil, "components which aren't compliant must only be built when FIPS isn't enforced")
+		})
+	}
+}
+
+func TestEnforceFIPS_ArgumentChanges(t *testing.T) {
+	defer verifyNoGoroutineLeaks(t)
+
+	opts := testOptions(t)
+	opts.ComponentRegistry = fipsTestRegistry()
+	opts.EnforceFIPS = true
+	ctrl := New(opts)
+	defer cleanUpController(t.Context(), ctrl)
+
+	load := func(cfg string) error {
+		f, err := ParseSource(t.Name(), []byte(cfg))
+		require.NoError(t, err)
+		return ctrl.LoadSource(f, nil, "")
+	}
+	violations := func() error {
+		info, err := ctrl.GetComponent(component.ID{LocalID: "fips_test.default"}, component.InfoOptions{})
+		require.NoError(t, err)
+		return info.FIPSViolations
+	}
+
+	require.NoError(t, load(`fips_test "default" {}`))
+	checks := fipsChecks.Load()
+
+	// Unchanged arguments aren't checked again.
+	require.NoError(t, load(`fips_test "default" {}`))
+	require.Equal(t, checks, fipsChecks.Load())
+
+	require.ErrorContains(t, load(`fips_test "default" { weak = true }`), "weak settings")
+	require.EqualError(t, violations(), "weak settings")
+
+	// Reverting to the applied arguments checks them again, since the last
+	// checked arguments were refused.
+	require.NoError(t, load(`fips_test "default" {}`))
+	require.NoError(t, violations())
+}
@@ -0,0 +1,70 @@
+package runtime
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/runtime/internal/controller"
+	"github.com/grafana/alloy/internal/runtime/internal/dag"
+)
+
+// Formats which the component graph can be written in by WriteGraph.
+const (
+	GraphFormatDOT  = "dot"
+	GraphFormatJSON = "json"
+)
+
+// WriteGraph writes the current graph of the module with the given ID, or of
+// the root module if moduleID is empty, to w in the given format. The graph
+// includes the configuration blocks and services of the module, the references
+// between them, and the data-flow edges between components.
+func (f *Runtime) WriteGraph(w io.Writer, moduleID string, format string) error {
+	if format != GraphFormatDOT && format != GraphFormatJSON {
+		return fmt.Errorf("unsupported graph format %q", format)
+	}
+
+	f.loadMut.RLock()
+	defer f.loadMut.RUnlock()
+
+	if moduleID != "" {
+		mod, ok := f.modules.Get(moduleID)
+		if !ok {
+			return component.ErrModuleNotFound
+		}
+
+		return mod.f.WriteGraph(w, "", format)
+	}
+
+	export := dag.NewExport(f.loader.Graph(), dag.ExportOptions{
+		Attributes:    graphNodeAttributes,
+		DataFlowEdges: graphDataFlowEdges,
+	})
+	if format == GraphFormatDOT {
+		return export.WriteDOT(w)
+	}
+	return export.WriteJSON(w)
+}
+
+func graphNodeAttributes(n dag.Node) map[string]string {
+	switch n := n.(type) {
+	case controller.ComponentNode:
+		return map[string]string{
+			"kind":   "component",
+			"type":   componentType(n).String(),
+			"name":   n.ComponentName(),
+			"health": n.CurrentHealth().Health.String(),
+		}
+	case *controller.ServiceNode:
+		return map[string]string{"kind": "service"}
+	default:
+		return map[string]string{"kind": "config"}
+	}
+}
+
+func graphDataFlowEdges(n dag.Node) []string {
+	if cn, ok := n.(controller.ComponentNode); ok {
+		return cn.GetDataFlowEdgesTo()
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package runtime
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestController_WriteGraph(t *testing.T) {
+	defer verifyNoGoroutineLeaks(t)
+	ctrl := New(testOptions(t))
+	defer cleanUpController(t.Context(), ctrl)
+
+	f, err := ParseSource(t.Name(), []byte(testFile))
+	require.NoError(t, err)
+	require.NoError(t, ctrl.LoadSource(f, nil, ""))
+
+	t.Run("json", func(t *testing.T) {
+		type edge struct {
+			From string `json:"from"`
+			To   string `json:"to"`
+			Type string `json:"type"`
+		}
+
+		var buf bytes.Buffer
+		require.NoError(t, ctrl.WriteGraph(&buf, "", GraphFormatJSON))
+
+		var graph struct {
+			Nodes []struct {
+				ID         string            `json:"id"`
+				Attributes map[string]string `json:"attributes"`
+			} `json:"nodes"`
+			Edges []edge `json:"edges"`
+		}
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &graph))
+
+		var ids []string
+		for _, n := range graph.Nodes {
+			ids = append(ids, n.ID)
+			if n.ID == "testcomponents.passthrough.static" {
+				require.Equal(t, "component", n.Attributes["kind"])
+				require.Equal(t, "builtin", n.Attributes["type"])
+				require.Equal(t, "testcomponents.passthrough", n.Attributes["name"])
+			}
+		}
+		require.Subset(t, ids, []string{
+			"testcomponents.passthrough.forwarded",
+			"testcomponents.passthrough.static",
+			"testcomponents.passthrough.ticker",
+			"testcomponents.tick.ticker",
+		})
+
+		require.Contains(t, graph.Edges, edge{"testcomponents.passthrough.ticker", "testcomponents.tick.ticker", "reference"})
+		require.Contains(t, graph.Edges, edge{"testcomponents.tick.ticker", "testcomponents.passthrough.ticker", "dataflow"})
+	})
+
+	t.Run("dot", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, ctrl.WriteGraph(&buf, "", GraphFormatDOT))
+		require.Contains(t, buf.String(), `"testcomponents.passthrough.forwarded" -> "testcomponents.passthrough.ticker";`)
+		require.Contains(t, buf.String(), `"testcomponents.passthrough.ticker" -> "testcomponents.passthrough.forwarded" [style="dashed"];`)
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		require.ErrorContains(t, ctrl.WriteGraph(&bytes.Buffer{}, "", "svg"), `unsupported graph format "svg"`)
+		require.Error(t, ctrl.WriteGraph(&bytes.Buffer{}, "missing", GraphFormatJSON))
+	})
+}
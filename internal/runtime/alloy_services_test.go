@@ -2,6 +2,7 @@ package runtime
 
 import (
 	"context"
+	"sync"
 	"testing"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/grafana/alloy/internal/runtime/internal/testservices"
 	"github.com/grafana/alloy/internal/service"
 	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax/alloytypes"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
 )
@@ -247,10 +249,10 @@ func TestComponents_Using_Services(t *testing.T) {
 	opts := testOptions(t)
 	opts.Services = append(opts.Services, existsSvc)
 
+	opts.ComponentRegistry = registry
 	ctrl := newController(controllerOptions{
-		Options:           opts,
-		ComponentRegistry: registry,
-		ModuleRegistry:    newModuleRegistry(),
+		Options:        opts,
+		ModuleRegistry: newModuleRegistry(),
 	})
 	require.NoError(t, ctrl.LoadSource(f, nil, ""))
 	go ctrl.Run(ctx)
@@ -326,10 +328,10 @@ func TestComponents_Using_Services_In_Modules(t *testing.T) {
 	opts := testOptions(t)
 	opts.Services = append(opts.Services, existsSvc)
 
+	opts.ComponentRegistry = registry
 	ctrl := newController(controllerOptions{
-		Options:           opts,
-		ComponentRegistry: registry,
-		ModuleRegistry:    newModuleRegistry(),
+		Options:        opts,
+		ModuleRegistry: newModuleRegistry(),
 	})
 	require.NoError(t, ctrl.LoadSource(f, nil, ""))
 	go ctrl.Run(ctx)
@@ -381,3 +383,98 @@ func makeEmptyFile(t *testing.T) *Source {
 
 	return f
 }
+
+func TestServices_SecretResolver(t *testing.T) {
+	defer verifyNoGoroutineLeaks(t)
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	resolver := &fakeSecretResolver{value: "hunter2"}
+	svc := &fakeSecretsService{
+		Fake: &testservices.Fake{
+			DefinitionFunc: func() service.Definition {
+				return service.Definition{Name: "fake", Stability: featuregate.StabilityExperimental}
+			},
+		},
+		fakeSecretResolver: resolver,
+	}
+
+	opts := testOptions(t)
+	opts.MinStability = featuregate.StabilityExperimental
+	opts.Services = append(opts.Services, svc)
+
+	ctrl := New(opts)
+	f, err := ParseSource(t.Name(), []byte(`
+		testcomponents.passthrough "secret" {
+			input = convert.nonsensitive(sys.secret("fake/db"))
+		}
+	`))
+	require.NoError(t, err)
+	require.NoError(t, ctrl.LoadSource(f, nil, ""))
+
+	input := func() string {
+		info, err := ctrl.GetComponent(component.ID{LocalID: "testcomponents.passthrough.secret"}, component.InfoOptions{GetArguments: true})
+		require.NoError(t, err)
+		return info.Arguments.(testcomponents.PassthroughConfig).Input
+	}
+	require.Equal(t, "hunter2", input())
+
+	done := make(chan struct{})
+	go func() {
+		ctrl.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	// The blocks calling sys.secret are evaluated again when the secrets
+	// change.
+	require.Eventually(t, func() bool { return resolver.subscribed() }, 5*time.Second, 10*time.Millisecond)
+	resolver.set("changed")
+	require.Eventually(t, func() bool { return input() == "changed" }, 5*time.Second, 10*time.Millisecond)
+}
+
+// fakeSecretsService is a service which resolves secrets.
+type fakeSecretsService struct {
+	*testservices.Fake
+	*fakeSecretResolver
+}
+
+type fakeSecretResolver struct {
+	mut      sync.Mutex
+	value    string
+	onChange func()
+}
+
+func (r *fakeSecretResolver) ResolveSecret(path string) (alloytypes.Secret, error) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	return alloytypes.Secret(r.value), nil
+}
+
+func (r *fakeSecretResolver) OnSecretsChange(f func()) (cancel func()) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	r.onChange = f
+	return func() {
+		r.mut.Lock()
+		defer r.mut.Unlock()
+		r.onChange = nil
+	}
+}
+
+func (r *fakeSecretResolver) subscribed() bool {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	return r.onChange != nil
+}
+
+func (r *fakeSecretResolver) set(value string) {
+	r.mut.Lock()
+	r.value = value
+	onChange := r.onChange
+	r.mut.Unlock()
+	onChange()
+}
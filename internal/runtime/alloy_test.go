@@ -126,6 +126,40 @@ func TestController_LoadSource_WithModulePathWithoutFileExtension_Evaluation(t *
 	require.Equal(t, filepath.Join("tmp_modulePath_test", "test"), out.(testcomponents.PassthroughExports).Output)
 }
 
+func TestController_LoadSource_IncrementalEvaluation(t *testing.T) {
+	defer verifyNoGoroutineLeaks(t)
+	ctrl := New(testOptions(t))
+	defer cleanUpController(t.Context(), ctrl)
+
+	configPath := filepath.Join(t.TempDir(), "main.alloy")
+	require.NoError(t, os.WriteFile(configPath, []byte(""), 0664))
+
+	load := func(input string) {
+		f, err := ParseSource(t.Name(), []byte(`
+			testcomponents.passthrough "static" {
+				input = module_path
+			}
+			testcomponents.passthrough "changed" {
+				input = "`+input+`"
+			}
+		`))
+		require.NoError(t, err)
+		require.NoError(t, ctrl.LoadSource(f, nil, configPath))
+	}
+
+	load("a")
+	require.True(t, ctrl.loader.LastApplyStats().Full)
+
+	// LoadSource passes the module path in the argument scope on every load,
+	// which must not evaluate the unchanged blocks again. The default logging
+	// and tracing blocks are unchanged too.
+	load("b")
+	require.Equal(t, controller.ApplyStats{Changed: 1, Unchanged: 3, Evaluated: 1}, ctrl.loader.LastApplyStats())
+
+	in, _ := getFields(t, ctrl.loader.Graph(), "testcomponents.passthrough.static")
+	require.Equal(t, filepath.Dir(configPath), in.(testcomponents.PassthroughConfig).Input)
+}
+
 // This test reloads the config a few times and checks that Alloy does not log errors.
 // The ticker has a very small frequency to put pressure on the concurrent evaluations happening
 // in the runtime while the loader is concurrently reloading the config.
@@ -180,6 +214,51 @@ func TestController_ReloadLoaderNoErrorLog(t *testing.T) {
 	require.False(t, strings.Contains(logsBuffer.String(), "level=error"))
 }
 
+func TestController_DryRun(t *testing.T) {
+	opts := testOptions(t)
+	opts.DryRun = true
+	ctrl := New(opts)
+	defer ctrl.loader.Cleanup(true)
+
+	f, err := ParseSource(t.Name(), []byte(`
+		declare "wrapper" {
+			argument "input" {}
+
+			testcomponents.passthrough "inner" {
+				input = argument.input.value
+			}
+		}
+
+		wrapper "default" {
+			input = testcomponents.passthrough.static.output
+		}
+	`+testFile))
+	require.NoError(t, err)
+
+	require.NoError(t, ctrl.LoadSource(f, nil, ""))
+	require.Len(t, ctrl.loader.Components(), 5)
+
+	// Arguments are decoded, but components aren't built so their exports keep
+	// their zero value.
+	in, out := getFields(t, ctrl.loader.Graph(), "testcomponents.passthrough.static")
+	require.Equal(t, "hello, world!", in.(testcomponents.PassthroughConfig).Input)
+	require.Equal(t, "", out.(testcomponents.PassthroughExports).Output)
+	for _, cn := range ctrl.loader.Components() {
+		if bcn, ok := cn.(*controller.BuiltinComponentNode); ok {
+			require.Nil(t, bcn.Component(), bcn.NodeID())
+		}
+	}
+
+	// Invalid arguments are still reported.
+	f, err = ParseSource(t.Name(), []byte(`
+		testcomponents.tick "ticker" {
+			frequency = "not a duration"
+		}
+	`))
+	require.NoError(t, err)
+	require.ErrorContains(t, ctrl.LoadSource(f, nil, ""), "not a duration")
+}
+
 func getFields(t *testing.T, g *dag.Graph, nodeID string) (component.Arguments, component.Exports) {
 	t.Helper()
 
@@ -2,11 +2,13 @@ package runtime_test
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"regexp"
 	"testing"
 	"time"
 
+	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/runtime"
 	"github.com/grafana/alloy/internal/runtime/internal/testcomponents"
@@ -327,6 +329,80 @@ func TestDeclare(t *testing.T) {
 			`,
 			expected: 10,
 		},
+		{
+			name: "Function",
+			config: `
+			declare "math" {
+				function "scale" {
+					params = ["x", "factor"]
+					value  = x * factor
+				}
+			}
+			testcomponents.count "inc" {
+				frequency = "10ms"
+				max = 10
+			}
+
+			testcomponents.summation "sum" {
+				input = math.scale(testcomponents.count.inc.count, -2)
+			}
+			`,
+			expected: -20,
+		},
+		{
+			name: "FunctionInDeclare",
+			config: `
+			declare "math" {
+				function "negate" {
+					params = ["x"]
+					value  = -x
+				}
+
+				argument "input" {
+					optional = false
+				}
+
+				export "output" {
+					value = math.negate(argument.input.value)
+				}
+			}
+			testcomponents.count "inc" {
+				frequency = "10ms"
+				max = 10
+			}
+
+			math "myModule" {
+				input = testcomponents.count.inc.count
+			}
+
+			testcomponents.summation "sum" {
+				input = math.myModule.output
+			}
+			`,
+			expected: -10,
+		},
+		{
+			name: "ImportedFunction",
+			config: `
+			import.string "lib" {
+				content = ` + "`" + `declare "math" {
+					function "negate" {
+						params = ["x"]
+						value  = -x
+					}
+				}` + "`" + `
+			}
+			testcomponents.count "inc" {
+				frequency = "10ms"
+				max = 10
+			}
+
+			testcomponents.summation "sum" {
+				input = lib.math.negate(testcomponents.count.inc.count)
+			}
+			`,
+			expected: -10,
+		},
 	}
 
 	for _, tc := range tt {
@@ -482,6 +558,62 @@ func TestDeclareError(t *testing.T) {
 			`,
 			expectedError: regexp.MustCompile(`'declare' is not a valid label for a declare block`),
 		},
+		{
+			name: "FunctionArguments",
+			config: `
+			declare "math" {
+				function "negate" {
+					params = ["x"]
+					value  = -x
+				}
+			}
+			testcomponents.summation "sum" {
+				input = math.negate(1, 2)
+			}
+			`,
+			expectedError: regexp.MustCompile(`function negate expects 1 arguments, got 2`),
+		},
+		{
+			name: "FunctionReferencesComponent",
+			config: `
+			testcomponents.count "inc" {
+				frequency = "10ms"
+				max = 10
+			}
+			declare "math" {
+				function "count" {
+					value = testcomponents.count.inc.count
+				}
+			}
+			testcomponents.summation "sum" {
+				input = math.count()
+			}
+			`,
+			expectedError: regexp.MustCompile(`identifier "testcomponents" does not exist`),
+		},
+		{
+			name: "FunctionShadowedByComponent",
+			config: `
+			declare "math" {
+				function "default" {
+					value = 1
+				}
+			}
+			math "default" {}
+			`,
+			expectedError: regexp.MustCompile(`component math.default has the same name as a function of its declare block`),
+		},
+		{
+			name: "FunctionWithoutValue",
+			config: `
+			declare "math" {
+				function "one" {
+					params = ["x"]
+				}
+			}
+			`,
+			expectedError: regexp.MustCompile(`missing required attribute value in function one`),
+		},
 	}
 	for _, tc := range tt {
 		t.Run(tc.name, func(t *testing.T) {
@@ -617,3 +749,56 @@ func TestDeclareUpdateConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestDeclareUpdateBodyKeepsState(t *testing.T) {
+	config := func(input int) string {
+		return fmt.Sprintf(`
+			declare "test" {
+				testcomponents.summation "changed" {
+					input = %d
+				}
+
+				testcomponents.summation "unchanged" {
+					input = 1
+				}
+			}
+
+			test "myModule" {}
+		`, input)
+	}
+
+	ctrl := runtime.New(testOptions(t))
+	f, err := runtime.ParseSource(t.Name(), []byte(config(1)))
+	require.NoError(t, err)
+	require.NoError(t, ctrl.LoadSource(f, nil, ""))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	done := make(chan struct{})
+	go func() {
+		ctrl.Run(ctx)
+		close(done)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	require.Eventually(t, func() bool {
+		_, err := ctrl.GetComponent(component.ID{ModuleID: "test.myModule", LocalID: "testcomponents.summation.changed"}, component.InfoOptions{})
+		return err == nil
+	}, 3*time.Second, 10*time.Millisecond)
+
+	// Editing the body of the declare block updates the components of the
+	// existing custom component instead of building new ones, so their state
+	// survives: the sum of the changed component includes the previous input.
+	f, err = runtime.ParseSource(t.Name(), []byte(config(2)))
+	require.NoError(t, err)
+	require.NoError(t, ctrl.LoadSource(f, nil, ""))
+
+	changed := getExport[testcomponents.SummationExports](t, ctrl, "test.myModule", "testcomponents.summation.changed")
+	require.Equal(t, testcomponents.SummationExports{Sum: 3, LastAdded: 2}, changed)
+
+	// The unchanged component isn't updated again.
+	unchanged := getExport[testcomponents.SummationExports](t, ctrl, "test.myModule", "testcomponents.summation.unchanged")
+	require.Equal(t, testcomponents.SummationExports{Sum: 1, LastAdded: 1}, unchanged)
+}
@@ -10,6 +10,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/featuregate"
 	alloy_runtime "github.com/grafana/alloy/internal/runtime"
 	"github.com/grafana/alloy/internal/runtime/internal/testcomponents"
@@ -132,6 +133,194 @@ func TestImportString(t *testing.T) {
 	}
 }
 
+func TestImportFrom(t *testing.T) {
+	directory := "./testdata/import_from"
+	for _, file := range getTestFiles(directory, t) {
+		archive, err := txtar.ParseFile(filepath.Join(directory, file.Name()))
+		require.NoError(t, err)
+		t.Run(archive.Files[0].Name, func(t *testing.T) {
+			testConfig(t, string(archive.Files[0].Data), "", nil)
+		})
+	}
+}
+
+func TestImportStability(t *testing.T) {
+	defer verifyNoGoroutineLeaks(t)
+
+	config := func(stability string) string {
+		return `
+			import.string "testImport" {
+				stability = "` + stability + `"
+				content   = ` + "`" + `declare "test" {
+					testcomponents.experimental "unstable" {}
+				}` + "`" + `
+			}
+
+			testImport.test "myModule" {}
+		`
+	}
+	ctrl, f := setup(t, config("experimental"), nil, featuregate.StabilityPublicPreview)
+	require.NoError(t, ctrl.LoadSource(f, nil, ""))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ctrl.Run(ctx)
+	}()
+
+	experimentalRunning := func() bool {
+		_, err := ctrl.GetComponent(component.ID{
+			ModuleID: "testImport.test.myModule",
+			LocalID:  "testcomponents.experimental.unstable",
+		}, component.InfoOptions{})
+		return err == nil
+	}
+	require.Eventually(t, experimentalRunning, 3*time.Second, 10*time.Millisecond)
+
+	// The custom component is recreated with the new stability level.
+	f, err := alloy_runtime.ParseSource(t.Name(), []byte(config("public-preview")))
+	require.NoError(t, err)
+	require.ErrorContains(t, ctrl.LoadSource(f, nil, ""), `component "testcomponents.experimental" is at stability level "experimental", which is below the minimum allowed stability level "public-preview"`)
+	require.Eventually(t, func() bool { return !experimentalRunning() }, 3*time.Second, 10*time.Millisecond)
+
+	f, err = alloy_runtime.ParseSource(t.Name(), []byte(config("experimental")))
+	require.NoError(t, err)
+	require.NoError(t, ctrl.LoadSource(f, nil, ""))
+	require.Eventually(t, experimentalRunning, 3*time.Second, 10*time.Millisecond)
+}
+
+func TestImportExportReferences(t *testing.T) {
+	defer verifyNoGoroutineLeaks(t)
+
+	modulePath := filepath.Join(t.TempDir(), "module.alloy")
+	module := func(export string) string {
+		return `declare "a" {
+			export "` + export + `" {
+				value = 10
+				type  = "number"
+			}
+		}`
+	}
+	require.NoError(t, os.WriteFile(modulePath, []byte(module("output")), 0664))
+
+	config := `
+		import.file "testImport" {
+			filename = "` + filepath.ToSlash(modulePath) + `"
+		}
+
+		testImport.a "cc" {}
+
+		testcomponents.summation "sum" {
+			input = testImport.a.cc.output
+		}
+	`
+	ctrl, f := setup(t, config, nil, featuregate.StabilityPublicPreview)
+	require.NoError(t, ctrl.LoadSource(f, nil, ""))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ctrl.Run(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		export := getExport[testcomponents.SummationExports](t, ctrl, "", "testcomponents.summation.sum")
+		return export.LastAdded == 10
+	}, 3*time.Second, 10*time.Millisecond)
+
+	// The update of the module removes the export used by the summation, which
+	// is reported by the custom component.
+	require.NoError(t, os.WriteFile(modulePath, []byte(module("renamed")), 0664))
+	require.Eventually(t, func() bool {
+		info, err := ctrl.GetComponent(component.ID{LocalID: "testImport.a.cc"}, component.InfoOptions{GetHealth: true})
+		require.NoError(t, err)
+		return info.Health.Health == component.HealthTypeUnhealthy &&
+			strings.Contains(info.Health.Message, `the definition of testImport.a doesn't export "output" (referenced by testcomponents.summation.sum at`)
+	}, 3*time.Second, 10*time.Millisecond)
+}
+
+func TestImportExpectations(t *testing.T) {
+	defer verifyNoGoroutineLeaks(t)
+
+	modulePath := filepath.Join(t.TempDir(), "module.alloy")
+	module := func(value string, typ string) string {
+		return `declare "a" {
+			export "output" {
+				value = ` + value + `
+				type  = "` + typ + `"
+			}
+		}`
+	}
+	require.NoError(t, os.WriteFile(modulePath, []byte(module("10", "number")), 0664))
+
+	config := `
+		import.file "testImport" {
+			filename = "` + filepath.ToSlash(modulePath) + `"
+
+			expect "a" {
+				exports = {
+					output = "number",
+				}
+			}
+		}
+
+		testImport.a "cc" {}
+
+		testcomponents.summation "sum" {
+			input = testImport.a.cc.output
+		}
+	`
+	ctrl, f := setup(t, config, nil, featuregate.StabilityPublicPreview)
+	require.NoError(t, ctrl.LoadSource(f, nil, ""))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	var wg sync.WaitGroup
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ctrl.Run(ctx)
+	}()
+
+	require.Eventually(t, func() bool {
+		export := getExport[testcomponents.SummationExports](t, ctrl, "", "testcomponents.summation.sum")
+		return export.LastAdded == 10
+	}, 3*time.Second, 10*time.Millisecond)
+
+	// The update of the module no longer declares the type of the export, so
+	// the import block keeps the previous definition.
+	require.NoError(t, os.WriteFile(modulePath, []byte(module("-5", "any")), 0664))
+	require.Never(t, func() bool {
+		export := getExport[testcomponents.SummationExports](t, ctrl, "", "testcomponents.summation.sum")
+		return export.LastAdded != 10
+	}, 500*time.Millisecond, 10*time.Millisecond)
+
+	// A compatible update is applied.
+	require.NoError(t, os.WriteFile(modulePath, []byte(module("-10", "number")), 0664))
+	require.Eventually(t, func() bool {
+		export := getExport[testcomponents.SummationExports](t, ctrl, "", "testcomponents.summation.sum")
+		return export.LastAdded == -10
+	}, 3*time.Second, 10*time.Millisecond)
+}
+
 func TestImportGit(t *testing.T) {
 	// Extract repo.git.tar so tests can make use of it.
 	// Make repo.git.tar with:
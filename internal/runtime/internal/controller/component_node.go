@@ -11,6 +11,10 @@ type ComponentNode interface {
 	// CurrentHealth returns the current health of the component.
 	CurrentHealth() component.Health
 
+	// HealthHistory returns the recent health transitions of the component,
+	// ordered from newest to oldest.
+	HealthHistory() []component.Health
+
 	// Arguments returns the current arguments of the managed component.
 	Arguments() component.Arguments
 
@@ -111,6 +111,21 @@ func expressionsFromBody(body ast.Body) []Traversal {
 	return w.traversals
 }
 
+// usesStdlibSecret returns true if the block of n calls sys.secret, whose
+// results can change without any change of the block or its dependencies.
+func usesStdlibSecret(n dag.Node) bool {
+	bn, ok := n.(BlockNode)
+	if !ok || bn.Block() == nil {
+		return false
+	}
+	for _, t := range expressionsFromBody(bn.Block().Body) {
+		if t.String() == "sys.secret" {
+			return true
+		}
+	}
+	return false
+}
+
 type traversalWalker struct {
 	traversals []Traversal
 
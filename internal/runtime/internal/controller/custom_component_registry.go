@@ -2,8 +2,10 @@ package controller
 
 import (
 	"fmt"
+	"maps"
 	"sync"
 
+	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/syntax/ast"
 	"github.com/grafana/alloy/syntax/vm"
 )
@@ -14,20 +16,24 @@ import (
 type CustomComponentRegistry struct {
 	parent *CustomComponentRegistry // nil if root config
 
-	mut      sync.RWMutex
-	scope    *vm.Scope
-	imports  map[string]*CustomComponentRegistry // importNamespace: importScope
-	declares map[string]ast.Body                 // customComponentName: template
+	mut       sync.RWMutex
+	scope     *vm.Scope
+	imports   map[string]*CustomComponentRegistry // importNamespace: importScope
+	declares  map[string]ast.Body                 // customComponentName: template
+	functions map[string]map[string]any           // customComponentName: functionName: function
+
+	minStability featuregate.Stability // Undefined if inherited from the parent
 }
 
 // NewCustomComponentRegistry creates a new CustomComponentRegistry with a parent.
 // parent can be nil.
 func NewCustomComponentRegistry(parent *CustomComponentRegistry, scope *vm.Scope) *CustomComponentRegistry {
 	return &CustomComponentRegistry{
-		parent:   parent,
-		scope:    scope,
-		declares: make(map[string]ast.Body),
-		imports:  make(map[string]*CustomComponentRegistry),
+		parent:    parent,
+		scope:     scope,
+		declares:  make(map[string]ast.Body),
+		functions: make(map[string]map[string]any),
+		imports:   make(map[string]*CustomComponentRegistry),
 	}
 }
 
@@ -45,17 +51,76 @@ func (s *CustomComponentRegistry) getImport(name string) (*CustomComponentRegist
 	return im, ok
 }
 
+// MinStability returns the minimum stability level of the custom components
+// of the registry. It returns StabilityUndefined if neither the registry nor
+// its parents set a stability level.
+func (s *CustomComponentRegistry) MinStability() featuregate.Stability {
+	s.mut.RLock()
+	minStability := s.minStability
+	s.mut.RUnlock()
+
+	if minStability == featuregate.StabilityUndefined && s.parent != nil {
+		return s.parent.MinStability()
+	}
+	return minStability
+}
+
 func (s *CustomComponentRegistry) Scope() *vm.Scope {
 	s.mut.RLock()
 	defer s.mut.RUnlock()
 	return s.scope
 }
 
-// registerDeclare stores a local declare block.
-func (s *CustomComponentRegistry) registerDeclare(declare *ast.BlockStmt) {
+// Functions returns the functions of the declare blocks available in the
+// registry, keyed by the name of the custom component. The functions of
+// imported declare blocks are nested under their import namespace.
+func (s *CustomComponentRegistry) Functions() map[string]any {
+	functions := s.declaredFunctions()
+
+	s.mut.RLock()
+	for namespace, imported := range s.imports {
+		// The namespace is exposed before the import is evaluated so
+		// references to its functions can be wired to the import node.
+		if imported == nil {
+			functions[namespace] = make(map[string]any)
+		} else {
+			functions[namespace] = imported.declaredFunctions()
+		}
+	}
+	s.mut.RUnlock()
+
+	// Local declare blocks and imports shadow the ones of the parents.
+	if s.parent != nil {
+		for name, fns := range s.parent.Functions() {
+			if _, ok := functions[name]; !ok {
+				functions[name] = fns
+			}
+		}
+	}
+	return functions
+}
+
+// declaredFunctions returns the functions of the declare blocks stored in the
+// registry, keyed by the name of the custom component.
+func (s *CustomComponentRegistry) declaredFunctions() map[string]any {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+	functions := make(map[string]any, len(s.functions))
+	for name, fns := range s.functions {
+		functions[name] = maps.Clone(fns)
+	}
+	return functions
+}
+
+// registerDeclare stores a local declare block, split into its template and
+// its functions.
+func (s *CustomComponentRegistry) registerDeclare(declare *ast.BlockStmt, template ast.Body, functions map[string]any) {
 	s.mut.Lock()
 	defer s.mut.Unlock()
-	s.declares[declare.Label] = declare.Body
+	s.declares[declare.Label] = template
+	if len(functions) > 0 {
+		s.functions[declare.Label] = functions
+	}
 }
 
 // registerImport stores the import namespace.
@@ -80,6 +145,8 @@ func (s *CustomComponentRegistry) updateImportContent(importNode *ImportConfigNo
 	}
 	importScope := NewCustomComponentRegistry(nil, importNode.Scope())
 	importScope.declares = importNode.ImportedDeclares()
+	importScope.functions = importNode.ImportedFunctions()
+	importScope.minStability = importNode.MinStability()
 	importScope.updateImportContentChildren(importNode)
 	s.imports[importNode.label] = importScope
 }
@@ -90,6 +157,8 @@ func (s *CustomComponentRegistry) updateImportContentChildren(importNode *Import
 	for _, child := range importNode.ImportConfigNodesChildren() {
 		childScope := NewCustomComponentRegistry(nil, child.Scope())
 		childScope.declares = child.ImportedDeclares()
+		childScope.functions = child.ImportedFunctions()
+		childScope.minStability = child.MinStability()
 		childScope.updateImportContentChildren(child)
 		s.imports[child.label] = childScope
 	}
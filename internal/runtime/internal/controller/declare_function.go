@@ -0,0 +1,175 @@
+package controller
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/syntax/ast"
+	"github.com/grafana/alloy/syntax/diag"
+	"github.com/grafana/alloy/syntax/scanner"
+	"github.com/grafana/alloy/syntax/vm"
+)
+
+// functionType is the name of the blocks defining functions in declare blocks.
+const functionType = "function"
+
+// declareFunction is a pure function defined in a declare block. Its value is
+// evaluated on every call with its parameters as the only variables.
+type declareFunction struct {
+	name   string
+	params []string
+	value  ast.Expr
+}
+
+// call evaluates the function with the given arguments.
+func (fn *declareFunction) call(args ...any) (any, error) {
+	if len(args) != len(fn.params) {
+		return nil, fmt.Errorf("function %s expects %d arguments, got %d", fn.name, len(fn.params), len(args))
+	}
+	vars := make(map[string]any, len(fn.params))
+	for i, param := range fn.params {
+		vars[param] = args[i]
+	}
+
+	var res any
+	if err := vm.New(fn.value).Evaluate(vm.NewScope(vars), &res); err != nil {
+		return nil, fmt.Errorf("function %s: %w", fn.name, err)
+	}
+	return res, nil
+}
+
+// splitDeclareBody splits the body of a declare block into the template used
+// to instantiate the custom component and the functions it exports, keyed by
+// name.
+func splitDeclareBody(body ast.Body, minStability featuregate.Stability) (ast.Body, map[string]any, diag.Diagnostics) {
+	var (
+		diags     diag.Diagnostics
+		template  = make(ast.Body, 0, len(body))
+		functions = make(map[string]any)
+		defined   = make(map[string]*ast.BlockStmt)
+	)
+
+	for _, stmt := range body {
+		block, ok := stmt.(*ast.BlockStmt)
+		if !ok || block.GetBlockName() != functionType {
+			template = append(template, stmt)
+			continue
+		}
+
+		if orig, ok := defined[block.Label]; ok {
+			diags.Add(diag.Diagnostic{
+				Severity: diag.SeverityLevelError,
+				Message:  fmt.Sprintf("function %q already declared at %s", block.Label, ast.StartPos(orig).Position()),
+				StartPos: ast.StartPos(block).Position(),
+				EndPos:   ast.EndPos(block).Position(),
+			})
+			continue
+		}
+		defined[block.Label] = block
+
+		fn, fnDiags := parseDeclareFunction(block, minStability)
+		diags = append(diags, fnDiags...)
+		if fnDiags.HasErrors() {
+			continue
+		}
+		functions[fn.name] = fn.call
+	}
+	return template, functions, diags
+}
+
+// parseDeclareFunction parses a function block.
+func parseDeclareFunction(block *ast.BlockStmt, minStability featuregate.Stability) (*declareFunction, diag.Diagnostics) {
+	var diags diag.Diagnostics
+	errorf := func(node ast.Node, format string, args ...any) {
+		diags.Add(diag.Diagnostic{
+			Severity: diag.SeverityLevelError,
+			Message:  fmt.Sprintf(format, args...),
+			StartPos: ast.StartPos(node).Position(),
+			EndPos:   ast.EndPos(node).Position(),
+		})
+	}
+
+	fn := &declareFunction{name: block.Label}
+	if !scanner.IsValidIdentifier(fn.name) {
+		errorf(block, "function name %q must be a valid identifier", fn.name)
+		return nil, diags
+	}
+
+	for _, stmt := range block.Body {
+		attr, ok := stmt.(*ast.AttributeStmt)
+		if !ok {
+			errorf(stmt, "function blocks only support the params and value attributes")
+			continue
+		}
+
+		switch attr.Name.Name {
+		case "params":
+			if err := vm.New(attr.Value).Evaluate(vm.NewScope(nil), &fn.params); err != nil {
+				errorf(attr, "params must be a list of strings: %s", err)
+				continue
+			}
+			seen := make(map[string]struct{}, len(fn.params))
+			for _, param := range fn.params {
+				if !scanner.IsValidIdentifier(param) {
+					errorf(attr, "parameter %q must be a valid identifier", param)
+				}
+				if _, ok := seen[param]; ok {
+					errorf(attr, "parameter %q is declared more than once", param)
+				}
+				seen[param] = struct{}{}
+			}
+		case "value":
+			fn.value = attr.Value
+		default:
+			errorf(attr, "unrecognized attribute %s in function block", attr.Name.Name)
+		}
+	}
+
+	if fn.value == nil {
+		errorf(block, "missing required attribute value in function %s", fn.name)
+		return nil, diags
+	}
+
+	// The value is evaluated without the controller, so experimental stdlib
+	// functions must be checked here.
+	var w traversalWalker
+	ast.Walk(&w, fn.value)
+	w.flush()
+	scope := vm.NewScope(nil)
+	for _, t := range w.traversals {
+		if name := t.String(); scope.IsStdlibExperimental(name) {
+			if err := featuregate.CheckAllowed(featuregate.StabilityExperimental, minStability, name); err != nil {
+				errorf(t[0], "%s", err)
+			}
+		}
+	}
+
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	return fn, diags
+}
+
+// mergeFunctions adds the functions of src which aren't set in dst to dst.
+// Functions of dst are replaced, since they're older copies of the functions
+// of a parent module, which the argument scope of nested modules holds. Nested
+// maps of functions are merged with the maps of dst, which are copied before
+// they are modified.
+func mergeFunctions(dst, src map[string]any) {
+	for k, v := range src {
+		existing, ok := dst[k]
+		if _, isFunction := existing.(func(...any) (any, error)); !ok || isFunction {
+			dst[k] = v
+			continue
+		}
+		existingMap, ok := existing.(map[string]any)
+		srcMap, srcOk := v.(map[string]any)
+		if !ok || !srcOk {
+			continue
+		}
+		merged := maps.Clone(existingMap)
+		mergeFunctions(merged, srcMap)
+		dst[k] = merged
+	}
+}
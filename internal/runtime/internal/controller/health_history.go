@@ -0,0 +1,56 @@
+package controller
+
+import (
+	"sync"
+
+	"github.com/grafana/alloy/internal/component"
+)
+
+// maxHealthHistory is the maximum number of health entries retained for a
+// single component node.
+const maxHealthHistory = 50
+
+// healthHistory is a bounded record of the transitions of the combined health
+// of a component node. It allows transient errors which have since resolved to still be
+// inspected after the fact.
+//
+// The zero value is ready for use.
+type healthHistory struct {
+	mut     sync.RWMutex
+	entries []component.Health // Ordered from oldest to newest.
+}
+
+// record appends h to the history if it represents a transition. A health
+// entry is considered a transition if its type differs from the previously
+// recorded entry, or if it is a non-healthy entry with a new message.
+//
+// Once the history is full, the oldest entry is discarded.
+func (hh *healthHistory) record(h component.Health) {
+	hh.mut.Lock()
+	defer hh.mut.Unlock()
+
+	if n := len(hh.entries); n > 0 {
+		last := hh.entries[n-1]
+		if last.Health == h.Health && (h.Health == component.HealthTypeHealthy || last.Message == h.Message) {
+			return
+		}
+	}
+
+	if len(hh.entries) >= maxHealthHistory {
+		copy(hh.entries, hh.entries[1:])
+		hh.entries = hh.entries[:len(hh.entries)-1]
+	}
+	hh.entries = append(hh.entries, h)
+}
+
+// list returns a copy of the recorded history, ordered from newest to oldest.
+func (hh *healthHistory) list() []component.Health {
+	hh.mut.RLock()
+	defer hh.mut.RUnlock()
+
+	res := make([]component.Health, len(hh.entries))
+	for i, h := range hh.entries {
+		res[len(hh.entries)-1-i] = h
+	}
+	return res
+}
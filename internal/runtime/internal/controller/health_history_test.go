@@ -0,0 +1,110 @@
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component"
+)
+
+func TestHealthHistory(t *testing.T) {
+	var (
+		hh  healthHistory
+		now = time.Now()
+	)
+
+	hh.record(component.Health{Health: component.HealthTypeHealthy, Message: "component evaluated", UpdateTime: now})
+	// Repeated healthy entries aren't transitions, even with a new message.
+	hh.record(component.Health{Health: component.HealthTypeHealthy, Message: "started component", UpdateTime: now.Add(time.Second)})
+	hh.record(component.Health{Health: component.HealthTypeUnhealthy, Message: "error 1", UpdateTime: now.Add(2 * time.Second)})
+	// Identical errors are only recorded once.
+	hh.record(component.Health{Health: component.HealthTypeUnhealthy, Message: "error 1", UpdateTime: now.Add(3 * time.Second)})
+	hh.record(component.Health{Health: component.HealthTypeUnhealthy, Message: "error 2", UpdateTime: now.Add(4 * time.Second)})
+	hh.record(component.Health{Health: component.HealthTypeHealthy, Message: "component evaluated", UpdateTime: now.Add(5 * time.Second)})
+
+	expect := []component.Health{
+		{Health: component.HealthTypeHealthy, Message: "component evaluated", UpdateTime: now.Add(5 * time.Second)},
+		{Health: component.HealthTypeUnhealthy, Message: "error 2", UpdateTime: now.Add(4 * time.Second)},
+		{Health: component.HealthTypeUnhealthy, Message: "error 1", UpdateTime: now.Add(2 * time.Second)},
+		{Health: component.HealthTypeHealthy, Message: "component evaluated", UpdateTime: now},
+	}
+	require.Equal(t, expect, hh.list())
+}
+
+func TestHealthHistory_Bounded(t *testing.T) {
+	var hh healthHistory
+
+	for i := 0; i < maxHealthHistory+10; i++ {
+		hh.record(component.Health{Health: component.HealthTypeUnhealthy, Message: fmt.Sprintf("error %d", i)})
+	}
+
+	list := hh.list()
+	require.Len(t, list, maxHealthHistory)
+	require.Equal(t, fmt.Sprintf("error %d", maxHealthHistory+9), list[0].Message)
+	require.Equal(t, "error 10", list[len(list)-1].Message)
+}
+
+func TestBuiltinComponentNode_HealthHistory(t *testing.T) {
+	managed := &fakeHealthComponent{}
+	managed.setHealth(component.Health{Health: component.HealthTypeHealthy, UpdateTime: time.Now()})
+	cn := &BuiltinComponentNode{managed: managed}
+
+	// The node is unknown until it runs.
+	cn.setEvalHealth(component.HealthTypeHealthy, "component evaluated")
+	cn.setRunHealth(component.HealthTypeHealthy, "started component")
+
+	// A change of the health reported by the component is recorded when it's
+	// observed, and only once.
+	managed.setHealth(component.Health{Health: component.HealthTypeUnhealthy, Message: "error 1", UpdateTime: time.Now()})
+	cn.CurrentHealth()
+	cn.CurrentHealth()
+
+	// A failed evaluation doesn't change the combined health if the component
+	// was already unhealthy.
+	cn.setEvalHealth(component.HealthTypeUnhealthy, "error 1")
+	managed.setHealth(component.Health{Health: component.HealthTypeHealthy, UpdateTime: time.Now()})
+	cn.setEvalHealth(component.HealthTypeHealthy, "component evaluated")
+
+	var types []component.HealthType
+	var messages []string
+	for _, h := range cn.HealthHistory() {
+		types = append(types, h.Health)
+		messages = append(messages, h.Message)
+	}
+	require.Equal(t, []component.HealthType{component.HealthTypeHealthy, component.HealthTypeUnhealthy, component.HealthTypeHealthy, component.HealthTypeUnknown}, types)
+	require.Equal(t, []string{"component evaluated", "error 1", "started component", ""}, messages)
+
+	// Reading the history records a change of the reported health which hasn't
+	// been observed yet.
+	managed.setHealth(component.Health{Health: component.HealthTypeUnhealthy, Message: "error 2", UpdateTime: time.Now()})
+	require.Equal(t, "error 2", cn.HealthHistory()[0].Message)
+}
+
+type fakeHealthComponent struct {
+	mut    sync.Mutex
+	health component.Health
+}
+
+func (c *fakeHealthComponent) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (c *fakeHealthComponent) Update(component.Arguments) error { return nil }
+
+func (c *fakeHealthComponent) setHealth(h component.Health) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.health = h
+}
+
+func (c *fakeHealthComponent) CurrentHealth() component.Health {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return c.health
+}
@@ -59,6 +59,32 @@ type Loader struct {
 	cc                   *controllerCollector
 	moduleExportIndex    int
 	componentNodeManager *ComponentNodeManager
+	lastArgs             map[string]any // Module arguments of the last call to Apply.
+	lastScope            map[string]any // Variables of the argument scope of the last call to Apply.
+	lastApplyStats       ApplyStats
+
+	// failedNodes holds the IDs of the nodes whose last evaluation failed. It
+	// has its own mutex because nodes are evaluated concurrently while mut is
+	// read locked.
+	failedMut   sync.Mutex
+	failedNodes map[string]struct{}
+
+	// timedOutNodes holds the IDs of the nodes whose evaluation timed out and
+	// is still running in the background.
+	timedOutMut   sync.Mutex
+	timedOutNodes map[string]struct{}
+
+	// secretsService is the service resolving the secrets of sys.secret with
+	// secretResolver, if any.
+	secretsService service.Service
+	secretResolver SecretResolver
+
+	// evalOrder holds the IDs of the nodes in the order they were evaluated
+	// by the last call to Apply, and lastEvaluations the timings of the last
+	// evaluation of each node.
+	evalMut         sync.Mutex
+	evalOrder       []string
+	lastEvaluations map[string]nodeEvaluationTimes
 }
 
 // LoaderOptions holds options for creating a Loader.
@@ -106,12 +132,21 @@ func NewLoader(opts LoaderOptions) *Loader {
 			MaxRetries: 20, // Give up after 20 attempts - it could be a deadlock instead of an overload.
 		},
 
-		graph: &dag.Graph{},
-		cache: newValueCache(),
-		cm:    newControllerMetrics(parent, id),
+		graph:         &dag.Graph{},
+		cache:         newValueCache(),
+		failedNodes:   make(map[string]struct{}),
+		timedOutNodes: make(map[string]struct{}),
+		cm:            newControllerMetrics(parent, id),
+
+		lastEvaluations: make(map[string]nodeEvaluationTimes),
 	}
 	l.cc = newControllerCollector(l, parent, id)
 
+	l.secretsService, l.secretResolver = findSecretResolver(services)
+	if l.secretResolver != nil {
+		l.cache.SetSecretResolver(l.secretResolver.ResolveSecret)
+	}
+
 	if globals.Registerer != nil {
 		globals.Registerer.MustRegister(l.cc)
 		globals.Registerer.MustRegister(l.cm)
@@ -146,7 +181,11 @@ type ApplyOptions struct {
 // matches the component ID specified by any of the provided Alloy blocks.
 // Reused components will be updated to point at the new Alloy block.
 //
-// Apply will perform an evaluation of all loaded components before returning.
+// Apply evaluates the nodes which were added, whose block or dependencies
+// changed, or whose previous evaluation failed, and the nodes depending on
+// them, before returning. All the nodes are evaluated when the changes can't
+// be limited to a subset of the graph, like on the first call to Apply or
+// when the module arguments change. LastApplyStats describes the changes.
 // The provided parentContext can be used to provide global variables and
 // functions to components. A child context will be constructed from the parent
 // to expose values of other components.
@@ -157,7 +196,13 @@ func (l *Loader) Apply(options ApplyOptions) diag.Diagnostics {
 	l.cm.controllerEvaluation.Set(1)
 	defer l.cm.controllerEvaluation.Set(0)
 
-	if options.ArgScope != nil {
+	// Capture the previous blocks before the nodes are updated with the new
+	// ones.
+	prev := snapshotGraph(l.graph)
+
+	// The exports of the components are cached in the scope, so it's only
+	// replaced when its variables changed, which evaluates all the nodes.
+	if options.ArgScope != nil && !scopeVariablesEqual(options.ArgScope.Variables, l.lastScope) {
 		l.cache.UpdateScopeVariables(options.ArgScope.Variables)
 	}
 
@@ -178,30 +223,59 @@ func (l *Loader) Apply(options ApplyOptions) diag.Diagnostics {
 		components   = make([]ComponentNode, 0)
 		componentIDs = make(map[string]ComponentID)
 		services     = make([]*ServiceNode, 0, len(l.services))
+
+		changes, stats = diffGraph(prev, &newGraph)
+		dirty          = make(map[dag.Node]struct{})
+		inherited      = inheritedFunctions(options.CustomComponentRegistry)
 	)
+	stats.Full = l.needsFullEvaluation(options, prev, &newGraph, changes)
 
 	tracer := l.tracer.Tracer("")
 	spanCtx, span := tracer.Start(context.Background(), "GraphEvaluate", trace.WithSpanKind(trace.SpanKindInternal))
 	defer span.End()
 
 	logger := log.With(l.log, "trace_id", span.SpanContext().TraceID())
-	level.Info(logger).Log("msg", "starting complete graph evaluation")
+	evaluation := "partial"
+	if stats.Full {
+		evaluation = "complete"
+	}
+	level.Info(logger).Log("msg", fmt.Sprintf("starting %s graph evaluation", evaluation))
 	defer func() {
 		span.SetStatus(codes.Ok, "")
 
-		level.Info(logger).Log("msg", "finished complete graph evaluation", "duration", time.Since(start))
+		level.Info(logger).Log(
+			"msg", fmt.Sprintf("finished %s graph evaluation", evaluation), "duration", time.Since(start),
+			"added", stats.Added, "removed", stats.Removed, "changed", stats.Changed, "unchanged", stats.Unchanged, "evaluated", stats.Evaluated,
+		)
 	}()
 
 	l.cache.ClearModuleExports()
 
 	// Evaluate all the components.
+	evalOrder := make([]string, 0, len(newGraph.Nodes()))
 	_ = dag.WalkTopological(&newGraph, newGraph.Leaves(), func(n dag.Node) error {
+		evalOrder = append(evalOrder, n.NodeID())
+		evaluate := stats.Full || l.needsEvaluation(&newGraph, n, changes[n], dirty, inherited)
+		if !evaluate {
+			// Unchanged nodes keep running with their current arguments.
+			switch n := n.(type) {
+			case ComponentNode:
+				components = append(components, n)
+				componentIDs[n.ID().String()] = n.ID()
+			case *ServiceNode:
+				services = append(services, n)
+			}
+			return nil
+		}
+		stats.Evaluated++
+
 		_, span := tracer.Start(spanCtx, "EvaluateNode", trace.WithSpanKind(trace.SpanKindInternal))
 		span.SetAttributes(attribute.String("node_id", n.NodeID()))
 		defer span.End()
 
 		start := time.Now()
 		defer func() {
+			l.recordEvaluation(n.NodeID(), start, 0)
 			level.Info(logger).Log("msg", "finished node evaluation", "node_id", n.NodeID(), "duration", time.Since(start))
 		}()
 
@@ -280,6 +354,10 @@ func (l *Loader) Apply(options ApplyOptions) diag.Diagnostics {
 		return diags
 	}
 	l.blocks = options.ComponentBlocks
+	l.lastArgs = options.Args
+	l.lastScope = scopeVariables(options.ArgScope)
+	l.lastApplyStats = stats
+	l.setEvaluationOrder(evalOrder)
 	if l.globals.OnExportsChange != nil && l.cache.ExportChangeIndex() != l.moduleExportIndex {
 		l.moduleExportIndex = l.cache.ExportChangeIndex()
 		l.globals.OnExportsChange(l.cache.CreateModuleExports())
@@ -287,6 +365,14 @@ func (l *Loader) Apply(options ApplyOptions) diag.Diagnostics {
 	return diags
 }
 
+// LastApplyStats returns how the graph changed during the last successful
+// call to Apply.
+func (l *Loader) LastApplyStats() ApplyStats {
+	l.mut.RLock()
+	defer l.mut.RUnlock()
+	return l.lastApplyStats
+}
+
 // Cleanup unregisters any existing metrics and optionally stops the worker pool.
 func (l *Loader) Cleanup(stopWorkerPool bool) {
 	if stopWorkerPool {
@@ -318,6 +404,10 @@ func (l *Loader) loadNewGraph(args map[string]any, componentBlocks []*ast.BlockS
 	configBlockDiags := l.populateConfigBlockNodes(args, &g, configBlocks)
 	diags = append(diags, configBlockDiags...)
 
+	// Expose the functions of the declare and import blocks, which must be
+	// registered before, to the expressions of the nodes.
+	l.cache.UpdateFunctions(l.componentNodeManager.customComponentReg.Functions())
+
 	// Fill our graph with components.
 	componentNodeDiags := l.populateComponentNodes(&g, componentBlocks)
 	diags = append(diags, componentNodeDiags...)
@@ -381,13 +471,19 @@ func (l *Loader) populateDeclareNodes(g *dag.Graph, declareBlocks []*ast.BlockSt
 			continue
 		}
 
+		template, functions, functionDiags := splitDeclareBody(declareBlock.Body, l.globals.MinStability)
+		diags = append(diags, functionDiags...)
+		if functionDiags.HasErrors() {
+			continue
+		}
+
 		if exist := l.graph.GetByID(id); exist != nil {
 			node = exist.(*DeclareNode)
 			node.UpdateBlock(declareBlock)
 		} else {
 			node = NewDeclareNode(declareBlock)
 		}
-		l.componentNodeManager.customComponentReg.registerDeclare(declareBlock)
+		l.componentNodeManager.customComponentReg.registerDeclare(declareBlock, template, functions)
 		l.declareNodes[node.label] = node
 		g.Add(node)
 	}
@@ -438,6 +534,7 @@ func (l *Loader) populateServiceNodes(g *dag.Graph, serviceBlocks []*ast.BlockSt
 			node = exist.(*ServiceNode)
 		} else {
 			node = NewServiceNode(l.host, svc)
+			node.dryRun = l.globals.DryRun
 		}
 
 		node.UpdateBlock(nil) // Reset configuration to nil.
@@ -477,10 +574,10 @@ func (l *Loader) populateServiceNodes(g *dag.Graph, serviceBlocks []*ast.BlockSt
 		//
 		// If the block is non-nil, it means that there was a duplicate block
 		// configuring the same service found in a previous iteration of this loop.
-		if node.Block() != nil {
+		if orig := node.Block(); orig != nil {
 			diags.Add(diag.Diagnostic{
 				Severity: diag.SeverityLevelError,
-				Message:  fmt.Sprintf("duplicate definition of %q", blockID),
+				Message:  fmt.Sprintf("duplicate definition of %q, already declared at %s", blockID, ast.StartPos(orig).Position()),
 				StartPos: ast.StartPos(block).Position(),
 				EndPos:   ast.EndPos(block).Position(),
 			})
@@ -532,6 +629,11 @@ func (l *Loader) populateConfigBlockNodes(args map[string]any, g *dag.Graph, con
 
 		if importNode, ok := node.(*ImportConfigNode); ok {
 			l.componentNodeManager.customComponentReg.registerImport(importNode.label)
+			// Import nodes which don't change aren't evaluated again, so the
+			// content they imported before is registered right away.
+			if l.graph.GetByID(id) != nil && !l.lastEvaluationFailed(id) {
+				l.componentNodeManager.customComponentReg.updateImportContent(importNode)
+			}
 		}
 
 		g.Add(node)
@@ -542,14 +644,12 @@ func (l *Loader) populateConfigBlockNodes(args map[string]any, g *dag.Graph, con
 
 	// If a logging config block is not provided, we create an empty node which uses defaults.
 	if nodeMap.logging == nil && l.isRootController() {
-		c := NewDefaultLoggingConfigNode(l.globals)
-		g.Add(c)
+		g.Add(l.defaultConfigNode(loggingBlockID, func() BlockNode { return NewDefaultLoggingConfigNode(l.globals) }))
 	}
 
 	// If a tracing config block is not provided, we create an empty node which uses defaults.
 	if nodeMap.tracing == nil && l.isRootController() {
-		c := NewDefaulTracingConfigNode(l.globals)
-		g.Add(c)
+		g.Add(l.defaultConfigNode(tracingBlockID, func() BlockNode { return NewDefaulTracingConfigNode(l.globals) }))
 	}
 
 	l.importConfigNodes = nodeMap.importMap
@@ -558,6 +658,16 @@ func (l *Loader) populateConfigBlockNodes(args map[string]any, g *dag.Graph, con
 	return diags
 }
 
+// defaultConfigNode returns the node of the previous graph with the given ID
+// if it was already using defaults, so that it isn't evaluated again, or a new
+// one created by newNode.
+func (l *Loader) defaultConfigNode(id string, newNode func() BlockNode) BlockNode {
+	if prev, ok := l.graph.GetByID(id).(BlockNode); ok && prev.Block() == nil {
+		return prev
+	}
+	return newNode()
+}
+
 // populateComponentNodes adds any components to the graph.
 func (l *Loader) populateComponentNodes(g *dag.Graph, componentBlocks []*ast.BlockStmt) diag.Diagnostics {
 	var (
@@ -570,6 +680,16 @@ func (l *Loader) populateComponentNodes(g *dag.Graph, componentBlocks []*ast.Blo
 			diags = append(diags, diag)
 			continue
 		}
+		// The exports of the component would hide the function with the same name.
+		if l.cache.HasFunction(BlockComponentID(block)) {
+			diags.Add(diag.Diagnostic{
+				Severity: diag.SeverityLevelError,
+				Message:  fmt.Sprintf("component %s has the same name as a function of its declare block", id),
+				StartPos: ast.StartPos(block).Position(),
+				EndPos:   ast.EndPos(block).Position(),
+			})
+			continue
+		}
 		// Check the graph from the previous call to Load to see if we can copy an
 		// existing instance of ComponentNode.
 		if exist := l.graph.GetByID(id); exist != nil {
@@ -599,12 +719,16 @@ func (l *Loader) populateComponentNodes(g *dag.Graph, componentBlocks []*ast.Blo
 func (l *Loader) wireGraphEdges(g *dag.Graph) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	// Reset outgoing data flow edges for all component nodes.
+	// Reset outgoing data flow edges for all component nodes and the references
+	// to the exports of custom components.
 	for _, n := range g.Nodes() {
 		switch n := n.(type) {
 		case ComponentNode:
 			n.ResetDataFlowEdgeTo()
 		}
+		if cc, ok := n.(*CustomComponentNode); ok {
+			cc.ResetExportReferences()
+		}
 	}
 
 	for _, n := range g.Nodes() {
@@ -642,6 +766,16 @@ func (l *Loader) wireGraphEdges(g *dag.Graph) diag.Diagnostics {
 			l.wireForEachNode(g, n)
 		}
 
+		l.wireImportedFunctionCalls(g, n)
+
+		// Blocks calling sys.secret must be evaluated after the service which
+		// resolves secrets.
+		if l.secretsService != nil && usesStdlibSecret(n) {
+			if svc := g.GetByID(l.secretsService.Definition().Name); svc != nil && svc != n {
+				g.AddEdge(dag.Edge{From: n, To: svc})
+			}
+		}
+
 		// Finally, wire component references.
 		l.cache.mut.RLock()
 		refs, nodeDiags := ComponentReferences(n, g, l.log, l.cache.GetContext(), l.globals.MinStability)
@@ -649,6 +783,12 @@ func (l *Loader) wireGraphEdges(g *dag.Graph) diag.Diagnostics {
 		setDataFlowEdges(n, refs)
 		for _, ref := range refs {
 			g.AddEdge(dag.Edge{From: n, To: ref.Target})
+
+			// Custom components check that their definition provides the
+			// referenced exports.
+			if cc, ok := ref.Target.(*CustomComponentNode); ok && len(ref.Traversal) > 0 {
+				cc.AddExportReference(ref.Traversal[0].Name, n.NodeID(), ast.StartPos(ref.Traversal[0]).Position().String())
+			}
 		}
 		diags = append(diags, nodeDiags...)
 	}
@@ -672,6 +812,25 @@ func (l *Loader) wireCustomComponentNode(g *dag.Graph, cc *CustomComponentNode)
 	}
 }
 
+// wireImportedFunctionCalls adds edges between a node and the import nodes
+// whose functions it may call, so that the node is evaluated after the import
+// and reevaluated when the imported content changes.
+func (l *Loader) wireImportedFunctionCalls(g *dag.Graph, n dag.Node) {
+	bn, ok := n.(BlockNode)
+	if !ok || bn.Block() == nil {
+		return
+	}
+	for _, t := range expressionsFromBody(bn.Block().Body) {
+		// Imported functions are called as namespace.declare.function.
+		if len(t) < 3 {
+			continue
+		}
+		if importNode, ok := l.importConfigNodes[t[0].Name]; ok && importNode != n {
+			g.AddEdge(dag.Edge{From: n, To: importNode})
+		}
+	}
+}
+
 // wireForEachNode add edges between a foreach node and declare/import nodes that are used in the foreach pipeline.
 func (l *Loader) wireForEachNode(g *dag.Graph, fn *ForeachConfigNode) {
 	refs := l.findCustomComponentReferences(fn.Block())
@@ -755,6 +914,7 @@ func (l *Loader) EvaluateDependants(ctx context.Context, updatedNodes []*QueuedN
 		case *ImportConfigNode:
 			// Update the scope with the imported content.
 			l.componentNodeManager.customComponentReg.updateImportContent(parentNode)
+			l.cache.UpdateFunctions(l.componentNodeManager.customComponentReg.Functions())
 		}
 		// We collect all nodes directly incoming to parent.
 		_ = dag.WalkIncomingNodes(l.graph, parent.Node, func(n dag.Node) error {
@@ -767,7 +927,13 @@ func (l *Loader) EvaluateDependants(ctx context.Context, updatedNodes []*QueuedN
 	// During evaluation, if a node's exports change, Alloy will add it to updated nodes queue (controller.Queue) and
 	// the Alloy controller will call EvaluateDependants on it again. This results in a concurrent breadth-first
 	// traversal of the nodes that need to be evaluated.
-	for n, parent := range dependenciesToParentsMap {
+	l.submitForEvaluation(ctx, spanCtx, tracer, dependenciesToParentsMap)
+}
+
+// submitForEvaluation submits the nodes to the worker pool for asynchronous evaluation, with the node which caused
+// their evaluation. mut must be read locked when calling submitForEvaluation.
+func (l *Loader) submitForEvaluation(ctx context.Context, spanCtx context.Context, tracer trace.Tracer, nodes map[dag.Node]*QueuedNode) {
+	for n, parent := range nodes {
 		dependantCtx, span := tracer.Start(spanCtx, "SubmitForEvaluation", trace.WithSpanKind(trace.SpanKindInternal))
 		span.SetAttributes(attribute.String("node_id", n.NodeID()))
 		span.SetAttributes(attribute.String("originator_id", parent.Node.NodeID()))
@@ -826,7 +992,8 @@ func (l *Loader) EvaluateDependants(ctx context.Context, updatedNodes []*QueuedN
 // a worker pool for asynchronous evaluation.
 func (l *Loader) concurrentEvalFn(n dag.Node, spanCtx context.Context, tracer trace.Tracer, parent *QueuedNode) {
 	start := time.Now()
-	l.cm.dependenciesWaitTime.Observe(time.Since(parent.LastUpdatedTime).Seconds())
+	queueWait := start.Sub(parent.LastUpdatedTime)
+	l.cm.dependenciesWaitTime.Observe(queueWait.Seconds())
 	_, span := tracer.Start(spanCtx, "EvaluateNode", trace.WithSpanKind(trace.SpanKindInternal))
 	span.SetAttributes(attribute.String("node_id", n.NodeID()))
 	defer span.End()
@@ -834,6 +1001,7 @@ func (l *Loader) concurrentEvalFn(n dag.Node, spanCtx context.Context, tracer tr
 	defer func() {
 		duration := time.Since(start)
 		l.cm.onComponentEvaluationDone(n.NodeID(), duration)
+		l.recordEvaluation(n.NodeID(), start, queueWait)
 		level.Debug(l.log).Log("msg", "finished node evaluation", "node_id", n.NodeID(), "duration", duration)
 	}()
 
@@ -844,7 +1012,7 @@ func (l *Loader) concurrentEvalFn(n dag.Node, spanCtx context.Context, tracer tr
 		// RLock before evaluate to prevent Evaluating while the config is being reloaded
 		l.mut.RLock()
 		ectx := l.cache.GetContext()
-		evalErr := n.Evaluate(ectx)
+		evalErr := l.evaluateNode(l.log, n, ectx)
 
 		err = l.postEvaluate(l.log, n, evalErr)
 
@@ -880,10 +1048,75 @@ func (l *Loader) concurrentEvalFn(n dag.Node, spanCtx context.Context, tracer tr
 // evaluates it. mut must be held when calling evaluate.
 func (l *Loader) evaluate(logger log.Logger, bn BlockNode) error {
 	ectx := l.cache.GetContext()
-	err := bn.Evaluate(ectx)
+	err := l.evaluateNode(logger, bn, ectx)
 	return l.postEvaluate(logger, bn, err)
 }
 
+// evalHealthReporter is implemented by the nodes which report the health of
+// their last evaluation.
+type evalHealthReporter interface {
+	setEvalHealth(t component.HealthType, msg string)
+}
+
+// evaluateNode evaluates bn with scope. If the evaluation of a builtin
+// component takes longer than the evaluation timeout, evaluateNode returns an
+// error and marks bn as unhealthy, so that a single block can't stall the
+// evaluation of the graph. Evaluations can't be interrupted: the evaluation of
+// bn keeps running in the background, and the dependants of bn are
+// reevaluated once it completes. Until then, new evaluations of bn fail right
+// away instead of piling up behind the running one.
+//
+// Other nodes are always evaluated synchronously, because their block can't be
+// updated while they're evaluated.
+func (l *Loader) evaluateNode(logger log.Logger, bn BlockNode, scope *vm.Scope) error {
+	timeout := l.globals.EvaluationTimeout
+	if _, ok := bn.(*BuiltinComponentNode); !ok || timeout <= 0 {
+		return bn.Evaluate(scope)
+	}
+
+	l.timedOutMut.Lock()
+	_, running := l.timedOutNodes[bn.NodeID()]
+	l.timedOutMut.Unlock()
+	if running {
+		return fmt.Errorf("a previous evaluation which timed out is still running")
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- bn.Evaluate(scope) }()
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+	}
+
+	err := fmt.Errorf("evaluation timed out after %s", timeout)
+	l.cm.evaluationTimeouts.WithLabelValues(bn.NodeID()).Inc()
+	if hr, ok := bn.(evalHealthReporter); ok {
+		hr.setEvalHealth(component.HealthTypeUnhealthy, fmt.Sprintf("component evaluation failed: %s", err))
+	}
+
+	l.timedOutMut.Lock()
+	l.timedOutNodes[bn.NodeID()] = struct{}{}
+	l.timedOutMut.Unlock()
+
+	go func() {
+		evalErr := <-done
+		l.timedOutMut.Lock()
+		delete(l.timedOutNodes, bn.NodeID())
+		l.timedOutMut.Unlock()
+
+		level.Warn(logger).Log("msg", "evaluation which timed out completed", "node", bn.NodeID(), "err", evalErr)
+		if l.globals.OnBlockNodeUpdate != nil {
+			// The exports of bn may have changed.
+			l.globals.OnBlockNodeUpdate(bn)
+		}
+	}()
+	return err
+}
+
 // postEvaluate is called after a node has been evaluated. It updates the caches and logs any errors.
 // mut must be held when calling postEvaluate.
 // The evaluation err is passed as an argument to allow shadowing it with an error that could be more relevant to the user
@@ -916,8 +1149,10 @@ func (l *Loader) postEvaluate(logger log.Logger, bn BlockNode, err error) error
 		}
 	case *ImportConfigNode:
 		l.componentNodeManager.customComponentReg.updateImportContent(c)
+		l.cache.UpdateFunctions(l.componentNodeManager.customComponentReg.Functions())
 	}
 
+	l.setEvaluationResult(bn.NodeID(), err)
 	if err != nil {
 		level.Error(logger).Log("msg", "failed to evaluate config", "node", bn.NodeID(), "err", err)
 		return err
@@ -0,0 +1,300 @@
+package controller
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/grafana/alloy/internal/runtime/equality"
+	"github.com/grafana/alloy/internal/runtime/internal/dag"
+	"github.com/grafana/alloy/syntax/printer"
+	"github.com/grafana/alloy/syntax/vm"
+)
+
+// ApplyStats describes how the graph changed during the last call to Apply.
+type ApplyStats struct {
+	Added     int // Nodes which weren't in the previous graph.
+	Removed   int // Nodes of the previous graph which were removed.
+	Changed   int // Nodes whose block or dependencies changed.
+	Unchanged int // Nodes whose block and dependencies didn't change.
+
+	// Evaluated is the number of nodes which were evaluated. Unchanged nodes
+	// are only evaluated when they depend on an added or changed node, or
+	// when their previous evaluation failed.
+	Evaluated int
+
+	// Full is true when all the nodes were evaluated because the changes
+	// couldn't be limited to a subset of the graph, like on the first load.
+	Full bool
+}
+
+// nodeChange is how a node changed between two calls to Apply.
+type nodeChange int
+
+const (
+	nodeUnchanged nodeChange = iota
+	nodeAdded
+	nodeChanged
+)
+
+// nodeSnapshot holds the state of a node of the graph before Apply updates
+// its block.
+type nodeSnapshot struct {
+	node         dag.Node
+	content      string
+	ok           bool     // false if the content of the block couldn't be printed.
+	dependencies []string // Sorted IDs of the nodes the node depends on.
+}
+
+// snapshotGraph captures the blocks and dependencies of the nodes of g, keyed
+// by node ID.
+func snapshotGraph(g *dag.Graph) map[string]nodeSnapshot {
+	snapshot := make(map[string]nodeSnapshot)
+	for _, n := range g.Nodes() {
+		content, ok := blockContent(n)
+		snapshot[n.NodeID()] = nodeSnapshot{
+			node:         n,
+			content:      content,
+			ok:           ok,
+			dependencies: dependencyIDs(g, n),
+		}
+	}
+	return snapshot
+}
+
+// diffGraph compares the nodes of g with a snapshot of the previous graph.
+func diffGraph(prev map[string]nodeSnapshot, g *dag.Graph) (map[dag.Node]nodeChange, ApplyStats) {
+	var (
+		changes = make(map[dag.Node]nodeChange)
+		stats   ApplyStats
+	)
+	for _, n := range g.Nodes() {
+		old, found := prev[n.NodeID()]
+		switch {
+		case !found:
+			changes[n] = nodeAdded
+			stats.Added++
+		case old.node != n || !old.ok:
+			// Nodes which were rebuilt must be evaluated.
+			changes[n] = nodeChanged
+			stats.Changed++
+		default:
+			content, ok := blockContent(n)
+			if !ok || content != old.content || !slices.Equal(dependencyIDs(g, n), old.dependencies) {
+				changes[n] = nodeChanged
+				stats.Changed++
+			} else {
+				changes[n] = nodeUnchanged
+				stats.Unchanged++
+			}
+		}
+	}
+	for id := range prev {
+		if g.GetByID(id) == nil {
+			stats.Removed++
+		}
+	}
+	return changes, stats
+}
+
+// needsFullEvaluation returns true if the changes can affect nodes whose
+// block and dependencies didn't change.
+func (l *Loader) needsFullEvaluation(options ApplyOptions, prev map[string]nodeSnapshot, g *dag.Graph, changes map[dag.Node]nodeChange) bool {
+	if len(prev) == 0 {
+		return true
+	}
+	if !reflect.DeepEqual(options.Args, l.lastArgs) {
+		return true
+	}
+	// Nested modules get variables from their parent, whose changes aren't
+	// visible in the blocks of the module.
+	if !scopeVariablesEqual(scopeVariables(options.ArgScope), l.lastScope) {
+		return true
+	}
+
+	// The functions of declare blocks can be called without an edge to the
+	// declare node.
+	for n, change := range changes {
+		if _, ok := n.(*DeclareNode); ok && change != nodeUnchanged {
+			return true
+		}
+	}
+	for id, old := range prev {
+		if _, ok := old.node.(*DeclareNode); ok && g.GetByID(id) == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// needsEvaluation returns true if n must be evaluated during an incremental
+// Apply. dirty holds the nodes evaluated so far because they or their
+// dependencies changed; nodes are visited after their dependencies. inherited
+// holds the namespaces of the functions the module gets from its parent.
+func (l *Loader) needsEvaluation(g *dag.Graph, n dag.Node, change nodeChange, dirty map[dag.Node]struct{}, inherited map[string]struct{}) bool {
+	isDirty := change != nodeUnchanged || l.lastEvaluationFailed(n.NodeID()) ||
+		// Secrets may have been rotated since the previous evaluation.
+		usesStdlibSecret(n) ||
+		usesInheritedDefinitions(n, inherited)
+	if !isDirty {
+		for _, dep := range g.Dependencies(n) {
+			if _, ok := dirty[dep]; ok {
+				isDirty = true
+				break
+			}
+		}
+	}
+	if isDirty {
+		dirty[n] = struct{}{}
+		return true
+	}
+
+	// Module arguments and exports are cached again on each Apply, without
+	// making their dependants dirty.
+	switch n.(type) {
+	case *ArgumentConfigNode, *ExportConfigNode:
+		return true
+	}
+	return false
+}
+
+// usesInheritedDefinitions returns true if n may depend on the definitions a
+// nested module gets from its parent, whose changes aren't visible in the
+// blocks of the module: custom components and foreach blocks load their
+// definition when they're evaluated, and other blocks can call the functions
+// of the declare blocks of the parent. inherited is nil for the root module.
+func usesInheritedDefinitions(n dag.Node, inherited map[string]struct{}) bool {
+	if inherited == nil {
+		return false
+	}
+	switch n.(type) {
+	case *CustomComponentNode, *ForeachConfigNode:
+		return true
+	}
+	bn, ok := n.(BlockNode)
+	if !ok || bn.Block() == nil {
+		return false
+	}
+	for _, t := range expressionsFromBody(bn.Block().Body) {
+		if _, ok := inherited[t[0].Name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// inheritedFunctions returns the namespaces of the functions of the declare
+// blocks a module gets from reg, the registry of its parent. It returns nil
+// for the root module, which doesn't have a parent.
+func inheritedFunctions(reg *CustomComponentRegistry) map[string]struct{} {
+	if reg == nil {
+		return nil
+	}
+	namespaces := make(map[string]struct{})
+	for name := range reg.Functions() {
+		namespaces[name] = struct{}{}
+	}
+	return namespaces
+}
+
+// scopeVariables returns the variables of scope, or nil if scope is nil.
+func scopeVariables(scope *vm.Scope) map[string]any {
+	if scope == nil {
+		return nil
+	}
+	return scope.Variables
+}
+
+// scopeVariablesEqual returns true if the variables of two scopes hold the
+// same values. Functions are ignored, because they're never equal with
+// reflect.DeepEqual, and the changes of the functions of declare blocks are
+// handled by usesInheritedDefinitions.
+func scopeVariablesEqual(x, y map[string]any) bool {
+	if (x == nil) != (y == nil) {
+		return false
+	}
+	return valuesEqualIgnoringFuncs(reflect.ValueOf(x), reflect.ValueOf(y))
+}
+
+func valuesEqualIgnoringFuncs(x, y reflect.Value) bool {
+	if !x.IsValid() || !y.IsValid() {
+		return x.IsValid() == y.IsValid()
+	}
+	if x.Type() != y.Type() {
+		return false
+	}
+	switch x.Kind() {
+	case reflect.Func:
+		return true
+	case reflect.Interface:
+		return valuesEqualIgnoringFuncs(x.Elem(), y.Elem())
+	case reflect.Map:
+		if x.Len() != y.Len() {
+			return false
+		}
+		iter := x.MapRange()
+		for iter.Next() {
+			if !valuesEqualIgnoringFuncs(iter.Value(), y.MapIndex(iter.Key())) {
+				return false
+			}
+		}
+		return true
+	case reflect.Slice, reflect.Array:
+		if x.Len() != y.Len() {
+			return false
+		}
+		for i := 0; i < x.Len(); i++ {
+			if !valuesEqualIgnoringFuncs(x.Index(i), y.Index(i)) {
+				return false
+			}
+		}
+		return true
+	}
+	if !x.CanInterface() || !y.CanInterface() {
+		return false
+	}
+	return equality.DeepEqual(x.Interface(), y.Interface())
+}
+
+// setEvaluationResult records whether the last evaluation of the node with
+// the given ID failed, so that it's evaluated again on the next Apply.
+func (l *Loader) setEvaluationResult(id string, err error) {
+	l.failedMut.Lock()
+	defer l.failedMut.Unlock()
+	if err != nil {
+		l.failedNodes[id] = struct{}{}
+	} else {
+		delete(l.failedNodes, id)
+	}
+}
+
+func (l *Loader) lastEvaluationFailed(id string) bool {
+	l.failedMut.Lock()
+	defer l.failedMut.Unlock()
+	_, failed := l.failedNodes[id]
+	return failed
+}
+
+// blockContent returns the formatted content of the block of n, which
+// doesn't depend on the position of the block in the file.
+func blockContent(n dag.Node) (string, bool) {
+	bn, ok := n.(BlockNode)
+	if !ok || bn.Block() == nil {
+		return "", true
+	}
+	var sb strings.Builder
+	if err := printer.Fprint(&sb, bn.Block()); err != nil {
+		return "", false
+	}
+	return sb.String(), true
+}
+
+func dependencyIDs(g *dag.Graph, n dag.Node) []string {
+	deps := g.Dependencies(n)
+	ids := make([]string, 0, len(deps))
+	for _, dep := range deps {
+		ids = append(ids, dep.NodeID())
+	}
+	slices.Sort(ids)
+	return ids
+}
@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestScopeVariablesEqual(t *testing.T) {
+	fn := func() func(...any) (any, error) {
+		return func(...any) (any, error) { return nil, nil }
+	}
+
+	// Functions are never equal with reflect.DeepEqual, so they're ignored.
+	require.True(t, scopeVariablesEqual(
+		map[string]any{"module_path": "/a", "math": map[string]any{"double": fn()}},
+		map[string]any{"module_path": "/a", "math": map[string]any{"double": fn()}},
+	))
+	require.True(t, scopeVariablesEqual(map[string]any{}, map[string]any{}))
+	require.True(t, scopeVariablesEqual(nil, nil))
+
+	require.False(t, scopeVariablesEqual(
+		map[string]any{"module_path": "/a", "math": map[string]any{"double": fn()}},
+		map[string]any{"module_path": "/b", "math": map[string]any{"double": fn()}},
+	))
+	require.False(t, scopeVariablesEqual(
+		map[string]any{"math": map[string]any{"double": fn()}},
+		map[string]any{"math": map[string]any{"triple": fn()}},
+	))
+	require.False(t, scopeVariablesEqual(
+		map[string]any{"items": []any{1, 2}},
+		map[string]any{"items": []any{1, 3}},
+	))
+	require.False(t, scopeVariablesEqual(nil, map[string]any{}))
+}
@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"time"
+)
+
+// NodeEvaluation describes the evaluation of a node of the graph.
+type NodeEvaluation struct {
+	NodeID string
+
+	// Order is the position of the node in the evaluation order of the graph,
+	// where a node comes after all the nodes it depends on.
+	Order int
+
+	// LastEvaluated is the time the last evaluation of the node started. It's
+	// zero if the node wasn't evaluated yet.
+	LastEvaluated time.Time
+	LastDuration  time.Duration // Duration of the last evaluation.
+
+	// LastQueueWait is the time the last evaluation waited in the evaluation
+	// queue after a dependency of the node updated its exports. It's zero
+	// when the last evaluation happened while loading the configuration.
+	LastQueueWait time.Duration
+}
+
+// nodeEvaluationTimes holds the timings of the last evaluation of a node.
+type nodeEvaluationTimes struct {
+	start     time.Time
+	duration  time.Duration
+	queueWait time.Duration
+}
+
+// EvaluationOrder returns the nodes of the graph in the order they were
+// evaluated by the last call to Apply, along with the timings of their last
+// evaluation, which may have happened since then because a dependency
+// updated its exports.
+func (l *Loader) EvaluationOrder() []NodeEvaluation {
+	l.evalMut.Lock()
+	defer l.evalMut.Unlock()
+
+	res := make([]NodeEvaluation, 0, len(l.evalOrder))
+	for i, id := range l.evalOrder {
+		times := l.lastEvaluations[id]
+		res = append(res, NodeEvaluation{
+			NodeID:        id,
+			Order:         i,
+			LastEvaluated: times.start,
+			LastDuration:  times.duration,
+			LastQueueWait: times.queueWait,
+		})
+	}
+	return res
+}
+
+// recordEvaluation stores the timings of an evaluation of the node id which
+// started at start.
+func (l *Loader) recordEvaluation(id string, start time.Time, queueWait time.Duration) {
+	l.evalMut.Lock()
+	defer l.evalMut.Unlock()
+	l.lastEvaluations[id] = nodeEvaluationTimes{
+		start:     start,
+		duration:  time.Since(start),
+		queueWait: queueWait,
+	}
+}
+
+// setEvaluationOrder stores the evaluation order of the graph, and forgets
+// the evaluations of the nodes which aren't part of it anymore.
+func (l *Loader) setEvaluationOrder(order []string) {
+	l.evalMut.Lock()
+	defer l.evalMut.Unlock()
+
+	lastEvaluations := make(map[string]nodeEvaluationTimes, len(order))
+	for _, id := range order {
+		if times, ok := l.lastEvaluations[id]; ok {
+			lastEvaluations[id] = times
+		}
+	}
+	l.evalOrder = order
+	l.lastEvaluations = lastEvaluations
+}
@@ -6,21 +6,22 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/runtime/internal/controller"
 	"github.com/grafana/alloy/internal/runtime/internal/dag"
+	"github.com/grafana/alloy/internal/runtime/internal/testcomponents"
 	"github.com/grafana/alloy/internal/runtime/logging"
 	"github.com/grafana/alloy/internal/service"
 	"github.com/grafana/alloy/syntax/ast"
 	"github.com/grafana/alloy/syntax/diag"
 	"github.com/grafana/alloy/syntax/parser"
+	"github.com/grafana/alloy/syntax/vm"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/trace/noop"
-
-	_ "github.com/grafana/alloy/internal/runtime/internal/testcomponents" // Include test components
 )
 
 func TestLoader(t *testing.T) {
@@ -117,6 +118,32 @@ func TestLoader(t *testing.T) {
 		requireGraph(t, l.Graph(), testGraphDefinition)
 	})
 
+	t.Run("Reload Graph Changed Component", func(t *testing.T) {
+		l := controller.NewLoader(newLoaderOptions())
+		diags := applyFromContent(t, l, []byte(testFile), []byte(testConfig), nil)
+		require.NoError(t, diags.ErrorOrNil())
+		require.Equal(t, controller.ApplyStats{Added: 6, Evaluated: 6, Full: true}, l.LastApplyStats())
+
+		updatedTestFile := strings.Replace(testFile, `frequency = "1s"`, `frequency = "2s"`, 1)
+		diags = applyFromContent(t, l, []byte(updatedTestFile), []byte(testConfig), nil)
+		require.NoError(t, diags.ErrorOrNil())
+		requireGraph(t, l.Graph(), testGraphDefinition)
+		// The ticker and the two passthrough components depending on it are
+		// evaluated.
+		require.Equal(t, controller.ApplyStats{Changed: 1, Unchanged: 5, Evaluated: 3}, l.LastApplyStats())
+
+		diags = applyFromContent(t, l, []byte(updatedTestFile), []byte(testConfig), nil)
+		require.NoError(t, diags.ErrorOrNil())
+		require.Equal(t, controller.ApplyStats{Unchanged: 6}, l.LastApplyStats())
+
+		removedTestFile := strings.Replace(updatedTestFile, `testcomponents.passthrough "static" {
+			input = "hello, world!"
+		}`, "", 1)
+		diags = applyFromContent(t, l, []byte(removedTestFile), []byte(testConfig), nil)
+		require.NoError(t, diags.ErrorOrNil())
+		require.Equal(t, controller.ApplyStats{Removed: 1, Unchanged: 5}, l.LastApplyStats())
+	})
+
 	t.Run("New Graph No Config", func(t *testing.T) {
 		l := controller.NewLoader(newLoaderOptions())
 		diags := applyFromContent(t, l, []byte(testFile), nil, nil)
@@ -505,6 +532,232 @@ func TestScopeWithFailingComponent(t *testing.T) {
 	require.True(t, strings.Contains(diags.Error(), `unrecognized attribute name "frequenc"`))
 }
 
+// blockingBuild is waited on by the build of the test.blocking component.
+var blockingBuild chan struct{}
+
+func init() {
+	component.Register(component.Registration{
+		Name:      "test.blocking",
+		Stability: featuregate.StabilityGenerallyAvailable,
+		Args:      struct{}{},
+
+		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
+			<-blockingBuild
+			return &testcomponents.Fake{}, nil
+		},
+	})
+}
+
+func TestLoader_EvaluationTimeout(t *testing.T) {
+	testFile := `
+		test.blocking "hung" {}
+
+		testcomponents.passthrough "static" {
+			input = "hello, world!"
+		}
+	`
+	blockingBuild = make(chan struct{})
+	updated := make(chan string, 10)
+	l, _ := logging.New(os.Stderr, logging.DefaultOptions)
+	loader := controller.NewLoader(controller.LoaderOptions{
+		ComponentGlobals: controller.ComponentGlobals{
+			Logger:            l,
+			TraceProvider:     noop.NewTracerProvider(),
+			DataPath:          t.TempDir(),
+			MinStability:      featuregate.StabilityPublicPreview,
+			OnBlockNodeUpdate: func(cn controller.BlockNode) { updated <- cn.NodeID() },
+			Registerer:        prometheus.NewRegistry(),
+			NewModuleController: func(opts controller.ModuleControllerOpts) controller.ModuleController {
+				return nil
+			},
+			EvaluationTimeout: 100 * time.Millisecond,
+		},
+	})
+
+	diags := applyFromContent(t, loader, []byte(testFile), nil, nil)
+	require.ErrorContains(t, diags.ErrorOrNil(), "evaluation timed out after 100ms")
+	require.Len(t, diags, 1)
+
+	// The other components are evaluated.
+	static := loader.Graph().GetByID("testcomponents.passthrough.static").(*controller.BuiltinComponentNode)
+	require.NotNil(t, static.Component())
+	hung := loader.Graph().GetByID("test.blocking.hung").(*controller.BuiltinComponentNode)
+	require.Equal(t, component.HealthTypeUnhealthy, hung.CurrentHealth().Health)
+
+	// Once the evaluation completes, the component is built and its dependants
+	// are reevaluated.
+	close(blockingBuild)
+	timeout := time.After(5 * time.Second)
+	for id := ""; id != "test.blocking.hung"; {
+		select {
+		case id = <-updated:
+		case <-timeout:
+			t.Fatal("the node wasn't updated after its evaluation completed")
+		}
+	}
+	require.NotNil(t, hung.Component())
+	require.NotEqual(t, component.HealthTypeUnhealthy, hung.CurrentHealth().Health)
+}
+
+func TestLoader_EvaluationTimeoutReapply(t *testing.T) {
+	blockingBuild = make(chan struct{})
+	updated := make(chan string, 10)
+	l, _ := logging.New(os.Stderr, logging.DefaultOptions)
+	loader := controller.NewLoader(controller.LoaderOptions{
+		ComponentGlobals: controller.ComponentGlobals{
+			Logger:            l,
+			TraceProvider:     noop.NewTracerProvider(),
+			DataPath:          t.TempDir(),
+			MinStability:      featuregate.StabilityPublicPreview,
+			OnBlockNodeUpdate: func(cn controller.BlockNode) { updated <- cn.NodeID() },
+			Registerer:        prometheus.NewRegistry(),
+			NewModuleController: func(opts controller.ModuleControllerOpts) controller.ModuleController {
+				return nil
+			},
+			EvaluationTimeout: 100 * time.Millisecond,
+		},
+	})
+
+	diags := applyFromContent(t, loader, []byte(`
+		test.blocking "hung" {}
+		testcomponents.passthrough "static" { input = "a" }
+	`), nil, nil)
+	require.ErrorContains(t, diags.ErrorOrNil(), "evaluation timed out after 100ms")
+
+	// Applying again while the evaluation is still running doesn't wait for
+	// it, and doesn't start another one.
+	start := time.Now()
+	diags = applyFromContent(t, loader, []byte(`
+		test.blocking "hung" {}
+		testcomponents.passthrough "static" { input = "b" }
+	`), nil, nil)
+	require.ErrorContains(t, diags.ErrorOrNil(), "a previous evaluation which timed out is still running")
+	require.Len(t, diags, 1)
+	require.Less(t, time.Since(start), 100*time.Millisecond)
+
+	static := loader.Graph().GetByID("testcomponents.passthrough.static").(*controller.BuiltinComponentNode)
+	require.Equal(t, "b", static.Arguments().(testcomponents.PassthroughConfig).Input)
+
+	// Once the evaluation completes, the next Apply evaluates the component
+	// again.
+	close(blockingBuild)
+	require.Eventually(t, func() bool {
+		select {
+		case id := <-updated:
+			return id == "test.blocking.hung"
+		default:
+			return false
+		}
+	}, 5*time.Second, 10*time.Millisecond)
+
+	diags = applyFromContent(t, loader, []byte(`
+		test.blocking "hung" {}
+		testcomponents.passthrough "static" { input = "b" }
+	`), nil, nil)
+	require.NoError(t, diags.ErrorOrNil())
+	require.Equal(t, controller.ApplyStats{Unchanged: 4, Evaluated: 1}, loader.LastApplyStats())
+}
+
+func TestLoader_NestedIncrementalApply(t *testing.T) {
+	l, _ := logging.New(os.Stderr, logging.DefaultOptions)
+	loader := controller.NewLoader(controller.LoaderOptions{
+		ComponentGlobals: controller.ComponentGlobals{
+			ControllerID:      "declared.default",
+			Logger:            l,
+			TraceProvider:     noop.NewTracerProvider(),
+			DataPath:          t.TempDir(),
+			MinStability:      featuregate.StabilityPublicPreview,
+			OnBlockNodeUpdate: func(cn controller.BlockNode) { /* no-op */ },
+			Registerer:        prometheus.NewRegistry(),
+			NewModuleController: func(opts controller.ModuleControllerOpts) controller.ModuleController {
+				return nil
+			},
+		},
+	})
+
+	// apply loads content the way the nested module of a custom component
+	// does, with a registry of the parent definitions and an argument scope.
+	apply := func(content string, modulePath string) {
+		blocks, diags := fileToBlock(t, []byte(content))
+		require.Empty(t, diags)
+		diags = loader.Apply(controller.ApplyOptions{
+			ComponentBlocks:         blocks,
+			CustomComponentRegistry: controller.NewCustomComponentRegistry(nil, nil),
+			ArgScope:                vm.NewScope(map[string]any{"module_path": modulePath}),
+		})
+		require.NoError(t, diags.ErrorOrNil())
+	}
+
+	apply(`
+		testcomponents.passthrough "a" { input = "a" }
+		testcomponents.passthrough "b" { input = "b" }
+	`, "/a")
+	require.Equal(t, controller.ApplyStats{Added: 2, Evaluated: 2, Full: true}, loader.LastApplyStats())
+
+	// Only the changed block is evaluated.
+	apply(`
+		testcomponents.passthrough "a" { input = "a" }
+		testcomponents.passthrough "b" { input = "c" }
+	`, "/a")
+	require.Equal(t, controller.ApplyStats{Changed: 1, Unchanged: 1, Evaluated: 1}, loader.LastApplyStats())
+
+	// A change of the argument scope evaluates all the blocks.
+	apply(`
+		testcomponents.passthrough "a" { input = "a" }
+		testcomponents.passthrough "b" { input = "c" }
+	`, "/b")
+	require.Equal(t, controller.ApplyStats{Unchanged: 2, Evaluated: 2, Full: true}, loader.LastApplyStats())
+}
+
+func TestLoader_EvaluationOrder(t *testing.T) {
+	testFile := `
+		testcomponents.passthrough "forwarded" {
+			input = testcomponents.passthrough.ticker.output
+		}
+
+		testcomponents.passthrough "ticker" {
+			input = testcomponents.tick.ticker.tick_time
+		}
+
+		testcomponents.tick "ticker" {
+			frequency = "1s"
+		}
+	`
+	l, _ := logging.New(os.Stderr, logging.DefaultOptions)
+	loader := controller.NewLoader(controller.LoaderOptions{
+		ComponentGlobals: controller.ComponentGlobals{
+			ControllerID:      "module",
+			Logger:            l,
+			TraceProvider:     noop.NewTracerProvider(),
+			DataPath:          t.TempDir(),
+			MinStability:      featuregate.StabilityPublicPreview,
+			OnBlockNodeUpdate: func(cn controller.BlockNode) { /* no-op */ },
+			Registerer:        prometheus.NewRegistry(),
+			NewModuleController: func(opts controller.ModuleControllerOpts) controller.ModuleController {
+				return nil
+			},
+		},
+	})
+	require.Empty(t, loader.EvaluationOrder())
+
+	diags := applyFromContent(t, loader, []byte(testFile), nil, nil)
+	require.NoError(t, diags.ErrorOrNil())
+
+	// The nodes come after the nodes they depend on.
+	var ids []string
+	for i, e := range loader.EvaluationOrder() {
+		ids = append(ids, e.NodeID)
+		require.Equal(t, i, e.Order)
+		require.False(t, e.LastEvaluated.IsZero())
+		require.Zero(t, e.LastQueueWait)
+	}
+	require.Equal(t, []string{
+		"testcomponents.tick.ticker",
+		"testcomponents.passthrough.ticker",
+		"testcomponents.passthrough.forwarded",
+	}, ids)
+}
+
 func applyFromContent(t *testing.T, l *controller.Loader, componentBytes []byte, configBytes []byte, declareBytes []byte) diag.Diagnostics {
 	t.Helper()
 
@@ -15,6 +15,7 @@ type controllerMetrics struct {
 	evaluationQueueSize         prometheus.Gauge
 	slowComponentThreshold      time.Duration
 	slowComponentEvaluationTime *prometheus.CounterVec
+	evaluationTimeouts          *prometheus.CounterVec
 }
 
 // newControllerMetrics inits the metrics for the components controller
@@ -69,6 +70,12 @@ func newControllerMetrics(parent, id string) *controllerMetrics {
 		ConstLabels: map[string]string{"controller_path": parent, "controller_id": id},
 	}, []string{"component_id"})
 
+	cm.evaluationTimeouts = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name:        "alloy_component_evaluation_timeouts_total",
+		Help:        "Number of evaluations of components and configuration blocks which exceeded the evaluation timeout",
+		ConstLabels: map[string]string{"controller_path": parent, "controller_id": id},
+	}, []string{"component_id"})
+
 	return cm
 }
 
@@ -85,6 +92,7 @@ func (cm *controllerMetrics) Collect(ch chan<- prometheus.Metric) {
 	cm.dependenciesWaitTime.Collect(ch)
 	cm.evaluationQueueSize.Collect(ch)
 	cm.slowComponentEvaluationTime.Collect(ch)
+	cm.evaluationTimeouts.Collect(ch)
 }
 
 func (cm *controllerMetrics) Describe(ch chan<- *prometheus.Desc) {
@@ -93,6 +101,7 @@ func (cm *controllerMetrics) Describe(ch chan<- *prometheus.Desc) {
 	cm.dependenciesWaitTime.Describe(ch)
 	cm.evaluationQueueSize.Describe(ch)
 	cm.slowComponentEvaluationTime.Describe(ch)
+	cm.evaluationTimeouts.Describe(ch)
 }
 
 type controllerCollector struct {
@@ -18,6 +18,7 @@ import (
 
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/fips"
 	"github.com/grafana/alloy/internal/runtime/equality"
 	"github.com/grafana/alloy/internal/runtime/logging"
 	"github.com/grafana/alloy/internal/runtime/tracing"
@@ -68,6 +69,10 @@ type ModuleControllerOpts struct {
 	// When RegOverride is not nil, the registry used in the module will be RegOverride.
 	// This can be used to disable metrics for modules by giving them a no-op registry.
 	RegOverride prometheus.Registerer
+
+	// When MinStability is undefined, the module uses the minimum stability
+	// level of its parent. Otherwise, the module uses MinStability.
+	MinStability featuregate.Stability
 }
 
 // ComponentGlobals are used by BuiltinComponentNodes to build managed components. All
@@ -84,6 +89,9 @@ type ComponentGlobals struct {
 	NewModuleController  func(opts ModuleControllerOpts) ModuleController // Func to generate a module controller.
 	GetServiceData       func(name string) (interface{}, error)           // Get data for a service.
 	EnableCommunityComps bool                                             // Enables the use of community components.
+	DryRun               bool                                             // Decode arguments without building components or updating services.
+	EnforceFIPS          bool                                             // Refuse to build components with arguments which aren't FIPS approved.
+	EvaluationTimeout    time.Duration                                    // Maximum duration of the evaluation of a builtin component. Zero means no timeout.
 }
 
 // BuiltinComponentNode is a controller node which manages a builtin component.
@@ -103,12 +111,23 @@ type BuiltinComponentNode struct {
 	exportsType       reflect.Type
 	moduleController  ModuleController
 	OnBlockNodeUpdate func(cn BlockNode) // Informs controller that we need to reevaluate
+	dryRun            bool               // Whether to only decode arguments, without building the component
+	enforceFIPS       bool               // Whether to refuse arguments which aren't FIPS approved
+
+	// blockMut guards the block separately from mut, so that the block can be
+	// updated while an evaluation which timed out is still running.
+	blockMut sync.RWMutex
+	block    *ast.BlockStmt // Current Alloy block to derive args from
+	eval     *vm.Evaluator
 
 	mut     sync.RWMutex
-	block   *ast.BlockStmt // Current Alloy block to derive args from
-	eval    *vm.Evaluator
 	managed component.Component // Inner managed component
 	args    component.Arguments // Evaluated arguments for the managed component
+	fipsErr error               // Settings of the last evaluated arguments which aren't FIPS approved
+
+	// fipsUnapplied is set when fipsErr was checked for arguments which
+	// differ from args.
+	fipsUnapplied bool
 
 	// NOTE(rfratto): health and exports have their own mutex because they may be
 	// set asynchronously while mut is still being held (i.e., when calling Evaluate
@@ -117,6 +136,7 @@ type BuiltinComponentNode struct {
 	healthMut  sync.RWMutex
 	evalHealth component.Health // Health of the last evaluate
 	runHealth  component.Health // Health of running the component
+	history    healthHistory    // Recent health transitions
 
 	exportsMut sync.RWMutex
 	exports    component.Exports // Evaluated exports for the managed component
@@ -161,6 +181,8 @@ func NewBuiltinComponentNode(globals ComponentGlobals, reg component.Registratio
 		exportsType:       getExportsType(reg),
 		moduleController:  globals.NewModuleController(ModuleControllerOpts{Id: globalID}),
 		OnBlockNodeUpdate: globals.OnBlockNodeUpdate,
+		dryRun:            globals.DryRun,
+		enforceFIPS:       globals.EnforceFIPS,
 
 		block: b,
 		eval:  vm.New(b.Body),
@@ -247,8 +269,8 @@ func (cn *BuiltinComponentNode) UpdateBlock(b *ast.BlockStmt) {
 		panic("UpdateBlock called with an block with a different component ID")
 	}
 
-	cn.mut.Lock()
-	defer cn.mut.Unlock()
+	cn.blockMut.Lock()
+	defer cn.blockMut.Unlock()
 	cn.block = b
 	cn.eval = vm.New(b.Body)
 }
@@ -276,8 +298,12 @@ func (cn *BuiltinComponentNode) evaluate(scope *vm.Scope) error {
 	cn.mut.Lock()
 	defer cn.mut.Unlock()
 
+	cn.blockMut.RLock()
+	eval := cn.eval
+	cn.blockMut.RUnlock()
+
 	argsPointer := cn.reg.CloneArguments()
-	if err := cn.eval.Evaluate(scope, argsPointer); err != nil {
+	if err := eval.Evaluate(scope, argsPointer); err != nil {
 		return fmt.Errorf("decoding configuration: %w", err)
 	}
 
@@ -285,6 +311,25 @@ func (cn *BuiltinComponentNode) evaluate(scope *vm.Scope) error {
 	// components expect a non-pointer.
 	argsCopyValue := reflect.ValueOf(argsPointer).Elem().Interface()
 
+	// Walking the arguments is expensive, so they're only checked again when
+	// they change, or when the last checked arguments weren't applied.
+	argsChanged := cn.args == nil || !equality.DeepEqual(cn.args, argsCopyValue)
+	if argsChanged || cn.fipsUnapplied {
+		cn.fipsErr = fips.Check(argsCopyValue)
+	}
+	cn.fipsUnapplied = argsChanged
+	if cn.fipsErr != nil && cn.enforceFIPS {
+		return fmt.Errorf("arguments are not FIPS compliant: %w", cn.fipsErr)
+	}
+
+	if cn.dryRun {
+		// Components are never built when validating the configuration; their
+		// exports keep their zero value.
+		cn.args = argsCopyValue
+		cn.fipsUnapplied = false
+		return nil
+	}
+
 	if cn.managed == nil {
 		// We haven't built the managed component successfully yet.
 		managed, err := cn.reg.Build(cn.managedOpts, argsCopyValue)
@@ -293,11 +338,12 @@ func (cn *BuiltinComponentNode) evaluate(scope *vm.Scope) error {
 		}
 		cn.managed = managed
 		cn.args = argsCopyValue
+		cn.fipsUnapplied = false
 
 		return nil
 	}
 
-	if equality.DeepEqual(cn.args, argsCopyValue) {
+	if !argsChanged {
 		// Ignore components which haven't changed. This reduces the cost of
 		// calling evaluate for components where evaluation is expensive (e.g., if
 		// re-evaluating requires re-starting some internal logic).
@@ -310,6 +356,7 @@ func (cn *BuiltinComponentNode) evaluate(scope *vm.Scope) error {
 	}
 
 	cn.args = argsCopyValue
+	cn.fipsUnapplied = false
 	return nil
 }
 
@@ -329,6 +376,7 @@ func (cn *BuiltinComponentNode) Run(ctx context.Context) error {
 	}
 
 	cn.setRunHealth(component.HealthTypeHealthy, "started component")
+
 	err := cn.managed.Run(ctx)
 
 	// Note: logging of this error is handled by the scheduler.
@@ -353,8 +401,8 @@ func (cn *BuiltinComponentNode) Arguments() component.Arguments {
 
 // Block implements BlockNode and returns the current block of the managed component.
 func (cn *BuiltinComponentNode) Block() *ast.BlockStmt {
-	cn.mut.RLock()
-	defer cn.mut.RUnlock()
+	cn.blockMut.RLock()
+	defer cn.blockMut.RUnlock()
 	return cn.block
 }
 
@@ -405,6 +453,9 @@ func (cn *BuiltinComponentNode) setExports(e component.Exports) {
 //  1. Health from the call to Run().
 //  2. Health from the last call to Evaluate().
 //  3. Health reported from the component.
+//
+// A change of the health reported from the component is recorded in the
+// health history when it's observed by CurrentHealth.
 func (cn *BuiltinComponentNode) CurrentHealth() component.Health {
 	cn.healthMut.RLock()
 	defer cn.healthMut.RUnlock()
@@ -414,12 +465,23 @@ func (cn *BuiltinComponentNode) CurrentHealth() component.Health {
 		evalHealth = cn.evalHealth
 	)
 
+	h := component.LeastHealthy(runHealth, evalHealth)
 	if hc, ok := cn.managed.(component.HealthComponent); ok {
 		componentHealth := hc.CurrentHealth()
-		return component.LeastHealthy(runHealth, evalHealth, componentHealth)
+		h = component.LeastHealthy(runHealth, evalHealth, componentHealth)
 	}
 
-	return component.LeastHealthy(runHealth, evalHealth)
+	cn.history.record(h)
+	return h
+}
+
+// HealthHistory returns the recent transitions of the health of the
+// BuiltinComponentNode, ordered from newest to oldest.
+func (cn *BuiltinComponentNode) HealthHistory() []component.Health {
+	// Record any change of the health reported from the component since it
+	// was last observed.
+	cn.CurrentHealth()
+	return cn.history.list()
 }
 
 // DebugInfo returns debugging information from the managed component (if any).
@@ -433,30 +495,42 @@ func (cn *BuiltinComponentNode) DebugInfo() interface{} {
 	return nil
 }
 
+// FIPSViolations returns the settings of the last evaluated arguments which
+// aren't approved by FIPS 140-3, or nil if they're all approved.
+func (cn *BuiltinComponentNode) FIPSViolations() error {
+	cn.mut.RLock()
+	defer cn.mut.RUnlock()
+	return cn.fipsErr
+}
+
 // setEvalHealth sets the internal health from a call to Evaluate. See Health
 // for information on how overall health is calculated.
 func (cn *BuiltinComponentNode) setEvalHealth(t component.HealthType, msg string) {
 	cn.healthMut.Lock()
-	defer cn.healthMut.Unlock()
-
 	cn.evalHealth = component.Health{
 		Health:     t,
 		Message:    msg,
 		UpdateTime: time.Now(),
 	}
+	cn.healthMut.Unlock()
+
+	// CurrentHealth locks healthMut and records the new health.
+	cn.CurrentHealth()
 }
 
 // setRunHealth sets the internal health from a call to Run. See Health for
 // information on how overall health is calculated.
 func (cn *BuiltinComponentNode) setRunHealth(t component.HealthType, msg string) {
 	cn.healthMut.Lock()
-	defer cn.healthMut.Unlock()
-
 	cn.runHealth = component.Health{
 		Health:     t,
 		Message:    msg,
 		UpdateTime: time.Now(),
 	}
+	cn.healthMut.Unlock()
+
+	// CurrentHealth locks healthMut and records the new health.
+	cn.CurrentHealth()
 }
 
 // ModuleIDs returns the current list of modules that this component is
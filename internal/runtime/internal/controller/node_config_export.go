@@ -2,9 +2,12 @@ package controller
 
 import (
 	"fmt"
+	"reflect"
+	"slices"
 	"strings"
 	"sync"
 
+	"github.com/grafana/alloy/syntax/alloytypes"
 	"github.com/grafana/alloy/syntax/ast"
 	"github.com/grafana/alloy/syntax/vm"
 )
@@ -36,9 +39,13 @@ func NewExportConfigNode(block *ast.BlockStmt, globals ComponentGlobals) *Export
 }
 
 type exportBlock struct {
-	Value any `alloy:"value,attr"`
+	Value any    `alloy:"value,attr"`
+	Type  string `alloy:"type,attr,optional"`
 }
 
+// exportTypes are the types that an export block can declare.
+var exportTypes = []string{"any", "string", "number", "bool", "list", "map", "secret", "function", "capsule"}
+
 // Evaluate implements BlockNode and updates the arguments for the managed config block
 // by re-evaluating its Alloy block with the provided scope. The managed config block
 // will be built the first time Evaluate is called.
@@ -53,10 +60,97 @@ func (cn *ExportConfigNode) Evaluate(scope *vm.Scope) error {
 	if err := cn.eval.Evaluate(scope, &export); err != nil {
 		return fmt.Errorf("decoding configuration: %w", err)
 	}
+	if err := checkExportType(export.Type, export.Value); err != nil {
+		return fmt.Errorf("export %q: %w", cn.label, err)
+	}
 	cn.value = export.Value
 	return nil
 }
 
+// checkExportType returns an error if value doesn't match the type declared by
+// an export block. Null values match every type.
+func checkExportType(typ string, value any) error {
+	if typ == "" || typ == "any" {
+		return nil
+	}
+	if !slices.Contains(exportTypes, typ) {
+		return fmt.Errorf("invalid type %q, must be one of %s", typ, strings.Join(exportTypes, ", "))
+	}
+	if value == nil {
+		return nil
+	}
+
+	if got := exportValueType(value); got != typ && !(typ == "secret" && got == "string") {
+		return fmt.Errorf("value must be of type %s, got %s", typ, got)
+	}
+	return nil
+}
+
+// exportValueType returns the type of an evaluated export value.
+func exportValueType(value any) string {
+	switch v := value.(type) {
+	case alloytypes.Secret:
+		return "secret"
+	case alloytypes.OptionalSecret:
+		if v.IsSecret {
+			return "secret"
+		}
+		return "string"
+	}
+
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Bool:
+		return "bool"
+	case reflect.Slice, reflect.Array:
+		return "list"
+	case reflect.Map, reflect.Struct:
+		return "map"
+	case reflect.Func:
+		return "function"
+	default:
+		return "capsule"
+	}
+}
+
+// declaredExportTypes returns the types declared by the export blocks of a
+// declare template, keyed by export name. Exports without a type are of type
+// any.
+func declaredExportTypes(template ast.Body) (map[string]string, error) {
+	res := make(map[string]string)
+	for _, stmt := range template {
+		block, ok := stmt.(*ast.BlockStmt)
+		if !ok || block.GetBlockName() != exportBlockID {
+			continue
+		}
+		res[block.Label] = "any"
+		for _, stmt := range block.Body {
+			if attr, ok := stmt.(*ast.AttributeStmt); ok && attr.Name.Name == "type" {
+				var typ string
+				if err := vm.New(attr.Value).Evaluate(vm.NewScope(nil), &typ); err != nil {
+					return nil, fmt.Errorf("decoding the type of export %q: %w", block.Label, err)
+				}
+				if typ != "" {
+					res[block.Label] = typ
+				}
+			}
+		}
+	}
+	return res, nil
+}
+
+// exportTypeCompatible returns true if an export declared with the declared
+// type can be used where the expected type is expected. A string can be used
+// as a secret.
+func exportTypeCompatible(expected, declared string) bool {
+	return expected == "any" || expected == declared || (expected == "secret" && declared == "string")
+}
+
 func (cn *ExportConfigNode) Label() string { return cn.label }
 
 // Value returns the value of the export.
@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/syntax/alloytypes"
+)
+
+func TestCheckExportType(t *testing.T) {
+	tt := []struct {
+		typ       string
+		value     any
+		expectErr string
+	}{
+		{typ: "", value: 1},
+		{typ: "any", value: "a"},
+		{typ: "number", value: nil},
+		{typ: "number", value: 1},
+		{typ: "number", value: 1.5},
+		{typ: "number", value: "1", expectErr: "value must be of type number, got string"},
+		{typ: "string", value: "a"},
+		{typ: "string", value: alloytypes.OptionalSecret{Value: "a"}},
+		{typ: "string", value: alloytypes.Secret("a"), expectErr: "value must be of type string, got secret"},
+		{typ: "secret", value: alloytypes.Secret("a")},
+		{typ: "secret", value: "a"},
+		{typ: "bool", value: true},
+		{typ: "list", value: []any{1, 2}},
+		{typ: "list", value: map[string]any{"a": 1}, expectErr: "value must be of type list, got map"},
+		{typ: "map", value: map[string]any{"a": 1}},
+		{typ: "function", value: func() int { return 1 }},
+		{typ: "capsule", value: make(chan int)},
+		{typ: "object", value: 1, expectErr: `invalid type "object", must be one of any, string, number, bool, list, map, secret, function, capsule`},
+	}
+	for _, tc := range tt {
+		err := checkExportType(tc.typ, tc.value)
+		if tc.expectErr != "" {
+			require.EqualError(t, err, tc.expectErr, "%s: %v", tc.typ, tc.value)
+		} else {
+			require.NoError(t, err, "%s: %v", tc.typ, tc.value)
+		}
+	}
+}
+
+func TestExportTypeCompatible(t *testing.T) {
+	require.True(t, exportTypeCompatible("any", "number"))
+	require.True(t, exportTypeCompatible("number", "number"))
+	require.True(t, exportTypeCompatible("secret", "string"))
+	require.False(t, exportTypeCompatible("string", "secret"))
+	require.False(t, exportTypeCompatible("number", "any"))
+	require.False(t, exportTypeCompatible("list", "map"))
+}
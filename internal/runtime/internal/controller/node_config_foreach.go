@@ -6,7 +6,9 @@ import (
 	"encoding/hex"
 	"fmt"
 	"hash/fnv"
+	"maps"
 	"path"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -18,16 +20,23 @@ import (
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/runner"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/syntax"
 	"github.com/grafana/alloy/syntax/ast"
 	"github.com/grafana/alloy/syntax/vm"
 )
 
 const templateType = "template"
 
+// defaultIndexVar is the default name of the variable referring to the
+// position of the current item in the collection.
+const defaultIndexVar = "index"
+
 // The ForeachConfigNode will create the pipeline defined in its template block for each entry defined in its collection argument.
 // Each pipeline is managed by a custom component.
 // The custom component has access to the root scope (it can access exports and modules outside of the foreach template).
 // The collection may contain any item. Each child has one item from the collection associated to him and that can be accessed via the defined var argument.
+// When the collection is an object, each child has one entry whose key and value can be accessed via the key_var and value_var arguments.
+// The position of the item in the collection can be accessed via the index_var argument.
 // Nesting foreach blocks is allowed.
 type ForeachConfigNode struct {
 	id               ComponentID
@@ -58,6 +67,7 @@ type ForeachConfigNode struct {
 	healthMut  sync.RWMutex
 	evalHealth component.Health // Health of the last evaluate
 	runHealth  component.Health // Health of running the component
+	history    healthHistory    // Recent health transitions
 
 	dataFlowEdgeMut  sync.RWMutex
 	dataFlowEdgeRefs []string
@@ -124,8 +134,11 @@ func (fn *ForeachConfigNode) ID() ComponentID {
 }
 
 type ForEachArguments struct {
-	Collection []any  `alloy:"collection,attr"`
-	Var        string `alloy:"var,attr"`
+	Collection any    `alloy:"collection,attr"`
+	Var        string `alloy:"var,attr,optional"`
+	KeyVar     string `alloy:"key_var,attr,optional"`
+	ValueVar   string `alloy:"value_var,attr,optional"`
+	IndexVar   string `alloy:"index_var,attr,optional"`
 
 	// enable_metrics should be false by default.
 	// That way users are protected from an explosion of debug metrics
@@ -133,6 +146,99 @@ type ForEachArguments struct {
 	EnableMetrics bool `alloy:"enable_metrics,attr,optional"`
 }
 
+// Validate implements syntax.Validator.
+func (args *ForEachArguments) Validate() error {
+	if _, isObject := collectionObject(args.Collection); isObject {
+		if args.Var != "" {
+			return fmt.Errorf("var can't be used when the collection is an object, use key_var and value_var instead")
+		}
+		if args.KeyVar == "" && args.ValueVar == "" {
+			return fmt.Errorf("key_var or value_var must be set when the collection is an object")
+		}
+		return nil
+	}
+
+	switch args.Collection.(type) {
+	case []any, nil:
+	default:
+		return fmt.Errorf("collection must be a list or an object, got %T", args.Collection)
+	}
+	if args.Var == "" {
+		return fmt.Errorf("var must be set when the collection is a list")
+	}
+	if args.KeyVar != "" || args.ValueVar != "" {
+		return fmt.Errorf("key_var and value_var can only be used when the collection is an object")
+	}
+	return nil
+}
+
+// forEachItem is an item of the collection of a foreach block.
+type forEachItem struct {
+	fingerprint string         // Identifies the child of the item across evaluations.
+	vars        map[string]any // Variables referring to the item.
+}
+
+// items returns the items of the collection. The items of a list are
+// identified by their value, and the entries of an object by their key, in
+// the order of the keys.
+func (args *ForEachArguments) items() []forEachItem {
+	object, isObject := collectionObject(args.Collection)
+	if !isObject {
+		list, _ := args.Collection.([]any)
+		items := make([]forEachItem, 0, len(list))
+		for _, item := range list {
+			items = append(items, forEachItem{
+				fingerprint: objectFingerprint(item),
+				vars:        map[string]any{args.Var: item},
+			})
+		}
+		return items
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	items := make([]forEachItem, 0, len(keys))
+	for _, key := range keys {
+		// The value takes precedence if both variables have the same name.
+		vars := make(map[string]any, 2)
+		if args.KeyVar != "" {
+			vars[args.KeyVar] = key
+		}
+		if args.ValueVar != "" {
+			vars[args.ValueVar] = object[key]
+		}
+		items = append(items, forEachItem{
+			fingerprint: objectFingerprint(key),
+			vars:        vars,
+		})
+	}
+	return items
+}
+
+// collectionObject returns the entries of collection if it's an object, or a
+// capsule which can be converted into one, such as a discovery target.
+func collectionObject(collection any) (map[string]any, bool) {
+	switch c := collection.(type) {
+	case map[string]any:
+		return c, true
+	case syntax.ConvertibleIntoCapsule:
+		var values map[string]syntax.Value
+		if err := c.ConvertInto(&values); err != nil {
+			return nil, false
+		}
+		object := make(map[string]any, len(values))
+		for key, value := range values {
+			object[key] = value.Interface()
+		}
+		return object, true
+	}
+	return nil, false
+}
+
 func (fn *ForeachConfigNode) Evaluate(evalScope *vm.Scope) error {
 	err := fn.evaluate(evalScope)
 
@@ -167,7 +273,7 @@ func (fn *ForeachConfigNode) evaluate(scope *vm.Scope) error {
 
 	eval := vm.New(argsBody)
 
-	var args ForEachArguments
+	args := ForEachArguments{IndexVar: defaultIndexVar}
 	if err := eval.Evaluate(scope, &args); err != nil {
 		return fmt.Errorf("decoding configuration: %w", err)
 	}
@@ -186,13 +292,14 @@ func (fn *ForeachConfigNode) evaluate(scope *vm.Scope) error {
 
 	// Loop through the items to create the custom components.
 	// On re-evaluation new components are added and existing ones are updated.
-	newCustomComponentIds := make(map[string]bool, len(args.Collection))
+	items := args.items()
+	newCustomComponentIds := make(map[string]bool, len(items))
 	fn.customComponentHashCounts = make(map[string]int)
-	for i := 0; i < len(args.Collection); i++ {
+	for i, item := range items {
 		// We must create an ID from the collection entries to avoid recreating all components on every updates.
 		// We track the hash counts because the collection might contain duplicates ([1, 1, 1] would result in the same ids
 		// so we handle it by adding the count at the end -> [11, 12, 13]
-		customComponentID := fmt.Sprintf("foreach_%s", objectFingerprint(args.Collection[i]))
+		customComponentID := fmt.Sprintf("foreach_%s", item.fingerprint)
 		count := fn.customComponentHashCounts[customComponentID] // count = 0 if the key is not found
 		fn.customComponentHashCounts[customComponentID] = count + 1
 		customComponentID += fmt.Sprintf("_%d", count+1)
@@ -202,9 +309,12 @@ func (fn *ForeachConfigNode) evaluate(scope *vm.Scope) error {
 			return err
 		}
 
-		// Expose the current scope + the collection item that correspond to the child.
+		// Expose the current scope + the index and the collection item that correspond to the child.
+		// The index shadows a variable of the same name in the scope, such as the index of an enclosing foreach.
+		// The item takes precedence if both variables have the same name.
 		vars := deepCopyMap(scope.Variables)
-		vars[args.Var] = args.Collection[i]
+		vars[args.IndexVar] = i
+		maps.Copy(vars, item.vars)
 
 		customComponentRegistry := NewCustomComponentRegistry(fn.customReg, vm.NewScope(vars))
 		if err := cc.LoadBody(template.Body, map[string]any{}, customComponentRegistry); err != nil {
@@ -323,6 +433,12 @@ func (fn *ForeachConfigNode) CurrentHealth() component.Health {
 	return component.LeastHealthy(fn.runHealth, fn.evalHealth)
 }
 
+// HealthHistory returns the recent transitions of the health of the
+// ForeachConfigNode, ordered from newest to oldest.
+func (fn *ForeachConfigNode) HealthHistory() []component.Health {
+	return fn.history.list()
+}
+
 func (fn *ForeachConfigNode) setEvalHealth(t component.HealthType, msg string) {
 	fn.healthMut.Lock()
 	defer fn.healthMut.Unlock()
@@ -332,6 +448,7 @@ func (fn *ForeachConfigNode) setEvalHealth(t component.HealthType, msg string) {
 		Message:    msg,
 		UpdateTime: time.Now(),
 	}
+	fn.history.record(component.LeastHealthy(fn.runHealth, fn.evalHealth))
 }
 
 func (fn *ForeachConfigNode) setRunHealth(t component.HealthType, msg string) {
@@ -343,6 +460,7 @@ func (fn *ForeachConfigNode) setRunHealth(t component.HealthType, msg string) {
 		Message:    msg,
 		UpdateTime: time.Now(),
 	}
+	fn.history.record(component.LeastHealthy(fn.runHealth, fn.evalHealth))
 }
 
 func (fn *ForeachConfigNode) AddDataFlowEdgeTo(nodeID string) {
@@ -2,6 +2,7 @@ package controller
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"testing"
 	"time"
@@ -284,7 +285,105 @@ func TestCollectionNonArrayValue(t *testing.T) {
 		}
 	}`
 	foreachConfigNode := NewForeachConfigNode(getBlockFromConfig(t, config), getComponentGlobals(t), nil)
-	require.ErrorContains(t, foreachConfigNode.Evaluate(vm.NewScope(make(map[string]interface{}))), `"aaa" should be array, got string`)
+	require.ErrorContains(t, foreachConfigNode.Evaluate(vm.NewScope(make(map[string]interface{}))), "collection must be a list or an object, got string")
+}
+
+func TestCollectionObject(t *testing.T) {
+	config := `foreach "default" {
+		collection = {"b" = 20, "a" = 10}
+		key_var = "key"
+		value_var = "num"
+		template {
+		}
+	}`
+	foreachConfigNode := NewForeachConfigNode(getBlockFromConfig(t, config), getComponentGlobals(t), nil)
+	require.NoError(t, foreachConfigNode.Evaluate(vm.NewScope(make(map[string]interface{}))))
+
+	variables := func(id string) map[string]any {
+		return foreachConfigNode.customComponents[id].(*CustomComponentMock).Variables
+	}
+	// The entries are iterated in the order of their keys.
+	require.Equal(t, map[string]any{"key": "a", "num": 10, "index": 0}, variables("foreach_a_1"))
+	require.Equal(t, map[string]any{"key": "b", "num": 20, "index": 1}, variables("foreach_b_1"))
+
+	// The children are identified by the keys, so changing a value updates
+	// the existing child.
+	cc := foreachConfigNode.customComponents["foreach_a_1"]
+	foreachConfigNode.UpdateBlock(getBlockFromConfig(t, `foreach "default" {
+		collection = {"a" = 30}
+		value_var = "num"
+		template {
+		}
+	}`))
+	require.NoError(t, foreachConfigNode.Evaluate(vm.NewScope(make(map[string]interface{}))))
+	require.Len(t, foreachConfigNode.customComponents, 1)
+	require.Same(t, cc, foreachConfigNode.customComponents["foreach_a_1"])
+	require.Equal(t, map[string]any{"num": 30, "index": 0}, variables("foreach_a_1"))
+}
+
+func TestCollectionVariablesValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		collection  string
+		vars        string
+		expectedErr string
+	}{
+		{"list without var", `[1]`, `key_var = "k"`, "var must be set when the collection is a list"},
+		{"list with key_var", `[1]`, `var = "v"
+		key_var = "k"`, "key_var and value_var can only be used when the collection is an object"},
+		{"object without key_var or value_var", `{"a" = 1}`, ``, "key_var or value_var must be set when the collection is an object"},
+		{"object with var", `{"a" = 1}`, `var = "v"
+		value_var = "k"`, "var can't be used when the collection is an object"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			config := fmt.Sprintf(`foreach "default" {
+				collection = %s
+				%s
+				template {
+				}
+			}`, tc.collection, tc.vars)
+			foreachConfigNode := NewForeachConfigNode(getBlockFromConfig(t, config), getComponentGlobals(t), nil)
+			require.ErrorContains(t, foreachConfigNode.Evaluate(vm.NewScope(make(map[string]interface{}))), tc.expectedErr)
+		})
+	}
+}
+
+func TestCustomComponentsScope(t *testing.T) {
+	config := `foreach "default" {
+		collection = [10, 20, 10]
+		var = "num"
+		template {
+		}
+	}`
+	foreachConfigNode := NewForeachConfigNode(getBlockFromConfig(t, config), getComponentGlobals(t), nil)
+	scope := vm.NewScope(map[string]any{
+		"argument": map[string]any{"max": map[string]any{"value": 5}},
+	})
+	require.NoError(t, foreachConfigNode.Evaluate(scope))
+
+	variables := func(id string) map[string]any {
+		return foreachConfigNode.customComponents[id].(*CustomComponentMock).Variables
+	}
+	require.Equal(t, map[string]any{
+		"argument": map[string]any{"max": map[string]any{"value": 5}},
+		"num":      10,
+		"index":    0,
+	}, variables("foreach_10_1"))
+	require.Equal(t, 1, variables("foreach_20_1")["index"])
+	require.Equal(t, 2, variables("foreach_10_2")["index"])
+
+	// The item takes precedence over the index if they have the same name.
+	config = `foreach "default" {
+		collection = [10, 20]
+		var = "num"
+		index_var = "num"
+		template {
+		}
+	}`
+	foreachConfigNode = NewForeachConfigNode(getBlockFromConfig(t, config), getComponentGlobals(t), nil)
+	require.NoError(t, foreachConfigNode.Evaluate(vm.NewScope(make(map[string]interface{}))))
+	require.Equal(t, map[string]any{"num": 20}, variables("foreach_20_1"))
 }
 
 func getBlockFromConfig(t *testing.T, config string) *ast.BlockStmt {
@@ -337,9 +436,11 @@ func (m *ModuleControllerMock) Reset() {
 
 type CustomComponentMock struct {
 	IsRunning atomic.Bool
+	Variables map[string]any // variables of the scope of the last load
 }
 
 func (c *CustomComponentMock) LoadBody(body ast.Body, args map[string]any, customComponentRegistry *CustomComponentRegistry) error {
+	c.Variables = customComponentRegistry.Scope().Variables
 	return nil
 }
 
@@ -7,6 +7,8 @@ import (
 	"maps"
 	"path"
 	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -17,6 +19,7 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 
 	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/runner"
 	"github.com/grafana/alloy/internal/runtime/internal/importsource"
 	"github.com/grafana/alloy/internal/runtime/logging/level"
@@ -32,6 +35,10 @@ import (
 // The children are evaluated and ran by the parent.
 // When an ImportConfigNode receives new content from its source, it updates its importedDeclares and recreates its children.
 // Then an update call is propagated to the root ImportConfigNode to inform the controller for reevaluation.
+//
+// The stability argument of the import block sets the minimum stability level of the imported module.
+// The expect blocks of the import block declare the exports that the importer expects from the imported declares.
+// Both are evaluated by the ImportConfigNode and aren't passed to the import source.
 type ImportConfigNode struct {
 	nodeID        string
 	globalID      string
@@ -41,6 +48,7 @@ type ImportConfigNode struct {
 	block         *ast.BlockStmt            // Current Alloy blocks to derive config from
 	source        importsource.ImportSource // source retrieves the module content
 	registry      *prometheus.Registry
+	nested        bool // true if the import block is part of imported content
 
 	OnBlockNodeUpdate func(cn BlockNode) // notifies the controller or the parent for reevaluation
 	logger            log.Logger
@@ -53,6 +61,10 @@ type ImportConfigNode struct {
 	importConfigNodesChildren map[string]*ImportConfigNode
 	importChildrenRunning     bool
 	importedDeclares          map[string]ast.Body
+	importedFunctions         map[string]map[string]any
+	minStability              featuregate.Stability        // Minimum stability level of the imported module
+	expectations              map[string]map[string]string // Types of the expected exports, keyed by declare and export name
+	expectErr                 error                        // Error of checking the last imported content against the expectations
 
 	// NOTE: To avoid deadlocks, whenever we need both locks we must always first lock the mut, then healthMut.
 	healthMut     sync.RWMutex
@@ -87,7 +99,8 @@ func NewImportConfigNode(block *ast.BlockStmt, globals ComponentGlobals, sourceT
 	}
 	managedOpts := getImportManagedOptions(globals, cn)
 	cn.logger = managedOpts.Logger
-	cn.source = importsource.NewImportSource(sourceType, managedOpts, vm.New(block.Body), cn.onContentUpdate)
+	sourceBody, _, _ := splitImportBody(block.Body)
+	cn.source = importsource.NewImportSource(sourceType, managedOpts, vm.New(sourceBody), cn.onContentUpdate)
 	return cn
 }
 
@@ -183,7 +196,7 @@ func (cn *ImportConfigNode) CurrentHealth() component.Health {
 
 // Evaluate implements BlockNode and evaluates the import source.
 func (cn *ImportConfigNode) Evaluate(scope *vm.Scope) error {
-	err := cn.source.Evaluate(scope)
+	err := cn.evaluate(scope)
 	switch err {
 	case nil:
 		cn.setEvalHealth(component.HealthTypeHealthy, "source evaluated")
@@ -194,26 +207,179 @@ func (cn *ImportConfigNode) Evaluate(scope *vm.Scope) error {
 	return err
 }
 
+func (cn *ImportConfigNode) evaluate(scope *vm.Scope) error {
+	minStability, err := cn.evaluateStability(scope)
+	if err != nil {
+		return err
+	}
+
+	expectations, err := cn.evaluateExpectations(scope)
+	if err != nil {
+		return err
+	}
+
+	cn.mut.Lock()
+	reload := cn.minStability != minStability || !reflect.DeepEqual(cn.expectations, expectations)
+	cn.minStability = minStability
+	cn.expectations = expectations
+	cn.mut.Unlock()
+
+	if err := cn.source.Evaluate(scope); err != nil {
+		return err
+	}
+
+	cn.mut.Lock()
+	defer cn.mut.Unlock()
+
+	// The imported content must be processed again with the new stability level
+	// or expectations.
+	if reload && cn.importedContent != nil {
+		cn.loadContent(cn.importedContent)
+	}
+	return cn.expectErr
+}
+
+// evaluateStability returns the minimum stability level of the imported
+// module. It defaults to the minimum stability level of the importer.
+//
+// Only import blocks of the root configuration can lower the stability level,
+// so that modules can't grant themselves access to less stable features.
+func (cn *ImportConfigNode) evaluateStability(scope *vm.Scope) (featuregate.Stability, error) {
+	cn.mut.RLock()
+	_, attr, _ := splitImportBody(cn.block.Body)
+	cn.mut.RUnlock()
+	if attr == nil {
+		return cn.globals.MinStability, nil
+	}
+
+	var (
+		value        string
+		minStability featuregate.Stability
+	)
+	if err := vm.New(attr.Value).Evaluate(scope, &value); err != nil {
+		return featuregate.StabilityUndefined, fmt.Errorf("decoding stability: %w", err)
+	}
+	if err := minStability.Set(value); err != nil {
+		return featuregate.StabilityUndefined, err
+	}
+
+	if minStability < cn.globals.MinStability && (cn.nested || cn.globals.ControllerID != "") {
+		return featuregate.StabilityUndefined, fmt.Errorf(
+			"stability level %s is below the minimum stability level %s of the module, only import blocks of the root configuration can lower the stability level",
+			minStability,
+			cn.globals.MinStability,
+		)
+	}
+	return minStability, nil
+}
+
+// expectBlock is the interface that an importer expects from a declare block
+// of the imported module.
+type expectBlock struct {
+	// Exports are the types of the expected exports, keyed by export name.
+	Exports map[string]string `alloy:"exports,attr,optional"`
+}
+
+// evaluateExpectations returns the types of the exports expected by the expect
+// blocks of the import block, keyed by declare and export name.
+func (cn *ImportConfigNode) evaluateExpectations(scope *vm.Scope) (map[string]map[string]string, error) {
+	cn.mut.RLock()
+	_, _, blocks := splitImportBody(cn.block.Body)
+	cn.mut.RUnlock()
+	if len(blocks) == 0 {
+		return nil, nil
+	}
+
+	expectations := make(map[string]map[string]string, len(blocks))
+	for _, block := range blocks {
+		if block.Label == "" {
+			return nil, fmt.Errorf("expect block must have a label with the name of a declare block of the module")
+		}
+		if _, ok := expectations[block.Label]; ok {
+			return nil, fmt.Errorf("expect block %q is defined more than once", block.Label)
+		}
+
+		var expect expectBlock
+		if err := vm.New(block.Body).Evaluate(scope, &expect); err != nil {
+			return nil, fmt.Errorf("decoding expect block %q: %w", block.Label, err)
+		}
+		for export, typ := range expect.Exports {
+			if !slices.Contains(exportTypes, typ) {
+				return nil, fmt.Errorf("expect block %q: invalid type %q for export %q, must be one of %s", block.Label, typ, export, strings.Join(exportTypes, ", "))
+			}
+		}
+		expectations[block.Label] = expect.Exports
+	}
+	return expectations, nil
+}
+
+// checkExpectations returns an error if the imported declares don't provide
+// the exports expected by the expect blocks. It must be called with the mut held.
+func (cn *ImportConfigNode) checkExpectations() error {
+	var errs []string
+	for _, name := range slices.Sorted(maps.Keys(cn.expectations)) {
+		template, ok := cn.importedDeclares[name]
+		if !ok {
+			errs = append(errs, fmt.Sprintf("the module doesn't declare %q", name))
+			continue
+		}
+		declared, err := declaredExportTypes(template)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("declare %q: %s", name, err))
+			continue
+		}
+
+		expected := cn.expectations[name]
+		for _, export := range slices.Sorted(maps.Keys(expected)) {
+			typ, ok := declared[export]
+			switch {
+			case !ok:
+				errs = append(errs, fmt.Sprintf("declare %q doesn't export %q", name, export))
+			case !exportTypeCompatible(expected[export], typ):
+				errs = append(errs, fmt.Sprintf("export %q of declare %q is of type %s, expected %s", export, name, typ, expected[export]))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("the imported module doesn't match the expect blocks of %s: %s", cn.nodeID, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
 // onContentUpdate is triggered every time the managed import source has new content.
 func (cn *ImportConfigNode) onContentUpdate(importedContent map[string]string) {
 	cn.mut.Lock()
 	defer cn.mut.Unlock()
 
-	cn.inContentUpdate.Store(true)
-	defer cn.inContentUpdate.Store(false)
-
 	// If the source sent the same content, there is no need to reload.
 	if maps.Equal(cn.importedContent, importedContent) {
 		return
 	}
 
+	cn.loadContent(importedContent)
+}
+
+// loadContent processes the imported content. It must be called with the mut held.
+func (cn *ImportConfigNode) loadContent(importedContent map[string]string) {
+	cn.inContentUpdate.Store(true)
+	defer cn.inContentUpdate.Store(false)
+
+	// The previous definitions are kept when the new content doesn't match the
+	// expectations.
+	prevDeclares, prevFunctions, prevChildren := cn.importedDeclares, cn.importedFunctions, cn.importConfigNodesChildren
+	cn.expectErr = nil
+
 	cn.importedContent = make(map[string]string)
 	for k, v := range importedContent {
 		cn.importedContent[k] = v
 	}
 	cn.importedDeclares = make(map[string]ast.Body)
+	cn.importedFunctions = make(map[string]map[string]any)
 	cn.importConfigNodesChildren = make(map[string]*ImportConfigNode)
 
+	// The required stability level is the lowest one required by the files of the module.
+	requiredStability := featuregate.StabilityUndefined
+
 	for f, ic := range importedContent {
 		parsedImportedContent, err := parser.ParseFile(cn.label, []byte(ic))
 		if err != nil {
@@ -223,12 +389,41 @@ func (cn *ImportConfigNode) onContentUpdate(importedContent map[string]string) {
 		}
 
 		// populate importedDeclares and importConfigNodesChildren
-		err = cn.processImportedContent(parsedImportedContent)
+		fileStability, err := cn.processImportedContent(parsedImportedContent)
 		if err != nil {
 			level.Error(cn.logger).Log("msg", "failed to process imported content", "file", f, "err", err)
 			cn.setContentHealth(component.HealthTypeUnhealthy, fmt.Sprintf("imported content from %q is invalid: %s", f, err))
 			return
 		}
+		if fileStability != featuregate.StabilityUndefined && (requiredStability == featuregate.StabilityUndefined || fileStability < requiredStability) {
+			requiredStability = fileStability
+		}
+	}
+
+	if requiredStability != featuregate.StabilityUndefined && !cn.minStability.Permits(requiredStability) {
+		err := fmt.Errorf(
+			"the module requires stability level %s, which is below the minimum allowed stability level %s. "+
+				"Set the stability argument of %s to %s to enable it for this module",
+			requiredStability,
+			cn.minStability,
+			cn.nodeID,
+			requiredStability,
+		)
+		level.Error(cn.logger).Log("msg", "imported module requires a lower stability level", "err", err)
+		cn.setContentHealth(component.HealthTypeUnhealthy, err.Error())
+		// The module can't be used until the stability level is granted.
+		cn.importedDeclares = make(map[string]ast.Body)
+		cn.importedFunctions = make(map[string]map[string]any)
+		cn.importConfigNodesChildren = make(map[string]*ImportConfigNode)
+		return
+	}
+
+	cn.expectErr = cn.checkExpectations()
+	if cn.expectErr != nil {
+		level.Error(cn.logger).Log("msg", "imported module doesn't match the expected exports, keeping the previous definitions", "err", cn.expectErr)
+		cn.setContentHealth(component.HealthTypeUnhealthy, cn.expectErr.Error())
+		cn.importedDeclares, cn.importedFunctions, cn.importConfigNodesChildren = prevDeclares, prevFunctions, prevChildren
+		return
 	}
 
 	// evaluate the importConfigNodesChildren that have been created
@@ -252,36 +447,63 @@ func (cn *ImportConfigNode) onContentUpdate(importedContent map[string]string) {
 }
 
 // processImportedContent processes declare and import blocks of the provided ast content.
-func (cn *ImportConfigNode) processImportedContent(content *ast.File) error {
+// It returns the stability level required by the content, if any.
+func (cn *ImportConfigNode) processImportedContent(content *ast.File) (featuregate.Stability, error) {
+	requiredStability := featuregate.StabilityUndefined
 	for _, stmt := range content.Body {
+		if attr, ok := stmt.(*ast.AttributeStmt); ok && attr.Name.Name == stabilityAttr {
+			if requiredStability != featuregate.StabilityUndefined {
+				return featuregate.StabilityUndefined, fmt.Errorf("the stability attribute is set more than once")
+			}
+			var value string
+			if err := vm.New(attr.Value).Evaluate(vm.NewScope(nil), &value); err != nil {
+				return featuregate.StabilityUndefined, fmt.Errorf("decoding stability: %w", err)
+			}
+			if err := requiredStability.Set(value); err != nil {
+				return featuregate.StabilityUndefined, err
+			}
+			continue
+		}
+
 		blockStmt, ok := stmt.(*ast.BlockStmt)
 		if !ok {
-			return fmt.Errorf("only declare and import blocks are allowed in a module")
+			return featuregate.StabilityUndefined, fmt.Errorf("only declare and import blocks and the stability attribute are allowed in a module")
 		}
 
 		componentName := strings.Join(blockStmt.Name, ".")
 		switch componentName {
 		case declareType:
-			cn.processDeclareBlock(blockStmt)
-		case importsource.BlockImportFile, importsource.BlockImportString, importsource.BlockImportHTTP, importsource.BlockImportGit:
+			if err := cn.processDeclareBlock(blockStmt); err != nil {
+				return featuregate.StabilityUndefined, err
+			}
+		case importsource.BlockImportFile, importsource.BlockImportString, importsource.BlockImportHTTP, importsource.BlockImportGit, importsource.BlockImportRegistry, importsource.BlockImportFrom, importsource.BlockImportBuiltin:
 			err := cn.processImportBlock(blockStmt, componentName)
 			if err != nil {
-				return err
+				return featuregate.StabilityUndefined, err
 			}
 		default:
-			return fmt.Errorf("only declare and import blocks are allowed in a module, got %s", componentName)
+			return featuregate.StabilityUndefined, fmt.Errorf("only declare and import blocks are allowed in a module, got %s", componentName)
 		}
 	}
-	return nil
+	return requiredStability, nil
 }
 
-// processDeclareBlock stores the declare definition in the importedDeclares.
-func (cn *ImportConfigNode) processDeclareBlock(stmt *ast.BlockStmt) {
+// processDeclareBlock stores the declare definition in the importedDeclares
+// and its functions in the importedFunctions.
+func (cn *ImportConfigNode) processDeclareBlock(stmt *ast.BlockStmt) error {
 	if _, ok := cn.importedDeclares[stmt.Label]; ok {
 		level.Error(cn.logger).Log("msg", "declare block redefined", "name", stmt.Label)
-		return
+		return nil
 	}
-	cn.importedDeclares[stmt.Label] = stmt.Body
+	template, functions, diags := splitDeclareBody(stmt.Body, cn.minStability)
+	if diags.HasErrors() {
+		return diags
+	}
+	cn.importedDeclares[stmt.Label] = template
+	if len(functions) > 0 {
+		cn.importedFunctions[stmt.Label] = functions
+	}
+	return nil
 }
 
 // processDeclareBlock creates an ImportConfigNode child from the provided import block.
@@ -295,12 +517,16 @@ func (cn *ImportConfigNode) processImportBlock(stmt *ast.BlockStmt, fullName str
 	childGlobals.OnBlockNodeUpdate = cn.onChildrenContentUpdate
 	// Children data paths are nested inside their parents to avoid collisions.
 	childGlobals.DataPath = filepath.Join(childGlobals.DataPath, cn.globalID)
+	// Children inherit the stability level of the module.
+	childGlobals.MinStability = cn.minStability
 
 	if importsource.GetSourceType(cn.block.GetBlockName()) == importsource.HTTP && sourceType == importsource.File {
 		return fmt.Errorf("importing a module via import.http (nodeID: %s) that contains an import.file block is not supported", cn.nodeID)
 	}
 
-	cn.importConfigNodesChildren[stmt.Label] = NewImportConfigNode(stmt, childGlobals, sourceType)
+	child := NewImportConfigNode(stmt, childGlobals, sourceType)
+	child.nested = true
+	cn.importConfigNodesChildren[stmt.Label] = child
 	return nil
 }
 
@@ -416,7 +642,8 @@ func (cn *ImportConfigNode) UpdateBlock(b *ast.BlockStmt) {
 	cn.mut.Lock()
 	defer cn.mut.Unlock()
 	cn.block = b
-	cn.source.SetEval(vm.New(b.Body))
+	sourceBody, _, _ := splitImportBody(b.Body)
+	cn.source.SetEval(vm.New(sourceBody))
 }
 
 func (cn *ImportConfigNode) Label() string { return cn.label }
@@ -434,6 +661,21 @@ func (cn *ImportConfigNode) ImportedDeclares() map[string]ast.Body {
 	return cn.importedDeclares
 }
 
+// ImportedFunctions returns the functions of the declare blocks that it
+// imported, keyed by the name of the custom component.
+func (cn *ImportConfigNode) ImportedFunctions() map[string]map[string]any {
+	cn.mut.RLock()
+	defer cn.mut.RUnlock()
+	return cn.importedFunctions
+}
+
+// MinStability returns the minimum stability level of the imported module.
+func (cn *ImportConfigNode) MinStability() featuregate.Stability {
+	cn.mut.RLock()
+	defer cn.mut.RUnlock()
+	return cn.minStability
+}
+
 // Scope returns the scope associated with the import source.
 func (cn *ImportConfigNode) Scope() *vm.Scope {
 	return vm.NewScope(map[string]interface{}{
@@ -473,3 +715,38 @@ func (cn *ImportConfigNode) Equals(other runner.Task) bool {
 	// TODO: if possible we could find a way to safely reuse previous nodes
 	return cn == other.(*ImportConfigNode)
 }
+
+// stabilityAttr is the name of the attribute setting the stability level of
+// a module, both in import blocks and in the imported content.
+const stabilityAttr = "stability"
+
+// expectBlockName is the name of the blocks of an import block declaring the
+// exports expected from the imported declares.
+const expectBlockName = "expect"
+
+// splitImportBody splits the body of an import block into the body passed to
+// the import source, the stability attribute, which is nil if unset, and the
+// expect blocks.
+func splitImportBody(body ast.Body) (ast.Body, *ast.AttributeStmt, []*ast.BlockStmt) {
+	var (
+		sourceBody = make(ast.Body, 0, len(body))
+		stability  *ast.AttributeStmt
+		expects    []*ast.BlockStmt
+	)
+	for _, stmt := range body {
+		switch stmt := stmt.(type) {
+		case *ast.AttributeStmt:
+			if stmt.Name.Name == stabilityAttr {
+				stability = stmt
+				continue
+			}
+		case *ast.BlockStmt:
+			if stmt.GetBlockName() == expectBlockName {
+				expects = append(expects, stmt)
+				continue
+			}
+		}
+		sourceBody = append(sourceBody, stmt)
+	}
+	return sourceBody, stability, expects
+}
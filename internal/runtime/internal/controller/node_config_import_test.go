@@ -0,0 +1,166 @@
+package controller
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/internal/importsource"
+	"github.com/grafana/alloy/syntax/vm"
+)
+
+func TestImportConfigNode_Stability(t *testing.T) {
+	tt := []struct {
+		name          string
+		config        string
+		controllerID  string
+		minStability  featuregate.Stability
+		expect        featuregate.Stability
+		expectErr     string
+		expectContent string // expected message of an unhealthy content update
+	}{
+		{
+			name: "inherited",
+			config: `import.string "lib" {
+				content = "declare \"a\" {}"
+			}`,
+			expect: featuregate.StabilityGenerallyAvailable,
+		},
+		{
+			name: "granted",
+			config: `import.string "lib" {
+				content   = "stability = \"experimental\"\ndeclare \"a\" {}"
+				stability = "experimental"
+			}`,
+			expect: featuregate.StabilityExperimental,
+		},
+		{
+			name: "required but not granted",
+			config: `import.string "lib" {
+				content = "stability = \"public-preview\"\ndeclare \"a\" {}"
+			}`,
+			expect:        featuregate.StabilityGenerallyAvailable,
+			expectContent: `the module requires stability level "public-preview", which is below the minimum allowed stability level "generally-available". Set the stability argument of import.string.lib to "public-preview" to enable it for this module`,
+		},
+		{
+			name: "required and granted a lower level",
+			config: `import.string "lib" {
+				content   = "stability = \"public-preview\"\ndeclare \"a\" {}"
+				stability = "experimental"
+			}`,
+			expect: featuregate.StabilityExperimental,
+		},
+		{
+			name: "lowered in a module",
+			config: `import.string "lib" {
+				content   = "declare \"a\" {}"
+				stability = "experimental"
+			}`,
+			controllerID: "a.cc",
+			expectErr:    `stability level "experimental" is below the minimum stability level "generally-available" of the module, only import blocks of the root configuration can lower the stability level`,
+		},
+		{
+			name: "raised in a module",
+			config: `import.string "lib" {
+				content   = "declare \"a\" {}"
+				stability = "generally-available"
+			}`,
+			controllerID: "a.cc",
+			minStability: featuregate.StabilityExperimental,
+			expect:       featuregate.StabilityGenerallyAvailable,
+		},
+		{
+			name: "lowered in a nested import",
+			config: `import.string "lib" {
+				content   = "import.string \"nested\" {\ncontent = \"declare \\\"a\\\" {}\"\nstability = \"experimental\"\n}"
+				stability = "public-preview"
+			}`,
+			expect:        featuregate.StabilityPublicPreview,
+			expectContent: `nested import block failed to evaluate: imported node nested failed to evaluate, stability level "experimental" is below the minimum stability level "public-preview" of the module`,
+		},
+		{
+			name: "invalid",
+			config: `import.string "lib" {
+				content   = "declare \"a\" {}"
+				stability = "beta"
+			}`,
+			expectErr: `invalid stability level "beta"`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			globals := getComponentGlobals(t)
+			globals.ControllerID = tc.controllerID
+			if tc.minStability != featuregate.StabilityUndefined {
+				globals.MinStability = tc.minStability
+			}
+
+			cn := NewImportConfigNode(getBlockFromConfig(t, tc.config), globals, importsource.String)
+			err := cn.Evaluate(vm.NewScope(nil))
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, cn.MinStability())
+
+			// The node isn't running, so only the health of the content update is checked.
+			health := cn.contentHealth
+			if tc.expectContent != "" {
+				require.Equal(t, component.HealthTypeUnhealthy, health.Health)
+				require.Contains(t, health.Message, tc.expectContent)
+				require.Empty(t, cn.ImportedDeclares())
+				return
+			}
+			require.Equal(t, component.HealthTypeHealthy, health.Health)
+			require.Contains(t, cn.ImportedDeclares(), "a")
+		})
+	}
+}
+
+func TestImportConfigNode_Expectations(t *testing.T) {
+	config := func(export string, typ string, expected string) string {
+		return `import.string "lib" {
+			content = "declare \"a\" {\nexport \"` + export + `\" {\nvalue = 1\ntype = \"` + typ + `\"\n}\n}"
+
+			expect "a" {
+				exports = {
+					output = "` + expected + `",
+				}
+			}
+		}`
+	}
+	exports := func(cn *ImportConfigNode) map[string]string {
+		declared, err := declaredExportTypes(cn.ImportedDeclares()["a"])
+		require.NoError(t, err)
+		return declared
+	}
+
+	cn := NewImportConfigNode(getBlockFromConfig(t, config("output", "number", "number")), getComponentGlobals(t), importsource.String)
+	require.NoError(t, cn.Evaluate(vm.NewScope(nil)))
+	require.Equal(t, component.HealthTypeHealthy, cn.contentHealth.Health)
+	require.Equal(t, map[string]string{"output": "number"}, exports(cn))
+
+	// An update which renames the export is rejected and the previous
+	// definition is kept.
+	cn.UpdateBlock(getBlockFromConfig(t, config("result", "number", "number")))
+	expectErr := `the imported module doesn't match the expect blocks of import.string.lib: declare "a" doesn't export "output"`
+	require.EqualError(t, cn.Evaluate(vm.NewScope(nil)), expectErr)
+	require.Equal(t, component.HealthTypeUnhealthy, cn.contentHealth.Health)
+	require.Equal(t, expectErr, cn.contentHealth.Message)
+	require.Equal(t, map[string]string{"output": "number"}, exports(cn))
+
+	// An update which changes the type of the export is rejected.
+	cn.UpdateBlock(getBlockFromConfig(t, config("output", "string", "number")))
+	require.EqualError(t, cn.Evaluate(vm.NewScope(nil)), `the imported module doesn't match the expect blocks of import.string.lib: export "output" of declare "a" is of type string, expected number`)
+	require.Equal(t, map[string]string{"output": "number"}, exports(cn))
+
+	// Updating the expectations applies the content.
+	cn.UpdateBlock(getBlockFromConfig(t, config("output", "string", "any")))
+	require.NoError(t, cn.Evaluate(vm.NewScope(nil)))
+	require.Equal(t, component.HealthTypeHealthy, cn.contentHealth.Health)
+	require.Equal(t, map[string]string{"output": "string"}, exports(cn))
+}
@@ -17,6 +17,7 @@ type LoggingConfigNode struct {
 	nodeID        string
 	componentName string
 	l             log.Logger
+	dryRun        bool
 
 	mut   sync.RWMutex
 	block *ast.BlockStmt // Current Alloy blocks to derive config from
@@ -30,6 +31,7 @@ func NewLoggingConfigNode(block *ast.BlockStmt, globals ComponentGlobals) *Loggi
 		nodeID:        BlockComponentID(block).String(),
 		componentName: block.GetBlockName(),
 		l:             globals.Logger,
+		dryRun:        globals.DryRun,
 
 		block: block,
 		eval:  vm.New(block.Body),
@@ -43,6 +45,7 @@ func NewDefaultLoggingConfigNode(globals ComponentGlobals) *LoggingConfigNode {
 		nodeID:        loggingBlockID,
 		componentName: loggingBlockID,
 		l:             globals.Logger,
+		dryRun:        globals.DryRun,
 
 		block: nil,
 		eval:  nil,
@@ -65,6 +68,11 @@ func (cn *LoggingConfigNode) Evaluate(scope *vm.Scope) error {
 		}
 	}
 
+	if cn.dryRun {
+		// Don't reconfigure the logger, which may open log files.
+		return nil
+	}
+
 	if err := cn.l.(*logging.Logger).Update(args); err != nil {
 		return fmt.Errorf("could not update logger: %w", err)
 	}
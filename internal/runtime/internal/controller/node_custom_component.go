@@ -3,7 +3,9 @@ package controller
 import (
 	"context"
 	"fmt"
+	"maps"
 	"path"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -11,6 +13,7 @@ import (
 	"github.com/go-kit/log"
 
 	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/featuregate"
 	"github.com/grafana/alloy/internal/runtime/equality"
 	"github.com/grafana/alloy/syntax/ast"
 	"github.com/grafana/alloy/syntax/vm"
@@ -28,8 +31,7 @@ type CustomComponentNode struct {
 	globalID          string
 	label             string
 	componentName     string
-	nodeID            string // Cached from id.String() to avoid allocating new strings every time NodeID is called.
-	moduleController  ModuleController
+	nodeID            string             // Cached from id.String() to avoid allocating new strings every time NodeID is called.
 	OnBlockNodeUpdate func(cn BlockNode) // Informs controller that we need to reevaluate
 	logger            log.Logger
 
@@ -38,11 +40,17 @@ type CustomComponentNode struct {
 
 	getConfig getCustomComponentConfig // Retrieve the custom component config.
 
-	mut     sync.RWMutex
-	block   *ast.BlockStmt // Current Alloy block to derive args from
-	eval    *vm.Evaluator
-	managed CustomComponent     // Inner managed custom component
-	args    component.Arguments // Evaluated arguments for the managed component
+	newModuleController func(opts ModuleControllerOpts) ModuleController
+	defaultStability    featuregate.Stability // Stability level used when the definition doesn't set one
+	managedChanged      chan struct{}         // Notifies Run that the managed custom component was replaced
+
+	mut              sync.RWMutex
+	block            *ast.BlockStmt // Current Alloy block to derive args from
+	eval             *vm.Evaluator
+	moduleController ModuleController
+	managed          CustomComponent       // Inner managed custom component
+	minStability     featuregate.Stability // Minimum stability level of the managed custom component
+	args             component.Arguments   // Evaluated arguments for the managed component
 
 	// NOTE(rfratto): health and exports have their own mutex because they may be
 	// set asynchronously while mut is still being held (i.e., when calling Evaluate
@@ -51,12 +59,24 @@ type CustomComponentNode struct {
 	healthMut  sync.RWMutex
 	evalHealth component.Health // Health of the last evaluate
 	runHealth  component.Health // Health of running the component
+	history    healthHistory    // Recent health transitions
 
 	exportsMut sync.RWMutex
 	exports    component.Exports // Evaluated exports for the managed custom component
 
 	dataFlowEdgeMut  sync.RWMutex
 	dataFlowEdgeRefs []string
+
+	exportRefsMut sync.RWMutex
+	exportRefs    []exportReference // References to the exports of the custom component
+}
+
+// exportReference is a reference from another node to an export of a custom
+// component.
+type exportReference struct {
+	export string // Name of the export
+	nodeID string // Node making the reference
+	pos    string // Position of the reference
 }
 
 var _ ComponentNode = (*CustomComponentNode)(nil)
@@ -117,13 +137,18 @@ func NewCustomComponentNode(globals ComponentGlobals, b *ast.BlockStmt, getConfi
 		componentName:       componentName,
 		importNamespace:     importNamespace,
 		customComponentName: customComponentName,
-		moduleController:    globals.NewModuleController(ModuleControllerOpts{Id: globalID}),
 		OnBlockNodeUpdate:   globals.OnBlockNodeUpdate,
 		logger:              log.With(globals.Logger, "component_path", parent, "component_id", node),
 		getConfig:           getConfig,
 
-		block: b,
-		eval:  vm.New(b.Body),
+		newModuleController: globals.NewModuleController,
+		defaultStability:    globals.MinStability,
+		managedChanged:      make(chan struct{}, 1),
+
+		block:            b,
+		eval:             vm.New(b.Body),
+		moduleController: globals.NewModuleController(ModuleControllerOpts{Id: globalID}),
+		minStability:     globals.MinStability,
 
 		evalHealth: initHealth,
 		runHealth:  initHealth,
@@ -206,6 +231,16 @@ func (cn *CustomComponentNode) evaluate(evalScope *vm.Scope) error {
 		return fmt.Errorf("loading custom component controller: %w", err)
 	}
 
+	// Check the exports before loading the definition, so a definition which
+	// doesn't provide the exports used by other nodes isn't applied.
+	if err := cn.checkExportReferences(template); err != nil {
+		return err
+	}
+
+	if err := cn.updateStability(customComponentRegistry.MinStability()); err != nil {
+		return err
+	}
+
 	// Reload the custom component with new config
 	if err := cn.managed.LoadBody(template, args, customComponentRegistry); err != nil {
 		return fmt.Errorf("updating custom component: %w", err)
@@ -213,6 +248,33 @@ func (cn *CustomComponentNode) evaluate(evalScope *vm.Scope) error {
 	return nil
 }
 
+// updateStability replaces the managed custom component when the minimum
+// stability level of its definition changes. It must be called with the mut
+// held.
+func (cn *CustomComponentNode) updateStability(minStability featuregate.Stability) error {
+	if minStability == featuregate.StabilityUndefined {
+		minStability = cn.defaultStability
+	}
+	if minStability == cn.minStability {
+		return nil
+	}
+
+	moduleController := cn.newModuleController(ModuleControllerOpts{Id: cn.globalID, MinStability: minStability})
+	mod, err := moduleController.NewCustomComponent("", func(exports map[string]any) { cn.setExports(exports) })
+	if err != nil {
+		return fmt.Errorf("creating custom component controller: %w", err)
+	}
+	cn.moduleController = moduleController
+	cn.managed = mod
+	cn.minStability = minStability
+
+	select {
+	case cn.managedChanged <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
 func (cn *CustomComponentNode) Run(ctx context.Context) error {
 	cn.mut.RLock()
 	managed := cn.managed
@@ -223,7 +285,7 @@ func (cn *CustomComponentNode) Run(ctx context.Context) error {
 	}
 
 	cn.setRunHealth(component.HealthTypeHealthy, "started custom component")
-	err := managed.Run(ctx)
+	err := cn.run(ctx, managed)
 
 	// Note: logging of this error is handled by the scheduler.
 	if err != nil {
@@ -234,6 +296,35 @@ func (cn *CustomComponentNode) Run(ctx context.Context) error {
 	return err
 }
 
+// run runs the managed custom component until ctx is canceled. The managed
+// custom component is restarted whenever it is replaced.
+func (cn *CustomComponentNode) run(ctx context.Context, managed CustomComponent) error {
+	for {
+		runCtx, cancel := context.WithCancel(ctx)
+		errCh := make(chan error, 1)
+		go func() { errCh <- managed.Run(runCtx) }()
+
+		next := managed
+		for next == managed {
+			select {
+			case err := <-errCh:
+				cancel()
+				return err
+			case <-cn.managedChanged:
+				cn.mut.RLock()
+				next = cn.managed
+				cn.mut.RUnlock()
+			}
+		}
+
+		// The previous custom component must be stopped before starting the new
+		// one because they share the same ID.
+		cancel()
+		<-errCh
+		managed = next
+	}
+}
+
 // Arguments returns the current arguments of the managed custom component.
 func (cn *CustomComponentNode) Arguments() component.Arguments {
 	cn.mut.RLock()
@@ -293,6 +384,12 @@ func (cn *CustomComponentNode) CurrentHealth() component.Health {
 	return component.LeastHealthy(cn.runHealth, cn.evalHealth)
 }
 
+// HealthHistory returns the recent transitions of the health of the
+// CustomComponentNode, ordered from newest to oldest.
+func (cn *CustomComponentNode) HealthHistory() []component.Health {
+	return cn.history.list()
+}
+
 // setEvalHealth sets the internal health from a call to Evaluate. See Health
 // for information on how overall health is calculated.
 func (cn *CustomComponentNode) setEvalHealth(t component.HealthType, msg string) {
@@ -304,6 +401,7 @@ func (cn *CustomComponentNode) setEvalHealth(t component.HealthType, msg string)
 		Message:    msg,
 		UpdateTime: time.Now(),
 	}
+	cn.history.record(component.LeastHealthy(cn.runHealth, cn.evalHealth))
 }
 
 // setRunHealth sets the internal health from a call to Run. See Health for
@@ -317,6 +415,7 @@ func (cn *CustomComponentNode) setRunHealth(t component.HealthType, msg string)
 		Message:    msg,
 		UpdateTime: time.Now(),
 	}
+	cn.history.record(component.LeastHealthy(cn.runHealth, cn.evalHealth))
 }
 
 // ComponentName returns the name of the component.
@@ -327,6 +426,8 @@ func (cn *CustomComponentNode) ComponentName() string {
 // TODO: currently used by the component provider to access the components running within
 // the custom components. Change it when getting rid of old modules.
 func (cn *CustomComponentNode) ModuleIDs() []string {
+	cn.mut.RLock()
+	defer cn.mut.RUnlock()
 	return cn.moduleController.ModuleIDs()
 }
 
@@ -347,3 +448,45 @@ func (cn *CustomComponentNode) ResetDataFlowEdgeTo() {
 	defer cn.dataFlowEdgeMut.Unlock()
 	cn.dataFlowEdgeRefs = []string{}
 }
+
+// ResetExportReferences resets the references to the exports of the custom
+// component.
+func (cn *CustomComponentNode) ResetExportReferences() {
+	cn.exportRefsMut.Lock()
+	defer cn.exportRefsMut.Unlock()
+	cn.exportRefs = nil
+}
+
+// AddExportReference records a reference from another node to an export of
+// the custom component.
+func (cn *CustomComponentNode) AddExportReference(export string, nodeID string, pos string) {
+	cn.exportRefsMut.Lock()
+	defer cn.exportRefsMut.Unlock()
+	cn.exportRefs = append(cn.exportRefs, exportReference{export: export, nodeID: nodeID, pos: pos})
+}
+
+// checkExportReferences returns an error if the exports referenced by other
+// nodes aren't declared by the export blocks of the template.
+func (cn *CustomComponentNode) checkExportReferences(template ast.Body) error {
+	declared := make(map[string]struct{})
+	for _, stmt := range template {
+		if block, ok := stmt.(*ast.BlockStmt); ok && block.GetBlockName() == exportBlockID {
+			declared[block.Label] = struct{}{}
+		}
+	}
+
+	cn.exportRefsMut.RLock()
+	defer cn.exportRefsMut.RUnlock()
+
+	var errs []string
+	for _, ref := range cn.exportRefs {
+		if _, ok := declared[ref.export]; !ok {
+			errs = append(errs, fmt.Sprintf("%q (referenced by %s at %s)", ref.export, ref.nodeID, ref.pos))
+		}
+	}
+	if len(errs) > 0 {
+		exports := slices.Sorted(maps.Keys(declared))
+		return fmt.Errorf("the definition of %s doesn't export %s, the available exports are [%s]", cn.componentName, strings.Join(errs, ", "), strings.Join(exports, ", "))
+	}
+	return nil
+}
@@ -23,6 +23,8 @@ type ServiceNode struct {
 	block *ast.BlockStmt // Current Alloy block to derive args from
 	eval  *vm.Evaluator
 	args  component.Arguments // Evaluated arguments for the managed component
+
+	dryRun bool // Whether to only decode arguments, without updating the service
 }
 
 var _ RunnableNode = (*ServiceNode)(nil)
@@ -105,6 +107,11 @@ func (sn *ServiceNode) Evaluate(scope *vm.Scope) error {
 	// since services expect a non-pointer.
 	argsCopyValue := reflect.ValueOf(argsPointer).Elem().Interface()
 
+	if sn.dryRun {
+		sn.args = argsCopyValue
+		return nil
+	}
+
 	if equality.DeepEqual(sn.args, argsCopyValue) {
 		// Ignore arguments which haven't changed. This reduces the cost of calling
 		// evaluate for services where evaluation is expensive (e.g., if
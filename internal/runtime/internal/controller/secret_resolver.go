@@ -0,0 +1,64 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/grafana/alloy/internal/runtime/internal/dag"
+	"github.com/grafana/alloy/internal/service"
+	"github.com/grafana/alloy/syntax/alloytypes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SecretResolver is implemented by the service which resolves the secrets of
+// the sys.secret function.
+type SecretResolver interface {
+	// ResolveSecret returns the secret identified by path.
+	ResolveSecret(path string) (alloytypes.Secret, error)
+
+	// OnSecretsChange calls f whenever the value of a resolved secret
+	// changes, until the returned function is called.
+	OnSecretsChange(f func()) (cancel func())
+}
+
+// findSecretResolver returns the first service which is a SecretResolver, or
+// nil if there's none. Data isn't called, as the services may not be running
+// yet, or may be creating the Loader.
+func findSecretResolver(services []service.Service) (service.Service, SecretResolver) {
+	for _, svc := range services {
+		if r, ok := svc.(SecretResolver); ok {
+			return svc, r
+		}
+	}
+	return nil, nil
+}
+
+// SecretResolver returns the resolver of the secrets of sys.secret, or nil if
+// none of the services of the Loader resolves secrets.
+func (l *Loader) SecretResolver() SecretResolver {
+	return l.secretResolver
+}
+
+// EvaluateSecretUsers evaluates the nodes calling sys.secret, after the
+// values of the secrets changed. The dependants of the nodes are evaluated
+// when their exports change, like for any other evaluation.
+func (l *Loader) EvaluateSecretUsers(ctx context.Context) {
+	tracer := l.tracer.Tracer("")
+	spanCtx, span := tracer.Start(context.Background(), "SubmitSecretUsersForEvaluation", trace.WithSpanKind(trace.SpanKindInternal))
+	defer span.End()
+
+	l.cm.controllerEvaluation.Set(1)
+	defer l.cm.controllerEvaluation.Set(0)
+
+	l.mut.RLock()
+	defer l.mut.RUnlock()
+
+	now := time.Now()
+	nodes := make(map[dag.Node]*QueuedNode)
+	for _, n := range l.graph.Nodes() {
+		if bn, ok := n.(BlockNode); ok && usesStdlibSecret(n) {
+			nodes[n] = &QueuedNode{Node: bn, LastUpdatedTime: now}
+		}
+	}
+	l.submitForEvaluation(ctx, spanCtx, tracer, nodes)
+}
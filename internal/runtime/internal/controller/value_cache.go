@@ -6,6 +6,7 @@ import (
 
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/runtime/equality"
+	"github.com/grafana/alloy/syntax/alloytypes"
 	"github.com/grafana/alloy/syntax/vm"
 )
 
@@ -22,6 +23,8 @@ type valueCache struct {
 	moduleArguments    map[string]any         // Argument label -> Map with the key "value" that points to the Argument value
 	moduleChangedIndex int                    // Everytime a change occurs this is incremented
 	scope              *vm.Scope              // scope provides additional context for the nodes in the module
+	functions          map[string]any         // Functions of the declare blocks available in the module
+	secretVariables    map[string]any         // Variables which resolve the secrets of sys.secret
 }
 
 // newValueCache creates a new ValueCache.
@@ -44,6 +47,42 @@ func (vc *valueCache) UpdateScopeVariables(variables map[string]any) {
 	vc.scope.Variables = deepCopyMap(variables)
 }
 
+// SetSecretResolver makes sys.secret resolve secrets with r in the scopes
+// returned by GetContext.
+func (vc *valueCache) SetSecretResolver(r func(path string) (alloytypes.Secret, error)) {
+	vc.mut.Lock()
+	defer vc.mut.Unlock()
+	vc.secretVariables = vm.SecretResolverVariables(r)
+}
+
+// UpdateFunctions replaces the functions of the declare blocks exposed to the
+// nodes in the module.
+func (vc *valueCache) UpdateFunctions(functions map[string]any) {
+	vc.mut.Lock()
+	defer vc.mut.Unlock()
+	vc.functions = functions
+}
+
+// HasFunction returns whether a function of a declare block is exposed at
+// the given path.
+func (vc *valueCache) HasFunction(path []string) bool {
+	vc.mut.RLock()
+	defer vc.mut.RUnlock()
+
+	var cur any = vc.functions
+	for _, name := range path {
+		m, ok := cur.(map[string]any)
+		if !ok {
+			return false
+		}
+		if cur, ok = m[name]; !ok {
+			return false
+		}
+	}
+	_, isMap := cur.(map[string]any)
+	return !isMap
+}
+
 // CacheExports will cache the provided exports using the given id. exports may
 // be nil to store an empty object.
 func (vc *valueCache) CacheExports(id ComponentID, exports component.Exports) error {
@@ -215,6 +254,17 @@ func (vc *valueCache) GetContext() *vm.Scope {
 		vars[argumentLabel] = deepCopyMap(vc.moduleArguments)
 	}
 
+	// Functions don't override the exports of custom components with the same
+	// name.
+	mergeFunctions(vars, vc.functions)
+
+	// Nested modules may already get the secret variables from their parent.
+	for name, v := range vc.secretVariables {
+		if _, ok := vars[name]; !ok {
+			vars[name] = v
+		}
+	}
+
 	return vm.NewScope(vars)
 }
 
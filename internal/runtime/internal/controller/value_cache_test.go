@@ -250,6 +250,27 @@ func TestScopePathOverrideError(t *testing.T) {
 	)
 }
 
+func TestScopeFunctions(t *testing.T) {
+	vc := newValueCache()
+	double := func(x int) int { return x * 2 }
+	vc.UpdateFunctions(map[string]any{
+		"math": map[string]any{"double": double},
+		"lib":  map[string]any{},
+	})
+	require.NoError(t, vc.CacheExports(ComponentID{"math", "default"}, barArgs{Number: 12}))
+	res := vc.GetContext()
+
+	math := res.Variables["math"].(map[string]any)
+	require.Equal(t, barArgs{Number: 12}, math["default"])
+	require.IsType(t, double, math["double"])
+	require.Equal(t, map[string]any{}, res.Variables["lib"])
+
+	require.True(t, vc.HasFunction([]string{"math", "double"}))
+	require.False(t, vc.HasFunction([]string{"math", "default"}))
+	require.False(t, vc.HasFunction([]string{"math"}))
+	require.False(t, vc.HasFunction([]string{"lib", "math", "double"}))
+}
+
 func TestScopeComplex(t *testing.T) {
 	vc := newValueCache()
 	vc.scope = vm.NewScope(
@@ -0,0 +1,134 @@
+package dag
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Types of the edges of an exported graph.
+const (
+	// EdgeTypeReference is an edge of the graph: the From node references
+	// the To node.
+	EdgeTypeReference = "reference"
+	// EdgeTypeDataFlow is an edge along which data is sent from the From node
+	// to the To node.
+	EdgeTypeDataFlow = "dataflow"
+)
+
+// Export is a serializable representation of a graph. Nodes and edges are
+// sorted so that exporting the same graph always gives the same output.
+type Export struct {
+	Nodes []ExportNode `json:"nodes"`
+	Edges []ExportEdge `json:"edges"`
+}
+
+// ExportNode is a node of an exported graph.
+type ExportNode struct {
+	ID         string            `json:"id"`
+	Attributes map[string]string `json:"attributes,omitempty"`
+}
+
+// ExportEdge is an edge of an exported graph.
+type ExportEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Type string `json:"type"`
+}
+
+// ExportOptions customizes the export of a graph.
+type ExportOptions struct {
+	// Attributes returns the attributes of a node, like its health. Optional.
+	Attributes func(n Node) map[string]string
+
+	// DataFlowEdges returns the IDs of the nodes which n sends data to.
+	// Edges to nodes which aren't in the graph are ignored. Optional.
+	DataFlowEdges func(n Node) []string
+}
+
+// NewExport returns the export of g.
+func NewExport(g *Graph, opts ExportOptions) *Export {
+	e := &Export{
+		Nodes: []ExportNode{},
+		Edges: []ExportEdge{},
+	}
+
+	for _, n := range g.Nodes() {
+		node := ExportNode{ID: n.NodeID()}
+		if opts.Attributes != nil {
+			node.Attributes = opts.Attributes(n)
+		}
+		e.Nodes = append(e.Nodes, node)
+
+		if opts.DataFlowEdges == nil {
+			continue
+		}
+		for _, to := range opts.DataFlowEdges(n) {
+			if g.GetByID(to) == nil {
+				continue
+			}
+			e.Edges = append(e.Edges, ExportEdge{From: n.NodeID(), To: to, Type: EdgeTypeDataFlow})
+		}
+	}
+	for _, edge := range g.Edges() {
+		e.Edges = append(e.Edges, ExportEdge{From: edge.From.NodeID(), To: edge.To.NodeID(), Type: EdgeTypeReference})
+	}
+
+	sort.Slice(e.Nodes, func(i, j int) bool {
+		return e.Nodes[i].ID < e.Nodes[j].ID
+	})
+	sort.Slice(e.Edges, func(i, j int) bool {
+		a, b := e.Edges[i], e.Edges[j]
+		switch {
+		case a.From != b.From:
+			return a.From < b.From
+		case a.To != b.To:
+			return a.To < b.To
+		default:
+			return a.Type < b.Type
+		}
+	})
+	return e
+}
+
+// WriteJSON writes e as JSON to w.
+func (e *Export) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(e)
+}
+
+// WriteDOT writes e to w in the Graphviz DOT language. Reference edges are
+// drawn as solid lines and data-flow edges as dashed lines.
+func (e *Export) WriteDOT(w io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("digraph {\n")
+	for _, n := range e.Nodes {
+		fmt.Fprintf(&sb, "\t%q", n.ID)
+		if len(n.Attributes) > 0 {
+			keys := make([]string, 0, len(n.Attributes))
+			for k := range n.Attributes {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+
+			attrs := make([]string, len(keys))
+			for i, k := range keys {
+				attrs[i] = fmt.Sprintf("%q=%q", k, n.Attributes[k])
+			}
+			fmt.Fprintf(&sb, " [%s]", strings.Join(attrs, ", "))
+		}
+		sb.WriteString(";\n")
+	}
+	for _, edge := range e.Edges {
+		fmt.Fprintf(&sb, "\t%q -> %q", edge.From, edge.To)
+		if edge.Type == EdgeTypeDataFlow {
+			sb.WriteString(` [style="dashed"]`)
+		}
+		sb.WriteString(";\n")
+	}
+	sb.WriteString("}\n")
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,69 @@
+package dag
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestExport(t *testing.T) {
+	var g Graph
+	var (
+		nodeA = stringNode("a")
+		nodeB = stringNode("b")
+		nodeC = stringNode("c")
+	)
+	g.Add(nodeA)
+	g.Add(nodeB)
+	g.Add(nodeC)
+	g.AddEdge(Edge{nodeC, nodeA})
+	g.AddEdge(Edge{nodeB, nodeA})
+
+	e := NewExport(&g, ExportOptions{
+		Attributes: func(n Node) map[string]string {
+			return map[string]string{"type": "component", "health": "healthy"}
+		},
+		DataFlowEdges: func(n Node) []string {
+			if n == nodeA {
+				return []string{"c", "b", "missing"}
+			}
+			return nil
+		},
+	})
+
+	var jsonBuf bytes.Buffer
+	require.NoError(t, e.WriteJSON(&jsonBuf))
+	require.JSONEq(t, `{
+		"nodes": [
+			{"id": "a", "attributes": {"health": "healthy", "type": "component"}},
+			{"id": "b", "attributes": {"health": "healthy", "type": "component"}},
+			{"id": "c", "attributes": {"health": "healthy", "type": "component"}}
+		],
+		"edges": [
+			{"from": "a", "to": "b", "type": "dataflow"},
+			{"from": "a", "to": "c", "type": "dataflow"},
+			{"from": "b", "to": "a", "type": "reference"},
+			{"from": "c", "to": "a", "type": "reference"}
+		]
+	}`, jsonBuf.String())
+
+	var dotBuf bytes.Buffer
+	require.NoError(t, e.WriteDOT(&dotBuf))
+	require.Equal(t, `digraph {
+	"a" ["health"="healthy", "type"="component"];
+	"b" ["health"="healthy", "type"="component"];
+	"c" ["health"="healthy", "type"="component"];
+	"a" -> "b" [style="dashed"];
+	"a" -> "c" [style="dashed"];
+	"b" -> "a";
+	"c" -> "a";
+}
+`, dotBuf.String())
+}
+
+func TestExport_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, NewExport(&Graph{}, ExportOptions{}).WriteJSON(&buf))
+	require.JSONEq(t, `{"nodes": [], "edges": []}`, buf.String())
+}
@@ -0,0 +1,120 @@
+package importsource
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/runtime/equality"
+	"github.com/grafana/alloy/syntax/vm"
+)
+
+// builtinModules holds the modules shipped with the binary. The module
+// "kubernetes/logs" is stored in builtin/kubernetes/logs.alloy.
+//
+//go:embed builtin
+var builtinModules embed.FS
+
+const (
+	builtinDir = "builtin"
+	builtinExt = ".alloy"
+)
+
+// ImportBuiltin imports one of the modules shipped with the binary. Builtin
+// modules are versioned with the binary and don't require network access.
+type ImportBuiltin struct {
+	arguments       BuiltinArguments
+	eval            *vm.Evaluator
+	onContentChange func(map[string]string)
+	modulePath      string
+}
+
+var _ ImportSource = (*ImportBuiltin)(nil)
+
+func NewImportBuiltin(eval *vm.Evaluator, onContentChange func(map[string]string)) *ImportBuiltin {
+	return &ImportBuiltin{
+		eval:            eval,
+		onContentChange: onContentChange,
+	}
+}
+
+// BuiltinArguments holds values which are used to configure import.builtin.
+type BuiltinArguments struct {
+	Module string `alloy:"module,attr"`
+}
+
+func (im *ImportBuiltin) Evaluate(scope *vm.Scope) error {
+	var arguments BuiltinArguments
+	if err := im.eval.Evaluate(scope, &arguments); err != nil {
+		return fmt.Errorf("decoding configuration: %w", err)
+	}
+
+	content, err := builtinModule(arguments.Module)
+	if err != nil {
+		return err
+	}
+
+	if equality.DeepEqual(im.arguments, arguments) {
+		return nil
+	}
+	im.arguments = arguments
+
+	im.modulePath, _ = scope.Variables[ModulePath].(string)
+
+	// notifies that the content has changed
+	im.onContentChange(map[string]string{arguments.Module + builtinExt: content})
+
+	return nil
+}
+
+// builtinModule returns the content of the builtin module with the given
+// name.
+func builtinModule(name string) (string, error) {
+	if fs.ValidPath(name) && name != "." {
+		if b, err := builtinModules.ReadFile(path.Join(builtinDir, name+builtinExt)); err == nil {
+			return string(b), nil
+		}
+	}
+	return "", fmt.Errorf("unknown builtin module %q, must be one of: %s", name, strings.Join(builtinModuleNames(), ", "))
+}
+
+// builtinModuleNames returns the sorted names of the builtin modules.
+func builtinModuleNames() []string {
+	var names []string
+	_ = fs.WalkDir(builtinModules, builtinDir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(p) != builtinExt {
+			return err
+		}
+		names = append(names, strings.TrimSuffix(strings.TrimPrefix(p, builtinDir+"/"), builtinExt))
+		return nil
+	})
+	sort.Strings(names)
+	return names
+}
+
+func (im *ImportBuiltin) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+// ImportBuiltin is always healthy, because unknown modules fail its
+// evaluation.
+func (im *ImportBuiltin) CurrentHealth() component.Health {
+	return component.Health{
+		Health: component.HealthTypeHealthy,
+	}
+}
+
+// Update the evaluator.
+func (im *ImportBuiltin) SetEval(eval *vm.Evaluator) {
+	im.eval = eval
+}
+
+func (im *ImportBuiltin) ModulePath() string {
+	return im.modulePath
+}
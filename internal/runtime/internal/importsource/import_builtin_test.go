@@ -0,0 +1,58 @@
+package importsource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/syntax/ast"
+	"github.com/grafana/alloy/syntax/parser"
+	"github.com/grafana/alloy/syntax/vm"
+)
+
+func TestImportBuiltin(t *testing.T) {
+	scope := vm.NewScope(map[string]any{ModulePath: "/etc/alloy"})
+
+	t.Run("known module", func(t *testing.T) {
+		f, err := parser.ParseFile(t.Name(), []byte(`module = "node/metrics"`))
+		require.NoError(t, err)
+
+		var content map[string]string
+		im := NewImportBuiltin(vm.New(f), func(c map[string]string) { content = c })
+		require.NoError(t, im.Evaluate(scope))
+		require.Len(t, content, 1)
+		require.Contains(t, content["node/metrics.alloy"], `declare "exporter"`)
+		require.Equal(t, "/etc/alloy", im.ModulePath())
+	})
+
+	for _, name := range []string{"node/missing", "node", "../node/metrics", ""} {
+		t.Run("unknown module "+name, func(t *testing.T) {
+			f, err := parser.ParseFile(t.Name(), []byte(`module = "`+name+`"`))
+			require.NoError(t, err)
+
+			im := NewImportBuiltin(vm.New(f), func(map[string]string) { require.Fail(t, "unexpected content") })
+			err = im.Evaluate(scope)
+			require.EqualError(t, err, `unknown builtin module "`+name+`", must be one of: kubernetes/logs, node/metrics, otlp/gateway`)
+		})
+	}
+}
+
+func TestBuiltinModules(t *testing.T) {
+	names := builtinModuleNames()
+	require.Equal(t, []string{"kubernetes/logs", "node/metrics", "otlp/gateway"}, names)
+
+	// Builtin modules must only declare custom components, so that importing
+	// them has no side effects.
+	for _, name := range names {
+		content, err := builtinModule(name)
+		require.NoError(t, err)
+
+		f, err := parser.ParseFile(name, []byte(content))
+		require.NoError(t, err, name)
+		for _, stmt := range f.Body {
+			block, ok := stmt.(*ast.BlockStmt)
+			require.True(t, ok, "%s: unexpected statement %T", name, stmt)
+			require.Equal(t, "declare", block.GetBlockName(), name)
+		}
+	}
+}
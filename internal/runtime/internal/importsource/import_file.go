@@ -11,6 +11,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/bmatcuk/doublestar"
 	"github.com/go-kit/log"
 
 	"github.com/grafana/alloy/internal/component"
@@ -21,7 +22,7 @@ import (
 	"github.com/grafana/alloy/syntax/vm"
 )
 
-// ImportFile imports a module from a file or a folder.
+// ImportFile imports a module from a file, a folder, or a glob pattern.
 type ImportFile struct {
 	managedOpts     component.Options
 	eval            *vm.Evaluator
@@ -58,7 +59,8 @@ func NewImportFile(managedOpts component.Options, eval *vm.Evaluator, onContentC
 }
 
 type FileArguments struct {
-	// Filename indicates the file to watch.
+	// Filename indicates the file, directory, or glob pattern of the files to
+	// watch.
 	Filename string `alloy:"filename,attr"`
 	// Type indicates how to detect changes to the file.
 	Type filedetector.Detector `alloy:"detector,attr,optional"`
@@ -111,20 +113,28 @@ func (im *ImportFile) Evaluate(scope *vm.Scope) error {
 		}
 	}
 
+	// Glob patterns are watched from their base directory, recursively, to pick
+	// up new files in new directories.
+	watched, glob := im.args.Filename, isGlob(im.args.Filename)
+	if glob {
+		watched = globBase(im.args.Filename)
+	}
+
 	var err error
 	switch im.args.Type {
 	case filedetector.DetectorPoll:
 		im.detector = filedetector.NewPoller(filedetector.PollerOptions{
-			Filename:      im.args.Filename,
+			Filename:      watched,
 			ReloadFile:    reloadFile,
 			PollFrequency: im.args.PollFrequency,
 		})
 	case filedetector.DetectorFSNotify:
 		im.detector, err = filedetector.NewFSNotify(filedetector.FSNotifyOptions{
 			Logger:        im.managedOpts.Logger,
-			Filename:      im.args.Filename,
+			Filename:      watched,
 			ReloadFile:    reloadFile,
 			PollFrequency: im.args.PollFrequency,
+			Recursive:     glob,
 		})
 	}
 
@@ -155,7 +165,7 @@ func (im *ImportFile) Run(ctx context.Context) error {
 }
 
 func (im *ImportFile) readFile() error {
-	files, dir, err := im.collectFiles()
+	files, err := im.collectFiles()
 	if err != nil {
 		im.setHealth(component.Health{
 			Health:     component.HealthTypeUnhealthy,
@@ -166,11 +176,7 @@ func (im *ImportFile) readFile() error {
 		return err
 	}
 	fileContents := make(map[string]string)
-	for _, f := range files {
-		fpath := f
-		if dir {
-			fpath = filepath.Join(im.args.Filename, fpath)
-		}
+	for f, fpath := range files {
 		bb, err := os.ReadFile(fpath)
 		if err != nil {
 			im.setHealth(component.Health{
@@ -205,24 +211,31 @@ func (im *ImportFile) setHealth(h component.Health) {
 	im.health = h
 }
 
-func (im *ImportFile) collectFiles() (content []string, dir bool, err error) {
+// collectFiles returns the paths of the files to import, keyed by the name
+// of the file in the module.
+func (im *ImportFile) collectFiles() (map[string]string, error) {
 	fpath := im.args.Filename
+	if isGlob(fpath) {
+		return collectFilesFromGlob(fpath)
+	}
+
 	fi, err := os.Stat(fpath)
 	if err != nil {
-		return nil, false, err
+		return nil, err
 	}
 
-	files := make([]string, 0)
-	dir = fi.IsDir()
-	if dir {
-		files, err = collectFilesFromDir(fpath)
-		if err != nil {
-			return nil, true, err
-		}
-	} else {
-		files = append(files, fpath)
+	if !fi.IsDir() {
+		return map[string]string{fpath: fpath}, nil
 	}
-	return files, dir, nil
+	names, err := collectFilesFromDir(fpath)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]string, len(names))
+	for _, name := range names {
+		files[name] = filepath.Join(fpath, name)
+	}
+	return files, nil
 }
 
 func collectFilesFromDir(path string) ([]string, error) {
@@ -252,12 +265,57 @@ func collectFilesFromDir(path string) ([]string, error) {
 	return files, nil
 }
 
+// isGlob returns whether path is a glob pattern.
+func isGlob(path string) bool {
+	return strings.ContainsAny(path, "*?[{")
+}
+
+// globBase returns the directory of the components of pattern before the
+// first component with a glob.
+func globBase(pattern string) string {
+	dir := filepath.Dir(pattern)
+	for isGlob(dir) {
+		dir = filepath.Dir(dir)
+	}
+	return dir
+}
+
+// collectFilesFromGlob returns the files matching pattern, which supports **
+// to match any number of directories, keyed by their path relative to the
+// base directory of pattern. The base directory must exist.
+func collectFilesFromGlob(pattern string) (map[string]string, error) {
+	base := globBase(pattern)
+	if _, err := os.Stat(base); err != nil {
+		return nil, err
+	}
+
+	matches, err := doublestar.Glob(pattern)
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]string, len(matches))
+	for _, m := range matches {
+		if fi, err := os.Stat(m); err != nil || fi.IsDir() {
+			continue
+		}
+		name, err := filepath.Rel(base, m)
+		if err != nil {
+			return nil, err
+		}
+		files[filepath.ToSlash(name)] = m
+	}
+	return files, nil
+}
+
 // Update the evaluator.
 func (im *ImportFile) SetEval(eval *vm.Evaluator) {
 	im.eval = eval
 }
 
 func (im *ImportFile) ModulePath() string {
+	if isGlob(im.args.Filename) {
+		return globBase(im.args.Filename)
+	}
 	path, err := util.ExtractDirPath(im.args.Filename)
 
 	if err != nil {
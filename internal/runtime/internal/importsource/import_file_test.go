@@ -0,0 +1,74 @@
+package importsource
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/syntax/parser"
+	"github.com/grafana/alloy/syntax/vm"
+)
+
+func TestImportFile_Glob(t *testing.T) {
+	dir := t.TempDir()
+	writeFile := func(name, content string) {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0700))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+	}
+	writeFile("modules/a.alloy", `declare "a" {}`)
+	writeFile("modules/net/b.alloy", `declare "b" {}`)
+	writeFile("modules/net/README.md", "not a module")
+
+	f, err := parser.ParseFile(t.Name(), []byte(`filename = "`+filepath.ToSlash(dir)+`/modules/**/*.alloy"`))
+	require.NoError(t, err)
+
+	var (
+		mut     sync.Mutex
+		content map[string]string
+	)
+	im := NewImportFile(component.Options{Logger: log.NewNopLogger()}, vm.New(f), func(c map[string]string) {
+		mut.Lock()
+		defer mut.Unlock()
+		content = c
+	})
+	require.NoError(t, im.Evaluate(vm.NewScope(nil)))
+	require.Equal(t, map[string]string{
+		"a.alloy":     `declare "a" {}`,
+		"net/b.alloy": `declare "b" {}`,
+	}, content)
+	require.Equal(t, filepath.Join(dir, "modules"), im.ModulePath())
+
+	ctx := t.Context()
+	go func() { _ = im.Run(ctx) }()
+
+	// Files in new directories are imported without evaluating again.
+	writeFile("modules/db/postgres/c.alloy", `declare "c" {}`)
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		mut.Lock()
+		defer mut.Unlock()
+		assert.Equal(c, `declare "c" {}`, content["db/postgres/c.alloy"])
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestGlobBase(t *testing.T) {
+	tt := []struct {
+		pattern string
+		expect  string
+	}{
+		{pattern: "modules/**/*.alloy", expect: "modules"},
+		{pattern: "modules/*.alloy", expect: "modules"},
+		{pattern: "/etc/alloy/modules/*/lib/*.alloy", expect: "/etc/alloy/modules"},
+		{pattern: "*.alloy", expect: "."},
+	}
+	for _, tc := range tt {
+		require.Equal(t, filepath.FromSlash(tc.expect), globBase(filepath.FromSlash(tc.pattern)), tc.pattern)
+	}
+}
@@ -0,0 +1,140 @@
+package importsource
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/runtime/equality"
+	"github.com/grafana/alloy/syntax/alloytypes"
+	"github.com/grafana/alloy/syntax/vm"
+)
+
+// ImportFrom imports a module from the string, secret, or map of strings or
+// secrets exported by any component, like remote.vault or
+// remote.kubernetes.configmap.
+type ImportFrom struct {
+	arguments       FromArguments
+	eval            *vm.Evaluator
+	onContentChange func(map[string]string)
+	modulePath      string
+}
+
+var _ ImportSource = (*ImportFrom)(nil)
+
+func NewImportFrom(eval *vm.Evaluator, onContentChange func(map[string]string)) *ImportFrom {
+	return &ImportFrom{
+		eval:            eval,
+		onContentChange: onContentChange,
+	}
+}
+
+// FromArguments holds values which are used to configure import.from.
+type FromArguments struct {
+	// Content is a string, a secret, or a map of strings or secrets.
+	Content any      `alloy:"content,attr"`
+	Keys    []string `alloy:"keys,attr,optional"`
+}
+
+// importFromFile is the name of the module when content is a single string.
+const importFromFile = "import_from"
+
+func (im *ImportFrom) Evaluate(scope *vm.Scope) error {
+	var arguments FromArguments
+	if err := im.eval.Evaluate(scope, &arguments); err != nil {
+		return fmt.Errorf("decoding configuration: %w", err)
+	}
+
+	if equality.DeepEqual(im.arguments, arguments) {
+		return nil
+	}
+
+	content, err := arguments.files()
+	if err != nil {
+		return err
+	}
+	im.arguments = arguments
+
+	im.modulePath, _ = scope.Variables[ModulePath].(string)
+
+	// notifies that the content has changed
+	im.onContentChange(content)
+
+	return nil
+}
+
+// files returns the files of the module, keyed by name.
+func (args *FromArguments) files() (map[string]string, error) {
+	switch content := args.Content.(type) {
+	case string, alloytypes.Secret, alloytypes.OptionalSecret:
+		if len(args.Keys) > 0 {
+			return nil, fmt.Errorf("keys can only be set if content is a map")
+		}
+		s, _ := fromValue(content)
+		return map[string]string{importFromFile: s}, nil
+
+	case map[string]any:
+		keys := args.Keys
+		if len(keys) == 0 {
+			keys = make([]string, 0, len(content))
+			for k := range content {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+		}
+
+		files := make(map[string]string, len(keys))
+		for _, k := range keys {
+			v, ok := content[k]
+			if !ok {
+				return nil, fmt.Errorf("content has no key %q", k)
+			}
+			s, ok := fromValue(v)
+			if !ok {
+				return nil, fmt.Errorf("content[%q] must be a string or a secret, got %T", k, v)
+			}
+			files[k] = s
+		}
+		return files, nil
+
+	default:
+		return nil, fmt.Errorf("content must be a string, a secret, or a map of strings or secrets, got %T", args.Content)
+	}
+}
+
+// fromValue returns the string of a string or secret value.
+func fromValue(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case alloytypes.Secret:
+		return string(v), true
+	case alloytypes.OptionalSecret:
+		return v.Value, true
+	default:
+		return "", false
+	}
+}
+
+func (im *ImportFrom) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+// ImportFrom is always healthy, because invalid content fails its
+// evaluation.
+func (im *ImportFrom) CurrentHealth() component.Health {
+	return component.Health{
+		Health: component.HealthTypeHealthy,
+	}
+}
+
+// Update the evaluator.
+func (im *ImportFrom) SetEval(eval *vm.Evaluator) {
+	im.eval = eval
+}
+
+func (im *ImportFrom) ModulePath() string {
+	return im.modulePath
+}
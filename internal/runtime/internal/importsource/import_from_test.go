@@ -0,0 +1,118 @@
+package importsource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/syntax/alloytypes"
+	"github.com/grafana/alloy/syntax/parser"
+	"github.com/grafana/alloy/syntax/vm"
+)
+
+func TestImportFrom(t *testing.T) {
+	scope := vm.NewScope(map[string]any{
+		ModulePath: "/etc/alloy",
+		"configmap": map[string]string{
+			"math.alloy":  `declare "add" {}`,
+			"other.alloy": `declare "other" {}`,
+			"settings":    "debug",
+		},
+		"vault": map[string]alloytypes.Secret{
+			"module": `declare "secret" {}`,
+		},
+		"secret_configmap": map[string]alloytypes.OptionalSecret{
+			"public.alloy": {Value: `declare "public" {}`},
+			"secret.alloy": {Value: `declare "secret" {}`, IsSecret: true},
+		},
+	})
+
+	tt := []struct {
+		name      string
+		config    string
+		expect    map[string]string
+		expectErr string
+	}{
+		{
+			name:   "string",
+			config: "content = `declare \"add\" {}`",
+			expect: map[string]string{"import_from": `declare "add" {}`},
+		},
+		{
+			name:   "secret",
+			config: "content = vault.module",
+			expect: map[string]string{"import_from": `declare "secret" {}`},
+		},
+		{
+			name:   "optional secret",
+			config: `content = secret_configmap["public.alloy"]`,
+			expect: map[string]string{"import_from": `declare "public" {}`},
+		},
+		{
+			name:   "map of secrets",
+			config: "content = vault",
+			expect: map[string]string{"module": `declare "secret" {}`},
+		},
+		{
+			name:   "map of optional secrets",
+			config: "content = secret_configmap",
+			expect: map[string]string{
+				"public.alloy": `declare "public" {}`,
+				"secret.alloy": `declare "secret" {}`,
+			},
+		},
+		{
+			name: "map with keys",
+			config: `
+				content = configmap
+				keys    = ["math.alloy", "other.alloy"]`,
+			expect: map[string]string{
+				"math.alloy":  `declare "add" {}`,
+				"other.alloy": `declare "other" {}`,
+			},
+		},
+		{
+			name: "missing key",
+			config: `
+				content = configmap
+				keys    = ["missing.alloy"]`,
+			expectErr: `content has no key "missing.alloy"`,
+		},
+		{
+			name: "keys of a string",
+			config: `
+				content = vault.module
+				keys    = ["module"]`,
+			expectErr: "keys can only be set if content is a map",
+		},
+		{
+			name:      "unsupported content",
+			config:    `content = ["declare \"add\" {}"]`,
+			expectErr: "content must be a string, a secret, or a map of strings or secrets, got []interface {}",
+		},
+		{
+			name:      "unsupported map value",
+			config:    `content = {"module" = 1}`,
+			expectErr: `content["module"] must be a string or a secret, got int`,
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			f, err := parser.ParseFile(t.Name(), []byte(tc.config))
+			require.NoError(t, err)
+
+			var content map[string]string
+			im := NewImportFrom(vm.New(f), func(c map[string]string) { content = c })
+
+			err = im.Evaluate(scope)
+			if tc.expectErr != "" {
+				require.EqualError(t, err, tc.expectErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expect, content)
+			require.Equal(t, "/etc/alloy", im.ModulePath())
+		})
+	}
+}
@@ -45,16 +45,21 @@ var (
 )
 
 type GitArguments struct {
-	Repository    string            `alloy:"repository,attr"`
-	Revision      string            `alloy:"revision,attr,optional"`
-	Path          string            `alloy:"path,attr"`
-	PullFrequency time.Duration     `alloy:"pull_frequency,attr,optional"`
-	GitAuthConfig vcs.GitAuthConfig `alloy:",squash"`
+	Repository     string            `alloy:"repository,attr"`
+	Revision       string            `alloy:"revision,attr,optional"`
+	Path           string            `alloy:"path,attr"`
+	PullFrequency  time.Duration     `alloy:"pull_frequency,attr,optional"`
+	Submodules     bool              `alloy:"submodules,attr,optional"`
+	SparseCheckout bool              `alloy:"sparse_checkout,attr,optional"`
+	GitAuthConfig  vcs.GitAuthConfig `alloy:",squash"`
+
+	VerifySignature *vcs.GitVerifyConfig `alloy:"verify_signature,block,optional"`
 }
 
 var DefaultGitArguments = GitArguments{
 	Revision:      "main",
 	PullFrequency: time.Minute,
+	Submodules:    true,
 }
 
 var (
@@ -204,6 +209,11 @@ func (im *ImportGit) Update(args component.Arguments) (err error) {
 		Repository: newArgs.Repository,
 		Revision:   newArgs.Revision,
 		Auth:       newArgs.GitAuthConfig,
+		Submodules: newArgs.Submodules,
+		Verify:     newArgs.VerifySignature,
+	}
+	if newArgs.SparseCheckout {
+		repoOpts.SparsePaths = []string{newArgs.Path}
 	}
 
 	// Create or update the repo field.
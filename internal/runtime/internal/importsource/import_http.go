@@ -2,40 +2,81 @@ package importsource
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"os"
 	"path"
+	"path/filepath"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/go-kit/log"
+	"github.com/grafana/dskit/backoff"
+	prom_config "github.com/prometheus/common/config"
+
 	"github.com/grafana/alloy/internal/component"
 	common_config "github.com/grafana/alloy/internal/component/common/config"
-	remote_http "github.com/grafana/alloy/internal/component/remote/http"
 	"github.com/grafana/alloy/internal/runtime/equality"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/useragent"
+	"github.com/grafana/alloy/syntax"
 	"github.com/grafana/alloy/syntax/vm"
 )
 
-// ImportHTTP imports a module from a HTTP server via the remote.http component.
+var userAgent = useragent.Get()
+
+// ImportHTTP imports a module from a HTTP server.
+// It polls the server like the remote.http component, and additionally caches
+// the module on disk and retries failed requests.
 type ImportHTTP struct {
-	managedRemoteHTTP *remote_http.Component
-	arguments         HTTPArguments
-	managedOpts       component.Options
-	eval              *vm.Evaluator
+	opts            component.Options
+	log             log.Logger
+	eval            *vm.Evaluator
+	onContentChange func(map[string]string)
+
+	mut      sync.Mutex
+	args     HTTPArguments
+	cli      *http.Client
+	lastPoll time.Time
+	cache    *httpCache // Last module fetched from the server.
+	content  *string    // Content passed to onContentChange.
+
+	// updated is written to whenever args updates.
+	updated chan struct{}
+
+	healthMut sync.RWMutex
+	health    component.Health
 }
 
 var _ ImportSource = (*ImportHTTP)(nil)
 
 func NewImportHTTP(managedOpts component.Options, eval *vm.Evaluator, onContentChange func(map[string]string)) *ImportHTTP {
-	opts := managedOpts
-	opts.OnStateChange = func(e component.Exports) {
-		onContentChange(map[string]string{opts.ID: e.(remote_http.Exports).Content.Value})
-	}
 	return &ImportHTTP{
-		managedOpts: opts,
-		eval:        eval,
+		opts:            managedOpts,
+		log:             managedOpts.Logger,
+		eval:            eval,
+		onContentChange: onContentChange,
+		updated:         make(chan struct{}, 1),
+		health: component.Health{
+			Health:     component.HealthTypeUnknown,
+			Message:    "component started",
+			UpdateTime: time.Now(),
+		},
 	}
 }
 
-// HTTPArguments holds values which are used to configure the remote.http component.
+// Fallback policies of import.http.
+const (
+	FallbackCached = "cached"
+	FallbackFail   = "fail"
+	FallbackEmpty  = "empty"
+)
+
+// HTTPArguments holds values which are used to configure import.http.
 type HTTPArguments struct {
 	URL           string        `alloy:"url,attr"`
 	PollFrequency time.Duration `alloy:"poll_frequency,attr,optional"`
@@ -45,7 +86,17 @@ type HTTPArguments struct {
 	Headers map[string]string `alloy:"headers,attr,optional"`
 	Body    string            `alloy:"body,attr,optional"`
 
+	Fallback string `alloy:"fallback,attr,optional"`
+
 	Client common_config.HTTPClientConfig `alloy:"client,block,optional"`
+	Retry  HTTPRetryArguments             `alloy:"retry,block,optional"`
+}
+
+// HTTPRetryArguments configures the retries of failed requests.
+type HTTPRetryArguments struct {
+	MaxRetries int           `alloy:"max_retries,attr,optional"`
+	MinBackoff time.Duration `alloy:"min_backoff,attr,optional"`
+	MaxBackoff time.Duration `alloy:"max_backoff,attr,optional"`
 }
 
 // DefaultHTTPArguments holds default settings for HTTPArguments.
@@ -54,54 +105,379 @@ var DefaultHTTPArguments = HTTPArguments{
 	PollTimeout:   10 * time.Second,
 	Client:        common_config.DefaultHTTPClientConfig,
 	Method:        http.MethodGet,
+	Fallback:      FallbackFail,
+	Retry:         DefaultHTTPRetryArguments,
+}
+
+// DefaultHTTPRetryArguments holds default settings for HTTPRetryArguments.
+var DefaultHTTPRetryArguments = HTTPRetryArguments{
+	MaxRetries: 0,
+	MinBackoff: 1 * time.Second,
+	MaxBackoff: 10 * time.Second,
 }
 
+var (
+	_ syntax.Validator = (*HTTPArguments)(nil)
+	_ syntax.Defaulter = (*HTTPArguments)(nil)
+	_ syntax.Validator = (*HTTPRetryArguments)(nil)
+	_ syntax.Defaulter = (*HTTPRetryArguments)(nil)
+)
+
 // SetToDefault implements syntax.Defaulter.
 func (args *HTTPArguments) SetToDefault() {
 	*args = DefaultHTTPArguments
 }
 
+// Validate implements syntax.Validator.
+func (args *HTTPArguments) Validate() error {
+	if args.PollFrequency <= 0 {
+		return fmt.Errorf("poll_frequency must be greater than 0")
+	}
+	if args.PollTimeout <= 0 {
+		return fmt.Errorf("poll_timeout must be greater than 0")
+	}
+	if args.PollTimeout >= args.PollFrequency {
+		return fmt.Errorf("poll_timeout must be less than poll_frequency")
+	}
+
+	switch args.Fallback {
+	case FallbackCached, FallbackFail, FallbackEmpty:
+	default:
+		return fmt.Errorf("fallback must be one of %q, %q, or %q", FallbackCached, FallbackFail, FallbackEmpty)
+	}
+
+	if _, err := http.NewRequest(args.Method, args.URL, nil); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *HTTPRetryArguments) SetToDefault() {
+	*args = DefaultHTTPRetryArguments
+}
+
+// Validate implements syntax.Validator.
+func (args *HTTPRetryArguments) Validate() error {
+	if args.MaxRetries < 0 {
+		return fmt.Errorf("max_retries must not be negative")
+	}
+	if args.MinBackoff <= 0 {
+		return fmt.Errorf("min_backoff must be greater than 0")
+	}
+	if args.MaxBackoff < args.MinBackoff {
+		return fmt.Errorf("max_backoff must not be less than min_backoff")
+	}
+	return nil
+}
+
 func (im *ImportHTTP) Evaluate(scope *vm.Scope) error {
 	var arguments HTTPArguments
 	if err := im.eval.Evaluate(scope, &arguments); err != nil {
 		return fmt.Errorf("decoding configuration: %w", err)
 	}
-	remoteHttpArguments := remote_http.Arguments{
-		URL:           arguments.URL,
-		PollFrequency: arguments.PollFrequency,
-		PollTimeout:   arguments.PollTimeout,
-		Method:        arguments.Method,
-		Headers:       arguments.Headers,
-		Body:          arguments.Body,
-		Client:        arguments.Client,
-	}
-	if im.managedRemoteHTTP == nil {
-		var err error
-		im.managedRemoteHTTP, err = remote_http.New(im.managedOpts, remoteHttpArguments)
-		if err != nil {
-			return fmt.Errorf("creating http component: %w", err)
-		}
-		im.arguments = arguments
-	}
 
-	if equality.DeepEqual(im.arguments, arguments) {
+	im.mut.Lock()
+	unchanged := im.cli != nil && equality.DeepEqual(im.args, arguments)
+	im.mut.Unlock()
+	if unchanged {
 		return nil
 	}
 
-	// Update the existing managed component
-	if err := im.managedRemoteHTTP.Update(remoteHttpArguments); err != nil {
+	if err := im.Update(arguments); err != nil {
 		return fmt.Errorf("updating component: %w", err)
 	}
-	im.arguments = arguments
 	return nil
 }
 
+// Update updates the arguments and fetches the module. If the module can't
+// be fetched, the fallback policy decides whether the error is returned.
+func (im *ImportHTTP) Update(args HTTPArguments) error {
+	im.mut.Lock()
+	defer im.mut.Unlock()
+
+	// Override default UserAgent if another is provided in "headers" section
+	customUserAgent, exist := args.Headers["User-Agent"]
+	if !exist {
+		customUserAgent = userAgent
+	}
+
+	cli, err := prom_config.NewClientFromConfig(
+		*args.Client.Convert(),
+		im.opts.ID,
+		prom_config.WithUserAgent(customUserAgent),
+	)
+	if err != nil {
+		return err
+	}
+	im.cli = cli
+	im.args = args
+
+	// Send an updated event if one wasn't already read.
+	select {
+	case im.updated <- struct{}{}:
+	default:
+	}
+
+	err = im.poll(context.Background())
+	im.updateHealth(err)
+	if err != nil {
+		return im.fallback(err)
+	}
+	return nil
+}
+
+// fallback applies the fallback policy after the module failed to be fetched
+// with err. fallback returns err if the policy doesn't apply. im.mut must be
+// held when calling.
+func (im *ImportHTTP) fallback(err error) error {
+	switch im.args.Fallback {
+	case FallbackCached:
+		if im.cache == nil || im.cache.URL != im.args.URL {
+			return fmt.Errorf("%w (no cached module)", err)
+		}
+		level.Warn(im.log).Log("msg", "failed to fetch module, using cached module", "err", err)
+		im.setContent(im.cache.Content)
+		return nil
+	case FallbackEmpty:
+		level.Warn(im.log).Log("msg", "failed to fetch module, using empty module", "err", err)
+		im.setContent("")
+		return nil
+	default:
+		return err
+	}
+}
+
 func (im *ImportHTTP) Run(ctx context.Context) error {
-	return im.managedRemoteHTTP.Run(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(im.nextPoll()):
+			im.mut.Lock()
+			err := im.poll(ctx)
+			im.mut.Unlock()
+
+			// The module which is already loaded is kept if polling fails.
+			im.updateHealth(err)
+		case <-im.updated:
+			// no-op; force the next wait to be reread.
+		}
+	}
+}
+
+// nextPoll returns how long to wait to poll given the last time a
+// poll occurred. nextPoll returns 0 if a poll should occur immediately.
+func (im *ImportHTTP) nextPoll() time.Duration {
+	im.mut.Lock()
+	defer im.mut.Unlock()
+
+	nextPoll := im.lastPoll.Add(im.args.PollFrequency)
+	now := time.Now()
+
+	if now.After(nextPoll) {
+		// Poll immediately; next poll period was in the past.
+		return 0
+	}
+	return nextPoll.Sub(now)
+}
+
+// poll fetches the module, retrying failed requests, and updates the content
+// of the import if it changed. im.mut must be held when calling.
+func (im *ImportHTTP) poll(ctx context.Context) error {
+	im.lastPoll = time.Now()
+
+	if im.cache == nil || im.cache.URL != im.args.URL {
+		im.cache = im.readCache()
+	}
+
+	b := backoff.New(ctx, backoff.Config{
+		MinBackoff: im.args.Retry.MinBackoff,
+		MaxBackoff: im.args.Retry.MaxBackoff,
+		// Backoff counts the first attempt as a retry, and retries forever if
+		// MaxRetries is 0.
+		MaxRetries: im.args.Retry.MaxRetries + 1,
+	})
+
+	var err error
+	for b.Ongoing() {
+		var module *httpCache
+		module, err = im.fetch(ctx)
+		if err == nil {
+			im.writeCache(module)
+			im.setContent(module.Content)
+			return nil
+		}
+
+		var statusErr httpStatusError
+		if errors.As(err, &statusErr) && !statusErr.retryable() {
+			break
+		}
+		if b.NumRetries() < im.args.Retry.MaxRetries {
+			level.Warn(im.log).Log("msg", "failed to fetch module, retrying", "retry", b.NumRetries()+1, "err", err)
+		}
+		b.Wait()
+	}
+	if err == nil {
+		err = b.Err()
+	}
+	level.Error(im.log).Log("msg", "failed to fetch module", "err", err)
+	return err
+}
+
+// httpStatusError is returned when the server responds with an unexpected
+// status code.
+type httpStatusError struct {
+	status string
+	code   int
+}
+
+func (e httpStatusError) Error() string {
+	return fmt.Sprintf("unexpected status code %s", e.status)
+}
+
+// retryable returns whether the request may succeed if it's retried.
+func (e httpStatusError) retryable() bool {
+	return e.code == http.StatusTooManyRequests || e.code >= 500
+}
+
+// fetch performs a single request for the module. If the module is cached
+// with an ETag, fetch returns the cached module if the server responds that it
+// wasn't modified.
+func (im *ImportHTTP) fetch(ctx context.Context) (*httpCache, error) {
+	ctx, cancel := context.WithTimeout(ctx, im.args.PollTimeout)
+	defer cancel()
+
+	var body io.Reader
+	if im.args.Body != "" {
+		body = strings.NewReader(im.args.Body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, im.args.Method, im.args.URL, body)
+	if err != nil {
+		return nil, fmt.Errorf("building request: %w", err)
+	}
+	for name, value := range im.args.Headers {
+		req.Header.Set(name, value)
+	}
+	cached := im.cache != nil && im.cache.ETag != "" && im.args.Method == http.MethodGet
+	if cached {
+		req.Header.Set("If-None-Match", im.cache.ETag)
+	}
+
+	resp, err := im.cli.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("performing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bb, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && cached:
+		return im.cache, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, httpStatusError{status: resp.Status, code: resp.StatusCode}
+	}
+
+	return &httpCache{
+		URL:     im.args.URL,
+		ETag:    resp.Header.Get("ETag"),
+		Content: strings.TrimSpace(string(bb)),
+	}, nil
+}
+
+// setContent updates the content of the import if it changed. im.mut must be
+// held when calling.
+func (im *ImportHTTP) setContent(content string) {
+	if im.content != nil && *im.content == content {
+		return
+	}
+	im.content = &content
+	im.onContentChange(map[string]string{im.opts.ID: content})
+}
+
+// httpCache is a module fetched from the server, which is stored on disk to
+// be used across restarts.
+type httpCache struct {
+	URL     string `json:"url"`
+	ETag    string `json:"etag,omitempty"`
+	Content string `json:"content"`
+}
+
+func (im *ImportHTTP) cachePath() string {
+	return filepath.Join(im.opts.DataPath, "module.json")
+}
+
+// readCache returns the module cached on disk for the current URL, or nil if
+// there's none.
+func (im *ImportHTTP) readCache() *httpCache {
+	bb, err := os.ReadFile(im.cachePath())
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			level.Warn(im.log).Log("msg", "failed to read cached module", "err", err)
+		}
+		return nil
+	}
+
+	var cache httpCache
+	if err := json.Unmarshal(bb, &cache); err != nil {
+		level.Warn(im.log).Log("msg", "failed to read cached module", "err", err)
+		return nil
+	}
+	if cache.URL != im.args.URL {
+		return nil
+	}
+	return &cache
+}
+
+// writeCache stores module as the cached module. Failing to write it to disk
+// only prevents it from being used after a restart, so errors are logged.
+func (im *ImportHTTP) writeCache(module *httpCache) {
+	if im.cache != nil && *im.cache == *module {
+		return
+	}
+	im.cache = module
+
+	bb, err := json.Marshal(module)
+	if err == nil {
+		err = os.MkdirAll(im.opts.DataPath, 0o750)
+	}
+	if err == nil {
+		err = os.WriteFile(im.cachePath(), bb, 0o600)
+	}
+	if err != nil {
+		level.Warn(im.log).Log("msg", "failed to write cached module", "err", err)
+	}
+}
+
+func (im *ImportHTTP) updateHealth(err error) {
+	im.healthMut.Lock()
+	defer im.healthMut.Unlock()
+
+	if err == nil {
+		im.health = component.Health{
+			Health:     component.HealthTypeHealthy,
+			Message:    "polled endpoint",
+			UpdateTime: time.Now(),
+		}
+	} else {
+		im.health = component.Health{
+			Health:     component.HealthTypeUnhealthy,
+			Message:    fmt.Sprintf("polling failed: %s", err),
+			UpdateTime: time.Now(),
+		}
+	}
 }
 
 func (im *ImportHTTP) CurrentHealth() component.Health {
-	return im.managedRemoteHTTP.CurrentHealth()
+	im.healthMut.RLock()
+	defer im.healthMut.RUnlock()
+	return im.health
 }
 
 // Update the evaluator.
@@ -110,6 +486,6 @@ func (im *ImportHTTP) SetEval(eval *vm.Evaluator) {
 }
 
 func (im *ImportHTTP) ModulePath() string {
-	dir, _ := path.Split(im.arguments.URL)
+	dir, _ := path.Split(im.args.URL)
 	return dir
 }
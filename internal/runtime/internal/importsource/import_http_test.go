@@ -0,0 +1,222 @@
+package importsource
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/syntax"
+)
+
+const testModule = `declare "test" {}`
+
+func newTestImportHTTP(t *testing.T, dataPath string) (*ImportHTTP, *[]string) {
+	t.Helper()
+
+	var contents []string
+	im := NewImportHTTP(component.Options{
+		ID:       "import.http.test",
+		Logger:   log.NewNopLogger(),
+		DataPath: dataPath,
+	}, nil, func(content map[string]string) {
+		contents = append(contents, content["import.http.test"])
+	})
+	return im, &contents
+}
+
+func testHTTPArguments(url string) HTTPArguments {
+	args := DefaultHTTPArguments
+	args.URL = url
+	args.Retry.MinBackoff = time.Millisecond
+	args.Retry.MaxBackoff = time.Millisecond
+	return args
+}
+
+func TestImportHTTP_ETag(t *testing.T) {
+	var requests, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		_, _ = w.Write([]byte(testModule))
+	}))
+	defer srv.Close()
+
+	dataPath := t.TempDir()
+	im, contents := newTestImportHTTP(t, dataPath)
+	require.NoError(t, im.Update(testHTTPArguments(srv.URL)))
+	require.Equal(t, []string{testModule}, *contents)
+
+	// Polling again uses the ETag of the module, and doesn't change the content.
+	im.mut.Lock()
+	require.NoError(t, im.poll(t.Context()))
+	im.mut.Unlock()
+	require.Equal(t, []string{testModule}, *contents)
+
+	// The ETag is used across restarts.
+	restarted, contents := newTestImportHTTP(t, dataPath)
+	require.NoError(t, restarted.Update(testHTTPArguments(srv.URL)))
+	require.Equal(t, []string{testModule}, *contents)
+
+	require.Equal(t, int32(3), requests.Load())
+	require.Equal(t, int32(2), notModified.Load())
+}
+
+func TestImportHTTP_Retry(t *testing.T) {
+	tt := []struct {
+		name       string
+		status     int
+		maxRetries int
+		requests   int32
+		expectErr  string
+	}{
+		{name: "succeeds after retries", status: http.StatusServiceUnavailable, maxRetries: 2, requests: 3},
+		{name: "retries exhausted", status: http.StatusServiceUnavailable, maxRetries: 1, requests: 2, expectErr: "unexpected status code 503"},
+		{name: "too many requests", status: http.StatusTooManyRequests, maxRetries: 2, requests: 3},
+		{name: "not retryable", status: http.StatusNotFound, maxRetries: 2, requests: 1, expectErr: "unexpected status code 404"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var requests atomic.Int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				// Fail the first two requests.
+				if requests.Add(1) <= 2 {
+					w.WriteHeader(tc.status)
+					return
+				}
+				_, _ = w.Write([]byte(testModule))
+			}))
+			defer srv.Close()
+
+			im, contents := newTestImportHTTP(t, t.TempDir())
+			args := testHTTPArguments(srv.URL)
+			args.Retry.MaxRetries = tc.maxRetries
+
+			err := im.Update(args)
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+				require.Empty(t, *contents)
+				require.Equal(t, component.HealthTypeUnhealthy, im.CurrentHealth().Health)
+			} else {
+				require.NoError(t, err)
+				require.Equal(t, []string{testModule}, *contents)
+				require.Equal(t, component.HealthTypeHealthy, im.CurrentHealth().Health)
+			}
+			require.Equal(t, tc.requests, requests.Load())
+		})
+	}
+}
+
+func TestImportHTTP_Fallback(t *testing.T) {
+	var down atomic.Bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(testModule))
+	}))
+	defer srv.Close()
+
+	// Cache the module.
+	dataPath := t.TempDir()
+	im, _ := newTestImportHTTP(t, dataPath)
+	require.NoError(t, im.Update(testHTTPArguments(srv.URL)))
+	down.Store(true)
+
+	tt := []struct {
+		fallback  string
+		dataPath  string
+		expect    []string
+		expectErr string
+	}{
+		{fallback: FallbackFail, dataPath: dataPath, expectErr: "unexpected status code 502 Bad Gateway"},
+		{fallback: FallbackCached, dataPath: dataPath, expect: []string{testModule}},
+		{fallback: FallbackCached, dataPath: t.TempDir(), expectErr: "unexpected status code 502 Bad Gateway (no cached module)"},
+		{fallback: FallbackEmpty, dataPath: dataPath, expect: []string{""}},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.fallback, func(t *testing.T) {
+			im, contents := newTestImportHTTP(t, tc.dataPath)
+			args := testHTTPArguments(srv.URL)
+			args.Fallback = tc.fallback
+
+			err := im.Update(args)
+			if tc.expectErr != "" {
+				require.EqualError(t, err, tc.expectErr)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.expect, *contents)
+			require.Equal(t, component.HealthTypeUnhealthy, im.CurrentHealth().Health)
+		})
+	}
+}
+
+func TestHTTPArguments_Validate(t *testing.T) {
+	tt := []struct {
+		name      string
+		config    string
+		expectErr string
+	}{
+		{
+			name: "valid",
+			config: `
+				url      = "http://localhost/module.alloy"
+				fallback = "cached"
+				retry {
+					max_retries = 3
+					min_backoff = "500ms"
+				}`,
+		},
+		{
+			name: "invalid fallback",
+			config: `
+				url      = "http://localhost/module.alloy"
+				fallback = "stale"`,
+			expectErr: `fallback must be one of "cached", "fail", or "empty"`,
+		},
+		{
+			name: "negative retries",
+			config: `
+				url = "http://localhost/module.alloy"
+				retry {
+					max_retries = -1
+				}`,
+			expectErr: "max_retries must not be negative",
+		},
+		{
+			name: "invalid backoff",
+			config: `
+				url = "http://localhost/module.alloy"
+				retry {
+					min_backoff = "1m"
+				}`,
+			expectErr: "max_backoff must not be less than min_backoff",
+		},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var args HTTPArguments
+			err := syntax.Unmarshal([]byte(tc.config), &args)
+			if tc.expectErr != "" {
+				require.ErrorContains(t, err, tc.expectErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,359 @@
+package importsource
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Masterminds/semver/v3"
+	"github.com/go-kit/log"
+	prom_config "github.com/prometheus/common/config"
+
+	"github.com/grafana/alloy/internal/component"
+	common_config "github.com/grafana/alloy/internal/component/common/config"
+	"github.com/grafana/alloy/internal/moduleregistry"
+	"github.com/grafana/alloy/internal/runtime/equality"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/syntax"
+	"github.com/grafana/alloy/syntax/vm"
+)
+
+// ImportRegistry imports a version of a module from a module registry.
+type ImportRegistry struct {
+	opts            component.Options
+	log             log.Logger
+	eval            *vm.Evaluator
+	onContentChange func(map[string]string)
+
+	mut        sync.Mutex
+	args       RegistryArguments
+	registry   moduleregistry.Registry
+	cache      *moduleregistry.Cache
+	constraint *semver.Constraints
+	lastPoll   time.Time
+	version    string // Imported version.
+	digest     string // Digest of the imported version.
+
+	// updated is written to whenever args updates.
+	updated chan struct{}
+
+	healthMut sync.RWMutex
+	health    component.Health
+}
+
+var _ ImportSource = (*ImportRegistry)(nil)
+
+func NewImportRegistry(managedOpts component.Options, eval *vm.Evaluator, onContentChange func(map[string]string)) *ImportRegistry {
+	return &ImportRegistry{
+		opts:            managedOpts,
+		log:             managedOpts.Logger,
+		eval:            eval,
+		onContentChange: onContentChange,
+		updated:         make(chan struct{}, 1),
+		health: component.Health{
+			Health:     component.HealthTypeUnknown,
+			Message:    "component started",
+			UpdateTime: time.Now(),
+		},
+	}
+}
+
+// RegistryArguments holds values which are used to configure import.registry.
+type RegistryArguments struct {
+	Registry      string        `alloy:"registry,attr"`
+	Module        string        `alloy:"module,attr"`
+	Version       string        `alloy:"version,attr,optional"`
+	Lockfile      string        `alloy:"lockfile,attr,optional"`
+	PollFrequency time.Duration `alloy:"poll_frequency,attr,optional"`
+	PollTimeout   time.Duration `alloy:"poll_timeout,attr,optional"`
+
+	Client common_config.HTTPClientConfig `alloy:"client,block,optional"`
+}
+
+// DefaultRegistryArguments holds default settings for RegistryArguments.
+var DefaultRegistryArguments = RegistryArguments{
+	Version:       "*",
+	PollFrequency: 10 * time.Minute,
+	PollTimeout:   30 * time.Second,
+	Client:        common_config.DefaultHTTPClientConfig,
+}
+
+var (
+	_ syntax.Validator = (*RegistryArguments)(nil)
+	_ syntax.Defaulter = (*RegistryArguments)(nil)
+)
+
+// SetToDefault implements syntax.Defaulter.
+func (args *RegistryArguments) SetToDefault() {
+	*args = DefaultRegistryArguments
+}
+
+// Validate implements syntax.Validator.
+func (args *RegistryArguments) Validate() error {
+	if _, err := moduleregistry.ParseURL(args.Registry); err != nil {
+		return err
+	}
+	if args.Module == "" {
+		return fmt.Errorf("module must not be empty")
+	}
+	if _, err := semver.NewConstraint(args.Version); err != nil {
+		return fmt.Errorf("invalid version constraint %q: %w", args.Version, err)
+	}
+	if args.PollFrequency <= 0 {
+		return fmt.Errorf("poll_frequency must be greater than 0")
+	}
+	if args.PollTimeout <= 0 {
+		return fmt.Errorf("poll_timeout must be greater than 0")
+	}
+	if args.PollTimeout >= args.PollFrequency {
+		return fmt.Errorf("poll_timeout must be less than poll_frequency")
+	}
+	return nil
+}
+
+func (im *ImportRegistry) Evaluate(scope *vm.Scope) error {
+	var arguments RegistryArguments
+	if err := im.eval.Evaluate(scope, &arguments); err != nil {
+		return fmt.Errorf("decoding configuration: %w", err)
+	}
+
+	im.mut.Lock()
+	unchanged := im.registry != nil && equality.DeepEqual(im.args, arguments)
+	im.mut.Unlock()
+	if unchanged {
+		return nil
+	}
+
+	if err := im.Update(arguments); err != nil {
+		return fmt.Errorf("updating component: %w", err)
+	}
+	return nil
+}
+
+// Update updates the arguments and imports the resolved version of the
+// module. Failing to reach the registry isn't an error if a cached version
+// satisfying the constraint can be imported instead.
+func (im *ImportRegistry) Update(args RegistryArguments) error {
+	im.mut.Lock()
+	defer im.mut.Unlock()
+
+	constraint, err := semver.NewConstraint(args.Version)
+	if err != nil {
+		return err
+	}
+	cli, err := prom_config.NewClientFromConfig(
+		*args.Client.Convert(),
+		im.opts.ID,
+		prom_config.WithUserAgent(userAgent),
+	)
+	if err != nil {
+		return err
+	}
+	registry, err := moduleregistry.New(args.Registry, cli)
+	if err != nil {
+		return err
+	}
+
+	im.args = args
+	im.cache = moduleregistry.NewCache(moduleregistry.CacheDir(im.opts.DataPath, moduleregistry.LockKey(args.Registry, args.Module)))
+	im.registry = registry
+	im.constraint = constraint
+
+	// Send an updated event if one wasn't already read.
+	select {
+	case im.updated <- struct{}{}:
+	default:
+	}
+
+	err = im.poll(context.Background())
+	im.updateHealth(err)
+	if errors.As(err, &cachedVersionError{}) {
+		return nil
+	}
+	return err
+}
+
+func (im *ImportRegistry) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(im.nextPoll()):
+			im.mut.Lock()
+			err := im.poll(ctx)
+			im.mut.Unlock()
+
+			// The version which is already imported is kept if polling fails.
+			im.updateHealth(err)
+		case <-im.updated:
+			// no-op; force the next wait to be reread.
+		}
+	}
+}
+
+// nextPoll returns how long to wait to poll given the last time a
+// poll occurred. nextPoll returns 0 if a poll should occur immediately.
+func (im *ImportRegistry) nextPoll() time.Duration {
+	im.mut.Lock()
+	defer im.mut.Unlock()
+
+	nextPoll := im.lastPoll.Add(im.args.PollFrequency)
+	now := time.Now()
+
+	if now.After(nextPoll) {
+		// Poll immediately; next poll period was in the past.
+		return 0
+	}
+	return nextPoll.Sub(now)
+}
+
+// cachedVersionError is returned when the registry couldn't be reached and a
+// cached version of the module was imported instead.
+type cachedVersionError struct {
+	Version string
+	Inner   error
+}
+
+func (err cachedVersionError) Error() string {
+	return fmt.Sprintf("imported cached version %s: %s", err.Version, err.Inner)
+}
+
+func (err cachedVersionError) Unwrap() error { return err.Inner }
+
+// poll resolves the version of the module to import, and imports it if it
+// changed. The version recorded in the lockfile is imported if the lockfile
+// records a version for the same constraint. Otherwise, the highest version
+// satisfying the constraint is imported and recorded in the lockfile.
+// im.mut must be held when calling.
+func (im *ImportRegistry) poll(ctx context.Context) error {
+	im.lastPoll = time.Now()
+
+	ctx, cancel := context.WithTimeout(ctx, im.args.PollTimeout)
+	defer cancel()
+
+	key := moduleregistry.LockKey(im.args.Registry, im.args.Module)
+	var locked *moduleregistry.LockedModule
+	if im.args.Lockfile != "" {
+		lf, err := moduleregistry.ReadLockfile(im.args.Lockfile)
+		if err != nil {
+			return err
+		}
+		if m, ok := lf.Modules[key]; ok && m.Constraint == im.args.Version {
+			locked = &m
+		}
+	}
+
+	var (
+		version string
+		// resolveErr is set if the registry couldn't be reached and the
+		// version was resolved from the cache.
+		resolveErr error
+	)
+	if locked != nil {
+		version = locked.Version
+	} else {
+		versions, err := im.registry.Versions(ctx, im.args.Module)
+		if err != nil {
+			resolveErr = err
+			if versions, err = im.cache.Versions(); err != nil {
+				return err
+			}
+		}
+		version, err = moduleregistry.Resolve(versions, im.constraint)
+		if err != nil {
+			if resolveErr != nil {
+				return fmt.Errorf("%w, and no cached version satisfies %q", resolveErr, im.args.Version)
+			}
+			return fmt.Errorf("module %q: %w", im.args.Module, err)
+		}
+	}
+
+	files, err := im.fetch(ctx, version)
+	if err != nil {
+		return err
+	}
+	digest := moduleregistry.Digest(files)
+
+	switch {
+	case locked != nil && locked.Digest != digest:
+		return fmt.Errorf("version %s of module %q has digest %s, but the lockfile records %s", version, im.args.Module, digest, locked.Digest)
+	case locked == nil && im.args.Lockfile != "" && resolveErr == nil:
+		err := moduleregistry.Lock(im.args.Lockfile, key, moduleregistry.LockedModule{
+			Constraint: im.args.Version,
+			Version:    version,
+			Digest:     digest,
+		})
+		if err != nil {
+			return fmt.Errorf("updating lockfile: %w", err)
+		}
+	}
+
+	if im.digest != digest {
+		level.Info(im.log).Log("msg", "importing module version", "module", im.args.Module, "version", version)
+		im.version, im.digest = version, digest
+		im.onContentChange(files)
+	}
+
+	if resolveErr != nil {
+		level.Warn(im.log).Log("msg", "failed to reach the registry, imported cached version", "version", version, "err", resolveErr)
+		return cachedVersionError{Version: version, Inner: resolveErr}
+	}
+	return nil
+}
+
+// fetch returns the files of version, from the cache if it's cached.
+func (im *ImportRegistry) fetch(ctx context.Context, version string) (map[string]string, error) {
+	files, ok, err := im.cache.Get(version)
+	if err != nil || ok {
+		return files, err
+	}
+
+	files, err = im.registry.Fetch(ctx, im.args.Module, version)
+	if err != nil {
+		return nil, err
+	}
+	if err := im.cache.Put(version, files); err != nil {
+		// The version is fetched again on the next poll.
+		level.Warn(im.log).Log("msg", "failed to cache module version", "version", version, "err", err)
+	}
+	return files, nil
+}
+
+func (im *ImportRegistry) updateHealth(err error) {
+	im.healthMut.Lock()
+	defer im.healthMut.Unlock()
+
+	if err != nil {
+		im.health = component.Health{
+			Health:     component.HealthTypeUnhealthy,
+			Message:    err.Error(),
+			UpdateTime: time.Now(),
+		}
+	} else {
+		im.health = component.Health{
+			Health:     component.HealthTypeHealthy,
+			Message:    "module updated",
+			UpdateTime: time.Now(),
+		}
+	}
+}
+
+func (im *ImportRegistry) CurrentHealth() component.Health {
+	im.healthMut.RLock()
+	defer im.healthMut.RUnlock()
+	return im.health
+}
+
+// Update the evaluator.
+func (im *ImportRegistry) SetEval(eval *vm.Evaluator) {
+	im.eval = eval
+}
+
+func (im *ImportRegistry) ModulePath() string {
+	if im.cache == nil {
+		return ""
+	}
+	return im.cache.Path(im.version)
+}
@@ -0,0 +1,190 @@
+package importsource
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/stretchr/testify/require"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/moduleregistry"
+)
+
+// testRegistry serves the versions of the module "grafana/test" as an HTTP
+// index.
+type testRegistry struct {
+	mut      sync.Mutex
+	versions []string
+	down     atomic.Bool
+}
+
+func (r *testRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r.down.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	path := strings.TrimPrefix(req.URL.Path, "/grafana/test/")
+	if path == "index.json" {
+		type version struct {
+			Version string   `json:"version"`
+			Files   []string `json:"files"`
+		}
+		var idx struct {
+			Versions []version `json:"versions"`
+		}
+		for _, v := range r.versions {
+			idx.Versions = append(idx.Versions, version{Version: v, Files: []string{"module.alloy"}})
+		}
+		_ = json.NewEncoder(w).Encode(idx)
+		return
+	}
+	version, _ := strings.CutSuffix(path, "/module.alloy")
+	_, _ = w.Write([]byte(testRegistryModule(version)))
+}
+
+func (r *testRegistry) addVersion(v string) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	r.versions = append(r.versions, v)
+}
+
+func testRegistryModule(version string) string {
+	return `declare "v` + strings.ReplaceAll(version, ".", "_") + `" {}`
+}
+
+func newTestImportRegistry(t *testing.T, dataPath string) (*ImportRegistry, *[]string) {
+	t.Helper()
+
+	var contents []string
+	im := NewImportRegistry(component.Options{
+		ID:       "import.registry.test",
+		Logger:   log.NewNopLogger(),
+		DataPath: dataPath,
+	}, nil, func(content map[string]string) {
+		contents = append(contents, content["module.alloy"])
+	})
+	return im, &contents
+}
+
+func testRegistryArguments(url, version, lockfile string) RegistryArguments {
+	args := DefaultRegistryArguments
+	args.Registry = url
+	args.Module = "grafana/test"
+	args.Version = version
+	args.Lockfile = lockfile
+	return args
+}
+
+func TestImportRegistry_Lockfile(t *testing.T) {
+	registry := &testRegistry{versions: []string{"1.0.0", "2.0.0", "2.0.1", "2.1.0"}}
+	srv := httptest.NewServer(registry)
+	defer srv.Close()
+
+	lockfile := filepath.Join(t.TempDir(), "alloy.lock")
+	im, contents := newTestImportRegistry(t, t.TempDir())
+	require.NoError(t, im.Update(testRegistryArguments(srv.URL, "~> 2.0", lockfile)))
+	require.Equal(t, []string{testRegistryModule("2.0.1")}, *contents)
+	require.Equal(t, im.cache.Path("2.0.1"), im.ModulePath())
+
+	lf, err := moduleregistry.ReadLockfile(lockfile)
+	require.NoError(t, err)
+	require.Equal(t, map[string]moduleregistry.LockedModule{
+		srv.URL + "/grafana/test": {
+			Constraint: "~> 2.0",
+			Version:    "2.0.1",
+			Digest:     moduleregistry.Digest(map[string]string{"module.alloy": testRegistryModule("2.0.1")}),
+		},
+	}, lf.Modules)
+
+	// New versions aren't imported while the version is locked.
+	registry.addVersion("2.0.2")
+	im, contents = newTestImportRegistry(t, t.TempDir())
+	require.NoError(t, im.Update(testRegistryArguments(srv.URL, "~> 2.0", lockfile)))
+	require.Equal(t, []string{testRegistryModule("2.0.1")}, *contents)
+
+	// Changing the constraint resolves the version again.
+	require.NoError(t, im.Update(testRegistryArguments(srv.URL, "^2.0", lockfile)))
+	require.Equal(t, []string{testRegistryModule("2.0.1"), testRegistryModule("2.1.0")}, *contents)
+
+	lf, err = moduleregistry.ReadLockfile(lockfile)
+	require.NoError(t, err)
+	require.Equal(t, "2.1.0", lf.Modules[srv.URL+"/grafana/test"].Version)
+}
+
+func TestImportRegistry_DigestMismatch(t *testing.T) {
+	registry := &testRegistry{versions: []string{"1.0.0"}}
+	srv := httptest.NewServer(registry)
+	defer srv.Close()
+
+	lockfile := filepath.Join(t.TempDir(), "alloy.lock")
+	require.NoError(t, moduleregistry.Lock(lockfile, srv.URL+"/grafana/test", moduleregistry.LockedModule{
+		Constraint: "*",
+		Version:    "1.0.0",
+		Digest:     "sha256:0000",
+	}))
+
+	im, contents := newTestImportRegistry(t, t.TempDir())
+	err := im.Update(testRegistryArguments(srv.URL, "*", lockfile))
+	require.ErrorContains(t, err, `version 1.0.0 of module "grafana/test" has digest sha256:`)
+	require.ErrorContains(t, err, "but the lockfile records sha256:0000")
+	require.Empty(t, *contents)
+}
+
+func TestImportRegistry_Cache(t *testing.T) {
+	registry := &testRegistry{versions: []string{"1.0.0", "1.1.0"}}
+	srv := httptest.NewServer(registry)
+	defer srv.Close()
+
+	dataPath := t.TempDir()
+	im, contents := newTestImportRegistry(t, dataPath)
+	require.NoError(t, im.Update(testRegistryArguments(srv.URL, "*", "")))
+	require.Equal(t, []string{testRegistryModule("1.1.0")}, *contents)
+
+	// New versions are imported when polling.
+	registry.addVersion("1.2.0")
+	im.mut.Lock()
+	require.NoError(t, im.poll(t.Context()))
+	im.mut.Unlock()
+	require.Equal(t, []string{testRegistryModule("1.1.0"), testRegistryModule("1.2.0")}, *contents)
+
+	// The highest cached version is imported if the registry is unavailable.
+	registry.down.Store(true)
+	im, contents = newTestImportRegistry(t, dataPath)
+	require.NoError(t, im.Update(testRegistryArguments(srv.URL, "< 1.2", "")))
+	require.Equal(t, []string{testRegistryModule("1.1.0")}, *contents)
+	require.Equal(t, component.HealthTypeUnhealthy, im.CurrentHealth().Health)
+	require.Contains(t, im.CurrentHealth().Message, "imported cached version 1.1.0")
+
+	// Modules are only imported from their own cache.
+	im, contents = newTestImportRegistry(t, dataPath)
+	args := testRegistryArguments(srv.URL, "*", "")
+	args.Module = "grafana/other"
+	require.ErrorContains(t, im.Update(args), `no cached version satisfies "*"`)
+	require.Empty(t, *contents)
+}
+
+func TestRegistryArguments_Validate(t *testing.T) {
+	args := testRegistryArguments("oci://ghcr.io/grafana", "~> 2.0", "")
+	require.NoError(t, args.Validate())
+
+	args.Version = "two"
+	require.ErrorContains(t, args.Validate(), `invalid version constraint "two"`)
+
+	args = testRegistryArguments("ftp://modules.example.com", "*", "")
+	require.ErrorContains(t, args.Validate(), `unsupported registry URL scheme "ftp"`)
+
+	args = testRegistryArguments("oci://ghcr.io/grafana", "*", "")
+	args.Module = ""
+	require.EqualError(t, args.Validate(), "module must not be empty")
+}
@@ -15,13 +15,19 @@ const (
 	String
 	Git
 	HTTP
+	Registry
+	From
+	Builtin
 )
 
 const (
-	BlockImportFile   = "import.file"
-	BlockImportString = "import.string"
-	BlockImportHTTP   = "import.http"
-	BlockImportGit    = "import.git"
+	BlockImportFile     = "import.file"
+	BlockImportString   = "import.string"
+	BlockImportHTTP     = "import.http"
+	BlockImportGit      = "import.git"
+	BlockImportRegistry = "import.registry"
+	BlockImportFrom     = "import.from"
+	BlockImportBuiltin  = "import.builtin"
 )
 
 const ModulePath = "module_path"
@@ -52,6 +58,12 @@ func NewImportSource(sourceType SourceType, managedOpts component.Options, eval
 		return NewImportHTTP(managedOpts, eval, onContentChange)
 	case Git:
 		return NewImportGit(managedOpts, eval, onContentChange)
+	case Registry:
+		return NewImportRegistry(managedOpts, eval, onContentChange)
+	case From:
+		return NewImportFrom(eval, onContentChange)
+	case Builtin:
+		return NewImportBuiltin(eval, onContentChange)
 	}
 	panic(fmt.Errorf("unsupported source type: %v", sourceType))
 }
@@ -67,6 +79,12 @@ func GetSourceType(fullName string) SourceType {
 		return HTTP
 	case BlockImportGit:
 		return Git
+	case BlockImportRegistry:
+		return Registry
+	case BlockImportFrom:
+		return From
+	case BlockImportBuiltin:
+		return Builtin
 	}
 	panic(fmt.Errorf("name does not map to a known source type: %v", fullName))
 }
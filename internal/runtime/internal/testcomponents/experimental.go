@@ -12,6 +12,7 @@ func init() {
 	component.Register(component.Registration{
 		Name:      "testcomponents.experimental",
 		Stability: featuregate.StabilityExperimental,
+		Args:      struct{}{},
 
 		Build: func(opts component.Options, args component.Arguments) (component.Component, error) {
 			return &Experimental{log: opts.Logger}, nil
@@ -0,0 +1,182 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/alecthomas/units"
+	"gopkg.in/natefinch/lumberjack.v2"
+
+	"github.com/grafana/alloy/syntax"
+)
+
+// FileOptions configures writing logs to files, in addition to stderr.
+type FileOptions struct {
+	Path           string           `alloy:"path,attr"`
+	MaxSize        units.Base2Bytes `alloy:"max_size,attr,optional"`
+	MaxAge         time.Duration    `alloy:"max_age,attr,optional"`
+	MaxBackups     int              `alloy:"max_backups,attr,optional"`
+	RotateInterval time.Duration    `alloy:"rotate_interval,attr,optional"`
+	Compress       bool             `alloy:"compress,attr,optional"`
+
+	// ErrorPath is the path of a file which only receives warn and error
+	// logs, in addition to the file at Path. It's rotated with the same
+	// settings.
+	ErrorPath string `alloy:"error_path,attr,optional"`
+}
+
+// DefaultFileOptions holds defaults for writing logs to files.
+var DefaultFileOptions = FileOptions{
+	MaxSize:    100 * units.MiB,
+	MaxBackups: 10,
+}
+
+var (
+	_ syntax.Defaulter = (*FileOptions)(nil)
+	_ syntax.Validator = (*FileOptions)(nil)
+)
+
+// SetToDefault implements syntax.Defaulter.
+func (o *FileOptions) SetToDefault() {
+	*o = DefaultFileOptions
+}
+
+// Validate implements syntax.Validator.
+func (o *FileOptions) Validate() error {
+	var errs []error
+	if o.Path == "" {
+		errs = append(errs, errors.New("path must not be empty"))
+	}
+	if o.ErrorPath != "" && o.ErrorPath == o.Path {
+		errs = append(errs, errors.New("error_path must be different from path"))
+	}
+	if o.MaxSize < units.MiB {
+		errs = append(errs, fmt.Errorf("max_size must be at least 1MiB, got %s", o.MaxSize))
+	}
+	if o.MaxAge < 0 {
+		errs = append(errs, errors.New("max_age must not be negative"))
+	}
+	if o.MaxBackups < 0 {
+		errs = append(errs, errors.New("max_backups must not be negative"))
+	}
+	if o.RotateInterval != 0 && o.RotateInterval < time.Minute {
+		errs = append(errs, fmt.Errorf("rotate_interval must be at least 1m, got %s", o.RotateInterval))
+	}
+	return errors.Join(errs...)
+}
+
+// fileSink writes logs to rotated files.
+type fileSink struct {
+	opts   FileOptions
+	file   *lumberjack.Logger
+	errors *lumberjack.Logger // nil if opts.ErrorPath is empty.
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// newFileSink opens the files configured by opts. The files are rotated every
+// opts.RotateInterval, if set, until the sink is closed.
+func newFileSink(opts FileOptions) (*fileSink, error) {
+	s := &fileSink{
+		opts: opts,
+		file: newRotatedFile(opts.Path, opts),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	if opts.ErrorPath != "" {
+		s.errors = newRotatedFile(opts.ErrorPath, opts)
+	}
+
+	// Writing no data opens the files, which reports invalid paths or
+	// permissions now instead of when the first log line is written.
+	for _, f := range s.files() {
+		if _, err := f.Write(nil); err != nil {
+			_ = s.closeFiles()
+			return nil, fmt.Errorf("opening log file: %w", err)
+		}
+	}
+
+	go s.run()
+	return s, nil
+}
+
+func newRotatedFile(path string, opts FileOptions) *lumberjack.Logger {
+	// lumberjack counts sizes in megabytes of 1024*1024 bytes.
+	maxSize := int((opts.MaxSize + units.MiB - 1) / units.MiB)
+
+	var maxAgeDays int
+	if opts.MaxAge > 0 {
+		maxAgeDays = max(1, int(opts.MaxAge/(24*time.Hour)))
+	}
+
+	return &lumberjack.Logger{
+		Filename:   path,
+		MaxSize:    maxSize,
+		MaxAge:     maxAgeDays,
+		MaxBackups: opts.MaxBackups,
+		Compress:   opts.Compress,
+	}
+}
+
+func (s *fileSink) files() []*lumberjack.Logger {
+	if s.errors == nil {
+		return []*lumberjack.Logger{s.file}
+	}
+	return []*lumberjack.Logger{s.file, s.errors}
+}
+
+func (s *fileSink) run() {
+	defer close(s.done)
+
+	if s.opts.RotateInterval == 0 {
+		<-s.stop
+		return
+	}
+
+	ticker := time.NewTicker(s.opts.RotateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			for _, f := range s.files() {
+				// There's nowhere to report the error to but the logger itself;
+				// a failed rotation is retried on the next tick.
+				_ = f.Rotate()
+			}
+		}
+	}
+}
+
+// write writes p to the log file, and also to the error file if isError is
+// true.
+func (s *fileSink) write(p []byte, isError bool) error {
+	if _, err := s.file.Write(p); err != nil {
+		return err
+	}
+	if isError && s.errors != nil {
+		if _, err := s.errors.Write(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close stops rotating the files and closes them.
+func (s *fileSink) Close() error {
+	close(s.stop)
+	<-s.done
+	return s.closeFiles()
+}
+
+func (s *fileSink) closeFiles() error {
+	var errs []error
+	for _, f := range s.files() {
+		errs = append(errs, f.Close())
+	}
+	return errors.Join(errs...)
+}
@@ -22,7 +22,8 @@ import (
 
 type handler struct {
 	w         io.Writer
-	leveler   slog.Leveler
+	errW      io.Writer // Writer for warn and error logs.
+	leveler   *levelVar
 	formatter formatter
 
 	nested []nesting
@@ -30,6 +31,7 @@ type handler struct {
 	mut           sync.RWMutex
 	currentFormat Format
 	inner         slog.Handler
+	innerErr      slog.Handler // Inner handler for warn and error logs.
 	replacer      func(groups []string, a slog.Attr) slog.Attr
 }
 
@@ -51,10 +53,48 @@ func (h *handler) Enabled(ctx context.Context, l slog.Level) bool {
 }
 
 func (h *handler) Handle(ctx context.Context, r slog.Record) error {
-	return h.buildHandler().Handle(ctx, r)
+	// Enabled only checked the most verbose level of all the overrides, so
+	// records must be checked against the level of the component logging them.
+	if h.leveler.hasOverrides() {
+		componentPath, componentID := h.componentAttrs(r)
+		if r.Level < h.leveler.levelFor(componentPath, componentID) {
+			return nil
+		}
+	}
+	return h.buildHandler(r.Level >= slog.LevelWarn).Handle(ctx, r)
 }
 
-func (h *handler) buildHandler() slog.Handler {
+// componentAttrs returns the values of the component_path and component_id
+// attributes of r and h, which identify the component logging r.
+func (h *handler) componentAttrs(r slog.Record) (componentPath, componentID string) {
+	find := func(a slog.Attr) {
+		switch a.Key {
+		case "component_path":
+			componentPath = a.Value.String()
+		case "component_id":
+			componentID = a.Value.String()
+		}
+	}
+
+	for _, n := range h.nested {
+		if n.group != "" {
+			// Attributes nested in groups aren't set by the controller.
+			return componentPath, componentID
+		}
+		for _, a := range n.attrs {
+			find(a)
+		}
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		find(a)
+		return true
+	})
+	return componentPath, componentID
+}
+
+// buildHandler returns the inner handler for the current format. isError
+// selects the handler which writes warn and error logs.
+func (h *handler) buildHandler(isError bool) slog.Handler {
 	// Get the expected format for the duration of this call. It's possible that
 	// this will be stale by the time the call returns, but it will be correct on
 	// the next call.
@@ -64,14 +104,31 @@ func (h *handler) buildHandler() slog.Handler {
 	h.mut.RLock()
 	if h.currentFormat == expectFormat && h.inner != nil {
 		defer h.mut.RUnlock()
+		if isError {
+			return h.innerErr
+		}
 		return h.inner
 	}
 	h.mut.RUnlock()
 
-	// Slow path: we need to build a new handler.
+	// Slow path: we need to build new handlers.
 	h.mut.Lock()
 	defer h.mut.Unlock()
 
+	h.currentFormat = expectFormat
+	h.inner = h.newInnerHandler(expectFormat, h.w)
+	h.innerErr = h.inner
+	if h.errW != nil {
+		h.innerErr = h.newInnerHandler(expectFormat, h.errW)
+	}
+
+	if isError {
+		return h.innerErr
+	}
+	return h.inner
+}
+
+func (h *handler) newInnerHandler(format Format, w io.Writer) slog.Handler {
 	var newHandler slog.Handler
 
 	handlerOpts := slog.HandlerOptions{
@@ -83,13 +140,13 @@ func (h *handler) buildHandler() slog.Handler {
 		ReplaceAttr: h.replacer,
 	}
 
-	switch expectFormat {
+	switch format {
 	case FormatLogfmt:
-		newHandler = slog.NewTextHandler(h.w, &handlerOpts)
+		newHandler = slog.NewTextHandler(w, &handlerOpts)
 	case FormatJSON:
-		newHandler = slog.NewJSONHandler(h.w, &handlerOpts)
+		newHandler = slog.NewJSONHandler(w, &handlerOpts)
 	default:
-		panic(fmt.Sprintf("unknown format %v", expectFormat))
+		panic(fmt.Sprintf("unknown format %v", format))
 	}
 
 	// Need to replay our groups and attrs in the correct order.
@@ -101,8 +158,6 @@ func (h *handler) buildHandler() slog.Handler {
 		}
 	}
 
-	h.currentFormat = expectFormat
-	h.inner = newHandler
 	return newHandler
 }
 
@@ -115,6 +170,7 @@ func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
 
 	return &handler{
 		w:         h.w,
+		errW:      h.errW,
 		leveler:   h.leveler,
 		formatter: h.formatter,
 
@@ -131,6 +187,7 @@ func (h *handler) WithGroup(name string) slog.Handler {
 	})
 	return &handler{
 		w:         h.w,
+		errW:      h.errW,
 		leveler:   h.leveler,
 		formatter: h.formatter,
 
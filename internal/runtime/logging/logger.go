@@ -28,7 +28,7 @@ type Logger struct {
 	buffer       []*bufferedItem // Store logs before correctly determine the log format
 	hasLogFormat bool            // Confirmation whether log format has been determined
 
-	level        *slog.LevelVar       // Current configured level.
+	level        *levelVar            // Current configured level and overrides.
 	format       *formatVar           // Current configured format.
 	writer       *writerVar           // Current configured multiwriter (inner + write_to).
 	handler      *handler             // Handler which handles logs.
@@ -65,7 +65,7 @@ func NewNop() *Logger {
 // The logger is not updated during initialization.
 func NewDeferred(w io.Writer) (*Logger, error) {
 	var (
-		leveler slog.LevelVar
+		leveler levelVar
 		format  formatVar
 		writer  writerVar
 	)
@@ -80,6 +80,7 @@ func NewDeferred(w io.Writer) (*Logger, error) {
 		writer: &writer,
 		handler: &handler{
 			w:         &writer,
+			errW:      errorWriter{&writer},
 			leveler:   &leveler,
 			formatter: &format,
 			replacer:  replace,
@@ -106,7 +107,11 @@ func (l *Logger) Update(o Options) error {
 		return fmt.Errorf("unrecognized log format %q", o.Format)
 	}
 
-	l.level.Set(slogLevel(o.Level).Level())
+	if err := l.writer.SetFileOptions(o.File); err != nil {
+		return err
+	}
+
+	l.level.Set(o.Level, o.LevelOverrides)
 	l.format.Set(o.Format)
 
 	l.writer.SetInnerWriter(l.inner)
@@ -145,6 +150,12 @@ func (l *Logger) RemoveTemporaryWriter() {
 	l.writer.RemoveTemporaryWriter()
 }
 
+// RecentLogs returns the most recent log lines written by the logger, ordered
+// from oldest to newest.
+func (l *Logger) RecentLogs() []byte {
+	return l.writer.recent.Bytes()
+}
+
 // Log implements log.Logger.
 func (l *Logger) Log(kvps ...interface{}) error {
 	// Buffer logs before confirming log format is configured in `logging` block
@@ -231,6 +242,8 @@ type writerVar struct {
 	lokiWriter  *lokiWriter
 	innerWriter io.Writer
 	tmpWriter   io.Writer
+	fileSink    *fileSink
+	recent      recentLogs // Always written to, independently of the other writers.
 }
 
 func (w *writerVar) SetTemporaryWriter(writer io.Writer) {
@@ -257,7 +270,43 @@ func (w *writerVar) SetLokiWriter(writer *lokiWriter) {
 	w.lokiWriter = writer
 }
 
+// SetFileOptions starts writing logs to the files configured by opts, or
+// stops writing logs to files if opts is nil. The files are only reopened
+// when opts changed.
+func (w *writerVar) SetFileOptions(opts *FileOptions) error {
+	w.mut.RLock()
+	current := w.fileSink
+	w.mut.RUnlock()
+
+	if current == nil && opts == nil || current != nil && opts != nil && current.opts == *opts {
+		return nil
+	}
+
+	var sink *fileSink
+	if opts != nil {
+		var err error
+		if sink, err = newFileSink(*opts); err != nil {
+			return err
+		}
+	}
+
+	w.mut.Lock()
+	w.fileSink = sink
+	w.mut.Unlock()
+
+	if current != nil {
+		return current.Close()
+	}
+	return nil
+}
+
 func (w *writerVar) Write(p []byte) (int, error) {
+	return w.write(p, false)
+}
+
+// write writes p to all the writers. isError is true for warn and error
+// logs, which are also written to the error file, if any.
+func (w *writerVar) write(p []byte, isError bool) (int, error) {
 	w.mut.RLock()
 	defer w.mut.RUnlock()
 
@@ -283,9 +332,26 @@ func (w *writerVar) Write(p []byte) (int, error) {
 		}
 	}
 
+	if w.fileSink != nil {
+		if err := w.fileSink.write(p, isError); err != nil {
+			return 0, err
+		}
+	}
+
+	_, _ = w.recent.Write(p)
+
 	return len(p), nil
 }
 
+// errorWriter writes warn and error logs.
+type errorWriter struct {
+	w *writerVar
+}
+
+func (ew errorWriter) Write(p []byte) (int, error) {
+	return ew.w.write(p, true)
+}
+
 type bufferedItem struct {
 	kvps    []interface{}
 	handler *deferredSlogHandler
@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/alecthomas/units"
 	"github.com/go-kit/log"
 	gokitlevel "github.com/go-kit/log/level"
 	"github.com/grafana/alloy/internal/component/common/loki"
@@ -185,6 +188,129 @@ func Test_lokiWriter_nil(t *testing.T) {
 	})
 }
 
+func TestRecentLogs(t *testing.T) {
+	logger, err := logging.New(io.Discard, debugLevel())
+	require.NoError(t, err)
+
+	for i := 0; i < 1010; i++ {
+		require.NoError(t, logger.Log("msg", fmt.Sprintf("message %d", i)))
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(logger.RecentLogs()), "\n"), "\n")
+	require.Len(t, lines, 1000)
+	require.Contains(t, lines[0], `msg="message 10"`)
+	require.Contains(t, lines[len(lines)-1], `msg="message 1009"`)
+}
+
+func TestLevelOverrides(t *testing.T) {
+	buffer := bytes.NewBuffer(nil)
+	logger, err := logging.New(buffer, logging.Options{
+		Level:  logging.LevelInfo,
+		Format: logging.FormatLogfmt,
+		LevelOverrides: []logging.LevelOverride{
+			{Components: []string{"loki.source.kafka.*"}, Level: logging.LevelDebug},
+			{Modules: []string{"import.file.lib"}, Level: logging.LevelError},
+		},
+	})
+	require.NoError(t, err)
+
+	componentLogger := func(path, id string) log.Logger {
+		return log.With(logger, "component_path", path, "component_id", id)
+	}
+	kafka := componentLogger("/", "loki.source.kafka.default")
+	scrape := componentLogger("/", "prometheus.scrape.default")
+	nested := componentLogger("/import.file.lib/custom.default", "loki.write.default")
+	slogger := slog.New(logger.Handler()).With("component_path", "/", "component_id", "loki.source.kafka.slog")
+
+	alloylevel.Debug(kafka).Log("msg", "kafka debug")
+	alloylevel.Debug(scrape).Log("msg", "scrape debug")
+	alloylevel.Info(scrape).Log("msg", "scrape info")
+	alloylevel.Warn(nested).Log("msg", "nested warn")
+	alloylevel.Error(nested).Log("msg", "nested error")
+	alloylevel.Debug(logger).Log("msg", "global debug")
+	slogger.Debug("slog debug")
+
+	out := buffer.String()
+	require.Contains(t, out, `msg="kafka debug"`)
+	require.NotContains(t, out, `msg="scrape debug"`)
+	require.Contains(t, out, `msg="scrape info"`)
+	require.NotContains(t, out, `msg="nested warn"`)
+	require.Contains(t, out, `msg="nested error"`)
+	require.NotContains(t, out, `msg="global debug"`)
+	require.Contains(t, out, `msg="slog debug"`)
+
+	// Overrides are removed on update.
+	buffer.Reset()
+	require.NoError(t, logger.Update(infoLevel()))
+	alloylevel.Debug(kafka).Log("msg", "kafka debug")
+	require.Empty(t, buffer.String())
+}
+
+func TestLevelOverrideValidate(t *testing.T) {
+	o := logging.LevelOverride{Level: logging.LevelDebug}
+	require.ErrorContains(t, o.Validate(), "at least one of components or modules must be set")
+
+	o.Components = []string{"loki.source.["}
+	require.ErrorContains(t, o.Validate(), `invalid pattern "loki.source.["`)
+}
+
+func TestFileOutput(t *testing.T) {
+	dir := t.TempDir()
+	opts := logging.Options{
+		Level:  logging.LevelInfo,
+		Format: logging.FormatLogfmt,
+		File: &logging.FileOptions{
+			Path:      filepath.Join(dir, "alloy.log"),
+			ErrorPath: filepath.Join(dir, "errors", "alloy-error.log"),
+			MaxSize:   units.MiB,
+		},
+	}
+	stderr := bytes.NewBuffer(nil)
+	logger, err := logging.New(stderr, opts)
+	require.NoError(t, err)
+
+	alloylevel.Info(logger).Log("msg", "info message")
+	alloylevel.Warn(logger).Log("msg", "warn message")
+	alloylevel.Error(logger).Log("msg", "error message")
+
+	// Updating with the same options keeps writing to the same files.
+	require.NoError(t, logger.Update(opts))
+	alloylevel.Info(logger).Log("msg", "after update")
+
+	// Logs are no longer written to files once the file block is removed.
+	require.NoError(t, logger.Update(infoLevel()))
+	alloylevel.Error(logger).Log("msg", "after removal")
+
+	all, err := os.ReadFile(opts.File.Path)
+	require.NoError(t, err)
+	require.Contains(t, string(all), `msg="info message"`)
+	require.Contains(t, string(all), `msg="warn message"`)
+	require.Contains(t, string(all), `msg="error message"`)
+	require.Contains(t, string(all), `msg="after update"`)
+	require.NotContains(t, string(all), `msg="after removal"`)
+
+	errs, err := os.ReadFile(opts.File.ErrorPath)
+	require.NoError(t, err)
+	require.NotContains(t, string(errs), `msg="info message"`)
+	require.Contains(t, string(errs), `msg="warn message"`)
+	require.Contains(t, string(errs), `msg="error message"`)
+
+	require.Contains(t, stderr.String(), `msg="after removal"`)
+}
+
+func TestFileOptionsValidate(t *testing.T) {
+	var opts logging.FileOptions
+	opts.SetToDefault()
+	opts.Path = "alloy.log"
+	require.NoError(t, opts.Validate())
+
+	opts.ErrorPath = "alloy.log"
+	opts.MaxSize = units.KiB
+	err := opts.Validate()
+	require.ErrorContains(t, err, "error_path must be different from path")
+	require.ErrorContains(t, err, "max_size must be at least 1MiB")
+}
+
 func BenchmarkLogging_NoLevel_Prints(b *testing.B) {
 	logger, err := logging.New(io.Discard, infoLevel())
 	require.NoError(b, err)
@@ -16,6 +16,10 @@ type Options struct {
 	Format Format `alloy:"format,attr,optional"`
 
 	WriteTo []loki.LogsReceiver `alloy:"write_to,attr,optional"`
+
+	LevelOverrides []LevelOverride `alloy:"level_override,block,optional"`
+
+	File *FileOptions `alloy:"file,block,optional"`
 }
 
 // DefaultOptions holds defaults for creating a Logger.
@@ -24,13 +28,26 @@ var DefaultOptions = Options{
 	Format: FormatDefault,
 }
 
-var _ syntax.Defaulter = (*Options)(nil)
+var (
+	_ syntax.Defaulter = (*Options)(nil)
+	_ syntax.Validator = (*Options)(nil)
+)
 
 // SetToDefault implements syntax.Defaulter.
 func (o *Options) SetToDefault() {
 	*o = DefaultOptions
 }
 
+// Validate implements syntax.Validator.
+func (o *Options) Validate() error {
+	for i, override := range o.LevelOverrides {
+		if err := override.Validate(); err != nil {
+			return fmt.Errorf("level_override block %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
 // Level represents how verbose logging should be.
 type Level string
 
@@ -0,0 +1,150 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// LevelOverride overrides the log level of the components matching its
+// patterns.
+type LevelOverride struct {
+	// Components holds glob patterns matched against the ID of components
+	// within their module, like "loki.source.kafka.*".
+	Components []string `alloy:"components,attr,optional"`
+	// Modules holds glob patterns matched against the ID of modules, like
+	// "import.file.lib". Components of the modules declared inside a matching
+	// module also match.
+	Modules []string `alloy:"modules,attr,optional"`
+	// Level is the level at which the logs of matching components are
+	// written.
+	Level Level `alloy:"level,attr"`
+}
+
+// Validate checks that the patterns of o are valid.
+func (o *LevelOverride) Validate() error {
+	if len(o.Components) == 0 && len(o.Modules) == 0 {
+		return errors.New("at least one of components or modules must be set")
+	}
+	for _, pattern := range append(o.Components, o.Modules...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return nil
+}
+
+// match returns true if the component componentID, declared in the module
+// moduleID, matches o. moduleID is empty for the root module.
+func (o *LevelOverride) match(moduleID, componentID string) bool {
+	if len(o.Components) > 0 && !matchAny(o.Components, componentID) {
+		return false
+	}
+	if len(o.Modules) == 0 {
+		return true
+	}
+	if moduleID == "" {
+		return false
+	}
+
+	// Check the module and the modules declaring it, from the outermost one.
+	parts := strings.Split(moduleID, "/")
+	for i := range parts {
+		if matchAny(o.Modules, strings.Join(parts[:i+1], "/")) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// levelVar holds the configured log level and its overrides. It implements
+// slog.Leveler by returning the most verbose of these levels, so that
+// handlers only drop records which no override would write; records are then
+// filtered by component with levelFor.
+type levelVar struct {
+	global    slog.LevelVar
+	overrides atomic.Pointer[levelOverrides]
+}
+
+var _ slog.Leveler = (*levelVar)(nil)
+
+type levelOverrides struct {
+	list     []LevelOverride
+	minLevel slog.Level
+
+	// cache holds the resolved level of components, keyed by their
+	// component_path and component_id.
+	cache sync.Map
+}
+
+// Set updates the global level and the overrides of v.
+func (v *levelVar) Set(global Level, overrides []LevelOverride) {
+	v.global.Set(slogLevel(global).Level())
+
+	if len(overrides) == 0 {
+		v.overrides.Store(nil)
+		return
+	}
+
+	lo := &levelOverrides{
+		list:     overrides,
+		minLevel: slogLevel(overrides[0].Level).Level(),
+	}
+	for _, o := range overrides[1:] {
+		lo.minLevel = min(lo.minLevel, slogLevel(o.Level).Level())
+	}
+	v.overrides.Store(lo)
+}
+
+// Level implements slog.Leveler.
+func (v *levelVar) Level() slog.Level {
+	level := v.global.Level()
+	if lo := v.overrides.Load(); lo != nil {
+		level = min(level, lo.minLevel)
+	}
+	return level
+}
+
+// hasOverrides returns true if any override is configured.
+func (v *levelVar) hasOverrides() bool {
+	return v.overrides.Load() != nil
+}
+
+// levelFor returns the level of the component identified by the values of
+// its component_path and component_id log attributes. The first matching
+// override wins. Logs which don't come from a component use the global level.
+func (v *levelVar) levelFor(componentPath, componentID string) slog.Level {
+	lo := v.overrides.Load()
+	if lo == nil || componentID == "" {
+		return v.global.Level()
+	}
+
+	key := componentPath + "\x00" + componentID
+	if level, ok := lo.cache.Load(key); ok {
+		return level.(slog.Level)
+	}
+
+	level := v.global.Level()
+	moduleID := strings.TrimPrefix(componentPath, "/")
+	for _, o := range lo.list {
+		if o.match(moduleID, componentID) {
+			level = slogLevel(o.Level).Level()
+			break
+		}
+	}
+	lo.cache.Store(key, level)
+	return level
+}
@@ -0,0 +1,47 @@
+package logging
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxRecentLogLines is the number of most recent log lines retained in memory
+// for inclusion in support bundles.
+const maxRecentLogLines = 1000
+
+// recentLogs is a bounded in-memory buffer of the most recently written log
+// lines. The zero value is ready for use.
+type recentLogs struct {
+	mut   sync.Mutex
+	lines [][]byte // Ring buffer of log lines.
+	next  int      // Index in lines where the next log line is written.
+}
+
+// Write implements io.Writer. Each call to Write is expected to contain a
+// single log line.
+func (rl *recentLogs) Write(p []byte) (int, error) {
+	line := bytes.Clone(p)
+
+	rl.mut.Lock()
+	defer rl.mut.Unlock()
+
+	if len(rl.lines) < maxRecentLogLines {
+		rl.lines = append(rl.lines, line)
+		return len(p), nil
+	}
+	rl.lines[rl.next] = line
+	rl.next = (rl.next + 1) % maxRecentLogLines
+	return len(p), nil
+}
+
+// Bytes returns the retained log lines, ordered from oldest to newest.
+func (rl *recentLogs) Bytes() []byte {
+	rl.mut.Lock()
+	defer rl.mut.Unlock()
+
+	var buf bytes.Buffer
+	for i := range rl.lines {
+		buf.Write(rl.lines[(rl.next+i)%len(rl.lines)])
+	}
+	return buf.Bytes()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path"
 	"sync"
+	"time"
 
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/featuregate"
@@ -131,10 +132,9 @@ func newModule(o *moduleOptions) *module {
 	return &module{
 		o: o,
 		f: newController(controllerOptions{
-			IsModule:          true,
-			ModuleRegistry:    o.ModuleRegistry,
-			ComponentRegistry: o.ComponentRegistry,
-			WorkerPool:        o.WorkerPool,
+			IsModule:       true,
+			ModuleRegistry: o.ModuleRegistry,
+			WorkerPool:     o.WorkerPool,
 			Options: Options{
 				ControllerID:         o.ID,
 				Tracer:               o.Tracer,
@@ -143,6 +143,10 @@ func newModule(o *moduleOptions) *module {
 				DataPath:             o.DataPath,
 				MinStability:         o.MinStability,
 				EnableCommunityComps: o.EnableCommunityComps,
+				DryRun:               o.DryRun,
+				EnforceFIPS:          o.EnforceFIPS,
+				EvaluationTimeout:    o.EvaluationTimeout,
+				ComponentRegistry:    o.ComponentRegistry,
 				OnExportsChange: func(exports map[string]any) {
 					if o.export != nil {
 						o.export(exports)
@@ -230,4 +234,13 @@ type moduleControllerOptions struct {
 
 	// EnableCommunityComps enables the use of community components.
 	EnableCommunityComps bool
+
+	// DryRun only validates the config of the module.
+	DryRun bool
+
+	// EnforceFIPS refuses components which aren't FIPS compliant.
+	EnforceFIPS bool
+
+	// EvaluationTimeout is the maximum duration of the evaluation of a block.
+	EvaluationTimeout time.Duration
 }
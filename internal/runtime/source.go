@@ -75,7 +75,14 @@ func sourceFromBody(body ast.Body) (*Source, error) {
 			switch fullName {
 			case "declare":
 				declares = append(declares, stmt)
-			case "logging", "tracing", "argument", "export", "import.file", "import.string", "import.http", "import.git", "foreach":
+			case "function":
+				return nil, diag.Diagnostic{
+					Severity: diag.SeverityLevelError,
+					StartPos: ast.StartPos(stmt).Position(),
+					EndPos:   ast.EndPos(stmt).Position(),
+					Message:  "function blocks are only allowed in declare blocks",
+				}
+			case "logging", "tracing", "argument", "export", "import.file", "import.string", "import.http", "import.git", "import.registry", "import.from", "import.builtin", "foreach":
 				configs = append(configs, stmt)
 			default:
 				components = append(components, stmt)
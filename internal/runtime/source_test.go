@@ -94,6 +94,13 @@ func TestParseSources_DuplicateComponent(t *testing.T) {
 	diagErrs, ok := err.(diag.Diagnostics)
 	require.True(t, ok)
 	require.Len(t, diagErrs, 2)
+
+	// Sources are loaded in the order of their names, so the duplicates are
+	// reported in t2 and point at the blocks in t1.
+	for _, d := range diagErrs {
+		require.Equal(t, "t2", d.StartPos.Filename)
+		require.Contains(t, d.Message, "already declared at t1:")
+	}
 }
 
 func TestParseSources_UniqueComponent(t *testing.T) {
@@ -0,0 +1,291 @@
+package tracing
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanFilter is a span processor which filters spans by component ID and
+// tail-samples traces before passing the remaining spans to the next span
+// processor.
+//
+// Tail sampling buffers the spans of a trace until its local root span ends,
+// or until the decision wait elapses, whichever comes first. The whole trace
+// is then kept or dropped depending on whether it contains an error or a slow
+// span.
+type spanFilter struct {
+	next tracesdk.SpanProcessor
+
+	mut        sync.Mutex
+	components []string
+	tail       *TailSamplingOptions
+	pending    map[trace.TraceID]*pendingTrace
+	decided    map[trace.TraceID]decision
+
+	checkInterval time.Duration
+	stop          chan struct{} // Closed to stop checking for expired traces; nil when not running.
+	now           func() time.Time
+}
+
+type pendingTrace struct {
+	firstSeen time.Time
+	spans     []tracesdk.ReadOnlySpan
+}
+
+type decision struct {
+	keep bool
+	at   time.Time
+}
+
+var _ tracesdk.SpanProcessor = (*spanFilter)(nil)
+
+// newSpanFilter creates a new spanFilter which forwards spans to next. While
+// tail sampling is enabled, the spanFilter checks for expired traces every
+// checkInterval.
+func newSpanFilter(next tracesdk.SpanProcessor, checkInterval time.Duration) *spanFilter {
+	return &spanFilter{
+		next:          next,
+		pending:       make(map[trace.TraceID]*pendingTrace),
+		decided:       make(map[trace.TraceID]decision),
+		checkInterval: checkInterval,
+		now:           time.Now,
+	}
+}
+
+// Update changes the component filter and the tail sampling options. Pending
+// traces are flushed when tail sampling gets disabled.
+func (sf *spanFilter) Update(components []string, tail *TailSamplingOptions) {
+	sf.mut.Lock()
+	sf.components = components
+	sf.tail = tail
+
+	var flush []tracesdk.ReadOnlySpan
+	switch {
+	case tail != nil && sf.stop == nil:
+		sf.stop = make(chan struct{})
+		go sf.run(sf.stop)
+	case tail == nil:
+		sf.stopLocked()
+		for id, pt := range sf.pending {
+			flush = append(flush, pt.spans...)
+			delete(sf.pending, id)
+		}
+		clear(sf.decided)
+	}
+	sf.mut.Unlock()
+
+	sf.forward(flush)
+}
+
+func (sf *spanFilter) run(stop chan struct{}) {
+	ticker := time.NewTicker(sf.checkInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			sf.forward(sf.expire(false))
+		}
+	}
+}
+
+// stopLocked stops checking for expired traces. sf.mut must be held when
+// calling stopLocked.
+func (sf *spanFilter) stopLocked() {
+	if sf.stop != nil {
+		close(sf.stop)
+		sf.stop = nil
+	}
+}
+
+// expire decides on all traces which have been pending for longer than the
+// decision wait, or on all pending traces if all is true. It returns the
+// spans which must be forwarded.
+func (sf *spanFilter) expire(all bool) []tracesdk.ReadOnlySpan {
+	sf.mut.Lock()
+	defer sf.mut.Unlock()
+
+	if sf.tail == nil {
+		return nil
+	}
+
+	var (
+		now  = sf.now()
+		keep []tracesdk.ReadOnlySpan
+	)
+	for id, pt := range sf.pending {
+		if all || now.Sub(pt.firstSeen) >= sf.tail.DecisionWait {
+			keep = append(keep, sf.decideLocked(id, pt)...)
+		}
+	}
+
+	// Decisions are remembered for the duration of the decision wait so that
+	// late spans of a trace follow the decision made for the rest of it.
+	for id, d := range sf.decided {
+		if now.Sub(d.at) >= sf.tail.DecisionWait {
+			delete(sf.decided, id)
+		}
+	}
+	return keep
+}
+
+// decideLocked makes a sampling decision for a pending trace and returns its
+// spans if the trace is kept. sf.mut must be held when calling decideLocked.
+func (sf *spanFilter) decideLocked(id trace.TraceID, pt *pendingTrace) []tracesdk.ReadOnlySpan {
+	delete(sf.pending, id)
+
+	keep := false
+	for _, s := range pt.spans {
+		if sf.tail.sampleSpan(s) {
+			keep = true
+			break
+		}
+	}
+	sf.decided[id] = decision{keep: keep, at: sf.now()}
+
+	if !keep {
+		return nil
+	}
+	return pt.spans
+}
+
+// OnStart implements tracesdk.SpanProcessor.
+func (sf *spanFilter) OnStart(parent context.Context, s tracesdk.ReadWriteSpan) {
+	sf.next.OnStart(parent, s)
+}
+
+// OnEnd implements tracesdk.SpanProcessor.
+func (sf *spanFilter) OnEnd(s tracesdk.ReadOnlySpan) {
+	sf.mut.Lock()
+	if sf.tail == nil {
+		sf.mut.Unlock()
+		sf.forward([]tracesdk.ReadOnlySpan{s})
+		return
+	}
+
+	id := s.SpanContext().TraceID()
+	if d, ok := sf.decided[id]; ok {
+		sf.mut.Unlock()
+		if d.keep {
+			sf.forward([]tracesdk.ReadOnlySpan{s})
+		}
+		return
+	}
+
+	var keep []tracesdk.ReadOnlySpan
+
+	pt, ok := sf.pending[id]
+	if !ok {
+		// Make room for the new trace by deciding on the oldest pending one.
+		if len(sf.pending) >= sf.tail.MaxTraces {
+			keep = append(keep, sf.decideOldestLocked()...)
+		}
+		pt = &pendingTrace{firstSeen: sf.now()}
+		sf.pending[id] = pt
+	}
+	pt.spans = append(pt.spans, s)
+
+	// The trace is complete once its local root span ends.
+	if !s.Parent().IsValid() || s.Parent().IsRemote() {
+		keep = append(keep, sf.decideLocked(id, pt)...)
+	}
+	sf.mut.Unlock()
+
+	sf.forward(keep)
+}
+
+func (sf *spanFilter) decideOldestLocked() []tracesdk.ReadOnlySpan {
+	var (
+		oldestID trace.TraceID
+		oldest   *pendingTrace
+	)
+	for id, pt := range sf.pending {
+		if oldest == nil || pt.firstSeen.Before(oldest.firstSeen) {
+			oldestID, oldest = id, pt
+		}
+	}
+	if oldest == nil {
+		return nil
+	}
+	return sf.decideLocked(oldestID, oldest)
+}
+
+// forward passes spans which match the component filter to the next span
+// processor.
+func (sf *spanFilter) forward(spans []tracesdk.ReadOnlySpan) {
+	if len(spans) == 0 {
+		return
+	}
+
+	sf.mut.Lock()
+	components := sf.components
+	sf.mut.Unlock()
+
+	for _, s := range spans {
+		if matchesComponents(s, components) {
+			sf.next.OnEnd(s)
+		}
+	}
+}
+
+// matchesComponents returns true if s isn't associated with a component, or if
+// its component ID matches one of components. A component ID matches if it's
+// equal to one of components, or if it belongs to a module whose ID is one of
+// components. An empty list of components matches all spans.
+func matchesComponents(s tracesdk.ReadOnlySpan, components []string) bool {
+	if len(components) == 0 {
+		return true
+	}
+
+	for _, attr := range s.Attributes() {
+		if string(attr.Key) != componentIDAttributeKey {
+			continue
+		}
+		id := attr.Value.AsString()
+		for _, c := range components {
+			if id == c || strings.HasPrefix(id, c+"/") {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// Shutdown implements tracesdk.SpanProcessor. Pending traces are decided on
+// before shutting down the next span processor.
+func (sf *spanFilter) Shutdown(ctx context.Context) error {
+	keep := sf.expire(true)
+
+	sf.mut.Lock()
+	sf.stopLocked()
+	sf.mut.Unlock()
+
+	sf.forward(keep)
+	return sf.next.Shutdown(ctx)
+}
+
+// ForceFlush implements tracesdk.SpanProcessor. Pending traces are kept
+// buffered, since a sampling decision can't be made for them yet.
+func (sf *spanFilter) ForceFlush(ctx context.Context) error {
+	return sf.next.ForceFlush(ctx)
+}
+
+// sampleSpan returns true if s makes its trace eligible to be kept.
+func (opts *TailSamplingOptions) sampleSpan(s tracesdk.ReadOnlySpan) bool {
+	if opts.Errors && s.Status().Code == codes.Error {
+		return true
+	}
+	if opts.MinDuration > 0 && s.EndTime().Sub(s.StartTime()) >= opts.MinDuration {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,129 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	tracesdk "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestSpanFilter_TailSampling(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	filter := newSpanFilter(recorder, time.Hour)
+	tp := tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(filter))
+
+	opts := DefaultTailSamplingOptions
+	opts.MinDuration = time.Second
+	filter.Update(nil, &opts)
+
+	tracer := tp.Tracer("test")
+	start := time.Now()
+
+	// Fast trace without errors: dropped.
+	ctx, root := tracer.Start(context.Background(), "fast")
+	_, child := tracer.Start(ctx, "fast-child")
+	child.End()
+	root.End()
+
+	// Trace with an error in a child span: kept.
+	ctx, root = tracer.Start(context.Background(), "error")
+	_, child = tracer.Start(ctx, "error-child")
+	child.SetStatus(codes.Error, "failed")
+	child.End()
+	root.End()
+
+	// Slow trace: kept.
+	_, root = tracer.Start(context.Background(), "slow", trace.WithTimestamp(start))
+	root.End(trace.WithTimestamp(start.Add(2 * time.Second)))
+
+	require.ElementsMatch(t, []string{"error-child", "error", "slow"}, spanNames(recorder.Ended()))
+
+	// Pending traces are decided on shutdown.
+	ctx, _ = tracer.Start(context.Background(), "unfinished")
+	_, child = tracer.Start(ctx, "unfinished-child")
+	child.SetStatus(codes.Error, "failed")
+	child.End()
+	require.NoError(t, tp.Shutdown(context.Background()))
+	require.Contains(t, spanNames(recorder.Ended()), "unfinished-child")
+}
+
+func TestSpanFilter_Expire(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	filter := newSpanFilter(recorder, time.Hour)
+	tp := tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(filter))
+	defer func() { require.NoError(t, tp.Shutdown(context.Background())) }()
+
+	now := time.Now()
+	filter.now = func() time.Time { return now }
+	filter.Update(nil, &DefaultTailSamplingOptions)
+
+	tracer := tp.Tracer("test")
+	ctx, root := tracer.Start(context.Background(), "root")
+	_, child := tracer.Start(ctx, "child")
+	child.SetStatus(codes.Error, "failed")
+	child.End()
+
+	filter.forward(filter.expire(false))
+	require.Empty(t, recorder.Ended())
+
+	// Once the decision wait elapses, the trace is decided on without its root
+	// span, and the root span follows the same decision.
+	now = now.Add(DefaultTailSamplingOptions.DecisionWait)
+	filter.forward(filter.expire(false))
+	require.Equal(t, []string{"child"}, spanNames(recorder.Ended()))
+
+	root.End()
+	require.Equal(t, []string{"child", "root"}, spanNames(recorder.Ended()))
+}
+
+func TestSpanFilter_Components(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	filter := newSpanFilter(recorder, time.Hour)
+	tp := tracesdk.NewTracerProvider(tracesdk.WithSpanProcessor(filter))
+	defer func() { require.NoError(t, tp.Shutdown(context.Background())) }()
+
+	filter.Update([]string{"prometheus.scrape.default", "import.file.mod"}, nil)
+
+	for _, id := range []string{
+		"prometheus.scrape.default",
+		"prometheus.scrape.other",
+		"import.file.mod/prometheus.remote_write.default",
+		"",
+	} {
+		_, span := WrapTracer(tp, id).Tracer("test").Start(context.Background(), "span "+id)
+		span.End()
+	}
+
+	require.Equal(t, []string{
+		"span prometheus.scrape.default",
+		"span import.file.mod/prometheus.remote_write.default",
+		"span ",
+	}, spanNames(recorder.Ended()))
+}
+
+func TestTailSamplingOptions_Validate(t *testing.T) {
+	opts := DefaultTailSamplingOptions
+	require.NoError(t, opts.Validate())
+
+	opts.Errors = false
+	require.ErrorContains(t, opts.Validate(), "at least one of errors or min_duration must be set")
+
+	opts.MinDuration = time.Second
+	require.NoError(t, opts.Validate())
+
+	opts.MaxTraces = 0
+	require.ErrorContains(t, opts.Validate(), "max_traces must be greater than 0")
+}
+
+func spanNames(spans []tracesdk.ReadOnlySpan) []string {
+	names := make([]string, 0, len(spans))
+	for _, s := range spans {
+		names = append(names, s.Name())
+	}
+	return names
+}
@@ -5,6 +5,7 @@ package tracing
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 
@@ -27,6 +28,12 @@ var (
 		WriteTo:          []otelcol.Consumer{}, // Don't send spans anywhere.
 	}
 
+	DefaultTailSamplingOptions = TailSamplingOptions{
+		Errors:       true,
+		DecisionWait: 5 * time.Second,
+		MaxTraces:    10000,
+	}
+
 	DefaultJaegerRemoteSamplerOptions = JaegerRemoteSamplerOptions{
 		URL:             "http://127.0.0.1:5778/sampling",
 		MaxOperations:   256,
@@ -44,6 +51,14 @@ type Options struct {
 	// fraction.
 	Sampler SamplerOptions `alloy:"sampler,block,optional"`
 
+	// TailSampling optionally only keeps traces containing errors or slow
+	// spans. Tail sampling applies to traces kept by the samplers above.
+	TailSampling *TailSamplingOptions `alloy:"tail_sampling,block,optional"`
+
+	// Components optionally restricts which components send spans. Spans
+	// which aren't associated with a component are always sent.
+	Components []string `alloy:"components,attr,optional"`
+
 	// WriteTo holds a set of OpenTelemetry Collector consumers where internal
 	// traces should be sent.
 	WriteTo []otelcol.Consumer `alloy:"write_to,attr,optional"`
@@ -56,6 +71,21 @@ type SamplerOptions struct {
 	// must enforce that only one inner block is provided.
 }
 
+// TailSamplingOptions configures tail sampling of internal traces.
+type TailSamplingOptions struct {
+	// Errors keeps traces which contain at least one span with an error
+	// status.
+	Errors bool `alloy:"errors,attr,optional"`
+	// MinDuration keeps traces which contain at least one span lasting
+	// MinDuration or longer. 0 disables the check.
+	MinDuration time.Duration `alloy:"min_duration,attr,optional"`
+	// DecisionWait is the maximum time to buffer the spans of a trace before
+	// making a sampling decision.
+	DecisionWait time.Duration `alloy:"decision_wait,attr,optional"`
+	// MaxTraces is the maximum number of traces to buffer.
+	MaxTraces int `alloy:"max_traces,attr,optional"`
+}
+
 type JaegerRemoteSamplerOptions struct {
 	URL             string        `alloy:"url,attr,optional"`
 	MaxOperations   int           `alloy:"max_operations,attr,optional"`
@@ -67,6 +97,26 @@ func (opts *Options) SetToDefault() {
 	*opts = DefaultOptions
 }
 
+// SetToDefault implements syntax.Defaulter.
+func (opts *TailSamplingOptions) SetToDefault() {
+	*opts = DefaultTailSamplingOptions
+}
+
+// Validate implements syntax.Validator.
+func (opts *TailSamplingOptions) Validate() error {
+	switch {
+	case !opts.Errors && opts.MinDuration <= 0:
+		return fmt.Errorf("at least one of errors or min_duration must be set, otherwise all traces are dropped")
+	case opts.MinDuration < 0:
+		return fmt.Errorf("min_duration must not be negative")
+	case opts.DecisionWait <= 0:
+		return fmt.Errorf("decision_wait must be greater than 0")
+	case opts.MaxTraces <= 0:
+		return fmt.Errorf("max_traces must be greater than 0")
+	}
+	return nil
+}
+
 // SetToDefault implements syntax.Defaulter.
 func (opts *JaegerRemoteSamplerOptions) SetToDefault() {
 	*opts = DefaultJaegerRemoteSamplerOptions
@@ -79,6 +129,7 @@ type Tracer struct {
 	trace.TracerProvider
 	sampler *lazySampler
 	client  *client
+	filter  *spanFilter
 	exp     *otlptrace.Exporter
 	tp      *tracesdk.TracerProvider
 
@@ -111,9 +162,10 @@ func New(cfg Options) (*Tracer, error) {
 
 	shimClient := &client{}
 	exp := otlptrace.NewUnstarted(shimClient)
+	filter := newSpanFilter(tracesdk.NewBatchSpanProcessor(exp), time.Second)
 
 	tp := tracesdk.NewTracerProvider(
-		tracesdk.WithBatcher(exp),
+		tracesdk.WithSpanProcessor(filter),
 		tracesdk.WithSampler(tracesdk.ParentBased(&sampler)),
 		tracesdk.WithResource(res),
 	)
@@ -121,6 +173,7 @@ func New(cfg Options) (*Tracer, error) {
 	t := &Tracer{
 		sampler: &sampler,
 		client:  shimClient,
+		filter:  filter,
 		exp:     exp,
 		tp:      tp,
 	}
@@ -137,6 +190,7 @@ func (t *Tracer) Update(opts Options) error {
 	defer t.samplerMut.Unlock()
 
 	t.client.UpdateWriteTo(opts.WriteTo)
+	t.filter.Update(opts.Components, opts.TailSampling)
 
 	// Stop the previous instance of the Jaeger remote sampler if it exists. The
 	// sampler can still make sampling decisions after being closed; it just
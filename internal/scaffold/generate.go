@@ -0,0 +1,322 @@
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/grafana/alloy/internal/featuregate"
+)
+
+//go:embed templates/*.tmpl
+var templatesFS embed.FS
+
+var templates = template.Must(template.ParseFS(templatesFS, "templates/*.tmpl"))
+
+const (
+	// componentsPath is the directory of the component packages, relative to
+	// the root of the repository.
+	componentsPath = "internal/component"
+	// modulePath is the import path of the repository.
+	modulePath = "github.com/grafana/alloy"
+)
+
+// allPath is the file importing all the component packages, relative to the
+// root of the repository.
+var allPath = filepath.Join(componentsPath, "all", "all.go")
+
+// Generate writes the files of the component described by spec into the
+// repository at root, and imports its package from the package importing all
+// components. Existing files are never overwritten.
+//
+// Generate returns the paths of the files it created or updated.
+func Generate(spec *Spec, root string) ([]string, error) {
+	data, err := newTemplateData(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	allFile := filepath.Join(root, allPath)
+	if _, err := os.Stat(allFile); err != nil {
+		return nil, fmt.Errorf("%s doesn't look like the root of the repository: %w", root, err)
+	}
+
+	dir := filepath.Join(root, filepath.FromSlash(data.dir))
+	files := map[string]string{
+		data.Package + ".go":      "component.go.tmpl",
+		data.Package + "_test.go": "component_test.go.tmpl",
+	}
+	if len(spec.Metrics) > 0 {
+		files["metrics.go"] = "metrics.go.tmpl"
+	}
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		if _, err := os.Stat(filepath.Join(dir, name)); !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("%s already exists", filepath.Join(dir, name))
+		}
+	}
+
+	rendered := make(map[string][]byte, len(files))
+	for _, name := range names {
+		bb, err := render(files[name], data)
+		if err != nil {
+			return nil, err
+		}
+		rendered[name] = bb
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	var written []string
+	for _, name := range names {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, rendered[name], 0o644); err != nil {
+			return written, err
+		}
+		written = append(written, p)
+	}
+
+	updated, err := addImport(allFile, path.Join(modulePath, data.dir), spec.Name)
+	if err != nil {
+		return written, err
+	}
+	if updated {
+		written = append(written, allFile)
+	}
+	return written, nil
+}
+
+func render(name string, data *templateData) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := templates.ExecuteTemplate(&buf, name, data); err != nil {
+		return nil, fmt.Errorf("rendering %s: %w", name, err)
+	}
+	bb, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting %s: %w", name, err)
+	}
+	return bb, nil
+}
+
+// addImport adds a blank import of importPath to the Go file at path, next to
+// the other component packages. addImport returns false if the file already
+// imports importPath.
+func addImport(path, importPath, name string) (bool, error) {
+	bb, err := os.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	var (
+		lines    = strings.Split(string(bb), "\n")
+		prefix   = "\t_ \"" + modulePath + "/" + componentsPath + "/"
+		line     = fmt.Sprintf("\t_ %q // Import %s", importPath, name)
+		insertAt = -1
+	)
+	for i, l := range lines {
+		if !strings.HasPrefix(l, prefix) {
+			continue
+		}
+		existing, _, _ := strings.Cut(strings.TrimPrefix(l, "\t_ \""), "\"")
+		switch {
+		case existing == importPath:
+			return false, nil
+		case existing < importPath:
+			insertAt = i + 1
+		case insertAt == -1:
+			insertAt = i
+		}
+	}
+	if insertAt == -1 {
+		return false, fmt.Errorf("%s doesn't import any component package", path)
+	}
+
+	lines = slices.Insert(lines, insertAt, line)
+	formatted, err := format.Source([]byte(strings.Join(lines, "\n")))
+	if err != nil {
+		return false, fmt.Errorf("formatting %s: %w", path, err)
+	}
+	return true, os.WriteFile(path, formatted, 0o644)
+}
+
+// templateData holds the values used by the templates.
+type templateData struct {
+	Name       string
+	Package    string
+	Stability  string
+	Community  bool
+	StdImports []string
+	Imports    []string
+
+	Arguments   []templateField
+	Exports     []templateField
+	Metrics     []templateMetric
+	HasDefaults bool
+	TestConfig  string
+
+	// dir is the directory of the package, relative to the root of the
+	// repository.
+	dir string
+}
+
+type templateField struct {
+	GoName      string
+	GoType      string
+	Tag         string
+	Description string
+	Default     string
+}
+
+type templateMetric struct {
+	GoName string
+	Name   string
+	Type   string
+	Help   string
+}
+
+func newTemplateData(spec *Spec) (*templateData, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+
+	parts := strings.Split(spec.Name, ".")
+	if pkg := parts[len(parts)-1]; token.IsKeyword(pkg) {
+		return nil, fmt.Errorf("name %q: %q isn't a valid package name", spec.Name, pkg)
+	}
+	data := &templateData{
+		Name:      spec.Name,
+		Package:   parts[len(parts)-1],
+		Community: spec.Community,
+		dir:       path.Join(append([]string{componentsPath}, parts...)...),
+	}
+
+	if !spec.Community {
+		stability := featuregate.StabilityExperimental
+		if spec.Stability != "" {
+			if err := stability.Set(spec.Stability); err != nil {
+				return nil, err
+			}
+		}
+		data.Stability = stabilityIdentifiers[stability]
+	}
+
+	var (
+		stdImports = map[string]bool{"context": true, "sync": true}
+		imports    = map[string]bool{modulePath + "/internal/component": true}
+		testConfig strings.Builder
+	)
+	if !spec.Community {
+		imports[modulePath+"/internal/featuregate"] = true
+	}
+
+	newField := func(f Field) templateField {
+		ft := fieldTypes[f.Type]
+		if ft.pkg != "" {
+			if strings.Contains(ft.pkg, ".") {
+				imports[ft.pkg] = true
+			} else {
+				stdImports[ft.pkg] = true
+			}
+		}
+
+		tf := templateField{
+			GoName:      goName(f.Name, true),
+			GoType:      ft.goType,
+			Tag:         f.Name + ",attr",
+			Description: f.Description,
+		}
+		if f.Optional {
+			tf.Tag += ",optional"
+		}
+		if f.Default != "" {
+			// The default was validated by spec.Validate.
+			tf.Default, _ = ft.defaultLiteral(f.Default)
+		}
+		return tf
+	}
+
+	for _, f := range spec.Arguments {
+		tf := newField(f)
+		data.Arguments = append(data.Arguments, tf)
+		data.HasDefaults = data.HasDefaults || tf.Default != ""
+		if !f.Optional {
+			fmt.Fprintf(&testConfig, "\t%s = %s\n", f.Name, fieldTypes[f.Type].example)
+		}
+	}
+	for _, f := range spec.Exports {
+		data.Exports = append(data.Exports, newField(f))
+	}
+	if testConfig.Len() > 0 {
+		data.TestConfig = "\n" + testConfig.String()
+	}
+
+	metricPrefix := strings.Join(parts, "_") + "_"
+	for _, m := range spec.Metrics {
+		data.Metrics = append(data.Metrics, templateMetric{
+			GoName: goName(m.Name, false),
+			Name:   metricPrefix + m.Name,
+			Type:   metricTypes[m.Type],
+			Help:   m.Help,
+		})
+	}
+
+	data.StdImports = sortedKeys(stdImports)
+	data.Imports = sortedKeys(imports)
+	return data, nil
+}
+
+var stabilityIdentifiers = map[featuregate.Stability]string{
+	featuregate.StabilityExperimental:       "featuregate.StabilityExperimental",
+	featuregate.StabilityPublicPreview:      "featuregate.StabilityPublicPreview",
+	featuregate.StabilityGenerallyAvailable: "featuregate.StabilityGenerallyAvailable",
+}
+
+// initialisms are written in upper case in Go identifiers.
+var initialisms = map[string]bool{
+	"api": true, "cpu": true, "dns": true, "http": true, "https": true, "id": true,
+	"ip": true, "json": true, "sql": true, "tls": true, "ttl": true, "uri": true,
+	"url": true, "uuid": true,
+}
+
+// goName converts a snake case name to a Go identifier, which is exported if
+// exported is true.
+func goName(name string, exported bool) string {
+	var sb strings.Builder
+	for i, word := range strings.Split(name, "_") {
+		switch {
+		case word == "":
+		case i == 0 && !exported:
+			sb.WriteString(word)
+		case initialisms[word]:
+			sb.WriteString(strings.ToUpper(word))
+		default:
+			sb.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return sb.String()
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
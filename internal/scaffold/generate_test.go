@@ -0,0 +1,179 @@
+package scaffold
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testAllFile = `// Package all imports all known component packages.
+package all
+
+import (
+	_ "github.com/grafana/alloy/internal/component/discovery/aws" // Import discovery.aws.ec2 and discovery.aws.lightsail
+	_ "github.com/grafana/alloy/internal/component/local/file"    // Import local.file
+
+	_ "github.com/grafana/alloy/internal/util/otelfeaturegatefix" // Gracefully handle duplicate OTEL feature gates
+)
+`
+
+// newTestRoot returns a directory laid out like the root of the repository.
+func newTestRoot(t *testing.T) string {
+	root := t.TempDir()
+	allFile := filepath.Join(root, allPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(allFile), 0o755))
+	require.NoError(t, os.WriteFile(allFile, []byte(testAllFile), 0o644))
+	return root
+}
+
+func TestGenerate(t *testing.T) {
+	bb, err := os.ReadFile("testdata/widget/spec.alloy")
+	require.NoError(t, err)
+	spec, err := ParseSpec(bb)
+	require.NoError(t, err)
+
+	root := newTestRoot(t)
+	files, err := Generate(spec, root)
+	require.NoError(t, err)
+
+	dir := filepath.Join(root, "internal", "component", "example", "widget")
+	require.Equal(t, []string{
+		filepath.Join(dir, "metrics.go"),
+		filepath.Join(dir, "widget.go"),
+		filepath.Join(dir, "widget_test.go"),
+		filepath.Join(root, allPath),
+	}, files)
+
+	for _, name := range []string{"metrics.go", "widget.go", "widget_test.go"} {
+		expect, err := os.ReadFile(filepath.Join("testdata", "widget", name+".golden"))
+		require.NoError(t, err)
+		actual, err := os.ReadFile(filepath.Join(dir, name))
+		require.NoError(t, err)
+		require.Equal(t, string(expect), string(actual), name)
+	}
+
+	all, err := os.ReadFile(filepath.Join(root, allPath))
+	require.NoError(t, err)
+	require.Equal(t, `// Package all imports all known component packages.
+package all
+
+import (
+	_ "github.com/grafana/alloy/internal/component/discovery/aws"  // Import discovery.aws.ec2 and discovery.aws.lightsail
+	_ "github.com/grafana/alloy/internal/component/example/widget" // Import example.widget
+	_ "github.com/grafana/alloy/internal/component/local/file"     // Import local.file
+
+	_ "github.com/grafana/alloy/internal/util/otelfeaturegatefix" // Gracefully handle duplicate OTEL feature gates
+)
+`, string(all))
+
+	_, err = Generate(spec, root)
+	require.EqualError(t, err, filepath.Join(dir, "metrics.go")+" already exists")
+}
+
+func TestGenerate_Community(t *testing.T) {
+	spec, err := ParseSpec([]byte(`
+		name      = "zeta.gadget"
+		community = true
+	`))
+	require.NoError(t, err)
+
+	root := newTestRoot(t)
+	_, err = Generate(spec, root)
+	require.NoError(t, err)
+
+	bb, err := os.ReadFile(filepath.Join(root, "internal", "component", "zeta", "gadget", "gadget.go"))
+	require.NoError(t, err)
+	require.Contains(t, string(bb), "Community: true,")
+	require.NotContains(t, string(bb), "featuregate")
+
+	// Packages sorted after all the other component packages are imported
+	// last.
+	all, err := os.ReadFile(filepath.Join(root, allPath))
+	require.NoError(t, err)
+	require.Regexp(t, `component/local/file" +// Import local.file\n\t_ "github.com/grafana/alloy/internal/component/zeta/gadget" +// Import zeta.gadget\n\n`, string(all))
+}
+
+func TestParseSpec_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		spec   string
+		expect string
+	}{
+		{
+			name:   "missing namespace",
+			spec:   `name = "widget"`,
+			expect: `name "widget" must have a namespace, like example.widget`,
+		},
+		{
+			name:   "invalid identifier",
+			spec:   `name = "example.Widget"`,
+			expect: `name "example.Widget": identifier "Widget" must be lowercase and start with a letter`,
+		},
+		{
+			name: "community stability",
+			spec: `
+				name      = "example.widget"
+				community = true
+				stability = "experimental"
+			`,
+			expect: "community components don't have a stability level",
+		},
+		{
+			name: "unsupported type",
+			spec: `
+				name = "example.widget"
+				argument "targets" {
+					type = "list(map(string))"
+				}
+			`,
+			expect: `argument "targets": unsupported type "list(map(string))", must be one of bool, duration, float, int, list(string), map(string), secret, string`,
+		},
+		{
+			name: "default of required argument",
+			spec: `
+				name = "example.widget"
+				argument "timeout" {
+					type    = "duration"
+					default = "1m"
+				}
+			`,
+			expect: `argument "timeout": only optional arguments can have a default`,
+		},
+		{
+			name: "invalid default",
+			spec: `
+				name = "example.widget"
+				argument "timeout" {
+					type     = "duration"
+					optional = true
+					default  = "soon"
+				}
+			`,
+			expect: `argument "timeout": invalid default "soon": time: invalid duration "soon"`,
+		},
+		{
+			name: "duplicate metric",
+			spec: `
+				name = "example.widget"
+				metric "polls_total" {
+					type = "counter"
+					help = "Total number of polls."
+				}
+				metric "polls_total" {
+					type = "counter"
+					help = "Total number of polls."
+				}
+			`,
+			expect: `metric "polls_total" is declared more than once`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := ParseSpec([]byte(tc.spec))
+			require.ErrorContains(t, err, tc.expect)
+		})
+	}
+}
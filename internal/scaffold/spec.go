@@ -0,0 +1,264 @@
+// Package scaffold generates the boilerplate of new components from a short
+// specification.
+package scaffold
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/syntax"
+)
+
+// Spec describes a component to generate. Specs are written in the Alloy
+// syntax:
+//
+//	name      = "example.widget"
+//	stability = "experimental"
+//
+//	argument "url" {
+//		type = "string"
+//	}
+//
+//	argument "poll_frequency" {
+//		type     = "duration"
+//		optional = true
+//		default  = "1m"
+//	}
+//
+//	export "content" {
+//		type = "string"
+//	}
+//
+//	metric "polls_total" {
+//		type = "counter"
+//		help = "Total number of polls."
+//	}
+type Spec struct {
+	// Name is the name of the component, like example.widget.
+	Name string `alloy:"name,attr"`
+	// Stability is the stability level of the component. Defaults to
+	// experimental for components which aren't community components.
+	Stability string `alloy:"stability,attr,optional"`
+	// Community is true for community components.
+	Community bool `alloy:"community,attr,optional"`
+
+	Arguments []Field  `alloy:"argument,block,optional"`
+	Exports   []Field  `alloy:"export,block,optional"`
+	Metrics   []Metric `alloy:"metric,block,optional"`
+}
+
+// Field describes an argument or an export of a component.
+type Field struct {
+	Name        string `alloy:",label"`
+	Type        string `alloy:"type,attr"`
+	Description string `alloy:"description,attr,optional"`
+	Optional    bool   `alloy:"optional,attr,optional"`
+	// Default is the default value of an optional argument, written as a
+	// string, like "1m" for a duration.
+	Default string `alloy:"default,attr,optional"`
+}
+
+// Metric describes a metric exposed by a component.
+type Metric struct {
+	Name string `alloy:",label"`
+	Type string `alloy:"type,attr"`
+	Help string `alloy:"help,attr"`
+}
+
+// ParseSpec parses a specification written in the Alloy syntax.
+func ParseSpec(bb []byte) (*Spec, error) {
+	var s Spec
+	if err := syntax.Unmarshal(bb, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+var identifierRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// fieldType describes how a type of a spec field is represented in Go.
+type fieldType struct {
+	goType string
+	// pkg is the import path of the package holding goType, if any.
+	pkg string
+	// example is an example value in the Alloy syntax, used in tests.
+	example string
+	// defaultLiteral converts a default value to a Go literal. Types which
+	// don't support defaults leave it nil.
+	defaultLiteral func(string) (string, error)
+}
+
+var fieldTypes = map[string]fieldType{
+	"string": {
+		goType:         "string",
+		example:        `"example"`,
+		defaultLiteral: func(s string) (string, error) { return strconv.Quote(s), nil },
+	},
+	"bool": {
+		goType:  "bool",
+		example: "true",
+		defaultLiteral: func(s string) (string, error) {
+			b, err := strconv.ParseBool(s)
+			return strconv.FormatBool(b), err
+		},
+	},
+	"int": {
+		goType:  "int",
+		example: "1",
+		defaultLiteral: func(s string) (string, error) {
+			i, err := strconv.Atoi(s)
+			return strconv.Itoa(i), err
+		},
+	},
+	"float": {
+		goType:  "float64",
+		example: "1.5",
+		defaultLiteral: func(s string) (string, error) {
+			f, err := strconv.ParseFloat(s, 64)
+			return strconv.FormatFloat(f, 'g', -1, 64), err
+		},
+	},
+	"duration": {
+		goType:  "time.Duration",
+		pkg:     "time",
+		example: `"1m"`,
+		defaultLiteral: func(s string) (string, error) {
+			d, err := time.ParseDuration(s)
+			return durationLiteral(d), err
+		},
+	},
+	"secret": {
+		goType:  "alloytypes.Secret",
+		pkg:     "github.com/grafana/alloy/syntax/alloytypes",
+		example: `"example"`,
+	},
+	"list(string)": {
+		goType:  "[]string",
+		example: `["example"]`,
+	},
+	"map(string)": {
+		goType:  "map[string]string",
+		example: `{ key = "value" }`,
+	},
+}
+
+// FieldTypes returns the supported types of arguments and exports.
+func FieldTypes() []string {
+	types := make([]string, 0, len(fieldTypes))
+	for t := range fieldTypes {
+		types = append(types, t)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// metricTypes maps the supported types of metrics to their Go type.
+var metricTypes = map[string]string{
+	"counter":   "Counter",
+	"gauge":     "Gauge",
+	"histogram": "Histogram",
+}
+
+// Validate implements syntax.Validator.
+func (s *Spec) Validate() error {
+	parts := strings.Split(s.Name, ".")
+	if len(parts) < 2 {
+		return fmt.Errorf("name %q must have a namespace, like example.widget", s.Name)
+	}
+	for _, part := range parts {
+		if !identifierRegex.MatchString(part) {
+			return fmt.Errorf("name %q: identifier %q must be lowercase and start with a letter", s.Name, part)
+		}
+	}
+
+	switch {
+	case s.Community && s.Stability != "":
+		return fmt.Errorf("community components don't have a stability level")
+	case s.Stability != "":
+		var stability featuregate.Stability
+		if err := stability.Set(s.Stability); err != nil {
+			return err
+		}
+	}
+
+	if err := validateFields("argument", s.Arguments, true); err != nil {
+		return err
+	}
+	if err := validateFields("export", s.Exports, false); err != nil {
+		return err
+	}
+
+	names := map[string]bool{}
+	for _, m := range s.Metrics {
+		if !identifierRegex.MatchString(m.Name) {
+			return fmt.Errorf("metric %q: name must be lowercase and start with a letter", m.Name)
+		}
+		if names[m.Name] {
+			return fmt.Errorf("metric %q is declared more than once", m.Name)
+		}
+		names[m.Name] = true
+		if _, ok := metricTypes[m.Type]; !ok {
+			return fmt.Errorf("metric %q: unsupported type %q, must be one of counter, gauge, histogram", m.Name, m.Type)
+		}
+	}
+	return nil
+}
+
+func validateFields(kind string, fields []Field, arguments bool) error {
+	names := map[string]bool{}
+	for _, f := range fields {
+		if !identifierRegex.MatchString(f.Name) {
+			return fmt.Errorf("%s %q: name must be lowercase and start with a letter", kind, f.Name)
+		}
+		if names[f.Name] {
+			return fmt.Errorf("%s %q is declared more than once", kind, f.Name)
+		}
+		names[f.Name] = true
+
+		ft, ok := fieldTypes[f.Type]
+		if !ok {
+			return fmt.Errorf("%s %q: unsupported type %q, must be one of %s", kind, f.Name, f.Type, strings.Join(FieldTypes(), ", "))
+		}
+		if !arguments && (f.Optional || f.Default != "") {
+			return fmt.Errorf("%s %q: exports can't be optional or have a default", kind, f.Name)
+		}
+		if f.Default == "" {
+			continue
+		}
+		if !f.Optional {
+			return fmt.Errorf("%s %q: only optional arguments can have a default", kind, f.Name)
+		}
+		if ft.defaultLiteral == nil {
+			return fmt.Errorf("%s %q: arguments of type %s can't have a default", kind, f.Name, f.Type)
+		}
+		if _, err := ft.defaultLiteral(f.Default); err != nil {
+			return fmt.Errorf("%s %q: invalid default %q: %w", kind, f.Name, f.Default, err)
+		}
+	}
+	return nil
+}
+
+// durationLiteral returns the Go expression of d using the largest unit which
+// divides it, like 5 * time.Minute.
+func durationLiteral(d time.Duration) string {
+	units := []struct {
+		d    time.Duration
+		name string
+	}{
+		{time.Hour, "time.Hour"},
+		{time.Minute, "time.Minute"},
+		{time.Second, "time.Second"},
+		{time.Millisecond, "time.Millisecond"},
+	}
+	for _, u := range units {
+		if d != 0 && d%u.d == 0 {
+			return fmt.Sprintf("%d * %s", d/u.d, u.name)
+		}
+	}
+	return fmt.Sprintf("time.Duration(%d)", d)
+}
@@ -12,8 +12,10 @@ import (
 	"net"
 	"net/http"
 	"os"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-kit/log"
@@ -40,8 +42,8 @@ const (
 	// ServiceName defines the name used for the cluster service.
 	ServiceName = "cluster"
 
-	// tokensPerNode is used to decide how many tokens each node should be given in
-	// the hash ring. All nodes must use the same value, otherwise they will have
+	// tokensPerNode is the default number of tokens each node is given in the
+	// hash ring. All nodes must use the same value, otherwise they will have
 	// different views of the ring and assign work differently.
 	//
 	// Using 512 tokens strikes a good balance between distribution accuracy and
@@ -87,6 +89,12 @@ type Options struct {
 	ClusterName            string        // Name to prevent nodes without this identifier from joining the cluster.
 	MinimumClusterSize     int           // Minimum cluster size before admitting traffic to components that use clustering.
 	MinimumSizeWaitTimeout time.Duration // Maximum duration to wait for minimum cluster size before proceeding; 0 means no timeout.
+	DrainTimeout           time.Duration // Maximum duration to hand off work to peers before leaving the cluster; 0 disables draining.
+	HashingAlgorithm       string        // Algorithm used to distribute work across nodes; defaults to HashingAlgorithmRing.
+	RingTokens             int           // Number of tokens per node for HashingAlgorithmRing; defaults to 512.
+	ReplicationFactor      int           // Minimum number of nodes which own each key; defaults to 1.
+	PartitionProtection    bool          // Stop admitting traffic while the node is in a minority partition of the cluster.
+	Zone                   string        // Availability zone of the node; work in a zone is preferably assigned to the nodes in that zone.
 
 	// Function to discover peers to join. If this function is nil or returns an
 	// empty slice, no peers will be joined.
@@ -99,14 +107,23 @@ type Service struct {
 	tracer trace.TracerProvider
 	opts   Options
 
-	sharder shard.Sharder
-	node    *ckit.Node
-	randGen *rand.Rand
+	sharder    shard.Sharder
+	zones      *zoneSharder
+	node       *ckit.Node
+	httpClient *http.Client
+	randGen    *rand.Rand
 
 	// alloyCluster is given to components via calls to Data() and implements Cluster.
 	alloyCluster *alloyCluster
 	// notifyClusterChange is used to signal that cluster has changed, and we need to notify all the components
 	notifyClusterChange chan struct{}
+	// peersChanged is used to signal that the zones of new peers need to be
+	// fetched.
+	peersChanged chan struct{}
+	// draining is set once the node started handing off its work to peers.
+	// Components aren't notified of cluster changes while draining so they
+	// keep processing their work until the node leaves the cluster.
+	draining atomic.Bool
 }
 
 // Component is a component which subscribes to clustering updates.
@@ -121,6 +138,21 @@ type Component interface {
 	NotifyClusterChange()
 }
 
+// DrainComponent is a Component which needs to coordinate with the cluster
+// before the local node leaves it.
+type DrainComponent interface {
+	Component
+
+	// Drain is called once the local node has announced to its peers that it's
+	// leaving the cluster. Implementations should keep processing their
+	// current work and block until peers had the opportunity to take it over,
+	// or until ctx is canceled.
+	//
+	// Implementations should return immediately if they are configured to not
+	// utilize clustering.
+	Drain(ctx context.Context)
+}
+
 // ComponentBlock holds common arguments for clustering settings within a
 // component. ComponentBlock is intended to be exposed as a block called
 // "clustering".
@@ -146,11 +178,21 @@ func New(opts Options) (*Service, error) {
 		t = noop.NewTracerProvider()
 	}
 
+	// Validate the hashing algorithm once, so that the sharders of each zone
+	// can be created without errors.
+	if _, err := newSharder(opts.HashingAlgorithm, opts.RingTokens); err != nil {
+		return nil, err
+	}
+	sharder := newZoneSharder(func() shard.Sharder {
+		s, _ := newSharder(opts.HashingAlgorithm, opts.RingTokens)
+		return s
+	}, opts.Zone)
+
 	ckitConfig := ckit.Config{
 		Name:          opts.NodeName,
 		AdvertiseAddr: opts.AdvertiseAddress,
 		Log:           l,
-		Sharder:       shard.Ring(tokensPerNode),
+		Sharder:       sharder,
 		Label:         opts.ClusterName,
 		EnableTLS:     opts.EnableTLS,
 	}
@@ -199,9 +241,12 @@ func New(opts Options) (*Service, error) {
 		opts:   opts,
 
 		sharder:             ckitConfig.Sharder,
+		zones:               sharder,
 		node:                node,
+		httpClient:          httpClient,
 		randGen:             rand.New(rand.NewSource(time.Now().UnixNano())),
 		notifyClusterChange: make(chan struct{}, 1),
+		peersChanged:        make(chan struct{}, 1),
 	}
 	s.alloyCluster = newAlloyCluster(ckitConfig.Sharder, s.triggerClusterChangeNotification, opts, l)
 
@@ -266,7 +311,12 @@ func (s *Service) Definition() service.Definition {
 // ServiceHandler returns the service handler for the clustering service. The
 // resulting handler always returns 404 when clustering is disabled.
 func (s *Service) ServiceHandler(_ service.Host) (base string, handler http.Handler) {
-	base, handler = s.node.Handler()
+	base, transport := s.node.Handler()
+
+	mux := http.NewServeMux()
+	mux.Handle(base, transport)
+	mux.HandleFunc("GET "+base+zonePath, s.handleZone)
+	handler = mux
 
 	if !s.opts.EnableClustering {
 		handler = http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
@@ -305,6 +355,10 @@ func (s *Service) Run(ctx context.Context, host service.Host) error {
 			return false
 		}
 		s.triggerClusterChangeNotification()
+		select {
+		case s.peersChanged <- struct{}{}:
+		default:
+		}
 		return true
 	}))
 
@@ -352,6 +406,14 @@ func (s *Service) Run(ctx context.Context, host service.Host) error {
 		}
 	}()
 
+	if s.opts.EnableClustering {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			s.resolveZones(ctx)
+		}()
+	}
+
 	if s.opts.EnableClustering && s.opts.RejoinInterval > 0 {
 		wg.Add(1)
 
@@ -367,17 +429,22 @@ func (s *Service) Run(ctx context.Context, host service.Host) error {
 					return
 
 				case <-t.C:
-					peers, err := s.getRandomPeers()
+					discovered, err := s.discoverPeers()
 					if err != nil {
 						level.Warn(s.log).Log("msg", "failed to refresh list of peers", "err", err)
 						continue
 					}
+					peers := s.randomPeers(discovered)
 					s.logPeers("rejoining peers", peers)
 
 					if err := s.node.Start(peers); err != nil {
 						level.Error(s.log).Log("msg", "failed to rejoin list of peers", "err", err)
 						continue
 					}
+
+					// Peers which are still missing after rejoining may be on
+					// the other side of a partition.
+					s.alloyCluster.checkPartition(discovered)
 				}
 			}
 		}()
@@ -387,6 +454,57 @@ func (s *Service) Run(ctx context.Context, host service.Host) error {
 	return nil
 }
 
+// Drain hands off the work of the local node to its peers before it leaves
+// the cluster. Drain announces the departure of the node to its peers, and
+// then waits for all components implementing [DrainComponent] to finish
+// draining, for at most the configured drain timeout.
+//
+// Drain is a no-op if clustering or draining is disabled. Drain must be called
+// before the components of host are stopped.
+func (s *Service) Drain(ctx context.Context, host component.Provider) error {
+	if !s.opts.EnableClustering || s.opts.DrainTimeout <= 0 {
+		return nil
+	}
+	if s.node.CurrentState() != peer.StateParticipant {
+		// The node doesn't own any work that needs to be handed off.
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.opts.DrainTimeout)
+	defer cancel()
+
+	level.Info(s.log).Log("msg", "draining cluster node", "timeout", s.opts.DrainTimeout)
+	start := time.Now()
+
+	// Stop notifying components about cluster changes, so they keep
+	// processing their current work while peers take it over.
+	s.draining.Store(true)
+
+	// Moving to the Terminating state signals peers that they should take
+	// over the work owned by this node.
+	if err := s.node.ChangeState(ctx, peer.StateTerminating); err != nil {
+		return fmt.Errorf("failed to announce departure from the cluster: %w", err)
+	}
+
+	var wg sync.WaitGroup
+	for _, comp := range component.GetAllComponents(host, component.InfoOptions{}) {
+		drainComponent, ok := comp.Component.(DrainComponent)
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			drainComponent.Drain(ctx)
+		}()
+	}
+	wg.Wait()
+
+	level.Info(s.log).Log("msg", "drained cluster node", "duration", time.Since(start))
+	return nil
+}
+
 func (s *Service) notifyComponentsOfClusterChanges(ctx context.Context, limiter *rate.Limiter, host service.Host) {
 	tracer := s.tracer.Tracer("")
 	spanCtx, span := tracer.Start(ctx, "NotifyClusterChange", trace.WithSpanKind(trace.SpanKindInternal))
@@ -414,6 +532,12 @@ func (s *Service) notifyComponentsOfClusterChanges(ctx context.Context, limiter
 	span.SetAttributes(attribute.Int("peers_count", len(peers)))
 	span.SetAttributes(attribute.Int("minimum_cluster_size", s.opts.MinimumClusterSize))
 
+	if s.draining.Load() {
+		// Components keep their current work while the node is draining.
+		span.End()
+		return
+	}
+
 	// Notify all components about the clustering change.
 	components := component.GetAllComponents(host, component.InfoOptions{})
 	for _, comp := range components {
@@ -446,6 +570,15 @@ func (s *Service) triggerClusterChangeNotification() {
 }
 
 func (s *Service) getRandomPeers() ([]string, error) {
+	peers, err := s.discoverPeers()
+	if err != nil {
+		return nil, err
+	}
+	return s.randomPeers(peers), nil
+}
+
+// discoverPeers returns all the discovered peers.
+func (s *Service) discoverPeers() ([]string, error) {
 	if !s.opts.EnableClustering || s.opts.DiscoverPeers == nil {
 		return nil, nil
 	}
@@ -461,17 +594,23 @@ func (s *Service) getRandomPeers() ([]string, error) {
 		"peers_count", len(peers),
 		"peers", strings.Join(peers, ","),
 	)
+	return peers, nil
+}
 
+// randomPeers returns a random subset of peers to join, limited by the
+// configured maximum number of peers to join.
+func (s *Service) randomPeers(peers []string) []string {
 	// Here we return the entire list because we can't take a subset.
 	if s.opts.ClusterMaxJoinPeers == 0 || len(peers) < s.opts.ClusterMaxJoinPeers {
-		return peers, nil
+		return peers
 	}
 
-	// We shuffle the list and return only a subset of the peers.
+	// We shuffle a copy of the list and return only a subset of the peers.
+	peers = slices.Clone(peers)
 	s.randGen.Shuffle(len(peers), func(i, j int) {
 		peers[i], peers[j] = peers[j], peers[i]
 	})
-	return peers[:s.opts.ClusterMaxJoinPeers], nil
+	return peers[:s.opts.ClusterMaxJoinPeers]
 }
 
 func (s *Service) stop() {
@@ -481,12 +620,12 @@ func (s *Service) stop() {
 	defer cancel()
 
 	// The node is going away. We move to the Terminating state to signal
-	// that we should not be owners for write hashing operations anymore.
-	//
-	// TODO(rfratto): should we enter terminating state earlier to allow for
-	// some kind of hand-off between components?
-	if err := s.node.ChangeState(ctx, peer.StateTerminating); err != nil {
-		level.Error(s.log).Log("msg", "failed to change state to Terminating", "err", err)
+	// that we should not be owners for write hashing operations anymore. The
+	// node may already be Terminating if it was drained.
+	if s.node.CurrentState() != peer.StateTerminating {
+		if err := s.node.ChangeState(ctx, peer.StateTerminating); err != nil {
+			level.Error(s.log).Log("msg", "failed to change state to Terminating", "err", err)
+		}
 	}
 
 	if err := s.node.Stop(); err != nil {
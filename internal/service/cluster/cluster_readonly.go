@@ -1,6 +1,8 @@
 package cluster
 
 import (
+	"context"
+	"net"
 	"sync"
 	"time"
 
@@ -51,9 +53,37 @@ type Cluster interface {
 	// - there is no minimum size requirement specified
 	// - there is a minimum size requirement and the cluster size is >= that size
 	// - there is a minimum size requirement and cluster size is too small, but the configured wait deadline has passed.
+	// When partition protection is enabled, the cluster is never ready while the local node is in a minority partition.
 	Ready() bool
 }
 
+// ReplicatedCluster is a Cluster which can assign the same work to several
+// peers.
+type ReplicatedCluster interface {
+	Cluster
+
+	// ReplicationFactor returns the number of owners to look up for work which
+	// is replicated across peers, like discovery targets. It's the configured
+	// replication factor, capped by the number of peers eligible for op, and
+	// is at least 1. The first owner of a key never depends on the number of
+	// owners.
+	ReplicationFactor(op shard.Op) int
+}
+
+// ZoneCluster is a Cluster which is aware of the availability zones of its
+// peers.
+type ZoneCluster interface {
+	Cluster
+
+	// LookupZone is like Lookup, but prefers the peers in the given zone. The
+	// owners are looked up among all peers when zone is empty or doesn't have
+	// enough eligible peers.
+	LookupZone(key shard.Key, zone string, replicationFactor int, op shard.Op) ([]peer.Peer, error)
+
+	// PeerZone returns the zone of a peer, or an empty string if it's unknown.
+	PeerZone(p peer.Peer) string
+}
+
 // alloyCluster implements the Cluster interface and manages the admission control logic.
 type alloyCluster struct {
 	log     log.Logger
@@ -62,13 +92,27 @@ type alloyCluster struct {
 
 	clusterChangeCallback func()
 	clusterReadyGauge     prometheus.Gauge
+	partitionMetrics      *partitionMetrics
 
 	rwMutex       sync.RWMutex
 	deadlineTimer *time.Timer
 	clusterState  clusterState
+
+	// missingSince holds when each discovered address was first found missing
+	// from the cluster. partitioned is set when partition protection is
+	// enabled and the local node is in a minority partition.
+	missingSince map[string]time.Time
+	partitioned  bool
+
+	// Overridden in tests.
+	now        func() time.Time
+	lookupHost func(ctx context.Context, host string) ([]string, error)
 }
 
-var _ Cluster = (*alloyCluster)(nil)
+var (
+	_ ReplicatedCluster = (*alloyCluster)(nil)
+	_ ZoneCluster       = (*alloyCluster)(nil)
+)
 
 func newAlloyCluster(sharder shard.Sharder, clusterChangeCallback func(), opts Options, log log.Logger) *alloyCluster {
 	c := &alloyCluster{
@@ -76,6 +120,10 @@ func newAlloyCluster(sharder shard.Sharder, clusterChangeCallback func(), opts O
 		sharder:               sharder,
 		opts:                  opts,
 		clusterChangeCallback: clusterChangeCallback,
+		partitionMetrics:      newPartitionMetrics(opts.ClusterName),
+		missingSince:          make(map[string]time.Time),
+		now:                   time.Now,
+		lookupHost:            net.DefaultResolver.LookupHost,
 	}
 
 	c.clusterReadyGauge = prometheus.NewGauge(prometheus.GaugeOpts{
@@ -107,6 +155,12 @@ func newAlloyCluster(sharder shard.Sharder, clusterChangeCallback func(), opts O
 		if err := opts.Metrics.Register(c.clusterReadyGauge); err != nil {
 			level.Warn(log).Log("msg", "failed to register cluster ready metric", "err", err)
 		}
+
+		for _, collector := range c.partitionMetrics.collectors() {
+			if err := opts.Metrics.Register(collector); err != nil {
+				level.Warn(log).Log("msg", "failed to register cluster partition metric", "err", err)
+			}
+		}
 	}
 
 	// For consistency, set cluster to always ready when clustering is disabled or no minimum size is set.
@@ -129,21 +183,78 @@ func (c *alloyCluster) Lookup(key shard.Key, replicationFactor int, op shard.Op)
 	return c.sharder.Lookup(key, replicationFactor, op)
 }
 
+func (c *alloyCluster) LookupZone(key shard.Key, zone string, replicationFactor int, op shard.Op) ([]peer.Peer, error) {
+	zs, ok := c.sharder.(*zoneSharder)
+	if !ok {
+		return c.Lookup(key, replicationFactor, op)
+	}
+	return zs.lookupZone(key, zone, replicationFactor, op)
+}
+
+func (c *alloyCluster) PeerZone(p peer.Peer) string {
+	zs, ok := c.sharder.(*zoneSharder)
+	if !ok {
+		return ""
+	}
+	return zs.zone(p)
+}
+
+func (c *alloyCluster) ReplicationFactor(op shard.Op) int {
+	if c.opts.ReplicationFactor <= 1 {
+		return 1
+	}
+	return max(1, min(c.opts.ReplicationFactor, c.eligiblePeers(op)))
+}
+
+// eligiblePeers returns the number of peers which can own keys for op.
+func (c *alloyCluster) eligiblePeers(op shard.Op) int {
+	peers := c.sharder.Peers()
+	if op == shard.OpRead {
+		return len(peers)
+	}
+
+	var count int
+	for _, p := range peers {
+		if p.State == peer.StateParticipant {
+			count++
+		}
+	}
+	return count
+}
+
 func (c *alloyCluster) Peers() []peer.Peer {
 	return c.sharder.Peers()
 }
 
 func (c *alloyCluster) Ready() bool {
-	// Lock-free path: if clustering is disabled or no minimum size is set, the cluster is always ready.
-	if !c.opts.EnableClustering || c.opts.MinimumClusterSize == 0 {
+	// Lock-free path: if clustering is disabled or no minimum size or partition protection is set, the cluster is
+	// always ready.
+	if !c.opts.EnableClustering || (c.opts.MinimumClusterSize == 0 && !c.opts.PartitionProtection) {
 		return true
 	}
 
 	c.rwMutex.RLock()
 	defer c.rwMutex.RUnlock()
+	return c.readyLocked()
+}
+
+// readyLocked returns whether the cluster is ready to admit traffic. rwMutex must be locked by the caller.
+func (c *alloyCluster) readyLocked() bool {
+	if c.partitioned {
+		return false
+	}
 	return c.clusterState == stateReady || c.clusterState == stateDeadlinePassed
 }
 
+// updateReadyGauge updates the cluster ready gauge from the current state. rwMutex must be locked by the caller.
+func (c *alloyCluster) updateReadyGauge() {
+	if c.readyLocked() {
+		c.clusterReadyGauge.Set(1)
+	} else {
+		c.clusterReadyGauge.Set(0)
+	}
+}
+
 func (c *alloyCluster) updateReadyState() {
 	c.rwMutex.Lock()
 	defer c.rwMutex.Unlock()
@@ -170,7 +281,7 @@ func (c *alloyCluster) transitionToStateReady() {
 		return
 	}
 	c.clusterState = stateReady
-	c.clusterReadyGauge.Set(1)
+	c.updateReadyGauge()
 
 	// Stop the deadline timer if it was running
 	if c.deadlineTimer != nil {
@@ -190,7 +301,7 @@ func (c *alloyCluster) transitionToStateNotReady() {
 		return
 	}
 	c.clusterState = stateNotReady
-	c.clusterReadyGauge.Set(0)
+	c.updateReadyGauge()
 
 	// Restart the deadline timer if it is configured and we just transitioned to not ready
 	if c.opts.MinimumSizeWaitTimeout != 0 {
@@ -226,7 +337,7 @@ func (c *alloyCluster) transitionToStateDeadlinePassed() {
 		return
 	}
 	c.clusterState = stateDeadlinePassed
-	c.clusterReadyGauge.Set(1)
+	c.updateReadyGauge()
 
 	level.Warn(c.log).Log(
 		"msg", "deadline passed, marking cluster as ready to admit traffic",
@@ -1,6 +1,7 @@
 package cluster
 
 import (
+	"context"
 	"fmt"
 	"math/rand"
 	"os"
@@ -13,6 +14,8 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/atomic"
+
+	"github.com/grafana/alloy/internal/component"
 )
 
 func TestGetPeers(t *testing.T) {
@@ -266,6 +269,86 @@ func TestAdmitTrafficSequence_NoDeadline(t *testing.T) {
 	s.alloyCluster.shutdown()
 }
 
+func TestDrain(t *testing.T) {
+	s, err := New(Options{
+		Log:              log.NewNopLogger(),
+		EnableClustering: true,
+		NodeName:         "node-a",
+		AdvertiseAddress: "127.0.0.1:12345",
+		DrainTimeout:     time.Second,
+	})
+	require.NoError(t, err)
+
+	require.NoError(t, s.node.Start(nil))
+	defer func() { _ = s.node.Stop() }()
+	require.NoError(t, s.ChangeState(context.Background(), peer.StateParticipant))
+
+	drained := &mockDrainComponent{}
+	provider := mockProvider{components: []*component.Info{
+		{ID: component.ID{LocalID: "drain.component"}, Component: drained},
+		{ID: component.ID{LocalID: "other.component"}, Component: &mockComponent{}},
+	}}
+
+	require.NoError(t, s.Drain(context.Background(), provider))
+	require.True(t, drained.drained.Load())
+	require.Equal(t, peer.StateTerminating, s.node.CurrentState())
+	require.True(t, s.draining.Load())
+}
+
+func TestDrain_Disabled(t *testing.T) {
+	s, err := New(Options{
+		Log:              log.NewNopLogger(),
+		EnableClustering: true,
+		NodeName:         "node-a",
+		AdvertiseAddress: "127.0.0.1:12345",
+	})
+	require.NoError(t, err)
+
+	drained := &mockDrainComponent{}
+	provider := mockProvider{components: []*component.Info{
+		{ID: component.ID{LocalID: "drain.component"}, Component: drained},
+	}}
+
+	require.NoError(t, s.Drain(context.Background(), provider))
+	require.False(t, drained.drained.Load())
+	require.False(t, s.draining.Load())
+}
+
+type mockProvider struct {
+	components []*component.Info
+}
+
+func (m mockProvider) GetComponent(id component.ID, _ component.InfoOptions) (*component.Info, error) {
+	return nil, component.ErrComponentNotFound
+}
+
+func (m mockProvider) ListComponents(moduleID string, _ component.InfoOptions) ([]*component.Info, error) {
+	if moduleID != "" {
+		return nil, component.ErrModuleNotFound
+	}
+	return m.components, nil
+}
+
+type mockComponent struct{}
+
+func (*mockComponent) Run(ctx context.Context) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (*mockComponent) Update(_ component.Arguments) error { return nil }
+
+func (*mockComponent) NotifyClusterChange() {}
+
+type mockDrainComponent struct {
+	mockComponent
+	drained atomic.Bool
+}
+
+func (m *mockDrainComponent) Drain(_ context.Context) {
+	m.drained.Store(true)
+}
+
 func updatePeers(service *Service, sharder *mockSharder) {
 	service.sharder = sharder
 	service.alloyCluster.sharder = sharder
@@ -0,0 +1,168 @@
+package cluster
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+const (
+	// missingPeerGracePeriod is how long a discovered peer must be missing
+	// from the cluster before a partition is suspected. It gives new peers
+	// time to join the cluster through gossip, and gives the DNS records of
+	// peers which left the cluster time to expire.
+	missingPeerGracePeriod = 5 * time.Minute
+
+	// resolveTimeout is the maximum duration of resolving the discovered
+	// addresses during a partition check.
+	resolveTimeout = 5 * time.Second
+)
+
+// partitionMetrics holds the metrics reported by partition checks.
+type partitionMetrics struct {
+	discoveredPeers    prometheus.Gauge
+	missingPeers       prometheus.Gauge
+	partitionSuspected prometheus.Gauge
+}
+
+func newPartitionMetrics(clusterName string) *partitionMetrics {
+	constLabels := prometheus.Labels{"cluster_name": clusterName}
+	return &partitionMetrics{
+		discoveredPeers: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "cluster_discovered_peers",
+			Help:        "Number of peers found by the last peer discovery.",
+			ConstLabels: constLabels,
+		}),
+		missingPeers: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "cluster_discovered_peers_missing",
+			Help:        "Number of peers found by the last peer discovery which aren't part of the cluster seen by the local node.",
+			ConstLabels: constLabels,
+		}),
+		partitionSuspected: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name:        "cluster_partition_suspected",
+			Help:        "Reports 1 when the local node may be part of a partition of the cluster, 0 otherwise.",
+			ConstLabels: constLabels,
+		}),
+	}
+}
+
+func (m *partitionMetrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.discoveredPeers, m.missingPeers, m.partitionSuspected}
+}
+
+// checkPartition compares the addresses of discovered peers with the peers
+// of the cluster seen by the local node. When discovered peers are missing
+// from the cluster for longer than missingPeerGracePeriod, the cluster may be
+// split into several partitions, each of them believing it owns all the work.
+//
+// When partition protection is enabled, the cluster isn't ready to admit
+// traffic while the local node is part of a partition holding at most half
+// of the known peers, so that only the majority partition keeps distributing
+// work.
+func (c *alloyCluster) checkPartition(discovered []string) {
+	members := c.sharder.Peers()
+
+	memberAddrs := make(map[string]struct{}, len(members))
+	for _, p := range members {
+		memberAddrs[p.Addr] = struct{}{}
+	}
+
+	// Discovered addresses may be hostnames, like the targets of SRV records,
+	// while peers advertise IP addresses. A discovered peer is part of the
+	// cluster if any of its addresses is.
+	var missingAddrs []string
+	for _, addr := range discovered {
+		found := false
+		for _, resolved := range c.resolveAddr(addr) {
+			if _, ok := memberAddrs[resolved]; ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missingAddrs = append(missingAddrs, addr)
+		}
+	}
+
+	c.partitionMetrics.discoveredPeers.Set(float64(len(discovered)))
+	c.partitionMetrics.missingPeers.Set(float64(len(missingAddrs)))
+
+	c.rwMutex.Lock()
+	now := c.now()
+	missingSince := make(map[string]time.Time, len(missingAddrs))
+	var missing int
+	for _, addr := range missingAddrs {
+		since, ok := c.missingSince[addr]
+		if !ok {
+			since = now
+		}
+		missingSince[addr] = since
+		if now.Sub(since) >= missingPeerGracePeriod {
+			missing++
+		}
+	}
+	// Addresses which aren't missing anymore, or aren't discovered anymore,
+	// are forgotten.
+	c.missingSince = missingSince
+
+	suspected := missing > 0
+	minority := suspected && 2*len(members) <= len(members)+missing
+
+	partitioned := c.opts.PartitionProtection && minority
+	changed := partitioned != c.partitioned
+	c.partitioned = partitioned
+	c.updateReadyGauge()
+	c.rwMutex.Unlock()
+
+	if suspected {
+		c.partitionMetrics.partitionSuspected.Set(1)
+		level.Warn(c.log).Log(
+			"msg", "discovered peers are missing from the cluster; the cluster may be partitioned",
+			"peers_count", len(members),
+			"missing_peers_count", missing,
+			"known_peers_count", len(members)+missing,
+			"minority", minority,
+		)
+	} else {
+		c.partitionMetrics.partitionSuspected.Set(0)
+	}
+
+	if changed {
+		if partitioned {
+			level.Warn(c.log).Log("msg", "local node is in a minority partition of the cluster, marking cluster as not ready for traffic")
+		} else {
+			level.Info(c.log).Log("msg", "local node is no longer in a minority partition of the cluster")
+		}
+		// Components must re-evaluate the readiness of the cluster, even if
+		// the peers didn't change.
+		c.clusterChangeCallback()
+	}
+}
+
+// resolveAddr returns the host:port addresses of a discovered address. The
+// address itself is returned if its host is an IP address or can't be
+// resolved.
+func (c *alloyCluster) resolveAddr(addr string) []string {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || net.ParseIP(host) != nil {
+		return []string{addr}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), resolveTimeout)
+	defer cancel()
+	ips, err := c.lookupHost(ctx, host)
+	if err != nil {
+		level.Debug(c.log).Log("msg", "failed to resolve discovered peer address", "addr", addr, "err", err)
+		return []string{addr}
+	}
+
+	res := []string{addr}
+	for _, ip := range ips {
+		res = append(res, net.JoinHostPort(ip, port))
+	}
+	return res
+}
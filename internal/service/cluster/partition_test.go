@@ -0,0 +1,120 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/grafana/ckit/peer"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/atomic"
+)
+
+func TestCheckPartition(t *testing.T) {
+	discovered := []string{"10.0.0.0:12345", "10.0.0.1:12345", "10.0.0.2:12345", "10.0.0.3:12345", "10.0.0.4:12345"}
+
+	tests := []struct {
+		name                string
+		partitionProtection bool
+		members             int
+		expectSuspected     bool
+		expectReady         bool
+	}{
+		{name: "all peers joined", partitionProtection: true, members: 5, expectReady: true},
+		{name: "majority partition", partitionProtection: true, members: 3, expectSuspected: true, expectReady: true},
+		{name: "minority partition", partitionProtection: true, members: 2, expectSuspected: true, expectReady: false},
+		{name: "minority partition without protection", members: 2, expectSuspected: true, expectReady: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			notifyChangeCallsCount := atomic.NewInt32(0)
+			s := newTestService(Options{
+				EnableClustering:    true,
+				PartitionProtection: tc.partitionProtection,
+			}, buildPeersWithAddrs(tc.members), func() {
+				notifyChangeCallsCount.Inc()
+			})
+			c := s.alloyCluster
+			now := time.Now()
+			c.now = func() time.Time { return now }
+			c.updateReadyState()
+
+			// Missing peers don't make a partition suspected during the grace
+			// period.
+			c.checkPartition(discovered)
+			require.True(t, c.Ready())
+			require.Equal(t, 0.0, testutil.ToFloat64(c.partitionMetrics.partitionSuspected))
+			require.Equal(t, float64(5-tc.members), testutil.ToFloat64(c.partitionMetrics.missingPeers))
+
+			now = now.Add(missingPeerGracePeriod)
+			c.checkPartition(discovered)
+			require.Equal(t, tc.expectReady, c.Ready())
+			require.Equal(t, tc.expectReady, testutil.ToFloat64(c.clusterReadyGauge) == 1)
+			require.Equal(t, tc.expectSuspected, testutil.ToFloat64(c.partitionMetrics.partitionSuspected) == 1)
+			if !tc.expectReady {
+				require.Equal(t, int32(1), notifyChangeCallsCount.Load())
+			}
+
+			// The cluster is ready again once the partition heals.
+			updatePeers(s, &mockSharder{peers: buildPeersWithAddrs(5)})
+			c.checkPartition(discovered)
+			require.True(t, c.Ready())
+			require.Equal(t, 0.0, testutil.ToFloat64(c.partitionMetrics.partitionSuspected))
+			c.shutdown()
+		})
+	}
+}
+
+func TestCheckPartition_Hostnames(t *testing.T) {
+	// Peers discovered through SRV records are hostnames, which must be
+	// resolved to match the advertised addresses of the peers.
+	discovered := []string{"alloy-0.alloy.svc:12345", "alloy-1.alloy.svc:12345", "alloy-2.alloy.svc:12345"}
+
+	s := newTestService(Options{
+		EnableClustering:    true,
+		PartitionProtection: true,
+	}, buildPeersWithAddrs(2), func() {})
+	c := s.alloyCluster
+	now := time.Now()
+	c.now = func() time.Time { return now }
+	c.lookupHost = func(_ context.Context, host string) ([]string, error) {
+		switch host {
+		case "alloy-0.alloy.svc":
+			return []string{"10.0.0.0"}, nil
+		case "alloy-1.alloy.svc":
+			return []string{"10.0.0.1"}, nil
+		}
+		return nil, fmt.Errorf("no such host")
+	}
+	c.updateReadyState()
+
+	c.checkPartition(discovered)
+	require.Equal(t, 1.0, testutil.ToFloat64(c.partitionMetrics.missingPeers))
+
+	// alloy-2 left the cluster, but its record is still in DNS. It's
+	// forgotten once it's removed from DNS before the grace period expires.
+	now = now.Add(missingPeerGracePeriod / 2)
+	c.checkPartition(discovered[:2])
+	require.Equal(t, 0.0, testutil.ToFloat64(c.partitionMetrics.missingPeers))
+
+	now = now.Add(missingPeerGracePeriod)
+	c.checkPartition(discovered[:2])
+	require.True(t, c.Ready())
+	require.Equal(t, 0.0, testutil.ToFloat64(c.partitionMetrics.partitionSuspected))
+
+	// The grace period restarts when a peer is discovered again.
+	c.checkPartition(discovered)
+	require.Equal(t, 0.0, testutil.ToFloat64(c.partitionMetrics.partitionSuspected))
+	c.shutdown()
+}
+
+func buildPeersWithAddrs(count int) []peer.Peer {
+	peers := buildPeers(count)
+	for i := range peers {
+		peers[i].Addr = fmt.Sprintf("10.0.0.%d:12345", i)
+	}
+	return peers
+}
@@ -0,0 +1,222 @@
+package cluster
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/cespare/xxhash/v2"
+	"github.com/grafana/ckit/peer"
+	"github.com/grafana/ckit/shard"
+)
+
+// Supported hashing algorithms used to distribute work across the cluster.
+const (
+	HashingAlgorithmRing       = "ring"
+	HashingAlgorithmRendezvous = "rendezvous"
+	HashingAlgorithmMaglev     = "maglev"
+)
+
+// newSharder returns a sharder for the given hashing algorithm. ringTokens is
+// only used by the ring algorithm.
+//
+// All nodes of a cluster must use the same hashing algorithm, otherwise they
+// have different views of which node owns a key.
+func newSharder(algorithm string, ringTokens int) (shard.Sharder, error) {
+	switch algorithm {
+	case "", HashingAlgorithmRing:
+		if ringTokens <= 0 {
+			ringTokens = tokensPerNode
+		}
+		return shard.Ring(ringTokens), nil
+	case HashingAlgorithmRendezvous:
+		return shard.Rendezvous(), nil
+	case HashingAlgorithmMaglev:
+		return newMaglevSharder(), nil
+	default:
+		return nil, fmt.Errorf("unsupported hashing algorithm %q: must be one of %q, %q or %q",
+			algorithm, HashingAlgorithmRing, HashingAlgorithmRendezvous, HashingAlgorithmMaglev)
+	}
+}
+
+// maglevTableSize is the size of the lookup table of the maglev sharder. It
+// must be a prime number, and much larger than the number of nodes for an even
+// distribution of keys.
+const maglevTableSize = 65537
+
+// maglevSharder implements shard.Sharder using Maglev hashing:
+// https://research.google/pubs/maglev-a-fast-and-reliable-software-network-load-balancer/
+//
+// Maglev hashing evenly distributes keys across nodes, and minimizes the
+// number of keys which move when nodes join or leave, at the cost of a lookup
+// table which needs to be rebuilt on every membership change.
+type maglevSharder struct {
+	mut             sync.RWMutex
+	peers           map[string]peer.Peer
+	read, readWrite *maglevTable
+}
+
+var _ shard.Sharder = (*maglevSharder)(nil)
+
+func newMaglevSharder() *maglevSharder {
+	return &maglevSharder{
+		peers:     make(map[string]peer.Peer),
+		read:      &maglevTable{},
+		readWrite: &maglevTable{},
+	}
+}
+
+// Lookup implements shard.Sharder.
+func (ms *maglevSharder) Lookup(key shard.Key, numOwners int, op shard.Op) ([]peer.Peer, error) {
+	ms.mut.RLock()
+	defer ms.mut.RUnlock()
+
+	var table *maglevTable
+	switch op {
+	case shard.OpRead:
+		table = ms.read
+	case shard.OpReadWrite:
+		table = ms.readWrite
+	default:
+		return nil, fmt.Errorf("unknown op %s", op)
+	}
+
+	names, err := table.get(uint64(key), numOwners)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]peer.Peer, len(names))
+	for i, name := range names {
+		res[i] = ms.peers[name]
+	}
+	return res, nil
+}
+
+// Peers implements shard.Sharder.
+func (ms *maglevSharder) Peers() []peer.Peer {
+	ms.mut.RLock()
+	defer ms.mut.RUnlock()
+
+	ps := make([]peer.Peer, 0, len(ms.peers))
+	for _, p := range ms.peers {
+		ps = append(ps, p)
+	}
+	sort.Slice(ps, func(i, j int) bool { return ps[i].Name < ps[j].Name })
+	return ps
+}
+
+// SetPeers implements shard.Sharder. Viewers are ignored, and Terminating
+// peers are only eligible for read operations.
+func (ms *maglevSharder) SetPeers(ps []peer.Peer) {
+	var (
+		newPeers     = make(map[string]peer.Peer, len(ps))
+		newRead      = make([]string, 0, len(ps))
+		newReadWrite = make([]string, 0, len(ps))
+	)
+	for _, p := range ps {
+		switch p.State {
+		case peer.StateParticipant:
+			newRead = append(newRead, p.Name)
+			newReadWrite = append(newReadWrite, p.Name)
+			newPeers[p.Name] = p
+		case peer.StateTerminating:
+			newRead = append(newRead, p.Name)
+			newPeers[p.Name] = p
+		}
+	}
+
+	read, readWrite := newMaglevTable(newRead), newMaglevTable(newReadWrite)
+
+	ms.mut.Lock()
+	defer ms.mut.Unlock()
+	ms.peers = newPeers
+	ms.read = read
+	ms.readWrite = readWrite
+}
+
+// maglevTable is an immutable Maglev lookup table.
+type maglevTable struct {
+	nodes []string // Sorted set of nodes.
+	table []int    // Index into nodes for each slot of the table.
+}
+
+func newMaglevTable(nodes []string) *maglevTable {
+	nodes = append([]string(nil), nodes...)
+	sort.Strings(nodes)
+
+	mt := &maglevTable{nodes: nodes}
+	if len(nodes) == 0 {
+		return mt
+	}
+
+	// Each node has its own permutation of the table slots, determined by an
+	// offset and a skip derived from its name.
+	var (
+		offsets = make([]uint64, len(nodes))
+		skips   = make([]uint64, len(nodes))
+		next    = make([]uint64, len(nodes))
+	)
+	for i, node := range nodes {
+		h := xxhash.Sum64String(node)
+		offsets[i] = h % maglevTableSize
+		skips[i] = (xorshiftMult64(h) % (maglevTableSize - 1)) + 1
+	}
+
+	mt.table = make([]int, maglevTableSize)
+	for i := range mt.table {
+		mt.table[i] = -1
+	}
+
+	// Nodes take turns claiming their next preferred free slot until the table
+	// is full.
+	for filled := 0; ; {
+		for i := range nodes {
+			slot := (offsets[i] + next[i]*skips[i]) % maglevTableSize
+			for mt.table[slot] >= 0 {
+				next[i]++
+				slot = (offsets[i] + next[i]*skips[i]) % maglevTableSize
+			}
+			mt.table[slot] = i
+			next[i]++
+
+			filled++
+			if filled == maglevTableSize {
+				return mt
+			}
+		}
+	}
+}
+
+// get returns n distinct owners for key. The first owner is the node which
+// owns the slot of key; further owners are found by walking the table.
+func (mt *maglevTable) get(key uint64, n int) ([]string, error) {
+	if n > len(mt.nodes) {
+		return nil, fmt.Errorf("not enough nodes: need at least %d, have %d", n, len(mt.nodes))
+	} else if n == 0 {
+		return []string{}, nil
+	}
+
+	var (
+		res  = make([]string, 0, n)
+		seen = make(map[int]struct{}, n)
+	)
+	for slot := key % maglevTableSize; len(res) < n; slot = (slot + 1) % maglevTableSize {
+		idx := mt.table[slot]
+		if _, ok := seen[idx]; ok {
+			continue
+		}
+		seen[idx] = struct{}{}
+		res = append(res, mt.nodes[idx])
+	}
+	return res, nil
+}
+
+// xorshiftMult64 scrambles x. It's used to derive a second hash from a node
+// name: https://vigna.di.unimi.it/ftp/papers/xorshift.pdf
+func xorshiftMult64(x uint64) uint64 {
+	x ^= x >> 12
+	x ^= x << 25
+	x ^= x >> 27
+	return x * 2685821657736338717
+}
@@ -0,0 +1,113 @@
+package cluster
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/grafana/ckit/peer"
+	"github.com/grafana/ckit/shard"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSharder(t *testing.T) {
+	for _, algorithm := range []string{"", HashingAlgorithmRing, HashingAlgorithmRendezvous, HashingAlgorithmMaglev} {
+		t.Run(algorithm, func(t *testing.T) {
+			sharder, err := newSharder(algorithm, 0)
+			require.NoError(t, err)
+
+			sharder.SetPeers(buildParticipants(3))
+			owners, err := sharder.Lookup(shard.StringKey("key"), 2, shard.OpReadWrite)
+			require.NoError(t, err)
+			require.Len(t, owners, 2)
+			require.NotEqual(t, owners[0].Name, owners[1].Name)
+		})
+	}
+
+	_, err := newSharder("unknown", 0)
+	require.ErrorContains(t, err, `unsupported hashing algorithm "unknown"`)
+}
+
+func TestMaglevSharder(t *testing.T) {
+	sharder := newMaglevSharder()
+
+	_, err := sharder.Lookup(shard.StringKey("key"), 1, shard.OpReadWrite)
+	require.ErrorContains(t, err, "not enough nodes")
+
+	peers := buildParticipants(10)
+	sharder.SetPeers(peers)
+	require.Len(t, sharder.Peers(), 10)
+
+	const numKeys = 10000
+	owners := make(map[string]string, numKeys)
+	load := make(map[string]int)
+	for i := 0; i < numKeys; i++ {
+		key := fmt.Sprintf("key-%d", i)
+		res, err := sharder.Lookup(shard.StringKey(key), 1, shard.OpReadWrite)
+		require.NoError(t, err)
+		owners[key] = res[0].Name
+		load[res[0].Name]++
+	}
+
+	// Keys are evenly distributed across nodes.
+	for _, p := range peers {
+		require.InDelta(t, numKeys/len(peers), load[p.Name], float64(numKeys/len(peers))*0.2)
+	}
+
+	// Only the keys of a terminating node move to other nodes, and the node
+	// is still eligible for reads.
+	peers[0].State = peer.StateTerminating
+	sharder.SetPeers(peers)
+
+	var moved int
+	for key, owner := range owners {
+		res, err := sharder.Lookup(shard.StringKey(key), 1, shard.OpReadWrite)
+		require.NoError(t, err)
+		if owner == peers[0].Name {
+			require.NotEqual(t, peers[0].Name, res[0].Name)
+		}
+		if res[0].Name != owner {
+			moved++
+		}
+	}
+	require.InDelta(t, load[peers[0].Name], moved, float64(numKeys)*0.05)
+
+	_, err = sharder.Lookup(shard.StringKey("key"), 10, shard.OpRead)
+	require.NoError(t, err)
+	_, err = sharder.Lookup(shard.StringKey("key"), 10, shard.OpReadWrite)
+	require.ErrorContains(t, err, "not enough nodes")
+}
+
+func TestReplicationFactor(t *testing.T) {
+	sharder := newMaglevSharder()
+	peers := buildParticipants(3)
+	sharder.SetPeers(peers)
+
+	c := newAlloyCluster(sharder, func() {}, Options{EnableClustering: true, ReplicationFactor: 2}, nil)
+	require.Equal(t, 2, c.ReplicationFactor(shard.OpReadWrite))
+
+	// Lookup returns as many owners as requested, regardless of the
+	// replication factor, since some callers elect a single leader.
+	primary, err := c.Lookup(shard.StringKey("key"), 1, shard.OpReadWrite)
+	require.NoError(t, err)
+	require.Len(t, primary, 1)
+
+	owners, err := c.Lookup(shard.StringKey("key"), c.ReplicationFactor(shard.OpReadWrite), shard.OpReadWrite)
+	require.NoError(t, err)
+	require.Len(t, owners, 2)
+	require.Equal(t, primary[0], owners[0])
+
+	// The replication factor is capped by the number of eligible peers.
+	c = newAlloyCluster(sharder, func() {}, Options{EnableClustering: true, ReplicationFactor: 5}, nil)
+	require.Equal(t, 3, c.ReplicationFactor(shard.OpReadWrite))
+
+	c = newAlloyCluster(sharder, func() {}, Options{EnableClustering: true}, nil)
+	require.Equal(t, 1, c.ReplicationFactor(shard.OpReadWrite))
+}
+
+func buildParticipants(count int) []peer.Peer {
+	peers := buildPeers(count)
+	for i := range peers {
+		peers[i].State = peer.StateParticipant
+	}
+	return peers
+}
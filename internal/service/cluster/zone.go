@@ -0,0 +1,245 @@
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/grafana/ckit/peer"
+	"github.com/grafana/ckit/shard"
+
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+)
+
+// ZoneLabel is the label of discovery targets which holds the availability
+// zone of the target. Targets with a zone are preferably assigned to the nodes
+// in the same zone.
+const ZoneLabel = "__zone__"
+
+const (
+	// zonePath is the path, relative to the base route of the cluster service,
+	// at which nodes serve their zone to their peers.
+	zonePath = "zone"
+
+	// zoneResyncInterval is how often the zones of peers which couldn't be
+	// fetched are fetched again.
+	zoneResyncInterval = 15 * time.Second
+
+	// zoneFetchTimeout is the maximum duration of fetching the zone of a peer.
+	zoneFetchTimeout = 5 * time.Second
+)
+
+// zoneResponse is served by nodes at zonePath.
+type zoneResponse struct {
+	Zone string `json:"zone"`
+}
+
+// zoneSharder implements shard.Sharder. Keys are looked up among all peers,
+// unless they're looked up with lookupZone, in which case the peers in the
+// same zone as the key are preferred.
+//
+// Peers don't gossip their zone, so the zones of remote peers are fetched
+// from them and set with setZone. Until then, their zone is unknown and they
+// only own keys looked up among all peers.
+type zoneSharder struct {
+	shard.Sharder // Looks up keys among all peers.
+
+	newSharder func() shard.Sharder
+	selfZone   string
+
+	mut    sync.RWMutex
+	peers  []peer.Peer
+	zones  map[string]string        // Zones of remote peers by name.
+	byZone map[string]shard.Sharder // Looks up keys among the peers of each zone.
+}
+
+var _ shard.Sharder = (*zoneSharder)(nil)
+
+func newZoneSharder(newSharder func() shard.Sharder, selfZone string) *zoneSharder {
+	return &zoneSharder{
+		Sharder:    newSharder(),
+		newSharder: newSharder,
+		selfZone:   selfZone,
+		zones:      make(map[string]string),
+		byZone:     make(map[string]shard.Sharder),
+	}
+}
+
+// SetPeers implements shard.Sharder.
+func (zs *zoneSharder) SetPeers(ps []peer.Peer) {
+	zs.Sharder.SetPeers(ps)
+
+	zs.mut.Lock()
+	defer zs.mut.Unlock()
+
+	zs.peers = ps
+	// Forget the zones of the peers which left the cluster.
+	current := make(map[string]struct{}, len(ps))
+	for _, p := range ps {
+		current[p.Name] = struct{}{}
+	}
+	for name := range zs.zones {
+		if _, ok := current[name]; !ok {
+			delete(zs.zones, name)
+		}
+	}
+	zs.rebuildLocked()
+}
+
+// rebuildLocked rebuilds the sharders of each zone. zs.mut must be locked for
+// writes by the caller.
+func (zs *zoneSharder) rebuildLocked() {
+	peersByZone := make(map[string][]peer.Peer)
+	for _, p := range zs.peers {
+		if zone := zs.zoneLocked(p); zone != "" {
+			peersByZone[zone] = append(peersByZone[zone], p)
+		}
+	}
+
+	byZone := make(map[string]shard.Sharder, len(peersByZone))
+	for zone, ps := range peersByZone {
+		s := zs.newSharder()
+		s.SetPeers(ps)
+		byZone[zone] = s
+	}
+	zs.byZone = byZone
+}
+
+// setZone sets the zone of a remote peer. It returns true if the zone changed.
+func (zs *zoneSharder) setZone(name, zone string) bool {
+	zs.mut.Lock()
+	defer zs.mut.Unlock()
+
+	if prev, ok := zs.zones[name]; ok && prev == zone {
+		return false
+	}
+	zs.zones[name] = zone
+	zs.rebuildLocked()
+	return true
+}
+
+// zone returns the zone of p, or an empty string if it's unknown.
+func (zs *zoneSharder) zone(p peer.Peer) string {
+	zs.mut.RLock()
+	defer zs.mut.RUnlock()
+	return zs.zoneLocked(p)
+}
+
+func (zs *zoneSharder) zoneLocked(p peer.Peer) string {
+	if p.Self {
+		return zs.selfZone
+	}
+	return zs.zones[p.Name]
+}
+
+// unresolvedPeers returns the remote peers whose zone hasn't been fetched yet.
+func (zs *zoneSharder) unresolvedPeers() []peer.Peer {
+	zs.mut.RLock()
+	defer zs.mut.RUnlock()
+
+	var res []peer.Peer
+	for _, p := range zs.peers {
+		if _, ok := zs.zones[p.Name]; !ok && !p.Self {
+			res = append(res, p)
+		}
+	}
+	return res
+}
+
+// lookupZone looks up the owners of key among the peers of zone. Keys are
+// looked up among all peers instead when zone is empty, or doesn't have
+// enough eligible peers, for example because all its nodes failed. This way,
+// only the keys of a failed zone move to other zones.
+func (zs *zoneSharder) lookupZone(key shard.Key, zone string, numOwners int, op shard.Op) ([]peer.Peer, error) {
+	if zone != "" {
+		zs.mut.RLock()
+		s, ok := zs.byZone[zone]
+		zs.mut.RUnlock()
+
+		if ok {
+			if owners, err := s.Lookup(key, numOwners, op); err == nil && len(owners) > 0 {
+				return owners, nil
+			}
+		}
+	}
+	return zs.Sharder.Lookup(key, numOwners, op)
+}
+
+// handleZone serves the zone of the local node to its peers.
+func (s *Service) handleZone(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(zoneResponse{Zone: s.opts.Zone})
+}
+
+// resolveZones fetches the zones of the peers which are unknown, each time
+// the peers change and periodically, until ctx is canceled. Components are
+// notified of a cluster change when a zone is learned, since the owners of
+// keys may have changed.
+func (s *Service) resolveZones(ctx context.Context) {
+	t := time.NewTicker(zoneResyncInterval)
+	defer t.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+		case <-s.peersChanged:
+		}
+
+		var changed bool
+		for _, p := range s.zones.unresolvedPeers() {
+			zone, err := s.fetchZone(ctx, p)
+			if err != nil {
+				level.Debug(s.log).Log("msg", "failed to fetch the zone of peer", "peer", p.Name, "err", err)
+				continue
+			}
+			if s.zones.setZone(p.Name, zone) {
+				changed = true
+			}
+		}
+		if changed {
+			s.triggerClusterChangeNotification()
+		}
+	}
+}
+
+// fetchZone fetches the zone of a remote peer. Peers which don't serve their
+// zone have no zone.
+func (s *Service) fetchZone(ctx context.Context, p peer.Peer) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, zoneFetchTimeout)
+	defer cancel()
+
+	scheme := "http"
+	if s.opts.EnableTLS {
+		scheme = "https"
+	}
+	base, _ := s.node.Handler()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s://%s%s%s", scheme, p.Addr, base, zonePath), nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", nil
+	default:
+		return "", fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	var zr zoneResponse
+	if err := json.NewDecoder(resp.Body).Decode(&zr); err != nil {
+		return "", err
+	}
+	return zr.Zone, nil
+}
@@ -0,0 +1,146 @@
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/ckit/peer"
+	"github.com/grafana/ckit/shard"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func TestZoneSharder(t *testing.T) {
+	zones := []string{"zone-a", "zone-b", "zone-c"}
+	peers := buildParticipants(9)
+	peers[0].Self = true
+
+	zs := newTestZoneSharder(t, zones[0])
+	zs.SetPeers(peers)
+	for i, p := range peers[1:] {
+		require.True(t, zs.setZone(p.Name, zones[(i+1)%len(zones)]))
+	}
+	require.False(t, zs.setZone(peers[1].Name, zones[1]))
+	require.Empty(t, zs.unresolvedPeers())
+
+	lookup := func(key, zone string) string {
+		owners, err := zs.lookupZone(shard.StringKey(key), zone, 1, shard.OpReadWrite)
+		require.NoError(t, err)
+		require.Len(t, owners, 1)
+		return owners[0].Name
+	}
+
+	// Keys are owned by the peers in the same zone.
+	const numKeys = 1000
+	owners := make(map[string]string, numKeys)
+	for i := 0; i < numKeys; i++ {
+		key, zone := fmt.Sprintf("key-%d", i), zones[i%len(zones)]
+		owners[key] = lookup(key, zone)
+		require.Equal(t, zone, zs.zone(peerByName(t, peers, owners[key])))
+	}
+
+	// Keys without a zone, or with an unknown zone, are owned by any peer.
+	for i := 0; i < numKeys; i++ {
+		require.NotEmpty(t, lookup(fmt.Sprintf("key-%d", i), ""))
+		require.NotEmpty(t, lookup(fmt.Sprintf("key-%d", i), "zone-d"))
+	}
+
+	// When all the peers of a zone fail, only the keys of that zone move to
+	// other zones.
+	var remaining []peer.Peer
+	for _, p := range peers {
+		if zs.zone(p) != zones[1] {
+			remaining = append(remaining, p)
+		}
+	}
+	zs.SetPeers(remaining)
+	for i := 0; i < numKeys; i++ {
+		key, zone := fmt.Sprintf("key-%d", i), zones[i%len(zones)]
+		owner := lookup(key, zone)
+		if zone == zones[1] {
+			require.NotEqual(t, zones[1], zs.zone(peerByName(t, remaining, owner)))
+			continue
+		}
+		require.Equal(t, owners[key], owner, "key %s moved", key)
+	}
+}
+
+func TestZoneSharderPeers(t *testing.T) {
+	peers := buildParticipants(3)
+	peers[0].Self = true
+
+	zs := newTestZoneSharder(t, "zone-a")
+	zs.SetPeers(peers)
+	require.Equal(t, "zone-a", zs.zone(peers[0]))
+	require.Equal(t, peers[1:], zs.unresolvedPeers())
+
+	// Peers without a zone are resolved, but only own keys without a zone.
+	require.True(t, zs.setZone(peers[1].Name, ""))
+	require.True(t, zs.setZone(peers[2].Name, "zone-a"))
+	require.Empty(t, zs.unresolvedPeers())
+	for i := 0; i < 100; i++ {
+		owners, err := zs.lookupZone(shard.StringKey(fmt.Sprintf("key-%d", i)), "zone-a", 1, shard.OpReadWrite)
+		require.NoError(t, err)
+		require.NotEqual(t, peers[1].Name, owners[0].Name)
+	}
+
+	// The zones of the peers which left the cluster are forgotten.
+	zs.SetPeers(peers[:2])
+	zs.SetPeers(peers)
+	require.Equal(t, peers[2:], zs.unresolvedPeers())
+	require.Empty(t, zs.zone(peers[2]))
+}
+
+func TestFetchZone(t *testing.T) {
+	s, err := New(Options{
+		Log:              log.NewNopLogger(),
+		EnableClustering: true,
+		NodeName:         "node-a",
+		AdvertiseAddress: "127.0.0.1:12345",
+		Zone:             "zone-a",
+	})
+	require.NoError(t, err)
+
+	// Peers serve the cluster service over HTTP/2 without TLS.
+	_, handler := s.ServiceHandler(nil)
+	srv := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	defer srv.Close()
+
+	zone, err := s.fetchZone(context.Background(), peer.Peer{Name: "node-a", Addr: strings.TrimPrefix(srv.URL, "http://")})
+	require.NoError(t, err)
+	require.Equal(t, "zone-a", zone)
+
+	// Peers which don't serve their zone have no zone.
+	other := httptest.NewServer(h2c.NewHandler(http.NotFoundHandler(), &http2.Server{}))
+	defer other.Close()
+
+	zone, err = s.fetchZone(context.Background(), peer.Peer{Name: "node-b", Addr: strings.TrimPrefix(other.URL, "http://")})
+	require.NoError(t, err)
+	require.Empty(t, zone)
+}
+
+func newTestZoneSharder(t *testing.T, selfZone string) *zoneSharder {
+	t.Helper()
+	return newZoneSharder(func() shard.Sharder {
+		s, err := newSharder(HashingAlgorithmRendezvous, 0)
+		require.NoError(t, err)
+		return s
+	}, selfZone)
+}
+
+func peerByName(t *testing.T, peers []peer.Peer, name string) peer.Peer {
+	t.Helper()
+	for _, p := range peers {
+		if p.Name == name {
+			return p
+		}
+	}
+	require.FailNow(t, "unknown peer", name)
+	return peer.Peer{}
+}
@@ -0,0 +1,162 @@
+package http
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/alecthomas/units"
+	"github.com/grafana/alloy/syntax"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// HTTP2Arguments configures HTTP/2 support of the HTTP server.
+type HTTP2Arguments struct {
+	H2CEnabled           bool             `alloy:"h2c_enabled,attr,optional"`
+	MaxConcurrentStreams uint32           `alloy:"max_concurrent_streams,attr,optional"`
+	MaxReadFrameSize     units.Base2Bytes `alloy:"max_read_frame_size,attr,optional"`
+}
+
+var _ syntax.Defaulter = (*HTTP2Arguments)(nil)
+var _ syntax.Validator = (*HTTP2Arguments)(nil)
+
+// DefaultHTTP2Arguments holds default settings for HTTP2Arguments.
+var DefaultHTTP2Arguments = HTTP2Arguments{
+	H2CEnabled: true,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *HTTP2Arguments) SetToDefault() {
+	*args = DefaultHTTP2Arguments
+}
+
+// Validate implements syntax.Validator.
+func (args *HTTP2Arguments) Validate() error {
+	// Limits defined by the HTTP/2 specification.
+	if args.MaxReadFrameSize != 0 && (args.MaxReadFrameSize < 16*units.KiB || args.MaxReadFrameSize > 16*units.MiB-1) {
+		return fmt.Errorf("max_read_frame_size must be between 16KiB and 16MiB")
+	}
+	return nil
+}
+
+// GRPCArguments configures the gRPC server which shares the HTTP server.
+type GRPCArguments struct {
+	MaxRecvMsgSize units.Base2Bytes        `alloy:"max_recv_msg_size,attr,optional"`
+	MaxSendMsgSize units.Base2Bytes        `alloy:"max_send_msg_size,attr,optional"`
+	Reflection     bool                    `alloy:"reflection,attr,optional"`
+	Keepalive      *GRPCKeepaliveArguments `alloy:"keepalive,block,optional"`
+}
+
+var _ syntax.Defaulter = (*GRPCArguments)(nil)
+
+// DefaultGRPCArguments holds default settings for GRPCArguments.
+var DefaultGRPCArguments = GRPCArguments{
+	MaxRecvMsgSize: 4 * units.MiB,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *GRPCArguments) SetToDefault() {
+	*args = DefaultGRPCArguments
+}
+
+// GRPCKeepaliveArguments configures keepalive pings on HTTP/2 connections,
+// which carry gRPC requests.
+type GRPCKeepaliveArguments struct {
+	Time              time.Duration `alloy:"time,attr,optional"`
+	Timeout           time.Duration `alloy:"timeout,attr,optional"`
+	MaxConnectionIdle time.Duration `alloy:"max_connection_idle,attr,optional"`
+}
+
+var _ syntax.Defaulter = (*GRPCKeepaliveArguments)(nil)
+
+// DefaultGRPCKeepaliveArguments holds default settings for
+// GRPCKeepaliveArguments.
+var DefaultGRPCKeepaliveArguments = GRPCKeepaliveArguments{
+	Timeout: 15 * time.Second,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *GRPCKeepaliveArguments) SetToDefault() {
+	*args = DefaultGRPCKeepaliveArguments
+}
+
+// serverHandlers holds the parts of the HTTP server which are rebuilt
+// whenever the HTTP service is updated.
+type serverHandlers struct {
+	h2c  *http2.Server // HTTP/2 server for cleartext connections; nil if h2c is disabled.
+	grpc *grpc.Server  // Shared gRPC server; nil if gRPC is disabled.
+}
+
+func newServerHandlers(http2Args *HTTP2Arguments, grpcArgs *GRPCArguments) *serverHandlers {
+	if http2Args == nil {
+		http2Args = &DefaultHTTP2Arguments
+	}
+
+	var hs serverHandlers
+	if http2Args.H2CEnabled {
+		hs.h2c = &http2.Server{
+			MaxConcurrentStreams: http2Args.MaxConcurrentStreams,
+			MaxReadFrameSize:     uint32(http2Args.MaxReadFrameSize),
+		}
+		if grpcArgs != nil && grpcArgs.Keepalive != nil {
+			hs.h2c.ReadIdleTimeout = grpcArgs.Keepalive.Time
+			hs.h2c.PingTimeout = grpcArgs.Keepalive.Timeout
+			hs.h2c.IdleTimeout = grpcArgs.Keepalive.MaxConnectionIdle
+		}
+	}
+
+	if grpcArgs != nil {
+		opts := []grpc.ServerOption{
+			grpc.MaxRecvMsgSize(int(grpcArgs.MaxRecvMsgSize)),
+		}
+		if grpcArgs.MaxSendMsgSize > 0 {
+			opts = append(opts, grpc.MaxSendMsgSize(int(grpcArgs.MaxSendMsgSize)))
+		}
+		hs.grpc = grpc.NewServer(opts...)
+		healthpb.RegisterHealthServer(hs.grpc, health.NewServer())
+		if grpcArgs.Reflection {
+			reflection.Register(hs.grpc)
+		}
+	}
+	return &hs
+}
+
+// serverHandler returns the root handler of the HTTP server. gRPC requests
+// are sent to the shared gRPC server, while other requests are sent to
+// router.
+func (s *Service) serverHandler(router http.Handler) http.Handler {
+	root := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hs := s.handlers.Load()
+		if hs.grpc == nil || !isGRPCRequest(r) {
+			router.ServeHTTP(w, r)
+			return
+		}
+
+		s.authenticatorMut.RLock()
+		err := s.authenticator(w, r)
+		s.authenticatorMut.RUnlock()
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		hs.grpc.ServeHTTP(w, r)
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if hs := s.handlers.Load(); hs.h2c != nil {
+			h2c.NewHandler(root, hs.h2c).ServeHTTP(w, r)
+			return
+		}
+		root.ServeHTTP(w, r)
+	})
+}
+
+func isGRPCRequest(r *http.Request) bool {
+	return r.ProtoMajor == 2 && strings.HasPrefix(r.Header.Get("Content-Type"), "application/grpc")
+}
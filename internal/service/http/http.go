@@ -16,6 +16,7 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-kit/log"
@@ -36,8 +37,6 @@ import (
 	"go.opentelemetry.io/contrib/instrumentation/github.com/gorilla/mux/otelmux"
 	"go.opentelemetry.io/otel/trace"
 	"go.opentelemetry.io/otel/trace/noop"
-	"golang.org/x/net/http2"
-	"golang.org/x/net/http2/h2c"
 )
 
 // ServiceName defines the name used for the HTTP service.
@@ -62,8 +61,11 @@ type Options struct {
 
 // Arguments holds runtime settings for the HTTP service.
 type Arguments struct {
-	Auth *AuthArguments `alloy:"auth,block,optional"`
-	TLS  *TLSArguments  `alloy:"tls,block,optional"`
+	Auth      *AuthArguments      `alloy:"auth,block,optional"`
+	TLS       *TLSArguments       `alloy:"tls,block,optional"`
+	Readiness *ReadinessArguments `alloy:"readiness,block,optional"`
+	HTTP2     *HTTP2Arguments     `alloy:"http2,block,optional"`
+	GRPC      *GRPCArguments      `alloy:"grpc,block,optional"`
 }
 
 type Service struct {
@@ -88,6 +90,13 @@ type Service struct {
 	// authenticator is applied to every request made to http server
 	authenticator authenticator
 
+	readinessMut sync.RWMutex
+	// readiness holds the components and services gating "/-/ready".
+	readiness *ReadinessArguments
+
+	// handlers holds the HTTP/2 and gRPC servers used by the HTTP server.
+	handlers atomic.Pointer[serverHandlers]
+
 	// publicLis and tcpLis are used to lazily enable TLS, since TLS is
 	// optionally configurable at runtime.
 	//
@@ -134,7 +143,7 @@ func New(opts Options) *Service {
 	// lazyLis should default to wrapping around lazyNetLis.
 	_ = publicLis.SetInner(tcpLis)
 
-	return &Service{
+	s := &Service{
 		globalLogger: l,
 		log:          log.With(l, "service", "http"),
 		tracer:       t,
@@ -150,6 +159,8 @@ func New(opts Options) *Service {
 		componentHttpPathPrefix:          "/api/v0/component/",
 		componentHttpPathPrefixRemotecfg: "/api/v0/component/remotecfg",
 	}
+	s.handlers.Store(newServerHandlers(nil, nil))
+	return s
 }
 
 // Definition returns the definition of the HTTP service.
@@ -252,14 +263,27 @@ func (s *Service) Run(ctx context.Context, host service.Host) error {
 	r.PathPrefix(s.componentHttpPathPrefix).Handler(s.componentHandler(rootHostProvider(host), s.componentHttpPathPrefix))
 
 	if s.opts.ReadyFunc != nil {
+		// Unlike "/-/healthy", "/-/ready" only reports on the components and
+		// services configured in the readiness block, so that a single
+		// non-critical component can't hold back a rollout.
 		r.HandleFunc("/-/ready", func(w http.ResponseWriter, _ *http.Request) {
-			if s.opts.ReadyFunc() {
-				w.WriteHeader(http.StatusOK)
-				_, _ = fmt.Fprintln(w, "Alloy is ready.")
-			} else {
+			if !s.opts.ReadyFunc() {
 				w.WriteHeader(http.StatusServiceUnavailable)
 				_, _ = fmt.Fprintln(w, "Alloy is not ready.")
+				return
+			}
+
+			s.readinessMut.RLock()
+			reasons := s.readiness.notReadyReasons(host)
+			s.readinessMut.RUnlock()
+			if len(reasons) > 0 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				_, _ = fmt.Fprintln(w, "Alloy is not ready: "+strings.Join(reasons, ", "))
+				return
 			}
+
+			w.WriteHeader(http.StatusOK)
+			_, _ = fmt.Fprintln(w, "Alloy is ready.")
 		})
 	}
 
@@ -290,7 +314,7 @@ func (s *Service) Run(ctx context.Context, host service.Host) error {
 		r.PathPrefix(route.Base).Handler(route.Handler)
 	}
 
-	srv := &http.Server{Handler: h2c.NewHandler(r, &http2.Server{})}
+	srv := &http.Server{Handler: s.serverHandler(r)}
 
 	level.Info(s.log).Log("msg", "now listening for http traffic", "addr", s.opts.HTTPListenAddr)
 
@@ -344,6 +368,10 @@ func (s *Service) generateSupportBundleHandler(host service.Host) func(rw http.R
 		ctx, cancel := context.WithTimeout(context.Background(), duration)
 		defer cancel()
 
+		// Capture the logs written before the bundle was requested, ahead of
+		// the logs written while it is being generated.
+		recentLogs := s.globalLogger.RecentLogs()
+
 		var logsBuffer bytes.Buffer
 		syncBuff := log.NewSyncWriter(&logsBuffer)
 		s.globalLogger.SetTemporaryWriter(syncBuff)
@@ -361,7 +389,20 @@ func (s *Service) generateSupportBundleHandler(host service.Host) func(rw http.R
 		// secret redaction.
 		sources := redactedSources(s.sources)
 
-		bundle, err := ExportSupportBundle(ctx, s.opts.BundleContext.RuntimeFlags, s.opts.HTTPListenAddr, sources, cachedConfig, s.Data().(Data).DialFunc)
+		componentGraph, err := exportComponentGraph(host)
+		if err != nil {
+			level.Debug(s.log).Log("msg", "failed to export component graph", "err", err)
+		}
+
+		bundle, err := ExportSupportBundle(ctx, SupportBundleOptions{
+			RuntimeFlags:   s.opts.BundleContext.RuntimeFlags,
+			ServerAddress:  s.opts.HTTPListenAddr,
+			Sources:        sources,
+			RemoteConfig:   cachedConfig,
+			ComponentGraph: componentGraph,
+			RecentLogs:     recentLogs,
+			DialContext:    s.Data().(Data).DialFunc,
+		})
 		if err != nil {
 			http.Error(rw, err.Error(), http.StatusInternalServerError)
 			return
@@ -502,6 +543,12 @@ func (s *Service) Update(newConfig any) error {
 	}
 	s.authenticatorMut.Unlock()
 
+	s.readinessMut.Lock()
+	s.readiness = newArgs.Readiness
+	s.readinessMut.Unlock()
+
+	s.handlers.Store(newServerHandlers(newArgs.HTTP2, newArgs.GRPC))
+
 	return nil
 }
 
@@ -2,10 +2,12 @@ package http
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/featuregate"
@@ -19,6 +21,10 @@ import (
 	"github.com/prometheus/common/config"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/trace/noop"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
 )
 
 func TestHTTP(t *testing.T) {
@@ -330,10 +336,159 @@ func TestUnhealthy(t *testing.T) {
 	})
 }
 
+func TestReadiness(t *testing.T) {
+	ctx := componenttest.TestContext(t)
+
+	env, err := newTestEnvironment(t)
+	require.NoError(t, err)
+
+	env.components = []*component.Info{
+		{
+			ID:     component.ID{LocalID: "prometheus.remote_write.default"},
+			Health: component.Health{Health: component.HealthTypeHealthy},
+		},
+		{
+			ID: component.ID{LocalID: "prometheus.scrape.default"},
+			Health: component.Health{
+				Health:  component.HealthTypeUnhealthy,
+				Message: "scrape failed",
+			},
+		},
+	}
+	env.remotecfgErr = errors.New("no remote configuration has been loaded yet")
+	require.NoError(t, env.ApplyConfig(``))
+
+	go func() {
+		require.NoError(t, env.Run(ctx))
+	}()
+
+	ready := func(t require.TestingT) (int, string) {
+		resp, err := http.Get(fmt.Sprintf("http://%s/-/ready", env.ListenAddr()))
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		buf, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return resp.StatusCode, string(buf)
+	}
+
+	// Unhealthy components don't affect readiness unless they're configured
+	// in the readiness block.
+	util.Eventually(t, func(t require.TestingT) {
+		code, body := ready(t)
+		require.Equal(t, http.StatusOK, code)
+		require.Equal(t, "Alloy is ready.\n", body)
+	})
+
+	require.NoError(t, env.ApplyConfig(`
+		readiness {
+			components = ["prometheus.remote_write.default", "prometheus.scrape.default", "loki.write.missing"]
+			services   = ["remotecfg", "unknown"]
+		}
+	`))
+	code, body := ready(t)
+	require.Equal(t, http.StatusServiceUnavailable, code)
+	require.Equal(t, `Alloy is not ready: `+
+		`component "prometheus.scrape.default" is unhealthy: scrape failed, `+
+		`component "loki.write.missing" not found, `+
+		`service "remotecfg" is not ready: no remote configuration has been loaded yet, `+
+		`service "unknown" not found`+"\n", body)
+
+	require.NoError(t, env.ApplyConfig(`
+		readiness {
+			components = ["prometheus.remote_write.default"]
+		}
+	`))
+	code, body = ready(t)
+	require.Equal(t, http.StatusOK, code)
+	require.Equal(t, "Alloy is ready.\n", body)
+}
+
+func TestGRPC(t *testing.T) {
+	ctx := componenttest.TestContext(t)
+
+	env, err := newTestEnvironment(t)
+	require.NoError(t, err)
+	require.NoError(t, env.ApplyConfig(`
+		grpc {
+			reflection = true
+		}
+	`))
+
+	go func() {
+		require.NoError(t, env.Run(ctx))
+	}()
+
+	conn, err := grpc.NewClient(env.ListenAddr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn.Close()
+
+	util.Eventually(t, func(t require.TestingT) {
+		resp, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+		require.NoError(t, err)
+		require.Equal(t, healthpb.HealthCheckResponse_SERVING, resp.Status)
+	})
+
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	require.NoError(t, err)
+	require.NoError(t, stream.Send(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_ListServices{},
+	}))
+	resp, err := stream.Recv()
+	require.NoError(t, err)
+	var services []string
+	for _, svc := range resp.GetListServicesResponse().GetService() {
+		services = append(services, svc.GetName())
+	}
+	require.Contains(t, services, "grpc.health.v1.Health")
+
+	// Disabling h2c prevents gRPC requests over cleartext connections.
+	require.NoError(t, env.ApplyConfig(`
+		http2 {
+			h2c_enabled = false
+		}
+		grpc {}
+	`))
+	conn2, err := grpc.NewClient(env.ListenAddr(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	defer conn2.Close()
+
+	checkCtx, cancel := context.WithTimeout(ctx, time.Second)
+	defer cancel()
+	_, err = healthpb.NewHealthClient(conn2).Check(checkCtx, &healthpb.HealthCheckRequest{})
+	require.Error(t, err)
+}
+
+func TestExportComponentGraph(t *testing.T) {
+	host := fakeGraphHost{graph: "digraph {\n\t\"prometheus.scrape.default\";\n}\n"}
+
+	graph, err := exportComponentGraph(host)
+	require.NoError(t, err)
+	require.Equal(t, host.graph, string(graph))
+
+	_, err = exportComponentGraph(fakeHost{})
+	require.ErrorContains(t, err, "graph export isn't supported")
+}
+
+// fakeGraphHost is a host which writes the graph of its root module.
+type fakeGraphHost struct {
+	fakeHost
+	graph string
+}
+
+func (f fakeGraphHost) WriteGraph(w io.Writer, moduleID string, format string) error {
+	if moduleID != "" || format != "dot" {
+		return fmt.Errorf("unexpected graph %q in format %q", moduleID, format)
+	}
+	_, err := io.WriteString(w, f.graph)
+	return err
+}
+
 type testEnvironment struct {
-	svc        *Service
-	addr       string
-	components []*component.Info
+	svc          *Service
+	addr         string
+	components   []*component.Info
+	remotecfgErr error
 }
 
 func newTestEnvironment(t *testing.T) (*testEnvironment, error) {
@@ -371,19 +526,26 @@ func (env *testEnvironment) ApplyConfig(config string) error {
 
 func (env *testEnvironment) Run(ctx context.Context) error {
 	return env.svc.Run(ctx, fakeHost{
-		components: env.components,
+		components:   env.components,
+		remotecfgErr: env.remotecfgErr,
 	})
 }
 
 func (env *testEnvironment) ListenAddr() string { return env.addr }
 
 type fakeHost struct {
-	components []*component.Info
+	components   []*component.Info
+	remotecfgErr error
 }
 
 var _ service.Host = (fakeHost{})
 
-func (fakeHost) GetComponent(id component.ID, opts component.InfoOptions) (*component.Info, error) {
+func (f fakeHost) GetComponent(id component.ID, opts component.InfoOptions) (*component.Info, error) {
+	for _, c := range f.components {
+		if c.ID == id {
+			return c, nil
+		}
+	}
 	return nil, fmt.Errorf("no such component %s", id)
 }
 
@@ -401,14 +563,18 @@ func (fakeHost) GetServiceConsumers(serviceName string) []service.Consumer { ret
 
 func (fakeHost) NewController(id string) service.Controller { return nil }
 
-func (fakeHost) GetService(svc string) (service.Service, bool) {
+func (f fakeHost) GetService(svc string) (service.Service, bool) {
 	if svc == remotecfg.ServiceName {
-		return fakeRemotecfg{}, true
+		return fakeRemotecfg{readyErr: f.remotecfgErr}, true
 	}
 	return nil, false
 }
 
-type fakeRemotecfg struct{}
+type fakeRemotecfg struct {
+	readyErr error
+}
+
+var _ ReadinessReporter = fakeRemotecfg{}
 
 func (f fakeRemotecfg) Definition() service.Definition {
 	return service.Definition{
@@ -421,3 +587,4 @@ func (f fakeRemotecfg) Definition() service.Definition {
 func (f fakeRemotecfg) Run(ctx context.Context, host service.Host) error { return nil }
 func (f fakeRemotecfg) Update(newConfig any) error                       { return nil }
 func (f fakeRemotecfg) Data() any                                        { return remotecfg.Data{} }
+func (f fakeRemotecfg) Ready() error                                     { return f.readyErr }
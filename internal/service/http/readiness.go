@@ -0,0 +1,63 @@
+package http
+
+import (
+	"fmt"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/service"
+)
+
+// ReadinessArguments configures which components and services must be ready
+// before the "/-/ready" endpoint reports Alloy as ready.
+type ReadinessArguments struct {
+	Components []string `alloy:"components,attr,optional"`
+	Services   []string `alloy:"services,attr,optional"`
+}
+
+// ReadinessReporter is an optional interface that services can implement to
+// take part in readiness checks. Services which don't implement
+// ReadinessReporter are considered ready as soon as they exist.
+type ReadinessReporter interface {
+	// Ready returns a non-nil error describing why the service isn't ready.
+	Ready() error
+}
+
+// notReadyReasons returns the reasons why the components and services
+// configured in args aren't ready. An empty result means that they're all
+// ready.
+func (args *ReadinessArguments) notReadyReasons(host service.Host) []string {
+	if args == nil {
+		return nil
+	}
+
+	var reasons []string
+	for _, id := range args.Components {
+		info, err := host.GetComponent(component.ParseID(id), component.InfoOptions{GetHealth: true})
+		if err != nil {
+			reasons = append(reasons, fmt.Sprintf("component %q not found", id))
+			continue
+		}
+		if info.Health.Health != component.HealthTypeHealthy {
+			reason := fmt.Sprintf("component %q is %s", id, info.Health.Health)
+			if info.Health.Message != "" {
+				reason += ": " + info.Health.Message
+			}
+			reasons = append(reasons, reason)
+		}
+	}
+
+	for _, name := range args.Services {
+		svc, found := host.GetService(name)
+		if !found {
+			reasons = append(reasons, fmt.Sprintf("service %q not found", name))
+			continue
+		}
+		if reporter, ok := svc.(ReadinessReporter); ok {
+			if err := reporter.Ready(); err != nil {
+				reasons = append(reasons, fmt.Sprintf("service %q is not ready: %s", name, err))
+			}
+		}
+	}
+
+	return reasons
+}
@@ -15,6 +15,7 @@ import (
 	"time"
 
 	"github.com/grafana/alloy/internal/build"
+	"github.com/grafana/alloy/internal/service"
 	"github.com/grafana/alloy/internal/static/server"
 	"github.com/mackerelio/go-osstat/uptime"
 	"gopkg.in/yaml.v3"
@@ -38,6 +39,8 @@ type Bundle struct {
 	environmentVariables []byte
 	sources              map[string][]byte
 	remoteCfg            []byte
+	componentGraph       []byte
+	recentLogs           []byte
 	heapBuf              *bytes.Buffer
 	goroutineBuf         *bytes.Buffer
 	blockBuf             *bytes.Buffer
@@ -53,24 +56,36 @@ type Metadata struct {
 	Uptime       float64 `yaml:"uptime"`
 }
 
+// SupportBundleOptions holds the information gathered by the caller of
+// ExportSupportBundle.
+type SupportBundleOptions struct {
+	RuntimeFlags   []string               // Flags Alloy was started with.
+	ServerAddress  string                 // Address of the HTTP server to query the API of.
+	Sources        map[string][]byte      // Redacted configuration files.
+	RemoteConfig   []byte                 // Redacted remote configuration cached on disk.
+	ComponentGraph []byte                 // DOT representation of the component graph.
+	RecentLogs     []byte                 // Logs written before the bundle was requested.
+	DialContext    server.DialContextFunc // Dials the HTTP server.
+}
+
 // ExportSupportBundle gathers the information required for the support bundle.
-func ExportSupportBundle(ctx context.Context, runtimeFlags []string, srvAddress string, sources map[string][]byte, remoteCfg []byte, dialContext server.DialContextFunc) (*Bundle, error) {
+func ExportSupportBundle(ctx context.Context, opts SupportBundleOptions) (*Bundle, error) {
 	var httpClient http.Client
-	httpClient.Transport = &http.Transport{DialContext: dialContext}
+	httpClient.Transport = &http.Transport{DialContext: opts.DialContext}
 
 	// Gather Alloy's own metrics.
-	alloyMetricsStart, err := retrieveAPIEndpoint(httpClient, srvAddress, "metrics")
+	alloyMetricsStart, err := retrieveAPIEndpoint(httpClient, opts.ServerAddress, "metrics")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get internal Alloy metrics: %s", err)
 	}
 
 	// Gather running component configuration
-	components, err := retrieveAPIEndpoint(httpClient, srvAddress, "api/v0/web/components")
+	components, err := retrieveAPIEndpoint(httpClient, opts.ServerAddress, "api/v0/web/components")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get component details: %s", err)
 	}
 	// Gather cluster peers information
-	peers, err := retrieveAPIEndpoint(httpClient, srvAddress, "api/v0/web/peers")
+	peers, err := retrieveAPIEndpoint(httpClient, opts.ServerAddress, "api/v0/web/peers")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get peer details: %s", err)
 	}
@@ -137,7 +152,7 @@ func ExportSupportBundle(ctx context.Context, runtimeFlags []string, srvAddress
 	}
 
 	// Gather Alloy's own metrics after the profile completes
-	alloyMetricsEnd, err := retrieveAPIEndpoint(httpClient, srvAddress, "metrics")
+	alloyMetricsEnd, err := retrieveAPIEndpoint(httpClient, opts.ServerAddress, "metrics")
 	if err != nil {
 		return nil, fmt.Errorf("failed to get internal Alloy metrics: %s", err)
 	}
@@ -150,9 +165,11 @@ func ExportSupportBundle(ctx context.Context, runtimeFlags []string, srvAddress
 		alloyMetricsEnd:      alloyMetricsEnd,
 		components:           components,
 		peers:                peers,
-		sources:              sources,
-		remoteCfg:            remoteCfg,
-		runtimeFlags:         []byte(strings.Join(runtimeFlags, "\n")),
+		sources:              opts.Sources,
+		remoteCfg:            opts.RemoteConfig,
+		componentGraph:       opts.ComponentGraph,
+		recentLogs:           opts.RecentLogs,
+		runtimeFlags:         []byte(strings.Join(opts.RuntimeFlags, "\n")),
 		environmentVariables: []byte(strings.Join(retrieveEnvironmentVariables(), "\n")),
 		heapBuf:              &heapBuf,
 		goroutineBuf:         &goroutineBuf,
@@ -178,6 +195,21 @@ func retrieveAPIEndpoint(httpClient http.Client, srvAddress, endpoint string) ([
 	return res, nil
 }
 
+// exportComponentGraph returns a DOT representation of the component graph of
+// the root module.
+func exportComponentGraph(host service.Host) ([]byte, error) {
+	gw, ok := host.(service.GraphWriter)
+	if !ok {
+		return nil, fmt.Errorf("graph export isn't supported by the host")
+	}
+
+	var buf bytes.Buffer
+	if err := gw.WriteGraph(&buf, "", "dot"); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
 func retrieveEnvironmentVariables() []string {
 	relevantVariables := []string{
 		"AUTOMEMLIMIT",
@@ -220,6 +252,8 @@ func ServeSupportBundle(rw http.ResponseWriter, b *Bundle, logsBuf *bytes.Buffer
 		"alloy-runtime-flags.txt":        b.runtimeFlags,
 		"alloy-environment.txt":          b.environmentVariables,
 		"alloy-logs.txt":                 logsBuf.Bytes(),
+		"alloy-logs-recent.txt":          b.recentLogs,
+		"alloy-components.dot":           b.componentGraph,
 		"pprof/cpu.pprof":                b.cpuBuf.Bytes(),
 		"pprof/heap.pprof":               b.heapBuf.Bytes(),
 		"pprof/goroutine.pprof":          b.goroutineBuf.Bytes(),
@@ -0,0 +1,121 @@
+package livedebugging
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// ErrRecordingLimitReached is returned by [Recorder.Record] once the recording
+// has reached its maximum size or duration.
+var ErrRecordingLimitReached = errors.New("recording limit reached")
+
+// RecordedData is a single entry of a live debugging recording. Recordings are
+// written as newline-delimited JSON, one RecordedData per line.
+type RecordedData struct {
+	Timestamp          time.Time `json:"timestamp"`
+	ComponentID        string    `json:"componentID"`
+	TargetComponentIDs []string  `json:"targetComponentIDs,omitempty"`
+	Type               string    `json:"type"`
+	Count              uint64    `json:"count"`
+	Data               string    `json:"data"`
+}
+
+// RecordingOptions bounds the size of a recording.
+type RecordingOptions struct {
+	// MaxBytes is the maximum number of bytes written to the recording.
+	// Entries which would exceed the limit are discarded. 0 means no limit.
+	MaxBytes int64
+	// MaxDuration is the maximum amount of time the recording accepts new
+	// entries, starting from the creation of the Recorder. 0 means no limit.
+	MaxDuration time.Duration
+}
+
+// Recorder writes live debugging data to an io.Writer so that a debugging
+// session can be stored and inspected later.
+type Recorder struct {
+	mut     sync.Mutex
+	w       io.Writer
+	opts    RecordingOptions
+	start   time.Time
+	written int64
+	done    bool
+}
+
+// NewRecorder creates a new Recorder writing to w.
+func NewRecorder(w io.Writer, opts RecordingOptions) *Recorder {
+	return &Recorder{
+		w:     w,
+		opts:  opts,
+		start: time.Now(),
+	}
+}
+
+// Record writes data to the recording. It returns ErrRecordingLimitReached
+// once the recording is full; no more data is written after that.
+func (r *Recorder) Record(data Data) error {
+	entry := RecordedData{
+		Timestamp:          time.Now(),
+		ComponentID:        string(data.ComponentID),
+		TargetComponentIDs: data.TargetComponentIDs,
+		Type:               string(data.Type),
+		Count:              data.Count,
+	}
+	if data.DataFunc != nil {
+		entry.Data = data.DataFunc()
+	}
+
+	bb, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	bb = append(bb, '\n')
+
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	if r.done {
+		return ErrRecordingLimitReached
+	}
+	if r.opts.MaxDuration > 0 && entry.Timestamp.Sub(r.start) > r.opts.MaxDuration {
+		r.done = true
+		return ErrRecordingLimitReached
+	}
+	if r.opts.MaxBytes > 0 && r.written+int64(len(bb)) > r.opts.MaxBytes {
+		r.done = true
+		return ErrRecordingLimitReached
+	}
+
+	n, err := r.w.Write(bb)
+	r.written += int64(n)
+	return err
+}
+
+// Written returns the number of bytes written to the recording so far.
+func (r *Recorder) Written() int64 {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+	return r.written
+}
+
+// ReadRecording reads all entries from a recording created by a Recorder.
+func ReadRecording(r io.Reader) ([]RecordedData, error) {
+	var (
+		entries []RecordedData
+		dec     = json.NewDecoder(bufio.NewReader(r))
+	)
+	for {
+		var entry RecordedData
+		err := dec.Decode(&entry)
+		if errors.Is(err, io.EOF) {
+			return entries, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("reading entry %d: %w", len(entries)+1, err)
+		}
+		entries = append(entries, entry)
+	}
+}
@@ -0,0 +1,62 @@
+package livedebugging
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder(t *testing.T) {
+	var buf bytes.Buffer
+	recorder := NewRecorder(&buf, RecordingOptions{})
+
+	require.NoError(t, recorder.Record(NewData("fake.liveDebugging", LokiLog, 1, func() string { return "log 1" })))
+	require.NoError(t, recorder.Record(NewData("fake.liveDebugging", OtelTrace, 2, func() string { return "trace 1" }, WithTargetComponentIDs([]string{"component1"}))))
+	require.Equal(t, int64(buf.Len()), recorder.Written())
+
+	entries, err := ReadRecording(&buf)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+
+	require.Equal(t, "fake.liveDebugging", entries[0].ComponentID)
+	require.Equal(t, string(LokiLog), entries[0].Type)
+	require.Equal(t, uint64(1), entries[0].Count)
+	require.Equal(t, "log 1", entries[0].Data)
+	require.Empty(t, entries[0].TargetComponentIDs)
+
+	require.Equal(t, string(OtelTrace), entries[1].Type)
+	require.Equal(t, []string{"component1"}, entries[1].TargetComponentIDs)
+	require.Equal(t, "trace 1", entries[1].Data)
+}
+
+func TestRecorder_MaxBytes(t *testing.T) {
+	var buf bytes.Buffer
+	recorder := NewRecorder(&buf, RecordingOptions{MaxBytes: 150})
+
+	data := NewData("fake.liveDebugging", LokiLog, 1, func() string { return "log line" })
+	require.NoError(t, recorder.Record(data))
+	require.ErrorIs(t, recorder.Record(data), ErrRecordingLimitReached)
+	// The recording is done once the limit has been reached.
+	require.ErrorIs(t, recorder.Record(data), ErrRecordingLimitReached)
+
+	entries, err := ReadRecording(&buf)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+}
+
+func TestRecorder_MaxDuration(t *testing.T) {
+	var buf bytes.Buffer
+	recorder := NewRecorder(&buf, RecordingOptions{MaxDuration: 10 * time.Millisecond})
+
+	data := NewData("fake.liveDebugging", LokiLog, 1, func() string { return "log line" })
+	require.NoError(t, recorder.Record(data))
+	time.Sleep(20 * time.Millisecond)
+	require.ErrorIs(t, recorder.Record(data), ErrRecordingLimitReached)
+}
+
+func TestReadRecording_Invalid(t *testing.T) {
+	_, err := ReadRecording(bytes.NewBufferString("{\"componentID\":\"a\"}\nnot json\n"))
+	require.ErrorContains(t, err, "reading entry 2")
+}
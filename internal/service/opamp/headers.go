@@ -0,0 +1,72 @@
+package opamp
+
+import (
+	"net/http"
+
+	"github.com/grafana/alloy/internal/component/common/config"
+	commonconfig "github.com/prometheus/common/config"
+)
+
+// headersRoundTripper returns a round tripper which sets the authentication
+// and custom headers of the arguments on the requests, like the round
+// trippers of an HTTP client created from the same settings would. The
+// requests aren't sent, as the OpAMP client sends them itself.
+func headersRoundTripper(args Arguments) http.RoundTripper {
+	var rt http.RoundTripper = captureRoundTripper{}
+
+	switch {
+	case args.BasicAuth != nil:
+		var password commonconfig.SecretReader = commonconfig.NewInlineSecret(string(args.BasicAuth.Password))
+		if args.BasicAuth.PasswordFile != "" {
+			password = commonconfig.NewFileSecret(args.BasicAuth.PasswordFile)
+		}
+		rt = commonconfig.NewBasicAuthRoundTripper(commonconfig.NewInlineSecret(args.BasicAuth.Username), password, rt)
+	case args.Authorization != nil:
+		rt = authorizationRoundTripper(args.Authorization, rt)
+	case args.BearerToken != "":
+		rt = authorizationRoundTripper(&config.Authorization{Credentials: args.BearerToken}, rt)
+	case args.BearerTokenFile != "":
+		rt = authorizationRoundTripper(&config.Authorization{CredentialsFile: args.BearerTokenFile}, rt)
+	}
+
+	if args.HTTPHeaders != nil {
+		rt = commonconfig.NewHeadersRoundTripper(args.HTTPHeaders.Convert(), rt)
+	}
+	return rt
+}
+
+func authorizationRoundTripper(a *config.Authorization, rt http.RoundTripper) http.RoundTripper {
+	authType := a.Type
+	if authType == "" {
+		authType = "Bearer"
+	}
+	var credentials commonconfig.SecretReader = commonconfig.NewInlineSecret(string(a.Credentials))
+	if a.CredentialsFile != "" {
+		credentials = commonconfig.NewFileSecret(a.CredentialsFile)
+	}
+	return commonconfig.NewAuthorizationCredentialsRoundTripper(authType, credentials, rt)
+}
+
+// requestHeaders returns the headers of a request to endpoint, once rt has
+// set the headers of the arguments on top of header.
+func requestHeaders(rt http.RoundTripper, endpoint string, header http.Header) (http.Header, error) {
+	req, err := http.NewRequest(http.MethodPost, endpoint, http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header
+	resp, err := rt.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Request.Header, nil
+}
+
+// captureRoundTripper returns a response to the requests without sending
+// them, so that the headers set by the other round trippers can be read from
+// the request of the response.
+type captureRoundTripper struct{}
+
+func (captureRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: http.StatusOK, Request: req, Body: http.NoBody}, nil
+}
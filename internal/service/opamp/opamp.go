@@ -0,0 +1,810 @@
+// Package opamp implements a service which lets Alloy be managed by any
+// control plane implementing the Open Agent Management Protocol (OpAMP).
+package opamp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/google/uuid"
+	"github.com/grafana/alloy/internal/alloyseed"
+	"github.com/grafana/alloy/internal/component/common/config"
+	"github.com/grafana/alloy/internal/featuregate"
+	alloy_runtime "github.com/grafana/alloy/internal/runtime"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/service"
+	"github.com/grafana/alloy/internal/util/jitter"
+	"github.com/grafana/alloy/syntax"
+	"github.com/grafana/alloy/syntax/alloytypes"
+	"github.com/grafana/alloy/syntax/ast"
+	"github.com/open-telemetry/opamp-go/client"
+	"github.com/open-telemetry/opamp-go/client/types"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	commonconfig "github.com/prometheus/common/config"
+	"google.golang.org/protobuf/proto"
+)
+
+// ServiceName defines the name used for the opamp service.
+const ServiceName = "opamp"
+
+const (
+	baseJitter = 100 * time.Millisecond
+
+	minPollFrequency = 10 * time.Second
+	stopTimeout      = 5 * time.Second
+)
+
+// Attribute keys of the agent description which identify the instance, and
+// which can't be set with the attributes argument.
+var identifyingAttributes = []string{"service.name", "service.version", "service.instance.id"}
+
+// Options are used to configure the opamp service. Options are constant for
+// the lifetime of the opamp service.
+type Options struct {
+	Logger      log.Logger            // Where to send logs.
+	StoragePath string                // Where to cache configuration on-disk.
+	ConfigPath  string                // Where the root config file is.
+	Metrics     prometheus.Registerer // Where to send metrics to.
+}
+
+// Arguments holds runtime settings for the opamp service.
+//
+// Only the HTTP client settings which can be applied to both the HTTP and the
+// WebSocket transports of the OpAMP client are supported.
+type Arguments struct {
+	URL                      string                `alloy:"url,attr,optional"`
+	InstanceUID              string                `alloy:"instance_uid,attr,optional"`
+	Attributes               map[string]string     `alloy:"attributes,attr,optional"`
+	PollFrequency            time.Duration         `alloy:"poll_frequency,attr,optional"`
+	AcceptConnectionSettings bool                  `alloy:"accept_connection_settings,attr,optional"`
+	BasicAuth                *config.BasicAuth     `alloy:"basic_auth,block,optional"`
+	Authorization            *config.Authorization `alloy:"authorization,block,optional"`
+	BearerToken              alloytypes.Secret     `alloy:"bearer_token,attr,optional"`
+	BearerTokenFile          string                `alloy:"bearer_token_file,attr,optional"`
+	TLSConfig                config.TLSConfig      `alloy:"tls_config,block,optional"`
+	FollowRedirects          bool                  `alloy:"follow_redirects,attr,optional"`
+	HTTPHeaders              *config.Headers       `alloy:",squash"`
+}
+
+// GetDefaultArguments populates the default values for the Arguments struct.
+func GetDefaultArguments() Arguments {
+	return Arguments{
+		InstanceUID:     alloyseed.Get().UID,
+		Attributes:      make(map[string]string),
+		PollFrequency:   30 * time.Second,
+		FollowRedirects: true,
+	}
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (a *Arguments) SetToDefault() {
+	*a = GetDefaultArguments()
+}
+
+// Validate implements syntax.Validator.
+func (a *Arguments) Validate() error {
+	if a.URL != "" {
+		if err := validateEndpoint(a.URL); err != nil {
+			return fmt.Errorf("invalid url: %w", err)
+		}
+	}
+
+	if a.PollFrequency < minPollFrequency {
+		return fmt.Errorf("poll_frequency must be at least %q, got %q", minPollFrequency, a.PollFrequency)
+	}
+
+	if _, err := uuid.Parse(a.InstanceUID); err != nil {
+		return fmt.Errorf("instance_uid must be a UUID: %w", err)
+	}
+
+	for _, k := range identifyingAttributes {
+		if _, ok := a.Attributes[k]; ok {
+			return fmt.Errorf("the %q attribute is reserved", k)
+		}
+	}
+
+	authCount := 0
+	for _, set := range []bool{a.BasicAuth != nil, a.Authorization != nil, a.BearerToken != "", a.BearerTokenFile != ""} {
+		if set {
+			authCount++
+		}
+	}
+	if authCount > 1 {
+		return fmt.Errorf("at most one of basic_auth, authorization, bearer_token & bearer_token_file must be configured")
+	}
+
+	// We must explicitly Validate because HTTPHeaders is squashed and it won't
+	// run otherwise.
+	return a.HTTPHeaders.Validate()
+}
+
+// validateEndpoint checks that the scheme of an OpAMP server endpoint is
+// supported by one of the transports of the OpAMP client.
+func validateEndpoint(endpoint string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return err
+	}
+	switch u.Scheme {
+	case "http", "https", "ws", "wss":
+		return nil
+	default:
+		return fmt.Errorf("unsupported scheme %q, must be one of http, https, ws, or wss", u.Scheme)
+	}
+}
+
+// Hash marshals the Arguments and returns a hash representation.
+func (a *Arguments) Hash() (string, error) {
+	b, err := syntax.Marshal(a)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal arguments: %w", err)
+	}
+	return getHash(b), nil
+}
+
+func getHash(in []byte) string {
+	fnvHash := fnv.New32()
+	fnvHash.Write(in)
+	return fmt.Sprintf("%x", fnvHash.Sum(nil))
+}
+
+// connectionSettings are the OpAMP connection settings offered by the server,
+// which override the arguments when accept_connection_settings is true.
+type connectionSettings struct {
+	endpoint      string
+	headers       http.Header
+	pollFrequency time.Duration
+}
+
+func (cs connectionSettings) equal(other connectionSettings) bool {
+	return cs.endpoint == other.endpoint &&
+		cs.pollFrequency == other.pollFrequency &&
+		maps.EqualFunc(cs.headers, other.headers, slices.Equal)
+}
+
+// Service implements a service for OpAMP. The service connects to the OpAMP
+// server with the HTTP or WebSocket transport of the OpAMP client, reports
+// the status of Alloy, and loads the remote configuration sent by the server
+// into an isolated controller.
+type Service struct {
+	opts Options
+
+	ctrl service.Controller
+
+	mut         sync.RWMutex
+	args        Arguments
+	argsHash    string
+	instanceUID uuid.UUID
+	offered     connectionSettings
+	dataPath    string
+	startTime   time.Time
+	metrics     *metrics
+	restartChan chan struct{}
+
+	// health and packageStatuses are the last ones reported to the server.
+	health          *protobufs.ComponentHealth
+	packageStatuses *protobufs.PackageStatuses
+
+	// These hold the state of the remote configuration, which is reported
+	// back to the server.
+	remoteConfigHash    []byte
+	lastLoadedConfigMap *protobufs.AgentConfigMap
+	lastApplyErr        error
+	effectiveConfig     *protobufs.AgentConfigMap
+
+	// This is the AST file parsed from the configuration. This is used
+	// for the support bundle
+	astFile *ast.File
+}
+
+type metrics struct {
+	lastRequestSuccess     prometheus.Gauge
+	lastRequestSuccessTime prometheus.Gauge
+	totalFailures          prometheus.Counter
+	configHash             *prometheus.GaugeVec
+}
+
+var _ service.Service = (*Service)(nil)
+
+// New returns a new instance of the opamp service.
+func New(opts Options) (*Service, error) {
+	basePath := filepath.Join(opts.StoragePath, ServiceName)
+	err := os.MkdirAll(basePath, 0750)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Service{
+		opts:            opts,
+		restartChan:     make(chan struct{}, 1),
+		packageStatuses: packageStatusesFor(nil),
+		startTime:       time.Now(),
+	}, nil
+}
+
+func (s *Service) registerMetrics() {
+	prom := promauto.With(s.opts.Metrics)
+	s.metrics = &metrics{
+		lastRequestSuccess: prom.NewGauge(prometheus.GaugeOpts{
+			Name: "opamp_last_request_successful",
+			Help: "Whether the last attempt to connect or send a request to the OpAMP server was successful.",
+		}),
+		lastRequestSuccessTime: prom.NewGauge(prometheus.GaugeOpts{
+			Name: "opamp_last_request_success_timestamp_seconds",
+			Help: "Timestamp of the last successful connection or request to the OpAMP server.",
+		}),
+		totalFailures: prom.NewCounter(prometheus.CounterOpts{
+			Name: "opamp_request_failures_total",
+			Help: "Failed attempts to connect or send a request to the OpAMP server.",
+		}),
+		configHash: prom.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "opamp_config_hash",
+			Help: "Hash of the currently active remote configuration.",
+		}, []string{"hash"}),
+	}
+}
+
+// Data returns an instance of [Data]. Calls to Data are cachable by the
+// caller.
+// Data must only be called after Run.
+func (s *Service) Data() any {
+	s.mut.RLock()
+	ctrl := s.ctrl
+	s.mut.RUnlock()
+
+	if ctrl == nil {
+		return Data{Host: nil}
+	}
+	return Data{Host: ctrl.(alloy_runtime.ServiceController).GetHost()}
+}
+
+// Data includes information associated with the opamp service.
+type Data struct {
+	// Host exposes the Host of the isolated controller that is created by the
+	// opamp service.
+	Host service.Host
+}
+
+// Definition returns the definition of the opamp service.
+func (s *Service) Definition() service.Definition {
+	return service.Definition{
+		Name:       ServiceName,
+		ConfigType: Arguments{},
+		DependsOn:  nil, // opamp has no dependencies.
+		Stability:  featuregate.StabilityExperimental,
+	}
+}
+
+// Run implements [service.Service] and starts the opamp service. It will run
+// until the provided context is canceled or there is a fatal error.
+func (s *Service) Run(ctx context.Context, host service.Host) error {
+	// The controller is created without holding mut, as creating it may call
+	// back into the services, including this one.
+	ctrl := host.NewController(ServiceName)
+	s.mut.Lock()
+	s.ctrl = ctrl
+	s.mut.Unlock()
+
+	// Run the service's own controller.
+	go func() {
+		ctrl.Run(ctx)
+	}()
+
+	// Updates made before Run are handled by starting the first client.
+	select {
+	case <-s.restartChan:
+	default:
+	}
+	c := s.startClient()
+
+	ticker := jitter.NewTicker(s.getPollFrequency(), baseJitter)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.updateHealth(c)
+		case <-s.restartChan:
+			s.stopClient(c)
+			c = s.startClient()
+			ticker.Reset(s.getPollFrequency())
+		case <-ctx.Done():
+			s.stopClient(c)
+			return nil
+		}
+	}
+}
+
+// Update implements [service.Service] and applies settings.
+func (s *Service) Update(newConfig any) error {
+	newArgs := newConfig.(Arguments)
+
+	hash, err := newArgs.Hash()
+	if err != nil {
+		return err
+	}
+
+	s.mut.Lock()
+	if hash == s.argsHash {
+		s.mut.Unlock()
+		return nil
+	}
+
+	// Settings offered by a server don't apply to another one.
+	if newArgs.URL != s.args.URL || !newArgs.AcceptConnectionSettings {
+		s.offered = connectionSettings{}
+	}
+
+	if newArgs.URL != "" {
+		if newArgs.InstanceUID != s.args.InstanceUID {
+			s.instanceUID = uuid.MustParse(newArgs.InstanceUID) // Checked by Validate.
+		}
+		s.dataPath = filepath.Join(s.opts.StoragePath, ServiceName, hash)
+		if s.metrics == nil {
+			s.registerMetrics()
+		}
+	}
+	s.args = newArgs
+	s.argsHash = hash
+	s.mut.Unlock()
+
+	// Reconnect to the server with the new arguments if Run was called.
+	s.restart()
+	return nil
+}
+
+// Ready returns an error until a configuration has been successfully loaded,
+// either from the server or from the on-disk cache. The service is always
+// ready when it isn't configured.
+func (s *Service) Ready() error {
+	if !s.isEnabled() {
+		return nil
+	}
+	if s.GetCachedAstFile() == nil {
+		return errors.New("no remote configuration has been loaded yet")
+	}
+	return nil
+}
+
+// GetCachedAstFile returns the AST file that was parsed from the configuration.
+func (s *Service) GetCachedAstFile() *ast.File {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+	return s.astFile
+}
+
+// startClient starts an OpAMP client with the current arguments and the
+// connection settings offered by the server. The cached configuration is
+// loaded first when no configuration has been loaded yet, so that Alloy runs
+// the last known configuration until the server sends one. It returns nil
+// when the service isn't configured or the client can't be started.
+func (s *Service) startClient() client.OpAMPClient {
+	if !s.isEnabled() {
+		return nil
+	}
+	if s.GetCachedAstFile() == nil {
+		s.loadCached()
+	}
+
+	health := s.currentHealth()
+
+	s.mut.Lock()
+	args := s.args
+	offered := s.offered
+	uid := s.instanceUID
+	s.health = health
+	remoteConfigStatus := s.remoteConfigStatus()
+	packageStatuses := s.packageStatuses
+	s.mut.Unlock()
+
+	endpoint := args.URL
+	if offered.endpoint != "" {
+		endpoint = offered.endpoint
+	}
+	pollFrequency := args.PollFrequency
+	if offered.pollFrequency > 0 {
+		pollFrequency = offered.pollFrequency
+	}
+
+	settings, err := s.startSettings(args, endpoint)
+	if err != nil {
+		level.Error(s.opts.Logger).Log("msg", "failed to configure the OpAMP client", "err", err)
+		return nil
+	}
+
+	logger := clientLogger{logger: s.opts.Logger}
+	var c client.OpAMPClient
+	if u, _ := url.Parse(endpoint); u.Scheme == "ws" || u.Scheme == "wss" {
+		c = client.NewWebSocket(logger)
+	} else {
+		c = client.NewHTTP(logger)
+	}
+
+	settings.Header = offered.headers
+	settings.InstanceUid = types.InstanceUid(uid)
+	settings.Callbacks = s.callbacks(c)
+	settings.RemoteConfigStatus = remoteConfigStatus
+	settings.PackagesStateProvider = packagesStateProvider{statuses: packageStatuses}
+	// The package capabilities can only be set with a PackagesStateProvider
+	// by passing them to Start.
+	settings.Capabilities = capabilitiesFor(args)
+	settings.HeartbeatInterval = &pollFrequency
+
+	if err := c.SetAgentDescription(agentDescriptionFor(args, uid)); err != nil {
+		level.Error(s.opts.Logger).Log("msg", "failed to set the agent description", "err", err)
+		return nil
+	}
+	if err := c.SetHealth(health); err != nil {
+		level.Error(s.opts.Logger).Log("msg", "failed to set the health", "err", err)
+		return nil
+	}
+	// The context is only used while starting the client.
+	if err := c.Start(context.Background(), settings); err != nil {
+		level.Error(s.opts.Logger).Log("msg", "failed to start the OpAMP client", "err", err)
+		return nil
+	}
+	return c
+}
+
+// startSettings returns the settings of the connection to the server from the
+// HTTP client settings of the arguments.
+func (s *Service) startSettings(args Arguments, endpoint string) (types.StartSettings, error) {
+	var settings types.StartSettings
+	settings.OpAMPServerURL = endpoint
+
+	// The TLS configuration makes the WebSocket transport use the wss scheme,
+	// so it's only set for secure endpoints.
+	if u, _ := url.Parse(endpoint); u.Scheme == "https" || u.Scheme == "wss" {
+		tlsConfig, err := commonconfig.NewTLSConfig(args.TLSConfig.Convert())
+		if err != nil {
+			return settings, err
+		}
+		settings.TLSConfig = tlsConfig
+	}
+
+	rt := headersRoundTripper(args)
+	settings.HeaderFunc = func(h http.Header) http.Header {
+		res, err := requestHeaders(rt, endpoint, h)
+		if err != nil {
+			level.Error(s.opts.Logger).Log("msg", "failed to set the headers of the request to the OpAMP server", "err", err)
+			return h
+		}
+		return res
+	}
+	return settings, nil
+}
+
+func (s *Service) stopClient(c client.OpAMPClient) {
+	if c == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), stopTimeout)
+	defer cancel()
+	if err := c.Stop(ctx); err != nil {
+		level.Warn(s.opts.Logger).Log("msg", "failed to stop the OpAMP client", "err", err)
+	}
+}
+
+// restart makes Run restart the client with the current settings.
+func (s *Service) restart() {
+	select {
+	// If the channel is full it means there's already a restart triggered or
+	// Run is not running. In both cases, we don't need to trigger another
+	// restart or block.
+	case s.restartChan <- struct{}{}:
+	default:
+	}
+}
+
+func (s *Service) callbacks(c client.OpAMPClient) types.Callbacks {
+	s.mut.RLock()
+	followRedirects := s.args.FollowRedirects
+	s.mut.RUnlock()
+
+	callbacks := types.Callbacks{
+		OnConnect: func(context.Context) {
+			s.metrics.lastRequestSuccess.Set(1)
+			s.metrics.lastRequestSuccessTime.SetToCurrentTime()
+		},
+		OnConnectFailed: func(context.Context, error) {
+			// The error is logged by the client, which retries with a
+			// backoff.
+			s.metrics.totalFailures.Inc()
+			s.metrics.lastRequestSuccess.Set(0)
+		},
+		OnError: func(_ context.Context, resp *protobufs.ServerErrorResponse) {
+			level.Error(s.opts.Logger).Log("msg", "the OpAMP server returned an error", "type", resp.Type, "err", resp.ErrorMessage)
+		},
+		OnMessage: func(ctx context.Context, msg *types.MessageData) {
+			s.handleMessage(ctx, c, msg)
+		},
+		OnOpampConnectionSettings: func(_ context.Context, settings *protobufs.OpAMPConnectionSettings) error {
+			return s.applyConnectionSettings(settings)
+		},
+		GetEffectiveConfig: func(context.Context) (*protobufs.EffectiveConfig, error) {
+			return s.getEffectiveConfig(), nil
+		},
+	}
+	if !followRedirects {
+		callbacks.CheckRedirect = func(req *http.Request, _ []*http.Request, _ []*http.Response) error {
+			return fmt.Errorf("not following the redirect to %s, as follow_redirects is false", req.URL.Redacted())
+		}
+	}
+	return callbacks
+}
+
+func (s *Service) handleMessage(ctx context.Context, c client.OpAMPClient, msg *types.MessageData) {
+	if msg.AgentIdentification != nil {
+		// The length of the instance UID is checked by the client.
+		uid := uuid.UUID(msg.AgentIdentification.NewInstanceUid)
+		s.mut.Lock()
+		changed := uid != s.instanceUID
+		s.instanceUID = uid
+		args := s.args
+		s.mut.Unlock()
+
+		// The server may assign the same instance UID in each response.
+		if changed {
+			level.Info(s.opts.Logger).Log("msg", "using the instance UID assigned by the OpAMP server", "instance_uid", uid)
+			if err := c.SetAgentDescription(agentDescriptionFor(args, uid)); err != nil {
+				level.Error(s.opts.Logger).Log("msg", "failed to report the new instance UID", "err", err)
+			}
+		}
+	}
+
+	if msg.PackagesAvailable != nil {
+		statuses := packageStatusesFor(msg.PackagesAvailable)
+		s.mut.Lock()
+		s.packageStatuses = statuses
+		s.mut.Unlock()
+		if err := c.SetPackageStatuses(statuses); err != nil {
+			level.Error(s.opts.Logger).Log("msg", "failed to report the package statuses", "err", err)
+		}
+	}
+
+	if msg.RemoteConfig != nil {
+		s.applyRemoteConfig(ctx, c, msg.RemoteConfig)
+	}
+}
+
+func (s *Service) applyConnectionSettings(offer *protobufs.OpAMPConnectionSettings) error {
+	var settings connectionSettings
+	if ep := offer.DestinationEndpoint; ep != "" {
+		if err := validateEndpoint(ep); err != nil {
+			return fmt.Errorf("invalid destination endpoint: %w", err)
+		}
+		settings.endpoint = ep
+	}
+	for _, h := range offer.GetHeaders().GetHeaders() {
+		if settings.headers == nil {
+			settings.headers = make(http.Header)
+		}
+		settings.headers.Add(h.Key, h.Value)
+	}
+	if sec := offer.HeartbeatIntervalSeconds; sec > 0 {
+		settings.pollFrequency = time.Duration(sec) * time.Second
+	}
+
+	s.mut.Lock()
+	unchanged := settings.equal(s.offered)
+	s.offered = settings
+	s.mut.Unlock()
+
+	// The server may send the same settings in each response.
+	if unchanged {
+		return nil
+	}
+
+	level.Info(s.opts.Logger).Log("msg", "applying the connection settings offered by the OpAMP server", "endpoint", settings.endpoint, "poll_frequency", settings.pollFrequency)
+	s.restart()
+	return nil
+}
+
+func (s *Service) applyRemoteConfig(ctx context.Context, c client.OpAMPClient, rc *protobufs.AgentRemoteConfig) {
+	hash := rc.ConfigHash
+	if hash == nil {
+		// The client requires a hash to report the status.
+		hash = []byte{}
+	}
+	config := rc.GetConfig()
+
+	s.mut.Lock()
+	s.remoteConfigHash = hash
+	unchanged := s.lastLoadedConfigMap != nil && proto.Equal(s.lastLoadedConfigMap, config)
+	s.mut.Unlock()
+
+	// The server may send the same remote configuration in each response,
+	// there's no need to reload it. Its status is reported regardless, as its
+	// hash may have changed.
+	if !unchanged {
+		if err := s.parseAndLoad(config); err != nil {
+			level.Error(s.opts.Logger).Log("msg", "failed to load the remote configuration", "err", err)
+		} else {
+			// If successful, flush to disk and keep a copy.
+			s.setCachedConfig(concatConfig(config))
+		}
+	}
+
+	s.mut.RLock()
+	status := s.remoteConfigStatus()
+	s.mut.RUnlock()
+	if err := c.SetRemoteConfigStatus(status); err != nil {
+		level.Error(s.opts.Logger).Log("msg", "failed to report the remote configuration status", "err", err)
+	}
+	if unchanged {
+		return
+	}
+	if err := c.UpdateEffectiveConfig(ctx); err != nil {
+		level.Error(s.opts.Logger).Log("msg", "failed to report the effective configuration", "err", err)
+	}
+	s.updateHealth(c)
+}
+
+// concatConfig returns the content of the files of a configuration map,
+// sorted by name.
+func concatConfig(config *protobufs.AgentConfigMap) []byte {
+	files := config.GetConfigMap()
+	var b []byte
+	for _, k := range slices.Sorted(maps.Keys(files)) {
+		b = append(b, files[k].GetBody()...)
+		if len(b) > 0 && b[len(b)-1] != '\n' {
+			b = append(b, '\n')
+		}
+	}
+	return b
+}
+
+func (s *Service) parseAndLoad(config *protobufs.AgentConfigMap) error {
+	s.mut.Lock()
+	ctrl := s.ctrl
+	s.lastLoadedConfigMap = config
+	s.mut.Unlock()
+
+	content := concatConfig(config)
+	file, err := ctrl.LoadSource(content, nil, s.opts.ConfigPath)
+
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.lastApplyErr = err
+	if err != nil {
+		return err
+	}
+	s.effectiveConfig = config
+	s.astFile = file
+	if s.metrics != nil {
+		s.metrics.configHash.Reset()
+		s.metrics.configHash.WithLabelValues(getHash(content)).Set(1)
+	}
+	return nil
+}
+
+func (s *Service) loadCached() {
+	s.mut.RLock()
+	p := s.dataPath
+	s.mut.RUnlock()
+
+	b, err := os.ReadFile(p)
+	if errors.Is(err, os.ErrNotExist) {
+		return
+	} else if err != nil {
+		level.Error(s.opts.Logger).Log("msg", "failed to read from cache", "err", err)
+		return
+	}
+
+	// The cached configuration is the concatenation of the last configuration
+	// map, which is reported as a single file.
+	err = s.parseAndLoad(&protobufs.AgentConfigMap{
+		ConfigMap: map[string]*protobufs.AgentConfigFile{"": {Body: b}},
+	})
+	if err != nil {
+		level.Error(s.opts.Logger).Log("msg", "failed to load from cache", "err", err)
+	}
+
+	// The configuration wasn't received from the server, which must send it
+	// again.
+	s.mut.Lock()
+	s.lastLoadedConfigMap = nil
+	s.mut.Unlock()
+}
+
+func (s *Service) setCachedConfig(b []byte) {
+	s.mut.RLock()
+	p := s.dataPath
+	s.mut.RUnlock()
+
+	err := os.WriteFile(p, b, 0750)
+	if err != nil {
+		level.Error(s.opts.Logger).Log("msg", "failed to flush remote configuration contents the on-disk cache", "err", err)
+	}
+}
+
+func (s *Service) getEffectiveConfig() *protobufs.EffectiveConfig {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+	if s.effectiveConfig == nil {
+		return &protobufs.EffectiveConfig{ConfigMap: &protobufs.AgentConfigMap{}}
+	}
+	return &protobufs.EffectiveConfig{ConfigMap: s.effectiveConfig}
+}
+
+// updateHealth reports the health of Alloy to the server when it changed
+// since it was last reported.
+func (s *Service) updateHealth(c client.OpAMPClient) {
+	if c == nil {
+		return
+	}
+
+	health := s.currentHealth()
+
+	s.mut.Lock()
+	changed := !healthEqual(s.health, health)
+	if changed {
+		s.health = health
+	}
+	s.mut.Unlock()
+
+	if !changed {
+		return
+	}
+	if err := c.SetHealth(health); err != nil {
+		level.Error(s.opts.Logger).Log("msg", "failed to report the health", "err", err)
+	}
+}
+
+func (s *Service) isEnabled() bool {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+	return s.args.URL != ""
+}
+
+// getPollFrequency returns how often the health of Alloy is checked, which is
+// the poll frequency of the client.
+func (s *Service) getPollFrequency() time.Duration {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+	switch {
+	case s.offered.pollFrequency > 0:
+		return s.offered.pollFrequency
+	case s.args.PollFrequency > 0:
+		return s.args.PollFrequency
+	default:
+		return GetDefaultArguments().PollFrequency
+	}
+}
+
+// clientLogger sends the logs of the OpAMP client to the logger of the
+// service.
+type clientLogger struct {
+	logger log.Logger
+}
+
+var _ types.Logger = clientLogger{}
+
+// deprecatedCapabilitiesMsg is logged by the OpAMP client when the
+// capabilities are passed to Start. It's ignored, as the package capabilities
+// can only be set this way with a PackagesStateProvider.
+const deprecatedCapabilitiesMsg = "settings.Capabilities is deprecated, use client.SetCapabilities() instead"
+
+func (l clientLogger) Debugf(_ context.Context, format string, v ...any) {
+	level.Debug(l.logger).Log("msg", fmt.Sprintf(format, v...))
+}
+
+func (l clientLogger) Errorf(_ context.Context, format string, v ...any) {
+	if format == deprecatedCapabilitiesMsg {
+		return
+	}
+	level.Error(l.logger).Log("msg", fmt.Sprintf(format, v...))
+}
@@ -0,0 +1,552 @@
+package opamp
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafana/alloy/internal/build"
+	"github.com/grafana/alloy/internal/component"
+	_ "github.com/grafana/alloy/internal/component/loki/process"
+	"github.com/grafana/alloy/internal/featuregate"
+	alloy_runtime "github.com/grafana/alloy/internal/runtime"
+	"github.com/grafana/alloy/internal/runtime/logging"
+	"github.com/grafana/alloy/internal/service"
+	"github.com/grafana/alloy/internal/service/livedebugging"
+	"github.com/grafana/alloy/internal/util"
+	"github.com/grafana/alloy/syntax"
+	"github.com/grafana/alloy/syntax/ast"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"github.com/open-telemetry/opamp-go/server"
+	servertypes "github.com/open-telemetry/opamp-go/server/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+const testInstanceUID = "0192c5d4-5b1a-7b2e-9c3d-4e5f60718293"
+
+// transports runs a test with both transports of the OpAMP client, passing
+// it the function returning the URL of a server for the transport.
+func transports(t *testing.T, test func(t *testing.T, serverURL func(*fakeServer) string)) {
+	t.Run("http", func(t *testing.T) {
+		test(t, func(srv *fakeServer) string { return srv.URL })
+	})
+	t.Run("websocket", func(t *testing.T) {
+		test(t, func(srv *fakeServer) string { return "ws" + strings.TrimPrefix(srv.URL, "http") })
+	})
+}
+
+func TestRemoteConfig(t *testing.T) {
+	transports(t, func(t *testing.T, serverURL func(*fakeServer) string) {
+		cfgA := `loki.process "a" { forward_to = [] }`
+		cfgB := `loki.process "b" { forward_to = [] }`
+
+		srv := newFakeServer(t)
+		srv.setResponse(&protobufs.ServerToAgent{RemoteConfig: &protobufs.AgentRemoteConfig{
+			Config: &protobufs.AgentConfigMap{ConfigMap: map[string]*protobufs.AgentConfigFile{
+				"b.alloy": {Body: []byte(cfgB)},
+				"a.alloy": {Body: []byte(cfgA)},
+			}},
+			ConfigHash: []byte("good"),
+		}})
+
+		env := newTestEnvironment(t)
+		require.NoError(t, env.ApplyConfig(fmt.Sprintf(`url = %q`, serverURL(srv))))
+		env.Run(t)
+
+		require.EventuallyWithT(t, func(c *assert.CollectT) {
+			state := srv.agentState()
+			assert.Equal(c, "good", string(state.GetRemoteConfigStatus().GetLastRemoteConfigHash()))
+			assert.Equal(c, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED, state.GetRemoteConfigStatus().GetStatus())
+			assert.Len(c, state.GetEffectiveConfig().GetConfigMap().GetConfigMap(), 2)
+			assert.True(c, state.GetHealth().GetHealthy())
+			assert.Contains(c, state.GetHealth().GetComponentHealthMap(), "opamp/loki.process.a")
+			assert.Contains(c, state.GetHealth().GetComponentHealthMap(), "opamp/loki.process.b")
+		}, 5*time.Second, 10*time.Millisecond)
+		require.NoError(t, env.svc.Ready())
+
+		// The configuration files are loaded and cached in the order of their
+		// names.
+		b, err := os.ReadFile(env.svc.dataPath)
+		require.NoError(t, err)
+		require.Equal(t, cfgA+"\n"+cfgB+"\n", string(b))
+
+		// A configuration which fails to be applied is reported, while the
+		// previous one keeps running.
+		srv.setResponse(&protobufs.ServerToAgent{RemoteConfig: &protobufs.AgentRemoteConfig{
+			Config: &protobufs.AgentConfigMap{ConfigMap: map[string]*protobufs.AgentConfigFile{
+				"bad.alloy": {Body: []byte("unparseable config")},
+			}},
+			ConfigHash: []byte("bad"),
+		}})
+		require.EventuallyWithT(t, func(c *assert.CollectT) {
+			state := srv.agentState()
+			assert.Equal(c, "bad", string(state.GetRemoteConfigStatus().GetLastRemoteConfigHash()))
+			assert.Equal(c, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED, state.GetRemoteConfigStatus().GetStatus())
+			assert.NotEmpty(c, state.GetRemoteConfigStatus().GetErrorMessage())
+			assert.False(c, state.GetHealth().GetHealthy())
+			assert.Contains(c, state.GetEffectiveConfig().GetConfigMap().GetConfigMap(), "a.alloy")
+		}, 5*time.Second, 10*time.Millisecond)
+
+		b, err = os.ReadFile(env.svc.dataPath)
+		require.NoError(t, err)
+		require.Equal(t, cfgA+"\n"+cfgB+"\n", string(b))
+	})
+}
+
+func TestOnDiskCache(t *testing.T) {
+	cacheContents := `loki.process "default" { forward_to = [] }`
+
+	srv := newFakeServer(t)
+	srv.setStatus(http.StatusInternalServerError)
+
+	env := newTestEnvironment(t)
+	require.NoError(t, env.ApplyConfig(fmt.Sprintf(`url = %q`, srv.URL)))
+	require.Error(t, env.svc.Ready())
+	require.NoError(t, os.WriteFile(env.svc.dataPath, []byte(cacheContents), 0644))
+	env.Run(t)
+
+	// As the server can't be reached, verify that the service has loaded the
+	// on-disk cache contents.
+	require.Eventually(t, func() bool { return env.svc.Ready() == nil }, time.Second, 10*time.Millisecond)
+	infos := component.GetAllComponents(env.svc.ctrl.(serviceController).GetHost(), component.InfoOptions{})
+	require.Len(t, infos, 1)
+	require.Equal(t, "loki.process.default", infos[0].ID.LocalID)
+}
+
+func TestAgentDescription(t *testing.T) {
+	transports(t, func(t *testing.T, serverURL func(*fakeServer) string) {
+		srv := newFakeServer(t)
+
+		env := newTestEnvironment(t)
+		require.NoError(t, env.ApplyConfig(fmt.Sprintf(`
+			url          = %q
+			instance_uid = %q
+			attributes   = {"deployment.environment" = "prod"}
+		`, serverURL(srv), testInstanceUID)))
+		rs := env.Run(t)
+
+		require.EventuallyWithT(t, func(c *assert.CollectT) {
+			state := srv.agentState()
+			assert.Equal(c, testInstanceUID, uuid.UUID(state.GetInstanceUid()).String())
+			assert.Equal(c, uint64(capabilities), state.GetCapabilities())
+			assert.Contains(c, attributes(state.GetAgentDescription().GetIdentifyingAttributes()), "service.instance.id="+testInstanceUID)
+			assert.Contains(c, attributes(state.GetAgentDescription().GetIdentifyingAttributes()), "service.version="+build.Version)
+			assert.Contains(c, attributes(state.GetAgentDescription().GetNonIdentifyingAttributes()), "deployment.environment=prod")
+			assert.Equal(c, protobufs.RemoteConfigStatuses_RemoteConfigStatuses_UNSET, state.GetRemoteConfigStatus().GetStatus())
+			assert.Equal(c, protobufs.PackageStatusEnum_PackageStatusEnum_Installed, state.GetPackageStatuses().GetPackages()[packageName].GetStatus())
+			assert.True(c, state.GetHealth().GetHealthy())
+		}, 5*time.Second, 10*time.Millisecond)
+
+		// The server can assign another instance UID, and offer packages.
+		newUID := uuid.New()
+		srv.setResponse(&protobufs.ServerToAgent{
+			AgentIdentification: &protobufs.AgentIdentification{NewInstanceUid: newUID[:]},
+			PackagesAvailable: &protobufs.PackagesAvailable{
+				Packages:        map[string]*protobufs.PackageAvailable{"other": {Version: "1.0.0"}},
+				AllPackagesHash: []byte("packages"),
+			},
+		})
+		require.EventuallyWithT(t, func(c *assert.CollectT) {
+			state := srv.agentState()
+			assert.Equal(c, newUID[:], state.GetInstanceUid())
+			assert.Contains(c, attributes(state.GetAgentDescription().GetIdentifyingAttributes()), "service.instance.id="+newUID.String())
+			if assert.Contains(c, state.GetPackageStatuses().GetPackages(), "other") {
+				assert.Equal(c, protobufs.PackageStatusEnum_PackageStatusEnum_InstallFailed, state.GetPackageStatuses().GetPackages()["other"].GetStatus())
+				assert.Equal(c, errPackagesUnsupported.Error(), state.GetPackageStatuses().GetPackages()["other"].GetErrorMessage())
+			}
+			assert.Equal(c, "packages", string(state.GetPackageStatuses().GetServerProvidedAllPackagesHash()))
+		}, 5*time.Second, 10*time.Millisecond)
+
+		// The connections to the server are closed when Alloy shuts down.
+		rs.cancel()
+		rs.wg.Wait()
+		require.Eventually(t, func() bool { return srv.openConnections() == 0 }, time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestAuthentication(t *testing.T) {
+	transports(t, func(t *testing.T, serverURL func(*fakeServer) string) {
+		srv := newFakeServer(t)
+
+		env := newTestEnvironment(t)
+		require.NoError(t, env.ApplyConfig(fmt.Sprintf(`
+			url          = %q
+			bearer_token = "token"
+			http_headers = {"X-Scope-OrgID" = ["tenant"]}
+		`, serverURL(srv))))
+		env.Run(t)
+
+		require.EventuallyWithT(t, func(c *assert.CollectT) {
+			header := srv.lastHeader()
+			assert.Equal(c, "Bearer token", header.Get("Authorization"))
+			assert.Equal(c, "tenant", header.Get("X-Scope-OrgID"))
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+}
+
+func TestConnectionSettings(t *testing.T) {
+	transports(t, func(t *testing.T, serverURL func(*fakeServer) string) {
+		for _, accept := range []bool{true, false} {
+			t.Run(fmt.Sprintf("accept=%t", accept), func(t *testing.T) {
+				newSrv := newFakeServer(t)
+				srv := newFakeServer(t)
+				srv.setResponse(&protobufs.ServerToAgent{ConnectionSettings: &protobufs.ConnectionSettingsOffers{
+					Hash: []byte("settings"),
+					Opamp: &protobufs.OpAMPConnectionSettings{
+						DestinationEndpoint: serverURL(newSrv),
+						Headers: &protobufs.Headers{Headers: []*protobufs.Header{
+							{Key: "Authorization", Value: "Bearer token"},
+						}},
+						HeartbeatIntervalSeconds: 60,
+					},
+				}})
+
+				env := newTestEnvironment(t)
+				require.NoError(t, env.ApplyConfig(fmt.Sprintf(`
+					url                        = %q
+					accept_connection_settings = %t
+				`, serverURL(srv), accept)))
+				env.Run(t)
+
+				if !accept {
+					require.Eventually(t, func() bool { return srv.agentState().GetCapabilities() != 0 }, 5*time.Second, 10*time.Millisecond)
+					require.Zero(t, srv.agentState().GetCapabilities()&uint64(protobufs.AgentCapabilities_AgentCapabilities_AcceptsOpAMPConnectionSettings))
+					require.Never(t, func() bool { return newSrv.agentState().GetCapabilities() != 0 }, 200*time.Millisecond, 10*time.Millisecond)
+					return
+				}
+
+				require.Eventually(t, func() bool { return newSrv.agentState().GetCapabilities() != 0 }, 5*time.Second, 10*time.Millisecond)
+				require.Equal(t, "Bearer token", newSrv.lastHeader().Get("Authorization"))
+				require.Equal(t, time.Minute, env.svc.getPollFrequency())
+				require.NotZero(t, newSrv.agentState().GetCapabilities()&uint64(protobufs.AgentCapabilities_AgentCapabilities_AcceptsOpAMPConnectionSettings))
+			})
+		}
+	})
+}
+
+func TestRetryAfter(t *testing.T) {
+	srv := newFakeServer(t)
+	srv.setStatus(http.StatusServiceUnavailable)
+	srv.setResponseHeader("Retry-After", "3600")
+
+	env := newTestEnvironment(t)
+	require.NoError(t, env.ApplyConfig(fmt.Sprintf(`url = %q`, srv.URL)))
+	env.Run(t)
+
+	// No more requests are sent until the delay requested by the server has
+	// elapsed.
+	require.Eventually(t, func() bool { return srv.requests() == 1 }, time.Second, 10*time.Millisecond)
+	time.Sleep(500 * time.Millisecond)
+	require.Equal(t, 1, srv.requests())
+}
+
+func TestArgumentsValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name:   "defaults",
+			config: `url = "http://localhost:4320/v1/opamp"`,
+		},
+		{
+			name:   "websocket",
+			config: `url = "wss://localhost:4320/v1/opamp"`,
+		},
+		{
+			name:   "url scheme",
+			config: `url = "localhost:4320"`,
+			err:    `invalid url: unsupported scheme "localhost", must be one of http, https, ws, or wss`,
+		},
+		{
+			name:   "poll frequency",
+			config: `poll_frequency = "1s"`,
+			err:    `poll_frequency must be at least "10s", got "1s"`,
+		},
+		{
+			name:   "instance UID",
+			config: `instance_uid = "alloy-1"`,
+			err:    "instance_uid must be a UUID: invalid UUID length: 7",
+		},
+		{
+			name:   "reserved attribute",
+			config: `attributes = {"service.name" = "other"}`,
+			err:    `the "service.name" attribute is reserved`,
+		},
+		{
+			name: "authentication",
+			config: `
+				bearer_token = "token"
+				basic_auth {
+					username = "user"
+				}
+			`,
+			err: "at most one of basic_auth, authorization, bearer_token & bearer_token_file must be configured",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tt.config), &args)
+			if tt.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tt.err)
+			}
+		})
+	}
+}
+
+// attributes returns the string attributes of an agent description as
+// key=value pairs.
+func attributes(kvs []*protobufs.KeyValue) []string {
+	var res []string
+	for _, kv := range kvs {
+		res = append(res, kv.Key+"="+kv.GetValue().GetStringValue())
+	}
+	return res
+}
+
+// fakeServer is an OpAMP server which accepts both transports. It merges the
+// messages it receives into the state of the agent, as the agent only sends
+// the fields which changed.
+type fakeServer struct {
+	*httptest.Server
+
+	mut            sync.Mutex
+	status         int
+	responseHeader map[string]string
+	response       *protobufs.ServerToAgent
+	state          *protobufs.AgentToServer
+	requestCount   int
+	header         http.Header
+	connections    int
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	srv := &fakeServer{status: http.StatusOK, state: &protobufs.AgentToServer{}}
+	handler, connContext, err := server.New(nil).Attach(server.Settings{
+		Callbacks: servertypes.Callbacks{OnConnecting: srv.onConnecting},
+	})
+	require.NoError(t, err)
+
+	srv.Server = httptest.NewUnstartedServer(http.HandlerFunc(handler))
+	srv.Config.ConnContext = connContext
+	srv.Start()
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func (srv *fakeServer) onConnecting(r *http.Request) servertypes.ConnectionResponse {
+	srv.mut.Lock()
+	defer srv.mut.Unlock()
+	srv.requestCount++
+	srv.header = r.Header.Clone()
+	if srv.status != http.StatusOK {
+		return servertypes.ConnectionResponse{HTTPStatusCode: srv.status, HTTPResponseHeader: srv.responseHeader}
+	}
+	return servertypes.ConnectionResponse{
+		Accept: true,
+		ConnectionCallbacks: servertypes.ConnectionCallbacks{
+			OnConnected:       func(context.Context, servertypes.Connection) { srv.updateConnections(1) },
+			OnMessage:         srv.onMessage,
+			OnConnectionClose: func(servertypes.Connection) { srv.updateConnections(-1) },
+		},
+	}
+}
+
+func (srv *fakeServer) onMessage(_ context.Context, _ servertypes.Connection, msg *protobufs.AgentToServer) *protobufs.ServerToAgent {
+	srv.mut.Lock()
+	defer srv.mut.Unlock()
+
+	srv.state.InstanceUid = msg.InstanceUid
+	if msg.Capabilities != 0 {
+		srv.state.Capabilities = msg.Capabilities
+	}
+	if msg.AgentDescription != nil {
+		srv.state.AgentDescription = msg.AgentDescription
+	}
+	if msg.Health != nil {
+		srv.state.Health = msg.Health
+	}
+	if msg.EffectiveConfig != nil {
+		srv.state.EffectiveConfig = msg.EffectiveConfig
+	}
+	if msg.RemoteConfigStatus != nil {
+		srv.state.RemoteConfigStatus = msg.RemoteConfigStatus
+	}
+	if msg.PackageStatuses != nil {
+		srv.state.PackageStatuses = msg.PackageStatuses
+	}
+
+	resp := &protobufs.ServerToAgent{}
+	if srv.response != nil {
+		resp = proto.Clone(srv.response).(*protobufs.ServerToAgent)
+	}
+	resp.InstanceUid = msg.InstanceUid
+	return resp
+}
+
+func (srv *fakeServer) updateConnections(delta int) {
+	srv.mut.Lock()
+	defer srv.mut.Unlock()
+	srv.connections += delta
+}
+
+func (srv *fakeServer) setResponse(resp *protobufs.ServerToAgent) {
+	srv.mut.Lock()
+	defer srv.mut.Unlock()
+	srv.response = resp
+}
+
+func (srv *fakeServer) setStatus(status int) {
+	srv.mut.Lock()
+	defer srv.mut.Unlock()
+	srv.status = status
+}
+
+func (srv *fakeServer) setResponseHeader(key, value string) {
+	srv.mut.Lock()
+	defer srv.mut.Unlock()
+	if srv.responseHeader == nil {
+		srv.responseHeader = make(map[string]string)
+	}
+	srv.responseHeader[key] = value
+}
+
+func (srv *fakeServer) requests() int {
+	srv.mut.Lock()
+	defer srv.mut.Unlock()
+	return srv.requestCount
+}
+
+func (srv *fakeServer) openConnections() int {
+	srv.mut.Lock()
+	defer srv.mut.Unlock()
+	return srv.connections
+}
+
+func (srv *fakeServer) agentState() *protobufs.AgentToServer {
+	srv.mut.Lock()
+	defer srv.mut.Unlock()
+	return proto.Clone(srv.state).(*protobufs.AgentToServer)
+}
+
+func (srv *fakeServer) lastHeader() http.Header {
+	srv.mut.Lock()
+	defer srv.mut.Unlock()
+	return srv.header
+}
+
+type testEnvironment struct {
+	t   *testing.T
+	svc *Service
+}
+
+func newTestEnvironment(t *testing.T) *testEnvironment {
+	svc, err := New(Options{
+		Logger:      util.TestLogger(t),
+		StoragePath: t.TempDir(),
+		Metrics:     prometheus.NewRegistry(),
+	})
+	require.NoError(t, err)
+
+	return &testEnvironment{
+		t:   t,
+		svc: svc,
+	}
+}
+
+func (env *testEnvironment) ApplyConfig(config string) error {
+	var args Arguments
+	if err := syntax.Unmarshal([]byte(config), &args); err != nil {
+		return err
+	}
+	// The lower limit of the poll_frequency argument would slow our tests
+	// considerably; let's artificially lower it after the initial validation
+	// has taken place.
+	args.PollFrequency /= 100
+	return env.svc.Update(args)
+}
+
+type runningService struct {
+	cancel context.CancelFunc
+	wg     *sync.WaitGroup
+}
+
+// Run runs the service until the end of the test.
+func (env *testEnvironment) Run(t *testing.T) runningService {
+	ctx, cancel := context.WithCancel(t.Context())
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		assert.NoError(t, env.svc.Run(ctx, fakeHost{}))
+	}()
+	t.Cleanup(func() {
+		cancel()
+		wg.Wait()
+	})
+	return runningService{cancel: cancel, wg: wg}
+}
+
+type fakeHost struct{}
+
+var _ service.Host = (fakeHost{})
+
+func (fakeHost) GetComponent(id component.ID, opts component.InfoOptions) (*component.Info, error) {
+	return nil, fmt.Errorf("no such component %s", id)
+}
+
+func (fakeHost) ListComponents(moduleID string, opts component.InfoOptions) ([]*component.Info, error) {
+	if moduleID == "" {
+		return nil, nil
+	}
+	return nil, fmt.Errorf("no such module %q", moduleID)
+}
+
+func (fakeHost) GetServiceConsumers(_ string) []service.Consumer { return nil }
+func (fakeHost) GetService(_ string) (service.Service, bool)     { return nil, false }
+
+func (f fakeHost) NewController(id string) service.Controller {
+	logger, _ := logging.New(io.Discard, logging.DefaultOptions)
+	ctrl := alloy_runtime.New(alloy_runtime.Options{
+		ControllerID:    ServiceName,
+		Logger:          logger,
+		Tracer:          nil,
+		DataPath:        "",
+		MinStability:    featuregate.StabilityGenerallyAvailable,
+		Reg:             prometheus.NewRegistry(),
+		OnExportsChange: func(map[string]interface{}) {},
+		Services:        []service.Service{livedebugging.New()},
+	})
+
+	return serviceController{ctrl}
+}
+
+type serviceController struct {
+	f *alloy_runtime.Runtime
+}
+
+func (sc serviceController) Run(ctx context.Context) { sc.f.Run(ctx) }
+func (sc serviceController) LoadSource(b []byte, args map[string]any, configPath string) (*ast.File, error) {
+	source, err := alloy_runtime.ParseSource("", b)
+	if err != nil {
+		return nil, err
+	}
+	return source.SourceFiles()[""], sc.f.LoadSource(source, args, configPath)
+}
+func (sc serviceController) Ready() bool           { return sc.f.Ready() }
+func (sc serviceController) GetHost() service.Host { return sc.f }
@@ -0,0 +1,242 @@
+package opamp
+
+import (
+	"context"
+	"errors"
+	"io"
+	"maps"
+	"os"
+	"runtime"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/grafana/alloy/internal/build"
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/service"
+	"github.com/open-telemetry/opamp-go/client/types"
+	"github.com/open-telemetry/opamp-go/protobufs"
+	"google.golang.org/protobuf/proto"
+)
+
+// capabilities are the capabilities reported to the server. Accepting
+// connection settings is only reported when it's enabled.
+const capabilities = protobufs.AgentCapabilities_AgentCapabilities_ReportsStatus |
+	protobufs.AgentCapabilities_AgentCapabilities_AcceptsRemoteConfig |
+	protobufs.AgentCapabilities_AgentCapabilities_ReportsEffectiveConfig |
+	protobufs.AgentCapabilities_AgentCapabilities_AcceptsPackages |
+	protobufs.AgentCapabilities_AgentCapabilities_ReportsPackageStatuses |
+	protobufs.AgentCapabilities_AgentCapabilities_ReportsHealth |
+	protobufs.AgentCapabilities_AgentCapabilities_ReportsRemoteConfig |
+	protobufs.AgentCapabilities_AgentCapabilities_ReportsHeartbeat
+
+// packageName is the name of the package Alloy reports itself as.
+const packageName = "alloy"
+
+var errPackagesUnsupported = errors.New("installing packages isn't supported")
+
+func capabilitiesFor(args Arguments) protobufs.AgentCapabilities {
+	if args.AcceptConnectionSettings {
+		return capabilities | protobufs.AgentCapabilities_AgentCapabilities_AcceptsOpAMPConnectionSettings
+	}
+	return capabilities
+}
+
+// remoteConfigStatus returns the status of the remote configuration. It must
+// be called with mut held.
+func (s *Service) remoteConfigStatus() *protobufs.RemoteConfigStatus {
+	status := &protobufs.RemoteConfigStatus{
+		LastRemoteConfigHash: s.remoteConfigHash,
+		Status:               protobufs.RemoteConfigStatuses_RemoteConfigStatuses_UNSET,
+	}
+	switch {
+	case s.lastApplyErr != nil:
+		status.Status = protobufs.RemoteConfigStatuses_RemoteConfigStatuses_FAILED
+		status.ErrorMessage = s.lastApplyErr.Error()
+	case s.effectiveConfig != nil:
+		status.Status = protobufs.RemoteConfigStatuses_RemoteConfigStatuses_APPLIED
+	}
+	return status
+}
+
+func agentDescriptionFor(args Arguments, instanceUID uuid.UUID) *protobufs.AgentDescription {
+	desc := &protobufs.AgentDescription{
+		IdentifyingAttributes: []*protobufs.KeyValue{
+			keyValue("service.name", "alloy"),
+			keyValue("service.version", build.Version),
+			keyValue("service.instance.id", instanceUID.String()),
+		},
+	}
+
+	attrs := map[string]string{"os.type": runtime.GOOS}
+	if hostname, err := os.Hostname(); err == nil {
+		attrs["host.name"] = hostname
+	}
+	maps.Copy(attrs, args.Attributes)
+	for _, k := range slices.Sorted(maps.Keys(attrs)) {
+		desc.NonIdentifyingAttributes = append(desc.NonIdentifyingAttributes, keyValue(k, attrs[k]))
+	}
+	return desc
+}
+
+func keyValue(key, value string) *protobufs.KeyValue {
+	return &protobufs.KeyValue{
+		Key:   key,
+		Value: &protobufs.AnyValue{Value: &protobufs.AnyValue_StringValue{StringValue: value}},
+	}
+}
+
+// currentHealth returns the health of Alloy, with the health of each
+// component defined by the remote configuration, including the ones running
+// in modules. Alloy is unhealthy when the last configuration failed to be
+// applied, or when a component is unhealthy or has exited.
+func (s *Service) currentHealth() *protobufs.ComponentHealth {
+	s.mut.RLock()
+	ctrl := s.ctrl
+	startTime := s.startTime
+	applyErr := s.lastApplyErr
+	s.mut.RUnlock()
+
+	res := &protobufs.ComponentHealth{
+		Healthy:            true,
+		StartTimeUnixNano:  uint64(startTime.UnixNano()),
+		Status:             "running",
+		StatusTimeUnixNano: uint64(time.Now().UnixNano()),
+		ComponentHealthMap: make(map[string]*protobufs.ComponentHealth),
+	}
+	if applyErr != nil {
+		res.Healthy = false
+		res.LastError = applyErr.Error()
+	}
+
+	// The host is only exposed by the controller of the runtime.
+	hc, ok := ctrl.(interface{ GetHost() service.Host })
+	if !ok {
+		return res
+	}
+
+	for _, info := range component.GetAllComponents(hc.GetHost(), component.InfoOptions{GetHealth: true}) {
+		h := &protobufs.ComponentHealth{
+			Status:    info.Health.Health.String(),
+			LastError: info.Health.Message,
+		}
+		switch info.Health.Health {
+		case component.HealthTypeHealthy, component.HealthTypeUnknown:
+			h.Healthy = true
+		default:
+			res.Healthy = false
+		}
+		if !info.Health.UpdateTime.IsZero() {
+			h.StatusTimeUnixNano = uint64(info.Health.UpdateTime.UnixNano())
+		}
+		res.ComponentHealthMap[info.ID.String()] = h
+	}
+	return res
+}
+
+// healthEqual returns whether two health reports are the same, regardless of
+// the time at which the health of Alloy was computed.
+func healthEqual(a, b *protobufs.ComponentHealth) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	b = proto.Clone(b).(*protobufs.ComponentHealth)
+	b.StatusTimeUnixNano = a.StatusTimeUnixNano
+	return proto.Equal(a, b)
+}
+
+// packageStatusesFor reports Alloy itself as an installed package, and the
+// packages offered by the server which aren't the running version of Alloy
+// as failed, since Alloy can't install packages.
+func packageStatusesFor(available *protobufs.PackagesAvailable) *protobufs.PackageStatuses {
+	res := &protobufs.PackageStatuses{
+		Packages: map[string]*protobufs.PackageStatus{
+			packageName: {
+				Name:            packageName,
+				AgentHasVersion: build.Version,
+				Status:          protobufs.PackageStatusEnum_PackageStatusEnum_Installed,
+			},
+		},
+		// The client requires the hash to report the statuses.
+		ServerProvidedAllPackagesHash: []byte{},
+	}
+	if available == nil {
+		return res
+	}
+
+	if available.AllPackagesHash != nil {
+		res.ServerProvidedAllPackagesHash = available.AllPackagesHash
+	}
+	for name, p := range available.Packages {
+		st := &protobufs.PackageStatus{
+			Name:                 name,
+			ServerOfferedVersion: p.Version,
+			ServerOfferedHash:    p.Hash,
+			Status:               protobufs.PackageStatusEnum_PackageStatusEnum_InstallFailed,
+			ErrorMessage:         errPackagesUnsupported.Error(),
+		}
+		if name == packageName && p.Version == build.Version {
+			st.AgentHasVersion = build.Version
+			st.Status = protobufs.PackageStatusEnum_PackageStatusEnum_Installed
+			st.ErrorMessage = ""
+		}
+		res.Packages[name] = st
+	}
+	return res
+}
+
+// packagesStateProvider lets the client report the package statuses. The
+// packages offered by the server are never synced, so it only provides the
+// statuses reported when the client starts.
+type packagesStateProvider struct {
+	statuses *protobufs.PackageStatuses
+}
+
+var _ types.PackagesStateProvider = packagesStateProvider{}
+
+func (p packagesStateProvider) AllPackagesHash() ([]byte, error) {
+	return p.statuses.GetServerProvidedAllPackagesHash(), nil
+}
+
+func (p packagesStateProvider) SetAllPackagesHash([]byte) error {
+	return errPackagesUnsupported
+}
+
+func (p packagesStateProvider) Packages() ([]string, error) {
+	return []string{packageName}, nil
+}
+
+func (p packagesStateProvider) PackageState(name string) (types.PackageState, error) {
+	if name != packageName {
+		return types.PackageState{}, nil
+	}
+	return types.PackageState{Exists: true, Version: build.Version}, nil
+}
+
+func (p packagesStateProvider) SetPackageState(string, types.PackageState) error {
+	return errPackagesUnsupported
+}
+
+func (p packagesStateProvider) CreatePackage(string, protobufs.PackageType) error {
+	return errPackagesUnsupported
+}
+
+func (p packagesStateProvider) FileContentHash(string) ([]byte, error) {
+	return nil, nil
+}
+
+func (p packagesStateProvider) UpdateContent(context.Context, string, io.Reader, []byte, []byte) error {
+	return errPackagesUnsupported
+}
+
+func (p packagesStateProvider) DeletePackage(string) error {
+	return errPackagesUnsupported
+}
+
+func (p packagesStateProvider) LastReportedStatuses() (*protobufs.PackageStatuses, error) {
+	return p.statuses, nil
+}
+
+func (p packagesStateProvider) SetLastReportedStatuses(*protobufs.PackageStatuses) error {
+	return nil
+}
@@ -0,0 +1,91 @@
+package remotecfg
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/grafana/alloy/syntax/alloytypes"
+)
+
+// CacheEncryptionArguments configures the encryption of the on-disk cache of
+// the remote configuration.
+type CacheEncryptionArguments struct {
+	Key     alloytypes.Secret `alloy:"key,attr,optional"`
+	KeyFile string            `alloy:"key_file,attr,optional"`
+}
+
+// Validate implements syntax.Validator.
+func (a *CacheEncryptionArguments) Validate() error {
+	switch {
+	case a.Key == "" && a.KeyFile == "":
+		return errors.New("one of key or key_file must be set in the cache_encryption block")
+	case a.Key != "" && a.KeyFile != "":
+		return errors.New("at most one of key and key_file can be set in the cache_encryption block")
+	}
+	return nil
+}
+
+// loadKey returns the AES-256 key derived from the configured secret.
+func (a *CacheEncryptionArguments) loadKey() ([]byte, error) {
+	secret := string(a.Key)
+	if a.KeyFile != "" {
+		b, err := os.ReadFile(a.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read cache encryption key file: %w", err)
+		}
+		secret = strings.TrimSpace(string(b))
+	}
+	if secret == "" {
+		return nil, errors.New("the cache encryption key is empty")
+	}
+
+	key := sha256.Sum256([]byte(secret))
+	return key[:], nil
+}
+
+// encryptCache seals b with AES-GCM. The random nonce is prepended to the
+// returned ciphertext.
+func encryptCache(key, b []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, b, nil), nil
+}
+
+// decryptCache opens a ciphertext which was sealed by encryptCache.
+func decryptCache(key, b []byte) ([]byte, error) {
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(b) < gcm.NonceSize() {
+		return nil, errors.New("encrypted cache is too short")
+	}
+	nonce, ciphertext := b[:gcm.NonceSize()], b[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt cache: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -2,6 +2,7 @@ package remotecfg
 
 import (
 	"context"
+	"crypto"
 	"errors"
 	"fmt"
 	"hash/fnv"
@@ -67,6 +68,8 @@ type Service struct {
 	updateTickerChan     chan struct{}
 	pollFrequency        time.Duration
 	dataPath             string
+	cacheKey             []byte           // Key to encrypt the on-disk cache with, if any.
+	signatureKey         crypto.PublicKey // Key to verify the configuration signature with, if any.
 	lastLoadedConfigHash string
 	systemAttrs          map[string]string
 	attrs                map[string]string
@@ -76,6 +79,11 @@ type Service struct {
 	// the configuration has changed since the last fetch
 	remoteHash string
 
+	// These hold the result of applying the last configuration, which is
+	// reported back to the API.
+	appliedConfigHash string
+	lastApplyErr      error
+
 	// This is the AST file parsed from the configuration. This is used
 	// for the support bundle
 	astFile *ast.File
@@ -89,6 +97,7 @@ type metrics struct {
 	lastFetchSuccessTime prometheus.Gauge
 	totalAttempts        prometheus.Counter
 	getConfigTime        prometheus.Histogram
+	signatureFailures    prometheus.Counter
 }
 
 // ServiceName defines the name used for the remotecfg service.
@@ -114,6 +123,9 @@ type Arguments struct {
 	Attributes       map[string]string        `alloy:"attributes,attr,optional"`
 	PollFrequency    time.Duration            `alloy:"poll_frequency,attr,optional"`
 	HTTPClientConfig *config.HTTPClientConfig `alloy:",squash"`
+
+	CacheEncryption       *CacheEncryptionArguments       `alloy:"cache_encryption,block,optional"`
+	SignatureVerification *SignatureVerificationArguments `alloy:"signature_verification,block,optional"`
 }
 
 // GetDefaultArguments populates the default values for the Arguments struct.
@@ -241,6 +253,12 @@ func (s *Service) registerMetrics() {
 				Help: "Duration of remote configuration requests.",
 			},
 		),
+		signatureFailures: prom.NewCounter(
+			prometheus.CounterOpts{
+				Name: "remotecfg_signature_verification_failures_total",
+				Help: "Remote configurations rejected because their signature is missing or invalid",
+			},
+		),
 	}
 	s.metrics = mets
 }
@@ -335,7 +353,26 @@ func (s *Service) Update(newConfig any) error {
 		s.mut.Unlock()
 		return err
 	}
+
+	var cacheKey []byte
+	if newArgs.CacheEncryption != nil {
+		cacheKey, err = newArgs.CacheEncryption.loadKey()
+		if err != nil {
+			s.mut.Unlock()
+			return err
+		}
+	}
+	var signatureKey crypto.PublicKey
+	if newArgs.SignatureVerification != nil {
+		signatureKey, err = newArgs.SignatureVerification.loadKey()
+		if err != nil {
+			s.mut.Unlock()
+			return err
+		}
+	}
 	s.dataPath = filepath.Join(s.opts.StoragePath, ServiceName, hash)
+	s.cacheKey = cacheKey
+	s.signatureKey = signatureKey
 
 	s.setPollFrequency(newArgs.PollFrequency)
 	// Update the HTTP client last since it might fail.
@@ -380,6 +417,19 @@ func (s *Service) GetCachedAstFile() *ast.File {
 	return s.astFile
 }
 
+// Ready returns an error until a configuration has been successfully loaded,
+// either from the API or from the on-disk cache. The service is always ready
+// when it isn't configured.
+func (s *Service) Ready() error {
+	if !s.isEnabled() {
+		return nil
+	}
+	if s.GetCachedAstFile() == nil {
+		return errors.New("no remote configuration has been loaded yet")
+	}
+	return nil
+}
+
 // fetch attempts to read configuration from the API and the local cache
 // and then parse/load their contents in order of preference.
 func (s *Service) fetch() {
@@ -411,7 +461,7 @@ func (s *Service) fetchRemote() error {
 
 	level.Debug(s.opts.Logger).Log("msg", "fetching remote configuration")
 
-	b, err := s.getAPIConfig()
+	b, signature, err := s.getAPIConfig()
 	s.metrics.totalAttempts.Add(1)
 
 	if err == nil {
@@ -444,7 +494,7 @@ func (s *Service) fetchRemote() error {
 	}
 
 	// If successful, flush to disk and keep a copy.
-	s.setCachedConfig(b)
+	s.setCachedConfig(b, signature)
 	return nil
 }
 
@@ -455,13 +505,38 @@ func (s *Service) fetchLocal() {
 		return
 	}
 
+	// The cache is verified like a configuration fetched from the API, since
+	// it can be modified on disk.
+	s.mut.RLock()
+	signatureKey := s.signatureKey
+	s.mut.RUnlock()
+	if signatureKey != nil && len(b) > 0 {
+		signature, err := s.getCachedSignature()
+		if err == nil {
+			err = verifySignature(signatureKey, b, signature)
+		}
+		if err != nil {
+			s.metrics.signatureFailures.Inc()
+			level.Error(s.opts.Logger).Log("msg", "refusing to load the cached configuration", "err", err)
+			return
+		}
+	}
+
 	err = s.parseAndLoad(b)
 	if err != nil {
 		level.Error(s.opts.Logger).Log("msg", "failed to load from cache", "err", err)
 	}
 }
 
-func (s *Service) getAPIConfig() ([]byte, error) {
+// getAPIConfig returns the configuration returned by the API and its
+// signature, if any.
+func (s *Service) getAPIConfig() ([]byte, string, error) {
+	// Report the status of the collector alongside each request.
+	status, err := encodeStatus(s.Status())
+	if err != nil {
+		return nil, "", err
+	}
+
 	s.mut.RLock()
 	req := connect.NewRequest(&collectorv1.GetConfigRequest{
 		Id:              s.args.ID,
@@ -469,39 +544,94 @@ func (s *Service) getAPIConfig() ([]byte, error) {
 		Hash:            s.remoteHash,
 	})
 	client := s.asClient
+	signatureKey := s.signatureKey
 	s.mut.RUnlock()
+	req.Header().Set(StatusHeader, status)
 
 	start := time.Now()
 	gcr, err := client.GetConfig(context.Background(), req)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	s.metrics.getConfigTime.Observe(time.Since(start).Seconds())
 	if gcr.Msg.NotModified {
-		return nil, errNotModified
+		return nil, "", errNotModified
+	}
+	content := []byte(gcr.Msg.GetContent())
+	signature := gcr.Header().Get(SignatureHeader)
+	// Reject the configuration before it's parsed. Its hash is recorded like
+	// the one of a configuration which fails to load, but the remote hash isn't
+	// kept so that it's fetched and verified again on the next poll.
+	if signatureKey != nil {
+		if err := verifySignature(signatureKey, content, signature); err != nil {
+			s.metrics.signatureFailures.Inc()
+			hash := getHash(content)
+			s.setLastLoadedCfgHash(hash)
+			s.setApplyResult(hash, err)
+			return nil, "", err
+		}
 	}
 	if gcr.Msg.Hash != "" {
 		s.mut.Lock()
 		s.remoteHash = gcr.Msg.Hash
 		s.mut.Unlock()
 	}
-	return []byte(gcr.Msg.GetContent()), nil
+	return content, signature, nil
 }
 
 func (s *Service) getCachedConfig() ([]byte, error) {
 	s.mut.RLock()
 	p := s.dataPath
+	key := s.cacheKey
 	s.mut.RUnlock()
 
-	return os.ReadFile(p)
+	b, err := os.ReadFile(p)
+	if err != nil || key == nil {
+		return b, err
+	}
+	return decryptCache(key, b)
 }
 
-func (s *Service) setCachedConfig(b []byte) {
+// getCachedSignature returns the signature of the cached configuration.
+func (s *Service) getCachedSignature() (string, error) {
 	s.mut.RLock()
 	p := s.dataPath
 	s.mut.RUnlock()
 
-	err := os.WriteFile(p, b, 0750)
+	b, err := os.ReadFile(p + signatureFileSuffix)
+	if errors.Is(err, os.ErrNotExist) {
+		// verifySignature reports the missing signature.
+		return "", nil
+	}
+	return string(b), err
+}
+
+func (s *Service) setCachedConfig(b []byte, signature string) {
+	s.mut.RLock()
+	p := s.dataPath
+	key := s.cacheKey
+	s.mut.RUnlock()
+
+	var err error
+	if signature != "" {
+		err = os.WriteFile(p+signatureFileSuffix, []byte(signature), 0640)
+	} else if err = os.Remove(p + signatureFileSuffix); errors.Is(err, os.ErrNotExist) {
+		err = nil
+	}
+	if err != nil {
+		level.Error(s.opts.Logger).Log("msg", "failed to flush the remote configuration signature to the on-disk cache", "err", err)
+		return
+	}
+
+	if key != nil {
+		b, err = encryptCache(key, b)
+		if err != nil {
+			level.Error(s.opts.Logger).Log("msg", "failed to encrypt remote configuration contents for the on-disk cache", "err", err)
+			return
+		}
+	}
+
+	err = os.WriteFile(p, b, 0750)
 	if err != nil {
 		level.Error(s.opts.Logger).Log("msg", "failed to flush remote configuration contents the on-disk cache", "err", err)
 	}
@@ -515,8 +645,10 @@ func (s *Service) parseAndLoad(b []byte) error {
 	if len(b) == 0 {
 		return nil
 	}
-	s.setLastLoadedCfgHash(getHash(b))
+	hash := getHash(b)
+	s.setLastLoadedCfgHash(hash)
 	file, err := ctrl.LoadSource(b, nil, s.opts.ConfigPath)
+	s.setApplyResult(hash, err)
 	if err != nil {
 		return err
 	}
@@ -525,6 +657,15 @@ func (s *Service) parseAndLoad(b []byte) error {
 	return nil
 }
 
+func (s *Service) setApplyResult(hash string, err error) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.lastApplyErr = err
+	if err == nil {
+		s.appliedConfigHash = hash
+	}
+}
+
 func (s *Service) getLastLoadedCfgHash() string {
 	s.mut.RLock()
 	defer s.mut.RUnlock()
@@ -2,9 +2,19 @@ package remotecfg
 
 import (
 	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"sync"
 	"testing"
 	"time"
@@ -25,6 +35,7 @@ import (
 	"github.com/grafana/alloy/syntax"
 	"github.com/grafana/alloy/syntax/ast"
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -74,6 +85,105 @@ func TestOnDiskCache(t *testing.T) {
 	wg.Wait()
 }
 
+func TestOnDiskCacheEncryption(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+
+	client := &collectorClient{}
+
+	var registerCalled atomic.Bool
+	client.registerCollectorFunc = buildRegisterCollectorFunc(&registerCalled)
+	url := "https://example.com/"
+
+	// The contents of the on-disk cache.
+	cacheContents := `loki.process "default" { forward_to = [] }`
+
+	keyFile := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(keyFile, []byte("my-secret-key\n"), 0600))
+
+	// Create a new service.
+	env := newTestEnvironment(t, client)
+	require.NoError(t, env.ApplyConfig(fmt.Sprintf(`
+		url = "%s"
+		cache_encryption {
+			key_file = "%s"
+		}
+	`, url, keyFile)))
+
+	// Mock client to return an unparseable response.
+	client.getConfigFunc = buildGetConfigHandler("unparseable config", "", false)
+
+	// Write the encrypted cache contents, and verify that they can't be read
+	// from the disk in plaintext.
+	env.svc.setCachedConfig([]byte(cacheContents), "")
+	b, err := os.ReadFile(env.svc.dataPath)
+	require.NoError(t, err)
+	require.NotContains(t, string(b), "loki.process")
+
+	// Run the service.
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		require.NoError(t, env.Run(ctx))
+	}()
+
+	// As the API response was unparseable, verify that the service has loaded
+	// the decrypted on-disk cache contents.
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		assert.Equal(c, getHash([]byte(cacheContents)), env.svc.getLastLoadedCfgHash())
+		assert.NotNil(c, env.svc.GetCachedAstFile())
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	wg.Wait()
+
+	// The cache can't be decrypted with another key.
+	env.svc.cacheKey, err = (&CacheEncryptionArguments{Key: "another-key"}).loadKey()
+	require.NoError(t, err)
+	_, err = env.svc.getCachedConfig()
+	require.ErrorContains(t, err, "failed to decrypt cache")
+}
+
+func TestCacheEncryptionValidate(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		config string
+		err    string
+	}{
+		{
+			name:   "key",
+			config: `cache_encryption { key = "secret" }`,
+		},
+		{
+			name:   "key_file",
+			config: `cache_encryption { key_file = "/etc/alloy/key" }`,
+		},
+		{
+			name:   "missing key",
+			config: `cache_encryption { }`,
+			err:    "one of key or key_file must be set in the cache_encryption block",
+		},
+		{
+			name: "both keys",
+			config: `cache_encryption {
+				key      = "secret"
+				key_file = "/etc/alloy/key"
+			}`,
+			err: "at most one of key and key_file can be set in the cache_encryption block",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var args Arguments
+			err := syntax.Unmarshal([]byte(tc.config), &args)
+			if tc.err == "" {
+				require.NoError(t, err)
+			} else {
+				require.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+}
+
 func TestGoodBadGood(t *testing.T) {
 	ctx, cancel := context.WithCancel(t.Context())
 	url := "https://example.com/"
@@ -167,6 +277,9 @@ func TestAPIResponse(t *testing.T) {
 		poll_frequency = "10s"
 	`, url)))
 
+	// The service isn't ready until a configuration has been loaded.
+	require.ErrorContains(t, env.svc.Ready(), "no remote configuration has been loaded yet")
+
 	// Run the service.
 	wg := sync.WaitGroup{}
 	wg.Add(1)
@@ -182,6 +295,7 @@ func TestAPIResponse(t *testing.T) {
 	require.EventuallyWithT(t, func(c *assert.CollectT) {
 		assert.Equal(c, getHash([]byte(cfg1)), env.svc.getLastLoadedCfgHash())
 	}, time.Second, 10*time.Millisecond)
+	require.NoError(t, env.svc.Ready())
 
 	// Update the response returned by the API.
 	client.mut.Lock()
@@ -197,6 +311,192 @@ func TestAPIResponse(t *testing.T) {
 	wg.Wait()
 }
 
+func TestSignatureVerification(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	url := "https://example.com/"
+	cfg1 := `loki.process "default" { forward_to = [] }`
+	cfg2 := `loki.process "tampered" { forward_to = [] }`
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "cosign.pub")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+
+	buildSignedGetConfigHandler := func(in string, signature []byte) func(context.Context, *connect.Request[collectorv1.GetConfigRequest]) (*connect.Response[collectorv1.GetConfigResponse], error) {
+		return func(context.Context, *connect.Request[collectorv1.GetConfigRequest]) (*connect.Response[collectorv1.GetConfigResponse], error) {
+			rsp := connect.NewResponse(&collectorv1.GetConfigResponse{Content: in})
+			if signature != nil {
+				rsp.Header().Set(SignatureHeader, base64.StdEncoding.EncodeToString(signature))
+			}
+			return rsp, nil
+		}
+	}
+
+	client := &collectorClient{}
+
+	// Mock client to return a correctly signed response.
+	var registerCalled atomic.Bool
+	client.mut.Lock()
+	client.getConfigFunc = buildSignedGetConfigHandler(cfg1, ed25519.Sign(priv, []byte(cfg1)))
+	client.registerCollectorFunc = buildRegisterCollectorFunc(&registerCalled)
+	client.mut.Unlock()
+
+	// Create a new service.
+	env := newTestEnvironment(t, client)
+	require.NoError(t, env.ApplyConfig(fmt.Sprintf(`
+		url            = "%s"
+		poll_frequency = "10s"
+		signature_verification {
+			public_key_file = "%s"
+		}
+	`, url, keyFile)))
+
+	// Run the service.
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		require.NoError(t, env.Run(ctx))
+	}()
+
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		assert.Equal(c, getHash([]byte(cfg1)), env.svc.getLastLoadedCfgHash())
+		assert.Equal(c, ApplyStateApplied, env.svc.Status().ApplyState)
+	}, time.Second, 10*time.Millisecond)
+
+	for _, signature := range [][]byte{
+		ed25519.Sign(priv, []byte(cfg1)), // Tampered configuration.
+		nil,                              // Unsigned configuration.
+	} {
+		client.mut.Lock()
+		client.getConfigFunc = buildSignedGetConfigHandler(cfg2, signature)
+		client.mut.Unlock()
+
+		// Verify that the configuration is rejected, and that the last valid
+		// configuration is still running.
+		require.EventuallyWithT(t, func(c *assert.CollectT) {
+			status := env.svc.Status()
+			assert.Equal(c, ApplyStateFailed, status.ApplyState)
+			assert.Contains(c, status.ApplyError, "invalid configuration signature")
+			assert.Equal(c, getHash([]byte(cfg1)), status.EffectiveConfigHash)
+		}, time.Second, 10*time.Millisecond)
+		require.Equal(t, getHash([]byte(cfg2)), env.svc.getLastLoadedCfgHash())
+
+		// Accept the valid configuration again before the next case.
+		client.mut.Lock()
+		client.getConfigFunc = buildSignedGetConfigHandler(cfg1, ed25519.Sign(priv, []byte(cfg1)))
+		client.mut.Unlock()
+		require.EventuallyWithT(t, func(c *assert.CollectT) {
+			assert.Equal(c, ApplyStateApplied, env.svc.Status().ApplyState)
+		}, time.Second, 10*time.Millisecond)
+	}
+	require.GreaterOrEqual(t, testutil.ToFloat64(env.svc.metrics.signatureFailures), 2.0)
+
+	cancel()
+	wg.Wait()
+}
+
+func TestSignatureVerificationCache(t *testing.T) {
+	cfg := `loki.process "default" { forward_to = [] }`
+
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	require.NoError(t, err)
+	keyFile := filepath.Join(t.TempDir(), "cosign.pub")
+	require.NoError(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0600))
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, []byte(cfg)))
+
+	tests := []struct {
+		name      string
+		content   string
+		signature string
+		loaded    bool
+	}{
+		{name: "signed", content: cfg, signature: signature, loaded: true},
+		{name: "tampered", content: `loki.process "tampered" { forward_to = [] }`, signature: signature},
+		{name: "unsigned", content: cfg},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The API is unavailable, so the cached configuration is loaded.
+			client := &collectorClient{}
+			var registerCalled atomic.Bool
+			client.registerCollectorFunc = buildRegisterCollectorFunc(&registerCalled)
+			client.getConfigFunc = func(context.Context, *connect.Request[collectorv1.GetConfigRequest]) (*connect.Response[collectorv1.GetConfigResponse], error) {
+				return nil, connect.NewError(connect.CodeUnavailable, fmt.Errorf("unavailable"))
+			}
+
+			env := newTestEnvironment(t, client)
+			require.NoError(t, env.ApplyConfig(fmt.Sprintf(`
+				url = "https://example.com/"
+				signature_verification {
+					public_key_file = "%s"
+				}
+			`, keyFile)))
+			env.svc.setCachedConfig([]byte(tt.content), tt.signature)
+
+			ctx, cancel := context.WithCancel(t.Context())
+			wg := sync.WaitGroup{}
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				require.NoError(t, env.Run(ctx))
+			}()
+
+			if tt.loaded {
+				require.EventuallyWithT(t, func(c *assert.CollectT) {
+					assert.Equal(c, getHash([]byte(tt.content)), env.svc.getLastLoadedCfgHash())
+					assert.NotNil(c, env.svc.GetCachedAstFile())
+				}, time.Second, 10*time.Millisecond)
+				require.Zero(t, testutil.ToFloat64(env.svc.metrics.signatureFailures))
+			} else {
+				require.EventuallyWithT(t, func(c *assert.CollectT) {
+					assert.Equal(c, 1.0, testutil.ToFloat64(env.svc.metrics.signatureFailures))
+				}, time.Second, 10*time.Millisecond)
+				require.Nil(t, env.svc.GetCachedAstFile())
+			}
+
+			cancel()
+			wg.Wait()
+		})
+	}
+}
+
+func TestVerifySignatureECDSA(t *testing.T) {
+	content := []byte(`loki.process "default" { forward_to = [] }`)
+
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKIXPublicKey(&priv.PublicKey)
+	require.NoError(t, err)
+
+	args := SignatureVerificationArguments{
+		PublicKey: string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})),
+	}
+	key, err := args.loadKey()
+	require.NoError(t, err)
+
+	digest := sha256.Sum256(content)
+	sig, err := ecdsa.SignASN1(rand.Reader, priv, digest[:])
+	require.NoError(t, err)
+
+	require.NoError(t, verifySignature(key, content, base64.StdEncoding.EncodeToString(sig)))
+	require.ErrorIs(t, verifySignature(key, []byte("tampered"), base64.StdEncoding.EncodeToString(sig)), errInvalidSignature)
+	require.ErrorIs(t, verifySignature(key, content, "not base64"), errInvalidSignature)
+}
+
+func TestSignatureVerificationValidate(t *testing.T) {
+	var args Arguments
+	err := syntax.Unmarshal([]byte(`signature_verification { }`), &args)
+	require.ErrorContains(t, err, "one of public_key or public_key_file must be set in the signature_verification block")
+
+	_, err = (&SignatureVerificationArguments{PublicKey: "not a key"}).loadKey()
+	require.ErrorContains(t, err, "no PEM block found")
+}
+
 func TestAPIResponseNotModified(t *testing.T) {
 	ctx, cancel := context.WithCancel(t.Context())
 	url := "https://example.com/"
@@ -252,6 +552,87 @@ func TestAPIResponseNotModified(t *testing.T) {
 	wg.Wait()
 }
 
+func TestStatusReporting(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	url := "https://example.com/"
+	cfgGood := `loki.process "default" { forward_to = [] }`
+	cfgBad := `unparseable config`
+
+	client := &collectorClient{}
+
+	// Mock client to record the reported status and return a valid response.
+	var (
+		statusMut sync.Mutex
+		status    Status
+	)
+	recordStatus := func(handler func(context.Context, *connect.Request[collectorv1.GetConfigRequest]) (*connect.Response[collectorv1.GetConfigResponse], error)) func(context.Context, *connect.Request[collectorv1.GetConfigRequest]) (*connect.Response[collectorv1.GetConfigResponse], error) {
+		return func(ctx context.Context, req *connect.Request[collectorv1.GetConfigRequest]) (*connect.Response[collectorv1.GetConfigResponse], error) {
+			b, err := base64.RawURLEncoding.DecodeString(req.Header().Get(StatusHeader))
+			assert.NoError(t, err)
+
+			statusMut.Lock()
+			assert.NoError(t, json.Unmarshal(b, &status))
+			statusMut.Unlock()
+			return handler(ctx, req)
+		}
+	}
+	lastStatus := func() Status {
+		statusMut.Lock()
+		defer statusMut.Unlock()
+		return status
+	}
+
+	var registerCalled atomic.Bool
+	client.mut.Lock()
+	client.getConfigFunc = recordStatus(buildGetConfigHandler(cfgGood, "rev-1", false))
+	client.registerCollectorFunc = buildRegisterCollectorFunc(&registerCalled)
+	client.mut.Unlock()
+
+	// Create a new service.
+	env := newTestEnvironment(t, client)
+	require.NoError(t, env.ApplyConfig(fmt.Sprintf(`
+		url            = "%s"
+		poll_frequency = "10s"
+	`, url)))
+
+	// Run the service.
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		require.NoError(t, env.Run(ctx))
+	}()
+
+	// Polls after the good configuration was loaded report it as applied,
+	// along with the health of its components.
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		st := lastStatus()
+		assert.Equal(c, ApplyStateApplied, st.ApplyState)
+		assert.Equal(c, "rev-1", st.LastRemoteHash)
+		assert.Equal(c, getHash([]byte(cfgGood)), st.LastConfigHash)
+		assert.Equal(c, getHash([]byte(cfgGood)), st.EffectiveConfigHash)
+		assert.Equal(c, 1, st.Components.Healthy+st.Components.Unknown)
+	}, time.Second, 10*time.Millisecond)
+
+	// Update the response returned by the API to an invalid configuration.
+	client.mut.Lock()
+	client.getConfigFunc = recordStatus(buildGetConfigHandler(cfgBad, "rev-2", false))
+	client.mut.Unlock()
+
+	// The failure is reported, while the good configuration keeps running.
+	require.EventuallyWithT(t, func(c *assert.CollectT) {
+		st := lastStatus()
+		assert.Equal(c, ApplyStateFailed, st.ApplyState)
+		assert.NotEmpty(c, st.ApplyError)
+		assert.Equal(c, "rev-2", st.LastRemoteHash)
+		assert.Equal(c, getHash([]byte(cfgBad)), st.LastConfigHash)
+		assert.Equal(c, getHash([]byte(cfgGood)), st.EffectiveConfigHash)
+	}, time.Second, 10*time.Millisecond)
+
+	cancel()
+	wg.Wait()
+}
+
 func buildGetConfigHandler(in string, hash string, notModified bool) func(context.Context, *connect.Request[collectorv1.GetConfigRequest]) (*connect.Response[collectorv1.GetConfigResponse], error) {
 	return func(context.Context, *connect.Request[collectorv1.GetConfigRequest]) (*connect.Response[collectorv1.GetConfigResponse], error) {
 		rsp := &connect.Response[collectorv1.GetConfigResponse]{
@@ -391,4 +772,5 @@ func (sc serviceController) LoadSource(b []byte, args map[string]any, configPath
 	}
 	return source.SourceFiles()[""], sc.f.LoadSource(source, args, configPath)
 }
-func (sc serviceController) Ready() bool { return sc.f.Ready() }
+func (sc serviceController) Ready() bool           { return sc.f.Ready() }
+func (sc serviceController) GetHost() service.Host { return sc.f }
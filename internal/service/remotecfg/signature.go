@@ -0,0 +1,100 @@
+package remotecfg
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SignatureHeader is the response header which carries the detached
+// signature of the configuration returned by the API. The value of the header
+// is the signature of the configuration content, encoded as standard base64,
+// as produced by `cosign sign-blob`.
+const SignatureHeader = "X-Alloy-Remotecfg-Signature"
+
+// signatureFileSuffix is appended to the path of the on-disk cache to store
+// the signature of the cached configuration.
+const signatureFileSuffix = ".sig"
+
+var errInvalidSignature = errors.New("invalid configuration signature")
+
+// SignatureVerificationArguments configures the verification of the
+// signature of the configuration returned by the API.
+type SignatureVerificationArguments struct {
+	PublicKey     string `alloy:"public_key,attr,optional"`
+	PublicKeyFile string `alloy:"public_key_file,attr,optional"`
+}
+
+// Validate implements syntax.Validator.
+func (a *SignatureVerificationArguments) Validate() error {
+	switch {
+	case a.PublicKey == "" && a.PublicKeyFile == "":
+		return errors.New("one of public_key or public_key_file must be set in the signature_verification block")
+	case a.PublicKey != "" && a.PublicKeyFile != "":
+		return errors.New("at most one of public_key and public_key_file can be set in the signature_verification block")
+	}
+	return nil
+}
+
+// loadKey returns the PEM-encoded public key which signs the configuration.
+// Only Ed25519 and ECDSA keys are supported.
+func (a *SignatureVerificationArguments) loadKey() (crypto.PublicKey, error) {
+	b := []byte(a.PublicKey)
+	if a.PublicKeyFile != "" {
+		var err error
+		b, err = os.ReadFile(a.PublicKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read signature public key file: %w", err)
+		}
+	}
+
+	block, _ := pem.Decode(b)
+	if block == nil {
+		return nil, errors.New("failed to decode signature public key: no PEM block found")
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signature public key: %w", err)
+	}
+
+	switch key.(type) {
+	case ed25519.PublicKey, *ecdsa.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("unsupported signature public key type %T, must be Ed25519 or ECDSA", key)
+	}
+}
+
+// verifySignature verifies the base64-encoded signature of content. Ed25519
+// signatures are computed over content, while ECDSA signatures are computed
+// over its SHA-256 digest, the same way as cosign does.
+func verifySignature(key crypto.PublicKey, content []byte, signature string) error {
+	if signature == "" {
+		return fmt.Errorf("%w: the configuration isn't signed", errInvalidSignature)
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(signature))
+	if err != nil {
+		return fmt.Errorf("%w: failed to decode signature: %w", errInvalidSignature, err)
+	}
+
+	var valid bool
+	switch key := key.(type) {
+	case ed25519.PublicKey:
+		valid = ed25519.Verify(key, content, sig)
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(content)
+		valid = ecdsa.VerifyASN1(key, digest[:], sig)
+	}
+	if !valid {
+		return fmt.Errorf("%w: the signature doesn't match the configuration", errInvalidSignature)
+	}
+	return nil
+}
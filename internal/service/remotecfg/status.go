@@ -0,0 +1,143 @@
+package remotecfg
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"sort"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/internal/service"
+)
+
+// StatusHeader is the request header which carries the status of the
+// collector to the API each time it's polled for configuration. The value of
+// the header is a [Status] encoded as JSON, then as unpadded base64url.
+const StatusHeader = "X-Alloy-Remotecfg-Status"
+
+// Limits which keep the size of the status header reasonable.
+const (
+	maxReportedUnhealthyComponents = 10
+	maxReportedMessageLength       = 256
+	maxReportedErrorLength         = 1024
+)
+
+// ApplyState is the result of applying the last configuration received from
+// the API.
+type ApplyState string
+
+// Possible values of ApplyState.
+const (
+	ApplyStateUnset   ApplyState = "unset"   // No configuration has been applied yet.
+	ApplyStateApplied ApplyState = "applied" // The last configuration was applied successfully.
+	ApplyStateFailed  ApplyState = "failed"  // The last configuration failed to be applied.
+)
+
+// Status is reported to the API on each poll, so that a fleet of collectors
+// can be monitored from the API side.
+type Status struct {
+	// Hash returned by the API alongside the last configuration received, if
+	// any.
+	LastRemoteHash string `json:"last_remote_hash,omitempty"`
+	// Hash of the content of the last configuration received.
+	LastConfigHash string `json:"last_config_hash,omitempty"`
+	// Hash of the content of the configuration which is currently running.
+	// It's different from LastConfigHash when the last configuration failed
+	// to be applied.
+	EffectiveConfigHash string `json:"effective_config_hash,omitempty"`
+
+	ApplyState ApplyState `json:"apply_state"`
+	ApplyError string     `json:"apply_error,omitempty"`
+
+	Components ComponentsStatus `json:"components"`
+}
+
+// ComponentsStatus summarizes the health of the components defined by the
+// remote configuration, including the ones running in modules.
+type ComponentsStatus struct {
+	Healthy   int `json:"healthy"`
+	Unhealthy int `json:"unhealthy"`
+	Unknown   int `json:"unknown"`
+	Exited    int `json:"exited"`
+
+	// Up to 10 unhealthy or exited components, sorted by ID.
+	Failing []FailingComponent `json:"failing,omitempty"`
+}
+
+// FailingComponent is a component which is either unhealthy or has exited.
+type FailingComponent struct {
+	ID      string `json:"id"`
+	Health  string `json:"health"`
+	Message string `json:"message,omitempty"`
+}
+
+// Status returns the status which is reported to the API on the next poll.
+func (s *Service) Status() Status {
+	s.mut.RLock()
+	status := Status{
+		LastRemoteHash:      s.remoteHash,
+		LastConfigHash:      s.lastLoadedConfigHash,
+		EffectiveConfigHash: s.appliedConfigHash,
+		ApplyState:          ApplyStateUnset,
+	}
+	switch {
+	case s.lastApplyErr != nil:
+		status.ApplyState = ApplyStateFailed
+		status.ApplyError = truncate(s.lastApplyErr.Error(), maxReportedErrorLength)
+	case s.appliedConfigHash != "":
+		status.ApplyState = ApplyStateApplied
+	}
+	ctrl := s.ctrl
+	s.mut.RUnlock()
+
+	// The host is only exposed by the controller of the runtime.
+	if hc, ok := ctrl.(interface{ GetHost() service.Host }); ok {
+		status.Components = componentsStatus(hc.GetHost())
+	}
+	return status
+}
+
+func componentsStatus(host service.Host) ComponentsStatus {
+	var res ComponentsStatus
+
+	for _, info := range component.GetAllComponents(host, component.InfoOptions{GetHealth: true}) {
+		switch info.Health.Health {
+		case component.HealthTypeHealthy:
+			res.Healthy++
+			continue
+		case component.HealthTypeUnknown:
+			res.Unknown++
+			continue
+		case component.HealthTypeUnhealthy:
+			res.Unhealthy++
+		case component.HealthTypeExited:
+			res.Exited++
+		}
+		res.Failing = append(res.Failing, FailingComponent{
+			ID:      info.ID.String(),
+			Health:  info.Health.Health.String(),
+			Message: truncate(info.Health.Message, maxReportedMessageLength),
+		})
+	}
+
+	sort.Slice(res.Failing, func(i, j int) bool { return res.Failing[i].ID < res.Failing[j].ID })
+	if len(res.Failing) > maxReportedUnhealthyComponents {
+		res.Failing = res.Failing[:maxReportedUnhealthyComponents]
+	}
+	return res
+}
+
+// encodeStatus encodes st as the value of StatusHeader.
+func encodeStatus(st Status) (string, error) {
+	b, err := json.Marshal(st)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
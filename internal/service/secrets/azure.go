@@ -0,0 +1,98 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+const (
+	azureKeyVaultAPIVersion = "7.4"
+	azureKeyVaultScope      = "https://vault.azure.net/.default"
+)
+
+// AzureKeyVaultArguments configures an Azure Key Vault secret provider. The
+// path given to sys.secret is the name of the secret, optionally followed by
+// /<version>. Credentials are loaded from the default Azure credential chain.
+type AzureKeyVaultArguments struct {
+	Name     string `alloy:",label"`
+	VaultURL string `alloy:"vault_url,attr"`
+}
+
+// Validate implements syntax.Validator.
+func (args *AzureKeyVaultArguments) Validate() error {
+	u, err := url.Parse(args.VaultURL)
+	if err != nil {
+		return fmt.Errorf("invalid vault_url: %w", err)
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("vault_url must be an https URL, like https://<vault>.vault.azure.net")
+	}
+	return nil
+}
+
+type azureKeyVaultProvider struct {
+	vaultURL string
+	cred     azcore.TokenCredential
+	cli      *http.Client
+}
+
+func newAzureKeyVaultProvider(args AzureKeyVaultArguments) (*azureKeyVaultProvider, error) {
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, err
+	}
+	return &azureKeyVaultProvider{
+		vaultURL: strings.TrimSuffix(args.VaultURL, "/"),
+		cred:     cred,
+		cli:      http.DefaultClient,
+	}, nil
+}
+
+// Read implements provider.
+func (ap *azureKeyVaultProvider) Read(ctx context.Context, path string) (*secretValue, error) {
+	token, err := ap.cred.GetToken(ctx, policy.TokenRequestOptions{Scopes: []string{azureKeyVaultScope}})
+	if err != nil {
+		return nil, fmt.Errorf("getting token: %w", err)
+	}
+
+	name, version, _ := strings.Cut(path, "/")
+	u := ap.vaultURL + "/secrets/" + url.PathEscape(name)
+	if version != "" {
+		u += "/" + url.PathEscape(version)
+	}
+	u += "?api-version=" + azureKeyVaultAPIVersion
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+token.Token)
+
+	resp, err := ap.cli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf("unexpected status code %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var secret struct {
+		Value string `json:"value"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return nil, fmt.Errorf("decoding response: %w", err)
+	}
+	return &secretValue{data: map[string]string{"": secret.Value}}, nil
+}
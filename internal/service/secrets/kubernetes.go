@@ -0,0 +1,64 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/go-kit/log"
+	commonk8s "github.com/grafana/alloy/internal/component/common/kubernetes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// KubernetesArguments configures a Kubernetes secret provider. The path given
+// to sys.secret has the form <namespace>/<name>.
+type KubernetesArguments struct {
+	Name   string                    `alloy:",label"`
+	Client commonk8s.ClientArguments `alloy:"client,block,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *KubernetesArguments) SetToDefault() {
+	*args = KubernetesArguments{
+		Client: commonk8s.DefaultClientArguments,
+	}
+}
+
+type kubernetesProvider struct {
+	cli kubernetes.Interface
+}
+
+func newKubernetesProvider(args KubernetesArguments, l log.Logger) (*kubernetesProvider, error) {
+	restConfig, err := args.Client.BuildRESTConfig(l)
+	if err != nil {
+		return nil, err
+	}
+	cli, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	return &kubernetesProvider{cli: cli}, nil
+}
+
+// Read implements provider.
+func (kp *kubernetesProvider) Read(ctx context.Context, path string) (*secretValue, error) {
+	namespace, name, ok := strings.Cut(path, "/")
+	if !ok || namespace == "" || name == "" {
+		return nil, fmt.Errorf("invalid path %q: expected <namespace>/<name>", path)
+	}
+
+	secret, err := kp.cli.CoreV1().Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	value := &secretValue{data: make(map[string]string, len(secret.Data)+len(secret.StringData))}
+	for k, v := range secret.Data {
+		value.data[k] = string(v)
+	}
+	for k, v := range secret.StringData {
+		value.data[k] = v
+	}
+	return value, nil
+}
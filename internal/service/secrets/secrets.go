@@ -0,0 +1,444 @@
+// Package secrets implements the secrets service, which resolves secrets from
+// external secret stores for the sys.secret standard library function.
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-kit/log"
+	"github.com/grafana/alloy/internal/featuregate"
+	"github.com/grafana/alloy/internal/runtime/logging/level"
+	"github.com/grafana/alloy/internal/service"
+	"github.com/grafana/alloy/syntax/alloytypes"
+)
+
+// ServiceName defines the name used for the secrets service.
+const ServiceName = "secrets"
+
+const (
+	// fetchTimeout is the maximum time spent fetching a secret from a
+	// provider.
+	fetchTimeout = 30 * time.Second
+
+	// refreshInterval is how often cached secrets are checked for renewal.
+	refreshInterval = 10 * time.Second
+)
+
+// Options are used to configure the secrets service. Options are constant
+// for the lifetime of the secrets service.
+type Options struct {
+	Logger log.Logger // Where to send logs.
+}
+
+// Arguments holds runtime settings for the secrets service.
+type Arguments struct {
+	CacheTTL time.Duration `alloy:"cache_ttl,attr,optional"`
+
+	Vault             []VaultArguments             `alloy:"vault,block,optional"`
+	AWSSecretsManager []AWSSecretsManagerArguments `alloy:"aws_secrets_manager,block,optional"`
+	AzureKeyVault     []AzureKeyVaultArguments     `alloy:"azure_key_vault,block,optional"`
+	Kubernetes        []KubernetesArguments        `alloy:"kubernetes,block,optional"`
+}
+
+// DefaultArguments holds default settings for Arguments.
+var DefaultArguments = Arguments{
+	CacheTTL: 5 * time.Minute,
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *Arguments) SetToDefault() {
+	*args = DefaultArguments
+}
+
+// Validate implements syntax.Validator.
+func (args *Arguments) Validate() error {
+	if args.CacheTTL <= 0 {
+		return fmt.Errorf("cache_ttl must be greater than 0")
+	}
+
+	names := make(map[string]struct{})
+	for _, name := range args.providerNames() {
+		switch {
+		case name == "":
+			return fmt.Errorf("secret provider names must not be empty")
+		case strings.ContainsAny(name, "/#"):
+			return fmt.Errorf("secret provider name %q must not contain '/' or '#'", name)
+		}
+		if _, ok := names[name]; ok {
+			return fmt.Errorf("secret provider name %q is used more than once", name)
+		}
+		names[name] = struct{}{}
+	}
+	return nil
+}
+
+func (args *Arguments) providerNames() []string {
+	var names []string
+	for _, p := range args.Vault {
+		names = append(names, p.Name)
+	}
+	for _, p := range args.AWSSecretsManager {
+		names = append(names, p.Name)
+	}
+	for _, p := range args.AzureKeyVault {
+		names = append(names, p.Name)
+	}
+	for _, p := range args.Kubernetes {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// provider reads secrets from a secret store.
+type provider interface {
+	// Read reads the secret at path.
+	Read(ctx context.Context, path string) (*secretValue, error)
+}
+
+// secretValue is a secret read from a provider.
+type secretValue struct {
+	// data holds the fields of the secret. Secrets without fields, like AWS
+	// Secrets Manager secret strings, are stored under the empty key.
+	data map[string]string
+
+	// ttl is how long the secret can be cached for. 0 means that the
+	// configured cache TTL applies.
+	ttl time.Duration
+
+	// renew optionally extends the lease of the secret, returning its new
+	// TTL. When nil or failing, the secret is read again instead.
+	renew func(ctx context.Context) (time.Duration, error)
+}
+
+// field returns the value of key. An empty key selects the only field of the
+// secret. For secrets without fields, key selects a field of the secret
+// decoded as a JSON object.
+func (sv *secretValue) field(key string) (string, error) {
+	if key == "" {
+		if len(sv.data) != 1 {
+			return "", fmt.Errorf("secret has %d fields; select one with #<key>", len(sv.data))
+		}
+		for _, v := range sv.data {
+			return v, nil
+		}
+	}
+
+	if v, ok := sv.data[key]; ok {
+		return v, nil
+	}
+
+	raw, ok := sv.data[""]
+	if !ok {
+		return "", fmt.Errorf("secret has no field %q", key)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(raw), &fields); err != nil {
+		return "", fmt.Errorf("selecting field %q: secret isn't a JSON object", key)
+	}
+	v, ok := fields[key]
+	if !ok {
+		return "", fmt.Errorf("secret has no field %q", key)
+	}
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(v), nil
+}
+
+// cacheEntry is a cached secret.
+type cacheEntry struct {
+	value     *secretValue
+	fetchedAt time.Time
+	expiresAt time.Time
+}
+
+// Service implements the secrets service.
+type Service struct {
+	opts Options
+
+	mut       sync.RWMutex
+	args      Arguments
+	providers map[string]provider
+	cache     map[string]*cacheEntry // Cached secrets by provider name and path.
+
+	// onChange holds the functions to call when refreshed secrets change, by
+	// subscription ID.
+	subMut   sync.Mutex
+	onChange map[int]func()
+	nextSub  int
+
+	now func() time.Time
+}
+
+var _ service.Service = (*Service)(nil)
+
+// New returns a new, unstarted instance of the secrets service.
+func New(opts Options) *Service {
+	if opts.Logger == nil {
+		opts.Logger = log.NewNopLogger()
+	}
+
+	return &Service{
+		opts:      opts,
+		providers: make(map[string]provider),
+		cache:     make(map[string]*cacheEntry),
+		onChange:  make(map[int]func()),
+		now:       time.Now,
+	}
+}
+
+// Definition returns the definition of the secrets service.
+func (s *Service) Definition() service.Definition {
+	return service.Definition{
+		Name:       ServiceName,
+		ConfigType: Arguments{},
+		DependsOn:  nil, // secrets has no dependencies.
+		Stability:  featuregate.StabilityExperimental,
+	}
+}
+
+// Run starts the secrets service. It blocks until the provided context is
+// canceled, renewing cached secrets before they expire.
+func (s *Service) Run(ctx context.Context, host service.Host) error {
+	ticker := time.NewTicker(refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			s.refresh(ctx)
+		}
+	}
+}
+
+// Update implements service.Service. Cached secrets are discarded, since
+// their providers may have changed.
+func (s *Service) Update(newConfig any) error {
+	newArgs := newConfig.(Arguments)
+
+	providers := make(map[string]provider)
+	for _, p := range newArgs.Vault {
+		vp, err := newVaultProvider(p)
+		if err != nil {
+			return fmt.Errorf("building vault %q secret provider: %w", p.Name, err)
+		}
+		providers[p.Name] = vp
+	}
+	for _, p := range newArgs.AWSSecretsManager {
+		ap, err := newAWSSecretsManagerProvider(p)
+		if err != nil {
+			return fmt.Errorf("building aws_secrets_manager %q secret provider: %w", p.Name, err)
+		}
+		providers[p.Name] = ap
+	}
+	for _, p := range newArgs.AzureKeyVault {
+		ap, err := newAzureKeyVaultProvider(p)
+		if err != nil {
+			return fmt.Errorf("building azure_key_vault %q secret provider: %w", p.Name, err)
+		}
+		providers[p.Name] = ap
+	}
+	for _, p := range newArgs.Kubernetes {
+		kp, err := newKubernetesProvider(p, s.opts.Logger)
+		if err != nil {
+			return fmt.Errorf("building kubernetes %q secret provider: %w", p.Name, err)
+		}
+		providers[p.Name] = kp
+	}
+
+	s.mut.Lock()
+	s.args = newArgs
+	s.providers = providers
+	s.cache = make(map[string]*cacheEntry)
+	s.mut.Unlock()
+	return nil
+}
+
+// Data returns the service, which resolves the secrets of sys.secret for the
+// controller.
+func (s *Service) Data() any { return s }
+
+// ResolveSecret returns the secret identified by path, which has the form
+// <provider>/<path>[#<key>]. Secrets are cached until their lease or the
+// cache TTL expires.
+func (s *Service) ResolveSecret(path string) (alloytypes.Secret, error) {
+	name, secretPath, key, err := parsePath(path)
+	if err != nil {
+		return "", err
+	}
+
+	entry, err := s.get(name, secretPath)
+	if err != nil {
+		return "", fmt.Errorf("reading secret %q: %w", path, err)
+	}
+	v, err := entry.value.field(key)
+	if err != nil {
+		return "", fmt.Errorf("reading secret %q: %w", path, err)
+	}
+	return alloytypes.Secret(v), nil
+}
+
+// OnSecretsChange calls f whenever a refresh changes the value of a cached
+// secret, until the returned function is called.
+func (s *Service) OnSecretsChange(f func()) (cancel func()) {
+	s.subMut.Lock()
+	defer s.subMut.Unlock()
+
+	id := s.nextSub
+	s.nextSub++
+	s.onChange[id] = f
+
+	return func() {
+		s.subMut.Lock()
+		defer s.subMut.Unlock()
+		delete(s.onChange, id)
+	}
+}
+
+func (s *Service) notifyChange() {
+	s.subMut.Lock()
+	defer s.subMut.Unlock()
+	for _, f := range s.onChange {
+		f()
+	}
+}
+
+// parsePath splits a sys.secret path into the name of its provider, the path
+// of the secret within the provider and an optional key.
+func parsePath(path string) (name, secretPath, key string, err error) {
+	if i := strings.LastIndex(path, "#"); i >= 0 {
+		path, key = path[:i], path[i+1:]
+	}
+	name, secretPath, ok := strings.Cut(path, "/")
+	if !ok || name == "" || secretPath == "" {
+		return "", "", "", fmt.Errorf("invalid secret path %q: expected <provider>/<path>[#<key>]", path)
+	}
+	return name, secretPath, key, nil
+}
+
+func (s *Service) get(name, path string) (*cacheEntry, error) {
+	cacheKey := name + "/" + path
+
+	s.mut.RLock()
+	entry, cached := s.cache[cacheKey]
+	p, found := s.providers[name]
+	s.mut.RUnlock()
+
+	if cached && s.now().Before(entry.expiresAt) {
+		return entry, nil
+	}
+	if !found {
+		return nil, fmt.Errorf("secret provider %q not found", name)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	value, err := p.Read(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	entry = s.newCacheEntry(value)
+
+	s.mut.Lock()
+	s.cache[cacheKey] = entry
+	s.mut.Unlock()
+	return entry, nil
+}
+
+func (s *Service) newCacheEntry(value *secretValue) *cacheEntry {
+	now := s.now()
+	return &cacheEntry{
+		value:     value,
+		fetchedAt: now,
+		expiresAt: now.Add(s.ttl(value.ttl)),
+	}
+}
+
+// ttl returns how long a secret with the given lease TTL can be cached for.
+func (s *Service) ttl(leaseTTL time.Duration) time.Duration {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+
+	if leaseTTL > 0 && leaseTTL < s.args.CacheTTL {
+		return leaseTTL
+	}
+	return s.args.CacheTTL
+}
+
+// refresh renews or reads again cached secrets which are past half of their
+// TTL, so that they're valid the next time they're resolved. The subscribers
+// are notified when the value of a secret changed, so that the blocks using
+// it are evaluated again.
+func (s *Service) refresh(ctx context.Context) {
+	s.mut.RLock()
+	var (
+		now   = s.now()
+		stale = make(map[string]*cacheEntry)
+	)
+	for key, entry := range s.cache {
+		if now.Sub(entry.fetchedAt) >= entry.expiresAt.Sub(entry.fetchedAt)/2 {
+			stale[key] = entry
+		}
+	}
+	s.mut.RUnlock()
+
+	var changed bool
+	for key, entry := range stale {
+		newEntry, err := s.refreshEntry(ctx, key, entry)
+		if err != nil {
+			level.Warn(s.opts.Logger).Log("msg", "failed to refresh secret", "secret", key, "err", err)
+			continue
+		}
+
+		s.mut.Lock()
+		// Don't overwrite the cache if it's been reset by an update.
+		if s.cache[key] == entry {
+			s.cache[key] = newEntry
+			changed = changed || !maps.Equal(entry.value.data, newEntry.value.data)
+		}
+		s.mut.Unlock()
+	}
+
+	if changed {
+		s.notifyChange()
+	}
+}
+
+func (s *Service) refreshEntry(ctx context.Context, key string, entry *cacheEntry) (*cacheEntry, error) {
+	ctx, cancel := context.WithTimeout(ctx, fetchTimeout)
+	defer cancel()
+
+	if entry.value.renew != nil {
+		ttl, err := entry.value.renew(ctx)
+		if err == nil {
+			value := *entry.value
+			value.ttl = ttl
+			return s.newCacheEntry(&value), nil
+		}
+		level.Debug(s.opts.Logger).Log("msg", "failed to renew secret lease, reading it again", "secret", key, "err", err)
+	}
+
+	name, path, _ := strings.Cut(key, "/")
+
+	s.mut.RLock()
+	p, found := s.providers[name]
+	s.mut.RUnlock()
+	if !found {
+		return nil, fmt.Errorf("secret provider %q not found", name)
+	}
+
+	value, err := p.Read(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	return s.newCacheEntry(value), nil
+}
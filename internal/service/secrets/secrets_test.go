@@ -0,0 +1,240 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/syntax"
+	"github.com/grafana/alloy/syntax/alloytypes"
+	"github.com/grafana/alloy/syntax/parser"
+	"github.com/grafana/alloy/syntax/vm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArguments(t *testing.T) {
+	var args Arguments
+	err := syntax.Unmarshal([]byte(`
+		cache_ttl = "1m"
+
+		vault "prod" {
+			server = "https://vault.example.com"
+
+			auth.token {
+				token = "token"
+			}
+		}
+
+		aws_secrets_manager "aws" {
+			region = "us-east-1"
+		}
+
+		azure_key_vault "azure" {
+			vault_url = "https://example.vault.azure.net"
+		}
+	`), &args)
+	require.NoError(t, err)
+	require.Equal(t, time.Minute, args.CacheTTL)
+	require.Equal(t, []string{"prod", "aws", "azure"}, args.providerNames())
+
+	err = syntax.Unmarshal([]byte(`
+		vault "prod" {
+			server = "https://vault.example.com"
+		}
+	`), &args)
+	require.ErrorContains(t, err, "exactly one auth.* block must be specified; found 0")
+
+	err = syntax.Unmarshal([]byte(`
+		aws_secrets_manager "default" {}
+		azure_key_vault "default" {
+			vault_url = "https://example.vault.azure.net"
+		}
+	`), &args)
+	require.ErrorContains(t, err, `secret provider name "default" is used more than once`)
+
+	err = syntax.Unmarshal([]byte(`
+		azure_key_vault "default" {
+			vault_url = "http://example.vault.azure.net"
+		}
+	`), &args)
+	require.ErrorContains(t, err, "vault_url must be an https URL")
+}
+
+func TestParsePath(t *testing.T) {
+	tt := []struct {
+		path                  string
+		name, secretPath, key string
+		err                   string
+	}{
+		{path: "vault/secret/data/db#password", name: "vault", secretPath: "secret/data/db", key: "password"},
+		{path: "aws/prod/db", name: "aws", secretPath: "prod/db"},
+		{path: "k8s/default/creds#a#b", name: "k8s", secretPath: "default/creds#a", key: "b"},
+		{path: "vault", err: "invalid secret path"},
+		{path: "/secret", err: "invalid secret path"},
+	}
+
+	for _, tc := range tt {
+		t.Run(tc.path, func(t *testing.T) {
+			name, secretPath, key, err := parsePath(tc.path)
+			if tc.err != "" {
+				require.ErrorContains(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.name, name)
+			require.Equal(t, tc.secretPath, secretPath)
+			require.Equal(t, tc.key, key)
+		})
+	}
+}
+
+func TestSecretValueField(t *testing.T) {
+	fields := &secretValue{data: map[string]string{"username": "admin", "password": "hunter2"}}
+
+	v, err := fields.field("password")
+	require.NoError(t, err)
+	require.Equal(t, "hunter2", v)
+
+	_, err = fields.field("")
+	require.ErrorContains(t, err, "secret has 2 fields; select one with #<key>")
+
+	_, err = fields.field("token")
+	require.ErrorContains(t, err, `secret has no field "token"`)
+
+	raw := &secretValue{data: map[string]string{"": `{"password": "hunter2", "port": 5432}`}}
+
+	v, err = raw.field("")
+	require.NoError(t, err)
+	require.Equal(t, `{"password": "hunter2", "port": 5432}`, v)
+
+	v, err = raw.field("port")
+	require.NoError(t, err)
+	require.Equal(t, "5432", v)
+
+	_, err = (&secretValue{data: map[string]string{"": "plain"}}).field("password")
+	require.ErrorContains(t, err, "secret isn't a JSON object")
+}
+
+func TestResolve(t *testing.T) {
+	s, p := newTestService(t)
+
+	v, err := s.ResolveSecret("fake/db#password")
+	require.NoError(t, err)
+	require.Equal(t, alloytypes.Secret("hunter2"), v)
+
+	// Secrets are cached until the cache TTL expires.
+	p.values["db"] = map[string]string{"password": "changed"}
+	v, err = s.ResolveSecret("fake/db#password")
+	require.NoError(t, err)
+	require.Equal(t, alloytypes.Secret("hunter2"), v)
+	require.Equal(t, 1, p.reads)
+
+	s.now = func() time.Time { return time.Now().Add(DefaultArguments.CacheTTL) }
+	v, err = s.ResolveSecret("fake/db#password")
+	require.NoError(t, err)
+	require.Equal(t, alloytypes.Secret("changed"), v)
+	require.Equal(t, 2, p.reads)
+
+	_, err = s.ResolveSecret("fake/missing")
+	require.ErrorContains(t, err, `reading secret "fake/missing": not found`)
+
+	_, err = s.ResolveSecret("other/db")
+	require.ErrorContains(t, err, `reading secret "other/db": secret provider "other" not found`)
+}
+
+func TestRefresh(t *testing.T) {
+	s, p := newTestService(t)
+
+	var renewals int
+	p.ttl = time.Minute
+	p.renew = func(ctx context.Context) (time.Duration, error) {
+		renewals++
+		if renewals > 1 {
+			return 0, errors.New("lease expired")
+		}
+		return time.Minute, nil
+	}
+
+	var changes int
+	cancel := s.OnSecretsChange(func() { changes++ })
+	defer cancel()
+
+	_, err := s.ResolveSecret("fake/db#password")
+	require.NoError(t, err)
+
+	// Secrets aren't refreshed before half of their TTL.
+	s.refresh(context.Background())
+	require.Equal(t, 0, renewals)
+
+	// The lease is renewed past half of its TTL.
+	start := time.Now()
+	s.now = func() time.Time { return start.Add(31 * time.Second) }
+	s.refresh(context.Background())
+	require.Equal(t, 1, renewals)
+	require.Equal(t, 1, p.reads)
+
+	// The secret is read again when renewing the lease fails.
+	s.now = func() time.Time { return start.Add(62 * time.Second) }
+	s.refresh(context.Background())
+	require.Equal(t, 2, renewals)
+	require.Equal(t, 2, p.reads)
+
+	// Subscribers are only notified when the value of a secret changes.
+	require.Equal(t, 0, changes)
+	p.values["db"] = map[string]string{"password": "changed"}
+	s.now = func() time.Time { return start.Add(93 * time.Second) }
+	s.refresh(context.Background())
+	require.Equal(t, 3, p.reads)
+	require.Equal(t, 1, changes)
+
+	cancel()
+	p.values["db"] = map[string]string{"password": "changed again"}
+	s.now = func() time.Time { return start.Add(124 * time.Second) }
+	s.refresh(context.Background())
+	require.Equal(t, 4, p.reads)
+	require.Equal(t, 1, changes)
+}
+
+func TestStdlibSecret(t *testing.T) {
+	s, _ := newTestService(t)
+
+	expr, err := parser.ParseExpression(`sys.secret("fake/db#password")`)
+	require.NoError(t, err)
+
+	var v alloytypes.Secret
+	scope := vm.NewScope(vm.SecretResolverVariables(s.ResolveSecret))
+	require.NoError(t, vm.New(expr).Evaluate(scope, &v))
+	require.Equal(t, alloytypes.Secret("hunter2"), v)
+}
+
+func newTestService(t *testing.T) (*Service, *fakeProvider) {
+	t.Helper()
+
+	s := New(Options{})
+	require.NoError(t, s.Update(DefaultArguments))
+
+	p := &fakeProvider{
+		values: map[string]map[string]string{
+			"db": {"username": "admin", "password": "hunter2"},
+		},
+	}
+	s.providers["fake"] = p
+	return s, p
+}
+
+type fakeProvider struct {
+	values map[string]map[string]string
+	ttl    time.Duration
+	renew  func(ctx context.Context) (time.Duration, error)
+	reads  int
+}
+
+func (fp *fakeProvider) Read(ctx context.Context, path string) (*secretValue, error) {
+	fp.reads++
+	data, ok := fp.values[path]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return &secretValue{data: data, ttl: fp.ttl, renew: fp.renew}, nil
+}
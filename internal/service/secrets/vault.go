@@ -0,0 +1,145 @@
+package secrets
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	remotevault "github.com/grafana/alloy/internal/component/remote/vault"
+	vault "github.com/hashicorp/vault/api"
+)
+
+// VaultArguments configures a HashiCorp Vault secret provider. Secrets are
+// read from the logical path given to sys.secret; KV version 2 secrets are
+// unwrapped automatically.
+type VaultArguments struct {
+	Name      string `alloy:",label"`
+	Server    string `alloy:"server,attr"`
+	Namespace string `alloy:"namespace,attr,optional"`
+
+	ClientOptions remotevault.ClientOptions `alloy:"client_options,block,optional"`
+
+	// The user must provide exactly one auth block.
+	Auth []remotevault.AuthArguments `alloy:"auth,enum,optional"`
+}
+
+// SetToDefault implements syntax.Defaulter.
+func (args *VaultArguments) SetToDefault() {
+	*args = VaultArguments{
+		ClientOptions: remotevault.DefaultArguments.ClientOptions,
+	}
+}
+
+// Validate implements syntax.Validator.
+func (args *VaultArguments) Validate() error {
+	if len(args.Auth) != 1 {
+		return fmt.Errorf("exactly one auth.* block must be specified; found %d", len(args.Auth))
+	}
+	if args.ClientOptions.Timeout == 0 {
+		return fmt.Errorf("client_options.timeout must be greater than 0")
+	}
+	return nil
+}
+
+type vaultProvider struct {
+	args VaultArguments
+	cli  *vault.Client
+
+	authMut     sync.Mutex
+	authExpires time.Time // Zero if the token doesn't expire.
+	authed      bool
+}
+
+func newVaultProvider(args VaultArguments) (*vaultProvider, error) {
+	cfg := vault.DefaultConfig()
+	cfg.Address = args.Server
+	cfg.MinRetryWait = args.ClientOptions.MinRetryWait
+	cfg.MaxRetryWait = args.ClientOptions.MaxRetryWait
+	cfg.MaxRetries = args.ClientOptions.MaxRetries
+	cfg.Timeout = args.ClientOptions.Timeout
+
+	cli, err := vault.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if args.Namespace != "" {
+		cli.SetNamespace(args.Namespace)
+	}
+	return &vaultProvider{args: args, cli: cli}, nil
+}
+
+// authenticate logs in to Vault if the provider hasn't logged in yet, or if
+// its token expired.
+func (vp *vaultProvider) authenticate(ctx context.Context) error {
+	vp.authMut.Lock()
+	defer vp.authMut.Unlock()
+
+	if vp.authed && (vp.authExpires.IsZero() || time.Now().Before(vp.authExpires)) {
+		return nil
+	}
+
+	secret, err := vp.args.Auth[0].Authenticate(ctx, vp.cli)
+	if err != nil {
+		return err
+	}
+
+	vp.authed = true
+	vp.authExpires = time.Time{}
+	if secret != nil && secret.Auth != nil && secret.Auth.LeaseDuration > 0 {
+		// Log in again a bit before the token expires.
+		ttl := time.Duration(secret.Auth.LeaseDuration) * time.Second
+		vp.authExpires = time.Now().Add(ttl * 9 / 10)
+	}
+	return nil
+}
+
+// Read implements provider.
+func (vp *vaultProvider) Read(ctx context.Context, path string) (*secretValue, error) {
+	if err := vp.authenticate(ctx); err != nil {
+		return nil, fmt.Errorf("authenticating: %w", err)
+	}
+
+	secret, err := vp.cli.Logical().ReadWithContext(ctx, path)
+	if err != nil {
+		return nil, err
+	} else if secret == nil {
+		return nil, fmt.Errorf("no secret found at %q", path)
+	}
+
+	data := secret.Data
+	// KV version 2 secrets nest their fields in a data object, next to the
+	// metadata of the secret.
+	if inner, ok := data["data"].(map[string]any); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+
+	value := &secretValue{
+		data: make(map[string]string, len(data)),
+		ttl:  time.Duration(secret.LeaseDuration) * time.Second,
+	}
+	for k, v := range data {
+		switch v := v.(type) {
+		case string:
+			value.data[k] = v
+		case []byte:
+			value.data[k] = string(v)
+		default:
+			value.data[k] = fmt.Sprint(v)
+		}
+	}
+
+	if secret.Renewable && secret.LeaseID != "" {
+		leaseID := secret.LeaseID
+		value.renew = func(ctx context.Context) (time.Duration, error) {
+			renewed, err := vp.cli.Sys().RenewWithContext(ctx, leaseID, 0)
+			if err != nil {
+				return 0, err
+			}
+			return time.Duration(renewed.LeaseDuration) * time.Second, nil
+		}
+	}
+	return value, nil
+}
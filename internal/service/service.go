@@ -9,6 +9,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/grafana/alloy/internal/component"
 	"github.com/grafana/alloy/internal/featuregate"
@@ -73,6 +74,14 @@ type Host interface {
 	NewController(id string) Controller
 }
 
+// GraphWriter is implemented by hosts which can serialize the component graph
+// of their modules.
+type GraphWriter interface {
+	// WriteGraph writes the graph of the module with the given ID, or of the
+	// root module if moduleID is empty, to w in the given format.
+	WriteGraph(w io.Writer, moduleID string, format string) error
+}
+
 // Controller is implemented by alloy.Alloy.
 type Controller interface {
 	Run(ctx context.Context)
@@ -28,6 +28,7 @@ type Options struct {
 	UIPrefix        string                        // Path prefix to host the UI at.
 	CallbackManager livedebugging.CallbackManager // CallbackManager is used for live debugging in the UI.
 	Logger          log.Logger
+	EnforceFIPS     bool // Whether components which aren't FIPS compliant are refused.
 }
 
 // Service implements the UI service.
@@ -81,7 +82,7 @@ func (s *Service) Data() any {
 func (s *Service) ServiceHandler(host service.Host) (base string, handler http.Handler) {
 	r := mux.NewRouter()
 
-	fa := api.NewAlloyAPI(host, s.opts.CallbackManager, s.opts.Logger)
+	fa := api.NewAlloyAPI(host, s.opts.CallbackManager, s.opts.EnforceFIPS, s.opts.Logger)
 	fa.RegisterRoutes(path.Join(s.opts.UIPrefix, "/api/v0/web"), r)
 	ui.RegisterRoutes(s.opts.UIPrefix, r)
 
@@ -0,0 +1,115 @@
+package waltools
+
+import (
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/prometheus/prometheus/tsdb/record"
+	"github.com/prometheus/prometheus/tsdb/wlog"
+)
+
+// SegmentStats are statistics for the latest checkpoint or a segment of the
+// WAL. The segments which aren't truncated yet hold the samples which may not
+// have been sent by remote_write.
+type SegmentStats struct {
+	// Name is the name of the checkpoint directory or of the segment file.
+	Name string
+	// Size is the size on disk, in bytes.
+	Size int64
+	// Series is the number of series records.
+	Series int
+	// Samples is the number of samples. From and To are the zero time when
+	// Samples is zero.
+	Samples int
+	From    time.Time
+	To      time.Time
+}
+
+// FindSegments returns statistics for the latest checkpoint of the WAL, if
+// any, followed by the segments after it.
+func FindSegments(walDir string) ([]SegmentStats, error) {
+	w, err := wlog.Open(nil, walDir)
+	if err != nil {
+		return nil, err
+	}
+	defer w.Close()
+
+	var segments []SegmentStats
+	err = walIterateNamed(w, func(path string, r *wlog.Reader) error {
+		size, err := diskSize(path)
+		if err != nil {
+			return err
+		}
+		stats, err := collectSegmentStats(r)
+		if err != nil {
+			return err
+		}
+		stats.Name = filepath.Base(path)
+		stats.Size = size
+		segments = append(segments, stats)
+		return nil
+	})
+	return segments, err
+}
+
+func collectSegmentStats(r *wlog.Reader) (SegmentStats, error) {
+	var (
+		dec        record.Decoder
+		stats      SegmentStats
+		minT, maxT int64 = math.MaxInt64, math.MinInt64
+	)
+
+	for r.Next() {
+		rec := r.Record()
+
+		switch dec.Type(rec) {
+		case record.Series:
+			series, err := dec.Series(rec, nil)
+			if err != nil {
+				return stats, err
+			}
+			stats.Series += len(series)
+		case record.Samples:
+			samples, err := dec.Samples(rec, nil)
+			if err != nil {
+				return stats, err
+			}
+			for _, s := range samples {
+				minT = min(minT, s.T)
+				maxT = max(maxT, s.T)
+			}
+			stats.Samples += len(samples)
+		}
+	}
+
+	if stats.Samples > 0 {
+		stats.From = timestamp.Time(minT)
+		stats.To = timestamp.Time(maxT)
+	}
+	return stats, r.Err()
+}
+
+// diskSize returns the size of the file at path, or the total size of the
+// files in the directory at path.
+func diskSize(path string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += fi.Size()
+		return nil
+	})
+	if err != nil && !os.IsNotExist(err) {
+		return 0, err
+	}
+	return size, nil
+}
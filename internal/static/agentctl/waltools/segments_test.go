@@ -0,0 +1,36 @@
+package waltools
+
+import (
+	"testing"
+
+	"github.com/prometheus/prometheus/model/timestamp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindSegments(t *testing.T) {
+	walDir := setupTestWAL(t)
+	segments, err := FindSegments(walDir)
+	require.NoError(t, err)
+	require.Len(t, segments, 3)
+
+	// The last segment is empty.
+	for _, s := range segments[:2] {
+		require.Positive(t, s.Size, s.Name)
+	}
+
+	checkpoint := segments[0]
+	require.Equal(t, "checkpoint.00000001", checkpoint.Name)
+	require.Equal(t, 21, checkpoint.Series)
+	require.Zero(t, checkpoint.Samples)
+	require.True(t, checkpoint.From.IsZero())
+
+	samples := segments[1]
+	require.Equal(t, "00000002", samples.Name)
+	require.Zero(t, samples.Series)
+	require.Equal(t, 21, samples.Samples)
+	require.Equal(t, int64(1), timestamp.FromTime(samples.From))
+	require.Equal(t, int64(20), timestamp.FromTime(samples.To))
+
+	require.Equal(t, "00000003", segments[2].Name)
+	require.Zero(t, segments[2].Samples)
+}
@@ -8,6 +8,12 @@ import (
 // walIterate iterates over the latest checkpoint in the provided WAL and all
 // of the segments in the WAL and calls f for each of them.
 func walIterate(w *wlog.WL, f func(r *wlog.Reader) error) error {
+	return walIterateNamed(w, func(_ string, r *wlog.Reader) error { return f(r) })
+}
+
+// walIterateNamed is like walIterate, and also passes the path of the
+// checkpoint directory or segment file to f.
+func walIterateNamed(w *wlog.WL, f func(path string, r *wlog.Reader) error) error {
 	checkpoint, checkpointIdx, err := wlog.LastCheckpoint(w.Dir())
 	if err != nil && err != record.ErrNotFound {
 		return err
@@ -23,7 +29,7 @@ func walIterate(w *wlog.WL, f func(r *wlog.Reader) error) error {
 		if err != nil {
 			return err
 		}
-		err = f(wlog.NewReader(sr))
+		err = f(checkpoint, wlog.NewReader(sr))
 		_ = sr.Close()
 		if err != nil {
 			return err
@@ -33,12 +39,13 @@ func walIterate(w *wlog.WL, f func(r *wlog.Reader) error) error {
 	}
 
 	for i := startIdx; i <= last; i++ {
-		s, err := wlog.OpenReadSegment(wlog.SegmentName(w.Dir(), i))
+		name := wlog.SegmentName(w.Dir(), i)
+		s, err := wlog.OpenReadSegment(name)
 		if err != nil {
 			return err
 		}
 		sr := wlog.NewSegmentBufReader(s)
-		err = f(wlog.NewReader(sr))
+		err = f(name, wlog.NewReader(sr))
 		_ = sr.Close()
 		if err != nil {
 			return err
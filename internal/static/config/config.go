@@ -18,6 +18,7 @@ import (
 	"github.com/grafana/alloy/internal/static/server"
 	"github.com/grafana/alloy/internal/static/traces"
 	"github.com/grafana/alloy/internal/util"
+	alloy_envsubst "github.com/grafana/alloy/internal/util/envsubst"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/yaml.v2"
 )
@@ -238,6 +239,11 @@ func performEnvVarExpansion(buf []byte, expandEnvVars bool) ([]byte, error) {
 	}
 	// (Optionally) expand with environment variables
 	if expandEnvVars {
+		// envsubst treats ${VAR:?message} like a default value, so report the
+		// required variables which aren't set first.
+		if _, diags := alloy_envsubst.Expand("", utf8Buf, os.LookupEnv); diags.HasErrors() {
+			return nil, fmt.Errorf("unable to substitute config with environment variables: %w", diags)
+		}
 		s, err := envsubst.Eval(string(utf8Buf), getenv)
 		if err != nil {
 			return nil, fmt.Errorf("unable to substitute config with environment variables: %w", err)
@@ -393,6 +393,22 @@ logs:
 	require.Equal(t, expected, pipelineStages["expression"].(string))
 }
 
+func TestConfig_ExpandEnvRequired(t *testing.T) {
+	cfg := `
+server:
+  log_level: ${STATIC_TEST_LOG_LEVEL:-info}
+metrics:
+  wal_directory: ${STATIC_TEST_WAL_DIRECTORY:?set it to a writable directory}`
+
+	var c Config
+	err := LoadBytes([]byte(cfg), true, &c)
+	require.EqualError(t, err, "unable to substitute config with environment variables: 5:18: required environment variable STATIC_TEST_WAL_DIRECTORY isn't set or is empty: set it to a writable directory")
+
+	t.Setenv("STATIC_TEST_WAL_DIRECTORY", "/tmp/wal")
+	require.NoError(t, LoadBytes([]byte(cfg), true, &c))
+	require.Equal(t, "/tmp/wal", c.Metrics.WALDir)
+}
+
 func TestConfig_ObscureSecrets(t *testing.T) {
 	cfgText := `
 metrics:
@@ -15,7 +15,9 @@ import (
 )
 
 var DefaultConfig = Config{
-	DirectConnect: true,
+	DirectConnect:  true,
+	CompatibleMode: true,
+	CollectAll:     true,
 }
 
 // Config controls mongodb_exporter
@@ -24,6 +26,23 @@ type Config struct {
 	URI             config_util.Secret `yaml:"mongodb_uri"`
 	DirectConnect   bool               `yaml:"direct_connect,omitempty"`
 	DiscoveringMode bool               `yaml:"discovering_mode,omitempty"`
+
+	// CompatibleMode exposes the metric names of mongodb_exporter <v0.20.0,
+	// which many existing dashboards rely on.
+	CompatibleMode bool `yaml:"compatible_mode"`
+	// CollectAll enables every collector, ignoring the Enable* fields below.
+	CollectAll             bool `yaml:"collect_all"`
+	EnableDiagnosticData   bool `yaml:"enable_diagnostic_data,omitempty"`
+	EnableReplicasetStatus bool `yaml:"enable_replicaset_status,omitempty"`
+	EnableDBStats          bool `yaml:"enable_db_stats,omitempty"`
+	EnableTopMetrics       bool `yaml:"enable_top_metrics,omitempty"`
+	EnableIndexStats       bool `yaml:"enable_index_stats,omitempty"`
+	EnableCollStats        bool `yaml:"enable_coll_stats,omitempty"`
+
+	// Namespaces (<db>.<collection>) to collect the collstats and indexstats
+	// of, instead of discovering every collection.
+	CollStatsNamespaces  []string `yaml:"collstats_namespaces,omitempty"`
+	IndexStatsNamespaces []string `yaml:"indexstats_namespaces,omitempty"`
 }
 
 // UnmarshalYAML implements yaml.Unmarshaler for Config
@@ -66,14 +85,20 @@ func New(logger log.Logger, c *Config) (integrations.Integration, error) {
 		Logger:                 logrusLogger,
 		DisableDefaultRegistry: true,
 
-		// NOTE(rfratto): CompatibleMode configures the exporter to use old metric
-		// names from mongodb_exporter <v0.20.0. Many existing dashboards rely on
-		// the old names, so we hard-code it to true now. We may wish to make this
-		// configurable in the future.
-		CompatibleMode:  true,
-		CollectAll:      true,
+		CompatibleMode:  c.CompatibleMode,
 		DirectConnect:   c.DirectConnect,
 		DiscoveringMode: c.DiscoveringMode,
+
+		CollectAll:             c.CollectAll,
+		EnableDiagnosticData:   c.EnableDiagnosticData,
+		EnableReplicasetStatus: c.EnableReplicasetStatus,
+		EnableDBStats:          c.EnableDBStats,
+		EnableTopMetrics:       c.EnableTopMetrics,
+		EnableIndexStats:       c.EnableIndexStats,
+		EnableCollStats:        c.EnableCollStats,
+
+		CollStatsNamespaces:   c.CollStatsNamespaces,
+		IndexStatsCollections: c.IndexStatsNamespaces,
 	})
 
 	return integrations.NewHandlerIntegration(c.Name(), exp.Handler()), nil
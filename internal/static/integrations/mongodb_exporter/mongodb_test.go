@@ -3,6 +3,9 @@ package mongodb_exporter
 import (
 	"testing"
 
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v2"
+
 	"github.com/grafana/alloy/internal/static/config"
 )
 
@@ -17,3 +20,29 @@ integrations:
 `
 	config.CheckSecret(t, stringCfg, "secret_password_in_uri")
 }
+
+func TestConfig_UnmarshalYAML(t *testing.T) {
+	var cfg Config
+	require.NoError(t, yaml.Unmarshal([]byte("mongodb_uri: mongodb://127.0.0.1:27017"), &cfg))
+	require.True(t, cfg.CompatibleMode)
+	require.True(t, cfg.CollectAll)
+
+	cfg = Config{}
+	require.NoError(t, yaml.Unmarshal([]byte(`
+mongodb_uri: mongodb://127.0.0.1:27017
+compatible_mode: false
+collect_all: false
+enable_db_stats: true
+enable_coll_stats: true
+collstats_namespaces: [shop.orders]
+indexstats_namespaces: [shop.orders, shop.users]
+`), &cfg))
+	require.Equal(t, Config{
+		URI:                  "mongodb://127.0.0.1:27017",
+		DirectConnect:        true,
+		EnableDBStats:        true,
+		EnableCollStats:      true,
+		CollStatsNamespaces:  []string{"shop.orders"},
+		IndexStatsNamespaces: []string{"shop.orders", "shop.users"},
+	}, cfg)
+}
@@ -0,0 +1,186 @@
+// Package envsubst expands references to environment variables in
+// configuration files before they're parsed.
+//
+// The following forms are supported, where NAME is a letter or underscore
+// followed by letters, digits, or underscores:
+//
+//	${NAME}          The value of NAME, or an empty string if NAME isn't set.
+//	${NAME:-default} default if NAME isn't set or is empty.
+//	${NAME-default}  default if NAME isn't set.
+//	${NAME:?message} An error with message if NAME isn't set or is empty.
+//	${NAME?message}  An error with message if NAME isn't set.
+//
+// Defaults and messages can reference other variables. Write $${ to produce
+// a literal ${. References in any other form, like ${1} in relabeling
+// replacements, are left untouched.
+package envsubst
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/grafana/alloy/syntax/diag"
+	"github.com/grafana/alloy/syntax/token"
+)
+
+// LookupFunc returns the value of an environment variable and whether it is
+// set, like os.LookupEnv.
+type LookupFunc func(name string) (string, bool)
+
+// Expand expands the references to environment variables in src, the
+// contents of the file filename. The returned diagnostics point at the
+// references to required variables which aren't set.
+//
+// Values are inserted verbatim: values containing quotes or newlines change
+// the meaning or the line numbers of the rest of the file.
+func Expand(filename string, src []byte, lookup LookupFunc) ([]byte, diag.Diagnostics) {
+	e := &expander{filename: filename, src: src, lookup: lookup}
+	return e.expand(0, len(src)), e.diags
+}
+
+type expander struct {
+	filename string
+	src      []byte
+	lookup   LookupFunc
+	diags    diag.Diagnostics
+}
+
+// expand expands the references in src[start:end].
+func (e *expander) expand(start, end int) []byte {
+	var out []byte
+	for i := start; i < end; {
+		j := bytes.IndexByte(e.src[i:end], '$')
+		if j < 0 {
+			out = append(out, e.src[i:end]...)
+			break
+		}
+		j += i
+		out = append(out, e.src[i:j]...)
+
+		switch {
+		case bytes.HasPrefix(e.src[j:end], []byte("$${")):
+			out = append(out, "${"...)
+			i = j + 3
+		case bytes.HasPrefix(e.src[j:end], []byte("${")):
+			closing := e.closingBrace(j+2, end)
+			if closing < 0 {
+				// Unterminated references are left untouched.
+				return append(out, e.src[j:end]...)
+			}
+			if val, ok := e.reference(j, closing); ok {
+				out = append(out, val...)
+			} else {
+				out = append(out, e.src[j:closing+1]...)
+			}
+			i = closing + 1
+		default:
+			out = append(out, '$')
+			i = j + 1
+		}
+	}
+	return out
+}
+
+// closingBrace returns the index of the brace closing the reference whose
+// content starts at start, or -1 if the reference isn't closed before end.
+func (e *expander) closingBrace(start, end int) int {
+	depth := 0
+	for i := start; i < end; i++ {
+		switch {
+		case e.src[i] == '$' && i+1 < end && e.src[i+1] == '{':
+			depth++
+			i++
+		case e.src[i] == '}' && depth == 0:
+			return i
+		case e.src[i] == '}':
+			depth--
+		}
+	}
+	return -1
+}
+
+// reference evaluates the reference spanning src[dollar:closing+1]. ok is
+// false if the reference isn't in a supported form.
+func (e *expander) reference(dollar, closing int) (val []byte, ok bool) {
+	nameStart := dollar + 2
+	nameEnd := nameStart
+	for nameEnd < closing && isNameChar(e.src[nameEnd], nameEnd == nameStart) {
+		nameEnd++
+	}
+	if nameEnd == nameStart {
+		return nil, false
+	}
+	name := string(e.src[nameStart:nameEnd])
+
+	var op string
+	for _, candidate := range []string{":-", ":?", "-", "?"} {
+		if bytes.HasPrefix(e.src[nameEnd:closing], []byte(candidate)) {
+			op = candidate
+			break
+		}
+	}
+	if op == "" && nameEnd != closing {
+		return nil, false
+	}
+	wordStart := nameEnd + len(op)
+
+	value, set := e.lookup(name)
+	missing := !set || (value == "" && len(op) == 2)
+
+	switch op {
+	case ":-", "-":
+		if missing {
+			return e.expand(wordStart, closing), true
+		}
+	case ":?", "?":
+		if missing {
+			e.requiredMissing(name, op, dollar, closing, e.expand(wordStart, closing))
+			return nil, true
+		}
+	}
+	return []byte(value), true
+}
+
+func (e *expander) requiredMissing(name, op string, dollar, closing int, message []byte) {
+	state := "isn't set"
+	if op == ":?" {
+		state = "isn't set or is empty"
+	}
+	msg := fmt.Sprintf("required environment variable %s %s", name, state)
+	if len(message) > 0 {
+		msg += ": " + string(message)
+	}
+
+	e.diags.Add(diag.Diagnostic{
+		Severity: diag.SeverityLevelError,
+		StartPos: e.position(dollar),
+		EndPos:   e.position(closing),
+		Message:  msg,
+	})
+}
+
+func (e *expander) position(offset int) token.Position {
+	line, lineStart := 1, 0
+	for i, b := range e.src[:offset] {
+		if b == '\n' {
+			line++
+			lineStart = i + 1
+		}
+	}
+	return token.Position{
+		Filename: e.filename,
+		Offset:   offset,
+		Line:     line,
+		Column:   offset - lineStart + 1,
+	}
+}
+
+func isNameChar(b byte, first bool) bool {
+	switch {
+	case b == '_', 'a' <= b && b <= 'z', 'A' <= b && b <= 'Z':
+		return true
+	case '0' <= b && b <= '9':
+		return !first
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package envsubst
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testEnv = map[string]string{
+	"HOST":  "localhost",
+	"PORT":  "9090",
+	"EMPTY": "",
+}
+
+func testLookup(name string) (string, bool) {
+	v, ok := testEnv[name]
+	return v, ok
+}
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		name, in, expect string
+	}{
+		{"no references", `url = "http://localhost"`, `url = "http://localhost"`},
+		{"value", `url = "http://${HOST}:${PORT}"`, `url = "http://localhost:9090"`},
+		{"unset", `x = "${UNSET}"`, `x = ""`},
+		{"default of unset", `x = "${UNSET:-a}"`, `x = "a"`},
+		{"default of empty", `x = "${EMPTY:-a}"`, `x = "a"`},
+		{"default of set", `x = "${HOST:-a}"`, `x = "localhost"`},
+		{"unset-only default of empty", `x = "${EMPTY-a}"`, `x = ""`},
+		{"unset-only default of unset", `x = "${UNSET-a}"`, `x = "a"`},
+		{"nested default", `x = "${UNSET:-${HOST}:${PORT}}"`, `x = "localhost:9090"`},
+		{"required set", `x = "${HOST:?must be set}"`, `x = "localhost"`},
+		{"required empty without colon", `x = "${EMPTY?must be set}"`, `x = ""`},
+		{"escape", `x = "$${HOST}"`, `x = "${HOST}"`},
+		{"numeric capture group", `replacement = "${1}"`, `replacement = "${1}"`},
+		{"unsupported form", `x = "${HOST^^}"`, `x = "${HOST^^}"`},
+		{"unterminated", `x = "${HOST`, `x = "${HOST`},
+		{"lone dollar", `regex = "^a$"`, `regex = "^a$"`},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			out, diags := Expand("config.alloy", []byte(tc.in), testLookup)
+			require.Empty(t, diags)
+			require.Equal(t, tc.expect, string(out))
+		})
+	}
+}
+
+func TestExpand_Required(t *testing.T) {
+	in := `logging {
+	level = "${LEVEL:-info}"
+}
+
+prometheus.remote_write "default" {
+	endpoint {
+		url = "${URL:?set it to the URL of the database}"
+	}
+	external_labels = { cluster = "${EMPTY:?}", region = "${UNSET?}" }
+}
+`
+	out, diags := Expand("config.alloy", []byte(in), testLookup)
+	require.Len(t, diags, 3)
+	require.Equal(t, `config.alloy:7:10: required environment variable URL isn't set or is empty: set it to the URL of the database`, diags[0].Error())
+	require.Equal(t, 7, diags[0].EndPos.Line)
+	require.Equal(t, 50, diags[0].EndPos.Column)
+	require.Equal(t, `config.alloy:9:33: required environment variable EMPTY isn't set or is empty`, diags[1].Error())
+	require.Equal(t, `config.alloy:9:56: required environment variable UNSET isn't set`, diags[2].Error())
+
+	require.Contains(t, string(out), `url = ""`)
+	require.Contains(t, string(out), `level = "info"`)
+}
@@ -18,22 +18,32 @@ type MetricSample struct {
 	Labels    labels.Labels
 }
 
+type HistogramSample struct {
+	Timestamp      int64
+	Histogram      *histogram.Histogram
+	FloatHistogram *histogram.FloatHistogram
+	Labels         labels.Labels
+}
+
 // CollectingAppender is an Appender that collects the samples it receives in a map. Useful for testing and verifying
 // the samples that are being written.
 type CollectingAppender interface {
 	storage.Appender
 	CollectedSamples() map[string]*MetricSample
 	LatestSampleFor(labels string) *MetricSample
+	LatestHistogramFor(labels string) *HistogramSample
 }
 
 type collectingAppender struct {
-	mut           sync.Mutex
-	latestSamples map[string]*MetricSample
+	mut              sync.Mutex
+	latestSamples    map[string]*MetricSample
+	latestHistograms map[string]*HistogramSample
 }
 
 func NewCollectingAppender() CollectingAppender {
 	return &collectingAppender{
-		latestSamples: map[string]*MetricSample{},
+		latestSamples:    map[string]*MetricSample{},
+		latestHistograms: map[string]*HistogramSample{},
 	}
 }
 
@@ -51,6 +61,12 @@ func (c *collectingAppender) LatestSampleFor(labels string) *MetricSample {
 	return c.latestSamples[labels]
 }
 
+func (c *collectingAppender) LatestHistogramFor(labels string) *HistogramSample {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return c.latestHistograms[labels]
+}
+
 func (c *collectingAppender) Append(ref storage.SeriesRef, l labels.Labels, t int64, v float64) (storage.SeriesRef, error) {
 	c.mut.Lock()
 	defer c.mut.Unlock()
@@ -75,7 +91,15 @@ func (c *collectingAppender) AppendExemplar(ref storage.SeriesRef, l labels.Labe
 }
 
 func (c *collectingAppender) AppendHistogram(ref storage.SeriesRef, l labels.Labels, t int64, h *histogram.Histogram, fh *histogram.FloatHistogram) (storage.SeriesRef, error) {
-	panic("not implemented yet")
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.latestHistograms[l.String()] = &HistogramSample{
+		Timestamp:      t,
+		Histogram:      h,
+		FloatHistogram: fh,
+		Labels:         l,
+	}
+	return ref, nil
 }
 
 func (c *collectingAppender) UpdateMetadata(ref storage.SeriesRef, l labels.Labels, m metadata.Metadata) (storage.SeriesRef, error) {
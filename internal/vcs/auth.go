@@ -2,16 +2,21 @@ package vcs
 
 import (
 	"fmt"
+	"net"
+	"os"
 
 	"github.com/go-git/go-git/v5/plumbing/transport"
 	"github.com/go-git/go-git/v5/plumbing/transport/http"
 	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/grafana/alloy/syntax"
 	"github.com/grafana/alloy/syntax/alloytypes"
+	"golang.org/x/crypto/ssh/agent"
 )
 
 type GitAuthConfig struct {
 	BasicAuth *BasicAuth `alloy:"basic_auth,block,optional"`
 	SSHKey    *SSHKey    `alloy:"ssh_key,block,optional"`
+	SSHAgent  *SSHAgent  `alloy:"ssh_agent,block,optional"`
 }
 
 // Convert converts HTTPClientConfig to the native Prometheus type. If h is
@@ -27,6 +32,10 @@ func (h *GitAuthConfig) Convert() (transport.AuthMethod, error) {
 	if h.SSHKey != nil {
 		return h.SSHKey.Convert()
 	}
+
+	if h.SSHAgent != nil {
+		return h.SSHAgent.Convert()
+	}
 	return nil, nil
 }
 
@@ -77,3 +86,41 @@ func (s *SSHKey) Convert() (transport.AuthMethod, error) {
 
 	return nil, nil
 }
+
+// SSHAgent authenticates with the keys of a running SSH agent.
+type SSHAgent struct {
+	Username   string `alloy:"username,attr,optional"`
+	SocketPath string `alloy:"socket_path,attr,optional"`
+}
+
+var _ syntax.Defaulter = (*SSHAgent)(nil)
+
+// SetToDefault implements syntax.Defaulter.
+func (s *SSHAgent) SetToDefault() {
+	*s = SSHAgent{Username: "git"}
+}
+
+// Convert converts our type to the native go-git type. The socket of the
+// agent defaults to the one of the SSH_AUTH_SOCK environment variable.
+func (s *SSHAgent) Convert() (transport.AuthMethod, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	socketPath := s.SocketPath
+	if socketPath == "" {
+		socketPath = os.Getenv("SSH_AUTH_SOCK")
+	}
+	if socketPath == "" {
+		return nil, fmt.Errorf("connecting to the SSH agent failed: socket_path isn't set and SSH_AUTH_SOCK is empty")
+	}
+
+	conn, err := net.Dial("unix", socketPath)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to the SSH agent failed: %w", err)
+	}
+	return &ssh.PublicKeysCallback{
+		User:     s.Username,
+		Callback: agent.NewClient(conn).Signers,
+	}, nil
+}
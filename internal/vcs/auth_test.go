@@ -0,0 +1,66 @@
+package vcs_test
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh/agent"
+
+	"github.com/grafana/alloy/internal/vcs"
+	"github.com/grafana/alloy/syntax"
+)
+
+func TestSSHAgent(t *testing.T) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	keyring := agent.NewKeyring()
+	require.NoError(t, keyring.Add(agent.AddedKey{PrivateKey: key}))
+
+	socketPath := filepath.Join(t.TempDir(), "agent.sock")
+	l, err := net.Listen("unix", socketPath)
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go agent.ServeAgent(keyring, conn)
+		}
+	}()
+
+	var cfg vcs.GitAuthConfig
+	require.NoError(t, syntax.Unmarshal([]byte(`ssh_agent {}`), &cfg))
+	require.Equal(t, &vcs.SSHAgent{Username: "git"}, cfg.SSHAgent)
+
+	t.Run("SSH_AUTH_SOCK", func(t *testing.T) {
+		t.Setenv("SSH_AUTH_SOCK", socketPath)
+		auth, err := cfg.Convert()
+		require.NoError(t, err)
+
+		callback := auth.(*ssh.PublicKeysCallback)
+		require.Equal(t, "git", callback.User)
+		signers, err := callback.Callback()
+		require.NoError(t, err)
+		require.Len(t, signers, 1)
+	})
+
+	t.Run("socket_path", func(t *testing.T) {
+		t.Setenv("SSH_AUTH_SOCK", "")
+		auth, err := (&vcs.SSHAgent{Username: "deploy", SocketPath: socketPath}).Convert()
+		require.NoError(t, err)
+		require.Equal(t, "deploy", auth.(*ssh.PublicKeysCallback).User)
+	})
+
+	t.Run("no socket", func(t *testing.T) {
+		t.Setenv("SSH_AUTH_SOCK", "")
+		_, err := cfg.Convert()
+		require.EqualError(t, err, "connecting to the SSH agent failed: socket_path isn't set and SSH_AUTH_SOCK is empty")
+	})
+}
@@ -45,3 +45,20 @@ type InvalidRevisionError struct {
 func (err InvalidRevisionError) Error() string {
 	return fmt.Sprintf("invalid revision \"%s\"", err.Revision)
 }
+
+// SignatureVerificationError represents a revision whose signature couldn't
+// be verified.
+type SignatureVerificationError struct {
+	Revision string
+	Object   string // Commit or tag whose signature was verified.
+	Inner    error
+}
+
+// Error returns the error string, denoting the revision and the verified
+// object.
+func (err SignatureVerificationError) Error() string {
+	return fmt.Sprintf("verifying the signature of revision %q (%s): %s", err.Revision, err.Object, err.Inner)
+}
+
+// Unwrap returns the inner error.
+func (err SignatureVerificationError) Unwrap() error { return err.Inner }
@@ -3,10 +3,14 @@ package vcs
 import (
 	"context"
 	"errors"
+	"fmt"
 	"io"
 	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
@@ -17,6 +21,17 @@ type GitRepoOptions struct {
 	Repository string
 	Revision   string
 	Auth       GitAuthConfig
+
+	// Submodules initializes and updates the submodules of the repository
+	// after each checkout.
+	Submodules bool
+	// SparsePaths, if not empty, limits the checkout to the files and
+	// directories starting with one of the paths. Objects are still fetched
+	// for the whole repository.
+	SparsePaths []string
+	// Verify, if set, requires the signature of Revision to be valid before
+	// checking it out.
+	Verify *GitVerifyConfig
 }
 
 // GitRepo manages a Git repository for the purposes of retrieving a file from
@@ -26,6 +41,7 @@ type GitRepo struct {
 	repo     *git.Repository
 	workTree *git.Worktree
 	auth     transport.AuthMethod
+	verifier *signatureVerifier
 }
 
 // NewGitRepo creates a new instance of a GitRepo, where the Git repository is
@@ -33,7 +49,8 @@ type GitRepo struct {
 //
 // 1. If storagePath is empty on disk, NewGitRepo initializes GitRepo by cloning the repository.
 // 2. After GitRepo is initialized/opened, a git fetch is don.
-// 3. Then, a git checkout is done to the Revision specified in GitRepoOptions.
+// 3. Then, a git checkout is done to the Revision specified in GitRepoOptions,
+// followed by an update of the submodules if enabled.
 func NewGitRepo(ctx context.Context, storagePath string, opts GitRepoOptions) (*GitRepo, error) {
 	var (
 		repo *git.Repository
@@ -48,13 +65,22 @@ func NewGitRepo(ctx context.Context, storagePath string, opts GitRepoOptions) (*
 		}
 	}
 
+	var verifier *signatureVerifier
+	if opts.Verify != nil {
+		if verifier, err = opts.Verify.verifier(); err != nil {
+			return nil, err
+		}
+	}
+
 	if !isRepoCloned(storagePath) {
+		// The revision is checked out by Update, which also updates the
+		// submodules, so the clone doesn't check out HEAD.
 		repo, err = git.PlainCloneContext(ctx, storagePath, false, &git.CloneOptions{
-			URL:               opts.Repository,
-			ReferenceName:     plumbing.HEAD,
-			Auth:              authConfig,
-			RecurseSubmodules: git.DefaultSubmoduleRecursionDepth,
-			Tags:              git.AllTags,
+			URL:           opts.Repository,
+			ReferenceName: plumbing.HEAD,
+			Auth:          authConfig,
+			NoCheckout:    true,
+			Tags:          git.AllTags,
 		})
 	} else {
 		repo, err = git.PlainOpen(storagePath)
@@ -80,6 +106,7 @@ func NewGitRepo(ctx context.Context, storagePath string, opts GitRepoOptions) (*
 		repo:     repo,
 		workTree: wt,
 		auth:     authConfig,
+		verifier: verifier,
 	}
 
 	err = gitRepo.Update(ctx)
@@ -110,7 +137,18 @@ func (repo *GitRepo) Update(ctx context.Context) error {
 		}
 	}
 
-	checkoutErr := checkout(repo.opts.Revision, repo.repo)
+	// The revision isn't checked out if its signature is invalid, so the
+	// previous revision is kept.
+	if repo.verifier != nil {
+		err := verifyRevision(repo.opts.Revision, repo.repo, repo.verifier)
+		if errors.Is(err, plumbing.ErrReferenceNotFound) {
+			return InvalidRevisionError{repo.opts.Revision}
+		} else if err != nil {
+			return err
+		}
+	}
+
+	checkoutErr := checkout(repo.opts.Revision, repo.repo, repo.sparsePaths())
 	if checkoutErr != nil {
 		if errors.Is(checkoutErr, plumbing.ErrReferenceNotFound) {
 			return InvalidRevisionError{repo.opts.Revision}
@@ -122,6 +160,64 @@ func (repo *GitRepo) Update(ctx context.Context) error {
 		}
 	}
 
+	if repo.opts.Submodules {
+		if err := repo.updateSubmodules(ctx); err != nil {
+			return UpdateFailedError{
+				Repository: repo.opts.Repository,
+				Inner:      err,
+			}
+		}
+	}
+
+	return nil
+}
+
+// sparsePaths returns the paths to check out, or nil to check out the whole
+// repository.
+func (repo *GitRepo) sparsePaths() []string {
+	if len(repo.opts.SparsePaths) == 0 {
+		return nil
+	}
+	paths := make([]string, 0, len(repo.opts.SparsePaths)+1)
+	for _, p := range repo.opts.SparsePaths {
+		p = path.Clean(filepath.ToSlash(p))
+		if p == "." || p == "/" {
+			// The whole repository is checked out.
+			return nil
+		}
+		paths = append(paths, strings.TrimPrefix(p, "/"))
+	}
+	if repo.opts.Submodules {
+		// Submodules are listed in .gitmodules at the root of the repository.
+		paths = append(paths, ".gitmodules")
+	}
+	return paths
+}
+
+// updateSubmodules initializes and updates the submodules in the checked out
+// paths.
+func (repo *GitRepo) updateSubmodules(ctx context.Context) error {
+	submodules, err := repo.workTree.Submodules()
+	if err != nil {
+		return err
+	}
+
+	sparsePaths := repo.sparsePaths()
+	for _, sm := range submodules {
+		if len(sparsePaths) > 0 && !slices.ContainsFunc(sparsePaths, func(p string) bool {
+			return strings.HasPrefix(sm.Config().Path, p)
+		}) {
+			continue
+		}
+		err := sm.UpdateContext(ctx, &git.SubmoduleUpdateOptions{
+			Init:              true,
+			RecurseSubmodules: git.DefaultSubmoduleRecursionDepth,
+			Auth:              repo.auth,
+		})
+		if err != nil {
+			return fmt.Errorf("updating submodule %q: %w", sm.Config().Name, err)
+		}
+	}
 	return nil
 }
 
@@ -167,7 +263,9 @@ func (repo *GitRepo) CurrentRevision() (string, error) {
 // Tags are checked out as branches
 // Branches as branches
 // Commits are commits
-func checkout(rev string, repo *git.Repository) error {
+//
+// Only the files starting with one of sparsePaths are checked out, if any.
+func checkout(rev string, repo *git.Repository, sparsePaths []string) error {
 	// Try looking for the revision in the following order:
 	//
 	// 1. Search by tag name.
@@ -180,22 +278,25 @@ func checkout(rev string, repo *git.Repository) error {
 
 	if tagRef, err := repo.Tag(rev); err == nil {
 		return wt.Checkout(&git.CheckoutOptions{
-			Branch: tagRef.Name(),
-			Force:  true,
+			Branch:                    tagRef.Name(),
+			Force:                     true,
+			SparseCheckoutDirectories: sparsePaths,
 		})
 	}
 
 	if remoteRef, err := repo.Reference(plumbing.NewRemoteReferenceName("origin", rev), true); err == nil {
 		return wt.Checkout(&git.CheckoutOptions{
-			Branch: remoteRef.Name(),
-			Force:  true,
+			Branch:                    remoteRef.Name(),
+			Force:                     true,
+			SparseCheckoutDirectories: sparsePaths,
 		})
 	}
 
 	if hash, err := repo.ResolveRevision(plumbing.Revision(rev)); err == nil {
 		return wt.Checkout(&git.CheckoutOptions{
-			Hash:  *hash,
-			Force: true,
+			Hash:                      *hash,
+			Force:                     true,
+			SparseCheckoutDirectories: sparsePaths,
 		})
 	}
 
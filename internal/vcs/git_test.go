@@ -2,11 +2,13 @@ package vcs_test
 
 import (
 	"fmt"
+	"os"
 	"testing"
 
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
 	"github.com/grafana/alloy/internal/vcs"
 	"github.com/stretchr/testify/require"
 )
@@ -74,6 +76,65 @@ func Test_NonFastForward(t *testing.T) {
 	repo.validate(tracker, msg)
 }
 
+func Test_SparseCheckout(t *testing.T) {
+	branchName := "main"
+	origRepo, repoDirectory := initRepository(t, branchName)
+	require.NoError(t, origRepo.WriteFile("modules/module.alloy", []byte("module")))
+	origRepo.commit()
+
+	newRepo, err := vcs.NewGitRepo(t.Context(), t.TempDir(), vcs.GitRepoOptions{
+		Repository:  repoDirectory,
+		Revision:    branchName,
+		SparsePaths: []string{"./modules"},
+	})
+	require.NoError(t, err)
+
+	bb, err := newRepo.ReadFile("modules/module.alloy")
+	require.NoError(t, err)
+	require.Equal(t, "module", string(bb))
+	_, err = newRepo.ReadFile("a.txt")
+	require.ErrorIs(t, err, os.ErrNotExist, "files outside of the sparse paths aren't checked out")
+
+	require.NoError(t, origRepo.WriteFile("modules/module.alloy", []byte("updated")))
+	origRepo.commit()
+	require.NoError(t, newRepo.Update(t.Context()))
+
+	bb, err = newRepo.ReadFile("modules/module.alloy")
+	require.NoError(t, err)
+	require.Equal(t, "updated", string(bb))
+	_, err = newRepo.ReadFile("a.txt")
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func Test_Submodules(t *testing.T) {
+	subRepo, subDirectory := initRepository(t, "main")
+	subMsg := subRepo.commit()
+	subHead, err := subRepo.repo.Head()
+	require.NoError(t, err)
+
+	origRepo, repoDirectory := initRepository(t, "main")
+	origRepo.addSubmodule("lib", subDirectory, subHead.Hash())
+
+	for _, submodules := range []bool{false, true} {
+		t.Run(fmt.Sprintf("submodules=%t", submodules), func(t *testing.T) {
+			newRepo, err := vcs.NewGitRepo(t.Context(), t.TempDir(), vcs.GitRepoOptions{
+				Repository: repoDirectory,
+				Revision:   "main",
+				Submodules: submodules,
+			})
+			require.NoError(t, err)
+
+			bb, err := newRepo.ReadFile("lib/a.txt")
+			if !submodules {
+				require.ErrorIs(t, err, os.ErrNotExist)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, subMsg, string(bb))
+		})
+	}
+}
+
 type testRepository struct {
 	t           *testing.T
 	repo        *git.Repository
@@ -167,6 +228,11 @@ func (r *testRepository) checkout(branchName string) {
 
 // Write a unique message on a file into the repository and commit it.
 func (r *testRepository) commit() string {
+	return r.commitWithOptions(&git.CommitOptions{})
+}
+
+// commitWithOptions is like commit, with the given commit options.
+func (r *testRepository) commitWithOptions(opts *git.CommitOptions) string {
 	r.commitCount += 1
 	msg := fmt.Sprintf("commit %d", r.commitCount)
 
@@ -176,12 +242,31 @@ func (r *testRepository) commit() string {
 	_, err = r.worktree.Add(".")
 	require.NoError(r.t, err)
 
-	_, err = r.worktree.Commit(msg, &git.CommitOptions{})
+	_, err = r.worktree.Commit(msg, opts)
 	require.NoError(r.t, err)
 
 	return msg
 }
 
+// addSubmodule commits a submodule at path, pointing at the commit hash of
+// the repository at url.
+func (r *testRepository) addSubmodule(path, url string, hash plumbing.Hash) {
+	gitmodules := fmt.Sprintf("[submodule %q]\n\tpath = %s\n\turl = %s\n", path, path, url)
+	require.NoError(r.t, r.WriteFile(".gitmodules", []byte(gitmodules)))
+	_, err := r.worktree.Add(".gitmodules")
+	require.NoError(r.t, err)
+
+	idx, err := r.repo.Storer.Index()
+	require.NoError(r.t, err)
+	e := idx.Add(path)
+	e.Hash = hash
+	e.Mode = filemode.Submodule
+	require.NoError(r.t, r.repo.Storer.SetIndex(idx))
+
+	_, err = r.worktree.Commit("add submodule", &git.CommitOptions{})
+	require.NoError(r.t, err)
+}
+
 func (r *testRepository) validate(tracker *vcs.GitRepo, expectedMsg string) {
 	bb, err := tracker.ReadFile(r.filename)
 	require.NoError(r.t, err)
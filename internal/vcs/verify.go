@@ -0,0 +1,233 @@
+package vcs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/crypto/ssh"
+)
+
+// GitVerifyConfig configures the verification of the signature of the
+// revision checked out from a Git repository.
+type GitVerifyConfig struct {
+	GPGKeys []string `alloy:"gpg_keys,attr,optional"`
+	SSHKeys []string `alloy:"ssh_keys,attr,optional"`
+}
+
+// Validate implements syntax.Validator.
+func (c *GitVerifyConfig) Validate() error {
+	if len(c.GPGKeys) == 0 && len(c.SSHKeys) == 0 {
+		return fmt.Errorf("at least one of gpg_keys and ssh_keys must be set")
+	}
+	_, err := c.verifier()
+	return err
+}
+
+// signatureVerifier verifies signatures against trusted keys.
+type signatureVerifier struct {
+	gpgKeys openpgp.EntityList
+	sshKeys [][]byte // Keys in wire format.
+}
+
+func (c *GitVerifyConfig) verifier() (*signatureVerifier, error) {
+	var v signatureVerifier
+	for i, key := range c.GPGKeys {
+		entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(key))
+		if err != nil {
+			return nil, fmt.Errorf("parsing gpg_keys[%d]: %w", i, err)
+		}
+		v.gpgKeys = append(v.gpgKeys, entities...)
+	}
+	for i, key := range c.SSHKeys {
+		pub, _, _, _, err := ssh.ParseAuthorizedKey([]byte(key))
+		if err != nil {
+			return nil, fmt.Errorf("parsing ssh_keys[%d]: %w", i, err)
+		}
+		v.sshKeys = append(v.sshKeys, pub.Marshal())
+	}
+	return &v, nil
+}
+
+// verify checks that signature is a valid signature of message by one of the
+// trusted keys.
+func (v *signatureVerifier) verify(message []byte, signature string) error {
+	switch {
+	case signature == "":
+		return errors.New("not signed")
+	case strings.HasPrefix(signature, "-----BEGIN PGP"):
+		if len(v.gpgKeys) == 0 {
+			return errors.New("signed with a GPG key, but no GPG keys are trusted")
+		}
+		_, err := openpgp.CheckArmoredDetachedSignature(v.gpgKeys, bytes.NewReader(message), strings.NewReader(signature), nil)
+		return err
+	case strings.HasPrefix(signature, "-----BEGIN SSH SIGNATURE"):
+		if len(v.sshKeys) == 0 {
+			return errors.New("signed with an SSH key, but no SSH keys are trusted")
+		}
+		return v.verifySSH(message, signature)
+	default:
+		return errors.New("unsupported signature format")
+	}
+}
+
+// sshSignatureNamespace is the namespace of the SSH signatures made by Git.
+const sshSignatureNamespace = "git"
+
+// sshSignature is an SSH signature, as described in
+// https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.sshsig.
+type sshSignature struct {
+	MagicPreamble [6]byte
+	Version       uint32
+	PublicKey     []byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Signature     []byte
+}
+
+// sshSignedData is the data signed by an SSH signature.
+type sshSignedData struct {
+	MagicPreamble [6]byte
+	Namespace     string
+	Reserved      string
+	HashAlgorithm string
+	Hash          []byte
+}
+
+var sshSignaturePreamble = [6]byte{'S', 'S', 'H', 'S', 'I', 'G'}
+
+func (v *signatureVerifier) verifySSH(message []byte, armored string) error {
+	block, _ := pem.Decode([]byte(armored))
+	if block == nil || block.Type != "SSH SIGNATURE" {
+		return errors.New("invalid SSH signature")
+	}
+	var sig sshSignature
+	if err := ssh.Unmarshal(block.Bytes, &sig); err != nil {
+		return fmt.Errorf("invalid SSH signature: %w", err)
+	}
+	if sig.MagicPreamble != sshSignaturePreamble || sig.Version != 1 {
+		return errors.New("invalid SSH signature")
+	}
+	if sig.Namespace != sshSignatureNamespace {
+		return fmt.Errorf("SSH signature has namespace %q instead of %q", sig.Namespace, sshSignatureNamespace)
+	}
+
+	var h hash.Hash
+	switch sig.HashAlgorithm {
+	case "sha256":
+		h = sha256.New()
+	case "sha512":
+		h = sha512.New()
+	default:
+		return fmt.Errorf("unsupported SSH signature hash algorithm %q", sig.HashAlgorithm)
+	}
+	h.Write(message)
+
+	trusted := false
+	for _, key := range v.sshKeys {
+		if bytes.Equal(key, sig.PublicKey) {
+			trusted = true
+			break
+		}
+	}
+	if !trusted {
+		return errors.New("signed with an SSH key which isn't trusted")
+	}
+
+	pub, err := ssh.ParsePublicKey(sig.PublicKey)
+	if err != nil {
+		return fmt.Errorf("invalid SSH signature: %w", err)
+	}
+	var s ssh.Signature
+	if err := ssh.Unmarshal(sig.Signature, &s); err != nil {
+		return fmt.Errorf("invalid SSH signature: %w", err)
+	}
+	signed := ssh.Marshal(sshSignedData{
+		MagicPreamble: sshSignaturePreamble,
+		Namespace:     sig.Namespace,
+		Reserved:      sig.Reserved,
+		HashAlgorithm: sig.HashAlgorithm,
+		Hash:          h.Sum(nil),
+	})
+	return pub.Verify(signed, &s)
+}
+
+// verifyRevision verifies the signature of the revision rev of repo, resolved
+// like checkout does. Annotated tags are verified if they're signed, and the
+// commit they point at otherwise.
+func verifyRevision(rev string, repo *git.Repository, v *signatureVerifier) error {
+	commit, err := resolveCommit(rev, repo, v)
+	if err != nil || commit == nil {
+		return err
+	}
+	message, err := encodedWithoutSignature(commit)
+	if err != nil {
+		return err
+	}
+	if err := v.verify(message, commit.PGPSignature); err != nil {
+		return SignatureVerificationError{Revision: rev, Object: "commit " + commit.Hash.String(), Inner: err}
+	}
+	return nil
+}
+
+// resolveCommit returns the commit of rev, or nil if rev is a signed tag
+// which is verified.
+func resolveCommit(rev string, repo *git.Repository, v *signatureVerifier) (*object.Commit, error) {
+	if tagRef, err := repo.Tag(rev); err == nil {
+		tag, err := repo.TagObject(tagRef.Hash())
+		if errors.Is(err, plumbing.ErrObjectNotFound) {
+			// Lightweight tags point at the commit directly.
+			return repo.CommitObject(tagRef.Hash())
+		} else if err != nil {
+			return nil, err
+		}
+		if tag.PGPSignature == "" {
+			return tag.Commit()
+		}
+		message, err := encodedWithoutSignature(tag)
+		if err != nil {
+			return nil, err
+		}
+		if err := v.verify(message, tag.PGPSignature); err != nil {
+			return nil, SignatureVerificationError{Revision: rev, Object: "tag " + tag.Name, Inner: err}
+		}
+		return nil, nil
+	}
+
+	if remoteRef, err := repo.Reference(plumbing.NewRemoteReferenceName("origin", rev), true); err == nil {
+		return repo.CommitObject(remoteRef.Hash())
+	}
+
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		return nil, plumbing.ErrReferenceNotFound
+	}
+	return repo.CommitObject(*hash)
+}
+
+// encodedWithoutSignature returns the payload of the signature of o.
+func encodedWithoutSignature(o interface {
+	EncodeWithoutSignature(plumbing.EncodedObject) error
+}) ([]byte, error) {
+	encoded := &plumbing.MemoryObject{}
+	if err := o.EncodeWithoutSignature(encoded); err != nil {
+		return nil, err
+	}
+	r, err := encoded.Reader()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
@@ -0,0 +1,152 @@
+package vcs_test
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/pem"
+	"io"
+	"testing"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+
+	"github.com/grafana/alloy/internal/vcs"
+)
+
+func TestVerifySignature(t *testing.T) {
+	gpgKey, err := openpgp.NewEntity("Go test", "", "go-test@example.com", nil)
+	require.NoError(t, err)
+	sshKey := newSSHSigner(t)
+	untrustedKey := newSSHSigner(t)
+
+	verify := &vcs.GitVerifyConfig{
+		GPGKeys: []string{armoredPublicKey(t, gpgKey)},
+		SSHKeys: []string{string(ssh.MarshalAuthorizedKey(sshKey.signer.PublicKey()))},
+	}
+
+	tests := []struct {
+		name      string
+		signer    git.Signer
+		expectErr string
+	}{
+		{name: "GPG signature", signer: gpgSigner{gpgKey}},
+		{name: "SSH signature", signer: sshKey},
+		{name: "unsigned", expectErr: "not signed"},
+		{name: "untrusted SSH key", signer: untrustedKey, expectErr: "signed with an SSH key which isn't trusted"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			origRepo, repoDirectory := initRepository(t, "main")
+			msg := origRepo.commitWithOptions(&git.CommitOptions{Signer: sshKey})
+
+			repo, err := vcs.NewGitRepo(t.Context(), t.TempDir(), vcs.GitRepoOptions{
+				Repository: repoDirectory,
+				Revision:   "main",
+				Verify:     verify,
+			})
+			require.NoError(t, err)
+
+			// The new commit is only checked out if its signature is valid.
+			newMsg := origRepo.commitWithOptions(&git.CommitOptions{Signer: tc.signer})
+			err = repo.Update(t.Context())
+			if tc.expectErr != "" {
+				require.ErrorAs(t, err, &vcs.SignatureVerificationError{})
+				require.ErrorContains(t, err, tc.expectErr)
+				origRepo.validate(repo, msg)
+				return
+			}
+			require.NoError(t, err)
+			origRepo.validate(repo, newMsg)
+		})
+	}
+
+	t.Run("signed tag", func(t *testing.T) {
+		origRepo, repoDirectory := initRepository(t, "main")
+		msg := origRepo.commit()
+		head, err := origRepo.repo.Head()
+		require.NoError(t, err)
+		_, err = origRepo.repo.CreateTag("v1.0.0", head.Hash(), &git.CreateTagOptions{Message: "v1.0.0", SignKey: gpgKey})
+		require.NoError(t, err)
+		_, err = origRepo.repo.CreateTag("v1.0.1", head.Hash(), &git.CreateTagOptions{Message: "v1.0.1"})
+		require.NoError(t, err)
+
+		repo, err := vcs.NewGitRepo(t.Context(), t.TempDir(), vcs.GitRepoOptions{
+			Repository: repoDirectory,
+			Revision:   "v1.0.0",
+			Verify:     verify,
+		})
+		require.NoError(t, err, "the tag is signed, even if the commit isn't")
+		origRepo.validate(repo, msg)
+
+		_, err = vcs.NewGitRepo(t.Context(), t.TempDir(), vcs.GitRepoOptions{
+			Repository: repoDirectory,
+			Revision:   "v1.0.1",
+			Verify:     verify,
+		})
+		require.EqualError(t, err, `verifying the signature of revision "v1.0.1" (commit `+head.Hash().String()+`): not signed`)
+	})
+}
+
+func TestGitVerifyConfig_Validate(t *testing.T) {
+	require.EqualError(t, (&vcs.GitVerifyConfig{}).Validate(), "at least one of gpg_keys and ssh_keys must be set")
+	require.ErrorContains(t, (&vcs.GitVerifyConfig{SSHKeys: []string{"invalid"}}).Validate(), "parsing ssh_keys[0]")
+	require.ErrorContains(t, (&vcs.GitVerifyConfig{GPGKeys: []string{"invalid"}}).Validate(), "parsing gpg_keys[0]")
+}
+
+func armoredPublicKey(t *testing.T, e *openpgp.Entity) string {
+	var buf bytes.Buffer
+	w, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, e.Serialize(w))
+	require.NoError(t, w.Close())
+	return buf.String()
+}
+
+type gpgSigner struct{ key *openpgp.Entity }
+
+func (s gpgSigner) Sign(message io.Reader) ([]byte, error) {
+	var buf bytes.Buffer
+	err := openpgp.ArmoredDetachSign(&buf, s.key, message, nil)
+	return buf.Bytes(), err
+}
+
+// sshSigner signs messages like ssh-keygen -Y sign -n git.
+type sshSigner struct{ signer ssh.Signer }
+
+func newSSHSigner(t *testing.T) sshSigner {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+	return sshSigner{signer}
+}
+
+func (s sshSigner) Sign(message io.Reader) ([]byte, error) {
+	h := sha512.New()
+	if _, err := io.Copy(h, message); err != nil {
+		return nil, err
+	}
+	preamble := [6]byte{'S', 'S', 'H', 'S', 'I', 'G'}
+	signed := ssh.Marshal(struct {
+		Preamble                       [6]byte
+		Namespace, Reserved, Algorithm string
+		Hash                           []byte
+	}{preamble, "git", "", "sha512", h.Sum(nil)})
+	sig, err := s.signer.Sign(rand.Reader, signed)
+	if err != nil {
+		return nil, err
+	}
+	blob := ssh.Marshal(struct {
+		Preamble                       [6]byte
+		Version                        uint32
+		PublicKey                      []byte
+		Namespace, Reserved, Algorithm string
+		Signature                      []byte
+	}{preamble, 1, s.signer.PublicKey().Marshal(), "git", "", "sha512", ssh.Marshal(sig)})
+	return pem.EncodeToMemory(&pem.Block{Type: "SSH SIGNATURE", Bytes: blob}), nil
+}
@@ -5,13 +5,17 @@
 package api
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand"
 	"net/http"
 	"path"
 	"strconv"
 	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-kit/log"
@@ -30,12 +34,14 @@ import (
 type AlloyAPI struct {
 	alloy           service.Host
 	CallbackManager livedebugging.CallbackManager
+	enforceFIPS     bool
 	logger          log.Logger
 }
 
-// NewAlloyAPI instantiates a new Alloy API.
-func NewAlloyAPI(alloy service.Host, CallbackManager livedebugging.CallbackManager, l log.Logger) *AlloyAPI {
-	return &AlloyAPI{alloy: alloy, CallbackManager: CallbackManager, logger: l}
+// NewAlloyAPI instantiates a new Alloy API. enforceFIPS reports whether
+// components which aren't FIPS compliant are refused.
+func NewAlloyAPI(alloy service.Host, CallbackManager livedebugging.CallbackManager, enforceFIPS bool, l log.Logger) *AlloyAPI {
+	return &AlloyAPI{alloy: alloy, CallbackManager: CallbackManager, enforceFIPS: enforceFIPS, logger: l}
 }
 
 // RegisterRoutes registers all the API's routes.
@@ -54,7 +60,11 @@ func (a *AlloyAPI) RegisterRoutes(urlPrefix string, r *mux.Router) {
 	r.Handle(path.Join(urlPrefix, "/remotecfg/components/{id:.+}"), httputil.CompressionHandler{Handler: getComponentHandlerRemoteCfg(a.alloy)})
 
 	r.Handle(path.Join(urlPrefix, "/peers"), httputil.CompressionHandler{Handler: getClusteringPeersHandler(a.alloy)})
+	r.Handle(path.Join(urlPrefix, "/remote_write"), httputil.CompressionHandler{Handler: getRemoteWriteHandler(a.alloy)})
+	r.Handle(path.Join(urlPrefix, "/exports"), httputil.CompressionHandler{Handler: getExportsHandler(a.alloy)})
+	r.Handle(path.Join(urlPrefix, "/fips"), httputil.CompressionHandler{Handler: getFIPSHandler(a.alloy, a.enforceFIPS)})
 	r.Handle(path.Join(urlPrefix, "/debug/{id:.+}"), liveDebugging(a.alloy, a.CallbackManager, a.logger))
+	r.Handle(path.Join(urlPrefix, "/record/{id:.+}"), liveDebuggingRecord(a.alloy, a.CallbackManager, a.logger))
 
 	r.Handle(path.Join(urlPrefix, "/graph"), graph(a.alloy, a.CallbackManager, a.logger))
 	r.Handle(path.Join(urlPrefix, "/graph/{moduleID:.+}"), graph(a.alloy, a.CallbackManager, a.logger))
@@ -98,13 +108,22 @@ func listComponentsHandlerInternal(host service.Host, w http.ResponseWriter, r *
 		moduleID = vars["moduleID"]
 	}
 
-	components, err := host.ListComponents(moduleID, component.InfoOptions{
-		GetHealth: true,
-	})
+	filter := parseComponentFilter(r.URL.Query())
+
+	var (
+		components []*component.Info
+		err        error
+	)
+	if filter.recursive {
+		components, err = listComponentsRecursive(host, moduleID, filter.infoOptions())
+	} else {
+		components, err = host.ListComponents(moduleID, filter.infoOptions())
+	}
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
+	components = filter.apply(components)
 
 	bb, err := json.Marshal(components)
 	if err != nil {
@@ -114,6 +133,28 @@ func listComponentsHandlerInternal(host service.Host, w http.ResponseWriter, r *
 	_, _ = w.Write(bb)
 }
 
+// listComponentsRecursive lists the components of moduleID and of all the
+// modules it contains.
+func listComponentsRecursive(host service.Host, moduleID string, opts component.InfoOptions) ([]*component.Info, error) {
+	components, err := host.ListComponents(moduleID, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	res := components
+	for _, info := range components {
+		for _, module := range info.ModuleIDs {
+			moduleComponents, err := listComponentsRecursive(host, module, opts)
+			if err != nil {
+				// The module may have gone away since it was listed.
+				continue
+			}
+			res = append(res, moduleComponents...)
+		}
+	}
+	return res, nil
+}
+
 func getComponentHandler(host service.Host) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		getComponentHandlerInternal(host, w, r)
@@ -136,10 +177,12 @@ func getComponentHandlerInternal(host service.Host, w http.ResponseWriter, r *ht
 	requestedComponent := component.ParseID(vars["id"])
 
 	component, err := host.GetComponent(requestedComponent, component.InfoOptions{
-		GetHealth:    true,
-		GetArguments: true,
-		GetExports:   true,
-		GetDebugInfo: true,
+		GetHealth:        true,
+		GetHealthHistory: true,
+		GetArguments:     true,
+		GetExports:       true,
+		GetDebugInfo:     true,
+		GetEvaluation:    true,
 	})
 	if err != nil {
 		http.NotFound(w, r)
@@ -155,6 +198,14 @@ func getComponentHandlerInternal(host service.Host, w http.ResponseWriter, r *ht
 }
 
 func getClusteringPeersHandler(host service.Host) http.HandlerFunc {
+	type peerJSON struct {
+		Name  string `json:"name"`
+		Addr  string `json:"addr"`
+		Self  bool   `json:"isSelf"`
+		State string `json:"state"`
+		Zone  string `json:"zone,omitempty"`
+	}
+
 	return func(w http.ResponseWriter, _ *http.Request) {
 		// TODO(@tpaschalis) Detect if clustering is disabled and propagate to
 		// the Typescript code (eg. via the returned status code?).
@@ -163,8 +214,24 @@ func getClusteringPeersHandler(host service.Host) http.HandlerFunc {
 			http.Error(w, "cluster service not running", http.StatusInternalServerError)
 			return
 		}
-		peers := svc.Data().(cluster.Cluster).Peers()
-		bb, err := json.Marshal(peers)
+		c := svc.Data().(cluster.Cluster)
+		zc, _ := c.(cluster.ZoneCluster)
+
+		peers := c.Peers()
+		res := make([]peerJSON, 0, len(peers))
+		for _, p := range peers {
+			pj := peerJSON{
+				Name:  p.Name,
+				Addr:  p.Addr,
+				Self:  p.Self,
+				State: p.State.String(),
+			}
+			if zc != nil {
+				pj.Zone = zc.PeerZone(p)
+			}
+			res = append(res, pj)
+		}
+		bb, err := json.Marshal(res)
 		if err != nil {
 			http.Error(w, err.Error(), http.StatusInternalServerError)
 			return
@@ -173,6 +240,77 @@ func getClusteringPeersHandler(host service.Host) http.HandlerFunc {
 	}
 }
 
+// getRemoteWriteHandler returns the debug info of every
+// prometheus.remote_write component, including the ones running in modules.
+func getRemoteWriteHandler(host service.Host) http.HandlerFunc {
+	type remoteWriteJSON struct {
+		ModuleID  string      `json:"moduleID"`
+		LocalID   string      `json:"localID"`
+		Health    string      `json:"health"`
+		DebugInfo interface{} `json:"debugInfo"`
+	}
+
+	return func(w http.ResponseWriter, _ *http.Request) {
+		res := []remoteWriteJSON{}
+		for _, info := range component.GetAllComponents(host, component.InfoOptions{}) {
+			if info.ComponentName != "prometheus.remote_write" {
+				continue
+			}
+
+			// Debug info is only retrieved for the matching components, as it
+			// may be expensive to compute.
+			info, err := host.GetComponent(info.ID, component.InfoOptions{GetHealth: true, GetDebugInfo: true})
+			if err != nil {
+				// The component may have been removed in the meantime.
+				continue
+			}
+			res = append(res, remoteWriteJSON{
+				ModuleID:  info.ID.ModuleID,
+				LocalID:   info.ID.LocalID,
+				Health:    info.Health.Health.String(),
+				DebugInfo: info.DebugInfo,
+			})
+		}
+
+		bb, err := json.Marshal(res)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write(bb)
+	}
+}
+
+// writeGraph writes a snapshot of the component graph of moduleID in the given
+// format, "dot" or "json", instead of streaming the live debugging data of the
+// graph.
+func writeGraph(host component.Provider, moduleID string, format string, w http.ResponseWriter) {
+	gw, ok := host.(service.GraphWriter)
+	if !ok {
+		http.Error(w, "graph export isn't supported", http.StatusNotImplemented)
+		return
+	}
+
+	var contentType string
+	switch format {
+	case "dot":
+		contentType = "text/vnd.graphviz; charset=utf-8"
+	case "json":
+		contentType = "application/json"
+	default:
+		http.Error(w, fmt.Sprintf("unsupported graph format %q, must be dot or json", format), http.StatusBadRequest)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := gw.WriteGraph(&buf, moduleID, format); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write(buf.Bytes())
+}
+
 type dataKey struct {
 	ComponentID livedebugging.ComponentID
 	Type        livedebugging.DataType
@@ -191,6 +329,11 @@ func graph(h service.Host, callbackManager livedebugging.CallbackManager, logger
 			return
 		}
 
+		if format := r.URL.Query().Get("format"); format != "" {
+			writeGraph(host, string(moduleID), format, w)
+			return
+		}
+
 		window := setWindow(w, r.URL.Query().Get("window"))
 
 		dataCh := make(chan livedebugging.Data, 1000)
@@ -351,6 +494,101 @@ func liveDebugging(h service.Host, callbackManager livedebugging.CallbackManager
 	}
 }
 
+// liveDebuggingRecord records the live debugging stream of a component and
+// sends it back as a downloadable file. The recording stops once it reaches
+// the requested duration or size, or when the client disconnects.
+func liveDebuggingRecord(h service.Host, callbackManager livedebugging.CallbackManager, logger log.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		componentID := livedebugging.ComponentID(vars["id"])
+
+		host, err := resolveServiceHost(h, string(componentID))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		duration, err := parseRecordDuration(r.URL.Query().Get("duration"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		maxBytes, err := parseRecordMaxBytes(r.URL.Query().Get("maxBytes"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming isn't supported by the connection", http.StatusInternalServerError)
+			return
+		}
+
+		dataCh := make(chan livedebugging.Data, 1000)
+		ctx, cancel := context.WithTimeout(r.Context(), duration)
+		defer cancel()
+
+		id := livedebugging.CallbackID(uuid.New().String())
+
+		// The callback is called concurrently by the components.
+		var droppedData atomic.Int64
+		err = callbackManager.AddCallback(host, id, componentID, func(data livedebugging.Data) {
+			select {
+			case <-ctx.Done():
+				return
+			default:
+				select {
+				case dataCh <- data:
+				default:
+					if droppedData.Add(1) == 1 {
+						level.Warn(logger).Log("msg", "data throughput is very high, not all debugging data can be recorded")
+					}
+				}
+			}
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		defer func() {
+			callbackManager.DeleteCallback(id, componentID)
+			if dropped := droppedData.Load(); dropped > 0 {
+				level.Warn(logger).Log("msg", "live debugging recording is incomplete", "component", componentID, "dropped_entries", dropped)
+			}
+		}()
+
+		filename := fmt.Sprintf("%s-%s.ndjson", strings.ReplaceAll(string(componentID), "/", "_"), time.Now().UTC().Format("20060102T150405Z"))
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+		w.WriteHeader(http.StatusOK)
+
+		recorder := livedebugging.NewRecorder(w, livedebugging.RecordingOptions{
+			MaxBytes:    maxBytes,
+			MaxDuration: duration,
+		})
+
+		flushTicker := time.NewTicker(time.Second)
+		defer flushTicker.Stop()
+
+		for {
+			select {
+			case data := <-dataCh:
+				if err := recorder.Record(data); err != nil {
+					if !errors.Is(err, livedebugging.ErrRecordingLimitReached) {
+						level.Warn(logger).Log("msg", "error writing live debugging recording", "error", err)
+					}
+					return
+				}
+			case <-flushTicker.C:
+				flusher.Flush()
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
 func resolveServiceHost(host service.Host, id string) (service.Host, error) {
 	if strings.HasPrefix(id, "remotecfg/") {
 		remoteCfgHost, err := getRemoteCfgHost(host)
@@ -375,6 +613,39 @@ func setSampleProb(w http.ResponseWriter, sampleProbParam string) (sampleProb fl
 	return sampleProb
 }
 
+// duration is expected to be in seconds, between 1 and 600.
+func parseRecordDuration(durationParam string) (time.Duration, error) {
+	const defaultDuration = time.Minute
+
+	if durationParam == "" {
+		return defaultDuration, nil
+	}
+
+	duration, err := strconv.Atoi(durationParam)
+	if err != nil || duration < 1 || duration > 600 {
+		return 0, fmt.Errorf("invalid duration: must be an integer between 1 and 600")
+	}
+	return time.Duration(duration) * time.Second, nil
+}
+
+// maxBytes is expected to be between 1 and 100MiB.
+func parseRecordMaxBytes(maxBytesParam string) (int64, error) {
+	const (
+		defaultMaxBytes = 10 * 1024 * 1024
+		limitMaxBytes   = 100 * 1024 * 1024
+	)
+
+	if maxBytesParam == "" {
+		return defaultMaxBytes, nil
+	}
+
+	maxBytes, err := strconv.ParseInt(maxBytesParam, 10, 64)
+	if err != nil || maxBytes < 1 || maxBytes > limitMaxBytes {
+		return 0, fmt.Errorf("invalid maxBytes: must be an integer between 1 and %d", limitMaxBytes)
+	}
+	return maxBytes, nil
+}
+
 // window is expected to be in seconds, between 1 and 60.
 func setWindow(w http.ResponseWriter, windowParam string) time.Duration {
 	const defaultWindow = 5 * time.Second
@@ -0,0 +1,64 @@
+package api
+
+import (
+	"cmp"
+	"encoding/json"
+	"net/http"
+	"slices"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/syntax/encoding/alloyjson"
+)
+
+// getExportsHandler returns the current exports of the components of every
+// module as JSON. Components are sorted by module and ID, and their exports
+// are encoded with alloyjson so that secrets are redacted, which makes
+// responses for the same exports identical.
+//
+// The type, module and q query parameters filter the components like for the
+// list components routes. Components without exports are omitted.
+func getExportsHandler(host component.Provider) http.HandlerFunc {
+	type exportsJSON struct {
+		ModuleID string          `json:"moduleID"`
+		LocalID  string          `json:"localID"`
+		Name     string          `json:"name"`
+		Exports  json.RawMessage `json:"exports"`
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		filter := parseComponentFilter(r.URL.Query())
+		opts := filter.infoOptions()
+		opts.GetExports = true
+
+		infos := component.GetAllComponents(host, opts)
+		slices.SortFunc(infos, func(a, b *component.Info) int {
+			return cmp.Or(cmp.Compare(a.ID.ModuleID, b.ID.ModuleID), cmp.Compare(a.ID.LocalID, b.ID.LocalID))
+		})
+
+		res := []exportsJSON{}
+		for _, info := range infos {
+			if info.Exports == nil || !filter.match(info) {
+				continue
+			}
+			exports, err := alloyjson.MarshalBody(info.Exports)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusInternalServerError)
+				return
+			}
+			res = append(res, exportsJSON{
+				ModuleID: info.ID.ModuleID,
+				LocalID:  info.ID.LocalID,
+				Name:     info.ComponentName,
+				Exports:  exports,
+			})
+		}
+
+		bb, err := json.Marshal(res)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(bb)
+	}
+}
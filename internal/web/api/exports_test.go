@@ -0,0 +1,99 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/syntax/alloytypes"
+	"github.com/stretchr/testify/require"
+)
+
+type testExports struct {
+	Targets []map[string]string `alloy:"targets,attr"`
+	Token   alloytypes.Secret   `alloy:"token,attr"`
+}
+
+func TestExportsHandler(t *testing.T) {
+	host := fakeProvider{components: map[string][]*component.Info{
+		"": {
+			{
+				ID:            component.ID{LocalID: "module.file.nested"},
+				ComponentName: "module.file",
+				ModuleIDs:     []string{"module.file.nested"},
+			},
+			{
+				ID:            component.ID{LocalID: "discovery.relabel.pods"},
+				ComponentName: "discovery.relabel",
+				Exports:       testExports{Targets: []map[string]string{{"__address__": "10.0.0.1:80"}}, Token: "hunter2"},
+			},
+		},
+		"module.file.nested": {
+			{
+				ID:            component.ID{ModuleID: "module.file.nested", LocalID: "discovery.kubernetes.pods"},
+				ComponentName: "discovery.kubernetes",
+				Exports:       testExports{Targets: []map[string]string{}},
+			},
+		},
+	}}
+
+	tests := []struct {
+		name   string
+		query  string
+		expect string
+	}{
+		{
+			name:  "all components",
+			query: "",
+			expect: `[
+				{
+					"moduleID": "",
+					"localID": "discovery.relabel.pods",
+					"name": "discovery.relabel",
+					"exports": [
+						{"name": "targets", "type": "attr", "value": {"type": "array", "value": [{"type": "object", "value": [{"key": "__address__", "value": {"type": "string", "value": "10.0.0.1:80"}}]}]}},
+						{"name": "token", "type": "attr", "value": {"type": "capsule", "value": "(secret)"}}
+					]
+				},
+				{
+					"moduleID": "module.file.nested",
+					"localID": "discovery.kubernetes.pods",
+					"name": "discovery.kubernetes",
+					"exports": [
+						{"name": "targets", "type": "attr", "value": {"type": "array", "value": []}},
+						{"name": "token", "type": "attr", "value": {"type": "capsule", "value": "(secret)"}}
+					]
+				}
+			]`,
+		},
+		{
+			name:  "filtered by type",
+			query: "?type=discovery.kubernetes",
+			expect: `[
+				{
+					"moduleID": "module.file.nested",
+					"localID": "discovery.kubernetes.pods",
+					"name": "discovery.kubernetes",
+					"exports": [
+						{"name": "targets", "type": "attr", "value": {"type": "array", "value": []}},
+						{"name": "token", "type": "attr", "value": {"type": "capsule", "value": "(secret)"}}
+					]
+				}
+			]`,
+		},
+		{
+			name:   "no match",
+			query:  "?type=loki.",
+			expect: `[]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			getExportsHandler(host).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/exports"+tt.query, nil))
+			require.Equal(t, http.StatusOK, rec.Code)
+			require.JSONEq(t, tt.expect, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"cmp"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/syntax"
+)
+
+// componentFilter filters the components returned by the list components
+// routes. It's built from the following query parameters:
+//
+//   - q: free text which must be found, case-insensitively, in the ID, name,
+//     label, arguments or exports of the component.
+//   - health: comma-separated list of health states to keep.
+//   - type: comma-separated list of component names, or name prefixes ending
+//     with a dot, like "prometheus.".
+//   - module: only keep components of this module and the modules it
+//     contains. Requires recursive.
+//   - recursive: when true, components of all the modules are listed.
+//   - sort: when "duration", components are sorted by the duration of their
+//     last evaluation, slowest first.
+type componentFilter struct {
+	query          string
+	health         []string
+	types          []string
+	module         string
+	recursive      bool
+	sortByDuration bool
+}
+
+func parseComponentFilter(values url.Values) componentFilter {
+	return componentFilter{
+		query:     strings.ToLower(strings.TrimSpace(values.Get("q"))),
+		health:    splitList(values.Get("health")),
+		types:     splitList(values.Get("type")),
+		module:    values.Get("module"),
+		recursive: values.Get("recursive") == "true",
+
+		sortByDuration: values.Get("sort") == "duration",
+	}
+}
+
+// infoOptions returns the options used to retrieve the components matched by
+// f. Arguments and exports are only retrieved when searching for text.
+func (f componentFilter) infoOptions() component.InfoOptions {
+	return component.InfoOptions{
+		GetHealth:     true,
+		GetArguments:  f.query != "",
+		GetExports:    f.query != "",
+		GetEvaluation: true,
+	}
+}
+
+// apply returns the components matched by f. The arguments and exports of
+// the returned components are cleared to keep the response small.
+func (f componentFilter) apply(infos []*component.Info) []*component.Info {
+	res := make([]*component.Info, 0, len(infos))
+	for _, info := range infos {
+		if !f.match(info) {
+			continue
+		}
+		info.Arguments, info.Exports = nil, nil
+		res = append(res, info)
+	}
+	if f.sortByDuration {
+		slices.SortStableFunc(res, func(a, b *component.Info) int {
+			return -cmp.Compare(lastEvaluationDuration(a), lastEvaluationDuration(b))
+		})
+	}
+	return res
+}
+
+func lastEvaluationDuration(info *component.Info) time.Duration {
+	if info.Evaluation == nil {
+		return 0
+	}
+	return info.Evaluation.LastDuration
+}
+
+func (f componentFilter) match(info *component.Info) bool {
+	if f.module != "" && info.ID.ModuleID != f.module && !strings.HasPrefix(info.ID.ModuleID, f.module+"/") {
+		return false
+	}
+
+	if len(f.health) > 0 && !containsFold(f.health, info.Health.Health.String()) {
+		return false
+	}
+
+	if len(f.types) > 0 {
+		var found bool
+		for _, t := range f.types {
+			if info.ComponentName == t || (strings.HasSuffix(t, ".") && strings.HasPrefix(info.ComponentName, t)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if f.query == "" {
+		return true
+	}
+	for _, s := range []string{info.ID.String(), info.ComponentName, info.Label} {
+		if strings.Contains(strings.ToLower(s), f.query) {
+			return true
+		}
+	}
+	return containsText(info.Arguments, f.query) || containsText(info.Exports, f.query)
+}
+
+// containsText returns true if the Alloy syntax representation of v contains
+// the lowercase text query. Secrets are never matched, as they're represented
+// as (secret).
+func containsText(v interface{}, query string) bool {
+	if v == nil {
+		return false
+	}
+	b, err := syntax.Marshal(v)
+	if err != nil {
+		return false
+	}
+	return strings.Contains(strings.ToLower(string(b)), query)
+}
+
+func containsFold(list []string, s string) bool {
+	for _, elem := range list {
+		if strings.EqualFold(elem, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func splitList(s string) []string {
+	var res []string
+	for _, elem := range strings.Split(s, ",") {
+		if elem = strings.TrimSpace(elem); elem != "" {
+			res = append(res, elem)
+		}
+	}
+	return res
+}
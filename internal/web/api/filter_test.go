@@ -0,0 +1,90 @@
+package api
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/grafana/alloy/syntax/alloytypes"
+	"github.com/stretchr/testify/require"
+)
+
+type testArguments struct {
+	URL      string            `alloy:"url,attr"`
+	Password alloytypes.Secret `alloy:"password,attr"`
+}
+
+func TestComponentFilter(t *testing.T) {
+	infos := []*component.Info{
+		{
+			ID:            component.ID{LocalID: "prometheus.remote_write.default"},
+			ComponentName: "prometheus.remote_write",
+			Label:         "default",
+			Health:        component.Health{Health: component.HealthTypeHealthy},
+			Arguments:     testArguments{URL: "https://prometheus.example.com", Password: "hunter2"},
+			Evaluation:    &component.EvaluationInfo{LastDuration: 10 * time.Millisecond},
+		},
+		{
+			ID:            component.ID{LocalID: "prometheus.scrape.pods"},
+			ComponentName: "prometheus.scrape",
+			Label:         "pods",
+			Health:        component.Health{Health: component.HealthTypeUnhealthy},
+			Evaluation:    &component.EvaluationInfo{LastDuration: 50 * time.Millisecond},
+		},
+		{
+			ID:            component.ID{ModuleID: "import.file.lib", LocalID: "loki.process.logs"},
+			ComponentName: "loki.process",
+			Label:         "logs",
+			Health:        component.Health{Health: component.HealthTypeHealthy},
+		},
+		{
+			ID:            component.ID{ModuleID: "import.file.lib/custom.default", LocalID: "loki.write.default"},
+			ComponentName: "loki.write",
+			Label:         "default",
+			Health:        component.Health{Health: component.HealthTypeExited},
+			Evaluation:    &component.EvaluationInfo{LastDuration: 20 * time.Millisecond},
+		},
+	}
+
+	tests := []struct {
+		query  string
+		expect []string
+	}{
+		{query: "", expect: []string{"prometheus.remote_write.default", "prometheus.scrape.pods", "import.file.lib/loki.process.logs", "import.file.lib/custom.default/loki.write.default"}},
+		{query: "q=PODS", expect: []string{"prometheus.scrape.pods"}},
+		{query: "q=example.com", expect: []string{"prometheus.remote_write.default"}},
+		{query: "q=hunter2", expect: []string{}},
+		{query: "health=unhealthy,exited", expect: []string{"prometheus.scrape.pods", "import.file.lib/custom.default/loki.write.default"}},
+		{query: "type=prometheus.scrape", expect: []string{"prometheus.scrape.pods"}},
+		{query: "type=loki.", expect: []string{"import.file.lib/loki.process.logs", "import.file.lib/custom.default/loki.write.default"}},
+		{query: "type=loki", expect: []string{}},
+		{query: "module=import.file.lib", expect: []string{"import.file.lib/loki.process.logs", "import.file.lib/custom.default/loki.write.default"}},
+		{query: "module=import.file.lib/custom.default&health=exited", expect: []string{"import.file.lib/custom.default/loki.write.default"}},
+		{query: "module=import.file", expect: []string{}},
+		{query: "sort=duration", expect: []string{"prometheus.scrape.pods", "import.file.lib/custom.default/loki.write.default", "prometheus.remote_write.default", "import.file.lib/loki.process.logs"}},
+		{query: "health=healthy&sort=duration", expect: []string{"prometheus.remote_write.default", "import.file.lib/loki.process.logs"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.query, func(t *testing.T) {
+			values, err := url.ParseQuery(tc.query)
+			require.NoError(t, err)
+
+			ids := []string{}
+			for _, info := range parseComponentFilter(values).apply(copyInfos(infos)) {
+				ids = append(ids, info.ID.String())
+			}
+			require.Equal(t, tc.expect, ids)
+		})
+	}
+}
+
+func copyInfos(infos []*component.Info) []*component.Info {
+	res := make([]*component.Info, 0, len(infos))
+	for _, info := range infos {
+		cp := *info
+		res = append(res, &cp)
+	}
+	return res
+}
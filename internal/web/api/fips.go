@@ -0,0 +1,68 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/grafana/alloy/internal/boringcrypto"
+	"github.com/grafana/alloy/internal/component"
+)
+
+// getFIPSHandler reports whether Alloy runs with a FIPS validated
+// cryptographic module and which components are configured with settings
+// that aren't approved by FIPS 140-3.
+func getFIPSHandler(host component.Provider, enforced bool) http.HandlerFunc {
+	type violationJSON struct {
+		ModuleID string   `json:"moduleID"`
+		LocalID  string   `json:"localID"`
+		Name     string   `json:"name"`
+		Errors   []string `json:"errors"`
+	}
+	type fipsJSON struct {
+		BoringCrypto bool            `json:"boringcrypto"`
+		Enforced     bool            `json:"enforced"`
+		Compliant    bool            `json:"compliant"`
+		Violations   []violationJSON `json:"violations"`
+	}
+
+	return func(w http.ResponseWriter, _ *http.Request) {
+		res := fipsJSON{
+			BoringCrypto: boringcrypto.Enabled,
+			Enforced:     enforced,
+			Violations:   []violationJSON{},
+		}
+		for _, info := range component.GetAllComponents(host, component.InfoOptions{}) {
+			if info.FIPSViolations == nil {
+				continue
+			}
+			res.Violations = append(res.Violations, violationJSON{
+				ModuleID: info.ID.ModuleID,
+				LocalID:  info.ID.LocalID,
+				Name:     info.ComponentName,
+				Errors:   flattenErrors(info.FIPSViolations),
+			})
+		}
+		res.Compliant = len(res.Violations) == 0
+
+		bb, err := json.Marshal(res)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write(bb)
+	}
+}
+
+// flattenErrors returns the messages of the errors joined in err.
+func flattenErrors(err error) []string {
+	joined, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return []string{err.Error()}
+	}
+
+	var res []string
+	for _, err := range joined.Unwrap() {
+		res = append(res, flattenErrors(err)...)
+	}
+	return res
+}
@@ -0,0 +1,76 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/alloy/internal/boringcrypto"
+	"github.com/grafana/alloy/internal/component"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeProvider struct {
+	components map[string][]*component.Info
+}
+
+func (p fakeProvider) GetComponent(id component.ID, _ component.InfoOptions) (*component.Info, error) {
+	for _, info := range p.components[id.ModuleID] {
+		if info.ID == id {
+			return info, nil
+		}
+	}
+	return nil, component.ErrComponentNotFound
+}
+
+func (p fakeProvider) ListComponents(moduleID string, _ component.InfoOptions) ([]*component.Info, error) {
+	infos, ok := p.components[moduleID]
+	if !ok {
+		return nil, component.ErrModuleNotFound
+	}
+	return infos, nil
+}
+
+func TestFIPSHandler(t *testing.T) {
+	host := fakeProvider{components: map[string][]*component.Info{
+		"": {
+			{
+				ID:            component.ID{LocalID: "prometheus.scrape.default"},
+				ComponentName: "prometheus.scrape",
+			},
+			{
+				ID:            component.ID{LocalID: "otelcol.exporter.otlp.default"},
+				ComponentName: "otelcol.exporter.otlp",
+				FIPSViolations: errors.Join(
+					errors.New("TLS version TLS 1.1 is not FIPS approved, the minimum version is TLS 1.2"),
+					errors.Join(errors.New("cipher suite TLS_RSA_WITH_RC4_128_SHA is not FIPS approved")),
+				),
+			},
+		},
+	}}
+
+	rec := httptest.NewRecorder()
+	getFIPSHandler(host, true).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fips", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, fmt.Sprintf(`{
+		"boringcrypto": %t,
+		"enforced": true,
+		"compliant": false,
+		"violations": [{
+			"moduleID": "",
+			"localID": "otelcol.exporter.otlp.default",
+			"name": "otelcol.exporter.otlp",
+			"errors": [
+				"TLS version TLS 1.1 is not FIPS approved, the minimum version is TLS 1.2",
+				"cipher suite TLS_RSA_WITH_RC4_128_SHA is not FIPS approved"
+			]
+		}]
+	}`, boringcrypto.Enabled), rec.Body.String())
+
+	host.components[""] = host.components[""][:1]
+	rec = httptest.NewRecorder()
+	getFIPSHandler(host, false).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/fips", nil))
+	require.JSONEq(t, fmt.Sprintf(`{"boringcrypto": %t, "enforced": false, "compliant": true, "violations": []}`, boringcrypto.Enabled), rec.Body.String())
+}
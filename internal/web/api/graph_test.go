@@ -0,0 +1,84 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/grafana/alloy/internal/component"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeGraphWriter struct {
+	fakeProvider
+}
+
+func (fakeGraphWriter) WriteGraph(w io.Writer, moduleID string, format string) error {
+	if moduleID != "" {
+		return component.ErrModuleNotFound
+	}
+	_, err := fmt.Fprintf(w, "graph in %s", format)
+	return err
+}
+
+func TestWriteGraph(t *testing.T) {
+	tests := []struct {
+		name        string
+		host        component.Provider
+		moduleID    string
+		format      string
+		code        int
+		contentType string
+		body        string
+	}{
+		{
+			name:        "dot",
+			host:        fakeGraphWriter{},
+			format:      "dot",
+			code:        http.StatusOK,
+			contentType: "text/vnd.graphviz; charset=utf-8",
+			body:        "graph in dot",
+		},
+		{
+			name:        "json",
+			host:        fakeGraphWriter{},
+			format:      "json",
+			code:        http.StatusOK,
+			contentType: "application/json",
+			body:        "graph in json",
+		},
+		{
+			name:   "unsupported format",
+			host:   fakeGraphWriter{},
+			format: "svg",
+			code:   http.StatusBadRequest,
+		},
+		{
+			name:     "unknown module",
+			host:     fakeGraphWriter{},
+			moduleID: "missing",
+			format:   "json",
+			code:     http.StatusInternalServerError,
+		},
+		{
+			name:   "unsupported host",
+			host:   fakeProvider{},
+			format: "json",
+			code:   http.StatusNotImplemented,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			writeGraph(tc.host, tc.moduleID, tc.format, rec)
+			require.Equal(t, tc.code, rec.Code)
+			if tc.code == http.StatusOK {
+				require.Equal(t, tc.contentType, rec.Header().Get("Content-Type"))
+				require.Equal(t, tc.body, rec.Body.String())
+			}
+		})
+	}
+}
@@ -8,7 +8,7 @@ interface PeerListProps {
   peers: PeerInfo[];
 }
 
-const TABLEHEADERS = ['Node Name', 'Advertised Address', 'Current State', 'Local Node'];
+const TABLEHEADERS = ['Node Name', 'Advertised Address', 'Current State', 'Zone', 'Local Node'];
 
 const PeerList = ({ peers }: PeerListProps) => {
   const tableStyles = { width: '130px' };
@@ -17,7 +17,7 @@ const PeerList = ({ peers }: PeerListProps) => {
    * Custom renderer for table data
    */
   const renderTableData = () => {
-    return peers.map(({ name, addr, state, isSelf }) => (
+    return peers.map(({ name, addr, state, zone, isSelf }) => (
       <tr key={name} style={{ lineHeight: '2.5' }}>
         <td>
           <span className={styles.idName}>{name}</span>
@@ -28,6 +28,9 @@ const PeerList = ({ peers }: PeerListProps) => {
         <td>
           <span className={styles.idName}>{state}</span>
         </td>
+        <td>
+          <span className={styles.idName}>{zone}</span>
+        </td>
         <td>
           <span> {isSelf ? '✅' : ' '}</span>
         </td>
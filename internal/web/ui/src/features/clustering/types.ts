@@ -6,4 +6,6 @@ export interface PeerInfo {
   state: string;
 
   isSelf: boolean;
+
+  zone?: string;
 }
@@ -0,0 +1,136 @@
+import { useEffect, useState } from 'react';
+
+import { ComponentFilter, emptyFilter, loadSavedFilters, SavedFilter, storeSavedFilters } from './filter';
+import { ComponentHealthState } from './types';
+
+import styles from './ComponentFilters.module.css';
+
+interface ComponentFiltersProps {
+  filter: ComponentFilter;
+  onChange: (filter: ComponentFilter) => void;
+  treeView: boolean;
+  onTreeViewChange: (treeView: boolean) => void;
+  /** Names of the known components, suggested when filtering by type. */
+  componentNames: string[];
+}
+
+// Delay before text inputs update the filter, to avoid querying the API on
+// every key press.
+const inputDebounceMs = 300;
+
+/**
+ * ComponentFilters displays the controls used to search and filter the list
+ * of components, as well as filters saved in the browser.
+ */
+const ComponentFilters = ({
+  filter,
+  onChange,
+  treeView,
+  onTreeViewChange,
+  componentNames,
+}: ComponentFiltersProps) => {
+  const [query, setQuery] = useState(filter.query);
+  const [types, setTypes] = useState(filter.types);
+  const [module, setModule] = useState(filter.module);
+  const [savedFilters, setSavedFilters] = useState<SavedFilter[]>(loadSavedFilters);
+
+  // Keep the inputs in sync when the filter changes from outside, for
+  // example when a saved filter is applied.
+  useEffect(() => {
+    setQuery(filter.query);
+    setTypes(filter.types);
+    setModule(filter.module);
+  }, [filter.query, filter.types, filter.module]);
+
+  useEffect(() => {
+    if (query === filter.query && types === filter.types && module === filter.module) {
+      return;
+    }
+    const timeout = setTimeout(() => onChange({ ...filter, query, types, module }), inputDebounceMs);
+    return () => clearTimeout(timeout);
+  }, [query, types, module, filter, onChange]);
+
+  const toggleHealth = (state: ComponentHealthState) => {
+    const health = filter.health.includes(state)
+      ? filter.health.filter((h) => h !== state)
+      : [...filter.health, state];
+    onChange({ ...filter, health });
+  };
+
+  const updateSavedFilters = (filters: SavedFilter[]) => {
+    storeSavedFilters(filters);
+    setSavedFilters(filters);
+  };
+
+  const saveFilter = () => {
+    const name = window.prompt('Name of the filter');
+    if (!name) return;
+    updateSavedFilters([...savedFilters.filter((f) => f.name !== name), { name, filter }]);
+  };
+
+  return (
+    <div className={styles.filters}>
+      <div className={styles.row}>
+        <input
+          type="search"
+          className={styles.search}
+          placeholder="Search IDs, arguments and exports"
+          value={query}
+          onChange={(e) => setQuery(e.target.value)}
+        />
+        <input
+          type="text"
+          list="component-names"
+          placeholder="Types, like prometheus."
+          value={types}
+          onChange={(e) => setTypes(e.target.value)}
+        />
+        <datalist id="component-names">
+          {componentNames.map((name) => (
+            <option key={name} value={name} />
+          ))}
+        </datalist>
+        <input type="text" placeholder="Module" value={module} onChange={(e) => setModule(e.target.value)} />
+      </div>
+      <div className={styles.row}>
+        {Object.values(ComponentHealthState).map((state) => (
+          <label key={state} className={styles.option}>
+            <input type="checkbox" checked={filter.health.includes(state)} onChange={() => toggleHealth(state)} />
+            {state}
+          </label>
+        ))}
+        <label className={styles.option}>
+          <input type="checkbox" checked={treeView} onChange={(e) => onTreeViewChange(e.target.checked)} />
+          Group by module
+        </label>
+        <button className={styles.button} onClick={() => onChange(emptyFilter)}>
+          Clear
+        </button>
+        <button className={styles.button} onClick={saveFilter}>
+          Save filter
+        </button>
+      </div>
+      {savedFilters.length > 0 && (
+        <div className={styles.row}>
+          Saved filters:
+          {savedFilters.map((saved) => (
+            <span key={saved.name} className={styles.saved}>
+              <button className={styles.link} onClick={() => onChange(saved.filter)}>
+                {saved.name}
+              </button>
+              <button
+                className={styles.link}
+                title="Delete filter"
+                onClick={() => updateSavedFilters(savedFilters.filter((f) => f.name !== saved.name))}
+              >
+                ×
+              </button>
+            </span>
+          ))}
+        </div>
+      )}
+    </div>
+  );
+};
+
+export default ComponentFilters;
@@ -11,6 +11,8 @@ interface ComponentListProps {
   components: ComponentInfo[];
   overrideModuleID?: string;
   useRemotecfg: boolean;
+  // showModuleID prefixes the ID of components with the ID of their module.
+  showModuleID?: boolean;
   handleSorting?: (sortField: string, sortOrder: SortOrder) => void;
 }
 
@@ -18,7 +20,13 @@ const TABLEHEADERS = ['Health', 'ID'];
 
 // overrideModuleID is a workaround for the remote config page because the remotecfg component has the moduleID of its controller,
 // it should not be fetched as a module.
-const ComponentList = ({ components, overrideModuleID, useRemotecfg, handleSorting }: ComponentListProps) => {
+const ComponentList = ({
+  components,
+  overrideModuleID,
+  useRemotecfg,
+  showModuleID,
+  handleSorting,
+}: ComponentListProps) => {
   const tableStyles = { width: '130px' };
   const urlPrefix = useRemotecfg ? '/remotecfg' : '';
   /**
@@ -26,12 +34,12 @@ const ComponentList = ({ components, overrideModuleID, useRemotecfg, handleSorti
    */
   const renderTableData = () => {
     return components.map(({ health, localID: id, moduleID }) => (
-      <tr key={id} style={{ lineHeight: '2.5' }}>
+      <tr key={moduleID ? moduleID + '/' + id : id} style={{ lineHeight: '2.5' }}>
         <td>
           <HealthLabel health={health.state} />
         </td>
         <td className={styles.idColumn}>
-          <span className={styles.idName}>{id}</span>
+          <span className={styles.idName}>{showModuleID && moduleID ? moduleID + '/' + id : id}</span>
           <NavLink
             to={
               urlPrefix +
@@ -0,0 +1,87 @@
+import ComponentList from './ComponentList';
+import { ComponentInfo, SortOrder } from './types';
+
+import styles from './ComponentTree.module.css';
+
+interface ComponentTreeProps {
+  components: ComponentInfo[];
+  handleSorting?: (sortField: string, sortOrder: SortOrder) => void;
+}
+
+interface ModuleNode {
+  // ID of the module, empty for the root module.
+  id: string;
+  components: ComponentInfo[];
+  children: Map<string, ModuleNode>;
+}
+
+/**
+ * buildTree groups components by module. Module IDs are made of the IDs of
+ * the components which declared them, separated by slashes.
+ */
+function buildTree(components: ComponentInfo[]): ModuleNode {
+  const root: ModuleNode = { id: '', components: [], children: new Map() };
+  for (const component of components) {
+    let node = root;
+    if (component.moduleID) {
+      for (const part of component.moduleID.split('/')) {
+        const id = node.id ? node.id + '/' + part : part;
+        let child = node.children.get(part);
+        if (!child) {
+          child = { id, components: [], children: new Map() };
+          node.children.set(part, child);
+        }
+        node = child;
+      }
+    }
+    node.components.push(component);
+  }
+  return root;
+}
+
+function countComponents(node: ModuleNode): number {
+  let count = node.components.length;
+  node.children.forEach((child) => (count += countComponents(child)));
+  return count;
+}
+
+const ModuleSection = ({
+  node,
+  handleSorting,
+}: {
+  node: ModuleNode;
+  handleSorting?: (sortField: string, sortOrder: SortOrder) => void;
+}) => {
+  const content = (
+    <>
+      {node.components.length > 0 && (
+        <ComponentList components={node.components} useRemotecfg={false} handleSorting={handleSorting} />
+      )}
+      {Array.from(node.children.values()).map((child) => (
+        <ModuleSection key={child.id} node={child} handleSorting={handleSorting} />
+      ))}
+    </>
+  );
+
+  if (!node.id) {
+    return content;
+  }
+  return (
+    <details className={styles.module} open>
+      <summary className={styles.summary}>
+        {node.id.substring(node.id.lastIndexOf('/') + 1)}
+        <span className={styles.count}>{countComponents(node)} components</span>
+      </summary>
+      <div className={styles.children}>{content}</div>
+    </details>
+  );
+};
+
+/**
+ * ComponentTree displays components in a collapsible hierarchy of modules.
+ */
+const ComponentTree = ({ components, handleSorting }: ComponentTreeProps) => {
+  return <ModuleSection node={buildTree(components)} handleSorting={handleSorting} />;
+};
+
+export default ComponentTree;
@@ -79,6 +79,13 @@ export const ComponentView: FC<ComponentViewProps> = (props) => {
           {argsPartition && partitionTOC(argsPartition)}
           {exportsPartition && partitionTOC(exportsPartition)}
           {debugPartition && partitionTOC(debugPartition)}
+          {props.component.healthHistory && props.component.healthHistory.length > 0 && (
+            <li>
+              <Link to="#health-history" target="_top">
+                Health history
+              </Link>
+            </li>
+          )}
           {props.component.referencesTo.length > 0 && (
             <li>
               <Link to="#dependencies" target="_top">
@@ -147,6 +154,29 @@ export const ComponentView: FC<ComponentViewProps> = (props) => {
         {exportsPartition && <ComponentBody partition={exportsPartition} />}
         {debugPartition && <ComponentBody partition={debugPartition} />}
 
+        {props.component.healthHistory && props.component.healthHistory.length > 0 && (
+          <section id="health-history">
+            <h2>Health history</h2>
+            <div className={styles.sectionContent}>
+              <table className={styles.healthHistory}>
+                <tbody>
+                  {props.component.healthHistory.map((entry, idx) => {
+                    return (
+                      <tr key={idx.toString()}>
+                        <td className={styles.healthHistoryTime}>{entry.updatedTime}</td>
+                        <td>
+                          <HealthLabel health={entry.state} />
+                        </td>
+                        <td className={styles.healthHistoryMessage}>{entry.message}</td>
+                      </tr>
+                    );
+                  })}
+                </tbody>
+              </table>
+            </div>
+          </section>
+        )}
+
         {props.component.referencesTo.length > 0 && (
           <section id="dependencies">
             <h2>Dependencies</h2>
@@ -0,0 +1,72 @@
+import { ComponentHealthState } from './types';
+
+/**
+ * ComponentFilter filters the list of components. Filtering happens in the
+ * API, which matches the query against the ID, arguments and exports of each
+ * component.
+ */
+export interface ComponentFilter {
+  /** Free text to search for. */
+  query: string;
+  /** Health states to keep; all states are kept when empty. */
+  health: ComponentHealthState[];
+  /** Comma-separated list of component names, or prefixes ending with a dot. */
+  types: string;
+  /** Only keep components of this module and of the modules it contains. */
+  module: string;
+}
+
+export const emptyFilter: ComponentFilter = { query: '', health: [], types: '', module: '' };
+
+/**
+ * SavedFilter is a filter saved in the local storage of the browser.
+ */
+export interface SavedFilter {
+  name: string;
+  filter: ComponentFilter;
+}
+
+const savedFiltersKey = 'alloy.componentList.savedFilters';
+
+/**
+ * filterToSearchParams converts a filter to the query parameters of the list
+ * components API. Components of all the modules are always listed.
+ */
+export function filterToSearchParams(filter: ComponentFilter): URLSearchParams {
+  const params = new URLSearchParams({ recursive: 'true' });
+  if (filter.query) params.set('q', filter.query);
+  if (filter.health.length > 0) params.set('health', filter.health.join(','));
+  if (filter.types) params.set('type', filter.types);
+  if (filter.module) params.set('module', filter.module);
+  return params;
+}
+
+/**
+ * filterFromSearchParams reads a filter from the query parameters of the page,
+ * so that filtered lists can be shared.
+ */
+export function filterFromSearchParams(params: URLSearchParams): ComponentFilter {
+  const health = (params.get('health') ?? '')
+    .split(',')
+    .filter((h): h is ComponentHealthState =>
+      Object.values(ComponentHealthState).includes(h as ComponentHealthState)
+    );
+  return {
+    query: params.get('q') ?? '',
+    health,
+    types: params.get('type') ?? '',
+    module: params.get('module') ?? '',
+  };
+}
+
+export function loadSavedFilters(): SavedFilter[] {
+  try {
+    return JSON.parse(localStorage.getItem(savedFiltersKey) ?? '[]');
+  } catch {
+    return [];
+  }
+}
+
+export function storeSavedFilters(filters: SavedFilter[]): void {
+  localStorage.setItem(savedFiltersKey, JSON.stringify(filters));
+}
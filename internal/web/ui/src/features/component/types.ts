@@ -111,6 +111,12 @@ export interface ComponentDetail extends ComponentInfo {
    */
   debugInfo?: AlloyBody;
 
+  /**
+   * Recent health transitions of the component, ordered from newest to
+   * oldest. Includes transient errors which have since resolved.
+   */
+  healthHistory?: ComponentHealth[];
+
   /**
    * If a component is a module loader, the IDs of modules it created are included here.
    */
@@ -23,6 +23,11 @@ function Navbar() {
             Clustering
           </NavLink>
         </li>
+        <li>
+          <NavLink to="/remote_write" className="nav-link">
+            Remote Write
+          </NavLink>
+        </li>
         <li>
           <NavLink to="/remotecfg" className="nav-link">
             Remote Configuration
@@ -0,0 +1,92 @@
+import { Link } from 'react-router-dom';
+
+import Table from '../clustering/Table';
+
+import { EndpointInfo, RemoteWriteInfo } from './types';
+
+import styles from './RemoteWriteList.module.css';
+
+interface RemoteWriteListProps {
+  components: RemoteWriteInfo[];
+}
+
+const TABLEHEADERS = ['Endpoint', 'Pending', 'Shards', 'Last Sent Sample', 'Lag', 'Retried', 'Failed'];
+
+/**
+ * A lag above this threshold, in seconds, is highlighted as the endpoint is
+ * likely falling behind.
+ */
+const LAG_WARNING_SECONDS = 60;
+
+const RemoteWriteList = ({ components }: RemoteWriteListProps) => {
+  if (components.length === 0) {
+    return <p>No prometheus.remote_write components are running.</p>;
+  }
+
+  return (
+    <>
+      {components.map((c) => {
+        const id = c.moduleID ? `${c.moduleID}/${c.localID}` : c.localID;
+        return (
+          <section key={id} className={styles.list}>
+            <header>
+              <h3>
+                <Link to={`/component/${id}`}>{id}</Link>
+              </h3>
+              <span>{c.health}</span>
+            </header>
+            <div className={styles.wal}>
+              WAL: {c.debugInfo.wal.segments} segments, {formatBytes(c.debugInfo.wal.sizeBytes)}, newest sample{' '}
+              {formatTimestamp(c.debugInfo.wal.highestTimestamp)}
+              {c.debugInfo.wal.error && <span className={styles.error}> ({c.debugInfo.wal.error})</span>}
+            </div>
+            <Table
+              tableHeaders={TABLEHEADERS}
+              renderTableData={() => (c.debugInfo.endpoints ?? []).map((ep) => renderEndpoint(ep))}
+            />
+          </section>
+        );
+      })}
+    </>
+  );
+};
+
+function renderEndpoint(ep: EndpointInfo) {
+  const lagSeconds = ep.lag / 1e9;
+  return (
+    <tr key={ep.name} style={{ lineHeight: '2.5' }}>
+      <td>
+        <span title={ep.url}>{ep.name}</span>
+      </td>
+      <td>{ep.pendingSamples + ep.pendingExemplars + ep.pendingHistograms}</td>
+      <td>
+        {ep.shards} (desired {ep.desiredShards})
+      </td>
+      <td>{formatTimestamp(ep.highestSentTimestamp)}</td>
+      <td className={lagSeconds > LAG_WARNING_SECONDS ? styles.stalled : undefined}>{lagSeconds.toFixed(1)}s</td>
+      <td>{ep.retriedSamples}</td>
+      <td className={ep.failedSamples > 0 ? styles.error : undefined}>{ep.failedSamples}</td>
+    </tr>
+  );
+}
+
+function formatTimestamp(ts: string): string {
+  const date = new Date(ts);
+  // The zero value of timestamps means that no data was seen yet.
+  if (date.getUTCFullYear() <= 1) {
+    return 'never';
+  }
+  return date.toLocaleString();
+}
+
+function formatBytes(bytes: number): string {
+  const units = ['B', 'KiB', 'MiB', 'GiB', 'TiB'];
+  let i = 0;
+  while (bytes >= 1024 && i < units.length - 1) {
+    bytes /= 1024;
+    i++;
+  }
+  return `${bytes.toFixed(i === 0 ? 0 : 1)} ${units[i]}`;
+}
+
+export default RemoteWriteList;
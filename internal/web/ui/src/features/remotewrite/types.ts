@@ -0,0 +1,49 @@
+/**
+ * RemoteWriteInfo is the state of a prometheus.remote_write component.
+ */
+export interface RemoteWriteInfo {
+  /** The moduleID that the component is defined in. moduleID may be the empty string. */
+  moduleID: string;
+
+  /** The id of the component within its module. */
+  localID: string;
+
+  /** Health of the component, like "healthy" or "unhealthy". */
+  health: string;
+
+  debugInfo: RemoteWriteDebugInfo;
+}
+
+export interface RemoteWriteDebugInfo {
+  wal: WALInfo;
+  endpoints: EndpointInfo[] | null;
+}
+
+export interface WALInfo {
+  segments: number;
+  sizeBytes: number;
+  error?: string;
+  /** Timestamp of the newest sample written to the WAL. */
+  highestTimestamp: string;
+}
+
+export interface EndpointInfo {
+  name: string;
+  url: string;
+
+  pendingSamples: number;
+  pendingExemplars: number;
+  pendingHistograms: number;
+
+  shards: number;
+  desiredShards: number;
+
+  /** Timestamp of the newest sample sent successfully to the endpoint. */
+  highestSentTimestamp: string;
+  /** Lag behind the newest sample in the WAL, in nanoseconds. */
+  lag: number;
+
+  retriedSamples: number;
+  failedSamples: number;
+  enqueueRetries: number;
+}
@@ -7,10 +7,13 @@ import { ComponentInfo } from '../features/component/types';
  *
  * @param fromComponent The component requesting component info. Required for
  * determining the proper list of components from the context of a module.
+ * @param filter Optional query string which filters the components, as
+ * returned by filterToSearchParams.
  */
 export const useComponentInfo = (
   moduleID: string,
-  isRemotecfg: boolean
+  isRemotecfg: boolean,
+  filter = ''
 ): [ComponentInfo[], React.Dispatch<React.SetStateAction<ComponentInfo[]>>] => {
   const [components, setComponents] = useState<ComponentInfo[]>([]);
 
@@ -26,7 +29,7 @@ export const useComponentInfo = (
           : `./api/v0/web/modules/${moduleID}/components`;
 
         // Request is relative to the <base> tag inside of <head>.
-        const resp = await fetch(infoPath, {
+        const resp = await fetch(filter ? `${infoPath}?${filter}` : infoPath, {
           cache: 'no-cache',
           credentials: 'same-origin',
         });
@@ -35,7 +38,7 @@ export const useComponentInfo = (
 
       worker().catch(console.error);
     },
-    [moduleID, isRemotecfg]
+    [moduleID, isRemotecfg, filter]
   );
 
   return [components, setComponents];
@@ -0,0 +1,33 @@
+import { useEffect, useState } from 'react';
+
+import { RemoteWriteInfo } from '../features/remotewrite/types';
+
+/**
+ * useRemoteWriteInfo retrieves the state of the prometheus.remote_write
+ * components from the API, and refreshes it periodically.
+ *
+ * @param refreshInterval How often to refresh the state, in milliseconds.
+ */
+export const useRemoteWriteInfo = (refreshInterval = 5000): RemoteWriteInfo[] => {
+  const [components, setComponents] = useState<RemoteWriteInfo[]>([]);
+
+  useEffect(
+    function () {
+      const worker = async () => {
+        // Request is relative to the <base> tag inside of <head>.
+        const resp = await fetch('./api/v0/web/remote_write', {
+          cache: 'no-cache',
+          credentials: 'same-origin',
+        });
+        setComponents(await resp.json());
+      };
+
+      worker().catch(console.error);
+      const interval = setInterval(() => worker().catch(console.error), refreshInterval);
+      return () => clearInterval(interval);
+    },
+    [refreshInterval]
+  );
+
+  return components;
+};
@@ -1,7 +1,7 @@
 import { useState } from 'react';
 import { useParams } from 'react-router-dom';
 import AutoScroll from '@brianmcallister/react-auto-scroll';
-import { faBroom, faBug, faCopy, faRoad, faStop } from '@fortawesome/free-solid-svg-icons';
+import { faBroom, faBug, faCircle, faCopy, faRoad, faStop } from '@fortawesome/free-solid-svg-icons';
 import { FontAwesomeIcon } from '@fortawesome/react-fontawesome';
 
 import { Field, Input, Slider } from '@grafana/ui';
@@ -18,6 +18,7 @@ function PageLiveDebugging() {
   const [sampleProb, setSampleProb] = useState(1);
   const [sliderProb, setSliderProb] = useState(100);
   const [filterValue, setFilterValue] = useState('');
+  const recordDuration = 60;
   const { loading, error } = useLiveDebugging(String(componentID), enabled, sampleProb, setData);
 
   const filteredData = data.filter((n) => n.toLowerCase().includes(filterValue.toLowerCase()));
@@ -63,6 +64,15 @@ function PageLiveDebugging() {
     }
   }
 
+  // Recording is done server side so that it keeps running independently from
+  // the live view; the browser downloads the file once the recording ends.
+  function recordData() {
+    const link = document.createElement('a');
+    link.href = `./api/v0/web/record/${componentID}?duration=${recordDuration}`;
+    link.download = '';
+    link.click();
+  }
+
   const samplingControl = (
     <div className={styles.slider}>
       <span className={styles.sliderLabel}>Sample rate</span>
@@ -103,6 +113,11 @@ function PageLiveDebugging() {
           <FontAwesomeIcon icon={faCopy} /> Copy
         </button>
       </div>
+      <div className={styles.debugLink}>
+        <button className={styles.recordButton} onClick={recordData} title={`Record ${recordDuration}s to a file`}>
+          <FontAwesomeIcon icon={faCircle} /> Record
+        </button>
+      </div>
     </>
   );
 
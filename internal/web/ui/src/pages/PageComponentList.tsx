@@ -1,13 +1,18 @@
+import { useCallback, useMemo } from 'react';
+import { useSearchParams } from 'react-router-dom';
 import { faCubes } from '@fortawesome/free-solid-svg-icons';
 
+import ComponentFilters from '../features/component/ComponentFilters';
 import ComponentList from '../features/component/ComponentList';
+import ComponentTree from '../features/component/ComponentTree';
+import { ComponentFilter, filterFromSearchParams, filterToSearchParams } from '../features/component/filter';
 import { ComponentInfo, SortOrder } from '../features/component/types';
 import Page from '../features/layout/Page';
 import { useComponentInfo } from '../hooks/componentInfo';
 
 const fieldMappings: { [key: string]: (comp: ComponentInfo) => string | undefined } = {
   Health: (comp) => comp.health?.state?.toString(),
-  ID: (comp) => comp.localID,
+  ID: (comp) => (comp.moduleID ? comp.moduleID + '/' + comp.localID : comp.localID),
   // Add new fields if needed here.
 };
 
@@ -17,7 +22,30 @@ function getSortValue(component: ComponentInfo, field: string): string | undefin
 }
 
 function PageComponentList() {
-  const [components, setComponents] = useComponentInfo('', false);
+  // Filters are kept in the URL so that filtered lists can be shared.
+  const [searchParams, setSearchParams] = useSearchParams();
+  const filter = useMemo(() => filterFromSearchParams(searchParams), [searchParams]);
+  const treeView = searchParams.get('view') === 'tree';
+
+  const [components, setComponents] = useComponentInfo('', false, filterToSearchParams(filter).toString());
+  const componentNames = useMemo(
+    () => Array.from(new Set(components.map((comp) => comp.name))).sort(),
+    [components]
+  );
+
+  const updateSearchParams = useCallback(
+    (filter: ComponentFilter, treeView: boolean) => {
+      const params = filterToSearchParams(filter);
+      params.delete('recursive');
+      if (treeView) params.set('view', 'tree');
+      setSearchParams(params, { replace: true });
+    },
+    [setSearchParams]
+  );
+  const handleFilterChange = useCallback(
+    (filter: ComponentFilter) => updateSearchParams(filter, treeView),
+    [updateSearchParams, treeView]
+  );
 
   // TODO: make this sorting logic reusable
   const handleSorting = (sortField: string, sortOrder: SortOrder): void => {
@@ -38,7 +66,18 @@ function PageComponentList() {
 
   return (
     <Page name="Components" desc="List of defined components" icon={faCubes}>
-      <ComponentList components={components} useRemotecfg={false} handleSorting={handleSorting} />
+      <ComponentFilters
+        filter={filter}
+        onChange={handleFilterChange}
+        treeView={treeView}
+        onTreeViewChange={(treeView) => updateSearchParams(filter, treeView)}
+        componentNames={componentNames}
+      />
+      {treeView ? (
+        <ComponentTree components={components} handleSorting={handleSorting} />
+      ) : (
+        <ComponentList components={components} useRemotecfg={false} showModuleID handleSorting={handleSorting} />
+      )}
     </Page>
   );
 }
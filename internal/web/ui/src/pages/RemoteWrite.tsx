@@ -0,0 +1,17 @@
+import { faUpload } from '@fortawesome/free-solid-svg-icons';
+
+import Page from '../features/layout/Page';
+import RemoteWriteList from '../features/remotewrite/RemoteWriteList';
+import { useRemoteWriteInfo } from '../hooks/remoteWriteInfo';
+
+function PageRemoteWrite() {
+  const components = useRemoteWriteInfo();
+
+  return (
+    <Page name="Remote Write" desc="Queue and WAL state of prometheus.remote_write components" icon={faUpload}>
+      <RemoteWriteList components={components} />
+    </Page>
+  );
+}
+
+export default PageRemoteWrite;
@@ -22,6 +22,7 @@ import (
 // identifiers that are considered "experimental".
 var ExperimentalIdentifiers = map[string]bool{
 	"array.combine_maps": true,
+	"sys.secret":         true,
 }
 
 // DeprecatedIdentifiers are deprecated in favour of the namespaced ones.
@@ -106,7 +107,25 @@ var convert = map[string]interface{}{
 }
 
 var sys = map[string]interface{}{
-	"env": os.Getenv,
+	"env":    os.Getenv,
+	"secret": secret,
+}
+
+// SecretResolver resolves the value of the secret identified by path.
+type SecretResolver func(path string) (alloytypes.Secret, error)
+
+// SysWithSecretResolver returns a copy of the sys namespace in which
+// sys.secret resolves secrets with r.
+func SysWithSecretResolver(r SecretResolver) map[string]interface{} {
+	ns := maps.Clone(sys)
+	ns["secret"] = func(path string) (alloytypes.Secret, error) { return r(path) }
+	return ns
+}
+
+// secret is used by sys.secret when the scope of the evaluation doesn't
+// provide a secret resolver.
+func secret(path string) (alloytypes.Secret, error) {
+	return "", fmt.Errorf("cannot resolve secret %q: no secret providers are configured", path)
 }
 
 func nonSensitive(secret alloytypes.Secret) string {
@@ -6,6 +6,7 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/grafana/alloy/syntax/alloytypes"
 	"github.com/grafana/alloy/syntax/ast"
 	"github.com/grafana/alloy/syntax/diag"
 	"github.com/grafana/alloy/syntax/internal/reflectutil"
@@ -523,6 +524,15 @@ func (s *Scope) Lookup(name string) (interface{}, bool) {
 	return nil, false
 }
 
+// SecretResolverVariables returns the variables to add to a Scope so that
+// sys.secret resolves secrets with r. They shadow the sys namespace of the
+// standard library with a copy in which only sys.secret differs.
+func SecretResolverVariables(r func(path string) (alloytypes.Secret, error)) map[string]interface{} {
+	return map[string]interface{}{
+		"sys": stdlib.SysWithSecretResolver(r),
+	}
+}
+
 // IsStdlibIdentifiers returns true if the identifier exists.
 func (s *Scope) IsStdlibIdentifiers(name string) bool {
 	_, exist := stdlib.Identifiers[name]
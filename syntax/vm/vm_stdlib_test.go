@@ -294,6 +294,44 @@ func TestStdlib_Nonsensitive(t *testing.T) {
 		})
 	}
 }
+
+func TestStdlib_Secret(t *testing.T) {
+	var scope *vm.Scope
+	eval := func(input string) (alloytypes.Secret, error) {
+		expr, err := parser.ParseExpression(input)
+		require.NoError(t, err)
+
+		var res alloytypes.Secret
+		err = vm.New(expr).Evaluate(scope, &res)
+		return res, err
+	}
+
+	_, err := eval(`sys.secret("vault/db#password")`)
+	require.ErrorContains(t, err, `cannot resolve secret "vault/db#password": no secret providers are configured`)
+
+	scope = vm.NewScope(vm.SecretResolverVariables(func(path string) (alloytypes.Secret, error) {
+		if path != "vault/db#password" {
+			return "", fmt.Errorf("secret %q not found", path)
+		}
+		return "hunter2", nil
+	}))
+
+	res, err := eval(`sys.secret("vault/db#password")`)
+	require.NoError(t, err)
+	require.Equal(t, alloytypes.Secret("hunter2"), res)
+
+	_, err = eval(`sys.secret("vault/missing")`)
+	require.ErrorContains(t, err, `secret "vault/missing" not found`)
+
+	// The other functions of the sys namespace are still available.
+	t.Setenv("ALLOY_TEST_SECRET_SCOPE", "value")
+	expr, err := parser.ParseExpression(`sys.env("ALLOY_TEST_SECRET_SCOPE")`)
+	require.NoError(t, err)
+	var env string
+	require.NoError(t, vm.New(expr).Evaluate(scope, &env))
+	require.Equal(t, "value", env)
+}
+
 func TestStdlib_StringFunc(t *testing.T) {
 	scope := vm.NewScope(make(map[string]interface{}))
 